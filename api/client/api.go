@@ -198,7 +198,7 @@ func (c *Client) postRequest(relPath string, data interface{}, response interfac
 			if httpErr != nil {
 				if onlyRetry500 && httpErr.Code < 500 {
 					// if we only retry 500-range responses and this is not a 500-range response, do not retry, instead return the error
-					return fmt.Errorf("%v", httpErr.Error)
+					return postRequestError(httpErr)
 				}
 				fmt.Fprintf(os.Stderr, "Error: %s (status code %d), retrying request...\n", httpErr.Error, httpErr.Code)
 			} else {
@@ -208,12 +208,35 @@ func (c *Client) postRequest(relPath string, data interface{}, response interfac
 	}
 
 	if httpErr != nil {
-		return fmt.Errorf("%v", httpErr.Error)
+		return postRequestError(httpErr)
 	}
 
 	return sendErr
 }
 
+// postRequestError returns the error of a failed post request, listing the pods of the app if the request was a deploy
+// which timed out waiting for them
+func postRequestError(httpErr *types.ExternalError) error {
+	if httpErr.Code != types.ErrCodeRolloutTimeout || len(httpErr.Pods) == 0 {
+		return fmt.Errorf("%v", httpErr.Error)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(httpErr.Error)
+	sb.WriteString("\npods of the app when the deploy timed out:")
+	for _, pod := range httpErr.Pods {
+		fmt.Fprintf(&sb, "\n  %s: %s, ready: %t, restarts: %d", pod.Name, pod.Phase, pod.Ready, pod.Restarts)
+		if pod.Reason != "" {
+			fmt.Fprintf(&sb, ", %s", pod.Reason)
+		}
+		if pod.Message != "" {
+			fmt.Fprintf(&sb, ": %s", pod.Message)
+		}
+	}
+
+	return errors.New(sb.String())
+}
+
 type patchRequestOpts struct {
 	retryCount uint
 }
@@ -293,10 +316,8 @@ func (c *Client) deleteRequest(relPath string, data interface{}, response interf
 	return nil
 }
 
-func (c *Client) sendRequest(req *http.Request, v interface{}, useCookie bool) (*types.ExternalError, error) {
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	req.Header.Set("Accept", "application/json; charset=utf-8")
-
+// setAuth authenticates a request with the token of the client, or its cookie if it has no token
+func (c *Client) setAuth(req *http.Request, useCookie bool) {
 	if c.Token != "" {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
 	} else if cookie, _ := c.getCookie(); useCookie && cookie != nil {
@@ -307,6 +328,52 @@ func (c *Client) sendRequest(req *http.Request, v interface{}, useCookie bool) (
 	if c.cfToken != "" {
 		req.Header.Set("cf-access-token", c.cfToken)
 	}
+}
+
+// streamRequest makes a GET request to the API for a stream of server-sent events, and returns the response once its
+// headers are read. The request has no timeout, so it is ended by canceling ctx, and the caller closes the body.
+func (c *Client) streamRequest(ctx context.Context, relPath string, data interface{}) (*http.Response, error) {
+	vals := make(map[string][]string)
+	_ = schema.NewEncoder().Encode(data, vals)
+
+	reqURL := fmt.Sprintf("%s%s", c.BaseURL, relPath)
+	if encodedURLVals := url.Values(vals).Encode(); encodedURLVals != "" {
+		reqURL = fmt.Sprintf("%s?%s", reqURL, encodedURLVals)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	c.setAuth(req, true)
+
+	// the client of every other request times out, which would end the stream
+	streamClient := &http.Client{Transport: c.HTTPClient.Transport}
+	res, err := streamClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+
+		var errRes types.ExternalError
+		if err = json.NewDecoder(res.Body).Decode(&errRes); err == nil {
+			return nil, fmt.Errorf("%v", errRes.Error)
+		}
+
+		return nil, fmt.Errorf("unknown error, status code: %d", res.StatusCode)
+	}
+
+	return res, nil
+}
+
+func (c *Client) sendRequest(req *http.Request, v interface{}, useCookie bool) (*types.ExternalError, error) {
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Accept", "application/json; charset=utf-8")
+
+	c.setAuth(req, useCookie)
 
 	res, err := c.HTTPClient.Do(req)
 	if err != nil {
@@ -33,6 +33,26 @@ func (c *Client) GetK8sNamespaces(
 	return resp, err
 }
 
+// ListClusterReleases lists the helm releases managed by Porter in a cluster
+func (c *Client) ListClusterReleases(
+	ctx context.Context,
+	projectID, clusterID uint,
+	req *types.ListClusterReleasesRequest,
+) (*types.ListClusterReleasesResponse, error) {
+	resp := &types.ListClusterReleasesResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/releases",
+			projectID, clusterID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 // CreateNewK8sNamespace creates a new namespace in a k8s cluster
 func (c *Client) CreateNewK8sNamespace(
 	ctx context.Context,
@@ -1,8 +1,11 @@
 package client
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/porter-dev/porter/api/server/handlers/porter_app"
 	"github.com/porter-dev/porter/internal/models"
@@ -111,6 +114,151 @@ func (c *Client) CreatePorterApp(
 	return resp, err
 }
 
+// DryRunPorterApp builds and renders the charts of a porter app without deploying it
+func (c *Client) DryRunPorterApp(
+	ctx context.Context,
+	projectID, clusterID uint,
+	name string,
+	req *types.CreatePorterAppRequest,
+) (*types.CreatePorterAppDryRunResponse, error) {
+	resp := &types.CreatePorterAppDryRunResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/stacks/%s/dry_run",
+			projectID, clusterID, name,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ValidatePorterApp builds the chart and values of a porter app without deploying it, returning the problems found
+// with its porter.yaml
+func (c *Client) ValidatePorterApp(
+	ctx context.Context,
+	projectID, clusterID uint,
+	name string,
+	req *types.CreatePorterAppRequest,
+) (*types.ValidatePorterAppResponse, error) {
+	resp := &types.ValidatePorterAppResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/applications/%s/validate",
+			projectID, clusterID, name,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ValidatePorterYAML runs a porter.yaml through the checks of a deploy, without the app it would be deployed to
+func (c *Client) ValidatePorterYAML(
+	ctx context.Context,
+	projectID, clusterID uint,
+	req *types.PorterAppValidateYAMLRequest,
+) (*types.PorterAppValidateYAMLResponse, error) {
+	resp := &types.PorterAppValidateYAMLResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/applications/validate-porter-yaml",
+			projectID, clusterID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// StreamDeployLogs streams the logs of a deploy of an app, calling onLine with each line, until the revision of the
+// deploy is deployed or fails, or ctx is canceled. It returns the status the revision ended in, or nil if the stream
+// ended before the revision did.
+func (c *Client) StreamDeployLogs(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	req *types.StreamDeployLogsRequest,
+	onLine func(line types.DeployLogLine),
+) (*types.DeployLogsDone, error) {
+	res, err := c.streamRequest(
+		ctx,
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/applications/%s/deploy-logs",
+			projectID, clusterID,
+			appName,
+		),
+		req,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var event string
+	scanner := bufio.NewScanner(res.Body)
+	// a log line is sent as a single event, which may be longer than the default limit of the scanner
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data := []byte(strings.TrimPrefix(line, "data: "))
+
+			switch event {
+			case types.DeployLogsEvent_Log:
+				var logLine types.DeployLogLine
+				if err := json.Unmarshal(data, &logLine); err != nil {
+					return nil, fmt.Errorf("error decoding deploy log line: %w", err)
+				}
+				onLine(logLine)
+			case types.DeployLogsEvent_Done:
+				done := &types.DeployLogsDone{}
+				if err := json.Unmarshal(data, done); err != nil {
+					return nil, fmt.Errorf("error decoding end of deploy logs: %w", err)
+				}
+				return done, nil
+			}
+		case line == "":
+			event = ""
+		}
+	}
+
+	return nil, scanner.Err()
+}
+
+// DiffPorterApp returns what deploying a porter.yaml would change in the current release of an app
+func (c *Client) DiffPorterApp(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	req *types.DiffPorterAppRequest,
+) (*types.DiffPorterAppResponse, error) {
+	resp := &types.DiffPorterAppResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/applications/%s/diff",
+			projectID, clusterID,
+			appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 // CreateOrUpdatePorterAppEvent will create a porter app event if one does not exist, or else it will update the existing one if an ID is passed in the object
 func (c *Client) CreateOrUpdatePorterAppEvent(
 	ctx context.Context,
@@ -132,6 +280,28 @@ func (c *Client) CreateOrUpdatePorterAppEvent(
 	return *resp, err
 }
 
+// UpdatePorterAppEvent finishes an event of an app which is in progress, or adds to its metadata
+func (c *Client) UpdatePorterAppEvent(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	eventID string,
+	req *types.UpdatePorterAppEventRequest,
+) (types.PorterAppEvent, error) {
+	resp := &types.PorterAppEvent{}
+
+	err := c.patchRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/events/%s",
+			projectID, clusterID, appName, eventID,
+		),
+		req,
+		resp,
+	)
+
+	return *resp, err
+}
+
 // ListEnvGroups (List all Env Groups for a given cluster)
 func (c *Client) ListEnvGroups(
 	ctx context.Context,
@@ -540,6 +710,27 @@ func (c *Client) GetBuildFromRevision(
 	return resp, err
 }
 
+// ValidateBuildSettings checks the build settings and build context the CLI is about to build with
+func (c *Client) ValidateBuildSettings(
+	ctx context.Context,
+	projectID uint, clusterID uint,
+	appName string,
+	req *porter_app.ValidateBuildSettingsRequest,
+) (*porter_app.ValidateBuildSettingsResponse, error) {
+	resp := &porter_app.ValidateBuildSettingsResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/build/validate",
+			projectID, clusterID, appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 // ReportRevisionStatusInput is the input struct to ReportRevisionStatus
 type ReportRevisionStatusInput struct {
 	ProjectID     uint
@@ -782,3 +973,182 @@ func (c *Client) RunAppJobStatus(
 
 	return resp, err
 }
+
+// RunStackJob launches a one-off job running the given command against a stack's image and environment
+func (c *Client) RunStackJob(
+	ctx context.Context,
+	projectID, clusterID uint,
+	stackName string,
+	command string,
+) (*porter_app.RunJobResponse, error) {
+	resp := &porter_app.RunJobResponse{}
+
+	req := &porter_app.RunJobRequest{
+		Command: command,
+	}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/applications/%s/run-jobs",
+			projectID, clusterID,
+			stackName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetStackRunJob gets the status and logs of a job launched by RunStackJob
+func (c *Client) GetStackRunJob(
+	ctx context.Context,
+	projectID, clusterID uint,
+	stackName string,
+	runID string,
+) (*appInternal.RunJob, error) {
+	resp := &appInternal.RunJob{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/applications/%s/run-jobs/%s",
+			projectID, clusterID,
+			stackName, runID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListImportableReleases lists the helm releases in a cluster which were not deployed by Porter, and whether each can be imported as a porter app
+func (c *Client) ListImportableReleases(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace string,
+) (*porter_app.ListImportableReleasesResponse, error) {
+	resp := &porter_app.ListImportableReleasesResponse{}
+
+	req := &porter_app.ListImportableReleasesRequest{
+		Namespace: namespace,
+	}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/import/releases",
+			projectID, clusterID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ImportRelease imports a helm release which was not deployed by Porter as a porter app, without redeploying it
+func (c *Client) ImportRelease(
+	ctx context.Context,
+	projectID, clusterID uint,
+	req *porter_app.ImportReleaseRequest,
+) (*porter_app.ImportReleaseResponse, error) {
+	resp := &porter_app.ImportReleaseResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/import",
+			projectID, clusterID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ScalePorterApp sets the replicas of services of an app without deploying its porter.yaml
+func (c *Client) ScalePorterApp(
+	ctx context.Context,
+	projectID, clusterID uint,
+	appName string,
+	req *types.ScaleAppRequest,
+) (*types.ScaleAppResponse, error) {
+	resp := &types.ScaleAppResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/scale",
+			projectID, clusterID,
+			appName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetStackDeletePlan lists what deleting a stack with the given options removes and what it leaves behind
+func (c *Client) GetStackDeletePlan(
+	ctx context.Context,
+	projectID, clusterID uint,
+	stackName string,
+	req *types.DeletePorterAppPlanRequest,
+) (*types.DeletePorterAppPlan, error) {
+	resp := &types.DeletePorterAppPlan{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/stacks/%s/delete_plan",
+			projectID, clusterID,
+			stackName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// DeleteStack deletes a stack as described by the delete plan with the hash in the request
+func (c *Client) DeleteStack(
+	ctx context.Context,
+	projectID, clusterID uint,
+	stackName string,
+	req *types.DeletePorterAppRequest,
+) (*types.DeletePorterAppResponse, error) {
+	resp := &types.DeletePorterAppResponse{}
+
+	err := c.deleteRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/stacks/%s",
+			projectID, clusterID,
+			stackName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetStackCleanup gets the cleanup of the load balancers and domains of a deleted stack
+func (c *Client) GetStackCleanup(
+	ctx context.Context,
+	projectID, clusterID uint,
+	cleanupID uint,
+) (*types.PorterAppCleanup, error) {
+	resp := &types.PorterAppCleanup{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/stack_cleanups/%d",
+			projectID, clusterID,
+			cleanupID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
@@ -0,0 +1,113 @@
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/devmode"
+)
+
+const createPorterAppTestYAML = `version: v1stack
+services:
+  web:
+    type: web
+    run: node index.js
+    config:
+      container:
+        port: 8080
+      ingress:
+        enabled: false
+`
+
+// TestCreatePorterAppRoundTrip deploys an app twice through the client, against the create handler served over http,
+// and checks that each response returns the persisted app and the revision the deploy created, so that the CLI does
+// not have to read them back
+func TestCreatePorterAppRoundTrip(t *testing.T) {
+	// the charts of the app are served by the dev mode chart repo, so that the test does not reach the Porter chart repos
+	chartRepo, err := devmode.StartChartRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chartRepo.Close() // nolint:errcheck
+
+	env := apitest.NewHandlerTestEnv(t, "porter-stack-web")
+	env.Config.ServerConf.DefaultApplicationHelmRepoURL = chartRepo.URL
+
+	handler := porter_app.NewCreatePorterAppHandler(
+		env.Config,
+		shared.NewDefaultRequestDecoderValidator(env.Config.Logger, env.Config.Alerter),
+		shared.NewDefaultResultWriter(env.Config.Logger, env.Config.Alerter),
+	)
+
+	// the server scopes every request to the project and cluster of the env, as the router would
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = apitest.WithAuthenticatedUser(t, r, env.User)
+		r = apitest.WithProject(t, r, env.Project)
+		r = apitest.WithCluster(t, r, env.Cluster)
+		r = apitest.WithURLParams(t, r, map[string]string{
+			string(types.URLParamPorterAppName): path.Base(r.URL.Path),
+		})
+		r = apitest.WithAgents(t, r, env.Agents)
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	c := client.Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	req := &types.CreatePorterAppRequest{
+		ClusterID:        env.Cluster.ID,
+		ProjectID:        env.Project.ID,
+		PorterYAMLBase64: base64.StdEncoding.EncodeToString([]byte(createPorterAppTestYAML)),
+		ImageRepoURI:     "nginx",
+		ImageInfo: types.ImageInfo{
+			Repository: "nginx",
+			Tag:        "latest",
+		},
+	}
+
+	for wantRevision := 1; wantRevision <= 2; wantRevision++ {
+		app, err := c.CreatePorterApp(context.Background(), env.Project.ID, env.Cluster.ID, "web", req)
+		if err != nil {
+			t.Fatalf("deploy %d: unexpected error: %v", wantRevision, err)
+		}
+
+		stored, err := env.Config.Repo.PorterApp().ReadScopedPorterAppByName(env.Project.ID, env.Cluster.ID, "web")
+		if err != nil {
+			t.Fatalf("deploy %d: expected the app to be in the database, got %v", wantRevision, err)
+		}
+
+		if app.ID != stored.ID || app.UUID != stored.UUID.String() || app.Name != "web" {
+			t.Errorf("deploy %d: expected the persisted app %d (%s), got %d (%s)", wantRevision, stored.ID, stored.UUID, app.ID, app.UUID)
+		}
+		if app.HelmRevisionNumber != wantRevision {
+			t.Errorf("deploy %d: expected revision %d, got %d", wantRevision, wantRevision, app.HelmRevisionNumber)
+		}
+		if app.Namespace != "porter-stack-web" {
+			t.Errorf("deploy %d: expected namespace porter-stack-web, got %q", wantRevision, app.Namespace)
+		}
+		if len(app.URLs) != 0 {
+			t.Errorf("deploy %d: expected no urls for an app without an ingress, got %v", wantRevision, app.URLs)
+		}
+
+		release, err := env.Agents.Helm.GetRelease(context.Background(), "web", 0, false)
+		if err != nil {
+			t.Fatalf("deploy %d: expected the release of the app to be installed, got %v", wantRevision, err)
+		}
+		if release.Version != app.HelmRevisionNumber {
+			t.Errorf("deploy %d: expected the returned revision to be the latest, which is %d", wantRevision, release.Version)
+		}
+	}
+}
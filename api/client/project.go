@@ -177,3 +177,45 @@ func (c *Client) DeleteProject(
 		nil,
 	)
 }
+
+// CreateBulkRedeploy redeploys every app of a project which matches the request's filter. The apps are redeployed in
+// the background, and their progress can be read with GetBulkRedeploy.
+func (c *Client) CreateBulkRedeploy(
+	ctx context.Context,
+	projectID uint,
+	req *types.CreateBulkRedeployRequest,
+) (*types.BulkRedeploy, error) {
+	resp := &types.BulkRedeploy{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/applications/redeploy",
+			projectID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetBulkRedeploy returns the progress of each app in a bulk redeploy
+func (c *Client) GetBulkRedeploy(
+	ctx context.Context,
+	projectID uint,
+	bulkRedeployID string,
+) (*types.BulkRedeploy, error) {
+	resp := &types.BulkRedeploy{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/applications/redeploy/%s",
+			projectID,
+			bulkRedeployID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
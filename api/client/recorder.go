@@ -0,0 +1,131 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/pkg/redact"
+)
+
+// SessionRecording is the file written by a session recorder
+type SessionRecording struct {
+	Exchanges []redact.Exchange `json:"exchanges"`
+}
+
+// sessionRecorder is a transport which records every request to the Porter API, and its response, to a file. The file
+// is rewritten after each exchange, so that the recording is complete even if the CLI exits before it returns.
+type sessionRecorder struct {
+	next http.RoundTripper
+	path string
+
+	mu        sync.Mutex
+	recording SessionRecording
+}
+
+// RecordSession records the requests sent by the client, and their responses, to the file at path. Secrets, tokens and
+// env variable values are redacted before they are written, so that the file can be attached to a support ticket.
+func (c *Client) RecordSession(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	recorder := &sessionRecorder{
+		next:      c.HTTPClient.Transport,
+		path:      path,
+		recording: SessionRecording{Exchanges: []redact.Exchange{}},
+	}
+	if recorder.next == nil {
+		recorder.next = http.DefaultTransport
+	}
+
+	// the file is created up front, so that an unwritable path is reported before any request is sent
+	if err := recorder.write(); err != nil {
+		return fmt.Errorf("error creating session recording: %w", err)
+	}
+
+	c.HTTPClient.Transport = recorder
+
+	return nil
+}
+
+func (s *sessionRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close() // nolint:errcheck,gosec
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	input := redact.ExchangeInput{
+		Start:          start,
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeaders: req.Header,
+		RequestBody:    requestBody,
+	}
+
+	res, err := s.next.RoundTrip(req)
+	if err != nil {
+		input.End = time.Now()
+		input.Error = err
+		s.record(input)
+
+		return nil, err
+	}
+
+	responseBody, readErr := io.ReadAll(res.Body)
+	res.Body.Close() // nolint:errcheck,gosec
+	res.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	input.End = time.Now()
+	input.StatusCode = res.StatusCode
+	input.ResponseHeaders = res.Header
+	input.ResponseBody = responseBody
+	input.Error = readErr
+	s.record(input)
+
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	return res, nil
+}
+
+func (s *sessionRecorder) record(input redact.ExchangeInput) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recording.Exchanges = append(s.recording.Exchanges, redact.NewExchange(input))
+
+	if err := s.writeLocked(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing session recording to %s: %v\n", s.path, err)
+	}
+}
+
+func (s *sessionRecorder) write() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.writeLocked()
+}
+
+func (s *sessionRecorder) writeLocked() error {
+	data, err := json.MarshalIndent(s.recording, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0o600)
+}
@@ -120,6 +120,26 @@ func (c *Client) GetRegistry(
 	return resp, err
 }
 
+// TestRegistryConnection calls the API of a registry with its credentials, and returns whether it could be reached
+func (c *Client) TestRegistryConnection(
+	ctx context.Context,
+	projectID, registryID uint,
+) (*types.TestRegistryConnectionResponse, error) {
+	resp := &types.TestRegistryConnectionResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/registries/%d/test",
+			projectID,
+			registryID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
 // DeleteProjectRegistry deletes a registry given a project id and registry id
 func (c *Client) DeleteProjectRegistry(
 	ctx context.Context,
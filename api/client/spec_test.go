@@ -0,0 +1,428 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/server/openapi"
+	"github.com/porter-dev/porter/api/server/router"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+)
+
+// recordedRequest is a request sent by a client method
+type recordedRequest struct {
+	method string
+	path   string
+	query  []string
+	body   map[string]json.RawMessage
+}
+
+// operationMatch is the operation of the API reference which a recorded request was routed to
+type operationMatch struct {
+	path      string
+	operation *openapi.Operation
+}
+
+// TestClientMatchesAPIReference calls every method of the client against a server which records the requests, and
+// checks that each request is a documented operation of the API reference, that the query parameters and body fields
+// which the client sends are part of the operation's request, and that the client decodes the operation's response
+// into the annotated type.
+func TestClientMatchesAPIReference(t *testing.T) {
+	doc := apiReference(t)
+	operations := newOperationMatcher(doc)
+
+	for _, call := range recordClientCalls(t) {
+		call := call
+
+		t.Run(call.name, func(t *testing.T) {
+			if len(call.requests) == 0 {
+				t.Skip("method does not send requests to the API")
+			}
+
+			for _, req := range call.requests {
+				match, ok := operations.match(req.method, req.path)
+				if !ok {
+					t.Fatalf("%s %s is not a route of the API router", req.method, req.path)
+				}
+
+				op := match.operation
+				if op.Undocumented {
+					t.Fatalf("%s %s is called by the client but has no schema annotation", req.method, match.path)
+				}
+
+				checkQuery(t, doc, op, req)
+				checkBody(t, doc, op, req)
+				checkResponse(t, doc, op, call.responseType, len(call.requests) == 1)
+			}
+		})
+	}
+}
+
+// apiReference returns the document served by the API router
+func apiReference(t *testing.T) *openapi.Document {
+	t.Helper()
+
+	conf := apitest.LoadConfig(t)
+	// the routes of preview environments are only registered when github webhooks are configured
+	conf.ServerConf.GithubIncomingWebhookSecret = "secret"
+
+	rr := httptest.NewRecorder()
+	router.NewAPIRouter(conf).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/swagger.json", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d serving the api reference, got %d", http.StatusOK, rr.Code)
+	}
+
+	doc := &openapi.Document{}
+	if err := json.Unmarshal(rr.Body.Bytes(), doc); err != nil {
+		t.Fatalf("error decoding api reference: %v", err)
+	}
+
+	return doc
+}
+
+// clientCall is the requests sent by a method of the client
+type clientCall struct {
+	name         string
+	requests     []recordedRequest
+	responseType reflect.Type
+}
+
+// recordClientCalls calls each exported method of the client with placeholder arguments, and records the requests
+// which it sends
+func recordClientCalls(t *testing.T) []clientCall {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		recorded []recordedRequest
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := recordedRequest{method: r.Method, path: strings.TrimPrefix(r.URL.Path, "/api")}
+		for key := range r.URL.Query() {
+			req.query = append(req.query, key)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		// bodies which are not JSON objects, such as the null body of a DELETE request, have no fields to check
+		_ = json.Unmarshal(body, &req.body)
+
+		mu.Lock()
+		recorded = append(recorded, req)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c, err := client.NewClientWithConfig(context.Background(), client.NewClientInput{
+		BaseURL:     server.URL + "/api",
+		BearerToken: "token",
+	})
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+
+	clientValue := reflect.ValueOf(&c)
+	clientType := clientValue.Type()
+
+	var calls []clientCall
+	for i := 0; i < clientType.NumMethod(); i++ {
+		method := clientType.Method(i)
+		if localClientMethods[method.Name] {
+			continue
+		}
+
+		mu.Lock()
+		recorded = nil
+		mu.Unlock()
+
+		callWithPlaceholders(t, clientValue.Method(i), method.Name)
+
+		mu.Lock()
+		calls = append(calls, clientCall{
+			name:         method.Name,
+			requests:     recorded,
+			responseType: responseType(method.Type),
+		})
+		mu.Unlock()
+	}
+
+	return calls
+}
+
+// localClientMethods are the methods of the client which write local files rather than sending requests, and which are
+// not called with placeholders, so that the test does not leave files behind
+var localClientMethods = map[string]bool{
+	"RecordSession": true,
+}
+
+// callWithPlaceholders calls a method with non-zero arguments, so that every field of a request struct is sent
+func callWithPlaceholders(t *testing.T, method reflect.Value, name string) {
+	t.Helper()
+
+	methodType := method.Type()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := make([]reflect.Value, methodType.NumIn())
+	for i := range args {
+		argType := methodType.In(i)
+		if argType == reflect.TypeOf((*context.Context)(nil)).Elem() {
+			args[i] = reflect.ValueOf(ctx)
+			continue
+		}
+
+		args[i] = placeholder(argType, 0)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			// methods may dereference results which the recording server does not fill in
+			_ = recover()
+		}()
+
+		if methodType.IsVariadic() {
+			method.CallSlice(args)
+		} else {
+			method.Call(args)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("calling %s did not return", name)
+	}
+}
+
+// placeholder returns a value of a type where every field is set, up to a depth which stops recursive types
+func placeholder(t reflect.Type, depth int) reflect.Value {
+	v := reflect.New(t).Elem()
+	if depth > 4 {
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString("name")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Pointer:
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(placeholder(t.Elem(), depth+1))
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(t, 1, 1))
+		v.Index(0).Set(placeholder(t.Elem(), depth+1))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(t))
+		v.SetMapIndex(placeholder(t.Key(), depth+1), placeholder(t.Elem(), depth+1))
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				v.Field(i).Set(placeholder(t.Field(i).Type, depth+1))
+			}
+		}
+	}
+
+	return v
+}
+
+// responseType returns the type which a client method decodes the response into, which is its first result other
+// than an error
+func responseType(methodType reflect.Type) reflect.Type {
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+
+	for i := 0; i < methodType.NumOut(); i++ {
+		out := methodType.Out(i)
+		if out == errorType {
+			continue
+		}
+		for out.Kind() == reflect.Pointer {
+			out = out.Elem()
+		}
+		return out
+	}
+
+	return nil
+}
+
+// operationMatcher routes a request to the operation of the API reference whose path matches it
+type operationMatcher struct {
+	paths []matcherPath
+}
+
+type matcherPath struct {
+	path    string
+	pattern *regexp.Regexp
+	// static is the number of segments which are not parameters
+	static int
+	item   *openapi.PathItem
+}
+
+func newOperationMatcher(doc *openapi.Document) *operationMatcher {
+	m := &operationMatcher{}
+
+	for path, item := range doc.Paths {
+		segments := strings.Split(strings.TrimPrefix(path, "/api"), "/")
+		static := 0
+		for i, segment := range segments {
+			switch {
+			case segment == "{wildcard}":
+				segments[i] = ".*"
+			case strings.HasPrefix(segment, "{"):
+				segments[i] = "[^/]+"
+			default:
+				segments[i] = regexp.QuoteMeta(segment)
+				static++
+			}
+		}
+
+		m.paths = append(m.paths, matcherPath{
+			path:    path,
+			pattern: regexp.MustCompile("^" + strings.Join(segments, "/") + "$"),
+			static:  static,
+			item:    item,
+		})
+	}
+
+	// as with the router, a static segment is preferred over a parameter
+	sort.Slice(m.paths, func(i, j int) bool {
+		if m.paths[i].static != m.paths[j].static {
+			return m.paths[i].static > m.paths[j].static
+		}
+		return m.paths[i].path < m.paths[j].path
+	})
+
+	return m
+}
+
+func (m *operationMatcher) match(method, path string) (operationMatch, bool) {
+	for _, p := range m.paths {
+		if !p.pattern.MatchString(path) {
+			continue
+		}
+		if op, ok := (*p.item)[strings.ToLower(method)]; ok {
+			return operationMatch{path: p.path, operation: op}, true
+		}
+	}
+
+	return operationMatch{}, false
+}
+
+func checkQuery(t *testing.T, doc *openapi.Document, op *openapi.Operation, req recordedRequest) {
+	t.Helper()
+
+	params := make(map[string]bool)
+	for _, param := range op.Parameters {
+		if param.In == "query" {
+			params[param.Name] = true
+		}
+	}
+
+	for _, key := range req.query {
+		if !params[key] {
+			t.Errorf("client sends query parameter %q which is not in the request of %s", key, op.OperationID)
+		}
+	}
+}
+
+func checkBody(t *testing.T, doc *openapi.Document, op *openapi.Operation, req recordedRequest) {
+	t.Helper()
+
+	if len(req.body) == 0 {
+		return
+	}
+	if op.RequestBody == nil {
+		t.Errorf("client sends a body to %s which does not document a request body", op.OperationID)
+		return
+	}
+
+	schema := resolve(doc, op.RequestBody.Content["application/json"].Schema)
+	if schema.Type != "object" || schema.Properties == nil {
+		return
+	}
+
+	for key := range req.body {
+		if _, ok := schema.Properties[key]; !ok {
+			t.Errorf("client sends body field %q which is not in the request of %s (%s)", key, op.OperationID, schema.GoType)
+		}
+	}
+}
+
+func checkResponse(t *testing.T, doc *openapi.Document, op *openapi.Operation, clientType reflect.Type, single bool) {
+	t.Helper()
+
+	if clientType == nil || !single {
+		return
+	}
+
+	ok := op.Responses["200"]
+	if ok == nil || ok.Content == nil {
+		t.Errorf("client decodes a %s from %s which does not document a response", clientType, op.OperationID)
+		return
+	}
+
+	got := schemaGoType(doc, ok.Content["application/json"].Schema)
+	if want := goType(clientType); got != want {
+		t.Errorf("response of %s is a %q, but the client decodes a %q", op.OperationID, got, want)
+	}
+}
+
+// goType returns the package path and name of a named type, and describes unnamed slices by their element type
+func goType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Name() == "" && t.Kind() == reflect.Slice {
+		return "[]" + goType(t.Elem())
+	}
+
+	return t.PkgPath() + "." + t.Name()
+}
+
+// schemaGoType returns the Go type of a schema in the form of goType, which is only known for components and arrays
+// of components
+func schemaGoType(doc *openapi.Document, schema *openapi.Schema) string {
+	if schema.Ref != "" {
+		return doc.Components.Schemas[openapi.ComponentName(schema.Ref)].GoType
+	}
+	if schema.Type == "array" && schema.Items != nil {
+		return "[]" + schemaGoType(doc, schema.Items)
+	}
+
+	return schema.Type
+}
+
+// resolve follows the reference of a schema to its component
+func resolve(doc *openapi.Document, schema *openapi.Schema) *openapi.Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = doc.Components.Schemas[openapi.ComponentName(schema.Ref)]
+	}
+	if schema == nil {
+		return &openapi.Schema{}
+	}
+
+	return schema
+}
@@ -87,6 +87,25 @@ func (c *Client) ListUserProjects(ctx context.Context) (*types.ListUserProjectsR
 	return resp, err
 }
 
+// ListUserProjectsV2 returns a page of projects associated with a user, along with the user's role
+// and activity information for each project
+func (c *Client) ListUserProjectsV2(
+	ctx context.Context,
+	req *types.ListUserProjectsV2Request,
+) (*types.ListUserProjectsV2Response, error) {
+	resp := &types.ListUserProjectsV2Response{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/v2/projects",
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 // DeleteUser deletes the current user
 func (c *Client) DeleteUser(
 	ctx context.Context,
@@ -2,6 +2,7 @@ package authn
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/internal/models"
@@ -27,6 +28,8 @@ func SaveUserAuthenticated(
 	session.Values["authenticated"] = true
 	session.Values["user_id"] = user.ID
 	session.Values["email"] = user.Email
+	session.Values["two_factor_user_id"] = nil
+	session.Values["two_factor_expires_at"] = nil
 
 	// we unset the redirect uri after login
 	session.Values["redirect_uri"] = ""
@@ -47,5 +50,53 @@ func SaveUserUnauthenticated(
 	session.Values["authenticated"] = false
 	session.Values["user_id"] = nil
 	session.Values["email"] = nil
+	session.Values["two_factor_user_id"] = nil
+	session.Values["two_factor_expires_at"] = nil
 	return session.Save(r, w)
 }
+
+// TwoFactorPendingTTL is how long a user has to submit their second factor after their password was accepted
+const TwoFactorPendingTTL = 5 * time.Minute
+
+// SaveUserTwoFactorPending records in the session that the password of a user was accepted, but that the session
+// is only authenticated once their second factor is verified
+func SaveUserTwoFactorPending(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+	user *models.User,
+) error {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+	if err != nil {
+		return err
+	}
+
+	session.Values["authenticated"] = false
+	session.Values["user_id"] = nil
+	session.Values["email"] = nil
+	session.Values["two_factor_user_id"] = user.ID
+	session.Values["two_factor_expires_at"] = time.Now().Add(TwoFactorPendingTTL).Unix()
+
+	return session.Save(r, w)
+}
+
+// UserTwoFactorPending returns the id of the user whose second factor the session is waiting for, or 0 if the session
+// is not waiting for one or the user took too long to submit it
+func UserTwoFactorPending(
+	r *http.Request,
+	config *config.Config,
+) (uint, error) {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+	if err != nil {
+		return 0, err
+	}
+
+	userID, _ := session.Values["two_factor_user_id"].(uint)
+	expiresAt, _ := session.Values["two_factor_expires_at"].(int64)
+
+	if userID == 0 || time.Now().Unix() > expiresAt {
+		return 0, nil
+	}
+
+	return userID, nil
+}
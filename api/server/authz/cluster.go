@@ -90,13 +90,25 @@ func NewOutOfClusterAgentGetter(config *config.Config) KubernetesAgentGetter {
 }
 
 func (d *OutOfClusterAgentGetter) GetOutOfClusterConfig(cluster *models.Cluster) *kubernetes.OutOfClusterConfig {
-	return &kubernetes.OutOfClusterConfig{
-		Repo:                        d.config.Repo,
-		DigitalOceanOAuth:           d.config.DOConf,
+	return OutOfClusterConfig(d.config, cluster)
+}
+
+// OutOfClusterConfig returns the config for connecting to a cluster with the server's credentials, through the tunnel
+// agent of the cluster if it is connected through one. Background workers use it to reach the same clusters as requests.
+func OutOfClusterConfig(conf *config.Config, cluster *models.Cluster) *kubernetes.OutOfClusterConfig {
+	ooc := &kubernetes.OutOfClusterConfig{
+		Repo:                        conf.Repo,
+		DigitalOceanOAuth:           conf.DOConf,
 		Cluster:                     cluster,
-		AllowInClusterConnections:   d.config.ServerConf.InitInCluster,
-		CAPIManagementClusterClient: d.config.ClusterControlPlaneClient,
+		AllowInClusterConnections:   conf.ServerConf.InitInCluster,
+		CAPIManagementClusterClient: conf.ClusterControlPlaneClient,
 	}
+
+	if conf.ClusterTunnel != nil {
+		ooc.Tunnel = conf.ClusterTunnel
+	}
+
+	return ooc
 }
 
 func (d *OutOfClusterAgentGetter) GetAgent(r *http.Request, cluster *models.Cluster, namespace string) (*kubernetes.Agent, error) {
@@ -134,7 +146,7 @@ func (d *OutOfClusterAgentGetter) GetAgent(r *http.Request, cluster *models.Clus
 
 	agent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, ooc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get agent: %s", err.Error())
+		return nil, fmt.Errorf("failed to get agent: %w", err)
 	}
 
 	newCtx := context.WithValue(ctx, KubernetesAgentCtxKey, agent)
@@ -0,0 +1,26 @@
+package authz_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/internal/kubernetes/tunnel"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutOfClusterConfigTunnel(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	cluster := &models.Cluster{ProjectID: 1, Name: "cluster-test"}
+
+	ooc := authz.OutOfClusterConfig(config, cluster)
+	assert.Nil(t, ooc.Tunnel, "tunnel should be left out when the server has no hub")
+	assert.Equal(t, cluster, ooc.Cluster)
+
+	config.ClusterTunnel = tunnel.NewHub(tunnel.HubOptions{})
+
+	ooc = authz.OutOfClusterConfig(config, cluster)
+	assert.Equal(t, config.ClusterTunnel, ooc.Tunnel, "tunnel should be the hub of the server")
+	assert.Equal(t, ooc, authz.NewOutOfClusterAgentGetter(config).GetOutOfClusterConfig(cluster))
+}
@@ -60,6 +60,15 @@ func (h *PolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		// FIXME: find a clean way to get the project
 
 		apiToken, _ := r.Context().Value("api_token").(*models.APIToken)
+
+		// the scopes of the token are checked before its policy, and are checked on every request so that the token
+		// cannot call endpoints outside of them even if its policy permits it
+		if !apiToken.CanCallEndpoint(h.endpointMeta) {
+			err := telemetry.Error(ctx, span, nil, "api token does not have the scope to call this endpoint")
+			apierrors.HandleAPIError(h.config.Logger, h.config.Alerter, w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden), true)
+			return
+		}
+
 		policyLoaderOpts.ProjectToken = apiToken
 		policyLoaderOpts.ProjectID = projID
 	} else {
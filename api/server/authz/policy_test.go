@@ -1,6 +1,7 @@
 package authz_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -228,6 +229,78 @@ func TestPolicyMiddlewareFailBadParam(t *testing.T) {
 	})
 }
 
+func TestPolicyMiddlewareAPITokenScopes(t *testing.T) {
+	tests := []struct {
+		name         string
+		scopes       string
+		endpointMeta types.APIRequestMetadata
+		allowed      bool
+	}{
+		{
+			name:         "token without scopes creates",
+			endpointMeta: types.APIRequestMetadata{Verb: types.APIVerbCreate, Scopes: []types.PermissionScope{types.ProjectScope}},
+			allowed:      true,
+		},
+		{
+			name:         "read token gets",
+			scopes:       "read",
+			endpointMeta: types.APIRequestMetadata{Verb: types.APIVerbGet, Scopes: []types.PermissionScope{types.ProjectScope}},
+			allowed:      true,
+		},
+		{
+			name:         "read token deploys",
+			scopes:       "read",
+			endpointMeta: types.APIRequestMetadata{Verb: types.APIVerbCreate, TokenScope: types.APITokenScopeDeploy, Scopes: []types.PermissionScope{types.ProjectScope}},
+			allowed:      false,
+		},
+		{
+			name:         "deploy token deploys",
+			scopes:       "deploy",
+			endpointMeta: types.APIRequestMetadata{Verb: types.APIVerbCreate, TokenScope: types.APITokenScopeDeploy, Scopes: []types.PermissionScope{types.ProjectScope}},
+			allowed:      true,
+		},
+		{
+			name:         "deploy token gets",
+			scopes:       "deploy",
+			endpointMeta: types.APIRequestMetadata{Verb: types.APIVerbGet, Scopes: []types.PermissionScope{types.ProjectScope}},
+			allowed:      false,
+		},
+		{
+			name:         "scoped token deletes",
+			scopes:       "read,deploy",
+			endpointMeta: types.APIRequestMetadata{Verb: types.APIVerbDelete, Scopes: []types.PermissionScope{types.ProjectScope}},
+			allowed:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := apitest.LoadConfig(t)
+			next := &testHandler{}
+			handler := authz.NewPolicyMiddleware(config, tt.endpointMeta, &adminDocLoader{}).Middleware(next)
+
+			req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1", nil)
+			req = apitest.WithURLParams(t, req, map[string]string{
+				"project_id": "1",
+			})
+			req = req.WithContext(context.WithValue(req.Context(), "api_token", &models.APIToken{
+				ProjectID: 1,
+				Scopes:    tt.scopes,
+			}))
+
+			handler.ServeHTTP(rr, req)
+
+			if tt.allowed {
+				assert.True(t, next.WasCalled, "next handler should have been called")
+				return
+			}
+
+			assert.False(t, next.WasCalled, "next handler should not have been called")
+			apitest.AssertForbiddenError(t, rr)
+		})
+	}
+}
+
 func loadHandlers(
 	t *testing.T,
 	endpointMeta types.APIRequestMetadata,
@@ -265,6 +338,12 @@ func (f *viewerDocLoader) LoadPolicyDocuments(opts *policy.PolicyLoaderOpts) ([]
 	return types.ViewerPolicy, nil
 }
 
+type adminDocLoader struct{}
+
+func (f *adminDocLoader) LoadPolicyDocuments(opts *policy.PolicyLoaderOpts) ([]*types.PolicyDocument, apierrors.RequestError) {
+	return types.AdminPolicy, nil
+}
+
 type testHandler struct {
 	WasCalled bool
 	ReqScopes map[types.PermissionScope]*types.RequestAction
@@ -0,0 +1,99 @@
+package admin_overview
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/adminoverview"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/supervisor"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// topErrors is the number of the most frequent API errors returned in the overview
+const topErrors = 20
+
+// GetAdminOverviewHandler returns the totals, deploy volume, slowest deploys, background component health and most
+// frequent API errors of the instance. Only the instance admin can read it.
+type GetAdminOverviewHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetAdminOverviewHandler returns a new GetAdminOverviewHandler
+func NewGetAdminOverviewHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetAdminOverviewHandler {
+	return &GetAdminOverviewHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetAdminOverviewHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-admin-overview")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	if !handlers.IsInstanceAdmin(c.Config(), user) {
+		err := telemetry.Error(ctx, span, nil, "user is not an instance admin")
+		c.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	now := time.Now().UTC()
+
+	var components []supervisor.ComponentState
+	if c.Config().Supervisor != nil {
+		components = c.Config().Supervisor.States()
+	}
+
+	errorCounts, errorsStart := apierrors.ErrorCounts.Top(now, topErrors)
+
+	res := adminoverview.Overview(now, c.Config().AdminOverview.Snapshot(), components, errorCounts, errorsStart)
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "collected", Value: res.CollectedAt != nil})
+
+	c.WriteResult(w, r, res)
+}
+
+// GetAdminOverviewSchemaHandler returns the JSON schema of the admin overview, for building dashboards against it.
+// Only the instance admin can read it.
+type GetAdminOverviewSchemaHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetAdminOverviewSchemaHandler returns a new GetAdminOverviewSchemaHandler
+func NewGetAdminOverviewSchemaHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetAdminOverviewSchemaHandler {
+	return &GetAdminOverviewSchemaHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetAdminOverviewSchemaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-admin-overview-schema")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	if !handlers.IsInstanceAdmin(c.Config(), user) {
+		err := telemetry.Error(ctx, span, nil, "user is not an instance admin")
+		c.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(adminoverview.Schema); err != nil {
+		_ = telemetry.Error(ctx, span, err, "error writing admin overview schema")
+	}
+}
@@ -3,6 +3,7 @@ package api_token
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/porter-dev/porter/api/server/authz/policy"
@@ -58,6 +59,16 @@ func (p *APITokenCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	scopes := make([]string, 0)
+	seenScopes := make(map[types.APITokenScope]bool)
+
+	for _, scope := range req.Scopes {
+		if !seenScopes[scope] {
+			seenScopes[scope] = true
+			scopes = append(scopes, string(scope))
+		}
+	}
+
 	uid, err := encryption.GenerateRandomBytes(16)
 	if err != nil {
 		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -86,6 +97,7 @@ func (p *APITokenCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		PolicyUID:       apiPolicy.UID,
 		PolicyName:      apiPolicy.Name,
 		Name:            req.Name,
+		Scopes:          strings.Join(scopes, ","),
 		SecretKey:       hashedToken,
 	}
 
@@ -0,0 +1,56 @@
+package cleanup
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// RunCleanupHandler deletes expired sessions and token caches immediately, instead of waiting for the next scheduled
+// cleanup. Only the instance admin can run it.
+type RunCleanupHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewRunCleanupHandler returns a new RunCleanupHandler
+func NewRunCleanupHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *RunCleanupHandler {
+	return &RunCleanupHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *RunCleanupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-run-cleanup")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	if !handlers.IsInstanceAdmin(c.Config(), user) {
+		err := telemetry.Error(ctx, span, nil, "user is not an instance admin")
+		c.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	if c.Config().Cleaner == nil {
+		err := telemetry.Error(ctx, span, errors.New("cleaner is not configured"), "error running cleanup")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := c.Config().Cleaner.Clean(ctx, time.Now())
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "duration", Value: res.Duration.String()})
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,102 @@
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/capabilities"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// DetectCapabilitiesHandler re-detects the capabilities of a cluster on demand
+type DetectCapabilitiesHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewDetectCapabilitiesHandler returns a new DetectCapabilitiesHandler
+func NewDetectCapabilitiesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *DetectCapabilitiesHandler {
+	return &DetectCapabilitiesHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *DetectCapabilitiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-detect-cluster-capabilities")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: cluster.ProjectID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: cluster.ID},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting kubernetes agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	detected, err := detectCapabilities(ctx, c.Config(), agent, cluster)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error detecting cluster capabilities")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadGateway))
+		return
+	}
+
+	c.WriteResult(w, r, detected)
+}
+
+// detectCapabilities detects the capabilities of a cluster and stores them on it. If the cluster cannot be reached, its
+// previously detected capabilities are kept and an error is returned.
+func detectCapabilities(ctx context.Context, config *config.Config, agent *kubernetes.Agent, cluster *models.Cluster) (*types.ClusterCapabilities, error) {
+	ctx, span := telemetry.NewSpan(ctx, "detect-cluster-capabilities")
+	defer span.End()
+
+	detected, err := capabilities.Detect(ctx, agent.Clientset, time.Now())
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error detecting cluster capabilities")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "distribution", Value: string(detected.Distribution)},
+		telemetry.AttributeKV{Key: "failed-probes", Value: len(detected.ProbeErrors)},
+	)
+
+	if err := config.Repo.Cluster().UpdateClusterCapabilities(cluster.ID, models.ClusterCapabilities(detected)); err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error storing cluster capabilities")
+	}
+	cluster.Capabilities = models.ClusterCapabilities(detected)
+
+	return &detected, nil
+}
+
+// detectCapabilitiesOnConnect detects the capabilities of a newly connected cluster. Errors are only recorded, since
+// connecting the cluster succeeded and its capabilities are detected again the next time the cluster is read.
+func detectCapabilitiesOnConnect(r *http.Request, config *config.Config, cluster *models.Cluster) {
+	ctx, span := telemetry.NewSpan(r.Context(), "detect-cluster-capabilities-on-connect")
+	defer span.End()
+
+	agent, err := authz.NewOutOfClusterAgentGetter(config).GetAgent(r, cluster, "")
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error getting kubernetes agent")
+		return
+	}
+
+	_, _ = detectCapabilities(ctx, config, agent, cluster)
+}
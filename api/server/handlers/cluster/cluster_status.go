@@ -36,6 +36,9 @@ type ClusterStatusResponse struct {
 	Phase                 string `json:"phase"`
 	IsInfrastructureReady bool   `json:"is_infrastructure_ready"`
 	IsControlPlaneReady   bool   `json:"is_control_plane_ready"`
+
+	// Tunnel is the connection state of the tunnel agent of the cluster, if it is reached through one
+	Tunnel *types.ClusterTunnelStatus `json:"tunnel,omitempty"`
 }
 
 func (c *ClusterStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -51,6 +54,7 @@ func (c *ClusterStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	resp := ClusterStatusResponse{
 		ProjectID: int(project.ID),
 		ClusterID: int(cluster.ID),
+		Tunnel:    tunnelClusterType(c.Config(), cluster).Tunnel,
 	}
 
 	status, err := c.Config().ClusterControlPlaneClient.ClusterStatus(ctx, req)
@@ -54,6 +54,8 @@ func (c *CreateClusterManualHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	detectCapabilitiesOnConnect(r, c.Config(), cluster)
+
 	c.WriteResult(w, r, cluster.ToClusterType())
 }
 
@@ -74,6 +74,8 @@ func (c *CreateClusterCandidateHandler) ServeHTTP(w http.ResponseWriter, r *http
 				return
 			}
 
+			detectCapabilitiesOnConnect(r, c.Config(), cluster)
+
 			c.Config().AnalyticsClient.Track(analytics.ClusterConnectionSuccessTrack(
 				&analytics.ClusterConnectionSuccessTrackOpts{
 					ClusterScopedTrackOpts: analytics.GetClusterScopedTrackOpts(user.ID, proj.ID, cluster.ID),
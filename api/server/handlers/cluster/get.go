@@ -2,6 +2,7 @@ package cluster
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
@@ -9,6 +10,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/capabilities"
 	"github.com/porter-dev/porter/internal/kubernetes/domain"
 	"github.com/porter-dev/porter/internal/models"
 )
@@ -32,7 +34,7 @@ func (c *ClusterGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
 
 	res := &types.ClusterGetResponse{
-		Cluster: cluster.ToClusterType(),
+		Cluster: tunnelClusterType(c.Config(), cluster),
 	}
 
 	agent, err := c.GetAgent(r, cluster, "")
@@ -41,6 +43,13 @@ func (c *ClusterGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// reading the cluster doubles as its health check, so stale capabilities are detected again here
+	if capabilities.Stale(res.Cluster.Capabilities, time.Now(), capabilities.DefaultMaxAge) {
+		if detected, err := detectCapabilities(r.Context(), c.Config(), agent, cluster); err == nil {
+			res.Cluster.Capabilities = detected
+		}
+	}
+
 	endpoint, found, ingressErr := domain.GetNGINXIngressServiceIP(agent.Clientset)
 
 	if found {
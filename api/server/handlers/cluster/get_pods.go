@@ -46,7 +46,7 @@ func (c *GetPodsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	pods := []v1.Pod{}
 	for _, selector := range request.Selectors {
-		podsList, err := agent.GetPodsByLabel(selector, request.Namespace)
+		podsList, err := agent.GetPodsByLabel(r.Context(), selector, request.Namespace)
 		if err != nil {
 			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 			return
@@ -79,7 +79,7 @@ func (c *InstallAgentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 
 	// create namespace if not exists
-	_, err = helmAgent.K8sAgent.CreateNamespace("porter-agent-system", nil)
+	_, err = helmAgent.K8sAgent.CreateNamespace(ctx, "porter-agent-system", nil)
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "failed to get create porter-agent-system namespace")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
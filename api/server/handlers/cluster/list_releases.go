@@ -0,0 +1,176 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/helmimport"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+// pendingReleaseStatuses are the statuses matched by the "pending" status filter
+var pendingReleaseStatuses = []string{
+	release.StatusPendingInstall.String(),
+	release.StatusPendingUpgrade.String(),
+	release.StatusPendingRollback.String(),
+}
+
+// releaseStatuses are the statuses by which releases can be filtered
+var releaseStatuses = map[string]bool{
+	release.StatusUnknown.String():         true,
+	release.StatusDeployed.String():        true,
+	release.StatusUninstalled.String():     true,
+	release.StatusSuperseded.String():      true,
+	release.StatusFailed.String():          true,
+	release.StatusUninstalling.String():    true,
+	release.StatusPendingInstall.String():  true,
+	release.StatusPendingUpgrade.String():  true,
+	release.StatusPendingRollback.String(): true,
+}
+
+// ListReleasesHandler handles GET requests to the /clusters/{cluster_id}/releases endpoint
+type ListReleasesHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewListReleasesHandler returns a new ListReleasesHandler
+func NewListReleasesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListReleasesHandler {
+	return &ListReleasesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP lists the latest revision of every helm release Porter manages in a cluster, and marks the releases whose
+// app no longer exists for cleanup
+func (c *ListReleasesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-cluster-releases")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.ListClusterReleasesRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	statuses, err := expandReleaseStatuses(request.Status)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid status filter")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "statuses", Value: strings.Join(statuses, ",")},
+		telemetry.AttributeKV{Key: "chart", Value: request.Chart},
+		telemetry.AttributeKV{Key: "namespace", Value: request.Namespace},
+	)
+
+	imports, err := c.Repo().HelmReleaseImport().ListHelmReleaseImportsByClusterID(ctx, project.ID, cluster.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing helm release imports")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	apps, err := c.Repo().PorterApp().ListScopedPorterAppsByClusterID(project.ID, cluster.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing porter apps")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	managed := helmimport.NewClusterReleases(imports, apps)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, request.Namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	releases, err := helmAgent.ListLatestReleases(ctx, request.Namespace, statuses)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing helm releases")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := types.ListClusterReleasesResponse{
+		Releases:         make([]types.ClusterRelease, 0),
+		MarkedForCleanup: make([]types.ReleaseRef, 0),
+	}
+	for _, rel := range releases {
+		clusterRelease, ok := managed.Classify(rel)
+		if !ok {
+			continue
+		}
+
+		if request.Chart != "" && clusterRelease.Chart != request.Chart {
+			continue
+		}
+
+		res.Releases = append(res.Releases, clusterRelease)
+	}
+
+	sort.Slice(res.Releases, func(i, j int) bool {
+		if res.Releases[i].Namespace != res.Releases[j].Namespace {
+			return res.Releases[i].Namespace < res.Releases[j].Namespace
+		}
+		return res.Releases[i].Name < res.Releases[j].Name
+	})
+
+	for _, rel := range res.Releases {
+		if rel.Orphaned {
+			res.MarkedForCleanup = append(res.MarkedForCleanup, types.ReleaseRef{Name: rel.Name, Namespace: rel.Namespace})
+		}
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "releases", Value: len(res.Releases)},
+		telemetry.AttributeKV{Key: "marked-for-cleanup", Value: len(res.MarkedForCleanup)},
+	)
+
+	c.WriteResult(w, r, res)
+}
+
+// expandReleaseStatuses expands "pending" to every pending status, and returns an error for unknown statuses
+func expandReleaseStatuses(statuses []string) ([]string, error) {
+	var res []string
+
+	for _, status := range statuses {
+		status = strings.ToLower(strings.TrimSpace(status))
+
+		switch {
+		case status == "":
+			continue
+		case status == "pending":
+			res = append(res, pendingReleaseStatuses...)
+		case releaseStatuses[status]:
+			res = append(res, status)
+		default:
+			return nil, fmt.Errorf("unknown release status %s", status)
+		}
+	}
+
+	return res, nil
+}
@@ -51,6 +51,8 @@ func (c *ResolveClusterCandidateHandler) ServeHTTP(w http.ResponseWriter, r *htt
 		return
 	}
 
+	detectCapabilitiesOnConnect(r, c.Config(), cluster)
+
 	c.Config().AnalyticsClient.Track(analytics.ClusterConnectionSuccessTrack(
 		&analytics.ClusterConnectionSuccessTrackOpts{
 			ClusterScopedTrackOpts: analytics.GetClusterScopedTrackOpts(user.ID, proj.ID, cluster.ID),
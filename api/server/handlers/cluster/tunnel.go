@@ -0,0 +1,243 @@
+package cluster
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/kubernetes/tunnel"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// CreateTunnelClusterHandler creates a cluster which is reached through a tunnel agent running inside it, for
+// clusters whose Kubernetes API is not reachable from the server
+type CreateTunnelClusterHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateTunnelClusterHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateTunnelClusterHandler {
+	return &CreateTunnelClusterHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *CreateTunnelClusterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-tunnel-cluster")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateTunnelClusterRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	token, tokenHash, err := generateTunnelToken()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error generating tunnel token")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	cluster, err := c.Repo().Cluster().CreateCluster(&models.Cluster{
+		ProjectID:       proj.ID,
+		AuthMechanism:   models.AgentTunnel,
+		Name:            request.Name,
+		TunnelTokenHash: tokenHash,
+	}, c.Config().LaunchDarklyClient)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "cluster-id", Value: cluster.ID})
+
+	// capabilities are detected by the cluster health check once the agent connects, since nothing can reach the
+	// cluster before then
+	c.WriteResult(w, r, &types.CreateTunnelClusterResponse{
+		Cluster: tunnelClusterType(c.Config(), cluster),
+		Token:   token,
+	})
+}
+
+// RotateTunnelTokenHandler replaces the token the tunnel agent of a cluster authenticates with, and disconnects the
+// agent until it reconnects with the new token
+type RotateTunnelTokenHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewRotateTunnelTokenHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *RotateTunnelTokenHandler {
+	return &RotateTunnelTokenHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *RotateTunnelTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-rotate-tunnel-token")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	if cluster.AuthMechanism != models.AgentTunnel {
+		err := telemetry.Error(ctx, span, nil, "cluster is not connected through a tunnel agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	token, tokenHash, err := generateTunnelToken()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error generating tunnel token")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	cluster.TunnelTokenHash = tokenHash
+
+	if _, err := c.Repo().Cluster().UpdateCluster(cluster, c.Config().LaunchDarklyClient); err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if c.Config().ClusterTunnel != nil {
+		c.Config().ClusterTunnel.Disconnect(cluster.ID)
+	}
+
+	c.WriteResult(w, r, &types.RotateTunnelTokenResponse{Token: token})
+}
+
+// TunnelConnectHandler accepts the connections of tunnel agents. Agents authenticate with the token of their
+// cluster rather than a user session, so the endpoint is not scoped to a project.
+type TunnelConnectHandler struct {
+	handlers.PorterHandler
+}
+
+func NewTunnelConnectHandler(
+	config *config.Config,
+) *TunnelConnectHandler {
+	return &TunnelConnectHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+func (c *TunnelConnectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-tunnel-connect")
+	defer span.End()
+
+	if c.Config().ClusterTunnel == nil {
+		err := telemetry.Error(ctx, span, nil, "cluster tunnels are not enabled on this server")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return
+	}
+
+	projectID, projectErr := strconv.ParseUint(r.Header.Get(tunnel.HeaderProjectID), 10, 64)
+	clusterID, clusterErr := strconv.ParseUint(r.Header.Get(tunnel.HeaderClusterID), 10, 64)
+	if projectErr != nil || clusterErr != nil {
+		err := telemetry.Error(ctx, span, nil, "project and cluster ids must be sent in the "+tunnel.HeaderProjectID+" and "+tunnel.HeaderClusterID+" headers")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: uint(projectID)},
+		telemetry.AttributeKV{Key: "cluster-id", Value: uint(clusterID)},
+	)
+
+	// every authentication failure returns the same error, so that the endpoint does not reveal which clusters exist
+	cluster, err := c.Repo().Cluster().ReadCluster(uint(projectID), uint(clusterID))
+	if err != nil || cluster.AuthMechanism != models.AgentTunnel || !validTunnelToken(cluster, r) {
+		err = telemetry.Error(ctx, span, nil, "invalid tunnel token")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusUnauthorized))
+		return
+	}
+
+	conn, err := c.Config().WSUpgrader.WSUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already written the error to the agent
+		_ = telemetry.Error(ctx, span, err, "error upgrading connection")
+		return
+	}
+	defer conn.Close()
+
+	registration, err := tunnel.ReadRegistration(conn)
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error reading agent registration")
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "agent-version", Value: registration.Version})
+
+	// serving blocks until the agent disconnects, its token is rotated, or a newer connection for the cluster
+	// replaces this one
+	if err := c.Config().ClusterTunnel.Serve(ctx, cluster.ID, conn, registration); err != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "disconnect-reason", Value: err.Error()})
+	}
+}
+
+// validTunnelToken returns true if the request is authenticated with the tunnel token of the cluster
+func validTunnelToken(cluster *models.Cluster, r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || len(cluster.TunnelTokenHash) == 0 {
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword(cluster.TunnelTokenHash, []byte(token)) == nil
+}
+
+// generateTunnelToken returns a new tunnel token, and the hash of it which is stored on the cluster
+func generateTunnelToken() (string, []byte, error) {
+	token, err := encryption.GenerateRandomBytes(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(token), 8)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, hash, nil
+}
+
+// tunnelClusterType returns the cluster with the connection state of its tunnel agent, if it is reached through one
+func tunnelClusterType(conf *config.Config, cluster *models.Cluster) *types.Cluster {
+	res := cluster.ToClusterType()
+
+	if cluster.AuthMechanism != models.AgentTunnel {
+		return res
+	}
+
+	res.Tunnel = &types.ClusterTunnelStatus{}
+	if conf.ClusterTunnel == nil {
+		return res
+	}
+
+	status := conf.ClusterTunnel.Status(cluster.ID)
+	res.Tunnel.Connected = status.Connected
+	res.Tunnel.AgentVersion = status.Version
+	if status.Connected {
+		connectedAt := status.ConnectedAt
+		res.Tunnel.ConnectedAt = &connectedAt
+	}
+	for _, capability := range status.Capabilities {
+		res.Tunnel.Capabilities = append(res.Tunnel.Capabilities, string(capability))
+	}
+
+	return res
+}
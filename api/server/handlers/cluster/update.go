@@ -10,6 +10,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
 )
 
 type ClusterUpdateHandler struct {
@@ -37,6 +38,13 @@ func (c *ClusterUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if request.SchedulingDefaults != nil {
+		if err := porter_app.ValidateSchedulingDefaults(*request.SchedulingDefaults); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
 	// if the cluster has an AWS integration, and the request does not have a cluster name attached, make
 	// sure that the old cluster name is set
 	if cluster.AWSIntegrationID != 0 && request.AWSClusterID == "" {
@@ -72,6 +80,14 @@ func (c *ClusterUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		cluster.Name = request.Name
 	}
 
+	if request.SchedulingDefaults != nil {
+		cluster.SchedulingDefaults = models.ClusterSchedulingDefaults(*request.SchedulingDefaults)
+	}
+
+	if request.NetworkPoliciesEnabled != nil {
+		cluster.NetworkPoliciesEnabled = *request.NetworkPoliciesEnabled
+	}
+
 	cluster, err := c.Repo().Cluster().UpdateCluster(cluster, c.Config().LaunchDarklyClient)
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -304,7 +304,7 @@ func (h *UpdateDatastoreHandler) performAddonPreinstall(ctx context.Context, r *
 	}
 
 	if _, err = agent.GetNamespace(release.Namespace_EnvironmentGroups); err != nil {
-		if _, err := agent.CreateNamespace(release.Namespace_EnvironmentGroups, map[string]string{}); err != nil {
+		if _, err := agent.CreateNamespace(ctx, release.Namespace_EnvironmentGroups, map[string]string{}); err != nil {
 			return telemetry.Error(ctx, span, err, "failed creating porter-env-group namespace")
 		}
 	}
@@ -0,0 +1,56 @@
+package debug_recording
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListDebugRecordingsHandler lists the debug recordings of a project, newest first
+type ListDebugRecordingsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListDebugRecordingsHandler returns a new ListDebugRecordingsHandler
+func NewListDebugRecordingsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListDebugRecordingsHandler {
+	return &ListDebugRecordingsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListDebugRecordingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-debug-recordings")
+	defer span.End()
+
+	_, project, ok := readAdminProject(c, w, r)
+	if !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: project.ID})
+
+	recordings, err := c.Repo().DebugRecording().ListDebugRecordingsByProjectID(ctx, project.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing debug recordings")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	now := time.Now().UTC()
+
+	res := make([]types.DebugRecording, 0, len(recordings))
+	for _, recording := range recordings {
+		res = append(res, recording.ToDebugRecordingType(now))
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,70 @@
+package debug_recording
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// maxListedExchanges is the largest number of exchanges returned by a single request
+const maxListedExchanges = 500
+
+// ListDebugRecordingExchangesHandler returns the redacted API calls of a debug recording, oldest first
+type ListDebugRecordingExchangesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListDebugRecordingExchangesHandler returns a new ListDebugRecordingExchangesHandler
+func NewListDebugRecordingExchangesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListDebugRecordingExchangesHandler {
+	return &ListDebugRecordingExchangesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListDebugRecordingExchangesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-debug-recording-exchanges")
+	defer span.End()
+
+	_, project, ok := readAdminProject(c, w, r)
+	if !ok {
+		return
+	}
+
+	recording, ok := readRecording(c, w, r, project)
+	if !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: project.ID},
+		telemetry.AttributeKV{Key: "debug-recording-id", Value: recording.ID},
+	)
+
+	if c.Config().DebugRecorder == nil {
+		err := telemetry.Error(ctx, span, nil, "debug recordings are not enabled on this instance")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusPreconditionFailed))
+		return
+	}
+
+	exchanges, truncated, err := c.Config().DebugRecorder.ListExchanges(ctx, recording, maxListedExchanges)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing recorded exchanges")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, types.ListDebugRecordingExchangesResponse{
+		Recording: recording.ToDebugRecordingType(time.Now().UTC()),
+		Exchanges: exchanges,
+		Truncated: truncated,
+	})
+}
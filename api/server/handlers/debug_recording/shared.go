@@ -0,0 +1,66 @@
+package debug_recording
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+// readAdminProject returns the project in the URL of an instance admin route. The routes are not project scoped, since
+// instance admins are usually not members of the projects whose calls they record, so the admin check is done here.
+func readAdminProject(h handlers.PorterHandler, w http.ResponseWriter, r *http.Request) (*models.User, *models.Project, bool) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	if !handlers.IsInstanceAdmin(h.Config(), user) {
+		h.HandleAPIError(w, r, apierrors.NewErrForbidden(fmt.Errorf("user is not an instance admin")))
+		return nil, nil, false
+	}
+
+	projectID, reqErr := requestutils.GetURLParamUint(r, types.URLParamProjectID)
+	if reqErr != nil {
+		h.HandleAPIError(w, r, reqErr)
+		return nil, nil, false
+	}
+
+	project, err := h.Repo().Project().ReadProject(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.HandleAPIError(w, r, apierrors.NewErrNotFound(fmt.Errorf("project %d not found", projectID)))
+			return nil, nil, false
+		}
+
+		h.HandleAPIError(w, r, apierrors.NewErrInternal(fmt.Errorf("error reading project: %w", err)))
+		return nil, nil, false
+	}
+
+	return user, project, true
+}
+
+// readRecording returns the recording in the URL of an instance admin route
+func readRecording(h handlers.PorterHandler, w http.ResponseWriter, r *http.Request, project *models.Project) (*models.DebugRecording, bool) {
+	recordingID, reqErr := requestutils.GetURLParamUint(r, types.URLParamDebugRecordingID)
+	if reqErr != nil {
+		h.HandleAPIError(w, r, reqErr)
+		return nil, false
+	}
+
+	recording, err := h.Repo().DebugRecording().ReadDebugRecording(r.Context(), project.ID, recordingID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.HandleAPIError(w, r, apierrors.NewErrNotFound(fmt.Errorf("debug recording %d not found", recordingID)))
+			return nil, false
+		}
+
+		h.HandleAPIError(w, r, apierrors.NewErrInternal(fmt.Errorf("error reading debug recording: %w", err)))
+		return nil, false
+	}
+
+	return recording, true
+}
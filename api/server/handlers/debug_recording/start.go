@@ -0,0 +1,116 @@
+package debug_recording
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// StartDebugRecordingHandler starts recording the API calls of a project. Only instance admins can start a recording,
+// and starting one is written to the project's audit log so that its members know their calls are recorded.
+type StartDebugRecordingHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewStartDebugRecordingHandler returns a new StartDebugRecordingHandler
+func NewStartDebugRecordingHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *StartDebugRecordingHandler {
+	return &StartDebugRecordingHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *StartDebugRecordingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-start-debug-recording")
+	defer span.End()
+
+	user, project, ok := readAdminProject(c, w, r)
+	if !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: project.ID})
+
+	if c.Config().DebugRecorder == nil {
+		err := telemetry.Error(ctx, span, nil, "debug recordings are not enabled on this instance")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusPreconditionFailed))
+		return
+	}
+
+	request := &types.StartDebugRecordingRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	duration := time.Duration(request.DurationMinutes) * time.Minute
+	if maxDuration := c.Config().ServerConf.DebugRecordingMaxDuration; maxDuration > 0 && duration > maxDuration {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("recordings cannot run for longer than %s", maxDuration))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	now := time.Now().UTC()
+
+	_, err := c.Repo().DebugRecording().ReadActiveDebugRecording(ctx, project.ID, now)
+	switch {
+	case err == nil:
+		err = telemetry.Error(ctx, span, nil, "the project already has an active debug recording")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		err = telemetry.Error(ctx, span, err, "error reading active debug recording")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	recording, err := c.Repo().DebugRecording().CreateDebugRecording(ctx, &models.DebugRecording{
+		ProjectID:       project.ID,
+		StartedByUserID: user.ID,
+		Reason:          request.Reason,
+		ExpiresAt:       now.Add(duration),
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating debug recording")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the recording is not started unless the project can see it in its audit log
+	_, err = c.Repo().AuditLog().CreateAuditLogEntry(ctx, &models.AuditLogEntry{
+		ProjectID:   project.ID,
+		Action:      string(types.AuditLogAction_DebugRecordingStarted),
+		ActorUserID: user.ID,
+		Description: fmt.Sprintf("Porter support started recording the API calls of this project until %s: %s", recording.ExpiresAt.Format(time.RFC3339), recording.Reason),
+		Metadata: models.JSONB{
+			"debug_recording_id": recording.ID,
+			"expires_at":         recording.ExpiresAt,
+			"reason":             recording.Reason,
+		},
+	})
+	if err != nil {
+		stoppedAt := now
+		recording.StoppedAt = &stoppedAt
+		_, _ = c.Repo().DebugRecording().UpdateDebugRecording(ctx, recording)
+
+		err = telemetry.Error(ctx, span, err, "error writing audit log entry")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.Config().DebugRecorder.Invalidate(project.ID)
+
+	c.WriteResult(w, r, recording.ToDebugRecordingType(now))
+}
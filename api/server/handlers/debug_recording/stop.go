@@ -0,0 +1,87 @@
+package debug_recording
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// StopDebugRecordingHandler stops a debug recording before it expires
+type StopDebugRecordingHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewStopDebugRecordingHandler returns a new StopDebugRecordingHandler
+func NewStopDebugRecordingHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *StopDebugRecordingHandler {
+	return &StopDebugRecordingHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *StopDebugRecordingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-stop-debug-recording")
+	defer span.End()
+
+	user, project, ok := readAdminProject(c, w, r)
+	if !ok {
+		return
+	}
+
+	recording, ok := readRecording(c, w, r, project)
+	if !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: project.ID},
+		telemetry.AttributeKV{Key: "debug-recording-id", Value: recording.ID},
+	)
+
+	now := time.Now().UTC()
+
+	// stopping a recording which already ended is a no-op
+	if !recording.IsActive(now) {
+		c.WriteResult(w, r, recording.ToDebugRecordingType(now))
+		return
+	}
+
+	recording.StoppedAt = &now
+
+	recording, err := c.Repo().DebugRecording().UpdateDebugRecording(ctx, recording)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error stopping debug recording")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if c.Config().DebugRecorder != nil {
+		c.Config().DebugRecorder.Invalidate(project.ID)
+	}
+
+	_, err = c.Repo().AuditLog().CreateAuditLogEntry(ctx, &models.AuditLogEntry{
+		ProjectID:   project.ID,
+		Action:      string(types.AuditLogAction_DebugRecordingStopped),
+		ActorUserID: user.ID,
+		Description: fmt.Sprintf("Porter support stopped recording the API calls of this project, after %d calls were recorded", recording.ExchangeCount),
+		Metadata: models.JSONB{
+			"debug_recording_id": recording.ID,
+		},
+	})
+	if err != nil {
+		// the recording has stopped, which is what the project cares about, so the missing entry is only logged
+		_ = telemetry.Error(ctx, span, err, "error writing audit log entry")
+	}
+
+	c.WriteResult(w, r, recording.ToDebugRecordingType(now))
+}
@@ -0,0 +1,108 @@
+package healthcheck
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/supervisor"
+)
+
+// ComponentzResponse is the state of the server's supervised background components
+type ComponentzResponse struct {
+	// Ready is false if a critical component has exceeded its restart budget
+	Ready bool `json:"ready"`
+	// Failed lists the critical components which have exceeded their restart budget
+	Failed     []string                    `json:"failed"`
+	Components []supervisor.ComponentState `json:"components"`
+}
+
+// ComponentzHandler returns the state of the server's background components, such as their restart counts and last
+// errors. Only the instance admin can read it.
+type ComponentzHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewComponentzHandler returns a new ComponentzHandler
+func NewComponentzHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ComponentzHandler {
+	return &ComponentzHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (v *ComponentzHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	if !handlers.IsInstanceAdmin(v.Config(), user) {
+		v.HandleAPIError(w, r, apierrors.NewErrForbidden(fmt.Errorf("user %d is not an instance admin", user.ID)))
+		return
+	}
+
+	ready, failed := v.Config().Supervisor.Ready()
+
+	res := &ComponentzResponse{
+		Ready:      ready,
+		Failed:     failed,
+		Components: make([]supervisor.ComponentState, 0),
+	}
+	if v.Config().Supervisor != nil {
+		res.Components = v.Config().Supervisor.States()
+	}
+
+	v.WriteResult(w, r, res)
+}
+
+// ComponentzMetricsHandler exposes the state of the server's background components, the rows deleted by the cleanups
+// of expired sessions and token caches, the queue of porter app events, and how status queries were served, as
+// prometheus metrics
+type ComponentzMetricsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewComponentzMetricsHandler returns a new ComponentzMetricsHandler
+func NewComponentzMetricsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ComponentzMetricsHandler {
+	return &ComponentzMetricsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (v *ComponentzMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+
+	if v.Config().Supervisor == nil {
+		return
+	}
+
+	if err := v.Config().Supervisor.WriteMetrics(w); err != nil {
+		v.Config().Logger.Error().Err(err).Msg("error writing component metrics")
+		return
+	}
+
+	if v.Config().Cleaner != nil {
+		if err := v.Config().Cleaner.WriteMetrics(w); err != nil {
+			v.Config().Logger.Error().Err(err).Msg("error writing cleanup metrics")
+		}
+	}
+
+	if v.Config().EventBuffer != nil {
+		if err := v.Config().EventBuffer.WriteMetrics(w); err != nil {
+			v.Config().Logger.Error().Err(err).Msg("error writing porter app event buffer metrics")
+		}
+	}
+
+	if err := v.Config().StatusQueryCoalescer.WriteMetrics(w); err != nil {
+		v.Config().Logger.Error().Err(err).Msg("error writing status query metrics")
+	}
+}
@@ -1,7 +1,9 @@
 package healthcheck
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
@@ -34,6 +36,13 @@ func (v *ReadyzHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// a critical background component which has exceeded its restart budget will not recover without a restart
+	if ready, failed := v.Config().Supervisor.Ready(); !ready {
+		err := fmt.Errorf("critical background components have failed: %s", strings.Join(failed, ", "))
+		v.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
 	writeHealthy(w)
 }
 
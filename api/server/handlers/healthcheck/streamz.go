@@ -0,0 +1,34 @@
+package healthcheck
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+)
+
+// StreamzHandler returns the number of open streaming connections on the server
+type StreamzHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewStreamzHandler returns a new StreamzHandler
+func NewStreamzHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *StreamzHandler {
+	return &StreamzHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (v *StreamzHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	counts := websocket.StreamCounts{}
+	if v.Config().StreamRegistry != nil {
+		counts = v.Config().StreamRegistry.Counts()
+	}
+
+	v.WriteResult(w, r, counts)
+}
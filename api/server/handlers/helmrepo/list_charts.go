@@ -10,6 +10,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/adapter"
 	"github.com/porter-dev/porter/internal/helm/loader"
 	"github.com/porter-dev/porter/internal/models"
 )
@@ -32,25 +33,26 @@ func (t *ChartListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
 	helmRepo, _ := r.Context().Value(types.HelmRepoScope).(*models.HelmRepo)
 
-	var repoIndex *repo.IndexFile
-	var err error
+	repoIndex, err := adapter.GetOrFill(r.Context(), t.Config().ResourceCache, adapter.CacheKey{
+		ProjectID:     proj.ID,
+		Resource:      adapter.CacheResource_HelmRepoIndex,
+		IntegrationID: helmRepo.ID,
+	}, t.Config().ServerConf.HelmRepoIndexCacheTTL, func() (*repo.IndexFile, error) {
+		if helmRepo.BasicAuthIntegrationID == 0 {
+			return loader.LoadRepoIndexPublic(helmRepo.RepoURL)
+		}
 
-	if helmRepo.BasicAuthIntegrationID != 0 {
 		// read the basic integration id
 		basic, err := t.Repo().BasicIntegration().ReadBasicIntegration(proj.ID, helmRepo.BasicAuthIntegrationID)
 		if err != nil {
-			t.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-			return
+			return nil, err
 		}
 
-		repoIndex, err = loader.LoadRepoIndex(&loader.BasicAuthClient{
+		return loader.LoadRepoIndex(&loader.BasicAuthClient{
 			Username: string(basic.Username),
 			Password: string(basic.Password),
 		}, helmRepo.RepoURL)
-	} else {
-		repoIndex, err = loader.LoadRepoIndexPublic(helmRepo.RepoURL)
-	}
-
+	})
 	if err != nil {
 		t.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
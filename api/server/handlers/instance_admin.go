@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// IsInstanceAdmin returns true if the user is the admin set by ADMIN_USER_ID. Instances which do not set it have no admin
+func IsInstanceAdmin(conf *config.Config, user *models.User) bool {
+	if user == nil || conf.ServerConf == nil || conf.ServerConf.AdminUserId == "" {
+		return false
+	}
+
+	adminUserID, err := strconv.ParseUint(conf.ServerConf.AdminUserId, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return uint(adminUserID) == user.ID
+}
@@ -39,7 +39,7 @@ func (c *GetPodsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pods, err := agent.GetJobPods(namespace, name)
+	pods, err := agent.GetJobPods(r.Context(), namespace, name)
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
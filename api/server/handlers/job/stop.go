@@ -37,7 +37,7 @@ func (c *StopHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = agent.StopJobWithJobSidecar(namespace, name)
+	err = agent.StopJobWithJobSidecar(r.Context(), namespace, name)
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
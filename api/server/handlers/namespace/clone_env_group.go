@@ -56,7 +56,7 @@ func (c *CloneEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	cm, _, err := agent.GetLatestVersionedConfigMap(request.SourceName, namespace)
+	cm, _, err := agent.GetLatestVersionedConfigMap(ctx, request.SourceName, namespace)
 	if err != nil {
 		if errors.Is(err, kubernetes.IsNotFoundError) {
 			_ = telemetry.Error(ctx, span, err, "error finding latest config map")
@@ -72,7 +72,7 @@ func (c *CloneEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	secret, _, err := agent.GetLatestVersionedSecret(request.SourceName, namespace)
+	secret, _, err := agent.GetLatestVersionedSecret(ctx, request.SourceName, namespace)
 	if err != nil {
 		if errors.Is(err, kubernetes.IsNotFoundError) {
 			_ = telemetry.Error(ctx, span, err, "error finding latest secret")
@@ -130,7 +130,7 @@ func (c *CloneEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	configMap, err := envgroup.CreateEnvGroup(agent, types.ConfigMapInput{
+	configMap, err := envgroup.CreateEnvGroup(ctx, agent, types.ConfigMapInput{
 		Name:            request.TargetName,
 		Namespace:       request.TargetNamespace,
 		Variables:       vars,
@@ -63,6 +63,24 @@ func (c *CreateStacksEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.R
 	}
 	// if the environment group exists and has MetaVersion=1, throw an error
 
+	// apps deployed with the env group are synced with it too, after the new version is copied into their namespace
+	linkedApps, err := c.Repo().PorterApp().ListScopedPorterAppsByClusterID(cluster.ProjectID, cluster.ID)
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, 504, "error listing apps"))
+		return
+	}
+	for _, app := range linkedApps {
+		if !app.EnvGroups.Contains(request.Name) || containsApp(request.Apps, app.Name) {
+			continue
+		}
+
+		if _, err := envgroup.CopyEnvGroup(ctx, agent, request.Name, namespace, "porter-stack-"+app.Name); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, 504, "error syncing env group"))
+			return
+		}
+		request.Apps = append(request.Apps, app.Name)
+	}
+
 	aggregateReleases := []*release.Release{}
 	for i := range request.Apps {
 		namespaceStack := "porter-stack-" + request.Apps[i]
@@ -95,6 +113,15 @@ func (c *CreateStacksEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.R
 	c.WriteResult(w, r, nil)
 }
 
+func containsApp(apps []string, name string) bool {
+	for _, app := range apps {
+		if app == name {
+			return true
+		}
+	}
+	return false
+}
+
 func rolloutStacksApplications(
 	c *CreateStacksEnvGroupHandler,
 	config *config.Config,
@@ -126,7 +153,7 @@ func rolloutStacksApplications(
 		if strings.HasSuffix(release.Name, suffix) {
 			releaseName = strings.TrimSuffix(releaseName, suffix)
 		}
-		cm, _, err := agent.GetLatestVersionedConfigMap(envGroupName, "porter-stack-"+releaseName)
+		cm, _, err := agent.GetLatestVersionedConfigMap(ctx, envGroupName, "porter-stack-"+releaseName)
 		if err != nil {
 			return []error{err}
 		}
@@ -290,7 +317,7 @@ func rolloutStacksApplications(
 			return []error{err}
 		}
 		imageInfo := attemptToGetImageInfoFromRelease(releases[i].Config)
-		_, err = createPorterAppEvent(ctx, "SUCCESS", updatedPorterApp.ID, releases[i].Version+1, imageInfo.Tag, c.Repo().PorterAppEvent())
+		_, err = createPorterAppEvent(ctx, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Success, updatedPorterApp.ID, releases[i].Version+1, imageInfo.Tag, c.Repo().PorterAppEvent())
 		if err != nil {
 			err = telemetry.Error(ctx, span, err, "error creating porter app event")
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
@@ -511,11 +538,12 @@ func getType(name string) string {
 	return ""
 }
 
-func createPorterAppEvent(ctx context.Context, status string, appID uint, revision int, tag string, repo repository.PorterAppEventRepository) (*models.PorterAppEvent, error) {
+// createPorterAppEvent creates an event of the given type and status for the activity feed of an app
+func createPorterAppEvent(ctx context.Context, eventType types.PorterAppEventType, status types.PorterAppEventStatus, appID uint, revision int, tag string, repo repository.PorterAppEventRepository) (*models.PorterAppEvent, error) {
 	event := models.PorterAppEvent{
 		ID:                 uuid.New(),
-		Status:             status,
-		Type:               "DEPLOY",
+		Status:             string(status),
+		Type:               string(eventType),
 		TypeExternalSource: "KUBERNETES",
 		PorterAppID:        appID,
 		Metadata: map[string]any{
@@ -529,10 +557,6 @@ func createPorterAppEvent(ctx context.Context, status string, appID uint, revisi
 		return nil, err
 	}
 
-	if event.ID == uuid.Nil {
-		return nil, err
-	}
-
 	return &event, nil
 }
 
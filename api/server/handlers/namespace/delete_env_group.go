@@ -48,7 +48,7 @@ func (c *DeleteEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	}
 
 	// get the env group: if it's MetaVersion=2, return an error
-	envGroup, err := envgroup.GetEnvGroup(agent, request.Name, namespace, 0)
+	envGroup, err := envgroup.GetEnvGroup(r.Context(), agent, request.Name, namespace, 0)
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
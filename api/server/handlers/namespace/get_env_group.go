@@ -50,7 +50,7 @@ func (c *GetEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	envGroup, err := envgroup.GetEnvGroup(agent, request.Name, namespace, request.Version)
+	envGroup, err := envgroup.GetEnvGroup(r.Context(), agent, request.Name, namespace, request.Version)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
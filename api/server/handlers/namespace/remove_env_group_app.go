@@ -49,7 +49,7 @@ func (c *RemoveEnvGroupAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	}
 
 	// read the attached configmap
-	cm, _, err := agent.GetLatestVersionedConfigMap(request.Name, namespace)
+	cm, _, err := agent.GetLatestVersionedConfigMap(r.Context(), request.Name, namespace)
 
 	if err != nil && errors.Is(err, kubernetes.IsNotFoundError) {
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
@@ -47,7 +47,7 @@ func (c *UpdateConfigMapHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	}
 
 	// get the env group: if it's MetaVersion=2, return an error
-	envGroup, err := envgroup.GetEnvGroup(agent, request.Name, namespace, 0)
+	envGroup, err := envgroup.GetEnvGroup(r.Context(), agent, request.Name, namespace, 0)
 
 	// if the environment group exists and has MetaVersion=2, throw an error
 	if envGroup != nil && envGroup.MetaVersion == 2 {
@@ -85,7 +85,7 @@ func (c *UpdateConfigMapHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	configMap, err = envgroup.ConvertV1ToV2EnvGroup(agent, request.Name, namespace)
+	configMap, err = envgroup.ConvertV1ToV2EnvGroup(r.Context(), agent, request.Name, namespace)
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -0,0 +1,187 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// appAccessDeniedError is returned when the user of a request holds no app grant permitting an action on an app
+type appAccessDeniedError struct {
+	AppName    string
+	Permission types.PorterAppGrantPermission
+}
+
+func (e *appAccessDeniedError) Error() string {
+	return fmt.Sprintf("this project requires an app grant with %s permission on %s for this action", e.Permission, e.AppName)
+}
+
+// appAccess decides which apps the user of a request may act on, once the policy of their project role has allowed
+// the request. Admins and API tokens are not restricted by app grants, and neither is anyone in projects without
+// scoped app access. The grants of each app are read once, so a request checking several actions on an app queries
+// them a single time.
+type appAccess struct {
+	conf    *config.Config
+	project *models.Project
+	user    *models.User
+
+	exempt bool
+	role   string
+	grants map[string][]*models.PorterAppGrant
+}
+
+func newAppAccess(ctx context.Context, conf *config.Config, r *http.Request) (*appAccess, error) {
+	ctx, span := telemetry.NewSpan(ctx, "new-app-access")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	access := &appAccess{
+		conf:    conf,
+		project: project,
+		user:    user,
+		grants:  make(map[string][]*models.PorterAppGrant),
+	}
+
+	// API tokens are governed by the policy of the token, and are given a user without an id
+	if !project.ScopedAppAccess || user == nil || user.ID == 0 || r.Context().Value("api_token") != nil {
+		access.exempt = true
+		return access, nil
+	}
+
+	role, err := conf.Repo.Project().ReadProjectRole(project.ID, user.ID)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading project role")
+	}
+
+	access.role = string(role.Kind)
+	access.exempt = role.Kind == types.RoleAdmin
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "role", Value: access.role},
+		telemetry.AttributeKV{Key: "exempt", Value: access.exempt},
+	)
+
+	return access, nil
+}
+
+// authorize returns an *appAccessDeniedError if the user holds no grant with the permission on the app. Denied
+// attempts are recorded in the audit log of the project.
+func (a *appAccess) authorize(ctx context.Context, appName string, permission types.PorterAppGrantPermission) error {
+	ctx, span := telemetry.NewSpan(ctx, "authorize-app-access")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "permission", Value: string(permission)},
+	)
+
+	if a.exempt {
+		return nil
+	}
+
+	grants, ok := a.grants[appName]
+	if !ok {
+		var err error
+		grants, err = a.conf.Repo.PorterAppGrant().ListMatchingPorterAppGrants(ctx, a.project.ID, appName, a.user.ID, a.role)
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error listing matching app grants")
+		}
+		a.grants[appName] = grants
+	}
+
+	for _, grant := range grants {
+		if grant.Allows(permission) {
+			return nil
+		}
+	}
+
+	denied := &appAccessDeniedError{AppName: appName, Permission: permission}
+
+	_, err := a.conf.Repo.AuditLog().CreateAuditLogEntry(ctx, &models.AuditLogEntry{
+		ProjectID:   a.project.ID,
+		Action:      string(types.AuditLogAction_AppAccessDenied),
+		ActorUserID: a.user.ID,
+		Description: fmt.Sprintf("%s was denied %s access to %s, since they hold no matching app grant", a.user.Email, permission, appName),
+		Metadata: models.JSONB{
+			"app_name":   appName,
+			"permission": string(permission),
+			"role":       a.role,
+		},
+	})
+	if err != nil {
+		// the action is denied either way
+		_ = telemetry.Error(ctx, span, err, "error writing audit log entry")
+	}
+
+	return denied
+}
+
+// authorizeAppAction checks that the user of a request may act on an app, for handlers which check a single action
+func authorizeAppAction(ctx context.Context, conf *config.Config, r *http.Request, appName string, permission types.PorterAppGrantPermission) error {
+	access, err := newAppAccess(ctx, conf, r)
+	if err != nil {
+		return err
+	}
+
+	return access.authorize(ctx, appName, permission)
+}
+
+// DeniedApps returns the apps which the user of a request may not act on, by name, with the reason each was denied.
+// It is for handlers outside of this package which act on several apps at once, and only fails for errors other than
+// denials.
+func DeniedApps(ctx context.Context, conf *config.Config, r *http.Request, appNames []string, permission types.PorterAppGrantPermission) (map[string]error, error) {
+	access, err := newAppAccess(ctx, conf, r)
+	if err != nil {
+		return nil, err
+	}
+
+	denied := make(map[string]error)
+	for _, appName := range appNames {
+		err := access.authorize(ctx, appName, permission)
+
+		var deniedErr *appAccessDeniedError
+		if errors.As(err, &deniedErr) {
+			denied[appName] = err
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return denied, nil
+}
+
+// appAccessError is the response to an error authorizing an app action. Denials are passed through to the client.
+func appAccessError(err error) apierrors.RequestError {
+	var denied *appAccessDeniedError
+	if errors.As(err, &denied) {
+		return apierrors.NewErrPassThroughToClient(err, http.StatusForbidden)
+	}
+
+	return apierrors.NewErrInternal(err)
+}
+
+// appGrants lists the grants of every user and role which match an app
+func appGrants(ctx context.Context, conf *config.Config, projectID uint, appName string) ([]types.PorterAppGrant, error) {
+	grants, err := conf.Repo.PorterAppGrant().ListMatchingPorterAppGrants(ctx, projectID, appName, 0, "")
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]types.PorterAppGrant, 0, len(grants))
+	for _, grant := range grants {
+		res = append(res, grant.ToPorterAppGrantType())
+	}
+
+	return res, nil
+}
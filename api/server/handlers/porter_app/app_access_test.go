@@ -0,0 +1,96 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+func TestAppAccess(t *testing.T) {
+	ctx := context.Background()
+
+	repo := test.NewRepository(true)
+	conf := &config.Config{Repo: repo}
+
+	project := &models.Project{ScopedAppAccess: true}
+	project.ID = 1
+	user := &models.User{Email: "dev@porter.run"}
+	user.ID = 2
+
+	grants := []*models.PorterAppGrant{
+		{ProjectID: 1, AppPattern: "payments-*", UserID: 2, Permission: string(types.PorterAppGrantPermission_Deploy)},
+		{ProjectID: 1, AppPattern: "*", Role: string(types.RoleDeveloper), Permission: string(types.PorterAppGrantPermission_Read)},
+		{ProjectID: 1, AppPattern: "*", UserID: 3, Permission: string(types.PorterAppGrantPermission_Deploy)},
+	}
+	for _, grant := range grants {
+		if _, err := repo.PorterAppGrant().CreatePorterAppGrant(ctx, grant); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	access := &appAccess{
+		conf:    conf,
+		project: project,
+		user:    user,
+		role:    string(types.RoleDeveloper),
+		grants:  make(map[string][]*models.PorterAppGrant),
+	}
+
+	tests := []struct {
+		appName    string
+		permission types.PorterAppGrantPermission
+		allowed    bool
+	}{
+		{"payments-api", types.PorterAppGrantPermission_Deploy, true},
+		{"payments-api", types.PorterAppGrantPermission_Read, true},
+		{"web", types.PorterAppGrantPermission_Read, true},
+		{"web", types.PorterAppGrantPermission_Deploy, false},
+	}
+
+	for _, tt := range tests {
+		err := access.authorize(ctx, tt.appName, tt.permission)
+		if tt.allowed && err != nil {
+			t.Errorf("expected %s access to %s to be allowed, got %v", tt.permission, tt.appName, err)
+		}
+
+		var denied *appAccessDeniedError
+		if !tt.allowed && !errors.As(err, &denied) {
+			t.Errorf("expected %s access to %s to be denied, got %v", tt.permission, tt.appName, err)
+		}
+	}
+
+	entries, err := repo.AuditLog().ListAuditLogEntriesByProjectID(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != string(types.AuditLogAction_AppAccessDenied) {
+		t.Errorf("expected the denied attempt to be audited, got %+v", entries)
+	}
+
+	t.Run("projects without scoped app access are not restricted", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		ctx := context.WithValue(ctx, types.ProjectScope, &models.Project{})
+		ctx = context.WithValue(ctx, types.UserScope, user)
+
+		if err := authorizeAppAction(ctx, conf, r, "web", types.PorterAppGrantPermission_Deploy); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("api tokens are not restricted", func(t *testing.T) {
+		tokenUser := &models.User{}
+		r := httptest.NewRequest("GET", "/", nil)
+		ctx := context.WithValue(ctx, types.ProjectScope, project)
+		ctx = context.WithValue(ctx, types.UserScope, tokenUser)
+
+		if err := authorizeAppAction(ctx, conf, r, "web", types.PorterAppGrantPermission_Deploy); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package porter_app_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// newScopedAppAccessEnv returns an env whose project has scoped app access, with a developer who may read every app
+// but only deploy the app named web. The project has the apps web and worker.
+func newScopedAppAccessEnv(t *testing.T) (*apitest.HandlerTestEnv, *models.User) {
+	t.Helper()
+
+	ctx := context.Background()
+	env := apitest.NewHandlerTestEnv(t, "porter-stack-web")
+
+	// requests carry the project of the env in their context
+	env.Project.ScopedAppAccess = true
+
+	developer, err := env.Config.Repo.User().CreateUser(&models.User{Email: "dev@porter.run", EmailVerified: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.Config.Repo.Project().CreateProjectRole(env.Project, &models.Role{
+		Role: types.Role{UserID: developer.ID, ProjectID: env.Project.ID, Kind: types.RoleDeveloper},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	grants := []*models.PorterAppGrant{
+		{ProjectID: env.Project.ID, AppPattern: "*", Role: string(types.RoleDeveloper), Permission: string(types.PorterAppGrantPermission_Read)},
+		{ProjectID: env.Project.ID, AppPattern: "web", UserID: developer.ID, Permission: string(types.PorterAppGrantPermission_Deploy)},
+	}
+	for _, grant := range grants {
+		if _, err := env.Config.Repo.PorterAppGrant().CreatePorterAppGrant(ctx, grant); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"web", "worker"} {
+		if _, err := env.Config.Repo.PorterApp().CreatePorterApp(&models.PorterApp{
+			ProjectID: env.Project.ID,
+			ClusterID: env.Cluster.ID,
+			Name:      name,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return env, developer
+}
+
+// newRequestAs returns a request from user, scoped to the project and cluster of the env
+func newRequestAs(t *testing.T, env *apitest.HandlerTestEnv, user *models.User, method string, requestObj interface{}, urlParams map[string]string) (*http.Request, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	req, rr := apitest.GetRequestAndRecorder(t, method, "/", requestObj)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, env.Project)
+	req = apitest.WithCluster(t, req, env.Cluster)
+	req = apitest.WithURLParams(t, req, urlParams)
+	req = apitest.WithAgents(t, req, env.Agents)
+
+	return req, rr
+}
+
+func TestAppActionsDeniedWithoutGrant(t *testing.T) {
+	env, developer := newScopedAppAccessEnv(t)
+
+	decoderValidator := shared.NewDefaultRequestDecoderValidator(env.Config.Logger, env.Config.Alerter)
+	writer := shared.NewDefaultResultWriter(env.Config.Logger, env.Config.Alerter)
+
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		request    interface{}
+		appName    string
+		deniedApp  string
+		permission types.PorterAppGrantPermission
+	}{
+		{
+			name:       "rename an app without access to remove it",
+			handler:    porter_app.NewRenamePorterAppHandler(env.Config, decoderValidator, writer),
+			request:    &types.RenamePorterAppRequest{Name: "jobs", MigrateRelease: true},
+			appName:    "worker",
+			deniedApp:  "worker",
+			permission: types.PorterAppGrantPermission_Delete,
+		},
+		{
+			name:       "rename an app to a name without deploy access",
+			handler:    porter_app.NewRenamePorterAppHandler(env.Config, decoderValidator, writer),
+			request:    &types.RenamePorterAppRequest{Name: "api", MigrateRelease: true},
+			appName:    "web",
+			deniedApp:  "api",
+			permission: types.PorterAppGrantPermission_Deploy,
+		},
+		{
+			name:    "restore a snapshot without deploy access",
+			handler: porter_app.NewRestorePorterAppSnapshotHandler(env.Config, decoderValidator, writer),
+			request: &types.RestoreStackSnapshotRequest{
+				Snapshot: &types.StackSnapshot{Version: types.StackSnapshotVersion, App: &types.PorterApp{Name: "web"}},
+				Name:     "api",
+			},
+			deniedApp:  "api",
+			permission: types.PorterAppGrantPermission_Deploy,
+		},
+		{
+			name:       "run a job without deploy access",
+			handler:    porter_app.NewRunJobHandler(env.Config, decoderValidator, writer),
+			request:    &porter_app.RunJobRequest{Command: "ls"},
+			appName:    "worker",
+			deniedApp:  "worker",
+			permission: types.PorterAppGrantPermission_Deploy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, rr := newRequestAs(t, env, developer, http.MethodPost, tt.request, map[string]string{
+				string(types.URLParamPorterAppName): tt.appName,
+			})
+
+			tt.handler.ServeHTTP(rr, req)
+
+			apitest.AssertResponseError(t, rr, http.StatusForbidden, &types.ExternalError{
+				Error: fmt.Sprintf("this project requires an app grant with %s permission on %s for this action", tt.permission, tt.deniedApp),
+			})
+		})
+	}
+}
+
+func TestBulkRedeploySkipsAppsWithoutGrant(t *testing.T) {
+	env, developer := newScopedAppAccessEnv(t)
+
+	handler := project.NewCreateBulkRedeployHandler(
+		env.Config,
+		shared.NewDefaultRequestDecoderValidator(env.Config.Logger, env.Config.Alerter),
+		shared.NewDefaultResultWriter(env.Config.Logger, env.Config.Alerter),
+	)
+
+	req, rr := newRequestAs(t, env, developer, http.MethodPost, &types.CreateBulkRedeployRequest{
+		BulkRedeployFilter: types.BulkRedeployFilter{ClusterID: env.Cluster.ID},
+		DryRun:             true,
+	}, nil)
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	res := types.BulkRedeploy{}
+	if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+
+	apps := make(map[string]types.BulkRedeployApp)
+	for _, app := range res.Apps {
+		apps[app.AppName] = app
+	}
+
+	if worker := apps["worker"]; worker.Status != types.BulkRedeployStatus_Skipped || !strings.Contains(worker.Error, "deploy permission on worker") {
+		t.Errorf("expected worker to be skipped for lack of deploy access, got %+v", worker)
+	}
+	if web, ok := apps["web"]; !ok || web.Status == types.BulkRedeployStatus_Skipped {
+		t.Errorf("expected web to be redeployed, got %+v", web)
+	}
+}
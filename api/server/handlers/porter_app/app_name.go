@@ -0,0 +1,18 @@
+package porter_app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// normalizeAppName returns the name an app is created or renamed with. App names are lowercase, since the namespace of
+// an app is derived from its name. A mixed-case name is refused with the lowercase name to use instead, unless
+// lowercase is set, in which case it is lowercased for clients which predate the check.
+func normalizeAppName(name string, lowercase bool) (string, error) {
+	lower := strings.ToLower(name)
+	if lower == name || lowercase {
+		return lower, nil
+	}
+
+	return "", fmt.Errorf("app names must be lowercase, use %s instead of %s", lower, name)
+}
@@ -0,0 +1,36 @@
+package porter_app
+
+import "testing"
+
+func TestNormalizeAppName(t *testing.T) {
+	tests := []struct {
+		name      string
+		appName   string
+		lowercase bool
+		want      string
+		wantErr   bool
+	}{
+		{name: "lowercase name", appName: "storefront", want: "storefront"},
+		{name: "lowercase name with compat flag", appName: "storefront", lowercase: true, want: "storefront"},
+		{name: "mixed-case name is refused", appName: "StoreFront", wantErr: true},
+		{name: "mixed-case name is lowered with compat flag", appName: "StoreFront", lowercase: true, want: "storefront"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeAppName(tt.appName, tt.lowercase)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	internalPorterApp "github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// canaryLabel is set to the name of a canary deployment on its pods, so that they can be selected apart from the
+	// pods of the release, which they otherwise share their labels with to receive the traffic of the service
+	canaryLabel = "porter.run/canary"
+	// canarySuffix is appended to the name of the deployment of a service to name its canary
+	canarySuffix = "-canary"
+)
+
+const (
+	// canaryAbortReason_CrashLoop is a canary whose pods crash loop
+	canaryAbortReason_CrashLoop = "crashloop"
+	// canaryAbortReason_ReadinessTimeout is a canary whose pods are not all ready by the end of the bake time
+	canaryAbortReason_ReadinessTimeout = "readiness-timeout"
+)
+
+// canaryPollInterval is how often the pods of the canaries are checked while they bake
+var canaryPollInterval = 5 * time.Second
+
+// canaryAbort is why the canary of a service was aborted
+type canaryAbort struct {
+	reason     string
+	deployment string
+	message    string
+}
+
+func (a *canaryAbort) Error() string {
+	return fmt.Sprintf("canary %s was aborted: %s", a.deployment, a.message)
+}
+
+// canaryTarget is the deployment of a web or worker service of the current release, and the image of the service in
+// the new values
+type canaryTarget struct {
+	deployment string
+	image      string
+}
+
+// canaryTargets returns the deployments of the web and worker services of the new values, in the order of their helm
+// names. Services which run their own image run it in their canary, and the others run imageInfo.
+func canaryTargets(appName string, values map[string]interface{}, imageInfo types.ImageInfo) []canaryTarget {
+	var helmNames []string
+	for helmName := range values {
+		if chartType := getChartTypeFromHelmName(helmName); chartType == "web" || chartType == "worker" {
+			helmNames = append(helmNames, helmName)
+		}
+	}
+	sort.Strings(helmNames)
+
+	targets := make([]canaryTarget, 0, len(helmNames))
+	for _, helmName := range helmNames {
+		image := imageInfo
+		if serviceValues, ok := values[helmName].(map[string]interface{}); ok {
+			if serviceImage, ok := serviceValues["image"].(map[string]interface{}); ok {
+				image.Repository, _ = serviceImage["repository"].(string)
+				image.Tag, _ = serviceImage["tag"].(string)
+			}
+		}
+
+		targets = append(targets, canaryTarget{
+			deployment: fmt.Sprintf("%s-%s", appName, helmName),
+			image:      fmt.Sprintf("%s:%s", image.Repository, image.Tag),
+		})
+	}
+
+	return targets
+}
+
+// canaryDeployment returns the canary of a deployment of the current release, which runs image in percentage of its
+// replicas. The app container is the first container of the pods of porter charts.
+func canaryDeployment(current *appsv1.Deployment, image string, percentage uint) *appsv1.Deployment {
+	name := current.Name + canarySuffix
+
+	currentReplicas := int32(1)
+	if current.Spec.Replicas != nil {
+		currentReplicas = *current.Spec.Replicas
+	}
+	replicas := (currentReplicas*int32(percentage) + 99) / 100
+	if replicas < 1 {
+		replicas = 1
+	}
+
+	canary := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: current.Namespace,
+			Labels:    map[string]string{canaryLabel: name},
+		},
+		Spec: *current.Spec.DeepCopy(),
+	}
+	canary.Spec.Replicas = &replicas
+
+	if canary.Spec.Selector == nil {
+		canary.Spec.Selector = &metav1.LabelSelector{}
+	}
+	if canary.Spec.Selector.MatchLabels == nil {
+		canary.Spec.Selector.MatchLabels = map[string]string{}
+	}
+	canary.Spec.Selector.MatchLabels[canaryLabel] = name
+
+	if canary.Spec.Template.Labels == nil {
+		canary.Spec.Template.Labels = map[string]string{}
+	}
+	canary.Spec.Template.Labels[canaryLabel] = name
+
+	if len(canary.Spec.Template.Spec.Containers) > 0 {
+		canary.Spec.Template.Spec.Containers[0].Image = image
+	}
+
+	return canary
+}
+
+// runCanary runs the new images of the web and worker services of an app in canary deployments alongside the current
+// release for the bake time of strategy, and removes the canaries once they have baked or been aborted. Services which
+// the current release does not run, or which are scaled to zero, have nothing to compare their canary against and are
+// left to the upgrade. It returns nil if the upgrade can complete.
+func runCanary(
+	ctx context.Context,
+	clientset k8s.Interface,
+	namespace string,
+	appName string,
+	values map[string]interface{},
+	imageInfo types.ImageInfo,
+	strategy *types.RolloutStrategy,
+) *deployFailure {
+	ctx, span := telemetry.NewSpan(ctx, "run-canary")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "percentage", Value: int(strategy.Percentage)},
+		telemetry.AttributeKV{Key: "bake-time-seconds", Value: int(strategy.BakeTimeSeconds)},
+	)
+
+	deployments := clientset.AppsV1().Deployments(namespace)
+
+	var canaries []*appsv1.Deployment
+	removeCanaries := func() error {
+		var errs []error
+		propagation := metav1.DeletePropagationBackground
+		for _, canary := range canaries {
+			// the request may have been cancelled, which must not leave the canaries running
+			err := deployments.Delete(context.Background(), canary.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("error deleting canary deployment %s: %w", canary.Name, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	for _, target := range canaryTargets(appName, values, imageInfo) {
+		current, err := deployments.Get(ctx, target.deployment, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return &deployFailure{stage: deployStage_Canary, err: fmt.Errorf("error reading deployment %s: %w", target.deployment, err), cleanupErr: removeCanaries()}
+		}
+		if current.Spec.Replicas != nil && *current.Spec.Replicas == 0 {
+			continue
+		}
+
+		canary, err := deployments.Create(ctx, canaryDeployment(current, target.image, strategy.Percentage), metav1.CreateOptions{})
+		if err != nil {
+			return &deployFailure{stage: deployStage_Canary, err: fmt.Errorf("error creating canary of deployment %s: %w", target.deployment, err), cleanupErr: removeCanaries()}
+		}
+		canaries = append(canaries, canary)
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "canaries", Value: len(canaries)})
+
+	if len(canaries) == 0 {
+		return nil
+	}
+
+	if err := bakeCanaries(ctx, clientset, namespace, canaries, time.Duration(strategy.BakeTimeSeconds)*time.Second); err != nil {
+		failure := &deployFailure{stage: deployStage_Canary, err: err, cleanupErr: removeCanaries()}
+
+		var abort *canaryAbort
+		if errors.As(err, &abort) {
+			failure.reason = abort.reason
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "abort-reason", Value: abort.reason})
+		}
+
+		return failure
+	}
+
+	if err := removeCanaries(); err != nil {
+		return &deployFailure{stage: deployStage_Canary, err: fmt.Errorf("error removing the canaries once they baked: %w", err)}
+	}
+
+	return nil
+}
+
+// bakeCanaries waits for bakeTime, and returns a canaryAbort if the pods of a canary crash loop, or are not all ready
+// once it has passed
+func bakeCanaries(ctx context.Context, clientset k8s.Interface, namespace string, canaries []*appsv1.Deployment, bakeTime time.Duration) error {
+	deadline := time.Now().Add(bakeTime)
+
+	ticker := time.NewTicker(canaryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		baked := !time.Now().Before(deadline)
+
+		for _, canary := range canaries {
+			pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: fmt.Sprintf("%s=%s", canaryLabel, canary.Name),
+			})
+			if err != nil {
+				return fmt.Errorf("error listing the pods of canary %s: %w", canary.Name, err)
+			}
+
+			ready := 0
+			for _, pod := range pods.Items {
+				if name, ok := crashLoopingContainer(pod); ok {
+					return &canaryAbort{
+						reason:     canaryAbortReason_CrashLoop,
+						deployment: canary.Name,
+						message:    fmt.Sprintf("container %s of pod %s is crash looping", name, pod.Name),
+					}
+				}
+				if podReady(pod) {
+					ready++
+				}
+			}
+
+			if baked && int32(ready) < *canary.Spec.Replicas {
+				return &canaryAbort{
+					reason:     canaryAbortReason_ReadinessTimeout,
+					deployment: canary.Name,
+					message:    fmt.Sprintf("%d of %d pods were ready after %s", ready, *canary.Spec.Replicas, bakeTime),
+				}
+			}
+		}
+
+		if baked {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("canary was interrupted: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// crashLoopingContainer returns the name of a container of pod which is crash looping
+func crashLoopingContainer(pod v1.Pod) (string, bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason == internalPorterApp.CrashLoopBackOff {
+			return status.Name, true
+		}
+	}
+
+	return "", false
+}
+
+func podReady(pod v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
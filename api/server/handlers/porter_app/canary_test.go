@@ -0,0 +1,139 @@
+package porter_app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func canaryTestDeployment(namespace, name string, replicas int32) *appsv1.Deployment {
+	podLabels := map[string]string{"app.kubernetes.io/instance": name}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{Name: "web", Image: "registry.example.com/storefront:v1"},
+						{Name: "proxy", Image: "envoyproxy/envoy:v1.28"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func canaryTestPod(namespace, canary string, ready bool, waitingReason string) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: canary + "-7d9f", Namespace: namespace, Labels: map[string]string{canaryLabel: canary}},
+		Status: v1.PodStatus{
+			Conditions:        []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+			ContainerStatuses: []v1.ContainerStatus{{Name: "web"}},
+		},
+	}
+	if waitingReason != "" {
+		pod.Status.ContainerStatuses[0].State.Waiting = &v1.ContainerStateWaiting{Reason: waitingReason}
+	}
+
+	return pod
+}
+
+func TestCanaryDeployment(t *testing.T) {
+	current := canaryTestDeployment("porter-stack-storefront", "storefront-web-web", 5)
+
+	canary := canaryDeployment(current, "registry.example.com/storefront:v2", 30)
+
+	if canary.Name != "storefront-web-web-canary" || *canary.Spec.Replicas != 2 {
+		t.Errorf("expected 30%% of 5 replicas to round up to 2 canary replicas, got %s with %d", canary.Name, *canary.Spec.Replicas)
+	}
+	if canary.Spec.Template.Labels["app.kubernetes.io/instance"] != "storefront-web-web" || canary.Spec.Template.Labels[canaryLabel] != canary.Name {
+		t.Errorf("expected the canary pods to keep the labels of the service and be told apart by the canary label, got %v", canary.Spec.Template.Labels)
+	}
+	if canary.Spec.Selector.MatchLabels[canaryLabel] != canary.Name {
+		t.Errorf("expected the canary to select only its own pods, got %v", canary.Spec.Selector.MatchLabels)
+	}
+	if canary.Spec.Template.Spec.Containers[0].Image != "registry.example.com/storefront:v2" || canary.Spec.Template.Spec.Containers[1].Image != "envoyproxy/envoy:v1.28" {
+		t.Errorf("expected only the app container to run the new image, got %v", canary.Spec.Template.Spec.Containers)
+	}
+	if current.Spec.Template.Labels[canaryLabel] != "" || current.Spec.Template.Spec.Containers[0].Image != "registry.example.com/storefront:v1" {
+		t.Error("expected the deployment of the release to be left as it was")
+	}
+
+	if got := canaryDeployment(current, "registry.example.com/storefront:v2", 1); *got.Spec.Replicas != 1 {
+		t.Errorf("expected a canary to run at least one replica, got %d", *got.Spec.Replicas)
+	}
+}
+
+func TestRunCanary(t *testing.T) {
+	ctx := context.Background()
+	namespace := "porter-stack-storefront"
+	canaryName := "storefront-web-web-canary"
+	strategy := &types.RolloutStrategy{Type: types.RolloutStrategyType_Canary, Percentage: 25, BakeTimeSeconds: 1}
+	values := map[string]interface{}{
+		"web-web":     map[string]interface{}{"replicaCount": "4"},
+		"api-web":     map[string]interface{}{},
+		"migrate-job": map[string]interface{}{},
+	}
+
+	defer func(interval time.Duration) { canaryPollInterval = interval }(canaryPollInterval)
+	canaryPollInterval = 10 * time.Millisecond
+
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		wantReason string
+	}{
+		{
+			name: "ready canary",
+			pod:  canaryTestPod(namespace, canaryName, true, ""),
+		},
+		{
+			name:       "crash looping canary",
+			pod:        canaryTestPod(namespace, canaryName, false, "CrashLoopBackOff"),
+			wantReason: canaryAbortReason_CrashLoop,
+		},
+		{
+			name:       "canary which is not ready",
+			pod:        canaryTestPod(namespace, canaryName, false, "ContainerCreating"),
+			wantReason: canaryAbortReason_ReadinessTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// api is a new service, which has no deployment to canary
+			clientset := fake.NewSimpleClientset(canaryTestDeployment(namespace, "storefront-web-web", 4), tt.pod)
+
+			failure := runCanary(ctx, clientset, namespace, "storefront", values, types.ImageInfo{Repository: "registry.example.com/storefront", Tag: "v2"}, strategy)
+
+			switch {
+			case tt.wantReason == "" && failure != nil:
+				t.Fatalf("expected the canary to bake, got %v", failure)
+			case tt.wantReason != "" && failure == nil:
+				t.Fatalf("expected the canary to be aborted with %s", tt.wantReason)
+			case failure != nil && (failure.reason != tt.wantReason || failure.stage != deployStage_Canary || failure.cleanupErr != nil):
+				t.Errorf("expected a canary failure with reason %s, got %+v", tt.wantReason, failure)
+			}
+
+			if _, err := clientset.AppsV1().Deployments(namespace).Get(ctx, canaryName, metav1.GetOptions{}); !k8serrors.IsNotFound(err) {
+				t.Errorf("expected the canary to be removed, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ClonePorterAppHandler handles POST /applications/{porter_app_name}/clone. The releases of the app are installed with
+// the same values under the new name, in the cluster of the app or in another cluster of the project, along with the
+// config they read from the namespace of the app. The porter.run subdomains of the app are not copied: the copy gets
+// subdomains of its own.
+type ClonePorterAppHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewClonePorterAppHandler returns a new ClonePorterAppHandler
+func NewClonePorterAppHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ClonePorterAppHandler {
+	return &ClonePorterAppHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ClonePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-clone-porter-app")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.ClonePorterAppRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-name", Value: appName},
+		telemetry.AttributeKV{Key: "new-porter-app-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "target-cluster-id", Value: request.ClusterID},
+	)
+
+	name, nameErr := normalizeAppName(request.Name, c.Config().ServerConf.LowercasePorterAppNames)
+	if nameErr != nil {
+		err := telemetry.Error(ctx, span, nameErr, "invalid app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	request.Name = name
+
+	if errs := validation.IsDNS1123Label(utils.NamespaceFromPorterAppName(request.Name)); len(errs) != 0 {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("invalid app name %s: %s", request.Name, strings.Join(errs, ", ")))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Read); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+	if err := authorizeAppAction(ctx, c.Config(), r, request.Name, types.PorterAppGrantPermission_Deploy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing access to the new app")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	targetCluster := cluster
+	if request.ClusterID != 0 && request.ClusterID != cluster.ID {
+		var err error
+		targetCluster, err = c.Repo().Cluster().ReadCluster(project.ID, request.ClusterID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = telemetry.Error(ctx, span, err, "target cluster not found in project")
+				c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+				return
+			}
+			err = telemetry.Error(ctx, span, err, "error reading target cluster")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	_, err = c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, targetCluster.ID, request.Name)
+	if err == nil {
+		err = telemetry.Error(ctx, span, nil, fmt.Sprintf("an app named %s already exists in the target cluster", request.Name))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading porter app by new name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if apiErr := c.cloneReleases(ctx, r, project, cluster, targetCluster, appName, request.Name); apiErr != nil {
+		c.HandleAPIError(w, r, apiErr)
+		return
+	}
+
+	clone := &models.PorterApp{
+		Name:      request.Name,
+		ProjectID: project.ID,
+		ClusterID: targetCluster.ID,
+
+		ImageRepoURI: porterApp.ImageRepoURI,
+		GitRepoID:    porterApp.GitRepoID,
+		RepoName:     porterApp.RepoName,
+		GitBranch:    porterApp.GitBranch,
+
+		BuildContext:   porterApp.BuildContext,
+		Builder:        porterApp.Builder,
+		Buildpacks:     porterApp.Buildpacks,
+		Dockerfile:     porterApp.Dockerfile,
+		PorterYamlPath: porterApp.PorterYamlPath,
+
+		ScalingSchedules: porterApp.ScalingSchedules,
+	}
+
+	clone, err = c.Repo().PorterApp().CreatePorterApp(clone)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error writing cloned app to DB")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, clone.ToPorterAppType())
+}
+
+// cloneReleases installs the release of an app, and of its pre-deploy job if it has one, under the name of the copy.
+// If a release fails to install, those already installed for the copy are uninstalled.
+func (c *ClonePorterAppHandler) cloneReleases(
+	ctx context.Context,
+	r *http.Request,
+	project *models.Project,
+	cluster, targetCluster *models.Cluster,
+	appName, newName string,
+) apierrors.RequestError {
+	ctx, span := telemetry.NewSpan(ctx, "clone-porter-app-releases")
+	defer span.End()
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+	newNamespace := utils.NamespaceFromPorterAppName(newName)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting helm agent"))
+	}
+
+	appRelease, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			err = telemetry.Error(ctx, span, err, fmt.Sprintf("app %s has no helm release in namespace %s: only apps deployed with porter apply v1 can be cloned", appName, namespace))
+			return apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest)
+		}
+		return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting app release"))
+	}
+
+	releases := []*release.Release{appRelease}
+
+	preDeployRelease, err := helmAgent.GetRelease(ctx, utils.PredeployJobNameFromPorterAppName(appName), 0, false)
+	if err == nil {
+		releases = append(releases, preDeployRelease)
+	} else if !errors.Is(err, driver.ErrReleaseNotFound) {
+		return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting pre-deploy job release"))
+	}
+
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting k8s agent"))
+	}
+
+	targetK8sAgent, err := c.GetAgent(r, targetCluster, newNamespace)
+	if err != nil {
+		return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting k8s agent for target cluster"))
+	}
+
+	targetHelmAgent, err := c.GetHelmAgent(ctx, r, targetCluster, newNamespace)
+	if err != nil {
+		return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting helm agent for target cluster"))
+	}
+
+	// a release left behind by an app which was deleted from the DB is not overwritten
+	_, err = targetHelmAgent.GetRelease(ctx, newName, 0, false)
+	if err == nil {
+		err = telemetry.Error(ctx, span, nil, fmt.Sprintf("a helm release named %s already exists in namespace %s of the target cluster", newName, newNamespace))
+		return apierrors.NewErrPassThroughToClient(err, http.StatusConflict)
+	}
+	if !errors.Is(err, driver.ErrReleaseNotFound) {
+		return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting release in target cluster"))
+	}
+
+	registries, err := c.Repo().Registry().ListRegistriesByProjectID(project.ID)
+	if err != nil {
+		return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error listing registries"))
+	}
+
+	_, err = targetK8sAgent.CreateNamespace(ctx, newNamespace, nil)
+	if err != nil {
+		return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error creating namespace"))
+	}
+
+	// pull secrets are generated again in the new namespace by the post-renderer, from the registries of the project
+	err = copyAppConfigBetween(ctx, k8sAgent.Clientset, targetK8sAgent.Clientset, namespace, newNamespace, appName, newName, true)
+	if err != nil {
+		return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error copying app config to new namespace"))
+	}
+
+	subdomainOpts := SubdomainCreateOpts{
+		k8sAgent:      targetK8sAgent,
+		dnsRepo:       c.Repo().DNSRecord(),
+		dnsClient:     c.Config().DNSClient,
+		appRootDomain: c.Config().ServerConf.AppRootDomain,
+		stackName:     newName,
+	}
+
+	var installed []string
+	uninstall := func() {
+		for _, name := range installed {
+			if _, err := targetHelmAgent.UninstallChart(ctx, name); err != nil {
+				_ = telemetry.Error(ctx, span, err, fmt.Sprintf("error uninstalling release %s", name))
+			}
+		}
+	}
+
+	for _, rel := range releases {
+		cloneName := newName + strings.TrimPrefix(rel.Name, appName)
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: telemetry.AttributeKey(fmt.Sprintf("clone-%s", rel.Name)), Value: cloneName})
+
+		values := clonedAppValues(rel.Config, appName, newName)
+		if rel.Name == appName {
+			for _, serviceValues := range values {
+				service, ok := serviceValues.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if err := createSubdomainIfRequired(service, subdomainOpts); err != nil {
+					uninstall()
+					return apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error creating subdomain for cloned app"))
+				}
+			}
+		}
+
+		_, err := targetHelmAgent.InstallChart(ctx, &helm.InstallChartConfig{
+			Chart:      chartWithoutDependencies(rel.Chart),
+			Name:       cloneName,
+			Namespace:  newNamespace,
+			Values:     values,
+			Cluster:    targetCluster,
+			Repo:       c.Repo(),
+			Registries: registries,
+		}, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection)
+		if err != nil {
+			uninstall()
+			return apierrors.NewErrPassThroughToClient(telemetry.Error(ctx, span, err, fmt.Sprintf("error installing release %s as %s", rel.Name, cloneName)), http.StatusBadRequest)
+		}
+
+		installed = append(installed, cloneName)
+	}
+
+	return nil
+}
+
+// porterPullSecretName matches the names of the image pull secrets Porter generates for the registries of a project
+var porterPullSecretName = regexp.MustCompile(`^porter-[a-z0-9]+-[0-9]+$`)
+
+func isPorterPullSecretName(name string) bool {
+	return porterPullSecretName.MatchString(name)
+}
+
+// clonedAppValues returns a copy of the helm values of an app for a copy of it named newName. The services are
+// relabelled as they are for a rename, and lose the porter.run subdomains generated for the app, the custom domains
+// set in its porter.yaml and the references to the pull secrets Porter generated, which are created again for the copy.
+func clonedAppValues(values map[string]interface{}, appName, newName string) map[string]interface{} {
+	cloned := renamedAppValues(values, appName, newName)
+
+	stripGenerated := func(values map[string]interface{}) {
+		if ingress, ok := values["ingress"].(map[string]interface{}); ok {
+			delete(ingress, "porter_hosts")
+		}
+
+		// custom domains are unique within a project, so they stay with the app which sets them in porter.yaml
+		setCustomDomains(values, "", nil, nil, "")
+
+		secrets, ok := values["imagePullSecrets"].([]interface{})
+		if !ok {
+			return
+		}
+
+		kept := make([]interface{}, 0, len(secrets))
+		for _, secret := range secrets {
+			if ref, ok := secret.(map[string]interface{}); ok {
+				if name, _ := ref["name"].(string); isPorterPullSecretName(name) {
+					continue
+				}
+			}
+			kept = append(kept, secret)
+		}
+
+		if len(kept) == 0 {
+			delete(values, "imagePullSecrets")
+		} else {
+			values["imagePullSecrets"] = kept
+		}
+	}
+
+	stripGenerated(cloned)
+	for _, serviceValues := range cloned {
+		if service, ok := serviceValues.(map[string]interface{}); ok {
+			stripGenerated(service)
+		}
+	}
+
+	return cloned
+}
@@ -0,0 +1,107 @@
+package porter_app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/porter_app"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClonedAppValues(t *testing.T) {
+	values := map[string]interface{}{
+		"web-web": map[string]interface{}{
+			"labels": map[string]interface{}{porter_app.LabelKey_PartOf: "storefront"},
+			"ingress": map[string]interface{}{
+				"enabled":      true,
+				"porter_hosts": []interface{}{"storefront-8f2k1.onporter.run"},
+				"hosts":        []interface{}{"shop.example.com"},
+			},
+			"imagePullSecrets": []interface{}{
+				map[string]interface{}{"name": "porter-ecr-3"},
+				map[string]interface{}{"name": "vendor-registry"},
+			},
+		},
+		"worker-wkr": map[string]interface{}{
+			"imagePullSecrets": []interface{}{map[string]interface{}{"name": "porter-gcr-7"}},
+		},
+	}
+
+	cloned := clonedAppValues(values, "storefront", "storefront-staging")
+
+	web := cloned["web-web"].(map[string]interface{})
+	if got := web["labels"].(map[string]interface{})[porter_app.LabelKey_PartOf]; got != "storefront-staging" {
+		t.Errorf("expected the service to be relabelled for the copy, got %v", got)
+	}
+
+	ingress := web["ingress"].(map[string]interface{})
+	if _, ok := ingress["porter_hosts"]; ok {
+		t.Errorf("expected the generated subdomains to be removed, got %v", ingress)
+	}
+	if hosts := ingress["hosts"].([]interface{}); len(hosts) != 1 {
+		t.Errorf("expected the custom domains to be kept, got %v", hosts)
+	}
+
+	secrets := web["imagePullSecrets"].([]interface{})
+	if len(secrets) != 1 || secrets[0].(map[string]interface{})["name"] != "vendor-registry" {
+		t.Errorf("expected only the pull secrets which Porter did not generate to be kept, got %v", secrets)
+	}
+	if _, ok := cloned["worker-wkr"].(map[string]interface{})["imagePullSecrets"]; ok {
+		t.Error("expected services with only generated pull secrets to leave them out")
+	}
+
+	original := values["web-web"].(map[string]interface{})["ingress"].(map[string]interface{})
+	if _, ok := original["porter_hosts"]; !ok {
+		t.Error("expected the values of the release to be left unchanged")
+	}
+}
+
+func TestCopyAppConfigBetweenClusters(t *testing.T) {
+	ctx := context.Background()
+	namespace := "porter-stack-storefront"
+	newNamespace := "porter-stack-storefront-staging"
+
+	source := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "storefront-env", Namespace: namespace},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"STRIPE_KEY": []byte("sk_test_4eC39HqLyjWDarjtT1zdp7dc")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "porter-ecr-3", Namespace: namespace},
+			Type:       corev1.SecretTypeDockerConfigJson,
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "vendor-registry", Namespace: namespace},
+			Type:       corev1.SecretTypeDockerConfigJson,
+		},
+	)
+	target := fake.NewSimpleClientset()
+
+	if err := copyAppConfigBetween(ctx, source, target, namespace, newNamespace, "storefront", "storefront-staging", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secrets, err := target.CoreV1().Secrets(newNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, secret := range secrets.Items {
+		names[secret.Name] = true
+	}
+	if len(names) != 2 || !names["storefront-env"] || !names["vendor-registry"] {
+		t.Errorf("expected every secret but the generated pull secret to be copied to the target cluster, got %v", names)
+	}
+
+	copied, err := source.CoreV1().Secrets(newNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(copied.Items) != 0 {
+		t.Errorf("expected nothing to be copied within the source cluster, got %v", copied.Items)
+	}
+}
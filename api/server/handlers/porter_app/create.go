@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -30,16 +31,27 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
 	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/adapter"
 	"github.com/porter-dev/porter/internal/helm"
 	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/integrations/secretstores"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/porter_app/helmimport"
+	"github.com/porter-dev/porter/internal/porter_app/lint"
+	"github.com/porter-dev/porter/internal/registry"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/stefanmcshane/helm/pkg/chart"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"gorm.io/gorm"
 )
 
 type CreatePorterAppHandler struct {
 	handlers.PorterHandlerReadWriter
 	authz.KubernetesAgentGetter
+
+	// dryRun is set for the dry run route, whose requests are dry runs whether or not they set DryRun
+	dryRun bool
 }
 
 func NewCreatePorterAppHandler(
@@ -53,6 +65,19 @@ func NewCreatePorterAppHandler(
 	}
 }
 
+// NewDryRunPorterAppHandler returns a CreatePorterAppHandler which builds and renders the charts of an app without
+// deploying it, responding with a CreatePorterAppDryRunResponse
+func NewDryRunPorterAppHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreatePorterAppHandler {
+	handler := NewCreatePorterAppHandler(config, decoderValidator, writer)
+	handler.dryRun = true
+
+	return handler
+}
+
 func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
@@ -67,6 +92,22 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
 		return
 	}
+	if c.dryRun {
+		request.DryRun = true
+	}
+
+	if maxTimeout := maxHelmTimeout(c.Config()); maxTimeout > 0 && time.Duration(request.TimeoutSeconds)*time.Second > maxTimeout {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("timeout_seconds must be at most %d", int(maxTimeout.Seconds())))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	// an app is linked to either a github app installation or a gitlab integration
+	if request.GitRepoID != 0 && request.GitlabIntegrationID != 0 {
+		err := telemetry.Error(ctx, span, nil, "git_repo_id and gitlab_integration_id cannot both be set")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
 
 	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
 	if reqErr != nil {
@@ -75,14 +116,50 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	appName, nameErr := normalizeAppName(appName, c.Config().ServerConf.LowercasePorterAppNames)
+	if nameErr != nil {
+		err := telemetry.Error(ctx, span, nameErr, "invalid app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Deploy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	// the app is read before the deploy takes either path, since porter apply v2 can only deploy an existing app, and a
+	// new app must not already exist
+	var existingApp *models.PorterApp
+	if app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName); err == nil {
+		existingApp = app
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading app from DB")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
 	// TODO (POR-2170): Deprecate this entire endpoint in favor of v2 endpoints
 	if project.GetFeatureFlag(models.ValidateApplyV2, c.Config().LaunchDarklyClient) {
-		porterApp, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
-		if err != nil {
-			err := telemetry.Error(ctx, span, err, "porter app not found in cluster")
+		if request.DryRun {
+			err := telemetry.Error(ctx, span, nil, "dry runs are not supported for projects using porter apply v2")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		if existingApp == nil {
+			err := telemetry.Error(ctx, span, nil, "porter app not found in cluster")
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
 			return
 		}
+		porterApp := existingApp
+
+		if c.Config().ClusterControlPlaneClient == nil {
+			err := telemetry.Error(ctx, span, nil, "cluster control plane client cannot be nil")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
 
 		appInstance, err := appInstanceFromAppName(ctx, appInstanceFromAppNameInput{
 			ProjectID: project.ID,
@@ -132,6 +209,7 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 
 		c.WriteResult(w, r, &types.PorterApp{
 			ID:                 porterApp.ID,
+			UUID:               porterApp.UUID.String(),
 			ProjectID:          project.ID,
 			ClusterID:          cluster.ID,
 			Name:               appName,
@@ -140,8 +218,87 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if request.GitlabIntegrationID != 0 {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "gitlab-integration-id", Value: request.GitlabIntegrationID})
+
+		_, err := c.Repo().GitlabIntegration().ReadGitlabIntegration(project.ID, request.GitlabIntegrationID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = telemetry.Error(ctx, span, err, "gitlab integration not found in project")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+				return
+			}
+
+			err = telemetry.Error(ctx, span, err, "error reading gitlab integration")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	var gitSourceWarnings []string
+	if !request.SkipGitValidation {
+		gitSource, err := validateRequestGitSource(ctx, c.Config(), project.ID, cluster.ID, appName, request)
+		if err != nil {
+			var sourceErr *gitSourceError
+			if errors.As(err, &sourceErr) {
+				err = telemetry.Error(ctx, span, err, "git source does not match the linked repository")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusUnprocessableEntity))
+				return
+			}
+
+			err = telemetry.Error(ctx, span, err, "error validating git source")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		// the repository is stored as github spells it, so it matches the names in github webhooks
+		if request.RepoName != "" && gitSource.RepoName != "" {
+			request.RepoName = gitSource.RepoName
+		}
+		gitSourceWarnings = gitSource.Warnings
+	}
+
+	helmTimeout := c.Config().ServerConf.HelmTimeout
+	if request.TimeoutSeconds != 0 {
+		helmTimeout = time.Duration(request.TimeoutSeconds) * time.Second
+	}
+
+	// dry runs do not change the stack, so they do not wait for other deploys
+	if !request.DryRun {
+		releaseDeployLock, err := acquireDeployLock(ctx, c.Config(), cluster.ID, appName, helmTimeout)
+		if err != nil {
+			if errors.Is(err, adapter.ErrLockNotAcquired) {
+				err = telemetry.Error(ctx, span, nil, fmt.Sprintf("another deploy of %s is in progress, retry once it has finished", appName))
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+				return
+			}
+
+			err = telemetry.Error(ctx, span, err, "error acquiring deploy lock")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		defer releaseDeployLock()
+
+		// a deploy which held the lock may have created the app since it was read
+		if existingApp == nil {
+			if app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName); err == nil {
+				existingApp = app
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				err = telemetry.Error(ctx, span, err, "error reading app from DB")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
+	}
+
 	namespace := utils.NamespaceFromPorterAppName(appName)
-	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "application-name", Value: appName})
+	// the attributes of the create flow are only built if they are recorded, since it is one of the busiest handlers
+	if telemetry.Enabled() {
+		telemetry.WithAttributes(span,
+			telemetry.AttributeKV{Key: "application-name", Value: appName},
+			telemetry.AttributeKV{Key: "dry-run", Value: request.DryRun},
+		)
+	}
 
 	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
 	if err != nil {
@@ -160,6 +317,70 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	helmRelease, err := helmAgent.GetRelease(ctx, appName, 0, false)
 	shouldCreate := err != nil
 
+	// the services of the app which run in other namespaces are parsed and deployed along with the release of the app
+	getNamespaceHelmAgent := func(namespace string) (*helm.Agent, error) {
+		return c.GetHelmAgent(ctx, r, cluster, namespace)
+	}
+	var previousNamespaceReleases map[string]*release.Release
+	var previousServiceNamespaces map[string]string
+	if !shouldCreate {
+		previousNamespaceReleases, err = mergeServiceNamespaceReleases(ctx, helmRelease, getNamespaceHelmAgent)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error reading releases of service namespaces")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		previousServiceNamespaces = servicesByNamespace(helmRelease.Config, namespace)
+	}
+
+	// an app without a release, such as one which another request is creating, is checked before anything is installed,
+	// so that a duplicate create does not install a chart which no app in the database owns
+	if shouldCreate && existingApp != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "existing-app-id", Value: existingApp.ID})
+		_ = telemetry.Error(ctx, span, nil, "app with name already exists in cluster")
+		w.WriteHeader(http.StatusConflict)
+		c.WriteResult(w, r, existingApp.ToPorterAppType())
+		return
+	}
+
+	// deploying a paused app would scale its deployments back up without resuming its cron jobs
+	if existingApp != nil && existingApp.PausedAt != nil && !request.DryRun {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("app %s is paused, resume it before deploying", appName))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	// apps which deploy an external image have no build settings, so they are never built or given build settings
+	sourceType := request.SourceType
+	if sourceType == "" && existingApp != nil {
+		sourceType = existingApp.SourceTypeOrInferred()
+	}
+	if sourceType == "" {
+		sourceType = types.PorterAppSourceType_Build
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "source-type", Value: string(sourceType)})
+
+	if sourceType == types.PorterAppSourceType_Image {
+		if fields := requestBuildFields(request); len(fields) > 0 {
+			err := telemetry.Error(ctx, span, nil, fmt.Sprintf("app %s deploys an external image and cannot be given build settings: %s", appName, strings.Join(fields, ", ")))
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+		if shouldCreate && !request.ImageInfo.Complete() {
+			err := telemetry.Error(ctx, span, nil, "apps which deploy an external image must provide both the repository and tag of the image")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	// tags are only changed by requests which set them
+	tags, err := normalizeTags(request.Tags)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid tags")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
 	porterYamlBase64 := request.PorterYAMLBase64
 	porterYaml, err := base64.StdEncoding.DecodeString(porterYamlBase64)
 	if err != nil {
@@ -194,6 +415,18 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 			imageInfo = attemptToGetImageInfoFromRelease(helmRelease.Config)
 		}
 	}
+	for service, serviceImage := range request.ServiceImageInfo {
+		if !serviceImage.Complete() {
+			err = telemetry.Error(ctx, span, nil, fmt.Sprintf("incomplete image info provided for service %s: must provide both repository and tag", service))
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+	// stacks which build an image per service may not build a default image, in which case the pre-deploy job and
+	// services without their own image run the first service image
+	if !imageInfo.Complete() && len(request.ServiceImageInfo) > 0 {
+		imageInfo = request.ServiceImageInfo[defaultImageService(request.ServiceImageInfo)]
+	}
 	if shouldCreate {
 		releaseValues = nil
 		releaseDependencies = nil
@@ -202,52 +435,118 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		releaseDependencies = helmRelease.Chart.Metadata.Dependencies
 	}
 
-	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "image-repo", Value: imageInfo.Repository}, telemetry.AttributeKV{Key: "image-tag", Value: imageInfo.Tag})
+	// parse merges the new values into those of the release, so the values they are diffed against are copied first
+	var previousValues map[string]interface{}
+	if request.ShowDiff && !shouldCreate {
+		previousValues, err = copyValues(helmRelease.Config)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error copying release values")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	// env variables set outside porter.yaml are kept, unless the update replaces the whole release. See release_env.go
+	// for the precedence of each source.
+	var existingEnv map[string]map[string]interface{}
+	if !shouldCreate && !request.OverrideRelease {
+		existingEnv = releaseEnv(helmRelease.Config)
+	}
+
+	if telemetry.Enabled() {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "image-repo", Value: imageInfo.Repository}, telemetry.AttributeKV{Key: "image-tag", Value: imageInfo.Tag})
+	}
 
-	if request.Builder == "" {
-		// attempt to get builder from db
-		app, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
-		if err == nil {
-			request.Builder = app.Builder
+	// images which porter did not build are run as they are, so they are never given the buildpack launcher
+	var injectLauncher bool
+	if sourceType == types.PorterAppSourceType_Build {
+		if request.Builder == "" {
+			// attempt to get builder from db
+			app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+			if err == nil {
+				request.Builder = app.Builder
+			}
 		}
+		injectLauncher = strings.Contains(request.Builder, "heroku") ||
+			strings.Contains(request.Builder, "paketo")
 	}
-	injectLauncher := strings.Contains(request.Builder, "heroku") ||
-		strings.Contains(request.Builder, "paketo")
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "builder", Value: request.Builder})
 
-	if shouldCreate {
+	// the namespace and subdomains created for a new app are removed if the app fails to be created, so that retrying
+	// under another name does not strand them. Updates of existing apps never remove them.
+	var created *createdResources
+	var createSucceeded bool
+	if shouldCreate && !request.DryRun {
+		created = &createdResources{}
+		defer func() {
+			if createSucceeded {
+				return
+			}
+			if err := removeCreatedResources(ctx, k8sAgent, c.Repo().DNSRecord(), created); err != nil {
+				telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "remove-created-resources-error", Value: err.Error()})
+			}
+		}()
+
 		// create the namespace if it does not exist already
-		_, err = k8sAgent.CreateNamespace(namespace, nil)
+		err = createAppNamespace(ctx, k8sAgent, namespace, tagLabels(tags), created)
 		if err != nil {
 			err = telemetry.Error(ctx, span, err, "error creating namespace")
 			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
-		cloneEnvGroup(c, w, r, k8sAgent, request.EnvGroups, namespace)
 	}
 
-	if imageInfo.Repository == "" || imageInfo.Tag == "" {
+	if !request.DryRun && len(request.EnvGroups) > 0 {
+		if err := syncEnvGroups(ctx, k8sAgent, request.EnvGroups, namespace); err != nil {
+			err = telemetry.Error(ctx, span, err, "error syncing env groups")
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "env-groups", Value: strings.Join(request.EnvGroups, ",")})
+			if errors.Is(err, kubernetes.IsNotFoundError) {
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+				return
+			}
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	if !imageInfo.Complete() {
 		err = telemetry.Error(ctx, span, nil, "incomplete image info provided: must provide both repository and tag")
 		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
 		return
 	}
 
+	images := []string{imageInfo.Repository}
+	for _, serviceImage := range request.ServiceImageInfo {
+		images = append(images, serviceImage.Repository)
+	}
+	registries, registryWarnings, err := registry.DeployRegistriesForImages(c.Repo(), registries, images, c.Config().DOConf)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error checking registry credentials")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "skipped-registries", Value: len(registryWarnings)})
+
 	var addCustomNodeSelector bool
 	if (cluster.ProvisionedBy == "CAPI" && cluster.CloudProvider == "GCP") || cluster.GCPIntegrationID != 0 {
 		addCustomNodeSelector = true
 	}
 
-	chart, values, preDeployJobValues, err := parse(
+	// a list of env groups replaces the env groups the app was deployed with, so that removed groups are detached from it
+	userUpdate := request.UserUpdate || request.EnvGroups != nil
+
+	chart, values, preDeployJobValues, warnings, err := parse(
 		ctx,
 		ParseConf{
 			PorterAppName:             appName,
 			PorterYaml:                porterYaml,
 			ImageInfo:                 imageInfo,
+			ServiceImageInfo:          request.ServiceImageInfo,
 			ServerConfig:              c.Config(),
 			ProjectID:                 cluster.ProjectID,
-			UserUpdate:                request.UserUpdate,
+			UserUpdate:                userUpdate,
 			EnvGroups:                 request.EnvGroups,
 			EnvironmentGroups:         request.EnvironmentGroups,
 			Namespace:                 namespace,
@@ -259,20 +558,179 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 				dnsClient:     c.Config().DNSClient,
 				appRootDomain: c.Config().ServerConf.AppRootDomain,
 				stackName:     appName,
+				clusterIssuer: c.Config().ServerConf.CustomDomainClusterIssuer,
+				created:       created,
 			},
 			InjectLauncherToStartCommand: injectLauncher,
 			ShouldValidateHelmValues:     shouldCreate,
 			FullHelmValues:               request.FullHelmValues,
 			AddCustomNodeSelector:        addCustomNodeSelector,
 			RemoveDeletedServices:        request.OverrideRelease,
+			SchedulingDefaults:           types.ClusterSchedulingDefaults(cluster.SchedulingDefaults),
+			Capabilities:                 cluster.Capabilities.ToClusterCapabilitiesType(),
+			NetworkPolicies:              cluster.NetworkPoliciesEnabled,
+			IngressNamespace:             c.Config().ServerConf.NetworkPolicyIngressNamespace,
+			Observability:                project.ObservabilityConfig.ToProjectObservabilityConfigType(),
+			SecretResolver:               secretstores.NewResolver(secretstores.RepoStoreLookup(c.Repo(), cluster.ProjectID)),
+			DryRun:                       request.DryRun,
 		},
 	)
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "parse error")
 		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
+		// porter.yaml which fails the lint is reported with the line and column of each error, such as an invalid cron
+		// schedule
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, lint.ErrInvalid) {
+			statusCode = http.StatusBadRequest
+		}
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+		return
+	}
+
+	if err := checkServiceNamespaceMoves(previousServiceNamespaces, values, namespace, request.MigrateServiceNamespaces); err != nil {
+		err = telemetry.Error(ctx, span, err, "deploy moves services to another namespace")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	var existingAppID uint
+	if existingApp != nil {
+		existingAppID = existingApp.ID
+	}
+	customDomains := customDomainsFromValues(values)
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "custom-domains", Value: len(customDomains)})
+
+	if err := checkCustomDomainsAvailable(ctx, c.Repo(), project.ID, existingAppID, customDomains); err != nil {
+		err = telemetry.Error(ctx, span, err, "error checking custom domains")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if existingEnv != nil {
+		preserved := preserveReleaseEnv(values, existingEnv)
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "preserved-env-variables", Value: preserved})
+	}
+
+	// services scaled by hand since the last deploy keep their replicas, unless the porter.yaml sets others
+	if existingApp != nil {
+		reverted := revertedManualScales(existingApp.ManualScales, values)
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "reverted-manual-scales", Value: len(reverted)})
+		warnings = append(warnings, reverted...)
+	}
+
+	// the scaling schedules of the services are kept on the app for the scaling scheduler. Full helm values replace the
+	// porter.yaml, so deploying them keeps the schedules of the last deploy.
+	var scalingSchedules models.PorterAppScalingSchedules
+	if request.FullHelmValues == "" {
+		scalingSchedules, err = servicesScalingSchedules(porterYaml, values)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error reading scaling schedules")
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	var valuesDiff []types.HelmValueChange
+	if previousValues != nil {
+		valuesDiff, err = diffValues(previousValues, values)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error diffing release values")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "values-diff-changes", Value: len(valuesDiff)})
+	}
+
+	if request.DryRun {
+		res, err := renderDryRun(ctx, renderDryRunInput{
+			HelmAgent:          helmAgent,
+			AppName:            appName,
+			Chart:              chart,
+			Values:             values,
+			PreDeployJobValues: preDeployJobValues,
+			HelmRepoURL:        c.Config().ServerConf.DefaultApplicationHelmRepoURL,
+		})
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error rendering charts")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		res.Warnings = append(warnings, registryWarnings...)
+		res.Warnings = append(res.Warnings, gitSourceWarnings...)
+		res.ValuesDiff = valuesDiff
+		c.WriteResult(w, r, res)
+		return
+	}
+
+	// the services which run in other namespaces are installed by releases of the app in those namespaces, so the
+	// release in the namespace of the app only installs the others
+	appChart, appValues, namespaceReleases, err := splitServiceNamespaces(chart, values)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error splitting services by namespace")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "service-namespaces", Value: len(namespaceReleases)})
+
+	if err := checkServiceNamespacesExist(ctx, k8sAgent.Clientset, namespaceReleases); err != nil {
+		err = telemetry.Error(ctx, span, err, "error checking service namespaces")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	namespaceEnvGroups := request.EnvGroups
+	if namespaceEnvGroups == nil && existingApp != nil {
+		namespaceEnvGroups = existingApp.EnvGroups
+	}
+	for _, namespaceRelease := range namespaceReleases {
+		if err := syncEnvGroups(ctx, k8sAgent, namespaceEnvGroups, namespaceRelease.namespace); err != nil {
+			err = telemetry.Error(ctx, span, err, "error syncing env groups to service namespace")
+			if errors.Is(err, kubernetes.IsNotFoundError) {
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+				return
+			}
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "helm-timeout", Value: helmTimeout.String()},
+		telemetry.AttributeKV{Key: "wait-for-jobs", Value: request.WaitForJobs},
+	)
+
+	deployMessage := porter_app.NormalizeDeployMessage(request.Message)
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "git-commit-sha", Value: request.GitCommitSHA})
+	deployDetails := deployEventDetails{
+		ChartDigests:      loader.ChartDigests(chart),
+		RegistryWarnings:  registryWarnings,
+		Message:           deployMessage,
+		GitCommitSHA:      strings.ToLower(request.GitCommitSHA),
+		GitCommitMessage:  porter_app.TruncateCommitMessage(request.GitCommitMessage),
+		ServiceNamespaces: serviceNamespacesFromValues(values),
+	}
+
+	namespaceDeploy := deployServiceNamespacesInput{
+		AppName:      appName,
+		Releases:     namespaceReleases,
+		Previous:     previousNamespaceReleases,
+		GetHelmAgent: getNamespaceHelmAgent,
+		Conf: helm.InstallChartConfig{
+			Cluster:     cluster,
+			Repo:        c.Repo(),
+			Registries:  registries,
+			Timeout:     helmTimeout,
+			WaitForJobs: request.WaitForJobs,
+		},
+		Config:   c.Config(),
+		Rollback: shouldCreate || !request.DisableRollbackOnFailure,
+	}
+
+	// the revision of the pre-deploy job chart, if this deploy installed or upgraded it
+	var preDeployRevision int
 
 	if shouldCreate {
 		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "installing-application", Value: true})
@@ -290,63 +748,84 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 				c.Repo(),
 			)
 			if err != nil {
+				recordFailedPreDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, &deployFailure{stage: deployStage_PreDeployConfig, err: err})
 				err = telemetry.Error(ctx, span, err, "error making config for pre-deploy job chart")
 				telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
 				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 				return
 			}
-			_, err = helmAgent.InstallChart(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection)
+			conf.Timeout = helmTimeout
+			conf.WaitForJobs = request.WaitForJobs
+
+			preDeployRelease, err := helmAgent.InstallChart(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection)
 			if err != nil {
-				err = telemetry.Error(ctx, span, err, "error installing pre-deploy job chart")
-				telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "install-pre-deploy-job-error", Value: err})
+				pods := rolloutTimeoutPods(ctx, k8sAgent.Clientset, namespace, request.WaitForJobs, err)
+				failure := &deployFailure{stage: deployStage_PreDeployInstall, err: err}
+				_, failure.cleanupErr = helmAgent.UninstallChart(ctx, fmt.Sprintf("%s-r", appName))
+				recordFailedPreDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, failure)
+
+				err = telemetry.Error(ctx, span, failure, "error installing pre-deploy job chart")
 				telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
-				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-				_, uninstallChartErr := helmAgent.UninstallChart(ctx, fmt.Sprintf("%s-r", appName))
-				if uninstallChartErr != nil {
-					uninstallChartErr = telemetry.Error(ctx, span, err, "error uninstalling pre-deploy job chart after failed install")
-					c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(uninstallChartErr, http.StatusInternalServerError))
-				}
+				handleDeployError(c.Config(), w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError), pods)
 				return
 			}
+			preDeployRevision = preDeployRelease.Version
+		}
+
+		rollbackNamespaces, failure := deployServiceNamespaces(ctx, namespaceDeploy)
+		if failure != nil {
+			c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appName)
+			recordFailedDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, failure)
+
+			// the deploy is a client error unless the releases which were deployed could not be rolled back
+			statusCode := http.StatusBadRequest
+			if failure.cleanupErr != nil {
+				statusCode = http.StatusInternalServerError
+			}
+
+			err = telemetry.Error(ctx, span, failure, "error deploying services in other namespaces")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+			return
 		}
 
 		conf := &helm.InstallChartConfig{
-			Chart:      chart,
-			Name:       appName,
-			Namespace:  namespace,
-			Values:     values,
-			Cluster:    cluster,
-			Repo:       c.Repo(),
-			Registries: registries,
+			Chart:       appChart,
+			Name:        appName,
+			Namespace:   namespace,
+			Values:      appValues,
+			Cluster:     cluster,
+			Repo:        c.Repo(),
+			Registries:  registries,
+			Timeout:     helmTimeout,
+			WaitForJobs: request.WaitForJobs,
 		}
 
 		// create the app chart
+		helmStart := time.Now()
 		release, err := helmAgent.InstallChart(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection)
+		deployDetails.HelmDuration = time.Since(helmStart)
+		// status reads cached before the deploy are stale whether or not it succeeded
+		c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appName)
 		if err != nil {
-			err = telemetry.Error(ctx, span, err, "error installing app chart")
-			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
-			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
-			_, err = helmAgent.UninstallChart(ctx, appName)
-			if err != nil {
-				err = telemetry.Error(ctx, span, err, "error uninstalling app chart after failed install")
-				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			pods := rolloutTimeoutPods(ctx, k8sAgent.Clientset, namespace, request.WaitForJobs, err)
+			failure := &deployFailure{stage: deployStage_Install, err: err}
+			_, failure.cleanupErr = helmAgent.UninstallChart(ctx, appName)
+			failure.cleanupErr = errors.Join(failure.cleanupErr, rollbackNamespaces(ctx))
+			recordFailedDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, failure)
+
+			// the install is a client error unless the failed release could not be removed, which breaks the next deploys
+			statusCode := http.StatusBadRequest
+			if failure.cleanupErr != nil {
+				statusCode = http.StatusInternalServerError
 			}
 
-			return
-		}
-
-		existing, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
-		if err != nil {
-			err = telemetry.Error(ctx, span, err, "error reading app from DB")
+			err = telemetry.Error(ctx, span, failure, "error installing app chart")
 			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
-			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-			return
-		} else if existing.Name != "" {
-			err = telemetry.Error(ctx, span, err, "app with name already exists in project")
-			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
-			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden))
+			handleDeployError(c.Config(), w, r, apierrors.NewErrPassThroughToClient(err, statusCode), pods)
 			return
 		}
+		// the app is installed, so the resources created for it are kept even if the rest of the request fails
+		createSucceeded = true
 
 		app := &models.PorterApp{
 			Name:      appName,
@@ -356,6 +835,8 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 			GitRepoID: request.GitRepoID,
 			GitBranch: request.GitBranch,
 
+			GitlabIntegrationID: request.GitlabIntegrationID,
+
 			BuildContext:   request.BuildContext,
 			Builder:        request.Builder,
 			Buildpacks:     request.Buildpacks,
@@ -363,22 +844,54 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 			ImageRepoURI:   request.ImageRepoURI,
 			PullRequestURL: request.PullRequestURL,
 			PorterYamlPath: request.PorterYamlPath,
+			SourceType:     sourceType,
+
+			ScalingSchedules: scalingSchedules,
+			EnvGroups:        request.EnvGroups,
+			Tags:             tags,
+		}
+		if sourceType == types.PorterAppSourceType_Image {
+			app.ImageRepoURI = imageInfo.Repository
 		}
 
-		// create the db entry
+		// create the db entry. The unique index on the cluster and name of apps rejects an app written by a concurrent
+		// create which was not serialized by the deploy lock.
 		porterApp, err := c.Repo().PorterApp().UpdatePorterApp(app)
 		if err != nil {
+			if existing, readErr := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName); readErr == nil {
+				telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "existing-app-id", Value: existing.ID})
+				_ = telemetry.Error(ctx, span, err, "app with name was created by another request")
+				w.WriteHeader(http.StatusConflict)
+				c.WriteResult(w, r, existing.ToPorterAppType())
+				return
+			}
+
 			err = telemetry.Error(ctx, span, err, "error writing app to DB")
 			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
 
+		// the domains are checked again along with their certificates, so failing to record them is not returned to the client
+		if err := recordCustomDomains(ctx, c.Repo().PorterAppDomain(), porterApp, customDomains); err != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "record-custom-domains-error", Value: err.Error()})
+		}
+
+		var preDeployEvent *models.PorterAppEvent
+		if preDeployRevision != 0 {
+			preDeployEvent, err = createPreDeployEvent(ctx, c.Repo().PorterAppEvent(), porterApp.ID, types.PorterAppEventStatus_Progressing, preDeployRevision, imageInfo.Tag, nil)
+			if err != nil {
+				err = telemetry.Error(ctx, span, err, "error creating pre-deploy event")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
+
 		if features.AreAgentDeployEventsEnabled(k8sAgent) {
 			serviceDeploymentStatusMap := getServiceDeploymentMetadataFromValues(values, types.PorterAppEventStatus_Progressing)
-			_, err = createNewPorterAppDeployEvent(ctx, serviceDeploymentStatusMap, porterApp.ID, 1, imageInfo.Tag, c.Repo().PorterAppEvent())
+			_, err = createNewPorterAppDeployEvent(ctx, serviceDeploymentStatusMap, porterApp.ID, 1, imageInfo.Tag, deployDetails, c.Repo().PorterAppEvent())
 		} else {
-			_, err = createOldPorterAppDeployEvent(ctx, types.PorterAppEventStatus_Success, porterApp.ID, 1, imageInfo.Tag, c.Repo().PorterAppEvent())
+			_, err = createOldPorterAppDeployEvent(ctx, types.PorterAppEventStatus_Success, porterApp.ID, 1, imageInfo.Tag, deployDetails, c.Repo().PorterAppEvent())
 		}
 		if err != nil {
 			err = telemetry.Error(ctx, span, err, "error creating porter app event")
@@ -387,7 +900,15 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 			return
 		}
 
-		c.WriteResult(w, r, porterApp.ToPorterAppTypeWithRevision(release.Version))
+		res := porterApp.ToPorterAppTypeWithRevision(release.Version)
+		res.Namespace = namespace
+		res.URLs = appURLsFromValues(values)
+		res.Warnings = append(warnings, registryWarnings...)
+		res.Warnings = append(res.Warnings, gitSourceWarnings...)
+		if preDeployEvent != nil {
+			res.PreDeployEventID = preDeployEvent.ID.String()
+		}
+		c.WriteResult(w, r, res)
 	} else {
 		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "upgrading-application", Value: true})
 
@@ -401,6 +922,7 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 					telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "deleting-pre-deploy-job", Value: true})
 					_, err = helmAgent.UninstallChart(ctx, preDeployJobName)
 					if err != nil {
+						recordFailedPreDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, &deployFailure{stage: deployStage_PreDeployUninstall, err: err})
 						err = telemetry.Error(ctx, span, err, "error uninstalling pre-deploy job chart")
 						telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
 						c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
@@ -422,29 +944,33 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 						c.Repo(),
 					)
 					if err != nil {
+						recordFailedPreDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, &deployFailure{stage: deployStage_PreDeployConfig, err: err})
 						err = telemetry.Error(ctx, span, err, "error making config for pre-deploy job chart")
 						telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
 						c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 						return
 					}
+					conf.Timeout = helmTimeout
+					conf.WaitForJobs = request.WaitForJobs
 
-					_, err = helmAgent.InstallChart(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection)
+					preDeployRelease, err := helmAgent.InstallChart(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection)
 					if err != nil {
-						err = telemetry.Error(ctx, span, err, "error installing pre-deploy job chart")
-						telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "install-pre-deploy-job-error", Value: err})
-						c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-						_, uninstallChartErr := helmAgent.UninstallChart(ctx, fmt.Sprintf("%s-r", appName))
-						if uninstallChartErr != nil {
-							uninstallChartErr = telemetry.Error(ctx, span, err, "error uninstalling pre-deploy job chart after failed install")
-							c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(uninstallChartErr, http.StatusInternalServerError))
-						}
+						pods := rolloutTimeoutPods(ctx, k8sAgent.Clientset, namespace, request.WaitForJobs, err)
+						failure := &deployFailure{stage: deployStage_PreDeployInstall, err: err}
+						_, failure.cleanupErr = helmAgent.UninstallChart(ctx, preDeployJobName)
+						recordFailedPreDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, failure)
+
+						err = telemetry.Error(ctx, span, failure, "error installing pre-deploy job chart")
 						telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
+						handleDeployError(c.Config(), w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError), pods)
 						return
 					}
+					preDeployRevision = preDeployRelease.Version
 				} else {
 					telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "updating-pre-deploy-job", Value: true})
 					chart, err := loader.LoadChartPublic(ctx, c.Config().Metadata.DefaultAppHelmRepoURL, "job", "")
 					if err != nil {
+						recordFailedPreDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, &deployFailure{stage: deployStage_PreDeployConfig, err: err})
 						err = telemetry.Error(ctx, span, err, "error loading latest job chart")
 						telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
 						c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
@@ -452,62 +978,136 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 					}
 
 					conf := &helm.UpgradeReleaseConfig{
-						Name:       helmRelease.Name,
-						Cluster:    cluster,
-						Repo:       c.Repo(),
-						Registries: registries,
-						Values:     preDeployJobValues,
-						Chart:      chart,
+						Name:        helmRelease.Name,
+						Cluster:     cluster,
+						Repo:        c.Repo(),
+						Registries:  registries,
+						Values:      preDeployJobValues,
+						Chart:       chart,
+						Timeout:     helmTimeout,
+						WaitForJobs: request.WaitForJobs,
 					}
-					_, err = helmAgent.UpgradeReleaseByValues(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection, false)
+					preDeployRelease, err := helmAgent.UpgradeReleaseByValues(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection, false)
 					if err != nil {
+						pods := rolloutTimeoutPods(ctx, k8sAgent.Clientset, namespace, request.WaitForJobs, err)
+						recordFailedPreDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, &deployFailure{stage: deployStage_PreDeployUpgrade, err: err})
 						err = telemetry.Error(ctx, span, err, "error upgrading pre-deploy job chart")
 						telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
-						c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+						handleDeployError(c.Config(), w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError), pods)
 						return
 					}
+					preDeployRevision = preDeployRelease.Version
 				}
 			}
 		}
 
+		if request.RolloutStrategy != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "rollout-strategy", Value: string(request.RolloutStrategy.Type)})
+
+			// the pre-deploy job has already run, as it does for every update, so the canary runs against migrated data
+			if failure := runCanary(ctx, k8sAgent.Clientset, namespace, appName, appValues, imageInfo, request.RolloutStrategy); failure != nil {
+				recordFailedDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, failure)
+
+				status := http.StatusInternalServerError
+				if failure.reason != "" {
+					status = http.StatusUnprocessableEntity
+				}
+				err = telemetry.Error(ctx, span, failure, "canary of application failed")
+				telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, status))
+				return
+			}
+		}
+
+		rollbackNamespaces, failure := deployServiceNamespaces(ctx, namespaceDeploy)
+		if failure != nil {
+			c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appName)
+			recordFailedDeploy(ctx, c.Config(), project.ID, cluster.ID, appName, imageInfo.Tag, failure)
+
+			// the deploy is a client error unless the releases which were deployed could not be rolled back
+			statusCode := http.StatusBadRequest
+			if failure.cleanupErr != nil {
+				statusCode = http.StatusInternalServerError
+			}
+
+			err = telemetry.Error(ctx, span, failure, "error deploying services in other namespaces")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, statusCode))
+			return
+		}
+
 		// update the app chart
 		conf := &helm.InstallChartConfig{
-			Chart:      chart,
-			Name:       appName,
-			Namespace:  namespace,
-			Values:     values,
-			Cluster:    cluster,
-			Repo:       c.Repo(),
-			Registries: registries,
+			Chart:       appChart,
+			Name:        appName,
+			Namespace:   namespace,
+			Values:      appValues,
+			Cluster:     cluster,
+			Repo:        c.Repo(),
+			Registries:  registries,
+			Timeout:     helmTimeout,
+			WaitForJobs: request.WaitForJobs,
 		}
 
 		// update the chart
+		helmStart := time.Now()
 		release, err := helmAgent.UpgradeInstallChart(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection)
+		deployDetails.HelmDuration = time.Since(helmStart)
+		// status reads cached before the deploy are stale whether or not it succeeded
+		c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appName)
 		if err != nil {
-			err = telemetry.Error(ctx, span, err, "error upgrading application")
+			pods := rolloutTimeoutPods(ctx, k8sAgent.Clientset, namespace, request.WaitForJobs, err)
+			// a failed upgrade leaves the release failed or pending, which breaks the next deploys unless it is rolled back
+			upgradeErr := rollbackFailedUpgrade(ctx, c.Config(), helmAgent, project.ID, cluster.ID, appName, helmRelease, imageInfo.Tag, err, !request.DisableRollbackOnFailure)
+			// the services in other namespaces are rolled back with the app, so that they do not run the failed revision
+			if !request.DisableRollbackOnFailure {
+				if rollbackErr := rollbackNamespaces(ctx); rollbackErr != nil {
+					upgradeErr.rollbackErr = errors.Join(upgradeErr.rollbackErr, rollbackErr)
+				}
+			}
+			err = telemetry.Error(ctx, span, upgradeErr, "error upgrading application")
 			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
-			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			handleDeployError(c.Config(), w, r, apierrors.NewErrPassThroughToClient(err, upgradeErr.statusCode()), pods)
 			return
 		}
 
+		if err := helmimport.RecordUpgrade(ctx, c.Repo().HelmReleaseImport(), cluster.ID, release); err != nil {
+			// the upgrade succeeded, so failing to record the adoption of an imported release is not returned to the client
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "record-import-adoption-error", Value: err.Error()})
+		}
+
+		// the namespaces which no longer run services of the app have their release removed once the deploy succeeded
+		for _, staleNamespace := range staleServiceNamespaces(previousNamespaceReleases, namespaceReleases) {
+			if err := uninstallServiceNamespace(ctx, getNamespaceHelmAgent, appName, staleNamespace); err != nil {
+				telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "uninstall-service-namespace-error", Value: err.Error()})
+			}
+		}
+
 		// update the DB entry
-		app, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
+		app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
 		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = telemetry.Error(ctx, span, err, "app with name does not exist in project")
+				c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+				return
+			}
 			err = telemetry.Error(ctx, span, err, "error reading app from DB")
 			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
-		if app == nil {
-			err = telemetry.Error(ctx, span, nil, "app with name does not exist in project")
-			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-yaml-base64", Value: porterYamlBase64})
-			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden))
-			return
-		}
 
 		if request.RepoName != "" {
 			app.RepoName = request.RepoName
 		}
+		// an app is linked to either a github app installation or a gitlab integration
+		if request.GitRepoID != 0 {
+			app.GitRepoID = request.GitRepoID
+			app.GitlabIntegrationID = 0
+		}
+		if request.GitlabIntegrationID != 0 {
+			app.GitlabIntegrationID = request.GitlabIntegrationID
+			app.GitRepoID = 0
+		}
 		if request.GitBranch != "" {
 			app.GitBranch = request.GitBranch
 		}
@@ -542,6 +1142,47 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		if request.PullRequestURL != "" {
 			app.PullRequestURL = request.PullRequestURL
 		}
+		if request.Tags != nil {
+			// the namespace is relabeled along with the app, so that it is labeled with the tags the app is saved with
+			if err := labelNamespaceWithTags(ctx, k8sAgent.Clientset, namespace, tags); err != nil {
+				err = telemetry.Error(ctx, span, err, "error labeling namespace with tags")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+			app.Tags = tags
+		}
+		app.SourceType = sourceType
+		if sourceType == types.PorterAppSourceType_Image {
+			// apps converted to deploy an external image keep no build settings, so they are not restored by later updates
+			app.RepoName = ""
+			app.GitRepoID = 0
+			app.GitlabIntegrationID = 0
+			app.GitBranch = ""
+			app.BuildContext = ""
+			app.Builder = ""
+			app.Buildpacks = ""
+			app.Dockerfile = ""
+			app.ImageRepoURI = imageInfo.Repository
+		}
+		// the deploy resets the replicas of the services, so the scheduler scales them again from the new schedules
+		if request.FullHelmValues == "" {
+			app.ScalingSchedules = scalingSchedules
+		} else {
+			for _, service := range app.ScalingSchedules {
+				service.Applied = nil
+			}
+		}
+
+		// the replicas of the services are those of this deploy, so the next deploy only warns of later manual scales
+		app.ManualScales = nil
+
+		// the env groups which are no longer deployed with the app are detached from it once its release stops
+		// referencing them
+		var detachedEnvGroups []string
+		if request.EnvGroups != nil {
+			detachedEnvGroups = removedEnvGroups(app.EnvGroups, request.EnvGroups)
+			app.EnvGroups = request.EnvGroups
+		}
 
 		telemetry.WithAttributes(
 			span,
@@ -563,11 +1204,32 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 			return
 		}
 
+		for _, envGroupName := range detachedEnvGroups {
+			// the app was deployed without the env group, so failing to remove its copy is not returned to the client
+			if err := envgroup.DeleteEnvGroup(k8sAgent, envGroupName, namespace); err != nil {
+				telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "detach-env-group-error", Value: fmt.Sprintf("%s: %s", envGroupName, err.Error())})
+			}
+		}
+
+		if err := recordCustomDomains(ctx, c.Repo().PorterAppDomain(), updatedPorterApp, customDomains); err != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "record-custom-domains-error", Value: err.Error()})
+		}
+
+		var preDeployEvent *models.PorterAppEvent
+		if preDeployRevision != 0 {
+			preDeployEvent, err = createPreDeployEvent(ctx, c.Repo().PorterAppEvent(), updatedPorterApp.ID, types.PorterAppEventStatus_Progressing, preDeployRevision, imageInfo.Tag, nil)
+			if err != nil {
+				err = telemetry.Error(ctx, span, err, "error creating pre-deploy event")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
+
 		if features.AreAgentDeployEventsEnabled(k8sAgent) {
 			serviceDeploymentStatusMap := getServiceDeploymentMetadataFromValues(values, types.PorterAppEventStatus_Progressing)
-			_, err = createNewPorterAppDeployEvent(ctx, serviceDeploymentStatusMap, updatedPorterApp.ID, helmRelease.Version+1, imageInfo.Tag, c.Repo().PorterAppEvent())
+			_, err = createNewPorterAppDeployEvent(ctx, serviceDeploymentStatusMap, updatedPorterApp.ID, helmRelease.Version+1, imageInfo.Tag, deployDetails, c.Repo().PorterAppEvent())
 		} else {
-			_, err = createOldPorterAppDeployEvent(ctx, types.PorterAppEventStatus_Success, updatedPorterApp.ID, helmRelease.Version+1, imageInfo.Tag, c.Repo().PorterAppEvent())
+			_, err = createOldPorterAppDeployEvent(ctx, types.PorterAppEventStatus_Success, updatedPorterApp.ID, helmRelease.Version+1, imageInfo.Tag, deployDetails, c.Repo().PorterAppEvent())
 		}
 		if err != nil {
 			err = telemetry.Error(ctx, span, err, "error creating porter app event")
@@ -576,7 +1238,16 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 			return
 		}
 
-		c.WriteResult(w, r, updatedPorterApp.ToPorterAppTypeWithRevision(release.Version))
+		res := updatedPorterApp.ToPorterAppTypeWithRevision(release.Version)
+		res.Namespace = namespace
+		res.URLs = appURLsFromValues(values)
+		res.Warnings = append(warnings, registryWarnings...)
+		res.Warnings = append(res.Warnings, gitSourceWarnings...)
+		res.ValuesDiff = valuesDiff
+		if preDeployEvent != nil {
+			res.PreDeployEventID = preDeployEvent.ID.String()
+		}
+		c.WriteResult(w, r, res)
 	}
 }
 
@@ -619,7 +1290,7 @@ func pollForRevisionNumber(ctx context.Context, input pollForRevisionNumberInput
 
 // createOldPorterAppDeployEvent creates an event for use in the activity feed
 // TODO: remove this method and all call-sites if this span no longer exists in telemetry for 4 consecutive weeks
-func createOldPorterAppDeployEvent(ctx context.Context, status types.PorterAppEventStatus, appID uint, revision int, tag string, repo repository.PorterAppEventRepository) (*models.PorterAppEvent, error) {
+func createOldPorterAppDeployEvent(ctx context.Context, status types.PorterAppEventStatus, appID uint, revision int, tag string, details deployEventDetails, repo repository.PorterAppEventRepository) (*models.PorterAppEvent, error) {
 	ctx, span := telemetry.NewSpan(ctx, "create-old-porter-app-deploy-event")
 	defer span.End()
 
@@ -634,27 +1305,74 @@ func createOldPorterAppDeployEvent(ctx context.Context, status types.PorterAppEv
 			"image_tag": tag,
 		},
 	}
+	details.addTo(event.Metadata)
+	event.Message = details.Message
 
 	err := repo.CreateEvent(ctx, &event)
 	if err != nil {
 		return nil, err
 	}
 
-	if event.ID == uuid.Nil {
-		return nil, err
-	}
-
 	return &event, nil
 }
 
+// deployEventDetails are added to the metadata of a deploy event when they are set
+type deployEventDetails struct {
+	// ChartDigests are the digests of the charts the revision was installed from
+	ChartDigests map[string]loader.ChartDigest
+	// RegistryWarnings name the registries which were skipped because their credentials are broken
+	RegistryWarnings []string
+	// RollbackFrom and RollbackTo are the revisions a rollback moved the app from and to
+	RollbackFrom int
+	RollbackTo   int
+	// Message is the release notes of the deploy, which is also stored on the event so that it can be searched
+	Message string
+	// GitCommitSHA and GitCommitMessage identify the commit which is deployed
+	GitCommitSHA     string
+	GitCommitMessage string
+	// ServiceNamespaces are the namespaces of the services which run outside of the namespace of the app, by helm name
+	ServiceNamespaces map[string]string
+	// HelmDuration is the time spent installing or upgrading the helm release of the app
+	HelmDuration time.Duration
+}
+
+func (d deployEventDetails) addTo(metadata map[string]any) {
+	if len(d.ChartDigests) != 0 {
+		metadata["chart_digests"] = d.ChartDigests
+	}
+	if len(d.RegistryWarnings) != 0 {
+		metadata["registry_warnings"] = d.RegistryWarnings
+	}
+	if d.RollbackTo != 0 {
+		metadata["rollback_from"] = d.RollbackFrom
+		metadata["rollback_to"] = d.RollbackTo
+	}
+	if d.Message != "" {
+		metadata["message"] = d.Message
+	}
+	if d.GitCommitSHA != "" {
+		metadata["git_commit_sha"] = d.GitCommitSHA
+	}
+	if d.GitCommitMessage != "" {
+		metadata["git_commit_message"] = d.GitCommitMessage
+	}
+	if len(d.ServiceNamespaces) != 0 {
+		metadata["service_namespaces"] = d.ServiceNamespaces
+	}
+	if d.HelmDuration != 0 {
+		metadata["helm_seconds"] = d.HelmDuration.Seconds()
+	}
+}
+
 // createNewPorterAppDeployEvent creates an event for use in the activity feed, supplemented with information about the
-// deployed services in serviceStatusMap as well as the image tag being deployed
+// deployed services in serviceStatusMap, the image tag being deployed and the details of the deploy
 func createNewPorterAppDeployEvent(
 	ctx context.Context,
 	serviceStatusMap map[string]types.ServiceDeploymentMetadata,
 	appID uint,
 	revision int,
 	tag string,
+	details deployEventDetails,
 	repo repository.PorterAppEventRepository,
 ) (*models.PorterAppEvent, error) {
 	ctx, span := telemetry.NewSpan(ctx, "create-new-porter-app-deploy-event")
@@ -683,6 +1401,8 @@ func createNewPorterAppDeployEvent(
 			"service_deployment_metadata": serviceStatusMap,
 		},
 	}
+	details.addTo(event.Metadata)
+	event.Message = details.Message
 
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "revision", Value: revision}, telemetry.AttributeKV{Key: "image-tag", Value: tag})
 
@@ -692,10 +1412,6 @@ func createNewPorterAppDeployEvent(
 		return nil, err
 	}
 
-	if event.ID == uuid.Nil {
-		return nil, telemetry.Error(ctx, span, nil, "event id for newly created app event is nil")
-	}
-
 	return &event, nil
 }
 
@@ -792,62 +1508,88 @@ func createPreDeployJobChart(
 	}, nil
 }
 
-func cloneEnvGroup(c *CreatePorterAppHandler, w http.ResponseWriter, r *http.Request, agent *kubernetes.Agent, envGroups []string, namespace string) {
-	for _, envGroupName := range envGroups {
-		cm, _, err := agent.GetLatestVersionedConfigMap(envGroupName, "porter-env-group")
-		if err != nil {
-			if errors.Is(err, kubernetes.IsNotFoundError) {
-				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
-					fmt.Errorf("error cloning env group: envgroup %s in namespace %s not found", envGroupName, "porter-env-group"), http.StatusNotFound,
-					"no config map found for envgroup",
-				))
-				return
-			}
+// defaultImageService returns the first service, by name, which an image was provided for
+// requestBuildFields returns the build settings which the request sets. A setting of "null", which clears it, is not
+// counted.
+func requestBuildFields(request *types.CreatePorterAppRequest) []string {
+	var fields []string
+
+	for field, value := range map[string]string{
+		"repo_name":     request.RepoName,
+		"git_branch":    request.GitBranch,
+		"build_context": request.BuildContext,
+		"builder":       request.Builder,
+		"buildpacks":    request.Buildpacks,
+		"dockerfile":    request.Dockerfile,
+	} {
+		if value != "" && value != "null" {
+			fields = append(fields, field)
+		}
+	}
+	if request.GitRepoID != 0 {
+		fields = append(fields, "git_repo_id")
+	}
+	if request.GitlabIntegrationID != 0 {
+		fields = append(fields, "gitlab_integration_id")
+	}
+	sort.Strings(fields)
 
-			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-			return
+	return fields
+}
+
+// appURLsFromValues returns the URLs the services of an app with an enabled ingress are served on, from the porter
+// subdomains and custom domains of their ingress
+func appURLsFromValues(values map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var urls []string
+
+	for key, value := range values {
+		serviceValues, ok := value.(map[string]interface{})
+		if !ok || key == "global" {
+			continue
 		}
-		secret, _, err := agent.GetLatestVersionedSecret(envGroupName, "porter-env-group")
+		ingress, err := getNestedMap(serviceValues, "ingress")
 		if err != nil {
-			if errors.Is(err, kubernetes.IsNotFoundError) {
-				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
-					fmt.Errorf("error cloning env group: envgroup %s in namespace %s not found", envGroupName, "porter-env-group"), http.StatusNotFound,
-					"no k8s secret found for envgroup",
-				))
-				return
-			}
-
-			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-			return
+			continue
+		}
+		if enabled, _ := ingress["enabled"].(bool); !enabled {
+			continue
 		}
-		vars := make(map[string]string)
-		secretVars := make(map[string]string)
 
-		for key, val := range cm.Data {
-			if !strings.Contains(val, "PORTERSECRET") {
-				vars[key] = val
+		var hosts []string
+		hosts = append(hosts, stringsFromValue(ingress["porter_hosts"])...)
+		hosts = append(hosts, stringsFromValue(ingress["hosts"])...)
+		for _, host := range hosts {
+			if host == "" || seen[host] {
+				continue
 			}
+			seen[host] = true
+			urls = append(urls, fmt.Sprintf("https://%s", host))
 		}
+	}
 
-		for key, val := range secret.Data {
-			secretVars[key] = string(val)
-		}
+	sort.Strings(urls)
 
-		configMap, err := envgroup.CreateEnvGroup(agent, types.ConfigMapInput{
-			Name:            envGroupName,
-			Namespace:       namespace,
-			Variables:       vars,
-			SecretVariables: secretVars,
-		})
-		if err != nil {
-			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-			return
-		}
+	return urls
+}
 
-		_, err = envgroup.ToEnvGroup(configMap)
-		if err != nil {
-			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-			return
-		}
+func defaultImageService(serviceImageInfo map[string]types.ImageInfo) string {
+	services := make([]string, 0, len(serviceImageInfo))
+	for service := range serviceImageInfo {
+		services = append(services, service)
 	}
+	sort.Strings(services)
+
+	return services[0]
+}
+
+// maxHelmTimeout returns the longest helm timeout a deploy may request, or 0 if any timeout is allowed. A deploy runs
+// within the request which started it, so its timeout is also bounded by the time budget of long requests.
+func maxHelmTimeout(conf *config.Config) time.Duration {
+	maxTimeout := conf.ServerConf.HelmMaxTimeout
+	if long := conf.ServerConf.RequestTimeoutLong; long > 0 && (maxTimeout == 0 || long < maxTimeout) {
+		maxTimeout = long
+	}
+
+	return maxTimeout
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/porter_app/notifications"
+	"github.com/porter-dev/porter/internal/repository/eventbuffer"
 	"github.com/porter-dev/porter/internal/telemetry"
 )
 
@@ -142,13 +143,10 @@ func (p *CreateUpdatePorterAppEventHandler) createNewAppEvent(ctx context.Contex
 	ctx, span := telemetry.NewSpan(ctx, "create-porter-app-event")
 	defer span.End()
 
-	app, err := p.Repo().PorterApp().ReadPorterAppByName(cluster.ID, porterAppName)
+	app, err := p.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, porterAppName)
 	if err != nil {
 		return types.PorterAppEvent{}, telemetry.Error(ctx, span, err, "error retrieving porter app by name for cluster")
 	}
-	if app == nil || app.ID == 0 {
-		return types.PorterAppEvent{}, telemetry.Error(ctx, span, nil, "porter app not found")
-	}
 	telemetry.WithAttributes(span,
 		telemetry.AttributeKV{Key: "porter-app-id", Value: app.ID},
 		telemetry.AttributeKV{Key: "porter-app-name", Value: porterAppName},
@@ -247,15 +245,13 @@ func (p *CreateUpdatePorterAppEventHandler) createNewAppEvent(ctx context.Contex
 		event.Metadata[k] = v
 	}
 
-	err = p.Repo().PorterAppEvent().CreateEvent(ctx, &event)
+	// the event is returned to the client, which may update it through another server, and agent events are
+	// deduplicated by listing the events of the app, so it is written before it is returned rather than queued
+	err = p.Repo().PorterAppEvent().CreateEvent(eventbuffer.Synchronous(ctx), &event)
 	if err != nil {
 		return types.PorterAppEvent{}, telemetry.Error(ctx, span, err, "error creating porter app event")
 	}
 
-	if event.ID == uuid.Nil {
-		return types.PorterAppEvent{}, telemetry.Error(ctx, span, nil, "porter app event not found")
-	}
-
 	return event.ToPorterAppEvent(), nil
 }
 
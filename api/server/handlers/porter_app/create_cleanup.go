@@ -0,0 +1,72 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/repository"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createdResources are the resources which a request creating an app made and which did not exist before it, so that
+// a create which fails removes them without removing resources which existed already
+type createdResources struct {
+	// namespace is the namespace of the app, if the request created it
+	namespace string
+	// dnsHostnames are the hostnames of the porter subdomains created for the services of the app
+	dnsHostnames []string
+}
+
+// addDNSRecord records the hostname of a porter subdomain created for a service. Nothing is recorded for a nil
+// createdResources, which updates of existing apps use, so that their subdomains are kept when the update fails.
+func (c *createdResources) addDNSRecord(hostname string) {
+	if c == nil {
+		return
+	}
+
+	c.dnsHostnames = append(c.dnsHostnames, hostname)
+}
+
+// createAppNamespace creates the namespace of a new app, recording it in created if it did not exist already. A
+// namespace which is terminating is recreated, so it is recorded as well.
+func createAppNamespace(ctx context.Context, k8sAgent *kubernetes.Agent, namespace string, labels map[string]string, created *createdResources) error {
+	existing, err := k8sAgent.Clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("error reading namespace %s: %w", namespace, err)
+	}
+	existed := err == nil && existing.Status.Phase != corev1.NamespaceTerminating
+
+	if _, err := k8sAgent.CreateNamespace(ctx, namespace, labels); err != nil {
+		return err
+	}
+
+	if !existed {
+		created.namespace = namespace
+	}
+
+	return nil
+}
+
+// removeCreatedResources removes the resources which a create which failed made. The namespace is deleted along with
+// everything Porter wrote into it, such as the pull secrets of the app and the env groups synced to it.
+func removeCreatedResources(ctx context.Context, k8sAgent *kubernetes.Agent, dnsRepo repository.DNSRecordRepository, created *createdResources) error {
+	var errs []error
+
+	if created.namespace != "" {
+		if err := k8sAgent.DeleteNamespace(created.namespace); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting namespace %s: %w", created.namespace, err))
+		}
+	}
+
+	if len(created.dnsHostnames) > 0 {
+		if _, err := dnsRepo.DeleteDNSRecordsByHostname(created.dnsHostnames); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting dns records: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
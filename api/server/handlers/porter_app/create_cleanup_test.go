@@ -0,0 +1,113 @@
+package porter_app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/integrations/dns"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/repository/test"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeDNSClient struct{}
+
+func (fakeDNSClient) CreateARecord(dns.Record) error     { return nil }
+func (fakeDNSClient) CreateCNAMERecord(dns.Record) error { return nil }
+
+// nginxIngressService is the load balancer of the ingress controller, which porter subdomains point at
+var nginxIngressService = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "ingress-nginx-controller",
+		Namespace: "ingress-nginx",
+		Labels:    map[string]string{"app.kubernetes.io/managed-by": "Helm", "helm.sh/chart": "ingress-nginx-4.0.1"},
+	},
+	Spec:   corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}}},
+}
+
+// createSubdomain creates the porter subdomain of a web service as parse does, and returns its hostname
+func createSubdomain(t *testing.T, k8sAgent *kubernetes.Agent, repo *test.TestRepository, created *createdResources) string {
+	t.Helper()
+
+	values := map[string]interface{}{"ingress": map[string]interface{}{"enabled": true}}
+	err := createSubdomainIfRequired(values, SubdomainCreateOpts{
+		k8sAgent:      k8sAgent,
+		dnsRepo:       repo.DNSRecord(),
+		dnsClient:     &dns.Client{Client: fakeDNSClient{}},
+		appRootDomain: "withporter.run",
+		stackName:     "shop",
+		created:       created,
+	})
+	if err != nil {
+		t.Fatalf("error creating subdomain: %v", err)
+	}
+
+	return values["ingress"].(map[string]interface{})["porter_hosts"].([]string)[0]
+}
+
+func dnsRecordExists(t *testing.T, repo *test.TestRepository, hostname string) bool {
+	t.Helper()
+
+	records, err := repo.DNSRecord().UpdateDNSRecordEndpointsByHostname([]string{hostname}, "203.0.113.10")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return len(records) > 0
+}
+
+func TestFailedCreateRemovesCreatedResources(t *testing.T) {
+	ctx := context.Background()
+	k8sAgent := kubernetes.GetAgentTesting(nginxIngressService)
+	repo := test.NewRepository(true).(*test.TestRepository)
+
+	created := &createdResources{}
+	if err := createAppNamespace(ctx, k8sAgent, "porter-stack-shop", nil, created); err != nil {
+		t.Fatalf("error creating namespace: %v", err)
+	}
+	hostname := createSubdomain(t, k8sAgent, repo, created)
+
+	if err := removeCreatedResources(ctx, k8sAgent, repo.DNSRecord(), created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := k8sAgent.Clientset.CoreV1().Namespaces().Get(ctx, "porter-stack-shop", metav1.GetOptions{})
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the namespace created for the app to be deleted, got %v", err)
+	}
+	if dnsRecordExists(t, repo, hostname) {
+		t.Errorf("expected the subdomain created for the app to be deleted")
+	}
+}
+
+func TestFailedUpdateKeepsExistingResources(t *testing.T) {
+	ctx := context.Background()
+	k8sAgent := kubernetes.GetAgentTesting(nginxIngressService, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "porter-stack-shop"}})
+	repo := test.NewRepository(true).(*test.TestRepository)
+
+	// a namespace which existed before the request is not recorded, so it is never deleted
+	created := &createdResources{}
+	if err := createAppNamespace(ctx, k8sAgent, "porter-stack-shop", nil, created); err != nil {
+		t.Fatalf("error creating namespace: %v", err)
+	}
+	if created.namespace != "" {
+		t.Errorf("expected the existing namespace not to be recorded as created")
+	}
+
+	// updates of existing apps do not record the subdomains they create
+	hostname := createSubdomain(t, k8sAgent, repo, nil)
+
+	if err := removeCreatedResources(ctx, k8sAgent, repo.DNSRecord(), created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := k8sAgent.Clientset.CoreV1().Namespaces().Get(ctx, "porter-stack-shop", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the existing namespace to be kept, got %v", err)
+	}
+	if !dnsRecordExists(t, repo, hostname) {
+		t.Errorf("expected the subdomain of the existing app to be kept")
+	}
+}
@@ -0,0 +1,207 @@
+package porter_app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/deployment_target"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/logalerts"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// CreateLogAlertRuleHandler handles POST requests to the /apps/{porter_app_name}/alert-rules endpoint
+type CreateLogAlertRuleHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateLogAlertRuleHandler returns a new CreateLogAlertRuleHandler
+func NewCreateLogAlertRuleHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateLogAlertRuleHandler {
+	return &CreateLogAlertRuleHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// CreateLogAlertRuleRequest is the request object for the POST /apps/{porter_app_name}/alert-rules endpoint
+type CreateLogAlertRuleRequest struct {
+	// DeploymentTargetID is the deployment target whose logs are evaluated. Defaults to the cluster's default deployment target
+	DeploymentTargetID string `json:"deployment_target_id"`
+	Name               string `json:"name"`
+	// ServiceName limits the rule to a single service of the app. If empty, logs from every service are evaluated
+	ServiceName string                  `json:"service_name"`
+	MatchType   types.LogAlertMatchType `json:"match_type"`
+	Pattern     string                  `json:"pattern"`
+	// Threshold is the number of matching lines within the window which must be exceeded for the rule to fire
+	Threshold          uint                  `json:"threshold"`
+	WindowMinutes      uint                  `json:"window_minutes"`
+	Channel            types.LogAlertChannel `json:"channel"`
+	SlackIntegrationID uint                  `json:"slack_integration_id"`
+	WebhookURL         string                `json:"webhook_url"`
+	// Enabled defaults to true
+	Enabled *bool `json:"enabled"`
+}
+
+// ServeHTTP creates a log alert rule on an app
+func (c *CreateLogAlertRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-log-alert-rule")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing app name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-name", Value: appName})
+
+	request := &CreateLogAlertRuleRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	existing, err := c.Repo().LogAlertRule().ListLogAlertRulesByPorterAppID(ctx, project.ID, app.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing log alert rules")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	maxRules := c.Config().ServerConf.LogAlertMaxRulesPerApp
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "existing-rules", Value: len(existing)},
+		telemetry.AttributeKV{Key: "max-rules", Value: maxRules},
+	)
+	if maxRules > 0 && len(existing) >= maxRules {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("an app can have at most %d log alert rules", maxRules))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	deploymentTargetID := request.DeploymentTargetID
+	if deploymentTargetID == "" {
+		defaultDeploymentTarget, err := defaultDeploymentTarget(ctx, defaultDeploymentTargetInput{
+			ProjectID:                 project.ID,
+			ClusterID:                 cluster.ID,
+			ClusterControlPlaneClient: c.Config().ClusterControlPlaneClient,
+		})
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error getting default deployment target")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		deploymentTargetID = defaultDeploymentTarget.ID.String()
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTargetID})
+
+	deploymentTarget, err := deployment_target.DeploymentTargetDetails(ctx, deployment_target.DeploymentTargetDetailsInput{
+		ProjectID:          int64(project.ID),
+		ClusterID:          int64(cluster.ID),
+		DeploymentTargetID: deploymentTargetID,
+		CCPClient:          c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting deployment target details")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	deploymentTargetUUID, err := uuid.Parse(deploymentTarget.ID)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error parsing deployment target id")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	enabled := true
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+
+	rule := &models.LogAlertRule{
+		ProjectID:          project.ID,
+		ClusterID:          cluster.ID,
+		PorterAppID:        app.ID,
+		DeploymentTargetID: deploymentTargetUUID,
+		Namespace:          deploymentTarget.Namespace,
+		Name:               request.Name,
+		ServiceName:        request.ServiceName,
+		MatchType:          string(request.MatchType),
+		Pattern:            request.Pattern,
+		Threshold:          request.Threshold,
+		WindowMinutes:      request.WindowMinutes,
+		Channel:            string(request.Channel),
+		SlackIntegrationID: request.SlackIntegrationID,
+		WebhookURL:         request.WebhookURL,
+		Enabled:            enabled,
+	}
+
+	if err := validateLogAlertRule(c.Repo(), rule); err != nil {
+		err := telemetry.Error(ctx, span, err, "invalid log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	rule, err = c.Repo().LogAlertRule().CreateLogAlertRule(ctx, rule)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, rule.ToLogAlertRuleType())
+}
+
+// validateLogAlertRule checks the rule's fields, and that its slack integration belongs to its project
+func validateLogAlertRule(repo repository.Repository, rule *models.LogAlertRule) error {
+	if err := logalerts.ValidateRule(rule); err != nil {
+		return err
+	}
+
+	if rule.SlackIntegrationID == 0 {
+		return nil
+	}
+
+	slackInts, err := repo.SlackIntegration().ListSlackIntegrationsByProjectID(rule.ProjectID)
+	if err != nil {
+		return fmt.Errorf("error listing slack integrations: %w", err)
+	}
+
+	for _, slackInt := range slackInts {
+		if slackInt.ID == rule.SlackIntegrationID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("slack integration %d not found in project", rule.SlackIntegrationID)
+}
@@ -189,7 +189,7 @@ func (c *OpenStackPRHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		if request.DeleteWorkflowFilename == "" {
 			// update DB with the PR url
-			porterApp, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
+			porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
 			if err != nil {
 				err = fmt.Errorf("unable to get porter app db: %w", err)
 				err := telemetry.Error(ctx, span, err, err.Error())
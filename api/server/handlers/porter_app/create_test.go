@@ -0,0 +1,436 @@
+package porter_app_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/devmode"
+	"github.com/porter-dev/porter/internal/models"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+)
+
+const createTestPorterYAML = `version: v1stack
+services:
+  web:
+    type: web
+    run: node index.js
+    config:
+      container:
+        port: 8080
+      ingress:
+        enabled: false
+`
+
+func TestCreatePorterApp(t *testing.T) {
+	// the charts of the app are served by the dev mode chart repo, so that the tests do not reach the Porter chart repos
+	chartRepo, err := devmode.StartChartRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chartRepo.Close() // nolint:errcheck
+
+	deployRequest := &types.CreatePorterAppRequest{
+		PorterYAMLBase64: base64.StdEncoding.EncodeToString([]byte(createTestPorterYAML)),
+		ImageRepoURI:     "nginx",
+		ImageInfo: types.ImageInfo{
+			Repository: "nginx",
+			Tag:        "latest",
+		},
+	}
+
+	tests := []struct {
+		name string
+		// setup runs before the deploy, against the env of the test
+		setup    func(t *testing.T, env *apitest.HandlerTestEnv, deploy func() int)
+		wantCode int
+		// wantRevision is the revision of the release of the app after the deploy, or 0 if there should be no release
+		wantRevision int
+		wantCreates  int
+		wantUpdates  int
+		// wantApp is whether the app should be in the database after the deploy
+		wantApp bool
+	}{
+		{
+			name:         "create",
+			wantCode:     http.StatusOK,
+			wantRevision: 1,
+			wantCreates:  1,
+			wantApp:      true,
+		},
+		{
+			name: "update",
+			setup: func(t *testing.T, env *apitest.HandlerTestEnv, deploy func() int) {
+				if code := deploy(); code != http.StatusOK {
+					t.Fatalf("expected the first deploy to create the app, got status %d", code)
+				}
+			},
+			wantCode:     http.StatusOK,
+			wantRevision: 2,
+			wantCreates:  1,
+			wantUpdates:  1,
+			wantApp:      true,
+		},
+		{
+			name: "install failure is cleaned up",
+			setup: func(t *testing.T, env *apitest.HandlerTestEnv, deploy func() int) {
+				env.Agents.KubeClient.FailCreates(1)
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "duplicate app",
+			setup: func(t *testing.T, env *apitest.HandlerTestEnv, deploy func() int) {
+				// the app is in the database without a release, so the deploy finds the app before installing the chart
+				if _, err := env.Config.Repo.PorterApp().UpdatePorterApp(&models.PorterApp{
+					Name:      "web",
+					ProjectID: env.Project.ID,
+					ClusterID: env.Cluster.ID,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantCode: http.StatusConflict,
+			wantApp:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := apitest.NewHandlerTestEnv(t, "porter-stack-web")
+			env.Config.ServerConf.DefaultApplicationHelmRepoURL = chartRepo.URL
+
+			handler := porter_app.NewCreatePorterAppHandler(
+				env.Config,
+				shared.NewDefaultRequestDecoderValidator(env.Config.Logger, env.Config.Alerter),
+				shared.NewDefaultResultWriter(env.Config.Logger, env.Config.Alerter),
+			)
+
+			deploy := func() int {
+				req, rr := env.NewRequest(t, string(types.HTTPVerbPost), "/api/projects/1/clusters/1/applications/web", deployRequest, map[string]string{
+					string(types.URLParamPorterAppName): "web",
+				})
+
+				handler.ServeHTTP(rr, req)
+
+				return rr.Code
+			}
+
+			if tt.setup != nil {
+				tt.setup(t, env, deploy)
+			}
+
+			if code := deploy(); code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, code)
+			}
+
+			release, err := env.Agents.Helm.GetRelease(context.Background(), "web", 0, false)
+			switch {
+			case tt.wantRevision == 0 && err == nil:
+				t.Errorf("expected the release of the app to be removed, got revision %d", release.Version)
+			case tt.wantRevision != 0 && err != nil:
+				t.Errorf("expected the release of the app to be installed, got %v", err)
+			case tt.wantRevision != 0 && release.Version != tt.wantRevision:
+				t.Errorf("expected revision %d of the release, got %d", tt.wantRevision, release.Version)
+			}
+
+			if creates := env.Agents.KubeClient.Creates(); creates != tt.wantCreates {
+				t.Errorf("expected %d installs to be applied, got %d", tt.wantCreates, creates)
+			}
+			if updates := env.Agents.KubeClient.Updates(); updates != tt.wantUpdates {
+				t.Errorf("expected %d upgrades to be applied, got %d", tt.wantUpdates, updates)
+			}
+
+			_, err = env.Config.Repo.PorterApp().ReadScopedPorterAppByName(env.Project.ID, env.Cluster.ID, "web")
+			if tt.wantApp && err != nil {
+				t.Errorf("expected the app to be in the database, got %v", err)
+			} else if !tt.wantApp && err == nil {
+				t.Error("expected the app not to be written to the database")
+			}
+		})
+	}
+}
+
+func TestCreatePorterAppApplyV2(t *testing.T) {
+	deployRequest := &types.CreatePorterAppRequest{
+		ImageInfo: types.ImageInfo{
+			Repository: "nginx",
+			Tag:        "latest",
+		},
+	}
+
+	tests := []struct {
+		name string
+		// existingApp is whether the app is in the database before the deploy
+		existingApp bool
+		wantCode    int
+	}{
+		{
+			name:     "app which does not exist is rejected",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			// the env has no cluster control plane client, which the image of the app is updated through
+			name:        "existing app without a cluster control plane fails",
+			existingApp: true,
+			wantCode:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := apitest.NewHandlerTestEnv(t, "porter-stack-web")
+			env.FeatureFlags[models.ValidateApplyV2] = true
+
+			if tt.existingApp {
+				if _, err := env.Config.Repo.PorterApp().UpdatePorterApp(&models.PorterApp{
+					Name:      "web",
+					ProjectID: env.Project.ID,
+					ClusterID: env.Cluster.ID,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			handler := porter_app.NewCreatePorterAppHandler(
+				env.Config,
+				shared.NewDefaultRequestDecoderValidator(env.Config.Logger, env.Config.Alerter),
+				shared.NewDefaultResultWriter(env.Config.Logger, env.Config.Alerter),
+			)
+
+			req, rr := env.NewRequest(t, string(types.HTTPVerbPost), "/api/projects/1/clusters/1/applications/web", deployRequest, map[string]string{
+				string(types.URLParamPorterAppName): "web",
+			})
+
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, rr.Code)
+			}
+			if creates := env.Agents.KubeClient.Creates(); creates != 0 {
+				t.Errorf("expected no chart to be installed, got %d installs", creates)
+			}
+		})
+	}
+}
+
+func TestCreatePorterAppGitSource(t *testing.T) {
+	chartRepo, err := devmode.StartChartRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chartRepo.Close() // nolint:errcheck
+
+	tests := []struct {
+		name string
+		// existingGitRepoID and existingGitlabIntegrationID are the git source of the app before the deploy, if it
+		// already exists
+		existingGitRepoID           uint
+		existingGitlabIntegrationID uint
+		gitRepoID                   uint
+		gitlabIntegrationID         uint
+		wantCode                    int
+		// wantApp is the git source the app should be linked to after the deploy, or nil if it should not exist
+		wantApp *models.PorterApp
+	}{
+		{
+			name:      "github",
+			gitRepoID: 1,
+			wantCode:  http.StatusOK,
+			wantApp:   &models.PorterApp{GitRepoID: 1},
+		},
+		{
+			name:                "gitlab",
+			gitlabIntegrationID: 1,
+			wantCode:            http.StatusOK,
+			wantApp:             &models.PorterApp{GitlabIntegrationID: 1},
+		},
+		{
+			name:                "gitlab integration which does not exist",
+			gitlabIntegrationID: 2,
+			wantCode:            http.StatusBadRequest,
+		},
+		{
+			name:                "github app moved to gitlab",
+			existingGitRepoID:   1,
+			gitlabIntegrationID: 1,
+			wantCode:            http.StatusOK,
+			wantApp:             &models.PorterApp{GitlabIntegrationID: 1},
+		},
+		{
+			name:                        "gitlab app moved to github",
+			existingGitlabIntegrationID: 1,
+			gitRepoID:                   1,
+			wantCode:                    http.StatusOK,
+			wantApp:                     &models.PorterApp{GitRepoID: 1},
+		},
+		{
+			name:                "both github and gitlab",
+			gitRepoID:           1,
+			gitlabIntegrationID: 1,
+			wantCode:            http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := apitest.NewHandlerTestEnv(t, "porter-stack-web")
+			env.Config.ServerConf.DefaultApplicationHelmRepoURL = chartRepo.URL
+
+			if _, err := env.Config.Repo.GitlabIntegration().CreateGitlabIntegration(&ints.GitlabIntegration{
+				ProjectID:   env.Project.ID,
+				InstanceURL: "https://gitlab.com",
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			handler := porter_app.NewCreatePorterAppHandler(
+				env.Config,
+				shared.NewDefaultRequestDecoderValidator(env.Config.Logger, env.Config.Alerter),
+				shared.NewDefaultResultWriter(env.Config.Logger, env.Config.Alerter),
+			)
+
+			deploy := func(gitRepoID, gitlabIntegrationID uint) int {
+				req, rr := env.NewRequest(t, string(types.HTTPVerbPost), "/api/projects/1/clusters/1/applications/web", &types.CreatePorterAppRequest{
+					PorterYAMLBase64:    base64.StdEncoding.EncodeToString([]byte(createTestPorterYAML)),
+					RepoName:            "porter-dev/web",
+					GitBranch:           "main",
+					GitRepoID:           gitRepoID,
+					GitlabIntegrationID: gitlabIntegrationID,
+					ImageInfo: types.ImageInfo{
+						Repository: "nginx",
+						Tag:        "latest",
+					},
+				}, map[string]string{
+					string(types.URLParamPorterAppName): "web",
+				})
+
+				handler.ServeHTTP(rr, req)
+
+				return rr.Code
+			}
+
+			if tt.existingGitRepoID != 0 || tt.existingGitlabIntegrationID != 0 {
+				if code := deploy(tt.existingGitRepoID, tt.existingGitlabIntegrationID); code != http.StatusOK {
+					t.Fatalf("expected the first deploy to create the app, got status %d", code)
+				}
+			}
+
+			if code := deploy(tt.gitRepoID, tt.gitlabIntegrationID); code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, code)
+			}
+
+			app, err := env.Config.Repo.PorterApp().ReadScopedPorterAppByName(env.Project.ID, env.Cluster.ID, "web")
+			if tt.wantApp == nil {
+				if err == nil {
+					t.Error("expected the app not to be written to the database")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected the app to be in the database, got %v", err)
+			}
+
+			if app.GitRepoID != tt.wantApp.GitRepoID || app.GitlabIntegrationID != tt.wantApp.GitlabIntegrationID {
+				t.Errorf("expected the app to be linked to git repo %d and gitlab integration %d, got %d and %d",
+					tt.wantApp.GitRepoID, tt.wantApp.GitlabIntegrationID, app.GitRepoID, app.GitlabIntegrationID)
+			}
+		})
+	}
+}
+
+func TestCreatePorterAppTimeoutSeconds(t *testing.T) {
+	chartRepo, err := devmode.StartChartRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chartRepo.Close() // nolint:errcheck
+
+	tests := []struct {
+		name               string
+		helmMaxTimeout     time.Duration
+		requestTimeoutLong time.Duration
+		timeoutSeconds     uint
+		wantCode           int
+		// wantError is the error returned to the client, if the deploy is rejected
+		wantError string
+	}{
+		{
+			name:               "timeout within both limits",
+			helmMaxTimeout:     15 * time.Minute,
+			requestTimeoutLong: 15 * time.Minute,
+			timeoutSeconds:     600,
+			wantCode:           http.StatusOK,
+		},
+		{
+			name:               "timeout above the helm max timeout",
+			helmMaxTimeout:     10 * time.Minute,
+			requestTimeoutLong: 15 * time.Minute,
+			timeoutSeconds:     900,
+			wantCode:           http.StatusBadRequest,
+			wantError:          "timeout_seconds must be at most 600",
+		},
+		{
+			name:               "timeout which outlasts the request",
+			helmMaxTimeout:     time.Hour,
+			requestTimeoutLong: 15 * time.Minute,
+			timeoutSeconds:     1800,
+			wantCode:           http.StatusBadRequest,
+			wantError:          "timeout_seconds must be at most 900",
+		},
+		{
+			name:               "timeout which outlasts the request without a helm max timeout",
+			requestTimeoutLong: 15 * time.Minute,
+			timeoutSeconds:     1800,
+			wantCode:           http.StatusBadRequest,
+			wantError:          "timeout_seconds must be at most 900",
+		},
+		{
+			name:           "any timeout without limits",
+			timeoutSeconds: 7200,
+			wantCode:       http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := apitest.NewHandlerTestEnv(t, "porter-stack-web")
+			env.Config.ServerConf.DefaultApplicationHelmRepoURL = chartRepo.URL
+			env.Config.ServerConf.HelmMaxTimeout = tt.helmMaxTimeout
+			env.Config.ServerConf.RequestTimeoutLong = tt.requestTimeoutLong
+
+			handler := porter_app.NewCreatePorterAppHandler(
+				env.Config,
+				shared.NewDefaultRequestDecoderValidator(env.Config.Logger, env.Config.Alerter),
+				shared.NewDefaultResultWriter(env.Config.Logger, env.Config.Alerter),
+			)
+
+			req, rr := env.NewRequest(t, string(types.HTTPVerbPost), "/api/projects/1/clusters/1/applications/web", &types.CreatePorterAppRequest{
+				PorterYAMLBase64: base64.StdEncoding.EncodeToString([]byte(createTestPorterYAML)),
+				TimeoutSeconds:   tt.timeoutSeconds,
+				ImageInfo: types.ImageInfo{
+					Repository: "nginx",
+					Tag:        "latest",
+				},
+			}, map[string]string{
+				string(types.URLParamPorterAppName): "web",
+			})
+
+			handler.ServeHTTP(rr, req)
+
+			if tt.wantError != "" {
+				apitest.AssertResponseError(t, rr, tt.wantCode, &types.ExternalError{Error: tt.wantError})
+				return
+			}
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
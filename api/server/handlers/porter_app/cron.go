@@ -0,0 +1,122 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// cronConcurrencyPolicies maps the concurrency policies of cron services in porter.yaml to those of a kubernetes
+// CronJob
+var cronConcurrencyPolicies = map[string]batchv1.ConcurrencyPolicy{
+	"allow":   batchv1.AllowConcurrent,
+	"forbid":  batchv1.ForbidConcurrent,
+	"replace": batchv1.ReplaceConcurrent,
+}
+
+// setCronSchedule sets the schedule of a cron service in the values of its job chart. The schedule replaces the one
+// of the last deploy, so that a time zone or history limit removed from porter.yaml is removed from the cron job.
+func setCronSchedule(serviceValues map[string]interface{}, service *Service) {
+	schedule := map[string]interface{}{
+		"enabled": true,
+		"value":   strings.TrimSpace(*service.Schedule),
+	}
+	if service.Timezone != nil && *service.Timezone != "" {
+		schedule["timeZone"] = *service.Timezone
+	}
+	serviceValues["schedule"] = schedule
+
+	delete(serviceValues, "concurrencyPolicy")
+	if service.ConcurrencyPolicy != nil {
+		if policy, ok := cronConcurrencyPolicies[*service.ConcurrencyPolicy]; ok {
+			serviceValues["concurrencyPolicy"] = string(policy)
+			// older versions of the job chart only read whether runs may overlap
+			serviceValues["allowConcurrent"] = policy == batchv1.AllowConcurrent
+		}
+	}
+
+	delete(serviceValues, "successfulJobsHistoryLimit")
+	delete(serviceValues, "failedJobsHistoryLimit")
+	if service.History != nil {
+		if service.History.Successful != nil {
+			serviceValues["successfulJobsHistoryLimit"] = *service.History.Successful
+		}
+		if service.History.Failed != nil {
+			serviceValues["failedJobsHistoryLimit"] = *service.History.Failed
+		}
+	}
+}
+
+// cronServiceStatuses returns the schedule and last run of every service of an app which runs on a schedule, read from
+// the values of its release and the cron jobs of its services. Services whose cron job cannot be found are listed
+// without a last run.
+func cronServiceStatuses(ctx context.Context, clientset k8s.Interface, appName string, namespace string, values map[string]interface{}) ([]types.CronServiceStatus, error) {
+	namespaces := servicesByNamespace(values, namespace)
+
+	helmNames := make([]string, 0, len(namespaces))
+	for helmName := range namespaces {
+		helmNames = append(helmNames, helmName)
+	}
+	sort.Strings(helmNames)
+
+	var res []types.CronServiceStatus
+	for _, helmName := range helmNames {
+		serviceName, serviceType := getServiceNameAndTypeFromHelmName(helmName)
+		if serviceType != "job" {
+			continue
+		}
+
+		schedule, err := getNestedMap(values, helmName, "schedule")
+		if err != nil {
+			continue
+		}
+		if enabled, _ := schedule["enabled"].(bool); !enabled {
+			continue
+		}
+
+		status := types.CronServiceStatus{Service: serviceName}
+		status.Schedule, _ = schedule["value"].(string)
+		status.Timezone, _ = schedule["timeZone"].(string)
+
+		cronJob, err := clientset.BatchV1().CronJobs(namespaces[helmName]).Get(ctx, fmt.Sprintf("%s-%s", appName, helmName), metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("error reading cron job of service %s: %w", serviceName, err)
+		}
+		if err == nil {
+			setLastCronRun(&status, cronJob)
+		}
+
+		res = append(res, status)
+	}
+
+	return res, nil
+}
+
+// setLastCronRun sets the last run of a cron service from the status of its cron job. A run which is no longer active
+// failed if the cron job has not completed a run since it was scheduled.
+func setLastCronRun(status *types.CronServiceStatus, cronJob *batchv1.CronJob) {
+	status.Suspended = cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend
+
+	if cronJob.Status.LastScheduleTime == nil {
+		return
+	}
+
+	lastRunAt := cronJob.Status.LastScheduleTime.Time.UTC()
+	status.LastRunAt = &lastRunAt
+
+	switch {
+	case len(cronJob.Status.Active) > 0:
+		status.LastRunResult = types.CronRunResult_Running
+	case cronJob.Status.LastSuccessfulTime != nil && !cronJob.Status.LastSuccessfulTime.Before(cronJob.Status.LastScheduleTime):
+		status.LastRunResult = types.CronRunResult_Succeeded
+	default:
+		status.LastRunResult = types.CronRunResult_Failed
+	}
+}
@@ -0,0 +1,151 @@
+package porter_app
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gopkg.in/yaml.v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const cronPorterYaml = `version: v1stack
+services:
+  cleanup:
+    type: cron
+    run: ./cleanup
+    schedule: "*/15 * * * *"
+    timezone: America/New_York
+    concurrency_policy: replace
+    history:
+      successful: 3
+      failed: 5
+`
+
+func buildCronTestValues(t *testing.T, porterYaml string, existingValues map[string]interface{}) map[string]interface{} {
+	t.Helper()
+
+	parsed := &PorterStackYAML{}
+	if err := yaml.Unmarshal([]byte(porterYaml), parsed); err != nil {
+		t.Fatalf("error parsing porter.yaml: %v", err)
+	}
+
+	application := &Application{Env: parsed.Env, Services: parsed.Services}
+	imageInfo := types.ImageInfo{Repository: "registry.example.com/shop", Tag: "8f14e45f"}
+
+	values, _, err := buildUmbrellaChartValues(context.Background(), application, nil, imageInfo, nil, existingValues, SubdomainCreateOpts{dryRun: true}, false, true, false, "porter-stack-shop", false, false, types.ClusterSchedulingDefaults{}, nil, "shop", nil)
+	if err != nil {
+		t.Fatalf("error building values: %v", err)
+	}
+
+	return convertMap(values).(map[string]interface{})
+}
+
+func TestCronServiceDeployedAsJob(t *testing.T) {
+	values := buildCronTestValues(t, cronPorterYaml, nil)
+
+	serviceValues, ok := values["cleanup-job"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected the cron service to be deployed with the job chart, got services %v", reflect.ValueOf(values).MapKeys())
+	}
+
+	want := map[string]interface{}{"enabled": true, "value": "*/15 * * * *", "timeZone": "America/New_York"}
+	if got := serviceValues["schedule"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the schedule of the job chart to be %v, got %v", want, got)
+	}
+	if got := serviceValues["concurrencyPolicy"]; got != "Replace" {
+		t.Errorf("expected runs to replace each other, got %v", got)
+	}
+	if allowConcurrent, _ := serviceValues["allowConcurrent"].(bool); allowConcurrent {
+		t.Errorf("expected runs not to overlap")
+	}
+	if serviceValues["successfulJobsHistoryLimit"] != 3 || serviceValues["failedJobsHistoryLimit"] != 5 {
+		t.Errorf("expected the history limits to be set, got %v and %v", serviceValues["successfulJobsHistoryLimit"], serviceValues["failedJobsHistoryLimit"])
+	}
+
+	// settings removed from porter.yaml are removed from the cron job rather than kept from the last deploy
+	trimmed := cronPorterYaml[:strings.Index(cronPorterYaml, "    timezone:")]
+	values = buildCronTestValues(t, trimmed, values)
+
+	serviceValues = values["cleanup-job"].(map[string]interface{})
+	want = map[string]interface{}{"enabled": true, "value": "*/15 * * * *"}
+	if got := serviceValues["schedule"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the time zone to be removed from the schedule, got %v", got)
+	}
+	for _, key := range []string{"concurrencyPolicy", "successfulJobsHistoryLimit", "failedJobsHistoryLimit"} {
+		if _, ok := serviceValues[key]; ok {
+			t.Errorf("expected %s to be removed, got %v", key, serviceValues[key])
+		}
+	}
+}
+
+func TestCronServiceStatuses(t *testing.T) {
+	ctx := context.Background()
+	namespace := "porter-stack-shop"
+
+	scheduledAt := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+	suspended := true
+
+	values := map[string]interface{}{
+		"cleanup-job": map[string]interface{}{"schedule": map[string]interface{}{"enabled": true, "value": "*/15 * * * *", "timeZone": "America/New_York"}},
+		"report-job":  map[string]interface{}{"schedule": map[string]interface{}{"enabled": true, "value": "@daily"}},
+		"sync-job":    map[string]interface{}{"schedule": map[string]interface{}{"enabled": true, "value": "@hourly"}},
+		"migrate-job": map[string]interface{}{"schedule": map[string]interface{}{"enabled": false}},
+		"web-web":     map[string]interface{}{},
+		"global":      map[string]interface{}{serviceNamespacesKey: map[string]interface{}{"sync-job": "internal"}},
+	}
+
+	clientset := fake.NewSimpleClientset(
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop-cleanup-job", Namespace: namespace},
+			Status: batchv1.CronJobStatus{
+				LastScheduleTime:   &metav1.Time{Time: scheduledAt},
+				LastSuccessfulTime: &metav1.Time{Time: scheduledAt.Add(2 * time.Minute)},
+			},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop-report-job", Namespace: namespace},
+			Spec:       batchv1.CronJobSpec{Suspend: &suspended},
+			Status: batchv1.CronJobStatus{
+				LastScheduleTime:   &metav1.Time{Time: scheduledAt},
+				LastSuccessfulTime: &metav1.Time{Time: scheduledAt.Add(-24 * time.Hour)},
+			},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop-sync-job", Namespace: "internal"},
+			Status: batchv1.CronJobStatus{
+				LastScheduleTime: &metav1.Time{Time: scheduledAt},
+				Active:           []corev1.ObjectReference{{Name: "shop-sync-job-28496700"}},
+			},
+		},
+	)
+
+	statuses, err := cronServiceStatuses(ctx, clientset, "shop", namespace, values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []types.CronServiceStatus{
+		{Service: "cleanup", Schedule: "*/15 * * * *", Timezone: "America/New_York", LastRunAt: &scheduledAt, LastRunResult: types.CronRunResult_Succeeded},
+		{Service: "report", Schedule: "@daily", Suspended: true, LastRunAt: &scheduledAt, LastRunResult: types.CronRunResult_Failed},
+		{Service: "sync", Schedule: "@hourly", LastRunAt: &scheduledAt, LastRunResult: types.CronRunResult_Running},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("unexpected cron statuses\nwant %+v\ngot  %+v", want, statuses)
+	}
+
+	// a service whose cron job has not been created yet is listed without a last run
+	statuses, err = cronServiceStatuses(ctx, fake.NewSimpleClientset(), "shop", namespace, values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 3 || statuses[0].LastRunAt != nil || statuses[0].LastRunResult != "" {
+		t.Errorf("expected the services to be listed without a last run, got %+v", statuses)
+	}
+}
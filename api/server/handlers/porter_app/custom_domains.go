@@ -0,0 +1,233 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/capabilities"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+const (
+	// annotationClusterIssuer selects the cert-manager ClusterIssuer which issues the certificates of the hosts of an
+	// ingress
+	annotationClusterIssuer = "cert-manager.io/cluster-issuer"
+	// customDomainsKey is the key of the ingress values of a service which records the hosts added from the
+	// custom_domains of the service in porter.yaml, so that they are removed from its hosts once they are removed from
+	// porter.yaml
+	customDomainsKey = "porter_custom_domains"
+)
+
+// setCustomDomains adds the custom domains of a web service to the hosts of its ingress, along with its porter
+// subdomain, and removes the custom domains which an earlier deploy added but porter.yaml no longer lists. When the
+// cluster has cert-manager, the ingress is annotated with clusterIssuer so that certificates are issued for the
+// domains; otherwise a warning is returned.
+func setCustomDomains(serviceValues map[string]interface{}, name string, customDomains []string, clusterCapabilities *types.ClusterCapabilities, clusterIssuer string) []string {
+	ingressMap, err := getNestedMap(serviceValues, "ingress")
+	if err != nil {
+		if len(customDomains) == 0 {
+			return nil
+		}
+		ingressMap = make(map[string]interface{})
+		serviceValues["ingress"] = ingressMap
+	}
+
+	previous := stringsFromValue(ingressMap[customDomainsKey])
+	if len(customDomains) == 0 && len(previous) == 0 {
+		return nil
+	}
+
+	removed := make(map[string]bool, len(previous))
+	for _, domain := range previous {
+		removed[domain] = true
+	}
+
+	hosts := make([]interface{}, 0)
+	seen := make(map[string]bool)
+	for _, host := range stringsFromValue(ingressMap["hosts"]) {
+		if !removed[host] && !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	for _, domain := range customDomains {
+		if !seen[domain] {
+			seen[domain] = true
+			hosts = append(hosts, domain)
+		}
+	}
+	ingressMap["hosts"] = hosts
+
+	annotations, _ := ingressMap["annotations"].(map[string]interface{})
+
+	if len(customDomains) == 0 {
+		delete(ingressMap, customDomainsKey)
+		if len(hosts) == 0 {
+			ingressMap["custom_domain"] = false
+		}
+		if issuer, _ := annotations[annotationClusterIssuer].(string); issuer == clusterIssuer {
+			delete(annotations, annotationClusterIssuer)
+		}
+		return nil
+	}
+
+	domains := make([]interface{}, 0, len(customDomains))
+	for _, domain := range customDomains {
+		domains = append(domains, domain)
+	}
+	ingressMap[customDomainsKey] = domains
+	ingressMap["enabled"] = true
+	ingressMap["custom_domain"] = true
+
+	if clusterIssuer == "" || !capabilities.Known(clusterCapabilities, types.ClusterCapabilityProbe_APIGroups) || !clusterCapabilities.CertManager {
+		return []string{fmt.Sprintf("cert-manager was not detected on the cluster, so certificates are not issued automatically for the custom domains of service %s: %s", name, strings.Join(customDomains, ", "))}
+	}
+
+	// an issuer set in the config of the service takes precedence
+	if annotations == nil {
+		annotations = make(map[string]interface{})
+		ingressMap["annotations"] = annotations
+	}
+	if _, ok := annotations[annotationClusterIssuer]; !ok {
+		annotations[annotationClusterIssuer] = clusterIssuer
+	}
+
+	return nil
+}
+
+// stringsFromValue returns the strings of a list in helm values, which is a []interface{} when read from a release and
+// a []string when set by porter
+func stringsFromValue(value interface{}) []string {
+	switch list := value.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		res := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok && s != "" {
+				res = append(res, s)
+			}
+		}
+		return res
+	default:
+		return nil
+	}
+}
+
+// customDomainsFromValues returns the custom domains which porter.yaml sets for the services of an app, sorted
+func customDomainsFromValues(values map[string]interface{}) []string {
+	var domains []string
+
+	for key, value := range values {
+		serviceValues, ok := value.(map[string]interface{})
+		if !ok || key == "global" {
+			continue
+		}
+
+		ingressMap, err := getNestedMap(serviceValues, "ingress")
+		if err != nil {
+			continue
+		}
+
+		domains = append(domains, stringsFromValue(ingressMap[customDomainsKey])...)
+	}
+
+	sort.Strings(domains)
+	return domains
+}
+
+// checkCustomDomainsAvailable returns an error naming the custom domains of an app which are already served by other
+// apps of the project. The domains of apps are recorded when they are deployed and by the checks of their
+// certificates. porterAppID is zero for an app which is deployed for the first time.
+func checkCustomDomainsAvailable(ctx context.Context, repo repository.Repository, projectID uint, porterAppID uint, customDomains []string) error {
+	if len(customDomains) == 0 {
+		return nil
+	}
+
+	domains, err := repo.PorterAppDomain().ListPorterAppDomainsByHostnames(ctx, projectID, customDomains)
+	if err != nil {
+		return fmt.Errorf("error listing domains of the project: %w", err)
+	}
+
+	taken := make(map[string]uint)
+	var appIDs []uint
+	for _, domain := range domains {
+		if domain.PorterAppID == porterAppID {
+			continue
+		}
+		if _, ok := taken[domain.Hostname]; !ok {
+			taken[domain.Hostname] = domain.PorterAppID
+			appIDs = append(appIDs, domain.PorterAppID)
+		}
+	}
+	if len(taken) == 0 {
+		return nil
+	}
+
+	appNames := make(map[uint]string)
+	apps, err := repo.PorterApp().ListPorterAppsByIDs(ctx, appIDs)
+	if err != nil {
+		return fmt.Errorf("error reading apps which serve custom domains: %w", err)
+	}
+	for _, app := range apps {
+		appNames[app.ID] = app.Name
+	}
+
+	var conflicts []string
+	for _, domain := range customDomains {
+		appID, ok := taken[domain]
+		if !ok {
+			continue
+		}
+
+		appName := appNames[appID]
+		if appName == "" {
+			appName = fmt.Sprintf("with id %d", appID)
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s is already served by app %s", domain, appName))
+	}
+
+	return fmt.Errorf("custom domains must be unique within a project: %s", strings.Join(conflicts, "; "))
+}
+
+// recordCustomDomains records the custom domains of an app which are not recorded yet, so that other apps of the
+// project cannot be deployed with them before their certificates are first checked
+func recordCustomDomains(ctx context.Context, repo repository.PorterAppDomainRepository, app *models.PorterApp, customDomains []string) error {
+	if len(customDomains) == 0 {
+		return nil
+	}
+
+	existing, err := repo.ListPorterAppDomains(ctx, app.ID)
+	if err != nil {
+		return fmt.Errorf("error listing domains of app: %w", err)
+	}
+
+	recorded := make(map[string]bool, len(existing))
+	for _, domain := range existing {
+		recorded[domain.Hostname] = true
+	}
+
+	for _, hostname := range customDomains {
+		if recorded[hostname] {
+			continue
+		}
+
+		_, err := repo.CreatePorterAppDomain(ctx, &models.PorterAppDomain{
+			ProjectID:   app.ProjectID,
+			ClusterID:   app.ClusterID,
+			PorterAppID: app.ID,
+			Hostname:    hostname,
+			CertStatus:  string(types.CertStatus_Unknown),
+		})
+		if err != nil {
+			return fmt.Errorf("error recording domain %s: %w", hostname, err)
+		}
+		recorded[hostname] = true
+	}
+
+	return nil
+}
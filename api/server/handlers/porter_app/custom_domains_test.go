@@ -0,0 +1,149 @@
+package porter_app
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+	"gopkg.in/yaml.v2"
+)
+
+const customDomainsPorterYaml = `version: v1stack
+services:
+  web:
+    type: web
+    run: node index.js
+    custom_domains:
+      - shop.example.com
+      - www.shop.example.com
+    config:
+      container:
+        port: 8080
+`
+
+func buildCustomDomainsTestValues(t *testing.T, porterYaml string, existingValues map[string]interface{}, clusterCapabilities *types.ClusterCapabilities) (map[string]interface{}, []string) {
+	t.Helper()
+
+	parsed := &PorterStackYAML{}
+	if err := yaml.Unmarshal([]byte(porterYaml), parsed); err != nil {
+		t.Fatalf("error parsing porter.yaml: %v", err)
+	}
+
+	application := &Application{Env: parsed.Env, Services: parsed.Services}
+	imageInfo := types.ImageInfo{Repository: "registry.example.com/shop", Tag: "8f14e45f"}
+	opts := SubdomainCreateOpts{dryRun: true, clusterIssuer: "letsencrypt-prod"}
+
+	values, warnings, err := buildUmbrellaChartValues(context.Background(), application, nil, imageInfo, nil, existingValues, opts, false, true, false, "porter-stack-shop", false, false, types.ClusterSchedulingDefaults{}, clusterCapabilities, "shop", nil)
+	if err != nil {
+		t.Fatalf("error building values: %v", err)
+	}
+
+	return values, warnings
+}
+
+func TestCustomDomainsAddedToIngress(t *testing.T) {
+	values, warnings := buildCustomDomainsTestValues(t, customDomainsPorterYaml, nil, &types.ClusterCapabilities{CertManager: true})
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings on a cluster with cert-manager, got %v", warnings)
+	}
+
+	ingress := values["web-web"].(map[string]interface{})["ingress"].(map[string]interface{})
+	if enabled, _ := ingress["enabled"].(bool); !enabled {
+		t.Errorf("expected the ingress to be enabled")
+	}
+	if customDomain, _ := ingress["custom_domain"].(bool); !customDomain {
+		t.Errorf("expected the ingress to serve custom domains")
+	}
+	if got := stringsFromValue(ingress["hosts"]); !reflect.DeepEqual(got, []string{"shop.example.com", "www.shop.example.com"}) {
+		t.Errorf("expected the custom domains to be the hosts of the ingress, got %v", got)
+	}
+	if got := stringValue(t, values, "web-web", "ingress", "annotations", annotationClusterIssuer); got != "letsencrypt-prod" {
+		t.Errorf("expected certificates to be issued by the configured cluster issuer, got %q", got)
+	}
+	if got := customDomainsFromValues(values); !reflect.DeepEqual(got, []string{"shop.example.com", "www.shop.example.com"}) {
+		t.Errorf("expected the custom domains of the app to be read from its values, got %v", got)
+	}
+
+	// hosts set in the config of the service are kept when a custom domain is removed from porter.yaml
+	ingress["hosts"] = append(ingress["hosts"].([]interface{}), "legacy.example.com")
+	values, _ = buildCustomDomainsTestValues(t, strings.Replace(customDomainsPorterYaml, "      - www.shop.example.com\n", "", 1), values, &types.ClusterCapabilities{CertManager: true})
+
+	ingress = values["web-web"].(map[string]interface{})["ingress"].(map[string]interface{})
+	if got := stringsFromValue(ingress["hosts"]); !reflect.DeepEqual(got, []string{"legacy.example.com", "shop.example.com"}) {
+		t.Errorf("expected the removed custom domain to be dropped from the hosts of the ingress, got %v", got)
+	}
+}
+
+func TestCustomDomainsWithoutCertManager(t *testing.T) {
+	values, warnings := buildCustomDomainsTestValues(t, customDomainsPorterYaml, nil, &types.ClusterCapabilities{})
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "cert-manager was not detected") {
+		t.Errorf("expected a warning that certificates are not issued, got %v", warnings)
+	}
+
+	ingress := values["web-web"].(map[string]interface{})["ingress"].(map[string]interface{})
+	if got := stringsFromValue(ingress["hosts"]); len(got) != 2 {
+		t.Errorf("expected the custom domains to be routed without certificates, got %v", got)
+	}
+	annotations, _ := ingress["annotations"].(map[string]interface{})
+	if _, ok := annotations[annotationClusterIssuer]; ok {
+		t.Errorf("expected no cluster issuer to be set on a cluster without cert-manager")
+	}
+}
+
+func TestCustomDomainsUniqueWithinProject(t *testing.T) {
+	ctx := context.Background()
+	repo := test.NewRepository(true)
+
+	storefront, err := repo.PorterApp().CreatePorterApp(&models.PorterApp{Name: "storefront", ProjectID: 1, ClusterID: 1})
+	if err != nil {
+		t.Fatalf("error creating app: %v", err)
+	}
+	shop, err := repo.PorterApp().CreatePorterApp(&models.PorterApp{Name: "shop", ProjectID: 1, ClusterID: 1})
+	if err != nil {
+		t.Fatalf("error creating app: %v", err)
+	}
+
+	if err := recordCustomDomains(ctx, repo.PorterAppDomain(), storefront, []string{"shop.example.com"}); err != nil {
+		t.Fatalf("error recording custom domains: %v", err)
+	}
+	// recording the domains again on the next deploy does not duplicate them
+	if err := recordCustomDomains(ctx, repo.PorterAppDomain(), storefront, []string{"shop.example.com"}); err != nil {
+		t.Fatalf("error recording custom domains: %v", err)
+	}
+	if domains, _ := repo.PorterAppDomain().ListPorterAppDomains(ctx, storefront.ID); len(domains) != 1 {
+		t.Errorf("expected the custom domain to be recorded once, got %v", domains)
+	}
+
+	err = checkCustomDomainsAvailable(ctx, repo, 1, shop.ID, []string{"shop.example.com", "www.shop.example.com"})
+	if err == nil || !strings.Contains(err.Error(), "shop.example.com is already served by app storefront") {
+		t.Errorf("expected the custom domain of another app to be rejected, got %v", err)
+	}
+	if err := checkCustomDomainsAvailable(ctx, repo, 1, 0, []string{"www.shop.example.com"}); err != nil {
+		t.Errorf("expected a domain which no app serves to be allowed, got %v", err)
+	}
+	if err := checkCustomDomainsAvailable(ctx, repo, 1, storefront.ID, []string{"shop.example.com"}); err != nil {
+		t.Errorf("expected an app to keep its own custom domains, got %v", err)
+	}
+	if err := checkCustomDomainsAvailable(ctx, repo, 2, 0, []string{"shop.example.com"}); err != nil {
+		t.Errorf("expected the apps of other projects to use the same domain, got %v", err)
+	}
+}
+
+func TestAppURLsFromValues(t *testing.T) {
+	values, _ := buildCustomDomainsTestValues(t, customDomainsPorterYaml, nil, &types.ClusterCapabilities{CertManager: true})
+
+	want := []string{"https://shop.example.com", "https://www.shop.example.com"}
+	if got := appURLsFromValues(values); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the urls of the custom domains, got %v", got)
+	}
+
+	// services whose ingress is disabled are not served on their hosts
+	values["web-web"].(map[string]interface{})["ingress"].(map[string]interface{})["enabled"] = false
+	if got := appURLsFromValues(values); len(got) != 0 {
+		t.Errorf("expected no urls for a disabled ingress, got %v", got)
+	}
+}
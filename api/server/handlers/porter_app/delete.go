@@ -54,6 +54,12 @@ func (c *DeletePorterAppByNameHandler) ServeHTTP(w http.ResponseWriter, r *http.
 
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-name", Value: appName})
 
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Delete); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
 	deleteReq := connect.NewRequest[porterv1.DeletePorterAppRequest](&porterv1.DeletePorterAppRequest{
 		ProjectId: int64(project.ID),
 		ClusterId: int64(cluster.ID),
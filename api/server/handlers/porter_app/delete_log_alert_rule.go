@@ -0,0 +1,90 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DeleteLogAlertRuleHandler handles DELETE requests to the /apps/{porter_app_name}/alert-rules/{log_alert_rule_id} endpoint
+type DeleteLogAlertRuleHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewDeleteLogAlertRuleHandler returns a new DeleteLogAlertRuleHandler
+func NewDeleteLogAlertRuleHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *DeleteLogAlertRuleHandler {
+	return &DeleteLogAlertRuleHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP deletes a log alert rule from an app
+func (c *DeleteLogAlertRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-log-alert-rule")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing app name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	ruleID, reqErr := requestutils.GetURLParamUint(r, types.URLParamLogAlertRuleID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing log alert rule id from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "log-alert-rule-id", Value: ruleID},
+	)
+
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rule, err := c.Repo().LogAlertRule().ReadLogAlertRule(ctx, project.ID, app.ID, ruleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "log alert rule not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Repo().LogAlertRule().DeleteLogAlertRule(ctx, rule); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, rule.ToLogAlertRuleType())
+}
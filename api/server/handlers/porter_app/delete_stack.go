@@ -0,0 +1,697 @@
+package porter_app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+	"gorm.io/gorm"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeletePorterAppPlanHandler handles GET /stacks/{porter_app_name}/delete_plan, which lists what deleting an app
+// deployed with porter apply v1 with the given options removes and what it leaves behind, along with the hash which
+// confirms the deletion
+type DeletePorterAppPlanHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewDeletePorterAppPlanHandler returns a new DeletePorterAppPlanHandler
+func NewDeletePorterAppPlanHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DeletePorterAppPlanHandler {
+	return &DeletePorterAppPlanHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *DeletePorterAppPlanHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-porter-app-plan")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.DeletePorterAppPlanRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-name", Value: appName},
+		telemetry.AttributeKV{Key: "delete-namespace", Value: request.DeleteNamespace},
+		telemetry.AttributeKV{Key: "delete-volumes", Value: request.DeleteVolumes},
+	)
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Delete); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	getNamespaceHelmAgent := func(namespace string) (*helm.Agent, error) {
+		return c.GetHelmAgent(ctx, r, cluster, namespace)
+	}
+
+	plan, err := planPorterAppDeletion(ctx, helmAgent, getNamespaceHelmAgent, k8sAgent, c.Repo(), porterApp, *request)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error planning porter app deletion")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, plan)
+}
+
+// DeletePorterAppHandler handles DELETE /stacks/{porter_app_name}, which deletes an app deployed with porter apply v1
+// as described by its deletion plan: the helm releases of the app and of its pre-deploy job, optionally its volumes and
+// namespace, its porter managed DNS records, and its record and events. The plan is read again and the deletion is
+// refused if its hash does not match the one in the request. A DELETE event is recorded for the app once it is deleted,
+// and the resources which are released asynchronously are tracked by a cleanup.
+// Apps deployed with porter apply v2 are deleted by DeletePorterAppByNameHandler instead.
+type DeletePorterAppHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewDeletePorterAppHandler returns a new DeletePorterAppHandler
+func NewDeletePorterAppHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DeletePorterAppHandler {
+	return &DeletePorterAppHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *DeletePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-porter-app")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.DeletePorterAppRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-name", Value: appName},
+		telemetry.AttributeKV{Key: "delete-namespace", Value: request.DeleteNamespace},
+		telemetry.AttributeKV{Key: "delete-volumes", Value: request.DeleteVolumes},
+		telemetry.AttributeKV{Key: "plan-hash", Value: request.PlanHash},
+	)
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Delete); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	getNamespaceHelmAgent := func(namespace string) (*helm.Agent, error) {
+		return c.GetHelmAgent(ctx, r, cluster, namespace)
+	}
+
+	plan, err := planPorterAppDeletion(ctx, helmAgent, getNamespaceHelmAgent, k8sAgent, c.Repo(), porterApp, request.DeletePorterAppPlanRequest)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error planning porter app deletion")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if plan.PlanHash != request.PlanHash {
+		err := telemetry.Error(ctx, span, nil, "the deletion plan has changed since it was read, read it again to confirm what is deleted")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	res, err := deletePorterApp(ctx, helmAgent, getNamespaceHelmAgent, k8sAgent, c.Repo(), porterApp, plan, request.DeletePorterAppPlanRequest, c.Config().ServerConf.PorterAppCleanupTimeout)
+	if err != nil {
+		err = telemetry.Error(ctx, span, fmt.Errorf("error deleting porter app, removed %s: %w", removedResources(res, namespace), err), "error deleting porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// planPorterAppDeletion reads from the cluster what deleting an app with the given options removes and what it leaves
+// behind. The plan only depends on the state of the app and the options, so that reading it twice without a change
+// in between gives the same hash. The releases of the app in the other namespaces its services run in are read with
+// getHelmAgent.
+func planPorterAppDeletion(
+	ctx context.Context,
+	helmAgent *helm.Agent,
+	getHelmAgent helmAgentForNamespace,
+	k8sAgent *kubernetes.Agent,
+	repo repository.Repository,
+	porterApp *models.PorterApp,
+	opts types.DeletePorterAppPlanRequest,
+) (types.DeletePorterAppPlan, error) {
+	ctx, span := telemetry.NewSpan(ctx, "plan-porter-app-deletion")
+	defer span.End()
+
+	namespace := utils.NamespaceFromPorterAppName(porterApp.Name)
+
+	plan := types.DeletePorterAppPlan{
+		Releases:               []string{},
+		PersistentVolumeClaims: []string{},
+		DNSRecords:             []string{},
+		Orphaned:               []types.OrphanedResource{},
+	}
+	var serviceNamespaces []string
+
+	// the pre-deploy job is uninstalled first, so that it cannot run against an app which is being removed
+	for _, name := range []string{utils.PredeployJobNameFromPorterAppName(porterApp.Name), porterApp.Name} {
+		rel, err := helmAgent.GetRelease(ctx, name, 0, false)
+		if err != nil {
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				continue
+			}
+			return plan, telemetry.Error(ctx, span, err, fmt.Sprintf("error getting release %s", name))
+		}
+
+		plan.Releases = append(plan.Releases, name)
+
+		// the domains of the app are read from its release, since they are not stored with the app
+		if name == porterApp.Name {
+			plan.DNSRecords = append(plan.DNSRecords, porterHostsFromValues(rel.Config)...)
+			serviceNamespaces = otherNamespaces(serviceNamespacesFromValues(rel.Config))
+		}
+	}
+
+	for _, serviceNamespace := range serviceNamespaces {
+		namespaceAgent, err := getHelmAgent(serviceNamespace)
+		if err != nil {
+			return plan, telemetry.Error(ctx, span, err, fmt.Sprintf("error getting helm agent for namespace %s", serviceNamespace))
+		}
+
+		rel, err := namespaceAgent.GetRelease(ctx, porterApp.Name, 0, false)
+		if err != nil {
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				continue
+			}
+			return plan, telemetry.Error(ctx, span, err, fmt.Sprintf("error getting release %s in namespace %s", porterApp.Name, serviceNamespace))
+		}
+
+		plan.ServiceNamespaces = append(plan.ServiceNamespaces, serviceNamespace)
+		plan.DNSRecords = append(plan.DNSRecords, porterHostsFromValues(rel.Config)...)
+	}
+	sort.Strings(plan.DNSRecords)
+
+	if opts.DeleteNamespace {
+		plan.Namespace = namespace
+	}
+
+	pvcs, err := k8sAgent.Clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return plan, telemetry.Error(ctx, span, err, "error listing persistent volume claims")
+	}
+	for _, pvc := range pvcs.Items {
+		if opts.DeleteVolumes || opts.DeleteNamespace {
+			plan.PersistentVolumeClaims = append(plan.PersistentVolumeClaims, pvc.Name)
+			continue
+		}
+
+		plan.Orphaned = append(plan.Orphaned, types.OrphanedResource{
+			Kind:   types.OrphanedResourceKind_PersistentVolumeClaim,
+			Name:   pvc.Name,
+			Reason: "delete_volumes is not set, so the claim and its volume are kept",
+		})
+	}
+
+	services, err := k8sAgent.Clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return plan, telemetry.Error(ctx, span, err, "error listing services")
+	}
+	for _, service := range services.Items {
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+		// services which are not part of a release are only removed with the namespace
+		if !opts.DeleteNamespace && !containsString(plan.Releases, service.Annotations["meta.helm.sh/release-name"]) {
+			continue
+		}
+
+		plan.Orphaned = append(plan.Orphaned, types.OrphanedResource{
+			Kind:   types.OrphanedResourceKind_LoadBalancer,
+			Name:   service.Name,
+			Reason: "the cloud load balancer is released by the finalizer of the service after it is deleted",
+		})
+	}
+
+	// the other namespaces are not managed by porter, so only what the release of the app installed in them is removed
+	for _, serviceNamespace := range plan.ServiceNamespaces {
+		orphaned, err := serviceNamespaceOrphans(ctx, k8sAgent, porterApp.Name, serviceNamespace)
+		if err != nil {
+			return plan, telemetry.Error(ctx, span, err, fmt.Sprintf("error listing resources in namespace %s", serviceNamespace))
+		}
+		plan.Orphaned = append(plan.Orphaned, orphaned...)
+	}
+
+	for _, hostname := range plan.DNSRecords {
+		plan.Orphaned = append(plan.Orphaned, types.OrphanedResource{
+			Kind:   types.OrphanedResourceKind_DNSRecord,
+			Name:   hostname,
+			Reason: "the domain resolves until the removal of its record has propagated",
+		})
+	}
+
+	if !opts.DeleteNamespace {
+		kept, err := unmanagedNamespaceResources(ctx, k8sAgent, namespace)
+		if err != nil {
+			return plan, telemetry.Error(ctx, span, err, "error listing namespace resources")
+		}
+		if kept != "" {
+			plan.Orphaned = append(plan.Orphaned, types.OrphanedResource{
+				Kind:   types.OrphanedResourceKind_Namespace,
+				Name:   namespace,
+				Reason: fmt.Sprintf("delete_namespace is not set, so the namespace is kept along with %s", kept),
+			})
+		}
+	}
+
+	_, events, err := repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, porterApp.ID, repository.PorterAppEventFilter{Limit: 1})
+	if err != nil {
+		return plan, telemetry.Error(ctx, span, err, "error counting porter app events")
+	}
+	plan.Events = events
+
+	plan.PlanHash, err = deletePlanHash(plan)
+	if err != nil {
+		return plan, telemetry.Error(ctx, span, err, "error hashing deletion plan")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "releases", Value: strings.Join(plan.Releases, ",")},
+		telemetry.AttributeKV{Key: "orphaned", Value: len(plan.Orphaned)},
+		telemetry.AttributeKV{Key: "plan-hash", Value: plan.PlanHash},
+	)
+
+	return plan, nil
+}
+
+// serviceNamespaceOrphans returns the resources of the release of an app in another namespace which its services run
+// in that are left behind once it is uninstalled. Volumes are only deleted from the namespace of the app, so the claims
+// in the namespace are always kept.
+func serviceNamespaceOrphans(ctx context.Context, k8sAgent *kubernetes.Agent, appName, namespace string) ([]types.OrphanedResource, error) {
+	var orphaned []types.OrphanedResource
+
+	pvcs, err := k8sAgent.Clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing persistent volume claims: %w", err)
+	}
+	for _, pvc := range pvcs.Items {
+		// claims of the volume templates of a stateful set are named after the set, and are not part of the release
+		if pvc.Annotations["meta.helm.sh/release-name"] != appName && !strings.HasPrefix(pvc.Name, appName+"-") {
+			continue
+		}
+
+		orphaned = append(orphaned, types.OrphanedResource{
+			Kind:      types.OrphanedResourceKind_PersistentVolumeClaim,
+			Name:      pvc.Name,
+			Namespace: namespace,
+			Reason:    "volumes are only deleted from the namespace of the app, so the claim and its volume are kept",
+		})
+	}
+
+	services, err := k8sAgent.Clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing services: %w", err)
+	}
+	for _, service := range services.Items {
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer || service.Annotations["meta.helm.sh/release-name"] != appName {
+			continue
+		}
+
+		orphaned = append(orphaned, types.OrphanedResource{
+			Kind:      types.OrphanedResourceKind_LoadBalancer,
+			Name:      service.Name,
+			Namespace: namespace,
+			Reason:    "the cloud load balancer is released by the finalizer of the service after it is deleted",
+		})
+	}
+
+	return orphaned, nil
+}
+
+// unmanagedNamespaceResources describes the config maps and secrets of a namespace which were not created by helm, and
+// are left in it once the releases of the app are uninstalled. It is empty if the namespace does not exist.
+func unmanagedNamespaceResources(ctx context.Context, k8sAgent *kubernetes.Agent, namespace string) (string, error) {
+	if _, err := k8sAgent.Clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("error getting namespace: %w", err)
+	}
+
+	configMaps, err := k8sAgent.Clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error listing config maps: %w", err)
+	}
+	secrets, err := k8sAgent.Clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error listing secrets: %w", err)
+	}
+
+	var unmanagedConfigMaps, unmanagedSecrets int
+	for _, configMap := range configMaps.Items {
+		if !createdByHelm(configMap.ObjectMeta) {
+			unmanagedConfigMaps++
+		}
+	}
+	for _, secret := range secrets.Items {
+		if !createdByHelm(secret.ObjectMeta) {
+			unmanagedSecrets++
+		}
+	}
+
+	return fmt.Sprintf("%d config maps and %d secrets which were not created by helm", unmanagedConfigMaps, unmanagedSecrets), nil
+}
+
+// createdByHelm reports whether an object is part of a release or stores one, so that it is removed when the release
+// is uninstalled
+func createdByHelm(meta metav1.ObjectMeta) bool {
+	return meta.Labels["app.kubernetes.io/managed-by"] == "Helm" || meta.Labels["owner"] == "helm"
+}
+
+// deletePlanHash returns the hex sha256 of a plan without its hash
+func deletePlanHash(plan types.DeletePorterAppPlan) (string, error) {
+	plan.PlanHash = ""
+
+	by, err := json.Marshal(plan)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(by)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// deletePorterApp uninstalls the releases of an app, then deletes its volumes if deleteVolumes is set and its
+// namespace if deleteNamespace is set, then the DNS records of its porter managed domains, then its events and record,
+// following the given plan. Releases and volumes which are already gone are skipped. If a step fails, the steps after
+// it are not run, so that the record of the app is kept for the deletion to be retried; the response lists what was
+// removed before the failure. Once the app is deleted, its load balancers and domains are tracked by a cleanup which
+// flags them as leaked if they are not released within cleanupTimeout. The releases of the app in the other namespaces
+// its services run in are uninstalled with getHelmAgent, and those namespaces are kept.
+func deletePorterApp(
+	ctx context.Context,
+	helmAgent *helm.Agent,
+	getHelmAgent helmAgentForNamespace,
+	k8sAgent *kubernetes.Agent,
+	repo repository.Repository,
+	porterApp *models.PorterApp,
+	plan types.DeletePorterAppPlan,
+	opts types.DeletePorterAppPlanRequest,
+	cleanupTimeout time.Duration,
+) (types.DeletePorterAppResponse, error) {
+	ctx, span := telemetry.NewSpan(ctx, "delete-porter-app")
+	defer span.End()
+
+	namespace := utils.NamespaceFromPorterAppName(porterApp.Name)
+
+	res := types.DeletePorterAppResponse{
+		UninstalledReleases: []string{},
+		DeletedVolumes:      []string{},
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-hosts", Value: strings.Join(plan.DNSRecords, ",")})
+
+	for _, name := range plan.Releases {
+		_, err := helmAgent.UninstallChart(ctx, name)
+		if err != nil {
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				telemetry.WithAttributes(span, telemetry.AttributeKV{Key: telemetry.AttributeKey(fmt.Sprintf("release-%s-not-found", name)), Value: true})
+				continue
+			}
+
+			return res, telemetry.Error(ctx, span, err, fmt.Sprintf("error uninstalling release %s", name))
+		}
+
+		res.UninstalledReleases = append(res.UninstalledReleases, name)
+	}
+
+	for _, serviceNamespace := range plan.ServiceNamespaces {
+		namespaceAgent, err := getHelmAgent(serviceNamespace)
+		if err != nil {
+			return res, telemetry.Error(ctx, span, err, fmt.Sprintf("error getting helm agent for namespace %s", serviceNamespace))
+		}
+
+		_, err = namespaceAgent.UninstallChart(ctx, porterApp.Name)
+		if err != nil {
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				continue
+			}
+
+			return res, telemetry.Error(ctx, span, err, fmt.Sprintf("error uninstalling release %s in namespace %s", porterApp.Name, serviceNamespace))
+		}
+
+		res.UninstalledReleases = append(res.UninstalledReleases, fmt.Sprintf("%s/%s", serviceNamespace, porterApp.Name))
+	}
+
+	if opts.DeleteVolumes {
+		for _, name := range plan.PersistentVolumeClaims {
+			err := k8sAgent.Clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+			if err != nil {
+				if k8serrors.IsNotFound(err) {
+					continue
+				}
+
+				return res, telemetry.Error(ctx, span, err, fmt.Sprintf("error deleting persistent volume claim %s", name))
+			}
+
+			res.DeletedVolumes = append(res.DeletedVolumes, name)
+		}
+	}
+
+	if opts.DeleteNamespace {
+		if err := k8sAgent.DeleteNamespace(namespace); err != nil {
+			return res, telemetry.Error(ctx, span, err, "error deleting namespace")
+		}
+
+		res.DeletedNamespace = true
+	}
+
+	deletedRecords, err := repo.DNSRecord().DeleteDNSRecordsByHostname(plan.DNSRecords)
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error deleting dns records")
+	}
+	res.DeletedDNSRecords = deletedRecords
+
+	deleted, err := repo.PorterAppEvent().DeleteEventsByPorterAppID(ctx, porterApp.ID)
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error deleting porter app events")
+	}
+	res.DeletedEvents = deleted
+
+	if _, err := repo.PorterApp().DeletePorterApp(porterApp); err != nil {
+		return res, telemetry.Error(ctx, span, err, "error deleting porter app")
+	}
+	res.DeletedApp = true
+
+	// the app is soft deleted, so the event is kept as a record of the deletion
+	if err := repo.PorterAppEvent().CreateEvent(ctx, &models.PorterAppEvent{
+		ID:          uuid.New(),
+		Type:        string(types.PorterAppEventType_Delete),
+		Status:      string(types.PorterAppEventStatus_Success),
+		PorterAppID: porterApp.ID,
+		Metadata: map[string]any{
+			"uninstalled_releases": res.UninstalledReleases,
+			"deleted_namespace":    res.DeletedNamespace,
+			"deleted_volumes":      res.DeletedVolumes,
+			"deleted_dns_records":  res.DeletedDNSRecords,
+			"plan_hash":            plan.PlanHash,
+		},
+	}); err != nil {
+		// the app is already deleted, so failing to record it is not returned to the client
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "create-delete-event-error", Value: err.Error()})
+	}
+
+	var pending models.PorterAppCleanupResources
+	for _, orphaned := range plan.Orphaned {
+		if orphaned.Kind == types.OrphanedResourceKind_LoadBalancer || orphaned.Kind == types.OrphanedResourceKind_DNSRecord {
+			pending = append(pending, types.PorterAppCleanupResource{Kind: orphaned.Kind, Name: orphaned.Name, Namespace: orphaned.Namespace})
+		}
+	}
+	if len(pending) == 0 {
+		return res, nil
+	}
+
+	cleanup, err := repo.PorterAppCleanup().CreatePorterAppCleanup(ctx, &models.PorterAppCleanup{
+		ProjectID: porterApp.ProjectID,
+		ClusterID: porterApp.ClusterID,
+		AppName:   porterApp.Name,
+		Namespace: namespace,
+		Status:    string(types.PorterAppCleanupStatus_Pending),
+		Pending:   pending,
+		Deadline:  time.Now().UTC().Add(cleanupTimeout),
+	})
+	if err != nil {
+		// as for the event, the app is already deleted, so this is not returned to the client
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "create-cleanup-error", Value: err.Error()})
+		return res, nil
+	}
+	res.CleanupID = cleanup.ID
+
+	return res, nil
+}
+
+// porterHostsFromValues returns the porter managed domains of the services in the values of an app release
+func porterHostsFromValues(values map[string]interface{}) []string {
+	var hosts []string
+
+	for _, serviceValues := range values {
+		serviceMap, ok := serviceValues.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ingress, err := getNestedMap(serviceMap, "ingress")
+		if err != nil {
+			continue
+		}
+
+		switch porterHosts := ingress["porter_hosts"].(type) {
+		case []interface{}:
+			for _, host := range porterHosts {
+				if h, ok := host.(string); ok && h != "" {
+					hosts = append(hosts, h)
+				}
+			}
+		case []string:
+			hosts = append(hosts, porterHosts...)
+		}
+	}
+
+	sort.Strings(hosts)
+
+	return hosts
+}
+
+// removedResources describes what a failed deletion removed, for the error returned to the client
+func removedResources(res types.DeletePorterAppResponse, namespace string) string {
+	removed := make([]string, 0)
+
+	for _, name := range res.UninstalledReleases {
+		removed = append(removed, fmt.Sprintf("release %s", name))
+	}
+	for _, name := range res.DeletedVolumes {
+		removed = append(removed, fmt.Sprintf("persistent volume claim %s", name))
+	}
+	if res.DeletedNamespace {
+		removed = append(removed, fmt.Sprintf("namespace %s", namespace))
+	}
+	if res.DeletedDNSRecords > 0 {
+		removed = append(removed, fmt.Sprintf("%d dns records", res.DeletedDNSRecords))
+	}
+	if res.DeletedEvents > 0 {
+		removed = append(removed, fmt.Sprintf("%d events", res.DeletedEvents))
+	}
+
+	if len(removed) == 0 {
+		return "nothing"
+	}
+
+	return strings.Join(removed, ", ")
+}
@@ -0,0 +1,308 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	kubefake "github.com/stefanmcshane/helm/pkg/kube/fake"
+	"github.com/stefanmcshane/helm/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createStack creates a porter apply v1 app with an event, and installs the given releases of it
+func createStack(t *testing.T, releases ...string) (*helm.Agent, *kubernetes.Agent, *test.TestRepository, *models.PorterApp) {
+	t.Helper()
+
+	ctx := context.Background()
+	repo := test.NewRepository(true).(*test.TestRepository)
+
+	app, err := repo.PorterApp().CreatePorterApp(&models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "payments"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.PorterAppEvent().CreateEvent(ctx, &models.PorterAppEvent{PorterAppID: app.ID, Type: "DEPLOY"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	k8sAgent := kubernetes.GetAgentTesting(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "porter-stack-payments"}})
+	helmAgent := helm.GetAgentTesting(&helm.Form{Namespace: "porter-stack-payments"}, nil, logger.NewConsole(true), k8sAgent)
+
+	for _, name := range releases {
+		err := helmAgent.ActionConfig.Releases.Create(&release.Release{
+			Name:      name,
+			Namespace: "porter-stack-payments",
+			Version:   1,
+			Info:      &release.Info{Status: release.StatusDeployed},
+			Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "umbrella", Version: "0.1.0"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	return helmAgent, k8sAgent, repo, app
+}
+
+// deleteStack plans the deletion of an app with the given options, then deletes it as planned
+func deleteStack(t *testing.T, helmAgent *helm.Agent, k8sAgent *kubernetes.Agent, repo *test.TestRepository, app *models.PorterApp, opts types.DeletePorterAppPlanRequest) (types.DeletePorterAppResponse, error) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	plan, err := planPorterAppDeletion(ctx, helmAgent, nil, k8sAgent, repo, app, opts)
+	if err != nil {
+		t.Fatalf("unexpected error planning deletion: %v", err)
+	}
+
+	return deletePorterApp(ctx, helmAgent, nil, k8sAgent, repo, app, plan, opts, time.Hour)
+}
+
+func TestDeletePorterApp(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("releases, namespace and record are removed", func(t *testing.T) {
+		helmAgent, k8sAgent, repo, app := createStack(t, "payments", "payments-r")
+
+		res, err := deleteStack(t, helmAgent, k8sAgent, repo, app, types.DeletePorterAppPlanRequest{DeleteNamespace: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Join(res.UninstalledReleases, ",") != "payments-r,payments" {
+			t.Errorf("expected the pre-deploy job and app releases to be uninstalled, got %v", res.UninstalledReleases)
+		}
+		if !res.DeletedNamespace || !res.DeletedApp || res.DeletedEvents != 1 {
+			t.Errorf("expected the namespace, app and its event to be deleted, got %+v", res)
+		}
+
+		if _, err := k8sAgent.Clientset.CoreV1().Namespaces().Get(ctx, "porter-stack-payments", metav1.GetOptions{}); err == nil {
+			t.Error("expected the namespace to be deleted")
+		}
+		if _, err := repo.PorterApp().ReadScopedPorterAppByName(1, 1, "payments"); err == nil {
+			t.Error("expected the app to be deleted")
+		}
+	})
+
+	t.Run("porter managed dns records are removed and the deletion is recorded", func(t *testing.T) {
+		helmAgent, k8sAgent, repo, app := createStack(t)
+
+		err := helmAgent.ActionConfig.Releases.Create(&release.Release{
+			Name:      "payments",
+			Namespace: "porter-stack-payments",
+			Version:   1,
+			Info:      &release.Info{Status: release.StatusDeployed},
+			Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "umbrella", Version: "0.1.0"}},
+			Config: map[string]interface{}{
+				"web-web": map[string]interface{}{
+					"ingress": map[string]interface{}{"porter_hosts": []interface{}{"payments-abc.withporter.run"}},
+				},
+				"admin-web": map[string]interface{}{
+					"ingress": map[string]interface{}{"hosts": []interface{}{"admin.example.com"}},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, hostname := range []string{"payments-abc.withporter.run", "other-def.withporter.run"} {
+			if _, err := repo.DNSRecord().CreateDNSRecord(&models.DNSRecord{Hostname: hostname}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		res, err := deleteStack(t, helmAgent, k8sAgent, repo, app, types.DeletePorterAppPlanRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if res.DeletedDNSRecords != 1 {
+			t.Errorf("expected only the dns record of the app to be deleted, got %d", res.DeletedDNSRecords)
+		}
+		if deleted, _ := repo.DNSRecord().DeleteDNSRecordsByHostname([]string{"other-def.withporter.run"}); deleted != 1 {
+			t.Error("expected the dns record of another app to be kept")
+		}
+
+		events, _, err := repo.PorterAppEvent().ListEventsByPorterAppID(ctx, app.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(events) != 1 || events[0].Type != string(types.PorterAppEventType_Delete) {
+			t.Errorf("expected only a delete event to be left for the app, got %+v", events)
+		}
+	})
+
+	t.Run("releases which are already gone do not stop the record being removed", func(t *testing.T) {
+		helmAgent, k8sAgent, repo, app := createStack(t, "payments")
+
+		res, err := deleteStack(t, helmAgent, k8sAgent, repo, app, types.DeletePorterAppPlanRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Join(res.UninstalledReleases, ",") != "payments" {
+			t.Errorf("expected only the app release to be uninstalled, got %v", res.UninstalledReleases)
+		}
+		if res.DeletedNamespace || !res.DeletedApp {
+			t.Errorf("expected the app to be deleted and the namespace to be kept, got %+v", res)
+		}
+
+		if _, err := k8sAgent.Clientset.CoreV1().Namespaces().Get(ctx, "porter-stack-payments", metav1.GetOptions{}); err != nil {
+			t.Errorf("expected the namespace to be kept, got %v", err)
+		}
+	})
+
+	t.Run("a release which fails to uninstall keeps the record", func(t *testing.T) {
+		helmAgent, k8sAgent, repo, app := createStack(t, "payments", "payments-r")
+
+		// the pre-deploy job is uninstalled, then the app release fails to
+		if _, err := helmAgent.UninstallChart(ctx, "payments-r"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		helmAgent.ActionConfig.KubeClient = &kubefake.FailingKubeClient{
+			PrintingKubeClient: kubefake.PrintingKubeClient{Out: io.Discard},
+			BuildError:         errors.New("connection refused"),
+		}
+
+		res, err := deleteStack(t, helmAgent, k8sAgent, repo, app, types.DeletePorterAppPlanRequest{DeleteNamespace: true})
+		if err == nil {
+			t.Fatal("expected an error uninstalling the app release")
+		}
+
+		if len(res.UninstalledReleases) != 0 || res.DeletedNamespace || res.DeletedApp {
+			t.Errorf("expected nothing to be removed, got %+v", res)
+		}
+		if got := removedResources(res, "porter-stack-payments"); got != "nothing" {
+			t.Errorf("expected the error to report that nothing was removed, got %s", got)
+		}
+
+		if _, err := repo.PorterApp().ReadScopedPorterAppByName(1, 1, "payments"); err != nil {
+			t.Errorf("expected the app to be kept for the deletion to be retried, got %v", err)
+		}
+		if _, err := k8sAgent.Clientset.CoreV1().Namespaces().Get(ctx, "porter-stack-payments", metav1.GetOptions{}); err != nil {
+			t.Errorf("expected the namespace to be kept, got %v", err)
+		}
+	})
+}
+
+func TestPlanPorterAppDeletion(t *testing.T) {
+	ctx := context.Background()
+
+	// createVolumeAndLoadBalancer adds a claim and a LoadBalancer service of the app release to the namespace
+	createVolumeAndLoadBalancer := func(t *testing.T, k8sAgent *kubernetes.Agent) {
+		t.Helper()
+
+		_, err := k8sAgent.Clientset.CoreV1().PersistentVolumeClaims("porter-stack-payments").Create(ctx, &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-payments-db-0", Namespace: "porter-stack-payments"},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = k8sAgent.Clientset.CoreV1().Services("porter-stack-payments").Create(ctx, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "payments-web",
+				Namespace:   "porter-stack-payments",
+				Annotations: map[string]string{"meta.helm.sh/release-name": "payments"},
+			},
+			Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("volumes and the namespace are left behind unless they are deleted", func(t *testing.T) {
+		helmAgent, k8sAgent, repo, app := createStack(t, "payments")
+		createVolumeAndLoadBalancer(t, k8sAgent)
+
+		plan, err := planPorterAppDeletion(ctx, helmAgent, nil, k8sAgent, repo, app, types.DeletePorterAppPlanRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Join(plan.Releases, ",") != "payments" || plan.Namespace != "" || len(plan.PersistentVolumeClaims) != 0 || plan.Events != 1 {
+			t.Errorf("expected only the app release and its event to be deleted, got %+v", plan)
+		}
+
+		kinds := make([]string, 0, len(plan.Orphaned))
+		for _, orphaned := range plan.Orphaned {
+			kinds = append(kinds, orphaned.Kind+"/"+orphaned.Name)
+		}
+		want := "PersistentVolumeClaim/data-payments-db-0,LoadBalancer/payments-web,Namespace/porter-stack-payments"
+		if strings.Join(kinds, ",") != want {
+			t.Errorf("expected %s to be left behind, got %v", want, kinds)
+		}
+
+		withVolumes, err := planPorterAppDeletion(ctx, helmAgent, nil, k8sAgent, repo, app, types.DeletePorterAppPlanRequest{DeleteVolumes: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(withVolumes.PersistentVolumeClaims, ",") != "data-payments-db-0" {
+			t.Errorf("expected the claim to be deleted with delete_volumes, got %v", withVolumes.PersistentVolumeClaims)
+		}
+		if withVolumes.PlanHash == plan.PlanHash {
+			t.Error("expected plans of different options to have different hashes")
+		}
+
+		again, err := planPorterAppDeletion(ctx, helmAgent, nil, k8sAgent, repo, app, types.DeletePorterAppPlanRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if again.PlanHash != plan.PlanHash {
+			t.Errorf("expected the same plan to have the same hash, got %s and %s", plan.PlanHash, again.PlanHash)
+		}
+	})
+
+	t.Run("volumes are deleted and load balancers are tracked by a cleanup", func(t *testing.T) {
+		helmAgent, k8sAgent, repo, app := createStack(t, "payments")
+		createVolumeAndLoadBalancer(t, k8sAgent)
+
+		res, err := deleteStack(t, helmAgent, k8sAgent, repo, app, types.DeletePorterAppPlanRequest{DeleteVolumes: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Join(res.DeletedVolumes, ",") != "data-payments-db-0" {
+			t.Errorf("expected the claim to be deleted, got %v", res.DeletedVolumes)
+		}
+		if _, err := k8sAgent.Clientset.CoreV1().PersistentVolumeClaims("porter-stack-payments").Get(ctx, "data-payments-db-0", metav1.GetOptions{}); err == nil {
+			t.Error("expected the claim to be removed from the cluster")
+		}
+
+		if res.CleanupID == 0 {
+			t.Fatal("expected a cleanup to track the load balancer")
+		}
+		cleanup, err := repo.PorterAppCleanup().ReadPorterAppCleanup(ctx, 1, res.CleanupID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cleanup.Status != string(types.PorterAppCleanupStatus_Pending) || len(cleanup.Pending) != 1 || cleanup.Pending[0].Name != "payments-web" {
+			t.Errorf("expected the load balancer to be pending, got %s %v", cleanup.Status, cleanup.Pending)
+		}
+	})
+
+	t.Run("no cleanup is created when nothing is released asynchronously", func(t *testing.T) {
+		helmAgent, k8sAgent, repo, app := createStack(t, "payments")
+
+		res, err := deleteStack(t, helmAgent, k8sAgent, repo, app, types.DeletePorterAppPlanRequest{DeleteNamespace: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.CleanupID != 0 {
+			t.Errorf("expected no cleanup, got %d", res.CleanupID)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+const (
+	// deployStage_Install is the install of the chart of an app which has no release yet
+	deployStage_Install = "install"
+	// deployStage_Upgrade is the upgrade of the chart of an app, recorded by rollbackFailedUpgrade
+	deployStage_Upgrade = "upgrade"
+	// deployStage_PreDeployConfig is building the chart of the pre-deploy job
+	deployStage_PreDeployConfig = "pre-deploy-config"
+	// deployStage_PreDeployInstall is the install of the chart of the pre-deploy job
+	deployStage_PreDeployInstall = "pre-deploy-install"
+	// deployStage_PreDeployUpgrade is the upgrade of the chart of the pre-deploy job
+	deployStage_PreDeployUpgrade = "pre-deploy-upgrade"
+	// deployStage_PreDeployUninstall is the removal of a pre-deploy job which the porter.yaml no longer defines
+	deployStage_PreDeployUninstall = "pre-deploy-uninstall"
+	// deployStage_Canary is the canary of an update with a canary rollout strategy, before the chart of the app is upgraded
+	deployStage_Canary = "canary"
+	// deployStage_ServiceNamespaces is the install or upgrade of the releases of an app in the other namespaces its
+	// services run in, before the chart of the app
+	deployStage_ServiceNamespaces = "service-namespaces"
+)
+
+// deployFailure is why a deploy failed, recorded in the metadata of its FAILED event
+type deployFailure struct {
+	// stage is the step of the deploy which failed
+	stage string
+	// reason is a short cause of the failure, for stages which tell their failures apart, such as the canary
+	reason string
+	err    error
+	// cleanupErr is the error removing what the failed step left behind, if that failed too. It is recorded on the
+	// same event, so that each failed deploy is recorded once.
+	cleanupErr error
+}
+
+// Error describes the failure, along with the failed cleanup, for the error returned to the client
+func (f *deployFailure) Error() string {
+	if f.cleanupErr != nil {
+		return fmt.Sprintf("%s; removing the failed chart also failed: %s", f.err, f.cleanupErr)
+	}
+
+	return f.err.Error()
+}
+
+func (f *deployFailure) Unwrap() error {
+	return f.err
+}
+
+func (f *deployFailure) addTo(metadata map[string]any) {
+	metadata["error"] = f.err.Error()
+	metadata["stage"] = f.stage
+	if f.reason != "" {
+		metadata["reason"] = f.reason
+	}
+	if f.cleanupErr != nil {
+		metadata["cleanup_error"] = f.cleanupErr.Error()
+	}
+}
+
+// recordFailedDeploy records a deploy which failed before the chart of the app was upgraded, such as a failed install,
+// as a FAILED deploy event. Failed upgrades are recorded by rollbackFailedUpgrade instead. Apps which are deployed for
+// the first time have no record to attach the event to until their chart is installed, so their failures are only
+// traced. The deploy fails either way, so errors recording the event are only traced too.
+func recordFailedDeploy(ctx context.Context, conf *config.Config, projectID, clusterID uint, appName, tag string, failure *deployFailure) {
+	ctx, span := telemetry.NewSpan(ctx, "record-failed-deploy")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "stage", Value: failure.stage},
+		telemetry.AttributeKV{Key: "cleanup-failed", Value: failure.cleanupErr != nil},
+	)
+
+	app, err := conf.Repo.PorterApp().ReadScopedPorterAppByName(projectID, clusterID, appName)
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error reading porter app")
+		return
+	}
+
+	event := models.PorterAppEvent{
+		ID:                 uuid.New(),
+		Status:             string(types.PorterAppEventStatus_Failed),
+		Type:               string(types.PorterAppEventType_Deploy),
+		TypeExternalSource: "KUBERNETES",
+		PorterAppID:        app.ID,
+		Metadata: map[string]any{
+			"image_tag": tag,
+		},
+	}
+	failure.addTo(event.Metadata)
+
+	if err := conf.Repo.PorterAppEvent().CreateEvent(ctx, &event); err != nil {
+		_ = telemetry.Error(ctx, span, err, "error creating failed deploy event")
+	}
+}
@@ -0,0 +1,93 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+func TestRecordFailedDeploy(t *testing.T) {
+	ctx := context.Background()
+	installErr := errors.New("timed out waiting for the condition")
+
+	setup := func(t *testing.T) *config.Config {
+		t.Helper()
+
+		repo := test.NewRepository(true)
+		if _, err := repo.PorterApp().CreatePorterApp(&models.PorterApp{Name: "payments", ProjectID: 1, ClusterID: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return &config.Config{Repo: repo}
+	}
+
+	t.Run("a failed cleanup is recorded on the same event", func(t *testing.T) {
+		conf := setup(t)
+
+		failure := &deployFailure{stage: deployStage_Install, err: installErr, cleanupErr: errors.New("release: not found")}
+		recordFailedDeploy(ctx, conf, 1, 1, "payments", "8f14e45f", failure)
+
+		events, _, err := conf.Repo.PorterAppEvent().ListEventsByPorterAppID(ctx, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(events) != 1 {
+			t.Fatalf("expected a single failed deploy event, got %d", len(events))
+		}
+
+		event := events[0]
+		if event.Type != string(types.PorterAppEventType_Deploy) || event.Status != string(types.PorterAppEventStatus_Failed) {
+			t.Errorf("expected a failed deploy event, got %s %s", event.Type, event.Status)
+		}
+		if event.Metadata["error"] != installErr.Error() || event.Metadata["stage"] != deployStage_Install || event.Metadata["cleanup_error"] != "release: not found" {
+			t.Errorf("expected the failure in the metadata, got %v", event.Metadata)
+		}
+
+		if got := failure.Error(); got != "timed out waiting for the condition; removing the failed chart also failed: release: not found" {
+			t.Errorf("expected the error to describe both failures, got %s", got)
+		}
+		if !errors.Is(failure, installErr) {
+			t.Error("expected the failure to wrap the install error")
+		}
+	})
+
+	t.Run("failed pre-deploy jobs are recorded as pre-deploy events", func(t *testing.T) {
+		conf := setup(t)
+
+		recordFailedPreDeploy(ctx, conf, 1, 1, "payments", "8f14e45f", &deployFailure{stage: deployStage_PreDeployUpgrade, err: installErr})
+
+		event, err := conf.Repo.PorterAppEvent().ReadLatestEventByType(ctx, 1, string(types.PorterAppEventType_PreDeploy))
+		if err != nil {
+			t.Fatalf("expected the failed pre-deploy job to be recorded, got %v", err)
+		}
+		if event.Status != string(types.PorterAppEventStatus_Failed) || event.Metadata["stage"] != deployStage_PreDeployUpgrade {
+			t.Errorf("unexpected pre-deploy event: %+v", event)
+		}
+		if _, ok := event.Metadata["cleanup_error"]; ok {
+			t.Error("expected no cleanup error without a failed cleanup")
+		}
+
+		if _, err := conf.Repo.PorterAppEvent().ReadLatestEventByType(ctx, 1, string(types.PorterAppEventType_Deploy)); err == nil {
+			t.Error("expected no deploy event to be recorded for the failed pre-deploy job")
+		}
+	})
+
+	t.Run("apps without a record are not recorded", func(t *testing.T) {
+		conf := setup(t)
+
+		recordFailedDeploy(ctx, conf, 1, 1, "checkout", "8f14e45f", &deployFailure{stage: deployStage_Install, err: installErr})
+
+		events, _, err := conf.Repo.PorterAppEvent().ListEventsByPorterAppID(ctx, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(events) != 0 {
+			t.Errorf("expected no events for another app, got %d", len(events))
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+)
+
+// deployLockName is the name of the lock held while a stack is deployed to a cluster, so that overlapping deploys of
+// the same stack do not race on its helm release and database row
+func deployLockName(clusterID uint, appName string) string {
+	return fmt.Sprintf("porter-app-deploy:cluster:%d:%s", clusterID, appName)
+}
+
+// deployLockMargin is how much longer than the helm timeout of a deploy its lock lasts, for the work done around the
+// install or upgrade of the stack
+const deployLockMargin = 5 * time.Minute
+
+// acquireDeployLock takes the deploy lock of a stack, waiting for another deploy of it to finish for up to the
+// configured timeout. It returns adapter.ErrLockNotAcquired if the other deploy is still running.
+func acquireDeployLock(ctx context.Context, conf *config.Config, clusterID uint, appName string, helmTimeout time.Duration) (func(), error) {
+	return conf.Locker.Acquire(ctx, deployLockName(clusterID, appName), deployLockTTL(conf, helmTimeout), conf.ServerConf.DeployLockTimeout)
+}
+
+// deployLockTTL returns how long the deploy lock of a deploy lasts if it is not released, which is never shorter than
+// the deploy may wait on helm, so that another deploy cannot start while it is still running
+func deployLockTTL(conf *config.Config, helmTimeout time.Duration) time.Duration {
+	if ttl := helmTimeout + deployLockMargin; ttl > conf.ServerConf.DeployLockTTL {
+		return ttl
+	}
+
+	return conf.ServerConf.DeployLockTTL
+}
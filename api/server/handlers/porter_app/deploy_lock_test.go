@@ -0,0 +1,145 @@
+package porter_app_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/features"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"github.com/stefanmcshane/helm/pkg/storage"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+)
+
+// blockingDriver is a release storage driver whose queries block until unblock is closed, so that a deploy can be held
+// in the middle of reading the release of the stack
+type blockingDriver struct {
+	*driver.Memory
+
+	queries     int32
+	enteredOnce sync.Once
+	entered     chan struct{}
+	unblock     chan struct{}
+}
+
+func (d *blockingDriver) Query(labels map[string]string) ([]*release.Release, error) {
+	atomic.AddInt32(&d.queries, 1)
+	d.enteredOnce.Do(func() { close(d.entered) })
+
+	<-d.unblock
+
+	return d.Memory.Query(labels)
+}
+
+func TestCreatePorterAppRejectsConcurrentDeploys(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	config.ServerConf.DeployLockTimeout = 50 * time.Millisecond
+	config.ServerConf.DeployLockTTL = time.Minute
+	// the stack is deployed through helm, whose release reads are blocked, rather than porter apply v2
+	config.LaunchDarklyClient = &features.Client{Client: apitest.FeatureFlags{models.ValidateApplyV2: false}}
+
+	user := apitest.CreateTestUser(t, config, true)
+
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "project",
+	}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster, err := config.Repo.Cluster().CreateCluster(&models.Cluster{
+		ProjectID: proj.ID,
+		Name:      "cluster",
+	}, config.LaunchDarklyClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases := &blockingDriver{
+		Memory:  driver.NewMemory(),
+		entered: make(chan struct{}),
+		unblock: make(chan struct{}),
+	}
+	k8sAgent := kubernetes.GetAgentTesting()
+	helmAgent := helm.GetAgentTesting(&helm.Form{}, storage.Init(releases), config.Logger, k8sAgent)
+
+	handler := porter_app.NewCreatePorterAppHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	deploy := func() *httptest.ResponseRecorder {
+		req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1/clusters/1/applications/web", &types.CreatePorterAppRequest{
+			PorterYAMLBase64: base64.StdEncoding.EncodeToString([]byte("version: v1stack\n")),
+			ImageInfo: types.ImageInfo{
+				Repository: "nginx",
+				Tag:        "latest",
+			},
+		})
+
+		req = apitest.WithAuthenticatedUser(t, req, user)
+		req = apitest.WithProject(t, req, proj)
+		req = apitest.WithCluster(t, req, cluster)
+		req = apitest.WithURLParams(t, req, map[string]string{
+			string(types.URLParamPorterAppName): "web",
+		})
+
+		// the out of cluster agent getter returns the agents in the request context instead of connecting to the cluster
+		ctx := context.WithValue(req.Context(), authz.KubernetesAgentCtxKey, k8sAgent)
+		ctx = context.WithValue(ctx, authz.HelmAgentCtxKey, helmAgent)
+
+		handler.ServeHTTP(rr, req.WithContext(ctx))
+
+		return rr
+	}
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() {
+		first <- deploy()
+	}()
+
+	select {
+	case <-releases.entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the first deploy to read the release of the stack")
+	}
+
+	// the first deploy holds the lock until it returns, so the second one gives up once the lock timeout passes
+	second := deploy()
+	if second.Code != http.StatusConflict {
+		t.Errorf("expected the concurrent deploy to be rejected with status %d, got %d", http.StatusConflict, second.Code)
+	}
+	if !strings.Contains(second.Body.String(), "another deploy of web is in progress") {
+		t.Errorf("expected the concurrent deploy to be told another deploy is in progress, got %s", second.Body.String())
+	}
+	if queries := atomic.LoadInt32(&releases.queries); queries != 1 {
+		t.Errorf("expected only the first deploy to read the release of the stack, got %d reads", queries)
+	}
+
+	close(releases.unblock)
+
+	if rr := <-first; rr.Code == http.StatusConflict {
+		t.Errorf("expected the first deploy to proceed, got status %d", rr.Code)
+	}
+
+	// once the first deploy has returned, the stack can be deployed again
+	if rr := deploy(); rr.Code == http.StatusConflict {
+		t.Errorf("expected the lock to be released after the first deploy, got status %d", rr.Code)
+	}
+}
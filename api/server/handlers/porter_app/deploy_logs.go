@@ -0,0 +1,273 @@
+package porter_app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// deployLogsMaxDuration bounds how long the logs of a deploy are streamed, for deploys whose revision is never created,
+// such as deploys whose pre-deploy job fails
+const deployLogsMaxDuration = 30 * time.Minute
+
+// deployLogsWriteGrace is the time after deployLogsMaxDuration in which the done event can still be written
+const deployLogsWriteGrace = 5 * time.Second
+
+// deployLogsPollInterval is how often the pods and release of an app are read while the logs of its deploy are streamed
+var deployLogsPollInterval = 2 * time.Second
+
+// StreamDeployLogsHandler handles GET /applications/{porter_app_name}/deploy-logs, which streams the logs of the pods
+// of an app, including its pre-deploy job, as server-sent events while the app is deployed
+type StreamDeployLogsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewStreamDeployLogsHandler returns a new StreamDeployLogsHandler
+func NewStreamDeployLogsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *StreamDeployLogsHandler {
+	return &StreamDeployLogsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *StreamDeployLogsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-stream-deploy-logs")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-name", Value: appName})
+
+	request := &types.StreamDeployLogsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "revision", Value: request.Revision})
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Read); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing deploy logs")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// the controller reaches the connection through the writers of the middlewares which wrap the response
+	rc := http.NewResponseController(w)
+	if err := rc.Flush(); err != nil {
+		// the status is already written, so the client only sees the stream end
+		_ = telemetry.Error(ctx, span, err, "response does not support streaming")
+		return
+	}
+
+	// the server's write timeout would otherwise end the stream. The error is ignored for writers which do not
+	// support deadlines, such as the recorders used in tests.
+	_ = rc.SetWriteDeadline(time.Now().Add(deployLogsMaxDuration + deployLogsWriteGrace))
+
+	ctx, cancel := context.WithTimeout(ctx, deployLogsMaxDuration)
+	defer cancel()
+
+	// services of the app which run in other namespaces are deployed along with it, so their pods are followed too
+	namespaces := []string{namespace}
+	if latest, err := helmAgent.GetRelease(ctx, appName, 0, false); err == nil {
+		namespaces = append(namespaces, otherNamespaces(serviceNamespacesFromValues(latest.Config))...)
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "namespaces", Value: strings.Join(namespaces, ",")})
+
+	events := &deployLogsWriter{w: w, rc: rc}
+	done, err := followDeployLogs(ctx, k8sAgent.Clientset, namespaces, time.Now(), events, func(ctx context.Context) (*types.DeployLogsDone, bool) {
+		return revisionDone(ctx, helmAgent, appName, request.Revision)
+	})
+	if err != nil {
+		// the client has disconnected or the stream timed out, so there is no one to send the error to
+		_ = telemetry.Error(ctx, span, err, "error streaming deploy logs")
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "release-status", Value: done.Status})
+	if err := events.writeEvent(types.DeployLogsEvent_Done, done); err != nil {
+		_ = telemetry.Error(ctx, span, err, "error writing done event")
+	}
+}
+
+// revisionDone returns the status of the revision of the release of an app once the revision is deployed or has failed
+func revisionDone(ctx context.Context, helmAgent *helm.Agent, appName string, revision int) (*types.DeployLogsDone, bool) {
+	rel, err := helmAgent.GetRelease(ctx, appName, revision, false)
+	if err != nil || rel == nil || rel.Info == nil {
+		return nil, false
+	}
+
+	switch rel.Info.Status {
+	// a revision which is superseded was deployed before a later revision, or failed and was rolled back
+	case release.StatusDeployed, release.StatusFailed, release.StatusSuperseded:
+		return &types.DeployLogsDone{
+			Revision:    rel.Version,
+			Status:      rel.Info.Status.String(),
+			Description: rel.Info.Description,
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// followDeployLogs writes the logs of the containers of every pod created in the namespaces since the deploy started,
+// until revisionDone reports that the revision of the deploy is done, and returns its status. Containers are followed
+// once they have started, so the logs of pods which are still being scheduled are picked up on a later poll.
+func followDeployLogs(
+	ctx context.Context,
+	clientset k8s.Interface,
+	namespaces []string,
+	since time.Time,
+	events *deployLogsWriter,
+	revisionDone func(ctx context.Context) (*types.DeployLogsDone, bool),
+) (*types.DeployLogsDone, error) {
+	tailCtx, cancelTails := context.WithCancel(ctx)
+	var tails sync.WaitGroup
+	defer func() {
+		cancelTails()
+		tails.Wait()
+	}()
+
+	// pod creation times are only kept to the second
+	since = since.Truncate(time.Second)
+	followed := make(map[string]bool)
+
+	for {
+		for _, namespace := range namespaces {
+			pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil && ctx.Err() == nil {
+				return nil, fmt.Errorf("error listing pods in namespace %s: %w", namespace, err)
+			}
+			if pods == nil {
+				continue
+			}
+
+			for _, pod := range pods.Items {
+				if pod.CreationTimestamp.Time.Before(since) {
+					continue
+				}
+
+				for _, containerStatus := range pod.Status.ContainerStatuses {
+					key := fmt.Sprintf("%s/%s/%s", namespace, pod.Name, containerStatus.Name)
+					if followed[key] || (containerStatus.State.Running == nil && containerStatus.State.Terminated == nil) {
+						continue
+					}
+					followed[key] = true
+
+					tails.Add(1)
+					go func(namespace, pod, container string) {
+						defer tails.Done()
+						tailContainerLogs(tailCtx, clientset, namespace, pod, container, events)
+					}(namespace, pod.Name, containerStatus.Name)
+				}
+			}
+		}
+
+		if done, ok := revisionDone(ctx); ok {
+			return done, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(deployLogsPollInterval):
+		}
+	}
+}
+
+// tailContainerLogs writes the logs of a container as log events until the container exits or ctx is canceled
+func tailContainerLogs(ctx context.Context, clientset k8s.Interface, namespace, pod, container string, events *deployLogsWriter) {
+	stream, err := clientset.CoreV1().Pods(namespace).GetLogs(pod, &v1.PodLogOptions{Container: container, Follow: true}).Stream(ctx)
+	if err != nil {
+		return
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		if err := events.writeEvent(types.DeployLogsEvent_Log, types.DeployLogLine{Pod: pod, Container: container, Line: scanner.Text()}); err != nil {
+			return
+		}
+	}
+}
+
+// deployLogsWriter writes server-sent events, which are written by the tail of every container at once
+type deployLogsWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+// writeEvent writes v as the json data of a server-sent event, and flushes it to the client
+func (d *deployLogsWriter) writeEvent(event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := fmt.Fprintf(d.w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return fmt.Errorf("error writing event: %w", err)
+	}
+	if d.rc != nil {
+		if err := d.rc.Flush(); err != nil {
+			return fmt.Errorf("error flushing event: %w", err)
+		}
+	}
+
+	return nil
+}
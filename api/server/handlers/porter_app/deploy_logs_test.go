@@ -0,0 +1,87 @@
+package porter_app
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func deployLogsTestPod(namespace, name string, created time.Time, state v1.ContainerState) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(created)},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{Name: "web", State: state}},
+		},
+	}
+}
+
+func TestFollowDeployLogs(t *testing.T) {
+	ctx := context.Background()
+	namespace := "porter-stack-storefront"
+	since := time.Now()
+	running := v1.ContainerState{Running: &v1.ContainerStateRunning{}}
+
+	defer func(interval time.Duration) { deployLogsPollInterval = interval }(deployLogsPollInterval)
+	deployLogsPollInterval = 10 * time.Millisecond
+
+	clientset := fake.NewSimpleClientset(
+		deployLogsTestPod(namespace, "storefront-web-new", since.Add(time.Second), running),
+		deployLogsTestPod(namespace, "storefront-r-migrate", since.Add(time.Second), v1.ContainerState{Terminated: &v1.ContainerStateTerminated{}}),
+		deployLogsTestPod(namespace, "storefront-web-pending", since.Add(time.Second), v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}}),
+		deployLogsTestPod(namespace, "storefront-web-old", since.Add(-time.Hour), running),
+		deployLogsTestPod("jobs", "storefront-worker-new", since.Add(time.Second), running),
+	)
+
+	polls := 0
+	revisionDone := func(ctx context.Context) (*types.DeployLogsDone, bool) {
+		polls++
+		if polls < 3 {
+			return nil, false
+		}
+		return &types.DeployLogsDone{Revision: 4, Status: "deployed"}, true
+	}
+
+	var buf bytes.Buffer
+	done, err := followDeployLogs(ctx, clientset, []string{namespace, "jobs"}, since, &deployLogsWriter{w: &buf}, revisionDone)
+	if err != nil {
+		t.Fatalf("expected the logs to be followed until the revision is done, got %v", err)
+	}
+	if done.Revision != 4 || done.Status != "deployed" {
+		t.Errorf("expected the status of the revision to be returned, got %+v", done)
+	}
+
+	events := buf.String()
+	for _, pod := range []string{"storefront-web-new", "storefront-r-migrate", "storefront-worker-new"} {
+		if !strings.Contains(events, "event: log\ndata: {\"pod\":\""+pod+"\",\"container\":\"web\"") {
+			t.Errorf("expected the logs of %s to be streamed, got:\n%s", pod, events)
+		}
+	}
+	for _, pod := range []string{"storefront-web-pending", "storefront-web-old"} {
+		if strings.Contains(events, pod) {
+			t.Errorf("expected the logs of %s not to be streamed, got:\n%s", pod, events)
+		}
+	}
+}
+
+func TestFollowDeployLogsCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	defer func(interval time.Duration) { deployLogsPollInterval = interval }(deployLogsPollInterval)
+	deployLogsPollInterval = 10 * time.Millisecond
+
+	var buf bytes.Buffer
+	_, err := followDeployLogs(ctx, fake.NewSimpleClientset(), []string{"porter-stack-storefront"}, time.Now(), &deployLogsWriter{w: &buf}, func(ctx context.Context) (*types.DeployLogsDone, bool) {
+		return nil, false
+	})
+	if err == nil {
+		t.Error("expected an error once the stream is canceled before the revision is done")
+	}
+}
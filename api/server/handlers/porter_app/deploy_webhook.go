@@ -0,0 +1,382 @@
+package porter_app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/features"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"gorm.io/gorm"
+)
+
+// errDeployWebhookNotFound is returned for every deploy webhook token which does not deploy an app, so that the
+// webhook does not reveal whether an app exists
+var errDeployWebhookNotFound = errors.New("deploy webhook not found")
+
+// hashDeployWebhookToken returns the hash a deploy webhook token is stored as. Tokens are random, so they are looked up
+// by an unsalted hash rather than hashed like a password.
+func hashDeployWebhookToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateDeployWebhookTokenHandler handles POST /apps/{porter_app_name}/deploy-webhook, which generates the token of
+// the deploy webhook of an app, revoking its previous token
+type CreateDeployWebhookTokenHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewCreateDeployWebhookTokenHandler returns a new CreateDeployWebhookTokenHandler
+func NewCreateDeployWebhookTokenHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *CreateDeployWebhookTokenHandler {
+	return &CreateDeployWebhookTokenHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *CreateDeployWebhookTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-deploy-webhook-token")
+	defer span.End()
+
+	porterApp, reqErr := readDeployWebhookApp(c.Config(), r)
+	if reqErr != nil {
+		_ = telemetry.Error(ctx, span, reqErr, "error reading porter app")
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	token, err := encryption.GenerateRandomBytes(32)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error generating deploy webhook token")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	porterApp.DeployWebhookTokenHash = hashDeployWebhookToken(token)
+	if _, err := c.Repo().PorterApp().UpdatePorterApp(porterApp); err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.CreateDeployWebhookTokenResponse{
+		Token:      token,
+		WebhookURL: fmt.Sprintf("%s/api/webhooks/apps/deploy/%s", c.Config().ServerConf.ServerURL, token),
+	})
+}
+
+// RevokeDeployWebhookTokenHandler handles DELETE /apps/{porter_app_name}/deploy-webhook, which revokes the token of the
+// deploy webhook of an app
+type RevokeDeployWebhookTokenHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewRevokeDeployWebhookTokenHandler returns a new RevokeDeployWebhookTokenHandler
+func NewRevokeDeployWebhookTokenHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *RevokeDeployWebhookTokenHandler {
+	return &RevokeDeployWebhookTokenHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *RevokeDeployWebhookTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-revoke-deploy-webhook-token")
+	defer span.End()
+
+	porterApp, reqErr := readDeployWebhookApp(c.Config(), r)
+	if reqErr != nil {
+		_ = telemetry.Error(ctx, span, reqErr, "error reading porter app")
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	porterApp.DeployWebhookTokenHash = ""
+	porterApp, err := c.Repo().PorterApp().UpdatePorterApp(porterApp)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, porterApp.ToPorterAppType())
+}
+
+// readDeployWebhookApp returns the app named in the URL of a request for its deploy webhook token, if the user of the
+// request can deploy it
+func readDeployWebhookApp(conf *config.Config, r *http.Request) (*models.PorterApp, apierrors.RequestError) {
+	ctx := r.Context()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	if err := authorizeAppAction(ctx, conf, r, appName, types.PorterAppGrantPermission_Deploy); err != nil {
+		return nil, appAccessError(err)
+	}
+
+	porterApp, err := conf.Repo.PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apierrors.NewErrNotFound(fmt.Errorf("porter app %s not found", appName))
+		}
+		return nil, apierrors.NewErrInternal(fmt.Errorf("error reading porter app by name: %w", err))
+	}
+
+	return porterApp, nil
+}
+
+// DeployWebhookHandler handles POST /webhooks/apps/deploy/{token}, which deploys the app the token is for with a new
+// image. The latest release of the app is upgraded with its own values, with only the image of the app replaced.
+type DeployWebhookHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewDeployWebhookHandler returns a new DeployWebhookHandler
+func NewDeployWebhookHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DeployWebhookHandler {
+	return &DeployWebhookHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *DeployWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-deploy-webhook")
+	defer span.End()
+
+	token, _ := requestutils.GetURLParamString(r, types.URLParamToken)
+
+	porterApp, err := c.Repo().PorterApp().ReadPorterAppByDeployWebhookTokenHash(ctx, hashDeployWebhookToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, errDeployWebhookNotFound, "porter app not found by deploy webhook token")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by deploy webhook token")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+	appName := porterApp.Name
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-name", Value: appName},
+		telemetry.AttributeKV{Key: "project-id", Value: porterApp.ProjectID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: porterApp.ClusterID},
+	)
+
+	cluster, err := c.Repo().Cluster().ReadCluster(porterApp.ProjectID, porterApp.ClusterID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// the app outlived its cluster, which is reported like an invalid token
+			err = telemetry.Error(ctx, span, errDeployWebhookNotFound, "cluster of porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	request := &types.DeployWebhookRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "image-repo", Value: request.Repository},
+		telemetry.AttributeKV{Key: "image-tag", Value: request.Tag},
+	)
+
+	// deploying a paused app would scale its deployments back up without resuming its cron jobs
+	if porterApp.PausedAt != nil {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("app %s is paused, resume it before deploying it", appName))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	releaseDeployLock, err := acquireDeployLock(ctx, c.Config(), cluster.ID, appName, c.Config().ServerConf.HelmTimeout)
+	if err != nil {
+		if errors.Is(err, adapter.ErrLockNotAcquired) {
+			err = telemetry.Error(ctx, span, nil, fmt.Sprintf("another deploy of %s is in progress, retry once it has finished", appName))
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error acquiring deploy lock")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+	defer releaseDeployLock()
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rel, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting latest helm release")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	values, err := copyValues(rel.Config)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error copying release values")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	imageInfo, err := replaceAppImage(values, request.Repository, request.Tag)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error replacing image of app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	registries, err := c.Repo().Registry().ListRegistriesByProjectID(cluster.ProjectID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing registries")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	conf := &helm.InstallChartConfig{
+		Chart:      chartWithRepairedDependencyNames(rel.Chart),
+		Name:       appName,
+		Namespace:  namespace,
+		Values:     values,
+		Cluster:    cluster,
+		Repo:       c.Repo(),
+		Registries: registries,
+		Timeout:    c.Config().ServerConf.HelmTimeout,
+	}
+
+	helmStart := time.Now()
+	release, err := helmAgent.UpgradeInstallChart(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection)
+	helmDuration := time.Since(helmStart)
+	// status reads cached before the deploy are stale whether or not it succeeded
+	c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appName)
+	if err != nil {
+		upgradeErr := rollbackFailedUpgrade(ctx, c.Config(), helmAgent, porterApp.ProjectID, cluster.ID, appName, rel, imageInfo.Tag, err, true)
+		err = telemetry.Error(ctx, span, upgradeErr, "error upgrading application")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, upgradeErr.statusCode()))
+		return
+	}
+
+	// apps which deploy an external image record the repository they deploy
+	if porterApp.SourceTypeOrInferred() == types.PorterAppSourceType_Image && porterApp.ImageRepoURI != imageInfo.Repository {
+		porterApp.ImageRepoURI = imageInfo.Repository
+		if _, err := c.Repo().PorterApp().UpdatePorterApp(porterApp); err != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "update-image-repo-error", Value: err.Error()})
+		}
+	}
+
+	deployDetails := deployEventDetails{
+		ChartDigests: loader.ChartDigests(rel.Chart),
+		Message:      "Deployed by the deploy webhook",
+		HelmDuration: helmDuration,
+	}
+	if features.AreAgentDeployEventsEnabled(k8sAgent) {
+		serviceDeploymentStatusMap := getServiceDeploymentMetadataFromValues(values, types.PorterAppEventStatus_Progressing)
+		_, err = createNewPorterAppDeployEvent(ctx, serviceDeploymentStatusMap, porterApp.ID, release.Version, imageInfo.Tag, deployDetails, c.Repo().PorterAppEvent())
+	} else {
+		_, err = createOldPorterAppDeployEvent(ctx, types.PorterAppEventStatus_Success, porterApp.ID, release.Version, imageInfo.Tag, deployDetails, c.Repo().PorterAppEvent())
+	}
+	if err != nil {
+		// the app is deployed already, so failing to record it is not returned to the client
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "create-deploy-event-error", Value: err.Error()})
+	}
+
+	c.WriteResult(w, r, &types.DeployWebhookResponse{Revision: release.Version})
+}
+
+// chartWithRepairedDependencyNames returns a copy of the chart of a release whose dependencies are named after their
+// charts again. Installing a chart renames its aliased dependencies to their alias because of
+// https://github.com/helm/helm/issues/9214, and the dependencies are loaded from their repos by name.
+func chartWithRepairedDependencyNames(ch *chart.Chart) *chart.Chart {
+	if ch.Metadata == nil {
+		return ch
+	}
+
+	metadata := *ch.Metadata
+	metadata.Dependencies = make([]*chart.Dependency, 0, len(ch.Metadata.Dependencies))
+	for _, dep := range ch.Metadata.Dependencies {
+		repaired := *dep
+		if chartType := getChartTypeFromHelmName(dep.Name); chartType != "" {
+			repaired.Name = chartType
+		}
+		metadata.Dependencies = append(metadata.Dependencies, &repaired)
+	}
+
+	copied := *ch
+	copied.Metadata = &metadata
+
+	return &copied
+}
+
+// replaceAppImage replaces the image of an app in the values of its release, and returns the new image. Services which
+// run their own image keep it. The repository of the app is kept if repository is empty.
+func replaceAppImage(values map[string]interface{}, repository, tag string) (types.ImageInfo, error) {
+	current := attemptToGetImageInfoFromRelease(values)
+	if current.Repository == "" {
+		return types.ImageInfo{}, errors.New("the release of the app has no image to replace")
+	}
+
+	imageInfo := types.ImageInfo{
+		Repository: current.Repository,
+		Tag:        tag,
+	}
+	if repository != "" {
+		imageInfo.Repository = repository
+	}
+
+	globalImage, err := getNestedMap(values, "global", "image")
+	if err != nil {
+		return types.ImageInfo{}, fmt.Errorf("error reading image of app: %w", err)
+	}
+	globalImage["repository"] = imageInfo.Repository
+	globalImage["tag"] = imageInfo.Tag
+
+	return imageInfo, nil
+}
@@ -0,0 +1,104 @@
+package porter_app_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/devmode"
+)
+
+func TestDeployWebhook(t *testing.T) {
+	chartRepo, err := devmode.StartChartRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chartRepo.Close() // nolint:errcheck
+
+	env := apitest.NewHandlerTestEnv(t, "porter-stack-web")
+	env.Config.ServerConf.DefaultApplicationHelmRepoURL = chartRepo.URL
+
+	decoderValidator := shared.NewDefaultRequestDecoderValidator(env.Config.Logger, env.Config.Alerter)
+	writer := shared.NewDefaultResultWriter(env.Config.Logger, env.Config.Alerter)
+	urlParams := map[string]string{string(types.URLParamPorterAppName): "web"}
+
+	req, rr := env.NewRequest(t, string(types.HTTPVerbPost), "/api/projects/1/clusters/1/applications/web", &types.CreatePorterAppRequest{
+		PorterYAMLBase64: base64.StdEncoding.EncodeToString([]byte(createTestPorterYAML)),
+		ImageInfo: types.ImageInfo{
+			Repository: "nginx",
+			Tag:        "1.25",
+		},
+	}, urlParams)
+	porter_app.NewCreatePorterAppHandler(env.Config, decoderValidator, writer).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected the app to be created, got status %d: %s", rr.Code, rr.Body.String())
+	}
+
+	createToken := func() string {
+		req, rr := env.NewRequest(t, string(types.HTTPVerbPost), "/api/projects/1/clusters/1/apps/web/deploy-webhook", nil, urlParams)
+		porter_app.NewCreateDeployWebhookTokenHandler(env.Config, writer).ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected a deploy webhook token to be generated, got status %d", rr.Code)
+		}
+
+		res := &types.CreateDeployWebhookTokenResponse{}
+		if err := json.Unmarshal(rr.Body.Bytes(), res); err != nil {
+			t.Fatal(err)
+		}
+
+		return res.Token
+	}
+
+	deploy := func(token, tag string) int {
+		req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/webhooks/apps/deploy/"+token, &types.DeployWebhookRequest{Tag: tag})
+		req = apitest.WithURLParams(t, req, map[string]string{string(types.URLParamToken): token})
+		req = apitest.WithAgents(t, req, env.Agents)
+
+		porter_app.NewDeployWebhookHandler(env.Config, decoderValidator, writer).ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	token := createToken()
+
+	if code := deploy(token, "1.26"); code != http.StatusOK {
+		t.Fatalf("expected the webhook to deploy the app, got status %d", code)
+	}
+
+	release, err := env.Agents.Helm.GetRelease(context.Background(), "web", 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.Version != 2 {
+		t.Errorf("expected the webhook to upgrade the release to revision 2, got %d", release.Version)
+	}
+	image, _ := release.Config["global"].(map[string]interface{})["image"].(map[string]interface{})
+	if image["repository"] != "nginx" || image["tag"] != "1.26" {
+		t.Errorf("expected the webhook to deploy nginx:1.26, got %v", image)
+	}
+
+	if code := deploy("not-a-token", "1.27"); code != http.StatusNotFound {
+		t.Errorf("expected an invalid token to return status %d, got %d", http.StatusNotFound, code)
+	}
+
+	// regenerating the token revokes the previous one
+	regenerated := createToken()
+	if code := deploy(token, "1.27"); code != http.StatusNotFound {
+		t.Errorf("expected a regenerated token to revoke the previous one, got status %d", code)
+	}
+
+	req, rr = env.NewRequest(t, string(types.HTTPVerbDelete), "/api/projects/1/clusters/1/apps/web/deploy-webhook", nil, urlParams)
+	porter_app.NewRevokeDeployWebhookTokenHandler(env.Config, writer).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected the deploy webhook token to be revoked, got status %d", rr.Code)
+	}
+	if code := deploy(regenerated, "1.27"); code != http.StatusNotFound {
+		t.Errorf("expected a revoked token to return status %d, got %d", http.StatusNotFound, code)
+	}
+}
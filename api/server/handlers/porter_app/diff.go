@@ -0,0 +1,354 @@
+package porter_app
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/integrations/secretstores"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// DiffPorterAppHandler handles POST /applications/{porter_app_name}/diff, which returns what deploying a porter.yaml
+// would change in the current release of an app. The values are built in the same way as an update by
+// CreatePorterAppHandler, without installing anything.
+type DiffPorterAppHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewDiffPorterAppHandler returns a new DiffPorterAppHandler
+func NewDiffPorterAppHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DiffPorterAppHandler {
+	return &DiffPorterAppHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *DiffPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-diff-porter-app")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "application-name", Value: appName})
+
+	request := &types.DiffPorterAppRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "render", Value: request.Render})
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Read); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	porterYaml, err := base64.StdEncoding.DecodeString(request.PorterYAMLBase64)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding porter yaml")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	for service, serviceImage := range request.ServiceImageInfo {
+		if !serviceImage.Complete() {
+			err = telemetry.Error(ctx, span, nil, fmt.Sprintf("incomplete image info provided for service %s: must provide both repository and tag", service))
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	helmRelease, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "app has no release to diff against")
+		c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+		return
+	}
+
+	// the image is resolved in the same way as an update: from the request, or else from the release
+	imageInfo := request.ImageInfo
+	if !imageInfo.Complete() {
+		imageInfo = attemptToGetImageInfoFromRelease(helmRelease.Config)
+	}
+	if !imageInfo.Complete() && len(request.ServiceImageInfo) > 0 {
+		imageInfo = request.ServiceImageInfo[defaultImageService(request.ServiceImageInfo)]
+	}
+	if !imageInfo.Complete() {
+		err = telemetry.Error(ctx, span, nil, "incomplete image info provided: must provide both repository and tag")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "image-repo", Value: imageInfo.Repository}, telemetry.AttributeKV{Key: "image-tag", Value: imageInfo.Tag})
+
+	// parse merges the new values into those of the release, so the values they are diffed against are copied first
+	previousValues, err := copyValues(helmRelease.Config)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error copying release values")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	var existingApp *models.PorterApp
+	var builder string
+	if app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName); err == nil {
+		existingApp = app
+		builder = app.Builder
+	}
+
+	appChart, values, _, warnings, err := parse(
+		ctx,
+		ParseConf{
+			PorterAppName:                appName,
+			PorterYaml:                   porterYaml,
+			ImageInfo:                    imageInfo,
+			ServiceImageInfo:             request.ServiceImageInfo,
+			ServerConfig:                 c.Config(),
+			ProjectID:                    cluster.ProjectID,
+			Namespace:                    namespace,
+			ExistingHelmValues:           helmRelease.Config,
+			ExistingChartDependencies:    helmRelease.Chart.Metadata.Dependencies,
+			InjectLauncherToStartCommand: strings.Contains(builder, "heroku") || strings.Contains(builder, "paketo"),
+			AddCustomNodeSelector:        (cluster.ProvisionedBy == "CAPI" && cluster.CloudProvider == "GCP") || cluster.GCPIntegrationID != 0,
+			RemoveDeletedServices:        request.OverrideRelease,
+			SchedulingDefaults:           types.ClusterSchedulingDefaults(cluster.SchedulingDefaults),
+			Capabilities:                 cluster.Capabilities.ToClusterCapabilitiesType(),
+			NetworkPolicies:              cluster.NetworkPoliciesEnabled,
+			IngressNamespace:             c.Config().ServerConf.NetworkPolicyIngressNamespace,
+			Observability:                project.ObservabilityConfig.ToProjectObservabilityConfigType(),
+			SecretResolver:               secretstores.NewResolver(secretstores.RepoStoreLookup(c.Repo(), cluster.ProjectID)),
+			DryRun:                       true,
+			ValuesOnly:                   !request.Render,
+		},
+	)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "parse error")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	// the env variables and manual scales of the release are kept by updates in the same way
+	if !request.OverrideRelease {
+		preserveReleaseEnv(values, releaseEnv(previousValues))
+	}
+	if existingApp != nil {
+		warnings = append(warnings, revertedManualScales(existingApp.ManualScales, values)...)
+	}
+
+	valuesDiff, err := diffValues(previousValues, values)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error diffing release values")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "values-diff-changes", Value: len(valuesDiff)})
+
+	res := &types.DiffPorterAppResponse{
+		ValuesDiff: valuesDiff,
+		Warnings:   warnings,
+	}
+
+	if request.Render {
+		manifests, err := helmAgent.TemplateChart(ctx, &helm.InstallChartConfig{
+			Chart:     appChart,
+			Name:      appName,
+			Namespace: namespace,
+			Values:    values,
+		})
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error rendering app chart")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		res.ManifestDiff = diffManifests(redactManifests(helmRelease.Manifest), redactManifests(manifests))
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// manifestDiffContext is the number of unchanged lines shown around each change of a manifest diff
+const manifestDiffContext = 3
+
+// diffManifests returns a line diff of two sets of manifests, with added lines prefixed by "+", removed lines by "-"
+// and the unchanged lines around them by a space. Unchanged lines further from a change are left out, and each gap is
+// marked by "...". It is empty if the manifests are the same.
+func diffManifests(oldManifests, newManifests string) string {
+	dmp := diffmatchpatch.New()
+	oldChars, newChars, lines := dmp.DiffLinesToChars(oldManifests, newManifests)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(oldChars, newChars, false), lines)
+
+	type diffLine struct {
+		prefix string
+		text   string
+	}
+
+	var diffLines []diffLine
+	changed := false
+	for _, diff := range diffs {
+		prefix := " "
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			prefix, changed = "+", true
+		case diffmatchpatch.DiffDelete:
+			prefix, changed = "-", true
+		}
+
+		for _, line := range strings.Split(strings.TrimSuffix(diff.Text, "\n"), "\n") {
+			diffLines = append(diffLines, diffLine{prefix: prefix, text: line})
+		}
+	}
+
+	if !changed {
+		return ""
+	}
+
+	// unchanged lines are shown if they are within the context of a change
+	shown := make([]bool, len(diffLines))
+	for i, line := range diffLines {
+		if line.prefix == " " {
+			continue
+		}
+		for j := i - manifestDiffContext; j <= i+manifestDiffContext; j++ {
+			if j >= 0 && j < len(diffLines) {
+				shown[j] = true
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for i, line := range diffLines {
+		if !shown[i] {
+			if i == 0 || shown[i-1] {
+				sb.WriteString("...\n")
+			}
+			continue
+		}
+		sb.WriteString(line.prefix)
+		sb.WriteString(line.text)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// redactManifests redacts the data of secrets, values whose key looks like a credential and the values of env
+// variables whose name looks like one, so that a manifest diff does not show them
+func redactManifests(manifests string) string {
+	var redacted []string
+	for _, document := range splitManifests(manifests) {
+		redacted = append(redacted, redactManifest(document))
+	}
+
+	return strings.Join(redacted, "\n")
+}
+
+// splitManifests splits rendered manifests into their documents, each of which starts with its separator
+func splitManifests(manifests string) []string {
+	var documents []string
+	var current []string
+	for _, line := range strings.Split(manifests, "\n") {
+		if strings.HasPrefix(line, "---") && len(current) != 0 {
+			documents = append(documents, strings.Join(current, "\n"))
+			current = nil
+		}
+		current = append(current, line)
+	}
+
+	return append(documents, strings.Join(current, "\n"))
+}
+
+func redactManifest(document string) string {
+	lines := strings.Split(document, "\n")
+
+	isSecret := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "kind: Secret" && !strings.HasPrefix(line, " ") {
+			isSecret = true
+			break
+		}
+	}
+
+	inSecretData := false
+	credentialEnv := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indented := strings.HasPrefix(line, " ")
+
+		if !indented && trimmed != "" {
+			inSecretData = isSecret && (trimmed == "data:" || trimmed == "stringData:")
+		}
+
+		key, value, ok := manifestKeyValue(trimmed)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case inSecretData && indented:
+			lines[i] = redactManifestLine(line, value)
+		case isCredentialKey(key):
+			lines[i] = redactManifestLine(line, value)
+		case key == "value" && credentialEnv:
+			lines[i] = redactManifestLine(line, value)
+		}
+
+		// env variables are listed as a name followed by its value
+		if key == "- name" {
+			credentialEnv = isCredentialKey(value)
+		} else if key != "value" {
+			credentialEnv = false
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// manifestKeyValue splits a line of a manifest which sets a scalar value into its key and value
+func manifestKeyValue(trimmed string) (string, string, bool) {
+	key, value, ok := strings.Cut(trimmed, ": ")
+	if !ok || strings.TrimSpace(value) == "" || strings.HasPrefix(trimmed, "#") {
+		return "", "", false
+	}
+
+	return key, strings.TrimSpace(value), true
+}
+
+func redactManifestLine(line, value string) string {
+	return strings.TrimSuffix(line, value) + redactedValue
+}
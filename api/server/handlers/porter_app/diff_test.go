@@ -0,0 +1,74 @@
+package porter_app
+
+import (
+	"strings"
+	"testing"
+)
+
+const diffTestManifests = `---
+# Source: web/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: storefront-web
+data:
+  DATABASE_URL: cG9zdGdyZXM6Ly8=
+type: Opaque
+---
+# Source: web/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: storefront-web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: web
+          image: registry.example.com/storefront:v1
+          env:
+            - name: PORT
+              value: "8080"
+            - name: STRIPE_API_TOKEN
+              value: sk_live_1234
+          args:
+            - --api-key: inline`
+
+func TestRedactManifests(t *testing.T) {
+	redacted := redactManifests(diffTestManifests)
+
+	for _, secret := range []string{"cG9zdGdyZXM6Ly8=", "sk_live_1234"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("expected %s to be redacted, got:\n%s", secret, redacted)
+		}
+	}
+	for _, kept := range []string{`value: "8080"`, "name: storefront-web", "type: Opaque", "image: registry.example.com/storefront:v1"} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("expected %s to be kept, got:\n%s", kept, redacted)
+		}
+	}
+	if !strings.Contains(redacted, "  DATABASE_URL: [redacted]") {
+		t.Errorf("expected the keys of secrets to be kept, got:\n%s", redacted)
+	}
+}
+
+func TestDiffManifests(t *testing.T) {
+	updated := strings.Replace(diffTestManifests, "replicas: 2", "replicas: 3", 1)
+	updated = strings.Replace(updated, "storefront:v1", "storefront:v2", 1)
+
+	diff := diffManifests(diffTestManifests, updated)
+
+	for _, want := range []string{"-  replicas: 2\n", "+  replicas: 3\n", "-          image: registry.example.com/storefront:v1\n", "+          image: registry.example.com/storefront:v2\n", " spec:\n"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected the diff to contain %q, got:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "kind: Secret") || !strings.HasPrefix(diff, "...\n") {
+		t.Errorf("expected unchanged lines away from the changes to be left out, got:\n%s", diff)
+	}
+
+	if diff := diffManifests(diffTestManifests, diffTestManifests); diff != "" {
+		t.Errorf("expected no diff between the same manifests, got:\n%s", diff)
+	}
+}
@@ -0,0 +1,232 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/certexpiry"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// ListPorterAppDomainsHandler handles GET /apps/{porter_app_name}/domains, which returns the certificate status of
+// each domain of an app as of its last check
+type ListPorterAppDomainsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListPorterAppDomainsHandler returns a new ListPorterAppDomainsHandler
+func NewListPorterAppDomainsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListPorterAppDomainsHandler {
+	return &ListPorterAppDomainsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListPorterAppDomainsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-porter-app-domains")
+	defer span.End()
+
+	porterApp, reqErr := readDomainsApp(c.Config(), r, types.PorterAppGrantPermission_Read)
+	if reqErr != nil {
+		_ = telemetry.Error(ctx, span, reqErr, "error reading porter app")
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	domains, err := c.Repo().PorterAppDomain().ListPorterAppDomains(ctx, porterApp.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing porter app domains")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, porterAppDomainsResponse(porterApp, domains))
+}
+
+// RecheckPorterAppDomainsHandler handles POST /apps/{porter_app_name}/domains/recheck, which reads the certificates
+// serving the domains of an app without waiting for the next scheduled check
+type RecheckPorterAppDomainsHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewRecheckPorterAppDomainsHandler returns a new RecheckPorterAppDomainsHandler
+func NewRecheckPorterAppDomainsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *RecheckPorterAppDomainsHandler {
+	return &RecheckPorterAppDomainsHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *RecheckPorterAppDomainsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-recheck-porter-app-domains")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	porterApp, reqErr := readDomainsApp(c.Config(), r, types.PorterAppGrantPermission_Deploy)
+	if reqErr != nil {
+		_ = telemetry.Error(ctx, span, reqErr, "error reading porter app")
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting kubernetes agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	checker := certexpiry.NewChecker(
+		c.Repo().PorterApp(),
+		c.Repo().PorterAppDomain(),
+		c.Repo().PorterAppEvent(),
+		nil,
+		certexpiry.NewNotifierFromConfig(c.Config()),
+		certexpiry.Options{Thresholds: c.Config().ServerConf.CertExpiryAlertDays, Logger: c.Config().Logger},
+	)
+
+	domains, err := checker.CheckApp(ctx, certexpiry.NewClusterDomains(agent), porterApp, time.Now().UTC())
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error checking porter app domains")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "domain-count", Value: len(domains)})
+
+	c.WriteResult(w, r, porterAppDomainsResponse(porterApp, domains))
+}
+
+// UpdatePorterAppCertAlertsHandler handles POST /apps/{porter_app_name}/domains/alerts, which changes where alerts
+// about the certificates of the domains of an app are sent
+type UpdatePorterAppCertAlertsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdatePorterAppCertAlertsHandler returns a new UpdatePorterAppCertAlertsHandler
+func NewUpdatePorterAppCertAlertsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdatePorterAppCertAlertsHandler {
+	return &UpdatePorterAppCertAlertsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdatePorterAppCertAlertsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-porter-app-cert-alerts")
+	defer span.End()
+
+	porterApp, reqErr := readDomainsApp(c.Config(), r, types.PorterAppGrantPermission_Deploy)
+	if reqErr != nil {
+		_ = telemetry.Error(ctx, span, reqErr, "error reading porter app")
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	request := &types.UpdateCertAlertsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "channel", Value: string(request.Channel)})
+
+	if err := certexpiry.ValidateAlertChannel(request.Channel, request.WebhookURL); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid alert channel")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	porterApp.CertAlertChannel = string(request.Channel)
+	porterApp.CertAlertWebhookURL = request.WebhookURL
+
+	porterApp, err := c.Repo().PorterApp().UpdatePorterApp(porterApp)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	domains, err := c.Repo().PorterAppDomain().ListPorterAppDomains(ctx, porterApp.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing porter app domains")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, porterAppDomainsResponse(porterApp, domains))
+}
+
+// readDomainsApp returns the app named in the URL of a request for its domains, if the user of the request has
+// permission on it
+func readDomainsApp(conf *config.Config, r *http.Request, permission types.PorterAppGrantPermission) (*models.PorterApp, apierrors.RequestError) {
+	ctx := r.Context()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	if err := authorizeAppAction(ctx, conf, r, appName, permission); err != nil {
+		return nil, appAccessError(err)
+	}
+
+	porterApp, err := conf.Repo.PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apierrors.NewErrNotFound(fmt.Errorf("porter app %s not found", appName))
+		}
+		return nil, apierrors.NewErrInternal(fmt.Errorf("error reading porter app by name: %w", err))
+	}
+
+	return porterApp, nil
+}
+
+// porterAppDomainsResponse returns the certificate status of the domains of an app, and where alerts about them are sent
+func porterAppDomainsResponse(porterApp *models.PorterApp, domains []*models.PorterAppDomain) *types.PorterAppDomainsResponse {
+	return &types.PorterAppDomainsResponse{
+		AlertChannel: certexpiry.AlertChannel(porterApp.CertAlertChannel),
+		WebhookURL:   porterApp.CertAlertWebhookURL,
+		Domains:      domainCertStatuses(domains),
+	}
+}
+
+// domainCertStatuses converts domains to their API type
+func domainCertStatuses(domains []*models.PorterAppDomain) []types.DomainCertStatus {
+	res := make([]types.DomainCertStatus, 0, len(domains))
+	for _, domain := range domains {
+		res = append(res, domain.ToDomainCertStatusType())
+	}
+	return res
+}
+
+// listDomainCertStatuses returns the certificate status of the domains of an app as of their last check
+func listDomainCertStatuses(ctx context.Context, repo repository.Repository, porterAppID uint) ([]types.DomainCertStatus, error) {
+	domains, err := repo.PorterAppDomain().ListPorterAppDomains(ctx, porterAppID)
+	if err != nil {
+		return nil, err
+	}
+	return domainCertStatuses(domains), nil
+}
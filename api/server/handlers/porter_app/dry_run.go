@@ -0,0 +1,63 @@
+package porter_app
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/chart"
+)
+
+type renderDryRunInput struct {
+	HelmAgent *helm.Agent
+	AppName   string
+	// Chart and Values are the app chart and values built by parse
+	Chart  *chart.Chart
+	Values map[string]interface{}
+	// PreDeployJobValues are the values of the release job chart, or nil if the app has no release job
+	PreDeployJobValues map[string]interface{}
+	// HelmRepoURL is the repository the release job chart is loaded from
+	HelmRepoURL string
+}
+
+// renderDryRun renders the charts of an app, and its release job if it has one, without installing them
+func renderDryRun(ctx context.Context, input renderDryRunInput) (types.CreatePorterAppDryRunResponse, error) {
+	ctx, span := telemetry.NewSpan(ctx, "render-dry-run")
+	defer span.End()
+
+	var res types.CreatePorterAppDryRunResponse
+
+	manifests, err := input.HelmAgent.TemplateChart(ctx, &helm.InstallChartConfig{
+		Chart:     input.Chart,
+		Name:      input.AppName,
+		Namespace: utils.NamespaceFromPorterAppName(input.AppName),
+		Values:    input.Values,
+	})
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error rendering app chart")
+	}
+
+	res.Manifests = manifests
+	res.Values = input.Values
+
+	if input.PreDeployJobValues == nil {
+		return res, nil
+	}
+
+	conf, err := createPreDeployJobChart(ctx, input.AppName, input.PreDeployJobValues, input.HelmRepoURL, nil, nil, nil)
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error loading pre-deploy job chart")
+	}
+
+	preDeployJobManifests, err := input.HelmAgent.TemplateChart(ctx, conf)
+	if err != nil {
+		return res, telemetry.Error(ctx, span, err, "error rendering pre-deploy job chart")
+	}
+
+	res.PreDeployJobManifests = preDeployJobManifests
+	res.PreDeployJobValues = input.PreDeployJobValues
+
+	return res, nil
+}
@@ -0,0 +1,44 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/envgroup"
+	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// syncEnvGroups copies the env groups from the porter-env-group namespace into the namespace of the app, unless they
+// have been synced into it already. Env groups which are updated afterwards are synced again by the stacks env group
+// handler, for the apps they are linked to.
+func syncEnvGroups(ctx context.Context, agent *kubernetes.Agent, envGroups []string, namespace string) error {
+	for _, envGroupName := range envGroups {
+		_, _, err := agent.GetLatestVersionedConfigMap(ctx, envGroupName, namespace)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, kubernetes.IsNotFoundError) {
+			return fmt.Errorf("error reading env group %s in namespace %s: %w", envGroupName, namespace, err)
+		}
+
+		if _, err := envgroup.CopyEnvGroup(ctx, agent, envGroupName, environment_groups.Namespace_EnvironmentGroups, namespace); err != nil {
+			return fmt.Errorf("error cloning env group %s from namespace %s: %w", envGroupName, environment_groups.Namespace_EnvironmentGroups, err)
+		}
+	}
+
+	return nil
+}
+
+// removedEnvGroups returns the env groups which were linked to the app and are not in the env groups it is deployed with
+func removedEnvGroups(linked models.PorterAppEnvGroups, envGroups []string) []string {
+	var removed []string
+	for _, name := range linked {
+		if !models.PorterAppEnvGroups(envGroups).Contains(name) {
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
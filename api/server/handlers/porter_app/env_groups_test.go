@@ -0,0 +1,69 @@
+package porter_app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestRemovedEnvGroups(t *testing.T) {
+	tests := []struct {
+		name      string
+		linked    models.PorterAppEnvGroups
+		envGroups []string
+		want      []string
+	}{
+		{
+			name:      "no linked env groups",
+			envGroups: []string{"shared"},
+		},
+		{
+			name:      "env groups kept and added",
+			linked:    models.PorterAppEnvGroups{"shared"},
+			envGroups: []string{"shared", "datadog"},
+		},
+		{
+			name:      "env groups removed",
+			linked:    models.PorterAppEnvGroups{"shared", "datadog", "stripe"},
+			envGroups: []string{"datadog"},
+			want:      []string{"shared", "stripe"},
+		},
+		{
+			name:      "every env group removed",
+			linked:    models.PorterAppEnvGroups{"shared"},
+			envGroups: []string{},
+			want:      []string{"shared"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := removedEnvGroups(tt.linked, tt.envGroups); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetDefaultValuesDetachesRemovedEnvGroups(t *testing.T) {
+	existingValues := map[string]interface{}{
+		"web-web": map[string]interface{}{
+			"container": map[string]interface{}{
+				"env": map[string]interface{}{
+					"synced": []interface{}{
+						map[string]interface{}{"name": "shared", "version": 2, "keys": []interface{}{}},
+					},
+				},
+			},
+		},
+	}
+
+	values := getDefaultValues(&Service{}, map[string]string{}, nil, "web", existingValues, "web", true, false, types.ClusterSchedulingDefaults{})
+
+	synced := values["container"].(map[string]interface{})["env"].(map[string]interface{})["synced"].([]map[string]interface{})
+	if len(synced) != 0 {
+		t.Errorf("expected the removed env group to be detached, got %v", synced)
+	}
+}
@@ -0,0 +1,485 @@
+package porter_app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/kubernetes/porter_app"
+	"github.com/porter-dev/porter/internal/models"
+	internalPorterApp "github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+	"gopkg.in/yaml.v2"
+)
+
+// launcherPrefix is prepended to the start commands of services built with buildpacks
+const launcherPrefix = "/cnb/lifecycle/launcher "
+
+// ExportPorterAppHandler returns the configuration a porter app is currently deployed with, so that it can be
+// recovered when the porter.yaml in the app's repo has drifted from what was last deployed
+type ExportPorterAppHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewExportPorterAppHandler returns a new ExportPorterAppHandler
+func NewExportPorterAppHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ExportPorterAppHandler {
+	return &ExportPorterAppHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP reads the latest helm release of the app and its release job, strips the values Porter injects on every
+// deploy and synthesizes a porter.yaml from what is left
+func (c *ExportPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-export-porter-app")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error getting stack name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "application-name", Value: appName})
+
+	if project.GetFeatureFlag(models.ValidateApplyV2, c.Config().LaunchDarklyClient) {
+		err := telemetry.Error(ctx, span, nil, "export is not supported for projects using porter apply v2")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	helmRelease, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			err = telemetry.Error(ctx, span, err, "app has no helm release")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error getting helm release")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "revision", Value: helmRelease.Version})
+
+	var preDeployJobValues map[string]interface{}
+	preDeployRelease, err := helmAgent.GetRelease(ctx, utils.PredeployJobNameFromPorterAppName(appName), 0, false)
+	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		err = telemetry.Error(ctx, span, err, "error getting pre-deploy job helm release")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if err == nil && preDeployRelease != nil {
+		preDeployJobValues = preDeployRelease.Config
+	}
+
+	// the scaling schedules are kept on the app rather than in the helm values
+	var scalingSchedules models.PorterAppScalingSchedules
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err == nil && app != nil {
+		scalingSchedules = app.ScalingSchedules
+	}
+
+	conf := injectedValuesConf{
+		appName:            appName,
+		namespace:          namespace,
+		imageTag:           attemptToGetImageInfoFromRelease(helmRelease.Config).Tag,
+		observability:      project.ObservabilityConfig.ToProjectObservabilityConfigType(),
+		schedulingDefaults: types.ClusterSchedulingDefaults(cluster.SchedulingDefaults),
+	}
+
+	values, warnings, err := exportedValues(helmRelease.Config, conf)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error exporting helm values")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if preDeployJobValues != nil {
+		var preDeployWarnings []string
+		preDeployJobValues, preDeployWarnings, err = exportedPreDeployJobValues(preDeployJobValues, conf)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error exporting pre-deploy job helm values")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		warnings = append(warnings, preDeployWarnings...)
+	}
+
+	porterYAML, yamlWarnings, err := porterYAMLFromValues(values, preDeployJobValues, scalingSchedules)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error building porter.yaml from helm values")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, types.ExportPorterAppResponse{
+		Revision:           helmRelease.Version,
+		Values:             values,
+		PreDeployJobValues: preDeployJobValues,
+		PorterYAML:         string(porterYAML),
+		Warnings:           append(warnings, yamlWarnings...),
+	})
+}
+
+// injectedValuesConf is what Porter injected into the values of an app when it was deployed
+type injectedValuesConf struct {
+	appName            string
+	namespace          string
+	imageTag           string
+	observability      *types.ProjectObservabilityConfig
+	schedulingDefaults types.ClusterSchedulingDefaults
+}
+
+// exportedValues returns a copy of the values of an app chart without the values Porter injects on every deploy
+func exportedValues(values map[string]interface{}, conf injectedValuesConf) (map[string]interface{}, []string, error) {
+	exported, err := copyValues(values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+
+	for key, value := range exported {
+		serviceValues, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if key == "global" {
+			delete(serviceValues, internalPorterApp.SchedulingDefaultsHashKey)
+			continue
+		}
+
+		serviceName, _ := getServiceNameAndTypeFromHelmName(key)
+		if serviceName == "" {
+			continue
+		}
+		warnings = append(warnings, stripInjectedValues(serviceValues, key, serviceName, porter_app.LabelKey_PorterApplication, conf)...)
+	}
+	sort.Strings(warnings)
+
+	return exported, warnings, nil
+}
+
+// exportedPreDeployJobValues returns a copy of the values of a release job chart without the values Porter injects on
+// every deploy. The image is injected from the image of the app.
+func exportedPreDeployJobValues(values map[string]interface{}, conf injectedValuesConf) (map[string]interface{}, []string, error) {
+	exported, err := copyValues(values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	delete(exported, "image")
+	warnings := stripInjectedValues(exported, utils.PredeployJobNameFromPorterAppName(conf.appName), "pre-deploy", porter_app.LabelKey_PorterApplicationPreDeploy, conf)
+
+	return exported, warnings, nil
+}
+
+// stripInjectedValues removes the values of a single service which are injected on every deploy rather than set by
+// the user. Injected values which the user overrode are kept. A warning is returned if the service had env variables
+// set with valueFrom, which cannot be recovered from the values.
+func stripInjectedValues(serviceValues map[string]interface{}, helmName string, serviceName string, appLabelKey string, conf injectedValuesConf) []string {
+	var warnings []string
+
+	delete(serviceValues, "imagePullSecrets")
+	// network policies are generated on every deploy of a cluster which has them enabled
+	delete(serviceValues, networkPolicyKey)
+
+	// env variables set with valueFrom are read from the secret stores on every deploy
+	if removeSecretRef(serviceValues, externalSecretsName(helmName)) {
+		warnings = append(warnings, fmt.Sprintf("env variables of service %s set with valueFrom are not part of its values, so they must be added back to porter.yaml", serviceName))
+	}
+	if refs, ok := serviceValues["secretRefs"].([]interface{}); ok && len(refs) == 0 {
+		delete(serviceValues, "secretRefs")
+	}
+
+	workload := internalPorterApp.ObservabilityWorkload{
+		AppName:     conf.appName,
+		ServiceName: serviceName,
+		Namespace:   conf.namespace,
+		Version:     conf.imageTag,
+	}
+
+	injectedLabels := internalPorterApp.ObservabilityLabels(workload)
+	for _, field := range []string{"labels", "podLabels"} {
+		labels, ok := serviceValues[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		delete(labels, appLabelKey)
+		delete(labels, labelKey_ExternalSecretsVersion)
+		deleteMatching(labels, injectedLabels)
+		if len(labels) == 0 {
+			delete(serviceValues, field)
+		}
+	}
+
+	if container, ok := serviceValues["container"].(map[string]interface{}); ok {
+		if command, ok := container["command"].(string); ok {
+			container["command"] = strings.TrimPrefix(command, launcherPrefix)
+		}
+
+		if env, ok := container["env"].(map[string]interface{}); ok {
+			if normal, ok := env["normal"].(map[string]interface{}); ok {
+				deleteMatching(normal, internalPorterApp.ObservabilityEnv(workload, conf.observability))
+			}
+		}
+	}
+
+	nodeSelector, tolerations := internalPorterApp.SchedulingDefaultsHelmValues(conf.schedulingDefaults)
+	if selector, ok := serviceValues["nodeSelector"].(map[string]interface{}); ok {
+		delete(selector, "porter.run/workload-kind")
+		for key, value := range nodeSelector {
+			if fmt.Sprint(selector[key]) == fmt.Sprint(value) {
+				delete(selector, key)
+			}
+		}
+		if len(selector) == 0 {
+			delete(serviceValues, "nodeSelector")
+		}
+	}
+	if len(tolerations) > 0 && fmt.Sprint(serviceValues["tolerations"]) == fmt.Sprint(tolerations) {
+		delete(serviceValues, "tolerations")
+	}
+
+	return warnings
+}
+
+// deleteMatching deletes the keys of values which are set to the injected value
+func deleteMatching(values map[string]interface{}, injected map[string]string) {
+	for key, value := range injected {
+		if existing, ok := values[key]; ok && fmt.Sprint(existing) == value {
+			delete(values, key)
+		}
+	}
+}
+
+// copyValues deep copies helm values, so that the values of a release are not changed while exporting them
+func copyValues(values map[string]interface{}) (map[string]interface{}, error) {
+	by, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding values: %w", err)
+	}
+
+	copied := map[string]interface{}{}
+	if err := json.Unmarshal(by, &copied); err != nil {
+		return nil, fmt.Errorf("error decoding values: %w", err)
+	}
+
+	return copied, nil
+}
+
+// porterYAMLFromValues synthesizes a porter.yaml which deploys the services of exported app values. The env variables
+// which every service has in common are moved to the top-level env, which porter.yaml copies into each service. The
+// returned warnings name the values which are not services and so are left out.
+func porterYAMLFromValues(values map[string]interface{}, preDeployJobValues map[string]interface{}, scalingSchedules models.PorterAppScalingSchedules) ([]byte, []string, error) {
+	copied, err := copyValues(values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var preDeploy map[string]interface{}
+	if preDeployJobValues != nil {
+		preDeploy, err = copyValues(preDeployJobValues)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var warnings []string
+	var keys []string
+	for key := range copied {
+		if key == "global" {
+			continue
+		}
+		if name, _ := getServiceNameAndTypeFromHelmName(key); name == "" {
+			warnings = append(warnings, fmt.Sprintf("value %s is not a service, so it was left out of porter.yaml", key))
+			continue
+		}
+		if _, ok := copied[key].(map[string]interface{}); !ok {
+			warnings = append(warnings, fmt.Sprintf("values of service %s are not a map, so it was left out of porter.yaml", key))
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	serviceValues := make([]map[string]interface{}, 0, len(keys)+1)
+	for _, key := range keys {
+		serviceValues = append(serviceValues, copied[key].(map[string]interface{}))
+	}
+	if preDeploy != nil {
+		serviceValues = append(serviceValues, preDeploy)
+	}
+	env := commonEnv(serviceValues)
+
+	doc := yaml.MapSlice{{Key: "version", Value: "v1stack"}}
+
+	if image := attemptToGetImageInfoFromRelease(copied); image.Repository != "" && image.Tag != "" {
+		doc = append(doc, yaml.MapItem{Key: "build", Value: yaml.MapSlice{
+			{Key: "method", Value: "registry"},
+			{Key: "image", Value: fmt.Sprintf("%s:%s", image.Repository, image.Tag)},
+		}})
+	}
+
+	if len(env) > 0 {
+		doc = append(doc, yaml.MapItem{Key: "env", Value: env})
+	}
+
+	services := yaml.MapSlice{}
+	for _, key := range keys {
+		name, serviceType := getServiceNameAndTypeFromHelmName(key)
+		service := porterYAMLService(serviceType, copied[key].(map[string]interface{}), env)
+		if schedule, ok := scalingSchedules[name]; ok && schedule != nil {
+			service = append(service, yaml.MapItem{Key: "scaling", Value: schedule.Schedule})
+		}
+		services = append(services, yaml.MapItem{Key: name, Value: service})
+	}
+	doc = append(doc, yaml.MapItem{Key: "services", Value: services})
+
+	if preDeploy != nil {
+		doc = append(doc, yaml.MapItem{Key: "release", Value: porterYAMLService("", preDeploy, env)})
+	}
+
+	by, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding porter.yaml: %w", err)
+	}
+
+	return by, warnings, nil
+}
+
+// porterYAMLService converts the values of a service into its porter.yaml definition. The start command is moved out
+// of the config into run, the custom domains out of the hosts of the ingress into custom_domains, the network
+// allowances into network, and the common env variables are removed. The type is left out if empty.
+func porterYAMLService(serviceType string, values map[string]interface{}, common yaml.MapSlice) yaml.MapSlice {
+	service := yaml.MapSlice{}
+	if serviceType != "" {
+		service = append(service, yaml.MapItem{Key: "type", Value: serviceType})
+	}
+
+	if container, ok := values["container"].(map[string]interface{}); ok {
+		if command, ok := container["command"].(string); ok && command != "" {
+			service = append(service, yaml.MapItem{Key: "run", Value: command})
+		}
+		delete(container, "command")
+
+		if env, ok := container["env"].(map[string]interface{}); ok {
+			if normal, ok := env["normal"].(map[string]interface{}); ok {
+				for _, item := range common {
+					delete(normal, item.Key.(string))
+				}
+				if len(normal) == 0 {
+					delete(env, "normal")
+				}
+			}
+			if synced, ok := env["synced"].([]interface{}); ok && len(synced) == 0 {
+				delete(env, "synced")
+			}
+			if len(env) == 0 {
+				delete(container, "env")
+			}
+		}
+
+		if len(container) == 0 {
+			delete(values, "container")
+		}
+	}
+
+	// the custom domains are set in porter.yaml rather than in the hosts of the ingress, which they are added to on deploy
+	if ingress, ok := values["ingress"].(map[string]interface{}); ok {
+		if customDomains := stringsFromValue(ingress[customDomainsKey]); len(customDomains) > 0 {
+			setCustomDomains(values, "", nil, nil, "")
+			service = append(service, yaml.MapItem{Key: "custom_domains", Value: customDomains})
+		}
+	}
+
+	if network, ok := values[serviceNetworkKey]; ok {
+		delete(values, serviceNetworkKey)
+		service = append(service, yaml.MapItem{Key: "network", Value: network})
+	}
+
+	// the probes set by a health check are exported as the health check rather than as config.health
+	if healthCheck, ok := values[serviceHealthCheckKey]; ok {
+		delete(values, serviceHealthCheckKey)
+		delete(values, "health")
+		service = append(service, yaml.MapItem{Key: "healthCheck", Value: healthCheck})
+	}
+
+	if len(values) > 0 {
+		service = append(service, yaml.MapItem{Key: "config", Value: values})
+	}
+
+	return service
+}
+
+// commonEnv returns the env variables which every service sets to the same value, sorted by name
+func commonEnv(services []map[string]interface{}) yaml.MapSlice {
+	if len(services) == 0 {
+		return nil
+	}
+
+	envs := make([]map[string]interface{}, 0, len(services))
+	for _, values := range services {
+		env, err := getNestedMap(values, "container", "env")
+		if err != nil {
+			return nil
+		}
+		normal, ok := env["normal"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		envs = append(envs, normal)
+	}
+
+	var names []string
+	for name, value := range envs[0] {
+		shared := true
+		for _, env := range envs[1:] {
+			if other, ok := env[name]; !ok || fmt.Sprint(other) != fmt.Sprint(value) {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	common := make(yaml.MapSlice, 0, len(names))
+	for _, name := range names {
+		common = append(common, yaml.MapItem{Key: name, Value: fmt.Sprint(envs[0][name])})
+	}
+
+	return common
+}
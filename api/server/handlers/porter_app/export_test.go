@@ -0,0 +1,213 @@
+package porter_app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	internalPorterApp "github.com/porter-dev/porter/internal/porter_app"
+	"gopkg.in/yaml.v2"
+)
+
+func deployedValues() map[string]interface{} {
+	return map[string]interface{}{
+		"global": map[string]interface{}{
+			"image": map[string]interface{}{"repository": "registry.example.com/api", "tag": "abc123"},
+			internalPorterApp.SchedulingDefaultsHashKey: "0123456789abcdef",
+		},
+		"web-web": map[string]interface{}{
+			"container": map[string]interface{}{
+				"command": "/cnb/lifecycle/launcher npm start",
+				"env": map[string]interface{}{
+					"normal": map[string]interface{}{
+						"NODE_ENV":                    "production",
+						"PORT":                        "8080",
+						"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4317",
+					},
+					"synced": []interface{}{},
+				},
+			},
+			"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "250m", "memory": "512Mi"}},
+			"ingress":   map[string]interface{}{"enabled": true, "hosts": []interface{}{"api.example.com"}},
+			"labels": map[string]interface{}{
+				"porter.run/porter-application": "true",
+				"app.kubernetes.io/name":        "web",
+				"app.kubernetes.io/part-of":     "api",
+				"app.kubernetes.io/version":     "abc123",
+				"team":                          "payments",
+			},
+			"nodeSelector":     map[string]interface{}{"porter.run/workload-kind": "application", "pool": "apps"},
+			"imagePullSecrets": []interface{}{map[string]interface{}{"name": "porter-registry"}},
+			"secretRefs":       []interface{}{"web-web-external-secrets"},
+		},
+		"worker-wkr": map[string]interface{}{
+			"container": map[string]interface{}{
+				"command": "npm run worker",
+				"env": map[string]interface{}{
+					"normal": map[string]interface{}{"NODE_ENV": "production"},
+				},
+			},
+		},
+	}
+}
+
+func exportConf() injectedValuesConf {
+	return injectedValuesConf{
+		appName:            "api",
+		namespace:          "porter-stack-api",
+		imageTag:           "abc123",
+		observability:      &types.ProjectObservabilityConfig{OTLPEndpoint: "http://collector:4317"},
+		schedulingDefaults: types.ClusterSchedulingDefaults{NodeSelector: map[string]string{"pool": "apps"}},
+	}
+}
+
+func TestExportedValuesStripsInjectedValues(t *testing.T) {
+	deployed := deployedValues()
+
+	values, warnings, err := exportedValues(deployed, exportConf())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := values["global"].(map[string]interface{})[internalPorterApp.SchedulingDefaultsHashKey]; ok {
+		t.Error("expected the scheduling defaults hash to be stripped")
+	}
+
+	web := values["web-web"].(map[string]interface{})
+	for _, key := range []string{"imagePullSecrets", "nodeSelector", "secretRefs"} {
+		if _, ok := web[key]; ok {
+			t.Errorf("expected %s to be stripped, got %v", key, web[key])
+		}
+	}
+
+	labels := web["labels"].(map[string]interface{})
+	if len(labels) != 1 || labels["team"] != "payments" {
+		t.Errorf("expected only the user's labels to be kept, got %v", labels)
+	}
+
+	container := web["container"].(map[string]interface{})
+	if container["command"] != "npm start" {
+		t.Errorf("expected the launcher to be stripped from the command, got %v", container["command"])
+	}
+	normal := container["env"].(map[string]interface{})["normal"].(map[string]interface{})
+	if _, ok := normal["OTEL_EXPORTER_OTLP_ENDPOINT"]; ok {
+		t.Error("expected the injected observability env to be stripped")
+	}
+	if normal["PORT"] != "8080" {
+		t.Errorf("expected the user's env to be kept, got %v", normal)
+	}
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "valueFrom") {
+		t.Errorf("expected a warning about env variables set with valueFrom, got %v", warnings)
+	}
+
+	// the values of the release must not be changed
+	if _, ok := deployed["web-web"].(map[string]interface{})["imagePullSecrets"]; !ok {
+		t.Error("expected the deployed values to be left alone")
+	}
+}
+
+func TestExportedValuesKeepsOverriddenValues(t *testing.T) {
+	deployed := deployedValues()
+	web := deployed["web-web"].(map[string]interface{})
+	web["container"].(map[string]interface{})["env"].(map[string]interface{})["normal"].(map[string]interface{})["OTEL_EXPORTER_OTLP_ENDPOINT"] = "http://my-collector:4317"
+	web["nodeSelector"].(map[string]interface{})["pool"] = "batch"
+
+	values, _, err := exportedValues(deployed, exportConf())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exported := values["web-web"].(map[string]interface{})
+	normal := exported["container"].(map[string]interface{})["env"].(map[string]interface{})["normal"].(map[string]interface{})
+	if normal["OTEL_EXPORTER_OTLP_ENDPOINT"] != "http://my-collector:4317" {
+		t.Errorf("expected an env variable the user overrode to be kept, got %v", normal)
+	}
+	if selector, ok := exported["nodeSelector"].(map[string]interface{}); !ok || selector["pool"] != "batch" {
+		t.Errorf("expected a node selector the user overrode to be kept, got %v", exported["nodeSelector"])
+	}
+}
+
+func TestPorterYAMLFromValues(t *testing.T) {
+	values, _, err := exportedValues(deployedValues(), exportConf())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	preDeploy, _, err := exportedPreDeployJobValues(map[string]interface{}{
+		"image": map[string]interface{}{"repository": "registry.example.com/api", "tag": "abc123"},
+		"container": map[string]interface{}{
+			"command": "npm run migrate",
+			"env":     map[string]interface{}{"normal": map[string]interface{}{"NODE_ENV": "production"}},
+		},
+		"labels": map[string]interface{}{"porter.run/porter-application-pre-deploy": "true"},
+	}, exportConf())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	schedules := models.PorterAppScalingSchedules{
+		"worker": {HelmName: "worker-wkr", Schedule: types.ServiceScalingSchedule{Timezone: "UTC"}},
+	}
+
+	by, warnings, err := porterYAMLFromValues(values, preDeploy, schedules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+
+	parsed := &PorterStackYAML{}
+	if err := yaml.Unmarshal(by, parsed); err != nil {
+		t.Fatalf("expected a valid porter.yaml, got %v:\n%s", err, by)
+	}
+
+	if parsed.Version == nil || *parsed.Version != "v1stack" {
+		t.Errorf("expected a v1stack porter.yaml, got %v", parsed.Version)
+	}
+	if parsed.Build == nil || parsed.Build.Image == nil || *parsed.Build.Image != "registry.example.com/api:abc123" {
+		t.Errorf("expected the deployed image to be built from the registry, got %+v", parsed.Build)
+	}
+	if len(parsed.Env) != 1 || parsed.Env["NODE_ENV"] != "production" {
+		t.Errorf("expected the env shared by every service to be moved to the top level, got %v", parsed.Env)
+	}
+
+	web := parsed.Services["web"]
+	if web == nil || web.Run == nil || *web.Run != "npm start" || web.Type == nil || *web.Type != "web" {
+		t.Fatalf("expected the web service with its start command, got %+v", web)
+	}
+	normal, err := getNestedMap(convertMap(web.Config).(map[string]interface{}), "container", "env", "normal")
+	if err != nil || normal["PORT"] != "8080" || normal["NODE_ENV"] != nil {
+		t.Errorf("expected only the env of the web service in its config, got %v (%v)", normal, err)
+	}
+	if _, ok := convertMap(web.Config).(map[string]interface{})["ingress"]; !ok {
+		t.Error("expected the domains of the web service to be kept")
+	}
+
+	worker := parsed.Services["worker"]
+	if worker == nil || worker.Scaling == nil || worker.Scaling.Timezone != "UTC" {
+		t.Errorf("expected the scaling schedule of the worker, got %+v", worker)
+	}
+
+	if parsed.Release == nil || parsed.Release.Run == nil || *parsed.Release.Run != "npm run migrate" {
+		t.Errorf("expected the release job, got %+v", parsed.Release)
+	}
+	if parsed.Release != nil && len(parsed.Release.Config) != 0 {
+		t.Errorf("expected the injected values of the release job to be stripped, got %v", parsed.Release.Config)
+	}
+}
+
+func TestPorterYAMLFromValuesWarnsAboutValuesWhichAreNotServices(t *testing.T) {
+	_, warnings, err := porterYAMLFromValues(map[string]interface{}{
+		"web-web":  map[string]interface{}{},
+		"postgres": map[string]interface{}{"enabled": true},
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "postgres") {
+		t.Errorf("expected a warning about the postgres values, got %v", warnings)
+	}
+}
@@ -0,0 +1,183 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/porter-dev/porter/internal/integrations/secretstores"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/porter_app"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// externalSecretsSuffix names the secret which holds the env variables of a service set with valueFrom
+	externalSecretsSuffix = "-external-secrets"
+	// labelKey_ExternalSecretsVersion identifies the versions of the secrets a service was deployed with, so that its pods
+	// are replaced when one is rotated
+	labelKey_ExternalSecretsVersion = "porter.run/external-secrets-version"
+)
+
+// externalSecretsName returns the name of the secret which holds the env variables of a service set with valueFrom
+func externalSecretsName(helmName string) string {
+	return helmName + externalSecretsSuffix
+}
+
+// applyExternalSecrets reads the env variables of a service set with valueFrom from the project's secret stores, writes
+// them to the service's secret in the cluster and points the service's values at it. The values are never written to
+// the helm values. A service without any such env variable has the secret of its previous deploys removed. If dryRun
+// is set, the env variables are still read so that missing secrets are reported, but the cluster is left alone.
+func applyExternalSecrets(
+	ctx context.Context,
+	agent *kubernetes.Agent,
+	resolver *secretstores.Resolver,
+	namespace string,
+	helmName string,
+	refs map[string]envvalues.SecretReference,
+	serviceValues map[string]interface{},
+	dryRun bool,
+) error {
+	secretName := externalSecretsName(helmName)
+
+	if len(refs) == 0 {
+		if !removeSecretRef(serviceValues, secretName) || agent == nil {
+			return nil
+		}
+		deleteLabel(serviceValues, "podLabels", labelKey_ExternalSecretsVersion)
+		if dryRun {
+			return nil
+		}
+
+		err := agent.Clientset.CoreV1().Secrets(namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("error deleting secret %s: %w", secretName, err)
+		}
+
+		return nil
+	}
+
+	if resolver == nil || (agent == nil && !dryRun) {
+		return errors.New("env variables set with valueFrom cannot be read for this deploy")
+	}
+
+	resolved, err := resolver.Resolve(ctx, refs)
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		if err := writeExternalSecret(ctx, agent, namespace, secretName, resolved.Values); err != nil {
+			return err
+		}
+	}
+
+	addSecretRef(serviceValues, secretName)
+
+	// env variables set directly take precedence over those of the secret, so any left by earlier deploys are removed
+	if env, err := getNestedMap(serviceValues, "container", "env"); err == nil {
+		if normal, ok := env["normal"].(map[string]interface{}); ok {
+			for key := range refs {
+				delete(normal, key)
+			}
+		}
+	}
+
+	podLabels, ok := serviceValues["podLabels"].(map[string]interface{})
+	if !ok {
+		podLabels = map[string]interface{}{}
+		serviceValues["podLabels"] = podLabels
+	}
+	podLabels[labelKey_ExternalSecretsVersion] = resolved.Fingerprint()
+
+	return nil
+}
+
+// writeExternalSecret writes the env variables of a service set with valueFrom to its secret in the cluster
+func writeExternalSecret(ctx context.Context, agent *kubernetes.Agent, namespace string, secretName string, values map[string]string) error {
+	data := make(map[string][]byte, len(values))
+	for key, value := range values {
+		data[key] = []byte(value)
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				porter_app.LabelKey_PorterApplication: porter_app.LabelValue_PorterApplication,
+				"porter.run/external-secrets":         "true",
+			},
+		},
+		Data: data,
+		Type: v1.SecretTypeOpaque,
+	}
+
+	_, err := agent.Clientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = agent.Clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error writing secret %s: %w", secretName, err)
+	}
+
+	return nil
+}
+
+// addSecretRef adds a secret to those the env of a service is read from, unless it is already one of them
+func addSecretRef(serviceValues map[string]interface{}, secretName string) {
+	var refs []interface{}
+	switch existing := serviceValues["secretRefs"].(type) {
+	case []interface{}:
+		refs = existing
+	case []string:
+		for _, ref := range existing {
+			refs = append(refs, ref)
+		}
+	}
+
+	for _, ref := range refs {
+		if ref == secretName {
+			serviceValues["secretRefs"] = refs
+			return
+		}
+	}
+
+	serviceValues["secretRefs"] = append(refs, secretName)
+}
+
+// removeSecretRef removes a secret from those the env of a service is read from, and returns true if it was one of them
+func removeSecretRef(serviceValues map[string]interface{}, secretName string) bool {
+	var refs []interface{}
+	switch existing := serviceValues["secretRefs"].(type) {
+	case []interface{}:
+		refs = existing
+	case []string:
+		for _, ref := range existing {
+			refs = append(refs, ref)
+		}
+	}
+
+	kept := make([]interface{}, 0, len(refs))
+	for _, ref := range refs {
+		if ref != secretName {
+			kept = append(kept, ref)
+		}
+	}
+	if len(kept) == len(refs) {
+		return false
+	}
+
+	serviceValues["secretRefs"] = kept
+
+	return true
+}
+
+func deleteLabel(serviceValues map[string]interface{}, field string, key string) {
+	if labels, ok := serviceValues[field].(map[string]interface{}); ok {
+		delete(labels, key)
+	}
+}
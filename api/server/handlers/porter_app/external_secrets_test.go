@@ -0,0 +1,143 @@
+package porter_app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/secretstores"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type testSecretStore map[string]secretstores.Secret
+
+func (s testSecretStore) Read(_ context.Context, ref envvalues.SecretReference) (secretstores.Secret, error) {
+	return s[ref.Path], nil
+}
+
+func (s testSecretStore) Test(context.Context) error { return nil }
+
+func TestApplyExternalSecrets(t *testing.T) {
+	ctx := context.Background()
+	agent := &kubernetes.Agent{Clientset: fake.NewSimpleClientset()}
+	store := testSecretStore{"app/db": {Value: "hunter2", Version: "1"}}
+	resolver := func() *secretstores.Resolver {
+		return secretstores.NewResolver(func(context.Context, types.SecretsProvider) (secretstores.Store, error) {
+			return store, nil
+		})
+	}
+
+	refs := map[string]envvalues.SecretReference{
+		"DB_PASSWORD": {Provider: types.SecretsProvider_Vault, Path: "app/db", Key: "password"},
+	}
+	serviceValues := map[string]interface{}{
+		"container": map[string]interface{}{
+			"env": map[string]interface{}{
+				// left by a deploy from before the variable was set with valueFrom
+				"normal": map[string]interface{}{"DB_PASSWORD": "plaintext", "LOG_LEVEL": "info"},
+			},
+		},
+		"secretRefs": []string{"shared.v2"},
+	}
+
+	if err := applyExternalSecrets(ctx, agent, resolver(), "porter-stack-storefront", "web-web", refs, serviceValues, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret, err := agent.Clientset.CoreV1().Secrets("porter-stack-storefront").Get(ctx, "web-web-external-secrets", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting secret: %v", err)
+	}
+	if string(secret.Data["DB_PASSWORD"]) != "hunter2" {
+		t.Errorf("expected the secret to hold the value read from the store, got %q", secret.Data["DB_PASSWORD"])
+	}
+
+	secretRefs, _ := serviceValues["secretRefs"].([]interface{})
+	if len(secretRefs) != 2 || secretRefs[0] != "shared.v2" || secretRefs[1] != "web-web-external-secrets" {
+		t.Errorf("unexpected secret refs %v", serviceValues["secretRefs"])
+	}
+
+	normal := serviceValues["container"].(map[string]interface{})["env"].(map[string]interface{})["normal"].(map[string]interface{})
+	if _, ok := normal["DB_PASSWORD"]; ok {
+		t.Errorf("expected the variable to be removed from the normal env")
+	}
+	if normal["LOG_LEVEL"] != "info" {
+		t.Errorf("expected the other variables to be kept")
+	}
+
+	podLabels := serviceValues["podLabels"].(map[string]interface{})
+	version := podLabels[labelKey_ExternalSecretsVersion]
+	if version == "" || version == nil {
+		t.Fatalf("expected the pods to be labelled with the secret versions")
+	}
+
+	// a rotated secret is written again and replaces the pods
+	store["app/db"] = secretstores.Secret{Value: "hunter3", Version: "2"}
+	if err := applyExternalSecrets(ctx, agent, resolver(), "porter-stack-storefront", "web-web", refs, serviceValues, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret, err = agent.Clientset.CoreV1().Secrets("porter-stack-storefront").Get(ctx, "web-web-external-secrets", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting secret: %v", err)
+	}
+	if string(secret.Data["DB_PASSWORD"]) != "hunter3" {
+		t.Errorf("expected the rotated value, got %q", secret.Data["DB_PASSWORD"])
+	}
+	if podLabels[labelKey_ExternalSecretsVersion] == version {
+		t.Errorf("expected the version label to change when the secret is rotated")
+	}
+	if len(serviceValues["secretRefs"].([]interface{})) != 2 {
+		t.Errorf("expected the secret ref to be added once, got %v", serviceValues["secretRefs"])
+	}
+
+	// removing every reference removes the secret
+	if err := applyExternalSecrets(ctx, agent, resolver(), "porter-stack-storefront", "web-web", nil, serviceValues, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := agent.Clientset.CoreV1().Secrets("porter-stack-storefront").Get(ctx, "web-web-external-secrets", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the secret to be deleted")
+	}
+	if secretRefs := serviceValues["secretRefs"].([]interface{}); len(secretRefs) != 1 || secretRefs[0] != "shared.v2" {
+		t.Errorf("unexpected secret refs %v", secretRefs)
+	}
+	if _, ok := podLabels[labelKey_ExternalSecretsVersion]; ok {
+		t.Errorf("expected the version label to be removed")
+	}
+
+	if err := applyExternalSecrets(ctx, agent, nil, "porter-stack-storefront", "web-web", refs, serviceValues, false); err == nil {
+		t.Errorf("expected an error without a resolver")
+	}
+}
+
+func TestApplyExternalSecretsDryRun(t *testing.T) {
+	ctx := context.Background()
+	agent := &kubernetes.Agent{Clientset: fake.NewSimpleClientset()}
+	resolver := secretstores.NewResolver(func(context.Context, types.SecretsProvider) (secretstores.Store, error) {
+		return testSecretStore{"app/db": {Value: "hunter2", Version: "1"}}, nil
+	})
+
+	refs := map[string]envvalues.SecretReference{
+		"DB_PASSWORD": {Provider: types.SecretsProvider_Vault, Path: "app/db", Key: "password"},
+	}
+	serviceValues := map[string]interface{}{}
+
+	if err := applyExternalSecrets(ctx, agent, resolver, "porter-stack-storefront", "web-web", refs, serviceValues, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := agent.Clientset.CoreV1().Secrets("porter-stack-storefront").Get(ctx, "web-web-external-secrets", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected no secret to be written on a dry run")
+	}
+	if secretRefs, _ := serviceValues["secretRefs"].([]interface{}); len(secretRefs) != 1 || secretRefs[0] != "web-web-external-secrets" {
+		t.Errorf("expected the values to point at the secret, got %v", serviceValues["secretRefs"])
+	}
+
+	if err := applyExternalSecrets(ctx, nil, resolver, "porter-stack-storefront", "web-web", refs, map[string]interface{}{}, true); err != nil {
+		t.Errorf("expected a dry run not to need the cluster, got %v", err)
+	}
+}
@@ -1,7 +1,10 @@
 package porter_app
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
@@ -11,8 +14,12 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
 	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/coalesce"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/scaling"
 	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"gorm.io/gorm"
 )
 
 type GetPorterAppHandler struct {
@@ -46,22 +53,46 @@ func (c *GetPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "application-name", Value: appName})
 
-	app, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Read); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
 		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
-	if app == nil || app.ID == 0 {
-		err = telemetry.Error(ctx, span, nil, "app with name does not exist in project")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+
+	grants, err := appGrants(ctx, c.Config(), project.ID, appName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing app grants")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	domains, err := listDomainCertStatuses(ctx, c.Repo(), app.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing porter app domains")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
 
 	// this is a temporary fix until we figure out how to reconcile the new revisions table
 	// with dependencies on helm releases throuhg the api
 	if project.GetFeatureFlag(models.ValidateApplyV2, c.Config().LaunchDarklyClient) {
-		c.WriteResult(w, r, app.ToPorterAppType())
+		res := app.ToPorterAppType()
+		res.ScalingSchedule = scaling.Status(app, time.Now())
+		res.Grants = grants
+		res.Domains = domains
+		c.WriteResult(w, r, res)
 		return
 	}
 
@@ -72,12 +103,43 @@ func (c *GetPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
-	helmRelease, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	helmRelease, cacheResult, err := coalesce.Do(c.Config().StatusQueryCoalescer, coalesce.Key{
+		ClusterID: cluster.ID,
+		Namespace: namespace,
+		StackName: appName,
+		QueryType: coalesce.QueryType_Release,
+		Params:    "0",
+	}, coalesce.OptionsFromRequest(r), func() (*release.Release, error) {
+		return helmAgent.GetRelease(ctx, appName, 0, false)
+	})
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "error getting helm release for app")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
 
-	c.WriteResult(w, r, app.ToPorterAppTypeWithRevision(helmRelease.Version))
+	res := app.ToPorterAppTypeWithRevision(helmRelease.Version)
+	res.ScalingSchedule = scaling.Status(app, time.Now())
+
+	// the last runs are left out rather than failing the request, since the app itself was read
+	agent, err := c.GetAgent(r, cluster, namespace)
+	if err == nil {
+		res.CronServices, _, err = coalesce.Do(c.Config().StatusQueryCoalescer, coalesce.Key{
+			ClusterID: cluster.ID,
+			Namespace: namespace,
+			StackName: appName,
+			QueryType: coalesce.QueryType_CronStatus,
+			Params:    strconv.Itoa(helmRelease.Version),
+		}, coalesce.OptionsFromRequest(r), func() ([]types.CronServiceStatus, error) {
+			return cronServiceStatuses(ctx, agent.Clientset, appName, namespace, helmRelease.Config)
+		})
+	}
+	if err != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "cron-status-error", Value: err.Error()})
+	}
+
+	res.Grants = grants
+	res.Domains = domains
+	cacheResult.SetHeaders(w)
+	c.WriteResult(w, r, res)
 }
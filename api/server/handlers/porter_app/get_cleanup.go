@@ -0,0 +1,69 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetPorterAppCleanupHandler handles GET /stack_cleanups/{porter_app_cleanup_id}, which returns the cleanup of a deleted
+// app so that its status can be polled
+type GetPorterAppCleanupHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetPorterAppCleanupHandler returns a new GetPorterAppCleanupHandler
+func NewGetPorterAppCleanupHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetPorterAppCleanupHandler {
+	return &GetPorterAppCleanupHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetPorterAppCleanupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-porter-app-cleanup")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	cleanupID, reqErr := requestutils.GetURLParamUint(r, types.URLParamPorterAppCleanupID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app cleanup id from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-cleanup-id", Value: cleanupID})
+
+	cleanup, err := c.Repo().PorterAppCleanup().ReadPorterAppCleanup(ctx, project.ID, cleanupID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app cleanup not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app cleanup")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// cleanups are scoped to the project, so a cleanup of another cluster is reported as missing
+	if cleanup.ClusterID != cluster.ID {
+		err = telemetry.Error(ctx, span, nil, "porter app cleanup not found in cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+		return
+	}
+
+	c.WriteResult(w, r, cleanup.ToPorterAppCleanupType())
+}
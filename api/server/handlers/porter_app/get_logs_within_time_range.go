@@ -103,7 +103,7 @@ func (c *GetLogsWithinTimeRangeHandler) ServeHTTP(w http.ResponseWriter, r *http
 			var latestPod *v1.Pod
 			for _, v := range podVals {
 				name := strings.Split(v, "-hook")[0] + "-hook"
-				pods, err := agent.GetJobPods(request.Namespace, name)
+				pods, err := agent.GetJobPods(ctx, request.Namespace, name)
 				if err != nil {
 					_ = telemetry.Error(ctx, span, err, "unable to get pods for job")
 					c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("unable to get pods for job"), http.StatusInternalServerError))
@@ -0,0 +1,308 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// maxBranchSuggestions is the number of close matches suggested when the branch of an app does not exist
+const maxBranchSuggestions = 3
+
+// gitRepositoriesClient is the part of the github repositories API used to validate the git source of an app
+type gitRepositoriesClient interface {
+	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
+	ListBranches(ctx context.Context, owner, repo string, opts *github.BranchListOptions) ([]*github.Branch, *github.Response, error)
+	GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
+}
+
+// gitSourceError is returned when a field of the git source of an app does not match the linked repository
+type gitSourceError struct {
+	Field  string
+	Reason string
+}
+
+func (e *gitSourceError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+type validateGitSourceInput struct {
+	GitRepoID           uint
+	GitlabIntegrationID uint
+	RepoName            string
+	GitBranch           string
+	PorterYamlPath      string
+	Dockerfile          string
+	BuildContext        string
+}
+
+type validateGitSourceOutput struct {
+	// RepoName is the full name of the repository as github spells it
+	RepoName string
+	// Warnings name the build settings which do not exist at the head of the branch
+	Warnings []string
+}
+
+// validateRequestGitSource validates the git source of a create or update request against the github app installation
+// it is linked to. Fields missing from an update are taken from the app. Nothing is validated if the request does not set
+// a repository or branch, or the app is not linked to a github app installation, such as apps deployed from gitlab.
+func validateRequestGitSource(ctx context.Context, conf *config.Config, projectID, clusterID uint, appName string, request *types.CreatePorterAppRequest) (validateGitSourceOutput, error) {
+	ctx, span := telemetry.NewSpan(ctx, "validate-request-git-source")
+	defer span.End()
+
+	inp := validateGitSourceInput{
+		GitRepoID:           request.GitRepoID,
+		GitlabIntegrationID: request.GitlabIntegrationID,
+		RepoName:            request.RepoName,
+		GitBranch:           request.GitBranch,
+		PorterYamlPath:      request.PorterYamlPath,
+		Dockerfile:          request.Dockerfile,
+		BuildContext:        request.BuildContext,
+	}
+	if inp.RepoName == "" && inp.GitBranch == "" {
+		return validateGitSourceOutput{}, nil
+	}
+
+	if inp.GitRepoID == 0 || inp.RepoName == "" || inp.GitBranch == "" {
+		app, err := conf.Repo.PorterApp().ReadScopedPorterAppByName(projectID, clusterID, appName)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return validateGitSourceOutput{}, telemetry.Error(ctx, span, err, "error reading app from DB")
+		}
+		if err == nil {
+			if inp.GitRepoID == 0 && inp.GitlabIntegrationID == 0 {
+				inp.GitRepoID = app.GitRepoID
+				inp.GitlabIntegrationID = app.GitlabIntegrationID
+			}
+			if inp.RepoName == "" {
+				inp.RepoName = app.RepoName
+			}
+			if inp.GitBranch == "" {
+				inp.GitBranch = app.GitBranch
+			}
+		}
+	}
+
+	if inp.GitRepoID == 0 || inp.GitlabIntegrationID != 0 || inp.RepoName == "" || conf.ServerConf.GithubAppSecret == nil || conf.ServerConf.GithubAppID == "" {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "skipped", Value: true})
+		return validateGitSourceOutput{}, nil
+	}
+
+	client, err := porter_app.GetGithubClientByRepoID(ctx, inp.GitRepoID, conf.ServerConf.GithubAppSecret, conf.ServerConf.GithubAppID)
+	if err != nil {
+		return validateGitSourceOutput{}, telemetry.Error(ctx, span, err, "error getting github client")
+	}
+
+	return validateGitSource(ctx, client.Repositories, inp)
+}
+
+// validateGitSource checks that the repository of an app is accessible to its github app installation, and that its
+// branch exists. Build settings which are missing from the head of the branch are returned as warnings, since they may be
+// pushed before the app is built. A *gitSourceError is returned if the repository or branch do not match.
+func validateGitSource(ctx context.Context, client gitRepositoriesClient, inp validateGitSourceInput) (validateGitSourceOutput, error) {
+	ctx, span := telemetry.NewSpan(ctx, "validate-git-source")
+	defer span.End()
+
+	var out validateGitSourceOutput
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "git-repo-id", Value: inp.GitRepoID},
+		telemetry.AttributeKV{Key: "repo-name", Value: inp.RepoName},
+		telemetry.AttributeKV{Key: "git-branch", Value: inp.GitBranch},
+	)
+
+	owner, name, ok := strings.Cut(inp.RepoName, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return out, &gitSourceError{Field: "repo_name", Reason: fmt.Sprintf("%q is not in the format <owner>/<name>", inp.RepoName)}
+	}
+
+	repo, resp, err := client.Get(ctx, owner, name)
+	if err != nil {
+		if isNotFound(resp) {
+			return out, &gitSourceError{
+				Field:  "repo_name",
+				Reason: fmt.Sprintf("repository %s does not exist or is not accessible to github installation %d", inp.RepoName, inp.GitRepoID),
+			}
+		}
+		return out, telemetry.Error(ctx, span, err, "error getting github repository")
+	}
+
+	out.RepoName = inp.RepoName
+	if repo.GetFullName() != "" {
+		out.RepoName = repo.GetFullName()
+		owner, name, _ = strings.Cut(out.RepoName, "/")
+	}
+
+	ref := inp.GitBranch
+	if ref == "" {
+		ref = repo.GetDefaultBranch()
+	} else {
+		branches, err := listBranchNames(ctx, client, owner, name)
+		if err != nil {
+			return out, telemetry.Error(ctx, span, err, "error listing github branches")
+		}
+
+		if !containsString(branches, inp.GitBranch) {
+			reason := fmt.Sprintf("branch %q does not exist in %s", inp.GitBranch, out.RepoName)
+			if suggestions := closeMatches(inp.GitBranch, branches, maxBranchSuggestions); len(suggestions) != 0 {
+				reason = fmt.Sprintf("%s, did you mean %s?", reason, strings.Join(suggestions, ", "))
+			}
+			return out, &gitSourceError{Field: "git_branch", Reason: reason}
+		}
+	}
+
+	buildPaths := []struct {
+		field string
+		path  string
+	}{
+		{field: "porter_yaml_path", path: inp.PorterYamlPath},
+		{field: "dockerfile", path: inp.Dockerfile},
+		{field: "build_context", path: inp.BuildContext},
+	}
+	for _, buildPath := range buildPaths {
+		repoPath := repositoryPath(buildPath.path)
+		if repoPath == "" {
+			continue
+		}
+
+		_, _, resp, err := client.GetContents(ctx, owner, name, repoPath, &github.RepositoryContentGetOptions{Ref: ref})
+		if err != nil {
+			if isNotFound(resp) {
+				out.Warnings = append(out.Warnings, fmt.Sprintf("%s %q does not exist at the head of %s in %s", buildPath.field, buildPath.path, ref, out.RepoName))
+				continue
+			}
+			return out, telemetry.Error(ctx, span, err, "error getting github contents")
+		}
+	}
+
+	return out, nil
+}
+
+func listBranchNames(ctx context.Context, client gitRepositoriesClient, owner, name string) ([]string, error) {
+	opts := &github.BranchListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+
+	var names []string
+	for {
+		branches, resp, err := client.ListBranches(ctx, owner, name, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, branch := range branches {
+			names = append(names, branch.GetName())
+		}
+
+		if resp == nil || resp.NextPage == 0 {
+			return names, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// repositoryPath returns a path of the build settings relative to the root of the repository, or an empty string if it
+// is the root itself
+func repositoryPath(p string) string {
+	cleaned := strings.TrimPrefix(path.Clean("/"+p), "/")
+	if p == "" || cleaned == "" {
+		return ""
+	}
+
+	return cleaned
+}
+
+func isNotFound(resp *github.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusNotFound
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// closeMatches returns up to max candidates which are closest to the value, ignoring case. Candidates which are too far
+// from the value to be a typo of it are left out.
+func closeMatches(value string, candidates []string, max int) []string {
+	type match struct {
+		candidate string
+		distance  int
+	}
+
+	lowerValue := strings.ToLower(value)
+	threshold := len(value) / 3
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	var matches []match
+	for _, candidate := range candidates {
+		distance := editDistance(lowerValue, strings.ToLower(candidate))
+		if distance <= threshold {
+			matches = append(matches, match{candidate: candidate, distance: distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].candidate < matches[j].candidate
+	})
+
+	var suggestions []string
+	for i := 0; i < len(matches) && i < max; i++ {
+		suggestions = append(suggestions, matches[i].candidate)
+	}
+
+	return suggestions
+}
+
+// editDistance returns the levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
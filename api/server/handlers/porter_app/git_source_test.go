@@ -0,0 +1,163 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+)
+
+type fakeGitRepositoriesClient struct {
+	repos    map[string]*github.Repository
+	branches []string
+	files    map[string]bool
+}
+
+func notFoundResponse() *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+}
+
+func (f *fakeGitRepositoriesClient) Get(_ context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	r, ok := f.repos[strings.ToLower(owner+"/"+repo)]
+	if !ok {
+		return nil, notFoundResponse(), errors.New("not found")
+	}
+
+	return r, &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
+}
+
+func (f *fakeGitRepositoriesClient) ListBranches(_ context.Context, _, _ string, opts *github.BranchListOptions) ([]*github.Branch, *github.Response, error) {
+	// serve one branch a page, to check that every page is read
+	page := opts.Page
+	if page == 0 {
+		page = 1
+	}
+	if page > len(f.branches) {
+		return nil, &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
+	}
+
+	resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	if page < len(f.branches) {
+		resp.NextPage = page + 1
+	}
+
+	return []*github.Branch{{Name: github.String(f.branches[page-1])}}, resp, nil
+}
+
+func (f *fakeGitRepositoriesClient) GetContents(_ context.Context, _, _, path string, _ *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	if !f.files[path] {
+		return nil, nil, notFoundResponse(), errors.New("not found")
+	}
+
+	return &github.RepositoryContent{Path: github.String(path)}, nil, &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
+}
+
+func fakeRepositories() *fakeGitRepositoriesClient {
+	return &fakeGitRepositoriesClient{
+		repos: map[string]*github.Repository{
+			"porter-dev/api": {FullName: github.String("Porter-Dev/API"), DefaultBranch: github.String("main")},
+		},
+		branches: []string{"main", "staging", "feature/login"},
+		files:    map[string]bool{"porter.yaml": true, "docker/Dockerfile": true},
+	}
+}
+
+func TestValidateGitSourceNormalizesRepoName(t *testing.T) {
+	out, err := validateGitSource(context.Background(), fakeRepositories(), validateGitSourceInput{
+		GitRepoID:      1,
+		RepoName:       "porter-dev/api",
+		GitBranch:      "feature/login",
+		PorterYamlPath: "./porter.yaml",
+		Dockerfile:     "./docker/Dockerfile",
+		BuildContext:   ".",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.RepoName != "Porter-Dev/API" {
+		t.Errorf("expected the repository name as github spells it, got %s", out.RepoName)
+	}
+	if len(out.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", out.Warnings)
+	}
+}
+
+func TestValidateGitSourceFailures(t *testing.T) {
+	tests := []struct {
+		name          string
+		inp           validateGitSourceInput
+		expectedField string
+		expectedText  string
+	}{
+		{
+			name:          "malformed repo name",
+			inp:           validateGitSourceInput{RepoName: "api"},
+			expectedField: "repo_name",
+			expectedText:  "<owner>/<name>",
+		},
+		{
+			name:          "inaccessible repo",
+			inp:           validateGitSourceInput{GitRepoID: 1, RepoName: "porter-dev/private"},
+			expectedField: "repo_name",
+			expectedText:  "not accessible to github installation 1",
+		},
+		{
+			name:          "missing branch",
+			inp:           validateGitSourceInput{GitRepoID: 1, RepoName: "porter-dev/api", GitBranch: "stagin"},
+			expectedField: "git_branch",
+			expectedText:  "did you mean staging?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateGitSource(context.Background(), fakeRepositories(), tt.inp)
+
+			var sourceErr *gitSourceError
+			if !errors.As(err, &sourceErr) {
+				t.Fatalf("expected a git source error, got %v", err)
+			}
+			if sourceErr.Field != tt.expectedField {
+				t.Errorf("expected field %s to fail, got %s", tt.expectedField, sourceErr.Field)
+			}
+			if !strings.Contains(sourceErr.Reason, tt.expectedText) {
+				t.Errorf("expected reason to contain %q, got %q", tt.expectedText, sourceErr.Reason)
+			}
+		})
+	}
+}
+
+func TestValidateGitSourceWarnsAboutMissingBuildSettings(t *testing.T) {
+	out, err := validateGitSource(context.Background(), fakeRepositories(), validateGitSourceInput{
+		GitRepoID:    1,
+		RepoName:     "porter-dev/api",
+		Dockerfile:   "./Dockerfile",
+		BuildContext: "./app",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(out.Warnings) != 2 {
+		t.Fatalf("expected warnings about the dockerfile and build context, got %v", out.Warnings)
+	}
+	if !strings.Contains(out.Warnings[0], "dockerfile") || !strings.Contains(out.Warnings[0], "head of main") {
+		t.Errorf("expected a warning about the dockerfile at the head of the default branch, got %s", out.Warnings[0])
+	}
+}
+
+func TestCloseMatches(t *testing.T) {
+	candidates := []string{"main", "master", "Main", "develop", "release/v1"}
+
+	if got := closeMatches("mian", candidates, 2); !reflect.DeepEqual(got, []string{"Main", "main"}) {
+		t.Errorf("unexpected suggestions for mian: %v", got)
+	}
+	if got := closeMatches("production", candidates, 3); len(got) != 0 {
+		t.Errorf("expected no suggestions for production, got %v", got)
+	}
+}
@@ -0,0 +1,99 @@
+package porter_app
+
+import (
+	"encoding/json"
+)
+
+// serviceHealthCheckKey is the key of the values of a service which records the health check of the service in
+// porter.yaml, so that the probes it set are reset to the defaults of the chart once it is removed from porter.yaml
+const serviceHealthCheckKey = "porter_health_check"
+
+// ServiceHealthCheck sets the probes of a web or worker service. The readiness and liveness probes are sent over HTTP
+// to the path, on the port of the container unless another port is set.
+type ServiceHealthCheck struct {
+	Path string `yaml:"path" json:"path"`
+	// Port is the port probes are sent to. Worker services, which do not expose a port, must set it
+	Port                *int `yaml:"port,omitempty" json:"port,omitempty"`
+	InitialDelaySeconds *int `yaml:"initialDelaySeconds,omitempty" json:"initialDelaySeconds,omitempty"`
+	PeriodSeconds       *int `yaml:"periodSeconds,omitempty" json:"periodSeconds,omitempty"`
+	FailureThreshold    *int `yaml:"failureThreshold,omitempty" json:"failureThreshold,omitempty"`
+	// StartupProbe holds off the liveness probe until the service first responds, so that services which are slow to
+	// start are not restarted before they are up
+	StartupProbe *StartupProbe `yaml:"startupProbe,omitempty" json:"startupProbe,omitempty"`
+}
+
+// StartupProbe probes a service on the path of its health check until it first responds. The service is restarted if
+// it has not responded after FailureThreshold probes, PeriodSeconds apart.
+type StartupProbe struct {
+	PeriodSeconds    *int `yaml:"periodSeconds,omitempty" json:"periodSeconds,omitempty"`
+	FailureThreshold *int `yaml:"failureThreshold,omitempty" json:"failureThreshold,omitempty"`
+}
+
+// setServiceHealthCheck sets the probes of a service from its health check in porter.yaml. The probes replace those of
+// the last deploy, so that a setting removed from the health check is removed from the probes. Services which have
+// never set a health check keep the probes in their values, so that they are unchanged by the next deploy.
+func setServiceHealthCheck(serviceValues map[string]interface{}, healthCheck *ServiceHealthCheck) {
+	if healthCheck == nil {
+		if _, ok := serviceValues[serviceHealthCheckKey]; ok {
+			delete(serviceValues, "health")
+			delete(serviceValues, serviceHealthCheckKey)
+		}
+		return
+	}
+
+	probe := func() map[string]interface{} {
+		res := map[string]interface{}{
+			"enabled": true,
+			"path":    healthCheck.Path,
+		}
+		if healthCheck.Port != nil {
+			res["port"] = *healthCheck.Port
+		}
+		if healthCheck.InitialDelaySeconds != nil {
+			res["initialDelaySeconds"] = *healthCheck.InitialDelaySeconds
+		}
+		if healthCheck.PeriodSeconds != nil {
+			res["periodSeconds"] = *healthCheck.PeriodSeconds
+		}
+		if healthCheck.FailureThreshold != nil {
+			res["failureThreshold"] = *healthCheck.FailureThreshold
+		}
+
+		return res
+	}
+
+	startupProbe := map[string]interface{}{"enabled": false}
+	if healthCheck.StartupProbe != nil {
+		startupProbe = map[string]interface{}{
+			"enabled": true,
+			"path":    healthCheck.Path,
+		}
+		if healthCheck.Port != nil {
+			startupProbe["port"] = *healthCheck.Port
+		}
+		if healthCheck.StartupProbe.PeriodSeconds != nil {
+			startupProbe["periodSeconds"] = *healthCheck.StartupProbe.PeriodSeconds
+		}
+		if healthCheck.StartupProbe.FailureThreshold != nil {
+			startupProbe["failureThreshold"] = *healthCheck.StartupProbe.FailureThreshold
+		}
+	}
+
+	serviceValues["health"] = map[string]interface{}{
+		"readinessProbe": probe(),
+		"livenessProbe":  probe(),
+		"startupProbe":   startupProbe,
+	}
+
+	by, err := json.Marshal(healthCheck)
+	if err != nil {
+		return
+	}
+
+	recorded := map[string]interface{}{}
+	if err := json.Unmarshal(by, &recorded); err != nil {
+		return
+	}
+
+	serviceValues[serviceHealthCheckKey] = recorded
+}
@@ -0,0 +1,60 @@
+package porter_app
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServiceHealthCheckSetsProbes(t *testing.T) {
+	port, period, threshold, startupThreshold := 9090, 10, 3, 30
+
+	values := map[string]interface{}{}
+	setServiceHealthCheck(values, &ServiceHealthCheck{
+		Path:             "/healthz",
+		Port:             &port,
+		PeriodSeconds:    &period,
+		FailureThreshold: &threshold,
+		StartupProbe:     &StartupProbe{FailureThreshold: &startupThreshold},
+	})
+
+	wantProbe := map[string]interface{}{"enabled": true, "path": "/healthz", "port": 9090, "periodSeconds": 10, "failureThreshold": 3}
+	want := map[string]interface{}{
+		"readinessProbe": wantProbe,
+		"livenessProbe":  wantProbe,
+		"startupProbe":   map[string]interface{}{"enabled": true, "path": "/healthz", "port": 9090, "failureThreshold": 30},
+	}
+	if got := values["health"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected probes\nwant %v\ngot  %v", want, got)
+	}
+
+	// a startup probe removed from the health check is disabled rather than kept from the last deploy
+	setServiceHealthCheck(values, &ServiceHealthCheck{Path: "/healthz"})
+
+	startupProbe := values["health"].(map[string]interface{})["startupProbe"]
+	if !reflect.DeepEqual(startupProbe, map[string]interface{}{"enabled": false}) {
+		t.Errorf("expected the startup probe to be disabled, got %v", startupProbe)
+	}
+}
+
+func TestServiceHealthCheckOmitted(t *testing.T) {
+	// the probes of a service which never set a health check are left to its values
+	health := map[string]interface{}{"livenessProbe": map[string]interface{}{"enabled": true, "path": "/livez"}}
+	values := map[string]interface{}{"health": health}
+
+	setServiceHealthCheck(values, nil)
+
+	if !reflect.DeepEqual(values["health"], health) {
+		t.Errorf("expected the probes in the values to be kept, got %v", values["health"])
+	}
+
+	// the probes set by a health check are reset to the defaults of the chart once it is removed
+	setServiceHealthCheck(values, &ServiceHealthCheck{Path: "/healthz"})
+	setServiceHealthCheck(values, nil)
+
+	if _, ok := values["health"]; ok {
+		t.Errorf("expected the probes of the removed health check to be removed, got %v", values["health"])
+	}
+	if _, ok := values[serviceHealthCheckKey]; ok {
+		t.Errorf("expected the recorded health check to be removed")
+	}
+}
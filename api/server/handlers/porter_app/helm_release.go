@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
@@ -21,6 +22,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
 	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/coalesce"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/telemetry"
 )
@@ -168,16 +170,25 @@ func (c *PorterAppHelmReleaseGetHandler) ServeHTTP(w http.ResponseWriter, r *htt
 		return
 	}
 
-	release, err := helmAgent.GetRelease(ctx, appName, int(version), false)
+	helmRelease, cacheResult, err := coalesce.Do(c.Config().StatusQueryCoalescer, coalesce.Key{
+		ClusterID: cluster.ID,
+		Namespace: namespace,
+		StackName: appName,
+		QueryType: coalesce.QueryType_Release,
+		Params:    strconv.Itoa(int(version)),
+	}, coalesce.OptionsFromRequest(r), func() (*release.Release, error) {
+		return helmAgent.GetRelease(ctx, appName, int(version), false)
+	})
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "error getting helm release")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
 	res := &types.Release{
-		Release: release,
+		Release: helmRelease,
 	}
 
+	cacheResult.SetHeaders(w)
 	c.WriteResult(w, r, res)
 }
 
@@ -11,8 +11,10 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
 	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/coalesce"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
 )
 
 type PorterAppHelmReleaseHistoryGetHandler struct {
@@ -52,12 +54,20 @@ func (c *PorterAppHelmReleaseHistoryGetHandler) ServeHTTP(w http.ResponseWriter,
 		return
 	}
 
-	history, err := helmAgent.GetReleaseHistory(ctx, appName)
+	history, cacheResult, err := coalesce.Do(c.Config().StatusQueryCoalescer, coalesce.Key{
+		ClusterID: cluster.ID,
+		Namespace: namespace,
+		StackName: appName,
+		QueryType: coalesce.QueryType_ReleaseHistory,
+	}, coalesce.OptionsFromRequest(r), func() ([]*release.Release, error) {
+		return helmAgent.GetReleaseHistory(ctx, appName)
+	})
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "error getting helm release history")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
 
+	cacheResult.SetHeaders(w)
 	c.WriteResult(w, r, history)
 }
@@ -0,0 +1,199 @@
+package porter_app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/helmimport"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ImportReleaseHandler handles POST requests to the /apps/import endpoint
+type ImportReleaseHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewImportReleaseHandler returns a new ImportReleaseHandler
+func NewImportReleaseHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ImportReleaseHandler {
+	return &ImportReleaseHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ImportReleaseRequest is the request object for the POST /apps/import endpoint
+type ImportReleaseRequest struct {
+	ReleaseName string `json:"release_name" form:"required"`
+	Namespace   string `json:"namespace" form:"required"`
+	// AppName is the name of the porter app the release is imported as. Defaults to the release name
+	AppName string `json:"app_name" form:"omitempty,dns1123"`
+}
+
+// ImportReleaseResponse is the response object for the POST /apps/import endpoint
+type ImportReleaseResponse struct {
+	App     *types.PorterApp        `json:"app"`
+	Import  types.HelmReleaseImport `json:"import"`
+	Release types.ImportableRelease `json:"release"`
+}
+
+// ServeHTTP imports a helm release which was not deployed by Porter as a porter app, without redeploying it
+func (c *ImportReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-import-release")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &ImportReleaseRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	appName := request.AppName
+	if appName == "" {
+		appName = request.ReleaseName
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "release-name", Value: request.ReleaseName},
+		telemetry.AttributeKV{Key: "namespace", Value: request.Namespace},
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+	)
+
+	appName, nameErr := normalizeAppName(appName, c.Config().ServerConf.LowercasePorterAppNames)
+	if nameErr != nil {
+		err := telemetry.Error(ctx, span, nameErr, "invalid app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if errStrs := validation.IsDNS1123Label(appName); len(errStrs) > 0 {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("invalid app name %s: %s", appName, strings.Join(errStrs, ", ")))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	_, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err == nil {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("an app named %s already exists in this cluster, choose a different app name", appName))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, request.Namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rel, err := helmAgent.GetRelease(ctx, request.ReleaseName, 0, false)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "helm release not found")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return
+	}
+
+	imports, err := c.Repo().HelmReleaseImport().ListHelmReleaseImportsByClusterID(ctx, project.ID, cluster.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing helm release imports")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	_, err = c.Repo().Release().ReadRelease(cluster.ID, rel.Name, rel.Namespace)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading release")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+	if err == nil || helmimport.NewManagedReleases(imports).Manages(rel) {
+		err := telemetry.Error(ctx, span, nil, "release is already managed by Porter")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	importable := helmimport.Classify(rel)
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "chart", Value: importable.Chart},
+		telemetry.AttributeKV{Key: "mode", Value: string(importable.Mode)},
+		telemetry.AttributeKV{Key: "importable", Value: importable.Importable},
+	)
+	if !importable.Importable {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("release cannot be imported: %s", strings.Join(importable.Reasons, "; ")))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	app := &models.PorterApp{
+		Name:      appName,
+		ProjectID: project.ID,
+		ClusterID: cluster.ID,
+	}
+	for _, service := range importable.Services {
+		if service.ImageRepository != "" {
+			app.ImageRepoURI = service.ImageRepository
+			break
+		}
+	}
+
+	app, err = c.Repo().PorterApp().CreatePorterApp(app)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmImport, err := c.Repo().HelmReleaseImport().CreateHelmReleaseImport(ctx, &models.HelmReleaseImport{
+		ProjectID:        project.ID,
+		ClusterID:        cluster.ID,
+		PorterAppID:      app.ID,
+		ReleaseName:      rel.Name,
+		Namespace:        rel.Namespace,
+		ChartName:        importable.Chart,
+		ChartVersion:     importable.ChartVersion,
+		Mode:             string(importable.Mode),
+		ImportedRevision: rel.Version,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating helm release import")
+		if _, deleteErr := c.Repo().PorterApp().DeletePorterApp(app); deleteErr != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "delete-porter-app-error", Value: deleteErr.Error()})
+		}
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := helmimport.LabelImportedRelease(ctx, helmAgent.K8sAgent.Clientset, rel, appName); err != nil {
+		// the import is recorded in the database, so a missing label only loses the marker in the cluster
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "label-imported-release-error", Value: err.Error()})
+	}
+
+	c.WriteResult(w, r, ImportReleaseResponse{
+		App:     app.ToPorterAppType(),
+		Import:  helmImport.ToHelmReleaseImportType(),
+		Release: importable,
+	})
+}
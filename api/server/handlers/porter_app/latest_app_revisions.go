@@ -114,17 +114,12 @@ func (c *LatestAppRevisionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 			return
 		}
 
-		porterApp, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, revision.App.Name)
+		porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, revision.App.Name)
 		if err != nil {
 			err := telemetry.Error(ctx, span, err, "error reading porter app")
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
-		if porterApp == nil {
-			err := telemetry.Error(ctx, span, err, "porter app is nil")
-			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-			return
-		}
 
 		deploymentTarget, ok := deploymentTargets[encodedRevision.DeploymentTarget.ID]
 		if !ok {
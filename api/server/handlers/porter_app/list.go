@@ -12,23 +12,30 @@ import (
 )
 
 type PorterAppListHandler struct {
-	handlers.PorterHandlerWriter
+	handlers.PorterHandlerReadWriter
 }
 
 func NewPorterAppListHandler(
 	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
 	writer shared.ResultWriter,
 ) *PorterAppListHandler {
 	return &PorterAppListHandler{
-		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
 	}
 }
 
 func (p *PorterAppListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
 	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
 
-	porterApps, err := p.Repo().PorterApp().ListPorterAppByClusterID(cluster.ID)
+	request := &types.ListPorterAppRequest{}
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	porterApps, err := p.Repo().PorterApp().ListScopedPorterAppsByClusterID(project.ID, cluster.ID)
 	if err != nil {
 		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
@@ -37,6 +44,9 @@ func (p *PorterAppListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	res := make(types.ListPorterAppResponse, 0)
 
 	for _, porterApp := range porterApps {
+		if !porterApp.Tags.HasAll(request.Tags) {
+			continue
+		}
 		res = append(res, porterApp.ToPorterAppType())
 	}
 
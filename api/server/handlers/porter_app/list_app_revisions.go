@@ -1,6 +1,7 @@
 package porter_app
 
 import (
+	"errors"
 	"net/http"
 
 	"connectrpc.com/connect"
@@ -16,6 +17,7 @@ import (
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/porter_app"
 	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
 )
 
 // ListAppRevisionsHandler handles requests to the /apps/{porter_app_name}/revisions endpoint
@@ -62,17 +64,17 @@ func (c *ListAppRevisionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 	}
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "application-name", Value: appName})
 
-	app, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
 		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
-	if app.ID == 0 {
-		err = telemetry.Error(ctx, span, nil, "app with name does not exist in project")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
-		return
-	}
 
 	request := &ListAppRevisionsRequest{}
 	if ok := c.DecodeAndValidate(w, r, request); !ok {
@@ -11,7 +11,6 @@ import (
 	"github.com/bradleyfalzon/ghinstallation/v2"
 	"github.com/google/go-github/v41/github"
 	"github.com/google/uuid"
-	"github.com/gorilla/schema"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
@@ -19,21 +18,25 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
-	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
+	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/telemetry"
 	"gorm.io/gorm"
 )
 
+// defaultEventPageSize is the number of events on a page when a request does not set per_page
+const defaultEventPageSize = 20
+
 type PorterAppEventListHandler struct {
-	handlers.PorterHandlerWriter
+	handlers.PorterHandlerReadWriter
 }
 
 func NewPorterAppEventListHandler(
 	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
 	writer shared.ResultWriter,
 ) *PorterAppEventListHandler {
 	return &PorterAppEventListHandler{
-		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
 	}
 }
 
@@ -52,29 +55,50 @@ func (p *PorterAppEventListHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	pr := types.PaginationRequest{}
-	d := schema.NewDecoder()
-	err := d.Decode(&pr, r.URL.Query())
-	if err != nil {
+	request := &types.ListPorterAppEventsRequest{}
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
 		e := telemetry.Error(ctx, span, nil, "error decoding request")
 		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(e, http.StatusBadRequest))
 		return
 	}
 
-	app, err := p.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = defaultEventPageSize
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "page", Value: request.Page},
+		telemetry.AttributeKV{Key: "per-page", Value: request.PerPage},
+		telemetry.AttributeKV{Key: "type", Value: string(request.Type)},
+		telemetry.AttributeKV{Key: "status", Value: string(request.Status)},
+	)
+
+	app, err := p.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
 		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
 	}
 
 	// legacy app events will have a nil deployment target id
-	porterAppEvents, paginatedResult, err := p.Repo().PorterAppEvent().ListEventsByPorterAppIDAndDeploymentTargetID(ctx, app.ID, uuid.Nil, helpers.WithPageSize(20), helpers.WithPage(int(pr.Page)))
+	legacyDeploymentTargetID := uuid.Nil
+	porterAppEvents, total, err := p.Repo().PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, app.ID, repository.PorterAppEventFilter{
+		Type:               string(request.Type),
+		Status:             string(request.Status),
+		DeploymentTargetID: &legacyDeploymentTargetID,
+		Limit:              request.PerPage,
+		Offset:             (request.Page - 1) * request.PerPage,
+	})
 	if err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			e := telemetry.Error(ctx, span, nil, "error listing porter app events by porter app id")
-			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(e, http.StatusBadRequest))
-			return
-		}
+		e := telemetry.Error(ctx, span, err, "error listing porter app events by porter app id")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(e))
+		return
 	}
 
 	for idx, appEvent := range porterAppEvents {
@@ -87,13 +111,10 @@ func (p *PorterAppEventListHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	res := struct {
-		Events []types.PorterAppEvent `json:"events"`
-		types.PaginationResponse
-	}{
-		PaginationResponse: types.PaginationResponse(paginatedResult),
+	res := types.ListPorterAppEventsResponse{
+		Events:             make([]types.PorterAppEvent, 0, len(porterAppEvents)),
+		PaginationResponse: eventPagination(total, request.Page, request.PerPage),
 	}
-	res.Events = make([]types.PorterAppEvent, 0)
 
 	for _, porterApp := range porterAppEvents {
 		if porterApp == nil {
@@ -105,6 +126,20 @@ func (p *PorterAppEventListHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 	p.WriteResult(w, r, res)
 }
 
+// eventPagination describes the page of a listing of total events, matching how helpers.Paginate numbers pages
+func eventPagination(total int64, page int, perPage int) types.PaginationResponse {
+	pagination := types.PaginationResponse{
+		NumPages:    (total + int64(perPage) - 1) / int64(perPage),
+		CurrentPage: int64(page),
+		NextPage:    int64(page + 1),
+	}
+	if pagination.CurrentPage >= pagination.NumPages {
+		pagination.NextPage = pagination.NumPages
+	}
+
+	return pagination
+}
+
 func (p *PorterAppEventListHandler) updateExistingAppEvent(
 	ctx context.Context,
 	cluster models.Cluster,
@@ -0,0 +1,117 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// defaultEventListLimit is the number of events listed when a request does not set a limit
+const defaultEventListLimit = 20
+
+// ListPorterAppEventsHandler lists the events of an app, newest first, filtered by type, status and message
+type ListPorterAppEventsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewListPorterAppEventsHandler returns a new ListPorterAppEventsHandler
+func NewListPorterAppEventsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListPorterAppEventsHandler {
+	return &ListPorterAppEventsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ListPorterAppEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-filtered-porter-app-events")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.ListFilteredPorterAppEventsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if request.Limit == 0 {
+		request.Limit = defaultEventListLimit
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-name", Value: appName},
+		telemetry.AttributeKV{Key: "type", Value: string(request.Type)},
+		telemetry.AttributeKV{Key: "status", Value: string(request.Status)},
+		telemetry.AttributeKV{Key: "search", Value: request.Search},
+		telemetry.AttributeKV{Key: "limit", Value: request.Limit},
+		telemetry.AttributeKV{Key: "offset", Value: request.Offset},
+	)
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Read); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	events, total, err := c.Repo().PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, app.ID, repository.PorterAppEventFilter{
+		Type:   string(request.Type),
+		Status: string(request.Status),
+		Search: request.Search,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing porter app events")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := types.ListFilteredPorterAppEventsResponse{
+		Events: make([]types.PorterAppEvent, 0, len(events)),
+		Total:  total,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		res.Events = append(res.Events, event.ToPorterAppEvent())
+	}
+
+	c.WriteResult(w, r, res)
+}
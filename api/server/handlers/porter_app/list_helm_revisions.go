@@ -0,0 +1,137 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/coalesce"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+)
+
+// ListHelmRevisionsHandler lists the revisions of the helm release of a porter app, newest first. Unlike the activity
+// feed, which only holds the events Porter created, this is the history helm has of the release.
+type ListHelmRevisionsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewListHelmRevisionsHandler returns a new ListHelmRevisionsHandler
+func NewListHelmRevisionsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListHelmRevisionsHandler {
+	return &ListHelmRevisionsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ListHelmRevisionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-helm-revisions")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.ListHelmRevisionsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error getting stack name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "application-name", Value: appName},
+		telemetry.AttributeKV{Key: "limit", Value: request.Limit},
+	)
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	history, cacheResult, err := coalesce.Do(c.Config().StatusQueryCoalescer, coalesce.Key{
+		ClusterID: cluster.ID,
+		Namespace: namespace,
+		StackName: appName,
+		QueryType: coalesce.QueryType_ReleaseHistory,
+	}, coalesce.OptionsFromRequest(r), func() ([]*release.Release, error) {
+		return helmAgent.GetReleaseHistory(ctx, appName)
+	})
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			err = telemetry.Error(ctx, span, err, "app has no helm release")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error getting helm release history")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	cacheResult.SetHeaders(w)
+	c.WriteResult(w, r, types.ListHelmRevisionsResponse{
+		Revisions: helmRevisions(history, int(request.Limit)),
+	})
+}
+
+// helmRevisions returns up to limit revisions of a release history, newest first. A limit of 0 returns all of them.
+func helmRevisions(history []*release.Release, limit int) []types.HelmRevision {
+	sorted := make([]*release.Release, 0, len(history))
+	for _, rel := range history {
+		if rel != nil {
+			sorted = append(sorted, rel)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version > sorted[j].Version
+	})
+
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+
+	revisions := make([]types.HelmRevision, 0, len(sorted))
+	for _, rel := range sorted {
+		revision := types.HelmRevision{
+			Revision:  rel.Version,
+			ImageInfo: attemptToGetImageInfoFromRelease(rel.Config),
+		}
+
+		if rel.Chart != nil && rel.Chart.Metadata != nil {
+			revision.ChartVersion = rel.Chart.Metadata.Version
+			revision.AppVersion = rel.Chart.Metadata.AppVersion
+		}
+
+		if rel.Info != nil {
+			revision.DeployedAt = rel.Info.LastDeployed.Time
+			revision.Status = rel.Info.Status.String()
+			revision.Description = rel.Info.Description
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions
+}
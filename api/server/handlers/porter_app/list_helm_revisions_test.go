@@ -0,0 +1,50 @@
+package porter_app
+
+import (
+	"testing"
+
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+func TestHelmRevisions(t *testing.T) {
+	withImage := func(rel *release.Release, tag string) *release.Release {
+		rel.Config = map[string]any{
+			"global": map[string]any{
+				"image": map[string]any{"repository": "registry.example.com:5000/payments", "tag": tag},
+			},
+		}
+		return rel
+	}
+
+	history := []*release.Release{
+		withImage(deployedAt("payments", 1, 0, release.StatusSuperseded), "v1"),
+		withImage(deployedAt("payments", 3, 20, release.StatusDeployed), "v3"),
+		withImage(deployedAt("payments", 2, 10, release.StatusFailed), "v2"),
+	}
+
+	revisions := helmRevisions(history, 0)
+	if len(revisions) != 3 {
+		t.Fatalf("expected every revision without a limit, got %d", len(revisions))
+	}
+	for i, expected := range []int{3, 2, 1} {
+		if revisions[i].Revision != expected {
+			t.Errorf("expected revision %d at %d, got %d", expected, i, revisions[i].Revision)
+		}
+	}
+
+	latest := revisions[0]
+	if latest.Status != "deployed" || latest.ChartVersion != "0.1.0" {
+		t.Errorf("unexpected status or chart version %+v", latest)
+	}
+	if latest.ImageInfo.Repository != "registry.example.com:5000/payments" || latest.ImageInfo.Tag != "v3" {
+		t.Errorf("expected the image to be read from the values, got %+v", latest.ImageInfo)
+	}
+	if !latest.DeployedAt.After(revisions[1].DeployedAt) {
+		t.Errorf("expected the deploy times to be kept, got %s and %s", latest.DeployedAt, revisions[1].DeployedAt)
+	}
+
+	limited := helmRevisions(history, 2)
+	if len(limited) != 2 || limited[0].Revision != 3 || limited[1].Revision != 2 {
+		t.Errorf("expected the 2 newest revisions, got %+v", limited)
+	}
+}
@@ -0,0 +1,131 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/helmimport"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// importableReleaseStatuses are the statuses of the releases listed for import. Releases which are not deployed are
+// listed so that the reason they cannot be imported is shown
+var importableReleaseStatuses = []string{
+	"deployed",
+	"pending",
+	"pending-install",
+	"pending-upgrade",
+	"pending-rollback",
+	"failed",
+}
+
+// ListImportableReleasesHandler handles GET requests to the /apps/import/releases endpoint
+type ListImportableReleasesHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewListImportableReleasesHandler returns a new ListImportableReleasesHandler
+func NewListImportableReleasesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListImportableReleasesHandler {
+	return &ListImportableReleasesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ListImportableReleasesRequest is the request object for the GET /apps/import/releases endpoint
+type ListImportableReleasesRequest struct {
+	// Namespace limits the releases to a single namespace. If empty, releases in every namespace are listed
+	Namespace string `schema:"namespace"`
+}
+
+// ListImportableReleasesResponse is the response object for the GET /apps/import/releases endpoint
+type ListImportableReleasesResponse struct {
+	Releases []types.ImportableRelease `json:"releases"`
+}
+
+// ServeHTTP lists the helm releases in a cluster which were not deployed by Porter, and whether each can be imported as a porter app
+func (c *ListImportableReleasesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-importable-releases")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &ListImportableReleasesRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "namespace", Value: request.Namespace})
+
+	imports, err := c.Repo().HelmReleaseImport().ListHelmReleaseImportsByClusterID(ctx, project.ID, cluster.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing helm release imports")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+	managed := helmimport.NewManagedReleases(imports)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, request.Namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	releases, err := helmAgent.ListReleases(ctx, request.Namespace, &types.ReleaseListFilter{
+		StatusFilter: importableReleaseStatuses,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing helm releases")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := ListImportableReleasesResponse{
+		Releases: make([]types.ImportableRelease, 0),
+	}
+	for _, rel := range releases {
+		if managed.Manages(rel) {
+			continue
+		}
+
+		// releases deployed through Porter's release endpoints are already managed by Porter
+		_, err := c.Repo().Release().ReadRelease(cluster.ID, rel.Name, rel.Namespace)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "error reading release")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		res.Releases = append(res.Releases, helmimport.Classify(rel))
+	}
+
+	sort.Slice(res.Releases, func(i, j int) bool {
+		if res.Releases[i].Namespace != res.Releases[j].Namespace {
+			return res.Releases[i].Namespace < res.Releases[j].Namespace
+		}
+		return res.Releases[i].Name < res.Releases[j].Name
+	})
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "importable-releases", Value: len(res.Releases)})
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,81 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// ListLogAlertRulesHandler handles GET requests to the /apps/{porter_app_name}/alert-rules endpoint
+type ListLogAlertRulesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListLogAlertRulesHandler returns a new ListLogAlertRulesHandler
+func NewListLogAlertRulesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListLogAlertRulesHandler {
+	return &ListLogAlertRulesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ListLogAlertRulesResponse is the response object for the GET /apps/{porter_app_name}/alert-rules endpoint
+type ListLogAlertRulesResponse struct {
+	Rules []types.LogAlertRule `json:"rules"`
+}
+
+// ServeHTTP lists the log alert rules on an app
+func (c *ListLogAlertRulesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-log-alert-rules")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing app name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-name", Value: appName})
+
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rules, err := c.Repo().LogAlertRule().ListLogAlertRulesByPorterAppID(ctx, project.ID, app.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing log alert rules")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := ListLogAlertRulesResponse{
+		Rules: make([]types.LogAlertRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		res.Rules = append(res.Rules, rule.ToLogAlertRuleType())
+	}
+
+	c.WriteResult(w, r, res)
+}
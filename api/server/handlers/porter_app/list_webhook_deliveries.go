@@ -0,0 +1,146 @@
+package porter_app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// defaultWebhookDeliveriesLimit is the number of delivery attempts returned if the request does not set a limit
+const defaultWebhookDeliveriesLimit = 50
+
+// ListWebhookDeliveriesHandler handles GET requests to the /apps/{porter_app_name}/alert-rules/{log_alert_rule_id}/deliveries endpoint
+type ListWebhookDeliveriesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewListWebhookDeliveriesHandler returns a new ListWebhookDeliveriesHandler
+func NewListWebhookDeliveriesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListWebhookDeliveriesHandler {
+	return &ListWebhookDeliveriesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP lists the attempts at delivering the alerts of a log alert rule to its webhook, with the rule's success
+// rate over the last day
+func (c *ListWebhookDeliveriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-webhook-deliveries")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.ListWebhookDeliveriesRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	filter := repository.WebhookDeliveryFilter{Limit: defaultWebhookDeliveriesLimit}
+	if request.Limit > 0 {
+		filter.Limit = int(request.Limit)
+	}
+	switch request.Status {
+	case "":
+	case types.WebhookDeliveryStatus_Succeeded, types.WebhookDeliveryStatus_Failed:
+		succeeded := request.Status == types.WebhookDeliveryStatus_Succeeded
+		filter.Succeeded = &succeeded
+	default:
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("status must be %q or %q", types.WebhookDeliveryStatus_Succeeded, types.WebhookDeliveryStatus_Failed))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing app name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	ruleID, reqErr := requestutils.GetURLParamUint(r, types.URLParamLogAlertRuleID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing log alert rule id from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "log-alert-rule-id", Value: ruleID},
+		telemetry.AttributeKV{Key: "status", Value: string(request.Status)},
+		telemetry.AttributeKV{Key: "limit", Value: filter.Limit},
+	)
+
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rule, err := c.Repo().LogAlertRule().ReadLogAlertRule(ctx, project.ID, app.ID, ruleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "log alert rule not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	deliveries, err := c.Repo().WebhookDelivery().ListWebhookDeliveries(ctx, project.ID, rule.ID, filter)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing webhook deliveries")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	attempts, succeeded, err := c.Repo().WebhookDelivery().CountWebhookDeliveries(ctx, project.ID, rule.ID, since)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error counting webhook deliveries")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := types.ListWebhookDeliveriesResponse{
+		Deliveries: make([]types.WebhookDelivery, 0, len(deliveries)),
+		Stats: types.WebhookDeliveryStats{
+			Since:     since,
+			Attempts:  attempts,
+			Succeeded: succeeded,
+		},
+	}
+	if attempts > 0 {
+		res.Stats.SuccessRate = float64(succeeded) / float64(attempts)
+	}
+	for _, delivery := range deliveries {
+		res.Deliveries = append(res.Deliveries, delivery.ToWebhookDeliveryType())
+	}
+
+	c.WriteResult(w, r, res)
+}
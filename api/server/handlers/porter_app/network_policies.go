@@ -0,0 +1,408 @@
+package porter_app
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/capabilities"
+	internalPorterApp "github.com/porter-dev/porter/internal/porter_app"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// networkPolicyKey is the key of the values of a service which holds the NetworkPolicy generated for it, which the
+	// umbrella chart renders in the namespace of the service
+	networkPolicyKey = "porter_network_policy"
+	// serviceNetworkKey is the key of the values of a service which records the network of the service in porter.yaml,
+	// so that its allowances are kept when the values are deployed without porter.yaml, such as on a rollback
+	serviceNetworkKey = "porter_network"
+	// labelKeyNamespaceName is the label kubernetes sets on every namespace to its name
+	labelKeyNamespaceName = "kubernetes.io/metadata.name"
+)
+
+// networkPoliciesTemplate renders the network policies in the values of the services of an umbrella chart
+const networkPoliciesTemplate = `{{- range $name, $service := .Values }}
+{{- if and (kindIs "map" $service) (hasKey $service "porter_network_policy") }}
+---
+{{ toYaml (index $service "porter_network_policy") }}
+{{- end }}
+{{- end }}
+`
+
+// ServiceNetwork allows traffic to and from a service beyond what the network policy Porter generates for it allows,
+// on clusters which have network policies enabled
+type ServiceNetwork struct {
+	// AllowIngress are the peers which can reach the service
+	AllowIngress []NetworkPeer `yaml:"allow_ingress,omitempty" json:"allow_ingress,omitempty"`
+	// AllowEgress are the peers which the service can reach
+	AllowEgress []NetworkPeer `yaml:"allow_egress,omitempty" json:"allow_egress,omitempty"`
+}
+
+// NetworkPeer is a range of addresses or a namespace, which sets exactly one of CIDR and Namespace
+type NetworkPeer struct {
+	CIDR      string `yaml:"cidr,omitempty" json:"cidr,omitempty"`
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	// Ports are the TCP ports the traffic is allowed on. Every port is allowed if it is empty.
+	Ports []int `yaml:"ports,omitempty" json:"ports,omitempty"`
+}
+
+// networkPolicyConf is what the network policies of an app are generated from, along with its values
+type networkPolicyConf struct {
+	enabled          bool
+	appName          string
+	appNamespace     string
+	ingressNamespace string
+	capabilities     *types.ClusterCapabilities
+}
+
+// setServiceNetwork records the network of a service from porter.yaml in its values, or removes it if porter.yaml no
+// longer sets one
+func setServiceNetwork(serviceValues map[string]interface{}, network *ServiceNetwork) {
+	if network == nil || (len(network.AllowIngress) == 0 && len(network.AllowEgress) == 0) {
+		delete(serviceValues, serviceNetworkKey)
+		return
+	}
+
+	by, err := json.Marshal(network)
+	if err != nil {
+		return
+	}
+
+	recorded := map[string]interface{}{}
+	if err := json.Unmarshal(by, &recorded); err != nil {
+		return
+	}
+
+	serviceValues[serviceNetworkKey] = recorded
+}
+
+// serviceNetworkFromValues returns the network of a service recorded in its values, or nil if none is recorded
+func serviceNetworkFromValues(serviceValues map[string]interface{}) *ServiceNetwork {
+	recorded, ok := serviceValues[serviceNetworkKey]
+	if !ok {
+		return nil
+	}
+
+	by, err := json.Marshal(convertMap(recorded))
+	if err != nil {
+		return nil
+	}
+
+	network := &ServiceNetwork{}
+	if err := json.Unmarshal(by, network); err != nil {
+		return nil
+	}
+
+	return network
+}
+
+// networkService is a service of an app which a network policy selects or allows traffic to and from
+type networkService struct {
+	name        string
+	helmName    string
+	serviceType string
+	namespace   string
+	// selector are the labels of the pods of the service, or nil if they cannot be selected
+	selector map[string]string
+	// port is the port the container of the service listens on, or zero if it does not set one
+	port int
+}
+
+// setNetworkPolicies generates a NetworkPolicy for every service of an app, which denies all traffic to and from the
+// service except DNS, traffic from the ingress controller to web services, traffic between services which link to each
+// other and the allowances set in porter.yaml. Services link to each other by setting an env variable to the address of
+// another service, such as http://app-web-web:8080. The policies of earlier deploys are removed if network policies
+// are not enabled. The release job runs outside of the umbrella chart, so no policy is generated for it.
+func setNetworkPolicies(values map[string]interface{}, conf networkPolicyConf) []string {
+	helmNames := make([]string, 0, len(values))
+	for helmName, serviceValues := range values {
+		if _, ok := serviceValues.(map[string]interface{}); ok && helmName != "global" {
+			helmNames = append(helmNames, helmName)
+		}
+	}
+	sort.Strings(helmNames)
+
+	if !conf.enabled {
+		for _, helmName := range helmNames {
+			delete(values[helmName].(map[string]interface{}), networkPolicyKey)
+		}
+		return nil
+	}
+
+	var warnings []string
+	if !capabilities.Known(conf.capabilities, types.ClusterCapabilityProbe_NetworkPolicy) || conf.capabilities.NetworkPolicyEnforcer == "" {
+		warnings = append(warnings, "no network plugin which enforces network policies was detected on the cluster, so the network policies of the app may not restrict any traffic")
+	}
+
+	namespaces := servicesByNamespace(values, conf.appNamespace)
+	services := make(map[string]*networkService, len(helmNames))
+	for _, helmName := range helmNames {
+		serviceValues := values[helmName].(map[string]interface{})
+		name, serviceType := getServiceNameAndTypeFromHelmName(helmName)
+
+		service := &networkService{
+			name:        name,
+			helmName:    helmName,
+			serviceType: serviceType,
+			namespace:   namespaces[helmName],
+			port:        containerPort(serviceValues),
+		}
+
+		podLabels, _ := serviceValues["podLabels"].(map[string]interface{})
+		selector := make(map[string]string)
+		for _, key := range []string{internalPorterApp.LabelKey_PartOf, internalPorterApp.LabelKey_Name} {
+			if value, ok := podLabels[key].(string); ok && value != "" {
+				selector[key] = value
+			}
+		}
+		if len(selector) == 2 {
+			service.selector = selector
+		} else {
+			warnings = append(warnings, fmt.Sprintf("the pods of service %s do not have the %s and %s labels, so no network policy was generated for it", name, internalPorterApp.LabelKey_PartOf, internalPorterApp.LabelKey_Name))
+		}
+
+		services[helmName] = service
+	}
+
+	links, linkWarnings := serviceLinks(values, services, conf.appName)
+	warnings = append(warnings, linkWarnings...)
+
+	for _, helmName := range helmNames {
+		serviceValues := values[helmName].(map[string]interface{})
+		service := services[helmName]
+		if service.selector == nil {
+			delete(serviceValues, networkPolicyKey)
+			continue
+		}
+
+		policy := networkPolicy(service, services, links, serviceNetworkFromValues(serviceValues), conf)
+
+		converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(policy)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("error generating the network policy of service %s: %s", service.name, err))
+			delete(serviceValues, networkPolicyKey)
+			continue
+		}
+		delete(converted["metadata"].(map[string]interface{}), "creationTimestamp")
+
+		serviceValues[networkPolicyKey] = converted
+	}
+
+	return warnings
+}
+
+// serviceLinks returns the ports each service links to each other service on, by the helm names of both. A link without
+// a port is to the port of the service it links to.
+func serviceLinks(values map[string]interface{}, services map[string]*networkService, appName string) (map[string]map[string][]int, []string) {
+	patterns := make(map[string]*regexp.Regexp, len(services))
+	for helmName := range services {
+		host := fmt.Sprintf("%s-%s", appName, helmName)
+		patterns[helmName] = regexp.MustCompile(`(?:^|[/@])` + regexp.QuoteMeta(host) + `(?:\.[a-z0-9-]+\.svc\.cluster\.local)?(?::([0-9]+))?(?:$|[:/])`)
+	}
+
+	links := make(map[string]map[string][]int)
+	var warnings []string
+
+	for helmName, source := range services {
+		env, err := getNestedMap(values, helmName, "container", "env", "normal")
+		if err != nil {
+			continue
+		}
+
+		for target, pattern := range patterns {
+			if target == helmName {
+				continue
+			}
+
+			ports := make(map[int]bool)
+			for _, value := range env {
+				value, ok := value.(string)
+				if !ok {
+					continue
+				}
+
+				for _, match := range pattern.FindAllStringSubmatch(value, -1) {
+					port, _ := strconv.Atoi(match[1])
+					if port == 0 {
+						port = services[target].port
+					}
+					if port == 0 {
+						warnings = append(warnings, fmt.Sprintf("service %s links to service %s, which does not set a port, so the network policies do not allow the link", source.name, services[target].name))
+						continue
+					}
+					ports[port] = true
+				}
+			}
+			if len(ports) == 0 {
+				continue
+			}
+
+			if links[helmName] == nil {
+				links[helmName] = make(map[string][]int)
+			}
+			for port := range ports {
+				links[helmName][target] = append(links[helmName][target], port)
+			}
+			sort.Ints(links[helmName][target])
+		}
+	}
+	sort.Strings(warnings)
+
+	return links, warnings
+}
+
+// networkPolicy returns the NetworkPolicy of a single service
+func networkPolicy(service *networkService, services map[string]*networkService, links map[string]map[string][]int, network *ServiceNetwork, conf networkPolicyConf) *networkingv1.NetworkPolicy {
+	ingress := []networkingv1.NetworkPolicyIngressRule{}
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				networkPolicyPort(corev1.ProtocolUDP, 53),
+				networkPolicyPort(corev1.ProtocolTCP, 53),
+			},
+		},
+	}
+
+	if service.serviceType == "web" && service.port != 0 && conf.ingressNamespace != "" {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  []networkingv1.NetworkPolicyPeer{namespacePeer(conf.ingressNamespace)},
+			Ports: tcpPorts([]int{service.port}),
+		})
+	}
+
+	for _, helmName := range sortedKeys(services) {
+		other := services[helmName]
+		if other.selector == nil {
+			continue
+		}
+
+		if ports, ok := links[helmName][service.helmName]; ok {
+			ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+				From:  []networkingv1.NetworkPolicyPeer{servicePeer(other)},
+				Ports: tcpPorts(ports),
+			})
+		}
+		if ports, ok := links[service.helmName][helmName]; ok {
+			egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+				To:    []networkingv1.NetworkPolicyPeer{servicePeer(other)},
+				Ports: tcpPorts(ports),
+			})
+		}
+	}
+
+	if network != nil {
+		for _, peer := range network.AllowIngress {
+			if policyPeer, ok := networkPeer(peer); ok {
+				ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{From: []networkingv1.NetworkPolicyPeer{policyPeer}, Ports: tcpPorts(peer.Ports)})
+			}
+		}
+		for _, peer := range network.AllowEgress {
+			if policyPeer, ok := networkPeer(peer); ok {
+				egress = append(egress, networkingv1.NetworkPolicyEgressRule{To: []networkingv1.NetworkPolicyPeer{policyPeer}, Ports: tcpPorts(peer.Ports)})
+			}
+		}
+	}
+
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("%s-%s", conf.appName, service.helmName),
+			Labels: service.selector,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: service.selector},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress:     ingress,
+			Egress:      egress,
+		},
+	}
+}
+
+// servicePeer selects the pods of another service of the app, in its namespace
+func servicePeer(service *networkService) networkingv1.NetworkPolicyPeer {
+	peer := namespacePeer(service.namespace)
+	peer.PodSelector = &metav1.LabelSelector{MatchLabels: service.selector}
+
+	return peer
+}
+
+func namespacePeer(namespace string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{labelKeyNamespaceName: namespace}},
+	}
+}
+
+// networkPeer returns the peer of an allowance set in porter.yaml, which is checked when porter.yaml is linted
+func networkPeer(peer NetworkPeer) (networkingv1.NetworkPolicyPeer, bool) {
+	switch {
+	case peer.CIDR != "":
+		return networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: peer.CIDR}}, true
+	case peer.Namespace != "":
+		return namespacePeer(peer.Namespace), true
+	default:
+		return networkingv1.NetworkPolicyPeer{}, false
+	}
+}
+
+func tcpPorts(ports []int) []networkingv1.NetworkPolicyPort {
+	if len(ports) == 0 {
+		return nil
+	}
+
+	res := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		res = append(res, networkPolicyPort(corev1.ProtocolTCP, port))
+	}
+
+	return res
+}
+
+func networkPolicyPort(protocol corev1.Protocol, port int) networkingv1.NetworkPolicyPort {
+	portValue := intstr.FromInt(port)
+
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &portValue}
+}
+
+// containerPort returns the port the container of a service listens on, which is a string when set in porter.yaml and
+// a number when read from a release
+func containerPort(serviceValues map[string]interface{}) int {
+	container, ok := serviceValues["container"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	switch port := container["port"].(type) {
+	case int:
+		return port
+	case int64:
+		return int(port)
+	case float64:
+		return int(port)
+	case string:
+		value, _ := strconv.Atoi(port)
+		return value
+	default:
+		return 0
+	}
+}
+
+func sortedKeys(services map[string]*networkService) []string {
+	keys := make([]string, 0, len(services))
+	for key := range services {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,248 @@
+package porter_app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"gopkg.in/yaml.v2"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const networkPoliciesPorterYaml = `version: v1stack
+services:
+  web:
+    type: web
+    run: node index.js
+    network:
+      allow_ingress:
+        - namespace: monitoring
+          ports: [9090]
+    config:
+      container:
+        port: 8080
+  api:
+    type: web
+    run: node api.js
+    namespace: internal
+    config:
+      container:
+        port: 3000
+  worker:
+    type: worker
+    run: node worker.js
+    network:
+      allow_egress:
+        - cidr: 10.20.0.0/16
+          ports: [5432]
+    config:
+      container:
+        env:
+          normal:
+            WEB_URL: http://shop-web-web:8080/hooks
+            API_URL: http://shop-api-web.internal.svc.cluster.local
+`
+
+var networkPoliciesTestCapabilities = &types.ClusterCapabilities{NetworkPolicyEnforcer: "calico"}
+
+func buildNetworkPoliciesTestValues(t *testing.T, porterYaml string, enabled bool, clusterCapabilities *types.ClusterCapabilities) (map[string]interface{}, []string) {
+	t.Helper()
+
+	parsed := &PorterStackYAML{}
+	if err := yaml.Unmarshal([]byte(porterYaml), parsed); err != nil {
+		t.Fatalf("error parsing porter.yaml: %v", err)
+	}
+
+	application := &Application{Env: parsed.Env, Services: parsed.Services}
+	imageInfo := types.ImageInfo{Repository: "registry.example.com/shop", Tag: "8f14e45f"}
+	opts := SubdomainCreateOpts{dryRun: true}
+
+	values, _, err := buildUmbrellaChartValues(context.Background(), application, nil, imageInfo, nil, nil, opts, false, true, false, "porter-stack-shop", false, false, types.ClusterSchedulingDefaults{}, clusterCapabilities, "shop", nil)
+	if err != nil {
+		t.Fatalf("error building values: %v", err)
+	}
+
+	convertedValues := convertMap(values).(map[string]interface{})
+	warnings := setNetworkPolicies(convertedValues, networkPolicyConf{
+		enabled:          enabled,
+		appName:          "shop",
+		appNamespace:     "porter-stack-shop",
+		ingressNamespace: "ingress-nginx",
+		capabilities:     clusterCapabilities,
+	})
+
+	return convertedValues, warnings
+}
+
+func networkPolicyFromValues(t *testing.T, values map[string]interface{}, helmName string) *networkingv1.NetworkPolicy {
+	t.Helper()
+
+	converted, ok := values[helmName].(map[string]interface{})[networkPolicyKey].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a network policy for %s", helmName)
+	}
+
+	policy := &networkingv1.NetworkPolicy{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(converted, policy); err != nil {
+		t.Fatalf("error reading the network policy of %s: %v", helmName, err)
+	}
+
+	return policy
+}
+
+// peerString describes a peer of a rule as its namespace, its pods and its CIDR, so that rules can be compared
+func peerString(peer networkingv1.NetworkPolicyPeer) string {
+	var res string
+	if peer.NamespaceSelector != nil {
+		res += "ns=" + peer.NamespaceSelector.MatchLabels[labelKeyNamespaceName]
+	}
+	if peer.PodSelector != nil {
+		res += " pod=" + peer.PodSelector.MatchLabels["app.kubernetes.io/name"]
+	}
+	if peer.IPBlock != nil {
+		res += "cidr=" + peer.IPBlock.CIDR
+	}
+
+	return res
+}
+
+func rulePorts(ports []networkingv1.NetworkPolicyPort) []int {
+	var res []int
+	for _, port := range ports {
+		res = append(res, port.Port.IntValue())
+	}
+
+	return res
+}
+
+type testNetworkRule struct {
+	peer  string
+	ports []int
+}
+
+func ingressRules(policy *networkingv1.NetworkPolicy) []testNetworkRule {
+	var res []testNetworkRule
+	for _, rule := range policy.Spec.Ingress {
+		res = append(res, testNetworkRule{peer: peerString(rule.From[0]), ports: rulePorts(rule.Ports)})
+	}
+
+	return res
+}
+
+func egressRules(policy *networkingv1.NetworkPolicy) []testNetworkRule {
+	var res []testNetworkRule
+	for _, rule := range policy.Spec.Egress {
+		var peer string
+		if len(rule.To) > 0 {
+			peer = peerString(rule.To[0])
+		}
+		res = append(res, testNetworkRule{peer: peer, ports: rulePorts(rule.Ports)})
+	}
+
+	return res
+}
+
+func TestNetworkPoliciesAllowLinkedServices(t *testing.T) {
+	values, warnings := buildNetworkPoliciesTestValues(t, networkPoliciesPorterYaml, true, networkPoliciesTestCapabilities)
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings on a cluster which enforces network policies, got %v", warnings)
+	}
+
+	worker := networkPolicyFromValues(t, values, "worker-wkr")
+	if worker.Name != "shop-worker-wkr" {
+		t.Errorf("expected the policy to be named after the service, got %s", worker.Name)
+	}
+	if got := worker.Spec.PodSelector.MatchLabels; !reflect.DeepEqual(got, map[string]string{"app.kubernetes.io/part-of": "shop", "app.kubernetes.io/name": "worker"}) {
+		t.Errorf("expected the policy to select the pods of the worker, got %v", got)
+	}
+	if len(worker.Spec.PolicyTypes) != 2 {
+		t.Errorf("expected the policy to deny both ingress and egress, got %v", worker.Spec.PolicyTypes)
+	}
+	if got := ingressRules(worker); len(got) != 0 {
+		t.Errorf("expected nothing to be allowed to reach the worker, got %v", got)
+	}
+	wantEgress := []testNetworkRule{
+		{peer: "", ports: []int{53, 53}},
+		{peer: "ns=internal pod=api", ports: []int{3000}},
+		{peer: "ns=porter-stack-shop pod=web", ports: []int{8080}},
+		{peer: "cidr=10.20.0.0/16", ports: []int{5432}},
+	}
+	if got := egressRules(worker); !reflect.DeepEqual(got, wantEgress) {
+		t.Errorf("expected the worker to reach DNS, the services it links to and its CIDR\nwant %v\ngot  %v", wantEgress, got)
+	}
+
+	web := networkPolicyFromValues(t, values, "web-web")
+	wantIngress := []testNetworkRule{
+		{peer: "ns=ingress-nginx", ports: []int{8080}},
+		{peer: "ns=porter-stack-shop pod=worker", ports: []int{8080}},
+		{peer: "ns=monitoring", ports: []int{9090}},
+	}
+	if got := ingressRules(web); !reflect.DeepEqual(got, wantIngress) {
+		t.Errorf("expected the web service to be reached by the ingress controller, the worker and its namespace\nwant %v\ngot  %v", wantIngress, got)
+	}
+
+	// the policy of a service in another namespace still allows the services of the app namespace which link to it
+	api := networkPolicyFromValues(t, values, "api-web")
+	wantIngress = []testNetworkRule{
+		{peer: "ns=ingress-nginx", ports: []int{3000}},
+		{peer: "ns=porter-stack-shop pod=worker", ports: []int{3000}},
+	}
+	if got := ingressRules(api); !reflect.DeepEqual(got, wantIngress) {
+		t.Errorf("expected the api service to be reached by the ingress controller and the worker\nwant %v\ngot  %v", wantIngress, got)
+	}
+}
+
+func TestNetworkPoliciesWarnWithoutEnforcer(t *testing.T) {
+	capabilities := []*types.ClusterCapabilities{
+		nil,
+		{},
+		{NetworkPolicyEnforcer: "calico", ProbeErrors: map[types.ClusterCapabilityProbe]string{types.ClusterCapabilityProbe_NetworkPolicy: "forbidden"}},
+	}
+
+	for _, clusterCapabilities := range capabilities {
+		values, warnings := buildNetworkPoliciesTestValues(t, networkPoliciesPorterYaml, true, clusterCapabilities)
+		if len(warnings) != 1 {
+			t.Errorf("expected a warning that network policies may not be enforced, got %v", warnings)
+		}
+		networkPolicyFromValues(t, values, "web-web")
+	}
+}
+
+func TestNetworkPoliciesRemovedWhenDisabled(t *testing.T) {
+	values, _ := buildNetworkPoliciesTestValues(t, networkPoliciesPorterYaml, true, networkPoliciesTestCapabilities)
+
+	warnings := setNetworkPolicies(values, networkPolicyConf{appName: "shop", appNamespace: "porter-stack-shop"})
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings when network policies are disabled, got %v", warnings)
+	}
+
+	for _, helmName := range []string{"web-web", "api-web", "worker-wkr"} {
+		if _, ok := values[helmName].(map[string]interface{})[networkPolicyKey]; ok {
+			t.Errorf("expected the network policy of %s to be removed", helmName)
+		}
+	}
+}
+
+func TestServiceNetworkKeptInValues(t *testing.T) {
+	values, _ := buildNetworkPoliciesTestValues(t, networkPoliciesPorterYaml, false, nil)
+
+	if got := serviceNetworkFromValues(values["worker-wkr"].(map[string]interface{})); !reflect.DeepEqual(got, &ServiceNetwork{AllowEgress: []NetworkPeer{{CIDR: "10.20.0.0/16", Ports: []int{5432}}}}) {
+		t.Errorf("expected the network of the worker to be recorded in its values, got %+v", got)
+	}
+	if got := serviceNetworkFromValues(values["api-web"].(map[string]interface{})); got != nil {
+		t.Errorf("expected no network to be recorded for the api service, got %+v", got)
+	}
+
+	exported := false
+	for _, item := range porterYAMLService("web", values["web-web"].(map[string]interface{}), nil) {
+		exported = exported || item.Key == "network"
+	}
+	if !exported {
+		t.Errorf("expected the network of the web service to be exported to porter.yaml")
+	}
+	if _, ok := values["web-web"].(map[string]interface{})[serviceNetworkKey]; ok {
+		t.Errorf("expected the recorded network to be moved out of the values of the web service")
+	}
+}
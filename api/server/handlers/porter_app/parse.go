@@ -11,10 +11,15 @@ import (
 	porterAppUtils "github.com/porter-dev/porter/api/utils/porter_app"
 	"github.com/porter-dev/porter/internal/helm/loader"
 	"github.com/porter-dev/porter/internal/integrations/dns"
+	"github.com/porter-dev/porter/internal/integrations/secretstores"
 	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/capabilities"
 	"github.com/porter-dev/porter/internal/kubernetes/domain"
 	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
 	"github.com/porter-dev/porter/internal/kubernetes/porter_app"
+	internalPorterApp "github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+	"github.com/porter-dev/porter/internal/porter_app/lint"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/telemetry"
 	"github.com/porter-dev/porter/internal/templater/utils"
@@ -55,7 +60,65 @@ type Build struct {
 type Service struct {
 	Run    *string                `yaml:"run"`
 	Config map[string]interface{} `yaml:"config"`
-	Type   *string                `yaml:"type" validate:"required, oneof=web worker job"`
+	Type   *string                `yaml:"type" validate:"required, oneof=web worker job cron"`
+	// Observability opts the service out of the OpenTelemetry env variables of the project
+	Observability *ServiceObservability `yaml:"observability,omitempty"`
+	// Scaling sets the replicas of the service during windows of the week. It is applied by the scaling scheduler
+	// rather than through the helm values of the service.
+	Scaling *types.ServiceScalingSchedule `yaml:"scaling,omitempty"`
+	// Namespace runs the service outside of the namespace of the app. The services of each namespace are installed by
+	// a release of the app in that namespace, which is deployed along with the release of the app.
+	Namespace *string `yaml:"namespace,omitempty"`
+	// CustomDomains are the domains a web service is served on along with its porter subdomain. They are added to the
+	// hosts of its ingress, whose certificates are issued by cert-manager on clusters which have it installed.
+	CustomDomains []string `yaml:"custom_domains,omitempty"`
+	// Network allows traffic to and from the service which the network policy generated for it would deny, on clusters
+	// which have network policies enabled
+	Network *ServiceNetwork `yaml:"network,omitempty"`
+	// Schedule is the cron expression a cron service runs on. Cron services are deployed with the job chart, as a job
+	// whose schedule is set from the fields of the cron service.
+	Schedule *string `yaml:"schedule,omitempty"`
+	// Timezone is the IANA time zone the schedule of a cron service runs in. Defaults to the time zone of the cluster
+	Timezone *string `yaml:"timezone,omitempty"`
+	// ConcurrencyPolicy is whether a run of a cron service starts while the last one is still running: allow, forbid
+	// or replace. Defaults to forbid
+	ConcurrencyPolicy *string `yaml:"concurrency_policy,omitempty"`
+	// History is how many finished runs of a cron service are kept
+	History *CronHistory `yaml:"history,omitempty"`
+	// HealthCheck sets the readiness, liveness and startup probes of a web or worker service. It is named as in v2
+	// porter.yaml files, and replaces config.health when set.
+	HealthCheck *ServiceHealthCheck `yaml:"healthCheck,omitempty"`
+}
+
+// CronHistory is how many finished runs of a cron service are kept, which default to those of the job chart
+type CronHistory struct {
+	Successful *int `yaml:"successful,omitempty"`
+	Failed     *int `yaml:"failed,omitempty"`
+}
+
+// ServiceObservability controls the observability values injected into a service
+type ServiceObservability struct {
+	// Enabled is false to stop the OpenTelemetry env variables being injected. The standard labels are always injected.
+	Enabled *bool `yaml:"enabled"`
+}
+
+// observabilityEnvEnabled reports whether the OpenTelemetry env variables should be injected into a service
+func (s *Service) observabilityEnvEnabled() bool {
+	return s.Observability == nil || s.Observability.Enabled == nil || *s.Observability.Enabled
+}
+
+// isCron reports whether the service is a cron service, which runs on the schedule set in porter.yaml
+func (s *Service) isCron() bool {
+	return s.Type != nil && *s.Type == "cron"
+}
+
+// namespaceOr returns the namespace the service runs in, which is appNamespace unless the service sets another
+func (s *Service) namespaceOr(appNamespace string) string {
+	if s.Namespace == nil || *s.Namespace == "" {
+		return appNamespace
+	}
+
+	return *s.Namespace
 }
 
 type SyncedEnvSection struct {
@@ -75,8 +138,18 @@ type SubdomainCreateOpts struct {
 	dnsClient     *dns.Client
 	appRootDomain string
 	stackName     string
+	// clusterIssuer is the cert-manager ClusterIssuer which issues the certificates of the custom domains of services
+	clusterIssuer string
+	// dryRun skips creating subdomains and syncing environment groups into the namespace, so that values can be built
+	// without changing anything
+	dryRun bool
+	// created records the subdomains created for a new app, so that they are removed if it fails to be created
+	created *createdResources
 }
 
+// annotationIngressClass selects the ingress controller which serves an ingress
+const annotationIngressClass = "kubernetes.io/ingress.class"
+
 type ParseConf struct {
 	// PorterAppName is the name of the porter app
 	PorterAppName string
@@ -85,6 +158,9 @@ type ParseConf struct {
 	// ImageInfo contains the repository and tag of the image to use for the helm upgrade. Kept separate from the PorterYaml because the image info
 	// is stored in the 'global' key of the values, which is not part of the porter yaml
 	ImageInfo types.ImageInfo
+	// ServiceImageInfo is the image of individual services by their name in porter.yaml, which is set in the values of
+	// their chart. Services which are not in it run ImageInfo.
+	ServiceImageInfo map[string]types.ImageInfo
 	// ServerConfig is the server conf, used to find the default helm repo
 	ServerConfig *config.Config
 	// ProjectID
@@ -113,23 +189,68 @@ type ParseConf struct {
 	AddCustomNodeSelector bool
 	// RemoveDeletedServices is a flag to determine whether to remove values and dependencies for services that are not defined in the porter.yaml
 	RemoveDeletedServices bool
+	// SchedulingDefaults are the cluster-level node selector and tolerations, merged into every service at the lowest precedence
+	SchedulingDefaults types.ClusterSchedulingDefaults
+	// Capabilities are the detected capabilities of the target cluster, used to refuse services the cluster cannot run
+	// and to pick the ingress class. If nil, services are deployed without these checks.
+	Capabilities *types.ClusterCapabilities
+	// Observability is the observability config of the project. If set, the OpenTelemetry env variables are injected
+	// into every service which does not opt out. If nil, only the standard labels are injected.
+	Observability *types.ProjectObservabilityConfig
+	// SecretResolver reads the env variables which porter.yaml sets with valueFrom. If nil, a porter.yaml which sets any
+	// is refused.
+	SecretResolver *secretstores.Resolver
+	// DryRun builds the chart and values without writing to the cluster or DNS: no subdomain is created for services
+	// without one, environment groups are not synced into the namespace and secrets set with valueFrom are read but not
+	// written
+	DryRun bool
+	// NetworkPolicies generates a NetworkPolicy for every service, which only allows the traffic Porter knows about. If
+	// false, the policies generated by earlier deploys are removed.
+	NetworkPolicies bool
+	// IngressNamespace is the namespace of the ingress controller, which the network policies of web services allow
+	// traffic from
+	IngressNamespace string
+	// ValuesOnly builds the values without the umbrella chart, whose dependency versions are read from the chart
+	// repository. The returned chart is nil.
+	ValuesOnly bool
 }
 
-func parse(ctx context.Context, conf ParseConf) (*chart.Chart, map[string]interface{}, map[string]interface{}, error) {
+// parse builds the umbrella chart and values of an app from its porter.yaml, and the values of its pre-deploy job if
+// it has one. The warnings are about values porter.yaml sets which stop Porter from injecting its own.
+func parse(ctx context.Context, conf ParseConf) (*chart.Chart, map[string]interface{}, map[string]interface{}, []string, error) {
 	ctx, span := telemetry.NewSpan(ctx, "parse-porter-yaml")
 	defer span.End()
 
+	conf.SubdomainCreateOpts.dryRun = conf.DryRun
+
+	// full helm values replace the porter.yaml entirely, so there is no porter.yaml to validate. The checks are the
+	// same ones porter app lint runs in the CLI.
+	if conf.FullHelmValues == "" {
+		findings := lint.Lint(conf.PorterYaml, lint.Options{AppName: conf.PorterAppName})
+		if err := lint.Errors(findings); err != nil {
+			err = telemetry.Error(ctx, span, err, "invalid porter.yaml")
+			return nil, nil, nil, nil, err
+		}
+	}
+
+	// env variables set with valueFrom are read when the services are built, and never reach the helm values
+	porterYaml, secretRefs, err := envvalues.ExtractSecretReferences(conf.PorterYaml)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading secret references from porter.yaml")
+		return nil, nil, nil, nil, err
+	}
+
 	parsed := &PorterStackYAML{}
-	if err := yaml.Unmarshal(conf.PorterYaml, parsed); err != nil {
+	if err := yaml.Unmarshal(porterYaml, parsed); err != nil {
 		err = telemetry.Error(ctx, span, err, "error parsing porter.yaml")
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	if conf.FullHelmValues != "" {
 		parsedHelmValues, err := convertHelmValuesToPorterYaml(conf.FullHelmValues)
 		if err != nil {
 			err = telemetry.Error(ctx, span, err, "error parsing raw helm values")
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 
 		if parsed.Release != nil && parsed.Release.Run != nil {
@@ -142,21 +263,21 @@ func parse(ctx context.Context, conf ParseConf) (*chart.Chart, map[string]interf
 	synced_env := make([]*SyncedEnvSection, 0)
 
 	for i := range conf.EnvGroups {
-		cm, _, err := conf.SubdomainCreateOpts.k8sAgent.GetLatestVersionedConfigMap(conf.EnvGroups[i], conf.Namespace)
+		cm, _, err := conf.SubdomainCreateOpts.k8sAgent.GetLatestVersionedConfigMap(ctx, conf.EnvGroups[i], conf.Namespace)
 		if err != nil {
 			err = telemetry.Error(ctx, span, err, "error getting latest versioned config map")
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 
 		versionStr, ok := cm.ObjectMeta.Labels["version"]
 		if !ok {
 			err = telemetry.Error(ctx, span, nil, "error extracting version from config map")
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 		versionInt, err := strconv.Atoi(versionStr)
 		if err != nil {
 			err = telemetry.Error(ctx, span, err, "error converting version to int")
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 
 		version := uint(versionInt)
@@ -181,7 +302,7 @@ func parse(ctx context.Context, conf ParseConf) (*chart.Chart, map[string]interf
 
 	if parsed.Apps != nil && parsed.Services != nil {
 		err := telemetry.Error(ctx, span, nil, "'apps' and 'services' are synonymous but both were defined")
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	var services map[string]*Service
@@ -204,31 +325,84 @@ func parse(ctx context.Context, conf ParseConf) (*chart.Chart, map[string]interf
 		Release:  parsed.Release,
 	}
 
-	values, err := buildUmbrellaChartValues(ctx, application, synced_env, conf.ImageInfo, conf.ExistingHelmValues, conf.SubdomainCreateOpts, conf.InjectLauncherToStartCommand, conf.ShouldValidateHelmValues, conf.UserUpdate, conf.Namespace, conf.AddCustomNodeSelector, conf.RemoveDeletedServices)
+	values, warnings, err := buildUmbrellaChartValues(ctx, application, synced_env, conf.ImageInfo, conf.ServiceImageInfo, conf.ExistingHelmValues, conf.SubdomainCreateOpts, conf.InjectLauncherToStartCommand, conf.ShouldValidateHelmValues, conf.UserUpdate, conf.Namespace, conf.AddCustomNodeSelector, conf.RemoveDeletedServices, conf.SchedulingDefaults, conf.Capabilities, conf.PorterAppName, conf.Observability)
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "error building values")
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 	convertedValues, ok := convertMap(values).(map[string]interface{})
 	if !ok {
 		err = telemetry.Error(ctx, span, nil, "error converting values")
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
-	umbrellaChart, err := buildUmbrellaChart(application, conf.ServerConfig, conf.ProjectID, conf.ExistingChartDependencies, conf.RemoveDeletedServices)
-	if err != nil {
-		err = telemetry.Error(ctx, span, err, "error building umbrella chart")
-		return nil, nil, nil, err
+	// rollbacks redeploy the values of an earlier release, which already point at the secrets it was deployed with
+	if conf.FullHelmValues == "" {
+		for name, service := range services {
+			helmName := getHelmName(name, getType(name, service))
+			serviceValues, ok := convertedValues[helmName].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			err := applyExternalSecrets(ctx, conf.SubdomainCreateOpts.k8sAgent, conf.SecretResolver, service.namespaceOr(conf.Namespace), helmName, secretRefs.ForService(name), serviceValues, conf.DryRun)
+			if err != nil {
+				err = telemetry.Error(ctx, span, err, fmt.Sprintf("error reading secrets of service %s", name))
+				return nil, nil, nil, nil, fmt.Errorf("service %s: %w", name, err)
+			}
+		}
+	}
+
+	// generated from the values rather than porter.yaml, so that the services kept from the release and rollbacks are
+	// covered as well
+	warnings = append(warnings, setNetworkPolicies(convertedValues, networkPolicyConf{
+		enabled:          conf.NetworkPolicies,
+		appName:          conf.PorterAppName,
+		appNamespace:     conf.Namespace,
+		ingressNamespace: conf.IngressNamespace,
+		capabilities:     conf.Capabilities,
+	})...)
+
+	var umbrellaChart *chart.Chart
+	if !conf.ValuesOnly {
+		umbrellaChart, err = buildUmbrellaChart(application, conf.ServerConfig, conf.ProjectID, conf.ExistingChartDependencies, conf.RemoveDeletedServices)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error building umbrella chart")
+			return nil, nil, nil, nil, err
+		}
 	}
 
 	// return the parsed release values for the release job chart, if they exist
 	var preDeployJobValues map[string]interface{}
 	if application.Release != nil && application.Release.Run != nil {
 		application.Release = addLabelsToService(application.Release, conf.EnvironmentGroups, porter_app.LabelKey_PorterApplicationPreDeploy)
-		preDeployJobValues = buildPreDeployJobChartValues(application.Release, application.Env, synced_env, conf.ImageInfo, conf.InjectLauncherToStartCommand, conf.ExistingHelmValues, porterAppUtils.PredeployJobNameFromPorterAppName(conf.PorterAppName), conf.UserUpdate, conf.AddCustomNodeSelector)
+		var preDeployWarnings []string
+		preDeployJobValues, preDeployWarnings = buildPreDeployJobChartValues(application.Release, application.Env, synced_env, conf.ImageInfo, conf.InjectLauncherToStartCommand, conf.ExistingHelmValues, porterAppUtils.PredeployJobNameFromPorterAppName(conf.PorterAppName), conf.UserUpdate, conf.AddCustomNodeSelector, conf.SchedulingDefaults, internalPorterApp.ObservabilityWorkload{
+			AppName:     conf.PorterAppName,
+			ServiceName: "pre-deploy",
+			Namespace:   conf.Namespace,
+			Version:     conf.ImageInfo.Tag,
+			InjectEnv:   application.Release.observabilityEnvEnabled(),
+		}, conf.Observability)
+		warnings = append(warnings, preDeployWarnings...)
+
+		if conf.FullHelmValues == "" {
+			preDeployJobValues, ok = convertMap(preDeployJobValues).(map[string]interface{})
+			if !ok {
+				err = telemetry.Error(ctx, span, nil, "error converting pre-deploy values")
+				return nil, nil, nil, nil, err
+			}
+
+			preDeployName := porterAppUtils.PredeployJobNameFromPorterAppName(conf.PorterAppName)
+			err := applyExternalSecrets(ctx, conf.SubdomainCreateOpts.k8sAgent, conf.SecretResolver, conf.Namespace, preDeployName, secretRefs.ForService("release"), preDeployJobValues, conf.DryRun)
+			if err != nil {
+				err = telemetry.Error(ctx, span, err, "error reading secrets of pre-deploy job")
+				return nil, nil, nil, nil, fmt.Errorf("pre-deploy: %w", err)
+			}
+		}
 	}
 
-	return umbrellaChart, convertedValues, preDeployJobValues, nil
+	return umbrellaChart, convertedValues, preDeployJobValues, warnings, nil
 }
 
 func buildUmbrellaChartValues(
@@ -236,6 +410,7 @@ func buildUmbrellaChartValues(
 	application *Application,
 	syncedEnv []*SyncedEnvSection,
 	imageInfo types.ImageInfo,
+	serviceImageInfo map[string]types.ImageInfo,
 	existingValues map[string]interface{},
 	opts SubdomainCreateOpts,
 	injectLauncher bool,
@@ -244,24 +419,68 @@ func buildUmbrellaChartValues(
 	namespace string,
 	addCustomNodeSelector bool,
 	removeDeletedValues bool,
-) (map[string]interface{}, error) {
+	schedulingDefaults types.ClusterSchedulingDefaults,
+	clusterCapabilities *types.ClusterCapabilities,
+	appName string,
+	observability *types.ProjectObservabilityConfig,
+) (map[string]interface{}, []string, error) {
 	values := make(map[string]interface{})
+	var warnings []string
 
 	if application.Services == nil {
 		if existingValues == nil {
-			return nil, fmt.Errorf("porter.yaml must contain at least one service, or pre-deploy must exist and have values")
+			return nil, nil, fmt.Errorf("porter.yaml must contain at least one service, or pre-deploy must exist and have values")
 		}
 	}
 
+	for name := range serviceImageInfo {
+		if _, ok := application.Services[name]; !ok {
+			warnings = append(warnings, fmt.Sprintf("an image was provided for service %s, which is not in porter.yaml", name))
+		}
+	}
+
+	// the services which run outside of the namespace of the app keep their namespace unless porter.yaml sets another
+	serviceNamespaces := serviceNamespacesFromValues(existingValues)
+
 	for name, service := range application.Services {
 		serviceType := getType(name, service)
+		serviceNamespace := service.namespaceOr(namespace)
 
-		defaultValues := getDefaultValues(service, application.Env, syncedEnv, serviceType, existingValues, name, userUpdate, addCustomNodeSelector)
+		// services built separately run their own image, which is set in the values of their chart over the global image
+		serviceImage, ownImage := serviceImageInfo[name]
+		if !ownImage {
+			serviceImage = imageInfo
+		}
+
+		defaultValues := getDefaultValues(service, application.Env, syncedEnv, serviceType, existingValues, name, userUpdate, addCustomNodeSelector, schedulingDefaults)
 		convertedConfig := convertMap(service.Config).(map[string]interface{})
 		helm_values := utils.DeepCoalesceValues(defaultValues, convertedConfig)
 
+		// injected before the existing values are merged, so that values injected by earlier deploys are not mistaken for
+		// values set in porter.yaml
+		warnings = append(warnings, internalPorterApp.InjectObservabilityValues(helm_values, internalPorterApp.ObservabilityWorkload{
+			AppName:     appName,
+			ServiceName: name,
+			Namespace:   serviceNamespace,
+			Version:     serviceImage.Tag,
+			InjectEnv:   service.observabilityEnvEnabled(),
+		}, observability)...)
+
 		// required to identify the chart type because of https://github.com/helm/helm/issues/9214
 		helmName := getHelmName(name, serviceType)
+
+		if serviceNamespace == namespace {
+			delete(serviceNamespaces, helmName)
+		} else {
+			serviceNamespaces[helmName] = serviceNamespace
+		}
+
+		// only volumes which do not exist yet need a storage class to be provisioned
+		existingVolume := false
+		if existingServiceValues, ok := existingValues[helmName].(map[string]interface{}); ok {
+			existingVolume = volumeEnabled(existingServiceValues)
+		}
+
 		if existingValues != nil {
 			if existingValues[helmName] != nil {
 				existingValuesMap := existingValues[helmName].(map[string]interface{})
@@ -288,17 +507,37 @@ func buildUmbrellaChartValues(
 
 		validateErr := validateHelmValues(helm_values, shouldValidateHelmValues, serviceType)
 		if validateErr != "" {
-			return nil, fmt.Errorf("error validating service \"%s\": %s", name, validateErr)
+			return nil, nil, fmt.Errorf("error validating service \"%s\": %s", name, validateErr)
 		}
 
-		err := syncEnvironmentGroupToNamespaceIfLabelsExist(ctx, opts.k8sAgent, service, namespace)
-		if err != nil {
-			return nil, fmt.Errorf("error syncing environment group to namespace: %w", err)
+		if !existingVolume {
+			if err := checkVolumeSupported(helm_values, clusterCapabilities); err != nil {
+				return nil, nil, fmt.Errorf("error validating service \"%s\": %w", name, err)
+			}
 		}
 
-		err = createSubdomainIfRequired(helm_values, opts) // modifies helm_values to add subdomains if necessary
-		if err != nil {
-			return nil, err
+		if !opts.dryRun {
+			err := syncEnvironmentGroupToNamespaceIfLabelsExist(ctx, opts.k8sAgent, service, serviceNamespace)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error syncing environment group to namespace: %w", err)
+			}
+
+			err = createSubdomainIfRequired(helm_values, opts) // modifies helm_values to add subdomains if necessary
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		// custom domains are added after the porter subdomain is created, so that services are served on both
+		if serviceType == "web" {
+			warnings = append(warnings, setCustomDomains(helm_values, name, service.CustomDomains, clusterCapabilities, opts.clusterIssuer)...)
+		}
+
+		setServiceNetwork(helm_values, service.Network)
+		setServiceHealthCheck(helm_values, service.HealthCheck)
+
+		if service.isCron() {
+			setCronSchedule(helm_values, service)
 		}
 
 		// just in case this slips by
@@ -310,6 +549,15 @@ func buildUmbrellaChartValues(
 			}
 		}
 
+		setIngressClass(helm_values, clusterCapabilities)
+
+		if ownImage {
+			helm_values["image"] = map[string]interface{}{
+				"repository": serviceImage.Repository,
+				"tag":        serviceImage.Tag,
+			}
+		}
+
 		values[helmName] = helm_values
 	}
 
@@ -337,14 +585,33 @@ func buildUmbrellaChartValues(
 		}
 	}
 
-	values["global"] = map[string]interface{}{
+	global := map[string]interface{}{
 		"image": map[string]interface{}{
 			"repository": imageInfo.Repository,
 			"tag":        imageInfo.Tag,
 		},
+		// recorded so that apps which have not been redeployed since the cluster defaults changed can be found
+		internalPorterApp.SchedulingDefaultsHashKey: internalPorterApp.SchedulingDefaultsHash(schedulingDefaults),
+	}
+
+	// services which were removed take their namespace with them
+	for helmName := range serviceNamespaces {
+		if values[helmName] == nil {
+			delete(serviceNamespaces, helmName)
+		}
+	}
+	if len(serviceNamespaces) > 0 {
+		namespaces := make(map[string]interface{}, len(serviceNamespaces))
+		for helmName, serviceNamespace := range serviceNamespaces {
+			namespaces[helmName] = serviceNamespace
+		}
+		global[serviceNamespacesKey] = namespaces
 	}
+	values["global"] = global
 
-	return values, nil
+	resolveServiceLinks(values, appName, namespace)
+
+	return values, warnings, nil
 }
 
 // syncEnvironmentGroupToNamespaceIfLabelsExist will sync the latest version of the environment group to the target namespace if the service has the appropriate label.
@@ -414,12 +681,8 @@ func validateHelmValues(values map[string]interface{}, shouldValidateHelmValues
 			} else {
 				portVal, portExists := containerMap["port"]
 				if portExists {
-					portStr, pOK := portVal.(string)
-					if !pOK {
-						return "error checking port: no port in container"
-					}
-
-					port, err := strconv.Atoi(portStr)
+					// ports which are not quoted in porter.yaml are parsed as numbers
+					port, err := strconv.Atoi(fmt.Sprint(portVal))
 					if err != nil || port < 1024 || port > 65535 {
 						return "port must be a number between 1024 and 65535"
 					}
@@ -432,11 +695,13 @@ func validateHelmValues(values map[string]interface{}, shouldValidateHelmValues
 	return ""
 }
 
-func buildPreDeployJobChartValues(release *Service, env map[string]string, synced_env []*SyncedEnvSection, imageInfo types.ImageInfo, injectLauncher bool, existingValues map[string]interface{}, name string, userUpdate bool, addCustomNodeSelector bool) map[string]interface{} {
-	defaultValues := getDefaultValues(release, env, synced_env, "job", existingValues, name+"-r", userUpdate, addCustomNodeSelector)
+func buildPreDeployJobChartValues(release *Service, env map[string]string, synced_env []*SyncedEnvSection, imageInfo types.ImageInfo, injectLauncher bool, existingValues map[string]interface{}, name string, userUpdate bool, addCustomNodeSelector bool, schedulingDefaults types.ClusterSchedulingDefaults, workload internalPorterApp.ObservabilityWorkload, observability *types.ProjectObservabilityConfig) (map[string]interface{}, []string) {
+	defaultValues := getDefaultValues(release, env, synced_env, "job", existingValues, name+"-r", userUpdate, addCustomNodeSelector, schedulingDefaults)
 	convertedConfig := convertMap(release.Config).(map[string]interface{})
 	helm_values := utils.DeepCoalesceValues(defaultValues, convertedConfig)
 
+	warnings := internalPorterApp.InjectObservabilityValues(helm_values, workload, observability)
+
 	if imageInfo.Repository != "" && imageInfo.Tag != "" {
 		helm_values["image"] = map[string]interface{}{
 			"repository": imageInfo.Repository,
@@ -455,10 +720,14 @@ func buildPreDeployJobChartValues(release *Service, env map[string]string, synce
 		}
 	}
 
-	return helm_values
+	return helm_values, warnings
 }
 
 func getType(name string, service *Service) string {
+	// cron services are deployed with the job chart
+	if service.isCron() {
+		return "job"
+	}
 	if service.Type != nil {
 		return *service.Type
 	}
@@ -473,7 +742,7 @@ func getType(name string, service *Service) string {
 	return "worker"
 }
 
-func getDefaultValues(service *Service, env map[string]string, synced_env []*SyncedEnvSection, appType string, existingValues map[string]interface{}, name string, userUpdate bool, addCustomNodeSelector bool) map[string]interface{} {
+func getDefaultValues(service *Service, env map[string]string, synced_env []*SyncedEnvSection, appType string, existingValues map[string]interface{}, name string, userUpdate bool, addCustomNodeSelector bool, schedulingDefaults types.ClusterSchedulingDefaults) map[string]interface{} {
 	var defaultValues map[string]interface{}
 	var runCommand string
 	if service.Run != nil {
@@ -495,13 +764,17 @@ func getDefaultValues(service *Service, env map[string]string, synced_env []*Syn
 				"synced": syncedEnvs,
 			},
 		},
-		"nodeSelector": map[string]interface{}{},
 	}
 
+	// cluster scheduling defaults are part of the default values, so anything set in the service config takes precedence
+	nodeSelector, tolerations := internalPorterApp.SchedulingDefaultsHelmValues(schedulingDefaults)
 	if addCustomNodeSelector {
-		defaultValues["nodeSelector"] = map[string]interface{}{
-			"porter.run/workload-kind": "application",
-		}
+		nodeSelector["porter.run/workload-kind"] = "application"
+	}
+	defaultValues["nodeSelector"] = nodeSelector
+
+	if len(tolerations) > 0 {
+		defaultValues["tolerations"] = tolerations
 	}
 
 	return defaultValues
@@ -626,6 +899,9 @@ func createChartFromDependencies(deps []*chart.Dependency) (*chart.Chart, error)
 	// create a new chart object with the metadata
 	c := &chart.Chart{
 		Metadata: metadata,
+		Templates: []*chart.File{
+			{Name: "templates/network-policies.yaml", Data: []byte(networkPoliciesTemplate)},
+		},
 	}
 	return c, nil
 }
@@ -697,6 +973,70 @@ func CopyEnv(env map[string]string) map[string]interface{} {
 	return envCopy
 }
 
+// volumeEnabled returns true if the values of a service enable its persistent volume
+func volumeEnabled(serviceValues map[string]interface{}) bool {
+	pvcMap, err := getNestedMap(serviceValues, "pvc")
+	if err != nil {
+		return false
+	}
+
+	enabled, _ := pvcMap["enabled"].(bool)
+	return enabled
+}
+
+// checkVolumeSupported refuses a service which enables a persistent volume without a storage class when the cluster
+// has no default storage class, since its volume claim would stay pending and the service would never start
+func checkVolumeSupported(serviceValues map[string]interface{}, clusterCapabilities *types.ClusterCapabilities) error {
+	if !volumeEnabled(serviceValues) || !capabilities.Known(clusterCapabilities, types.ClusterCapabilityProbe_StorageClasses) {
+		return nil
+	}
+
+	pvcMap, _ := getNestedMap(serviceValues, "pvc")
+	if storageClass, _ := pvcMap["storageClassName"].(string); storageClass != "" {
+		return nil
+	}
+
+	if clusterCapabilities.DefaultStorageClass == "" {
+		return fmt.Errorf("the cluster has no default storage class, so persistent volumes cannot be provisioned: set pvc.storageClassName or mark a storage class as the default")
+	}
+
+	return nil
+}
+
+// setIngressClass sets the ingress class of a service's ingress to the cluster's detected default ingress class, when
+// that is not the nginx class the charts use and the service does not set a class itself
+func setIngressClass(serviceValues map[string]interface{}, clusterCapabilities *types.ClusterCapabilities) {
+	if !capabilities.Known(clusterCapabilities, types.ClusterCapabilityProbe_IngressClasses) {
+		return
+	}
+
+	ingressClass := clusterCapabilities.DefaultIngressClass
+	if ingressClass == "" || ingressClass == "nginx" {
+		return
+	}
+
+	ingressMap, err := getNestedMap(serviceValues, "ingress")
+	if err != nil {
+		return
+	}
+	if enabled, _ := ingressMap["enabled"].(bool); !enabled {
+		return
+	}
+
+	annotations := make(map[string]interface{})
+	if existing, ok := ingressMap["annotations"]; ok && existing != nil {
+		if annotations, ok = existing.(map[string]interface{}); !ok {
+			return
+		}
+	}
+	if _, ok := annotations[annotationIngressClass]; ok {
+		return
+	}
+
+	annotations[annotationIngressClass] = ingressClass
+	ingressMap["annotations"] = annotations
+}
+
 func createSubdomainIfRequired(
 	mergedValues map[string]interface{},
 	opts SubdomainCreateOpts,
@@ -729,6 +1069,7 @@ func createSubdomainIfRequired(
 				}
 
 				subdomain := dnsRecord.ExternalURL
+				opts.created.addDNSRecord(dnsRecord.Hostname)
 
 				if ingressVal, ok := mergedValues["ingress"]; !ok {
 					mergedValues["ingress"] = map[string]interface{}{
@@ -860,8 +1201,9 @@ func attemptToGetImageInfoFromRelease(values map[string]interface{}) types.Image
 	repoVal, okRepo := globalImage["repository"]
 	tagVal, okTag := globalImage["tag"]
 	if okRepo && okTag {
-		imageInfo.Repository = repoVal.(string)
-		imageInfo.Tag = tagVal.(string)
+		// values edited by hand can hold a tag which is not a string, such as 1.0, which is left out rather than panicking
+		imageInfo.Repository, _ = repoVal.(string)
+		imageInfo.Tag, _ = tagVal.(string)
 	}
 
 	return imageInfo
@@ -943,11 +1285,20 @@ func convertHelmValuesToPorterYaml(helmValues string) (*PorterStackYAML, error)
 			}
 		}
 
-		services[serviceName] = &Service{
+		service := &Service{
 			Run:    &runCommand,
 			Config: config,
 			Type:   &serviceType,
 		}
+
+		// the custom domains are kept, so that they are not removed from the hosts of the ingress as if porter.yaml had
+		// dropped them
+		if ingressMap, err := getNestedMap(config, "ingress"); err == nil {
+			service.CustomDomains = stringsFromValue(ingressMap[customDomainsKey])
+		}
+		service.Network = serviceNetworkFromValues(config)
+
+		services[serviceName] = service
 	}
 
 	return &PorterStackYAML{
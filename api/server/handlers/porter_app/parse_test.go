@@ -0,0 +1,271 @@
+package porter_app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/porter_app"
+	internalPorterApp "github.com/porter-dev/porter/internal/porter_app"
+	"gopkg.in/yaml.v2"
+)
+
+const observabilityPorterYaml = `version: v1stack
+env:
+  LOG_LEVEL: info
+services:
+  web:
+    type: web
+    run: node index.js
+    config:
+      container:
+        port: 8080
+        env:
+          normal:
+            OTEL_SERVICE_NAME: storefront
+      ingress:
+        enabled: false
+  worker:
+    type: worker
+    run: node worker.js
+    observability:
+      enabled: false
+    config: {}
+  cron:
+    type: job
+    run: node cleanup.js
+    config:
+      schedule:
+        enabled: true
+        value: "*/10 * * * *"
+  job:
+    type: job
+    run: node backfill.js
+    config: {}
+release:
+  run: npm run migrate
+  config: {}
+`
+
+var testObservabilityConfig = &types.ProjectObservabilityConfig{
+	OTLPEndpoint: "http://otel-collector.monitoring:4317",
+	SampleRate:   0.25,
+}
+
+// buildTestValues builds the values of each service and of the pre-deploy job as parse does, without the chart
+func buildTestValues(t *testing.T, existingValues map[string]interface{}, observability *types.ProjectObservabilityConfig) (map[string]interface{}, map[string]interface{}, []string) {
+	t.Helper()
+
+	parsed := &PorterStackYAML{}
+	if err := yaml.Unmarshal([]byte(observabilityPorterYaml), parsed); err != nil {
+		t.Fatalf("error parsing porter.yaml: %v", err)
+	}
+	for name := range parsed.Services {
+		parsed.Services[name] = addLabelsToService(parsed.Services[name], nil, porter_app.LabelKey_PorterApplication)
+	}
+
+	parsed.Release = addLabelsToService(parsed.Release, nil, porter_app.LabelKey_PorterApplicationPreDeploy)
+
+	application := &Application{Env: parsed.Env, Services: parsed.Services, Release: parsed.Release}
+	imageInfo := types.ImageInfo{Repository: "registry.example.com/storefront", Tag: "8f14e45f"}
+
+	values, warnings, err := buildUmbrellaChartValues(context.Background(), application, nil, imageInfo, nil, existingValues, SubdomainCreateOpts{}, false, true, false, "porter-stack-storefront", false, false, types.ClusterSchedulingDefaults{}, nil, "storefront", observability)
+	if err != nil {
+		t.Fatalf("error building values: %v", err)
+	}
+
+	preDeployValues, preDeployWarnings := buildPreDeployJobChartValues(application.Release, application.Env, nil, imageInfo, false, existingValues, "storefront", false, false, types.ClusterSchedulingDefaults{}, preDeployWorkload(application.Release), observability)
+
+	return values, preDeployValues, append(warnings, preDeployWarnings...)
+}
+
+func preDeployWorkload(release *Service) internalPorterApp.ObservabilityWorkload {
+	return internalPorterApp.ObservabilityWorkload{
+		AppName:     "storefront",
+		ServiceName: "pre-deploy",
+		Namespace:   "porter-stack-storefront",
+		Version:     "8f14e45f",
+		InjectEnv:   release.observabilityEnvEnabled(),
+	}
+}
+
+func stringValue(t *testing.T, values map[string]interface{}, path ...string) string {
+	t.Helper()
+
+	for i, key := range path {
+		if i == len(path)-1 {
+			value, _ := values[key].(string)
+			return value
+		}
+
+		next, ok := values[key].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected %s to be a map, found %T", strings.Join(path[:i+1], "."), values[key])
+		}
+		values = next
+	}
+
+	return ""
+}
+
+func TestObservabilityLabelsInjectedIntoEveryChart(t *testing.T) {
+	values, preDeployValues, _ := buildTestValues(t, nil, nil)
+
+	charts := map[string]map[string]interface{}{
+		"web":        values["web-web"].(map[string]interface{}),
+		"worker":     values["worker-wkr"].(map[string]interface{}),
+		"cron":       values["cron-job"].(map[string]interface{}),
+		"job":        values["job-job"].(map[string]interface{}),
+		"pre-deploy": preDeployValues,
+	}
+
+	for service, chartValues := range charts {
+		porterLabel := porter_app.LabelKey_PorterApplication
+		if service == "pre-deploy" {
+			porterLabel = porter_app.LabelKey_PorterApplicationPreDeploy
+		}
+
+		for _, labelsKey := range []string{"labels", "podLabels"} {
+			if got := stringValue(t, chartValues, labelsKey, "app.kubernetes.io/name"); got != service {
+				t.Errorf("%s: expected %s app.kubernetes.io/name to be %q, got %q", service, labelsKey, service, got)
+			}
+			if got := stringValue(t, chartValues, labelsKey, "app.kubernetes.io/part-of"); got != "storefront" {
+				t.Errorf("%s: expected %s app.kubernetes.io/part-of to be storefront, got %q", service, labelsKey, got)
+			}
+			if got := stringValue(t, chartValues, labelsKey, "app.kubernetes.io/version"); got != "8f14e45f" {
+				t.Errorf("%s: expected %s app.kubernetes.io/version to be the image tag, got %q", service, labelsKey, got)
+			}
+			// the existing porter labels are kept
+			if got := stringValue(t, chartValues, labelsKey, porterLabel); got == "" {
+				t.Errorf("%s: expected %s to keep %s", service, labelsKey, porterLabel)
+			}
+		}
+
+		if got := stringValue(t, chartValues, "container", "env", "normal", "OTEL_EXPORTER_OTLP_ENDPOINT"); got != "" {
+			t.Errorf("%s: expected no OpenTelemetry env without an observability config, got endpoint %q", service, got)
+		}
+	}
+}
+
+func TestObservabilityEnvInjectedIntoEveryChart(t *testing.T) {
+	values, preDeployValues, warnings := buildTestValues(t, nil, testObservabilityConfig)
+
+	charts := map[string]map[string]interface{}{
+		"cron":       values["cron-job"].(map[string]interface{}),
+		"job":        values["job-job"].(map[string]interface{}),
+		"pre-deploy": preDeployValues,
+	}
+
+	for service, chartValues := range charts {
+		expected := map[string]string{
+			"OTEL_SERVICE_NAME":           "storefront-" + service,
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://otel-collector.monitoring:4317",
+			"OTEL_TRACES_SAMPLER":         "parentbased_traceidratio",
+			"OTEL_TRACES_SAMPLER_ARG":     "0.25",
+			"OTEL_RESOURCE_ATTRIBUTES":    "service.namespace=storefront,k8s.namespace.name=porter-stack-storefront,service.version=8f14e45f",
+			// env set for the app is still set for every service
+			"LOG_LEVEL": "info",
+		}
+		for key, want := range expected {
+			if got := stringValue(t, chartValues, "container", "env", "normal", key); got != want {
+				t.Errorf("%s: expected %s to be %q, got %q", service, key, want, got)
+			}
+		}
+	}
+
+	web := values["web-web"].(map[string]interface{})
+	if got := stringValue(t, web, "container", "env", "normal", "OTEL_SERVICE_NAME"); got != "storefront" {
+		t.Errorf("expected the OTEL_SERVICE_NAME set in porter.yaml to be kept, got %q", got)
+	}
+	if got := stringValue(t, web, "container", "env", "normal", "OTEL_EXPORTER_OTLP_ENDPOINT"); got != testObservabilityConfig.OTLPEndpoint {
+		t.Errorf("expected the env which is not set in porter.yaml to be injected into web, got endpoint %q", got)
+	}
+
+	worker := values["worker-wkr"].(map[string]interface{})
+	if got := stringValue(t, worker, "container", "env", "normal", "OTEL_EXPORTER_OTLP_ENDPOINT"); got != "" {
+		t.Errorf("expected no OpenTelemetry env for a service which opts out, got endpoint %q", got)
+	}
+	if got := stringValue(t, worker, "podLabels", "app.kubernetes.io/name"); got != "worker" {
+		t.Errorf("expected the standard labels for a service which opts out of the env, got name %q", got)
+	}
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "env variable OTEL_SERVICE_NAME of service web is set to \"storefront\"") {
+		t.Errorf("expected a single warning for the OTEL_SERVICE_NAME set in porter.yaml, got %v", warnings)
+	}
+}
+
+func TestObservabilityEnvReplacesValuesInjectedByEarlierDeploys(t *testing.T) {
+	existingValues := map[string]interface{}{
+		"job-job": map[string]interface{}{
+			"container": map[string]interface{}{
+				"env": map[string]interface{}{
+					"normal": map[string]interface{}{
+						"OTEL_EXPORTER_OTLP_ENDPOINT": "http://old-collector:4317",
+					},
+				},
+			},
+			"labels": map[string]interface{}{
+				"app.kubernetes.io/version": "previous",
+			},
+		},
+	}
+
+	values, _, warnings := buildTestValues(t, existingValues, testObservabilityConfig)
+
+	job := values["job-job"].(map[string]interface{})
+	if got := stringValue(t, job, "container", "env", "normal", "OTEL_EXPORTER_OTLP_ENDPOINT"); got != testObservabilityConfig.OTLPEndpoint {
+		t.Errorf("expected the endpoint of the current config, got %q", got)
+	}
+	if got := stringValue(t, job, "labels", "app.kubernetes.io/version"); got != "8f14e45f" {
+		t.Errorf("expected the version label of the current image, got %q", got)
+	}
+
+	for _, warning := range warnings {
+		if strings.Contains(warning, "service job") {
+			t.Errorf("expected no warnings for values injected by an earlier deploy, got %q", warning)
+		}
+	}
+}
+
+func TestServiceImagesOverrideTheGlobalImage(t *testing.T) {
+	parsed := &PorterStackYAML{}
+	if err := yaml.Unmarshal([]byte(observabilityPorterYaml), parsed); err != nil {
+		t.Fatalf("error parsing porter.yaml: %v", err)
+	}
+
+	application := &Application{Env: parsed.Env, Services: parsed.Services, Release: parsed.Release}
+	imageInfo := types.ImageInfo{Repository: "registry.example.com/storefront", Tag: "8f14e45f"}
+	serviceImageInfo := map[string]types.ImageInfo{
+		"worker": {Repository: "registry.example.com/storefront-worker", Tag: "c9f0f895"},
+		"api":    {Repository: "registry.example.com/storefront-api", Tag: "45c48cce"},
+	}
+
+	values, warnings, err := buildUmbrellaChartValues(context.Background(), application, nil, imageInfo, serviceImageInfo, nil, SubdomainCreateOpts{}, false, true, false, "porter-stack-storefront", false, false, types.ClusterSchedulingDefaults{}, nil, "storefront", nil)
+	if err != nil {
+		t.Fatalf("error building values: %v", err)
+	}
+
+	if got := stringValue(t, values, "global", "image", "repository"); got != imageInfo.Repository {
+		t.Errorf("expected the global image to be the default image, got %q", got)
+	}
+
+	worker := values["worker-wkr"].(map[string]interface{})
+	if got := stringValue(t, worker, "image", "repository"); got != "registry.example.com/storefront-worker" {
+		t.Errorf("expected the worker to run its own image, got %q", got)
+	}
+	if got := stringValue(t, worker, "image", "tag"); got != "c9f0f895" {
+		t.Errorf("expected the worker to run its own tag, got %q", got)
+	}
+	if got := stringValue(t, worker, "labels", "app.kubernetes.io/version"); got != "c9f0f895" {
+		t.Errorf("expected the version label of the worker to be its own tag, got %q", got)
+	}
+
+	if _, ok := values["web-web"].(map[string]interface{})["image"]; ok {
+		t.Errorf("expected the web service to run the global image")
+	}
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "service api") {
+		t.Errorf("expected a warning for the image of a service which is not in porter.yaml, got %v", warnings)
+	}
+}
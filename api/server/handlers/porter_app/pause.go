@@ -0,0 +1,310 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// PausePorterAppHandler handles POST /applications/{porter_app_name}/pause, which scales the deployments of an app to
+// zero and suspends its cron jobs, so that an idle app stops using the cluster without being deleted. The replicas
+// of each deployment are recorded on the app, and restored when it is resumed. Pausing a paused app pauses the
+// workloads which have been added or scaled up since.
+type PausePorterAppHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewPausePorterAppHandler returns a new PausePorterAppHandler
+func NewPausePorterAppHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *PausePorterAppHandler {
+	return &PausePorterAppHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *PausePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-pause-porter-app")
+	defer span.End()
+
+	porterApp, clientset, reqErr := readAppForPause(ctx, c.Config(), c.KubernetesAgentGetter, r)
+	if reqErr != nil {
+		telemetry.Error(ctx, span, reqErr, "error reading app to pause")
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-name", Value: porterApp.Name},
+		telemetry.AttributeKV{Key: "already-paused", Value: porterApp.PausedAt != nil},
+	)
+
+	workloads, pauseErr := pauseWorkloads(ctx, clientset, utils.NamespaceFromPorterAppName(porterApp.Name), porterApp.PausedWorkloads)
+	c.Config().StatusQueryCoalescer.Invalidate(porterApp.ClusterID, porterApp.Name)
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "paused-deployments", Value: len(workloads.Deployments)},
+		telemetry.AttributeKV{Key: "suspended-cron-jobs", Value: len(workloads.CronJobs)},
+	)
+
+	// the workloads which were paused are recorded even if others failed to pause, so that they are restored on resume
+	if pauseErr == nil || !workloads.Empty() {
+		if porterApp.PausedAt == nil {
+			now := time.Now().UTC()
+			porterApp.PausedAt = &now
+		}
+		porterApp.PausedWorkloads = workloads
+
+		var err error
+		porterApp, err = c.Repo().PorterApp().UpdatePorterApp(porterApp)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error recording paused workloads")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	if pauseErr != nil {
+		err := telemetry.Error(ctx, span, pauseErr, "error pausing app workloads")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, porterApp.ToPorterAppType())
+}
+
+// ResumePorterAppHandler handles POST /applications/{porter_app_name}/resume, which scales the deployments of a paused
+// app back to the replicas they ran before it was paused and resumes its cron jobs. Resuming a running app does nothing.
+type ResumePorterAppHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewResumePorterAppHandler returns a new ResumePorterAppHandler
+func NewResumePorterAppHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ResumePorterAppHandler {
+	return &ResumePorterAppHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ResumePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-resume-porter-app")
+	defer span.End()
+
+	porterApp, clientset, reqErr := readAppForPause(ctx, c.Config(), c.KubernetesAgentGetter, r)
+	if reqErr != nil {
+		telemetry.Error(ctx, span, reqErr, "error reading app to resume")
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-name", Value: porterApp.Name},
+		telemetry.AttributeKV{Key: "paused", Value: porterApp.PausedAt != nil},
+	)
+
+	if porterApp.PausedAt == nil {
+		c.WriteResult(w, r, porterApp.ToPorterAppType())
+		return
+	}
+
+	remaining, resumeErr := resumeWorkloads(ctx, clientset, utils.NamespaceFromPorterAppName(porterApp.Name), porterApp.PausedWorkloads)
+	c.Config().StatusQueryCoalescer.Invalidate(porterApp.ClusterID, porterApp.Name)
+
+	// the workloads which failed to resume stay recorded, so that resuming again retries them
+	porterApp.PausedWorkloads = remaining
+	if resumeErr == nil {
+		porterApp.PausedAt = nil
+
+		// services are back at the replicas they were deployed with, so their scaling schedules are applied again
+		for _, service := range porterApp.ScalingSchedules {
+			service.Applied = nil
+		}
+	}
+
+	porterApp, err := c.Repo().PorterApp().UpdatePorterApp(porterApp)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error recording resumed workloads")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if resumeErr != nil {
+		err := telemetry.Error(ctx, span, resumeErr, "error resuming app workloads")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, porterApp.ToPorterAppType())
+}
+
+// readAppForPause reads the app named in the url of a pause or resume request, once the user is authorized to deploy
+// it, and connects to its cluster
+func readAppForPause(ctx context.Context, conf *config.Config, agentGetter authz.KubernetesAgentGetter, r *http.Request) (*models.PorterApp, k8s.Interface, apierrors.RequestError) {
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		return nil, nil, apierrors.NewErrPassThroughToClient(reqErr, http.StatusBadRequest)
+	}
+
+	if err := authorizeAppAction(ctx, conf, r, appName, types.PorterAppGrantPermission_Deploy); err != nil {
+		return nil, nil, appAccessError(err)
+	}
+
+	porterApp, err := conf.Repo.PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apierrors.NewErrNotFound(fmt.Errorf("porter app %s not found", appName))
+		}
+		return nil, nil, apierrors.NewErrInternal(fmt.Errorf("error reading porter app by name: %w", err))
+	}
+
+	k8sAgent, err := agentGetter.GetAgent(r, cluster, utils.NamespaceFromPorterAppName(porterApp.Name))
+	if err != nil {
+		return nil, nil, apierrors.NewErrInternal(fmt.Errorf("error getting k8s agent: %w", err))
+	}
+
+	return porterApp, k8sAgent.Clientset, nil
+}
+
+// pauseWorkloads scales the deployments in the namespace of an app to zero and suspends its cron jobs, and returns
+// the workloads which are paused, including the ones in paused which were paused before. Deployments which already run
+// no replicas, and cron jobs which are already suspended, are not recorded, so that resuming the app leaves them as
+// they are. Autoscalers do not scale a deployment which runs no replicas, so they are left in place.
+//
+// A workload which fails to pause does not stop the others from being paused, and the workloads which were paused are
+// returned along with the error.
+func pauseWorkloads(ctx context.Context, clientset k8s.Interface, namespace string, paused models.PorterAppPausedWorkloads) (models.PorterAppPausedWorkloads, error) {
+	workloads := models.PorterAppPausedWorkloads{
+		Deployments: make(map[string]int32, len(paused.Deployments)),
+		CronJobs:    append([]string{}, paused.CronJobs...),
+	}
+	for name, replicas := range paused.Deployments {
+		workloads.Deployments[name] = replicas
+	}
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return workloads, fmt.Errorf("error listing deployments: %w", err)
+	}
+
+	var errs []error
+	for _, deployment := range deployments.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if replicas == 0 {
+			continue
+		}
+
+		if err := patchDeploymentReplicas(ctx, clientset, namespace, deployment.Name, 0); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// a deployment which was scaled up while the app was paused is resumed to the replicas it was paused from
+		if _, ok := workloads.Deployments[deployment.Name]; !ok {
+			workloads.Deployments[deployment.Name] = replicas
+		}
+	}
+
+	cronJobs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("error listing cron jobs: %w", err))
+		return workloads, errors.Join(errs...)
+	}
+
+	for _, cronJob := range cronJobs.Items {
+		if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+			continue
+		}
+
+		if err := patchCronJobSuspend(ctx, clientset, namespace, cronJob.Name, true); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		workloads.CronJobs = append(workloads.CronJobs, cronJob.Name)
+	}
+
+	return workloads, errors.Join(errs...)
+}
+
+// resumeWorkloads scales the paused deployments in the namespace of an app back to their replicas and resumes its
+// suspended cron jobs, and returns the workloads which are still paused because they failed to resume. Workloads which
+// were removed while the app was paused are skipped.
+func resumeWorkloads(ctx context.Context, clientset k8s.Interface, namespace string, paused models.PorterAppPausedWorkloads) (models.PorterAppPausedWorkloads, error) {
+	var remaining models.PorterAppPausedWorkloads
+	var errs []error
+
+	for name, replicas := range paused.Deployments {
+		err := patchDeploymentReplicas(ctx, clientset, namespace, name, replicas)
+		if err == nil || k8serrors.IsNotFound(err) {
+			continue
+		}
+
+		errs = append(errs, err)
+		if remaining.Deployments == nil {
+			remaining.Deployments = map[string]int32{}
+		}
+		remaining.Deployments[name] = replicas
+	}
+
+	for _, name := range paused.CronJobs {
+		err := patchCronJobSuspend(ctx, clientset, namespace, name, false)
+		if err == nil || k8serrors.IsNotFound(err) {
+			continue
+		}
+
+		errs = append(errs, err)
+		remaining.CronJobs = append(remaining.CronJobs, name)
+	}
+
+	return remaining, errors.Join(errs...)
+}
+
+func patchDeploymentReplicas(ctx context.Context, clientset k8s.Interface, namespace, name string, replicas int32) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	if _, err := clientset.AppsV1().Deployments(namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("error scaling deployment %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func patchCronJobSuspend(ctx context.Context, clientset k8s.Interface, namespace, name string, suspend bool) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend))
+	if _, err := clientset.BatchV1().CronJobs(namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		if suspend {
+			return fmt.Errorf("error suspending cron job %s: %w", name, err)
+		}
+		return fmt.Errorf("error resuming cron job %s: %w", name, err)
+	}
+
+	return nil
+}
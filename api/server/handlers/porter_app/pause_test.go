@@ -0,0 +1,103 @@
+package porter_app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPauseAndResumeWorkloads(t *testing.T) {
+	ctx := context.Background()
+	namespace := "porter-stack-storefront"
+
+	replicas := func(n int32) *int32 { return &n }
+	suspended := true
+
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "storefront-web", Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: replicas(3)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "storefront-worker", Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: replicas(0)},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "storefront-cleanup", Namespace: namespace},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "storefront-report", Namespace: namespace},
+			Spec:       batchv1.CronJobSpec{Suspend: &suspended},
+		},
+	)
+
+	paused, err := pauseWorkloads(ctx, clientset, namespace, models.PorterAppPausedWorkloads{})
+	if err != nil {
+		t.Fatalf("unexpected error pausing: %v", err)
+	}
+	if len(paused.Deployments) != 1 || paused.Deployments["storefront-web"] != 3 {
+		t.Errorf("expected only the running deployment to be recorded, got %v", paused.Deployments)
+	}
+	if len(paused.CronJobs) != 1 || paused.CronJobs[0] != "storefront-cleanup" {
+		t.Errorf("expected only the running cron job to be recorded, got %v", paused.CronJobs)
+	}
+	expectDeploymentReplicas(t, clientset, namespace, "storefront-web", 0)
+	expectCronJobSuspended(t, clientset, namespace, "storefront-cleanup", true)
+
+	// a deployment scaled up while the app is paused is paused again, and resumes to the replicas it was paused from
+	if err := patchDeploymentReplicas(ctx, clientset, namespace, "storefront-web", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	paused, err = pauseWorkloads(ctx, clientset, namespace, paused)
+	if err != nil {
+		t.Fatalf("unexpected error pausing again: %v", err)
+	}
+	if paused.Deployments["storefront-web"] != 3 || len(paused.CronJobs) != 1 {
+		t.Errorf("expected pausing again to keep the recorded workloads, got %+v", paused)
+	}
+	expectDeploymentReplicas(t, clientset, namespace, "storefront-web", 0)
+
+	// workloads which were removed while the app was paused are skipped
+	paused.Deployments["storefront-api"] = 2
+
+	remaining, err := resumeWorkloads(ctx, clientset, namespace, paused)
+	if err != nil {
+		t.Fatalf("unexpected error resuming: %v", err)
+	}
+	if !remaining.Empty() {
+		t.Errorf("expected every workload to be resumed, got %+v", remaining)
+	}
+	expectDeploymentReplicas(t, clientset, namespace, "storefront-web", 3)
+	expectDeploymentReplicas(t, clientset, namespace, "storefront-worker", 0)
+	expectCronJobSuspended(t, clientset, namespace, "storefront-cleanup", false)
+	expectCronJobSuspended(t, clientset, namespace, "storefront-report", true)
+}
+
+func expectDeploymentReplicas(t *testing.T, clientset *fake.Clientset, namespace, name string, want int32) {
+	t.Helper()
+
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error reading deployment %s: %v", name, err)
+	}
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != want {
+		t.Errorf("expected deployment %s to run %d replicas, got %v", name, want, deployment.Spec.Replicas)
+	}
+}
+
+func expectCronJobSuspended(t *testing.T, clientset *fake.Clientset, namespace, name string, want bool) {
+	t.Helper()
+
+	cronJob, err := clientset.BatchV1().CronJobs(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error reading cron job %s: %v", name, err)
+	}
+	if got := cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend; got != want {
+		t.Errorf("expected cron job %s to be suspended %t, got %t", name, want, got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/coalesce"
 	"github.com/porter-dev/porter/internal/deployment_target"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/telemetry"
@@ -124,7 +125,15 @@ func (c *PodStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	} else {
 		selectors = fmt.Sprintf("porter.run/service-name=%s,porter.run/deployment-target-id=%s,porter.run/app-name=%s", deploymentTarget.ID, request.DeploymentTargetID, appName)
 	}
-	podsList, err := agent.GetPodsByLabel(selectors, namespace)
+	podsList, cacheResult, err := coalesce.Do(c.Config().StatusQueryCoalescer, coalesce.Key{
+		ClusterID: cluster.ID,
+		Namespace: namespace,
+		StackName: appName,
+		QueryType: coalesce.QueryType_PodStatus,
+		Params:    selectors,
+	}, coalesce.OptionsFromRequest(r), func() (*v1.PodList, error) {
+		return agent.GetPodsByLabel(ctx, selectors, namespace)
+	})
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "unable to get pods by label")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
@@ -133,5 +142,6 @@ func (c *PodStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	pods = append(pods, podsList.Items...)
 
+	cacheResult.SetHeaders(w)
 	c.WriteResult(w, r, pods)
 }
@@ -1,6 +1,9 @@
 package porter_app
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/porter-dev/porter/api/server/authz"
@@ -14,6 +17,8 @@ import (
 	utils "github.com/porter-dev/porter/api/utils/porter_app"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+	v1 "k8s.io/api/core/v1"
 )
 
 type PorterAppPodsGetHandler struct {
@@ -81,5 +86,40 @@ func (c *PorterAppPodsGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// the releases of the app in the other namespaces its services run in are deployed along with the release of the
+	// app, but their revisions are numbered on their own, so the pods of their latest revision are listed
+	for _, serviceNamespace := range otherNamespaces(serviceNamespacesFromValues(helmRelease.Config)) {
+		namespacePods, err := c.namespacePods(ctx, r, cluster, appName, serviceNamespace)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error getting pods for release in service namespace")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		pods = append(pods, namespacePods...)
+	}
+
 	c.WriteResult(w, r, pods)
 }
+
+// namespacePods returns the pods of the latest release of an app in another namespace its services run in
+func (c *PorterAppPodsGetHandler) namespacePods(ctx context.Context, r *http.Request, cluster *models.Cluster, appName, namespace string) ([]v1.Pod, error) {
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error getting helm agent for namespace %s: %w", namespace, err)
+	}
+
+	helmRelease, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting helm release in namespace %s: %w", namespace, err)
+	}
+
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error getting k8s agent for namespace %s: %w", namespace, err)
+	}
+
+	return release.GetPodsForRelease(ctx, helmRelease, k8sAgent)
+}
@@ -0,0 +1,74 @@
+package porter_app
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// createPreDeployEvent records the pre-deploy job of a deploy in the activity feed, between the build and deploy events.
+// The job runs once its chart is installed, so a successful install is recorded as PROGRESSING, and the event is
+// finished through the event update endpoint with the exit code of the job. A job whose chart could not be installed is
+// recorded as FAILED along with the failure.
+func createPreDeployEvent(
+	ctx context.Context,
+	repo repository.PorterAppEventRepository,
+	appID uint,
+	status types.PorterAppEventStatus,
+	revision int,
+	tag string,
+	failure *deployFailure,
+) (*models.PorterAppEvent, error) {
+	ctx, span := telemetry.NewSpan(ctx, "create-pre-deploy-event")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-id", Value: appID},
+		telemetry.AttributeKV{Key: "status", Value: string(status)},
+		telemetry.AttributeKV{Key: "revision", Value: revision},
+	)
+
+	event := models.PorterAppEvent{
+		ID:                 uuid.New(),
+		Status:             string(status),
+		Type:               string(types.PorterAppEventType_PreDeploy),
+		TypeExternalSource: "KUBERNETES",
+		PorterAppID:        appID,
+		Metadata: map[string]any{
+			"image_tag": tag,
+		},
+	}
+	if revision != 0 {
+		event.Metadata["revision"] = revision
+	}
+	if failure != nil {
+		failure.addTo(event.Metadata)
+	}
+
+	if err := repo.CreateEvent(ctx, &event); err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating pre-deploy event")
+	}
+
+	return &event, nil
+}
+
+// recordFailedPreDeploy records a pre-deploy job whose chart could not be built, installed, upgraded or removed. As
+// for recordFailedDeploy, apps which have no record yet are only traced, and errors recording the event are only
+// traced since the deploy fails either way.
+func recordFailedPreDeploy(ctx context.Context, conf *config.Config, projectID, clusterID uint, appName, tag string, failure *deployFailure) {
+	ctx, span := telemetry.NewSpan(ctx, "record-failed-pre-deploy")
+	defer span.End()
+
+	app, err := conf.Repo.PorterApp().ReadScopedPorterAppByName(projectID, clusterID, appName)
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error reading porter app")
+		return
+	}
+
+	_, _ = createPreDeployEvent(ctx, conf.Repo.PorterAppEvent(), app.ID, types.PorterAppEventStatus_Failed, 0, tag, failure)
+}
@@ -0,0 +1,160 @@
+package porter_app_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// tenant is a project with a single cluster, containing a single app named "web"
+type tenant struct {
+	project *models.Project
+	cluster *models.Cluster
+	app     *models.PorterApp
+}
+
+// createTenants creates two projects whose clusters and apps have identical names, so that any
+// read which is not scoped by project ID would be able to return the other project's data
+func createTenants(t *testing.T, config *config.Config) (*models.User, tenant, tenant) {
+	user := apitest.CreateTestUser(t, config, true)
+
+	tenants := make([]tenant, 0, 2)
+	for _, projectName := range []string{"project-a", "project-b"} {
+		proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+			Name: projectName,
+		}, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cluster, err := config.Repo.Cluster().CreateCluster(&models.Cluster{
+			ProjectID: proj.ID,
+			Name:      "cluster",
+		}, config.LaunchDarklyClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		app, err := config.Repo.PorterApp().CreatePorterApp(&models.PorterApp{
+			ProjectID: proj.ID,
+			ClusterID: cluster.ID,
+			Name:      "web",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tenants = append(tenants, tenant{project: proj, cluster: cluster, app: app})
+	}
+
+	return user, tenants[0], tenants[1]
+}
+
+func getScopedRequestAndRecorder(t *testing.T, user *models.User, proj *models.Project, cluster *models.Cluster) (*http.Request, *httptest.ResponseRecorder) {
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/projects/1/clusters/1/applications/web", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+	req = apitest.WithCluster(t, req, cluster)
+	req = apitest.WithURLParams(t, req, map[string]string{
+		string(types.URLParamPorterAppName): "web",
+		string(types.URLParamAppRevisionID): uuid.New().String(),
+	})
+
+	return req, rr
+}
+
+func TestListPorterAppsScopedToProject(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user, tenantA, tenantB := createTenants(t, config)
+
+	handler := porter_app.NewPorterAppListHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	req, rr := getScopedRequestAndRecorder(t, user, tenantA.project, tenantA.cluster)
+	handler.ServeHTTP(rr, req)
+
+	expApps := types.ListPorterAppResponse{tenantA.app.ToPorterAppType()}
+	gotApps := types.ListPorterAppResponse{}
+
+	apitest.AssertResponseExpected(t, rr, &expApps, &gotApps)
+
+	// a cluster ID belonging to another project must not leak that project's apps
+	req, rr = getScopedRequestAndRecorder(t, user, tenantA.project, tenantB.cluster)
+	handler.ServeHTTP(rr, req)
+
+	expApps = types.ListPorterAppResponse{}
+	gotApps = types.ListPorterAppResponse{}
+
+	apitest.AssertResponseExpected(t, rr, &expApps, &gotApps)
+}
+
+func TestGetPorterAppScopedToProject(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user, tenantA, tenantB := createTenants(t, config)
+
+	handler := porter_app.NewGetPorterAppHandler(
+		config,
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	// each project sees its own app, even though both apps share a name
+	for _, tenant := range []tenant{tenantA, tenantB} {
+		req, rr := getScopedRequestAndRecorder(t, user, tenant.project, tenant.cluster)
+		handler.ServeHTTP(rr, req)
+
+		expApp := tenant.app.ToPorterAppType()
+		gotApp := &types.PorterApp{}
+
+		apitest.AssertResponseExpected(t, rr, expApp, gotApp)
+	}
+}
+
+func TestPorterAppReadsReturnNotFoundAcrossProjects(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user, tenantA, tenantB := createTenants(t, config)
+
+	writer := shared.NewDefaultResultWriter(config.Logger, config.Alerter)
+	decoderValidator := shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter)
+
+	handlers := map[string]http.Handler{
+		"get":                porter_app.NewGetPorterAppHandler(config, writer),
+		"get app template":   porter_app.NewGetAppTemplateHandler(config, decoderValidator, writer),
+		"list app revisions": porter_app.NewListAppRevisionsHandler(config, decoderValidator, writer),
+		"list events":        porter_app.NewPorterAppEventListHandler(config, decoderValidator, writer),
+		"report status":      porter_app.NewReportRevisionStatusHandler(config, decoderValidator, writer),
+		"snapshot":           porter_app.NewSnapshotPorterAppHandler(config, writer),
+	}
+
+	// both the other project's cluster ID, and a cluster ID which does not exist at all, must be
+	// indistinguishable from an app which does not exist
+	missingCluster := &models.Cluster{ProjectID: tenantA.project.ID}
+	missingCluster.ID = 1000
+
+	crafted := []*models.Cluster{tenantB.cluster, missingCluster}
+
+	for name, handler := range handlers {
+		for _, cluster := range crafted {
+			t.Run(name, func(t *testing.T) {
+				req, rr := getScopedRequestAndRecorder(t, user, tenantA.project, cluster)
+				handler.ServeHTTP(rr, req)
+
+				apitest.AssertResponseError(t, rr, http.StatusNotFound, &types.ExternalError{
+					Error: "Resource not found.",
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/porter-dev/porter/internal/webhooks"
+	"gorm.io/gorm"
+)
+
+// RedeliverWebhookHandler handles POST requests to the /apps/{porter_app_name}/alert-rules/{log_alert_rule_id}/deliveries/{webhook_delivery_id}/redeliver endpoint
+type RedeliverWebhookHandler struct {
+	handlers.PorterHandlerWriter
+	dispatcher *webhooks.Dispatcher
+}
+
+// NewRedeliverWebhookHandler returns a new RedeliverWebhookHandler
+func NewRedeliverWebhookHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *RedeliverWebhookHandler {
+	return &RedeliverWebhookHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+		dispatcher:          webhooks.NewDispatcherFromConfig(config),
+	}
+}
+
+// ServeHTTP sends the payload of a past delivery to the rule's webhook again, as a new chain of attempts marked as a
+// replay, and returns the last attempt
+func (c *RedeliverWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-redeliver-webhook")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing app name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	ruleID, reqErr := requestutils.GetURLParamUint(r, types.URLParamLogAlertRuleID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing log alert rule id from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	deliveryID, reqErr := requestutils.GetURLParamUint(r, types.URLParamWebhookDeliveryID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing webhook delivery id from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "log-alert-rule-id", Value: ruleID},
+		telemetry.AttributeKV{Key: "webhook-delivery-id", Value: deliveryID},
+	)
+
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rule, err := c.Repo().LogAlertRule().ReadLogAlertRule(ctx, project.ID, app.ID, ruleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "log alert rule not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the event is sent to the rule's current webhook, so that a delivery which failed because of a wrong url can be
+	// replayed once the url is fixed
+	if types.LogAlertChannel(rule.Channel) != types.LogAlertChannel_Webhook || rule.WebhookURL == "" {
+		err := telemetry.Error(ctx, span, nil, "log alert rule does not send its alerts to a webhook")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	delivery, err := c.Repo().WebhookDelivery().ReadWebhookDelivery(ctx, project.ID, rule.ID, deliveryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "webhook delivery not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading webhook delivery")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	attempt, err := c.dispatcher.Redeliver(ctx, delivery, rule.WebhookURL)
+	if err != nil {
+		if errors.Is(err, webhooks.ErrRedeliveryRateLimited) {
+			err = telemetry.Error(ctx, span, err, "webhook redelivery rate limited")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusTooManyRequests))
+			return
+		}
+		// a redelivery which the webhook rejected is still returned, so that the caller can see why it failed
+		if attempt == nil || attempt.ID == 0 {
+			err = telemetry.Error(ctx, span, err, "error redelivering webhook")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "redelivery-error", Value: err.Error()})
+	}
+
+	c.WriteResult(w, r, attempt.ToWebhookDeliveryType())
+}
@@ -0,0 +1,87 @@
+package porter_app
+
+// The env variables of a service are set, from highest to lowest precedence, by:
+//
+//  1. the env of the service's config in porter.yaml
+//  2. the top level env of porter.yaml
+//  3. the env of the app's current release which porter.yaml does not set, such as variables set through the env
+//     variable endpoints or the dashboard
+//
+// Updates with override_release set treat porter.yaml as the whole app, so the env of the current release is dropped
+// and only the variables porter.yaml sets are deployed.
+
+// releaseEnv returns a copy of the normal env variables of each service in the values of a release, by helm name
+func releaseEnv(values map[string]interface{}) map[string]map[string]interface{} {
+	env := make(map[string]map[string]interface{})
+
+	for helmName, serviceValues := range values {
+		normal := serviceNormalEnv(serviceValues)
+		if len(normal) == 0 {
+			continue
+		}
+
+		envCopy := make(map[string]interface{}, len(normal))
+		for k, v := range normal {
+			envCopy[k] = v
+		}
+		env[helmName] = envCopy
+	}
+
+	return env
+}
+
+// preserveReleaseEnv adds the env variables of the current release to the services in the new values which do not set
+// them, and returns the number of variables added. Variables set in the new values always take precedence.
+func preserveReleaseEnv(values map[string]interface{}, env map[string]map[string]interface{}) int {
+	var preserved int
+
+	for helmName, existing := range env {
+		serviceValues, ok := values[helmName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		container, ok := serviceValues["container"].(map[string]interface{})
+		if !ok {
+			container = make(map[string]interface{})
+			serviceValues["container"] = container
+		}
+		envValues, ok := container["env"].(map[string]interface{})
+		if !ok {
+			envValues = make(map[string]interface{})
+			container["env"] = envValues
+		}
+		normal, ok := envValues["normal"].(map[string]interface{})
+		if !ok {
+			normal = make(map[string]interface{})
+			envValues["normal"] = normal
+		}
+
+		for k, v := range existing {
+			if _, ok := normal[k]; !ok {
+				normal[k] = v
+				preserved++
+			}
+		}
+	}
+
+	return preserved
+}
+
+func serviceNormalEnv(serviceValues interface{}) map[string]interface{} {
+	serviceMap, ok := serviceValues.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	container, ok := serviceMap["container"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	env, ok := container["env"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	normal, _ := env["normal"].(map[string]interface{})
+
+	return normal
+}
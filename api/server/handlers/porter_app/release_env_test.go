@@ -0,0 +1,111 @@
+package porter_app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"gopkg.in/yaml.v2"
+)
+
+const releaseEnvPorterYaml = `version: v1stack
+env:
+  LOG_LEVEL: info
+services:
+  web:
+    type: web
+    run: node index.js
+    config:
+      container:
+        port: 8080
+      ingress:
+        enabled: false
+`
+
+// existingReleaseValues are the values of a release whose env was changed after its last deploy from porter.yaml
+func existingReleaseValues() map[string]interface{} {
+	return map[string]interface{}{
+		"web-web": map[string]interface{}{
+			"container": map[string]interface{}{
+				"port": 8080,
+				"env": map[string]interface{}{
+					"normal": map[string]interface{}{
+						"FOO":       "bar",
+						"LOG_LEVEL": "debug",
+					},
+				},
+			},
+		},
+	}
+}
+
+func buildReleaseEnvValues(t *testing.T, existingValues map[string]interface{}, overrideRelease bool) map[string]interface{} {
+	t.Helper()
+
+	parsed := &PorterStackYAML{}
+	if err := yaml.Unmarshal([]byte(releaseEnvPorterYaml), parsed); err != nil {
+		t.Fatalf("error parsing porter.yaml: %v", err)
+	}
+
+	application := &Application{Env: parsed.Env, Services: parsed.Services}
+	imageInfo := types.ImageInfo{Repository: "registry.example.com/storefront", Tag: "8f14e45f"}
+
+	values, _, err := buildUmbrellaChartValues(context.Background(), application, nil, imageInfo, nil, existingValues, SubdomainCreateOpts{}, false, false, false, "porter-stack-storefront", false, overrideRelease, types.ClusterSchedulingDefaults{}, nil, "storefront", nil)
+	if err != nil {
+		t.Fatalf("error building values: %v", err)
+	}
+
+	return values
+}
+
+func TestReleaseEnvPreservedOnUpdate(t *testing.T) {
+	existingValues := existingReleaseValues()
+	existingEnv := releaseEnv(existingValues)
+
+	values := buildReleaseEnvValues(t, existingValues, false)
+	preserveReleaseEnv(values, existingEnv)
+
+	normal := serviceNormalEnv(values["web-web"])
+	if normal["FOO"] != "bar" {
+		t.Errorf("expected FOO to be kept from the release, got %v", normal)
+	}
+	if normal["LOG_LEVEL"] != "info" {
+		t.Errorf("expected porter.yaml to take precedence over the release, got %v", normal)
+	}
+}
+
+func TestReleaseEnvDroppedWithOverrideRelease(t *testing.T) {
+	values := buildReleaseEnvValues(t, existingReleaseValues(), true)
+
+	normal := serviceNormalEnv(values["web-web"])
+	if _, ok := normal["FOO"]; ok {
+		t.Errorf("expected FOO to be removed when the release is overridden, got %v", normal)
+	}
+	if normal["LOG_LEVEL"] != "info" {
+		t.Errorf("expected the porter.yaml env to be deployed, got %v", normal)
+	}
+}
+
+func TestPreserveReleaseEnv(t *testing.T) {
+	env := releaseEnv(existingReleaseValues())
+
+	// full helm values replace those of the release, so they may not set the env at all
+	values := map[string]interface{}{
+		"web-web": map[string]interface{}{
+			"container": map[string]interface{}{"port": 8080},
+		},
+		"worker-wkr": map[string]interface{}{},
+	}
+
+	if preserved := preserveReleaseEnv(values, env); preserved != 2 {
+		t.Errorf("expected 2 variables to be preserved, got %d", preserved)
+	}
+
+	normal := serviceNormalEnv(values["web-web"])
+	if normal["FOO"] != "bar" || normal["LOG_LEVEL"] != "debug" {
+		t.Errorf("expected the env of the release to be kept, got %v", normal)
+	}
+	if serviceNormalEnv(values["worker-wkr"]) != nil {
+		t.Error("expected services which are not in the release to be left as they are")
+	}
+}
@@ -0,0 +1,550 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/domain"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// RenamePorterAppHandler handles POST /applications/{porter_app_name}/rename. Helm cannot rename a release, so the
+// releases of the app are installed under the new name in the namespace of the new name, along with the env groups,
+// secrets and image pull secrets they read from the namespace of the previous name. The releases of the previous name
+// are only uninstalled once the app is ready under its new name and its record is renamed. The events of the app stay
+// attached to it, and the porter.run subdomains of the app are repointed at the ingress of the cluster.
+type RenamePorterAppHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewRenamePorterAppHandler returns a new RenamePorterAppHandler
+func NewRenamePorterAppHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RenamePorterAppHandler {
+	return &RenamePorterAppHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *RenamePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-rename-porter-app")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.RenamePorterAppRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-name", Value: appName},
+		telemetry.AttributeKV{Key: "new-porter-app-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "migrate-release", Value: request.MigrateRelease},
+	)
+
+	name, nameErr := normalizeAppName(request.Name, c.Config().ServerConf.LowercasePorterAppNames)
+	if nameErr != nil {
+		err := telemetry.Error(ctx, span, nameErr, "invalid app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	request.Name = name
+
+	if errs := validation.IsDNS1123Label(utils.NamespaceFromPorterAppName(request.Name)); len(errs) != 0 {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("invalid app name %s: %s", request.Name, strings.Join(errs, ", ")))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if request.Name == appName {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("app is already named %s", appName))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	// the app stops running under its previous name, and is deployed under the new one
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Delete); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+	if err := authorizeAppAction(ctx, c.Config(), r, request.Name, types.PorterAppGrantPermission_Deploy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing access to the new app name")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the name is checked again when the app is renamed, but checking it first avoids installing releases for a
+	// rename which is refused
+	_, err = c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, request.Name)
+	if err == nil {
+		err = telemetry.Error(ctx, span, nil, fmt.Sprintf("an app named %s already exists in the cluster", request.Name))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading porter app by new name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	migration, apiErr := c.newReleaseMigration(ctx, r, cluster, appName, request)
+	if apiErr != nil {
+		c.HandleAPIError(w, r, apiErr)
+		return
+	}
+
+	if err := migration.install(ctx); err != nil {
+		err = telemetry.Error(ctx, span, err, "error installing releases under the new name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	expiresAt := time.Now().UTC().Add(c.Config().ServerConf.PorterAppRenameGracePeriod)
+	renamed, err := c.Repo().PorterApp().RenamePorterApp(ctx, porterApp, request.Name, expiresAt)
+	if err != nil || !renamed {
+		migration.uninstallRenamed(ctx)
+
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error renaming porter app")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, nil, fmt.Sprintf("an app named %s was created in the cluster during the rename", request.Name))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	// the app now runs, and is recorded, under its new name, so failing to clean up after its previous name is only
+	// recorded rather than failing the rename
+	if err := migration.uninstallPrevious(ctx); err != nil {
+		_ = telemetry.Error(ctx, span, err, "error uninstalling releases of the previous name")
+	}
+
+	if err := c.repointSubdomains(ctx, migration); err != nil {
+		_ = telemetry.Error(ctx, span, err, "error repointing subdomains")
+	}
+
+	c.WriteResult(w, r, porterApp.ToPorterAppType())
+}
+
+// releaseMigration moves the releases of an app, and of its pre-deploy job if it has one, to the new name of the app
+type releaseMigration struct {
+	helmAgent    *helm.Agent
+	newHelmAgent *helm.Agent
+	k8sAgent     *kubernetes.Agent
+
+	cluster    *models.Cluster
+	repo       repository.Repository
+	registries []*models.Registry
+
+	doConf                      *oauth2.Config
+	disablePullSecretsInjection bool
+	timeout                     time.Duration
+
+	appName      string
+	newName      string
+	namespace    string
+	newNamespace string
+
+	// releases are the releases of the previous name, the app release first
+	releases []*release.Release
+	// installed are the names of the releases installed under the new name so far
+	installed []string
+}
+
+// newReleaseMigration reads the releases of an app which are migrated to its new name
+func (c *RenamePorterAppHandler) newReleaseMigration(
+	ctx context.Context,
+	r *http.Request,
+	cluster *models.Cluster,
+	appName string,
+	request *types.RenamePorterAppRequest,
+) (*releaseMigration, apierrors.RequestError) {
+	ctx, span := telemetry.NewSpan(ctx, "new-porter-app-release-migration")
+	defer span.End()
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+	newNamespace := utils.NamespaceFromPorterAppName(request.Name)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		return nil, apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting helm agent"))
+	}
+
+	appRelease, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			err = telemetry.Error(ctx, span, err, fmt.Sprintf("app %s has no helm release in namespace %s: only apps deployed with porter apply v1 can be renamed", appName, namespace))
+			return nil, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest)
+		}
+		return nil, apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting app release"))
+	}
+
+	if !request.MigrateRelease {
+		err = telemetry.Error(ctx, span, nil, fmt.Sprintf("helm cannot rename the release of app %s in place: set migrate_release to reinstall it as %s, which replaces the pods of the app", appName, request.Name))
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest)
+	}
+
+	releases := []*release.Release{appRelease}
+
+	preDeployRelease, err := helmAgent.GetRelease(ctx, utils.PredeployJobNameFromPorterAppName(appName), 0, false)
+	if err == nil {
+		releases = append(releases, preDeployRelease)
+	} else if !errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting pre-deploy job release"))
+	}
+
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		return nil, apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting k8s agent"))
+	}
+
+	newHelmAgent, err := c.GetHelmAgent(ctx, r, cluster, newNamespace)
+	if err != nil {
+		return nil, apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error getting helm agent for new namespace"))
+	}
+
+	registries, err := c.Repo().Registry().ListRegistriesByProjectID(cluster.ProjectID)
+	if err != nil {
+		return nil, apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error listing registries"))
+	}
+
+	return &releaseMigration{
+		helmAgent:                   helmAgent,
+		newHelmAgent:                newHelmAgent,
+		k8sAgent:                    k8sAgent,
+		cluster:                     cluster,
+		repo:                        c.Repo(),
+		registries:                  registries,
+		doConf:                      c.Config().DOConf,
+		disablePullSecretsInjection: c.Config().ServerConf.DisablePullSecretsInjection,
+		timeout:                     c.Config().ServerConf.HelmTimeout,
+		appName:                     appName,
+		newName:                     request.Name,
+		namespace:                   namespace,
+		newNamespace:                newNamespace,
+		releases:                    releases,
+	}, nil
+}
+
+// renamedReleaseName returns the name a release of the app is installed under once the app is renamed
+func (m *releaseMigration) renamedReleaseName(rel *release.Release) string {
+	return m.newName + strings.TrimPrefix(rel.Name, m.appName)
+}
+
+// install copies the config of the app to the namespace of its new name and installs its releases there, waiting for
+// the app to become ready. The releases of the previous name are left running. If a release fails to install, those
+// installed under the new name are uninstalled again.
+func (m *releaseMigration) install(ctx context.Context) error {
+	ctx, span := telemetry.NewSpan(ctx, "install-renamed-porter-app-releases")
+	defer span.End()
+
+	if _, err := m.k8sAgent.CreateNamespace(ctx, m.newNamespace, nil); err != nil {
+		return telemetry.Error(ctx, span, err, "error creating namespace")
+	}
+
+	// env groups, secrets and the image pull secrets of the app are copied, since the releases read them by name
+	if err := copyAppConfig(ctx, m.k8sAgent.Clientset, m.namespace, m.newNamespace, m.appName, m.newName); err != nil {
+		return telemetry.Error(ctx, span, err, "error copying app config to new namespace")
+	}
+
+	for _, rel := range m.releases {
+		newName := m.renamedReleaseName(rel)
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: telemetry.AttributeKey(fmt.Sprintf("migrate-%s", rel.Name)), Value: newName})
+
+		_, err := m.newHelmAgent.InstallChart(ctx, &helm.InstallChartConfig{
+			Chart:      chartWithoutDependencies(rel.Chart),
+			Name:       newName,
+			Namespace:  m.newNamespace,
+			Values:     renamedAppValues(rel.Config, m.appName, m.newName),
+			Cluster:    m.cluster,
+			Repo:       m.repo,
+			Registries: m.registries,
+			Timeout:    m.timeout,
+			// only the app waits for its deployments to become ready, as the pre-deploy job runs again when its
+			// release is installed
+			WaitForJobs: rel.Name == m.appName,
+		}, m.doConf, m.disablePullSecretsInjection)
+		if err != nil {
+			// a release which failed to become ready is still installed
+			m.installed = append(m.installed, newName)
+			m.uninstallRenamed(ctx)
+
+			return telemetry.Error(ctx, span, err, fmt.Sprintf("error installing release %s as %s", rel.Name, newName))
+		}
+
+		m.installed = append(m.installed, newName)
+	}
+
+	return nil
+}
+
+// uninstallRenamed uninstalls the releases installed under the new name. Failures are only recorded, as the error
+// which caused the uninstall is the one reported.
+func (m *releaseMigration) uninstallRenamed(ctx context.Context) {
+	ctx, span := telemetry.NewSpan(ctx, "uninstall-renamed-porter-app-releases")
+	defer span.End()
+
+	for _, name := range m.installed {
+		if _, err := m.newHelmAgent.UninstallChart(ctx, name); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+			_ = telemetry.Error(ctx, span, err, fmt.Sprintf("error uninstalling release %s", name))
+		}
+	}
+	m.installed = nil
+}
+
+// uninstallPrevious uninstalls the releases of the previous name, the pre-deploy job first
+func (m *releaseMigration) uninstallPrevious(ctx context.Context) error {
+	ctx, span := telemetry.NewSpan(ctx, "uninstall-previous-porter-app-releases")
+	defer span.End()
+
+	var errs []string
+	for i := len(m.releases) - 1; i >= 0; i-- {
+		name := m.releases[i].Name
+		if _, err := m.helmAgent.UninstallChart(ctx, name); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+			errs = append(errs, fmt.Sprintf("%s: %s", name, err.Error()))
+		}
+	}
+
+	if len(errs) != 0 {
+		return telemetry.Error(ctx, span, nil, fmt.Sprintf("error uninstalling releases %s", strings.Join(errs, ", ")))
+	}
+
+	return nil
+}
+
+// repointSubdomains points the porter.run subdomains of the app at the ingress of the cluster, which serves the
+// releases of its new name
+func (c *RenamePorterAppHandler) repointSubdomains(ctx context.Context, m *releaseMigration) error {
+	ctx, span := telemetry.NewSpan(ctx, "repoint-porter-app-subdomains")
+	defer span.End()
+
+	hosts := porterHostsFromValues(m.releases[0].Config)
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-hosts", Value: strings.Join(hosts, ",")})
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	endpoint, found, err := domain.GetNGINXIngressServiceIP(m.k8sAgent.Clientset)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error getting nginx ingress service ip")
+	}
+	if !found || endpoint == "" {
+		return telemetry.Error(ctx, span, nil, "nginx ingress service ip not found")
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "nginx-ingress-ip", Value: endpoint})
+
+	records, err := c.Repo().DNSRecord().UpdateDNSRecordEndpointsByHostname(hosts, endpoint)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error updating dns records")
+	}
+
+	if c.Config().DNSClient == nil {
+		return nil
+	}
+
+	for _, record := range records {
+		_record := domain.DNSRecord(*record)
+		if err := _record.CreateDomain(c.Config().DNSClient); err != nil {
+			return telemetry.Error(ctx, span, err, fmt.Sprintf("error repointing domain %s", record.Hostname))
+		}
+	}
+
+	return nil
+}
+
+// chartWithoutDependencies returns a copy of the chart of a release without the dependencies it was installed with,
+// which are loaded again from their repos when the chart is installed
+func chartWithoutDependencies(ch *chart.Chart) *chart.Chart {
+	copied := *ch
+	copied.SetDependencies()
+
+	return &copied
+}
+
+// copyAppConfig copies the config maps and secrets of an app, such as its env groups, to the namespace of its new name.
+// Those created by helm are left out: the releases of the app create them again when they are installed.
+func copyAppConfig(ctx context.Context, clientset k8s.Interface, namespace, newNamespace, appName, newName string) error {
+	return copyAppConfigBetween(ctx, clientset, clientset, namespace, newNamespace, appName, newName, false)
+}
+
+// copyAppConfigBetween copies the config of an app as copyAppConfig does, from a namespace of the source cluster to one
+// of the target cluster. If skipPorterPullSecrets is set, the image pull secrets which Porter generates are left out
+// too, so that the releases create them again from the registries of the project.
+func copyAppConfigBetween(ctx context.Context, source, target k8s.Interface, namespace, newNamespace, appName, newName string, skipPorterPullSecrets bool) error {
+	configMaps, err := source.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing config maps: %w", err)
+	}
+
+	for _, cm := range configMaps.Items {
+		if isHelmManaged(cm.ObjectMeta) || cm.Name == "kube-root-ca.crt" {
+			continue
+		}
+
+		copied := &corev1.ConfigMap{
+			ObjectMeta: copiedObjectMeta(cm.ObjectMeta, newNamespace, appName, newName),
+			Data:       cm.Data,
+			BinaryData: cm.BinaryData,
+		}
+
+		_, err := target.CoreV1().ConfigMaps(newNamespace).Create(ctx, copied, metav1.CreateOptions{})
+		if k8serrors.IsAlreadyExists(err) {
+			_, err = target.CoreV1().ConfigMaps(newNamespace).Update(ctx, copied, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("error copying config map %s: %w", cm.Name, err)
+		}
+	}
+
+	secrets, err := source.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing secrets: %w", err)
+	}
+
+	for _, secret := range secrets.Items {
+		if isHelmManaged(secret.ObjectMeta) || secret.Type == corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+		if skipPorterPullSecrets && secret.Type == corev1.SecretTypeDockerConfigJson && isPorterPullSecretName(secret.Name) {
+			continue
+		}
+
+		copied := &corev1.Secret{
+			ObjectMeta: copiedObjectMeta(secret.ObjectMeta, newNamespace, appName, newName),
+			Type:       secret.Type,
+			Data:       secret.Data,
+		}
+
+		_, err := target.CoreV1().Secrets(newNamespace).Create(ctx, copied, metav1.CreateOptions{})
+		if k8serrors.IsAlreadyExists(err) {
+			_, err = target.CoreV1().Secrets(newNamespace).Update(ctx, copied, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("error copying secret %s: %w", secret.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// isHelmManaged reports whether a resource is the storage of a helm release, or was created by one
+func isHelmManaged(meta metav1.ObjectMeta) bool {
+	return meta.Labels["owner"] == "helm" || meta.Labels["app.kubernetes.io/managed-by"] == "Helm"
+}
+
+func copiedObjectMeta(meta metav1.ObjectMeta, namespace, appName, newName string) metav1.ObjectMeta {
+	labels := make(map[string]string, len(meta.Labels))
+	for k, v := range meta.Labels {
+		labels[k] = v
+	}
+	if labels[porter_app.LabelKey_AppName] == appName {
+		labels[porter_app.LabelKey_AppName] = newName
+	}
+
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   namespace,
+		Labels:      labels,
+		Annotations: meta.Annotations,
+	}
+}
+
+// renamedAppValues returns a copy of the helm values of an app in which the part-of label of its services, and of its
+// pre-deploy job, is set to the new name of the app
+func renamedAppValues(values map[string]interface{}, appName, newName string) map[string]interface{} {
+	renamed, _ := copyValue(values).(map[string]interface{})
+	if renamed == nil {
+		return nil
+	}
+
+	relabel := func(values map[string]interface{}) {
+		for _, key := range []string{"labels", "podLabels"} {
+			if labels, ok := values[key].(map[string]interface{}); ok && labels[porter_app.LabelKey_PartOf] == appName {
+				labels[porter_app.LabelKey_PartOf] = newName
+			}
+		}
+	}
+
+	relabel(renamed)
+	for _, serviceValues := range renamed {
+		if service, ok := serviceValues.(map[string]interface{}); ok {
+			relabel(service)
+		}
+	}
+
+	return renamed
+}
+
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			copied[key] = copyValue(val)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, val := range v {
+			copied[i] = copyValue(val)
+		}
+		return copied
+	default:
+		return v
+	}
+}
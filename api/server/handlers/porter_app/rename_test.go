@@ -0,0 +1,111 @@
+package porter_app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/porter_app"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCopyAppConfig(t *testing.T) {
+	ctx := context.Background()
+	namespace := "porter-stack-payments"
+	newNamespace := "porter-stack-checkout"
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "payments.v3",
+				Namespace: namespace,
+				Labels:    map[string]string{"envgroup": "payments", porter_app.LabelKey_AppName: "payments"},
+			},
+			Data: map[string]string{"LOG_LEVEL": "info"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments-web-config", Namespace: namespace, Labels: map[string]string{"app.kubernetes.io/managed-by": "Helm"}},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: namespace},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments-external-secrets", Namespace: namespace},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"DB_PASSWORD": []byte("hunter2")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.payments.v3", Namespace: namespace, Labels: map[string]string{"owner": "helm"}},
+			Type:       "helm.sh/release.v1",
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-token-x7k2p", Namespace: namespace},
+			Type:       corev1.SecretTypeServiceAccountToken,
+		},
+	)
+
+	if err := copyAppConfig(ctx, clientset, namespace, newNamespace, "payments", "checkout"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	configMaps, err := clientset.CoreV1().ConfigMaps(newNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(configMaps.Items) != 1 || configMaps.Items[0].Name != "payments.v3" {
+		t.Fatalf("expected only the env group config map to be copied, got %v", configMaps.Items)
+	}
+	if got := configMaps.Items[0].Labels[porter_app.LabelKey_AppName]; got != "checkout" {
+		t.Errorf("expected the app name label to be checkout, got %s", got)
+	}
+	if got := configMaps.Items[0].Data["LOG_LEVEL"]; got != "info" {
+		t.Errorf("expected the config map data to be copied, got %s", got)
+	}
+
+	secrets, err := clientset.CoreV1().Secrets(newNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(secrets.Items) != 1 || secrets.Items[0].Name != "payments-external-secrets" {
+		t.Fatalf("expected only the external secrets to be copied, got %v", secrets.Items)
+	}
+
+	// copying again, as a retried rename does, updates the copies
+	if err := copyAppConfig(ctx, clientset, namespace, newNamespace, "payments", "checkout"); err != nil {
+		t.Fatalf("unexpected error copying again: %v", err)
+	}
+}
+
+func TestRenamedAppValues(t *testing.T) {
+	values := map[string]interface{}{
+		"web": map[string]interface{}{
+			"labels":       map[string]interface{}{porter_app.LabelKey_PartOf: "payments", "team": "billing"},
+			"podLabels":    map[string]interface{}{porter_app.LabelKey_PartOf: "payments"},
+			"replicaCount": 2,
+		},
+		"global": map[string]interface{}{"image": map[string]interface{}{"tag": "v1"}},
+		"labels": map[string]interface{}{porter_app.LabelKey_PartOf: "payments"},
+	}
+
+	renamed := renamedAppValues(values, "payments", "checkout")
+
+	web := renamed["web"].(map[string]interface{})
+	if got := web["labels"].(map[string]interface{})[porter_app.LabelKey_PartOf]; got != "checkout" {
+		t.Errorf("expected the service label to be renamed, got %v", got)
+	}
+	if got := web["podLabels"].(map[string]interface{})[porter_app.LabelKey_PartOf]; got != "checkout" {
+		t.Errorf("expected the pod label to be renamed, got %v", got)
+	}
+	if got := web["labels"].(map[string]interface{})["team"]; got != "billing" {
+		t.Errorf("expected other labels to be kept, got %v", got)
+	}
+	if got := renamed["labels"].(map[string]interface{})[porter_app.LabelKey_PartOf]; got != "checkout" {
+		t.Errorf("expected the pre-deploy job label to be renamed, got %v", got)
+	}
+
+	original := values["web"].(map[string]interface{})["labels"].(map[string]interface{})[porter_app.LabelKey_PartOf]
+	if original != "payments" {
+		t.Errorf("expected the values of the release to be left unchanged, got %v", original)
+	}
+}
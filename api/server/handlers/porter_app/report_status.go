@@ -3,6 +3,7 @@ package porter_app
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -22,6 +23,7 @@ import (
 	"github.com/porter-dev/porter/internal/porter_app"
 	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
 	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
 	"k8s.io/utils/pointer"
 )
 
@@ -93,17 +95,17 @@ func (c *ReportRevisionStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.R
 	}
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-revision-id", Value: appRevisionUuid.String()})
 
-	porterApp, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
+	porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
 		err := telemetry.Error(ctx, span, err, "error reading porter app by name")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
-	if porterApp.ID == 0 {
-		err := telemetry.Error(ctx, span, nil, "porter app not found")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
-		return
-	}
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-id", Value: porterApp.ID})
 
 	request := &ReportRevisionStatusRequest{}
@@ -146,6 +148,31 @@ func (c *ReportRevisionStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.R
 
 	resp := &ReportRevisionStatusResponse{}
 
+	if porterApp.PullRequestURL != "" && porter_app.IsDeploySummaryStatus(revision.Status) {
+		// the deploy has already happened, so a failure to comment on the pull request is recorded but not returned
+		var deployMessage string
+		deployEvent, err := c.Repo().PorterAppEvent().ReadDeployEventByAppRevisionID(ctx, porterApp.ID, revision.ID)
+		if err == nil {
+			deployMessage = deployEvent.Message
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = telemetry.Error(ctx, span, err, "error reading deploy event for the deploy summary comment")
+		}
+
+		err = writeDeploySummaryComment(ctx, writeDeploySummaryCommentInput{
+			revision:        revision,
+			project:         project,
+			porterApp:       porterApp,
+			commitSha:       request.CommitSHA,
+			message:         deployMessage,
+			serverURL:       c.Config().ServerConf.ServerURL,
+			githubAppSecret: c.Config().ServerConf.GithubAppSecret,
+			githubAppID:     c.Config().ServerConf.GithubAppID,
+		})
+		if err != nil {
+			_ = telemetry.Error(ctx, span, err, "error writing deploy summary comment")
+		}
+	}
+
 	if !deploymentTarget.IsPreview || request.PRNumber == 0 || revision.RevisionNumber > 1 {
 		c.WriteResult(w, r, resp)
 		return
@@ -271,3 +298,92 @@ func writePRComment(ctx context.Context, inp writePRCommentInput) error {
 
 	return nil
 }
+
+type writeDeploySummaryCommentInput struct {
+	revision  porter_app.Revision
+	project   *models.Project
+	porterApp *models.PorterApp
+	commitSha string
+	// message is the release notes of the deploy
+	message   string
+	serverURL string
+
+	githubAppSecret []byte
+	githubAppID     string
+}
+
+// writeDeploySummaryComment upserts the deploy summary comment on the pull request linked to the app, unless comments are turned off for the project or app
+func writeDeploySummaryComment(ctx context.Context, inp writeDeploySummaryCommentInput) error {
+	ctx, span := telemetry.NewSpan(ctx, "write-deploy-summary-comment")
+	defer span.End()
+
+	if inp.project == nil {
+		return telemetry.Error(ctx, span, nil, "project is nil")
+	}
+	if inp.porterApp == nil {
+		return telemetry.Error(ctx, span, nil, "porter app is nil")
+	}
+	if inp.project.DeploySummaryCommentsDisabled || inp.porterApp.DeploySummaryCommentsDisabled {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "deploy-summary-comments-disabled", Value: true})
+		return nil
+	}
+	if inp.githubAppSecret == nil {
+		return telemetry.Error(ctx, span, nil, "github app secret is empty")
+	}
+	if inp.githubAppID == "" {
+		return telemetry.Error(ctx, span, nil, "github app id is empty")
+	}
+
+	pullRequest, err := porter_app.ParsePullRequestURL(inp.porterApp.PullRequestURL)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error parsing pull request url")
+	}
+
+	client, err := porter_app.GetGithubClientByRepoID(ctx, inp.porterApp.GitRepoID, inp.githubAppSecret, inp.githubAppID)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error getting github client")
+	}
+
+	summary := porter_app.DeploySummary{
+		AppName:        inp.porterApp.Name,
+		RevisionNumber: inp.revision.RevisionNumber,
+		Status:         inp.revision.Status,
+		CommitSHA:      inp.commitSha,
+		Message:        inp.message,
+	}
+	if inp.serverURL != "" {
+		summary.DashboardURL = fmt.Sprintf("%s/apps/%s", inp.serverURL, inp.porterApp.Name)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(inp.revision.B64AppProto)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error decoding base proto")
+	}
+
+	appProto := &porterv1.PorterApp{}
+	err = helpers.UnmarshalContractObject(decoded, appProto)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error unmarshalling app proto")
+	}
+
+	app, err := v2.AppFromProto(appProto)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error converting app proto to app")
+	}
+
+	if app.Image != nil {
+		summary.ImageTag = app.Image.Tag
+	}
+	for _, service := range app.Services {
+		for _, domain := range service.Domains {
+			summary.AppURLs = append(summary.AppURLs, domain.Name)
+		}
+	}
+
+	return porter_app.UpsertDeploySummaryComment(ctx, porter_app.UpsertDeploySummaryCommentInput{
+		Client:                 client,
+		PullRequest:            pullRequest,
+		Summary:                summary,
+		SkipClosedPullRequests: inp.project.DeploySummaryCommentsSkipClosedPRs,
+	})
+}
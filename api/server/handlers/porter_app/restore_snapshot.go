@@ -0,0 +1,217 @@
+package porter_app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// RestorePorterAppSnapshotHandler handles POST /applications/snapshots/restore, which recreates a stack from a snapshot
+type RestorePorterAppSnapshotHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewRestorePorterAppSnapshotHandler returns a new RestorePorterAppSnapshotHandler
+func NewRestorePorterAppSnapshotHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RestorePorterAppSnapshotHandler {
+	return &RestorePorterAppSnapshotHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *RestorePorterAppSnapshotHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-restore-porter-app-snapshot")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.RestoreStackSnapshotRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	snapshot := request.Snapshot
+	if snapshot.Version != types.StackSnapshotVersion {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("unsupported snapshot version %q", snapshot.Version))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	if snapshot.App == nil {
+		err := telemetry.Error(ctx, span, nil, "snapshot does not contain an app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	appName := request.Name
+	if appName == "" {
+		appName = snapshot.App.Name
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "stack-name", Value: appName},
+		telemetry.AttributeKV{Key: "snapshot-stack-name", Value: snapshot.App.Name},
+	)
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Deploy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	_, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error checking for existing porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+	if err == nil {
+		err = telemetry.Error(ctx, span, nil, fmt.Sprintf("stack %s already exists in this cluster", appName))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	registries, err := c.Repo().Registry().ListRegistriesByProjectID(cluster.ProjectID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing registries")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	snapshotValues, err := porter_app.StackSnapshotRestoreValues(snapshot, appName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting snapshot values")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	valuesYaml, err := yaml.Marshal(snapshotValues)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error marshalling snapshot values to yaml")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	imageInfo := attemptToGetImageInfoFromRelease(snapshotValues)
+	if imageInfo.Tag == "" {
+		imageInfo.Tag = "latest"
+	}
+
+	injectLauncher := strings.Contains(snapshot.App.Builder, "heroku") ||
+		strings.Contains(snapshot.App.Builder, "paketo")
+
+	// the chart is rebuilt the same way as a rollback, so that the restored stack uses the current application templates
+	chart, values, _, _, err := parse(
+		ctx,
+		ParseConf{
+			PorterAppName: appName,
+			ImageInfo:     imageInfo,
+			ServerConfig:  c.Config(),
+			ProjectID:     cluster.ProjectID,
+			Namespace:     namespace,
+			SubdomainCreateOpts: SubdomainCreateOpts{
+				k8sAgent:      k8sAgent,
+				dnsRepo:       c.Repo().DNSRecord(),
+				dnsClient:     c.Config().DNSClient,
+				appRootDomain: c.Config().ServerConf.AppRootDomain,
+				stackName:     appName,
+				clusterIssuer: c.Config().ServerConf.CustomDomainClusterIssuer,
+			},
+			InjectLauncherToStartCommand: injectLauncher,
+			FullHelmValues:               string(valuesYaml),
+			SchedulingDefaults:           types.ClusterSchedulingDefaults(cluster.SchedulingDefaults),
+			Capabilities:                 cluster.Capabilities.ToClusterCapabilitiesType(),
+			NetworkPolicies:              cluster.NetworkPoliciesEnabled,
+			IngressNamespace:             c.Config().ServerConf.NetworkPolicyIngressNamespace,
+			Observability:                project.ObservabilityConfig.ToProjectObservabilityConfigType(),
+		},
+	)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error parsing snapshot values")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	output, err := porter_app.RestoreStack(ctx, porter_app.RestoreStackInput{
+		Snapshot:                    snapshot,
+		Name:                        appName,
+		Namespace:                   namespace,
+		Chart:                       chart,
+		Values:                      values,
+		Cluster:                     cluster,
+		Repo:                        c.Repo(),
+		Registries:                  registries,
+		HelmAgent:                   helmAgent,
+		K8sAgent:                    k8sAgent,
+		DOConf:                      c.Config().DOConf,
+		DisablePullSecretsInjection: c.Config().ServerConf.DisablePullSecretsInjection,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error restoring stack")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	porterApp, err := c.Repo().PorterApp().CreatePorterApp(&models.PorterApp{
+		ProjectID:      cluster.ProjectID,
+		ClusterID:      cluster.ID,
+		Name:           appName,
+		ImageRepoURI:   snapshot.App.ImageRepoURI,
+		GitRepoID:      snapshot.App.GitRepoID,
+		RepoName:       snapshot.App.RepoName,
+		GitBranch:      snapshot.App.GitBranch,
+		BuildContext:   snapshot.App.BuildContext,
+		Builder:        snapshot.App.Builder,
+		Buildpacks:     snapshot.App.Buildpacks,
+		Dockerfile:     snapshot.App.Dockerfile,
+		PullRequestURL: snapshot.App.PullRequestURL,
+		PorterYamlPath: snapshot.App.PorterYamlPath,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error writing restored app to DB")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.RestoreStackSnapshotResponse{
+		App:               porterApp.ToPorterAppType(),
+		Namespace:         namespace,
+		ReattachedVolumes: output.ReattachedVolumes,
+		NewVolumes:        output.NewVolumes,
+		MissingEnvGroups:  output.MissingEnvGroups,
+	})
+}
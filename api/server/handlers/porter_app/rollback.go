@@ -1,7 +1,12 @@
 package porter_app
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/porter-dev/porter/api/server/authz"
@@ -14,9 +19,14 @@ import (
 	"github.com/porter-dev/porter/api/types"
 	utils "github.com/porter-dev/porter/api/utils/porter_app"
 	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/loader"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/registry"
 	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
 	"gopkg.in/yaml.v2"
+	"gorm.io/gorm"
 )
 
 type RollbackPorterAppHandler struct {
@@ -38,6 +48,7 @@ func NewRollbackPorterAppHandler(
 func (c *RollbackPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx, span := telemetry.NewSpan(r.Context(), "serve-rollback-porter-app")
 	defer span.End()
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
 	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
 
 	request := &types.RollbackPorterAppRequest{}
@@ -54,6 +65,13 @@ func (c *RollbackPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "stack-name", Value: appName})
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Deploy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
 	namespace := utils.NamespaceFromPorterAppName(appName)
 
 	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
@@ -70,13 +88,21 @@ func (c *RollbackPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	helmReleaseFromRequestedRevision, err := helmAgent.GetRelease(ctx, appName, request.Revision, false)
+	history, err := helmAgent.GetReleaseHistory(ctx, appName)
 	if err != nil {
-		err = telemetry.Error(ctx, span, err, "error getting helm release for requested revision")
+		err = telemetry.Error(ctx, span, err, "error getting helm release history")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
 
+	helmReleaseFromRequestedRevision, availableRevisions := releaseRevision(history, request.Revision)
+	if helmReleaseFromRequestedRevision == nil {
+		err = telemetry.Error(ctx, span, nil, fmt.Sprintf("revision %d does not exist, available revisions are %s", request.Revision, joinRevisions(availableRevisions)))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "requested-revision", Value: request.Revision})
+
 	latestHelmRelease, err := helmAgent.GetRelease(ctx, appName, 0, false)
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "error getting latest helm release")
@@ -96,8 +122,13 @@ func (c *RollbackPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		imageInfo.Tag = "latest"
 	}
 
-	porterApp, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
+	porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
 		err = telemetry.Error(ctx, span, err, "error getting porter app")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
@@ -112,7 +143,15 @@ func (c *RollbackPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	chart, values, _, err := parse(
+	registries, registryWarnings, err := registry.DeployRegistries(c.Repo(), registries, imageInfo.Repository, c.Config().DOConf)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error checking registry credentials")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "skipped-registries", Value: len(registryWarnings)})
+
+	chart, values, _, _, err := parse(
 		ctx,
 		ParseConf{
 			PorterAppName: appName,
@@ -126,9 +165,15 @@ func (c *RollbackPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 				dnsClient:     c.Config().DNSClient,
 				appRootDomain: c.Config().ServerConf.AppRootDomain,
 				stackName:     appName,
+				clusterIssuer: c.Config().ServerConf.CustomDomainClusterIssuer,
 			},
 			InjectLauncherToStartCommand: injectLauncher,
 			FullHelmValues:               string(valuesYaml),
+			SchedulingDefaults:           types.ClusterSchedulingDefaults(cluster.SchedulingDefaults),
+			Capabilities:                 cluster.Capabilities.ToClusterCapabilitiesType(),
+			NetworkPolicies:              cluster.NetworkPoliciesEnabled,
+			IngressNamespace:             c.Config().ServerConf.NetworkPolicyIngressNamespace,
+			Observability:                project.ObservabilityConfig.ToProjectObservabilityConfigType(),
 		},
 	)
 	if err != nil {
@@ -147,17 +192,37 @@ func (c *RollbackPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		Registries: registries,
 	}
 	_, err = helmAgent.UpgradeInstallChart(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection)
+	// status reads cached before the rollback are stale whether or not it succeeded
+	c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appName)
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "error upgrading application")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
 		return
 	}
 
+	releaseJobRevision, err := rollbackReleaseJob(ctx, helmAgent, appName, helmReleaseFromRequestedRevision, latestHelmRelease.Version)
+	if err != nil {
+		// the app is restored to the revision it was at when the release job cannot be rolled back
+		c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appName)
+
+		err = telemetry.Error(ctx, span, err, "error rolling back release job")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "release-job-revision", Value: releaseJobRevision})
+
+	details := deployEventDetails{
+		ChartDigests:     loader.ChartDigests(chart),
+		RegistryWarnings: registryWarnings,
+		RollbackFrom:     latestHelmRelease.Version,
+		RollbackTo:       request.Revision,
+	}
+
 	if features.AreAgentDeployEventsEnabled(k8sAgent) {
 		serviceDeploymentStatusMap := getServiceDeploymentMetadataFromValues(values, types.PorterAppEventStatus_Progressing)
-		_, err = createNewPorterAppDeployEvent(ctx, serviceDeploymentStatusMap, porterApp.ID, latestHelmRelease.Version+1, imageInfo.Tag, c.Repo().PorterAppEvent())
+		_, err = createNewPorterAppDeployEvent(ctx, serviceDeploymentStatusMap, porterApp.ID, latestHelmRelease.Version+1, imageInfo.Tag, details, c.Repo().PorterAppEvent())
 	} else {
-		_, err = createOldPorterAppDeployEvent(ctx, types.PorterAppEventStatus_Success, porterApp.ID, latestHelmRelease.Version+1, imageInfo.Tag, c.Repo().PorterAppEvent())
+		_, err = createOldPorterAppDeployEvent(ctx, types.PorterAppEventStatus_Success, porterApp.ID, latestHelmRelease.Version+1, imageInfo.Tag, details, c.Repo().PorterAppEvent())
 	}
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "error creating porter app event")
@@ -165,3 +230,103 @@ func (c *RollbackPorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 }
+
+// rollbackReleaseJob rolls the release job chart of an app back to the revision which was deployed with target, the
+// revision of the app chart being rolled back to, and returns that revision. Nothing is rolled back if the app has no
+// release job, or had none when target was deployed. The app chart has already been rolled back when this is called, so
+// if the release job fails to roll back the app chart is restored to appRevision, the revision it was on before, rather
+// than leaving the two charts at mismatched revisions.
+func rollbackReleaseJob(ctx context.Context, helmAgent *helm.Agent, appName string, target *release.Release, appRevision int) (int, error) {
+	ctx, span := telemetry.NewSpan(ctx, "rollback-release-job")
+	defer span.End()
+
+	jobName := utils.PredeployJobNameFromPorterAppName(appName)
+
+	history, err := helmAgent.GetReleaseHistory(ctx, jobName)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return 0, nil
+		}
+		return 0, telemetry.Error(ctx, span, err, "error getting release job history")
+	}
+
+	revision, latest := releaseJobRevisionAt(history, target)
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "release-job-revision", Value: revision},
+		telemetry.AttributeKV{Key: "release-job-latest-revision", Value: latest},
+	)
+	if revision == 0 || revision == latest {
+		return revision, nil
+	}
+
+	err = helmAgent.RollbackRelease(ctx, jobName, revision)
+	if err == nil {
+		return revision, nil
+	}
+
+	restoreErr := helmAgent.RollbackRelease(ctx, appName, appRevision)
+	if restoreErr != nil {
+		err = fmt.Errorf(
+			"error rolling back release job to revision %d, and error restoring the app to revision %d (%s): the app is running revision %d while the release job is still at revision %d: %w",
+			revision, appRevision, restoreErr.Error(), target.Version, latest, err,
+		)
+		return 0, telemetry.Error(ctx, span, err, "error rolling back release job and restoring app")
+	}
+
+	err = fmt.Errorf("error rolling back release job to revision %d, the app was restored to revision %d: %w", revision, appRevision, err)
+	return 0, telemetry.Error(ctx, span, err, "error rolling back release job")
+}
+
+// releaseJobRevisionAt returns the revision of the release job which was deployed when target was deployed, or 0 if there
+// was none, along with the latest revision of the release job. The release job is upgraded right before the app chart on
+// each deploy, so this is the last revision deployed no later than target.
+func releaseJobRevisionAt(history []*release.Release, target *release.Release) (int, int) {
+	var revision, latest int
+
+	for _, rel := range history {
+		if rel.Version > latest {
+			latest = rel.Version
+		}
+
+		if rel.Info == nil || target.Info == nil || rel.Info.LastDeployed.After(target.Info.LastDeployed) {
+			continue
+		}
+		if rel.Version > revision {
+			revision = rel.Version
+		}
+	}
+
+	return revision, latest
+}
+
+// releaseRevision returns the revision of a release with the given version from its history, or nil if it does not
+// exist, along with every version in the history in ascending order
+func releaseRevision(history []*release.Release, version int) (*release.Release, []int) {
+	var target *release.Release
+	versions := make([]int, 0, len(history))
+
+	for _, rel := range history {
+		versions = append(versions, rel.Version)
+
+		if rel.Version == version {
+			target = rel
+		}
+	}
+
+	sort.Ints(versions)
+
+	return target, versions
+}
+
+func joinRevisions(versions []int) string {
+	if len(versions) == 0 {
+		return "none"
+	}
+
+	parts := make([]string, 0, len(versions))
+	for _, version := range versions {
+		parts = append(parts, strconv.Itoa(version))
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,173 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	kubefake "github.com/stefanmcshane/helm/pkg/kube/fake"
+	"github.com/stefanmcshane/helm/pkg/release"
+	helmtime "github.com/stefanmcshane/helm/pkg/time"
+)
+
+// deployedAt returns a revision of a release deployed the given number of minutes after the first
+func deployedAt(name string, version int, minutes int, status release.Status) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: "porter-stack-payments",
+		Version:   version,
+		Info: &release.Info{
+			Status:       status,
+			LastDeployed: helmtime.Unix(0, 0).Add(time.Duration(minutes) * time.Minute),
+		},
+		Chart:  &chart.Chart{Metadata: &chart.Metadata{Name: name, Version: "0.1.0"}},
+		Config: map[string]any{"version": version},
+	}
+}
+
+// deployStack stores the revisions of an app and its release job, as deployed by each run of porter apply: the
+// release job is upgraded at revisions 1 and 3 of the app, right before the app chart
+func deployStack(t *testing.T) *helm.Agent {
+	t.Helper()
+
+	helmAgent := helm.GetAgentTesting(&helm.Form{Namespace: "porter-stack-payments"}, nil, logger.NewConsole(true), kubernetes.GetAgentTesting())
+
+	revisions := []*release.Release{
+		deployedAt("payments-r", 1, 0, release.StatusSuperseded),
+		deployedAt("payments", 1, 1, release.StatusSuperseded),
+		deployedAt("payments", 2, 10, release.StatusSuperseded),
+		deployedAt("payments-r", 2, 20, release.StatusDeployed),
+		deployedAt("payments", 3, 21, release.StatusSuperseded),
+		// the rollback of the app chart to revision 1, which is done before the release job is rolled back
+		deployedAt("payments", 4, 30, release.StatusDeployed),
+	}
+	for _, rel := range revisions {
+		if err := helmAgent.ActionConfig.Releases.Create(rel); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	return helmAgent
+}
+
+func TestRollbackReleaseJob(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("release job is rolled back to the revision deployed with the target", func(t *testing.T) {
+		helmAgent := deployStack(t)
+
+		target, err := helmAgent.GetRelease(ctx, "payments", 1, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		revision, err := rollbackReleaseJob(ctx, helmAgent, "payments", target, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if revision != 1 {
+			t.Errorf("expected the release job to be rolled back to revision 1, got %d", revision)
+		}
+
+		job, err := helmAgent.GetRelease(ctx, "payments-r", 0, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if job.Version != 3 || job.Config["version"] != 1 {
+			t.Errorf("expected revision 3 of the release job to redeploy revision 1, got revision %d with %v", job.Version, job.Config)
+		}
+	})
+
+	t.Run("release job is kept when it is already at the revision deployed with the target", func(t *testing.T) {
+		helmAgent := deployStack(t)
+
+		target, err := helmAgent.GetRelease(ctx, "payments", 3, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		revision, err := rollbackReleaseJob(ctx, helmAgent, "payments", target, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if revision != 2 {
+			t.Errorf("expected the release job to be at revision 2, got %d", revision)
+		}
+
+		job, err := helmAgent.GetRelease(ctx, "payments-r", 0, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if job.Version != 2 {
+			t.Errorf("expected the release job not to be rolled back, got revision %d", job.Version)
+		}
+	})
+
+	t.Run("apps without a release job are rolled back", func(t *testing.T) {
+		helmAgent := helm.GetAgentTesting(&helm.Form{Namespace: "porter-stack-payments"}, nil, logger.NewConsole(true), kubernetes.GetAgentTesting())
+
+		revision, err := rollbackReleaseJob(ctx, helmAgent, "payments", deployedAt("payments", 1, 0, release.StatusSuperseded), 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if revision != 0 {
+			t.Errorf("expected no release job revision, got %d", revision)
+		}
+	})
+
+	t.Run("failing to roll back the release job is reported with the revisions the charts are left at", func(t *testing.T) {
+		helmAgent := deployStack(t)
+		helmAgent.ActionConfig.KubeClient = &kubefake.FailingKubeClient{
+			PrintingKubeClient: kubefake.PrintingKubeClient{Out: io.Discard},
+			BuildError:         errors.New("connection refused"),
+		}
+
+		target, err := helmAgent.GetRelease(ctx, "payments", 1, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = rollbackReleaseJob(ctx, helmAgent, "payments", target, 3)
+		if err == nil {
+			t.Fatal("expected an error rolling back the release job")
+		}
+
+		for _, expected := range []string{"release job to revision 1", "restoring the app to revision 3", "the app is running revision 1 while the release job is still at revision 2"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected the error to contain %q, got %s", expected, err)
+			}
+		}
+	})
+}
+
+func TestReleaseRevision(t *testing.T) {
+	history := []*release.Release{
+		deployedAt("payments", 3, 21, release.StatusDeployed),
+		deployedAt("payments", 1, 1, release.StatusSuperseded),
+		deployedAt("payments", 2, 10, release.StatusSuperseded),
+	}
+
+	target, versions := releaseRevision(history, 2)
+	if target == nil || target.Version != 2 {
+		t.Fatalf("expected revision 2 to be found, got %v", target)
+	}
+
+	target, versions = releaseRevision(history, 7)
+	if target != nil {
+		t.Fatalf("expected revision 7 not to be found, got revision %d", target.Version)
+	}
+	if got := joinRevisions(versions); got != "1, 2, 3" {
+		t.Errorf("expected the available revisions in order, got %q", got)
+	}
+
+	if got := joinRevisions(nil); got != "none" {
+		t.Errorf("expected a release without history to have no revisions, got %q", got)
+	}
+}
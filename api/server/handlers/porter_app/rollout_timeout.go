@@ -0,0 +1,114 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// rolloutPodsTimeout bounds listing the pods of an app after its deploy timed out
+const rolloutPodsTimeout = 10 * time.Second
+
+// isRolloutTimeout reports whether a helm install or upgrade failed because the resources of its chart were not ready
+// within its timeout
+func isRolloutTimeout(err error) bool {
+	if errors.Is(err, wait.ErrWaitTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// helm does not wrap the error of its wait on every path, such as when it rolls back an atomic upgrade
+	return err != nil && strings.Contains(err.Error(), wait.ErrWaitTimeout.Error())
+}
+
+// rolloutTimeoutPods returns the status of the pods of the namespace of an app if its deploy waited for them and timed
+// out, so that the client sees why the rollout stalled. It must be called before the failed chart is removed or rolled
+// back, which replaces the pods. Pods which cannot be listed are only traced, since the deploy failed either way.
+func rolloutTimeoutPods(ctx context.Context, clientset k8s.Interface, namespace string, waited bool, err error) []types.RolloutPodStatus {
+	if !waited || !isRolloutTimeout(err) {
+		return nil
+	}
+
+	ctx, span := telemetry.NewSpan(ctx, "list-rollout-timeout-pods")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, rolloutPodsTimeout)
+	defer cancel()
+
+	pods, listErr := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if listErr != nil {
+		_ = telemetry.Error(ctx, span, listErr, "error listing pods")
+		return nil
+	}
+
+	res := make([]types.RolloutPodStatus, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		res = append(res, rolloutPodStatus(pod))
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res
+}
+
+// rolloutPodStatus returns the status of a pod, with the first reason it found for the pod not being ready
+func rolloutPodStatus(pod v1.Pod) types.RolloutPodStatus {
+	status := types.RolloutPodStatus{
+		Name:  pod.Name,
+		Phase: string(pod.Status.Phase),
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		switch {
+		case condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue:
+			status.Ready = true
+		case condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && status.Reason == "":
+			status.Reason = condition.Reason
+			status.Message = condition.Message
+		}
+	}
+
+	containerStatuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, containerStatus := range containerStatuses {
+		status.Restarts += containerStatus.RestartCount
+
+		if status.Reason != "" {
+			continue
+		}
+
+		switch {
+		case containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason != "":
+			status.Reason = containerStatus.State.Waiting.Reason
+			status.Message = containerStatus.State.Waiting.Message
+		case containerStatus.State.Terminated != nil && containerStatus.State.Terminated.ExitCode != 0:
+			status.Reason = containerStatus.State.Terminated.Reason
+			status.Message = containerStatus.State.Terminated.Message
+		}
+	}
+
+	return status
+}
+
+// handleDeployError writes the error of a failed deploy, along with the pods of the app if the deploy timed out
+// waiting for them
+func handleDeployError(conf *config.Config, w http.ResponseWriter, r *http.Request, err apierrors.RequestError, pods []types.RolloutPodStatus) {
+	if len(pods) == 0 {
+		apierrors.HandleAPIError(conf.Logger, conf.Alerter, w, r, err, true)
+		return
+	}
+
+	apierrors.HandleAPIError(conf.Logger, conf.Alerter, w, r, err, true, apierrors.ErrorOpts{
+		Code: types.ErrCodeRolloutTimeout,
+		Pods: pods,
+	})
+}
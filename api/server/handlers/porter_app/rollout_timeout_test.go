@@ -0,0 +1,93 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRolloutTimeoutPods(t *testing.T) {
+	namespace := "porter-stack-storefront"
+	clientset := fake.NewSimpleClientset(
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "storefront-web-b", Namespace: namespace},
+			Status: v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionFalse}},
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:         "web",
+					RestartCount: 4,
+					State:        v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 40s restarting failed container"}},
+				}},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "storefront-web-a", Namespace: namespace},
+			Status: v1.PodStatus{
+				Phase:      v1.PodPending,
+				Conditions: []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available: 3 Insufficient memory."}},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "storefront-r-migrate", Namespace: namespace},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				InitContainerStatuses: []v1.ContainerStatus{{
+					Name:  "wait-for-db",
+					State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+				}},
+				ContainerStatuses: []v1.ContainerStatus{{Name: "job", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "PodInitializing"}}}},
+			},
+		},
+	)
+
+	timeoutErr := fmt.Errorf("release storefront failed: %w", wait.ErrWaitTimeout)
+
+	if pods := rolloutTimeoutPods(context.Background(), clientset, namespace, false, timeoutErr); pods != nil {
+		t.Errorf("expected no pods for a deploy which did not wait for them, got %+v", pods)
+	}
+	if pods := rolloutTimeoutPods(context.Background(), clientset, namespace, true, errors.New("chart is invalid")); pods != nil {
+		t.Errorf("expected no pods for a deploy which did not time out, got %+v", pods)
+	}
+
+	pods := rolloutTimeoutPods(context.Background(), clientset, namespace, true, timeoutErr)
+	expected := []types.RolloutPodStatus{
+		{Name: "storefront-r-migrate", Phase: "Running", Reason: "Error"},
+		{Name: "storefront-web-a", Phase: "Pending", Reason: "Unschedulable", Message: "0/3 nodes are available: 3 Insufficient memory."},
+		{Name: "storefront-web-b", Phase: "Running", Restarts: 4, Reason: "CrashLoopBackOff", Message: "back-off 40s restarting failed container"},
+	}
+	if len(pods) != len(expected) {
+		t.Fatalf("expected %d pods, got %+v", len(expected), pods)
+	}
+	for i := range expected {
+		if pods[i] != expected[i] {
+			t.Errorf("expected pod %+v, got %+v", expected[i], pods[i])
+		}
+	}
+}
+
+func TestIsRolloutTimeout(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{err: fmt.Errorf("release web failed: %w", wait.ErrWaitTimeout), expected: true},
+		{err: fmt.Errorf("error installing chart: %w", context.DeadlineExceeded), expected: true},
+		{err: errors.New("release web failed, and has been rolled back due to atomic being set: timed out waiting for the condition"), expected: true},
+		{err: errors.New("unable to build kubernetes objects from release manifest"), expected: false},
+		{err: nil, expected: false},
+	}
+
+	for _, tt := range tests {
+		if actual := isRolloutTimeout(tt.err); actual != tt.expected {
+			t.Errorf("expected isRolloutTimeout(%v) to be %t, got %t", tt.err, tt.expected, actual)
+		}
+	}
+}
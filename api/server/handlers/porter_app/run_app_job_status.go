@@ -223,7 +223,7 @@ func (c *AppJobRunStatusHandler) getJobStatus(ctx context.Context, input getJobS
 	}
 	labelSelector := strings.Join(selectors, ",")
 
-	podsList, err := input.ClusterK8sAgent.GetPodsByLabel(labelSelector, input.Namespace)
+	podsList, err := input.ClusterK8sAgent.GetPodsByLabel(ctx, labelSelector, input.Namespace)
 	if err != nil {
 		return porter_app.InstanceStatusDescriptor_Unknown, telemetry.Error(ctx, span, err, "error getting jobs from cluster")
 	}
@@ -1,6 +1,7 @@
 package porter_app
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/porter-dev/porter/internal/kubernetes/porter_app"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
 )
 
 // RunPorterAppCommandHandler runs a command on a porter app
@@ -37,6 +39,7 @@ func NewRunPorterAppCommandHandler(
 
 func (c *RunPorterAppCommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
 	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
 
 	ctx, span := telemetry.NewSpan(r.Context(), "serve-run-porter-app-command")
@@ -58,17 +61,17 @@ func (c *RunPorterAppCommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 	namespace := utils.NamespaceFromPorterAppName(appName)
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "application-name", Value: appName})
 
-	app, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
 		err = telemetry.Error(ctx, span, err, "error reading app from DB")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
-	if app == nil {
-		err = telemetry.Error(ctx, span, nil, "app with name does not exist in project")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden))
-		return
-	}
 
 	k8sAgent, err := c.GetAgent(r, cluster, namespace)
 	if err != nil {
@@ -77,7 +80,7 @@ func (c *RunPorterAppCommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	podList, err := k8sAgent.GetPodsByLabel(porter_app.LabelKey_PorterApplication, namespace)
+	podList, err := k8sAgent.GetPodsByLabel(ctx, porter_app.LabelKey_PorterApplication, namespace)
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "error getting pods by label")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
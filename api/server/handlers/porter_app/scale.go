@@ -0,0 +1,382 @@
+package porter_app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// ScalePorterAppHandler handles POST /apps/{porter_app_name}/scale, which sets the replicas of services of an app
+// without deploying its porter.yaml. Only the replicas in the values of the latest release are changed. The scales are
+// recorded on the app, so that the next deploy warns if its porter.yaml sets other replicas.
+type ScalePorterAppHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewScalePorterAppHandler returns a new ScalePorterAppHandler
+func NewScalePorterAppHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ScalePorterAppHandler {
+	return &ScalePorterAppHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ScalePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-scale-porter-app")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-name", Value: appName})
+
+	request := &types.ScaleAppRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	apply := request.Apply
+	if apply == "" {
+		apply = types.ScalingScheduleApply_Upgrade
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "apply", Value: string(apply)},
+		telemetry.AttributeKV{Key: "adjust-autoscaling", Value: request.AdjustAutoscaling},
+	)
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Deploy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the replicas a paused app is resumed to are recorded when it is paused
+	if porterApp.PausedAt != nil {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("app %s is paused, resume it before scaling it", appName))
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	// the release is upgraded, so the scale waits for deploys of the app like another deploy
+	releaseDeployLock, err := acquireDeployLock(ctx, c.Config(), cluster.ID, appName, c.Config().ServerConf.HelmTimeout)
+	if err != nil {
+		if errors.Is(err, adapter.ErrLockNotAcquired) {
+			err = telemetry.Error(ctx, span, nil, fmt.Sprintf("a deploy of %s is in progress, retry once it has finished", appName))
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error acquiring deploy lock")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+	defer releaseDeployLock()
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rel, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting latest helm release")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	values := rel.Config
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	scales, err := planManualScale(values, request.Services, request.AdjustAutoscaling)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error planning scale")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "scaled-services", Value: len(scales)})
+
+	res := &types.ScaleAppResponse{
+		Services: make([]types.ServiceScale, 0, len(scales)),
+	}
+	if len(scales) == 0 {
+		c.WriteResult(w, r, res)
+		return
+	}
+
+	registries, err := c.Repo().Registry().ListRegistriesByProjectID(cluster.ProjectID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing registries")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	conf := &helm.InstallChartConfig{
+		Chart:      rel.Chart,
+		Name:       appName,
+		Namespace:  namespace,
+		Values:     values,
+		Cluster:    cluster,
+		Repo:       c.Repo(),
+		Registries: registries,
+	}
+
+	switch apply {
+	case types.ScalingScheduleApply_Patch:
+		for _, scale := range scales {
+			if err := patchDeploymentReplicas(ctx, k8sAgent.Clientset, namespace, fmt.Sprintf("%s-%s", appName, scale.helmName), int32(scale.After)); err != nil {
+				// the services before this one were scaled already
+				c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appName)
+
+				err = telemetry.Error(ctx, span, err, "error scaling deployment")
+				c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+				return
+			}
+		}
+
+		// the deployments are scaled already, so failing to sync the values is only a warning
+		if _, err := helmAgent.UpgradeInstallChart(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection); err != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "sync-values-error", Value: err.Error()})
+			res.Warnings = append(res.Warnings, fmt.Sprintf("the values of the release were not synced with the new replicas, so the next upgrade of the app resets them: %s", err.Error()))
+		}
+	default:
+		if _, err := helmAgent.UpgradeInstallChart(ctx, conf, c.Config().DOConf, c.Config().ServerConf.DisablePullSecretsInjection); err != nil {
+			// a failed upgrade may have changed some of the services
+			c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appName)
+
+			err = telemetry.Error(ctx, span, err, "error upgrading application")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+	c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appName)
+
+	now := time.Now().UTC()
+	var userID uint
+	var userEmail string
+	if user != nil {
+		userID = user.ID
+		userEmail = user.Email
+	}
+
+	if porterApp.ManualScales == nil {
+		porterApp.ManualScales = models.PorterAppManualScales{}
+	}
+	for _, scale := range scales {
+		res.Services = append(res.Services, scale.ServiceScale)
+		porterApp.ManualScales[scale.Service] = &models.ManualScale{
+			HelmName:   scale.helmName,
+			Replicas:   scale.After,
+			Autoscaled: scale.Autoscaled,
+			UserID:     userID,
+			At:         now,
+		}
+	}
+
+	// the services are scaled already, so failing to record it is not returned to the client
+	if _, err := c.Repo().PorterApp().UpdatePorterApp(porterApp); err != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "record-manual-scales-error", Value: err.Error()})
+	}
+
+	if err := c.Repo().PorterAppEvent().CreateEvent(ctx, &models.PorterAppEvent{
+		ID:          uuid.New(),
+		Type:        string(types.PorterAppEventType_Scale),
+		Status:      string(types.PorterAppEventStatus_Success),
+		PorterAppID: porterApp.ID,
+		Metadata: map[string]any{
+			"services":   res.Services,
+			"apply":      string(apply),
+			"user_id":    userID,
+			"user_email": userEmail,
+		},
+	}); err != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "create-scale-event-error", Value: err.Error()})
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// plannedScale is a change of the replicas of a service, with the name of its chart in the umbrella chart of its app
+type plannedScale struct {
+	types.ServiceScale
+	helmName string
+}
+
+// planManualScale sets the replicas of the services in the values of an app, by their name in porter.yaml, and returns
+// the services whose replicas changed, ordered by name. The replicas of autoscaled services are set by their autoscaler,
+// so they are refused unless adjustAutoscaling is set, in which case the minimum replicas of the autoscaler are set
+// instead, and its maximum raised to match if it is lower.
+func planManualScale(values map[string]interface{}, services map[string]int, adjustAutoscaling bool) ([]plannedScale, error) {
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var scales []plannedScale
+	for _, name := range names {
+		replicas := services[name]
+
+		helmName, serviceValues, ok := scalableService(values, name)
+		if !ok {
+			return nil, fmt.Errorf("service %s is not a web or worker service of the app", name)
+		}
+
+		scale := plannedScale{
+			ServiceScale: types.ServiceScale{Service: name, After: replicas},
+			helmName:     helmName,
+		}
+
+		autoscaling, autoscaled := autoscalingValues(serviceValues)
+		if autoscaled {
+			if !adjustAutoscaling {
+				return nil, fmt.Errorf("service %s is autoscaled, set adjust_autoscaling to set the minimum replicas of its autoscaler instead", name)
+			}
+
+			scale.Autoscaled = true
+			scale.Before = intValue(autoscaling["minReplicas"], defaultReplicaCount)
+			if scale.Before == replicas {
+				continue
+			}
+
+			autoscaling["minReplicas"] = replicas
+			if intValue(autoscaling["maxReplicas"], replicas) < replicas {
+				autoscaling["maxReplicas"] = replicas
+			}
+		} else {
+			scale.Before = replicaCount(values, helmName)
+			if scale.Before == replicas {
+				continue
+			}
+
+			serviceValues["replicaCount"] = replicas
+		}
+
+		scales = append(scales, scale)
+	}
+
+	return scales, nil
+}
+
+// revertedManualScales returns a warning for each service which was scaled by hand since the app was last deployed,
+// and which the values of a deploy set to other replicas
+func revertedManualScales(scales models.PorterAppManualScales, values map[string]interface{}) []string {
+	names := make([]string, 0, len(scales))
+	for name := range scales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		scale := scales[name]
+
+		serviceValues, ok := values[scale.HelmName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		replicas := replicaCount(values, scale.HelmName)
+		if autoscaling, autoscaled := autoscalingValues(serviceValues); autoscaled {
+			replicas = intValue(autoscaling["minReplicas"], defaultReplicaCount)
+		}
+		if replicas == scale.Replicas {
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf("service %s was scaled to %d replicas at %s, and this deploy sets it to %d replicas", name, scale.Replicas, scale.At.Format(time.RFC3339), replicas))
+	}
+
+	return warnings
+}
+
+// scalableService returns the helm name and values of a web or worker service of an app by its name in porter.yaml
+func scalableService(values map[string]interface{}, name string) (string, map[string]interface{}, bool) {
+	for _, serviceType := range []string{"web", "worker"} {
+		helmName := getHelmName(name, serviceType)
+		if serviceValues, ok := values[helmName].(map[string]interface{}); ok {
+			return helmName, serviceValues, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// autoscalingValues returns the autoscaling values of a service, and whether its autoscaler is enabled
+func autoscalingValues(serviceValues map[string]interface{}) (map[string]interface{}, bool) {
+	autoscaling, ok := serviceValues["autoscaling"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	// values are written as strings or booleans in porter.yaml, depending on how they were quoted
+	return autoscaling, fmt.Sprint(autoscaling["enabled"]) == "true"
+}
+
+// intValue reads a number from helm values, which are written as strings or numbers in porter.yaml, or returns def
+func intValue(value interface{}, def int) int {
+	if value == nil {
+		return def
+	}
+
+	n, err := strconv.Atoi(fmt.Sprint(value))
+	if err != nil || n < 0 {
+		return def
+	}
+
+	return n
+}
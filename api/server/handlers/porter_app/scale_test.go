@@ -0,0 +1,121 @@
+package porter_app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func scaleTestValues() map[string]interface{} {
+	return map[string]interface{}{
+		"web-web": map[string]interface{}{
+			"replicaCount": "3",
+		},
+		"worker-wkr": map[string]interface{}{
+			"replicaCount": 2,
+			"autoscaling": map[string]interface{}{
+				"enabled":     true,
+				"minReplicas": "2",
+				"maxReplicas": "5",
+			},
+		},
+		"migrate-job": map[string]interface{}{},
+	}
+}
+
+func TestPlanManualScale(t *testing.T) {
+	values := scaleTestValues()
+
+	scales, err := planManualScale(values, map[string]int{"web": 10, "worker": 8}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []plannedScale{
+		{ServiceScale: types.ServiceScale{Service: "web", Before: 3, After: 10}, helmName: "web-web"},
+		{ServiceScale: types.ServiceScale{Service: "worker", Before: 2, After: 8, Autoscaled: true}, helmName: "worker-wkr"},
+	}
+	if !reflect.DeepEqual(scales, want) {
+		t.Errorf("expected %+v, got %+v", want, scales)
+	}
+
+	if got := values["web-web"].(map[string]interface{})["replicaCount"]; got != 10 {
+		t.Errorf("expected the replicas of web to be set, got %v", got)
+	}
+
+	autoscaling := values["worker-wkr"].(map[string]interface{})["autoscaling"].(map[string]interface{})
+	if autoscaling["minReplicas"] != 8 || autoscaling["maxReplicas"] != 8 {
+		t.Errorf("expected the autoscaler of worker to run at least 8 replicas, got %v", autoscaling)
+	}
+	if got := values["worker-wkr"].(map[string]interface{})["replicaCount"]; got != 2 {
+		t.Errorf("expected the replicas of an autoscaled service to be left to its autoscaler, got %v", got)
+	}
+}
+
+func TestPlanManualScaleSkipsUnchangedServices(t *testing.T) {
+	scales, err := planManualScale(scaleTestValues(), map[string]int{"web": 3}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scales) != 0 {
+		t.Errorf("expected no scales, got %+v", scales)
+	}
+}
+
+func TestPlanManualScaleRefusals(t *testing.T) {
+	tests := []struct {
+		name     string
+		services map[string]int
+		wantErr  string
+	}{
+		{
+			name:     "autoscaled service",
+			services: map[string]int{"worker": 4},
+			wantErr:  "service worker is autoscaled",
+		},
+		{
+			name:     "job",
+			services: map[string]int{"migrate": 2},
+			wantErr:  "service migrate is not a web or worker service",
+		},
+		{
+			name:     "unknown service",
+			services: map[string]int{"api": 2},
+			wantErr:  "service api is not a web or worker service",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := scaleTestValues()
+
+			_, err := planManualScale(values, tt.services, false)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(values, scaleTestValues()) {
+				t.Errorf("expected the values to be left as they were, got %v", values)
+			}
+		})
+	}
+}
+
+func TestRevertedManualScales(t *testing.T) {
+	at := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	scales := models.PorterAppManualScales{
+		"web":    {HelmName: "web-web", Replicas: 10, At: at},
+		"worker": {HelmName: "worker-wkr", Replicas: 2, Autoscaled: true, At: at},
+		"api":    {HelmName: "api-web", Replicas: 4, At: at},
+	}
+
+	warnings := revertedManualScales(scales, scaleTestValues())
+
+	want := []string{"service web was scaled to 10 replicas at 2026-03-02T09:30:00Z, and this deploy sets it to 3 replicas"}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("expected %v, got %v", want, warnings)
+	}
+}
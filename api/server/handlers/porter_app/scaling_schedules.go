@@ -0,0 +1,73 @@
+package porter_app
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultReplicaCount is the number of replicas the charts of services run when their values do not set one
+const defaultReplicaCount = 1
+
+// servicesScalingSchedules returns the scaling schedules of the services in a porter.yaml, keyed by service name, or
+// nil if no service has one. Each service runs the replicas it is deployed with in values outside of its windows.
+func servicesScalingSchedules(porterYaml []byte, values map[string]interface{}) (models.PorterAppScalingSchedules, error) {
+	porterYaml, _, err := envvalues.ExtractSecretReferences(porterYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret references from porter.yaml: %w", err)
+	}
+
+	parsed := &PorterStackYAML{}
+	if err := yaml.Unmarshal(porterYaml, parsed); err != nil {
+		return nil, fmt.Errorf("error parsing porter.yaml: %w", err)
+	}
+
+	services := parsed.Services
+	if services == nil {
+		services = parsed.Apps
+	}
+
+	var schedules models.PorterAppScalingSchedules
+	for name, service := range services {
+		if service == nil || service.Scaling == nil {
+			continue
+		}
+
+		helmName := getHelmName(name, getType(name, service))
+
+		if schedules == nil {
+			schedules = models.PorterAppScalingSchedules{}
+		}
+		schedules[name] = &models.ScheduledService{
+			HelmName:     helmName,
+			BaseReplicas: replicaCount(values, helmName),
+			Schedule:     *service.Scaling,
+		}
+	}
+
+	return schedules, nil
+}
+
+// replicaCount returns the replicas a service is deployed with in the values of its app
+func replicaCount(values map[string]interface{}, helmName string) int {
+	serviceValues, ok := values[helmName].(map[string]interface{})
+	if !ok {
+		return defaultReplicaCount
+	}
+
+	count, ok := serviceValues["replicaCount"]
+	if !ok {
+		return defaultReplicaCount
+	}
+
+	// replica counts are written as strings or numbers in porter.yaml, depending on how they were quoted
+	replicas, err := strconv.Atoi(fmt.Sprint(count))
+	if err != nil || replicas < 0 {
+		return defaultReplicaCount
+	}
+
+	return replicas
+}
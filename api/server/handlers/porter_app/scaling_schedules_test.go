@@ -0,0 +1,62 @@
+package porter_app
+
+import (
+	"testing"
+)
+
+func TestServicesScalingSchedules(t *testing.T) {
+	porterYaml := []byte(`version: v1stack
+services:
+  web:
+    type: web
+    run: node index.js
+    scaling:
+      timezone: America/New_York
+      schedules:
+        - name: business hours
+          days: MON-FRI
+          start: "08:00"
+          end: "20:00"
+          replicas: 10
+  worker:
+    type: worker
+    run: node worker.js
+    scaling:
+      schedules:
+        - days: "*"
+          start: "22:00"
+          end: "06:00"
+          replicas: 0
+  cleanup-job:
+    type: job
+    run: node cleanup.js
+`)
+	values := map[string]interface{}{
+		"web-web":    map[string]interface{}{"replicaCount": "3"},
+		"worker-wkr": map[string]interface{}{},
+	}
+
+	schedules, err := servicesScalingSchedules(porterYaml, values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schedules) != 2 {
+		t.Fatalf("expected the schedules of web and worker, got %v", schedules)
+	}
+
+	web := schedules["web"]
+	if web.HelmName != "web-web" || web.BaseReplicas != 3 || web.Schedule.Timezone != "America/New_York" || len(web.Schedule.Schedules) != 1 || web.Schedule.Schedules[0].Replicas != 10 {
+		t.Errorf("expected the schedule of web with the replicas it is deployed with, got %+v", web)
+	}
+	if worker := schedules["worker"]; worker.HelmName != "worker-wkr" || worker.BaseReplicas != defaultReplicaCount {
+		t.Errorf("expected worker to run the default replicas outside of its window, got %+v", worker)
+	}
+
+	schedules, err = servicesScalingSchedules([]byte("version: v1stack\nservices:\n  web:\n    run: node index.js\n"), values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if schedules != nil {
+		t.Errorf("expected no schedules for an app without them, got %v", schedules)
+	}
+}
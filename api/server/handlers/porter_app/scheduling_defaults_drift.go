@@ -0,0 +1,90 @@
+package porter_app
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// SchedulingDefaultsDriftHandler lists the apps in a cluster whose latest release was not deployed with the cluster's current scheduling defaults
+type SchedulingDefaultsDriftHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewSchedulingDefaultsDriftHandler returns a new SchedulingDefaultsDriftHandler
+func NewSchedulingDefaultsDriftHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *SchedulingDefaultsDriftHandler {
+	return &SchedulingDefaultsDriftHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *SchedulingDefaultsDriftHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-scheduling-defaults-drift")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	currentHash := porter_app.SchedulingDefaultsHash(types.ClusterSchedulingDefaults(cluster.SchedulingDefaults))
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "cluster-id", Value: cluster.ID},
+		telemetry.AttributeKV{Key: "scheduling-defaults-hash", Value: currentHash},
+	)
+
+	porterApps, err := c.Repo().PorterApp().ListScopedPorterAppsByClusterID(project.ID, cluster.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing porter apps")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.ListSchedulingDefaultsDriftResponse{
+		SchedulingDefaultsHash: currentHash,
+		Apps:                   make([]types.AppSchedulingDefaultsStatus, 0, len(porterApps)),
+	}
+
+	for _, porterApp := range porterApps {
+		status := types.AppSchedulingDefaultsStatus{
+			Name: porterApp.Name,
+		}
+
+		helmAgent, err := c.GetHelmAgent(ctx, r, cluster, utils.NamespaceFromPorterAppName(porterApp.Name))
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error getting helm agent")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		// an app which cannot be read is reported rather than failing the whole request, since it is most likely mid-deploy or deleted
+		release, err := helmAgent.GetRelease(ctx, porterApp.Name, 0, false)
+		if err != nil {
+			status.Error = err.Error()
+			res.Apps = append(res.Apps, status)
+			continue
+		}
+
+		var deployedHash string
+		if global, ok := release.Config["global"].(map[string]interface{}); ok {
+			deployedHash, _ = global[porter_app.SchedulingDefaultsHashKey].(string)
+		}
+
+		status.UpToDate = deployedHash == currentHash
+		res.Apps = append(res.Apps, status)
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,396 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// serviceNamespacesKey is the key of the global values of an app which maps the helm names of the services which run
+// outside of the namespace of the app to their namespace. Each of those namespaces holds a release named after the
+// app, which installs the services of the app in that namespace.
+const serviceNamespacesKey = "serviceNamespaces"
+
+// serviceNamespacesFromValues returns the namespaces of the services which run outside of the namespace of the app, by
+// helm name
+func serviceNamespacesFromValues(values map[string]interface{}) map[string]string {
+	res := make(map[string]string)
+
+	global, ok := values["global"].(map[string]interface{})
+	if !ok {
+		return res
+	}
+
+	switch namespaces := global[serviceNamespacesKey].(type) {
+	case map[string]interface{}:
+		for helmName, namespace := range namespaces {
+			if namespace, ok := namespace.(string); ok && namespace != "" {
+				res[helmName] = namespace
+			}
+		}
+	case map[string]string:
+		for helmName, namespace := range namespaces {
+			if namespace != "" {
+				res[helmName] = namespace
+			}
+		}
+	}
+
+	return res
+}
+
+// servicesByNamespace returns the namespace of every service in values, by helm name
+func servicesByNamespace(values map[string]interface{}, appNamespace string) map[string]string {
+	serviceNamespaces := serviceNamespacesFromValues(values)
+
+	res := make(map[string]string)
+	for helmName := range values {
+		if helmName == "global" {
+			continue
+		}
+
+		res[helmName] = appNamespace
+		if namespace, ok := serviceNamespaces[helmName]; ok {
+			res[helmName] = namespace
+		}
+	}
+
+	return res
+}
+
+// otherNamespaces returns the namespaces which run services of the app outside of its namespace, sorted
+func otherNamespaces(serviceNamespaces map[string]string) []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, namespace := range serviceNamespaces {
+		if !seen[namespace] {
+			seen[namespace] = true
+			res = append(res, namespace)
+		}
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// helmAgentForNamespace returns a helm agent for a namespace of the cluster the app is deployed to
+type helmAgentForNamespace func(namespace string) (*helm.Agent, error)
+
+// mergeServiceNamespaceReleases reads the releases of an app in the other namespaces its services run in, and merges
+// their values and chart dependencies into the release of the app, so that a deploy is parsed against every service of
+// the app. The releases are returned by namespace as they were before the deploy, so that they can be rolled back.
+func mergeServiceNamespaceReleases(ctx context.Context, appRelease *release.Release, getHelmAgent helmAgentForNamespace) (map[string]*release.Release, error) {
+	res := make(map[string]*release.Release)
+
+	for _, namespace := range otherNamespaces(serviceNamespacesFromValues(appRelease.Config)) {
+		helmAgent, err := getHelmAgent(namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error getting helm agent for namespace %s: %w", namespace, err)
+		}
+
+		namespaceRelease, err := helmAgent.GetRelease(ctx, appRelease.Name, 0, false)
+		if err != nil {
+			// a release which was removed by hand installs its services again if porter.yaml still defines them
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("error reading release of namespace %s: %w", namespace, err)
+		}
+		res[namespace] = namespaceRelease
+
+		for key, value := range namespaceRelease.Config {
+			if key != "global" {
+				appRelease.Config[key] = value
+			}
+		}
+		if namespaceRelease.Chart != nil && namespaceRelease.Chart.Metadata != nil {
+			for _, dep := range namespaceRelease.Chart.Metadata.Dependencies {
+				if !dependencyExists(appRelease.Chart.Metadata.Dependencies, dep) {
+					appRelease.Chart.Metadata.Dependencies = append(appRelease.Chart.Metadata.Dependencies, dep)
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// checkServiceNamespaceMoves returns an error if a deploy moves a service which is already deployed to another
+// namespace, unless the deploy migrates it. Moving a service uninstalls it from one namespace and installs it in the
+// other, so its pods, volumes and load balancers are recreated.
+func checkServiceNamespaceMoves(previous map[string]string, values map[string]interface{}, appNamespace string, migrate bool) error {
+	if migrate {
+		return nil
+	}
+
+	var moves []string
+	for helmName, namespace := range servicesByNamespace(values, appNamespace) {
+		if from, ok := previous[helmName]; ok && from != namespace {
+			serviceName, _ := getServiceNameAndTypeFromHelmName(helmName)
+			moves = append(moves, fmt.Sprintf("%s from %s to %s", serviceName, from, namespace))
+		}
+	}
+	if len(moves) == 0 {
+		return nil
+	}
+	sort.Strings(moves)
+
+	return fmt.Errorf("moving a service to another namespace recreates its resources, set migrate_service_namespaces to move %s", strings.Join(moves, ", "))
+}
+
+// serviceNamespaceRelease is the release of an app which installs the services of the app in another namespace
+type serviceNamespaceRelease struct {
+	namespace string
+	chart     *chart.Chart
+	values    map[string]interface{}
+}
+
+// splitServiceNamespaces splits the chart and values of an app into those of the release in the namespace of the app,
+// and those of the releases in the other namespaces its services run in. The global values are copied into every
+// release.
+func splitServiceNamespaces(umbrella *chart.Chart, values map[string]interface{}) (*chart.Chart, map[string]interface{}, []*serviceNamespaceRelease, error) {
+	serviceNamespaces := serviceNamespacesFromValues(values)
+	if len(serviceNamespaces) == 0 {
+		return umbrella, values, nil, nil
+	}
+
+	global := make(map[string]interface{})
+	if appGlobal, ok := values["global"].(map[string]interface{}); ok {
+		for key, value := range appGlobal {
+			if key != serviceNamespacesKey {
+				global[key] = value
+			}
+		}
+	}
+
+	appValues := make(map[string]interface{})
+	releaseValues := make(map[string]map[string]interface{})
+	for key, value := range values {
+		namespace, ok := serviceNamespaces[key]
+		if !ok {
+			appValues[key] = value
+			continue
+		}
+
+		if releaseValues[namespace] == nil {
+			releaseValues[namespace] = map[string]interface{}{"global": global}
+		}
+		releaseValues[namespace][key] = value
+	}
+
+	var appDeps []*chart.Dependency
+	releaseDeps := make(map[string][]*chart.Dependency)
+	for _, dep := range umbrella.Metadata.Dependencies {
+		namespace, ok := serviceNamespaces[dep.Alias]
+		if !ok {
+			appDeps = append(appDeps, dep)
+			continue
+		}
+		releaseDeps[namespace] = append(releaseDeps[namespace], dep)
+	}
+
+	appChart, err := createChartFromDependencies(appDeps)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var releases []*serviceNamespaceRelease
+	for _, namespace := range otherNamespaces(serviceNamespaces) {
+		namespaceChart, err := createChartFromDependencies(releaseDeps[namespace])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		releases = append(releases, &serviceNamespaceRelease{
+			namespace: namespace,
+			chart:     namespaceChart,
+			values:    releaseValues[namespace],
+		})
+	}
+
+	return appChart, appValues, releases, nil
+}
+
+// checkServiceNamespacesExist returns an error naming the namespaces which do not exist. Services are only deployed to
+// namespaces which were created for them, so that a typo does not create a namespace nobody manages.
+func checkServiceNamespacesExist(ctx context.Context, clientset k8s.Interface, releases []*serviceNamespaceRelease) error {
+	var missing []string
+	for _, namespaceRelease := range releases {
+		_, err := clientset.CoreV1().Namespaces().Get(ctx, namespaceRelease.namespace, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			missing = append(missing, namespaceRelease.namespace)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error reading namespace %s: %w", namespaceRelease.namespace, err)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("services can only be deployed to namespaces which exist, create %s first", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+type deployServiceNamespacesInput struct {
+	AppName  string
+	Releases []*serviceNamespaceRelease
+	// Previous are the releases of the app in other namespaces before the deploy, by namespace
+	Previous     map[string]*release.Release
+	GetHelmAgent helmAgentForNamespace
+	// Conf is copied for the release of each namespace, with its chart, name, namespace and values set
+	Conf   helm.InstallChartConfig
+	Config *config.Config
+	// Rollback rolls back the releases which were deployed if one of them fails
+	Rollback bool
+}
+
+// deployServiceNamespaces installs or upgrades the releases of an app in the other namespaces its services run in,
+// before the release in the namespace of the app. If one fails, those already deployed are rolled back, so that a
+// deploy changes the services in every namespace or in none. The returned function rolls every release back, for when
+// the release in the namespace of the app fails after them.
+func deployServiceNamespaces(ctx context.Context, inp deployServiceNamespacesInput) (func(context.Context) error, *deployFailure) {
+	var deployed []string
+
+	rollback := func(ctx context.Context) error {
+		var errs []error
+		for _, namespace := range deployed {
+			if err := rollbackServiceNamespace(ctx, inp, namespace); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+
+	for _, namespaceRelease := range inp.Releases {
+		helmAgent, err := inp.GetHelmAgent(namespaceRelease.namespace)
+		if err != nil {
+			failure := &deployFailure{stage: deployStage_ServiceNamespaces, err: fmt.Errorf("error getting helm agent for namespace %s: %w", namespaceRelease.namespace, err)}
+			if inp.Rollback {
+				failure.cleanupErr = rollback(ctx)
+			}
+			return nil, failure
+		}
+
+		conf := inp.Conf
+		conf.Chart = namespaceRelease.chart
+		conf.Name = inp.AppName
+		conf.Namespace = namespaceRelease.namespace
+		conf.Values = namespaceRelease.values
+
+		_, err = helmAgent.UpgradeInstallChart(ctx, &conf, inp.Config.DOConf, inp.Config.ServerConf.DisablePullSecretsInjection)
+		// the failed release is rolled back along with those deployed before it
+		deployed = append(deployed, namespaceRelease.namespace)
+		if err != nil {
+			failure := &deployFailure{stage: deployStage_ServiceNamespaces, err: fmt.Errorf("error deploying services in namespace %s: %w", namespaceRelease.namespace, err)}
+			if inp.Rollback {
+				failure.cleanupErr = rollback(ctx)
+			}
+			return nil, failure
+		}
+	}
+
+	return rollback, nil
+}
+
+// rollbackServiceNamespace rolls the release of an app in a namespace back to its revision before the deploy, or
+// uninstalls it if the deploy installed it
+func rollbackServiceNamespace(ctx context.Context, inp deployServiceNamespacesInput, namespace string) error {
+	previous, ok := inp.Previous[namespace]
+	if !ok {
+		return uninstallServiceNamespace(ctx, inp.GetHelmAgent, inp.AppName, namespace)
+	}
+
+	helmAgent, err := inp.GetHelmAgent(namespace)
+	if err != nil {
+		return fmt.Errorf("error getting helm agent for namespace %s: %w", namespace, err)
+	}
+
+	if err := helmAgent.RollbackRelease(ctx, inp.AppName, previous.Version); err != nil {
+		return fmt.Errorf("error rolling back services in namespace %s to revision %d: %w", namespace, previous.Version, err)
+	}
+
+	return nil
+}
+
+// uninstallServiceNamespace uninstalls the release of an app in a namespace. The namespace itself is left in place, as
+// it was not created by porter.
+func uninstallServiceNamespace(ctx context.Context, getHelmAgent helmAgentForNamespace, appName, namespace string) error {
+	helmAgent, err := getHelmAgent(namespace)
+	if err != nil {
+		return fmt.Errorf("error getting helm agent for namespace %s: %w", namespace, err)
+	}
+
+	if _, err := helmAgent.UninstallChart(ctx, appName); err != nil {
+		return fmt.Errorf("error uninstalling services from namespace %s: %w", namespace, err)
+	}
+
+	return nil
+}
+
+// staleServiceNamespaces returns the namespaces which ran services of the app before the deploy and no longer do
+func staleServiceNamespaces(previous map[string]*release.Release, releases []*serviceNamespaceRelease) []string {
+	current := make(map[string]bool)
+	for _, namespaceRelease := range releases {
+		current[namespaceRelease.namespace] = true
+	}
+
+	var res []string
+	for namespace := range previous {
+		if !current[namespace] {
+			res = append(res, namespace)
+		}
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// resolveServiceLinks rewrites the env variables of the services of an app which point at another service of the app,
+// such as http://app-web-web:8080, so that they resolve from the namespace of the service. Services in other namespaces
+// are linked by their fully qualified name, and services in the same namespace by their short name.
+func resolveServiceLinks(values map[string]interface{}, appName, appNamespace string) {
+	namespaces := servicesByNamespace(values, appNamespace)
+
+	links := make(map[string]*regexp.Regexp)
+	for helmName := range namespaces {
+		host := fmt.Sprintf("%s-%s", appName, helmName)
+		links[helmName] = regexp.MustCompile(`(^|[/@])` + regexp.QuoteMeta(host) + `(\.[a-z0-9-]+\.svc\.cluster\.local)?($|[:/])`)
+	}
+
+	for helmName, namespace := range namespaces {
+		env, err := getNestedMap(values, helmName, "container", "env", "normal")
+		if err != nil {
+			continue
+		}
+
+		for key, value := range env {
+			value, ok := value.(string)
+			if !ok {
+				continue
+			}
+
+			for target, link := range links {
+				host := fmt.Sprintf("%s-%s", appName, target)
+				if namespaces[target] != namespace {
+					host = fmt.Sprintf("%s.%s.svc.cluster.local", host, namespaces[target])
+				}
+				value = link.ReplaceAllString(value, "${1}"+host+"${3}")
+			}
+			env[key] = value
+		}
+	}
+}
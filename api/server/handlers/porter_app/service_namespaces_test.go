@@ -0,0 +1,130 @@
+package porter_app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"gopkg.in/yaml.v2"
+)
+
+const serviceNamespacesPorterYaml = `version: v1stack
+services:
+  web:
+    type: web
+    run: node index.js
+    config:
+      container:
+        port: 8080
+        env:
+          normal:
+            WORKER_URL: http://storefront-worker-wkr:9000/jobs
+  worker:
+    type: worker
+    run: node worker.js
+    namespace: jobs
+    config:
+      container:
+        env:
+          normal:
+            WEB_URL: http://storefront-web-web:80
+            DATABASE_URL: postgres://db.internal:5432/storefront
+`
+
+func buildServiceNamespacesTestValues(t *testing.T, porterYaml string) map[string]interface{} {
+	t.Helper()
+
+	parsed := &PorterStackYAML{}
+	if err := yaml.Unmarshal([]byte(porterYaml), parsed); err != nil {
+		t.Fatalf("error parsing porter.yaml: %v", err)
+	}
+
+	application := &Application{Env: parsed.Env, Services: parsed.Services}
+	imageInfo := types.ImageInfo{Repository: "registry.example.com/storefront", Tag: "8f14e45f"}
+
+	values, _, err := buildUmbrellaChartValues(context.Background(), application, nil, imageInfo, nil, nil, SubdomainCreateOpts{dryRun: true}, false, true, false, "porter-stack-storefront", false, false, types.ClusterSchedulingDefaults{}, nil, "storefront", nil)
+	if err != nil {
+		t.Fatalf("error building values: %v", err)
+	}
+
+	return values
+}
+
+func TestServiceNamespacesSplitIntoReleases(t *testing.T) {
+	values := buildServiceNamespacesTestValues(t, serviceNamespacesPorterYaml)
+
+	if got := serviceNamespacesFromValues(values); len(got) != 1 || got["worker-wkr"] != "jobs" {
+		t.Fatalf("expected only the worker to run outside of the namespace of the app, got %v", got)
+	}
+
+	// links resolve from the namespace of the service which holds them
+	if got := stringValue(t, values, "web-web", "container", "env", "normal", "WORKER_URL"); got != "http://storefront-worker-wkr.jobs.svc.cluster.local:9000/jobs" {
+		t.Errorf("expected the web service to link to the worker by its fully qualified name, got %q", got)
+	}
+	if got := stringValue(t, values, "worker-wkr", "container", "env", "normal", "WEB_URL"); got != "http://storefront-web-web.porter-stack-storefront.svc.cluster.local:80" {
+		t.Errorf("expected the worker to link to the web service by its fully qualified name, got %q", got)
+	}
+	if got := stringValue(t, values, "worker-wkr", "container", "env", "normal", "DATABASE_URL"); got != "postgres://db.internal:5432/storefront" {
+		t.Errorf("expected hosts which are not services of the app to be kept, got %q", got)
+	}
+
+	umbrella, err := createChartFromDependencies([]*chart.Dependency{
+		{Name: "web", Alias: "web-web", Version: "0.1.0"},
+		{Name: "worker", Alias: "worker-wkr", Version: "0.1.0"},
+	})
+	if err != nil {
+		t.Fatalf("error creating chart: %v", err)
+	}
+
+	appChart, appValues, releases, err := splitServiceNamespaces(umbrella, values)
+	if err != nil {
+		t.Fatalf("error splitting values: %v", err)
+	}
+
+	if len(appChart.Metadata.Dependencies) != 1 || appChart.Metadata.Dependencies[0].Alias != "web-web" {
+		t.Errorf("expected the release of the app to only install the web service, got %v", appChart.Metadata.Dependencies)
+	}
+	if _, ok := appValues["worker-wkr"]; ok {
+		t.Errorf("expected the values of the worker to be moved out of the release of the app")
+	}
+
+	if len(releases) != 1 || releases[0].namespace != "jobs" {
+		t.Fatalf("expected a release in the jobs namespace, got %v", releases)
+	}
+	if deps := releases[0].chart.Metadata.Dependencies; len(deps) != 1 || deps[0].Alias != "worker-wkr" {
+		t.Errorf("expected the release in the jobs namespace to only install the worker, got %v", deps)
+	}
+	if got := stringValue(t, releases[0].values, "global", "image", "repository"); got != "registry.example.com/storefront" {
+		t.Errorf("expected the global values to be copied into the release in the jobs namespace, got %q", got)
+	}
+	if _, ok := releases[0].values["global"].(map[string]interface{})[serviceNamespacesKey]; ok {
+		t.Errorf("expected the namespaces of the services to only be kept in the release of the app")
+	}
+}
+
+func TestServiceNamespaceMovesRequireMigration(t *testing.T) {
+	previous := servicesByNamespace(buildServiceNamespacesTestValues(t, serviceNamespacesPorterYaml), "porter-stack-storefront")
+
+	// the worker is moved back into the namespace of the app
+	values := buildServiceNamespacesTestValues(t, strings.Replace(serviceNamespacesPorterYaml, "    namespace: jobs\n", "", 1))
+	if got := serviceNamespacesFromValues(values); len(got) != 0 {
+		t.Fatalf("expected every service to run in the namespace of the app, got %v", got)
+	}
+	if got := stringValue(t, values, "web-web", "container", "env", "normal", "WORKER_URL"); got != "http://storefront-worker-wkr:9000/jobs" {
+		t.Errorf("expected services in the same namespace to link by their short name, got %q", got)
+	}
+
+	err := checkServiceNamespaceMoves(previous, values, "porter-stack-storefront", false)
+	if err == nil || !strings.Contains(err.Error(), "worker from jobs to porter-stack-storefront") {
+		t.Errorf("expected moving the worker to be rejected, got %v", err)
+	}
+
+	if err := checkServiceNamespaceMoves(previous, values, "porter-stack-storefront", true); err != nil {
+		t.Errorf("expected moving the worker to be allowed with a migration, got %v", err)
+	}
+	if err := checkServiceNamespaceMoves(nil, values, "porter-stack-storefront", false); err != nil {
+		t.Errorf("expected services which are deployed for the first time to be placed in any namespace, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package porter_app
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"connectrpc.com/connect"
@@ -12,10 +15,13 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/coalesce"
 	"github.com/porter-dev/porter/internal/deployment_target"
+	"github.com/porter-dev/porter/internal/kubernetes"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/porter_app"
 	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
 )
 
 // ServiceStatusHandler is the handler for GET /apps/pods
@@ -92,17 +98,17 @@ func (c *ServiceStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTarget.ID},
 	)
 
-	app, err := c.Repo().PorterApp().ReadPorterAppByName(cluster.ID, appName)
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
 		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
-	if app == nil || app.ID == 0 {
-		err = telemetry.Error(ctx, span, nil, "app with name does not exist in project")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
-		return
-	}
 
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-id", Value: app.ID})
 
@@ -118,24 +124,65 @@ func (c *ServiceStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// dashboards poll this endpoint from every open tab, so identical queries share a single call to the cluster
+	serviceStatus, cacheResult, err := coalesce.Do(c.Config().StatusQueryCoalescer, coalesce.Key{
+		ClusterID: cluster.ID,
+		Namespace: namespace,
+		StackName: appName,
+		QueryType: coalesce.QueryType_ServiceStatus,
+		Params:    fmt.Sprintf("%s/%s", deploymentTarget.ID, request.ServiceName),
+	}, coalesce.OptionsFromRequest(r), func() (porter_app.ServiceStatus, error) {
+		return c.serviceStatus(ctx, serviceStatusInput{
+			projectID:        project.ID,
+			appID:            app.ID,
+			appName:          appName,
+			serviceName:      request.ServiceName,
+			deploymentTarget: deploymentTarget,
+			agent:            agent,
+		})
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting service status")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := ServiceStatusResponse{
+		Status: serviceStatus,
+	}
+
+	cacheResult.SetHeaders(w)
+	c.WriteResult(w, r, res)
+}
+
+type serviceStatusInput struct {
+	projectID        uint
+	appID            uint
+	appName          string
+	serviceName      string
+	deploymentTarget deployment_target.DeploymentTarget
+	agent            *kubernetes.Agent
+}
+
+// serviceStatus lists the app's revisions and computes the status of its services from the cluster
+func (c *ServiceStatusHandler) serviceStatus(ctx context.Context, inp serviceStatusInput) (porter_app.ServiceStatus, error) {
+	ctx, span := telemetry.NewSpan(ctx, "get-service-status")
+	defer span.End()
+
 	listAppRevisionsReq := connect.NewRequest(&porterv1.ListAppRevisionsRequest{
-		ProjectId:          int64(project.ID),
-		AppId:              int64(app.ID),
-		DeploymentTargetId: request.DeploymentTargetID,
-		AppName:            appName,
+		ProjectId:          int64(inp.projectID),
+		AppId:              int64(inp.appID),
+		DeploymentTargetId: inp.deploymentTarget.ID,
+		AppName:            inp.appName,
 	})
 
 	listAppRevisionsResp, err := c.Config().ClusterControlPlaneClient.ListAppRevisions(ctx, listAppRevisionsReq)
 	if err != nil {
-		err = telemetry.Error(ctx, span, err, "error listing app revisions")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-		return
+		return porter_app.ServiceStatus{}, telemetry.Error(ctx, span, err, "error listing app revisions")
 	}
 
 	if listAppRevisionsResp == nil || listAppRevisionsResp.Msg == nil {
-		err = telemetry.Error(ctx, span, nil, "list app revisions response is nil")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-		return
+		return porter_app.ServiceStatus{}, telemetry.Error(ctx, span, nil, "list app revisions response is nil")
 	}
 
 	appRevisions := listAppRevisionsResp.Msg.AppRevisions
@@ -147,30 +194,22 @@ func (c *ServiceStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	for _, revision := range appRevisions {
 		encodedRevision, err := porter_app.EncodedRevisionFromProto(ctx, revision)
 		if err != nil {
-			err := telemetry.Error(ctx, span, err, "error getting encoded revision from proto")
-			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-			return
+			return porter_app.ServiceStatus{}, telemetry.Error(ctx, span, err, "error getting encoded revision from proto")
 		}
 
 		revisions = append(revisions, encodedRevision)
 	}
 
 	serviceStatus, err := porter_app.GetServiceStatus(ctx, porter_app.GetServiceStatusInput{
-		DeploymentTarget: deploymentTarget,
-		Agent:            *agent,
-		AppName:          appName,
-		ServiceName:      request.ServiceName,
+		DeploymentTarget: inp.deploymentTarget,
+		Agent:            *inp.agent,
+		AppName:          inp.appName,
+		ServiceName:      inp.serviceName,
 		AppRevisions:     revisions,
 	})
 	if err != nil {
-		err := telemetry.Error(ctx, span, err, "error getting service status")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-		return
+		return porter_app.ServiceStatus{}, telemetry.Error(ctx, span, err, "error getting service status")
 	}
 
-	res := ServiceStatusResponse{
-		Status: serviceStatus,
-	}
-
-	c.WriteResult(w, r, res)
+	return serviceStatus, nil
 }
@@ -0,0 +1,182 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// tagLabelPrefix prefixes the label each tag of an app sets on the namespace of the app
+const tagLabelPrefix = "tags.porter.run/"
+
+// UpdatePorterAppTagsHandler handles POST /apps/{porter_app_name}/tags, which replaces the tags of an app and relabels
+// its namespace, without deploying the app
+type UpdatePorterAppTagsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewUpdatePorterAppTagsHandler returns a new UpdatePorterAppTagsHandler
+func NewUpdatePorterAppTagsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdatePorterAppTagsHandler {
+	return &UpdatePorterAppTagsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpdatePorterAppTagsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-porter-app-tags")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-name", Value: appName})
+
+	request := &types.UpdatePorterAppTagsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	tags, err := normalizeTags(request.Tags)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid tags")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "tags", Value: strings.Join(tags, ",")})
+
+	if err := authorizeAppAction(ctx, c.Config(), r, appName, types.PorterAppGrantPermission_Deploy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing tag update")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
+	porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	namespace := utils.NamespaceFromPorterAppName(porterApp.Name)
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := labelNamespaceWithTags(ctx, k8sAgent.Clientset, namespace, tags); err != nil {
+		err = telemetry.Error(ctx, span, err, "error labeling namespace with tags")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	porterApp.Tags = tags
+	porterApp, err = c.Repo().PorterApp().UpdatePorterApp(porterApp)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, porterApp.ToPorterAppType())
+}
+
+// normalizeTags sorts and removes duplicates from the tags of an app, and returns an error if a tag cannot be used as
+// the name of a kubernetes label
+func normalizeTags(tags []string) (models.PorterAppTags, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make(models.PorterAppTags, 0, len(tags))
+
+	for _, tag := range tags {
+		if tag == "" {
+			return nil, errors.New("tags cannot be empty")
+		}
+		if errs := validation.IsValidLabelValue(tag); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid tag %s: %s", tag, strings.Join(errs, ", "))
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+
+	return normalized, nil
+}
+
+// tagLabels returns the labels the tags of an app set on its namespace
+func tagLabels(tags []string) map[string]string {
+	labels := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		labels[tagLabelPrefix+tag] = "true"
+	}
+
+	return labels
+}
+
+// labelNamespaceWithTags replaces the tag labels of the namespace of an app with the labels of tags. Other labels of the
+// namespace are left as they are.
+func labelNamespaceWithTags(ctx context.Context, clientset k8s.Interface, namespace string, tags []string) error {
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("namespace %s not found", namespace)
+		}
+		return fmt.Errorf("error reading namespace %s: %w", namespace, err)
+	}
+
+	labels := make(map[string]string, len(ns.Labels)+len(tags))
+	for key, value := range ns.Labels {
+		if !strings.HasPrefix(key, tagLabelPrefix) {
+			labels[key] = value
+		}
+	}
+	for key, value := range tagLabels(tags) {
+		labels[key] = value
+	}
+	ns.Labels = labels
+
+	if _, err := clientset.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating labels of namespace %s: %w", namespace, err)
+	}
+
+	return nil
+}
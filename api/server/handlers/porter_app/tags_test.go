@@ -0,0 +1,65 @@
+package porter_app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	got, err := normalizeTags([]string{"staging", "team-payments", "staging"})
+	if err != nil {
+		t.Fatalf("expected the tags to be valid, got %v", err)
+	}
+	if want := (models.PorterAppTags{"staging", "team-payments"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	for _, tags := range [][]string{{""}, {"team payments"}, {"-staging"}} {
+		if _, err := normalizeTags(tags); err == nil {
+			t.Errorf("expected %q to be rejected", tags)
+		}
+	}
+}
+
+func TestLabelNamespaceWithTags(t *testing.T) {
+	ctx := context.Background()
+	namespace := "porter-stack-storefront"
+
+	clientset := fake.NewSimpleClientset(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace,
+			Labels: map[string]string{
+				"kubernetes.io/metadata.name": namespace,
+				tagLabelPrefix + "staging":    "true",
+			},
+		},
+	})
+
+	if err := labelNamespaceWithTags(ctx, clientset, namespace, []string{"production", "team-payments"}); err != nil {
+		t.Fatalf("expected the namespace to be labeled, got %v", err)
+	}
+
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"kubernetes.io/metadata.name":    namespace,
+		tagLabelPrefix + "production":    "true",
+		tagLabelPrefix + "team-payments": "true",
+	}
+	if !reflect.DeepEqual(ns.Labels, want) {
+		t.Errorf("expected the tag labels to be replaced and other labels kept, got %v", ns.Labels)
+	}
+
+	if err := labelNamespaceWithTags(ctx, clientset, "porter-stack-missing", nil); err == nil {
+		t.Error("expected an error labeling a namespace which does not exist")
+	}
+}
@@ -3,6 +3,7 @@ package porter_app
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"net/http"
 
 	"connectrpc.com/connect"
@@ -14,8 +15,10 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/secretstores"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
 	"github.com/porter-dev/porter/internal/telemetry"
 )
 
@@ -137,8 +140,9 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var overrides *porterv1.PorterApp
 	appProto := &porterv1.PorterApp{}
 
-	var previewEnvVariables map[string]string
+	var previewEnvVariables, previewSecrets map[string]string
 	envVariables := request.Variables
+	appSecrets := request.Secrets
 
 	// get app definition from either base64 yaml or base64 porter app proto
 	if request.Base64AppProto != "" {
@@ -184,6 +188,19 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// env variables set with valueFrom are read from the project's secret stores, and only ever written to the app's secret
+		decoded, secretRefs, err := envvalues.ExtractSecretReferences(decoded)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error reading secret references from yaml")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+		if len(secretRefs.Services) > 0 {
+			err := telemetry.Error(ctx, span, nil, "valueFrom is only supported in the app env and the preview env")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
 		appFromYaml, err := porter_app.ParseYAML(ctx, decoded, request.Name)
 		if err != nil {
 			err := telemetry.Error(ctx, span, err, "error parsing yaml")
@@ -201,6 +218,41 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			previewEnvVariables = appFromYaml.PreviewApp.EnvVariables
 		}
 
+		if !secretRefs.Empty() {
+			telemetry.WithAttributes(span,
+				telemetry.AttributeKV{Key: "secret-references", Value: len(secretRefs.App)},
+				telemetry.AttributeKV{Key: "preview-secret-references", Value: len(secretRefs.Previews)},
+			)
+
+			resolver := secretstores.NewResolver(secretstores.RepoStoreLookup(c.Repo(), project.ID))
+
+			resolved, err := resolver.Resolve(ctx, secretRefs.App)
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "error reading secrets from secret stores")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+				return
+			}
+			appSecrets, err = mergeResolvedSecrets(request.Secrets, envVariables, resolved.Values)
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "error merging secrets from secret stores")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+				return
+			}
+
+			resolvedPreviews, err := resolver.Resolve(ctx, secretRefs.Previews)
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "error reading preview secrets from secret stores")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+				return
+			}
+			previewSecrets, err = mergeResolvedSecrets(nil, previewEnvVariables, resolvedPreviews.Values)
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "error merging preview secrets from secret stores")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+				return
+			}
+		}
+
 		addons = appFromYaml.Addons
 	}
 
@@ -213,6 +265,12 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		appProto.Name = request.Name
 	}
 
+	if err := authorizeAppAction(ctx, c.Config(), r, appProto.Name, types.PorterAppGrantPermission_Deploy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		c.HandleAPIError(w, r, appAccessError(err))
+		return
+	}
+
 	sourceType, image, err := sourceFromAppAndGitSource(ctx, appProto, request.GitSource)
 	if err != nil {
 		err := telemetry.Error(ctx, span, err, "error getting source from app and git source")
@@ -266,10 +324,11 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		AppRevisionId: request.AppRevisionID,
 		AppEnv: &porterv1.EnvGroupVariables{
 			Normal: envVariables,
-			Secret: request.Secrets,
+			Secret: appSecrets,
 		},
 		AppEnvOverrides: &porterv1.EnvGroupVariables{
 			Normal: previewEnvVariables,
+			Secret: previewSecrets,
 		},
 		Deletions: &porterv1.Deletions{
 			ServiceNames:     request.Deletions.ServiceNames,
@@ -288,6 +347,7 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	})
 
 	ccpResp, err := c.Config().ClusterControlPlaneClient.UpdateApp(ctx, updateReq)
+	c.Config().StatusQueryCoalescer.Invalidate(cluster.ID, appProto.Name)
 	if err != nil {
 		err := telemetry.Error(ctx, span, err, "error calling ccp update app")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
@@ -375,3 +435,26 @@ func mergeEnvVariables(currentEnv, previousEnv map[string]string) map[string]str
 
 	return env
 }
+
+// mergeResolvedSecrets adds the values read from secret stores to the secrets of a request. A variable which is also
+// set as a secret in the request is rejected, since it is unclear which value should win, and one which is also set
+// as a normal variable is removed from them, so that its value is only written to the secret.
+func mergeResolvedSecrets(secrets, normal, resolved map[string]string) (map[string]string, error) {
+	if len(resolved) == 0 {
+		return secrets, nil
+	}
+
+	merged := make(map[string]string, len(secrets)+len(resolved))
+	for k, v := range secrets {
+		merged[k] = v
+	}
+	for k, v := range resolved {
+		if _, ok := merged[k]; ok {
+			return nil, fmt.Errorf("env variable %s is set with valueFrom and is also set as a secret", k)
+		}
+		merged[k] = v
+		delete(normal, k)
+	}
+
+	return merged, nil
+}
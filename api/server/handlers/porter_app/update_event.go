@@ -0,0 +1,157 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdatePorterAppEventHandler finishes an event of an app, such as a build or pre-deploy job, and adds to its metadata
+type UpdatePorterAppEventHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdatePorterAppEventHandler returns a new UpdatePorterAppEventHandler
+func NewUpdatePorterAppEventHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdatePorterAppEventHandler {
+	return &UpdatePorterAppEventHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// errInvalidEventTransition is returned when an update would change the status of a finished event
+var errInvalidEventTransition = errors.New("invalid event status transition")
+
+func (c *UpdatePorterAppEventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-porter-app-event")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	eventIDParam, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppEventID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app event id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-name", Value: appName},
+		telemetry.AttributeKV{Key: "porter-app-event-id", Value: eventIDParam},
+	)
+
+	eventID, err := uuid.Parse(eventIDParam)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error parsing porter app event id as uuid")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &types.UpdatePorterAppEventRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-event-status", Value: string(request.Status)})
+
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	event, err := updatePorterAppEvent(ctx, c.Config(), app.ID, eventID, request)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app event not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		if errors.Is(err, errInvalidEventTransition) {
+			err = telemetry.Error(ctx, span, err, "invalid porter app event update")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error updating porter app event")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// finished builds are tracked as they are when they are finished through the create or update endpoint
+	if event.Type == string(types.PorterAppEventType_Build) && request.Status != "" {
+		validateApplyV2 := project.GetFeatureFlag(models.ValidateApplyV2, c.Config().LaunchDarklyClient)
+		reportBuildStatus(ctx, &types.CreateOrUpdatePorterAppEventRequest{
+			ID:       event.ID.String(),
+			Status:   request.Status,
+			Type:     types.PorterAppEventType_Build,
+			Metadata: request.Metadata,
+		}, c.Config(), user, project, appName, validateApplyV2)
+	}
+
+	c.WriteResult(w, r, event.ToPorterAppEvent())
+}
+
+// updatePorterAppEvent transitions an event of an app to the status of the request and merges the request metadata into
+// that of the event. Events of other apps are not found.
+func updatePorterAppEvent(ctx context.Context, conf *config.Config, appID uint, eventID uuid.UUID, request *types.UpdatePorterAppEventRequest) (*models.PorterAppEvent, error) {
+	event, err := conf.Repo.PorterAppEvent().ReadEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.PorterAppID != appID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if request.Status != "" {
+		current := types.PorterAppEventStatus(event.Status)
+		if !current.CanTransitionTo(request.Status) {
+			return nil, fmt.Errorf("%w: %s event is %s and cannot become %s", errInvalidEventTransition, event.Type, current, request.Status)
+		}
+		event.Status = string(request.Status)
+	}
+
+	if event.Metadata == nil {
+		event.Metadata = make(map[string]any)
+	}
+	for k, v := range request.Metadata {
+		event.Metadata[k] = v
+	}
+
+	if err := conf.Repo.PorterAppEvent().UpdateEvent(ctx, &event); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
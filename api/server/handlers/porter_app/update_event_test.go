@@ -0,0 +1,112 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/eventbuffer"
+	"github.com/porter-dev/porter/internal/repository/test"
+	"gorm.io/gorm"
+)
+
+func TestUpdatePorterAppEvent(t *testing.T) {
+	ctx := context.Background()
+
+	repo := test.NewRepository(true)
+	conf := &config.Config{Repo: repo}
+
+	event, err := createPreDeployEvent(ctx, repo.PorterAppEvent(), 1, types.PorterAppEventStatus_Progressing, 3, "8f14e45f", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("events of other apps are not found", func(t *testing.T) {
+		_, err := updatePorterAppEvent(ctx, conf, 2, event.ID, &types.UpdatePorterAppEventRequest{Status: types.PorterAppEventStatus_Success})
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected the event not to be found, got %v", err)
+		}
+
+		_, err = updatePorterAppEvent(ctx, conf, 1, uuid.New(), &types.UpdatePorterAppEventRequest{Status: types.PorterAppEventStatus_Success})
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected the event not to be found, got %v", err)
+		}
+	})
+
+	t.Run("progressing events finish with their metadata", func(t *testing.T) {
+		updated, err := updatePorterAppEvent(ctx, conf, 1, event.ID, &types.UpdatePorterAppEventRequest{
+			Status:   types.PorterAppEventStatus_Failed,
+			Metadata: map[string]any{"exit_code": 1},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if updated.Status != string(types.PorterAppEventStatus_Failed) {
+			t.Errorf("expected the event to have failed, got %s", updated.Status)
+		}
+		if updated.Metadata["exit_code"] != 1 || updated.Metadata["revision"] != 3 {
+			t.Errorf("expected the metadata to be merged, got %v", updated.Metadata)
+		}
+	})
+
+	t.Run("finished events keep their status", func(t *testing.T) {
+		_, err := updatePorterAppEvent(ctx, conf, 1, event.ID, &types.UpdatePorterAppEventRequest{Status: types.PorterAppEventStatus_Success})
+		if !errors.Is(err, errInvalidEventTransition) {
+			t.Errorf("expected the transition to be rejected, got %v", err)
+		}
+
+		updated, err := updatePorterAppEvent(ctx, conf, 1, event.ID, &types.UpdatePorterAppEventRequest{
+			Metadata: map[string]any{"logs_url": "https://logs.example.com/pre-deploy"},
+		})
+		if err != nil {
+			t.Fatalf("expected metadata to be added to a finished event, got %v", err)
+		}
+		if updated.Status != string(types.PorterAppEventStatus_Failed) || updated.Metadata["logs_url"] == nil {
+			t.Errorf("unexpected event: %+v", updated)
+		}
+	})
+
+	stored, err := repo.PorterAppEvent().ReadEvent(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Type != string(types.PorterAppEventType_PreDeploy) || stored.Status != string(types.PorterAppEventStatus_Failed) {
+		t.Errorf("expected the update to be stored, got %+v", stored)
+	}
+}
+
+func TestCreatePorterAppEventWrittenBeforeReturned(t *testing.T) {
+	ctx := context.Background()
+
+	repo := test.NewRepository(true)
+	// the buffer is never run, so only events written synchronously reach the repository
+	buffer := eventbuffer.New(repo.PorterAppEvent(), eventbuffer.Options{})
+	conf := &config.Config{Repo: eventbuffer.WrapRepository(repo, buffer)}
+
+	project := models.Project{}
+	project.ID = 1
+	cluster := models.Cluster{ProjectID: 1}
+	cluster.ID = 2
+
+	if _, err := repo.PorterApp().CreatePorterApp(&models.PorterApp{ProjectID: 1, ClusterID: 2, Name: "web"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handler := &CreateUpdatePorterAppEventHandler{PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(conf, nil, nil)}
+
+	// events returned to clients may be updated through another server as soon as they are returned
+	event, err := handler.createNewAppEvent(ctx, project, cluster, "web", "", types.PorterAppEventStatus_Progressing, string(types.PorterAppEventType_Build), "GITHUB", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := repo.PorterAppEvent().ReadEvent(ctx, uuid.MustParse(event.ID)); err != nil {
+		t.Errorf("expected the event to be written before it was returned, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateInactivityCleanupHandler handles POST /apps/{porter_app_name}/inactivity-cleanup, which flags the app as
+// ephemeral or opts it out of the inactivity policy of its project
+type UpdateInactivityCleanupHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateInactivityCleanupHandler returns a new UpdateInactivityCleanupHandler
+func NewUpdateInactivityCleanupHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateInactivityCleanupHandler {
+	return &UpdateInactivityCleanupHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateInactivityCleanupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-inactivity-cleanup")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-name", Value: appName})
+
+	request := &types.UpdateAppInactivityCleanupRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if request.Ephemeral != nil {
+		porterApp.Ephemeral = *request.Ephemeral
+	}
+	if request.CleanupDisabled != nil {
+		porterApp.InactivityCleanupDisabled = *request.CleanupDisabled
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "ephemeral", Value: porterApp.Ephemeral},
+		telemetry.AttributeKV{Key: "inactivity-cleanup-disabled", Value: porterApp.InactivityCleanupDisabled},
+	)
+
+	porterApp, err = c.Repo().PorterApp().UpdatePorterApp(porterApp)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, porterApp.ToPorterAppType())
+}
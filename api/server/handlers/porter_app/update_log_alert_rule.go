@@ -0,0 +1,151 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateLogAlertRuleHandler handles PATCH requests to the /apps/{porter_app_name}/alert-rules/{log_alert_rule_id} endpoint
+type UpdateLogAlertRuleHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateLogAlertRuleHandler returns a new UpdateLogAlertRuleHandler
+func NewUpdateLogAlertRuleHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateLogAlertRuleHandler {
+	return &UpdateLogAlertRuleHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// UpdateLogAlertRuleRequest is the request object for the PATCH /apps/{porter_app_name}/alert-rules/{log_alert_rule_id} endpoint.
+// Only the fields which are set are updated
+type UpdateLogAlertRuleRequest struct {
+	Name               *string                  `json:"name"`
+	ServiceName        *string                  `json:"service_name"`
+	MatchType          *types.LogAlertMatchType `json:"match_type"`
+	Pattern            *string                  `json:"pattern"`
+	Threshold          *uint                    `json:"threshold"`
+	WindowMinutes      *uint                    `json:"window_minutes"`
+	Channel            *types.LogAlertChannel   `json:"channel"`
+	SlackIntegrationID *uint                    `json:"slack_integration_id"`
+	WebhookURL         *string                  `json:"webhook_url"`
+	Enabled            *bool                    `json:"enabled"`
+}
+
+// ServeHTTP updates a log alert rule on an app
+func (c *UpdateLogAlertRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-log-alert-rule")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing app name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	ruleID, reqErr := requestutils.GetURLParamUint(r, types.URLParamLogAlertRuleID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing log alert rule id from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "log-alert-rule-id", Value: ruleID},
+	)
+
+	request := &UpdateLogAlertRuleRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rule, err := c.Repo().LogAlertRule().ReadLogAlertRule(ctx, project.ID, app.ID, ruleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "log alert rule not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if request.Name != nil {
+		rule.Name = *request.Name
+	}
+	if request.ServiceName != nil {
+		rule.ServiceName = *request.ServiceName
+	}
+	if request.MatchType != nil {
+		rule.MatchType = string(*request.MatchType)
+	}
+	if request.Pattern != nil {
+		rule.Pattern = *request.Pattern
+	}
+	if request.Threshold != nil {
+		rule.Threshold = *request.Threshold
+	}
+	if request.WindowMinutes != nil {
+		rule.WindowMinutes = *request.WindowMinutes
+	}
+	if request.Channel != nil {
+		rule.Channel = string(*request.Channel)
+	}
+	if request.SlackIntegrationID != nil {
+		rule.SlackIntegrationID = *request.SlackIntegrationID
+	}
+	if request.WebhookURL != nil {
+		rule.WebhookURL = *request.WebhookURL
+	}
+	if request.Enabled != nil {
+		rule.Enabled = *request.Enabled
+	}
+
+	if err := validateLogAlertRule(c.Repo(), rule); err != nil {
+		err := telemetry.Error(ctx, span, err, "invalid log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	rule, err = c.Repo().LogAlertRule().UpdateLogAlertRule(ctx, rule)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating log alert rule")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, rule.ToLogAlertRuleType())
+}
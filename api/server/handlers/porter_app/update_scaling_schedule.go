@@ -0,0 +1,94 @@
+package porter_app
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/scaling"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateScalingScheduleHandler handles POST /apps/{porter_app_name}/scaling-schedule, which pauses or resumes the
+// scaling schedules of the services of an app
+type UpdateScalingScheduleHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateScalingScheduleHandler returns a new UpdateScalingScheduleHandler
+func NewUpdateScalingScheduleHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateScalingScheduleHandler {
+	return &UpdateScalingScheduleHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateScalingScheduleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-scaling-schedule")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-name", Value: appName})
+
+	request := &types.UpdateScalingScheduleRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	porterApp, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "porter app not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading porter app by name")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if len(porterApp.ScalingSchedules) == 0 {
+		err := telemetry.Error(ctx, span, nil, "app has no scaling schedules")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	// services may have been scaled by hand while the schedules were paused, so they are all scaled again on resume
+	if porterApp.ScalingSchedulesPaused && !*request.Paused {
+		for _, service := range porterApp.ScalingSchedules {
+			service.Applied = nil
+		}
+	}
+	porterApp.ScalingSchedulesPaused = *request.Paused
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "scaling-schedules-paused", Value: porterApp.ScalingSchedulesPaused})
+
+	porterApp, err = c.Repo().PorterApp().UpdatePorterApp(porterApp)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating porter app")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := porterApp.ToPorterAppType()
+	res.ScalingSchedule = scaling.Status(porterApp, time.Now())
+	c.WriteResult(w, r, res)
+}
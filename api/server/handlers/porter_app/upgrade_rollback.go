@@ -0,0 +1,119 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+// upgradeFailedError is returned to the client when the upgrade of an app chart fails. It tells apart upgrades which
+// were rolled back to the previous revision, so the next deploy can go ahead, from those which leave the release
+// failed or pending because the rollback failed too.
+type upgradeFailedError struct {
+	upgradeErr error
+	// rollbackRevision is the revision the release was rolled back to, or 0 if no rollback was attempted
+	rollbackRevision int
+	rollbackErr      error
+}
+
+func (e *upgradeFailedError) Error() string {
+	switch {
+	case e.rollbackRevision == 0:
+		return fmt.Sprintf("error upgrading application: %s", e.upgradeErr)
+	case e.rollbackErr != nil:
+		return fmt.Sprintf("error upgrading application, and the rollback to revision %d also failed: %s; rollback error: %s", e.rollbackRevision, e.upgradeErr, e.rollbackErr)
+	default:
+		return fmt.Sprintf("error upgrading application, the application was rolled back to revision %d: %s", e.rollbackRevision, e.upgradeErr)
+	}
+}
+
+func (e *upgradeFailedError) Unwrap() error {
+	return e.upgradeErr
+}
+
+// statusCode is a client error if the release was left as it was before the upgrade, and a server error if the
+// release is left failed
+func (e *upgradeFailedError) statusCode() int {
+	if e.rollbackErr != nil {
+		return http.StatusInternalServerError
+	}
+
+	return http.StatusBadRequest
+}
+
+// rollbackFailedUpgrade rolls the app chart back to the revision it was at before a failed upgrade, if rollback is
+// set, and records the failed deploy with the outcome of the rollback
+func rollbackFailedUpgrade(
+	ctx context.Context,
+	conf *config.Config,
+	helmAgent *helm.Agent,
+	projectID, clusterID uint,
+	appName string,
+	previous *release.Release,
+	tag string,
+	upgradeErr error,
+	rollback bool,
+) *upgradeFailedError {
+	ctx, span := telemetry.NewSpan(ctx, "rollback-failed-upgrade")
+	defer span.End()
+
+	res := &upgradeFailedError{upgradeErr: upgradeErr}
+
+	if rollback && previous != nil {
+		res.rollbackRevision = previous.Version
+		res.rollbackErr = helmAgent.RollbackRelease(ctx, appName, previous.Version)
+		conf.StatusQueryCoalescer.Invalidate(clusterID, appName)
+
+		telemetry.WithAttributes(span,
+			telemetry.AttributeKV{Key: "rollback-revision", Value: res.rollbackRevision},
+			telemetry.AttributeKV{Key: "rollback-succeeded", Value: res.rollbackErr == nil},
+		)
+	}
+
+	// the deploy fails either way, so errors recording the event are only traced
+	app, err := conf.Repo.PorterApp().ReadScopedPorterAppByName(projectID, clusterID, appName)
+	if err != nil {
+		_ = telemetry.Error(ctx, span, err, "error reading porter app")
+		return res
+	}
+
+	event := models.PorterAppEvent{
+		ID:                 uuid.New(),
+		Status:             string(types.PorterAppEventStatus_Failed),
+		Type:               string(types.PorterAppEventType_Deploy),
+		TypeExternalSource: "KUBERNETES",
+		PorterAppID:        app.ID,
+		Metadata: map[string]any{
+			"image_tag": tag,
+			"error":     upgradeErr.Error(),
+			"stage":     deployStage_Upgrade,
+		},
+	}
+	if previous != nil {
+		event.Metadata["revision"] = previous.Version + 1
+	}
+	if res.rollbackRevision != 0 {
+		rollbackMetadata := map[string]any{
+			"revision":  res.rollbackRevision,
+			"succeeded": res.rollbackErr == nil,
+		}
+		if res.rollbackErr != nil {
+			rollbackMetadata["error"] = res.rollbackErr.Error()
+		}
+		event.Metadata["rollback"] = rollbackMetadata
+	}
+
+	if err := conf.Repo.PorterAppEvent().CreateEvent(ctx, &event); err != nil {
+		_ = telemetry.Error(ctx, span, err, "error creating failed deploy event")
+	}
+
+	return res
+}
@@ -0,0 +1,116 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+func TestRollbackFailedUpgrade(t *testing.T) {
+	ctx := context.Background()
+	upgradeErr := errors.New("timed out waiting for the condition")
+
+	setup := func(t *testing.T) (*config.Config, *helm.Agent, *release.Release) {
+		t.Helper()
+
+		repo := test.NewRepository(true)
+		if _, err := repo.PorterApp().CreatePorterApp(&models.PorterApp{Name: "payments", ProjectID: 1, ClusterID: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		helmAgent := helm.GetAgentTesting(&helm.Form{Namespace: "porter-stack-payments"}, nil, logger.NewConsole(true), kubernetes.GetAgentTesting())
+
+		previous := deployedAt("payments", 1, 0, release.StatusSuperseded)
+		for _, rel := range []*release.Release{previous, deployedAt("payments", 2, 10, release.StatusFailed)} {
+			if err := helmAgent.ActionConfig.Releases.Create(rel); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		return &config.Config{Repo: repo}, helmAgent, previous
+	}
+
+	failedDeploy := func(t *testing.T, conf *config.Config) *models.PorterAppEvent {
+		t.Helper()
+
+		event, err := conf.Repo.PorterAppEvent().ReadLatestEventByType(ctx, 1, string(types.PorterAppEventType_Deploy))
+		if err != nil {
+			t.Fatalf("expected the failed deploy to be recorded, got %v", err)
+		}
+		if event.Status != string(types.PorterAppEventStatus_Failed) || event.Metadata["error"] != upgradeErr.Error() {
+			t.Errorf("unexpected deploy event: %+v", event)
+		}
+
+		return event
+	}
+
+	t.Run("failed upgrades are rolled back to the previous revision", func(t *testing.T) {
+		conf, helmAgent, previous := setup(t)
+
+		err := rollbackFailedUpgrade(ctx, conf, helmAgent, 1, 1, "payments", previous, "8f14e45f", upgradeErr, true)
+		if err.rollbackRevision != 1 || err.rollbackErr != nil || err.statusCode() != http.StatusBadRequest {
+			t.Errorf("expected the rollback to succeed, got %v", err)
+		}
+		if !errors.Is(err, upgradeErr) {
+			t.Errorf("expected the upgrade error to be wrapped, got %v", err)
+		}
+
+		rel, getErr := helmAgent.GetRelease(ctx, "payments", 0, false)
+		if getErr != nil {
+			t.Fatalf("unexpected error: %v", getErr)
+		}
+		if rel.Version != 3 || rel.Config["version"] != 1 {
+			t.Errorf("expected revision 3 to redeploy revision 1, got revision %d with %v", rel.Version, rel.Config)
+		}
+
+		rollback, _ := failedDeploy(t, conf).Metadata["rollback"].(map[string]any)
+		if rollback["revision"] != 1 || rollback["succeeded"] != true {
+			t.Errorf("expected the rollback to be recorded, got %v", rollback)
+		}
+	})
+
+	t.Run("failed rollbacks are returned as server errors", func(t *testing.T) {
+		conf, helmAgent, _ := setup(t)
+
+		err := rollbackFailedUpgrade(ctx, conf, helmAgent, 1, 1, "payments", deployedAt("payments", 7, 0, release.StatusSuperseded), "8f14e45f", upgradeErr, true)
+		if err.rollbackRevision != 7 || err.rollbackErr == nil || err.statusCode() != http.StatusInternalServerError {
+			t.Errorf("expected the rollback to fail, got %v", err)
+		}
+
+		rollback, _ := failedDeploy(t, conf).Metadata["rollback"].(map[string]any)
+		if rollback["succeeded"] != false || rollback["error"] == nil {
+			t.Errorf("expected the failed rollback to be recorded, got %v", rollback)
+		}
+	})
+
+	t.Run("releases are left failed when rollbacks are disabled", func(t *testing.T) {
+		conf, helmAgent, previous := setup(t)
+
+		err := rollbackFailedUpgrade(ctx, conf, helmAgent, 1, 1, "payments", previous, "8f14e45f", upgradeErr, false)
+		if err.rollbackRevision != 0 || err.statusCode() != http.StatusBadRequest {
+			t.Errorf("expected no rollback, got %v", err)
+		}
+
+		rel, getErr := helmAgent.GetRelease(ctx, "payments", 0, false)
+		if getErr != nil {
+			t.Fatalf("unexpected error: %v", getErr)
+		}
+		if rel.Version != 2 {
+			t.Errorf("expected the release to be left at revision 2, got %d", rel.Version)
+		}
+
+		if _, ok := failedDeploy(t, conf).Metadata["rollback"]; ok {
+			t.Error("expected no rollback to be recorded")
+		}
+	})
+}
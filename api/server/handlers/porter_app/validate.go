@@ -0,0 +1,347 @@
+package porter_app
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/integrations/secretstores"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/lint"
+	"github.com/porter-dev/porter/internal/registry"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ValidatePorterAppHandler runs a CreatePorterAppRequest through the same steps as CreatePorterAppHandler, stopping
+// before anything is installed, so that a broken porter.yaml is reported before the app is built
+type ValidatePorterAppHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewValidatePorterAppHandler returns a new ValidatePorterAppHandler
+func NewValidatePorterAppHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ValidatePorterAppHandler {
+	return &ValidatePorterAppHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ValidatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-validate-porter-app")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.CreatePorterAppRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error getting stack name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "application-name", Value: appName})
+
+	if project.GetFeatureFlag(models.ValidateApplyV2, c.Config().LaunchDarklyClient) {
+		err := telemetry.Error(ctx, span, nil, "validation is not supported for projects using porter apply v2")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	res := &types.ValidatePorterAppResponse{
+		Findings: []types.PorterYAMLFinding{},
+	}
+
+	porterYaml, err := base64.StdEncoding.DecodeString(request.PorterYAMLBase64)
+	if err != nil {
+		res.Findings = append(res.Findings, validationError("", fmt.Sprintf("porter_yaml is not valid base64: %s", err)))
+		c.WriteResult(w, r, res)
+		return
+	}
+
+	// full helm values replace the porter.yaml, so there is no porter.yaml to lint
+	if request.FullHelmValues == "" {
+		for _, finding := range lint.Lint(porterYaml, lint.Options{AppName: appName}) {
+			res.Findings = append(res.Findings, porterYAMLFinding(finding))
+		}
+	}
+
+	if hasValidationErrors(res.Findings) {
+		c.WriteResult(w, r, res)
+		return
+	}
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	k8sAgent, err := c.GetAgent(r, cluster, namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting k8s agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	helmRelease, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	shouldCreate := err != nil
+
+	// the image is resolved in the same way as a deploy: from the request, or else from the release being updated
+	imageInfo := request.ImageInfo
+	if helmRelease != nil && (imageInfo.Repository == "" || imageInfo.Tag == "") {
+		if request.FullHelmValues != "" {
+			imageInfo, err = attemptToGetImageInfoFromFullHelmValues(request.FullHelmValues)
+			if err != nil {
+				res.Findings = append(res.Findings, validationError("full_helm_values", fmt.Sprintf("error reading the image from full_helm_values: %s", err)))
+				c.WriteResult(w, r, res)
+				return
+			}
+		} else {
+			imageInfo = attemptToGetImageInfoFromRelease(helmRelease.Config)
+		}
+	}
+
+	if imageInfo.Repository == "" || imageInfo.Tag == "" {
+		res.Findings = append(res.Findings, validationError("image_info", "no image to deploy: image_info must set both a repository and a tag unless the app is already deployed"))
+		c.WriteResult(w, r, res)
+		return
+	}
+
+	registries, err := c.Repo().Registry().ListRegistriesByProjectID(cluster.ProjectID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing registries")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	_, registryWarnings, err := registry.DeployRegistries(c.Repo(), registries, imageInfo.Repository, c.Config().DOConf)
+	if err != nil {
+		res.Findings = append(res.Findings, validationError("image_info.repository", err.Error()))
+		c.WriteResult(w, r, res)
+		return
+	}
+
+	var releaseValues map[string]interface{}
+	var releaseDependencies []*chart.Dependency
+	if !shouldCreate {
+		releaseValues = helmRelease.Config
+		releaseDependencies = helmRelease.Chart.Metadata.Dependencies
+	}
+
+	builder := request.Builder
+	if builder == "" {
+		if app, err := c.Repo().PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, appName); err == nil {
+			builder = app.Builder
+		}
+	}
+
+	addCustomNodeSelector := (cluster.ProvisionedBy == "CAPI" && cluster.CloudProvider == "GCP") || cluster.GCPIntegrationID != 0
+
+	appChart, values, preDeployJobValues, warnings, err := parse(
+		ctx,
+		ParseConf{
+			PorterAppName:             appName,
+			PorterYaml:                porterYaml,
+			ImageInfo:                 imageInfo,
+			ServerConfig:              c.Config(),
+			ProjectID:                 cluster.ProjectID,
+			UserUpdate:                request.UserUpdate,
+			EnvGroups:                 request.EnvGroups,
+			EnvironmentGroups:         request.EnvironmentGroups,
+			Namespace:                 namespace,
+			ExistingHelmValues:        releaseValues,
+			ExistingChartDependencies: releaseDependencies,
+			SubdomainCreateOpts: SubdomainCreateOpts{
+				k8sAgent:      k8sAgent,
+				dnsRepo:       c.Repo().DNSRecord(),
+				dnsClient:     c.Config().DNSClient,
+				appRootDomain: c.Config().ServerConf.AppRootDomain,
+				stackName:     appName,
+				clusterIssuer: c.Config().ServerConf.CustomDomainClusterIssuer,
+			},
+			InjectLauncherToStartCommand: strings.Contains(builder, "heroku") || strings.Contains(builder, "paketo"),
+			ShouldValidateHelmValues:     shouldCreate,
+			FullHelmValues:               request.FullHelmValues,
+			AddCustomNodeSelector:        addCustomNodeSelector,
+			RemoveDeletedServices:        request.OverrideRelease,
+			SchedulingDefaults:           types.ClusterSchedulingDefaults(cluster.SchedulingDefaults),
+			Capabilities:                 cluster.Capabilities.ToClusterCapabilitiesType(),
+			NetworkPolicies:              cluster.NetworkPoliciesEnabled,
+			IngressNamespace:             c.Config().ServerConf.NetworkPolicyIngressNamespace,
+			Observability:                project.ObservabilityConfig.ToProjectObservabilityConfigType(),
+			SecretResolver:               secretstores.NewResolver(secretstores.RepoStoreLookup(c.Repo(), cluster.ProjectID)),
+			DryRun:                       true,
+		},
+	)
+	if err != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "parse-error", Value: err.Error()})
+		res.Findings = append(res.Findings, validationError("", err.Error()))
+		c.WriteResult(w, r, res)
+		return
+	}
+
+	res.Findings = append(res.Findings, validateResourceRequests(values, preDeployJobValues)...)
+	if hasValidationErrors(res.Findings) {
+		c.WriteResult(w, r, res)
+		return
+	}
+
+	res.Valid = true
+	res.Chart = validatedChart(appChart)
+	res.Values = values
+	res.PreDeployJobValues = preDeployJobValues
+	res.Warnings = append(warnings, registryWarnings...)
+
+	c.WriteResult(w, r, res)
+}
+
+// validationError returns an error finding which is not about a single node of porter.yaml
+func validationError(path string, message string) types.PorterYAMLFinding {
+	return types.PorterYAMLFinding{
+		Path:     path,
+		Severity: string(lint.SeverityError),
+		Message:  message,
+	}
+}
+
+func hasValidationErrors(findings []types.PorterYAMLFinding) bool {
+	for _, finding := range findings {
+		if finding.Severity == string(lint.SeverityError) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateResourceRequests checks the resource requests and limits of the services and the release job, which porter.yaml
+// passes through to the charts as they are, so that a bad quantity is reported before helm fails to install the chart
+func validateResourceRequests(values map[string]interface{}, preDeployJobValues map[string]interface{}) []types.PorterYAMLFinding {
+	var findings []types.PorterYAMLFinding
+
+	helmNames := make([]string, 0, len(values))
+	for helmName := range values {
+		helmNames = append(helmNames, helmName)
+	}
+	sort.Strings(helmNames)
+
+	for _, helmName := range helmNames {
+		serviceName, _ := getServiceNameAndTypeFromHelmName(helmName)
+		if serviceName == "" {
+			continue
+		}
+
+		serviceValues, ok := values[helmName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		findings = append(findings, validateServiceResources(serviceValues, fmt.Sprintf("services.%s.config", serviceName))...)
+	}
+
+	if preDeployJobValues != nil {
+		findings = append(findings, validateServiceResources(preDeployJobValues, "release.config")...)
+	}
+
+	return findings
+}
+
+func validateServiceResources(serviceValues map[string]interface{}, path string) []types.PorterYAMLFinding {
+	var findings []types.PorterYAMLFinding
+
+	resources, ok := serviceValues["resources"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	quantities := make(map[string]map[string]resource.Quantity)
+	for _, section := range []string{"requests", "limits"} {
+		sectionValues, ok := resources[section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		quantities[section] = make(map[string]resource.Quantity)
+		for _, name := range []string{"cpu", "memory"} {
+			value, ok := sectionValues[name]
+			if !ok || value == nil {
+				continue
+			}
+
+			fieldPath := fmt.Sprintf("%s.resources.%s.%s", path, section, name)
+
+			quantity, err := resource.ParseQuantity(fmt.Sprint(value))
+			if err != nil {
+				findings = append(findings, validationError(fieldPath, fmt.Sprintf("%s %s %v is not a valid quantity, such as 250m for cpu or 512Mi for memory", name, section, value)))
+				continue
+			}
+			if quantity.Sign() <= 0 {
+				findings = append(findings, validationError(fieldPath, fmt.Sprintf("%s %s must be greater than 0", name, section)))
+				continue
+			}
+
+			quantities[section][name] = quantity
+		}
+	}
+
+	for _, name := range []string{"cpu", "memory"} {
+		request, hasRequest := quantities["requests"][name]
+		limit, hasLimit := quantities["limits"][name]
+		if hasRequest && hasLimit && limit.Cmp(request) < 0 {
+			findings = append(findings, validationError(fmt.Sprintf("%s.resources.limits.%s", path, name), fmt.Sprintf("%s limit %s is lower than the %s request %s", name, limit.String(), name, request.String())))
+		}
+	}
+
+	return findings
+}
+
+// validatedChart returns the metadata of the app chart built from a porter.yaml
+func validatedChart(appChart *chart.Chart) *types.ValidatedChart {
+	if appChart == nil || appChart.Metadata == nil {
+		return nil
+	}
+
+	res := &types.ValidatedChart{
+		Name:         appChart.Metadata.Name,
+		Version:      appChart.Metadata.Version,
+		Dependencies: make([]types.ValidatedChartDependency, 0, len(appChart.Metadata.Dependencies)),
+	}
+
+	for _, dep := range appChart.Metadata.Dependencies {
+		res.Dependencies = append(res.Dependencies, types.ValidatedChartDependency{
+			Name:       dep.Name,
+			Alias:      dep.Alias,
+			Version:    dep.Version,
+			Repository: dep.Repository,
+		})
+	}
+
+	return res
+}
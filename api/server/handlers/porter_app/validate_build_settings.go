@@ -0,0 +1,120 @@
+package porter_app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+const bytesPerMB = 1024 * 1024
+
+// ValidateBuildSettingsHandler handles requests to the POST /apps/{porter_app_name}/build/validate endpoint
+type ValidateBuildSettingsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewValidateBuildSettingsHandler returns a new ValidateBuildSettingsHandler
+func NewValidateBuildSettingsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ValidateBuildSettingsHandler {
+	return &ValidateBuildSettingsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ValidateBuildSettingsRequest is the request object for the POST /apps/{porter_app_name}/build/validate endpoint
+type ValidateBuildSettingsRequest struct {
+	BuildSettings BuildSettings `json:"build_settings"`
+	// ContextSizeBytes is the total size of the build context found by the CLI, after its ignore file is applied
+	ContextSizeBytes int64 `json:"context_size_bytes"`
+	// ContextFileCount is the number of files in the build context
+	ContextFileCount int `json:"context_file_count"`
+}
+
+// ValidateBuildSettingsResponse is the response object for the POST /apps/{porter_app_name}/build/validate endpoint
+type ValidateBuildSettingsResponse struct {
+	// Warnings are problems with the build which do not prevent it from running
+	Warnings []string `json:"warnings"`
+}
+
+// ServeHTTP checks the build settings the CLI is about to build with, returning an error if the build cannot run
+// and warnings for anything likely to make it slow
+func (c *ValidateBuildSettingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-validate-build-settings")
+	defer span.End()
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		e := telemetry.Error(ctx, span, reqErr, "error parsing app name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(e, http.StatusBadRequest))
+		return
+	}
+
+	request := &ValidateBuildSettingsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "build-method", Value: request.BuildSettings.Method},
+		telemetry.AttributeKV{Key: "context-size-bytes", Value: request.ContextSizeBytes},
+		telemetry.AttributeKV{Key: "context-file-count", Value: request.ContextFileCount},
+	)
+
+	if err := validateBuildSettings(request.BuildSettings); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid build settings")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	c.WriteResult(w, r, &ValidateBuildSettingsResponse{
+		Warnings: buildContextWarnings(request.ContextSizeBytes, c.Config().ServerConf.BuildContextWarningSizeMB),
+	})
+}
+
+func validateBuildSettings(build BuildSettings) error {
+	switch build.Method {
+	case "":
+		return errors.New("build method must be specified")
+	case "docker":
+		if build.Dockerfile == "" {
+			return errors.New("dockerfile must be specified for docker builds")
+		}
+	case "pack":
+		if build.Builder == "" {
+			return errors.New("builder must be specified for pack builds")
+		}
+	default:
+		return fmt.Errorf("invalid build method: %s", build.Method)
+	}
+
+	return nil
+}
+
+// buildContextWarnings warns when the build context is larger than the configured threshold, as every byte of it is
+// uploaded to the builder on each deploy
+func buildContextWarnings(contextSizeBytes int64, warningSizeMB int64) []string {
+	warnings := make([]string, 0)
+
+	if warningSizeMB > 0 && contextSizeBytes > warningSizeMB*bytesPerMB {
+		warnings = append(warnings, fmt.Sprintf(
+			"build context is %d MB, which is larger than the recommended %d MB. Add a .porterignore to the build context to exclude files the build does not need",
+			contextSizeBytes/bytesPerMB, warningSizeMB,
+		))
+	}
+
+	return warnings
+}
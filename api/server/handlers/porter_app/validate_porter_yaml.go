@@ -0,0 +1,177 @@
+package porter_app
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/integrations/secretstores"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/lint"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// validationAppName is the app name a porter.yaml is validated as when the request does not name the app
+const validationAppName = "porter-yaml"
+
+// validationImage stands in for the image of the app, which porter.yaml does not set and is not checked when a
+// porter.yaml is validated on its own
+var validationImage = types.ImageInfo{Repository: "porter-yaml-validation", Tag: "latest"}
+
+// ValidatePorterYAMLHandler validates a porter.yaml on its own. It runs the porter.yaml through the same parse as
+// CreatePorterAppHandler, without reading the release of the app or connecting to the cluster, so that it only reports
+// problems with the porter.yaml itself.
+type ValidatePorterYAMLHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewValidatePorterYAMLHandler returns a new ValidatePorterYAMLHandler
+func NewValidatePorterYAMLHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ValidatePorterYAMLHandler {
+	return &ValidatePorterYAMLHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ValidatePorterYAMLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-validate-porter-yaml")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.PorterAppValidateYAMLRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	appName := request.AppName
+	if appName == "" {
+		appName = validationAppName
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "application-name", Value: appName})
+
+	porterYaml, err := base64.StdEncoding.DecodeString(request.PorterYAMLBase64)
+	if err != nil {
+		c.WriteResult(w, r, porterYAMLValidation([]types.PorterYAMLFinding{
+			validationError("", fmt.Sprintf("porter_yaml is not valid base64: %s", err)),
+		}))
+		return
+	}
+
+	var findings []types.PorterYAMLFinding
+	for _, finding := range lint.Lint(porterYaml, lint.Options{AppName: request.AppName}) {
+		findings = append(findings, porterYAMLFinding(finding))
+	}
+
+	if hasValidationErrors(findings) {
+		c.WriteResult(w, r, porterYAMLValidation(findings))
+		return
+	}
+
+	_, values, preDeployJobValues, warnings, err := parse(
+		ctx,
+		ParseConf{
+			PorterAppName:            appName,
+			PorterYaml:               porterYaml,
+			ImageInfo:                validationImage,
+			ServerConfig:             c.Config(),
+			ProjectID:                project.ID,
+			Namespace:                utils.NamespaceFromPorterAppName(appName),
+			ShouldValidateHelmValues: true,
+			AddCustomNodeSelector:    (cluster.ProvisionedBy == "CAPI" && cluster.CloudProvider == "GCP") || cluster.GCPIntegrationID != 0,
+			RemoveDeletedServices:    true,
+			SchedulingDefaults:       types.ClusterSchedulingDefaults(cluster.SchedulingDefaults),
+			Capabilities:             cluster.Capabilities.ToClusterCapabilitiesType(),
+			NetworkPolicies:          cluster.NetworkPoliciesEnabled,
+			IngressNamespace:         c.Config().ServerConf.NetworkPolicyIngressNamespace,
+			Observability:            project.ObservabilityConfig.ToProjectObservabilityConfigType(),
+			SecretResolver:           secretstores.NewResolver(secretstores.RepoStoreLookup(c.Repo(), project.ID)),
+			DryRun:                   true,
+			ValuesOnly:               true,
+		},
+	)
+	if err != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "parse-error", Value: err.Error()})
+		findings = append(findings, parseErrorFinding(porterYaml, err))
+		c.WriteResult(w, r, porterYAMLValidation(findings))
+		return
+	}
+
+	for _, finding := range validateResourceRequests(values, preDeployJobValues) {
+		finding.Line, finding.Column = lint.Locate(porterYaml, finding.Path)
+		findings = append(findings, finding)
+	}
+
+	for _, warning := range warnings {
+		findings = append(findings, types.PorterYAMLFinding{
+			Severity: string(lint.SeverityWarning),
+			Message:  warning,
+		})
+	}
+
+	c.WriteResult(w, r, porterYAMLValidation(findings))
+}
+
+// porterYAMLFinding returns a lint finding as it is returned by the API
+func porterYAMLFinding(finding lint.Finding) types.PorterYAMLFinding {
+	return types.PorterYAMLFinding{
+		Line:     finding.Line,
+		Column:   finding.Column,
+		Path:     finding.Path,
+		Severity: string(finding.Severity),
+		Message:  finding.Message,
+	}
+}
+
+// porterYAMLValidation splits findings into the errors and warnings of a PorterAppValidateYAMLResponse. Notices are
+// returned as warnings.
+func porterYAMLValidation(findings []types.PorterYAMLFinding) *types.PorterAppValidateYAMLResponse {
+	res := &types.PorterAppValidateYAMLResponse{
+		Errors:   []types.PorterYAMLFinding{},
+		Warnings: []types.PorterYAMLFinding{},
+	}
+
+	for _, finding := range findings {
+		if finding.Severity == string(lint.SeverityError) {
+			res.Errors = append(res.Errors, finding)
+			continue
+		}
+		res.Warnings = append(res.Warnings, finding)
+	}
+
+	res.Valid = len(res.Errors) == 0
+
+	return res
+}
+
+// parseErrorService matches the errors parse returns for a single service
+var parseErrorService = regexp.MustCompile(`^(?:error validating service "([^"]+)"|service ([^\s:]+)):`)
+
+// parseErrorFinding returns an error returned by parse as a finding, located at the service it is about if there is one
+func parseErrorFinding(porterYaml []byte, err error) types.PorterYAMLFinding {
+	finding := validationError("", err.Error())
+
+	if match := parseErrorService.FindStringSubmatch(err.Error()); match != nil {
+		finding.Path = fmt.Sprintf("services.%s%s", match[1], match[2])
+	} else if strings.HasPrefix(err.Error(), "pre-deploy:") {
+		finding.Path = "release"
+	}
+
+	finding.Line, finding.Column = lint.Locate(porterYaml, finding.Path)
+
+	return finding
+}
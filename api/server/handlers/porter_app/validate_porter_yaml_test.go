@@ -0,0 +1,81 @@
+package porter_app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+const invalidPortPorterYaml = `version: v1stack
+services:
+  web:
+    type: web
+    run: node index.js
+release:
+  run: npm run migrate
+`
+
+func TestParseErrorFinding(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		path   string
+		line   int
+		column int
+	}{
+		{
+			name:   "invalid service values",
+			err:    errors.New(`error validating service "web": port must be specified for web services`),
+			path:   "services.web",
+			line:   3,
+			column: 3,
+		},
+		{
+			name:   "service secrets",
+			err:    errors.New("service web: secret myapp/db was not found"),
+			path:   "services.web",
+			line:   3,
+			column: 3,
+		},
+		{
+			name:   "pre-deploy secrets",
+			err:    errors.New("pre-deploy: secret myapp/db was not found"),
+			path:   "release",
+			line:   6,
+			column: 1,
+		},
+		{
+			name: "whole file",
+			err:  errors.New("'apps' and 'services' are synonymous but both were defined"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding := parseErrorFinding([]byte(invalidPortPorterYaml), tt.err)
+
+			if finding.Path != tt.path || finding.Line != tt.line || finding.Column != tt.column {
+				t.Errorf("expected %s at %d:%d, got %s at %d:%d", tt.path, tt.line, tt.column, finding.Path, finding.Line, finding.Column)
+			}
+			if finding.Severity != "error" || finding.Message != tt.err.Error() {
+				t.Errorf("expected an error with the message of the parse error, got %+v", finding)
+			}
+		})
+	}
+}
+
+func TestPorterYAMLValidation(t *testing.T) {
+	res := porterYAMLValidation([]types.PorterYAMLFinding{
+		{Severity: "warning", Message: "unknown field"},
+		{Severity: "notice", Message: "service name is inferred"},
+	})
+	if !res.Valid || len(res.Errors) != 0 || len(res.Warnings) != 2 {
+		t.Errorf("expected warnings and notices not to make porter.yaml invalid, got %+v", res)
+	}
+
+	res = porterYAMLValidation([]types.PorterYAMLFinding{validationError("services.web", "port must be specified")})
+	if res.Valid || len(res.Errors) != 1 || res.Warnings == nil {
+		t.Errorf("expected an error to make porter.yaml invalid, got %+v", res)
+	}
+}
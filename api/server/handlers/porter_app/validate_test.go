@@ -0,0 +1,106 @@
+package porter_app
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+func resourceValues(requests, limits map[string]interface{}) map[string]interface{} {
+	resources := map[string]interface{}{}
+	if requests != nil {
+		resources["requests"] = requests
+	}
+	if limits != nil {
+		resources["limits"] = limits
+	}
+
+	return map[string]interface{}{"resources": resources}
+}
+
+func TestValidateResourceRequests(t *testing.T) {
+	tests := []struct {
+		name               string
+		values             map[string]interface{}
+		preDeployJobValues map[string]interface{}
+		expPaths           []string
+	}{
+		{
+			name: "valid requests and limits",
+			values: map[string]interface{}{
+				"web-web": resourceValues(
+					map[string]interface{}{"cpu": "250m", "memory": "512Mi"},
+					map[string]interface{}{"cpu": 1, "memory": "1Gi"},
+				),
+				"global": map[string]interface{}{"image": map[string]interface{}{}},
+			},
+		},
+		{
+			name: "services without resources",
+			values: map[string]interface{}{
+				"web-web":    map[string]interface{}{"container": map[string]interface{}{"port": 8080}},
+				"worker-wkr": map[string]interface{}{},
+			},
+		},
+		{
+			name: "invalid quantities",
+			values: map[string]interface{}{
+				"web-web":    resourceValues(map[string]interface{}{"cpu": "lots", "memory": "512Mi"}, nil),
+				"worker-wkr": resourceValues(map[string]interface{}{"memory": "-1Gi"}, nil),
+			},
+			expPaths: []string{
+				"services.web.config.resources.requests.cpu",
+				"services.worker.config.resources.requests.memory",
+			},
+		},
+		{
+			name: "limit lower than request",
+			values: map[string]interface{}{
+				"web-web": resourceValues(
+					map[string]interface{}{"memory": "1Gi"},
+					map[string]interface{}{"memory": "512Mi"},
+				),
+			},
+			expPaths: []string{"services.web.config.resources.limits.memory"},
+		},
+		{
+			name:               "release job",
+			values:             map[string]interface{}{},
+			preDeployJobValues: resourceValues(map[string]interface{}{"cpu": "0"}, nil),
+			expPaths:           []string{"release.config.resources.requests.cpu"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := validateResourceRequests(tt.values, tt.preDeployJobValues)
+
+			if len(findings) != len(tt.expPaths) {
+				t.Fatalf("expected %d findings, got %v", len(tt.expPaths), findings)
+			}
+			for i, finding := range findings {
+				if finding.Path != tt.expPaths[i] {
+					t.Errorf("expected finding %d to be about %s, got %s", i, tt.expPaths[i], finding.Path)
+				}
+				if finding.Severity != "error" {
+					t.Errorf("expected finding %d to be an error, got %s", i, finding.Severity)
+				}
+			}
+
+			if !hasValidationErrors(findings) != (len(tt.expPaths) == 0) {
+				t.Errorf("expected the values to be valid: %v", len(tt.expPaths) == 0)
+			}
+		})
+	}
+}
+
+func TestHasValidationErrors(t *testing.T) {
+	warnings := []types.PorterYAMLFinding{{Severity: "warning", Message: "unknown field"}}
+	if hasValidationErrors(warnings) {
+		t.Error("expected warnings not to make porter.yaml invalid")
+	}
+
+	if !hasValidationErrors(append(warnings, validationError("image_info", "no image"))) {
+		t.Error("expected an error to make porter.yaml invalid")
+	}
+}
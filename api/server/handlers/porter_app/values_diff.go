@@ -0,0 +1,126 @@
+package porter_app
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// redactedValue replaces the values of keys which look like credentials in a values diff
+const redactedValue = "[redacted]"
+
+// credentialKeyParts are the parts of a key which mark its value, and everything below it, as a credential
+var credentialKeyParts = []string{"password", "secret", "token"}
+
+// diffValues returns the changes between two sets of helm values, sorted by path. Nested maps are compared key by key,
+// and a map which is only set on one side is reported as each of its values being added or removed. Other values,
+// including lists, are compared as a whole.
+func diffValues(oldValues, newValues map[string]interface{}) ([]types.HelmValueChange, error) {
+	// values built from porter.yaml can hold maps of strings and ints, while the values of a release are decoded from
+	// json, so both are normalized before they are compared
+	normalizedOld, err := copyValues(oldValues)
+	if err != nil {
+		return nil, err
+	}
+	normalizedNew, err := copyValues(newValues)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []types.HelmValueChange{}
+	diffMaps(&changes, "", normalizedOld, normalizedNew, false)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+func diffMaps(changes *[]types.HelmValueChange, prefix string, oldValues, newValues map[string]interface{}, redact bool) {
+	for key, oldValue := range oldValues {
+		newValue, inNew := newValues[key]
+		diffValue(changes, joinValuePath(prefix, key), oldValue, newValue, true, inNew, redact || isCredentialKey(key))
+	}
+
+	for key, newValue := range newValues {
+		if _, inOld := oldValues[key]; !inOld {
+			diffValue(changes, joinValuePath(prefix, key), nil, newValue, false, true, redact || isCredentialKey(key))
+		}
+	}
+}
+
+// diffValue appends the changes of a single value which is set in the old values, the new values or both
+func diffValue(changes *[]types.HelmValueChange, path string, oldValue, newValue interface{}, inOld, inNew bool, redact bool) {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+
+	// a map replaced by something else is reported as each of its values being removed, and the other way around
+	if oldIsMap || newIsMap {
+		if oldIsMap && newIsMap {
+			diffMaps(changes, path, oldMap, newMap, redact)
+			return
+		}
+
+		if oldIsMap {
+			diffMaps(changes, path, oldMap, nil, redact)
+			if inNew {
+				diffValue(changes, path, nil, newValue, false, true, redact)
+			}
+			return
+		}
+
+		if inOld {
+			diffValue(changes, path, oldValue, nil, true, false, redact)
+		}
+		diffMaps(changes, path, nil, newMap, redact)
+		return
+	}
+
+	change := types.HelmValueChange{Path: path}
+	switch {
+	case !inOld:
+		change.Kind = types.HelmValueChangeKind_Added
+		change.New = redactValue(newValue, redact)
+	case !inNew:
+		change.Kind = types.HelmValueChangeKind_Removed
+		change.Old = redactValue(oldValue, redact)
+	case reflect.DeepEqual(oldValue, newValue):
+		return
+	default:
+		change.Kind = types.HelmValueChangeKind_Changed
+		change.Old = redactValue(oldValue, redact)
+		change.New = redactValue(newValue, redact)
+	}
+
+	*changes = append(*changes, change)
+}
+
+func redactValue(value interface{}, redact bool) interface{} {
+	if redact && value != nil {
+		return redactedValue
+	}
+
+	return value
+}
+
+func isCredentialKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, part := range credentialKeyParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinValuePath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
@@ -0,0 +1,90 @@
+package porter_app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+func TestDiffValues(t *testing.T) {
+	oldValues := map[string]interface{}{
+		"global": map[string]interface{}{"image": map[string]interface{}{"repository": "api", "tag": "v1"}},
+		"web-web": map[string]interface{}{
+			"replicaCount": 1,
+			"container": map[string]interface{}{
+				"command": "npm start",
+				"env":     map[string]interface{}{"normal": map[string]interface{}{"PORT": "8080", "DB_PASSWORD": "hunter2", "DEBUG": "true"}},
+			},
+			"ingress": map[string]interface{}{"hosts": []interface{}{"api.example.com"}},
+		},
+		"worker-wkr": map[string]interface{}{"replicaCount": 2},
+	}
+	newValues := map[string]interface{}{
+		"global": map[string]interface{}{"image": map[string]interface{}{"repository": "api", "tag": "v2"}},
+		"web-web": map[string]interface{}{
+			// values built from porter.yaml hold ints and maps of strings, which must compare equal to the json
+			// decoded values of the release
+			"replicaCount": 1,
+			"container": map[string]interface{}{
+				"command": "npm start",
+				"env":     map[string]interface{}{"normal": map[string]string{"PORT": "8080", "DB_PASSWORD": "hunter3", "API_TOKEN": "abc"}},
+			},
+			"ingress": map[string]interface{}{"hosts": []interface{}{"api.example.com", "www.example.com"}},
+		},
+		"cron-job": map[string]interface{}{"schedule": "* * * * *"},
+	}
+
+	changes, err := diffValues(oldValues, newValues)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []types.HelmValueChange{
+		{Path: "cron-job.schedule", Kind: types.HelmValueChangeKind_Added, New: "* * * * *"},
+		{Path: "global.image.tag", Kind: types.HelmValueChangeKind_Changed, Old: "v1", New: "v2"},
+		{Path: "web-web.container.env.normal.API_TOKEN", Kind: types.HelmValueChangeKind_Added, New: redactedValue},
+		{Path: "web-web.container.env.normal.DB_PASSWORD", Kind: types.HelmValueChangeKind_Changed, Old: redactedValue, New: redactedValue},
+		{Path: "web-web.container.env.normal.DEBUG", Kind: types.HelmValueChangeKind_Removed, Old: "true"},
+		{Path: "web-web.ingress.hosts", Kind: types.HelmValueChangeKind_Changed, Old: []interface{}{"api.example.com"}, New: []interface{}{"api.example.com", "www.example.com"}},
+		{Path: "worker-wkr.replicaCount", Kind: types.HelmValueChangeKind_Removed, Old: float64(2)},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected diff:\n got %+v\nwant %+v", changes, expected)
+	}
+
+	// the values being diffed must be left alone
+	if oldValues["web-web"].(map[string]interface{})["container"].(map[string]interface{})["env"].(map[string]interface{})["normal"].(map[string]interface{})["DB_PASSWORD"] != "hunter2" {
+		t.Error("expected the old values not to be changed")
+	}
+}
+
+func TestDiffValuesRedactsNestedCredentials(t *testing.T) {
+	changes, err := diffValues(
+		map[string]interface{}{"postgres": map[string]interface{}{"secrets": map[string]interface{}{"url": "postgres://a"}}},
+		map[string]interface{}{"postgres": map[string]interface{}{"secrets": "none"}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []types.HelmValueChange{
+		{Path: "postgres.secrets", Kind: types.HelmValueChangeKind_Added, New: redactedValue},
+		{Path: "postgres.secrets.url", Kind: types.HelmValueChangeKind_Removed, Old: redactedValue},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected diff:\n got %+v\nwant %+v", changes, expected)
+	}
+}
+
+func TestDiffValuesWithoutChanges(t *testing.T) {
+	values := map[string]interface{}{"web-web": map[string]interface{}{"replicaCount": 1}}
+
+	changes, err := diffValues(values, values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
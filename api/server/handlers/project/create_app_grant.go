@@ -0,0 +1,115 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// appGrantPatternRegex matches app names, where * may stand for any part of a name
+var appGrantPatternRegex = regexp.MustCompile(`^[a-z0-9*]([a-z0-9*-]*[a-z0-9*])?$`)
+
+// CreateAppGrantHandler grants a user, or every member of a project with a role, access to the apps matching a pattern.
+// Project roles have no notion of groups, so grants for a team of developers are made to their role or to each of them.
+type CreateAppGrantHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateAppGrantHandler returns a new CreateAppGrantHandler
+func NewCreateAppGrantHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateAppGrantHandler {
+	return &CreateAppGrantHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *CreateAppGrantHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-app-grant")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	request := &types.CreatePorterAppGrantRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-pattern", Value: request.AppPattern},
+		telemetry.AttributeKV{Key: "grant-user-id", Value: request.UserID},
+		telemetry.AttributeKV{Key: "grant-role", Value: request.Role},
+		telemetry.AttributeKV{Key: "permission", Value: string(request.Permission)},
+	)
+
+	if !appGrantPatternRegex.MatchString(request.AppPattern) {
+		err := telemetry.Error(ctx, span, nil, "app pattern must be made of lowercase letters, digits, dashes and *")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if (request.UserID == 0) == (request.Role == "") {
+		err := telemetry.Error(ctx, span, nil, "exactly one of user_id and role must be set")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	holder := fmt.Sprintf("members with the %s role", request.Role)
+	if request.UserID != 0 {
+		if _, err := c.Repo().Project().ReadProjectRole(proj.ID, request.UserID); err != nil {
+			err = telemetry.Error(ctx, span, err, "user is not a member of the project")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+		holder = fmt.Sprintf("user %d", request.UserID)
+	}
+
+	grant, err := c.Repo().PorterAppGrant().CreatePorterAppGrant(ctx, &models.PorterAppGrant{
+		ProjectID:       proj.ID,
+		AppPattern:      request.AppPattern,
+		UserID:          request.UserID,
+		Role:            request.Role,
+		Permission:      string(request.Permission),
+		CreatedByUserID: user.ID,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating app grant")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	_, err = c.Repo().AuditLog().CreateAuditLogEntry(ctx, &models.AuditLogEntry{
+		ProjectID:   proj.ID,
+		Action:      string(types.AuditLogAction_AppGrantCreated),
+		ActorUserID: user.ID,
+		Description: fmt.Sprintf("%s granted %s %s access to apps matching %s", user.Email, holder, request.Permission, request.AppPattern),
+		Metadata: models.JSONB{
+			"porter_app_grant_id": grant.ID,
+			"app_pattern":         grant.AppPattern,
+			"user_id":             grant.UserID,
+			"role":                grant.Role,
+			"permission":          grant.Permission,
+		},
+	})
+	if err != nil {
+		// grants are only kept if they can be audited
+		_ = c.Repo().PorterAppGrant().DeletePorterAppGrant(ctx, grant)
+
+		err = telemetry.Error(ctx, span, err, "error writing audit log entry")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, grant.ToPorterAppGrantType())
+}
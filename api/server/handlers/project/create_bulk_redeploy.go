@@ -0,0 +1,194 @@
+package project
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/bulkredeploy"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// CreateBulkRedeployHandler handles POST /projects/{project_id}/applications/redeploy, which redeploys every app of a
+// project which matches a filter. The matching apps are resolved before responding, and redeployed in the background.
+type CreateBulkRedeployHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateBulkRedeployHandler returns a new CreateBulkRedeployHandler
+func NewCreateBulkRedeployHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateBulkRedeployHandler {
+	return &CreateBulkRedeployHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *CreateBulkRedeployHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-bulk-redeploy")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateBulkRedeployRequest{}
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+	if request.Concurrency == 0 {
+		request.Concurrency = types.DefaultBulkRedeployConcurrency
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: proj.ID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: request.ClusterID},
+		telemetry.AttributeKV{Key: "label", Value: request.Label},
+		telemetry.AttributeKV{Key: "env-group", Value: request.EnvGroup},
+		telemetry.AttributeKV{Key: "concurrency", Value: request.Concurrency},
+		telemetry.AttributeKV{Key: "dry-run", Value: request.DryRun},
+	)
+
+	if p.Config().ServerConf.BulkRedeployPollInterval <= 0 && !request.DryRun {
+		err := telemetry.Error(ctx, span, nil, "bulk redeploys are not enabled on this server")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotImplemented))
+		return
+	}
+
+	matcher, err := bulkredeploy.NewMatcher(request.BulkRedeployFilter)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid filter")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	clusters, err := p.Repo().Cluster().ListClustersByProjectID(proj.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing clusters")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	var apps []*models.PorterApp
+	clusterFound := request.ClusterID == 0
+	for _, cluster := range clusters {
+		if request.ClusterID != 0 && cluster.ID != request.ClusterID {
+			continue
+		}
+		clusterFound = true
+
+		clusterApps, err := p.Repo().PorterApp().ListScopedPorterAppsByClusterID(proj.ID, cluster.ID)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error listing porter apps")
+			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+		apps = append(apps, clusterApps...)
+	}
+	if !clusterFound {
+		err := telemetry.Error(ctx, span, nil, "cluster not found in project")
+		p.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+		return
+	}
+
+	resolved := bulkredeploy.Resolve(ctx, bulkredeploy.NewHelmReleaseSource(p.Config()), proj.ID, apps, matcher)
+
+	appNames := make([]string, 0, len(resolved))
+	for _, app := range resolved {
+		appNames = append(appNames, app.AppName)
+	}
+	denied, err := porter_app.DeniedApps(ctx, p.Config(), r, appNames, types.PorterAppGrantPermission_Deploy)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error authorizing app access")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+	// the apps which the user may not deploy are reported along with the others, but are never redeployed
+	for i, app := range resolved {
+		if err, ok := denied[app.AppName]; ok {
+			resolved[i].Status = types.BulkRedeployStatus_Skipped
+			resolved[i].Error = err.Error()
+		}
+	}
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "apps", Value: len(resolved)},
+		telemetry.AttributeKV{Key: "denied-apps", Value: len(denied)},
+	)
+
+	if request.DryRun {
+		p.WriteResult(w, r, dryRunBulkRedeploy(proj.ID, request, resolved))
+		return
+	}
+
+	now := time.Now().UTC()
+	bulkRedeploy := &models.BulkRedeploy{
+		ProjectID:    proj.ID,
+		ClusterID:    request.ClusterID,
+		Label:        request.Label,
+		EnvGroup:     request.EnvGroup,
+		Concurrency:  request.Concurrency,
+		BumpChecksum: request.BumpChecksum,
+	}
+	for _, app := range resolved {
+		op := models.BulkRedeployOperation{
+			ProjectID:   proj.ID,
+			ClusterID:   app.ClusterID,
+			PorterAppID: app.PorterAppID,
+			AppName:     app.AppName,
+			Status:      string(app.Status),
+			Error:       app.Error,
+		}
+		if app.Status != types.BulkRedeployStatus_Pending {
+			// apps which were skipped or failed while resolving the filter are finished from the start
+			op.FinishedAt = &now
+		}
+		bulkRedeploy.Operations = append(bulkRedeploy.Operations, op)
+	}
+	if resolvedCounts(resolved)[types.BulkRedeployStatus_Pending] == 0 {
+		// there is nothing for the runner to do, so the bulk redeploy is complete as soon as it is created
+		bulkRedeploy.CompletedAt = &now
+	}
+
+	bulkRedeploy, err = p.Repo().BulkRedeploy().CreateBulkRedeploy(ctx, bulkRedeploy)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating bulk redeploy")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "bulk-redeploy-id", Value: bulkRedeploy.ID.String()})
+
+	w.WriteHeader(http.StatusAccepted)
+	p.WriteResult(w, r, bulkRedeploy.ToBulkRedeployType())
+}
+
+// dryRunBulkRedeploy returns the bulk redeploy which the request would create
+func dryRunBulkRedeploy(projectID uint, request *types.CreateBulkRedeployRequest, apps []types.BulkRedeployApp) types.BulkRedeploy {
+	res := types.BulkRedeploy{
+		ProjectID:    projectID,
+		Filter:       request.BulkRedeployFilter,
+		Concurrency:  request.Concurrency,
+		BumpChecksum: request.BumpChecksum,
+		DryRun:       true,
+		Status:       types.BulkRedeployStatus_Pending,
+		Counts:       resolvedCounts(apps),
+		Apps:         append(make([]types.BulkRedeployApp, 0, len(apps)), apps...),
+	}
+
+	return res
+}
+
+// resolvedCounts returns the number of apps with each status
+func resolvedCounts(apps []types.BulkRedeployApp) map[types.BulkRedeployStatus]int {
+	counts := make(map[types.BulkRedeployStatus]int)
+	for _, app := range apps {
+		counts[app.Status]++
+	}
+
+	return counts
+}
@@ -0,0 +1,92 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DeleteAppGrantHandler revokes an app grant
+type DeleteAppGrantHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewDeleteAppGrantHandler returns a new DeleteAppGrantHandler
+func NewDeleteAppGrantHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *DeleteAppGrantHandler {
+	return &DeleteAppGrantHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *DeleteAppGrantHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-app-grant")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	grantID, reqErr := requestutils.GetURLParamUint(r, types.URLParamPorterAppGrantID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing app grant id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-grant-id", Value: grantID})
+
+	grant, err := c.Repo().PorterAppGrant().ReadPorterAppGrant(ctx, proj.ID, grantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "app grant not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading app grant")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Repo().PorterAppGrant().DeletePorterAppGrant(ctx, grant); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting app grant")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	holder := fmt.Sprintf("members with the %s role", grant.Role)
+	if grant.UserID != 0 {
+		holder = fmt.Sprintf("user %d", grant.UserID)
+	}
+
+	_, err = c.Repo().AuditLog().CreateAuditLogEntry(ctx, &models.AuditLogEntry{
+		ProjectID:   proj.ID,
+		Action:      string(types.AuditLogAction_AppGrantDeleted),
+		ActorUserID: user.ID,
+		Description: fmt.Sprintf("%s revoked the %s access of %s to apps matching %s", user.Email, grant.Permission, holder, grant.AppPattern),
+		Metadata: models.JSONB{
+			"porter_app_grant_id": grant.ID,
+			"app_pattern":         grant.AppPattern,
+			"user_id":             grant.UserID,
+			"role":                grant.Role,
+			"permission":          grant.Permission,
+		},
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error writing audit log entry")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, grant.ToPorterAppGrantType())
+}
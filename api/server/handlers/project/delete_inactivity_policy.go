@@ -0,0 +1,56 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DeleteInactivityPolicyHandler removes the inactivity policy of a project. Apps which were paused by it stay paused
+// until they are next deployed, but are no longer deleted.
+type DeleteInactivityPolicyHandler struct {
+	handlers.PorterHandler
+}
+
+// NewDeleteInactivityPolicyHandler returns a new DeleteInactivityPolicyHandler
+func NewDeleteInactivityPolicyHandler(
+	config *config.Config,
+) *DeleteInactivityPolicyHandler {
+	return &DeleteInactivityPolicyHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+func (c *DeleteInactivityPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-inactivity-policy")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	policy, err := c.Repo().InactivityPolicy().ReadInactivityPolicy(ctx, proj.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "project has no inactivity policy")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading inactivity policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Repo().InactivityPolicy().DeleteInactivityPolicy(ctx, policy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting inactivity policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,44 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// DeleteObservabilityConfigHandler removes the observability config of a project. The OpenTelemetry environment
+// variables are no longer injected into the project's apps, starting with their next deploy.
+type DeleteObservabilityConfigHandler struct {
+	handlers.PorterHandler
+}
+
+// NewDeleteObservabilityConfigHandler returns a new DeleteObservabilityConfigHandler
+func NewDeleteObservabilityConfigHandler(
+	config *config.Config,
+) *DeleteObservabilityConfigHandler {
+	return &DeleteObservabilityConfigHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+func (c *DeleteObservabilityConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-observability-config")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	proj.ObservabilityConfig = models.ProjectObservabilityConfig{}
+
+	if _, err := c.Repo().Project().UpdateProject(proj); err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating project")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,67 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DeleteSecretsProviderHandler removes an external secret store of a project. Apps which reference it with valueFrom
+// keep the values they were last deployed with, but can no longer be deployed until the references are removed.
+type DeleteSecretsProviderHandler struct {
+	handlers.PorterHandler
+}
+
+// NewDeleteSecretsProviderHandler returns a new DeleteSecretsProviderHandler
+func NewDeleteSecretsProviderHandler(
+	config *config.Config,
+) *DeleteSecretsProviderHandler {
+	return &DeleteSecretsProviderHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+func (c *DeleteSecretsProviderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-secrets-provider")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	provider, reqErr := requestutils.GetURLParamString(r, types.URLParamSecretsProvider)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing secrets provider")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "provider", Value: provider})
+
+	integration, err := c.Repo().SecretsProviderIntegration().ReadSecretsProviderIntegration(ctx, proj.ID, types.SecretsProvider(provider))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, fmt.Errorf("project has no %s secrets provider", provider), "secrets provider not found")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading secrets provider")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Repo().SecretsProviderIntegration().DeleteSecretsProviderIntegration(ctx, integration); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting secrets provider")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,73 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetBulkRedeployHandler handles GET /projects/{project_id}/applications/redeploy/{bulk_redeploy_id}, which reports
+// the progress of each app in a bulk redeploy
+type GetBulkRedeployHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetBulkRedeployHandler returns a new GetBulkRedeployHandler
+func NewGetBulkRedeployHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetBulkRedeployHandler {
+	return &GetBulkRedeployHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (p *GetBulkRedeployHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-bulk-redeploy")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	idParam, reqErr := requestutils.GetURLParamString(r, types.URLParamBulkRedeployID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error getting bulk redeploy id from url")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: proj.ID},
+		telemetry.AttributeKV{Key: "bulk-redeploy-id", Value: idParam},
+	)
+
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "bulk redeploy id is not a valid uuid")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	bulkRedeploy, err := p.Repo().BulkRedeploy().ReadBulkRedeploy(ctx, proj.ID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "bulk redeploy not found")
+			p.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading bulk redeploy")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	p.WriteResult(w, r, bulkRedeploy.ToBulkRedeployType())
+}
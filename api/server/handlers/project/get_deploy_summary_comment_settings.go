@@ -0,0 +1,32 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// GetDeploySummaryCommentSettingsHandler returns the settings for deploy summary comments on the pull requests of a project's apps
+type GetDeploySummaryCommentSettingsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetDeploySummaryCommentSettingsHandler returns a new GetDeploySummaryCommentSettingsHandler
+func NewGetDeploySummaryCommentSettingsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetDeploySummaryCommentSettingsHandler {
+	return &GetDeploySummaryCommentSettingsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetDeploySummaryCommentSettingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	c.WriteResult(w, r, proj.ToDeploySummaryCommentSettingsType())
+}
@@ -0,0 +1,51 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetInactivityPolicyHandler returns the policy which pauses and deletes the idle apps of a project
+type GetInactivityPolicyHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetInactivityPolicyHandler returns a new GetInactivityPolicyHandler
+func NewGetInactivityPolicyHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetInactivityPolicyHandler {
+	return &GetInactivityPolicyHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetInactivityPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-inactivity-policy")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	policy, err := c.Repo().InactivityPolicy().ReadInactivityPolicy(ctx, proj.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "project has no inactivity policy")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading inactivity policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, policy.ToInactivityPolicyType())
+}
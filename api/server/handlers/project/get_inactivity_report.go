@@ -0,0 +1,84 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/inactivity"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetInactivityReportHandler lists the apps which the inactivity policy of a project applies to, with the dates they
+// will be paused and deleted, so that teams can deploy or opt out the apps they still need
+type GetInactivityReportHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetInactivityReportHandler returns a new GetInactivityReportHandler
+func NewGetInactivityReportHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetInactivityReportHandler {
+	return &GetInactivityReportHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetInactivityReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-inactivity-report")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	policy, err := c.Repo().InactivityPolicy().ReadInactivityPolicy(ctx, proj.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "project has no inactivity policy")
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+		err = telemetry.Error(ctx, span, err, "error reading inactivity policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	clusters, err := c.Repo().Cluster().ListClustersByProjectID(proj.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing clusters")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	var apps []*models.PorterApp
+	for _, cluster := range clusters {
+		clusterApps, err := c.Repo().PorterApp().ListScopedPorterAppsByClusterID(proj.ID, cluster.ID)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error listing porter apps")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+		apps = append(apps, clusterApps...)
+	}
+
+	candidates, err := inactivity.Candidates(ctx, c.Repo().PorterAppEvent(), policy, apps)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error computing inactivity candidates")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "candidates", Value: len(candidates)})
+
+	c.WriteResult(w, r, types.InactivityReport{
+		Policy:      policy.ToInactivityPolicyType(),
+		GeneratedAt: time.Now().UTC(),
+		Candidates:  candidates,
+	})
+}
@@ -0,0 +1,33 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// GetObservabilityConfigHandler returns where the apps of a project send their telemetry
+type GetObservabilityConfigHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetObservabilityConfigHandler returns a new GetObservabilityConfigHandler
+func NewGetObservabilityConfigHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetObservabilityConfigHandler {
+	return &GetObservabilityConfigHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetObservabilityConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	// a project without a config gets an empty one, rather than null
+	c.WriteResult(w, r, types.ProjectObservabilityConfig(proj.ObservabilityConfig))
+}
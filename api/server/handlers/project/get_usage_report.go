@@ -0,0 +1,123 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/chargeback"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// maxUsageReportDays is the longest period a usage report can cover
+const maxUsageReportDays = 366
+
+// ProjectGetUsageReportHandler handles GET /projects/{project_id}/usage/report, which reports the deploys and builds of
+// a project and each of its apps over a period, from the daily usage rollups
+type ProjectGetUsageReportHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewProjectGetUsageReportHandler returns a new ProjectGetUsageReportHandler
+func NewProjectGetUsageReportHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ProjectGetUsageReportHandler {
+	return &ProjectGetUsageReportHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *ProjectGetUsageReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-usage-report")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.GetUsageReportRequest{}
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: proj.ID},
+		telemetry.AttributeKV{Key: "start", Value: request.Start},
+		telemetry.AttributeKV{Key: "end", Value: request.End},
+		telemetry.AttributeKV{Key: "format", Value: string(request.Format)},
+	)
+
+	start, err := time.Parse(time.DateOnly, request.Start)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "start must be formatted as YYYY-MM-DD")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	end, err := time.Parse(time.DateOnly, request.End)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "end must be formatted as YYYY-MM-DD")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if end.Before(start) {
+		err := telemetry.Error(ctx, span, nil, "end must not be before start")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if end.Sub(start) >= maxUsageReportDays*24*time.Hour {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("a usage report can cover at most %d days", maxUsageReportDays))
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	rollups, err := p.Repo().UsageRollup().ListUsageRollupsByProjectID(ctx, proj.ID, start, end)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing usage rollups")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rolledUp, err := p.Repo().UsageRollup().ListUsageRollupDays(ctx, start, end)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing rolled up days")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	report := chargeback.BuildReport(proj.ID, start, end, rollups, rolledUp)
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "apps", Value: len(report.Apps)},
+		telemetry.AttributeKV{Key: "missing-days", Value: len(report.MissingDays)},
+	)
+
+	if request.Format != types.UsageReportFormat_CSV {
+		p.WriteResult(w, r, report)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := chargeback.WriteCSV(&buf, report); err != nil {
+		err = telemetry.Error(ctx, span, err, "error writing usage report csv")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"usage-%d-%s-%s.csv\"", proj.ID, report.Start, report.End))
+	if len(report.MissingDays) > 0 {
+		// csv has no room for the missing days, so they are reported in a header instead
+		w.Header().Set("X-Porter-Usage-Missing-Days", fmt.Sprintf("%d", len(report.MissingDays)))
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
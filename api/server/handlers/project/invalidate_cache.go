@@ -0,0 +1,100 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// InvalidateCacheHandler removes cached registry tags or helm repo indexes for a project, so that the next read
+// fetches them from the registry or helm repo again
+type InvalidateCacheHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewInvalidateCacheHandler returns a new InvalidateCacheHandler
+func NewInvalidateCacheHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *InvalidateCacheHandler {
+	return &InvalidateCacheHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *InvalidateCacheHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-invalidate-cache")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.InvalidateCacheRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "resource", Value: request.Resource},
+		telemetry.AttributeKV{Key: "integration-id", Value: request.IntegrationID},
+		telemetry.AttributeKV{Key: "name", Value: request.Name},
+	)
+
+	if request.Name != "" && request.IntegrationID == 0 {
+		err := telemetry.Error(ctx, span, nil, "integration id is required when a name is set")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	resource := adapter.CacheResource(request.Resource)
+
+	if request.IntegrationID != 0 {
+		var err error
+
+		switch resource {
+		case adapter.CacheResource_RegistryTags:
+			_, err = c.Repo().Registry().ReadRegistry(proj.ID, request.IntegrationID)
+		case adapter.CacheResource_HelmRepoIndex:
+			_, err = c.Repo().HelmRepo().ReadHelmRepo(proj.ID, request.IntegrationID)
+		}
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = telemetry.Error(ctx, span, err, "integration not found in project")
+				c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+				return
+			}
+			err = telemetry.Error(ctx, span, err, "error reading integration")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	deleted, err := c.Config().ResourceCache.Invalidate(ctx, adapter.CacheScope{
+		ProjectID:     proj.ID,
+		Resource:      resource,
+		IntegrationID: request.IntegrationID,
+		Name:          request.Name,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error invalidating cache")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "deleted", Value: deleted})
+
+	c.WriteResult(w, r, &types.InvalidateCacheResponse{
+		Deleted: deleted,
+	})
+}
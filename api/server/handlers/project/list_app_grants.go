@@ -0,0 +1,52 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListAppGrantsHandler lists the app grants of a project, and whether they are enforced
+type ListAppGrantsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListAppGrantsHandler returns a new ListAppGrantsHandler
+func NewListAppGrantsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListAppGrantsHandler {
+	return &ListAppGrantsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListAppGrantsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-app-grants")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	grants, err := c.Repo().PorterAppGrant().ListPorterAppGrantsByProjectID(ctx, proj.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing app grants")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := types.ListPorterAppGrantsResponse{
+		ScopedAppAccess: proj.ScopedAppAccess,
+		Grants:          make([]types.PorterAppGrant, 0, len(grants)),
+	}
+	for _, grant := range grants {
+		res.Grants = append(res.Grants, grant.ToPorterAppGrantType())
+	}
+
+	c.WriteResult(w, r, res)
+}
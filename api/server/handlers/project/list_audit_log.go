@@ -0,0 +1,65 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// defaultAuditLogLimit is the number of entries returned when the request does not set a limit
+const defaultAuditLogLimit = 100
+
+// ListAuditLogHandler lists the audit log of a project, such as the debug recordings instance admins started on it
+type ListAuditLogHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewListAuditLogHandler returns a new ListAuditLogHandler
+func NewListAuditLogHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListAuditLogHandler {
+	return &ListAuditLogHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ListAuditLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-audit-log")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.ListAuditLogRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	limit := defaultAuditLogLimit
+	if request.Limit > 0 {
+		limit = int(request.Limit)
+	}
+
+	entries, err := c.Repo().AuditLog().ListAuditLogEntriesByProjectID(ctx, proj.ID, limit)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing audit log entries")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := types.ListAuditLogResponse{
+		Entries: make([]types.AuditLogEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		res.Entries = append(res.Entries, entry.ToAuditLogEntryType())
+	}
+
+	c.WriteResult(w, r, res)
+}
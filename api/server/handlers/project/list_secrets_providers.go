@@ -0,0 +1,50 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListSecretsProvidersHandler returns the external secret stores a project reads secrets from, without their
+// credentials
+type ListSecretsProvidersHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListSecretsProvidersHandler returns a new ListSecretsProvidersHandler
+func NewListSecretsProvidersHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListSecretsProvidersHandler {
+	return &ListSecretsProvidersHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListSecretsProvidersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-secrets-providers")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	integrations, err := c.Repo().SecretsProviderIntegration().ListSecretsProviderIntegrations(ctx, proj.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing secrets providers")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make([]*types.SecretsProviderIntegration, 0, len(integrations))
+	for _, integration := range integrations {
+		res = append(res, integration.ToSecretsProviderIntegrationType())
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -1,6 +1,7 @@
 package project_test
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/porter-dev/porter/api/server/handlers/project"
@@ -70,3 +71,95 @@ func TestFailingListMethod(t *testing.T) {
 
 	apitest.AssertResponseInternalServerError(t, rr)
 }
+
+func TestListProjectsV2Successful(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+
+	for _, name := range []string{"prod-beta", "staging", "prod-alpha"} {
+		_, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+			Name: name,
+		}, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/v2/projects?search=PROD&page=1&page_size=1", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+
+	handler := project.NewProjectListV2Handler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	proj, err := config.Repo.Project().ReadProject(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expResponse := &types.ListUserProjectsV2Response{
+		Projects: []*types.ProjectSummary{
+			{
+				ProjectList: proj.ToProjectListType(),
+				Role:        types.RoleAdmin,
+			},
+		},
+		Pagination: types.PaginationResponse{
+			NumPages:    2,
+			CurrentPage: 1,
+			NextPage:    2,
+		},
+	}
+	gotResponse := &types.ListUserProjectsV2Response{}
+
+	apitest.AssertResponseExpected(t, rr, expResponse, gotResponse)
+}
+
+func TestListProjectsV2InvalidSort(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/v2/projects?sort_by=size", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+
+	handler := project.NewProjectListV2Handler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestFailingListV2Method(t *testing.T) {
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbGet),
+		"/api/v2/projects",
+		nil,
+	)
+
+	config := apitest.LoadConfig(t, test.ListProjectSummariesMethod)
+	user := apitest.CreateTestUser(t, config, true)
+	req = apitest.WithAuthenticatedUser(t, req, user)
+
+	handler := project.NewProjectListV2Handler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseInternalServerError(t, rr)
+}
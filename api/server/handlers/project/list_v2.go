@@ -0,0 +1,86 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// defaultProjectListPageSize is the page size used when a request to GET /v2/projects does not specify one
+const defaultProjectListPageSize = 50
+
+// ProjectListV2Handler handles GET /v2/projects, a paginated version of GET /projects
+// which also includes the user's role and activity information for each project
+type ProjectListV2Handler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewProjectListV2Handler returns a new ProjectListV2Handler
+func NewProjectListV2Handler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ProjectListV2Handler {
+	return &ProjectListV2Handler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *ProjectListV2Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-projects-v2")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	request := &types.ListUserProjectsV2Request{}
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	pageSize := int(request.PageSize)
+	if pageSize == 0 {
+		pageSize = defaultProjectListPageSize
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "user-id", Value: user.ID},
+		telemetry.AttributeKV{Key: "page", Value: request.Page},
+		telemetry.AttributeKV{Key: "page-size", Value: pageSize},
+		telemetry.AttributeKV{Key: "sort-by", Value: string(request.SortBy)},
+	)
+
+	summaries, paginatedResult, err := p.Repo().Project().ListProjectSummariesByUserID(
+		ctx,
+		user.ID,
+		repository.ProjectSummaryFilter{
+			Search: request.Search,
+			SortBy: request.SortBy,
+		},
+		helpers.WithPage(int(request.Page)),
+		helpers.WithPageSize(pageSize),
+	)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing project summaries")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.ListUserProjectsV2Response{
+		Projects:   make([]*types.ProjectSummary, 0, len(summaries)),
+		Pagination: types.PaginationResponse(paginatedResult),
+	}
+
+	for _, summary := range summaries {
+		res.Projects = append(res.Projects, summary.ToProjectSummaryType())
+	}
+
+	p.WriteResult(w, r, res)
+}
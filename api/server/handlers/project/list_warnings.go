@@ -0,0 +1,75 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListProjectWarningsHandler lists the problems with a project which need the attention of its users, such as the
+// resources of deleted apps which were not released before the deadline of their cleanup
+type ListProjectWarningsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListProjectWarningsHandler returns a new ListProjectWarningsHandler
+func NewListProjectWarningsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListProjectWarningsHandler {
+	return &ListProjectWarningsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListProjectWarningsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-project-warnings")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	leaked, err := c.Repo().PorterAppCleanup().ListPorterAppCleanupsByStatus(ctx, proj.ID, string(types.PorterAppCleanupStatus_Leaked))
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing leaked porter app cleanups")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := types.ListProjectWarningsResponse{
+		Warnings: make([]types.ProjectWarning, 0, len(leaked)),
+	}
+	for _, cleanup := range leaked {
+		res.Warnings = append(res.Warnings, leakedResourcesWarning(cleanup))
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// leakedResourcesWarning describes the resources of a deleted app which were not released
+func leakedResourcesWarning(cleanup *models.PorterAppCleanup) types.ProjectWarning {
+	resources := make([]string, 0, len(cleanup.Pending))
+	for _, resource := range cleanup.Pending {
+		resources = append(resources, fmt.Sprintf("%s %s", resource.Kind, resource.Name))
+	}
+
+	createdAt := cleanup.Deadline
+	if cleanup.FinishedAt != nil {
+		createdAt = *cleanup.FinishedAt
+	}
+
+	return types.ProjectWarning{
+		Kind:      types.ProjectWarningKind_LeakedResources,
+		Message:   fmt.Sprintf("resources of deleted app %s were not released by %s, and may need to be removed from the cloud provider: %s", cleanup.AppName, cleanup.Deadline.Format("2006-01-02 15:04 MST"), strings.Join(resources, ", ")),
+		ClusterID: cleanup.ClusterID,
+		CleanupID: cleanup.ID,
+		CreatedAt: createdAt,
+	}
+}
@@ -1,6 +1,8 @@
 package project
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/porter-dev/porter/api/server/authz"
@@ -10,6 +12,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
 )
 
 // RenameProjectHandler Renames a project
@@ -18,7 +21,7 @@ type RenameProjectHandler struct {
 	authz.KubernetesAgentGetter
 }
 
-// NewRenameProjectHandler renames the project with the given name
+// NewRenameProjectHandler renames the project with the given name, or sets its default cluster
 func NewRenameProjectHandler(
 	config *config.Config,
 	decoderValidator shared.RequestDecoderValidator,
@@ -37,10 +40,31 @@ func (c *RenameProjectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if request.Name == "" && request.DefaultClusterID == nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(errors.New("name or default_cluster_id must be set"), http.StatusBadRequest))
+		return
+	}
+
 	if request.Name != "" && proj.Name != request.Name {
 		proj.Name = request.Name
 	}
 
+	if request.DefaultClusterID != nil {
+		if *request.DefaultClusterID != 0 {
+			_, err := c.Repo().Cluster().ReadCluster(proj.ID, *request.DefaultClusterID)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("project has no cluster %d", *request.DefaultClusterID), http.StatusBadRequest))
+					return
+				}
+				c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+				return
+			}
+		}
+
+		proj.DefaultClusterID = *request.DefaultClusterID
+	}
+
 	project, err := c.Repo().Project().UpdateProject(proj)
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
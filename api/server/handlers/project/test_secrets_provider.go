@@ -0,0 +1,66 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/secretstores"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// TestSecretsProviderHandler connects to an external secret store with a config, without saving it
+type TestSecretsProviderHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewTestSecretsProviderHandler returns a new TestSecretsProviderHandler
+func NewTestSecretsProviderHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *TestSecretsProviderHandler {
+	return &TestSecretsProviderHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *TestSecretsProviderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-test-secrets-provider")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateSecretsProviderRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "provider", Value: string(request.Provider)})
+
+	integration, awsIntegration, reqErr := secretsProviderFromRequest(ctx, c.Repo(), proj.ID, request)
+	if reqErr != nil {
+		_ = telemetry.Error(ctx, span, reqErr, "invalid secrets provider")
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	res := &types.TestSecretsProviderResponse{
+		Provider: request.Provider,
+	}
+
+	// a store which cannot be reached is the result of the test, not an error of the request
+	store, err := secretstores.NewStore(integration, awsIntegration)
+	if err == nil {
+		err = store.Test(ctx)
+	}
+	if err != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "test-error", Value: err.Error()})
+		res.Error = err.Error()
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,78 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UpdateAppAccessSettingsHandler turns scoped app access on or off for a project. With scoped app access on, members
+// who are not admins need an app grant to read an app, and a deploy grant to deploy, roll back or delete it.
+type UpdateAppAccessSettingsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateAppAccessSettingsHandler returns a new UpdateAppAccessSettingsHandler
+func NewUpdateAppAccessSettingsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateAppAccessSettingsHandler {
+	return &UpdateAppAccessSettingsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateAppAccessSettingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-app-access-settings")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	request := &types.UpdateAppAccessSettingsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "scoped-app-access", Value: *request.ScopedAppAccess})
+
+	if proj.ScopedAppAccess != *request.ScopedAppAccess {
+		proj.ScopedAppAccess = *request.ScopedAppAccess
+
+		if _, err := c.Repo().Project().UpdateProject(proj); err != nil {
+			err = telemetry.Error(ctx, span, err, "error updating project")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		state := "off"
+		if proj.ScopedAppAccess {
+			state = "on"
+		}
+
+		_, err := c.Repo().AuditLog().CreateAuditLogEntry(ctx, &models.AuditLogEntry{
+			ProjectID:   proj.ID,
+			Action:      string(types.AuditLogAction_ScopedAppAccessUpdated),
+			ActorUserID: user.ID,
+			Description: fmt.Sprintf("%s turned scoped app access %s", user.Email, state),
+			Metadata: models.JSONB{
+				"scoped_app_access": proj.ScopedAppAccess,
+			},
+		})
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error writing audit log entry")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	c.WriteResult(w, r, proj.ToProjectType(c.Config().LaunchDarklyClient))
+}
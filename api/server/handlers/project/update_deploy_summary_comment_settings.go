@@ -0,0 +1,62 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UpdateDeploySummaryCommentSettingsHandler updates the settings for deploy summary comments on the pull requests of a project's apps
+type UpdateDeploySummaryCommentSettingsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateDeploySummaryCommentSettingsHandler returns a new UpdateDeploySummaryCommentSettingsHandler
+func NewUpdateDeploySummaryCommentSettingsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateDeploySummaryCommentSettingsHandler {
+	return &UpdateDeploySummaryCommentSettingsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateDeploySummaryCommentSettingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-deploy-summary-comment-settings")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateDeploySummaryCommentSettingsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if request.Enabled != nil {
+		proj.DeploySummaryCommentsDisabled = !*request.Enabled
+	}
+	if request.SkipClosedPullRequests != nil {
+		proj.DeploySummaryCommentsSkipClosedPRs = *request.SkipClosedPullRequests
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "deploy-summary-comments-disabled", Value: proj.DeploySummaryCommentsDisabled},
+		telemetry.AttributeKV{Key: "deploy-summary-comments-skip-closed-prs", Value: proj.DeploySummaryCommentsSkipClosedPRs},
+	)
+
+	proj, err := c.Repo().Project().UpdateProject(proj)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating project")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, proj.ToDeploySummaryCommentSettingsType())
+}
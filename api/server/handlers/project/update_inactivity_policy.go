@@ -0,0 +1,71 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/inactivity"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UpdateInactivityPolicyHandler creates or replaces the policy which pauses and deletes the idle apps of a project
+type UpdateInactivityPolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateInactivityPolicyHandler returns a new UpdateInactivityPolicyHandler
+func NewUpdateInactivityPolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateInactivityPolicyHandler {
+	return &UpdateInactivityPolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateInactivityPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-inactivity-policy")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateInactivityPolicyRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "enabled", Value: request.Enabled},
+		telemetry.AttributeKV{Key: "name-pattern", Value: request.NamePattern},
+		telemetry.AttributeKV{Key: "idle-days", Value: request.IdleDays},
+		telemetry.AttributeKV{Key: "grace-days", Value: request.GraceDays},
+	)
+
+	if err := inactivity.ValidateNamePattern(request.NamePattern); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid name pattern")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	policy, err := c.Repo().InactivityPolicy().CreateOrUpdateInactivityPolicy(ctx, &models.InactivityPolicy{
+		ProjectID:        proj.ID,
+		Enabled:          request.Enabled,
+		NamePattern:      request.NamePattern,
+		IdleDays:         request.IdleDays,
+		GraceDays:        request.GraceDays,
+		MaxDailyRequests: request.MaxDailyRequests,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error saving inactivity policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, policy.ToInactivityPolicyType())
+}
@@ -0,0 +1,67 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UpdateObservabilityConfigHandler sets where the apps of a project send their telemetry. The config is applied to
+// each app the next time it is deployed.
+type UpdateObservabilityConfigHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateObservabilityConfigHandler returns a new UpdateObservabilityConfigHandler
+func NewUpdateObservabilityConfigHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateObservabilityConfigHandler {
+	return &UpdateObservabilityConfigHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateObservabilityConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-observability-config")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateProjectObservabilityConfigRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	sampleRate := 1.0
+	if request.SampleRate != nil {
+		sampleRate = *request.SampleRate
+	}
+
+	proj.ObservabilityConfig = models.ProjectObservabilityConfig{
+		OTLPEndpoint: request.OTLPEndpoint,
+		OTLPProtocol: request.OTLPProtocol,
+		SampleRate:   sampleRate,
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "otlp-endpoint", Value: proj.ObservabilityConfig.OTLPEndpoint},
+		telemetry.AttributeKV{Key: "sample-rate", Value: proj.ObservabilityConfig.SampleRate},
+	)
+
+	proj, err := c.Repo().Project().UpdateProject(proj)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating project")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, types.ProjectObservabilityConfig(proj.ObservabilityConfig))
+}
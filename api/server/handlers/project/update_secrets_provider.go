@@ -0,0 +1,150 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateSecretsProviderHandler creates or replaces the config a project uses to read secrets from an external store
+type UpdateSecretsProviderHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateSecretsProviderHandler returns a new UpdateSecretsProviderHandler
+func NewUpdateSecretsProviderHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateSecretsProviderHandler {
+	return &UpdateSecretsProviderHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateSecretsProviderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-secrets-provider")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateSecretsProviderRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "provider", Value: string(request.Provider)})
+
+	integration, _, reqErr := secretsProviderFromRequest(ctx, c.Repo(), proj.ID, request)
+	if reqErr != nil {
+		_ = telemetry.Error(ctx, span, reqErr, "invalid secrets provider")
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	integration, err := c.Repo().SecretsProviderIntegration().CreateOrUpdateSecretsProviderIntegration(ctx, integration)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error saving secrets provider")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, integration.ToSecretsProviderIntegrationType())
+}
+
+// secretsProviderFromRequest returns the integration a request configures, with the saved credentials of the project's
+// integration for the provider in place of those the request leaves empty, and the AWS integration it reads secrets
+// with, if any
+func secretsProviderFromRequest(
+	ctx context.Context,
+	repo repository.Repository,
+	projectID uint,
+	request *types.UpdateSecretsProviderRequest,
+) (*ints.SecretsProviderIntegration, *ints.AWSIntegration, apierrors.RequestError) {
+	saved, err := repo.SecretsProviderIntegration().ReadSecretsProviderIntegration(ctx, projectID, request.Provider)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, apierrors.NewErrInternal(fmt.Errorf("error reading secrets provider: %w", err))
+	}
+	if saved == nil {
+		saved = &ints.SecretsProviderIntegration{}
+	}
+
+	integration := &ints.SecretsProviderIntegration{
+		ProjectID: projectID,
+		Provider:  request.Provider,
+	}
+
+	switch request.Provider {
+	case types.SecretsProvider_Vault:
+		if request.Vault == nil {
+			return nil, nil, apierrors.NewErrPassThroughToClient(errors.New("vault must be set for the vault provider"), http.StatusBadRequest)
+		}
+
+		integration.VaultAddress = request.Vault.Address
+		integration.VaultNamespace = request.Vault.Namespace
+		integration.VaultMount = request.Vault.Mount
+		integration.VaultAuthMethod = request.Vault.AuthMethod
+		if integration.VaultMount == "" {
+			integration.VaultMount = "secret"
+		}
+
+		// saved credentials are only kept while the auth method they belong to is used
+		keepSaved := saved.VaultAuthMethod == request.Vault.AuthMethod
+
+		switch request.Vault.AuthMethod {
+		case types.VaultAuthMethod_Token:
+			integration.VaultToken = []byte(request.Vault.Token)
+			if request.Vault.Token == "" && keepSaved {
+				integration.VaultToken = saved.VaultToken
+			}
+			if len(integration.VaultToken) == 0 {
+				return nil, nil, apierrors.NewErrPassThroughToClient(errors.New("token must be set for the token auth method"), http.StatusBadRequest)
+			}
+		case types.VaultAuthMethod_AppRole:
+			integration.VaultRoleID = []byte(request.Vault.RoleID)
+			if request.Vault.RoleID == "" && keepSaved {
+				integration.VaultRoleID = saved.VaultRoleID
+			}
+			integration.VaultSecretID = []byte(request.Vault.SecretID)
+			if request.Vault.SecretID == "" && keepSaved {
+				integration.VaultSecretID = saved.VaultSecretID
+			}
+			if len(integration.VaultRoleID) == 0 || len(integration.VaultSecretID) == 0 {
+				return nil, nil, apierrors.NewErrPassThroughToClient(errors.New("role_id and secret_id must be set for the approle auth method"), http.StatusBadRequest)
+			}
+		}
+
+		return integration, nil, nil
+	case types.SecretsProvider_AWSSecretsManager:
+		if request.AWS == nil {
+			return nil, nil, apierrors.NewErrPassThroughToClient(errors.New("aws must be set for the aws_secrets_manager provider"), http.StatusBadRequest)
+		}
+
+		awsIntegration, err := repo.AWSIntegration().ReadAWSIntegration(projectID, request.AWS.AWSIntegrationID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, apierrors.NewErrPassThroughToClient(fmt.Errorf("project has no AWS integration %d", request.AWS.AWSIntegrationID), http.StatusBadRequest)
+			}
+			return nil, nil, apierrors.NewErrInternal(fmt.Errorf("error reading AWS integration: %w", err))
+		}
+
+		integration.AWSIntegrationID = request.AWS.AWSIntegrationID
+		integration.AWSRegion = request.AWS.Region
+
+		return integration, awsIntegration, nil
+	default:
+		return nil, nil, apierrors.NewErrPassThroughToClient(fmt.Errorf("unknown secrets provider %s", request.Provider), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,50 @@
+package registry
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// RegistryCredentialStatusListHandler lists the last known credential status of each registry of a project, which the
+// dashboard uses to prompt fixing registries that deploys skip
+type RegistryCredentialStatusListHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewRegistryCredentialStatusListHandler returns a new RegistryCredentialStatusListHandler
+func NewRegistryCredentialStatusListHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *RegistryCredentialStatusListHandler {
+	return &RegistryCredentialStatusListHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *RegistryCredentialStatusListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-registry-credential-status")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	regs, err := c.Repo().Registry().ListRegistriesByProjectID(proj.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing registries")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListRegistryCredentialStatusResponse, 0, len(regs))
+	for _, reg := range regs {
+		res = append(res, reg.ToRegistryCredentialStateType())
+	}
+
+	c.WriteResult(w, r, res)
+}
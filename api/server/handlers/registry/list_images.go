@@ -11,6 +11,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/adapter"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/registry"
 )
@@ -38,7 +39,14 @@ func (c *RegistryListImagesHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 	_reg := registry.Registry(*reg)
 	regAPI := &_reg
 
-	imgs, err := regAPI.ListImages(ctx, repoName, c.Repo(), c.Config())
+	imgs, err := adapter.GetOrFill(ctx, c.Config().ResourceCache, adapter.CacheKey{
+		ProjectID:     reg.ProjectID,
+		Resource:      adapter.CacheResource_RegistryTags,
+		IntegrationID: reg.ID,
+		Name:          repoName,
+	}, c.Config().ServerConf.RegistryTagsCacheTTL, func() ([]*types.Image, error) {
+		return regAPI.ListImages(ctx, repoName, c.Repo(), c.Config())
+	})
 	if err != nil {
 		if strings.Contains(err.Error(), "RepositoryNotFoundException") {
 			c.HandleAPIError(w, r, apierrors.NewErrNotFound(fmt.Errorf("no such repository: %s", repoName)))
@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/registry"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// registryConnectionTimeout bounds each request made to a registry while its connection is tested
+const registryConnectionTimeout = 15 * time.Second
+
+// TestRegistryConnectionHandler calls the API of a registry with the credentials its pull secrets are generated from,
+// so that misconfigured credentials are found before a deploy fails to pull
+type TestRegistryConnectionHandler struct {
+	handlers.PorterHandlerWriter
+
+	httpClient *http.Client
+}
+
+// NewTestRegistryConnectionHandler returns a new TestRegistryConnectionHandler
+func NewTestRegistryConnectionHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *TestRegistryConnectionHandler {
+	return &TestRegistryConnectionHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+		httpClient:          &http.Client{Timeout: registryConnectionTimeout},
+	}
+}
+
+func (c *TestRegistryConnectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-test-registry-connection")
+	defer span.End()
+
+	reg, _ := ctx.Value(types.RegistryScope).(*models.Registry)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "registry-id", Value: reg.ID},
+		telemetry.AttributeKV{Key: "project-id", Value: reg.ProjectID},
+	)
+
+	_reg := registry.Registry(*reg)
+	connErr := _reg.TestConnection(ctx, c.Repo(), c.Config().DOConf, c.httpClient)
+
+	res := types.TestRegistryConnectionResponse{
+		RegistryID: reg.ID,
+		Success:    connErr == nil,
+	}
+
+	// network and unexpected errors say nothing about the credentials, so they do not change the credential status
+	recordStatus := true
+	if connErr != nil {
+		var connectionErr *registry.ConnectionError
+		if !errors.As(connErr, &connectionErr) {
+			connectionErr = &registry.ConnectionError{Kind: types.RegistryConnectionErrorKind_Unknown, Err: connErr}
+		}
+
+		res.ErrorKind = connectionErr.Kind
+		res.Error = connectionErr.Error()
+		res.StatusCode = connectionErr.StatusCode
+
+		recordStatus = connectionErr.Kind == types.RegistryConnectionErrorKind_Auth || connectionErr.Kind == types.RegistryConnectionErrorKind_Permission
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "success", Value: res.Success},
+		telemetry.AttributeKV{Key: "error-kind", Value: string(res.ErrorKind)},
+		telemetry.AttributeKV{Key: "error", Value: res.Error},
+	)
+
+	if recordStatus {
+		if err := registry.RecordCredentialStatus(c.Repo().Registry(), reg, connErr); err != nil {
+			err = telemetry.Error(ctx, span, err, "error recording credential status")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	res.CredentialStatus = reg.ToRegistryCredentialStateType().Status
+
+	c.WriteResult(w, r, res)
+}
@@ -135,7 +135,7 @@ func (c *CreateReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 
 	// create the namespace if it does not exist already
-	_, err = k8sAgent.CreateNamespace(namespace, nil)
+	_, err = k8sAgent.CreateNamespace(ctx, namespace, nil)
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(telemetry.Error(ctx, span, err, "error creating namespace")))
 		return
@@ -166,7 +166,7 @@ func (c *CreateReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	if request.SyncedEnvGroups != nil && len(request.SyncedEnvGroups) > 0 {
 		for _, envGroupName := range request.SyncedEnvGroups {
 			// read the attached configmap
-			cm, _, err := k8sAgent.GetLatestVersionedConfigMap(envGroupName, namespace)
+			cm, _, err := k8sAgent.GetLatestVersionedConfigMap(ctx, envGroupName, namespace)
 			if err != nil {
 				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(telemetry.Error(ctx, span, err, "Couldn't find the env group"), http.StatusNotFound))
 				return
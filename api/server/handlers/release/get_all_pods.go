@@ -104,7 +104,7 @@ func GetPodsForRelease(ctx context.Context, helmRelease *release.Release, k8sAge
 				})
 			}
 
-			jobPods, err := getPodsForJobs(k8sAgent, helmRelease.Namespace, jobLabels)
+			jobPods, err := getPodsForJobs(ctx, k8sAgent, helmRelease.Namespace, jobLabels)
 			if err != nil {
 				err = telemetry.Error(ctx, span, err, "error getting cronjob pods")
 				return nil, err
@@ -123,7 +123,7 @@ func GetPodsForRelease(ctx context.Context, helmRelease *release.Release, k8sAge
 			}
 		}
 
-		podList, err := k8sAgent.GetPodsByLabel(strings.Join(selectors, ","), helmRelease.Namespace)
+		podList, err := k8sAgent.GetPodsByLabel(ctx, strings.Join(selectors, ","), helmRelease.Namespace)
 		if err != nil {
 			err = telemetry.Error(ctx, span, err, "error getting pods")
 			return nil, err
@@ -131,7 +131,7 @@ func GetPodsForRelease(ctx context.Context, helmRelease *release.Release, k8sAge
 
 		pods = append(pods, podList.Items...)
 
-		podList, err = k8sAgent.GetPodsByLabel(strings.Join(selectors, ","), "default")
+		podList, err = k8sAgent.GetPodsByLabel(ctx, strings.Join(selectors, ","), "default")
 		if err != nil {
 			err = telemetry.Error(ctx, span, err, "error getting pods")
 			return nil, err
@@ -148,7 +148,7 @@ func GetPodsForRelease(ctx context.Context, helmRelease *release.Release, k8sAge
 		Val: fmt.Sprintf("%d", helmRelease.Version),
 	})
 
-	jobPods, err := getPodsForJobs(k8sAgent, helmRelease.Namespace, labels)
+	jobPods, err := getPodsForJobs(ctx, k8sAgent, helmRelease.Namespace, labels)
 	if err != nil {
 		err = telemetry.Error(ctx, span, err, "error getting cronjob pods")
 		return nil, err
@@ -159,7 +159,7 @@ func GetPodsForRelease(ctx context.Context, helmRelease *release.Release, k8sAge
 	return pods, nil
 }
 
-func getPodsForJobs(agent *kubernetes.Agent, namespace string, labels []kubernetes.Label) ([]v1.Pod, error) {
+func getPodsForJobs(ctx context.Context, agent *kubernetes.Agent, namespace string, labels []kubernetes.Label) ([]v1.Pod, error) {
 	pods := make([]v1.Pod, 0)
 
 	jobs, err := agent.ListJobsByLabel(namespace, labels...)
@@ -168,7 +168,7 @@ func getPodsForJobs(agent *kubernetes.Agent, namespace string, labels []kubernet
 	}
 
 	for _, job := range jobs {
-		podList, err := agent.GetPodsByLabel("job-name="+job.Name, namespace)
+		podList, err := agent.GetPodsByLabel(ctx, "job-name="+job.Name, namespace)
 		if err != nil {
 			return nil, err
 		}
@@ -18,6 +18,7 @@ import (
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/notifier"
 	"github.com/porter-dev/porter/internal/notifier/slack"
+	"github.com/porter-dev/porter/internal/porter_app/helmimport"
 	"github.com/porter-dev/porter/internal/stacks"
 	"github.com/stefanmcshane/helm/pkg/release"
 )
@@ -278,6 +279,11 @@ func (c *UpgradeReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 
 // postUpgrade runs any necessary scripting after the release has been upgraded.
 func postUpgrade(config *config.Config, projectID, clusterID uint, release *release.Release) error {
+	// mark the first upgrade of an imported release as the point Porter adopted it
+	if err := helmimport.RecordUpgrade(context.Background(), config.Repo.HelmReleaseImport(), clusterID, release); err != nil {
+		return err
+	}
+
 	// update the relevant helm revision number if tied to a stack resource
 	return stacks.UpdateHelmRevision(config, projectID, clusterID, release)
 }
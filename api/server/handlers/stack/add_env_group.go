@@ -118,7 +118,7 @@ func (p *StackAddEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 
 	envGroupDeployErrors := make([]string, 0)
 
-	cm, err := envgroup.CreateEnvGroup(k8sAgent, types.ConfigMapInput{
+	cm, err := envgroup.CreateEnvGroup(r.Context(), k8sAgent, types.ConfigMapInput{
 		Name:            req.Name,
 		Namespace:       namespace,
 		Variables:       req.Variables,
@@ -129,7 +129,7 @@ func (p *StackCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	envGroupDeployErrors := make([]string, 0)
 
 	for _, envGroup := range req.EnvGroups {
-		cm, err := envgroup.CreateEnvGroup(k8sAgent, types.ConfigMapInput{
+		cm, err := envgroup.CreateEnvGroup(r.Context(), k8sAgent, types.ConfigMapInput{
 			Name:            envGroup.Name,
 			Namespace:       namespace,
 			Variables:       envGroup.Variables,
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// GetSettingsHandler returns the tracing settings of the server. Only the instance admin can read them.
+type GetSettingsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetSettingsHandler returns a new GetSettingsHandler
+func NewGetSettingsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetSettingsHandler {
+	return &GetSettingsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetSettingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-telemetry-settings")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	if !handlers.IsInstanceAdmin(c.Config(), user) {
+		err := telemetry.Error(ctx, span, nil, "user is not an instance admin")
+		c.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	c.WriteResult(w, r, currentSettings())
+}
+
+// UpdateSettingsHandler turns tracing on or off and changes the sample ratio, without restarting the server. Only the
+// instance admin can change them.
+type UpdateSettingsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateSettingsHandler returns a new UpdateSettingsHandler
+func NewUpdateSettingsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateSettingsHandler {
+	return &UpdateSettingsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateSettingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-telemetry-settings")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	if !handlers.IsInstanceAdmin(c.Config(), user) {
+		err := telemetry.Error(ctx, span, nil, "user is not an instance admin")
+		c.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	request := &types.UpdateTelemetrySettingsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if request.SampleRatio != nil {
+		if err := telemetry.SetSampleRatio(*request.SampleRatio); err != nil {
+			err = telemetry.Error(ctx, span, err, "invalid sample ratio")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	if request.Enabled != nil {
+		if err := telemetry.SetEnabled(*request.Enabled); err != nil {
+			err = telemetry.Error(ctx, span, err, "error enabling tracing")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	res := currentSettings()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "enabled", Value: res.Enabled},
+		telemetry.AttributeKV{Key: "sample-ratio", Value: res.SampleRatio},
+	)
+
+	c.WriteResult(w, r, res)
+}
+
+func currentSettings() types.TelemetrySettings {
+	return types.TelemetrySettings{
+		Enabled:     telemetry.Enabled(),
+		Configured:  telemetry.Configured(),
+		SampleRatio: telemetry.SampleRatio(),
+	}
+}
@@ -0,0 +1,122 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/tracing"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// createInstanceAdmin creates a user and makes them the admin of the instance
+func createInstanceAdmin(t *testing.T, config *config.Config) *models.User {
+	t.Helper()
+
+	user := apitest.CreateTestUser(t, config, true)
+	config.ServerConf.AdminUserId = strconv.FormatUint(uint64(user.ID), 10)
+
+	return user
+}
+
+func updateSettings(t *testing.T, request *types.UpdateTelemetrySettingsRequest) {
+	t.Helper()
+
+	config := apitest.LoadConfig(t)
+	authUser := createInstanceAdmin(t, config)
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPatch), "/api/admin/telemetry", request)
+	req = apitest.WithAuthenticatedUser(t, req, authUser)
+
+	handler := tracing.NewUpdateSettingsHandler(config, shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter), shared.NewDefaultResultWriter(config.Logger, config.Alerter))
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected the settings to be updated, got status %d: %s", rr.Code, rr.Body.String())
+	}
+
+	res := &types.TelemetrySettings{}
+	apitest.AssertResponseExpected(t, rr, &types.TelemetrySettings{Enabled: true, SampleRatio: *request.SampleRatio}, res)
+}
+
+func TestUpdateSampleRatioWithoutRestart(t *testing.T) {
+	defer func() { _ = telemetry.SetSampleRatio(1) }()
+
+	sampler, err := telemetry.NewSampler(telemetry.SamplerRatio)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+	defer provider.Shutdown(context.Background()) // nolint:errcheck
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	recorded := func() bool {
+		_, span := telemetry.NewSpan(context.Background(), "sampled")
+		defer span.End()
+		return span.IsRecording()
+	}
+
+	if !recorded() {
+		t.Fatalf("expected spans to be recorded at a ratio of 1")
+	}
+
+	none := 0.0
+	updateSettings(t, &types.UpdateTelemetrySettingsRequest{SampleRatio: &none})
+
+	for i := 0; i < 20; i++ {
+		if recorded() {
+			t.Fatalf("expected no spans to be recorded once the ratio is 0")
+		}
+	}
+
+	all := 1.0
+	updateSettings(t, &types.UpdateTelemetrySettingsRequest{SampleRatio: &all})
+
+	if !recorded() {
+		t.Errorf("expected spans to be recorded once the ratio is back to 1")
+	}
+}
+
+func TestUpdateSettingsInvalid(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	authUser := createInstanceAdmin(t, config)
+
+	enabled := true
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPatch), "/api/admin/telemetry", &types.UpdateTelemetrySettingsRequest{Enabled: &enabled})
+	req = apitest.WithAuthenticatedUser(t, req, authUser)
+
+	handler := tracing.NewUpdateSettingsHandler(config, shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter), shared.NewDefaultResultWriter(config.Logger, config.Alerter))
+	handler.ServeHTTP(rr, req)
+
+	// tracing cannot be enabled on a server which exports spans to no collector
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUpdateSettingsForbidden(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	// a verified porter email does not make a user an admin of the instance
+	authUser := apitest.CreateTestUser(t, config, true)
+
+	ratio := 0.5
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPatch), "/api/admin/telemetry", &types.UpdateTelemetrySettingsRequest{SampleRatio: &ratio})
+	req = apitest.WithAuthenticatedUser(t, req, authUser)
+
+	handler := tracing.NewUpdateSettingsHandler(config, shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter), shared.NewDefaultResultWriter(config.Logger, config.Alerter))
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertForbiddenError(t, rr)
+	assert.Equal(t, float64(1), telemetry.SampleRatio())
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/config/env"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/analytics"
+	"github.com/porter-dev/porter/internal/auth/password"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
 	"golang.org/x/crypto/bcrypt"
@@ -61,6 +63,11 @@ func (u *UserCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := checkPasswordPolicy(u.Config().ServerConf, request.Password); err != nil {
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
 	// hash the password using bcrypt
 	hashedPw, err := bcrypt.GenerateFromPassword([]byte(user.Password), 8)
 	if err != nil {
@@ -126,6 +133,18 @@ func doesUserExist(userRepo repository.UserRepository, user *models.User) bool {
 	return user != nil && err == nil
 }
 
+// checkPasswordPolicy checks a password which a user signs up or resets their password with against the password
+// policy of the Porter instance
+func checkPasswordPolicy(serverConf *env.ServerConf, pw string) error {
+	policy := password.Policy{
+		MinLength:           serverConf.PasswordMinLength,
+		MinCharacterClasses: serverConf.PasswordMinCharacterClasses,
+		RejectCommon:        serverConf.PasswordRejectCommon,
+	}
+
+	return policy.Validate(pw)
+}
+
 // addUserToDefaultProject adds the created user to any default projects if required by
 // config variables.
 func addUserToDefaultProject(config *config.Config, user *models.User) error {
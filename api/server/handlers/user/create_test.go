@@ -21,7 +21,7 @@ func TestCreateUserSuccessful(t *testing.T) {
 			LastName:    "Porter",
 			CompanyName: "Porter Technologies, Inc.",
 			Email:       "mrp@porter.run",
-			Password:    "somepassword",
+			Password:    "some-password",
 		},
 	)
 
@@ -59,7 +59,7 @@ func TestCreateUserBadEmail(t *testing.T) {
 			LastName:    "Porter",
 			CompanyName: "Porter Technologies, Inc.",
 			Email:       "notanemail",
-			Password:    "somepassword",
+			Password:    "some-password",
 		},
 	)
 
@@ -106,6 +106,35 @@ func TestCreateUserMissingField(t *testing.T) {
 	})
 }
 
+func TestCreateUserWeakPassword(t *testing.T) {
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/users",
+		&types.CreateUserRequest{
+			FirstName:   "Mister",
+			LastName:    "Porter",
+			CompanyName: "Porter Technologies, Inc.",
+			Email:       "mrp@porter.run",
+			Password:    "a",
+		},
+	)
+
+	config := apitest.LoadConfig(t)
+
+	handler := user.NewUserCreateHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error: "password must be at least 8 characters long; contain at least 2 of lowercase letters, uppercase letters, digits, symbols",
+	})
+}
+
 func TestCreateUserSameEmail(t *testing.T) {
 	req, rr := apitest.GetRequestAndRecorder(
 		t,
@@ -116,7 +145,7 @@ func TestCreateUserSameEmail(t *testing.T) {
 			LastName:    "Porter",
 			CompanyName: "Porter Technologies, Inc.",
 			Email:       "mrp@porter.run",
-			Password:    "somepassword",
+			Password:    "some-password",
 		},
 	)
 
@@ -148,7 +177,7 @@ func TestFailingCreateUserMethod(t *testing.T) {
 			LastName:    "Porter",
 			CompanyName: "Porter Technologies, Inc.",
 			Email:       "mrp@porter.run",
-			Password:    "somepassword",
+			Password:    "some-password",
 		},
 	)
 
@@ -175,7 +204,7 @@ func TestFailingCreateSessionMethod(t *testing.T) {
 			LastName:    "Porter",
 			CompanyName: "Porter Technologies, Inc.",
 			Email:       "mrp@porter.run",
-			Password:    "somepassword",
+			Password:    "some-password",
 		},
 	)
 
@@ -2,8 +2,10 @@ package user
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
@@ -13,6 +15,7 @@ import (
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/telemetry"
 )
 
 type VerifyEmailInitiateHandler struct {
@@ -36,6 +39,63 @@ func (v *VerifyEmailInitiateHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// ResendVerificationEmailHandler sends a new verification email to the authenticated user, invalidating the links of
+// the emails sent before it
+type ResendVerificationEmailHandler struct {
+	handlers.PorterHandler
+}
+
+func NewResendVerificationEmailHandler(
+	config *config.Config,
+) *ResendVerificationEmailHandler {
+	return &ResendVerificationEmailHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+func (v *ResendVerificationEmailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-resend-verification-email")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "user-id", Value: user.ID})
+
+	retryAfter, err := v.Config().VerifyEmailLimiter.Allow(ctx, user.Email, loginClientIP(r, v.Config().ServerConf.LoginTrustedProxies))
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error checking verification email limit")
+		v.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		err := telemetry.Error(ctx, span, nil, "too many verification emails, try again later")
+		v.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusTooManyRequests))
+		return
+	}
+
+	// users who are already verified get the same response, so that the endpoint does not reveal whether they are
+	if user.EmailVerified {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "already-verified", Value: true})
+		return
+	}
+
+	// only the link of the newest email can be used to verify the user. Password reset tokens are stored with
+	// verification tokens, and a pending password reset is left valid.
+	if err := v.Repo().PWResetToken().InvalidatePWResetTokensByEmail(user.Email, models.PWResetTokenPurpose_EmailVerification); err != nil {
+		err = telemetry.Error(ctx, span, err, "error invalidating verification tokens")
+		v.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := startEmailVerification(v.Config(), w, r, user); err != nil {
+		err = telemetry.Error(ctx, span, err, "error sending verification email")
+		v.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+}
+
 type VerifyEmailFinalizeHandler struct {
 	handlers.PorterHandlerReader
 }
@@ -113,6 +173,7 @@ func startEmailVerification(
 		&types.InitiateResetUserPasswordRequest{
 			Email: user.Email,
 		},
+		models.PWResetTokenPurpose_EmailVerification,
 	)
 	if err != nil {
 		return err
@@ -2,14 +2,18 @@ package user_test
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/handlers/user"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apitest"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/models"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -67,6 +71,7 @@ func TestEmailVerifyFinalizeSuccessful(t *testing.T) {
 		&types.InitiateResetUserPasswordRequest{
 			Email: authUser.Email,
 		},
+		models.PWResetTokenPurpose_EmailVerification,
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -96,3 +101,147 @@ func TestEmailVerifyFinalizeSuccessful(t *testing.T) {
 
 	assert.True(t, authUser.EmailVerified)
 }
+
+func TestEmailVerifyResendInvalidatesPriorTokens(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	authUser := apitest.CreateTestUser(t, config, false)
+
+	// create the token of an earlier verification email
+	_, _, err := user.CreatePWResetTokenForEmail(
+		config.Repo.PWResetToken(),
+		handlers.IgnoreAPIError,
+		nil,
+		nil,
+		&types.InitiateResetUserPasswordRequest{
+			Email: authUser.Email,
+		},
+		models.PWResetTokenPurpose_EmailVerification,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/email/verify/resend", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, authUser)
+
+	handler := user.NewResendVerificationEmailHandler(config)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	fakeNotifier := config.UserNotifier.(*apitest.FakeUserNotifier)
+
+	initiateOpts := fakeNotifier.GetSendEmailVerificationLastOpts()
+	if initiateOpts == nil {
+		t.Fatal("expected a verification email to be sent")
+	}
+
+	parsedURL, err := url.Parse(initiateOpts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "2", parsedURL.Query()["token_id"][0])
+
+	prevToken, err := config.Repo.PWResetToken().ReadPWResetToken(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.False(t, prevToken.IsValid, "expected the token of the earlier email to be invalidated")
+
+	token, err := config.Repo.PWResetToken().ReadPWResetToken(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, token.IsValid)
+}
+
+func TestEmailVerifyResendKeepsPasswordReset(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	authUser := apitest.CreateTestUser(t, config, false)
+
+	// the user has requested a password reset whose link has not been used yet
+	pwReset, _, err := user.CreatePWResetTokenForEmail(
+		config.Repo.PWResetToken(),
+		handlers.IgnoreAPIError,
+		nil,
+		nil,
+		&types.InitiateResetUserPasswordRequest{
+			Email: authUser.Email,
+		},
+		models.PWResetTokenPurpose_PasswordReset,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/email/verify/resend", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, authUser)
+
+	handler := user.NewResendVerificationEmailHandler(config)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	token, err := config.Repo.PWResetToken().ReadPWResetToken(pwReset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, token.IsValid, "expected the pending password reset to be kept")
+}
+
+func TestEmailVerifyResendAlreadyVerified(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	authUser := apitest.CreateTestUser(t, config, true)
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/email/verify/resend", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, authUser)
+
+	handler := user.NewResendVerificationEmailHandler(config)
+
+	handler.ServeHTTP(rr, req)
+
+	// the response is the same as for an unverified user, but no email is sent
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	fakeNotifier := config.UserNotifier.(*apitest.FakeUserNotifier)
+	assert.Nil(t, fakeNotifier.GetSendEmailVerificationLastOpts())
+}
+
+func TestEmailVerifyResendRateLimited(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	config.VerifyEmailLimiter = adapter.NewMemoryLoginLimiter(adapter.LoginLimitOptions{
+		Window:              time.Hour,
+		MaxAttemptsPerEmail: 1,
+		Scope:               "verify-email",
+	})
+	authUser := apitest.CreateTestUser(t, config, false)
+
+	handler := user.NewResendVerificationEmailHandler(config)
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/email/verify/resend", nil)
+	req = apitest.WithAuthenticatedUser(t, req, authUser)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req, rr = apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/email/verify/resend", nil)
+	req = apitest.WithAuthenticatedUser(t, req, authUser)
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Header().Get("Retry-After") == "" {
+		t.Errorf("expected to be told when to retry")
+	}
+	apitest.AssertResponseError(t, rr, http.StatusTooManyRequests, &types.ExternalError{
+		Error: "too many verification emails, try again later",
+	})
+}
@@ -117,55 +117,78 @@ func upsertUserFromToken(config *config.Config, tok *oauth2.Token) (*models.User
 			return nil, err
 		}
 
-		primary := ""
-		verified := false
-
-		// get the primary email
-		for _, email := range emails {
-			if email.GetPrimary() {
-				primary = email.GetEmail()
-				verified = email.GetVerified()
-				break
-			}
-		}
+		return linkOrCreateGithubUser(config, githubUser.GetID(), emails)
+	} else if err != nil {
+		return nil, fmt.Errorf("unexpected error occurred:%s", err.Error())
+	}
 
-		if primary == "" {
-			return nil, fmt.Errorf("github user must have an email")
-		}
+	return user, nil
+}
 
-		if err := checkUserRestrictions(config.ServerConf, primary); err != nil {
-			return nil, err
+// linkOrCreateGithubUser returns the user with the primary email of a github user who has not logged in with github
+// before, linking the github user to the user if the email is verified on github, or creates the user if no user has
+// that email
+func linkOrCreateGithubUser(config *config.Config, githubUserID int64, emails []*github.UserEmail) (*models.User, error) {
+	primary := ""
+	verified := false
+
+	// get the primary email
+	for _, email := range emails {
+		if email.GetPrimary() {
+			primary = email.GetEmail()
+			verified = email.GetVerified()
+			break
 		}
+	}
+
+	if primary == "" {
+		return nil, fmt.Errorf("github user must have an email")
+	}
+
+	if err := checkUserRestrictions(config.ServerConf, primary); err != nil {
+		return nil, err
+	}
 
-		// check if a user with that email address already exists
-		_, err = config.Repo.User().ReadUserByEmail(primary)
+	// check if a user with that email address already exists
+	user, err := config.Repo.User().ReadUserByEmail(primary)
 
-		if err == gorm.ErrRecordNotFound {
-			user = &models.User{
-				Email:         primary,
-				EmailVerified: !config.Metadata.Email || verified,
-				GithubUserID:  githubUser.GetID(),
-			}
+	if err == gorm.ErrRecordNotFound {
+		user = &models.User{
+			Email:         primary,
+			EmailVerified: !config.Metadata.Email || verified,
+			GithubUserID:  githubUserID,
+		}
 
-			user, err = config.Repo.User().CreateUser(user)
+		user, err = config.Repo.User().CreateUser(user)
 
-			if err != nil {
-				return nil, err
-			}
+		if err != nil {
+			return nil, err
+		}
 
-			err = addUserToDefaultProject(config, user)
+		err = addUserToDefaultProject(config, user)
 
-			if err != nil {
-				return nil, err
-			}
-		} else if err == nil {
-			return nil, fmt.Errorf("email already registered")
-		} else if err != nil {
+		if err != nil {
 			return nil, err
 		}
+
+		return user, nil
 	} else if err != nil {
-		return nil, fmt.Errorf("unexpected error occurred:%s", err.Error())
+		return nil, err
 	}
 
-	return user, nil
+	// a user who signed up with a password is only linked if github has verified that the github user owns the email,
+	// and only to one github user
+	if !verified || user.GithubUserID != 0 {
+		return nil, fmt.Errorf("email already registered")
+	}
+
+	// the password of a user whose email was never verified may have been set by someone else signing up with the email,
+	// so it is cleared now that github has verified who owns the email
+	if !user.EmailVerified {
+		user.Password = ""
+		user.EmailVerified = true
+	}
+	user.GithubUserID = githubUserID
+
+	return config.Repo.User().UpdateUser(user)
 }
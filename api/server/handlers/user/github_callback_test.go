@@ -0,0 +1,113 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+)
+
+func TestLinkOrCreateGithubUser(t *testing.T) {
+	githubEmails := func(verified bool) []*github.UserEmail {
+		return []*github.UserEmail{
+			{Email: github.String("mrp@users.noreply.github.com"), Verified: github.Bool(true)},
+			{Email: github.String("mrp@porter.run"), Primary: github.Bool(true), Verified: github.Bool(verified)},
+		}
+	}
+
+	tests := []struct {
+		name string
+		// existingUser creates a user with the email of the github user before the login, whose email is verified if
+		// verified is set
+		existingUser  bool
+		verified      bool
+		githubUserID  int64
+		githubEmails  []*github.UserEmail
+		wantErr       bool
+		wantCreatedID uint
+		// wantPassword is whether the linked user can still log in with the password set before the login
+		wantPassword bool
+	}{
+		{
+			name:          "creates a user",
+			githubEmails:  githubEmails(true),
+			wantCreatedID: 1,
+		},
+		{
+			name:         "links a password user",
+			existingUser: true,
+			verified:     true,
+			githubEmails: githubEmails(true),
+			wantPassword: true,
+		},
+		{
+			name:         "links an unverified password user",
+			existingUser: true,
+			githubEmails: githubEmails(true),
+		},
+		{
+			name:         "does not link an email github has not verified",
+			existingUser: true,
+			verified:     true,
+			githubEmails: githubEmails(false),
+			wantErr:      true,
+		},
+		{
+			name:         "does not link a user linked to another github user",
+			existingUser: true,
+			verified:     true,
+			githubUserID: 7,
+			githubEmails: githubEmails(true),
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := apitest.LoadConfig(t)
+			conf.Metadata = config.MetadataFromConf(conf.ServerConf, "test")
+
+			var password string
+			if tt.existingUser {
+				user := apitest.CreateTestUser(t, conf, tt.verified)
+				password = user.Password
+				if tt.githubUserID != 0 {
+					user.GithubUserID = tt.githubUserID
+					if _, err := conf.Repo.User().UpdateUser(user); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			user, err := linkOrCreateGithubUser(conf, 42, tt.githubEmails)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected the github user not to be linked")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			stored, err := conf.Repo.User().ReadUserByGithubUserID(42)
+			if err != nil {
+				t.Fatalf("expected the user to be found by github user id, got %v", err)
+			}
+			if stored.ID != user.ID || stored.Email != "mrp@porter.run" || !stored.EmailVerified {
+				t.Errorf("expected the verified user mrp@porter.run to be returned, got %+v", stored)
+			}
+			if tt.wantCreatedID != 0 && stored.ID != tt.wantCreatedID {
+				t.Errorf("expected a new user to be created, got user %d", stored.ID)
+			}
+			// the password of an unverified user may have been set by someone else who registered the email first
+			if tt.wantPassword && stored.Password != password {
+				t.Errorf("expected the password of the user to be kept")
+			}
+			if tt.existingUser && !tt.wantPassword && stored.Password != "" {
+				t.Errorf("expected the password of the user to be cleared")
+			}
+		})
+	}
+}
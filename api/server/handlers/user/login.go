@@ -3,7 +3,11 @@ package user
 import (
 	"errors"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/porter-dev/porter/api/server/authn"
 	"github.com/porter-dev/porter/api/server/handlers"
@@ -42,11 +46,30 @@ func (u *UserLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	retryAfter, err := u.Config().LoginLimiter.Allow(r.Context(), request.Email, loginClientIP(r, u.Config().ServerConf.LoginTrustedProxies))
+	if err != nil {
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		reqErr := apierrors.NewErrPassThroughToClient(fmt.Errorf("too many login attempts, try again later"), http.StatusTooManyRequests)
+		u.HandleAPIError(w, r, reqErr)
+		return
+	}
+
 	// check that passwords match
 	storedUser, err := u.Repo().User().ReadUserByEmail(request.Email)
 	// case on user not existing, send forbidden error if not exist
 	if err != nil {
 		if targetErr := gorm.ErrRecordNotFound; errors.Is(err, targetErr) {
+			// failures are counted for unknown emails as well, so that lockouts do not reveal which emails have users
+			if err := u.Config().LoginLimiter.Fail(r.Context(), request.Email); err != nil {
+				u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+				return
+			}
+
 			u.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
 			return
 		} else {
@@ -56,11 +79,38 @@ func (u *UserLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(storedUser.Password), []byte(request.Password)); err != nil {
+		if err := u.Config().LoginLimiter.Fail(r.Context(), request.Email); err != nil {
+			u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
 		reqErr := apierrors.NewErrPassThroughToClient(fmt.Errorf("incorrect password"), http.StatusUnauthorized)
 		u.HandleAPIError(w, r, reqErr)
 		return
 	}
 
+	if err := u.Config().LoginLimiter.Succeed(r.Context(), request.Email); err != nil {
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// users who enabled a second factor are only authenticated once they submit it to POST /login/2fa
+	twoFactor, err := u.Repo().UserTwoFactor().ReadUserTwoFactor(r.Context(), storedUser.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if twoFactor != nil && twoFactor.Enabled {
+		if err := authn.SaveUserTwoFactorPending(w, r, u.Config(), storedUser); err != nil {
+			u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		u.WriteResult(w, r, types.LoginTwoFactorRequiredResponse{TwoFactorRequired: true})
+		return
+	}
+
 	// save the user as authenticated in the session
 	redirect, err := authn.SaveUserAuthenticated(w, r, u.Config(), storedUser)
 	if err != nil {
@@ -89,3 +139,32 @@ func checkUserRestrictions(
 
 	return nil
 }
+
+// loginClientIP returns the IP address which login attempts are limited by. Each of the trusted proxies in front of the
+// server appends the address it received the request from to the X-Forwarded-For header, so the address appended by
+// the outermost of them is used. The addresses before it are set by the client, which could change them to avoid the
+// limit.
+func loginClientIP(r *http.Request, trustedProxies int) string {
+	if trustedProxies > 0 {
+		if forwardedFor := strings.Join(r.Header.Values("X-Forwarded-For"), ","); forwardedFor != "" {
+			addrs := strings.Split(forwardedFor, ",")
+
+			// a request with fewer addresses than proxies was only forwarded by the innermost of them
+			i := len(addrs) - trustedProxies
+			if i < 0 {
+				i = 0
+			}
+
+			if addr := strings.TrimSpace(addrs[i]); addr != "" {
+				return addr
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
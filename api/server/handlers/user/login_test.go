@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/porter-dev/porter/api/server/handlers/user"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apitest"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/adapter"
 	"github.com/porter-dev/porter/internal/repository/test"
 )
 
@@ -178,3 +180,84 @@ func TestLoginUserFailingReadUserByEmailMethod(t *testing.T) {
 
 	apitest.AssertResponseInternalServerError(t, rr)
 }
+
+func TestLoginUserLockedOut(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	config.LoginLimiter = adapter.NewMemoryLoginLimiter(adapter.LoginLimitOptions{
+		LockoutThreshold: 2,
+		LockoutDuration:  time.Minute,
+	})
+	apitest.CreateTestUser(t, config, true)
+
+	handler := user.NewUserLoginHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	for i := 0; i < 2; i++ {
+		req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/login", &types.LoginUserRequest{
+			Email:    "mrp@porter.run",
+			Password: "hello1",
+		})
+
+		handler.ServeHTTP(rr, req)
+
+		apitest.AssertResponseError(t, rr, http.StatusUnauthorized, &types.ExternalError{
+			Error: "incorrect password",
+		})
+	}
+
+	// the correct password is rejected while the email is locked out
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/login", &types.LoginUserRequest{
+		Email:    "mrp@porter.run",
+		Password: "hello",
+	})
+
+	handler.ServeHTTP(rr, req)
+
+	if retryAfter := rr.Header().Get("Retry-After"); retryAfter != "60" {
+		t.Errorf("expected to be told to retry after 60 seconds, got %q", retryAfter)
+	}
+	apitest.AssertResponseError(t, rr, http.StatusTooManyRequests, &types.ExternalError{
+		Error: "too many login attempts, try again later",
+	})
+}
+
+func TestLoginUserLimitedBySpoofedForwardedFor(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	config.ServerConf.LoginTrustedProxies = 1
+	config.LoginLimiter = adapter.NewMemoryLoginLimiter(adapter.LoginLimitOptions{
+		Window:           time.Minute,
+		MaxAttemptsPerIP: 2,
+	})
+	apitest.CreateTestUser(t, config, true)
+
+	handler := user.NewUserLoginHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+
+	// the client changes the leading address of every attempt, but the proxy appends the address it connected from
+	for i := 0; i < 3; i++ {
+		req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/login", &types.LoginUserRequest{
+			Email:    fmt.Sprintf("user%d@porter.run", i),
+			Password: "hello",
+		})
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("10.0.0.%d, 203.0.113.7", i))
+
+		handler.ServeHTTP(rr, req)
+
+		if i < 2 {
+			if rr.Code == http.StatusTooManyRequests {
+				t.Fatalf("expected attempt %d to be allowed", i+1)
+			}
+			continue
+		}
+
+		apitest.AssertResponseError(t, rr, http.StatusTooManyRequests, &types.ExternalError{
+			Error: "too many login attempts, try again later",
+		})
+	}
+}
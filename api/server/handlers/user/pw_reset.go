@@ -81,6 +81,7 @@ func (c *UserPasswordInitiateResetHandler) ServeHTTP(w http.ResponseWriter, r *h
 		w,
 		r,
 		request,
+		models.PWResetTokenPurpose_PasswordReset,
 	)
 	if err != nil {
 		return
@@ -164,6 +165,11 @@ func (c *UserPasswordFinalizeResetHandler) ServeHTTP(w http.ResponseWriter, r *h
 		return
 	}
 
+	if err := checkPasswordPolicy(c.Config().ServerConf, request.NewPassword); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
 	token, err := VerifyToken(
 		c.Repo().PWResetToken(),
 		c.HandleAPIError,
@@ -272,6 +278,7 @@ func CreatePWResetTokenForEmail(
 	w http.ResponseWriter,
 	r *http.Request,
 	request *types.InitiateResetUserPasswordRequest,
+	purpose models.PWResetTokenPurpose,
 ) (*models.PWResetToken, string, error) {
 	// convert the form to a project model
 	expiry := time.Now().Add(30 * time.Minute)
@@ -293,6 +300,7 @@ func CreatePWResetTokenForEmail(
 		IsValid: true,
 		Expiry:  &expiry,
 		Token:   string(hashedToken),
+		Purpose: purpose,
 	}
 
 	// handle write to the database
@@ -0,0 +1,401 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authn"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/totp"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+const (
+	// twoFactorIssuer is the name authenticator apps show the secret under
+	twoFactorIssuer = "Porter"
+	// twoFactorBackupCodeCount is the number of backup codes a user is given when they enable their second factor
+	twoFactorBackupCodeCount = 10
+	// twoFactorMaxFailedAttempts is the number of incorrect codes after which a user is locked out
+	twoFactorMaxFailedAttempts = 5
+	// twoFactorLockout is how long a user is locked out for
+	twoFactorLockout = 15 * time.Minute
+)
+
+// TwoFactorStatusHandler returns whether the authenticated user has enabled their second factor
+type TwoFactorStatusHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewTwoFactorStatusHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *TwoFactorStatusHandler {
+	return &TwoFactorStatusHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *TwoFactorStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-two-factor-status")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	res := types.TwoFactorStatus{}
+
+	twoFactor, err := c.Repo().UserTwoFactor().ReadUserTwoFactor(ctx, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading user two factor")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if twoFactor != nil && twoFactor.Enabled {
+		res.Enabled = true
+		res.BackupCodesRemaining = len(twoFactor.BackupCodes)
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// TwoFactorEnrollHandler generates a new secret for the authenticated user. The second factor is not enabled until a
+// code generated from the secret is submitted to TwoFactorEnableHandler.
+type TwoFactorEnrollHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewTwoFactorEnrollHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *TwoFactorEnrollHandler {
+	return &TwoFactorEnrollHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *TwoFactorEnrollHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-enroll-two-factor")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	twoFactor, err := c.Repo().UserTwoFactor().ReadUserTwoFactor(ctx, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading user two factor")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// an enabled second factor can only be replaced by disabling it first, which requires a code
+	if twoFactor != nil && twoFactor.Enabled {
+		err := telemetry.Error(ctx, span, nil, "two-factor authentication is already enabled")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error generating secret")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	_, err = c.Repo().UserTwoFactor().CreateOrUpdateUserTwoFactor(ctx, &models.UserTwoFactor{
+		UserID: user.ID,
+		Secret: secret,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error saving user two factor")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, types.EnrollTwoFactorResponse{
+		Secret:          totp.EncodeSecret(secret),
+		ProvisioningURI: totp.ProvisioningURI(twoFactorIssuer, user.Email, secret),
+	})
+}
+
+// TwoFactorEnableHandler enables the second factor the authenticated user enrolled, once they submit a code from
+// their authenticator app, and returns their backup codes
+type TwoFactorEnableHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewTwoFactorEnableHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *TwoFactorEnableHandler {
+	return &TwoFactorEnableHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *TwoFactorEnableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-enable-two-factor")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	request := &types.EnableTwoFactorRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	twoFactor, err := c.Repo().UserTwoFactor().ReadUserTwoFactor(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, nil, "two-factor authentication has not been enrolled")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading user two factor")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if twoFactor.Enabled {
+		err := telemetry.Error(ctx, span, nil, "two-factor authentication is already enabled")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	backupCodes, err := totp.GenerateBackupCodes(twoFactorBackupCodeCount)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error generating backup codes")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// backup codes are only accepted once the second factor is enabled, so the code is always from the authenticator
+	if reqErr := verifyTwoFactorCode(ctx, c.Repo(), twoFactor, request.Code, func(tf *models.UserTwoFactor) {
+		tf.Enabled = true
+		tf.BackupCodes = make(models.UserBackupCodes, 0, len(backupCodes))
+		for _, code := range backupCodes {
+			tf.BackupCodes = append(tf.BackupCodes, totp.HashBackupCode(code))
+		}
+	}); reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	c.WriteResult(w, r, types.EnableTwoFactorResponse{BackupCodes: backupCodes})
+}
+
+// TwoFactorDisableHandler disables the second factor of the authenticated user, once they submit a code from their
+// authenticator app or one of their backup codes
+type TwoFactorDisableHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewTwoFactorDisableHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *TwoFactorDisableHandler {
+	return &TwoFactorDisableHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *TwoFactorDisableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-disable-two-factor")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	request := &types.DisableTwoFactorRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	twoFactor, err := c.Repo().UserTwoFactor().ReadUserTwoFactor(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, nil, "two-factor authentication is not enabled")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading user two factor")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// a second factor which was enrolled but never enabled is removed without checking the code
+	if twoFactor.Enabled {
+		if reqErr := verifyTwoFactorCode(ctx, c.Repo(), twoFactor, request.Code, nil); reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+	}
+
+	if err := c.Repo().UserTwoFactor().DeleteUserTwoFactor(ctx, twoFactor); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting user two factor")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, types.TwoFactorStatus{})
+}
+
+// UserLoginTwoFactorHandler completes a password login of a user who has enabled their second factor, once they
+// submit a code from their authenticator app or one of their backup codes
+type UserLoginTwoFactorHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUserLoginTwoFactorHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UserLoginTwoFactorHandler {
+	return &UserLoginTwoFactorHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (u *UserLoginTwoFactorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-login-two-factor")
+	defer span.End()
+
+	request := &types.LoginTwoFactorRequest{}
+	if ok := u.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	userID, err := authn.UserTwoFactorPending(r, u.Config())
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading session")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if userID == 0 {
+		err := telemetry.Error(ctx, span, nil, "no login is waiting for a second factor, log in with your password again")
+		u.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusUnauthorized))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "user-id", Value: userID})
+
+	storedUser, err := u.Repo().User().ReadUser(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading user")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	twoFactor, err := u.Repo().UserTwoFactor().ReadUserTwoFactor(ctx, userID)
+	if err != nil {
+		// the second factor was disabled from another session since the password was accepted
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading user two factor")
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if !twoFactor.Enabled {
+		u.HandleAPIError(w, r, apierrors.NewErrForbidden(fmt.Errorf("two-factor authentication of user %d is not enabled", userID)))
+		return
+	}
+
+	if reqErr := verifyTwoFactorCode(ctx, u.Repo(), twoFactor, request.Code, nil); reqErr != nil {
+		u.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	redirect, err := authn.SaveUserAuthenticated(w, r, u.Config(), storedUser)
+	if err != nil {
+		u.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if redirect != "" {
+		http.Redirect(w, r, redirect, http.StatusFound)
+		return
+	}
+
+	u.WriteResult(w, r, storedUser.ToUserType())
+}
+
+// verifyTwoFactorCode checks a code from the authenticator app of a user, or one of their backup codes once their
+// second factor is enabled. Codes are rejected while the user is locked out, and the user is locked out after too
+// many incorrect codes. Accepted codes cannot be used again. onAccept is applied to the second factor before it is
+// saved when the code is accepted.
+func verifyTwoFactorCode(
+	ctx context.Context,
+	repo repository.Repository,
+	twoFactor *models.UserTwoFactor,
+	code string,
+	onAccept func(*models.UserTwoFactor),
+) apierrors.RequestError {
+	ctx, span := telemetry.NewSpan(ctx, "verify-two-factor-code")
+	defer span.End()
+
+	now := time.Now()
+
+	if twoFactor.LockedUntil != nil && now.Before(*twoFactor.LockedUntil) {
+		err := telemetry.Error(ctx, span, nil, "too many incorrect codes, try again later")
+		return apierrors.NewErrPassThroughToClient(err, http.StatusTooManyRequests)
+	}
+
+	accepted := false
+	if counter, ok := totp.Validate(twoFactor.Secret, code, now, twoFactor.LastCounter); ok {
+		twoFactor.LastCounter = counter
+		accepted = true
+	} else if twoFactor.Enabled && twoFactor.BackupCodes.Use(totp.HashBackupCode(code)) {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "backup-code", Value: true})
+		accepted = true
+	}
+
+	if accepted {
+		twoFactor.FailedAttempts = 0
+		twoFactor.LockedUntil = nil
+
+		if onAccept != nil {
+			onAccept(twoFactor)
+		}
+	} else {
+		twoFactor.FailedAttempts++
+
+		if twoFactor.FailedAttempts >= twoFactorMaxFailedAttempts {
+			lockedUntil := now.Add(twoFactorLockout)
+			twoFactor.FailedAttempts = 0
+			twoFactor.LockedUntil = &lockedUntil
+		}
+	}
+
+	if _, err := repo.UserTwoFactor().CreateOrUpdateUserTwoFactor(ctx, twoFactor); err != nil {
+		err = telemetry.Error(ctx, span, err, "error saving user two factor")
+		return apierrors.NewErrInternal(err)
+	}
+
+	if !accepted {
+		err := telemetry.Error(ctx, span, nil, "incorrect code")
+		return apierrors.NewErrPassThroughToClient(err, http.StatusUnauthorized)
+	}
+
+	return nil
+}
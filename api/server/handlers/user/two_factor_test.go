@@ -0,0 +1,161 @@
+package user_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/user"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/totp"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// enableTwoFactor enrolls and enables the second factor of a user, and returns its secret and backup codes
+func enableTwoFactor(t *testing.T, config *config.Config, authUser *models.User) ([]byte, []string) {
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/users/current/2fa", nil)
+	req = apitest.WithAuthenticatedUser(t, req, authUser)
+
+	user.NewTwoFactorEnrollHandler(
+		config,
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	).ServeHTTP(rr, req)
+
+	if rr.Result().StatusCode != http.StatusOK {
+		t.Fatalf("expected enrolling to succeed, got status %d", rr.Result().StatusCode)
+	}
+
+	twoFactor, err := config.Repo.UserTwoFactor().ReadUserTwoFactor(context.Background(), authUser.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, twoFactor.Enabled, "second factor should not be enabled until a code is submitted")
+
+	req, rr = apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/users/current/2fa/enable", &types.EnableTwoFactorRequest{
+		Code: totp.Code(twoFactor.Secret, totp.Counter(time.Now())),
+	})
+	req = apitest.WithAuthenticatedUser(t, req, authUser)
+
+	user.NewTwoFactorEnableHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	).ServeHTTP(rr, req)
+
+	res := &types.EnableTwoFactorResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.BackupCodes) != 10 {
+		t.Fatalf("expected 10 backup codes, got %d", len(res.BackupCodes))
+	}
+
+	return twoFactor.Secret, res.BackupCodes
+}
+
+// loginWithPassword logs in with the password of the test user, and returns the recorder of the login
+func loginWithPassword(t *testing.T, config *config.Config) *httptest.ResponseRecorder {
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/login", &types.LoginUserRequest{
+		Email:    "mrp@porter.run",
+		Password: "hello",
+	})
+
+	user.NewUserLoginHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	).ServeHTTP(rr, req)
+
+	return rr
+}
+
+// submitTwoFactor submits a code to complete the login whose session cookie is in loginRR
+func submitTwoFactor(t *testing.T, config *config.Config, loginRR *httptest.ResponseRecorder, code string) *httptest.ResponseRecorder {
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/login/2fa", &types.LoginTwoFactorRequest{Code: code})
+	for _, cookie := range loginRR.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+
+	user.NewUserLoginTwoFactorHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	).ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestLoginUserTwoFactor(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	authUser := apitest.CreateTestUser(t, config, true)
+
+	secret, backupCodes := enableTwoFactor(t, config, authUser)
+
+	loginRR := loginWithPassword(t, config)
+	apitest.AssertResponseExpected(
+		t,
+		loginRR,
+		&types.LoginTwoFactorRequiredResponse{TwoFactorRequired: true},
+		&types.LoginTwoFactorRequiredResponse{},
+	)
+
+	rr := submitTwoFactor(t, config, loginRR, "000000")
+	apitest.AssertResponseError(t, rr, http.StatusUnauthorized, &types.ExternalError{Error: "incorrect code"})
+
+	// the code of the current period was used to enable the second factor, so the next one is submitted
+	rr = submitTwoFactor(t, config, loginRR, totp.Code(secret, totp.Counter(time.Now())+1))
+	apitest.AssertResponseExpected(t, rr, &types.LoginUserResponse{
+		ID:            1,
+		FirstName:     "Mister",
+		LastName:      "Porter",
+		CompanyName:   "Porter Technologies, Inc.",
+		Email:         "mrp@porter.run",
+		EmailVerified: true,
+	}, &types.LoginUserResponse{})
+
+	// backup codes can only be used once
+	loginRR = loginWithPassword(t, config)
+	rr = submitTwoFactor(t, config, loginRR, backupCodes[0])
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "backup code should be accepted")
+
+	loginRR = loginWithPassword(t, config)
+	rr = submitTwoFactor(t, config, loginRR, backupCodes[0])
+	apitest.AssertResponseError(t, rr, http.StatusUnauthorized, &types.ExternalError{Error: "incorrect code"})
+}
+
+func TestLoginUserTwoFactorWithoutPassword(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	authUser := apitest.CreateTestUser(t, config, true)
+
+	_, backupCodes := enableTwoFactor(t, config, authUser)
+
+	rr := submitTwoFactor(t, config, httptest.NewRecorder(), backupCodes[0])
+	apitest.AssertResponseError(t, rr, http.StatusUnauthorized, &types.ExternalError{
+		Error: "no login is waiting for a second factor, log in with your password again",
+	})
+}
+
+func TestLoginUserTwoFactorLockout(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	authUser := apitest.CreateTestUser(t, config, true)
+
+	_, backupCodes := enableTwoFactor(t, config, authUser)
+
+	loginRR := loginWithPassword(t, config)
+	for i := 0; i < 5; i++ {
+		rr := submitTwoFactor(t, config, loginRR, "000000")
+		assert.Equal(t, http.StatusUnauthorized, rr.Result().StatusCode, "incorrect code should be rejected")
+	}
+
+	rr := submitTwoFactor(t, config, loginRR, backupCodes[0])
+	apitest.AssertResponseError(t, rr, http.StatusTooManyRequests, &types.ExternalError{
+		Error: "too many incorrect codes, try again later",
+	})
+}
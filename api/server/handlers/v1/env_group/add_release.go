@@ -56,7 +56,7 @@ func (c *AddReleaseToEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.R
 	}
 
 	// read the attached configmap
-	cm, _, err := agent.GetLatestVersionedConfigMap(name, namespace)
+	cm, _, err := agent.GetLatestVersionedConfigMap(r.Context(), name, namespace)
 
 	if err != nil && errors.Is(err, kubernetes.IsNotFoundError) {
 		c.HandleAPIError(w, r, apierrors.NewErrNotFound(fmt.Errorf("env group not found")))
@@ -56,7 +56,7 @@ func (c *CreateEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	envGroup, err := envgroup.GetEnvGroup(agent, request.Name, namespace, 0)
+	envGroup, err := envgroup.GetEnvGroup(r.Context(), agent, request.Name, namespace, 0)
 
 	// if the environment group exists and has MetaVersion=1, throw an error
 	if envGroup != nil && envGroup.MetaVersion == 1 {
@@ -76,7 +76,7 @@ func (c *CreateEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	configMap, err := envgroup.CreateEnvGroup(agent, types.ConfigMapInput{
+	configMap, err := envgroup.CreateEnvGroup(r.Context(), agent, types.ConfigMapInput{
 		Name:            request.Name,
 		Namespace:       namespace,
 		Variables:       request.Variables,
@@ -57,7 +57,7 @@ func (c *GetEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	envGroup, err := envgroup.GetEnvGroup(agent, name, namespace, version)
+	envGroup, err := envgroup.GetEnvGroup(r.Context(), agent, name, namespace, version)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			c.HandleAPIError(w, r, apierrors.NewErrNotFound(fmt.Errorf("env group not found")))
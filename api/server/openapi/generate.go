@@ -0,0 +1,312 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// Route is an endpoint registered on the API router
+type Route struct {
+	// Path is the full pattern which the endpoint is registered under, such as /api/projects/{project_id}
+	Path string
+
+	// Name identifies the handler of the endpoint, and is used as the operation id
+	Name string
+
+	Metadata *types.APIRequestMetadata
+}
+
+// Options configures the generated document
+type Options struct {
+	// Version is the version of the server
+	Version string
+
+	// CookieName is the name of the session cookie which authenticates requests from the dashboard
+	CookieName string
+}
+
+const (
+	securityBearer = "bearerAuth"
+	securityCookie = "cookieAuth"
+)
+
+// intPathParams are the path parameters which are numeric ids
+var intPathParams = map[string]bool{
+	string(types.URLParamProjectID):         true,
+	string(types.URLParamClusterID):         true,
+	string(types.URLParamRegistryID):        true,
+	string(types.URLParamHelmRepoID):        true,
+	string(types.URLParamGitInstallationID): true,
+	string(types.URLParamInfraID):           true,
+	string(types.URLParamOperationID):       true,
+	string(types.URLParamInviteID):          true,
+	string(types.URLParamPorterAppID):       true,
+	string(types.URLParamIntegrationID):     true,
+	string(types.URLParamReleaseVersion):    true,
+}
+
+// pathParamPattern matches the parameters of a chi pattern, including the regexp which a parameter may be limited to
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// wildcardParam is the name under which the trailing wildcard of a chi pattern is documented
+const wildcardParam = "wildcard"
+
+// Generate returns the OpenAPI document of the routes. Routes without a schema annotation are listed with their path
+// parameters and marked as undocumented.
+func Generate(opts Options, routes []Route) *Document {
+	g := &generator{
+		schemas:      newSchemaRegistry(),
+		operationIDs: make(map[string]bool),
+	}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Porter API",
+			Description: "The API used by the Porter dashboard and CLI.",
+			Version:     opts.Version,
+		},
+		Paths: make(map[string]*PathItem),
+	}
+
+	errorSchema := g.schemas.schemaOf(reflect.TypeOf(types.ExternalError{}))
+
+	for _, route := range routes {
+		path, params := openAPIPath(route.Path)
+
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+
+		op := g.operation(route, params)
+		op.Responses["default"] = &Response{
+			Description: "Error",
+			Content:     map[string]*MediaType{"application/json": {Schema: errorSchema}},
+		}
+
+		(*item)[strings.ToLower(string(route.Metadata.Method))] = op
+	}
+
+	doc.Components = Components{
+		Schemas: g.schemas.schemas,
+		SecuritySchemes: map[string]*SecurityScheme{
+			securityBearer: {
+				Type:        "http",
+				Scheme:      "bearer",
+				Description: "An API token of a project, or the token of a logged in user",
+			},
+			securityCookie: {
+				Type:        "apiKey",
+				In:          "cookie",
+				Name:        opts.CookieName,
+				Description: "The session cookie of the dashboard",
+			},
+		},
+	}
+
+	return doc
+}
+
+type generator struct {
+	schemas      *schemaRegistry
+	operationIDs map[string]bool
+}
+
+func (g *generator) operation(route Route, pathParams []string) *Operation {
+	metadata := route.Metadata
+	schema := metadata.Schema
+
+	op := &Operation{
+		OperationID: g.operationID(route),
+		Tags:        []string{defaultTag(route.Path)},
+		Responses:   make(map[string]*Response),
+	}
+
+	for _, name := range pathParams {
+		paramSchema := &Schema{Type: "string"}
+		if intPathParams[name] {
+			paramSchema = &Schema{Type: "integer", Minimum: float(0)}
+		}
+		op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: paramSchema})
+	}
+
+	for _, scope := range metadata.Scopes {
+		if scope == types.UserScope {
+			op.Security = []map[string][]string{{securityBearer: {}}, {securityCookie: {}}}
+			break
+		}
+	}
+
+	if metadata.IsWebsocket {
+		op.Description = "Upgrades the connection to a websocket."
+	}
+
+	if schema == nil {
+		op.Undocumented = true
+		op.Responses["200"] = &Response{Description: "OK"}
+		return op
+	}
+
+	op.Summary = schema.Summary
+	op.Description = strings.TrimSpace(schema.Description + "\n\n" + op.Description)
+	if schema.Tag != "" {
+		op.Tags = []string{schema.Tag}
+	}
+
+	if schema.Request != nil {
+		requestType := reflect.TypeOf(schema.Request)
+		if metadata.Method == types.HTTPVerbGet {
+			op.Parameters = append(op.Parameters, g.queryParameters(requestType)...)
+		} else {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"application/json": {Schema: g.schemas.schemaOf(requestType)}},
+			}
+		}
+	}
+
+	if schema.Response != nil {
+		op.Responses["200"] = &Response{
+			Description: "OK",
+			Content:     map[string]*MediaType{"application/json": {Schema: g.schemas.schemaOf(reflect.TypeOf(schema.Response))}},
+		}
+	} else {
+		op.Responses["200"] = &Response{Description: "OK"}
+	}
+
+	return op
+}
+
+// queryParameters returns the query parameters of a GET request, which are decoded with gorilla/schema
+func (g *generator) queryParameters(t reflect.Type) []*Parameter {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("schema"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			params = append(params, g.queryParameters(field.Type)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		rules := parseFormTag(field.Tag.Get("form"))
+		paramSchema := g.schemas.schemaOf(field.Type)
+		if paramSchema.Ref == "" {
+			applyFormRules(paramSchema, rules)
+		}
+
+		params = append(params, &Parameter{
+			Name:        name,
+			In:          "query",
+			Description: field.Tag.Get("doc"),
+			Required:    rules.required,
+			Schema:      paramSchema,
+		})
+	}
+
+	return params
+}
+
+// operationID returns the name of the route's handler, suffixed with a number if another route has the same handler
+func (g *generator) operationID(route Route) string {
+	id := route.Name
+	if id == "" {
+		id = strings.ToLower(string(route.Metadata.Method)) + sanitizeName(route.Path)
+	}
+
+	unique := id
+	for i := 2; g.operationIDs[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", id, i)
+	}
+	g.operationIDs[unique] = true
+
+	return unique
+}
+
+// openAPIPath converts a chi pattern to an OpenAPI path, and returns the names of its parameters
+func openAPIPath(pattern string) (string, []string) {
+	var params []string
+
+	path := pathParamPattern.ReplaceAllStringFunc(pattern, func(param string) string {
+		name := pathParamPattern.FindStringSubmatch(param)[1]
+		params = append(params, name)
+
+		return "{" + name + "}"
+	})
+
+	if strings.HasSuffix(path, "/*") {
+		path = strings.TrimSuffix(path, "*") + "{" + wildcardParam + "}"
+		params = append(params, wildcardParam)
+	}
+
+	return path, params
+}
+
+// defaultTag returns the resource which a path acts on: the segment after its last parameter, ignoring the parameters
+// which the path ends with, or the first segment if the path has no other parameters
+func defaultTag(pattern string) string {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if segment != "api" && segment != "v1" && segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	isParam := func(segment string) bool {
+		return strings.HasPrefix(segment, "{") || segment == "*"
+	}
+
+	for len(segments) > 0 && isParam(segments[len(segments)-1]) {
+		segments = segments[:len(segments)-1]
+	}
+	if len(segments) == 0 {
+		return "default"
+	}
+
+	for i := len(segments) - 1; i > 0; i-- {
+		if isParam(segments[i-1]) {
+			return segments[i]
+		}
+	}
+
+	return segments[0]
+}
+
+// HandlerName returns the name of the type of a handler without its Handler suffix, or an empty string for handler
+// functions
+func HandlerName(handler http.Handler) string {
+	t := reflect.TypeOf(handler)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Func {
+		return ""
+	}
+
+	return strings.TrimSuffix(t.Name(), "Handler")
+}
@@ -0,0 +1,199 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type testCreateRequest struct {
+	Name    string            `json:"name" form:"required,dns1123" doc:"The name of the app"`
+	Kind    string            `json:"kind,omitempty" form:"oneof=web worker job"`
+	Size    int               `json:"size" form:"min=1,max=10"`
+	Tags    []string          `json:"tags" form:"max=3,dive,required"`
+	Email   string            `json:"email" form:"email"`
+	Owner   *testOwner        `json:"owner" doc:"The owner of the app"`
+	Labels  map[string]string `json:"labels"`
+	Ignored string            `json:"-"`
+
+	testEmbedded
+}
+
+type testEmbedded struct {
+	Region string `json:"region"`
+}
+
+type testOwner struct {
+	ID      uint       `json:"id"`
+	Manager *testOwner `json:"manager"`
+}
+
+type testListRequest struct {
+	Page   int    `schema:"page" form:"min=1" doc:"The page of results"`
+	Filter string `schema:"filter" form:"required"`
+}
+
+func TestFormRulesAndDocTags(t *testing.T) {
+	r := newSchemaRegistry()
+
+	ref := r.schemaOf(reflect.TypeOf(testCreateRequest{}))
+	schema := r.schemas[ComponentName(ref.Ref)]
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, "github.com/porter-dev/porter/api/server/openapi.testCreateRequest", schema.GoType)
+	assert.Equal(t, []string{"name"}, schema.Required)
+
+	name := schema.Properties["name"]
+	assert.Equal(t, dns1123LabelPattern, name.Pattern)
+	assert.Equal(t, uint64(63), *name.MaxLength)
+	assert.Equal(t, "The name of the app", name.Description)
+
+	assert.Equal(t, []string{"web", "worker", "job"}, schema.Properties["kind"].Enum)
+
+	size := schema.Properties["size"]
+	assert.Equal(t, 1.0, *size.Minimum)
+	assert.Equal(t, 10.0, *size.Maximum)
+
+	tags := schema.Properties["tags"]
+	assert.Equal(t, uint64(3), *tags.MaxItems)
+	assert.Nil(t, tags.MinItems, "rules after dive apply to the elements")
+
+	assert.Equal(t, "email", schema.Properties["email"].Format)
+
+	owner := schema.Properties["owner"]
+	assert.Equal(t, "The owner of the app", owner.Description)
+	assert.Len(t, owner.AllOf, 1, "a documented reference is wrapped to keep its description")
+
+	assert.Equal(t, "object", schema.Properties["labels"].Type)
+	assert.Equal(t, "string", schema.Properties["labels"].AdditionalProperties.Type)
+
+	assert.Contains(t, schema.Properties, "region", "fields of embedded structs are promoted")
+	assert.NotContains(t, schema.Properties, "Ignored")
+}
+
+func TestRecursiveTypes(t *testing.T) {
+	r := newSchemaRegistry()
+
+	ref := r.schemaOf(reflect.TypeOf(testOwner{}))
+	schema := r.schemas[ComponentName(ref.Ref)]
+
+	assert.Equal(t, ref.Ref, schema.Properties["manager"].Ref)
+}
+
+func TestComponentNameCollisions(t *testing.T) {
+	r := newSchemaRegistry()
+
+	apiRef := r.schemaOf(reflect.TypeOf(types.Project{}))
+	modelRef := r.schemaOf(reflect.TypeOf(models.Project{}))
+
+	assert.Equal(t, "Project", ComponentName(apiRef.Ref))
+	assert.Equal(t, "models.Project", ComponentName(modelRef.Ref))
+	assert.Equal(t, apiRef.Ref, r.schemaOf(reflect.TypeOf(&types.Project{})).Ref, "a type is registered once")
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	path, params := openAPIPath("/api/projects/{project_id}/clusters/{cluster_id}/namespaces/{namespace}/pods/{name:[a-z]+}/*")
+
+	assert.Equal(t, "/api/projects/{project_id}/clusters/{cluster_id}/namespaces/{namespace}/pods/{name}/{wildcard}", path)
+	assert.Equal(t, []string{"project_id", "cluster_id", "namespace", "name", "wildcard"}, params)
+}
+
+func TestDefaultTag(t *testing.T) {
+	tests := map[string]string{
+		"/api/projects":                                    "projects",
+		"/api/projects/{project_id}":                       "projects",
+		"/api/projects/{project_id}/clusters":              "clusters",
+		"/api/projects/{project_id}/clusters/{cluster_id}": "clusters",
+		"/api/v1/projects/{project_id}/stacks/{stack}/*":   "stacks",
+		"/api/{wildcard}":                                  "default",
+	}
+
+	for path, tag := range tests {
+		assert.Equal(t, tag, defaultTag(path), path)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	userScoped := []types.PermissionScope{types.UserScope, types.ProjectScope}
+
+	doc := Generate(Options{Version: "v1.0.0", CookieName: "porter"}, []Route{
+		{
+			Path: "/api/projects/{project_id}/apps",
+			Name: "CreateApp",
+			Metadata: &types.APIRequestMetadata{
+				Method: types.HTTPVerbPost,
+				Scopes: userScoped,
+				Schema: &types.APISchema{
+					Summary:  "Create an app",
+					Request:  testCreateRequest{},
+					Response: testOwner{},
+				},
+			},
+		},
+		{
+			Path: "/api/projects/{project_id}/apps",
+			Name: "ListApps",
+			Metadata: &types.APIRequestMetadata{
+				Method: types.HTTPVerbGet,
+				Scopes: userScoped,
+				Schema: &types.APISchema{
+					Summary:  "List apps",
+					Tag:      "applications",
+					Request:  testListRequest{},
+					Response: []testOwner{},
+				},
+			},
+		},
+		{
+			Path:     "/api/livez",
+			Name:     "Livez",
+			Metadata: &types.APIRequestMetadata{Method: types.HTTPVerbGet},
+		},
+	})
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Equal(t, "v1.0.0", doc.Info.Version)
+	assert.Equal(t, "porter", doc.Components.SecuritySchemes[securityCookie].Name)
+
+	apps := *doc.Paths["/api/projects/{project_id}/apps"]
+
+	create := apps["post"]
+	assert.Equal(t, "CreateApp", create.OperationID)
+	assert.Equal(t, []string{"apps"}, create.Tags)
+	assert.Len(t, create.Security, 2)
+	assert.Equal(t, "integer", create.Parameters[0].Schema.Type)
+	assert.Equal(t, ComponentRef("testCreateRequest"), create.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, ComponentRef("testOwner"), create.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, ComponentRef("ExternalError"), create.Responses["default"].Content["application/json"].Schema.Ref)
+
+	list := apps["get"]
+	assert.Equal(t, []string{"applications"}, list.Tags)
+	assert.Nil(t, list.RequestBody, "the request of a GET is read from the query string")
+	if assert.Len(t, list.Parameters, 3) {
+		page, filter := list.Parameters[1], list.Parameters[2]
+		assert.Equal(t, "page", page.Name)
+		assert.Equal(t, "query", page.In)
+		assert.Equal(t, "The page of results", page.Description)
+		assert.Equal(t, 1.0, *page.Schema.Minimum)
+		assert.True(t, filter.Required)
+	}
+	assert.Equal(t, "array", list.Responses["200"].Content["application/json"].Schema.Type)
+
+	livez := (*doc.Paths["/api/livez"])["get"]
+	assert.True(t, livez.Undocumented)
+	assert.Empty(t, livez.Security)
+}
+
+type testCreateHandler struct{}
+
+func (h *testCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func TestHandlerName(t *testing.T) {
+	assert.Equal(t, "testCreate", HandlerName(&testCreateHandler{}))
+	assert.Equal(t, "", HandlerName(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+}
@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+// SpecHandler returns a handler which writes the document as JSON. The document is encoded once, since the routes
+// it is generated from do not change while the server runs.
+func SpecHandler(doc *Document) (http.Handler, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding openapi document: %w", err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		_, _ = w.Write(spec)
+	}), nil
+}
+
+// DocsHandler returns a handler which serves a page that renders the document served at /api/swagger.json
+func DocsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("X-Frame-Options", "DENY")
+		_, _ = w.Write(docsPage)
+	})
+}
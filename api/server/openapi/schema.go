@@ -0,0 +1,317 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	durationType   = reflect.TypeOf(time.Duration(0))
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	marshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaRegistry builds schemas of Go types, and collects the named struct, slice and map types as components
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schemaOf returns the schema of a type, which is a reference if the type is a component
+func (r *schemaRegistry) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "A duration in nanoseconds"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		// the JSON encoding of the type is custom, so nothing can be derived from its fields
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return r.component(t, func() *Schema {
+			return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+		})
+	case reflect.Map:
+		return r.component(t, func() *Schema {
+			return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem())}
+		})
+	case reflect.Struct:
+		return r.component(t, func() *Schema {
+			return r.structSchema(t)
+		})
+	default:
+		// interfaces can hold any value, and channels and functions are never encoded
+		return &Schema{}
+	}
+}
+
+// component returns a reference to the component schema of a named type, building it the first time the type is
+// seen. Unnamed types are built inline.
+func (r *schemaRegistry) component(t reflect.Type, build func() *Schema) *Schema {
+	if t.Name() == "" {
+		return build()
+	}
+
+	if name, ok := r.names[t]; ok {
+		return &Schema{Ref: ComponentRef(name)}
+	}
+
+	name := r.componentName(t)
+	r.names[t] = name
+
+	// the placeholder is registered before building so that recursive types reference it instead of looping
+	schema := &Schema{}
+	r.schemas[name] = schema
+
+	*schema = *build()
+	schema.GoType = t.PkgPath() + "." + t.Name()
+
+	return &Schema{Ref: ComponentRef(name)}
+}
+
+// componentName returns the name of the component of a type, which is the type name unless another package already
+// has a type of that name
+func (r *schemaRegistry) componentName(t reflect.Type) string {
+	name := sanitizeName(t.Name())
+	if _, taken := r.schemas[name]; !taken {
+		return name
+	}
+
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+
+	qualified := sanitizeName(pkg) + "." + name
+	for i := 2; ; i++ {
+		if _, taken := r.schemas[qualified]; !taken {
+			return qualified
+		}
+		qualified = sanitizeName(pkg) + "." + name + strconv.Itoa(i)
+	}
+}
+
+// sanitizeName replaces the characters which component names may not contain, such as the brackets of generic types
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for _, field := range jsonFields(t) {
+		fieldSchema := r.schemaOf(field.Type)
+		rules := parseFormTag(field.Tag.Get("form"))
+
+		if fieldSchema.Ref == "" {
+			applyFormRules(fieldSchema, rules)
+		}
+		if doc := field.Tag.Get("doc"); doc != "" {
+			if fieldSchema.Ref != "" {
+				// keywords next to a reference are ignored in OpenAPI 3.0, so the reference is wrapped to keep the
+				// description
+				fieldSchema = &Schema{AllOf: []*Schema{fieldSchema}}
+			}
+			fieldSchema.Description = doc
+		}
+
+		if rules.required {
+			schema.Required = append(schema.Required, field.name)
+		}
+		schema.Properties[field.name] = fieldSchema
+	}
+
+	return schema
+}
+
+// structField is a field of a struct as encoding/json sees it
+type structField struct {
+	reflect.StructField
+	name string
+}
+
+// jsonFields returns the fields of a struct which encoding/json encodes, with the fields of embedded structs
+// promoted into the parent
+func jsonFields(t reflect.Type) []structField {
+	var fields []structField
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, jsonFields(embedded)...)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, structField{StructField: field, name: name})
+	}
+
+	return fields
+}
+
+// formRules are the validation rules of a `form` struct tag which can be expressed in a schema
+type formRules struct {
+	required bool
+	min      *float64
+	max      *float64
+	oneOf    []string
+	format   string
+	pattern  string
+}
+
+// dns1123LabelPattern matches the names accepted by the dns1123 rule. The rule also limits names to 63 characters.
+const dns1123LabelPattern = "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+
+func parseFormTag(tag string) formRules {
+	var rules formRules
+	if tag == "" {
+		return rules
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+
+		switch key {
+		case "dive":
+			// the rules after dive apply to the elements of a slice or map, which are not described
+			return rules
+		case "required":
+			rules.required = true
+		case "min", "gte":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				rules.min = &v
+			}
+		case "max", "lte":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				rules.max = &v
+			}
+		case "oneof":
+			rules.oneOf = strings.Fields(value)
+		case "email":
+			rules.format = "email"
+		case "url":
+			rules.format = "uri"
+		case "uuid":
+			rules.format = "uuid"
+		case "dns1123":
+			rules.pattern = dns1123LabelPattern
+		}
+	}
+
+	return rules
+}
+
+// applyFormRules sets the keywords of a schema which correspond to validation rules. As with the validator, min and
+// max bound the length of strings and arrays and the value of numbers.
+func applyFormRules(schema *Schema, rules formRules) {
+	switch schema.Type {
+	case "string":
+		schema.MinLength = length(rules.min)
+		schema.MaxLength = length(rules.max)
+		if len(rules.oneOf) > 0 {
+			schema.Enum = rules.oneOf
+		}
+		if rules.format != "" {
+			schema.Format = rules.format
+		}
+		if rules.pattern != "" {
+			schema.Pattern = rules.pattern
+			if schema.MaxLength == nil {
+				schema.MaxLength = length(float(63))
+			}
+		}
+	case "array":
+		schema.MinItems = length(rules.min)
+		schema.MaxItems = length(rules.max)
+	case "integer", "number":
+		if rules.min != nil {
+			schema.Minimum = rules.min
+		}
+		if rules.max != nil {
+			schema.Maximum = rules.max
+		}
+	}
+}
+
+func length(v *float64) *uint64 {
+	if v == nil || *v < 0 {
+		return nil
+	}
+	l := uint64(*v)
+
+	return &l
+}
+
+func float(v float64) *float64 {
+	return &v
+}
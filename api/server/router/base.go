@@ -4,10 +4,12 @@ import (
 	"fmt"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/porter-dev/porter/api/server/handlers/cluster"
 	"github.com/porter-dev/porter/api/server/handlers/credentials"
 	"github.com/porter-dev/porter/api/server/handlers/gitinstallation"
 	"github.com/porter-dev/porter/api/server/handlers/healthcheck"
 	"github.com/porter-dev/porter/api/server/handlers/metadata"
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
 	"github.com/porter-dev/porter/api/server/handlers/release"
 	"github.com/porter-dev/porter/api/server/handlers/user"
 	"github.com/porter-dev/porter/api/server/handlers/webhook"
@@ -81,6 +83,80 @@ func GetBaseRoutes(
 		Router:   r,
 	})
 
+	// GET /api/streamz -> healthcheck.NewStreamzHandler
+	getStreamzEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/streamz",
+			},
+			Quiet: true,
+		},
+	)
+
+	getStreamzHandler := healthcheck.NewStreamzHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getStreamzEndpoint,
+		Handler:  getStreamzHandler,
+		Router:   r,
+	})
+
+	// GET /api/componentz -> healthcheck.NewComponentzHandler
+	getComponentzEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/componentz",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+			},
+		},
+	)
+
+	getComponentzHandler := healthcheck.NewComponentzHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getComponentzEndpoint,
+		Handler:  getComponentzHandler,
+		Router:   r,
+	})
+
+	// GET /api/componentz/metrics -> healthcheck.NewComponentzMetricsHandler
+	getComponentzMetricsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/componentz/metrics",
+			},
+			Quiet: true,
+		},
+	)
+
+	getComponentzMetricsHandler := healthcheck.NewComponentzMetricsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getComponentzMetricsEndpoint,
+		Handler:  getComponentzMetricsHandler,
+		Router:   r,
+	})
+
 	// GET /api/metadata -> metadata.NewMetadataGetHandler
 	getMetadataEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -90,6 +166,10 @@ func GetBaseRoutes(
 				Parent:       basePath,
 				RelativePath: "/metadata",
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get the features enabled on this Porter instance",
+				Response: config.Metadata,
+			},
 		},
 	)
 
@@ -182,6 +262,11 @@ func GetBaseRoutes(
 				Parent:       basePath,
 				RelativePath: "/users",
 			},
+			Schema: &types.APISchema{
+				Summary:  "Sign up a user",
+				Request:  types.CreateUserRequest{},
+				Response: types.CreateUserResponse{},
+			},
 		},
 	)
 
@@ -206,6 +291,11 @@ func GetBaseRoutes(
 				Parent:       basePath,
 				RelativePath: "/login",
 			},
+			Schema: &types.APISchema{
+				Summary:  "Log in with an email and password",
+				Request:  types.LoginUserRequest{},
+				Response: types.GetAuthenticatedUserResponse{},
+			},
 		},
 	)
 
@@ -221,6 +311,35 @@ func GetBaseRoutes(
 		Router:   r,
 	})
 
+	// POST /api/login/2fa -> user.NewUserLoginTwoFactorHandler
+	loginTwoFactorEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/login/2fa",
+			},
+			Schema: &types.APISchema{
+				Summary:  "Complete a login which requires a second factor",
+				Request:  types.LoginTwoFactorRequest{},
+				Response: types.GetAuthenticatedUserResponse{},
+			},
+		},
+	)
+
+	loginTwoFactorHandler := user.NewUserLoginTwoFactorHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: loginTwoFactorEndpoint,
+		Handler:  loginTwoFactorHandler,
+		Router:   r,
+	})
+
 	// POST /api/cli/login/exchange -> user.NewCLILoginExchangeHandler
 	cliLoginExchangeEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -327,6 +446,10 @@ func GetBaseRoutes(
 				RelativePath: "/webhooks/deploy/{token}",
 			},
 			Scopes: []types.PermissionScope{},
+			Schema: &types.APISchema{
+				Summary: "Deploy a release with its webhook token",
+				Request: types.WebhookRequest{},
+			},
 		},
 	)
 
@@ -342,6 +465,64 @@ func GetBaseRoutes(
 		Router:   r,
 	})
 
+	// POST /api/webhooks/apps/deploy/{token} -> porter_app.NewDeployWebhookHandler
+	appDeployWebhookEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/webhooks/apps/deploy/{%s}", types.URLParamToken),
+			},
+			Scopes: []types.PermissionScope{},
+			Schema: &types.APISchema{
+				Summary:     "Deploy an app with its deploy webhook token",
+				Description: "Deploys the app the token was generated for with a new image, keeping the rest of the values of its latest release. Tokens which do not deploy an app return a 404.",
+				Request:     types.DeployWebhookRequest{},
+				Response:    types.DeployWebhookResponse{},
+			},
+		},
+	)
+
+	appDeployWebhookHandler := porter_app.NewDeployWebhookHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: appDeployWebhookEndpoint,
+		Handler:  appDeployWebhookHandler,
+		Router:   r,
+	})
+
+	// GET /api/cluster-tunnel/connect -> cluster.NewTunnelConnectHandler
+	tunnelConnectEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/cluster-tunnel/connect",
+			},
+			Scopes:  []types.PermissionScope{},
+			Quiet:   true,
+			Timeout: types.TimeoutClassNone,
+			Schema: &types.APISchema{
+				Summary:     "Connect the tunnel agent of a cluster",
+				Description: "Upgrades the connection to a websocket which the server reaches the Kubernetes API of the cluster through. The agent authenticates with the project and cluster ids and the tunnel token of the cluster in its headers.",
+			},
+		},
+	)
+
+	tunnelConnectHandler := cluster.NewTunnelConnectHandler(config)
+
+	routes = append(routes, &router.Route{
+		Endpoint: tunnelConnectEndpoint,
+		Handler:  tunnelConnectHandler,
+		Router:   r,
+	})
+
 	//  GET /api/integrations/github-app/install
 	githubAppInstallEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
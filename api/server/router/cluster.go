@@ -87,6 +87,40 @@ func getClusterRoutes(
 		Router:   r,
 	})
 
+	// POST /api/projects/{project_id}/clusters/tunnel -> cluster.NewCreateTunnelClusterHandler
+	createTunnelClusterEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/clusters/tunnel",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Create a cluster which is reached through a tunnel agent",
+				Description: "The token of the agent is only returned once. The agent connects to the server from inside the cluster, for clusters whose API is not reachable from the server.",
+				Request:     types.CreateTunnelClusterRequest{},
+				Response:    types.CreateTunnelClusterResponse{},
+			},
+		},
+	)
+
+	createTunnelClusterHandler := cluster.NewCreateTunnelClusterHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createTunnelClusterEndpoint,
+		Handler:  createTunnelClusterHandler,
+		Router:   r,
+	})
+
 	// POST /api/projects/{project_id}/clusters/candidates -> project.NewCreateClusterCandidateHandler
 	createCandidateEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -102,6 +136,11 @@ func getClusterRoutes(
 			},
 			CheckUsage:  true,
 			UsageMetric: types.Clusters,
+			Schema: &types.APISchema{
+				Summary:  "Create cluster candidates from a kubeconfig",
+				Request:  types.CreateClusterCandidateRequest{},
+				Response: types.CreateClusterCandidateResponse{},
+			},
 		},
 	)
 
@@ -130,6 +169,10 @@ func getClusterRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the cluster candidates of a project",
+				Response: types.ListClusterCandidateResponse{},
+			},
 		},
 	)
 
@@ -162,6 +205,11 @@ func getClusterRoutes(
 			},
 			CheckUsage:  true,
 			UsageMetric: types.Clusters,
+			Schema: &types.APISchema{
+				Summary:  "Resolve a cluster candidate into a cluster",
+				Request:  types.ClusterResolverAll{},
+				Response: types.Cluster{},
+			},
 		},
 	)
 
@@ -220,6 +268,9 @@ func getClusterRoutes(
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Delete a cluster",
+			},
 		},
 	)
 
@@ -248,6 +299,10 @@ func getClusterRoutes(
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get a cluster",
+				Response: types.ClusterGetResponse{},
+			},
 		},
 	)
 
@@ -262,6 +317,34 @@ func getClusterRoutes(
 		Router:   r,
 	})
 
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/capabilities/detect -> cluster.NewDetectCapabilitiesHandler
+	detectCapabilitiesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/capabilities/detect",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	detectCapabilitiesHandler := cluster.NewDetectCapabilitiesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: detectCapabilitiesEndpoint,
+		Handler:  detectCapabilitiesHandler,
+		Router:   r,
+	})
+
 	// POST /api/projects/{project_id}/clusters/{cluster_id}/rename -> cluster.NewRenameClusterHandler
 	renameClusterEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -291,6 +374,38 @@ func getClusterRoutes(
 		Router:   r,
 	})
 
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/tunnel/token -> cluster.NewRotateTunnelTokenHandler
+	rotateTunnelTokenEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/tunnel/token",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Rotate the token of the tunnel agent of a cluster",
+				Response: types.RotateTunnelTokenResponse{},
+			},
+		},
+	)
+
+	rotateTunnelTokenHandler := cluster.NewRotateTunnelTokenHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: rotateTunnelTokenEndpoint,
+		Handler:  rotateTunnelTokenHandler,
+		Router:   r,
+	})
+
 	// GET /api/projects/{project_id}/clusters/{cluster_id}/databases -> database.NewDatabaseListHandler
 	listDatabaseEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -394,6 +509,10 @@ func getClusterRoutes(
 					types.ClusterScope,
 					types.PreviewEnvironmentScope,
 				},
+				Schema: &types.APISchema{
+					Summary:  "List the preview environments of a cluster",
+					Response: types.ListEnvironmentsResponse{},
+				},
 			},
 		)
 
@@ -512,6 +631,11 @@ func getClusterRoutes(
 					types.ClusterScope,
 					types.PreviewEnvironmentScope,
 				},
+				Schema: &types.APISchema{
+					Summary:  "Create a preview environment deployment",
+					Request:  types.CreateDeploymentRequest{},
+					Response: types.Deployment{},
+				},
 			},
 		)
 
@@ -572,6 +696,11 @@ func getClusterRoutes(
 					types.ClusterScope,
 					types.PreviewEnvironmentScope,
 				},
+				Schema: &types.APISchema{
+					Summary:  "Update a preview environment deployment",
+					Request:  types.UpdateDeploymentByClusterRequest{},
+					Response: types.Deployment{},
+				},
 			},
 		)
 
@@ -602,6 +731,11 @@ func getClusterRoutes(
 					types.ClusterScope,
 					types.PreviewEnvironmentScope,
 				},
+				Schema: &types.APISchema{
+					Summary:  "Update the status of a preview environment deployment",
+					Request:  types.UpdateDeploymentStatusByClusterRequest{},
+					Response: types.Deployment{},
+				},
 			},
 		)
 
@@ -632,6 +766,11 @@ func getClusterRoutes(
 					types.ClusterScope,
 					types.PreviewEnvironmentScope,
 				},
+				Schema: &types.APISchema{
+					Summary:  "Get the deployment of a preview environment",
+					Request:  types.GetDeploymentRequest{},
+					Response: types.Deployment{},
+				},
 			},
 		)
 
@@ -663,6 +802,11 @@ func getClusterRoutes(
 					types.ClusterScope,
 					types.PreviewEnvironmentScope,
 				},
+				Schema: &types.APISchema{
+					Summary:  "Mark a preview environment deployment as created",
+					Request:  types.FinalizeDeploymentByClusterRequest{},
+					Response: types.Deployment{},
+				},
 			},
 		)
 
@@ -694,6 +838,11 @@ func getClusterRoutes(
 					types.ClusterScope,
 					types.PreviewEnvironmentScope,
 				},
+				Schema: &types.APISchema{
+					Summary:  "Mark a preview environment deployment as failed",
+					Request:  types.FinalizeDeploymentWithErrorsByClusterRequest{},
+					Response: types.Deployment{},
+				},
 			},
 		)
 
@@ -815,6 +964,9 @@ func getClusterRoutes(
 					types.ClusterScope,
 					types.PreviewEnvironmentScope,
 				},
+				Schema: &types.APISchema{
+					Summary: "Delete a preview environment deployment",
+				},
 			},
 		)
 
@@ -876,6 +1028,10 @@ func getClusterRoutes(
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the namespaces of a cluster",
+				Response: types.ListNamespacesResponse{},
+			},
 		},
 	)
 
@@ -890,6 +1046,41 @@ func getClusterRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/releases -> cluster.NewListReleasesHandler
+	listReleasesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/releases",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "List the helm releases managed by Porter in a cluster",
+				Description: "Lists the latest revision of every helm release deployed or imported by Porter, filtered by status, chart and namespace. Releases whose app no longer exists are marked for cleanup.",
+				Request:     types.ListClusterReleasesRequest{},
+				Response:    types.ListClusterReleasesResponse{},
+			},
+		},
+	)
+
+	listReleasesHandler := cluster.NewListReleasesHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listReleasesEndpoint,
+		Handler:  listReleasesHandler,
+		Router:   r,
+	})
+
 	// GET /api/projects/{project_id}/clusters/{cluster_id}/nodes -> cluster.NewListNodesHandler
 	listNodesEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -960,6 +1151,11 @@ func getClusterRoutes(
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Create a namespace in a cluster",
+				Request:  types.CreateNamespaceRequest{},
+				Response: types.NamespaceResponse{},
+			},
 		},
 	)
 
@@ -1018,6 +1214,10 @@ func getClusterRoutes(
 					types.ProjectScope,
 					types.ClusterScope,
 				},
+				Schema: &types.APISchema{
+					Summary:  "Get a temporary kubeconfig for a cluster",
+					Response: types.GetTemporaryKubeconfigResponse{},
+				},
 			},
 		)
 
@@ -1086,8 +1286,9 @@ func getClusterRoutes(
 	// POST /api/projects/{project_id}/clusters/{cluster_id}/agent/install -> cluster.NewInstallAgentHandler
 	installAgentEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath + "/agent/install",
@@ -1140,8 +1341,9 @@ func getClusterRoutes(
 	// POST /api/projects/{project_id}/clusters/{cluster_id}/agent/upgrade -> cluster.NewInstallAgentHandler
 	upgradeAgentEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath + "/agent/upgrade",
@@ -1238,6 +1440,7 @@ func getClusterRoutes(
 				types.ClusterScope,
 			},
 			IsWebsocket: true,
+			StreamType:  types.StreamTypeStatus,
 		},
 	)
 
@@ -1301,6 +1504,7 @@ func getClusterRoutes(
 				types.ClusterScope,
 			},
 			IsWebsocket: true,
+			StreamType:  types.StreamTypeStatus,
 		},
 	)
 
@@ -1736,6 +1940,11 @@ func getClusterRoutes(
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the environment groups of a cluster",
+				Request:  environment_groups.ListEnvironmentGroupsRequest{},
+				Response: types.ListEnvironmentGroupsResponse{},
+			},
 		},
 	)
 
@@ -1765,6 +1974,10 @@ func getClusterRoutes(
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get the latest variables of an environment group",
+				Response: environment_groups.LatestEnvGroupVariablesResponse{},
+			},
 		},
 	)
 
@@ -75,6 +75,11 @@ func getLegacyDeploymentTargetRoutes(
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Create a deployment target in a cluster",
+				Request:  deployment_target.CreateDeploymentTargetRequest{},
+				Response: deployment_target.CreateDeploymentTargetResponse{},
+			},
 		},
 	)
 
@@ -465,6 +465,10 @@ func getGitInstallationRoutes(
 				types.ProjectScope,
 				types.GitInstallationScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the repositories of a GitHub app installation",
+				Response: types.ListReposResponse{},
+			},
 		},
 	)
 
@@ -721,6 +725,10 @@ func getGitInstallationRoutes(
 				types.ProjectScope,
 				types.GitInstallationScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get the download URL of a repository archive",
+				Response: types.GetTarballURLResponse{},
+			},
 		},
 	)
 
@@ -124,6 +124,9 @@ func getHelmRepoRoutes(
 				types.ProjectScope,
 				types.HelmRepoScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Remove a helm repo from a project",
+			},
 		},
 	)
 
@@ -272,6 +272,7 @@ func getInfraRoutes(
 				types.OperationScope,
 			},
 			IsWebsocket: true,
+			StreamType:  types.StreamTypeProvisioning,
 		},
 	)
 
@@ -302,6 +303,7 @@ func getInfraRoutes(
 				types.OperationScope,
 			},
 			IsWebsocket: true,
+			StreamType:  types.StreamTypeProvisioning,
 		},
 	)
 
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/porter-dev/porter/pkg/redact"
+)
+
+// debugRecordingWriteTimeout bounds the time spent writing an exchange to the object store after the request returned
+const debugRecordingWriteTimeout = 30 * time.Second
+
+// DebugRecordingMiddleware records the requests to a project, and their responses, while an instance admin has a debug
+// recording running for the project. Exchanges are redacted and written to the object store after the response is
+// sent, so recording does not slow down the request.
+type DebugRecordingMiddleware struct {
+	config *config.Config
+}
+
+func NewDebugRecordingMiddleware(config *config.Config) *DebugRecordingMiddleware {
+	return &DebugRecordingMiddleware{config}
+}
+
+func (d *DebugRecordingMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := d.config.DebugRecorder
+		proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+		if recorder == nil || proj == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recording, ok := recorder.Active(r.Context(), proj.ID)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, span := telemetry.NewSpan(r.Context(), "middleware-debug-recording")
+		defer span.End()
+
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "debug-recording-id", Value: recording.ID})
+
+		r = r.Clone(ctx)
+		start := time.Now()
+
+		var requestBody []byte
+		if r.Body != nil {
+			var err error
+			requestBody, err = io.ReadAll(io.LimitReader(r.Body, redact.MaxBodyBytes+1))
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// the handler reads the part of the body which was recorded, followed by the rest
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(requestBody), r.Body), r.Body}
+		}
+
+		rw := &recordingWriter{ResponseWriter: w}
+		next.ServeHTTP(rw, r)
+
+		exchange := redact.NewExchange(redact.ExchangeInput{
+			Start:           start,
+			End:             time.Now(),
+			Method:          r.Method,
+			URL:             r.URL.String(),
+			RequestHeaders:  r.Header,
+			RequestBody:     requestBody,
+			StatusCode:      rw.statusCode(),
+			ResponseHeaders: w.Header(),
+			ResponseBody:    rw.body.Bytes(),
+		})
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), debugRecordingWriteTimeout)
+			defer cancel()
+
+			if err := recorder.Record(ctx, recording, exchange); err != nil {
+				d.config.Logger.Error().Err(err).Uint("project_id", proj.ID).Uint("debug_recording_id", recording.ID).Msg("error recording exchange")
+			}
+		}()
+	})
+}
+
+// recordingWriter keeps a copy of the start of a response as it is written
+type recordingWriter struct {
+	http.ResponseWriter
+
+	code int
+	body bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	if rw.code == 0 {
+		rw.code = code
+	}
+
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	if rw.code == 0 {
+		rw.code = http.StatusOK
+	}
+
+	if remaining := redact.MaxBodyBytes + 1 - rw.body.Len(); remaining > 0 {
+		if len(b) < remaining {
+			remaining = len(b)
+		}
+		rw.body.Write(b[:remaining])
+	}
+
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rw *recordingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+func (rw *recordingWriter) statusCode() int {
+	if rw.code == 0 {
+		return http.StatusOK
+	}
+
+	return rw.code
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// PorterAppNameMiddleware resolves the app name URL param of app routes to the current name of the app, so that
+// handlers only ever see the current name. The param can be the UUID of the app in place of its name. The name an app
+// was renamed from is served for the app until it expires, unless another app has taken it: GET requests are
+// redirected to the URL with the current name, and other requests are served as if they used it.
+type PorterAppNameMiddleware struct {
+	config *config.Config
+}
+
+func NewPorterAppNameMiddleware(config *config.Config) *PorterAppNameMiddleware {
+	return &PorterAppNameMiddleware{config}
+}
+
+func (m *PorterAppNameMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := telemetry.NewSpan(r.Context(), "middleware-porter-app-name")
+		defer span.End()
+
+		project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+		cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+		param := chi.URLParam(r, string(types.URLParamPorterAppName))
+
+		if project == nil || cluster == nil || param == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if id, err := uuid.Parse(param); err == nil {
+			app, err := m.config.Repo.PorterApp().ReadScopedPorterAppByUUID(ctx, project.ID, cluster.ID, id)
+			if err == nil {
+				telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-name", Value: app.Name})
+				setPorterAppName(r, app.Name)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				_ = telemetry.Error(ctx, span, err, "error reading app by uuid")
+			}
+
+			// an app may be named like a UUID, so the param is still looked up as a name
+		}
+
+		_, err := m.config.Repo.PorterApp().ReadScopedPorterAppByName(project.ID, cluster.ID, param)
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			// errors other than the app not existing are left to the handler to report
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		app, err := m.config.Repo.PorterApp().ReadScopedPorterAppByPreviousName(ctx, project.ID, cluster.ID, param)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				_ = telemetry.Error(ctx, span, err, "error reading app by previous name")
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		telemetry.WithAttributes(span,
+			telemetry.AttributeKV{Key: "previous-app-name", Value: param},
+			telemetry.AttributeKV{Key: "app-name", Value: app.Name},
+		)
+
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			location := *r.URL
+			location.Path = renamedAppPath(r.URL.Path, param, app.Name)
+			location.RawPath = ""
+
+			http.Redirect(w, r, location.RequestURI(), http.StatusMovedPermanently)
+			return
+		}
+
+		setPorterAppName(r, app.Name)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setPorterAppName replaces the app name URL param which handlers read
+func setPorterAppName(r *http.Request, name string) {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return
+	}
+
+	for i, key := range rctx.URLParams.Keys {
+		if key == string(types.URLParamPorterAppName) {
+			rctx.URLParams.Values[i] = name
+		}
+	}
+}
+
+// renamedAppPath replaces the previous name of an app with its current name in the path of an app route, which is the
+// segment after /applications or /apps
+func renamedAppPath(path, previousName, name string) string {
+	segments := strings.Split(path, "/")
+
+	for i := 1; i < len(segments); i++ {
+		if segments[i] == previousName && (segments[i-1] == "applications" || segments[i-1] == "apps") {
+			segments[i] = name
+			break
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,144 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/porter-dev/porter/api/server/router/middleware"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestPorterAppNameMiddleware(t *testing.T) {
+	conf := apitest.LoadConfig(t)
+
+	project := &models.Project{}
+	project.ID = 1
+	cluster := &models.Cluster{ProjectID: 1}
+	cluster.ID = 2
+
+	expiresAt := time.Now().Add(time.Hour)
+	expiredAt := time.Now().Add(-time.Hour)
+
+	renamed, err := conf.Repo.PorterApp().CreatePorterApp(&models.PorterApp{
+		ProjectID:             1,
+		ClusterID:             2,
+		Name:                  "checkout",
+		PreviousName:          "payments",
+		PreviousNameExpiresAt: &expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = conf.Repo.PorterApp().CreatePorterApp(&models.PorterApp{
+		ProjectID:             1,
+		ClusterID:             2,
+		Name:                  "worker",
+		PreviousName:          "jobs",
+		PreviousNameExpiresAt: &expiredAt,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = conf.Repo.PorterApp().CreatePorterApp(&models.PorterApp{
+		ProjectID:             1,
+		ClusterID:             2,
+		Name:                  "api",
+		PreviousName:          "web",
+		PreviousNameExpiresAt: &expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// an app which took the name another app was renamed from
+	_, err = conf.Repo.PorterApp().CreatePorterApp(&models.PorterApp{ProjectID: 1, ClusterID: 2, Name: "web"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		param  string
+
+		wantStatus   int
+		wantLocation string
+		wantAppName  string
+	}{
+		{
+			name:        "current name",
+			method:      http.MethodGet,
+			param:       "checkout",
+			wantStatus:  http.StatusOK,
+			wantAppName: "checkout",
+		},
+		{
+			name:        "uuid",
+			method:      http.MethodPost,
+			param:       renamed.UUID.String(),
+			wantStatus:  http.StatusOK,
+			wantAppName: "checkout",
+		},
+		{
+			name:         "previous name is redirected on get",
+			method:       http.MethodGet,
+			param:        "payments",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "/api/projects/1/clusters/2/applications/checkout/events?page=2",
+		},
+		{
+			name:        "previous name is rewritten on post",
+			method:      http.MethodPost,
+			param:       "payments",
+			wantStatus:  http.StatusOK,
+			wantAppName: "checkout",
+		},
+		{
+			name:        "app which took a previous name",
+			method:      http.MethodGet,
+			param:       "web",
+			wantStatus:  http.StatusOK,
+			wantAppName: "web",
+		},
+		{
+			name:        "expired previous name",
+			method:      http.MethodGet,
+			param:       "jobs",
+			wantStatus:  http.StatusOK,
+			wantAppName: "jobs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAppName string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAppName = chi.URLParam(r, string(types.URLParamPorterAppName))
+			})
+
+			req := httptest.NewRequest(tt.method, "/api/projects/1/clusters/2/applications/"+tt.param+"/events?page=2", nil)
+			req = apitest.WithURLParams(t, req, map[string]string{string(types.URLParamPorterAppName): tt.param})
+			req = req.WithContext(context.WithValue(req.Context(), types.ProjectScope, project))
+			req = req.WithContext(context.WithValue(req.Context(), types.ClusterScope, cluster))
+
+			rr := httptest.NewRecorder()
+			middleware.NewPorterAppNameMiddleware(conf).Middleware(next).ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if location := rr.Header().Get("Location"); location != tt.wantLocation {
+				t.Errorf("expected location %q, got %q", tt.wantLocation, location)
+			}
+			if gotAppName != tt.wantAppName {
+				t.Errorf("expected app name %q, got %q", tt.wantAppName, gotAppName)
+			}
+		})
+	}
+}
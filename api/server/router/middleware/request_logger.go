@@ -2,21 +2,32 @@ package middleware
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
+	"io"
 	"net"
 	"net/http"
 	"time"
 
 	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/porter-dev/porter/pkg/redact"
 )
 
 type requestLoggerResponseWriter struct {
 	http.ResponseWriter
 	statusCode int
+
+	// body is only kept when logging verbosely
+	body *bytes.Buffer
 }
 
-func newRequestLoggerResponseWriter(w http.ResponseWriter) *requestLoggerResponseWriter {
-	return &requestLoggerResponseWriter{w, http.StatusOK}
+func newRequestLoggerResponseWriter(w http.ResponseWriter, verbose bool) *requestLoggerResponseWriter {
+	rw := &requestLoggerResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	if verbose {
+		rw.body = &bytes.Buffer{}
+	}
+
+	return rw
 }
 
 func (rw *requestLoggerResponseWriter) WriteHeader(code int) {
@@ -24,6 +35,19 @@ func (rw *requestLoggerResponseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *requestLoggerResponseWriter) Write(b []byte) (int, error) {
+	if rw.body != nil {
+		if remaining := redact.MaxBodyBytes - rw.body.Len(); remaining > 0 {
+			if len(b) < remaining {
+				remaining = len(b)
+			}
+			rw.body.Write(b[:remaining])
+		}
+	}
+
+	return rw.ResponseWriter.Write(b)
+}
+
 func (rw *requestLoggerResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	h, ok := rw.ResponseWriter.(http.Hijacker)
 	if !ok {
@@ -32,18 +56,38 @@ func (rw *requestLoggerResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, er
 	return h.Hijack()
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rw *requestLoggerResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 type RequestLoggerMiddleware struct {
 	logger *logger.Logger
+	// verbose logs the redacted request and response bodies along with each request
+	verbose bool
 }
 
-func NewRequestLoggerMiddleware(logger *logger.Logger) *RequestLoggerMiddleware {
-	return &RequestLoggerMiddleware{logger}
+func NewRequestLoggerMiddleware(logger *logger.Logger, verbose bool) *RequestLoggerMiddleware {
+	return &RequestLoggerMiddleware{logger, verbose}
 }
 
 func (mw *RequestLoggerMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		rw := newRequestLoggerResponseWriter(w)
+		rw := newRequestLoggerResponseWriter(w, mw.verbose)
+
+		var requestBody []byte
+		if mw.verbose && r.Body != nil {
+			var err error
+			requestBody, err = io.ReadAll(io.LimitReader(r.Body, redact.MaxBodyBytes))
+			if err == nil {
+				// the handler reads the part of the body which was logged, followed by the rest
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(requestBody), r.Body), r.Body}
+			}
+		}
 
 		next.ServeHTTP(rw, r)
 
@@ -54,6 +98,11 @@ func (mw *RequestLoggerMiddleware) Middleware(next http.Handler) http.Handler {
 		logger.AddLoggingContextScopes(r.Context(), event)
 		logger.AddLoggingRequestMeta(r, event)
 
+		if mw.verbose {
+			event.Bytes("request_body", redact.Body(requestBody))
+			event.Bytes("response_body", redact.Body(rw.body.Bytes()))
+		}
+
 		event.Send()
 	})
 }
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// timeoutWriteGrace is the time after the budget of a request in which the 504 can still be written
+const timeoutWriteGrace = 5 * time.Second
+
+// TimeoutMiddleware bounds the time spent serving a request. The request context is given a deadline which
+// repository and agent calls respect, and a 504 is written if the handler has not finished when it passes.
+type TimeoutMiddleware struct {
+	config  *config.Config
+	timeout time.Duration
+}
+
+func NewTimeoutMiddleware(config *config.Config, timeout time.Duration) *TimeoutMiddleware {
+	return &TimeoutMiddleware{config, timeout}
+}
+
+func (t *TimeoutMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := telemetry.NewSpan(r.Context(), "middleware-timeout")
+		defer span.End()
+
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "timeout-budget", Value: t.timeout.String()})
+
+		ctx, cancel := context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+
+		ctx, budget := telemetry.WithBudget(ctx)
+
+		// the server's write timeout would otherwise end requests whose budget is longer. The error is ignored for
+		// writers which do not support deadlines, such as the recorders used in tests.
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(t.timeout + timeoutWriteGrace))
+
+		tw := &timeoutWriter{w: w, header: make(http.Header)}
+		done := make(chan struct{})
+		panicChan := make(chan interface{}, 1)
+
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicChan <- fmt.Sprintf("%v\n\n%s", p, debug.Stack())
+				}
+			}()
+
+			next.ServeHTTP(tw, r.Clone(ctx))
+			close(done)
+		}()
+
+		select {
+		case p := <-panicChan:
+			// the panic middleware runs in this goroutine, so the panic is raised again to be handled by it
+			panic(p)
+		case <-done:
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// the handler returned because a downstream call ran out of time, and reports the error itself
+				_ = t.recordTimeout(ctx, span, budget)
+			}
+
+			tw.flush()
+		case <-ctx.Done():
+			tw.timeOut()
+
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// the client went away, so there is nobody to write the error to
+				return
+			}
+
+			err := t.recordTimeout(ctx, span, budget)
+			apierrors.HandleAPIError(t.config.Logger, t.config.Alerter, w, r, apierrors.NewErrTimeout(err), true)
+		}
+	})
+}
+
+// recordTimeout records the downstream call which consumed the budget of the request on the middleware's span
+func (t *TimeoutMiddleware) recordTimeout(ctx context.Context, span trace.Span, budget *telemetry.Budget) error {
+	call := budget.Exceeded()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "timeout-exceeded", Value: true},
+		telemetry.AttributeKV{Key: "timeout-downstream-call", Value: call},
+	)
+
+	return telemetry.Error(ctx, span, fmt.Errorf("request exceeded its budget of %s in %q", t.timeout, call), "request timed out")
+}
+
+// timeoutWriter buffers the response of a handler, so that it is either written in full once the handler returns or
+// discarded if the request times out first
+type timeoutWriter struct {
+	w http.ResponseWriter
+
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+
+	return tw.buf.Write(b)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+
+	tw.code = code
+}
+
+func (tw *timeoutWriter) timeOut() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.timedOut = true
+}
+
+func (tw *timeoutWriter) flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	dst := tw.w.Header()
+	for key, values := range tw.header {
+		dst[key] = values
+	}
+
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+
+	tw.w.WriteHeader(tw.code)
+	_, _ = tw.w.Write(tw.buf.Bytes())
+}
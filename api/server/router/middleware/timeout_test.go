@@ -0,0 +1,195 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/porter-dev/porter/api/server/router/middleware"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// slowRepository returns a porter app repository whose reads take longer than the budget of the request
+type slowRepository struct {
+	repository.Repository
+	porterApps repository.PorterAppRepository
+}
+
+func (r slowRepository) PorterApp() repository.PorterAppRepository {
+	return r.porterApps
+}
+
+type slowPorterAppRepository struct {
+	repository.PorterAppRepository
+	delay time.Duration
+	// ignoreDeadline makes the read block for the full delay, like a query made without the request context
+	ignoreDeadline bool
+}
+
+func (r *slowPorterAppRepository) ReadPorterAppByID(ctx context.Context, id uint) (*models.PorterApp, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-porter-app")
+	defer span.End()
+
+	if r.ignoreDeadline {
+		time.Sleep(r.delay)
+		return &models.PorterApp{}, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, telemetry.Error(ctx, span, ctx.Err(), "error reading porter app")
+	case <-time.After(r.delay):
+		return &models.PorterApp{}, nil
+	}
+}
+
+// readAppHandler reads a porter app and reports errors like the API handlers do
+func readAppHandler(config *config.Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := telemetry.NewSpan(r.Context(), "serve-read-app")
+		defer span.End()
+
+		app, err := config.Repo.PorterApp().ReadPorterAppByID(ctx, 1)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error reading app")
+			apierrors.HandleAPIError(config.Logger, config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(app)
+	})
+}
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func spanAttribute(recorder *tracetest.SpanRecorder, spanName, key string) (attribute.Value, bool) {
+	for _, span := range recorder.Ended() {
+		if span.Name() != "porter.run/"+spanName {
+			continue
+		}
+		for _, attr := range span.Attributes() {
+			if string(attr.Key) == "porter.run/"+key {
+				return attr.Value, true
+			}
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		ignoreDeadline bool
+	}{
+		{
+			name: "call which respects the deadline",
+		},
+		{
+			name:           "call which ignores the deadline",
+			ignoreDeadline: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+
+			conf := apitest.LoadConfig(t)
+			conf.Repo = slowRepository{
+				Repository: conf.Repo,
+				porterApps: &slowPorterAppRepository{delay: 2 * time.Second, ignoreDeadline: tt.ignoreDeadline},
+			}
+
+			handler := middleware.NewTimeoutMiddleware(conf, 50*time.Millisecond).Middleware(readAppHandler(conf))
+
+			rr := httptest.NewRecorder()
+			start := time.Now()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/apps/1", nil))
+
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("expected the request to end at its deadline, took %s", elapsed)
+			}
+			if rr.Code != http.StatusGatewayTimeout {
+				t.Fatalf("expected status %d, got %d", http.StatusGatewayTimeout, rr.Code)
+			}
+
+			extErr := &types.ExternalError{}
+			if err := json.NewDecoder(rr.Body).Decode(extErr); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if extErr.Code != types.ErrCodeRequestTimeout {
+				t.Errorf("expected error code %d, got %d", types.ErrCodeRequestTimeout, extErr.Code)
+			}
+
+			call, ok := spanAttribute(recorder, "middleware-timeout", "timeout-downstream-call")
+			if !ok || call.AsString() != "gorm-read-porter-app" {
+				t.Errorf("expected the timeout to be attributed to gorm-read-porter-app, got %q", call.AsString())
+			}
+
+			if !tt.ignoreDeadline {
+				// the response can be written before the handler returns, so its spans may end after the request
+				var exceeded attribute.Value
+				for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+					if exceeded, ok = spanAttribute(recorder, "gorm-read-porter-app", "deadline-exceeded"); ok {
+						break
+					}
+				}
+				if !ok || !exceeded.AsBool() {
+					t.Errorf("expected the span of the slow call to be marked as exceeding the deadline")
+				}
+			}
+		})
+	}
+}
+
+func TestTimeoutMiddlewareWithinBudget(t *testing.T) {
+	recorder := recordSpans(t)
+
+	conf := apitest.LoadConfig(t)
+	conf.Repo = slowRepository{
+		Repository: conf.Repo,
+		porterApps: &slowPorterAppRepository{delay: time.Millisecond},
+	}
+
+	handler := middleware.NewTimeoutMiddleware(conf, time.Second).Middleware(readAppHandler(conf))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/apps/1", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if contentType := rr.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected the handler's headers to be written, got content type %q", contentType)
+	}
+
+	if _, ok := spanAttribute(recorder, "middleware-timeout", "timeout-exceeded"); ok {
+		t.Errorf("expected no timeout to be recorded")
+	}
+}
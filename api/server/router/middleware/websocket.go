@@ -9,18 +9,47 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/websocket"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
 )
 
 type WebsocketMiddleware struct {
-	config *config.Config
+	config     *config.Config
+	streamType types.StreamType
 }
 
-func NewWebsocketMiddleware(config *config.Config) *WebsocketMiddleware {
-	return &WebsocketMiddleware{config}
+// NewWebsocketMiddleware returns a middleware which upgrades requests to websocket connections of the given stream type
+func NewWebsocketMiddleware(config *config.Config, streamType types.StreamType) *WebsocketMiddleware {
+	return &WebsocketMiddleware{config, streamType}
 }
 
 func (wm *WebsocketMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the stream is registered before the upgrade, so that a client over its limit gets a 429 it can read instead of a dropped connection
+		var stream *websocket.Stream
+		if wm.config.StreamRegistry != nil {
+			var userID, projectID uint
+			if user, ok := r.Context().Value(types.UserScope).(*models.User); ok {
+				userID = user.ID
+			}
+			if project, ok := r.Context().Value(types.ProjectScope).(*models.Project); ok {
+				projectID = project.ID
+			}
+
+			var err error
+			stream, err = wm.config.StreamRegistry.Open(userID, projectID, wm.streamType)
+			if err != nil {
+				var limitErr *websocket.StreamLimitError
+				if errors.As(err, &limitErr) {
+					apierrors.HandleAPIError(wm.config.Logger, wm.config.Alerter, w, r, apierrors.NewErrPassThroughToClient(err, http.StatusTooManyRequests), true)
+					return
+				}
+
+				apierrors.HandleAPIError(wm.config.Logger, wm.config.Alerter, w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable), true)
+				return
+			}
+			defer stream.Release()
+		}
+
 		conn, newRW, safeRW, err := wm.config.WSUpgrader.Upgrade(w, r, nil)
 		if err != nil {
 			if errors.Is(err, websocket.UpgraderCheckOriginErr) {
@@ -35,6 +64,11 @@ func (wm *WebsocketMiddleware) Middleware(next http.Handler) http.Handler {
 		w = newRW
 		defer conn.Close()
 
+		if stream != nil {
+			stream.Attach(conn)
+			safeRW.SetStream(stream)
+		}
+
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, types.RequestCtxWebsocketKey, safeRW)
 
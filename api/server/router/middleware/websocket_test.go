@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gorillaws "github.com/gorilla/websocket"
+
+	"github.com/porter-dev/porter/api/server/router/middleware"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestWebsocketMiddlewareRejectsStreamsOverLimit(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	config.WSUpgrader = &websocket.Upgrader{
+		WSUpgrader: &gorillaws.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+	config.StreamRegistry = websocket.NewStreamRegistry(websocket.StreamLimits{MaxPerUser: 1})
+
+	user := &models.User{Email: "user@porter.run"}
+	user.ID = 1
+
+	held := make(chan struct{})
+	handler := middleware.NewWebsocketMiddleware(config, types.StreamTypeLogs).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-held
+	}))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, apitest.WithAuthenticatedUser(t, r, user))
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	conn, _, err := gorillaws.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("expected first stream to upgrade, got %v", err)
+	}
+	defer conn.Close()
+
+	// the user is at their limit, so the second stream is rejected before the upgrade
+	_, resp, err := gorillaws.DefaultDialer.Dial(url, nil)
+	if err == nil {
+		t.Fatalf("expected second stream to be rejected")
+	}
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 response, got %v", resp)
+	}
+	defer resp.Body.Close()
+
+	extErr := &types.ExternalError{}
+	if err := json.NewDecoder(resp.Body).Decode(extErr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(extErr.Error, "user limit of 1 reached") {
+		t.Errorf("expected limit reason in response, got %q", extErr.Error)
+	}
+
+	counts := config.StreamRegistry.Counts()
+	if counts.Total != 1 || counts.ByType[types.StreamTypeLogs] != 1 {
+		t.Errorf("expected one open logs stream, got %+v", counts)
+	}
+
+	close(held)
+}
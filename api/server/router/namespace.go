@@ -11,6 +11,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/router"
 	"github.com/porter-dev/porter/api/types"
+	helmrelease "github.com/stefanmcshane/helm/pkg/release"
 )
 
 func NewNamespaceScopedRegisterer(children ...*router.Registerer) *router.Registerer {
@@ -101,6 +102,11 @@ func getNamespaceRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Clone an env group into another namespace",
+				Request:  types.CloneEnvGroupRequest{},
+				Response: types.EnvGroup{},
+			},
 		},
 	)
 
@@ -131,6 +137,11 @@ func getNamespaceRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get an env group",
+				Request:  types.GetEnvGroupRequest{},
+				Response: types.GetEnvGroupResponse{},
+			},
 		},
 	)
 
@@ -191,6 +202,11 @@ func getNamespaceRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Create or update an env group",
+				Request:  types.CreateEnvGroupRequest{},
+				Response: types.EnvGroup{},
+			},
 		},
 	)
 
@@ -399,6 +415,11 @@ func getNamespaceRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the releases in a namespace",
+				Request:  types.ListReleasesRequest{},
+				Response: []*helmrelease.Release{},
+			},
 		},
 	)
 
@@ -434,6 +455,7 @@ func getNamespaceRoutes(
 				types.NamespaceScope,
 			},
 			IsWebsocket: true,
+			StreamType:  types.StreamTypeLogs,
 		},
 	)
 
@@ -468,6 +490,7 @@ func getNamespaceRoutes(
 				types.NamespaceScope,
 			},
 			IsWebsocket: true,
+			StreamType:  types.StreamTypeLogs,
 		},
 	)
 
@@ -502,6 +525,7 @@ func getNamespaceRoutes(
 				types.NamespaceScope,
 			},
 			IsWebsocket: true,
+			StreamType:  types.StreamTypeEvents,
 		},
 	)
 
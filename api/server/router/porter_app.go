@@ -9,6 +9,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/router"
 	"github.com/porter-dev/porter/api/types"
+	internalPorterApp "github.com/porter-dev/porter/internal/porter_app"
 )
 
 func NewPorterAppScopedRegisterer(children ...*router.Registerer) *router.Registerer {
@@ -70,6 +71,10 @@ func getPorterAppRoutes(
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get an app",
+				Response: types.PorterApp{},
+			},
 		},
 	)
 
@@ -140,215 +145,257 @@ func getPorterAppRoutes(
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/pods -> cluster.NewPodStatusHandler
-	appPodStatusEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/{name}/helm-revisions -> porter_app.NewListHelmRevisionsHandler
+	listHelmRevisionsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
+			Verb:   types.APIVerbList,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/pods", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/helm-revisions", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the helm revisions of an app, newest first",
+				Request:  types.ListHelmRevisionsRequest{},
+				Response: types.ListHelmRevisionsResponse{},
+			},
 		},
 	)
 
-	appPodStatusHandler := porter_app.NewPodStatusHandler(
+	listHelmRevisionsHandler := porter_app.NewListHelmRevisionsHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appPodStatusEndpoint,
-		Handler:  appPodStatusHandler,
+		Endpoint: listHelmRevisionsEndpoint,
+		Handler:  listHelmRevisionsHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/{name}/releases/{version}/pods/all -> porter_app.NewPorterAppPodsGetHandler
-	getPorterAppPodsEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/validate -> porter_app.NewValidatePorterAppHandler
+	validatePorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbList,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/releases/{%s}/pods/all", relPath, types.URLParamPorterAppName, types.URLParamReleaseVersion),
+				RelativePath: fmt.Sprintf("%s/{%s}/validate", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Validate an app",
+				Description: "Builds the chart and values a create or update of the app would deploy, without installing anything. Problems with porter.yaml are returned as findings instead of errors.",
+				Request:     types.CreatePorterAppRequest{},
+				Response:    types.ValidatePorterAppResponse{},
+			},
 		},
 	)
 
-	getPorterAppPodsHandler := porter_app.NewPorterAppPodsGetHandler(
+	validatePorterAppHandler := porter_app.NewValidatePorterAppHandler(
 		config,
+		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: getPorterAppPodsEndpoint,
-		Handler:  getPorterAppPodsHandler,
+		Endpoint: validatePorterAppEndpoint,
+		Handler:  validatePorterAppHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications -> porter_app.NewPorterAppListHandler
-	listPorterAppEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/validate-porter-yaml -> porter_app.NewValidatePorterYAMLHandler
+	validatePorterYAMLEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbList,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: relPath,
+				RelativePath: fmt.Sprintf("%s/validate-porter-yaml", relPath),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Validate a porter.yaml",
+				Description: "Runs a porter.yaml through the same checks as a deploy, without reading the app it would be deployed to or connecting to the cluster. Errors and warnings are located at the line of porter.yaml they are about where possible.",
+				Request:     types.PorterAppValidateYAMLRequest{},
+				Response:    types.PorterAppValidateYAMLResponse{},
+			},
 		},
 	)
 
-	listPorterAppHandler := porter_app.NewPorterAppListHandler(
+	validatePorterYAMLHandler := porter_app.NewValidatePorterYAMLHandler(
 		config,
+		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: listPorterAppEndpoint,
-		Handler:  listPorterAppHandler,
+		Endpoint: validatePorterYAMLEndpoint,
+		Handler:  validatePorterYAMLHandler,
 		Router:   r,
 	})
 
-	// DELETE /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name} -> release.NewDeletePorterAppByNameHandler
-	deletePorterAppByNameEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/diff -> porter_app.NewDiffPorterAppHandler
+	diffPorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbDelete,
-			Method: types.HTTPVerbDelete,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}", relPath, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/diff", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Diff an update of an app",
+				Description: "Builds the values an update with the porter.yaml would deploy, without deploying them, and returns how they differ from the values of the current release. With render=true, the manifests of the update are also rendered and diffed against those of the release. Secrets and values whose key looks like a credential are redacted.",
+				Request:     types.DiffPorterAppRequest{},
+				Response:    types.DiffPorterAppResponse{},
+			},
 		},
 	)
 
-	deletePorterAppByNameHandler := porter_app.NewDeletePorterAppByNameHandler(
+	diffPorterAppHandler := porter_app.NewDiffPorterAppHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: deletePorterAppByNameEndpoint,
-		Handler:  deletePorterAppByNameHandler,
+		Endpoint: diffPorterAppEndpoint,
+		Handler:  diffPorterAppHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name} -> porter_app.NewCreatePorterAppHandler
-	createPorterAppEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/deploy-logs -> porter_app.NewStreamDeployLogsHandler
+	streamDeployLogsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}", relPath, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/deploy-logs", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			// the logs are streamed for longer than the time budget of a request
+			Timeout: types.TimeoutClassNone,
+			Schema: &types.APISchema{
+				Summary:     "Stream the logs of a deploy of an app",
+				Description: "Streams the logs of the pods of the app, including its pre-deploy job, as server-sent log events while the app is deployed. The stream ends with a done event once the revision of the deploy is deployed or fails. The response documents the data of the done event.",
+				Request:     types.StreamDeployLogsRequest{},
+				Response:    types.DeployLogsDone{},
+			},
 		},
 	)
 
-	createPorterAppHandler := porter_app.NewCreatePorterAppHandler(
+	streamDeployLogsHandler := porter_app.NewStreamDeployLogsHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: createPorterAppEndpoint,
-		Handler:  createPorterAppHandler,
+		Endpoint: streamDeployLogsEndpoint,
+		Handler:  streamDeployLogsHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/rollback -> porter_app.NewRollbackPorterAppHandler
-	rollbackPorterAppEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/export -> porter_app.NewExportPorterAppHandler
+	exportPorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/rollback", relPath, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/export", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Export an app",
+				Description: "Returns the values of the latest helm release of the app, without the values Porter injects on every deploy, and a porter.yaml which deploys its services as they are currently configured.",
+				Response:    types.ExportPorterAppResponse{},
+			},
 		},
 	)
 
-	rollbackPorterAppHandler := porter_app.NewRollbackPorterAppHandler(
+	exportPorterAppHandler := porter_app.NewExportPorterAppHandler(
 		config,
-		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: rollbackPorterAppEndpoint,
-		Handler:  rollbackPorterAppHandler,
+		Endpoint: exportPorterAppEndpoint,
+		Handler:  exportPorterAppHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/pr -> porter_app.NewOpenStackPRHandler
-	createSecretAndOpenGitHubPullRequestEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/pods -> cluster.NewPodStatusHandler
+	appPodStatusEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/pr", relPath, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/pods", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the pods of an app",
+				Request:  porter_app.PodStatusRequest{},
+				Response: types.GetReleaseAllPodsResponse{},
+			},
 		},
 	)
 
-	createSecretAndOpenGitHubPullRequestHandler := porter_app.NewOpenStackPRHandler(
+	appPodStatusHandler := porter_app.NewPodStatusHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: createSecretAndOpenGitHubPullRequestEndpoint,
-		Handler:  createSecretAndOpenGitHubPullRequestHandler,
+		Endpoint: appPodStatusEndpoint,
+		Handler:  appPodStatusHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/events -> porter_app.NewPorterAppEventListHandler
-	listPorterAppEventsEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/{name}/releases/{version}/pods/all -> porter_app.NewPorterAppPodsGetHandler
+	getPorterAppPodsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbList,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/events", relPath, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/releases/{%s}/pods/all", relPath, types.URLParamPorterAppName, types.URLParamReleaseVersion),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -358,54 +405,60 @@ func getPorterAppRoutes(
 		},
 	)
 
-	listPorterAppEventsHandler := porter_app.NewPorterAppEventListHandler(
+	getPorterAppPodsHandler := porter_app.NewPorterAppPodsGetHandler(
 		config,
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: listPorterAppEventsEndpoint,
-		Handler:  listPorterAppEventsHandler,
+		Endpoint: getPorterAppPodsEndpoint,
+		Handler:  getPorterAppPodsHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{name}/events -> porter_app.NewCreatePorterAppEventHandler
-	createPorterAppEventEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications -> porter_app.NewPorterAppListHandler
+	listPorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/events", relPath, types.URLParamPorterAppName),
+				RelativePath: relPath,
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "List the apps of a cluster",
+				Description: "Lists the apps of the cluster, optionally only the apps with every one of the tags given by tag query params.",
+				Request:     types.ListPorterAppRequest{},
+				Response:    types.ListPorterAppResponse{},
+			},
 		},
 	)
 
-	createPorterAppEventHandler := porter_app.NewCreateUpdatePorterAppEventHandler(
+	listPorterAppHandler := porter_app.NewPorterAppListHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: createPorterAppEventEndpoint,
-		Handler:  createPorterAppEventHandler,
+		Endpoint: listPorterAppEndpoint,
+		Handler:  listPorterAppHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/events/id -> porter_app.NewGetPorterAppEventHandler
-	getPorterAppEventEndpoint := factory.NewAPIEndpoint(
+	// DELETE /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name} -> release.NewDeletePorterAppByNameHandler
+	deletePorterAppByNameEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/events/{%s}", types.URLParamPorterAppEventID),
+				RelativePath: fmt.Sprintf("%s/{%s}", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -415,259 +468,305 @@ func getPorterAppRoutes(
 		},
 	)
 
-	getPorterAppEventHandler := porter_app.NewGetPorterAppEventHandler(
+	deletePorterAppByNameHandler := porter_app.NewDeletePorterAppByNameHandler(
 		config,
+		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: getPorterAppEventEndpoint,
-		Handler:  getPorterAppEventHandler,
+		Endpoint: deletePorterAppByNameEndpoint,
+		Handler:  deletePorterAppByNameHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/analytics -> porter_app.NewPorterAppAnalyticsHandler
-	porterAppAnalyticsEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/stacks/{porter_app_name}/delete_plan -> porter_app.NewDeletePorterAppPlanHandler
+	deletePorterAppPlanEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/analytics", relPath),
+				RelativePath: fmt.Sprintf("/stacks/{%s}/delete_plan", types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Plan the deletion of an app deployed with porter apply v1",
+				Description: "Lists what deleting the app with the given options removes and what it leaves behind: volumes which are kept, load balancers and domains which are released asynchronously, and the namespace if it is kept. The plan hash confirms the deletion.",
+				Request:     types.DeletePorterAppPlanRequest{},
+				Response:    types.DeletePorterAppPlan{},
+			},
 		},
 	)
 
-	porterAppAnalyticsHandler := porter_app.NewPorterAppAnalyticsHandler(
+	deletePorterAppPlanHandler := porter_app.NewDeletePorterAppPlanHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: porterAppAnalyticsEndpoint,
-		Handler:  porterAppAnalyticsHandler,
+		Endpoint: deletePorterAppPlanEndpoint,
+		Handler:  deletePorterAppPlanHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/logs -> cluster.NewGetChartLogsWithinTimeRangeHandler
-	getChartLogsWithinTimeRangeEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/stack_cleanups/{cleanup_id} -> porter_app.NewGetPorterAppCleanupHandler
+	getPorterAppCleanupEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbGet,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/logs", relPath),
+				RelativePath: fmt.Sprintf("/stack_cleanups/{%s}", types.URLParamPorterAppCleanupID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Get the cleanup of a deleted app",
+				Description: "Returns the load balancers and domains of a deleted app which have not been released yet. The cleanup is SUCCEEDED once they are all released, and LEAKED if some were not released before its deadline.",
+				Response:    types.PorterAppCleanup{},
+			},
 		},
 	)
 
-	getChartLogsWithinTimeRangeHandler := porter_app.NewGetLogsWithinTimeRangeHandler(
+	getPorterAppCleanupHandler := porter_app.NewGetPorterAppCleanupHandler(
 		config,
-		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: getChartLogsWithinTimeRangeEndpoint,
-		Handler:  getChartLogsWithinTimeRangeHandler,
+		Endpoint: getPorterAppCleanupEndpoint,
+		Handler:  getPorterAppCleanupHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/run -> porter_app.NewRunPorterAppCommandHandler
-	runPorterAppCommandEndpoint := factory.NewAPIEndpoint(
+	// DELETE /api/projects/{project_id}/clusters/{cluster_id}/stacks/{porter_app_name} -> porter_app.NewDeletePorterAppHandler
+	deletePorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/run", relPath, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("/stacks/{%s}", types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Delete an app deployed with porter apply v1, uninstalling its helm releases",
+				Description: "Deletes the app as described by its deletion plan. The deletion is refused with a 409 if plan_hash is not the hash of the current plan for the same options. The load balancers and domains of the app are released asynchronously; if there are any, the response has the id of the cleanup which tracks them.",
+				Request:     types.DeletePorterAppRequest{},
+				Response:    types.DeletePorterAppResponse{},
+			},
 		},
 	)
 
-	runPorterAppCommandHandler := porter_app.NewRunPorterAppCommandHandler(
+	deletePorterAppHandler := porter_app.NewDeletePorterAppHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: runPorterAppCommandEndpoint,
-		Handler:  runPorterAppCommandHandler,
+		Endpoint: deletePorterAppEndpoint,
+		Handler:  deletePorterAppHandler,
 		Router:   r,
 	})
 
-	// TODO: remove these three endpoints once these three 'stacks' routes are no longer used in telemetry
-
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/stacks/{name} -> porter_app.NewPorterAppGetHandler
-	LEGACY_getPorterAppEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/rename -> porter_app.NewRenamePorterAppHandler
+	renamePorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/stacks/{%s}", types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/rename", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Rename an app",
+				Description: "Installs the helm releases of the app under the new name, waits for the app to become ready, then renames the app and uninstalls the releases of its previous name. Renaming onto the name of another app in the cluster is refused. The previous name keeps resolving to the app until the rename grace period expires.",
+				Request:     types.RenamePorterAppRequest{},
+				Response:    types.PorterApp{},
+			},
 		},
 	)
 
-	LEGACY_getPorterAppHandler := porter_app.NewGetPorterAppHandler(
+	renamePorterAppHandler := porter_app.NewRenamePorterAppHandler(
 		config,
+		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: LEGACY_getPorterAppEndpoint,
-		Handler:  LEGACY_getPorterAppHandler,
+		Endpoint: renamePorterAppEndpoint,
+		Handler:  renamePorterAppHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/stacks/{porter_app_name} -> porter_app.NewCreatePorterAppHandler
-	LEGACY_createPorterAppEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/pause -> porter_app.NewPausePorterAppHandler
+	pausePorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
+			Verb:   types.APIVerbUpdate,
 			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/stacks/{%s}", types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/pause", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Pause an app",
+				Description: "Scales the deployments of the app to zero replicas and suspends its cron jobs, recording the replicas of each deployment so that they are restored when the app is resumed. Deploys of a paused app are refused until it is resumed.",
+				Response:    types.PorterApp{},
+			},
 		},
 	)
 
-	LEGACY_createPorterAppHandler := porter_app.NewCreatePorterAppHandler(
+	pausePorterAppHandler := porter_app.NewPausePorterAppHandler(
 		config,
-		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: LEGACY_createPorterAppEndpoint,
-		Handler:  LEGACY_createPorterAppHandler,
+		Endpoint: pausePorterAppEndpoint,
+		Handler:  pausePorterAppHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/stacks/{name}/events -> porter_app.NewCreatePorterAppEventHandler
-	LEGACY_createPorterAppEventEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/resume -> porter_app.NewResumePorterAppHandler
+	resumePorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
+			Verb:   types.APIVerbUpdate,
 			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/stacks/{%s}/events", types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/resume", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Resume a paused app",
+				Description: "Scales the deployments of the app back to the replicas they ran before it was paused and resumes its suspended cron jobs. Resuming a running app does nothing.",
+				Response:    types.PorterApp{},
+			},
 		},
 	)
 
-	LEGACY_createPorterAppEventHandler := porter_app.NewCreateUpdatePorterAppEventHandler(
+	resumePorterAppHandler := porter_app.NewResumePorterAppHandler(
 		config,
-		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: LEGACY_createPorterAppEventEndpoint,
-		Handler:  LEGACY_createPorterAppEventHandler,
+		Endpoint: resumePorterAppEndpoint,
+		Handler:  resumePorterAppHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/parse -> porter_app.NewParsePorterYAMLToProtoHandler
-	parsePorterYAMLToProtoEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/clone -> porter_app.NewClonePorterAppHandler
+	clonePorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
+			Verb:   types.APIVerbCreate,
 			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/parse", relPathV2),
+				RelativePath: fmt.Sprintf("%s/{%s}/clone", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Clone an app",
+				Description: "Installs the helm releases of the app with the same values under a new name, in the cluster of the app or in another cluster of the project. The copy gets porter.run subdomains and image pull secrets of its own.",
+				Request:     types.ClonePorterAppRequest{},
+				Response:    types.PorterApp{},
+			},
 		},
 	)
 
-	parsePorterYAMLToProtoHandler := porter_app.NewParsePorterYAMLToProtoHandler(
+	clonePorterAppHandler := porter_app.NewClonePorterAppHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: parsePorterYAMLToProtoEndpoint,
-		Handler:  parsePorterYAMLToProtoHandler,
+		Endpoint: clonePorterAppEndpoint,
+		Handler:  clonePorterAppHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/manifests -> porter_app.NewGetAppManifestsHandler
-	getAppManifestsEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name} -> porter_app.NewCreatePorterAppHandler
+	createPorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/manifests", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}", relPath, types.URLParamPorterAppName),
 			},
+			TokenScope: types.APITokenScopeDeploy,
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Create or update an app",
+				Request:  types.CreatePorterAppRequest{},
+				Response: types.PorterApp{},
+			},
 		},
 	)
 
-	getAppManifestsHandler := porter_app.NewAppManifestsHandler(
+	createPorterAppHandler := porter_app.NewCreatePorterAppHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: getAppManifestsEndpoint,
-		Handler:  getAppManifestsHandler,
+		Endpoint: createPorterAppEndpoint,
+		Handler:  createPorterAppHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/env-variables -> porter_app.AppEnvVariablesHandler
-	appEnvVariablesEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/rollback -> porter_app.NewRollbackPorterAppHandler
+	rollbackPorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/env-variables", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/rollback", relPath, types.URLParamPorterAppName),
 			},
+			TokenScope: types.APITokenScopeDeploy,
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
@@ -676,26 +775,26 @@ func getPorterAppRoutes(
 		},
 	)
 
-	appEnvVariablesHandler := porter_app.NewAppEnvVariablesHandler(
+	rollbackPorterAppHandler := porter_app.NewRollbackPorterAppHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appEnvVariablesEndpoint,
-		Handler:  appEnvVariablesHandler,
+		Endpoint: rollbackPorterAppEndpoint,
+		Handler:  rollbackPorterAppHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/yaml -> porter_app.NewPorterYAMLFromRevisionHandler
-	porterYAMLFromRevision := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/snapshot -> porter_app.NewSnapshotPorterAppHandler
+	snapshotPorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbGet,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/revisions/{%s}/yaml", relPathV2, types.URLParamPorterAppName, types.URLParamAppRevisionID),
+				RelativePath: fmt.Sprintf("%s/{%s}/snapshot", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -705,26 +804,26 @@ func getPorterAppRoutes(
 		},
 	)
 
-	porterYAMLFromRevisionHandler := porter_app.NewPorterYAMLFromRevisionHandler(
+	snapshotPorterAppHandler := porter_app.NewSnapshotPorterAppHandler(
 		config,
-		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: porterYAMLFromRevision,
-		Handler:  porterYAMLFromRevisionHandler,
+		Endpoint: snapshotPorterAppEndpoint,
+		Handler:  snapshotPorterAppHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/create -> porter_app.NewCreateAppHandler
-	createAppEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/snapshots/restore -> porter_app.NewRestorePorterAppSnapshotHandler
+	restorePorterAppSnapshotEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/create", relPathV2),
+				RelativePath: fmt.Sprintf("%s/snapshots/restore", relPath),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -734,26 +833,26 @@ func getPorterAppRoutes(
 		},
 	)
 
-	createAppHandler := porter_app.NewCreateAppHandler(
+	restorePorterAppSnapshotHandler := porter_app.NewRestorePorterAppSnapshotHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: createAppEndpoint,
-		Handler:  createAppHandler,
+		Endpoint: restorePorterAppSnapshotEndpoint,
+		Handler:  restorePorterAppSnapshotHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/attach-env-group -> porter_app.NewAttachEnvGroupHandler
-	attachEnvGroupEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/pr -> porter_app.NewOpenStackPRHandler
+	createSecretAndOpenGitHubPullRequestEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
+			Verb:   types.APIVerbCreate,
 			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/attach-env-group", relPathV2),
+				RelativePath: fmt.Sprintf("%s/{%s}/pr", relPath, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -763,26 +862,1183 @@ func getPorterAppRoutes(
 		},
 	)
 
-	attachEnvGroupHandler := porter_app.NewAttachEnvGroupHandler(
+	createSecretAndOpenGitHubPullRequestHandler := porter_app.NewOpenStackPRHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createSecretAndOpenGitHubPullRequestEndpoint,
+		Handler:  createSecretAndOpenGitHubPullRequestHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/events -> porter_app.NewPorterAppEventListHandler
+	listPorterAppEventsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/events", relPath, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "List the legacy events of an app",
+				Description: "Lists a page of the events of an app which have no deployment target, newest first, optionally only those of a type such as DEPLOY or with a status such as FAILED. The response includes the number of pages of matching events.",
+				Request:     types.ListPorterAppEventsRequest{},
+				Response:    types.ListPorterAppEventsResponse{},
+			},
+		},
+	)
+
+	listPorterAppEventsHandler := porter_app.NewPorterAppEventListHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listPorterAppEventsEndpoint,
+		Handler:  listPorterAppEventsHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{name}/events -> porter_app.NewCreatePorterAppEventHandler
+	createPorterAppEventEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/events", relPath, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Create or update an event of an app",
+				Request:  types.CreateOrUpdatePorterAppEventRequest{},
+				Response: types.PorterAppEvent{},
+			},
+		},
+	)
+
+	createPorterAppEventHandler := porter_app.NewCreateUpdatePorterAppEventHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createPorterAppEventEndpoint,
+		Handler:  createPorterAppEventHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/events/id -> porter_app.NewGetPorterAppEventHandler
+	getPorterAppEventEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/events/{%s}", types.URLParamPorterAppEventID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	getPorterAppEventHandler := porter_app.NewGetPorterAppEventHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getPorterAppEventEndpoint,
+		Handler:  getPorterAppEventHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/analytics -> porter_app.NewPorterAppAnalyticsHandler
+	porterAppAnalyticsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/analytics", relPath),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	porterAppAnalyticsHandler := porter_app.NewPorterAppAnalyticsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: porterAppAnalyticsEndpoint,
+		Handler:  porterAppAnalyticsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/scheduling-defaults/drift -> porter_app.NewSchedulingDefaultsDriftHandler
+	schedulingDefaultsDriftEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/scheduling-defaults/drift", relPath),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	schedulingDefaultsDriftHandler := porter_app.NewSchedulingDefaultsDriftHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: schedulingDefaultsDriftEndpoint,
+		Handler:  schedulingDefaultsDriftHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/logs -> cluster.NewGetChartLogsWithinTimeRangeHandler
+	getChartLogsWithinTimeRangeEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/logs", relPath),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	getChartLogsWithinTimeRangeHandler := porter_app.NewGetLogsWithinTimeRangeHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getChartLogsWithinTimeRangeEndpoint,
+		Handler:  getChartLogsWithinTimeRangeHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/run -> porter_app.NewRunPorterAppCommandHandler
+	runPorterAppCommandEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/run", relPath, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	runPorterAppCommandHandler := porter_app.NewRunPorterAppCommandHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: runPorterAppCommandEndpoint,
+		Handler:  runPorterAppCommandHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/run-jobs -> porter_app.NewRunJobHandler
+	runJobEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/run-jobs", relPath, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Run a one-off job with the image and environment of an app",
+				Request:  porter_app.RunJobRequest{},
+				Response: porter_app.RunJobResponse{},
+			},
+		},
+	)
+
+	runJobHandler := porter_app.NewRunJobHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: runJobEndpoint,
+		Handler:  runJobHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/applications/{porter_app_name}/run-jobs/{run_job_id} -> porter_app.NewRunJobStatusHandler
+	runJobStatusEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/run-jobs/{%s}", relPath, types.URLParamPorterAppName, types.URLParamRunJobID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Get the status and logs of a one-off job",
+				Response: internalPorterApp.RunJob{},
+			},
+		},
+	)
+
+	runJobStatusHandler := porter_app.NewRunJobStatusHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: runJobStatusEndpoint,
+		Handler:  runJobStatusHandler,
+		Router:   r,
+	})
+
+	// TODO: remove these three endpoints once these three 'stacks' routes are no longer used in telemetry
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/stacks/{name} -> porter_app.NewPorterAppGetHandler
+	LEGACY_getPorterAppEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/stacks/{%s}", types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Get an app",
+				Response: types.PorterApp{},
+			},
+		},
+	)
+
+	LEGACY_getPorterAppHandler := porter_app.NewGetPorterAppHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: LEGACY_getPorterAppEndpoint,
+		Handler:  LEGACY_getPorterAppHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/stacks/{porter_app_name} -> porter_app.NewCreatePorterAppHandler
+	LEGACY_createPorterAppEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/stacks/{%s}", types.URLParamPorterAppName),
+			},
+			TokenScope: types.APITokenScopeDeploy,
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Create or update an app",
+				Request:  types.CreatePorterAppRequest{},
+				Response: types.PorterApp{},
+			},
+		},
+	)
+
+	LEGACY_createPorterAppHandler := porter_app.NewCreatePorterAppHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: LEGACY_createPorterAppEndpoint,
+		Handler:  LEGACY_createPorterAppHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/stacks/{porter_app_name}/dry_run -> porter_app.NewDryRunPorterAppHandler
+	dryRunPorterAppEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/stacks/{%s}/dry_run", types.URLParamPorterAppName),
+			},
+			TokenScope: types.APITokenScopeDeploy,
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Dry run an app",
+				Description: "Builds and renders the charts of an app without installing them, creating its namespace or writing it to the database.",
+				Request:     types.CreatePorterAppRequest{},
+				Response:    types.CreatePorterAppDryRunResponse{},
+			},
+		},
+	)
+
+	dryRunPorterAppHandler := porter_app.NewDryRunPorterAppHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: dryRunPorterAppEndpoint,
+		Handler:  dryRunPorterAppHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/stacks/{name}/events -> porter_app.NewCreatePorterAppEventHandler
+	LEGACY_createPorterAppEventEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/stacks/{%s}/events", types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Create or update an event of an app",
+				Request:  types.CreateOrUpdatePorterAppEventRequest{},
+				Response: types.PorterAppEvent{},
+			},
+		},
+	)
+
+	LEGACY_createPorterAppEventHandler := porter_app.NewCreateUpdatePorterAppEventHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: LEGACY_createPorterAppEventEndpoint,
+		Handler:  LEGACY_createPorterAppEventHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/parse -> porter_app.NewParsePorterYAMLToProtoHandler
+	parsePorterYAMLToProtoEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/parse", relPathV2),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Parse a porter.yaml into an app",
+				Request:  porter_app.ParsePorterYAMLToProtoRequest{},
+				Response: porter_app.ParsePorterYAMLToProtoResponse{},
+			},
+		},
+	)
+
+	parsePorterYAMLToProtoHandler := porter_app.NewParsePorterYAMLToProtoHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: parsePorterYAMLToProtoEndpoint,
+		Handler:  parsePorterYAMLToProtoHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/manifests -> porter_app.NewGetAppManifestsHandler
+	getAppManifestsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/manifests", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Get the manifests of an app",
+				Request:  porter_app.AppManifestsRequest{},
+				Response: porter_app.AppManifestsResponse{},
+			},
+		},
+	)
+
+	getAppManifestsHandler := porter_app.NewAppManifestsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getAppManifestsEndpoint,
+		Handler:  getAppManifestsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/env-variables -> porter_app.AppEnvVariablesHandler
+	appEnvVariablesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/env-variables", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Get the env variables of an app",
+				Request:  porter_app.LatestAppRevisionRequest{},
+				Response: porter_app.AppEnvVariablesResponse{},
+			},
+		},
+	)
+
+	appEnvVariablesHandler := porter_app.NewAppEnvVariablesHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: appEnvVariablesEndpoint,
+		Handler:  appEnvVariablesHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/yaml -> porter_app.NewPorterYAMLFromRevisionHandler
+	porterYAMLFromRevision := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/revisions/{%s}/yaml", relPathV2, types.URLParamPorterAppName, types.URLParamAppRevisionID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	porterYAMLFromRevisionHandler := porter_app.NewPorterYAMLFromRevisionHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: porterYAMLFromRevision,
+		Handler:  porterYAMLFromRevisionHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/create -> porter_app.NewCreateAppHandler
+	createAppEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/create", relPathV2),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary: "Create an app",
+				Request: porter_app.CreateAppRequest{},
+			},
+		},
+	)
+
+	createAppHandler := porter_app.NewCreateAppHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createAppEndpoint,
+		Handler:  createAppHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/attach-env-group -> porter_app.NewAttachEnvGroupHandler
+	attachEnvGroupEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/attach-env-group", relPathV2),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	attachEnvGroupHandler := porter_app.NewAttachEnvGroupHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: attachEnvGroupEndpoint,
+		Handler:  attachEnvGroupHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/rollback -> porter_app.NewRollbackAppRevisionHandler
+	rollbackAppRevisionEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/rollback", relPathV2, types.URLParamPorterAppName),
+			},
+			TokenScope: types.APITokenScopeDeploy,
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Roll an app back to a previous revision",
+				Request:  porter_app.RollbackAppRevisionRequest{},
+				Response: porter_app.RollbackAppRevisionResponse{},
+			},
+		},
+	)
+
+	rollbackAppRevisionHandler := porter_app.NewRollbackAppRevisionHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: rollbackAppRevisionEndpoint,
+		Handler:  rollbackAppRevisionHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/update-image -> porter_app.NewUpdateImageHandler
+	updatePorterAppImageEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/update-image", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Update the image of an app",
+				Request:  porter_app.UpdateImageRequest{},
+				Response: porter_app.UpdateImageResponse{},
+			},
+		},
+	)
+
+	updatePorterAppImageHandler := porter_app.NewUpdateImageHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updatePorterAppImageEndpoint,
+		Handler:  updatePorterAppImageHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/default-deployment-target -> porter_app.NewDefaultDeploymentTargetHandler
+	defaultDeploymentTargetEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/default-deployment-target",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Get the default deployment target of a cluster",
+				Response: porter_app.DefaultDeploymentTargetResponse{},
+			},
+		},
+	)
+
+	defaultDeploymentTargetHandler := porter_app.NewDefaultDeploymentTargetHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: defaultDeploymentTargetEndpoint,
+		Handler:  defaultDeploymentTargetHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/latest -> porter_app.NewCurrentAppRevisionHandler
+	currentAppRevisionEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/latest", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Get the current revision of an app",
+				Request:  porter_app.LatestAppRevisionRequest{},
+				Response: porter_app.LatestAppRevisionResponse{},
+			},
+		},
+	)
+
+	currentAppRevisionHandler := porter_app.NewLatestAppRevisionHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: currentAppRevisionEndpoint,
+		Handler:  currentAppRevisionHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/notifications -> porter_app.NewAppNotificationsHandler
+	appNotificationsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/notifications", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	appNotificationsHandler := porter_app.NewAppNotificationsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: appNotificationsEndpoint,
+		Handler:  appNotificationsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions -> porter_app.NewCurrentAppRevisionHandler
+	listAppRevisionsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/revisions", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "List the revisions of an app",
+				Request:  porter_app.ListAppRevisionsRequest{},
+				Response: porter_app.ListAppRevisionsResponse{},
+			},
+		},
+	)
+
+	listAppRevisionsHandler := porter_app.NewListAppRevisionsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listAppRevisionsEndpoint,
+		Handler:  listAppRevisionsHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/update -> porter_app.UpdateAppHandler
+	updateAppEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/update", relPathV2),
+			},
+			TokenScope: types.APITokenScopeDeploy,
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Apply an app",
+				Request:  porter_app.UpdateAppRequest{},
+				Response: porter_app.UpdateAppResponse{},
+			},
+		},
+	)
+
+	updateAppHandler := porter_app.NewUpdateAppHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateAppEndpoint,
+		Handler:  updateAppHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/build -> porter_app.NewUpdateAppBuildSettingsHandler
+	updateAppBuildSettingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/build", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	updateAppBuildSettingsHandler := porter_app.NewUpdateAppBuildSettingsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateAppBuildSettingsEndpoint,
+		Handler:  updateAppBuildSettingsHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/build/validate -> porter_app.NewValidateBuildSettingsHandler
+	validateBuildSettingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/build/validate", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Validate the build settings of an app",
+				Request:  porter_app.ValidateBuildSettingsRequest{},
+				Response: porter_app.ValidateBuildSettingsResponse{},
+			},
+		},
+	)
+
+	validateBuildSettingsHandler := porter_app.NewValidateBuildSettingsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: validateBuildSettingsEndpoint,
+		Handler:  validateBuildSettingsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/revisions -> porter_app.NewLatestAppRevisionsHandler
+	latestAppRevisionsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/revisions", relPathV2),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	latestAppRevisionsHandler := porter_app.NewLatestAppRevisionsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: latestAppRevisionsEndpoint,
+		Handler:  latestAppRevisionsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/instances -> porter_app.NewAppInstancesHandler
+	latestAppInstancesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/instances", relPathV2),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	latestAppInstancesHandler := porter_app.NewAppInstancesHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: latestAppInstancesEndpoint,
+		Handler:  latestAppInstancesHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/subdomain -> porter_app.NewCreateSubdomainHandler
+	createSubdomainEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/subdomain", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Create a subdomain for a service of an app",
+				Request:  porter_app.CreateSubdomainRequest{},
+				Response: porter_app.CreateSubdomainResponse{},
+			},
+		},
+	)
+
+	createSubdomainHandler := porter_app.NewCreateSubdomainHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createSubdomainEndpoint,
+		Handler:  createSubdomainHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/{app_revision_id}/predeploy-status -> porter_app.NewPredeployStatusHandler
+	predeployStatusEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/{%s}/predeploy-status", relPathV2, types.URLParamPorterAppName, types.URLParamAppRevisionID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Get the status of the pre-deploy job of an app revision",
+				Response: porter_app.PredeployStatusResponse{},
+			},
+		},
+	)
+
+	predeployStatusHandler := porter_app.NewPredeployStatusHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: predeployStatusEndpoint,
+		Handler:  predeployStatusHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/logs -> cluster.NewAppLogsHandler
+	appLogsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/logs", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	appLogsHandler := porter_app.NewAppLogsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: appLogsEndpoint,
+		Handler:  appLogsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/logs/loki -> namespace.NewStreamLogsLokiHandler
+	streamLogsLokiEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/logs/loki", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			IsWebsocket: true,
+			StreamType:  types.StreamTypeLogs,
+		},
+	)
+
+	streamLogsLokiHandler := porter_app.NewStreamLogsLokiHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: streamLogsLokiEndpoint,
+		Handler:  streamLogsLokiHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/metrics -> cluster.NewGetPodMetricsHandler
+	appMetricsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/metrics", relPathV2),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	appMetricsHandler := porter_app.NewAppMetricsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: appMetricsEndpoint,
+		Handler:  appMetricsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/status -> cluster.NewAppStatusHandler
+	appStatusEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/status", relPathV2, types.URLParamKind),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			IsWebsocket: true,
+			StreamType:  types.StreamTypeStatus,
+		},
+	)
+
+	appStatusHandler := porter_app.NewAppStatusHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: attachEnvGroupEndpoint,
-		Handler:  attachEnvGroupHandler,
+		Endpoint: appStatusEndpoint,
+		Handler:  appStatusHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/rollback -> porter_app.NewRollbackAppRevisionHandler
-	rollbackAppRevisionEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/service_status -> cluster.NewAppServiceStatusHandler
+	appServiceStatusEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/rollback", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/service_status", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -792,26 +2048,26 @@ func getPorterAppRoutes(
 		},
 	)
 
-	rollbackAppRevisionHandler := porter_app.NewRollbackAppRevisionHandler(
+	appServiceStatusHandler := porter_app.NewServiceStatusHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: rollbackAppRevisionEndpoint,
-		Handler:  rollbackAppRevisionHandler,
+		Endpoint: appServiceStatusEndpoint,
+		Handler:  appServiceStatusHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/update-image -> porter_app.NewUpdateImageHandler
-	updatePorterAppImageEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/jobs -> cluster.NewJobStatusHandler
+	appJobStatusEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/update-image", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/jobs", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -821,26 +2077,26 @@ func getPorterAppRoutes(
 		},
 	)
 
-	updatePorterAppImageHandler := porter_app.NewUpdateImageHandler(
+	appJobStatusHandler := porter_app.NewJobStatusHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: updatePorterAppImageEndpoint,
-		Handler:  updatePorterAppImageHandler,
+		Endpoint: appJobStatusEndpoint,
+		Handler:  appJobStatusHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/default-deployment-target -> porter_app.NewDefaultDeploymentTargetHandler
-	defaultDeploymentTargetEndpoint := factory.NewAPIEndpoint(
+	// GET  /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/jobs/{job_run_name} -> porter_app.JobStatusByNameHandler
+	appJobStatusByNameEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbGet,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: "/default-deployment-target",
+				RelativePath: fmt.Sprintf("%s/{%s}/jobs/{%s}", relPathV2, types.URLParamPorterAppName, types.URLParamJobRunName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -850,171 +2106,226 @@ func getPorterAppRoutes(
 		},
 	)
 
-	defaultDeploymentTargetHandler := porter_app.NewDefaultDeploymentTargetHandler(
+	appJobStatusByNameHandler := porter_app.NewJobStatusByNameHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: defaultDeploymentTargetEndpoint,
-		Handler:  defaultDeploymentTargetHandler,
+		Endpoint: appJobStatusByNameEndpoint,
+		Handler:  appJobStatusByNameHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/latest -> porter_app.NewCurrentAppRevisionHandler
-	currentAppRevisionEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id} -> porter_app.NewGetAppRevisionHandler
+	getAppRevisionEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbGet,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/latest", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}", types.URLParamPorterAppName, types.URLParamAppRevisionID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get an app revision",
+				Response: porter_app.GetAppRevisionResponse{},
+			},
 		},
 	)
 
-	currentAppRevisionHandler := porter_app.NewLatestAppRevisionHandler(
+	getAppRevisionHandler := porter_app.NewGetAppRevisionHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: currentAppRevisionEndpoint,
-		Handler:  currentAppRevisionHandler,
+		Endpoint: getAppRevisionEndpoint,
+		Handler:  getAppRevisionHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/notifications -> porter_app.NewAppNotificationsHandler
-	appNotificationsEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/status -> porter_app.NewGetAppRevisionStatusHandler
+	getAppRevisionStatusEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbGet,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/notifications", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}/status", types.URLParamPorterAppName, types.URLParamAppRevisionID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get the status of an app revision",
+				Response: porter_app.GetAppRevisionStatusResponse{},
+			},
 		},
 	)
 
-	appNotificationsHandler := porter_app.NewAppNotificationsHandler(
+	getAppRevisionStatusHandler := porter_app.NewGetAppRevisionStatusHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appNotificationsEndpoint,
-		Handler:  appNotificationsHandler,
+		Endpoint: getAppRevisionStatusEndpoint,
+		Handler:  getAppRevisionStatusHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions -> porter_app.NewCurrentAppRevisionHandler
-	listAppRevisionsEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id} -> porter_app.NewUpdateAppRevisionStatusHandler
+	updateAppRevisionStatusEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}", types.URLParamPorterAppName, types.URLParamAppRevisionID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Update the status of an app revision",
+				Request:  porter_app.UpdateAppRevisionStatusRequest{},
+				Response: porter_app.UpdateAppRevisionStatusResponse{},
+			},
+		},
+	)
+
+	updateAppRevisionStatusHandler := porter_app.NewUpdateAppRevisionStatusHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateAppRevisionStatusEndpoint,
+		Handler:  updateAppRevisionStatusHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/build-env -> porter_app.NewGetBuildEnvHandler
+	getBuildEnvEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbGet,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/revisions", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}/build-env", types.URLParamPorterAppName, types.URLParamAppRevisionID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get the build environment of an app revision",
+				Response: porter_app.GetBuildEnvResponse{},
+			},
 		},
 	)
 
-	listAppRevisionsHandler := porter_app.NewListAppRevisionsHandler(
+	getBuildEnvHandler := porter_app.NewGetBuildEnvHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: listAppRevisionsEndpoint,
-		Handler:  listAppRevisionsHandler,
+		Endpoint: getBuildEnvEndpoint,
+		Handler:  getBuildEnvHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/update -> porter_app.UpdateAppHandler
-	updateAppEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/build-env -> porter_app.NewGetBuildFromRevisionHandler
+	getBuildFromRevisionEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/update", relPathV2),
+				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}/build", types.URLParamPorterAppName, types.URLParamAppRevisionID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get the build settings of an app revision",
+				Response: porter_app.GetBuildFromRevisionResponse{},
+			},
 		},
 	)
 
-	updateAppHandler := porter_app.NewUpdateAppHandler(
+	getBuildFromRevisionHandler := porter_app.NewGetBuildFromRevisionHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: updateAppEndpoint,
-		Handler:  updateAppHandler,
+		Endpoint: getBuildFromRevisionEndpoint,
+		Handler:  getBuildFromRevisionHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/build -> porter_app.NewUpdateAppBuildSettingsHandler
-	updateAppBuildSettingsEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/status -> porter_app.NewReportRevisionStatusHandler
+	reportRevisionStatusEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbUpdate,
 			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/build", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}/status", types.URLParamPorterAppName, types.URLParamAppRevisionID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Report the status of an app revision to external services",
+				Request:  porter_app.ReportRevisionStatusRequest{},
+				Response: porter_app.ReportRevisionStatusResponse{},
+			},
 		},
 	)
 
-	updateAppBuildSettingsHandler := porter_app.NewUpdateAppBuildSettingsHandler(
+	reportRevisionStatusHandler := porter_app.NewReportRevisionStatusHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: updateAppBuildSettingsEndpoint,
-		Handler:  updateAppBuildSettingsHandler,
+		Endpoint: reportRevisionStatusEndpoint,
+		Handler:  reportRevisionStatusHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/revisions -> porter_app.NewLatestAppRevisionsHandler
-	latestAppRevisionsEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/deploy-summary-comments -> porter_app.NewUpdateDeploySummaryCommentsHandler
+	updateDeploySummaryCommentsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/revisions", relPathV2),
+				RelativePath: fmt.Sprintf("/apps/{%s}/deploy-summary-comments", types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -1024,231 +2335,367 @@ func getPorterAppRoutes(
 		},
 	)
 
-	latestAppRevisionsHandler := porter_app.NewLatestAppRevisionsHandler(
+	updateDeploySummaryCommentsHandler := porter_app.NewUpdateDeploySummaryCommentsHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: latestAppRevisionsEndpoint,
-		Handler:  latestAppRevisionsHandler,
+		Endpoint: updateDeploySummaryCommentsEndpoint,
+		Handler:  updateDeploySummaryCommentsHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/instances -> porter_app.NewAppInstancesHandler
-	latestAppInstancesEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/inactivity-cleanup -> porter_app.NewUpdateInactivityCleanupHandler
+	updateInactivityCleanupEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/instances", relPathV2),
+				RelativePath: fmt.Sprintf("/apps/{%s}/inactivity-cleanup", types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Change how the inactivity policy of the project applies to an app",
+				Description: "Flags the app as ephemeral, which the policy applies to regardless of its name, or opts it out of the policy.",
+				Request:     types.UpdateAppInactivityCleanupRequest{},
+				Response:    types.PorterApp{},
+			},
 		},
 	)
 
-	latestAppInstancesHandler := porter_app.NewAppInstancesHandler(
+	updateInactivityCleanupHandler := porter_app.NewUpdateInactivityCleanupHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: latestAppInstancesEndpoint,
-		Handler:  latestAppInstancesHandler,
+		Endpoint: updateInactivityCleanupEndpoint,
+		Handler:  updateInactivityCleanupHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/subdomain -> porter_app.NewCreateSubdomainHandler
-	createSubdomainEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/tags -> porter_app.NewUpdatePorterAppTagsHandler
+	updatePorterAppTagsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbUpdate,
 			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/subdomain", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("/apps/{%s}/tags", types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Set the tags of an app",
+				Description: "Replaces the tags of the app and relabels its namespace with them, without deploying the app.",
+				Request:     types.UpdatePorterAppTagsRequest{},
+				Response:    types.PorterApp{},
+			},
 		},
 	)
 
-	createSubdomainHandler := porter_app.NewCreateSubdomainHandler(
+	updatePorterAppTagsHandler := porter_app.NewUpdatePorterAppTagsHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: createSubdomainEndpoint,
-		Handler:  createSubdomainHandler,
+		Endpoint: updatePorterAppTagsEndpoint,
+		Handler:  updatePorterAppTagsHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/{app_revision_id}/predeploy-status -> porter_app.NewPredeployStatusHandler
-	predeployStatusEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/deploy-webhook -> porter_app.NewCreateDeployWebhookTokenHandler
+	createDeployWebhookTokenEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/apps/{%s}/deploy-webhook", types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Generate the deploy webhook token of an app",
+				Description: "Generates a token for the deploy webhook of the app, which deploys the app with a new image tag. The token replaces any previous token of the app, and is only returned once.",
+				Response:    types.CreateDeployWebhookTokenResponse{},
+			},
+		},
+	)
+
+	createDeployWebhookTokenHandler := porter_app.NewCreateDeployWebhookTokenHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createDeployWebhookTokenEndpoint,
+		Handler:  createDeployWebhookTokenHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/deploy-webhook -> porter_app.NewRevokeDeployWebhookTokenHandler
+	revokeDeployWebhookTokenEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/apps/{%s}/deploy-webhook", types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Revoke the deploy webhook token of an app",
+				Description: "Revokes the token of the deploy webhook of the app, so that the webhook no longer deploys it.",
+				Response:    types.PorterApp{},
+			},
+		},
+	)
+
+	revokeDeployWebhookTokenHandler := porter_app.NewRevokeDeployWebhookTokenHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: revokeDeployWebhookTokenEndpoint,
+		Handler:  revokeDeployWebhookTokenHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/domains -> porter_app.NewListPorterAppDomainsHandler
+	listPorterAppDomainsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbGet,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/{%s}/predeploy-status", relPathV2, types.URLParamPorterAppName, types.URLParamAppRevisionID),
+				RelativePath: fmt.Sprintf("/apps/{%s}/domains", types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "List the domains of an app",
+				Description: "Returns the certificate serving each domain of the app as of its last check, and where alerts about expiring certificates are sent.",
+				Response:    types.PorterAppDomainsResponse{},
+			},
 		},
 	)
 
-	predeployStatusHandler := porter_app.NewPredeployStatusHandler(
+	listPorterAppDomainsHandler := porter_app.NewListPorterAppDomainsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listPorterAppDomainsEndpoint,
+		Handler:  listPorterAppDomainsHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/domains/recheck -> porter_app.NewRecheckPorterAppDomainsHandler
+	recheckPorterAppDomainsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/apps/{%s}/domains/recheck", types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Check the certificates of the domains of an app",
+				Description: "Reads the certificate serving each domain of the app without waiting for the next scheduled check, such as after renewing a certificate.",
+				Response:    types.PorterAppDomainsResponse{},
+			},
+		},
+	)
+
+	recheckPorterAppDomainsHandler := porter_app.NewRecheckPorterAppDomainsHandler(
 		config,
-		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: predeployStatusEndpoint,
-		Handler:  predeployStatusHandler,
+		Endpoint: recheckPorterAppDomainsEndpoint,
+		Handler:  recheckPorterAppDomainsHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/logs -> cluster.NewAppLogsHandler
-	appLogsEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/domains/alerts -> porter_app.NewUpdatePorterAppCertAlertsHandler
+	updatePorterAppCertAlertsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/logs", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("/apps/{%s}/domains/alerts", types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Change where alerts about the certificates of an app are sent",
+				Description: "Sends alerts about expiring certificates of the domains of the app to the project's Slack integrations, to a webhook, or only to the app's activity feed.",
+				Request:     types.UpdateCertAlertsRequest{},
+				Response:    types.PorterAppDomainsResponse{},
+			},
 		},
 	)
 
-	appLogsHandler := porter_app.NewAppLogsHandler(
+	updatePorterAppCertAlertsHandler := porter_app.NewUpdatePorterAppCertAlertsHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appLogsEndpoint,
-		Handler:  appLogsHandler,
+		Endpoint: updatePorterAppCertAlertsEndpoint,
+		Handler:  updatePorterAppCertAlertsHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/logs/loki -> namespace.NewStreamLogsLokiHandler
-	streamLogsLokiEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/scaling-schedule -> porter_app.NewUpdateScalingScheduleHandler
+	updateScalingScheduleEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/logs/loki", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("/apps/{%s}/scaling-schedule", types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
-			IsWebsocket: true,
+			Schema: &types.APISchema{
+				Summary:     "Pause or resume the scaling schedules of an app",
+				Description: "Paused schedules leave the services of the app at the replicas they have. Resumed schedules scale every service to the replicas of its schedule again.",
+				Request:     types.UpdateScalingScheduleRequest{},
+				Response:    types.PorterApp{},
+			},
 		},
 	)
 
-	streamLogsLokiHandler := porter_app.NewStreamLogsLokiHandler(
+	updateScalingScheduleHandler := porter_app.NewUpdateScalingScheduleHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: streamLogsLokiEndpoint,
-		Handler:  streamLogsLokiHandler,
+		Endpoint: updateScalingScheduleEndpoint,
+		Handler:  updateScalingScheduleHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/metrics -> cluster.NewGetPodMetricsHandler
-	appMetricsEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/scale -> porter_app.NewScalePorterAppHandler
+	scalePorterAppEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/metrics", relPathV2),
+				RelativePath: fmt.Sprintf("/apps/{%s}/scale", types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Scale the services of an app",
+				Description: "Sets the replicas of services of the app without deploying its porter.yaml, by changing only their replicas in the values of the latest release. Autoscaled services are refused unless adjust_autoscaling is set. The scale is recorded in the activity feed of the app, and the next deploy warns if its porter.yaml sets other replicas.",
+				Request:     types.ScaleAppRequest{},
+				Response:    types.ScaleAppResponse{},
+			},
 		},
 	)
 
-	appMetricsHandler := porter_app.NewAppMetricsHandler(
+	scalePorterAppHandler := porter_app.NewScalePorterAppHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appMetricsEndpoint,
-		Handler:  appMetricsHandler,
+		Endpoint: scalePorterAppEndpoint,
+		Handler:  scalePorterAppHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/status -> cluster.NewAppStatusHandler
-	appStatusEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/update-environment -> porter_app.NewUpdateAppEnvironmentHandler
+	updateAppEnvironmentGroupEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/status", relPathV2, types.URLParamKind),
+				RelativePath: fmt.Sprintf("/apps/{%s}/update-environment", types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
-			IsWebsocket: true,
+			Schema: &types.APISchema{
+				Summary:  "Create or update the environment group of an app",
+				Request:  porter_app.UpdateAppEnvironmentRequest{},
+				Response: porter_app.UpdateAppEnvironmentResponse{},
+			},
 		},
 	)
 
-	appStatusHandler := porter_app.NewAppStatusHandler(
+	updateAppEnvironmentGroupHandler := porter_app.NewUpdateAppEnvironmentHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appStatusEndpoint,
-		Handler:  appStatusHandler,
+		Endpoint: updateAppEnvironmentGroupEndpoint,
+		Handler:  updateAppEnvironmentGroupHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/service_status -> cluster.NewAppServiceStatusHandler
-	appServiceStatusEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/env -> porter_app.NewGetAppEnvHandler
+	getAppEnvEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbGet,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/service_status", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}/env", types.URLParamPorterAppName, types.URLParamAppRevisionID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -1258,26 +2705,26 @@ func getPorterAppRoutes(
 		},
 	)
 
-	appServiceStatusHandler := porter_app.NewServiceStatusHandler(
+	getAppEnvHandler := porter_app.NewGetAppEnvHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appServiceStatusEndpoint,
-		Handler:  appServiceStatusHandler,
+		Endpoint: getAppEnvEndpoint,
+		Handler:  getAppEnvHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/jobs -> cluster.NewJobStatusHandler
-	appJobStatusEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/events -> porter_app.NewPorterAppV2EventListHandler
+	listPorterAppV2EventsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
+			Verb:   types.APIVerbList,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/jobs", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/events", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -1287,84 +2734,96 @@ func getPorterAppRoutes(
 		},
 	)
 
-	appJobStatusHandler := porter_app.NewJobStatusHandler(
+	listPorterAppV2EventsHandler := porter_app.NewPorterAppV2EventListHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appJobStatusEndpoint,
-		Handler:  appJobStatusHandler,
+		Endpoint: listPorterAppV2EventsEndpoint,
+		Handler:  listPorterAppV2EventsHandler,
 		Router:   r,
 	})
 
-	// GET  /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/jobs/{job_run_name} -> porter_app.JobStatusByNameHandler
-	appJobStatusByNameEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/activity -> porter_app.NewListPorterAppEventsHandler
+	listFilteredPorterAppEventsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
+			Verb:   types.APIVerbList,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/jobs/{%s}", relPathV2, types.URLParamPorterAppName, types.URLParamJobRunName),
+				RelativePath: fmt.Sprintf("%s/{%s}/activity", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "List the events of an app",
+				Description: "Lists the events of an app newest first, optionally only those of a type such as DEPLOY or with a status such as FAILED. Pages are selected with limit and offset, and the response includes the number of matching events across every page.",
+				Request:     types.ListFilteredPorterAppEventsRequest{},
+				Response:    types.ListFilteredPorterAppEventsResponse{},
+			},
 		},
 	)
 
-	appJobStatusByNameHandler := porter_app.NewJobStatusByNameHandler(
+	listFilteredPorterAppEventsHandler := porter_app.NewListPorterAppEventsHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appJobStatusByNameEndpoint,
-		Handler:  appJobStatusByNameHandler,
+		Endpoint: listFilteredPorterAppEventsEndpoint,
+		Handler:  listFilteredPorterAppEventsHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id} -> porter_app.NewGetAppRevisionHandler
-	getAppRevisionEndpoint := factory.NewAPIEndpoint(
+	// PATCH /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/events/{porter_app_event_id} -> porter_app.NewUpdatePorterAppEventHandler
+	updatePorterAppEventEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPatch,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}", types.URLParamPorterAppName, types.URLParamAppRevisionID),
+				RelativePath: fmt.Sprintf("%s/{%s}/events/{%s}", relPathV2, types.URLParamPorterAppName, types.URLParamPorterAppEventID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Update an event of an app",
+				Description: "Moves a PROGRESSING event, such as a build or pre-deploy job, to SUCCESS or FAILED, and merges the metadata of the request into that of the event. Finished events keep their status.",
+				Request:     types.UpdatePorterAppEventRequest{},
+				Response:    types.PorterAppEvent{},
+			},
 		},
 	)
 
-	getAppRevisionHandler := porter_app.NewGetAppRevisionHandler(
+	updatePorterAppEventHandler := porter_app.NewUpdatePorterAppEventHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: getAppRevisionEndpoint,
-		Handler:  getAppRevisionHandler,
+		Endpoint: updatePorterAppEventEndpoint,
+		Handler:  updatePorterAppEventHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/status -> porter_app.NewGetAppRevisionStatusHandler
-	getAppRevisionStatusEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/templates -> porter_app.NewGetAppTemplateHandler
+	getAppTemplateEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbGet,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}/status", types.URLParamPorterAppName, types.URLParamAppRevisionID),
+				RelativePath: fmt.Sprintf("%s/{%s}/templates", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -1374,26 +2833,26 @@ func getPorterAppRoutes(
 		},
 	)
 
-	getAppRevisionStatusHandler := porter_app.NewGetAppRevisionStatusHandler(
+	getAppTemplateHandler := porter_app.NewGetAppTemplateHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: getAppRevisionStatusEndpoint,
-		Handler:  getAppRevisionStatusHandler,
+		Endpoint: getAppTemplateEndpoint,
+		Handler:  getAppTemplateHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id} -> porter_app.NewUpdateAppRevisionStatusHandler
-	updateAppRevisionStatusEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/templates -> porter_app.NewCreateAppTemplateHandler
+	createAppTemplateEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
+			Verb:   types.APIVerbCreate,
 			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}", types.URLParamPorterAppName, types.URLParamAppRevisionID),
+				RelativePath: fmt.Sprintf("%s/{%s}/templates", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -1403,26 +2862,26 @@ func getPorterAppRoutes(
 		},
 	)
 
-	updateAppRevisionStatusHandler := porter_app.NewUpdateAppRevisionStatusHandler(
+	createAppTemplateHandler := porter_app.NewCreateAppTemplateHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: updateAppRevisionStatusEndpoint,
-		Handler:  updateAppRevisionStatusHandler,
+		Endpoint: createAppTemplateEndpoint,
+		Handler:  createAppTemplateHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/build-env -> porter_app.NewGetBuildEnvHandler
-	getBuildEnvEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/helm-values -> porter_app.NewAppHelmValuesHandler
+	appHelmValuesEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
 			Verb:   types.APIVerbGet,
 			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}/build-env", types.URLParamPorterAppName, types.URLParamAppRevisionID),
+				RelativePath: fmt.Sprintf("%s/{%s}/helm-values", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -1432,84 +2891,95 @@ func getPorterAppRoutes(
 		},
 	)
 
-	getBuildEnvHandler := porter_app.NewGetBuildEnvHandler(
+	appHelmValuesHandler := porter_app.NewAppHelmValuesHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: getBuildEnvEndpoint,
-		Handler:  getBuildEnvHandler,
+		Endpoint: appHelmValuesEndpoint,
+		Handler:  appHelmValuesHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/build-env -> porter_app.NewGetBuildFromRevisionHandler
-	getBuildFromRevisionEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{app_name}/run -> porter_app.NewRunAppJobHandler
+	runAppJobEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}/build", types.URLParamPorterAppName, types.URLParamAppRevisionID),
+				RelativePath: fmt.Sprintf("%s/{%s}/run", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Run a job of an app",
+				Request:  porter_app.RunAppJobRequest{},
+				Response: porter_app.RunAppJobResponse{},
+			},
 		},
 	)
 
-	getBuildFromRevisionHandler := porter_app.NewGetBuildFromRevisionHandler(
+	runAppJobHandler := porter_app.NewRunAppJobHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: getBuildFromRevisionEndpoint,
-		Handler:  getBuildFromRevisionHandler,
+		Endpoint: runAppJobEndpoint,
+		Handler:  runAppJobHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/status -> porter_app.NewReportRevisionStatusHandler
-	reportRevisionStatusEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{app_name}/run-status -> porter_app.NewAppJobRunStatusHandler
+	appJobRunStatusEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}/status", types.URLParamPorterAppName, types.URLParamAppRevisionID),
+				RelativePath: fmt.Sprintf("%s/{%s}/run-status", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get the status of a job run of an app",
+				Request:  porter_app.AppJobRunStatusRequest{},
+				Response: porter_app.AppJobRunStatusResponse{},
+			},
 		},
 	)
 
-	reportRevisionStatusHandler := porter_app.NewReportRevisionStatusHandler(
+	appJobRunStatusHandler := porter_app.NewAppJobRunStatusHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: reportRevisionStatusEndpoint,
-		Handler:  reportRevisionStatusHandler,
+		Endpoint: appJobRunStatusEndpoint,
+		Handler:  appJobRunStatusHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/update-environment -> porter_app.NewUpdateAppEnvironmentHandler
-	updateAppEnvironmentGroupEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{app_name}/alert-rules -> porter_app.NewListLogAlertRulesHandler
+	listLogAlertRulesEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/apps/{%s}/update-environment", types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/alert-rules", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -1519,26 +2989,25 @@ func getPorterAppRoutes(
 		},
 	)
 
-	updateAppEnvironmentGroupHandler := porter_app.NewUpdateAppEnvironmentHandler(
+	listLogAlertRulesHandler := porter_app.NewListLogAlertRulesHandler(
 		config,
-		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: updateAppEnvironmentGroupEndpoint,
-		Handler:  updateAppEnvironmentGroupHandler,
+		Endpoint: listLogAlertRulesEndpoint,
+		Handler:  listLogAlertRulesHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/env -> porter_app.NewGetAppEnvHandler
-	getAppEnvEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{app_name}/alert-rules -> porter_app.NewCreateLogAlertRuleHandler
+	createLogAlertRuleEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("/apps/{%s}/revisions/{%s}/env", types.URLParamPorterAppName, types.URLParamAppRevisionID),
+				RelativePath: fmt.Sprintf("%s/{%s}/alert-rules", relPathV2, types.URLParamPorterAppName),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -1548,26 +3017,26 @@ func getPorterAppRoutes(
 		},
 	)
 
-	getAppEnvHandler := porter_app.NewGetAppEnvHandler(
+	createLogAlertRuleHandler := porter_app.NewCreateLogAlertRuleHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: getAppEnvEndpoint,
-		Handler:  getAppEnvHandler,
+		Endpoint: createLogAlertRuleEndpoint,
+		Handler:  createLogAlertRuleHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/events -> porter_app.NewPorterAppV2EventListHandler
-	listPorterAppV2EventsEndpoint := factory.NewAPIEndpoint(
+	// PATCH /api/projects/{project_id}/clusters/{cluster_id}/apps/{app_name}/alert-rules/{log_alert_rule_id} -> porter_app.NewUpdateLogAlertRuleHandler
+	updateLogAlertRuleEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbList,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPatch,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/events", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/alert-rules/{%s}", relPathV2, types.URLParamPorterAppName, types.URLParamLogAlertRuleID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -1577,26 +3046,26 @@ func getPorterAppRoutes(
 		},
 	)
 
-	listPorterAppV2EventsHandler := porter_app.NewPorterAppV2EventListHandler(
+	updateLogAlertRuleHandler := porter_app.NewUpdateLogAlertRuleHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: listPorterAppV2EventsEndpoint,
-		Handler:  listPorterAppV2EventsHandler,
+		Endpoint: updateLogAlertRuleEndpoint,
+		Handler:  updateLogAlertRuleHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/templates -> porter_app.NewGetAppTemplateHandler
-	getAppTemplateEndpoint := factory.NewAPIEndpoint(
+	// DELETE /api/projects/{project_id}/clusters/{cluster_id}/apps/{app_name}/alert-rules/{log_alert_rule_id} -> porter_app.NewDeleteLogAlertRuleHandler
+	deleteLogAlertRuleEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/templates", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/alert-rules/{%s}", relPathV2, types.URLParamPorterAppName, types.URLParamLogAlertRuleID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
@@ -1606,131 +3075,149 @@ func getPorterAppRoutes(
 		},
 	)
 
-	getAppTemplateHandler := porter_app.NewGetAppTemplateHandler(
+	deleteLogAlertRuleHandler := porter_app.NewDeleteLogAlertRuleHandler(
 		config,
-		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: getAppTemplateEndpoint,
-		Handler:  getAppTemplateHandler,
+		Endpoint: deleteLogAlertRuleEndpoint,
+		Handler:  deleteLogAlertRuleHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/templates -> porter_app.NewCreateAppTemplateHandler
-	createAppTemplateEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{app_name}/alert-rules/{log_alert_rule_id}/deliveries -> porter_app.NewListWebhookDeliveriesHandler
+	listWebhookDeliveriesEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/templates", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/alert-rules/{%s}/deliveries", relPathV2, types.URLParamPorterAppName, types.URLParamLogAlertRuleID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the webhook deliveries of a log alert rule",
+				Request:  types.ListWebhookDeliveriesRequest{},
+				Response: types.ListWebhookDeliveriesResponse{},
+			},
 		},
 	)
 
-	createAppTemplateHandler := porter_app.NewCreateAppTemplateHandler(
+	listWebhookDeliveriesHandler := porter_app.NewListWebhookDeliveriesHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: createAppTemplateEndpoint,
-		Handler:  createAppTemplateHandler,
+		Endpoint: listWebhookDeliveriesEndpoint,
+		Handler:  listWebhookDeliveriesHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/helm-values -> porter_app.NewAppHelmValuesHandler
-	appHelmValuesEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{app_name}/alert-rules/{log_alert_rule_id}/deliveries/{webhook_delivery_id}/redeliver -> porter_app.NewRedeliverWebhookHandler
+	redeliverWebhookEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/helm-values", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/{%s}/alert-rules/{%s}/deliveries/{%s}/redeliver", relPathV2, types.URLParamPorterAppName, types.URLParamLogAlertRuleID, types.URLParamWebhookDeliveryID),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Redeliver a webhook delivery of a log alert rule",
+				Response: types.WebhookDelivery{},
+			},
+			Timeout: types.TimeoutClassLong,
 		},
 	)
 
-	appHelmValuesHandler := porter_app.NewAppHelmValuesHandler(
+	redeliverWebhookHandler := porter_app.NewRedeliverWebhookHandler(
 		config,
-		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appHelmValuesEndpoint,
-		Handler:  appHelmValuesHandler,
+		Endpoint: redeliverWebhookEndpoint,
+		Handler:  redeliverWebhookHandler,
 		Router:   r,
 	})
 
-	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{app_name}/run -> porter_app.NewRunAppJobHandler
-	runAppJobEndpoint := factory.NewAPIEndpoint(
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/import/releases -> porter_app.NewListImportableReleasesHandler
+	listImportableReleasesEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPost,
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/run", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/import/releases", relPathV2),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the helm releases which can be imported as apps",
+				Request:  porter_app.ListImportableReleasesRequest{},
+				Response: porter_app.ListImportableReleasesResponse{},
+			},
 		},
 	)
 
-	runAppJobHandler := porter_app.NewRunAppJobHandler(
+	listImportableReleasesHandler := porter_app.NewListImportableReleasesHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: runAppJobEndpoint,
-		Handler:  runAppJobHandler,
+		Endpoint: listImportableReleasesEndpoint,
+		Handler:  listImportableReleasesHandler,
 		Router:   r,
 	})
 
-	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{app_name}/run-status -> porter_app.NewAppJobRunStatusHandler
-	appJobRunStatusEndpoint := factory.NewAPIEndpoint(
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/import -> porter_app.NewImportReleaseHandler
+	importReleaseEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbGet,
-			Method: types.HTTPVerbGet,
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
 			Path: &types.Path{
 				Parent:       basePath,
-				RelativePath: fmt.Sprintf("%s/{%s}/run-status", relPathV2, types.URLParamPorterAppName),
+				RelativePath: fmt.Sprintf("%s/import", relPathV2),
 			},
 			Scopes: []types.PermissionScope{
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Import a helm release as an app",
+				Request:  porter_app.ImportReleaseRequest{},
+				Response: porter_app.ImportReleaseResponse{},
+			},
 		},
 	)
 
-	appJobRunStatusHandler := porter_app.NewAppJobRunStatusHandler(
+	importReleaseHandler := porter_app.NewImportReleaseHandler(
 		config,
 		factory.GetDecoderValidator(),
 		factory.GetResultWriter(),
 	)
 
 	routes = append(routes, &router.Route{
-		Endpoint: appJobRunStatusEndpoint,
-		Handler:  appJobRunStatusHandler,
+		Endpoint: importReleaseEndpoint,
+		Handler:  importReleaseHandler,
 		Router:   r,
 	})
 
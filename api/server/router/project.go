@@ -78,6 +78,10 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get a project",
+				Response: types.ReadProjectResponse{},
+			},
 		},
 	)
 
@@ -105,6 +109,9 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Delete a project",
+			},
 		},
 	)
 
@@ -285,6 +292,131 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/warnings -> project.NewListProjectWarningsHandler
+	listProjectWarningsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/warnings",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "List the warnings of a project",
+				Description: "Lists the problems with the project which need the attention of its users, such as the load balancers and domains of deleted apps which were not released before the deadline of their cleanup.",
+				Response:    types.ListProjectWarningsResponse{},
+			},
+		},
+	)
+
+	listProjectWarningsHandler := project.NewListProjectWarningsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listProjectWarningsEndpoint,
+		Handler:  listProjectWarningsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/usage/report -> project.NewProjectGetUsageReportHandler
+	getUsageReportEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/usage/report",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getUsageReportHandler := project.NewProjectGetUsageReportHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getUsageReportEndpoint,
+		Handler:  getUsageReportHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/applications/redeploy -> project.NewCreateBulkRedeployHandler
+	createBulkRedeployEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/applications/redeploy",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Redeploy the apps of a project which match a filter",
+				Description: "The apps which match the filter are resolved before responding, and redeployed in the background with at most the given number of concurrent redeploys per cluster. A dry run only returns the apps which would be redeployed.",
+				Request:     types.CreateBulkRedeployRequest{},
+				Response:    types.BulkRedeploy{},
+			},
+		},
+	)
+
+	createBulkRedeployHandler := project.NewCreateBulkRedeployHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createBulkRedeployEndpoint,
+		Handler:  createBulkRedeployHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/applications/redeploy/{bulk_redeploy_id} -> project.NewGetBulkRedeployHandler
+	getBulkRedeployEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/applications/redeploy/{%s}", relPath, types.URLParamBulkRedeployID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Get the progress of a bulk redeploy",
+				Response: types.BulkRedeploy{},
+			},
+		},
+	)
+
+	getBulkRedeployHandler := project.NewGetBulkRedeployHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getBulkRedeployEndpoint,
+		Handler:  getBulkRedeployHandler,
+		Router:   r,
+	})
+
 	// GET /api/project/{project_id}/billing/redirect -> billing.NewRedirectBillingHandler
 	redirectBillingEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -376,6 +508,10 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the clusters of a project",
+				Response: types.ListClusterResponse{},
+			},
 		},
 	)
 
@@ -403,6 +539,10 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the GitHub app installations of a project",
+				Response: types.ListGitInstallationIDsResponse{},
+			},
 		},
 	)
 
@@ -540,6 +680,10 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Create a proxy to connect to a datastore",
+				Response: types.CreateDatastoreProxyResponse{},
+			},
 		},
 	)
 
@@ -652,6 +796,10 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the registries of a project",
+				Response: types.RegistryListResponse{},
+			},
 		},
 	)
 
@@ -666,6 +814,38 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/registries/credential-status -> registry.NewRegistryCredentialStatusListHandler
+	listRegistryCredentialStatusEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/registries/credential-status",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "List the credential status of the registries of a project",
+				Description: "Registries whose credentials last failed to generate a pull secret are skipped by deploys, unless the image being deployed is pulled from them.",
+				Response:    types.ListRegistryCredentialStatusResponse{},
+			},
+		},
+	)
+
+	listRegistryCredentialStatusHandler := registry.NewRegistryCredentialStatusListHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listRegistryCredentialStatusEndpoint,
+		Handler:  listRegistryCredentialStatusHandler,
+		Router:   r,
+	})
+
 	// POST /api/projects/{project_id}/registries -> registry.NewRegistryCreateHandler
 	createRegistryEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -679,6 +859,11 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Connect a registry to a project",
+				Request:  types.CreateRegistryRequest{},
+				Response: types.Registry{},
+			},
 		},
 	)
 
@@ -707,6 +892,11 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get an authorization token for an ECR registry",
+				Request:  types.GetRegistryECRTokenRequest{},
+				Response: types.GetRegistryTokenResponse{},
+			},
 		},
 	)
 
@@ -735,6 +925,11 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get an authorization token for a DOCR registry",
+				Request:  types.GetRegistryDOCRTokenRequest{},
+				Response: types.GetRegistryTokenResponse{},
+			},
 		},
 	)
 
@@ -763,6 +958,11 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get an authorization token for a GCR registry",
+				Request:  types.GetRegistryGCRTokenRequest{},
+				Response: types.GetRegistryTokenResponse{},
+			},
 		},
 	)
 
@@ -791,6 +991,11 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get an authorization token for a GAR registry",
+				Request:  types.GetRegistryGCRTokenRequest{},
+				Response: types.GetRegistryTokenResponse{},
+			},
 		},
 	)
 
@@ -819,6 +1024,11 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get an authorization token for an ACR registry",
+				Request:  types.GetRegistryACRTokenRequest{},
+				Response: types.GetRegistryTokenResponse{},
+			},
 		},
 	)
 
@@ -847,6 +1057,10 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get an authorization token for Docker Hub",
+				Response: types.GetRegistryTokenResponse{},
+			},
 		},
 	)
 
@@ -1363,6 +1577,11 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Add a helm repo to a project",
+				Request:  types.CreateUpdateHelmRepoRequest{},
+				Response: types.HelmRepo{},
+			},
 		},
 	)
 
@@ -1391,6 +1610,10 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the helm repos of a project",
+				Response: []*types.HelmRepo{},
+			},
 		},
 	)
 
@@ -1432,6 +1655,39 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/audit-log -> project.NewListAuditLogHandler
+	listAuditLogEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/audit-log",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "List the audit log of a project",
+				Request:  types.ListAuditLogRequest{},
+				Response: types.ListAuditLogResponse{},
+			},
+		},
+	)
+
+	listAuditLogHandler := project.NewListAuditLogHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listAuditLogEndpoint,
+		Handler:  listAuditLogHandler,
+		Router:   r,
+	})
+
 	// POST /api/projects/{project_id}/tags -> project.NewCreateTagHandler
 	createTagEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -1584,6 +1840,12 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:     "Rename a project or set its default cluster",
+				Description: "The CLI uses the default cluster when no cluster is configured. Fields which are omitted are left unchanged.",
+				Request:     types.UpdateProjectNameRequest{},
+				Response:    types.Project{},
+			},
 		},
 	)
 
@@ -1599,6 +1861,582 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/deploy-summary-comments -> project.NewGetDeploySummaryCommentSettingsHandler
+	getDeploySummaryCommentSettingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/deploy-summary-comments",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getDeploySummaryCommentSettingsHandler := project.NewGetDeploySummaryCommentSettingsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getDeploySummaryCommentSettingsEndpoint,
+		Handler:  getDeploySummaryCommentSettingsHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/deploy-summary-comments -> project.NewUpdateDeploySummaryCommentSettingsHandler
+	updateDeploySummaryCommentSettingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/deploy-summary-comments",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	updateDeploySummaryCommentSettingsHandler := project.NewUpdateDeploySummaryCommentSettingsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateDeploySummaryCommentSettingsEndpoint,
+		Handler:  updateDeploySummaryCommentSettingsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/observability -> project.NewGetObservabilityConfigHandler
+	getObservabilityConfigEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/observability",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Get the observability config of a project",
+				Response: types.ProjectObservabilityConfig{},
+			},
+		},
+	)
+
+	getObservabilityConfigHandler := project.NewGetObservabilityConfigHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getObservabilityConfigEndpoint,
+		Handler:  getObservabilityConfigHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/observability -> project.NewUpdateObservabilityConfigHandler
+	updateObservabilityConfigEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/observability",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Set the observability config of a project",
+				Description: "The OpenTelemetry environment variables for the config are injected into every service of the project's apps, starting with their next deploy.",
+				Request:     types.UpdateProjectObservabilityConfigRequest{},
+				Response:    types.ProjectObservabilityConfig{},
+			},
+		},
+	)
+
+	updateObservabilityConfigHandler := project.NewUpdateObservabilityConfigHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateObservabilityConfigEndpoint,
+		Handler:  updateObservabilityConfigHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/observability -> project.NewDeleteObservabilityConfigHandler
+	deleteObservabilityConfigEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/observability",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Remove the observability config of a project",
+				Description: "The OpenTelemetry environment variables are no longer injected into the project's apps, starting with their next deploy.",
+			},
+		},
+	)
+
+	deleteObservabilityConfigHandler := project.NewDeleteObservabilityConfigHandler(
+		config,
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: deleteObservabilityConfigEndpoint,
+		Handler:  deleteObservabilityConfigHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/inactivity-policy -> project.NewGetInactivityPolicyHandler
+	getInactivityPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/inactivity-policy",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Get the inactivity policy of a project",
+				Response: types.InactivityPolicy{},
+			},
+		},
+	)
+
+	getInactivityPolicyHandler := project.NewGetInactivityPolicyHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getInactivityPolicyEndpoint,
+		Handler:  getInactivityPolicyHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/inactivity-policy -> project.NewUpdateInactivityPolicyHandler
+	updateInactivityPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/inactivity-policy",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Set the inactivity policy of a project",
+				Description: "Apps which are flagged as ephemeral or whose name matches the pattern are paused once they have not been deployed for the idle days, and deleted once they have stayed paused for the grace days.",
+				Request:     types.UpdateInactivityPolicyRequest{},
+				Response:    types.InactivityPolicy{},
+			},
+		},
+	)
+
+	updateInactivityPolicyHandler := project.NewUpdateInactivityPolicyHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateInactivityPolicyEndpoint,
+		Handler:  updateInactivityPolicyHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/inactivity-policy -> project.NewDeleteInactivityPolicyHandler
+	deleteInactivityPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/inactivity-policy",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Remove the inactivity policy of a project",
+				Description: "Apps which were paused by the policy stay paused until they are next deployed, but are no longer deleted.",
+			},
+		},
+	)
+
+	deleteInactivityPolicyHandler := project.NewDeleteInactivityPolicyHandler(
+		config,
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: deleteInactivityPolicyEndpoint,
+		Handler:  deleteInactivityPolicyHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/inactivity-policy/report -> project.NewGetInactivityReportHandler
+	getInactivityReportEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/inactivity-policy/report",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "List the apps the inactivity policy of a project applies to",
+				Description: "Each app is listed with the date it will be paused and deleted unless it is deployed or opted out.",
+				Response:    types.InactivityReport{},
+			},
+		},
+	)
+
+	getInactivityReportHandler := project.NewGetInactivityReportHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getInactivityReportEndpoint,
+		Handler:  getInactivityReportHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/app-grants -> project.NewListAppGrantsHandler
+	listAppGrantsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/app-grants",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "List the app grants of a project",
+				Response: types.ListPorterAppGrantsResponse{},
+			},
+		},
+	)
+
+	listAppGrantsHandler := project.NewListAppGrantsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listAppGrantsEndpoint,
+		Handler:  listAppGrantsHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/app-grants -> project.NewCreateAppGrantHandler
+	createAppGrantEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/app-grants",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Grant a user or a role access to the apps of a project",
+				Description: "The grant applies to the apps whose name matches its pattern, where * matches any part of a name. Grants are only enforced once scoped app access is turned on.",
+				Request:     types.CreatePorterAppGrantRequest{},
+				Response:    types.PorterAppGrant{},
+			},
+		},
+	)
+
+	createAppGrantHandler := project.NewCreateAppGrantHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createAppGrantEndpoint,
+		Handler:  createAppGrantHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/app-grants/{porter_app_grant_id} -> project.NewDeleteAppGrantHandler
+	deleteAppGrantEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/app-grants/{%s}", relPath, types.URLParamPorterAppGrantID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "Revoke an app grant",
+				Response: types.PorterAppGrant{},
+			},
+		},
+	)
+
+	deleteAppGrantHandler := project.NewDeleteAppGrantHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: deleteAppGrantEndpoint,
+		Handler:  deleteAppGrantHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/app-grants/settings -> project.NewUpdateAppAccessSettingsHandler
+	updateAppAccessSettingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/app-grants/settings",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Turn scoped app access on or off for a project",
+				Description: "With scoped app access on, members who are not admins need a read grant to view an app, and a deploy grant to deploy, roll back or delete it.",
+				Request:     types.UpdateAppAccessSettingsRequest{},
+				Response:    types.Project{},
+			},
+		},
+	)
+
+	updateAppAccessSettingsHandler := project.NewUpdateAppAccessSettingsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateAppAccessSettingsEndpoint,
+		Handler:  updateAppAccessSettingsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/secrets-providers -> project.NewListSecretsProvidersHandler
+	listSecretsProvidersEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/secrets-providers",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+			Schema: &types.APISchema{
+				Summary:  "List the external secret stores of a project",
+				Response: []types.SecretsProviderIntegration{},
+			},
+		},
+	)
+
+	listSecretsProvidersHandler := project.NewListSecretsProvidersHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listSecretsProvidersEndpoint,
+		Handler:  listSecretsProvidersHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/secrets-providers -> project.NewUpdateSecretsProviderHandler
+	updateSecretsProviderEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/secrets-providers",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Set an external secret store of a project",
+				Description: "Env variables in porter.yaml which reference the store with valueFrom are read from it each time the app is deployed. Credentials which are left empty keep their saved value.",
+				Request:     types.UpdateSecretsProviderRequest{},
+				Response:    types.SecretsProviderIntegration{},
+			},
+		},
+	)
+
+	updateSecretsProviderHandler := project.NewUpdateSecretsProviderHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateSecretsProviderEndpoint,
+		Handler:  updateSecretsProviderHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/secrets-providers/test -> project.NewTestSecretsProviderHandler
+	testSecretsProviderEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/secrets-providers/test",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Test the connection to an external secret store",
+				Description: "The config is not saved. Credentials which are left empty are taken from the saved config of the provider.",
+				Request:     types.UpdateSecretsProviderRequest{},
+				Response:    types.TestSecretsProviderResponse{},
+			},
+		},
+	)
+
+	testSecretsProviderHandler := project.NewTestSecretsProviderHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: testSecretsProviderEndpoint,
+		Handler:  testSecretsProviderHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/secrets-providers/{secrets_provider} -> project.NewDeleteSecretsProviderHandler
+	deleteSecretsProviderEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/secrets-providers/{%s}", relPath, types.URLParamSecretsProvider),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Remove an external secret store of a project",
+				Description: "Apps which reference the store keep the values they were last deployed with, but cannot be deployed again until the references are removed.",
+			},
+		},
+	)
+
+	deleteSecretsProviderHandler := project.NewDeleteSecretsProviderHandler(
+		config,
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: deleteSecretsProviderEndpoint,
+		Handler:  deleteSecretsProviderHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/cache -> project.NewInvalidateCacheHandler
+	invalidateCacheEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/cache",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	invalidateCacheHandler := project.NewInvalidateCacheHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: invalidateCacheEndpoint,
+		Handler:  invalidateCacheHandler,
+		Router:   r,
+	})
+
 	// GET /api/projects/{project_id}/images -> project.ImagesHandler
 	imagesEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
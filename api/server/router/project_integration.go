@@ -68,6 +68,10 @@ func getProjectIntegrationRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the OAuth integrations of a project",
+				Response: types.ListOAuthResponse{},
+			},
 		},
 	)
 
@@ -122,6 +126,11 @@ func getProjectIntegrationRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Create a basic auth integration",
+				Request:  types.CreateBasicRequest{},
+				Response: types.CreateBasicResponse{},
+			},
 		},
 	)
 
@@ -150,6 +159,11 @@ func getProjectIntegrationRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Create an AWS integration",
+				Request:  types.CreateAWSRequest{},
+				Response: types.CreateAWSResponse{},
+			},
 		},
 	)
 
@@ -316,6 +330,11 @@ func getProjectIntegrationRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Create a GCP integration",
+				Request:  types.CreateGCPRequest{},
+				Response: types.CreateGCPResponse{},
+			},
 		},
 	)
 
@@ -69,6 +69,10 @@ func getRegistryRoutes(
 				types.ProjectScope,
 				types.RegistryScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get a registry",
+				Response: types.Registry{},
+			},
 		},
 	)
 
@@ -126,6 +130,9 @@ func getRegistryRoutes(
 				types.ProjectScope,
 				types.RegistryScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Remove a registry from a project",
+			},
 		},
 	)
 
@@ -154,6 +161,10 @@ func getRegistryRoutes(
 				types.ProjectScope,
 				types.RegistryScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the repositories in a registry",
+				Response: types.ListRegistryRepositoryResponse{},
+			},
 		},
 	)
 
@@ -186,6 +197,10 @@ func getRegistryRoutes(
 				types.ProjectScope,
 				types.RegistryScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the images in a repository",
+				Response: types.ListImageResponse{},
+			},
 		},
 	)
 
@@ -214,6 +229,10 @@ func getRegistryRoutes(
 				types.ProjectScope,
 				types.RegistryScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Create a repository in a registry",
+				Request: types.CreateRegistryRepositoryRequest{},
+			},
 		},
 	)
 
@@ -229,5 +248,38 @@ func getRegistryRoutes(
 		Router:   r,
 	})
 
+	// POST /api/projects/{project_id}/registries/{registry_id}/test -> registry.NewTestRegistryConnectionHandler
+	testConnectionEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/test",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.RegistryScope,
+			},
+			Schema: &types.APISchema{
+				Summary:     "Test the connection to a registry",
+				Description: "Calls the API of the registry with the credentials its pull secrets are generated from, and returns whether the credentials were rejected, the registry could not be reached, or the credentials do not grant access to pull from it. The credential status of the registry is updated unless the registry could not be reached.",
+				Response:    types.TestRegistryConnectionResponse{},
+			},
+		},
+	)
+
+	testConnectionHandler := registry.NewTestRegistryConnectionHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: testConnectionEndpoint,
+		Handler:  testConnectionHandler,
+		Router:   r,
+	})
+
 	return routes, newPath
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/router"
 	"github.com/porter-dev/porter/api/types"
+	batchv1 "k8s.io/api/batch/v1"
 )
 
 func NewReleaseScopedRegisterer(children ...*router.Registerer) *router.Registerer {
@@ -69,6 +70,10 @@ func getReleaseRoutes(
 				types.NamespaceScope,
 				types.ReleaseScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get a release",
+				Response: types.GetReleaseResponse{},
+			},
 		},
 	)
 
@@ -100,6 +105,7 @@ func getReleaseRoutes(
 				types.ReleaseScope,
 			},
 			IsWebsocket: true,
+			StreamType:  types.StreamTypeStatus,
 		},
 	)
 
@@ -221,6 +227,10 @@ func getReleaseRoutes(
 				types.NamespaceScope,
 				types.ReleaseScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the pods of a release",
+				Response: types.GetReleaseAllPodsResponse{},
+			},
 		},
 	)
 
@@ -339,6 +349,10 @@ func getReleaseRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get the deploy webhook of a release",
+				Response: types.PorterRelease{},
+			},
 		},
 	)
 
@@ -428,6 +442,10 @@ func getReleaseRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Update the deployment steps of a release",
+				Request: types.UpdateReleaseStepsRequest{},
+			},
 		},
 	)
 
@@ -446,8 +464,9 @@ func getReleaseRoutes(
 	// POST /api/projects/{project_id}/clusters/{cluster_id}/namespaces/{namespace}/releases -> release.NewCreateReleaseHandler
 	createReleaseEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: "/releases",
@@ -458,6 +477,10 @@ func getReleaseRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Deploy a template as a release",
+				Request: types.CreateReleaseRequest{},
+			},
 		},
 	)
 
@@ -476,8 +499,9 @@ func getReleaseRoutes(
 	// POST /api/projects/{project_id}/clusters/{cluster_id}/namespaces/{namespace}/addons -> release.NewCreateAddonHandler
 	createAddonEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: "/addons",
@@ -488,6 +512,10 @@ func getReleaseRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Deploy an add-on",
+				Request: types.CreateAddonRequest{},
+			},
 		},
 	)
 
@@ -537,8 +565,9 @@ func getReleaseRoutes(
 	// release.NewRollbackReleaseHandler
 	rollbackEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPost,
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath + "/rollback",
@@ -569,8 +598,9 @@ func getReleaseRoutes(
 	// release.NewUpgradeReleaseHandler
 	upgradeEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPost,
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath + "/upgrade",
@@ -582,6 +612,10 @@ func getReleaseRoutes(
 				types.NamespaceScope,
 				types.ReleaseScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Upgrade a release",
+				Request: types.UpgradeReleaseRequest{},
+			},
 		},
 	)
 
@@ -614,6 +648,9 @@ func getReleaseRoutes(
 				types.NamespaceScope,
 				types.ReleaseScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Delete a release",
+			},
 		},
 	)
 
@@ -645,6 +682,10 @@ func getReleaseRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Update the image tag of every release using an image",
+				Request: types.UpdateImageBatchRequest{},
+			},
 		},
 	)
 
@@ -677,6 +718,11 @@ func getReleaseRoutes(
 				types.NamespaceScope,
 				types.ReleaseScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the jobs of a release",
+				Request:  types.GetJobsRequest{},
+				Response: []batchv1.Job{},
+			},
 		},
 	)
 
@@ -769,6 +815,10 @@ func getReleaseRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Create a subdomain for a release",
+				Response: types.DNSRecord{},
+			},
 		},
 	)
 
@@ -1,16 +1,20 @@
 package router
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"path"
+	"reflect"
 	"strings"
+	"time"
 
 	chiMiddleware "github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/porter-dev/porter/api/server/authn"
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/authz/policy"
+	"github.com/porter-dev/porter/api/server/openapi"
 	"github.com/porter-dev/porter/api/server/router/middleware"
 	v1 "github.com/porter-dev/porter/api/server/router/v1"
 	"github.com/porter-dev/porter/api/server/shared"
@@ -21,6 +25,13 @@ import (
 )
 
 func NewAPIRouter(config *config.Config) *chi.Mux {
+	r, _ := newAPIRouter(config)
+
+	return r
+}
+
+// newAPIRouter returns the API router, and the routes registered on it which the API reference is generated from
+func newAPIRouter(config *config.Config) (*chi.Mux, []openapi.Route) {
 	r := chi.NewRouter()
 
 	endpointFactory := shared.NewAPIObjectEndpointFactory(config)
@@ -66,6 +77,8 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 	userRegisterer := NewUserScopedRegisterer(projRegisterer, statusRegisterer)
 	panicMW := middleware.NewPanicMiddleware(config)
 
+	var apiRoutes []*router.Route
+
 	if config.ServerConf.PprofEnabled {
 		r.Mount("/debug", chiMiddleware.Profiler())
 	}
@@ -73,7 +86,7 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 	r.Route("/api", func(r chi.Router) {
 		r.Use(
 			otelchi.Middleware("porter-server-middleware", otelchi.WithRequestMethodInSpanName(true), otelchi.WithChiRoutes(r), otelchi.WithFilter(func(r *http.Request) bool {
-				if strings.HasSuffix(r.URL.Path, "/livez") || strings.HasSuffix(r.URL.Path, "/readyz") {
+				if strings.HasSuffix(r.URL.Path, "/livez") || strings.HasSuffix(r.URL.Path, "/readyz") || strings.HasSuffix(r.URL.Path, "/streamz") {
 					return false
 				}
 				return true
@@ -122,12 +135,13 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 		}
 
 		registerRoutes(config, allRoutes)
+		apiRoutes = append(apiRoutes, allRoutes...)
 	})
 
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Use(
 			otelchi.Middleware("porter-server-middleware", otelchi.WithRequestMethodInSpanName(true), otelchi.WithChiRoutes(r), otelchi.WithFilter(func(r *http.Request) bool {
-				if strings.HasSuffix(r.URL.Path, "/livez") || strings.HasSuffix(r.URL.Path, "/readyz") {
+				if strings.HasSuffix(r.URL.Path, "/livez") || strings.HasSuffix(r.URL.Path, "/readyz") || strings.HasSuffix(r.URL.Path, "/streamz") {
 					return false
 				}
 				return true
@@ -166,8 +180,12 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 		allRoutes = append(allRoutes, v1Routes...)
 
 		registerRoutes(config, allRoutes)
+		apiRoutes = append(apiRoutes, allRoutes...)
 	})
 
+	referenceRoutes := apiReferenceRoutes(r, apiRoutes)
+	registerAPIReference(r, config, referenceRoutes)
+
 	staticFilePath := config.ServerConf.StaticFilePath
 	fs := http.FileServer(http.Dir(staticFilePath))
 
@@ -189,7 +207,7 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 		}
 	})
 
-	return r
+	return r, referenceRoutes
 }
 
 func registerRoutes(config *config.Config, routes []*router.Route) {
@@ -249,10 +267,7 @@ func registerRoutes(config *config.Config, routes []*router.Route) {
 	policyDocLoader := policy.NewBasicPolicyDocumentLoader(config.Repo.Project(), config.Repo.Policy())
 
 	// set up logging middleware to log information about the request
-	loggerMw := middleware.NewRequestLoggerMiddleware(config.Logger)
-
-	// websocket middleware for upgrading requests
-	websocketMw := middleware.NewWebsocketMiddleware(config)
+	loggerMw := middleware.NewRequestLoggerMiddleware(config.Logger, config.ServerConf.VerboseRequestLogging)
 
 	// gitlab integration middleware to handle gitlab integrations for a specific project
 	gitlabIntFactory := authz.NewGitlabIntegrationScopedFactory(config)
@@ -262,9 +277,22 @@ func registerRoutes(config *config.Config, routes []*router.Route) {
 
 	apiContractRevisionFactory := authz.NewAPIContractRevisionScopedFactory(config)
 
+	// resolves the UUID or previous name of an app in the URL of app routes to its current name
+	porterAppNameMw := middleware.NewPorterAppNameMiddleware(config)
+	porterAppNameParam := fmt.Sprintf("{%s}", types.URLParamPorterAppName)
+
+	// records the requests to projects with an active debug recording
+	debugRecordingMw := middleware.NewDebugRecordingMiddleware(config)
+
 	for _, route := range routes {
 		atomicGroup := route.Router.Group(nil)
 
+		// the timeout is applied first so that the scope middlewares' queries share the budget of the request
+		if timeout := requestTimeout(config, route.Endpoint.Metadata); timeout > 0 {
+			timeoutMw := middleware.NewTimeoutMiddleware(config, timeout)
+			atomicGroup.Use(timeoutMw.Middleware)
+		}
+
 		for _, scope := range route.Endpoint.Metadata.Scopes {
 			switch scope {
 			case types.UserScope:
@@ -310,11 +338,20 @@ func registerRoutes(config *config.Config, routes []*router.Route) {
 			}
 		}
 
+		if strings.Contains(route.Endpoint.Metadata.Path.RelativePath, porterAppNameParam) {
+			atomicGroup.Use(porterAppNameMw.Middleware)
+		}
+
+		if !route.Endpoint.Metadata.IsWebsocket && hasScope(route.Endpoint.Metadata, types.ProjectScope) {
+			atomicGroup.Use(debugRecordingMw.Middleware)
+		}
+
 		if !route.Endpoint.Metadata.Quiet {
 			atomicGroup.Use(loggerMw.Middleware)
 		}
 
 		if route.Endpoint.Metadata.IsWebsocket {
+			websocketMw := middleware.NewWebsocketMiddleware(config, route.Endpoint.Metadata.StreamType)
 			atomicGroup.Use(websocketMw.Middleware)
 		}
 
@@ -332,3 +369,104 @@ func registerRoutes(config *config.Config, routes []*router.Route) {
 		)
 	}
 }
+
+// hasScope returns true if the endpoint is scoped to the given scope
+func hasScope(metadata *types.APIRequestMetadata, scope types.PermissionScope) bool {
+	for _, s := range metadata.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requestTimeout returns the time budget of an endpoint, or zero if the endpoint is not timed out
+func requestTimeout(config *config.Config, metadata *types.APIRequestMetadata) time.Duration {
+	if metadata.IsWebsocket {
+		return 0
+	}
+
+	switch metadata.Timeout {
+	case types.TimeoutClassNone:
+		return 0
+	case types.TimeoutClassLong:
+		return config.ServerConf.RequestTimeoutLong
+	}
+
+	if metadata.Method == types.HTTPVerbGet {
+		return config.ServerConf.RequestTimeoutRead
+	}
+
+	return config.ServerConf.RequestTimeoutWrite
+}
+
+// registerAPIReference serves the OpenAPI document of the routes at /api/swagger.json, and a page which renders it
+// at /api/docs if the docs UI is enabled
+func registerAPIReference(r chi.Router, config *config.Config, routes []openapi.Route) {
+	opts := openapi.Options{CookieName: config.ServerConf.CookieName}
+	if config.Metadata != nil {
+		opts.Version = config.Metadata.Version
+	}
+
+	specHandler, err := openapi.SpecHandler(openapi.Generate(opts, routes))
+	if err != nil {
+		// the document is generated from Go types, so it only fails to encode if a type cannot be described at all
+		config.Logger.Error().Err(err).Msg("error generating api reference")
+		return
+	}
+
+	r.Method(http.MethodGet, "/api/swagger.json", specHandler)
+
+	if config.ServerConf.APIDocsUIEnabled {
+		r.Method(http.MethodGet, "/api/docs", openapi.DocsHandler())
+	}
+}
+
+// apiReferenceRoutes returns the routes in the form which the API reference is generated from. Routes are registered
+// relative to the router they are given, so the prefix of each router is found by walking the API router for the
+// handlers registered on it.
+func apiReferenceRoutes(r chi.Routes, routes []*router.Route) []openapi.Route {
+	type endpoint struct {
+		method  string
+		handler http.Handler
+	}
+
+	patterns := make(map[endpoint]string)
+	_ = chi.Walk(r, func(method string, pattern string, handler http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if reflect.TypeOf(handler).Comparable() {
+			patterns[endpoint{method, handler}] = pattern
+		}
+		return nil
+	})
+
+	// chi does not walk the handlers registered on the same pattern as a subrouter, such as GET /projects/{project_id},
+	// so those routes rely on the prefix found from the other routes of their router
+	prefixes := make(map[chi.Router]string)
+	for _, route := range routes {
+		if !reflect.TypeOf(route.Handler).Comparable() {
+			continue
+		}
+
+		relPath := route.Endpoint.Metadata.Path.RelativePath
+		if pattern, ok := patterns[endpoint{string(route.Endpoint.Metadata.Method), route.Handler}]; ok && strings.HasSuffix(pattern, relPath) {
+			prefixes[route.Router] = strings.TrimSuffix(pattern, relPath)
+		}
+	}
+
+	res := make([]openapi.Route, 0, len(routes))
+	for _, route := range routes {
+		prefix, ok := prefixes[route.Router]
+		if !ok {
+			continue
+		}
+
+		res = append(res, openapi.Route{
+			Path:     prefix + route.Endpoint.Metadata.Path.RelativePath,
+			Name:     openapi.HandlerName(route.Handler),
+			Metadata: route.Endpoint.Metadata,
+		})
+	}
+
+	return res
+}
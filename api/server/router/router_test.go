@@ -0,0 +1,135 @@
+package router
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+var update = flag.Bool("update", false, "rewrite the list of undocumented routes")
+
+// undocumentedRoutesFile lists the routes which were registered before schema annotations were required. Routes
+// should be removed from it as they are annotated, and new routes may not be added to it.
+var undocumentedRoutesFile = filepath.Join("testdata", "undocumented_routes.txt")
+
+// TestRoutesHaveSchemas walks the API router and fails for routes which are registered without a schema annotation,
+// so that every new endpoint is part of the API reference.
+func TestRoutesHaveSchemas(t *testing.T) {
+	conf := apitest.LoadConfig(t)
+	// the routes of preview environments are only registered when github webhooks are configured
+	conf.ServerConf.GithubIncomingWebhookSecret = "secret"
+
+	_, routes := newAPIRouter(conf)
+
+	var undocumented []string
+	for _, route := range routes {
+		if route.Metadata.Schema == nil {
+			undocumented = append(undocumented, string(route.Metadata.Method)+" "+route.Path)
+		}
+	}
+	sort.Strings(undocumented)
+
+	if *update {
+		if err := os.WriteFile(undocumentedRoutesFile, []byte(strings.Join(undocumented, "\n")+"\n"), 0o644); err != nil {
+			t.Fatalf("error writing %s: %v", undocumentedRoutesFile, err)
+		}
+		return
+	}
+
+	contents, err := os.ReadFile(undocumentedRoutesFile)
+	if err != nil {
+		t.Fatalf("error reading %s: %v", undocumentedRoutesFile, err)
+	}
+
+	allowed := make(map[string]bool)
+	for _, line := range strings.Split(string(contents), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			allowed[line] = true
+		}
+	}
+
+	for _, route := range undocumented {
+		if !allowed[route] {
+			t.Errorf("%s is registered without a schema annotation: set Schema in its APIRequestMetadata", route)
+		}
+		delete(allowed, route)
+	}
+
+	for route := range allowed {
+		t.Errorf("%s is listed in %s but is annotated or no longer registered: remove it from the list", route, undocumentedRoutesFile)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	conf := apitest.LoadConfig(t)
+	conf.ServerConf.RequestTimeoutRead = time.Second
+	conf.ServerConf.RequestTimeoutWrite = 2 * time.Second
+	conf.ServerConf.RequestTimeoutLong = 3 * time.Second
+
+	tests := []struct {
+		name     string
+		metadata types.APIRequestMetadata
+		want     time.Duration
+	}{
+		{"read", types.APIRequestMetadata{Method: types.HTTPVerbGet}, time.Second},
+		{"write", types.APIRequestMetadata{Method: types.HTTPVerbPost}, 2 * time.Second},
+		{"deploy", types.APIRequestMetadata{Method: types.HTTPVerbPost, Timeout: types.TimeoutClassLong}, 3 * time.Second},
+		{"exempt", types.APIRequestMetadata{Method: types.HTTPVerbGet, Timeout: types.TimeoutClassNone}, 0},
+		{"websocket", types.APIRequestMetadata{Method: types.HTTPVerbGet, IsWebsocket: true}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := requestTimeout(conf, &tt.metadata); got != tt.want {
+			t.Errorf("%s: expected a budget of %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestStreamDeployLogs streams the logs of a deploy through the middlewares of the router, which must leave the
+// response unbuffered and not time the stream out
+func TestStreamDeployLogs(t *testing.T) {
+	env := apitest.NewHandlerTestEnv(t, "porter-stack-web")
+	// a budget would end the stream before the first event
+	env.Config.ServerConf.RequestTimeoutRead = time.Nanosecond
+
+	env.Agents.AddRelease(t, &release.Release{
+		Name:      "web",
+		Namespace: "porter-stack-web",
+		Version:   1,
+		Info:      &release.Info{Status: release.StatusDeployed},
+	})
+
+	r := NewAPIRouter(env.Config)
+	cookie := apitest.AuthenticateUserWithCookie(t, env.Config, env.User, false)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/projects/%d/clusters/%d/applications/web/deploy-logs?revision=1", env.Project.ID, env.Cluster.ID), nil)
+	req.AddCookie(cookie)
+	req = apitest.WithAgents(t, req, env.Agents)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("expected an event stream, got content type %q", got)
+	}
+	if !rr.Flushed {
+		t.Errorf("expected the stream to be flushed to the client")
+	}
+	if want := "event: " + types.DeployLogsEvent_Done; !strings.Contains(rr.Body.String(), want) {
+		t.Errorf("expected a done event, got %q", rr.Body.String())
+	}
+}
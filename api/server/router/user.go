@@ -4,14 +4,19 @@ import (
 	"fmt"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/porter-dev/porter/api/server/handlers/admin_overview"
+	"github.com/porter-dev/porter/api/server/handlers/cleanup"
+	"github.com/porter-dev/porter/api/server/handlers/debug_recording"
 	"github.com/porter-dev/porter/api/server/handlers/gitinstallation"
 	"github.com/porter-dev/porter/api/server/handlers/project"
 	"github.com/porter-dev/porter/api/server/handlers/template"
+	"github.com/porter-dev/porter/api/server/handlers/tracing"
 	"github.com/porter-dev/porter/api/server/handlers/user"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/router"
 	"github.com/porter-dev/porter/api/types"
+	internalCleanup "github.com/porter-dev/porter/internal/cleanup"
 )
 
 func NewUserScopedRegisterer(children ...*router.Registerer) *router.Registerer {
@@ -110,6 +115,9 @@ func getUserRoutes(
 				RelativePath: "/logout",
 			},
 			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary: "Log out of the current session",
+			},
 		},
 	)
 
@@ -156,6 +164,10 @@ func getUserRoutes(
 				RelativePath: "/users/current",
 			},
 			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "Get the authenticated user",
+				Response: types.GetAuthenticatedUserResponse{},
+			},
 		},
 	)
 
@@ -180,6 +192,9 @@ func getUserRoutes(
 				RelativePath: "/users/current",
 			},
 			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary: "Delete the authenticated user",
+			},
 		},
 	)
 
@@ -194,6 +209,121 @@ func getUserRoutes(
 		Router:   r,
 	})
 
+	// GET /api/users/current/2fa -> user.NewTwoFactorStatusHandler
+	twoFactorStatusEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/2fa",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "Get whether the authenticated user has enabled two-factor authentication",
+				Response: types.TwoFactorStatus{},
+			},
+		},
+	)
+
+	twoFactorStatusHandler := user.NewTwoFactorStatusHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: twoFactorStatusEndpoint,
+		Handler:  twoFactorStatusHandler,
+		Router:   r,
+	})
+
+	// POST /api/users/current/2fa -> user.NewTwoFactorEnrollHandler
+	enrollTwoFactorEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/2fa",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "Generate a new two-factor authentication secret for the authenticated user",
+				Response: types.EnrollTwoFactorResponse{},
+			},
+		},
+	)
+
+	enrollTwoFactorHandler := user.NewTwoFactorEnrollHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: enrollTwoFactorEndpoint,
+		Handler:  enrollTwoFactorHandler,
+		Router:   r,
+	})
+
+	// POST /api/users/current/2fa/enable -> user.NewTwoFactorEnableHandler
+	enableTwoFactorEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/2fa/enable",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "Enable two-factor authentication with a code from the enrolled authenticator",
+				Request:  types.EnableTwoFactorRequest{},
+				Response: types.EnableTwoFactorResponse{},
+			},
+		},
+	)
+
+	enableTwoFactorHandler := user.NewTwoFactorEnableHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: enableTwoFactorEndpoint,
+		Handler:  enableTwoFactorHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/users/current/2fa -> user.NewTwoFactorDisableHandler
+	disableTwoFactorEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/users/current/2fa",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary: "Disable two-factor authentication",
+				Request: types.DisableTwoFactorRequest{},
+			},
+		},
+	)
+
+	disableTwoFactorHandler := user.NewTwoFactorDisableHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: disableTwoFactorEndpoint,
+		Handler:  disableTwoFactorHandler,
+		Router:   r,
+	})
+
 	// POST /api/projects -> project.NewProjectCreateHandler
 	createEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -204,6 +334,11 @@ func getUserRoutes(
 				RelativePath: "/projects",
 			},
 			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "Create a project",
+				Request:  types.CreateProjectRequest{},
+				Response: types.CreateProjectResponse{},
+			},
 		},
 	)
 
@@ -229,6 +364,10 @@ func getUserRoutes(
 				RelativePath: "/projects",
 			},
 			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "List the projects of the authenticated user",
+				Response: types.ListUserProjectsResponse{},
+			},
 		},
 	)
 
@@ -243,6 +382,36 @@ func getUserRoutes(
 		Router:   r,
 	})
 
+	// GET /api/v2/projects -> project.NewProjectListV2Handler
+	listV2Endpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/v2/projects",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "List a page of the projects of the authenticated user",
+				Request:  types.ListUserProjectsV2Request{},
+				Response: types.ListUserProjectsV2Response{},
+			},
+		},
+	)
+
+	listV2Handler := project.NewProjectListV2Handler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listV2Endpoint,
+		Handler:  listV2Handler,
+		Router:   r,
+	})
+
 	// POST /email/verify/initiate -> user.VerifyEmailInitiateHandler
 	emailVerifyInitiateEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -264,6 +433,31 @@ func getUserRoutes(
 		Router:   r,
 	})
 
+	// POST /email/verify/resend -> user.ResendVerificationEmailHandler
+	resendVerificationEmailEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/email/verify/resend",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:     "Resend the verification email of the authenticated user",
+				Description: "Only the link of the newest verification email can be used. Users who are already verified get the same response, without an email. Too many requests are refused with status 429 and a Retry-After header.",
+			},
+		},
+	)
+
+	resendVerificationEmailHandler := user.NewResendVerificationEmailHandler(config)
+
+	routes = append(routes, &router.Route{
+		Endpoint: resendVerificationEmailEndpoint,
+		Handler:  resendVerificationEmailHandler,
+		Router:   r,
+	})
+
 	// GET /email/verify/finalize -> user.VerifyEmailInitiateHandler
 	emailVerifyFinalizeEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -472,5 +666,270 @@ func getUserRoutes(
 		Router:   r,
 	})
 
+	// POST /api/admin/projects/{project_id}/debug-recordings -> debug_recording.NewStartDebugRecordingHandler
+	startDebugRecordingEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/admin/projects/{%s}/debug-recordings", types.URLParamProjectID),
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "Start recording the API requests of a project",
+				Request:  types.StartDebugRecordingRequest{},
+				Response: types.DebugRecording{},
+			},
+		},
+	)
+
+	startDebugRecordingHandler := debug_recording.NewStartDebugRecordingHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: startDebugRecordingEndpoint,
+		Handler:  startDebugRecordingHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/projects/{project_id}/debug-recordings -> debug_recording.NewListDebugRecordingsHandler
+	listDebugRecordingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/admin/projects/{%s}/debug-recordings", types.URLParamProjectID),
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "List the debug recordings of a project",
+				Response: []types.DebugRecording{},
+			},
+		},
+	)
+
+	listDebugRecordingsHandler := debug_recording.NewListDebugRecordingsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listDebugRecordingsEndpoint,
+		Handler:  listDebugRecordingsHandler,
+		Router:   r,
+	})
+
+	// POST /api/admin/projects/{project_id}/debug-recordings/{debug_recording_id}/stop -> debug_recording.NewStopDebugRecordingHandler
+	stopDebugRecordingEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent: basePath,
+				RelativePath: fmt.Sprintf(
+					"/admin/projects/{%s}/debug-recordings/{%s}/stop",
+					types.URLParamProjectID,
+					types.URLParamDebugRecordingID,
+				),
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "Stop a debug recording",
+				Response: types.DebugRecording{},
+			},
+		},
+	)
+
+	stopDebugRecordingHandler := debug_recording.NewStopDebugRecordingHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: stopDebugRecordingEndpoint,
+		Handler:  stopDebugRecordingHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/projects/{project_id}/debug-recordings/{debug_recording_id}/exchanges -> debug_recording.NewListDebugRecordingExchangesHandler
+	listDebugRecordingExchangesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent: basePath,
+				RelativePath: fmt.Sprintf(
+					"/admin/projects/{%s}/debug-recordings/{%s}/exchanges",
+					types.URLParamProjectID,
+					types.URLParamDebugRecordingID,
+				),
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "List the redacted requests and responses of a debug recording",
+				Response: types.ListDebugRecordingExchangesResponse{},
+			},
+		},
+	)
+
+	listDebugRecordingExchangesHandler := debug_recording.NewListDebugRecordingExchangesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listDebugRecordingExchangesEndpoint,
+		Handler:  listDebugRecordingExchangesHandler,
+		Router:   r,
+	})
+
+	// POST /api/admin/cleanup -> cleanup.NewRunCleanupHandler
+	runCleanupEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/admin/cleanup",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:     "Delete expired sessions and token caches",
+				Description: "Runs the cleanup which otherwise runs on a schedule, after any run in progress has finished.",
+				Response:    internalCleanup.Result{},
+			},
+		},
+	)
+
+	runCleanupHandler := cleanup.NewRunCleanupHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: runCleanupEndpoint,
+		Handler:  runCleanupHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/telemetry -> tracing.NewGetSettingsHandler
+	getTelemetrySettingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/admin/telemetry",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "Get the tracing settings of the instance",
+				Response: types.TelemetrySettings{},
+			},
+		},
+	)
+
+	getTelemetrySettingsHandler := tracing.NewGetSettingsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getTelemetrySettingsEndpoint,
+		Handler:  getTelemetrySettingsHandler,
+		Router:   r,
+	})
+
+	// PATCH /api/admin/telemetry -> tracing.NewUpdateSettingsHandler
+	updateTelemetrySettingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPatch,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/admin/telemetry",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "Update the tracing settings of the instance",
+				Request:  types.UpdateTelemetrySettingsRequest{},
+				Response: types.TelemetrySettings{},
+			},
+		},
+	)
+
+	updateTelemetrySettingsHandler := tracing.NewUpdateSettingsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateTelemetrySettingsEndpoint,
+		Handler:  updateTelemetrySettingsHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/overview -> admin_overview.NewGetAdminOverviewHandler
+	getAdminOverviewEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/admin/overview",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:  "Get the overview of the instance",
+				Response: types.AdminOverview{},
+			},
+		},
+	)
+
+	getAdminOverviewHandler := admin_overview.NewGetAdminOverviewHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getAdminOverviewEndpoint,
+		Handler:  getAdminOverviewHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/overview/schema -> admin_overview.NewGetAdminOverviewSchemaHandler
+	getAdminOverviewSchemaEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/admin/overview/schema",
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+			Schema: &types.APISchema{
+				Summary:     "Get the JSON schema of the instance overview",
+				Description: "The schema is written as application/schema+json, for building dashboards against the overview.",
+			},
+		},
+	)
+
+	getAdminOverviewSchemaHandler := admin_overview.NewGetAdminOverviewSchemaHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getAdminOverviewSchemaEndpoint,
+		Handler:  getAdminOverviewSchemaHandler,
+		Router:   r,
+	})
+
 	return routes
 }
@@ -125,6 +125,11 @@ func getV1ProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List templates",
+				Request:  types.ListTemplatesRequest{},
+				Response: types.ListTemplatesResponse{},
+			},
 		},
 	)
 
@@ -186,6 +191,11 @@ func getV1ProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "Get a template",
+				Request:  types.GetTemplateRequest{},
+				Response: types.GetTemplateResponse{},
+			},
 		},
 	)
 
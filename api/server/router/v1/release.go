@@ -128,8 +128,9 @@ func getV1ReleaseRoutes(
 	//     description: A precondition failed for the request
 	createReleaseEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath,
@@ -292,8 +293,9 @@ func getV1ReleaseRoutes(
 	//     description: Forbidden
 	upgradeEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPatch,
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPatch,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath + "/{name}/{version}",
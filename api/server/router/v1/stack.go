@@ -198,8 +198,9 @@ func getV1StackRoutes(
 	//     description: Forbidden
 	createEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbCreate,
-			Method: types.HTTPVerbPost,
+			Verb:    types.APIVerbCreate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath,
@@ -261,6 +262,10 @@ func getV1StackRoutes(
 				types.ClusterScope,
 				types.NamespaceScope,
 			},
+			Schema: &types.APISchema{
+				Summary:  "List the stacks in a namespace",
+				Response: types.StackListResponse{},
+			},
 		},
 	)
 
@@ -462,8 +467,9 @@ func getV1StackRoutes(
 	//     description: Forbidden
 	putSourceEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPut,
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPut,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath + "/{stack_id}/source",
@@ -520,8 +526,9 @@ func getV1StackRoutes(
 	//     description: Forbidden
 	rollbackEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPost,
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPost,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath + "/{stack_id}/rollback",
@@ -628,8 +635,9 @@ func getV1StackRoutes(
 	//     description: Forbidden
 	addApplicationEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPatch,
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPatch,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath + "/{stack_id}/add_application",
@@ -739,8 +747,9 @@ func getV1StackRoutes(
 	//     description: Forbidden
 	addEnvGroupEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPatch,
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPatch,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath + "/{stack_id}/add_env_group",
@@ -752,6 +761,10 @@ func getV1StackRoutes(
 				types.NamespaceScope,
 				types.StackScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Add an env group to a stack",
+				Request: types.CreateStackEnvGroupRequest{},
+			},
 		},
 	)
 
@@ -806,6 +819,9 @@ func getV1StackRoutes(
 				types.NamespaceScope,
 				types.StackScope,
 			},
+			Schema: &types.APISchema{
+				Summary: "Remove an env group from a stack",
+			},
 		},
 	)
 
@@ -848,8 +864,9 @@ func getV1StackRoutes(
 	//     description: Forbidden
 	updateStackEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
-			Verb:   types.APIVerbUpdate,
-			Method: types.HTTPVerbPatch,
+			Verb:    types.APIVerbUpdate,
+			Method:  types.HTTPVerbPatch,
+			Timeout: types.TimeoutClassLong,
 			Path: &types.Path{
 				Parent:       basePath,
 				RelativePath: relPath + "/{stack_id}",
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/server/shared/websocket"
 )
 
 // PorterAPIServer contains the routing and configuration options for starting the PorterAPIServer
@@ -17,6 +19,8 @@ type PorterAPIServer struct {
 	Router *chi.Mux
 	// ServerConf is the server configuration
 	ServerConf *env.ServerConf
+	// StreamRegistry tracks the server's streaming connections, which are closed on shutdown. Optional
+	StreamRegistry *websocket.StreamRegistry
 }
 
 // ListenAndServe starts the Porter API server
@@ -50,5 +54,23 @@ func (p PorterAPIServer) ListenAndServe(ctx context.Context) error {
 	case <-ctx.Done():
 	}
 
+	// websockets are hijacked from the http server, so they are not closed by srv.Shutdown
+	if p.StreamRegistry != nil {
+		drainCtx, drainCancel := context.WithTimeout(context.Background(), p.streamDrainTimeout())
+		defer drainCancel()
+
+		if err := p.StreamRegistry.Shutdown(drainCtx); err != nil {
+			return fmt.Errorf("streaming connections did not close before the drain deadline: %w", err)
+		}
+	}
+
 	return nil
 }
+
+func (p PorterAPIServer) streamDrainTimeout() time.Duration {
+	if p.ServerConf == nil || p.ServerConf.StreamDrainTimeout <= 0 {
+		return 10 * time.Second
+	}
+
+	return p.ServerConf.StreamDrainTimeout
+}
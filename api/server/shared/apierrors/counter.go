@@ -0,0 +1,101 @@
+package apierrors
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// errorCounterBuckets is the number of hourly buckets an ErrorCounter keeps, so that it covers the last day
+const errorCounterBuckets = 24
+
+// ErrorCounts counts the errors handled by HandleAPIError, for the overview of the instance read by its admin
+var ErrorCounts = NewErrorCounter()
+
+type errorKey struct {
+	statusCode int
+	code       uint
+}
+
+type errorBucket struct {
+	hour   time.Time
+	counts map[errorKey]int64
+}
+
+// ErrorCounter counts the errors returned by the API in memory, in hourly buckets covering the last day. A bucket is
+// reused once its hour is a day old, so the counter never grows past a day of distinct status and error codes.
+type ErrorCounter struct {
+	mu      sync.Mutex
+	started time.Time
+	buckets [errorCounterBuckets]errorBucket
+}
+
+// NewErrorCounter returns an empty ErrorCounter
+func NewErrorCounter() *ErrorCounter {
+	return &ErrorCounter{started: time.Now().UTC()}
+}
+
+// Record counts an error returned at now with a status code and error code
+func (c *ErrorCounter) Record(now time.Time, statusCode int, code uint) {
+	hour := now.UTC().Truncate(time.Hour)
+	bucket := &c.buckets[hour.Unix()/int64(time.Hour/time.Second)%errorCounterBuckets]
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !bucket.hour.Equal(hour) {
+		bucket.hour = hour
+		bucket.counts = make(map[errorKey]int64)
+	}
+
+	bucket.counts[errorKey{statusCode: statusCode, code: code}]++
+}
+
+// Top returns the limit most frequent errors of the day before now, most frequent first, along with the start of the
+// period they were counted over
+func (c *ErrorCounter) Top(now time.Time, limit int) ([]types.AdminOverviewErrorCount, time.Time) {
+	now = now.UTC()
+	cutoff := now.Truncate(time.Hour).Add(-(errorCounterBuckets - 1) * time.Hour)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	totals := make(map[errorKey]int64)
+	for _, bucket := range c.buckets {
+		if bucket.hour.Before(cutoff) || bucket.hour.After(now) {
+			continue
+		}
+
+		for key, count := range bucket.counts {
+			totals[key] += count
+		}
+	}
+
+	res := make([]types.AdminOverviewErrorCount, 0, len(totals))
+	for key, count := range totals {
+		res = append(res, types.AdminOverviewErrorCount{StatusCode: key.statusCode, Code: key.code, Count: count})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		if res[i].StatusCode != res[j].StatusCode {
+			return res[i].StatusCode < res[j].StatusCode
+		}
+		return res[i].Code < res[j].Code
+	})
+
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+
+	start := cutoff
+	if c.started.After(start) {
+		start = c.started
+	}
+
+	return res, start
+}
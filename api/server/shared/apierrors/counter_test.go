@@ -0,0 +1,45 @@
+package apierrors_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+)
+
+func TestErrorCounterTop(t *testing.T) {
+	counter := apierrors.NewErrorCounter()
+	now := time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)
+
+	// errors over a day old are not counted, even once their bucket is reused
+	counter.Record(now.Add(-25*time.Hour), http.StatusInternalServerError, 0)
+	counter.Record(now.Add(-24*time.Hour), http.StatusInternalServerError, 0)
+
+	counter.Record(now.Add(-23*time.Hour), http.StatusBadRequest, 0)
+	counter.Record(now.Add(-2*time.Hour), http.StatusGatewayTimeout, types.ErrCodeRequestTimeout)
+	counter.Record(now.Add(-time.Hour), http.StatusGatewayTimeout, types.ErrCodeRequestTimeout)
+	counter.Record(now, http.StatusGatewayTimeout, types.ErrCodeRequestTimeout)
+	counter.Record(now, http.StatusForbidden, 0)
+	counter.Record(now, http.StatusForbidden, 0)
+
+	got, _ := counter.Top(now, 2)
+	want := []types.AdminOverviewErrorCount{
+		{StatusCode: http.StatusGatewayTimeout, Code: types.ErrCodeRequestTimeout, Count: 3},
+		{StatusCode: http.StatusForbidden, Count: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the most frequent errors of the last day %+v, got %+v", want, got)
+	}
+
+	got, _ = counter.Top(now.Add(23*time.Hour), 0)
+	want = []types.AdminOverviewErrorCount{
+		{StatusCode: http.StatusForbidden, Count: 2},
+		{StatusCode: http.StatusGatewayTimeout, Code: types.ErrCodeRequestTimeout, Count: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the errors of the earlier hours to expire, got %+v", got)
+	}
+}
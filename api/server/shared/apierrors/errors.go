@@ -1,12 +1,16 @@
 package apierrors
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/porter-dev/porter/api/server/shared/apierrors/alerter"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/tunnel"
 	"github.com/porter-dev/porter/pkg/logger"
 )
 
@@ -41,6 +45,10 @@ func (e *ErrInternal) GetStatusCode() int {
 	return http.StatusInternalServerError
 }
 
+func (e *ErrInternal) Unwrap() error {
+	return e.err
+}
+
 type ErrForbidden struct {
 	err error
 }
@@ -117,8 +125,60 @@ func (e *ErrNotFound) GetStatusCode() int {
 	return http.StatusNotFound
 }
 
+// ErrTimeout denotes that a request ran past its time budget
+type ErrTimeout struct {
+	err error
+}
+
+func NewErrTimeout(err error) RequestError {
+	return &ErrTimeout{err}
+}
+
+func (e *ErrTimeout) Error() string {
+	return e.err.Error()
+}
+
+func (e *ErrTimeout) InternalError() string {
+	return e.err.Error()
+}
+
+func (e *ErrTimeout) ExternalError() string {
+	return "The request timed out."
+}
+
+func (e *ErrTimeout) GetStatusCode() int {
+	return http.StatusGatewayTimeout
+}
+
+// ErrTunnelAgentOffline denotes that the cluster of a request is reached through a tunnel agent which is not connected
+type ErrTunnelAgentOffline struct {
+	err error
+}
+
+func NewErrTunnelAgentOffline(err error) RequestError {
+	return &ErrTunnelAgentOffline{err}
+}
+
+func (e *ErrTunnelAgentOffline) Error() string {
+	return e.err.Error()
+}
+
+func (e *ErrTunnelAgentOffline) InternalError() string {
+	return e.err.Error()
+}
+
+func (e *ErrTunnelAgentOffline) ExternalError() string {
+	return "The tunnel agent of the cluster is not connected. Check that the agent is running in the cluster."
+}
+
+func (e *ErrTunnelAgentOffline) GetStatusCode() int {
+	return http.StatusServiceUnavailable
+}
+
 type ErrorOpts struct {
 	Code uint
+	// Pods are returned with ErrCodeRolloutTimeout
+	Pods []types.RolloutPodStatus
 }
 
 func HandleAPIError(
@@ -130,6 +190,34 @@ func HandleAPIError(
 	writeErr bool,
 	opts ...ErrorOpts,
 ) {
+	// a handler whose downstream call failed because the request ran out of time reports an internal error, which
+	// is reported as a timeout instead
+	if err.GetStatusCode() == http.StatusInternalServerError && errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+		err = NewErrTimeout(err)
+	}
+
+	var timeoutErr *ErrTimeout
+	if errors.As(err, &timeoutErr) && len(opts) == 0 {
+		opts = []ErrorOpts{{Code: types.ErrCodeRequestTimeout}}
+	}
+
+	// handlers report the errors of the agents of their clusters as internal errors, which are reported as
+	// unavailable instead when the agent could not be reached through its tunnel
+	if err.GetStatusCode() == http.StatusInternalServerError && errors.Is(err, tunnel.ErrAgentOffline) {
+		err = NewErrTunnelAgentOffline(err)
+	}
+
+	var offlineErr *ErrTunnelAgentOffline
+	if errors.As(err, &offlineErr) && len(opts) == 0 {
+		opts = []ErrorOpts{{Code: types.ErrCodeTunnelAgentOffline}}
+	}
+
+	var code uint
+	if len(opts) > 0 {
+		code = opts[0].Code
+	}
+	ErrorCounts.Record(time.Now(), err.GetStatusCode(), code)
+
 	extErrorStr := err.ExternalError()
 
 	// log the internal error
@@ -162,6 +250,7 @@ func HandleAPIError(
 
 		if len(opts) > 0 {
 			resp.Code = opts[0].Code
+			resp.Pods = opts[0].Pods
 		}
 
 		// write the status code
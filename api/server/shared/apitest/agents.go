@@ -0,0 +1,219 @@
+package apitest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/stefanmcshane/helm/pkg/kube"
+	kubefake "github.com/stefanmcshane/helm/pkg/kube/fake"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"github.com/stefanmcshane/helm/pkg/storage"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakerest "k8s.io/client-go/rest/fake"
+)
+
+// ErrFakeKubeClient is returned by the installs and upgrades a FakeKubeClient is scripted to fail
+var ErrFakeKubeClient = errors.New("fake kube client: scripted failure")
+
+// FakeKubeClient applies the charts a helm agent installs and upgrades without a cluster. It can be scripted to fail
+// installs and upgrades, or to make them slow.
+type FakeKubeClient struct {
+	kubefake.PrintingKubeClient
+
+	// namespace is the namespace of resources whose manifest does not set one
+	namespace string
+
+	mu sync.Mutex
+	// createFailures and updateFailures are how many of the next installs and upgrades fail
+	createFailures int
+	updateFailures int
+	// delay is how long every install and upgrade takes
+	delay time.Duration
+
+	creates int
+	updates int
+}
+
+// FailCreates fails the next n installs
+func (f *FakeKubeClient) FailCreates(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.createFailures = n
+}
+
+// FailUpdates fails the next n upgrades
+func (f *FakeKubeClient) FailUpdates(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updateFailures = n
+}
+
+// SetDelay makes every install and upgrade take d
+func (f *FakeKubeClient) SetDelay(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delay = d
+}
+
+// Creates returns how many installs have been applied, not counting the ones which failed
+func (f *FakeKubeClient) Creates() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.creates
+}
+
+// Updates returns how many upgrades have been applied, not counting the ones which failed
+func (f *FakeKubeClient) Updates() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.updates
+}
+
+// Build returns the resources of a manifest, so that helm applies them through Create and Update. The resources are
+// read through a client which finds none of them, as if the cluster was empty.
+func (f *FakeKubeClient) Build(reader io.Reader, _ bool) (kube.ResourceList, error) {
+	client := &fakerest.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: fakerest.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+
+	var resources kube.ResourceList
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("error decoding manifest: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		gvk := obj.GroupVersionKind()
+		namespace := obj.GetNamespace()
+		if namespace == "" {
+			namespace = f.namespace
+		}
+		resources.Append(&resource.Info{
+			Client:    client,
+			Name:      obj.GetName(),
+			Namespace: namespace,
+			Object:    obj,
+			Mapping: &meta.RESTMapping{
+				Resource:         gvk.GroupVersion().WithResource(strings.ToLower(gvk.Kind) + "s"),
+				GroupVersionKind: gvk,
+				Scope:            meta.RESTScopeNamespace,
+			},
+		})
+	}
+
+	return resources, nil
+}
+
+// Create applies the resources of an install, unless it is scripted to fail
+func (f *FakeKubeClient) Create(resources kube.ResourceList) (*kube.Result, error) {
+	if err := f.apply(&f.createFailures, &f.creates); err != nil {
+		return nil, err
+	}
+	return f.PrintingKubeClient.Create(resources)
+}
+
+// Update applies the resources of an upgrade, unless it is scripted to fail
+func (f *FakeKubeClient) Update(original, target kube.ResourceList, force bool) (*kube.Result, error) {
+	if err := f.apply(&f.updateFailures, &f.updates); err != nil {
+		return nil, err
+	}
+	return f.PrintingKubeClient.Update(original, target, force)
+}
+
+func (f *FakeKubeClient) apply(failures *int, applied *int) error {
+	f.mu.Lock()
+	delay := f.delay
+	f.mu.Unlock()
+
+	time.Sleep(delay)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if *failures > 0 {
+		*failures--
+		return ErrFakeKubeClient
+	}
+	*applied++
+
+	return nil
+}
+
+// Agents are a kubernetes agent backed by a fake clientset and a helm agent which stores releases in memory, for testing
+// handlers without a cluster. The out of cluster agent getter returns the agents in the request context, so handlers
+// are given them by WithAgents.
+type Agents struct {
+	K8s  *kubernetes.Agent
+	Helm *helm.Agent
+	// KubeClient applies the charts the helm agent installs and upgrades
+	KubeClient *FakeKubeClient
+}
+
+// NewAgents returns in-memory agents for a namespace, whose clientset starts with objects
+func NewAgents(t *testing.T, conf *config.Config, namespace string, objects ...runtime.Object) *Agents {
+	t.Helper()
+
+	releases := driver.NewMemory()
+	// releases are read from the namespace of the agent, like the secrets driver the agents of a cluster use
+	releases.SetNamespace(namespace)
+
+	kubeClient := &FakeKubeClient{PrintingKubeClient: kubefake.PrintingKubeClient{Out: io.Discard}, namespace: namespace}
+
+	k8sAgent := kubernetes.GetAgentTesting(objects...)
+	helmAgent := helm.GetAgentTesting(&helm.Form{Namespace: namespace}, storage.Init(releases), conf.Logger, k8sAgent)
+	helmAgent.ActionConfig.KubeClient = kubeClient
+
+	return &Agents{
+		K8s:        k8sAgent,
+		Helm:       helmAgent,
+		KubeClient: kubeClient,
+	}
+}
+
+// AddRelease stores a release, as if it had been installed
+func (a *Agents) AddRelease(t *testing.T, rel *release.Release) {
+	t.Helper()
+
+	if err := a.Helm.ActionConfig.Releases.Create(rel); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// WithAgents returns the request with the agents in its context, which the out of cluster agent getter returns instead
+// of connecting to the cluster
+func WithAgents(t *testing.T, req *http.Request, agents *Agents) *http.Request {
+	t.Helper()
+
+	ctx := context.WithValue(req.Context(), authz.KubernetesAgentCtxKey, agents.K8s)
+	ctx = context.WithValue(ctx, authz.HelmAgentCtxKey, agents.Helm)
+
+	return req.WithContext(ctx)
+}
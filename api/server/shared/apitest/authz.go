@@ -25,3 +25,11 @@ func WithRequestScopes(t *testing.T, req *http.Request, reqScopes map[types.Perm
 
 	return req
 }
+
+func WithCluster(t *testing.T, req *http.Request, cluster *models.Cluster) *http.Request {
+	ctx := req.Context()
+	ctx = context.WithValue(ctx, types.ClusterScope, cluster)
+	req = req.WithContext(ctx)
+
+	return req
+}
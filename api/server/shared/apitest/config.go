@@ -4,11 +4,14 @@ import (
 	"os"
 	"testing"
 
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
 	"github.com/porter-dev/porter/internal/features"
+	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/telemetry"
 
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/config/envloader"
+	"github.com/porter-dev/porter/internal/adapter"
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/auth/sessionstore"
 	"github.com/porter-dev/porter/internal/auth/token"
@@ -62,6 +65,20 @@ func (t *TestConfigLoader) LoadConfig() (*config.Config, error) {
 		AnalyticsClient:    analytics.InitializeAnalyticsSegmentClient("", l),
 		BillingManager:     &billing.NoopBillingManager{},
 		TelemetryConfig:    telemetry.TracerConfig{ServiceName: "fake", CollectorURL: "fake"},
+		Locker:             adapter.NewDBLocker(repo.Lock()),
+		LoginLimiter: adapter.NewMemoryLoginLimiter(adapter.LoginLimitOptions{
+			Window:              envConf.ServerConf.LoginRateLimitWindow,
+			MaxAttemptsPerEmail: envConf.ServerConf.LoginRateLimitPerEmail,
+			MaxAttemptsPerIP:    envConf.ServerConf.LoginRateLimitPerIP,
+			LockoutThreshold:    envConf.ServerConf.LoginLockoutThreshold,
+			LockoutDuration:     envConf.ServerConf.LoginLockoutDuration,
+		}),
+		VerifyEmailLimiter: adapter.NewMemoryLoginLimiter(adapter.LoginLimitOptions{
+			Window:              envConf.ServerConf.VerifyEmailResendWindow,
+			MaxAttemptsPerEmail: envConf.ServerConf.VerifyEmailResendPerUser,
+			MaxAttemptsPerIP:    envConf.ServerConf.VerifyEmailResendPerIP,
+			Scope:               "verify-email",
+		}),
 	}, nil
 }
 
@@ -75,3 +92,16 @@ func LoadConfig(t *testing.T, failingRepoMethods ...string) *config.Config {
 
 	return config
 }
+
+// FeatureFlags is a launchdarkly client which returns the value of each flag set in it, and the default value of the
+// others
+type FeatureFlags map[models.FeatureFlagLabel]bool
+
+// BoolVariation returns the value of the flag
+func (f FeatureFlags) BoolVariation(key string, _ ldcontext.Context, defaultVal bool) (bool, error) {
+	if value, ok := f[models.FeatureFlagLabel(key)]; ok {
+		return value, nil
+	}
+
+	return defaultVal, nil
+}
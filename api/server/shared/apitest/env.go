@@ -0,0 +1,92 @@
+package apitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/features"
+	"github.com/porter-dev/porter/internal/models"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// HandlerTestEnv is a project with a cluster, an admin of the project and in-memory agents for the cluster, backed by
+// the in-memory repository of the test config, for testing the handlers of the cluster end to end
+type HandlerTestEnv struct {
+	Config  *config.Config
+	User    *models.User
+	Project *models.Project
+	Cluster *models.Cluster
+	Agents  *Agents
+	// FeatureFlags are the flags of the project. Flags which are not set have their default value.
+	FeatureFlags FeatureFlags
+}
+
+// NewHandlerTestEnv returns a HandlerTestEnv whose agents are for namespace, and whose clientset starts with objects
+func NewHandlerTestEnv(t *testing.T, namespace string, objects ...runtime.Object) *HandlerTestEnv {
+	t.Helper()
+
+	conf := LoadConfig(t)
+	user := CreateTestUser(t, conf, true)
+
+	// apps are deployed through helm rather than the cluster control plane of porter apply v2, which the env does not
+	// have
+	flags := FeatureFlags{models.ValidateApplyV2: false}
+	conf.LaunchDarklyClient = &features.Client{Client: flags}
+
+	proj, err := conf.Repo.Project().CreateProject(&models.Project{Name: "project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conf.Repo.Project().CreateProjectRole(proj, &models.Role{
+		Role: types.Role{
+			UserID:    user.ID,
+			ProjectID: proj.ID,
+			Kind:      types.RoleAdmin,
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the project is read again to get the model with the role attached
+	proj, err = conf.Repo.Project().ReadProject(proj.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster, err := conf.Repo.Cluster().CreateCluster(&models.Cluster{
+		ProjectID: proj.ID,
+		Name:      "cluster",
+	}, conf.LaunchDarklyClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &HandlerTestEnv{
+		Config:       conf,
+		User:         user,
+		Project:      proj,
+		Cluster:      cluster,
+		Agents:       NewAgents(t, conf, namespace, objects...),
+		FeatureFlags: flags,
+	}
+}
+
+// NewRequest returns a request from the user of the env, scoped to its project and cluster, with the agents of the env
+// and the url params set
+func (e *HandlerTestEnv) NewRequest(t *testing.T, method, route string, requestObj interface{}, urlParams map[string]string) (*http.Request, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	req, rr := GetRequestAndRecorder(t, method, route, requestObj)
+
+	req = WithAuthenticatedUser(t, req, e.User)
+	req = WithProject(t, req, e.Project)
+	req = WithCluster(t, req, e.Cluster)
+	req = WithURLParams(t, req, urlParams)
+	req = WithAgents(t, req, e.Agents)
+
+	return req, rr
+}
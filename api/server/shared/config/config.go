@@ -6,17 +6,25 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors/alerter"
 	"github.com/porter-dev/porter/api/server/shared/config/env"
 	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/adminoverview"
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/billing"
+	"github.com/porter-dev/porter/internal/cleanup"
+	"github.com/porter-dev/porter/internal/coalesce"
+	"github.com/porter-dev/porter/internal/debugrecording"
 	"github.com/porter-dev/porter/internal/features"
 	"github.com/porter-dev/porter/internal/helm/urlcache"
 	"github.com/porter-dev/porter/internal/integrations/dns"
+	"github.com/porter-dev/porter/internal/kubernetes/tunnel"
 	"github.com/porter-dev/porter/internal/nats"
 	"github.com/porter-dev/porter/internal/notifier"
 	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/eventbuffer"
+	"github.com/porter-dev/porter/internal/supervisor"
 	"github.com/porter-dev/porter/internal/telemetry"
 	"github.com/porter-dev/porter/pkg/logger"
 	"github.com/porter-dev/porter/provisioner/client"
@@ -79,6 +87,9 @@ type Config struct {
 	// WSUpgrader upgrades HTTP connections to websocket connections
 	WSUpgrader *websocket.Upgrader
 
+	// StreamRegistry tracks open streaming connections and enforces the per-user, per-project and global caps on them
+	StreamRegistry *websocket.StreamRegistry
+
 	// URLCache contains a cache of chart names to chart repos
 	URLCache *urlcache.ChartURLCache
 
@@ -113,6 +124,44 @@ type Config struct {
 	// EnableCAPIProvisioner enables CAPI Provisioner, which requires config for ClusterControlPlaneClient and NATS, if set to true
 	EnableCAPIProvisioner bool
 
+	// ResourceCache caches the results of slow calls to external services, such as listing registry tags
+	ResourceCache *adapter.Cache
+
+	// Locker takes the advisory locks shared by every replica of the server, such as the lock held while a stack is deployed
+	Locker *adapter.Locker
+
+	// LoginLimiter limits the login attempts for each email and from each IP address, and locks out emails after too
+	// many consecutive failed logins
+	LoginLimiter *adapter.LoginLimiter
+
+	// VerifyEmailLimiter limits the verification emails resent to each user and from each IP address
+	VerifyEmailLimiter *adapter.LoginLimiter
+
+	// StatusQueryCoalescer merges identical concurrent status, release and revision queries for a stack into a single call
+	// to the cluster, and serves their results to repeat queries for a few seconds
+	StatusQueryCoalescer *coalesce.Coalescer
+
+	// Supervisor runs the server's background components, restarting them when they panic or fail
+	Supervisor *supervisor.Supervisor
+
+	// EventBuffer queues the porter app events written through Repo and writes them in batches. Nil if events are
+	// written as they are created
+	EventBuffer *eventbuffer.Buffer
+
+	// Cleaner deletes expired sessions and token caches, on a schedule and when an instance admin triggers a cleanup
+	Cleaner *cleanup.Cleaner
+
+	// AdminOverview periodically collects the totals and deploys of every project for the overview read by instance admins
+	AdminOverview *adminoverview.Collector
+
+	// DebugRecorder records the API calls of projects with an active debug recording to the object store. It is nil
+	// if no store is configured, in which case recordings cannot be started.
+	DebugRecorder *debugrecording.Recorder
+
+	// ClusterTunnel holds the connections of the tunnel agents connected to this replica of the server, and routes the
+	// requests for their clusters through them
+	ClusterTunnel *tunnel.Hub
+
 	TelemetryConfig telemetry.TracerConfig
 }
 
@@ -5,6 +5,9 @@ import "time"
 // ServerConf is the server configuration
 type ServerConf struct {
 	Debug bool `env:"DEBUG,default=false"`
+	// VerboseRequestLogging logs the redacted body of every request and response along with the request, for local
+	// development
+	VerboseRequestLogging bool `env:"VERBOSE_REQUEST_LOGGING,default=false"`
 
 	ServerURL string `env:"SERVER_URL,default=http://localhost:8080"`
 
@@ -29,10 +32,55 @@ type ServerConf struct {
 	IsTesting            bool          `env:"IS_TESTING,default=false"`
 	AppRootDomain        string        `env:"APP_ROOT_DOMAIN,default=porter.run"`
 
+	// SessionMaxLifetime is how long a session lasts after it is created, however often it is used
+	SessionMaxLifetime time.Duration `env:"SESSION_MAX_LIFETIME,default=720h"`
+	// SessionIdleTimeout is how long a session lasts after an authenticated request last used it. Zero disables the idle timeout
+	SessionIdleTimeout time.Duration `env:"SESSION_IDLE_TIMEOUT,default=168h"`
+
+	// CustomDomainClusterIssuer is the cert-manager ClusterIssuer which issues the certificates of the custom domains
+	// set in porter.yaml, on clusters which have cert-manager installed
+	CustomDomainClusterIssuer string `env:"CUSTOM_DOMAIN_CLUSTER_ISSUER,default=letsencrypt-prod"`
+
+	// NetworkPolicyIngressNamespace is the namespace of the ingress controller, which the network policies of web services
+	// allow traffic from on clusters which have network policies enabled
+	NetworkPolicyIngressNamespace string `env:"NETWORK_POLICY_INGRESS_NAMESPACE,default=ingress-nginx"`
+
+	// RequestTimeoutRead is the time budget of GET requests. Zero disables the timeout
+	RequestTimeoutRead time.Duration `env:"REQUEST_TIMEOUT_READ,default=30s"`
+	// RequestTimeoutWrite is the time budget of requests with other methods. Zero disables the timeout
+	RequestTimeoutWrite time.Duration `env:"REQUEST_TIMEOUT_WRITE,default=60s"`
+	// RequestTimeoutLong is the time budget of endpoints which deploy, which extends past SERVER_TIMEOUT_WRITE. Zero disables the timeout
+	RequestTimeoutLong time.Duration `env:"REQUEST_TIMEOUT_LONG,default=15m"`
+
 	DefaultApplicationHelmRepoURL string `env:"HELM_APP_REPO_URL,default=https://charts.getporter.dev"`
 	DefaultAddonHelmRepoURL       string `env:"HELM_ADD_ON_REPO_URL,default=https://chart-addons.getporter.dev"`
 
 	BasicLoginEnabled bool `env:"BASIC_LOGIN_ENABLED,default=true"`
+	// PasswordMinLength is the minimum number of characters of the passwords users sign up or reset their password with
+	PasswordMinLength int `env:"PASSWORD_MIN_LENGTH,default=8"`
+	// PasswordMinCharacterClasses is the minimum number of lowercase letters, uppercase letters, digits and symbols which passwords must mix
+	PasswordMinCharacterClasses int `env:"PASSWORD_MIN_CHARACTER_CLASSES,default=2"`
+	// PasswordRejectCommon rejects passwords which are on a list of commonly used passwords
+	PasswordRejectCommon bool `env:"PASSWORD_REJECT_COMMON,default=true"`
+	// LoginRateLimitWindow is the period over which login attempts are counted for the per-email and per-IP limits
+	LoginRateLimitWindow time.Duration `env:"LOGIN_RATE_LIMIT_WINDOW,default=1m"`
+	// LoginRateLimitPerEmail is the number of login attempts allowed for an email within the window. Zero disables the limit
+	LoginRateLimitPerEmail int `env:"LOGIN_RATE_LIMIT_PER_EMAIL,default=10"`
+	// LoginRateLimitPerIP is the number of login attempts allowed from an IP address within the window. Zero disables the limit
+	LoginRateLimitPerIP int `env:"LOGIN_RATE_LIMIT_PER_IP,default=50"`
+	// LoginLockoutThreshold is the number of consecutive failed logins after which an email is locked out. Zero disables the lockout
+	LoginLockoutThreshold int `env:"LOGIN_LOCKOUT_THRESHOLD,default=5"`
+	// LoginLockoutDuration is how long an email is locked out for, and how long its failed logins are remembered
+	LoginLockoutDuration time.Duration `env:"LOGIN_LOCKOUT_DURATION,default=15m"`
+	// LoginTrustedProxies is the number of proxies in front of the server which append to the X-Forwarded-For header. Logins are
+	// limited by the address the outermost of them received the request from, or by the address of the connection if zero
+	LoginTrustedProxies int `env:"LOGIN_TRUSTED_PROXIES,default=0"`
+	// VerifyEmailResendWindow is the period over which verification emails resent to a user are counted
+	VerifyEmailResendWindow time.Duration `env:"VERIFY_EMAIL_RESEND_WINDOW,default=1h"`
+	// VerifyEmailResendPerUser is the number of verification emails which can be resent to a user within the window. Zero disables the limit
+	VerifyEmailResendPerUser int `env:"VERIFY_EMAIL_RESEND_PER_USER,default=5"`
+	// VerifyEmailResendPerIP is the number of verification emails which can be resent from an IP address within the window. Zero disables the limit
+	VerifyEmailResendPerIP int `env:"VERIFY_EMAIL_RESEND_PER_IP,default=20"`
 
 	GithubClientID     string `env:"GITHUB_CLIENT_ID"`
 	GithubClientSecret string `env:"GITHUB_CLIENT_SECRET"`
@@ -120,6 +168,9 @@ type ServerConf struct {
 	PprofEnabled    bool `env:"PPROF_ENABLED,default=false"`
 	ProvisionerTest bool `env:"PROVISIONER_TEST,default=false"`
 
+	// APIDocsUIEnabled serves a page at /api/docs which renders the API reference served at /api/swagger.json
+	APIDocsUIEnabled bool `env:"API_DOCS_UI_ENABLED,default=false"`
+
 	// Disable filtering for project creation
 	DisableAllowlist bool `env:"DISABLE_ALLOWLIST,default=true"`
 
@@ -130,6 +181,16 @@ type ServerConf struct {
 	// imagePullSecrets into a kubernetes deployment (Porter application)
 	DisablePullSecretsInjection bool `env:"DISABLE_PULL_SECRETS_INJECTION,default=false"`
 
+	// LowercasePorterAppNames lowercases the mixed-case names porter apps are created or renamed with, instead of
+	// refusing them, for clients which predate app names being lowercase
+	LowercasePorterAppNames bool `env:"LOWERCASE_PORTER_APP_NAMES,default=false"`
+
+	// HelmTimeout bounds the helm installs and upgrades of porter apps whose deploy does not set a timeout
+	HelmTimeout time.Duration `env:"HELM_TIMEOUT,default=5m"`
+	// HelmMaxTimeout is the longest timeout a deploy of a porter app can request. It must be at most REQUEST_TIMEOUT_LONG,
+	// which deploys run within. Zero allows any timeout up to REQUEST_TIMEOUT_LONG
+	HelmMaxTimeout time.Duration `env:"HELM_MAX_TIMEOUT,default=15m"`
+
 	// EnableAutoPreviewBranchDeploy is used to enable preview branch deployments automatically
 	// The default behaviour is to automatically create preview deployment against a deploy branch
 	EnableAutoPreviewBranchDeploy bool `env:"ENABLE_AUTO_PREVIEW_BRANCH_DEPLOY,default=true"`
@@ -145,6 +206,156 @@ type ServerConf struct {
 	// EnableSandbox configures the API server to hit the endpoints designed for Porter's sandbox instance
 	EnableSandbox bool `env:"ENABLE_SANDBOX"`
 
+	// EnableStatusQueryCoalescing makes identical concurrent status and release queries for a stack share a single call to the cluster
+	EnableStatusQueryCoalescing bool `env:"ENABLE_STATUS_QUERY_COALESCING,default=true"`
+	// StatusQueryCacheTTL is how long the results of status, release and revision queries for a stack are served to repeat
+	// queries. Zero only shares the results of concurrent queries
+	StatusQueryCacheTTL time.Duration `env:"STATUS_QUERY_CACHE_TTL,default=5s"`
+
+	// MaxStreamsPerUser caps the number of streaming connections (logs, status, provisioning) a single user can have open. Zero is unlimited
+	MaxStreamsPerUser int `env:"MAX_STREAMS_PER_USER,default=25"`
+	// MaxStreamsPerProject caps the number of streaming connections open across all users of a project. Zero is unlimited
+	MaxStreamsPerProject int `env:"MAX_STREAMS_PER_PROJECT,default=100"`
+	// MaxStreams caps the number of streaming connections open on the server. Zero is unlimited
+	MaxStreams int `env:"MAX_STREAMS,default=2000"`
+	// StreamIdleTimeout closes streaming connections which have not sent or received a message for this long. Zero disables the timeout
+	StreamIdleTimeout time.Duration `env:"STREAM_IDLE_TIMEOUT,default=30m"`
+	// StreamDrainTimeout is how long the server waits on shutdown for streaming connections to close after they are sent a going away message
+	StreamDrainTimeout time.Duration `env:"STREAM_DRAIN_TIMEOUT,default=10s"`
+
+	// BuildContextWarningSizeMB is the build context size reported by the CLI above which build settings validation warns the user. Zero disables the warning
+	BuildContextWarningSizeMB int64 `env:"BUILD_CONTEXT_WARNING_SIZE_MB,default=500"`
+
+	// ResourceCacheRedisEnabled stores cached registry tags and helm repo indexes in the redis instance set by the REDIS_ env vars, rather than in memory
+	ResourceCacheRedisEnabled bool `env:"RESOURCE_CACHE_REDIS_ENABLED,default=false"`
+	// ResourceCacheSize is the number of entries kept by the in-memory cache when redis is not enabled
+	ResourceCacheSize int `env:"RESOURCE_CACHE_SIZE,default=1000"`
+	// RegistryTagsCacheTTL is how long the list of tags in a registry repository is cached for
+	RegistryTagsCacheTTL time.Duration `env:"REGISTRY_TAGS_CACHE_TTL,default=2m"`
+	// HelmRepoIndexCacheTTL is how long the index of a connected helm repo is cached for
+	HelmRepoIndexCacheTTL time.Duration `env:"HELM_REPO_INDEX_CACHE_TTL,default=10m"`
+
+	// DeployLockTimeout is how long a deploy of a stack waits for another deploy of the same stack to finish before it is rejected
+	DeployLockTimeout time.Duration `env:"DEPLOY_LOCK_TIMEOUT,default=10s"`
+	// DeployLockTTL is how long the lock held while a stack is deployed lasts if the server deploying it goes away without releasing it.
+	// Deploys whose helm timeout is longer hold the lock for their timeout plus a margin. It must be at least HELM_MAX_TIMEOUT
+	DeployLockTTL time.Duration `env:"DEPLOY_LOCK_TTL,default=20m"`
+
+	// PorterAppRenameGracePeriod is how long requests for the previous name of a renamed app are served for the app
+	PorterAppRenameGracePeriod time.Duration `env:"PORTER_APP_RENAME_GRACE_PERIOD,default=720h"`
+
+	// ChartVerificationMode is how charts downloaded from helm repos are checked against ChartVerificationPins or the provenance
+	// files signed by a key of ChartVerificationKeyringPath: off, warn (charts which fail the check are still installed) or enforce
+	ChartVerificationMode string `env:"CHART_VERIFICATION_MODE,default=off"`
+	// ChartVerificationKeyringPath is the path of the PGP public keyring the provenance files of charts can be signed with
+	ChartVerificationKeyringPath string `env:"CHART_VERIFICATION_KEYRING_PATH"`
+	// ChartVerificationPins are the digests charts must match, as name@version=sha256:<hex> separated by semicolons
+	ChartVerificationPins []string `env:"CHART_VERIFICATION_PINS"`
+
+	// ComponentMaxRestarts is the number of consecutive restarts a background component is allowed after a panic or error before it is marked as
+	// failed. A failed critical component fails the readiness check
+	ComponentMaxRestarts int `env:"COMPONENT_MAX_RESTARTS,default=10"`
+	// ComponentMaxBackoff caps the wait between restarts of a failed background component
+	ComponentMaxBackoff time.Duration `env:"COMPONENT_MAX_BACKOFF,default=1m"`
+	// ComponentStableAfter is how long a background component must run before its consecutive restarts are reset
+	ComponentStableAfter time.Duration `env:"COMPONENT_STABLE_AFTER,default=5m"`
+
+	// LogAlertEvaluationInterval is how often log alert rules are matched against their apps' logs. Zero disables log alerts
+	LogAlertEvaluationInterval time.Duration `env:"LOG_ALERT_EVALUATION_INTERVAL,default=1m"`
+	// LogAlertClusterTimeout bounds the time spent reading logs from a single cluster in each evaluation, so that an unreachable cluster cannot stall the others
+	LogAlertClusterTimeout time.Duration `env:"LOG_ALERT_CLUSTER_TIMEOUT,default=30s"`
+	// LogAlertMaxLinesPerApp is the number of the most recent log lines of an app which are read in each evaluation
+	LogAlertMaxLinesPerApp uint `env:"LOG_ALERT_MAX_LINES_PER_APP,default=5000"`
+	// LogAlertMaxRulesPerApp caps the number of log alert rules which can be created on a single app
+	LogAlertMaxRulesPerApp int `env:"LOG_ALERT_MAX_RULES_PER_APP,default=10"`
+
+	// WebhookSigningKey signs the payloads sent to webhooks, such as the webhook channel of log alerts. Payloads are not signed if it is empty
+	WebhookSigningKey string `env:"WEBHOOK_SIGNING_KEY"`
+	// WebhookDeliveryMaxAttempts is the number of attempts made to deliver an event to a webhook before giving up
+	WebhookDeliveryMaxAttempts uint `env:"WEBHOOK_DELIVERY_MAX_ATTEMPTS,default=3"`
+	// WebhookDeliveryRetention is how long the record of each webhook delivery attempt is kept. Zero keeps them forever
+	WebhookDeliveryRetention time.Duration `env:"WEBHOOK_DELIVERY_RETENTION,default=168h"`
+	// WebhookRedeliveryLimit caps the manual redeliveries to a single webhook in an hour
+	WebhookRedeliveryLimit int64 `env:"WEBHOOK_REDELIVERY_LIMIT,default=10"`
+
+	// UsageRollupInterval is how often the daily usage rollups used by usage reports are updated. Zero disables the rollups
+	UsageRollupInterval time.Duration `env:"USAGE_ROLLUP_INTERVAL,default=1h"`
+	// UsageRollupBackfillDays is how many past days are rolled up if they are missing, such as when the rollups are first enabled
+	UsageRollupBackfillDays int `env:"USAGE_ROLLUP_BACKFILL_DAYS,default=90"`
+	// UsageRollupSettleDays is the number of days after a day ends that its rollup is recomputed, to count deploys and builds which finish late
+	UsageRollupSettleDays int `env:"USAGE_ROLLUP_SETTLE_DAYS,default=2"`
+	// PorterAppEventRetention is how long porter app events are kept. Events are only deleted once their day is rolled up. Zero keeps events forever
+	PorterAppEventRetention time.Duration `env:"PORTER_APP_EVENT_RETENTION,default=0"`
+
+	// BulkRedeployPollInterval is how often bulk redeploys are checked for apps to redeploy. Zero disables bulk redeploys
+	BulkRedeployPollInterval time.Duration `env:"BULK_REDEPLOY_POLL_INTERVAL,default=5s"`
+	// BulkRedeployOperationTimeout bounds the time spent redeploying a single app in a bulk redeploy
+	BulkRedeployOperationTimeout time.Duration `env:"BULK_REDEPLOY_OPERATION_TIMEOUT,default=10m"`
+
+	// InactivityCleanupInterval is how often inactivity policies are evaluated to pause and delete idle apps. Zero disables inactivity policies
+	InactivityCleanupInterval time.Duration `env:"INACTIVITY_CLEANUP_INTERVAL,default=1h"`
+	// InactivityCleanupClusterTimeout bounds the time spent pausing and deleting the idle apps of a single cluster in each evaluation
+	InactivityCleanupClusterTimeout time.Duration `env:"INACTIVITY_CLEANUP_CLUSTER_TIMEOUT,default=2m"`
+
+	// ScalingScheduleInterval is how often the scaling schedules of services are evaluated. Windows open and close within one interval of their boundary. Zero disables scaling schedules
+	ScalingScheduleInterval time.Duration `env:"SCALING_SCHEDULE_INTERVAL,default=1m"`
+	// ScalingScheduleClusterTimeout bounds the time spent scaling the services of a single cluster in each evaluation
+	ScalingScheduleClusterTimeout time.Duration `env:"SCALING_SCHEDULE_CLUSTER_TIMEOUT,default=2m"`
+
+	// CertExpiryCheckInterval is how often the certificates serving the domains of apps are read. Zero disables the checks, though domains can still be checked from the dashboard
+	CertExpiryCheckInterval time.Duration `env:"CERT_EXPIRY_CHECK_INTERVAL,default=1h"`
+	// CertExpiryCheckClusterTimeout bounds the time spent checking the domains of a single cluster in each check
+	CertExpiryCheckClusterTimeout time.Duration `env:"CERT_EXPIRY_CHECK_CLUSTER_TIMEOUT,default=2m"`
+	// CertExpiryAlertDays are the numbers of days before a certificate expires at which an alert is sent about it
+	CertExpiryAlertDays []uint `env:"CERT_EXPIRY_ALERT_DAYS,default=30;14;3"`
+
+	// PorterAppCleanupTimeout is how long the load balancers and domains of a deleted app have to be released before they are flagged as leaked on its project
+	PorterAppCleanupTimeout time.Duration `env:"PORTER_APP_CLEANUP_TIMEOUT,default=1h"`
+	// PorterAppCleanupInterval is how often the resources of deleted apps are checked for whether they were released. Zero disables the checks, so cleanups stay pending
+	PorterAppCleanupInterval time.Duration `env:"PORTER_APP_CLEANUP_INTERVAL,default=1m"`
+	// PorterAppCleanupClusterTimeout bounds the time spent checking the resources of a single cluster in each check
+	PorterAppCleanupClusterTimeout time.Duration `env:"PORTER_APP_CLEANUP_CLUSTER_TIMEOUT,default=30s"`
+
+	// RegistryCredentialCheckInterval is how often the credentials of the registries which failed to generate a pull secret are checked again. Zero disables the checks
+	RegistryCredentialCheckInterval time.Duration `env:"REGISTRY_CREDENTIAL_CHECK_INTERVAL,default=15m"`
+
+	// ExpiredRowCleanupInterval is how often expired sessions and token caches are deleted. Zero disables the scheduled cleanup, though instance admins can still run one
+	ExpiredRowCleanupInterval time.Duration `env:"EXPIRED_ROW_CLEANUP_INTERVAL,default=1h"`
+	// ExpiredRowCleanupBatchSize is the number of rows deleted by each statement of a cleanup
+	ExpiredRowCleanupBatchSize int `env:"EXPIRED_ROW_CLEANUP_BATCH_SIZE,default=500"`
+	// ExpiredRowCleanupMaxRowsPerRun caps the rows deleted from each table in a single cleanup. The rest are deleted by the next cleanups
+	ExpiredRowCleanupMaxRowsPerRun int `env:"EXPIRED_ROW_CLEANUP_MAX_ROWS_PER_RUN,default=10000"`
+	// ExpiredRowCleanupMaxDuration bounds the time spent deleting rows in a single cleanup, across all tables
+	ExpiredRowCleanupMaxDuration time.Duration `env:"EXPIRED_ROW_CLEANUP_MAX_DURATION,default=1m"`
+	// TokenCacheCleanupGracePeriod is how long after it expires a token cache is kept
+	TokenCacheCleanupGracePeriod time.Duration `env:"TOKEN_CACHE_CLEANUP_GRACE_PERIOD,default=24h"`
+
+	// AdminOverviewInterval is how often the totals and deploys of the instance overview read by instance admins are collected. Zero disables the collection, so the overview only reports background components and errors
+	AdminOverviewInterval time.Duration `env:"ADMIN_OVERVIEW_INTERVAL,default=5m"`
+	// AdminOverviewDays is the number of days of deploy volume in the instance overview
+	AdminOverviewDays int `env:"ADMIN_OVERVIEW_DAYS,default=30"`
+
+	// PorterAppEventFlushInterval is the longest a porter app event is queued before it is written in a batch. Zero writes every event as it is created
+	PorterAppEventFlushInterval time.Duration `env:"PORTER_APP_EVENT_FLUSH_INTERVAL,default=0"`
+	// PorterAppEventBatchSize is the number of queued porter app events which are written before the flush interval
+	PorterAppEventBatchSize int `env:"PORTER_APP_EVENT_BATCH_SIZE,default=100"`
+	// PorterAppEventMaxQueueSize bounds the queued porter app events. Events created while the queue is full are written as they are created
+	PorterAppEventMaxQueueSize int `env:"PORTER_APP_EVENT_MAX_QUEUE_SIZE,default=1000"`
+
+	// DebugRecordingStore is where the API calls of projects with a debug recording are written, one of s3 or file. If it is unset, debug recordings cannot be started
+	DebugRecordingStore string `env:"DEBUG_RECORDING_STORE"`
+	// DebugRecordingS3Bucket, DebugRecordingS3Region and the access keys configure the s3 store. If no access key is set, the default AWS credential chain is used
+	DebugRecordingS3Bucket          string `env:"DEBUG_RECORDING_S3_BUCKET"`
+	DebugRecordingS3Region          string `env:"DEBUG_RECORDING_S3_REGION,default=us-east-1"`
+	DebugRecordingS3AccessKeyID     string `env:"DEBUG_RECORDING_S3_ACCESS_KEY_ID"`
+	DebugRecordingS3SecretAccessKey string `env:"DEBUG_RECORDING_S3_SECRET_ACCESS_KEY"`
+	// DebugRecordingFilePath is the directory of the file store
+	DebugRecordingFilePath string `env:"DEBUG_RECORDING_FILE_PATH,default=/porter/debug-recordings"`
+	// DebugRecordingMaxExchanges caps the API calls written for a single recording
+	DebugRecordingMaxExchanges uint `env:"DEBUG_RECORDING_MAX_EXCHANGES,default=5000"`
+	// DebugRecordingMaxDuration is the longest a recording can run before it expires
+	DebugRecordingMaxDuration time.Duration `env:"DEBUG_RECORDING_MAX_DURATION,default=24h"`
+
 	// NATSUrl is the URL of the NATS cluster. This is required if ENABLE_CAPI_PROVISIONER is true
 	NATSUrl string `env:"NATS_URL"`
 
@@ -152,6 +363,17 @@ type ServerConf struct {
 	TelemetryName string `env:"TELEMETRY_NAME"`
 	// TelemetryCollectorURL is the URL (host:port) for collecting spans
 	TelemetryCollectorURL string `env:"TELEMETRY_COLLECTOR_URL,default=localhost:4317"`
+	// TelemetryDisabled turns tracing off, for installs which do not run a collector
+	TelemetryDisabled bool `env:"TELEMETRY_DISABLED,default=false"`
+	// TelemetryProtocol is the protocol spans are exported with, either grpc or http/protobuf
+	TelemetryProtocol string `env:"TELEMETRY_PROTOCOL,default=grpc"`
+	// TelemetrySampler decides which traces are recorded: always, never, ratio or parent_based
+	TelemetrySampler string `env:"TELEMETRY_SAMPLER,default=parent_based"`
+	// TelemetrySampleRatio is the ratio of traces recorded by the ratio and parent_based samplers. Instance admins can
+	// change it at runtime
+	TelemetrySampleRatio float64 `env:"TELEMETRY_SAMPLE_RATIO,default=1"`
+	// TelemetryEnvironment is recorded as the deployment environment of spans
+	TelemetryEnvironment string `env:"TELEMETRY_ENVIRONMENT"`
 }
 
 // DBConf is the database configuration: if generated from environment variables,
@@ -165,7 +387,16 @@ type DBConf struct {
 	Username string `env:"DB_USER,default=porter"`
 	Password string `env:"DB_PASS,default=porter"`
 	DbName   string `env:"DB_NAME,default=porter"`
-	ForceSSL bool   `env:"DB_FORCE_SSL,default=false"`
+	// ForceSSL is kept for backwards compatibility: if set, an SSLMode of disable is upgraded to require
+	ForceSSL bool `env:"DB_FORCE_SSL,default=false"`
+
+	// SSLMode is the postgres sslmode to connect with, one of disable, require or verify-full
+	SSLMode string `env:"DB_SSL_MODE,default=require"`
+	// SSLRootCertPath is the path to the CA certificate used to verify the database server certificate
+	SSLRootCertPath string `env:"DB_SSL_ROOT_CERT"`
+	// SSLCertPath and SSLKeyPath are the paths to the client certificate and key, used for mTLS
+	SSLCertPath string `env:"DB_SSL_CERT"`
+	SSLKeyPath  string `env:"DB_SSL_KEY"`
 
 	SQLLite     bool   `env:"SQL_LITE,default=false"`
 	SQLLitePath string `env:"SQL_LITE_PATH,default=/porter/porter.db"`
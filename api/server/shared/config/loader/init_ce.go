@@ -3,6 +3,7 @@
 
 package loader
 
-func init() {
-	sharedInit()
+// editionInit sets up the instance after the database is connected. The community edition has nothing to set up.
+func editionInit() error {
+	return nil
 }
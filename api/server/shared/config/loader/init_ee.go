@@ -9,9 +9,8 @@ import (
 	"github.com/porter-dev/porter/internal/billing"
 )
 
-func init() {
-	sharedInit()
-
+// editionInit sets up the instance after the database is connected
+func editionInit() error {
 	InstanceDB.AutoMigrate(
 		&models.ProjectBilling{},
 		&models.UserBilling{},
@@ -30,11 +29,12 @@ func init() {
 		var err error
 
 		InstanceBillingManager, err = eeBilling.NewClient(serverURL, publicServerURL, apiKey)
-
 		if err != nil {
-			panic(err)
+			return err
 		}
 	} else {
 		InstanceBillingManager = &billing.NoopBillingManager{}
 	}
+
+	return nil
 }
@@ -9,6 +9,8 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
+	"time"
 
 	gorillaws "github.com/gorilla/websocket"
 	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
@@ -19,20 +21,28 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/websocket"
 	"github.com/porter-dev/porter/ee/integrations/vault"
 	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/adminoverview"
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/auth/sessionstore"
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/billing"
+	"github.com/porter-dev/porter/internal/cleanup"
+	"github.com/porter-dev/porter/internal/coalesce"
+	"github.com/porter-dev/porter/internal/debugrecording"
 	"github.com/porter-dev/porter/internal/features"
+	helmloader "github.com/porter-dev/porter/internal/helm/loader"
 	"github.com/porter-dev/porter/internal/helm/urlcache"
 	"github.com/porter-dev/porter/internal/integrations/cloudflare"
 	"github.com/porter-dev/porter/internal/integrations/dns"
 	"github.com/porter-dev/porter/internal/integrations/powerdns"
+	"github.com/porter-dev/porter/internal/kubernetes/tunnel"
 	"github.com/porter-dev/porter/internal/notifier"
 	"github.com/porter-dev/porter/internal/notifier/sendgrid"
 	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/eventbuffer"
 	"github.com/porter-dev/porter/internal/repository/gorm"
+	"github.com/porter-dev/porter/internal/supervisor"
 	"github.com/porter-dev/porter/internal/telemetry"
 	lr "github.com/porter-dev/porter/pkg/logger"
 	"github.com/porter-dev/porter/provisioner/client"
@@ -43,6 +53,11 @@ var (
 	InstanceBillingManager billing.BillingManager
 	InstanceEnvConf        *envloader.EnvConf
 	InstanceDB             *pgorm.DB
+
+	// instanceInitOnce connects to the database the first time a config is loaded, rather than when the package is
+	// imported, so that the server can set up its environment first, such as in dev mode
+	instanceInitOnce sync.Once
+	instanceInitErr  error
 )
 
 type EnvConfigLoader struct {
@@ -53,29 +68,50 @@ func NewEnvLoader(version string) config.ConfigLoader {
 	return &EnvConfigLoader{version}
 }
 
-func sharedInit() {
+func sharedInit() error {
 	var err error
 	InstanceEnvConf, _ = envloader.FromEnv()
 
 	InstanceDB, err = adapter.New(InstanceEnvConf.DBConf)
-
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	InstanceBillingManager = &billing.NoopBillingManager{}
+
+	return nil
+}
+
+func instanceInit() error {
+	instanceInitOnce.Do(func() {
+		if instanceInitErr = sharedInit(); instanceInitErr != nil {
+			return
+		}
+
+		instanceInitErr = editionInit()
+	})
+
+	return instanceInitErr
 }
 
 func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 	// ctx := context.Background()
 
-	envConf := InstanceEnvConf
-	sc := envConf.ServerConf
+	if err := instanceInit(); err != nil {
+		return nil, fmt.Errorf("error initializing instance: %w", err)
+	}
 
+	envConf := InstanceEnvConf
 	if envConf == nil {
 		return nil, errors.New("nil environment config passed to loader")
 	}
 
+	sc := envConf.ServerConf
+
+	if err := validateTimeouts(sc); err != nil {
+		return nil, fmt.Errorf("invalid timeout config: %w", err)
+	}
+
 	var instanceCredentialBackend credentials.CredentialStorage
 	if envConf.DBConf.VaultEnabled {
 		if envConf.DBConf.VaultAPIKey == "" || envConf.DBConf.VaultServerURL == "" || envConf.DBConf.VaultPrefix == "" {
@@ -112,6 +148,17 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 	res.Repo = gorm.NewRepository(InstanceDB, &key, instanceCredentialBackend)
 	res.Logger.Info().Msg("Created new gorm repository")
 
+	// porter app events are written through the buffer everywhere the repository is used, so it is wrapped first
+	if sc.PorterAppEventFlushInterval > 0 {
+		res.EventBuffer = eventbuffer.New(res.Repo.PorterAppEvent(), eventbuffer.Options{
+			FlushInterval: sc.PorterAppEventFlushInterval,
+			BatchSize:     sc.PorterAppEventBatchSize,
+			MaxQueueSize:  sc.PorterAppEventMaxQueueSize,
+			Logger:        res.Logger,
+		})
+		res.Repo = eventbuffer.WrapRepository(res.Repo, res.EventBuffer)
+	}
+
 	res.Logger.Info().Msg("Creating new session store")
 	// create the session store
 	res.Store, err = sessionstore.NewStore(
@@ -119,6 +166,8 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 			SessionRepository: res.Repo.Session(),
 			CookieSecrets:     envConf.ServerConf.CookieSecrets,
 			Insecure:          envConf.ServerConf.CookieInsecure,
+			MaxLifetime:       envConf.ServerConf.SessionMaxLifetime,
+			IdleTimeout:       envConf.ServerConf.SessionIdleTimeout,
 		},
 	)
 
@@ -282,6 +331,15 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 		},
 	}
 
+	res.ClusterTunnel = tunnel.NewHub(tunnel.HubOptions{})
+
+	res.StreamRegistry = websocket.NewStreamRegistry(websocket.StreamLimits{
+		MaxPerUser:    sc.MaxStreamsPerUser,
+		MaxPerProject: sc.MaxStreamsPerProject,
+		MaxGlobal:     sc.MaxStreams,
+		IdleTimeout:   sc.StreamIdleTimeout,
+	})
+
 	// construct the whitelisted users map
 	wlUsers := make(map[uint]uint)
 
@@ -290,6 +348,88 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 	}
 
 	res.WhitelistedUsers = wlUsers
+	res.StatusQueryCoalescer = coalesce.NewCoalescer(sc.EnableStatusQueryCoalescing, sc.StatusQueryCacheTTL)
+
+	if sc.ResourceCacheRedisEnabled {
+		redisClient, err := adapter.NewRedisClient(envConf.RedisConf)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to redis for the resource cache: %w", err)
+		}
+		res.ResourceCache = adapter.NewRedisCache(redisClient)
+	} else {
+		res.ResourceCache = adapter.NewLRUCache(sc.ResourceCacheSize)
+	}
+
+	loginLimitOpts := adapter.LoginLimitOptions{
+		Window:              sc.LoginRateLimitWindow,
+		MaxAttemptsPerEmail: sc.LoginRateLimitPerEmail,
+		MaxAttemptsPerIP:    sc.LoginRateLimitPerIP,
+		LockoutThreshold:    sc.LoginLockoutThreshold,
+		LockoutDuration:     sc.LoginLockoutDuration,
+	}
+
+	verifyEmailLimitOpts := adapter.LoginLimitOptions{
+		Window:              sc.VerifyEmailResendWindow,
+		MaxAttemptsPerEmail: sc.VerifyEmailResendPerUser,
+		MaxAttemptsPerIP:    sc.VerifyEmailResendPerIP,
+		Scope:               "verify-email",
+	}
+
+	if envConf.RedisConf.Enabled {
+		redisClient, err := adapter.NewRedisClient(envConf.RedisConf)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to redis for locks: %w", err)
+		}
+		res.Locker = adapter.NewRedisLocker(redisClient)
+		res.LoginLimiter = adapter.NewRedisLoginLimiter(redisClient, loginLimitOpts)
+		res.VerifyEmailLimiter = adapter.NewRedisLoginLimiter(redisClient, verifyEmailLimitOpts)
+	} else {
+		res.Locker = adapter.NewDBLocker(res.Repo.Lock())
+		res.LoginLimiter = adapter.NewMemoryLoginLimiter(loginLimitOpts)
+		res.VerifyEmailLimiter = adapter.NewMemoryLoginLimiter(verifyEmailLimitOpts)
+	}
+
+	chartVerificationPolicy, err := helmloader.NewVerificationPolicy(sc.ChartVerificationMode, sc.ChartVerificationKeyringPath, sc.ChartVerificationPins)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart verification config: %w", err)
+	}
+	helmloader.SetVerificationPolicy(chartVerificationPolicy)
+
+	debugRecordingStore, err := getDebugRecordingStore(sc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid debug recording config: %w", err)
+	}
+	if debugRecordingStore != nil {
+		res.DebugRecorder = debugrecording.NewRecorder(debugrecording.RecorderOptions{
+			Repo:         res.Repo.DebugRecording(),
+			Store:        debugRecordingStore,
+			MaxExchanges: sc.DebugRecordingMaxExchanges,
+			CacheTTL:     30 * time.Second,
+		})
+	}
+
+	res.Supervisor = supervisor.New(supervisor.Options{
+		MaxRestarts: sc.ComponentMaxRestarts,
+		MaxBackoff:  sc.ComponentMaxBackoff,
+		StableAfter: sc.ComponentStableAfter,
+		Logger:      res.Logger,
+	})
+
+	res.Cleaner = cleanup.NewCleaner(res.Repo.Session(), res.Repo.TokenCache(), cleanup.Options{
+		Interval:              sc.ExpiredRowCleanupInterval,
+		BatchSize:             sc.ExpiredRowCleanupBatchSize,
+		MaxRowsPerRun:         sc.ExpiredRowCleanupMaxRowsPerRun,
+		MaxDuration:           sc.ExpiredRowCleanupMaxDuration,
+		TokenCacheGracePeriod: sc.TokenCacheCleanupGracePeriod,
+		Logger:                res.Logger,
+	})
+
+	res.AdminOverview = adminoverview.NewCollector(res.Repo, adminoverview.Options{
+		Interval: sc.AdminOverviewInterval,
+		Days:     sc.AdminOverviewDays,
+		Logger:   res.Logger,
+	})
+
 	res.Logger.Info().Msg("Creating URL Cache")
 	res.URLCache = urlcache.Init(sc.DefaultApplicationHelmRepoURL, sc.DefaultAddonHelmRepoURL)
 	res.Logger.Info().Msg("Created URL Cache")
@@ -333,8 +473,14 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 	}
 
 	res.TelemetryConfig = telemetry.TracerConfig{
-		ServiceName:  sc.TelemetryName,
-		CollectorURL: sc.TelemetryCollectorURL,
+		Disabled:       sc.TelemetryDisabled,
+		ServiceName:    sc.TelemetryName,
+		ServiceVersion: e.version,
+		Environment:    sc.TelemetryEnvironment,
+		CollectorURL:   sc.TelemetryCollectorURL,
+		Protocol:       sc.TelemetryProtocol,
+		Sampler:        sc.TelemetrySampler,
+		SampleRatio:    sc.TelemetrySampleRatio,
 	}
 
 	return res, nil
@@ -355,6 +501,37 @@ func getProvisionerServiceClient(sc *env.ServerConf) (*client.Client, error) {
 	return nil, fmt.Errorf("required env vars not set for provisioner")
 }
 
+// validateTimeouts checks that the timeouts of the server do not cut each other short
+func validateTimeouts(sc *env.ServerConf) error {
+	if sc.RequestTimeoutLong > 0 && sc.HelmMaxTimeout > sc.RequestTimeoutLong {
+		return fmt.Errorf("HELM_MAX_TIMEOUT (%s) must be at most REQUEST_TIMEOUT_LONG (%s), or a deploy could outlast the request it runs in", sc.HelmMaxTimeout, sc.RequestTimeoutLong)
+	}
+	if sc.HelmMaxTimeout > 0 && sc.DeployLockTTL < sc.HelmMaxTimeout {
+		return fmt.Errorf("DEPLOY_LOCK_TTL (%s) must be at least HELM_MAX_TIMEOUT (%s), or the lock of a deploy could expire while it runs", sc.DeployLockTTL, sc.HelmMaxTimeout)
+	}
+
+	return nil
+}
+
+// getDebugRecordingStore returns the object store debug recordings are written to, or nil if none is configured
+func getDebugRecordingStore(sc *env.ServerConf) (debugrecording.Store, error) {
+	switch sc.DebugRecordingStore {
+	case "":
+		return nil, nil
+	case "s3":
+		return debugrecording.NewS3Store(debugrecording.S3Options{
+			Region:          sc.DebugRecordingS3Region,
+			AccessKeyID:     sc.DebugRecordingS3AccessKeyID,
+			SecretAccessKey: sc.DebugRecordingS3SecretAccessKey,
+			Bucket:          sc.DebugRecordingS3Bucket,
+		})
+	case "file":
+		return debugrecording.NewFileStore(sc.DebugRecordingFilePath)
+	default:
+		return nil, fmt.Errorf("unknown store %q, must be s3 or file", sc.DebugRecordingStore)
+	}
+}
+
 // createDirectoryRecursively creates a directory and all its parents if they don't exist
 func createDirectoryRecursively(p string) (*os.File, error) {
 	if err := os.MkdirAll(filepath.Dir(p), 0o770); err != nil {
@@ -0,0 +1,29 @@
+package loader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+)
+
+func TestValidateTimeouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		sc      env.ServerConf
+		wantErr bool
+	}{
+		{"defaults", env.ServerConf{RequestTimeoutLong: 15 * time.Minute, HelmMaxTimeout: 15 * time.Minute, DeployLockTTL: 20 * time.Minute}, false},
+		{"deploys outlast their request", env.ServerConf{RequestTimeoutLong: 15 * time.Minute, HelmMaxTimeout: time.Hour, DeployLockTTL: time.Hour}, true},
+		{"requests without a timeout", env.ServerConf{HelmMaxTimeout: time.Hour, DeployLockTTL: time.Hour}, false},
+		{"lock outlasts deploys", env.ServerConf{HelmMaxTimeout: 15 * time.Minute, DeployLockTTL: 20 * time.Minute}, false},
+		{"lock expires during deploys", env.ServerConf{HelmMaxTimeout: time.Hour, DeployLockTTL: 10 * time.Minute}, true},
+		{"deploys without a max timeout", env.ServerConf{DeployLockTTL: 10 * time.Minute}, false},
+	}
+
+	for _, tt := range tests {
+		if err := validateTimeouts(&tt.sc); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %t, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
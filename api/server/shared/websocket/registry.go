@@ -0,0 +1,347 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// closeWriteTimeout is how long we wait to send a close frame to a client before closing the connection
+const closeWriteTimeout = time.Second
+
+var (
+	// ErrStreamRegistryClosed is returned when a stream is opened after the registry has started shutting down
+	ErrStreamRegistryClosed = errors.New("server is shutting down")
+)
+
+// StreamLimitError is returned when opening a stream would exceed one of the registry's caps
+type StreamLimitError struct {
+	// Scope is the cap which was hit: user, project or global
+	Scope string
+	Limit int
+}
+
+func (e *StreamLimitError) Error() string {
+	return fmt.Sprintf("too many open streaming connections: %s limit of %d reached", e.Scope, e.Limit)
+}
+
+// StreamLimits are the caps enforced by a StreamRegistry. A cap of zero is unlimited.
+type StreamLimits struct {
+	MaxPerUser    int
+	MaxPerProject int
+	MaxGlobal     int
+	// IdleTimeout is how long a stream may go without sending or receiving a message before it is closed
+	IdleTimeout time.Duration
+}
+
+// StreamConn is the part of a websocket connection used by the registry to close streams
+type StreamConn interface {
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+	Close() error
+}
+
+// Stream is a streaming connection tracked by a StreamRegistry
+type Stream struct {
+	id        uint64
+	userID    uint
+	projectID uint
+	kind      types.StreamType
+	registry  *StreamRegistry
+
+	// lastActive is the unix nano time of the last message sent or received on the stream
+	lastActive int64
+
+	// mu guards conn and pendingClose
+	mu   sync.Mutex
+	conn StreamConn
+	// pendingClose is set when the stream is closed before its connection is attached
+	pendingClose *closeFrame
+}
+
+type closeFrame struct {
+	code   int
+	reason string
+}
+
+// Attach sets the connection which is closed when the stream is reaped or the registry shuts down. If the
+// stream was closed before the connection was attached, the connection is closed immediately.
+func (s *Stream) Attach(conn StreamConn) {
+	s.Touch()
+
+	s.mu.Lock()
+	s.conn = conn
+	pending := s.pendingClose
+	s.mu.Unlock()
+
+	if pending != nil {
+		s.closeWithReason(pending.code, pending.reason)
+	}
+}
+
+// Touch records activity on the stream, delaying it from being reaped as idle
+func (s *Stream) Touch() {
+	if s == nil {
+		return
+	}
+
+	atomic.StoreInt64(&s.lastActive, time.Now().UnixNano())
+}
+
+// Release removes the stream from the registry. It is safe to call more than once.
+func (s *Stream) Release() {
+	s.registry.release(s)
+}
+
+// closeWithReason sends a close frame to the client with the given reason, then closes the connection
+func (s *Stream) closeWithReason(code int, reason string) {
+	s.mu.Lock()
+	conn := s.conn
+	if conn == nil {
+		s.pendingClose = &closeFrame{code: code, reason: reason}
+	}
+	s.mu.Unlock()
+
+	if conn == nil {
+		return
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(closeWriteTimeout))
+	_ = conn.Close()
+}
+
+// StreamCounts are the number of open streams, used by the stream metrics endpoint
+type StreamCounts struct {
+	Total  int                      `json:"total"`
+	ByType map[types.StreamType]int `json:"by_type"`
+	// Users is the number of distinct users with an open stream
+	Users int `json:"users"`
+	// Projects is the number of distinct projects with an open stream
+	Projects int `json:"projects"`
+	// MaxPerUser is the highest number of streams open by a single user
+	MaxPerUser int `json:"max_per_user"`
+	// MaxPerProject is the highest number of streams open in a single project
+	MaxPerProject int `json:"max_per_project"`
+}
+
+// StreamRegistry keeps track of every open streaming connection on the server, so that a leaking client cannot
+// exhaust the server's file descriptors
+type StreamRegistry struct {
+	limits StreamLimits
+
+	mu        sync.Mutex
+	nextID    uint64
+	streams   map[uint64]*Stream
+	byUser    map[uint]int
+	byProject map[uint]int
+	closing   bool
+	// drained is closed once the registry is closing and every stream has been released
+	drained chan struct{}
+}
+
+// NewStreamRegistry returns a new StreamRegistry enforcing the given limits
+func NewStreamRegistry(limits StreamLimits) *StreamRegistry {
+	return &StreamRegistry{
+		limits:    limits,
+		streams:   make(map[uint64]*Stream),
+		byUser:    make(map[uint]int),
+		byProject: make(map[uint]int),
+		drained:   make(chan struct{}),
+	}
+}
+
+// Open reserves a stream for the user and project, returning a *StreamLimitError if a cap would be exceeded. This
+// is called before the websocket upgrade, so that the client receives a 429 rather than a dropped connection. A
+// project ID of zero is not counted against any project.
+func (sr *StreamRegistry) Open(userID, projectID uint, kind types.StreamType) (*Stream, error) {
+	if kind == "" {
+		kind = types.StreamTypeOther
+	}
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if sr.closing {
+		return nil, ErrStreamRegistryClosed
+	}
+
+	if sr.limits.MaxGlobal > 0 && len(sr.streams) >= sr.limits.MaxGlobal {
+		return nil, &StreamLimitError{Scope: "global", Limit: sr.limits.MaxGlobal}
+	}
+	if sr.limits.MaxPerUser > 0 && sr.byUser[userID] >= sr.limits.MaxPerUser {
+		return nil, &StreamLimitError{Scope: "user", Limit: sr.limits.MaxPerUser}
+	}
+	if projectID != 0 && sr.limits.MaxPerProject > 0 && sr.byProject[projectID] >= sr.limits.MaxPerProject {
+		return nil, &StreamLimitError{Scope: "project", Limit: sr.limits.MaxPerProject}
+	}
+
+	sr.nextID++
+	stream := &Stream{
+		id:         sr.nextID,
+		userID:     userID,
+		projectID:  projectID,
+		kind:       kind,
+		registry:   sr,
+		lastActive: time.Now().UnixNano(),
+	}
+
+	sr.streams[stream.id] = stream
+	sr.byUser[userID]++
+	if projectID != 0 {
+		sr.byProject[projectID]++
+	}
+
+	return stream, nil
+}
+
+func (sr *StreamRegistry) release(s *Stream) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if _, ok := sr.streams[s.id]; ok {
+		delete(sr.streams, s.id)
+
+		sr.byUser[s.userID]--
+		if sr.byUser[s.userID] <= 0 {
+			delete(sr.byUser, s.userID)
+		}
+		if s.projectID != 0 {
+			sr.byProject[s.projectID]--
+			if sr.byProject[s.projectID] <= 0 {
+				delete(sr.byProject, s.projectID)
+			}
+		}
+	}
+
+	if sr.closing && len(sr.streams) == 0 {
+		sr.closeDrained()
+	}
+}
+
+// closeDrained must be called with sr.mu held
+func (sr *StreamRegistry) closeDrained() {
+	select {
+	case <-sr.drained:
+	default:
+		close(sr.drained)
+	}
+}
+
+// Counts returns the number of open streams, broken down by type
+func (sr *StreamRegistry) Counts() StreamCounts {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	counts := StreamCounts{
+		Total:    len(sr.streams),
+		ByType:   make(map[types.StreamType]int),
+		Users:    len(sr.byUser),
+		Projects: len(sr.byProject),
+	}
+
+	for _, stream := range sr.streams {
+		counts.ByType[stream.kind]++
+	}
+	for _, n := range sr.byUser {
+		if n > counts.MaxPerUser {
+			counts.MaxPerUser = n
+		}
+	}
+	for _, n := range sr.byProject {
+		if n > counts.MaxPerProject {
+			counts.MaxPerProject = n
+		}
+	}
+
+	return counts
+}
+
+// UserCount returns the number of streams open by a user
+func (sr *StreamRegistry) UserCount(userID uint) int {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	return sr.byUser[userID]
+}
+
+// ReapIdle closes every stream which has been silent for longer than the idle timeout, returning the number of
+// streams closed. Reaped streams are released by their handlers once the closed connection unblocks them.
+func (sr *StreamRegistry) ReapIdle(now time.Time) int {
+	if sr.limits.IdleTimeout <= 0 {
+		return 0
+	}
+
+	cutoff := now.Add(-sr.limits.IdleTimeout).UnixNano()
+
+	sr.mu.Lock()
+	idle := make([]*Stream, 0)
+	for _, stream := range sr.streams {
+		if atomic.LoadInt64(&stream.lastActive) < cutoff {
+			idle = append(idle, stream)
+		}
+	}
+	sr.mu.Unlock()
+
+	for _, stream := range idle {
+		stream.closeWithReason(websocket.CloseGoingAway, "idle timeout")
+		stream.Release()
+	}
+
+	return len(idle)
+}
+
+// Run reaps idle streams until the context is cancelled
+func (sr *StreamRegistry) Run(ctx context.Context) {
+	if sr.limits.IdleTimeout <= 0 {
+		return
+	}
+
+	interval := sr.limits.IdleTimeout / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sr.ReapIdle(now)
+		}
+	}
+}
+
+// Shutdown stops new streams from being opened, and sends a going away close frame to every open stream. It returns
+// once every stream has been released, or with the context's error if the deadline passes first.
+func (sr *StreamRegistry) Shutdown(ctx context.Context) error {
+	sr.mu.Lock()
+	sr.closing = true
+	open := make([]*Stream, 0, len(sr.streams))
+	for _, stream := range sr.streams {
+		open = append(open, stream)
+	}
+	if len(sr.streams) == 0 {
+		sr.closeDrained()
+	}
+	sr.mu.Unlock()
+
+	for _, stream := range open {
+		stream.closeWithReason(websocket.CloseGoingAway, "server shutting down")
+	}
+
+	select {
+	case <-sr.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
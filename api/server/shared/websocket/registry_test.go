@@ -0,0 +1,338 @@
+package websocket_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket"
+
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
+)
+
+// fakeConn records the close frame sent to it, and optionally releases its stream once closed in the way a
+// handler would after its connection is closed underneath it
+type fakeConn struct {
+	mu          sync.Mutex
+	closeFrames []string
+	closed      bool
+	onClose     func()
+}
+
+func (f *fakeConn) WriteControl(messageType int, data []byte, _ time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if messageType == gorillaws.CloseMessage {
+		f.closeFrames = append(f.closeFrames, string(data))
+	}
+
+	return nil
+}
+
+func (f *fakeConn) Close() error {
+	f.mu.Lock()
+	alreadyClosed := f.closed
+	f.closed = true
+	onClose := f.onClose
+	f.mu.Unlock()
+
+	if !alreadyClosed && onClose != nil {
+		go onClose()
+	}
+
+	return nil
+}
+
+func (f *fakeConn) closeFrame() (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.closeFrames) == 0 {
+		return "", f.closed
+	}
+
+	return f.closeFrames[0], f.closed
+}
+
+func expectCloseFrame(t *testing.T, conn *fakeConn, code int, reason string) {
+	t.Helper()
+
+	frame, closed := conn.closeFrame()
+	if !closed {
+		t.Fatalf("expected connection to be closed")
+	}
+	if frame != string(gorillaws.FormatCloseMessage(code, reason)) {
+		t.Fatalf("expected close frame with code %d and reason %q, got %q", code, reason, frame)
+	}
+}
+
+func TestStreamRegistryEnforcesCapsConcurrently(t *testing.T) {
+	registry := websocket.NewStreamRegistry(websocket.StreamLimits{
+		MaxPerUser:    10,
+		MaxPerProject: 60,
+		MaxGlobal:     250,
+	})
+
+	const attempts = 1000
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		opened  []*websocket.Stream
+		byScope = make(map[string]int)
+	)
+
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// 50 users spread across 5 projects, each trying to open 20 streams
+			userID := uint(i%50) + 1
+			projectID := uint(i%5) + 1
+
+			stream, err := registry.Open(userID, projectID, types.StreamTypeLogs)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				var limitErr *websocket.StreamLimitError
+				if !errors.As(err, &limitErr) {
+					t.Errorf("expected a stream limit error, got %v", err)
+					return
+				}
+				byScope[limitErr.Scope]++
+				return
+			}
+
+			opened = append(opened, stream)
+		}(i)
+	}
+	wg.Wait()
+
+	// 50 users at 10 streams each would be 500, but each project reaches its cap at 60, so 5 projects allow 300,
+	// and the global cap stops the registry at 250
+	if len(opened) != 250 {
+		t.Fatalf("expected 250 open streams, got %d", len(opened))
+	}
+	if byScope["global"] == 0 {
+		t.Errorf("expected the global cap to reject streams, got %v", byScope)
+	}
+
+	counts := registry.Counts()
+	if counts.Total != 250 || counts.ByType[types.StreamTypeLogs] != 250 {
+		t.Errorf("expected 250 logs streams, got %+v", counts)
+	}
+	if counts.MaxPerUser > 10 {
+		t.Errorf("expected no user over 10 streams, got %d", counts.MaxPerUser)
+	}
+	if counts.MaxPerProject > 60 {
+		t.Errorf("expected no project over 60 streams, got %d", counts.MaxPerProject)
+	}
+
+	// releasing streams, including more than once, frees capacity for new ones
+	for _, stream := range opened {
+		wg.Add(1)
+		go func(stream *websocket.Stream) {
+			defer wg.Done()
+			stream.Release()
+			stream.Release()
+		}(stream)
+	}
+	wg.Wait()
+
+	counts = registry.Counts()
+	if counts.Total != 0 || counts.Users != 0 || counts.Projects != 0 {
+		t.Fatalf("expected no open streams after release, got %+v", counts)
+	}
+
+	if _, err := registry.Open(1, 1, types.StreamTypeLogs); err != nil {
+		t.Fatalf("expected stream to open after release, got %v", err)
+	}
+}
+
+func TestStreamRegistryPerUserAndProjectCaps(t *testing.T) {
+	registry := websocket.NewStreamRegistry(websocket.StreamLimits{
+		MaxPerUser:    2,
+		MaxPerProject: 3,
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := registry.Open(1, 1, types.StreamTypeStatus); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var limitErr *websocket.StreamLimitError
+	_, err := registry.Open(1, 2, types.StreamTypeStatus)
+	if !errors.As(err, &limitErr) || limitErr.Scope != "user" {
+		t.Fatalf("expected user limit error, got %v", err)
+	}
+
+	if _, err := registry.Open(2, 1, types.StreamTypeStatus); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = registry.Open(3, 1, types.StreamTypeStatus)
+	if !errors.As(err, &limitErr) || limitErr.Scope != "project" {
+		t.Fatalf("expected project limit error, got %v", err)
+	}
+
+	// streams without a project, such as user scoped streams, are only counted against the user and global caps
+	if _, err := registry.Open(3, 0, types.StreamTypeStatus); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := registry.UserCount(1); got != 2 {
+		t.Errorf("expected user 1 to have 2 streams, got %d", got)
+	}
+}
+
+func TestStreamRegistryReapsIdleStreams(t *testing.T) {
+	registry := websocket.NewStreamRegistry(websocket.StreamLimits{IdleTimeout: 100 * time.Millisecond})
+
+	const streams = 300
+
+	type openStream struct {
+		stream *websocket.Stream
+		conn   *fakeConn
+	}
+
+	open := make([]openStream, streams)
+	for i := 0; i < streams; i++ {
+		stream, err := registry.Open(uint(i)+1, 1, types.StreamTypeEvents)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conn := &fakeConn{}
+		stream.Attach(conn)
+		open[i] = openStream{stream: stream, conn: conn}
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	// half of the streams send a message before the reaper runs
+	var wg sync.WaitGroup
+	for i := 0; i < streams; i += 2 {
+		wg.Add(1)
+		go func(stream *websocket.Stream) {
+			defer wg.Done()
+			stream.Touch()
+		}(open[i].stream)
+	}
+	wg.Wait()
+
+	reaped := registry.ReapIdle(time.Now())
+	if reaped != streams/2 {
+		t.Fatalf("expected %d idle streams to be reaped, got %d", streams/2, reaped)
+	}
+
+	for i, s := range open {
+		if i%2 == 0 {
+			if _, closed := s.conn.closeFrame(); closed {
+				t.Fatalf("expected active stream %d to stay open", i)
+			}
+			continue
+		}
+
+		expectCloseFrame(t, s.conn, gorillaws.CloseGoingAway, "idle timeout")
+	}
+
+	if counts := registry.Counts(); counts.Total != streams/2 {
+		t.Errorf("expected %d open streams after reaping, got %d", streams/2, counts.Total)
+	}
+}
+
+func TestStreamRegistryShutdownClosesAllStreams(t *testing.T) {
+	registry := websocket.NewStreamRegistry(websocket.StreamLimits{})
+
+	const streams = 500
+
+	conns := make([]*fakeConn, streams)
+	var wg sync.WaitGroup
+	for i := 0; i < streams; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			stream, err := registry.Open(uint(i%20)+1, uint(i%4)+1, types.StreamTypeProvisioning)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			conns[i] = &fakeConn{onClose: stream.Release}
+			stream.Attach(conns[i])
+		}(i)
+	}
+	wg.Wait()
+
+	// a stream reserved before its upgrade has finished is closed as soon as its connection is attached
+	pending, err := registry.Open(100, 1, types.StreamTypeLogs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	shutdownErr := make(chan error)
+	go func() {
+		shutdownErr <- registry.Shutdown(ctx)
+	}()
+
+	// wait for shutdown to start, after which new streams are rejected
+	for {
+		probe, err := registry.Open(200, 1, types.StreamTypeLogs)
+		if errors.Is(err, websocket.ErrStreamRegistryClosed) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		probe.Release()
+		time.Sleep(time.Millisecond)
+	}
+
+	pendingConn := &fakeConn{onClose: pending.Release}
+	pending.Attach(pendingConn)
+
+	if err := <-shutdownErr; err != nil {
+		t.Fatalf("expected shutdown to drain every stream, got %v", err)
+	}
+
+	for _, conn := range append(conns, pendingConn) {
+		expectCloseFrame(t, conn, gorillaws.CloseGoingAway, "server shutting down")
+	}
+
+	if counts := registry.Counts(); counts.Total != 0 {
+		t.Errorf("expected no open streams after shutdown, got %d", counts.Total)
+	}
+}
+
+func TestStreamRegistryShutdownDeadline(t *testing.T) {
+	registry := websocket.NewStreamRegistry(websocket.StreamLimits{})
+
+	// this stream's handler never exits, so it is never released
+	stream, err := registry.Open(1, 1, types.StreamTypeLogs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := &fakeConn{}
+	stream.Attach(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := registry.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected shutdown to hit the drain deadline, got %v", err)
+	}
+
+	expectCloseFrame(t, conn, gorillaws.CloseGoingAway, "server shutting down")
+}
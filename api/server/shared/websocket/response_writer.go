@@ -12,6 +12,13 @@ import (
 type WebsocketSafeReadWriter struct {
 	conn *websocket.Conn
 	mu   sync.Mutex
+	// stream is the registered stream for this connection, which is kept alive by every message sent or received
+	stream *Stream
+}
+
+// SetStream sets the registered stream whose activity is recorded on every message sent or received
+func (w *WebsocketSafeReadWriter) SetStream(stream *Stream) {
+	w.stream = stream
 }
 
 func (w *WebsocketSafeReadWriter) WriteJSON(v interface{}) error {
@@ -28,6 +35,8 @@ func (w *WebsocketSafeReadWriter) WriteJSON(v interface{}) error {
 		return err
 	}
 
+	w.stream.Touch()
+
 	return nil
 }
 
@@ -45,11 +54,18 @@ func (w *WebsocketSafeReadWriter) Write(data []byte) (int, error) {
 		}
 	}
 
+	w.stream.Touch()
+
 	return len(data), nil
 }
 
 func (w *WebsocketSafeReadWriter) ReadMessage() (messageType int, p []byte, err error) {
-	return w.conn.ReadMessage()
+	messageType, p, err = w.conn.ReadMessage()
+	if err == nil {
+		w.stream.Touch()
+	}
+
+	return messageType, p, err
 }
 
 func (w *WebsocketSafeReadWriter) Close() error {
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminOverviewSchemaVersion is the version of the schema of AdminOverview. Fields are only added within a version; it
+// is incremented when a field is renamed, removed or changes meaning, so that dashboards built against the overview
+// can detect the change
+const AdminOverviewSchemaVersion = 1
+
+// DeployDaySource is where the deploys of a day in an admin overview were counted from
+type DeployDaySource string
+
+const (
+	// DeployDaySource_Rollup is a day counted from the daily usage rollups
+	DeployDaySource_Rollup DeployDaySource = "rollup"
+	// DeployDaySource_Events is a recent day which has not been rolled up yet, counted from its deploy events
+	DeployDaySource_Events DeployDaySource = "events"
+	// DeployDaySource_Missing is a day which was not rolled up, so its deploys are unknown and reported as zero
+	DeployDaySource_Missing DeployDaySource = "missing"
+)
+
+// AdminOverview is the aggregate view of every project of the instance, which only the instance admin can read
+type AdminOverview struct {
+	SchemaVersion int `json:"schema_version"`
+	// CollectedAt is when the totals, deploys and slowest deploys were last collected. They are collected
+	// periodically, so they are nil until the first collection
+	CollectedAt *time.Time `json:"collected_at"`
+	// GeneratedAt is when the overview was returned. Components and errors are read live
+	GeneratedAt time.Time `json:"generated_at"`
+
+	Totals         *AdminOverviewTotals      `json:"totals"`
+	Deploys        []AdminOverviewDeployDay  `json:"deploys"`
+	SlowestDeploys []AdminOverviewSlowDeploy `json:"slowest_deploys"`
+	Components     []AdminOverviewComponent  `json:"components"`
+	Errors         []AdminOverviewErrorCount `json:"errors"`
+	ErrorsWindow   AdminOverviewErrorsWindow `json:"errors_window"`
+}
+
+// AdminOverviewTotals counts the resources of the instance
+type AdminOverviewTotals struct {
+	Projects int64 `json:"projects"`
+	Users    int64 `json:"users"`
+	Apps     int64 `json:"apps"`
+	Clusters int64 `json:"clusters"`
+	// ClustersByProvider counts the clusters hosted by each cloud provider: AWS, GCP, AZURE, DO, or OTHER for clusters
+	// connected by kubeconfig
+	ClustersByProvider []AdminOverviewProviderCount `json:"clusters_by_provider"`
+}
+
+// AdminOverviewProviderCount is the number of clusters hosted by a cloud provider
+type AdminOverviewProviderCount struct {
+	Provider string `json:"provider"`
+	Clusters int64  `json:"clusters"`
+}
+
+// AdminOverviewDeployDay is the deploy volume of every app on a single day
+type AdminOverviewDeployDay struct {
+	// Day is midnight UTC of the day
+	Day            time.Time `json:"day"`
+	Deploys        int       `json:"deploys"`
+	DeployFailures int       `json:"deploy_failures"`
+	// FailureRate is DeployFailures divided by Deploys, or zero on a day without deploys
+	FailureRate float64         `json:"failure_rate"`
+	Source      DeployDaySource `json:"source"`
+}
+
+// AdminOverviewSlowDeploy is a recent deploy of an app which took a long time to finish, with the time spent in each
+// phase of the deploy
+type AdminOverviewSlowDeploy struct {
+	EventID     uuid.UUID `json:"event_id"`
+	ProjectID   uint      `json:"project_id"`
+	ClusterID   uint      `json:"cluster_id"`
+	PorterAppID uint      `json:"porter_app_id"`
+	AppName     string    `json:"app_name"`
+	Revision    int       `json:"revision"`
+	Status      string    `json:"status"`
+	StartedAt   time.Time `json:"started_at"`
+	// HelmSeconds is the time spent installing or upgrading the helm release of the app. It is zero for deploys made
+	// before it was recorded
+	HelmSeconds float64 `json:"helm_seconds"`
+	// RolloutSeconds is the time from the helm release being written until every service reported that it was
+	// deployed
+	RolloutSeconds float64 `json:"rollout_seconds"`
+	TotalSeconds   float64 `json:"total_seconds"`
+}
+
+// AdminOverviewComponent is the health of a background component of the server
+type AdminOverviewComponent struct {
+	Name     string `json:"name"`
+	Critical bool   `json:"critical"`
+	Status   string `json:"status"`
+	// Healthy is false once the component has exceeded its restart budget
+	Healthy     bool       `json:"healthy"`
+	Restarts    int        `json:"restarts"`
+	LastError   string     `json:"last_error"`
+	LastErrorAt *time.Time `json:"last_error_at"`
+}
+
+// AdminOverviewErrorCount is the number of times the API returned an error with a status and error code
+type AdminOverviewErrorCount struct {
+	StatusCode int `json:"status_code"`
+	// Code is the error code returned with the error, or zero if it was returned without one
+	Code  uint  `json:"code"`
+	Count int64 `json:"count"`
+}
+
+// AdminOverviewErrorsWindow is the period the errors of an admin overview were counted over. Errors are counted in
+// memory by each server since it started, so the window is shorter than a day after a restart
+type AdminOverviewErrorsWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
@@ -4,6 +4,17 @@ import "time"
 
 const URLParamTokenID URLParam = "api_token_id"
 
+// APITokenScope limits the endpoints an API token can call, on top of the policy of the token
+type APITokenScope string
+
+const (
+	// APITokenScopeRead permits the get and list endpoints of the project
+	APITokenScopeRead APITokenScope = "read"
+	// APITokenScopeDeploy permits the endpoints which deploy an app, such as creating, updating or rolling back a
+	// porter app
+	APITokenScopeDeploy APITokenScope = "deploy"
+)
+
 type APITokenMeta struct {
 	CreatedAt time.Time `json:"created_at"`
 	ExpiresAt time.Time `json:"expires_at"`
@@ -12,6 +23,9 @@ type APITokenMeta struct {
 	PolicyName string `json:"policy_name"`
 	PolicyUID  string `json:"policy_uid"`
 	Name       string `json:"name"`
+
+	// Scopes are the scopes the token is limited to, or empty if the token may call any endpoint its policy permits
+	Scopes []APITokenScope `json:"scopes,omitempty"`
 }
 
 type APIToken struct {
@@ -25,4 +39,7 @@ type CreateAPIToken struct {
 	PolicyUID string    `json:"policy_uid" form:"required"`
 	ExpiresAt time.Time `json:"expires_at"`
 	Name      string    `json:"name" form:"required"`
+
+	// Scopes limits the token to the given scopes. A token without scopes may call any endpoint its policy permits.
+	Scopes []APITokenScope `json:"scopes,omitempty" form:"omitempty,dive,oneof=read deploy"`
 }
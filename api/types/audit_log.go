@@ -0,0 +1,44 @@
+package types
+
+import "time"
+
+// AuditLogAction is a kind of entry in the audit log of a project
+type AuditLogAction string
+
+const (
+	// AuditLogAction_DebugRecordingStarted is an instance admin starting to record the API calls of the project
+	AuditLogAction_DebugRecordingStarted AuditLogAction = "debug_recording.started"
+	// AuditLogAction_DebugRecordingStopped is an instance admin stopping a recording before it expired
+	AuditLogAction_DebugRecordingStopped AuditLogAction = "debug_recording.stopped"
+	// AuditLogAction_AppGrantCreated is a project admin granting access to apps
+	AuditLogAction_AppGrantCreated AuditLogAction = "app_grant.created"
+	// AuditLogAction_AppGrantDeleted is a project admin revoking an app grant
+	AuditLogAction_AppGrantDeleted AuditLogAction = "app_grant.deleted"
+	// AuditLogAction_ScopedAppAccessUpdated is a project admin turning scoped app access on or off
+	AuditLogAction_ScopedAppAccessUpdated AuditLogAction = "app_grant.scoped_access_updated"
+	// AuditLogAction_AppAccessDenied is a developer without a matching app grant attempting to act on an app
+	AuditLogAction_AppAccessDenied AuditLogAction = "app_grant.access_denied"
+)
+
+// AuditLogEntry is an action taken on a project which its members can review
+type AuditLogEntry struct {
+	ID        uint           `json:"id"`
+	ProjectID uint           `json:"project_id"`
+	Action    AuditLogAction `json:"action"`
+	// ActorUserID is the user who took the action, which may be an instance admin who is not a member of the project
+	ActorUserID uint   `json:"actor_user_id"`
+	Description string `json:"description"`
+	// Metadata holds details specific to the action, such as when a debug recording expires
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// ListAuditLogRequest is the request to list the audit log of a project, newest first
+type ListAuditLogRequest struct {
+	Limit uint `schema:"limit" form:"omitempty,lte=500" doc:"The maximum number of entries to return, 100 if unset"`
+}
+
+// ListAuditLogResponse is the audit log of a project, newest first
+type ListAuditLogResponse struct {
+	Entries []AuditLogEntry `json:"entries"`
+}
@@ -0,0 +1,84 @@
+package types
+
+import "time"
+
+// URLParamBulkRedeployID is the url param for the id of a bulk redeploy
+const URLParamBulkRedeployID URLParam = "bulk_redeploy_id"
+
+// BulkRedeployStatus is the status of a single app in a bulk redeploy, or of the bulk redeploy as a whole
+type BulkRedeployStatus string
+
+const (
+	// BulkRedeployStatus_Pending is an app waiting for a free slot on its cluster
+	BulkRedeployStatus_Pending BulkRedeployStatus = "pending"
+	// BulkRedeployStatus_Running is an app being redeployed, or a bulk redeploy with apps left to redeploy
+	BulkRedeployStatus_Running BulkRedeployStatus = "running"
+	// BulkRedeployStatus_Succeeded is an app which was redeployed
+	BulkRedeployStatus_Succeeded BulkRedeployStatus = "succeeded"
+	// BulkRedeployStatus_Failed is an app which could not be redeployed
+	BulkRedeployStatus_Failed BulkRedeployStatus = "failed"
+	// BulkRedeployStatus_Skipped is an app which matched the filter but cannot be redeployed by a bulk redeploy
+	BulkRedeployStatus_Skipped BulkRedeployStatus = "skipped"
+	// BulkRedeployStatus_Completed is a bulk redeploy whose apps have all finished
+	BulkRedeployStatus_Completed BulkRedeployStatus = "completed"
+)
+
+const (
+	// DefaultBulkRedeployConcurrency is the number of apps redeployed at once on each cluster if no concurrency is set
+	DefaultBulkRedeployConcurrency = 3
+	// MaxBulkRedeployConcurrency is the largest number of apps which can be redeployed at once on each cluster
+	MaxBulkRedeployConcurrency = 20
+)
+
+// BulkRedeployFilter selects the apps of a project to redeploy. Every filter which is set must match.
+type BulkRedeployFilter struct {
+	// ClusterID only matches apps on this cluster
+	ClusterID uint `json:"cluster_id,omitempty" schema:"cluster_id" doc:"Only match apps on this cluster"`
+	// Label is a Kubernetes label selector, which matches apps with a service whose labels match it
+	Label string `json:"label,omitempty" schema:"label" doc:"A label selector which matches apps with a service whose labels match it"`
+	// EnvGroup matches apps with a service which is linked to or synced with this env group
+	EnvGroup string `json:"env_group,omitempty" schema:"env_group" doc:"Match apps with a service which is linked to or synced with this env group"`
+}
+
+// CreateBulkRedeployRequest is the request to redeploy every app of a project which matches a filter
+type CreateBulkRedeployRequest struct {
+	BulkRedeployFilter
+
+	// Concurrency is the number of apps redeployed at once on each cluster
+	Concurrency int `json:"concurrency" form:"min=0,max=20" doc:"The number of apps redeployed at once on each cluster, 3 if unset"`
+	// BumpChecksum restarts the pods of every redeployed app, even if its values did not change
+	BumpChecksum bool `json:"bump_checksum" doc:"Restart the pods of every redeployed app, even if its values did not change"`
+	// DryRun lists the apps which would be redeployed without redeploying them
+	DryRun bool `json:"dry_run" doc:"List the apps which would be redeployed without redeploying them"`
+}
+
+// BulkRedeployApp is the progress of a single app in a bulk redeploy
+type BulkRedeployApp struct {
+	PorterAppID uint               `json:"porter_app_id"`
+	AppName     string             `json:"app_name"`
+	ClusterID   uint               `json:"cluster_id"`
+	Status      BulkRedeployStatus `json:"status"`
+	// Error is why the app failed or was skipped
+	Error string `json:"error,omitempty"`
+	// Revision is the helm revision created by the redeploy
+	Revision   int        `json:"revision,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// BulkRedeploy is a group of app redeploys created by a single request
+type BulkRedeploy struct {
+	// ID is empty for a dry run
+	ID           string             `json:"id,omitempty"`
+	ProjectID    uint               `json:"project_id"`
+	Filter       BulkRedeployFilter `json:"filter"`
+	Concurrency  int                `json:"concurrency"`
+	BumpChecksum bool               `json:"bump_checksum"`
+	DryRun       bool               `json:"dry_run"`
+	Status       BulkRedeployStatus `json:"status"`
+	// Counts maps each app status to the number of apps with that status
+	Counts      map[BulkRedeployStatus]int `json:"counts"`
+	Apps        []BulkRedeployApp          `json:"apps"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	CompletedAt *time.Time                 `json:"completed_at,omitempty"`
+}
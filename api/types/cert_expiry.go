@@ -0,0 +1,71 @@
+package types
+
+import "time"
+
+// CertStatus is whether the certificate serving a domain of an app is valid
+type CertStatus string
+
+const (
+	// CertStatus_OK is a certificate which does not expire within the first alert threshold
+	CertStatus_OK CertStatus = "ok"
+	// CertStatus_Expiring is a certificate which expires within the first alert threshold
+	CertStatus_Expiring CertStatus = "expiring"
+	// CertStatus_Expired is a certificate whose expiry has passed
+	CertStatus_Expired CertStatus = "expired"
+	// CertStatus_Unknown is a domain whose certificate could not be read, such as a domain behind a private ingress or
+	// on a cluster which is unreachable
+	CertStatus_Unknown CertStatus = "unknown"
+)
+
+// CertSource is where the certificate serving a domain was read from
+type CertSource string
+
+const (
+	// CertSource_CertManager is a certificate read from the status of the cert-manager Certificate of the domain's TLS
+	// secret
+	CertSource_CertManager CertSource = "cert-manager"
+	// CertSource_TLSProbe is a certificate read by connecting to the ingress of the domain
+	CertSource_TLSProbe CertSource = "tls-probe"
+)
+
+// DomainCertStatus is the certificate serving a domain of an app, as of the last time it was checked
+type DomainCertStatus struct {
+	Hostname string     `json:"hostname"`
+	Status   CertStatus `json:"status"`
+	Source   CertSource `json:"source,omitempty"`
+	Issuer   string     `json:"issuer,omitempty"`
+	// ExpiresAt is when the certificate expires. It is kept from the last successful check when the status is unknown
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	// Error is why the certificate could not be read, if the status is unknown
+	Error string `json:"error,omitempty"`
+}
+
+// PorterAppDomainsResponse is the certificate status of every domain of an app, and where alerts about them are sent
+type PorterAppDomainsResponse struct {
+	AlertChannel LogAlertChannel    `json:"alert_channel"`
+	WebhookURL   string             `json:"webhook_url,omitempty"`
+	Domains      []DomainCertStatus `json:"domains"`
+}
+
+// UpdateCertAlertsRequest is the request to change where alerts about the certificates of an app's domains are sent
+type UpdateCertAlertsRequest struct {
+	Channel    LogAlertChannel `json:"channel" form:"required,oneof=slack webhook inbox" doc:"Where alerts are sent: the project's Slack integrations, a webhook, or only the app's activity feed"`
+	WebhookURL string          `json:"webhook_url" doc:"The https url alerts are posted to, for the webhook channel"`
+}
+
+// PorterAppCertExpiryEventMetadata is the metadata of a Porter App Event of type CERT_EXPIRY, recorded when the
+// certificate of a domain crosses an alert threshold or expires. It is also the payload sent to the app's alert channel
+type PorterAppCertExpiryEventMetadata struct {
+	// EventID is the ID of the alert's event in the activity feed, which identifies the alert across webhook deliveries
+	EventID  string     `json:"event_id,omitempty"`
+	AppName  string     `json:"app_name"`
+	Hostname string     `json:"hostname"`
+	Status   CertStatus `json:"status"`
+	Source   CertSource `json:"source"`
+	Issuer   string     `json:"issuer,omitempty"`
+	// ThresholdDays is the alert threshold which was crossed, or 0 if the certificate expired
+	ThresholdDays uint      `json:"threshold_days"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	At            time.Time `json:"at"`
+}
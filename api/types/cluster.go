@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 const (
 	URLParamCandidateID URLParam = "candidate_id"
 	URLParamNodeName    URLParam = "node_name"
@@ -51,6 +53,152 @@ type Cluster struct {
 	// This was likely the credential that was used to create the cluster.
 	// For AWS EKS clusters, this will be an ARN for the final target role in the assume role chain.
 	CloudProviderCredentialIdentifier string `json:"cloud_provider_credential_identifier"`
+
+	// SchedulingDefaults are the scheduling settings applied to every Porter-managed workload on the cluster
+	SchedulingDefaults *ClusterSchedulingDefaults `json:"scheduling_defaults,omitempty"`
+
+	// NetworkPoliciesEnabled is true if the apps of the cluster are deployed with network policies which only allow the
+	// traffic Porter knows about
+	NetworkPoliciesEnabled bool `json:"network_policies_enabled"`
+
+	// Capabilities are the features detected on the cluster, if detection has run
+	Capabilities *ClusterCapabilities `json:"capabilities,omitempty"`
+
+	// Tunnel is the connection state of the tunnel agent of a cluster which is reached through one
+	Tunnel *ClusterTunnelStatus `json:"tunnel,omitempty"`
+}
+
+// ClusterTunnelStatus is the connection state of the tunnel agent of a cluster
+type ClusterTunnelStatus struct {
+	// Connected is true if the agent is connected to the server. Requests to the cluster fail while it is not.
+	Connected   bool       `json:"connected"`
+	ConnectedAt *time.Time `json:"connected_at,omitempty"`
+
+	AgentVersion string `json:"agent_version,omitempty"`
+	// Capabilities are the classes of Kubernetes API requests the agent can proxy: deploy, stream and exec
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// CreateTunnelClusterRequest creates a cluster which is reached through a tunnel agent running inside it
+type CreateTunnelClusterRequest struct {
+	Name string `json:"name" form:"required"`
+}
+
+// CreateTunnelClusterResponse is the created cluster, with the token its tunnel agent authenticates with
+type CreateTunnelClusterResponse struct {
+	Cluster *Cluster `json:"cluster"`
+	// Token is only returned when it is generated, and cannot be read again
+	Token string `json:"token"`
+}
+
+// RotateTunnelTokenResponse is the new token of the tunnel agent of a cluster. The previous token stops working, and
+// the agent is disconnected until it reconnects with the new one.
+type RotateTunnelTokenResponse struct {
+	Token string `json:"token"`
+}
+
+// ClusterDistribution is the Kubernetes distribution a cluster runs
+type ClusterDistribution string
+
+const (
+	// ClusterDistribution_Unknown is used when no distribution could be identified
+	ClusterDistribution_Unknown ClusterDistribution = "unknown"
+	// ClusterDistribution_EKS is Amazon Elastic Kubernetes Service
+	ClusterDistribution_EKS ClusterDistribution = "eks"
+	// ClusterDistribution_GKE is Google Kubernetes Engine
+	ClusterDistribution_GKE ClusterDistribution = "gke"
+	// ClusterDistribution_AKS is Azure Kubernetes Service
+	ClusterDistribution_AKS ClusterDistribution = "aks"
+	// ClusterDistribution_DOKS is DigitalOcean Kubernetes
+	ClusterDistribution_DOKS ClusterDistribution = "doks"
+	// ClusterDistribution_K3s is k3s, including k3d
+	ClusterDistribution_K3s ClusterDistribution = "k3s"
+	// ClusterDistribution_OpenShift is Red Hat OpenShift
+	ClusterDistribution_OpenShift ClusterDistribution = "openshift"
+)
+
+// ClusterCapabilityProbe names a single check run during capability detection
+type ClusterCapabilityProbe string
+
+const (
+	// ClusterCapabilityProbe_Distribution identifies the distribution and Kubernetes version
+	ClusterCapabilityProbe_Distribution ClusterCapabilityProbe = "distribution"
+	// ClusterCapabilityProbe_IngressClasses lists the ingress classes
+	ClusterCapabilityProbe_IngressClasses ClusterCapabilityProbe = "ingress_classes"
+	// ClusterCapabilityProbe_StorageClasses finds the default storage class
+	ClusterCapabilityProbe_StorageClasses ClusterCapabilityProbe = "storage_classes"
+	// ClusterCapabilityProbe_APIGroups checks for metrics-server, cert-manager and pod security policies
+	ClusterCapabilityProbe_APIGroups ClusterCapabilityProbe = "api_groups"
+	// ClusterCapabilityProbe_PodSecurity reads the pod security admission level of the default namespace
+	ClusterCapabilityProbe_PodSecurity ClusterCapabilityProbe = "pod_security"
+	// ClusterCapabilityProbe_NetworkPolicy looks for a network plugin which enforces NetworkPolicy
+	ClusterCapabilityProbe_NetworkPolicy ClusterCapabilityProbe = "network_policy"
+)
+
+// ClusterCapabilities describes the features of a cluster which behave differently across Kubernetes distributions.
+// A probe which failed is listed in ProbeErrors, and the fields it sets should not be relied on.
+type ClusterCapabilities struct {
+	// Distribution is the detected Kubernetes distribution
+	Distribution ClusterDistribution `json:"distribution"`
+	// KubernetesVersion is the git version reported by the API server
+	KubernetesVersion string `json:"kubernetes_version,omitempty"`
+	// IngressClasses are the names of the ingress classes on the cluster
+	IngressClasses []string `json:"ingress_classes"`
+	// DefaultIngressClass is the ingress class marked as the default, or the only ingress class if there is one
+	DefaultIngressClass string `json:"default_ingress_class,omitempty"`
+	// DefaultStorageClass is the storage class used by volumes which do not set one, if any
+	DefaultStorageClass string `json:"default_storage_class,omitempty"`
+	// MetricsServer is true if the resource metrics API used by autoscaling is served
+	MetricsServer bool `json:"metrics_server"`
+	// CertManager is true if cert-manager is installed
+	CertManager bool `json:"cert_manager"`
+	// PodSecurityPolicies is true if the cluster still serves the PodSecurityPolicy API, removed in Kubernetes 1.25
+	PodSecurityPolicies bool `json:"pod_security_policies"`
+	// PodSecurityEnforce is the pod security admission level enforced on the default namespace, empty if none is set
+	PodSecurityEnforce string `json:"pod_security_enforce,omitempty"`
+	// NetworkPolicyEnforcer is the network plugin which enforces NetworkPolicy, such as calico or cilium, empty if none
+	// was found. Clusters whose plugin does not enforce NetworkPolicy accept policies but let all traffic through.
+	NetworkPolicyEnforcer string `json:"network_policy_enforcer,omitempty"`
+	// ProbeErrors maps the probes which failed to their error
+	ProbeErrors map[ClusterCapabilityProbe]string `json:"probe_errors,omitempty"`
+	// DetectedAt is when the capabilities were detected
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// ClusterSchedulingDefaults are scheduling settings applied to every Porter-managed workload on a cluster.
+// They have the lowest precedence: scheduling settings defined on a service override them.
+type ClusterSchedulingDefaults struct {
+	// NodeSelector is merged into the nodeSelector of every service, with keys set on the service taking precedence
+	NodeSelector map[string]string `json:"node_selector,omitempty"`
+	// Tolerations are applied to every service that does not define its own tolerations
+	Tolerations []Toleration `json:"tolerations,omitempty"`
+}
+
+// Toleration is a Kubernetes pod toleration
+type Toleration struct {
+	Key               string `json:"key,omitempty"`
+	Operator          string `json:"operator,omitempty"`
+	Value             string `json:"value,omitempty"`
+	Effect            string `json:"effect,omitempty"`
+	TolerationSeconds *int64 `json:"toleration_seconds,omitempty"`
+}
+
+// AppSchedulingDefaultsStatus describes whether the latest release of an app was deployed with the current scheduling defaults of its cluster
+type AppSchedulingDefaultsStatus struct {
+	// Name is the name of the app
+	Name string `json:"name"`
+	// UpToDate is true if the latest release of the app was deployed with the current cluster scheduling defaults
+	UpToDate bool `json:"up_to_date"`
+	// Error is set if the latest release of the app could not be checked
+	Error string `json:"error,omitempty"`
+}
+
+// ListSchedulingDefaultsDriftResponse is the response from a `GET /clusters/{cluster_id}/applications/scheduling-defaults/drift` request
+type ListSchedulingDefaultsDriftResponse struct {
+	// SchedulingDefaultsHash identifies the current scheduling defaults of the cluster
+	SchedulingDefaultsHash string `json:"scheduling_defaults_hash"`
+	// Apps contains the status of every app on the cluster
+	Apps []AppSchedulingDefaultsStatus `json:"apps"`
 }
 
 type ClusterCandidate struct {
@@ -296,6 +444,14 @@ type UpdateClusterRequest struct {
 	AgentIntegrationEnabled *bool `json:"agent_integration_enabled"`
 
 	PreviewEnvsEnabled *bool `json:"preview_envs_enabled"`
+
+	// SchedulingDefaults replaces the scheduling defaults of the cluster, if set. Changing the defaults does
+	// not redeploy any apps; they are picked up on each app's next deploy.
+	SchedulingDefaults *ClusterSchedulingDefaults `json:"scheduling_defaults"`
+
+	// NetworkPoliciesEnabled turns the network policies of the apps of the cluster on or off, if set. Like the
+	// scheduling defaults, apps pick up the change on their next deploy.
+	NetworkPoliciesEnabled *bool `json:"network_policies_enabled"`
 }
 
 type RenameClusterRequest struct {
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DebugRecording is a window in which the API calls of a project are recorded, redacted, to the object store, so that
+// instance admins can inspect what the server received and returned when investigating a support ticket
+type DebugRecording struct {
+	ID        uint `json:"id"`
+	ProjectID uint `json:"project_id"`
+	// StartedByUserID is the instance admin who started the recording
+	StartedByUserID uint   `json:"started_by_user_id"`
+	Reason          string `json:"reason"`
+	Active          bool   `json:"active"`
+	// ExchangeCount is the number of exchanges which have been recorded
+	ExchangeCount uint       `json:"exchange_count"`
+	StartedAt     time.Time  `json:"started_at"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	StoppedAt     *time.Time `json:"stopped_at,omitempty"`
+}
+
+// StartDebugRecordingRequest is the request to start recording the API calls of a project
+type StartDebugRecordingRequest struct {
+	DurationMinutes uint   `json:"duration_minutes" form:"required,gte=1,lte=1440" doc:"The number of minutes after which the recording stops on its own"`
+	Reason          string `json:"reason" form:"required,max=255" doc:"Why the calls are recorded, such as a support ticket, which is shown in the project's audit log"`
+}
+
+// DebugRecordingExchange is a single recorded API call
+type DebugRecordingExchange struct {
+	Key      string          `json:"key"`
+	Exchange json.RawMessage `json:"exchange"`
+}
+
+// ListDebugRecordingExchangesResponse is the recorded API calls of a debug recording
+type ListDebugRecordingExchangesResponse struct {
+	Recording DebugRecording           `json:"recording"`
+	Exchanges []DebugRecordingExchange `json:"exchanges"`
+	// Truncated is true if the recording has more exchanges than were returned
+	Truncated bool `json:"truncated"`
+}
+
+// URLParamDebugRecordingID is the URL param for the id of a debug recording
+const URLParamDebugRecordingID URLParam = "debug_recording_id"
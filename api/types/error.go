@@ -2,6 +2,13 @@ package types
 
 const (
 	ErrCodeUnavailable uint = 601
+	// ErrCodeRequestTimeout is returned with a 504 when a request runs past its time budget
+	ErrCodeRequestTimeout uint = 602
+	// ErrCodeTunnelAgentOffline is returned with a 503 when the tunnel agent of the cluster of a request is not connected
+	ErrCodeTunnelAgentOffline uint = 603
+	// ErrCodeRolloutTimeout is returned when a deploy which waits for its resources times out, along with the status of
+	// the pods of the app
+	ErrCodeRolloutTimeout uint = 604
 )
 
 type ExternalError struct {
@@ -9,4 +16,19 @@ type ExternalError struct {
 	Code uint `json:"code,omitempty"`
 
 	Error string `json:"error"`
+
+	// Pods are the pods of the app when its deploy timed out, returned with ErrCodeRolloutTimeout
+	Pods []RolloutPodStatus `json:"pods,omitempty"`
+}
+
+// RolloutPodStatus is the state of a pod of an app when its deploy timed out waiting for it to be ready
+type RolloutPodStatus struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Ready    bool   `json:"ready"`
+	Restarts int32  `json:"restarts"`
+	// Reason is why the pod is not ready, such as the waiting reason of one of its containers or why it could not be
+	// scheduled
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
 }
@@ -1,7 +1,61 @@
 package types
 
+import "time"
+
 type StreamHelmReleaseRequest struct {
 	Selectors string   `schema:"selectors"`
 	Charts    []string `schema:"charts"`
 	Namespace string   `schema:"namespace"`
 }
+
+// ClusterReleaseKind is what a helm release managed by Porter deploys
+type ClusterReleaseKind string
+
+const (
+	// ClusterReleaseKind_App is the release of the services of a porter app
+	ClusterReleaseKind_App ClusterReleaseKind = "app"
+	// ClusterReleaseKind_PreDeploy is the release of the pre-deploy job of a porter app
+	ClusterReleaseKind_PreDeploy ClusterReleaseKind = "pre_deploy"
+	// ClusterReleaseKind_Addon is any other release deployed into the namespace of a porter app
+	ClusterReleaseKind_Addon ClusterReleaseKind = "addon"
+)
+
+// ListClusterReleasesRequest filters the helm releases managed by Porter in a cluster
+type ListClusterReleasesRequest struct {
+	// Status limits the releases to those whose latest revision has one of the statuses. "pending" matches every
+	// pending status. If empty, releases of every status are listed
+	Status []string `schema:"status"`
+	// Chart limits the releases to those of the chart with this name
+	Chart string `schema:"chart"`
+	// Namespace limits the releases to a single namespace. If empty, releases in every namespace are listed
+	Namespace string `schema:"namespace"`
+}
+
+// ClusterRelease is the latest revision of a helm release managed by Porter
+type ClusterRelease struct {
+	Name         string             `json:"name"`
+	Namespace    string             `json:"namespace"`
+	Chart        string             `json:"chart"`
+	ChartVersion string             `json:"chart_version"`
+	Revision     int                `json:"revision"`
+	Status       string             `json:"status"`
+	LastDeployed *time.Time         `json:"last_deployed,omitempty"`
+	Kind         ClusterReleaseKind `json:"kind"`
+	// App is the name of the porter app which owns the release. It is empty for orphaned releases
+	App string `json:"app,omitempty"`
+	// Orphaned is true if the porter app which deployed the release no longer exists
+	Orphaned bool `json:"orphaned"`
+}
+
+// ReleaseRef identifies a helm release in a cluster
+type ReleaseRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// ListClusterReleasesResponse is the response object for the GET /clusters/{cluster_id}/releases endpoint
+type ListClusterReleasesResponse struct {
+	Releases []ClusterRelease `json:"releases"`
+	// MarkedForCleanup are the orphaned releases in the list, which can be uninstalled since no app owns them
+	MarkedForCleanup []ReleaseRef `json:"marked_for_cleanup"`
+}
@@ -0,0 +1,67 @@
+package types
+
+import "time"
+
+// HelmReleaseImportMode is how Porter manages a helm release which was imported as a porter app
+type HelmReleaseImportMode string
+
+const (
+	// HelmReleaseImportMode_Application is a release of one of Porter's application charts. Its services and
+	// environment are read from the release values
+	HelmReleaseImportMode_Application HelmReleaseImportMode = "application"
+	// HelmReleaseImportMode_ExternalChart is a release of any other chart. Porter keeps the chart as it is and only
+	// upgrades the release with new values
+	HelmReleaseImportMode_ExternalChart HelmReleaseImportMode = "external_chart"
+)
+
+// ImportableRelease is a helm release deployed outside of Porter, and whether it can be imported as a porter app
+type ImportableRelease struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Chart        string `json:"chart"`
+	ChartVersion string `json:"chart_version"`
+	AppVersion   string `json:"app_version,omitempty"`
+	Revision     int    `json:"revision"`
+	Status       string `json:"status"`
+
+	// Mode is only set if the release can be imported
+	Mode HelmReleaseImportMode `json:"mode,omitempty"`
+	// Services are the services inferred from the values of an application chart release
+	Services []ImportedService `json:"services,omitempty"`
+
+	Importable bool `json:"importable"`
+	// Reasons explain why the release cannot be imported
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// ImportedService is a service inferred from the values of an imported application chart release
+type ImportedService struct {
+	Name string `json:"name"`
+	// Type is one of web, worker or job
+	Type            string `json:"type"`
+	ImageRepository string `json:"image_repository,omitempty"`
+	ImageTag        string `json:"image_tag,omitempty"`
+	// Env contains the plain environment variables of the service. Secret values are never returned
+	Env map[string]string `json:"env,omitempty"`
+	// SecretEnvKeys are the names of the service's secret environment variables
+	SecretEnvKeys []string `json:"secret_env_keys,omitempty"`
+}
+
+// HelmReleaseImport records that a helm release was imported as a porter app
+type HelmReleaseImport struct {
+	ID           uint                  `json:"id"`
+	ProjectID    uint                  `json:"project_id"`
+	ClusterID    uint                  `json:"cluster_id"`
+	PorterAppID  uint                  `json:"porter_app_id"`
+	ReleaseName  string                `json:"release_name"`
+	Namespace    string                `json:"namespace"`
+	ChartName    string                `json:"chart_name"`
+	ChartVersion string                `json:"chart_version"`
+	Mode         HelmReleaseImportMode `json:"mode"`
+	// ImportedRevision is the helm revision of the release when it was imported
+	ImportedRevision int `json:"imported_revision"`
+	// AdoptedRevision is the first helm revision deployed by Porter, or 0 if Porter has not upgraded the release yet
+	AdoptedRevision int        `json:"adopted_revision"`
+	AdoptedAt       *time.Time `json:"adopted_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
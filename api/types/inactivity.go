@@ -0,0 +1,98 @@
+package types
+
+import "time"
+
+// InactivityPolicy pauses and then deletes the apps of a project which have gone unused. It applies to apps flagged as
+// ephemeral, and to apps whose name matches its pattern.
+type InactivityPolicy struct {
+	ProjectID uint `json:"project_id"`
+	Enabled   bool `json:"enabled"`
+	// NamePattern is a glob such as "preview-*" which matches the names of the apps the policy applies to, in addition to
+	// the apps flagged as ephemeral
+	NamePattern string `json:"name_pattern,omitempty"`
+	// IdleDays is the number of days without a deploy after which an app is paused
+	IdleDays uint `json:"idle_days"`
+	// GraceDays is the number of days an app stays paused before it is deleted
+	GraceDays uint `json:"grace_days"`
+	// MaxDailyRequests is the number of requests within the last day above which an app is not idle, even without a
+	// deploy. It is only checked on clusters with metrics.
+	MaxDailyRequests uint      `json:"max_daily_requests"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// UpdateInactivityPolicyRequest is the request to create or replace the inactivity policy of a project
+type UpdateInactivityPolicyRequest struct {
+	Enabled          bool   `json:"enabled" doc:"Pause and delete apps which match the policy"`
+	NamePattern      string `json:"name_pattern" form:"max=255" doc:"A glob such as preview-* which matches the names of apps the policy applies to, in addition to apps flagged as ephemeral"`
+	IdleDays         uint   `json:"idle_days" form:"required,gte=1,lte=365" doc:"The number of days without a deploy after which an app is paused"`
+	GraceDays        uint   `json:"grace_days" form:"required,gte=1,lte=365" doc:"The number of days an app stays paused before it is deleted"`
+	MaxDailyRequests uint   `json:"max_daily_requests" doc:"The number of requests within the last day above which an app is not idle, on clusters with metrics"`
+}
+
+// UpdateAppInactivityCleanupRequest is the request to change how the inactivity policy of a project applies to an app
+type UpdateAppInactivityCleanupRequest struct {
+	// Ephemeral flags an app as a preview or experiment, which the inactivity policy applies to regardless of its name
+	Ephemeral *bool `json:"ephemeral" doc:"Flag the app as a preview or experiment, which the inactivity policy applies to regardless of its name"`
+	// CleanupDisabled opts an app out of the inactivity policy
+	CleanupDisabled *bool `json:"cleanup_disabled" doc:"Opt the app out of the inactivity policy"`
+}
+
+// InactivityAction is a step the inactivity policy takes on an idle app
+type InactivityAction string
+
+const (
+	// InactivityAction_Paused is an app whose workloads were scaled down
+	InactivityAction_Paused InactivityAction = "PAUSED"
+	// InactivityAction_Deleted is an app which was deleted after being paused for the grace period
+	InactivityAction_Deleted InactivityAction = "DELETED"
+)
+
+// PorterAppInactivityEventMetadata is the metadata of an event recorded when the inactivity policy pauses or deletes an app
+type PorterAppInactivityEventMetadata struct {
+	Action         InactivityAction `json:"action"`
+	AppName        string           `json:"app_name"`
+	LastDeployedAt time.Time        `json:"last_deployed_at"`
+	IdleDays       uint             `json:"idle_days"`
+	// DailyRequests is the number of requests within the last day, if the cluster has metrics
+	DailyRequests *float64 `json:"daily_requests,omitempty"`
+	// DeleteAt is when a paused app will be deleted
+	DeleteAt *time.Time `json:"delete_at,omitempty"`
+	At       time.Time  `json:"at"`
+}
+
+// InactivityCandidateStatus is where an app is in the inactivity policy
+type InactivityCandidateStatus string
+
+const (
+	// InactivityCandidateStatus_Scheduled is an app which will be paused if it is not deployed before its pause date
+	InactivityCandidateStatus_Scheduled InactivityCandidateStatus = "scheduled"
+	// InactivityCandidateStatus_Paused is an app which will be deleted if it is not deployed before its delete date
+	InactivityCandidateStatus_Paused InactivityCandidateStatus = "paused"
+	// InactivityCandidateStatus_OptedOut is an app which matches the policy but has opted out of it
+	InactivityCandidateStatus_OptedOut InactivityCandidateStatus = "opted_out"
+)
+
+// InactivityCandidate is an app which the inactivity policy of its project applies to
+type InactivityCandidate struct {
+	PorterAppID    uint                      `json:"porter_app_id"`
+	AppName        string                    `json:"app_name"`
+	ClusterID      uint                      `json:"cluster_id"`
+	Ephemeral      bool                      `json:"ephemeral"`
+	Status         InactivityCandidateStatus `json:"status"`
+	LastDeployedAt time.Time                 `json:"last_deployed_at"`
+	// PauseAt is when the app will be paused if it is not deployed, or when it was due to be paused if it is paused
+	PauseAt  time.Time  `json:"pause_at"`
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+	// DeleteAt is when the app will be deleted if it is not deployed. Apps which are not yet paused are deleted the grace
+	// period after they are paused, so this moves later if the pause is delayed.
+	DeleteAt time.Time `json:"delete_at"`
+}
+
+// InactivityReport lists the apps which the inactivity policy of a project applies to, with the dates they will be
+// paused and deleted
+type InactivityReport struct {
+	Policy      *InactivityPolicy     `json:"policy"`
+	GeneratedAt time.Time             `json:"generated_at"`
+	Candidates  []InactivityCandidate `json:"candidates"`
+}
@@ -0,0 +1,71 @@
+package types
+
+import "time"
+
+// LogAlertMatchType is how a log alert rule's pattern is matched against log lines
+type LogAlertMatchType string
+
+const (
+	// LogAlertMatchType_Substring matches log lines which contain the pattern
+	LogAlertMatchType_Substring LogAlertMatchType = "substring"
+	// LogAlertMatchType_Regex matches log lines which match the pattern as a regular expression
+	LogAlertMatchType_Regex LogAlertMatchType = "regex"
+)
+
+// LogAlertChannel is where a log alert is sent when its rule fires
+type LogAlertChannel string
+
+const (
+	// LogAlertChannel_Slack sends the alert to the project's Slack integrations
+	LogAlertChannel_Slack LogAlertChannel = "slack"
+	// LogAlertChannel_Webhook posts the alert as JSON to the rule's webhook URL
+	LogAlertChannel_Webhook LogAlertChannel = "webhook"
+	// LogAlertChannel_Inbox only records the alert in the app's activity feed
+	LogAlertChannel_Inbox LogAlertChannel = "inbox"
+)
+
+// LogAlertRule is an alert which fires when lines matching its pattern appear in an app's logs more often than its
+// threshold within its window
+type LogAlertRule struct {
+	ID                 uint              `json:"id"`
+	ProjectID          uint              `json:"project_id"`
+	ClusterID          uint              `json:"cluster_id"`
+	PorterAppID        uint              `json:"porter_app_id"`
+	DeploymentTargetID string            `json:"deployment_target_id"`
+	Name               string            `json:"name"`
+	ServiceName        string            `json:"service_name,omitempty"`
+	MatchType          LogAlertMatchType `json:"match_type"`
+	Pattern            string            `json:"pattern"`
+	// Threshold is the number of matching lines within the window which must be exceeded for the rule to fire
+	Threshold     uint            `json:"threshold"`
+	WindowMinutes uint            `json:"window_minutes"`
+	Channel       LogAlertChannel `json:"channel"`
+	// SlackIntegrationID limits a slack alert to a single slack integration. If unset, every slack integration in the project is notified
+	SlackIntegrationID uint       `json:"slack_integration_id,omitempty"`
+	WebhookURL         string     `json:"webhook_url,omitempty"`
+	Enabled            bool       `json:"enabled"`
+	LastFiredAt        *time.Time `json:"last_fired_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// PorterAppAlertEventMetadata is the metadata of a Porter App Event of type ALERT, which is also the payload sent to
+// the rule's notification channel
+type PorterAppAlertEventMetadata struct {
+	// EventID is the ID of the alert's event in the activity feed, which identifies the alert across webhook deliveries
+	EventID       string            `json:"event_id,omitempty"`
+	RuleID        uint              `json:"rule_id"`
+	RuleName      string            `json:"rule_name"`
+	AppName       string            `json:"app_name"`
+	ServiceName   string            `json:"service_name,omitempty"`
+	MatchType     LogAlertMatchType `json:"match_type"`
+	Pattern       string            `json:"pattern"`
+	Threshold     uint              `json:"threshold"`
+	WindowMinutes uint              `json:"window_minutes"`
+	// MatchCount is the number of lines which matched within the window
+	MatchCount int `json:"match_count"`
+	// Snippet contains the most recent matching lines
+	Snippet []string        `json:"snippet"`
+	Channel LogAlertChannel `json:"channel"`
+	FiredAt time.Time       `json:"fired_at"`
+}
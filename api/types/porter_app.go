@@ -5,18 +5,24 @@ import (
 )
 
 type PorterApp struct {
-	ID        uint `json:"id"`
-	ProjectID uint `json:"project_id"`
-	ClusterID uint `json:"cluster_id"`
+	ID uint `json:"id"`
+	// UUID identifies the app across renames, and can be used in place of its name in URLs
+	UUID      string `json:"uuid"`
+	ProjectID uint   `json:"project_id"`
+	ClusterID uint   `json:"cluster_id"`
 
 	Name string `json:"name"`
 
+	// SourceType is whether the app is built by Porter or deploys an external image
+	SourceType PorterAppSourceType `json:"source_type,omitempty"`
+
 	ImageRepoURI string `json:"image_repo_uri,omitempty"`
 
-	// Git repo information (optional)
-	GitRepoID uint   `json:"git_repo_id,omitempty"`
-	RepoName  string `json:"repo_name,omitempty"`
-	GitBranch string `json:"git_branch,omitempty"`
+	// Git repo information (optional). GitlabIntegrationID is set instead of GitRepoID for repos hosted on gitlab
+	GitRepoID           uint   `json:"git_repo_id,omitempty"`
+	GitlabIntegrationID uint   `json:"gitlab_integration_id,omitempty"`
+	RepoName            string `json:"repo_name,omitempty"`
+	GitBranch           string `json:"git_branch,omitempty"`
 
 	// Build settings (optional)
 	BuildContext   string `json:"build_context,omitempty"`
@@ -25,14 +31,243 @@ type PorterApp struct {
 	Dockerfile     string `json:"dockerfile,omitempty"`
 	PullRequestURL string `json:"pull_request_url,omitempty"`
 
+	// DeploySummaryCommentsDisabled is true if deploy summaries are not commented on the app's pull request
+	DeploySummaryCommentsDisabled bool `json:"deploy_summary_comments_disabled,omitempty"`
+	// Ephemeral is true if the app is a preview or experiment, which the inactivity policy of the project applies to
+	Ephemeral bool `json:"ephemeral,omitempty"`
+	// InactivityCleanupDisabled is true if the app has opted out of the inactivity policy of the project
+	InactivityCleanupDisabled bool `json:"inactivity_cleanup_disabled,omitempty"`
+
+	// Tags group the app with other apps, such as by team or environment
+	Tags []string `json:"tags,omitempty"`
+
+	// DeployWebhookEnabled is true if the app has a deploy webhook token
+	DeployWebhookEnabled bool `json:"deploy_webhook_enabled,omitempty"`
+
+	// Status is whether the app is running or paused
+	Status PorterAppStatus `json:"status,omitempty"`
+	// PausedAt is when the app was paused, if it is paused
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+
 	// Porter YAML
 	PorterYAMLBase64 string `json:"porter_yaml,omitempty"`
 	PorterYamlPath   string `json:"porter_yaml_path,omitempty"`
 
 	// Helm
 	HelmRevisionNumber int `json:"helm_revision_number,omitempty"`
+	// Namespace is the namespace the app was deployed to. It is only set in the response to a deploy.
+	Namespace string `json:"namespace,omitempty"`
+	// URLs are the URLs the web services of the app are served on. They are only set in the response to a deploy.
+	URLs []string `json:"urls,omitempty"`
+
+	// Warnings are about values set in porter.yaml which stopped Porter from injecting its own when the app was deployed,
+	// and registries which no pull secret was generated for because their credentials are broken
+	Warnings []string `json:"warnings,omitempty"`
+
+	// ScalingSchedule is where the services of the app are in their scaling schedules, if any of them has one
+	ScalingSchedule *ScalingScheduleStatus `json:"scaling_schedule,omitempty"`
+
+	// Domains are the certificates serving the domains of the app, as of the last time they were checked
+	Domains []DomainCertStatus `json:"domains,omitempty"`
+
+	// CronServices are the last runs of the services of the app which run on a schedule
+	CronServices []CronServiceStatus `json:"cron_services,omitempty"`
+
+	// ValuesDiff are the changes an update made to the values of the app's helm release, if they were requested
+	ValuesDiff []HelmValueChange `json:"values_diff,omitempty"`
+
+	// Grants are the app grants of the project which match the app
+	Grants []PorterAppGrant `json:"grants,omitempty"`
+
+	// PreDeployEventID is the PRE_DEPLOY event of a deploy which installed or upgraded the pre-deploy job. It is
+	// PROGRESSING until the job finishes, when it is updated with the exit code of the job.
+	PreDeployEventID string `json:"pre_deploy_event_id,omitempty"`
+}
+
+// CronRunResult is how the last run of a cron service ended
+type CronRunResult string
+
+const (
+	// CronRunResult_Running is a run which has not finished yet
+	CronRunResult_Running CronRunResult = "running"
+	// CronRunResult_Succeeded is a run whose job completed
+	CronRunResult_Succeeded CronRunResult = "succeeded"
+	// CronRunResult_Failed is a run whose job finished without completing
+	CronRunResult_Failed CronRunResult = "failed"
+)
+
+// CronServiceStatus is the schedule and the last run of a service which runs on a schedule
+type CronServiceStatus struct {
+	Service  string `json:"service"`
+	Schedule string `json:"schedule"`
+	// Timezone is the time zone the schedule runs in, empty for the time zone of the cluster
+	Timezone string `json:"timezone,omitempty"`
+	// Suspended is true if the cron job does not start runs, such as while the app is paused
+	Suspended bool `json:"suspended,omitempty"`
+	// LastRunAt is when the last run was scheduled, unset if the service has not run yet
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	// LastRunResult is how the last run ended, unset if the service has not run yet or its cron job was not found
+	LastRunResult CronRunResult `json:"last_run_result,omitempty"`
+}
+
+// PorterAppStatus is whether an app is running or paused
+type PorterAppStatus string
+
+const (
+	// PorterAppStatus_Running is an app which runs the replicas it was deployed with
+	PorterAppStatus_Running PorterAppStatus = "running"
+	// PorterAppStatus_Paused is an app whose deployments are scaled to zero and whose cron jobs are suspended until
+	// it is resumed
+	PorterAppStatus_Paused PorterAppStatus = "paused"
+)
+
+// UpdatePorterAppDeploySummaryCommentsRequest turns deploy summary comments on the app's pull request on or off
+type UpdatePorterAppDeploySummaryCommentsRequest struct {
+	Enabled *bool `json:"enabled" form:"required"`
+}
+
+// RenamePorterAppRequest renames an app
+type RenamePorterAppRequest struct {
+	Name string `json:"name" form:"required"`
+	// MigrateRelease reinstalls the helm release of the app under the new name, since helm cannot rename a release in
+	// place. The pods of the app are replaced.
+	MigrateRelease bool `json:"migrate_release"`
+}
+
+// ClonePorterAppRequest copies an app into a new app
+type ClonePorterAppRequest struct {
+	Name string `json:"name" form:"required"`
+	// ClusterID is the cluster of the project the copy is deployed to. Defaults to the cluster of the app.
+	ClusterID uint `json:"cluster_id"`
+}
+
+// DeletePorterAppPlanRequest selects what deleting an app deployed with porter apply v1 removes
+type DeletePorterAppPlanRequest struct {
+	// DeleteNamespace deletes the porter-stack-<name> namespace of the app, along with anything left in it once its
+	// helm releases are uninstalled
+	DeleteNamespace bool `schema:"delete_namespace" json:"delete_namespace"`
+	// DeleteVolumes deletes the persistent volume claims of the app, which are otherwise left behind unless the
+	// namespace is deleted
+	DeleteVolumes bool `schema:"delete_volumes" json:"delete_volumes"`
+}
+
+// DeletePorterAppRequest deletes an app deployed with porter apply v1, as described by the plan of the same options
+type DeletePorterAppRequest struct {
+	DeletePorterAppPlanRequest
+	// PlanHash is the hash of the plan returned for the same options. The deletion is refused if the plan has changed
+	// since, so that only what the user was shown is deleted.
+	PlanHash string `schema:"plan_hash" json:"plan_hash" form:"required"`
+}
+
+const (
+	// OrphanedResourceKind_PersistentVolumeClaim is a persistent volume claim of the app, which keeps its volume
+	OrphanedResourceKind_PersistentVolumeClaim = "PersistentVolumeClaim"
+	// OrphanedResourceKind_LoadBalancer is a LoadBalancer service of the app, whose cloud load balancer is released by
+	// the finalizer of the service after the release of the app is uninstalled
+	OrphanedResourceKind_LoadBalancer = "LoadBalancer"
+	// OrphanedResourceKind_Namespace is the namespace of the app, which is kept along with the env groups and secrets
+	// in it which were not created by helm
+	OrphanedResourceKind_Namespace = "Namespace"
+	// OrphanedResourceKind_DNSRecord is a porter managed domain of the app, which resolves until the removal of its
+	// record has propagated
+	OrphanedResourceKind_DNSRecord = "DNSRecord"
+)
+
+// OrphanedResource is a resource which deleting an app leaves behind, either intentionally or until it is released
+// asynchronously
+type OrphanedResource struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Namespace is set for resources outside of the namespace of the app, such as those of services which run in
+	// another namespace
+	Namespace string `json:"namespace,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// DeletePorterAppPlan lists what deleting an app removes and what it leaves behind
+type DeletePorterAppPlan struct {
+	// Releases are the helm releases of the app which are uninstalled
+	Releases []string `json:"releases"`
+	// Namespace is the namespace which is deleted, if the namespace is deleted
+	Namespace string `json:"namespace,omitempty"`
+	// ServiceNamespaces are the other namespaces which run services of the app. The release of the app in each of them
+	// is uninstalled, but the namespaces are always kept.
+	ServiceNamespaces []string `json:"service_namespaces,omitempty"`
+	// PersistentVolumeClaims are the claims which are deleted, if volumes are deleted
+	PersistentVolumeClaims []string `json:"persistent_volume_claims"`
+	// DNSRecords are the hostnames of the porter managed domains of the app whose records are removed
+	DNSRecords []string `json:"dns_records"`
+	Events     int64    `json:"events"`
+	// Orphaned are the resources which are left behind, intentionally or until they are released asynchronously
+	Orphaned []OrphanedResource `json:"orphaned"`
+	// PlanHash identifies the plan, and is passed to the delete endpoint to confirm it
+	PlanHash string `json:"plan_hash"`
 }
 
+// DeletePorterAppResponse lists the resources of an app which were removed
+type DeletePorterAppResponse struct {
+	// UninstalledReleases are the helm releases of the app which were uninstalled. Releases which were already gone
+	// are not listed. Releases in the other namespaces its services run in are listed as namespace/name.
+	UninstalledReleases []string `json:"uninstalled_releases"`
+	DeletedNamespace    bool     `json:"deleted_namespace"`
+	// DeletedVolumes are the persistent volume claims of the app which were deleted
+	DeletedVolumes []string `json:"deleted_volumes"`
+	DeletedEvents  int64    `json:"deleted_events"`
+	// DeletedDNSRecords is the number of porter managed domains of the app which were removed
+	DeletedDNSRecords int64 `json:"deleted_dns_records"`
+	DeletedApp        bool  `json:"deleted_app"`
+	// CleanupID is the id of the cleanup which tracks the resources released asynchronously after the deletion, if
+	// there are any
+	CleanupID uint `json:"cleanup_id,omitempty"`
+}
+
+// PorterAppCleanupStatus is the status of the asynchronous cleanup after an app is deleted
+type PorterAppCleanupStatus string
+
+const (
+	// PorterAppCleanupStatus_Pending is a cleanup with resources which have not been released yet
+	PorterAppCleanupStatus_Pending PorterAppCleanupStatus = "PENDING"
+	// PorterAppCleanupStatus_Succeeded is a cleanup whose resources were all released
+	PorterAppCleanupStatus_Succeeded PorterAppCleanupStatus = "SUCCEEDED"
+	// PorterAppCleanupStatus_Leaked is a cleanup with resources which were not released before its deadline. It is
+	// reported as a warning on the project.
+	PorterAppCleanupStatus_Leaked PorterAppCleanupStatus = "LEAKED"
+)
+
+// PorterAppCleanupResource is a resource of a deleted app which is released asynchronously
+type PorterAppCleanupResource struct {
+	// Kind is OrphanedResourceKind_LoadBalancer or OrphanedResourceKind_DNSRecord
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Namespace is set for resources outside of the namespace of the cleanup
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// PorterAppCleanup tracks the resources of a deleted app which are released asynchronously
+type PorterAppCleanup struct {
+	ID        uint                   `json:"id"`
+	ClusterID uint                   `json:"cluster_id"`
+	AppName   string                 `json:"app_name"`
+	Namespace string                 `json:"namespace"`
+	Status    PorterAppCleanupStatus `json:"status"`
+	// Pending are the resources which have not been released yet
+	Pending    []PorterAppCleanupResource `json:"pending"`
+	Deadline   time.Time                  `json:"deadline"`
+	CreatedAt  time.Time                  `json:"created_at"`
+	FinishedAt *time.Time                 `json:"finished_at,omitempty"`
+}
+
+// PorterAppSourceType is where the image of an app comes from
+type PorterAppSourceType string
+
+const (
+	// PorterAppSourceType_Build is an app whose image is built by Porter, from a repository or from the CLI
+	PorterAppSourceType_Build PorterAppSourceType = "build"
+	// PorterAppSourceType_Image is an app which deploys an external image, such as a public image, and has no build
+	// settings
+	PorterAppSourceType_Image PorterAppSourceType = "image"
+)
+
 // swagger:model
 type CreatePorterAppRequest struct {
 	ClusterID        uint      `json:"cluster_id"`
@@ -49,12 +284,240 @@ type CreatePorterAppRequest struct {
 	PorterYAMLBase64 string    `json:"porter_yaml"`
 	PorterYamlPath   string    `json:"porter_yaml_path"`
 	ImageInfo        ImageInfo `json:"image_info" form:"omitempty"`
-	OverrideRelease  bool      `json:"override_release"`
-	EnvGroups        []string  `json:"env_groups"`
+	// ServiceImageInfo sets the image of individual services, by their name in porter.yaml, for stacks which build an
+	// image per service. Services which are not in it, and the pre-deploy job, run ImageInfo.
+	ServiceImageInfo map[string]ImageInfo `json:"service_image_info,omitempty"`
+	// OverrideRelease treats the porter.yaml as the whole app on updates: services, env variables and the pre-deploy
+	// job which it does not define are removed. Otherwise, env variables of the current release which the porter.yaml
+	// does not set are kept.
+	OverrideRelease bool     `json:"override_release"`
+	EnvGroups       []string `json:"env_groups"`
 	// EnvironmentGroups are the list of environment groups that this app is linked to. This should be used instead of EnvGroups.
 	EnvironmentGroups []string `json:"environment_groups"`
 	UserUpdate        bool     `json:"user_update"`
 	FullHelmValues    string   `json:"full_helm_values"`
+	// DryRun builds and renders the charts of the app without installing them, creating its namespace or writing it to
+	// the database. The response is a CreatePorterAppDryRunResponse instead of the app. Requests to the dry run route are
+	// dry runs whether or not they set it.
+	DryRun bool `json:"dry_run"`
+	// ShowDiff returns the changes the update makes to the values of the app's current helm release. It has no effect
+	// when the app is created.
+	ShowDiff bool `json:"show_diff"`
+	// SkipGitValidation skips checking the repository, branch and build settings of the app against its github app
+	// installation
+	SkipGitValidation bool `json:"skip_git_validation"`
+	// GitlabIntegrationID is the gitlab integration of the project which the repository is hosted on. It is set instead
+	// of GitRepoID for repositories hosted on gitlab, and a request which sets both is rejected.
+	GitlabIntegrationID uint `json:"gitlab_integration_id"`
+	// DisableRollbackOnFailure leaves the app chart at the failed revision when its upgrade fails, instead of rolling
+	// it back to the previous revision
+	DisableRollbackOnFailure bool `json:"disable_rollback_on_failure"`
+	// MigrateServiceNamespaces allows the deploy to move services which are already deployed to another namespace.
+	// Moved services are uninstalled from their namespace and installed in the other, so their resources are recreated.
+	MigrateServiceNamespaces bool `json:"migrate_service_namespaces"`
+	// TimeoutSeconds bounds the helm install or upgrade of each chart of the app. The server default is used if it is 0,
+	// and the server rejects timeouts above its maximum
+	TimeoutSeconds uint `json:"timeout_seconds" form:"omitempty"`
+	// WaitForJobs waits until the resources of each chart are ready and its jobs, such as the migrations of the
+	// pre-deploy job, have completed before the deploy succeeds. A chart which is not ready within the timeout fails,
+	// and is rolled back unless DisableRollbackOnFailure is set. The error of a deploy which timed out has the
+	// ErrCodeRolloutTimeout code and the status of the pods of the app.
+	WaitForJobs bool `json:"wait_for_jobs"`
+	// Message is the release notes of the deploy, shown in the activity feed, notifications and the pull request
+	// comment. It is collapsed onto a single line and truncated to 280 characters.
+	Message string `json:"message" form:"omitempty"`
+	// GitCommitSHA and GitCommitMessage identify the commit which is deployed, if the app is deployed from a repository.
+	// They are stored on the deploy event, and the commit message is truncated to 1000 characters.
+	GitCommitSHA     string `json:"git_commit_sha" form:"omitempty,hexadecimal,max=64"`
+	GitCommitMessage string `json:"git_commit_message" form:"omitempty"`
+	// RolloutStrategy stages the update of the web and worker services of the app. It has no effect when the app is
+	// created, since there is no release to compare the new image against.
+	RolloutStrategy *RolloutStrategy `json:"rollout_strategy,omitempty" form:"omitempty"`
+	// SourceType sets whether the app is built by Porter or deploys an external image. Apps are created as build apps
+	// if it is not set, and updates which do not set it keep the source type of the app. Image apps must be created
+	// with both the repository and tag of their image, and cannot have build settings.
+	SourceType PorterAppSourceType `json:"source_type,omitempty" form:"omitempty,oneof=build image"`
+	// Tags sets the tags of the app, which label its namespace. Updates which do not set it keep the tags of the app,
+	// and an empty list removes them.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// RolloutStrategyType is how an update of an app is rolled out
+type RolloutStrategyType string
+
+const (
+	// RolloutStrategyType_Canary runs the new image of each web and worker service in a scaled down deployment
+	// alongside the current release, which serves a share of the traffic of the service. The upgrade completes once
+	// the canary has been ready for the bake time, and is aborted if its pods crash loop or are not ready by then.
+	RolloutStrategyType_Canary RolloutStrategyType = "canary"
+)
+
+// RolloutStrategy is how an update of an app is rolled out
+type RolloutStrategy struct {
+	Type RolloutStrategyType `json:"type" form:"required,oneof=canary"`
+	// Percentage is the share of the replicas of each service which the canary runs, rounded up to at least one replica
+	Percentage uint `json:"percentage" form:"required,min=1,max=100"`
+	// BakeTimeSeconds is how long the canary runs before the upgrade completes
+	BakeTimeSeconds uint `json:"bake_time_seconds" form:"required,min=1,max=1800"`
+}
+
+// CreatePorterAppDryRunResponse is the response to a CreatePorterAppRequest with DryRun set
+type CreatePorterAppDryRunResponse struct {
+	// Manifests are the rendered manifests of the app chart
+	Manifests string `json:"manifests"`
+	// Values are the values the app chart was rendered with
+	Values map[string]interface{} `json:"values"`
+	// PreDeployJobManifests and PreDeployJobValues are set if the porter.yaml defines a release job
+	PreDeployJobManifests string                 `json:"pre_deploy_job_manifests,omitempty"`
+	PreDeployJobValues    map[string]interface{} `json:"pre_deploy_job_values,omitempty"`
+	Warnings              []string               `json:"warnings,omitempty"`
+	// ValuesDiff are the changes the update would make to the values of the app's current helm release, if ShowDiff
+	// was set
+	ValuesDiff []HelmValueChange `json:"values_diff,omitempty"`
+}
+
+// HelmValueChangeKind is how a value changed between two sets of helm values
+type HelmValueChangeKind string
+
+const (
+	// HelmValueChangeKind_Added is a value which is only set in the new values
+	HelmValueChangeKind_Added HelmValueChangeKind = "added"
+	// HelmValueChangeKind_Removed is a value which is only set in the old values
+	HelmValueChangeKind_Removed HelmValueChangeKind = "removed"
+	// HelmValueChangeKind_Changed is a value which is set to something else in the new values
+	HelmValueChangeKind_Changed HelmValueChangeKind = "changed"
+)
+
+// HelmValueChange is a single value which differs between two sets of helm values. Maps are compared key by key, so
+// the values are always scalars or lists. Values whose key looks like a credential are redacted.
+type HelmValueChange struct {
+	// Path is the dotted path of the value, such as web-web.container.env.normal.PORT
+	Path string              `json:"path"`
+	Kind HelmValueChangeKind `json:"kind"`
+	Old  interface{}         `json:"old,omitempty"`
+	New  interface{}         `json:"new,omitempty"`
+}
+
+// DiffPorterAppRequest is a porter.yaml to compare against the current release of an app, without deploying it
+type DiffPorterAppRequest struct {
+	PorterYAMLBase64 string `json:"porter_yaml" form:"required"`
+	// ImageInfo is the image the update would deploy. The image of the current release is used if it is not set.
+	ImageInfo        ImageInfo            `json:"image_info" form:"omitempty"`
+	ServiceImageInfo map[string]ImageInfo `json:"service_image_info,omitempty"`
+	OverrideRelease  bool                 `json:"override_release"`
+	// Render renders the app chart with the new values, and diffs its manifests against those of the current release
+	Render bool `schema:"render" json:"render"`
+}
+
+// DiffPorterAppResponse is what an update of an app would change
+type DiffPorterAppResponse struct {
+	ValuesDiff []HelmValueChange `json:"values_diff"`
+	// ManifestDiff is a line diff of the manifests of the current release and those of the update, if Render was set.
+	// The data of secrets, and values whose key looks like a credential, are redacted.
+	ManifestDiff string   `json:"manifest_diff,omitempty"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+// StreamDeployLogsRequest is the request to stream the logs of a deploy of a porter app
+type StreamDeployLogsRequest struct {
+	// Revision is the helm revision the deploy creates. The stream ends once the revision is deployed or fails.
+	Revision int `schema:"revision" form:"required,min=1" doc:"The helm revision the deploy creates, which ends the stream once it is deployed or fails"`
+}
+
+// DeployLogLine is a line logged by a pod of a porter app while the app is deployed. It is sent as a log event of the
+// deploy logs stream.
+type DeployLogLine struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Line      string `json:"line"`
+}
+
+// DeployLogsDone is sent as the done event of the deploy logs stream, once the revision of the deploy reaches a terminal
+// state
+type DeployLogsDone struct {
+	Revision int `json:"revision"`
+	// Status is the helm status of the revision, such as deployed or failed
+	Status      string `json:"status"`
+	Description string `json:"description,omitempty"`
+}
+
+const (
+	// DeployLogsEvent_Log is the server-sent event of a DeployLogLine
+	DeployLogsEvent_Log = "log"
+	// DeployLogsEvent_Done is the server-sent event of a DeployLogsDone, which ends the stream
+	DeployLogsEvent_Done = "done"
+)
+
+// ValidatePorterAppResponse is the response to validating a CreatePorterAppRequest without deploying it. The chart and
+// values are only set if the porter.yaml is valid.
+type ValidatePorterAppResponse struct {
+	// Valid is false if any of the findings is an error, in which case the app would fail to deploy
+	Valid    bool                `json:"valid"`
+	Findings []PorterYAMLFinding `json:"findings"`
+	// Chart is the metadata of the app chart the porter.yaml generates
+	Chart *ValidatedChart `json:"chart,omitempty"`
+	// Values are the values of the app chart, merged with the values of the current release of the app
+	Values map[string]interface{} `json:"values,omitempty"`
+	// PreDeployJobValues are set if the porter.yaml defines a release job
+	PreDeployJobValues map[string]interface{} `json:"pre_deploy_job_values,omitempty"`
+	Warnings           []string               `json:"warnings,omitempty"`
+}
+
+// PorterYAMLFinding is a problem found while validating a porter.yaml
+type PorterYAMLFinding struct {
+	// Line and Column are the 1-indexed position of the finding in porter.yaml, or 0 if it is not about a single node
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Path is the dotted path of the field the finding is about, such as services.web.config.resources.requests.cpu
+	Path     string `json:"path,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// PorterAppValidateYAMLRequest is a porter.yaml to validate on its own, without the app it would be deployed to
+type PorterAppValidateYAMLRequest struct {
+	PorterYAMLBase64 string `json:"porter_yaml" form:"required"`
+	// AppName is the name the app would be deployed as. If set, the names of the resources created for each service are
+	// checked against the kubernetes limits.
+	AppName string `json:"app_name,omitempty"`
+}
+
+// PorterAppValidateYAMLResponse is the response to validating a porter.yaml on its own
+type PorterAppValidateYAMLResponse struct {
+	// Valid is false if there are any errors, in which case the porter.yaml would fail to deploy
+	Valid    bool                `json:"valid"`
+	Errors   []PorterYAMLFinding `json:"errors"`
+	Warnings []PorterYAMLFinding `json:"warnings"`
+}
+
+// ValidatedChart is the metadata of a chart generated from a porter.yaml
+type ValidatedChart struct {
+	Name         string                     `json:"name"`
+	Version      string                     `json:"version"`
+	Dependencies []ValidatedChartDependency `json:"dependencies"`
+}
+
+// ValidatedChartDependency is a chart of a service or add-on included in the app chart
+type ValidatedChartDependency struct {
+	Name       string `json:"name"`
+	Alias      string `json:"alias,omitempty"`
+	Version    string `json:"version"`
+	Repository string `json:"repository"`
+}
+
+// ExportPorterAppResponse is the configuration a porter app is currently deployed with, read from its latest helm
+// release rather than from the porter.yaml in its repo
+type ExportPorterAppResponse struct {
+	// Revision is the helm revision the configuration was read from
+	Revision int `json:"revision"`
+	// Values are the values of the app chart, without the values Porter injects on every deploy
+	Values map[string]interface{} `json:"values"`
+	// PreDeployJobValues are set if the app has a release job
+	PreDeployJobValues map[string]interface{} `json:"pre_deploy_job_values,omitempty"`
+	// PorterYAML deploys the services of the app as they are currently configured
+	PorterYAML string `json:"porter_yaml"`
+	// Warnings name the values which could not be represented in porter.yaml
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 type UpdatePorterAppRequest struct {
@@ -73,12 +536,130 @@ type RunPorterAppCommandRequest struct {
 	Command string `json:"command" form:"required"`
 }
 
+// ListHelmRevisionsRequest is the request to list the helm revisions of a porter app
+type ListHelmRevisionsRequest struct {
+	Limit uint `schema:"limit" doc:"The maximum number of revisions to return, all of them if unset"`
+}
+
+// HelmRevision is a revision of the helm release of a porter app
+type HelmRevision struct {
+	Revision     int       `json:"revision"`
+	ChartVersion string    `json:"chart_version"`
+	AppVersion   string    `json:"app_version"`
+	DeployedAt   time.Time `json:"deployed_at"`
+	Status       string    `json:"status"`
+	Description  string    `json:"description"`
+	// ImageInfo is the image the revision deployed, read from the global image of its values
+	ImageInfo ImageInfo `json:"image_info"`
+}
+
+// ListHelmRevisionsResponse is the helm revision history of a porter app, newest first
+type ListHelmRevisionsResponse struct {
+	Revisions []HelmRevision `json:"revisions"`
+}
+
 type RollbackPorterAppRequest struct {
 	Revision int `json:"revision" form:"required"`
 }
 
+// ListPorterAppRequest is the request to list the porter apps of a cluster
+type ListPorterAppRequest struct {
+	// Tags filters the apps to those with every one of the tags, such as ?tag=payments&tag=staging
+	Tags []string `schema:"tag" doc:"Only list the apps with every one of the tags"`
+}
+
 type ListPorterAppResponse []*PorterApp
 
+// UpdatePorterAppTagsRequest is the request to set the tags of a porter app
+type UpdatePorterAppTagsRequest struct {
+	// Tags replaces the tags of the app. An empty list removes them.
+	Tags []string `json:"tags" doc:"The tags of the app, which replace its current tags"`
+}
+
+// CreateDeployWebhookTokenResponse is the token of the deploy webhook of an app, which is only shown when it is
+// generated
+type CreateDeployWebhookTokenResponse struct {
+	Token string `json:"token" doc:"The token of the deploy webhook, which replaces any previous token of the app"`
+	// WebhookURL is the URL the deploy webhook of the app is called at
+	WebhookURL string `json:"webhook_url" doc:"The URL to POST to in order to deploy the app"`
+}
+
+// DeployWebhookRequest is the request to deploy an app with its deploy webhook
+type DeployWebhookRequest struct {
+	// Repository replaces the image repository of the app. If empty, the current repository is kept.
+	Repository string `json:"repository" doc:"The image repository to deploy, which defaults to the current repository of the app"`
+	Tag        string `json:"tag" form:"required" doc:"The image tag to deploy"`
+}
+
+// DeployWebhookResponse is the revision an app was deployed at by its deploy webhook
+type DeployWebhookResponse struct {
+	Revision int `json:"revision"`
+}
+
+// StackSnapshotVersion is the format version written to every StackSnapshot
+const StackSnapshotVersion = "v1"
+
+// StackSnapshot is a portable bundle containing everything needed to recreate a stack. Secrets are never exported:
+// env groups are recorded by reference, and volumes are recorded by name and size without their data.
+type StackSnapshot struct {
+	Version   string     `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	App       *PorterApp `json:"app"`
+	Namespace string     `json:"namespace"`
+
+	// Values are the helm values of the latest stack release
+	Values map[string]interface{} `json:"values"`
+	// PreDeployValues are the helm values of the latest pre-deploy job release, if one exists
+	PreDeployValues map[string]interface{} `json:"pre_deploy_values,omitempty"`
+
+	EnvGroups []StackSnapshotEnvGroup `json:"env_groups"`
+	Domains   []StackSnapshotDomain   `json:"domains"`
+	Volumes   []StackSnapshotVolume   `json:"volumes"`
+}
+
+// StackSnapshotEnvGroup is a reference to an env group synced to a stack
+type StackSnapshotEnvGroup struct {
+	Name    string `json:"name"`
+	Version uint   `json:"version"`
+}
+
+// StackSnapshotDomain is a host routed to a service of a stack
+type StackSnapshotDomain struct {
+	Service string `json:"service"`
+	Host    string `json:"host"`
+	// PorterManaged is true if the host is a Porter-generated subdomain rather than a custom domain
+	PorterManaged bool `json:"porter_managed"`
+}
+
+// StackSnapshotVolume describes a persistent volume claim in the stack namespace
+type StackSnapshotVolume struct {
+	Name string `json:"name"`
+	// VolumeName is the persistent volume the claim was bound to, used to reattach retained volumes on restore
+	VolumeName   string   `json:"volume_name,omitempty"`
+	StorageClass string   `json:"storage_class,omitempty"`
+	Size         string   `json:"size"`
+	AccessModes  []string `json:"access_modes"`
+}
+
+// RestoreStackSnapshotRequest is the request to recreate a stack from a snapshot
+type RestoreStackSnapshotRequest struct {
+	Snapshot *StackSnapshot `json:"snapshot" form:"required"`
+	// Name is the name of the restored stack. If empty, the name of the snapshotted stack is used.
+	Name string `json:"name"`
+}
+
+// RestoreStackSnapshotResponse is the response to restoring a stack from a snapshot
+type RestoreStackSnapshotResponse struct {
+	App       *PorterApp `json:"app"`
+	Namespace string     `json:"namespace"`
+	// ReattachedVolumes are the volumes from the snapshot which still existed and were attached to the restored stack
+	ReattachedVolumes []string `json:"reattached_volumes"`
+	// NewVolumes are the volumes from the snapshot which no longer exist, and will be provisioned empty
+	NewVolumes []string `json:"new_volumes"`
+	// MissingEnvGroups are env groups referenced by the snapshot which could not be found in the cluster
+	MissingEnvGroups []string `json:"missing_env_groups"`
+}
+
 // PorterAppEvent represents an event that occurs on a Porter stack during a stacks lifecycle.
 type PorterAppEvent struct {
 	ID string `json:"id"`
@@ -95,8 +676,10 @@ type PorterAppEvent struct {
 	// PorterAppID is the ID that the given event relates to
 	PorterAppID uint `json:"porter_app_id"`
 	// DeploymentTargetID is the ID of the deployment target that the given event relates to
-	DeploymentTargetID string         `json:"deployment_target_id"`
-	Metadata           map[string]any `json:"metadata,omitempty"`
+	DeploymentTargetID string `json:"deployment_target_id"`
+	// Message is the release notes of a deploy event
+	Message  string         `json:"message,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 // PorterAppAppEventMetadata represents the metadata for a Porter App Event of type APP_EVENT
@@ -120,6 +703,16 @@ const (
 	PorterAppEventType_AppEvent PorterAppEventType = "APP_EVENT"
 	// PorterAppEventType_Notification represents a translation of the porter agent app event into the new notification format, which details everything that occurs while the app is running
 	PorterAppEventType_Notification PorterAppEventType = "NOTIFICATION"
+	// PorterAppEventType_Alert represents a log alert rule which fired because its pattern matched the app's logs more often than its threshold
+	PorterAppEventType_Alert PorterAppEventType = "ALERT"
+	// PorterAppEventType_Inactivity represents the inactivity policy of the project pausing or deleting an idle app
+	PorterAppEventType_Inactivity PorterAppEventType = "INACTIVITY"
+	// PorterAppEventType_Delete represents a Porter Stack being deleted, along with its helm releases and DNS records
+	PorterAppEventType_Delete PorterAppEventType = "DELETE"
+	// PorterAppEventType_Scale represents the services of a Porter Stack being scaled by hand, outside of a deploy of its porter.yaml
+	PorterAppEventType_Scale PorterAppEventType = "SCALE"
+	// PorterAppEventType_CertExpiry represents the certificate of a domain of a Porter Stack nearing its expiry, or expiring
+	PorterAppEventType_CertExpiry PorterAppEventType = "CERT_EXPIRY"
 )
 
 // PorterAppEventStatus is an alias for a string that represents a Porter Stack Event Status
@@ -151,6 +744,74 @@ type CreateOrUpdatePorterAppEventRequest struct {
 	DeploymentTargetID string         `json:"deployment_target_id"`
 }
 
+// UpdatePorterAppEventRequest finishes an event which is in progress, or adds to its metadata
+type UpdatePorterAppEventRequest struct {
+	// Status is the status the event transitions to. Only PROGRESSING events can transition, and only once.
+	Status PorterAppEventStatus `json:"status,omitempty" form:"omitempty,oneof=SUCCESS FAILED"`
+	// Metadata is merged into the metadata of the event, such as the url of the build logs or the exit code of a
+	// pre-deploy job
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// ListPorterAppEventsRequest pages the legacy events of an app, optionally filtered by type and status
+type ListPorterAppEventsRequest struct {
+	// Page is the page of events listed, starting at 1. Defaults to 1
+	Page int `schema:"page" form:"omitempty,min=0"`
+	// PerPage is the number of events on a page. Defaults to 20
+	PerPage int `schema:"per_page" form:"omitempty,min=0,max=100"`
+	// Type only lists events of this type, such as DEPLOY, if set
+	Type PorterAppEventType `schema:"type" form:"omitempty,oneof=BUILD DEPLOY PRE_DEPLOY APP_EVENT NOTIFICATION ALERT INACTIVITY DELETE CERT_EXPIRY"`
+	// Status only lists events with this status, such as FAILED, if set
+	Status PorterAppEventStatus `schema:"status" form:"omitempty,oneof=SUCCESS FAILED PROGRESSING CANCELED"`
+}
+
+// ListPorterAppEventsResponse is a page of the legacy events of an app, newest first
+type ListPorterAppEventsResponse struct {
+	Events []PorterAppEvent `json:"events"`
+	PaginationResponse
+}
+
+// ListFilteredPorterAppEventsRequest filters and pages the events of an app
+type ListFilteredPorterAppEventsRequest struct {
+	// Type only lists events of this type, such as DEPLOY, if set
+	Type PorterAppEventType `schema:"type" form:"omitempty,oneof=BUILD DEPLOY PRE_DEPLOY APP_EVENT NOTIFICATION ALERT INACTIVITY DELETE CERT_EXPIRY"`
+	// Status only lists events with this status, such as FAILED, if set
+	Status PorterAppEventStatus `schema:"status" form:"omitempty,oneof=SUCCESS FAILED PROGRESSING CANCELED"`
+	// Search only lists events whose message contains every word of it, ignoring case, if set
+	Search string `schema:"search" form:"omitempty,max=280"`
+	// Limit is the number of events listed. Defaults to 20
+	Limit int `schema:"limit" form:"omitempty,min=0,max=100"`
+	// Offset is the number of the newest matching events which are skipped
+	Offset int `schema:"offset" form:"omitempty,min=0"`
+}
+
+// ListFilteredPorterAppEventsResponse is a page of the events of an app, newest first
+type ListFilteredPorterAppEventsResponse struct {
+	Events []PorterAppEvent `json:"events"`
+	// Total is the number of events which match the filters, across every page
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+// CanTransitionTo returns true if an event with the status can move to the next status. Events in progress can
+// finish, while finished events keep their status.
+func (s PorterAppEventStatus) CanTransitionTo(next PorterAppEventStatus) bool {
+	if s == next {
+		return true
+	}
+	if s != PorterAppEventStatus_Progressing {
+		return false
+	}
+
+	switch next {
+	case PorterAppEventStatus_Success, PorterAppEventStatus_Failed, PorterAppEventStatus_Canceled:
+		return true
+	}
+
+	return false
+}
+
 // ServiceDeploymentMetadata contains information about a service when it deploys
 type ServiceDeploymentMetadata struct {
 	// Status is the status of the service deployment
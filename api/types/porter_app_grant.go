@@ -0,0 +1,58 @@
+package types
+
+import "time"
+
+// PorterAppGrantPermission is what an app grant allows its holders to do with the apps it matches
+type PorterAppGrantPermission string
+
+const (
+	// PorterAppGrantPermission_Read lets the holders of a grant read the app
+	PorterAppGrantPermission_Read PorterAppGrantPermission = "read"
+	// PorterAppGrantPermission_Deploy lets the holders of a grant read, deploy, roll back and delete the app
+	PorterAppGrantPermission_Deploy PorterAppGrantPermission = "deploy"
+	// PorterAppGrantPermission_Delete is checked by actions which remove an app, or its name. It is held through deploy
+	// grants, and cannot be granted on its own.
+	PorterAppGrantPermission_Delete PorterAppGrantPermission = "delete"
+)
+
+// PorterAppGrant gives a user, or every member of a project with a role, access to the apps whose names match a pattern.
+// Grants are only enforced in projects which have scoped app access turned on.
+type PorterAppGrant struct {
+	ID        uint `json:"id"`
+	ProjectID uint `json:"project_id"`
+
+	// AppPattern is the name of an app, or a pattern where * matches any part of a name
+	AppPattern string `json:"app_pattern"`
+
+	// UserID is the user the grant is for, or zero if it is for a role
+	UserID uint `json:"user_id,omitempty"`
+	// Role is the project role whose members the grant is for, or empty if it is for a user
+	Role string `json:"role,omitempty"`
+
+	Permission PorterAppGrantPermission `json:"permission"`
+
+	CreatedByUserID uint      `json:"created_by_user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// CreatePorterAppGrantRequest grants a user or a role access to the apps matching a pattern
+type CreatePorterAppGrantRequest struct {
+	AppPattern string `json:"app_pattern" form:"required,max=63" doc:"The name of an app, or a pattern where * matches any part of a name"`
+
+	UserID uint   `json:"user_id" doc:"The user the grant is for. Exactly one of user_id and role must be set."`
+	Role   string `json:"role" form:"omitempty,oneof=admin developer viewer" doc:"The project role whose members the grant is for"`
+
+	Permission PorterAppGrantPermission `json:"permission" form:"required,oneof=read deploy"`
+}
+
+// ListPorterAppGrantsResponse is every app grant of a project
+type ListPorterAppGrantsResponse struct {
+	// ScopedAppAccess is true if the grants are enforced
+	ScopedAppAccess bool             `json:"scoped_app_access"`
+	Grants          []PorterAppGrant `json:"grants"`
+}
+
+// UpdateAppAccessSettingsRequest turns scoped app access on or off for a project
+type UpdateAppAccessSettingsRequest struct {
+	ScopedAppAccess *bool `json:"scoped_app_access" form:"required" doc:"Whether developers need an app grant to deploy, roll back or delete an app"`
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // ProjectList type for entries in the api response on GET /projects
 type ProjectList struct {
 	ID   uint   `json:"id"`
@@ -54,6 +56,10 @@ type Project struct {
 	ManagedDeploymentTargetsEnabled bool    `json:"managed_deployment_targets_enabled"`
 	AdvancedInfraEnabled            bool    `json:"advanced_infra_enabled"`
 	SandboxEnabled                  bool    `json:"sandbox_enabled"`
+	// DefaultClusterID is the cluster the CLI uses when no cluster is configured, or zero if there is none
+	DefaultClusterID uint `json:"default_cluster_id,omitempty"`
+	// ScopedAppAccess is true if developers need an app grant to deploy, roll back or delete an app
+	ScopedAppAccess bool `json:"scoped_app_access"`
 }
 
 // FeatureFlags is a struct that contains old feature flag representations
@@ -103,6 +109,52 @@ type ListProjectsRequest struct{}
 // ListProjectsResponse is a struct that contains the response from a `GET /projects` request
 type ListProjectsResponse []Project
 
+// ProjectListSort is the ordering applied to the projects returned by `GET /v2/projects`
+type ProjectListSort string
+
+const (
+	// ProjectListSortName sorts projects alphabetically by name
+	ProjectListSortName ProjectListSort = "name"
+	// ProjectListSortCreatedAt sorts projects by creation time, newest first
+	ProjectListSortCreatedAt ProjectListSort = "created_at"
+	// ProjectListSortRecentlyActive sorts projects by the time of their latest deploy, most recent first.
+	// Projects that have never been deployed to are sorted last.
+	ProjectListSortRecentlyActive ProjectListSort = "recently_active"
+)
+
+// ListUserProjectsV2Request is a struct that contains the information needed to make a `GET /v2/projects` request
+type ListUserProjectsV2Request struct {
+	PaginationRequest
+
+	// PageSize is the number of projects returned per page, defaulting to 50
+	PageSize int64 `schema:"page_size,omitempty" form:"omitempty,min=1,max=100"`
+	// Search filters projects to those whose name contains the search string (case-insensitive)
+	Search string `schema:"search,omitempty" form:"omitempty,max=255"`
+	// SortBy is the ordering of the returned projects, defaulting to sorting by name
+	SortBy ProjectListSort `schema:"sort_by,omitempty" form:"omitempty,oneof=name created_at recently_active"`
+}
+
+// ProjectSummary is an entry in the `GET /v2/projects` response. It extends ProjectList
+// with the calling user's role and aggregate information about the project.
+type ProjectSummary struct {
+	*ProjectList
+
+	// Role is the kind of role the calling user has in the project
+	Role RoleKind `json:"role"`
+	// NumApps is the number of porter apps in the project
+	NumApps int64 `json:"num_apps"`
+	// NumClusters is the number of clusters in the project
+	NumClusters int64 `json:"num_clusters"`
+	// LastActivityAt is the time of the latest deploy event in the project, if there has been one
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+}
+
+// ListUserProjectsV2Response is a struct that contains the response from a `GET /v2/projects` request
+type ListUserProjectsV2Response struct {
+	Projects   []*ProjectSummary  `json:"projects"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
 // DeleteProjectRequest is a struct that contains the information needed to make a `DELETE /projects` request
 type DeleteProjectRequest struct {
 	Name string `json:"name" form:"required"`
@@ -213,7 +265,77 @@ type UpdateOnboardingStepRequest struct {
 	ExternalId string `json:"external_id"`
 }
 
-// UpdateProjectNameRequest takes in a name to rename projects
+// UpdateProjectNameRequest renames a project or sets its default cluster. Fields which are omitted are left unchanged.
 type UpdateProjectNameRequest struct {
-	Name string `json:"name" form:"required"`
+	Name string `json:"name" doc:"The new name of the project"`
+	// DefaultClusterID is the cluster the CLI uses when no cluster is configured. Zero clears it.
+	DefaultClusterID *uint `json:"default_cluster_id" doc:"The cluster the CLI uses when no cluster is configured, or 0 to clear it"`
+}
+
+// DeploySummaryCommentSettings controls the deploy summary comments posted on the pull requests of a project's apps
+type DeploySummaryCommentSettings struct {
+	Enabled bool `json:"enabled"`
+	// SkipClosedPullRequests stops comments being updated once their pull request is merged or closed
+	SkipClosedPullRequests bool `json:"skip_closed_pull_requests"`
+}
+
+// UpdateDeploySummaryCommentSettingsRequest updates the deploy summary comment settings of a project; omitted fields are left unchanged
+type UpdateDeploySummaryCommentSettingsRequest struct {
+	Enabled                *bool `json:"enabled"`
+	SkipClosedPullRequests *bool `json:"skip_closed_pull_requests"`
+}
+
+// ProjectObservabilityConfig is where the apps of a project send their telemetry. If it is set, the OpenTelemetry
+// environment variables are injected into every service of the project's apps when they are deployed.
+type ProjectObservabilityConfig struct {
+	// OTLPEndpoint is the URL of the OpenTelemetry collector, such as http://otel-collector.monitoring:4317
+	OTLPEndpoint string `json:"otlp_endpoint"`
+	// OTLPProtocol is the protocol used to export to the collector: grpc or http/protobuf. If empty, the SDK default is used
+	OTLPProtocol string `json:"otlp_protocol,omitempty"`
+	// SampleRate is the fraction of traces which are sampled, from 0 to 1
+	SampleRate float64 `json:"sample_rate"`
+}
+
+// UpdateProjectObservabilityConfigRequest sets the observability config of a project
+type UpdateProjectObservabilityConfigRequest struct {
+	OTLPEndpoint string `json:"otlp_endpoint" form:"required,url"`
+	OTLPProtocol string `json:"otlp_protocol" form:"omitempty,oneof=grpc http/protobuf"`
+	// SampleRate defaults to 1, which samples every trace
+	SampleRate *float64 `json:"sample_rate" form:"omitempty,gte=0,lte=1"`
+}
+
+// InvalidateCacheRequest is the request object for the `DELETE projects/{project_id}/cache` endpoint
+type InvalidateCacheRequest struct {
+	// Resource is the kind of cached result to remove: registry-tags or helm-repo-index
+	Resource string `schema:"resource" json:"resource" form:"required,oneof=registry-tags helm-repo-index"`
+	// IntegrationID is the ID of the registry or helm repo to remove cached results for. If it is zero, cached results
+	// for every registry or helm repo in the project are removed
+	IntegrationID uint `schema:"integration_id" json:"integration_id"`
+	// Name limits the removal to a single registry repository. It requires IntegrationID
+	Name string `schema:"name" json:"name"`
+}
+
+// InvalidateCacheResponse is the response object for the `DELETE projects/{project_id}/cache` endpoint
+type InvalidateCacheResponse struct {
+	// Deleted is the number of cache entries removed
+	Deleted int `json:"deleted"`
+}
+
+// ProjectWarningKind_LeakedResources is a warning about the resources of a deleted app which were not released before
+// the deadline of its cleanup
+const ProjectWarningKind_LeakedResources = "LEAKED_RESOURCES"
+
+// ProjectWarning is a problem with a project which needs the attention of its users
+type ProjectWarning struct {
+	Kind      string `json:"kind"`
+	Message   string `json:"message"`
+	ClusterID uint   `json:"cluster_id,omitempty"`
+	// CleanupID is the cleanup a LEAKED_RESOURCES warning is about
+	CleanupID uint      `json:"cleanup_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListProjectWarningsResponse is the response object for the `GET projects/{project_id}/warnings` endpoint
+type ListProjectWarningsResponse struct {
+	Warnings []ProjectWarning `json:"warnings"`
 }
@@ -107,6 +107,67 @@ const (
 // swagger:model ListRegistriesResponse
 type RegistryListResponse []Registry
 
+// RegistryCredentialStatus is the last known state of the credentials a registry is accessed with
+type RegistryCredentialStatus string
+
+const (
+	// RegistryCredentialStatus_Unknown is a registry whose credentials have not been used since it was connected
+	RegistryCredentialStatus_Unknown RegistryCredentialStatus = "unknown"
+	// RegistryCredentialStatus_Valid is a registry whose credentials last generated a pull secret
+	RegistryCredentialStatus_Valid RegistryCredentialStatus = "valid"
+	// RegistryCredentialStatus_Invalid is a registry whose credentials last failed to generate a pull secret. Deploys
+	// skip it unless the image being deployed is pulled from it.
+	RegistryCredentialStatus_Invalid RegistryCredentialStatus = "invalid"
+)
+
+// RegistryCredentialState is the credential status of a registry, which the dashboard shows to prompt fixing broken
+// credentials
+type RegistryCredentialState struct {
+	RegistryID uint                     `json:"registry_id"`
+	Name       string                   `json:"name"`
+	URL        string                   `json:"url"`
+	Service    string                   `json:"service"`
+	Status     RegistryCredentialStatus `json:"status"`
+	// LastError is the error the credentials last failed with
+	LastError string `json:"last_error,omitempty"`
+	// CheckedAt is when the credentials were last used or checked
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// ListRegistryCredentialStatusResponse is the credential status of each registry of a project
+type ListRegistryCredentialStatusResponse []RegistryCredentialState
+
+// RegistryConnectionErrorKind is why a registry could not be reached with its credentials
+type RegistryConnectionErrorKind string
+
+const (
+	// RegistryConnectionErrorKind_Auth is a registry whose credentials could not be generated or were rejected
+	RegistryConnectionErrorKind_Auth RegistryConnectionErrorKind = "auth"
+	// RegistryConnectionErrorKind_Network is a registry, or a service its credentials are generated by, which could not
+	// be reached
+	RegistryConnectionErrorKind_Network RegistryConnectionErrorKind = "network"
+	// RegistryConnectionErrorKind_Permission is a registry whose credentials were accepted, but do not grant access to
+	// pull from it
+	RegistryConnectionErrorKind_Permission RegistryConnectionErrorKind = "permission"
+	// RegistryConnectionErrorKind_Unknown is a registry which responded with an unexpected error
+	RegistryConnectionErrorKind_Unknown RegistryConnectionErrorKind = "unknown"
+)
+
+// TestRegistryConnectionResponse is the outcome of calling the API of a registry with its credentials
+type TestRegistryConnectionResponse struct {
+	RegistryID uint `json:"registry_id"`
+	// Success is true if the registry accepted the credentials
+	Success bool `json:"success"`
+	// ErrorKind is why the registry could not be reached, if it could not
+	ErrorKind RegistryConnectionErrorKind `json:"error_kind,omitempty"`
+	// Error describes the failure, if the registry could not be reached
+	Error string `json:"error,omitempty"`
+	// StatusCode is the status the registry responded with when it refused the request, if it responded
+	StatusCode int `json:"status_code,omitempty"`
+	// CredentialStatus is the credential status of the registry after the test
+	CredentialStatus RegistryCredentialStatus `json:"credential_status"`
+}
+
 // swagger:model
 type CreateRegistryRequest struct {
 	// URL of the container registry
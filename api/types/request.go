@@ -64,6 +64,12 @@ const (
 	URLParamDeploymentTargetIdentifier URLParam = "deployment_target_identifier"
 	URLParamWebhookID                  URLParam = "webhook_id"
 	URLParamJobRunName                 URLParam = "job_run_name"
+	URLParamRunJobID                   URLParam = "run_job_id"
+	URLParamLogAlertRuleID             URLParam = "log_alert_rule_id"
+	URLParamWebhookDeliveryID          URLParam = "webhook_delivery_id"
+	URLParamPorterAppGrantID           URLParam = "porter_app_grant_id"
+	URLParamSecretsProvider            URLParam = "secrets_provider"
+	URLParamPorterAppCleanupID         URLParam = "porter_app_cleanup_id"
 )
 
 type Path struct {
@@ -84,13 +90,87 @@ type APIRequestMetadata struct {
 	// Whether the endpoint upgrades to a websocket
 	IsWebsocket bool
 
+	// The kind of data streamed by a websocket endpoint, used to break down the count of open streams
+	StreamType StreamType
+
 	// Whether the endpoint should check for a usage limit
 	CheckUsage bool
 
 	// The usage metric that the request should check for, if CheckUsage
 	UsageMetric UsageMetric
+
+	// Schema documents the endpoint in the generated API reference
+	Schema *APISchema
+
+	// Timeout selects the time budget of the request. Websocket endpoints are never timed out.
+	Timeout TimeoutClass
+
+	// TokenScope is the scope an API token with scopes must have to call the endpoint. When it is not set, get and
+	// list endpoints require the read scope, and other endpoints cannot be called by API tokens with scopes.
+	TokenScope APITokenScope
+}
+
+// RequiredTokenScope returns the scope an API token with scopes must have to call the endpoint, or an empty scope if
+// only API tokens without scopes may call it
+func (m APIRequestMetadata) RequiredTokenScope() APITokenScope {
+	if m.TokenScope != "" {
+		return m.TokenScope
+	}
+
+	if m.Verb == APIVerbGet || m.Verb == APIVerbList {
+		return APITokenScopeRead
+	}
+
+	return ""
+}
+
+// TimeoutClass is the time budget of a class of endpoints
+type TimeoutClass string
+
+const (
+	// TimeoutClassDefault uses the read budget for GET requests and the write budget for other methods
+	TimeoutClassDefault TimeoutClass = ""
+	// TimeoutClassLong is for endpoints which deploy, such as installing or upgrading a helm release
+	TimeoutClassLong TimeoutClass = "long"
+	// TimeoutClassNone exempts an endpoint from timeouts, for streams and other long-lived responses
+	TimeoutClassNone TimeoutClass = "none"
+)
+
+// APISchema describes an endpoint for the API reference served at /api/swagger.json
+type APISchema struct {
+	// Summary is a one-line description of what the endpoint does
+	Summary string
+
+	// Description is an optional longer explanation of the endpoint
+	Description string
+
+	// Tag groups the endpoint in the API reference, and defaults to the resource in the path
+	Tag string
+
+	// Request is a value of the request type, which is read from the query string of GET requests and
+	// from the JSON body otherwise. It is nil if the endpoint has no request parameters.
+	Request interface{}
+
+	// Response is a value of the type written on success, or nil if the endpoint writes no body
+	Response interface{}
 }
 
+// StreamType is the kind of data sent over a streaming connection
+type StreamType string
+
+const (
+	// StreamTypeLogs streams pod or app logs
+	StreamTypeLogs StreamType = "logs"
+	// StreamTypeProvisioning streams infra provisioning state and logs
+	StreamTypeProvisioning StreamType = "provisioning"
+	// StreamTypeEvents streams job runs and other kubernetes events
+	StreamTypeEvents StreamType = "events"
+	// StreamTypeStatus streams the status of kubernetes resources or helm releases
+	StreamTypeStatus StreamType = "status"
+	// StreamTypeOther is used for streams which do not declare a type
+	StreamTypeOther StreamType = "other"
+)
+
 const RequestScopeCtxKey = "requestscopes"
 
 type RequestAction struct {
@@ -0,0 +1,96 @@
+package types
+
+import "time"
+
+// ScalingScheduleApply is how a scaling schedule changes the replicas of a service when a window opens or closes
+type ScalingScheduleApply string
+
+const (
+	// ScalingScheduleApply_Patch scales the deployment of the service directly, without a new helm revision. The next
+	// deploy of the app resets the replicas, and the schedule scales the service again if a window is open.
+	ScalingScheduleApply_Patch ScalingScheduleApply = "patch"
+	// ScalingScheduleApply_Upgrade upgrades the helm release of the app with the replicas of the service changed, which
+	// creates a revision for every window which opens or closes
+	ScalingScheduleApply_Upgrade ScalingScheduleApply = "upgrade"
+)
+
+// ScalingWindow is a time of the week during which a service runs a set number of replicas
+type ScalingWindow struct {
+	// Name identifies the window in the app status. Windows without a name are identified by their position in the list.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Days are the days of the week the window opens on, written as the day of week field of a cron expression, such as
+	// MON-FRI, SAT,SUN or *
+	Days string `json:"days" yaml:"days"`
+	// Start is the time of day the window opens, as HH:MM
+	Start string `json:"start" yaml:"start"`
+	// End is the time of day the window closes, as HH:MM. A window which ends at or before its start closes on the next
+	// day.
+	End      string `json:"end" yaml:"end"`
+	Replicas int    `json:"replicas" yaml:"replicas"`
+}
+
+// ServiceScalingSchedule is the scaling section of a service in porter.yaml. Outside of its windows, a service runs the
+// replicas set by its config.
+type ServiceScalingSchedule struct {
+	// Timezone is the IANA time zone the windows are in, such as America/New_York. Defaults to UTC
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	// Apply is how the replicas are changed. Defaults to patch
+	Apply     ScalingScheduleApply `json:"apply,omitempty" yaml:"apply,omitempty"`
+	Schedules []ScalingWindow      `json:"schedules" yaml:"schedules"`
+}
+
+// ServiceScalingStatus is where a service is in its scaling schedule
+type ServiceScalingStatus struct {
+	Service  string `json:"service"`
+	Timezone string `json:"timezone"`
+	// ActiveWindow is the window which is open, if any
+	ActiveWindow string `json:"active_window,omitempty"`
+	// Replicas is the number of replicas the schedule runs the service with now
+	Replicas int `json:"replicas"`
+	// AppliedReplicas is the number of replicas the schedule last scaled the service to, and AppliedAt when it did
+	AppliedReplicas *int       `json:"applied_replicas,omitempty"`
+	AppliedAt       *time.Time `json:"applied_at,omitempty"`
+	// LastError is why the schedule last failed to scale the service, if it has not succeeded since
+	LastError string `json:"last_error,omitempty"`
+	// NextTransitionAt is when the number of replicas next changes, and NextReplicas what it changes to
+	NextTransitionAt *time.Time `json:"next_transition_at,omitempty"`
+	NextReplicas     int        `json:"next_replicas"`
+}
+
+// ScalingScheduleStatus is where the services of an app are in their scaling schedules
+type ScalingScheduleStatus struct {
+	// Paused is true if the schedules are suspended, in which case the services keep the replicas they have
+	Paused   bool                   `json:"paused"`
+	Services []ServiceScalingStatus `json:"services"`
+}
+
+// UpdateScalingScheduleRequest pauses or resumes the scaling schedules of an app
+type UpdateScalingScheduleRequest struct {
+	Paused *bool `json:"paused" form:"required" doc:"Suspend the scaling schedules of the app, leaving its services at the replicas they have"`
+}
+
+// ScaleAppRequest scales services of an app to a number of replicas, without deploying its porter.yaml
+type ScaleAppRequest struct {
+	Services map[string]int `json:"services" form:"required,min=1,dive,min=0" doc:"The replicas to run, by the name of the service in porter.yaml"`
+	// Apply defaults to ScalingScheduleApply_Upgrade
+	Apply ScalingScheduleApply `json:"apply,omitempty" form:"omitempty,oneof=patch upgrade" doc:"upgrade changes the replicas in the values of the release of the app. patch scales the deployments first, then syncs the values of the release"`
+	// AdjustAutoscaling sets the minimum replicas of autoscaled services, which are refused otherwise since their
+	// autoscaler sets their replicas
+	AdjustAutoscaling bool `json:"adjust_autoscaling" doc:"Set the minimum replicas of the autoscaler of autoscaled services, instead of refusing to scale them"`
+}
+
+// ServiceScale is a change of the replicas of a service
+type ServiceScale struct {
+	Service string `json:"service"`
+	Before  int    `json:"before"`
+	After   int    `json:"after"`
+	// Autoscaled is true if the minimum replicas of the autoscaler of the service were changed
+	Autoscaled bool `json:"autoscaled,omitempty"`
+}
+
+// ScaleAppResponse lists the services which were scaled. Services which already ran the requested replicas are left out.
+type ScaleAppResponse struct {
+	Services []ServiceScale `json:"services"`
+	// Warnings are problems which did not stop the services from being scaled
+	Warnings []string `json:"warnings,omitempty"`
+}
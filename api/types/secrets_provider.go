@@ -0,0 +1,87 @@
+package types
+
+import "time"
+
+// SecretsProvider is an external secret store which env variables in porter.yaml can reference with valueFrom
+type SecretsProvider string
+
+const (
+	// SecretsProvider_Vault is a HashiCorp Vault KV version 2 secrets engine
+	SecretsProvider_Vault SecretsProvider = "vault"
+	// SecretsProvider_AWSSecretsManager is AWS Secrets Manager, accessed with one of the project's AWS integrations
+	SecretsProvider_AWSSecretsManager SecretsProvider = "aws_secrets_manager"
+)
+
+// VaultAuthMethod is how Porter logs in to Vault
+type VaultAuthMethod string
+
+const (
+	// VaultAuthMethod_Token uses a Vault token directly
+	VaultAuthMethod_Token VaultAuthMethod = "token"
+	// VaultAuthMethod_AppRole logs in with an AppRole role ID and secret ID before every deploy
+	VaultAuthMethod_AppRole VaultAuthMethod = "approle"
+)
+
+// SecretsProviderIntegration is the config Porter uses to read secrets from an external store when an app is deployed.
+// Credentials are never returned.
+type SecretsProviderIntegration struct {
+	ID        uint            `json:"id"`
+	ProjectID uint            `json:"project_id"`
+	Provider  SecretsProvider `json:"provider"`
+
+	Vault *VaultSecretsProviderConfig `json:"vault,omitempty"`
+	AWS   *AWSSecretsProviderConfig   `json:"aws,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// VaultSecretsProviderConfig is the connection to a Vault server, without its credentials
+type VaultSecretsProviderConfig struct {
+	Address string `json:"address"`
+	// Namespace is the Vault Enterprise namespace, if any
+	Namespace string `json:"namespace,omitempty"`
+	// Mount is the path the KV version 2 secrets engine is mounted at
+	Mount      string          `json:"mount"`
+	AuthMethod VaultAuthMethod `json:"auth_method"`
+}
+
+// AWSSecretsProviderConfig is the AWS integration and region secrets are read with
+type AWSSecretsProviderConfig struct {
+	AWSIntegrationID uint   `json:"aws_integration_id"`
+	Region           string `json:"region"`
+}
+
+// UpdateSecretsProviderRequest is the request to create or replace the config of a secrets provider of a project, or
+// to test a config before saving it
+type UpdateSecretsProviderRequest struct {
+	Provider SecretsProvider `json:"provider" form:"required,oneof=vault aws_secrets_manager" doc:"The external secret store, either vault or aws_secrets_manager"`
+
+	Vault *UpdateVaultSecretsProviderRequest `json:"vault,omitempty" doc:"The Vault server to read secrets from, for the vault provider"`
+	AWS   *UpdateAWSSecretsProviderRequest   `json:"aws,omitempty" doc:"The AWS integration and region to read secrets from, for the aws_secrets_manager provider"`
+}
+
+// UpdateVaultSecretsProviderRequest is the connection to a Vault server with its credentials. Credentials which are left
+// empty keep their saved value, so that the other settings can be changed without sending them again.
+type UpdateVaultSecretsProviderRequest struct {
+	Address    string          `json:"address" form:"required,url" doc:"The address of the Vault server, such as https://vault.example.com:8200"`
+	Namespace  string          `json:"namespace" doc:"The Vault Enterprise namespace secrets are read from"`
+	Mount      string          `json:"mount" doc:"The path the KV version 2 secrets engine is mounted at. Defaults to secret"`
+	AuthMethod VaultAuthMethod `json:"auth_method" form:"required,oneof=token approle" doc:"How Porter logs in to Vault, either token or approle"`
+	Token      string          `json:"token" doc:"The Vault token, for the token auth method"`
+	RoleID     string          `json:"role_id" doc:"The AppRole role ID, for the approle auth method"`
+	SecretID   string          `json:"secret_id" doc:"The AppRole secret ID, for the approle auth method"`
+}
+
+// UpdateAWSSecretsProviderRequest is the AWS integration and region secrets are read with
+type UpdateAWSSecretsProviderRequest struct {
+	AWSIntegrationID uint   `json:"aws_integration_id" form:"required" doc:"The ID of the project's AWS integration secrets are read with"`
+	Region           string `json:"region" form:"required" doc:"The AWS region the secrets are stored in"`
+}
+
+// TestSecretsProviderResponse is the result of connecting to a secrets provider
+type TestSecretsProviderResponse struct {
+	Provider SecretsProvider `json:"provider"`
+	// Error is why Porter could not connect, or empty if it connected
+	Error string `json:"error,omitempty"`
+}
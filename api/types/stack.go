@@ -4,6 +4,9 @@ type CreateStackReleaseRequest struct {
 	StackName        string    `json:"stack_name" form:"required,dns1123"`
 	PorterYAMLBase64 string    `json:"porter_yaml" form:"required"`
 	ImageInfo        ImageInfo `json:"image_info" form:"omitempty"`
+	// ServiceImageInfo sets the image of individual services, by their name in porter.yaml. Services which are not in
+	// it run ImageInfo.
+	ServiceImageInfo map[string]ImageInfo `json:"service_image_info,omitempty"`
 }
 
 type ImageInfo struct {
@@ -11,6 +14,11 @@ type ImageInfo struct {
 	Tag        string `json:"tag"`
 }
 
+// Complete reports whether both the repository and the tag of the image are set
+func (i ImageInfo) Complete() bool {
+	return i.Repository != "" && i.Tag != ""
+}
+
 type CreateSecretAndOpenGHPRRequest struct {
 	GithubAppInstallationID  int64  `json:"github_app_installation_id" form:"required"`
 	GithubRepoOwner          string `json:"github_repo_owner" form:"required"`
@@ -0,0 +1,18 @@
+package types
+
+// TelemetrySettings are the tracing settings of the server which instance admins can change at runtime
+type TelemetrySettings struct {
+	// Enabled is true if spans are recorded
+	Enabled bool `json:"enabled"`
+	// Configured is true if the server exports spans to a collector. Tracing can only be enabled if it is
+	Configured bool `json:"configured"`
+	// SampleRatio is the ratio of traces recorded by the ratio and parent_based samplers
+	SampleRatio float64 `json:"sample_ratio"`
+}
+
+// UpdateTelemetrySettingsRequest changes the tracing settings of the server, without restarting it. Unset fields are
+// left as they are.
+type UpdateTelemetrySettingsRequest struct {
+	Enabled     *bool    `json:"enabled,omitempty"`
+	SampleRatio *float64 `json:"sample_ratio,omitempty" form:"omitempty,gte=0,lte=1"`
+}
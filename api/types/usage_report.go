@@ -0,0 +1,62 @@
+package types
+
+// UsageReportFormat is the format a usage report is exported in
+type UsageReportFormat string
+
+const (
+	// UsageReportFormat_JSON exports a usage report as JSON
+	UsageReportFormat_JSON UsageReportFormat = "json"
+	// UsageReportFormat_CSV exports a usage report as CSV, with one row for each app
+	UsageReportFormat_CSV UsageReportFormat = "csv"
+)
+
+// Usage counts the deploys and builds of an app or project
+type Usage struct {
+	Deploys              int     `json:"deploys"`
+	DeployFailures       int     `json:"deploy_failures"`
+	HelmOperationSeconds float64 `json:"helm_operation_seconds"`
+	Builds               int     `json:"builds"`
+	BuildFailures        int     `json:"build_failures"`
+	// BuildSeconds only includes the builds which reported when they finished
+	BuildSeconds float64 `json:"build_seconds"`
+}
+
+// Add adds other to u
+func (u *Usage) Add(other Usage) {
+	u.Deploys += other.Deploys
+	u.DeployFailures += other.DeployFailures
+	u.HelmOperationSeconds += other.HelmOperationSeconds
+	u.Builds += other.Builds
+	u.BuildFailures += other.BuildFailures
+	u.BuildSeconds += other.BuildSeconds
+}
+
+// AppUsage is the usage of a single app over the period of a usage report
+type AppUsage struct {
+	PorterAppID uint   `json:"porter_app_id"`
+	AppName     string `json:"app_name"`
+	Usage
+}
+
+// UsageReport is the usage of a project and each of its apps over a period of days
+type UsageReport struct {
+	ProjectID uint `json:"project_id"`
+	// Start is the first day of the period, formatted as YYYY-MM-DD
+	Start string `json:"start"`
+	// End is the last day of the period, formatted as YYYY-MM-DD
+	End   string     `json:"end"`
+	Total Usage      `json:"total"`
+	Apps  []AppUsage `json:"apps"`
+	// MissingDays are the days in the period which have not been rolled up yet, and are not included in the report
+	MissingDays []string `json:"missing_days"`
+}
+
+// GetUsageReportRequest is the request object for the GET /projects/{project_id}/usage/report endpoint
+type GetUsageReportRequest struct {
+	// Start is the first day of the period, formatted as YYYY-MM-DD
+	Start string `schema:"start" form:"required"`
+	// End is the last day of the period, formatted as YYYY-MM-DD
+	End string `schema:"end" form:"required"`
+	// Format is json or csv, and defaults to json
+	Format UsageReportFormat `schema:"format" form:"omitempty,oneof=json csv"`
+}
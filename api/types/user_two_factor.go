@@ -0,0 +1,45 @@
+package types
+
+// LoginTwoFactorRequiredResponse is returned by POST /login instead of the user when the user has enabled a second
+// factor, which must then be submitted to POST /login/2fa within the same session
+type LoginTwoFactorRequiredResponse struct {
+	TwoFactorRequired bool `json:"two_factor_required"`
+}
+
+// LoginTwoFactorRequest is the request to POST /login/2fa
+type LoginTwoFactorRequest struct {
+	// Code is a code from the user's authenticator app, or one of their backup codes
+	Code string `json:"code" form:"required,max=32"`
+}
+
+// TwoFactorStatus is the response to GET /users/current/2fa
+type TwoFactorStatus struct {
+	Enabled              bool `json:"enabled"`
+	BackupCodesRemaining int  `json:"backup_codes_remaining"`
+}
+
+// EnrollTwoFactorResponse is the response to POST /users/current/2fa. The second factor is only enabled once a code
+// generated from the secret is submitted to POST /users/current/2fa/enable.
+type EnrollTwoFactorResponse struct {
+	// Secret is the base32 encoded secret, for authenticator apps which cannot scan a QR code
+	Secret string `json:"secret"`
+	// ProvisioningURI is the otpauth URI of the secret, which is shown as a QR code
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// EnableTwoFactorRequest is the request to POST /users/current/2fa/enable
+type EnableTwoFactorRequest struct {
+	Code string `json:"code" form:"required,max=32"`
+}
+
+// EnableTwoFactorResponse is the response to POST /users/current/2fa/enable
+type EnableTwoFactorResponse struct {
+	// BackupCodes are only returned once, when the second factor is enabled
+	BackupCodes []string `json:"backup_codes"`
+}
+
+// DisableTwoFactorRequest is the request to DELETE /users/current/2fa
+type DisableTwoFactorRequest struct {
+	// Code is a code from the user's authenticator app, or one of their backup codes
+	Code string `json:"code" form:"required,max=32"`
+}
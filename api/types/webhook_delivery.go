@@ -0,0 +1,55 @@
+package types
+
+import "time"
+
+// WebhookDeliveryStatus filters webhook deliveries by their outcome
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatus_Succeeded is an attempt which the webhook answered with a 2xx or 3xx status
+	WebhookDeliveryStatus_Succeeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryStatus_Failed is an attempt which got no response or an error status
+	WebhookDeliveryStatus_Failed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is a single attempt at delivering an event to a webhook
+type WebhookDelivery struct {
+	ID             uint   `json:"id"`
+	LogAlertRuleID uint   `json:"log_alert_rule_id"`
+	EventID        string `json:"event_id"`
+	// ChainID is shared by the attempts of a delivery. Redelivering an event starts a new chain.
+	ChainID string `json:"chain_id"`
+	Attempt uint   `json:"attempt"`
+	// Replay is true for the attempts of a redelivery
+	Replay         bool              `json:"replay"`
+	TargetURL      string            `json:"target_url"`
+	RequestHeaders map[string]string `json:"request_headers"`
+	BodySHA256     string            `json:"body_sha256"`
+	// ResponseCode is 0 if the webhook did not respond, in which case Error says why
+	ResponseCode int       `json:"response_code"`
+	Error        string    `json:"error,omitempty"`
+	LatencyMS    int64     `json:"latency_ms"`
+	Succeeded    bool      `json:"succeeded"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ListWebhookDeliveriesRequest filters the deliveries of a webhook
+type ListWebhookDeliveriesRequest struct {
+	Status WebhookDeliveryStatus `schema:"status" doc:"Only return attempts which succeeded or failed"`
+	Limit  uint                  `schema:"limit" doc:"The maximum number of attempts to return, newest first. Defaults to 50"`
+}
+
+// ListWebhookDeliveriesResponse lists the delivery attempts of a webhook, newest first
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery    `json:"deliveries"`
+	Stats      WebhookDeliveryStats `json:"stats"`
+}
+
+// WebhookDeliveryStats summarizes the delivery attempts of a webhook since a point in time
+type WebhookDeliveryStats struct {
+	Since     time.Time `json:"since"`
+	Attempts  int64     `json:"attempts"`
+	Succeeded int64     `json:"succeeded"`
+	// SuccessRate is the fraction of attempts which succeeded, or 0 if there were none
+	SuccessRate float64 `json:"success_rate"`
+}
@@ -22,6 +22,12 @@ func RegisterCommands() (*cobra.Command, error) {
 		Long:  `Porter is a tool for creating, versioning, and updating Kubernetes deployments using a visual dashboard. For more information, visit github.com/porter-dev/porter`,
 	}
 	rootCmd.PersistentFlags().AddFlagSet(utils.DefaultFlagSet)
+	// the flag is not part of the default flag set, which is bound to the config file, so that it is never saved
+	rootCmd.PersistentFlags().String(
+		"record-session",
+		"",
+		"record the requests sent to the Porter API, and their responses, to a file to attach to a support ticket. Secrets, tokens and env variable values are redacted",
+	)
 
 	rootCmd.AddCommand(registerCommand_App(cliConf))
 	rootCmd.AddCommand(registerCommand_Apply(cliConf))
@@ -85,6 +91,10 @@ func overrideConfigWithFlags(cmd *cobra.Command, config config.CLIConfig) config
 			stringName:         "kubeconfig",
 			stringConfigTarget: &config.Kubeconfig,
 		},
+		{
+			stringName:         "record-session",
+			stringConfigTarget: &config.RecordSession,
+		},
 		{
 			uintName:         "project",
 			uintConfigTarget: &config.Project,
@@ -3,19 +3,25 @@ package commands
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/cli/cmd/config"
 	"github.com/porter-dev/porter/cli/cmd/utils"
 	v2 "github.com/porter-dev/porter/cli/cmd/v2"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+	"github.com/porter-dev/porter/internal/porter_app/lint"
 	"github.com/spf13/cobra"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
@@ -46,6 +52,18 @@ var (
 	appWait              bool
 	deploymentTargetName string
 	jobName              string
+
+	appImportFromRelease string
+	appImportAppName     string
+	appImportNamespace   string
+
+	appLintAppName string
+	appLintOutput  string
+
+	appScaleService           string
+	appScaleReplicas          int
+	appScaleApply             string
+	appScaleAdjustAutoscaling bool
 )
 
 const (
@@ -139,6 +157,54 @@ func registerCommand_App(cliConf config.CLIConfig) *cobra.Command {
 	}
 	appCmd.AddCommand(appRollbackCmd)
 
+	// appScaleCmd represents the "porter app scale" subcommand
+	appScaleCmd := &cobra.Command{
+		Use:   "scale [application]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Sets the replicas of a service of an application without deploying its porter.yaml.",
+		Long: fmt.Sprintf(`%s
+
+Sets the replicas of a web or worker service of an application, changing only its replicas in the
+values of the latest release. The next deploy of the porter.yaml warns if it sets other replicas.
+Autoscaled services are refused unless --adjust-autoscaling is set, which sets the minimum replicas
+of their autoscaler instead:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app scale\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app scale my-app --service web --replicas 10"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appScale)
+		},
+	}
+
+	appScaleCmd.Flags().StringVar(
+		&appScaleService,
+		"service",
+		"",
+		"the name of the service in porter.yaml",
+	)
+	appScaleCmd.Flags().IntVar(
+		&appScaleReplicas,
+		"replicas",
+		-1,
+		"the number of replicas to run",
+	)
+	appScaleCmd.Flags().StringVar(
+		&appScaleApply,
+		"apply",
+		string(types.ScalingScheduleApply_Upgrade),
+		"how the service is scaled: \"upgrade\" upgrades the release, \"patch\" scales the deployment first and syncs the release after",
+	)
+	appScaleCmd.Flags().BoolVar(
+		&appScaleAdjustAutoscaling,
+		"adjust-autoscaling",
+		false,
+		"set the minimum replicas of the autoscaler of an autoscaled service",
+	)
+	appCmd.AddCommand(appScaleCmd)
+
 	// appManifestsCmd represents the "porter app manifest" subcommand
 	appManifestsCmd := &cobra.Command{
 		Use:   "manifests [application]",
@@ -150,6 +216,96 @@ func registerCommand_App(cliConf config.CLIConfig) *cobra.Command {
 	}
 	appCmd.AddCommand(appManifestsCmd)
 
+	// appImportCmd represents the "porter app import" subcommand
+	appImportCmd := &cobra.Command{
+		Use:   "import",
+		Args:  cobra.NoArgs,
+		Short: "Imports a helm release deployed outside of Porter as an application, without redeploying it.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appImport)
+		},
+	}
+
+	appImportCmd.Flags().StringVar(
+		&appImportFromRelease,
+		"from-release",
+		"",
+		"the name of the helm release to import",
+	)
+	appImportCmd.Flags().StringVar(
+		&appImportAppName,
+		"name",
+		"",
+		"the name of the application the release is imported as, defaults to the release name",
+	)
+	appImportCmd.PersistentFlags().StringVarP(
+		&appImportNamespace,
+		"namespace",
+		"n",
+		"default",
+		"the namespace of the helm release",
+	)
+	appCmd.AddCommand(appImportCmd)
+
+	// appImportListCmd represents the "porter app import list" subcommand
+	appImportListCmd := &cobra.Command{
+		Use:   "list",
+		Args:  cobra.NoArgs,
+		Short: "Lists the helm releases in a namespace which can be imported, and the reasons the others cannot.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appImportList)
+		},
+	}
+	appImportCmd.AddCommand(appImportListCmd)
+
+	// appLintCmd represents the "porter app lint" subcommand
+	appLintCmd := &cobra.Command{
+		Use:   "lint [file]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Checks a porter.yaml for errors without connecting to Porter.",
+		Long: fmt.Sprintf(`%s
+
+Checks a v1stack porter.yaml for the errors which would stop it from being deployed, such as
+invalid service names, ports, cron schedules and env values, without connecting to Porter. The
+same checks are run by Porter when the porter.yaml is applied. Checks which need the project or
+the cluster are only run when the porter.yaml is applied.
+
+The file defaults to porter.yaml in the current directory. Findings are printed with their line
+and column, and the command exits with a non-zero status if any of them are errors. Use
+--output json to print the findings for annotations in CI:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app lint\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app lint porter.yaml --output json"),
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			ok, err := appLint(cmd.OutOrStdout(), args)
+			if err != nil {
+				_, _ = color.New(color.FgRed).Fprintf(os.Stderr, "Error: %s\n", err.Error())
+				os.Exit(1)
+			}
+			if !ok {
+				os.Exit(1)
+			}
+		},
+	}
+
+	appLintCmd.Flags().StringVar(
+		&appLintAppName,
+		"name",
+		"",
+		"the name the app is deployed as, used to check the length of the names of its resources (defaults to PORTER_APP_NAME)",
+	)
+	appLintCmd.Flags().StringVarP(
+		&appLintOutput,
+		"output",
+		"o",
+		"",
+		"the output format to use (\"json\" or the default text output)",
+	)
+	appCmd.AddCommand(appLintCmd)
+
 	return appCmd
 }
 
@@ -240,6 +396,141 @@ func appManifests(ctx context.Context, _ *types.GetAuthenticatedUserResponse, cl
 	return nil
 }
 
+func appImport(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
+	if appImportFromRelease == "" {
+		return fmt.Errorf("--from-release must be specified, run \"porter app import list\" to see the releases which can be imported")
+	}
+
+	resp, err := client.ImportRelease(ctx, cliConfig.Project, cliConfig.Cluster, &porter_app.ImportReleaseRequest{
+		ReleaseName: appImportFromRelease,
+		Namespace:   appImportNamespace,
+		AppName:     appImportAppName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import release: %w", err)
+	}
+
+	_, _ = color.New(color.FgGreen).Printf("Imported release %s in namespace %s as application %s\n", resp.Import.ReleaseName, resp.Import.Namespace, resp.App.Name)
+
+	if resp.Import.Mode == types.HelmReleaseImportMode_ExternalChart {
+		_, _ = color.New(color.FgYellow).Printf("Chart %s is not a Porter application chart, so Porter will only upgrade the release with new values\n", resp.Import.ChartName)
+	}
+	for _, service := range resp.Release.Services {
+		fmt.Printf("  %s (%s) %s:%s\n", service.Name, service.Type, service.ImageRepository, service.ImageTag)
+	}
+
+	fmt.Println("Nothing was redeployed. The next upgrade through Porter will be recorded as the point Porter adopted the release.")
+
+	return nil
+}
+
+func appImportList(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
+	resp, err := client.ListImportableReleases(ctx, cliConfig.Project, cliConfig.Cluster, appImportNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to list importable releases: %w", err)
+	}
+
+	if len(resp.Releases) == 0 {
+		_, _ = color.New(color.FgBlue).Printf("No helm releases deployed outside of Porter found in namespace %s\n", appImportNamespace)
+		return nil
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 2, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "NAME", "NAMESPACE", "CHART", "MODE", "REASONS")
+
+	for _, rel := range resp.Releases {
+		mode := string(rel.Mode)
+		if !rel.Importable {
+			mode = "-"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", rel.Name, rel.Namespace, fmt.Sprintf("%s-%s", rel.Chart, rel.ChartVersion), mode, strings.Join(rel.Reasons, "; "))
+	}
+
+	return w.Flush()
+}
+
+// appLintResult is the output of porter app lint with --output json
+type appLintResult struct {
+	File     string         `json:"file"`
+	Findings []lint.Finding `json:"findings"`
+	// SkippedChecks are the checks which are only run when porter.yaml is applied
+	SkippedChecks []string `json:"skipped_checks"`
+}
+
+// appLint prints the findings for a porter.yaml, and reports whether none of them are errors
+func appLint(out io.Writer, args []string) (bool, error) {
+	file := "porter.yaml"
+	if len(args) > 0 {
+		file = args[0]
+	}
+
+	if appLintOutput != "" && appLintOutput != "json" {
+		return false, fmt.Errorf("unsupported output format %q, expected \"json\"", appLintOutput)
+	}
+
+	porterYaml, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return false, fmt.Errorf("error reading porter.yaml: %w", err)
+	}
+
+	appName := appLintAppName
+	if appName == "" {
+		appName = appNameFromEnvironmentVariable()
+	}
+
+	findings := lint.Lint(porterYaml, lint.Options{AppName: appName, ResolvesFromFile: true})
+
+	// env variables set with fromFile are read by the CLI, so the files they name can be checked here
+	if lint.Errors(findings) == nil {
+		if _, err := envvalues.ResolveFiles(porterYaml, filepath.Dir(file)); err != nil {
+			findings = append(findings, lint.Finding{Severity: lint.SeverityError, Message: err.Error()})
+		}
+	}
+
+	ok := lint.Errors(findings) == nil
+
+	if appLintOutput == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		return ok, enc.Encode(appLintResult{File: file, Findings: findings, SkippedChecks: lint.ServerOnlyChecks})
+	}
+
+	counts := map[lint.Severity]int{}
+	for _, finding := range findings {
+		counts[finding.Severity]++
+
+		c := color.New(color.FgBlue)
+		switch finding.Severity {
+		case lint.SeverityError:
+			c = color.New(color.FgRed)
+		case lint.SeverityWarning:
+			c = color.New(color.FgYellow)
+		}
+
+		location := file
+		if finding.Line != 0 {
+			location = fmt.Sprintf("%s:%d:%d", file, finding.Line, finding.Column)
+		}
+		_, _ = fmt.Fprintf(out, "%s: %s: %s\n", location, c.Sprint(finding.Severity), finding.Message)
+	}
+
+	if len(findings) == 0 {
+		_, _ = color.New(color.FgGreen).Fprintf(out, "No problems found in %s\n", file)
+	} else {
+		_, _ = fmt.Fprintf(out, "\nFound %d errors and %d warnings in %s\n", counts[lint.SeverityError], counts[lint.SeverityWarning], file)
+	}
+
+	_, _ = fmt.Fprintf(out, "Skipped the checks which are only run when porter.yaml is applied: %s\n", strings.Join(lint.ServerOnlyChecks, ", "))
+
+	return ok, nil
+}
+
 func appRollback(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
 	project, err := client.GetProject(ctx, cliConfig.Project)
 	if err != nil {
@@ -267,6 +558,42 @@ func appRollback(ctx context.Context, _ *types.GetAuthenticatedUserResponse, cli
 	return nil
 }
 
+func appScale(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	if appScaleService == "" {
+		return fmt.Errorf("--service must be specified")
+	}
+	if appScaleReplicas < 0 {
+		return fmt.Errorf("--replicas must be specified")
+	}
+
+	resp, err := client.ScalePorterApp(ctx, cliConfig.Project, cliConfig.Cluster, args[0], &types.ScaleAppRequest{
+		Services:          map[string]int{appScaleService: appScaleReplicas},
+		Apply:             types.ScalingScheduleApply(appScaleApply),
+		AdjustAutoscaling: appScaleAdjustAutoscaling,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scale app: %w", err)
+	}
+
+	if len(resp.Services) == 0 {
+		_, _ = color.New(color.FgBlue).Printf("Service %s already runs %d replicas\n", appScaleService, appScaleReplicas)
+		return nil
+	}
+
+	for _, service := range resp.Services {
+		target := "replicas"
+		if service.Autoscaled {
+			target = "minimum replicas"
+		}
+		_, _ = color.New(color.FgGreen).Printf("Scaled the %s of service %s from %d to %d\n", target, service.Service, service.Before, service.After)
+	}
+	for _, warning := range resp.Warnings {
+		_, _ = color.New(color.FgYellow).Printf("Warning: %s\n", warning)
+	}
+
+	return nil
+}
+
 func appRun(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, ff config.FeatureFlags, _ *cobra.Command, args []string) error {
 	if jobName != "" {
 		if !ff.ValidateApplyV2Enabled {
@@ -1291,7 +1618,7 @@ func appUpdateTag(ctx context.Context, user *types.GetAuthenticatedUserResponse,
 
 		_, _ = color.New(color.FgGreen).Printf("Updating application %s to build using tag \"%s\"\n", args[0], appTag)
 
-		_, err = client.CreatePorterApp(
+		app, err := client.CreatePorterApp(
 			ctx,
 			cliConf.Project,
 			cliConf.Cluster,
@@ -1302,7 +1629,7 @@ func appUpdateTag(ctx context.Context, user *types.GetAuthenticatedUserResponse,
 			return fmt.Errorf("Unable to update application %s: %w", args[0], err)
 		}
 
-		_, _ = color.New(color.FgGreen).Printf("Successfully updated application %s to use tag \"%s\"\n", args[0], appTag)
+		_, _ = color.New(color.FgGreen).Printf("Successfully updated application %s to use tag \"%s\" in revision %d\n", args[0], appTag, app.HelmRevisionNumber)
 		return nil
 	}
 }
@@ -28,6 +28,7 @@ import (
 	previewV2Beta1 "github.com/porter-dev/porter/cli/cmd/preview/v2beta1"
 	cliUtils "github.com/porter-dev/porter/cli/cmd/utils"
 	previewInt "github.com/porter-dev/porter/internal/integrations/preview"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
 	"github.com/porter-dev/porter/internal/templater/utils"
 	"github.com/porter-dev/switchboard/pkg/drivers"
 	switchboardModels "github.com/porter-dev/switchboard/pkg/models"
@@ -47,6 +48,14 @@ var (
 	pullImageBeforeBuild bool
 	predeploy            bool
 	exact                bool
+	// showBuildContext prints the files in the build context and their total size before they are uploaded
+	showBuildContext bool
+	// deployMessage is the release notes of the deploy. Defaults to the title of the head commit
+	deployMessage string
+	// applyWaitTimeout bounds how long --wait waits for the deploy of a porter app to be ready
+	applyWaitTimeout time.Duration
+	// applyStreamLogs prints the logs of the pods of a porter app, including its pre-deploy job, while it is deployed
+	applyStreamLogs bool
 )
 
 func registerCommand_Apply(cliConf config.CLIConfig) *cobra.Command {
@@ -116,6 +125,8 @@ applying a configuration:
 	applyCmd.PersistentFlags().StringVar(&imageTagOverride, "tag", "", "set the image tag used for the application (overrides field in yaml)")
 	applyCmd.PersistentFlags().BoolVar(&predeploy, "predeploy", false, "run predeploy job before deploying the application")
 	applyCmd.PersistentFlags().BoolVar(&exact, "exact", false, "apply the exact configuration as specified in the porter.yaml file (default is to merge with existing configuration)")
+	applyCmd.PersistentFlags().BoolVar(&showBuildContext, "show-context", false, "list the files included in the build context, and their total size, before they are uploaded")
+	applyCmd.PersistentFlags().StringVarP(&deployMessage, "message", "m", "", "release notes for the deploy, shown in the activity feed, notifications and pull request comment (defaults to the title of the head commit)")
 	applyCmd.PersistentFlags().BoolVarP(
 		&appWait,
 		"wait",
@@ -123,11 +134,28 @@ applying a configuration:
 		false,
 		"set this to wait and be notified when an apply is successful, otherwise time out",
 	)
+	applyCmd.PersistentFlags().DurationVar(&applyWaitTimeout, "wait-timeout", porter_app.DefaultWaitTimeout, "how long --wait waits for the deploy to be ready before it fails")
+	applyCmd.PersistentFlags().BoolVar(&applyStreamLogs, "stream-logs", false, "print the logs of the app and its pre-deploy job while it is deployed")
 	applyCmd.MarkFlagRequired("file")
 
 	return applyCmd
 }
 
+// deployMessageOrLastCommit returns the release notes set with --message, or the title of the head commit if the
+// flag is not set and the command is run from a git repository
+func deployMessageOrLastCommit() string {
+	if deployMessage != "" {
+		return deployMessage
+	}
+
+	commit, err := git.LastCommit()
+	if err != nil {
+		return ""
+	}
+
+	return commit.Title
+}
+
 func appNameFromEnvironmentVariable() string {
 	if os.Getenv("PORTER_APP_NAME") != "" {
 		return os.Getenv("PORTER_APP_NAME")
@@ -162,6 +190,7 @@ func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client ap
 			PullImageBeforeBuild:        pullImageBeforeBuild,
 			WithPredeploy:               predeploy,
 			Exact:                       exact,
+			ShowBuildContext:            showBuildContext,
 		}
 		err := v2.Apply(ctx, inp)
 		if err != nil {
@@ -215,6 +244,10 @@ func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client ap
 			return fmt.Errorf("error parsing porter.yaml: %w", err)
 		}
 	} else if previewVersion.Version == "v1stack" || previewVersion.Version == "" {
+		fileBytes, err = envvalues.ResolveFiles(fileBytes, filepath.Dir(porterYAML))
+		if err != nil {
+			return fmt.Errorf("error resolving env in porter.yaml: %w", err)
+		}
 
 		parsed, err := porter_app.ValidateAndMarshal(fileBytes)
 		if err != nil {
@@ -231,9 +264,11 @@ func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client ap
 			},
 		}
 
+		message := deployMessageOrLastCommit()
+
 		if parsed.Applications != nil {
 			for name, app := range parsed.Applications {
-				resources, err := porter_app.CreateApplicationDeploy(ctx, client, worker, app, name, cliConfig)
+				resources, err := porter_app.CreateApplicationDeploy(ctx, client, worker, app, name, message, appWait, applyWaitTimeout, applyStreamLogs, cliConfig)
 				if err != nil {
 					return fmt.Errorf("error parsing porter.yaml for build resources: %w", err)
 				}
@@ -269,7 +304,7 @@ func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client ap
 				return fmt.Errorf("error parsing porter.yaml for build resources: %w", err)
 			}
 
-			resources, err := porter_app.CreateApplicationDeploy(ctx, client, worker, app, appName, cliConfig)
+			resources, err := porter_app.CreateApplicationDeploy(ctx, client, worker, app, appName, message, appWait, applyWaitTimeout, applyStreamLogs, cliConfig)
 			if err != nil {
 				return fmt.Errorf("error parsing porter.yaml for build resources: %w", err)
 			}
@@ -417,6 +452,8 @@ type DeployDriver struct {
 	logger      *zerolog.Logger
 	cliConfig   config.CLIConfig
 	apiClient   api.Client
+	// ctx is the context of the apply command, since switchboard drivers are not passed one
+	ctx context.Context
 }
 
 // NewDeployDriver creates a deployment driver for use with switchboard
@@ -428,6 +465,7 @@ func NewDeployDriver(ctx context.Context, apiClient api.Client, cliConfig config
 			output:      make(map[string]interface{}),
 			cliConfig:   cliConfig,
 			apiClient:   apiClient,
+			ctx:         ctx,
 		}
 
 		target, err := preview.GetTarget(ctx, resource.Name, resource.Target, apiClient, cliConfig)
@@ -455,7 +493,7 @@ func (d *DeployDriver) ShouldApply(_ *switchboardModels.Resource) bool {
 
 // Apply extends switchboard
 func (d *DeployDriver) Apply(resource *switchboardModels.Resource) (*switchboardModels.Resource, error) {
-	ctx := context.TODO() // blocked from switchboard for now
+	ctx := d.ctx
 	_, err := d.apiClient.GetRelease(
 		ctx,
 		d.target.Project,
@@ -839,14 +877,14 @@ func (d *DeployDriver) updateApplication(ctx context.Context, resource *switchbo
 		// use the built image in the values if it is set
 		// if it contains a $, then the query did not resolve
 		if appConf.Build.Image != "" && !strings.Contains(appConf.Build.Image, "$") {
-			imageSpl := strings.Split(appConf.Build.Image, ":")
-			if len(imageSpl) == 2 {
-				appConf.Values["image"] = map[string]interface{}{
-					"repository": imageSpl[0],
-					"tag":        imageSpl[1],
-				}
-			} else {
-				return nil, fmt.Errorf("could not parse image info %s", appConf.Build.Image)
+			imageInfo, err := porter_app.ImageInfoFromImage(appConf.Build.Image)
+			if err != nil {
+				return nil, err
+			}
+
+			appConf.Values["image"] = map[string]interface{}{
+				"repository": imageInfo.Repository,
+				"tag":        imageInfo.Tag,
 			}
 		}
 	}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
@@ -16,6 +17,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	clusterReleasesStatus    []string
+	clusterReleasesChart     string
+	clusterReleasesNamespace string
+)
+
 func registerCommand_Cluster(cliConf config.CLIConfig) *cobra.Command {
 	clusterCmd := &cobra.Command{
 		Use:     "cluster",
@@ -67,6 +74,53 @@ func registerCommand_Cluster(cliConf config.CLIConfig) *cobra.Command {
 	}
 	clusterNamespaceCmd.AddCommand(clusterNamespaceListCmd)
 
+	clusterReleasesCmd := &cobra.Command{
+		Use:   "releases",
+		Short: "Lists the helm releases managed by Porter in a cluster",
+		Long: fmt.Sprintf(`
+%s
+
+Lists the latest revision of every helm release deployed or imported by Porter in the current cluster. Releases whose
+app no longer exists are marked as orphaned, and can be uninstalled. To list the failed releases of the web chart:
+
+  %s
+
+To list the releases stuck in a pending state:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("porter cluster releases"),
+			color.GreenString("porter cluster releases --status failed --chart web"),
+			color.GreenString("porter cluster releases --status pending"),
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, listClusterReleases)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+	clusterReleasesCmd.Flags().StringSliceVar(
+		&clusterReleasesStatus,
+		"status",
+		nil,
+		"only list releases with these statuses, where pending matches every pending status",
+	)
+	clusterReleasesCmd.Flags().StringVar(
+		&clusterReleasesChart,
+		"chart",
+		"",
+		"only list releases of the chart with this name",
+	)
+	clusterReleasesCmd.Flags().StringVarP(
+		&clusterReleasesNamespace,
+		"namespace",
+		"n",
+		"",
+		"only list releases in this namespace, defaults to every namespace",
+	)
+	clusterCmd.AddCommand(clusterReleasesCmd)
+
 	return clusterCmd
 }
 
@@ -159,3 +213,48 @@ func listNamespaces(ctx context.Context, user *types.GetAuthenticatedUserRespons
 
 	return nil
 }
+
+func listClusterReleases(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
+	resp, err := client.ListClusterReleases(ctx, cliConf.Project, cliConf.Cluster, &types.ListClusterReleasesRequest{
+		Status:    clusterReleasesStatus,
+		Chart:     clusterReleasesChart,
+		Namespace: clusterReleasesNamespace,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	if len(resp.Releases) == 0 {
+		_, _ = color.New(color.FgBlue).Println("No releases managed by Porter match the filters")
+		return nil
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 2, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", "NAME", "NAMESPACE", "CHART", "STATUS", "KIND", "APP", "LAST DEPLOYED")
+
+	for _, rel := range resp.Releases {
+		app := rel.App
+		if rel.Orphaned {
+			app = "(orphaned)"
+		}
+
+		lastDeployed := "-"
+		if rel.LastDeployed != nil {
+			lastDeployed = rel.LastDeployed.Format(time.RFC1123)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", rel.Name, rel.Namespace, fmt.Sprintf("%s-%s", rel.Chart, rel.ChartVersion), rel.Status, rel.Kind, app, lastDeployed)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(resp.MarkedForCleanup) > 0 {
+		_, _ = color.New(color.FgYellow).Printf("%d orphaned releases are marked for cleanup, since the app which deployed them no longer exists\n", len(resp.MarkedForCleanup))
+	}
+
+	return nil
+}
@@ -27,14 +27,10 @@ func checkLoginAndRunWithConfig(cmd *cobra.Command, cliConf config.CLIConfig, ar
 	cliConf = overrideConfigWithFlags(cmd, cliConf)
 	red := color.New(color.FgRed)
 
-	client, err := api.NewClientWithConfig(ctx, api.NewClientInput{
-		BaseURL:        fmt.Sprintf("%s/api", cliConf.Host),
-		BearerToken:    cliConf.Token,
-		CookieFileName: "cookie.json",
-	})
+	client, err := cliConf.GetAPIClient(ctx)
 	if err != nil {
 		red.Print("You are not logged in. Log in using \"porter auth login\"\n") // nolint:errcheck,gosec
-		return fmt.Errorf("error creating porter API client: %w", err)
+		return err
 	}
 
 	user, err := client.AuthCheck(ctx)
@@ -53,16 +49,24 @@ func checkLoginAndRunWithConfig(cmd *cobra.Command, cliConf config.CLIConfig, ar
 		return err
 	}
 
-	project, err := client.GetProject(ctx, cliConf.Project)
-	if err != nil {
-		return fmt.Errorf("could not retrieve project from Porter API. Please contact support@porter.run: %w", err)
-	}
-	if project == nil {
-		return fmt.Errorf("project [%d] not found", cliConf.Project)
+	if resolveOpts, ok := resolveOptionsForCommand(cmd); ok {
+		if err := cliConf.ResolveProjectAndCluster(ctx, &client, resolveOpts); err != nil {
+			red.Fprintf(os.Stderr, "Error: %v\n", err.Error()) // nolint:errcheck,gosec
+			return err
+		}
 	}
 
-	flags := config.FeatureFlags{
-		ValidateApplyV2Enabled: project.ValidateApplyV2,
+	var flags config.FeatureFlags
+	if cliConf.Project != 0 {
+		project, err := client.GetProject(ctx, cliConf.Project)
+		if err != nil {
+			return fmt.Errorf("could not retrieve project from Porter API. Please contact support@porter.run: %w", err)
+		}
+		if project == nil {
+			return fmt.Errorf("project [%d] not found", cliConf.Project)
+		}
+
+		flags.ValidateApplyV2Enabled = project.ValidateApplyV2
 	}
 
 	err = runner(ctx, user, client, cliConf, flags, cmd, args)
@@ -91,3 +95,24 @@ func checkLoginAndRunWithConfig(cmd *cobra.Command, cliConf config.CLIConfig, ar
 
 	return nil
 }
+
+// resolveOptionsForCommand returns how the project and cluster of a command are inferred when they are not configured,
+// or false if they are not inferred. The config commands pick the project and cluster themselves, and commands which
+// list or manage projects and clusters only use one when it is unambiguous.
+func resolveOptionsForCommand(cmd *cobra.Command) (config.ResolveOptions, bool) {
+	group := cmd
+	for group.HasParent() && group.Parent().HasParent() {
+		group = group.Parent()
+	}
+
+	switch group.Name() {
+	case "config":
+		return config.ResolveOptions{}, false
+	case "project":
+		return config.ResolveOptions{ProjectOptional: true, ClusterOptional: true}, true
+	case "cluster", "registry":
+		return config.ResolveOptions{ClusterOptional: true}, true
+	default:
+		return config.ResolveOptions{}, true
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
@@ -16,6 +17,24 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	projectListPage     int64
+	projectListPageSize int64
+	projectListSearch   string
+	projectListSort     string
+
+	projectRedeployCluster      uint
+	projectRedeployLabel        string
+	projectRedeployEnvGroup     string
+	projectRedeployConcurrency  int
+	projectRedeployBumpChecksum bool
+	projectRedeployDryRun       bool
+	projectRedeployWatch        bool
+)
+
+// projectRedeployWatchInterval is the time between checks of a bulk redeploy's progress when watching it
+const projectRedeployWatchInterval = 3 * time.Second
+
 func registerCommand_Project(cliConf config.CLIConfig) *cobra.Command {
 	projectCmd := &cobra.Command{
 		Use:     "project",
@@ -59,8 +78,67 @@ func registerCommand_Project(cliConf config.CLIConfig) *cobra.Command {
 			}
 		},
 	}
+	listProjectCmd.PersistentFlags().Int64Var(&projectListPage, "page", 1, "the page of projects to list")
+	listProjectCmd.PersistentFlags().Int64Var(&projectListPageSize, "page-size", 50, "the number of projects to list per page")
+	listProjectCmd.PersistentFlags().StringVar(&projectListSearch, "search", "", "only list projects whose name contains this value")
+	listProjectCmd.PersistentFlags().StringVar(
+		&projectListSort,
+		"sort",
+		string(types.ProjectListSortName),
+		fmt.Sprintf("the order to list projects in, one of %s, %s or %s", types.ProjectListSortName, types.ProjectListSortCreatedAt, types.ProjectListSortRecentlyActive),
+	)
 	projectCmd.AddCommand(listProjectCmd)
 
+	redeployProjectCmd := &cobra.Command{
+		Use:   "redeploy",
+		Short: "Redeploys every app in the project which matches the given filters",
+		Long: fmt.Sprintf(`%s
+
+Redeploys every app in the current project which matches all of the given filters, with the same values it was last
+deployed with. This is useful after changing an env group or a secret that many apps use. Apps are redeployed in the
+background, a few at a time on each cluster.
+
+  %s
+
+Use --dry-run to list the apps which would be redeployed without redeploying them.`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter project redeploy\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter project redeploy --env-group shared-db --concurrency 5 --watch"),
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, redeployProject)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+	redeployProjectCmd.PersistentFlags().UintVar(&projectRedeployCluster, "cluster", 0, "only redeploy apps on the cluster with this id")
+	redeployProjectCmd.PersistentFlags().StringVar(&projectRedeployLabel, "label", "", "only redeploy apps with a service whose labels match this selector, such as tier=backend")
+	redeployProjectCmd.PersistentFlags().StringVar(&projectRedeployEnvGroup, "env-group", "", "only redeploy apps which use this env group")
+	redeployProjectCmd.PersistentFlags().IntVar(
+		&projectRedeployConcurrency,
+		"concurrency",
+		types.DefaultBulkRedeployConcurrency,
+		fmt.Sprintf("the number of apps redeployed at once on each cluster, at most %d", types.MaxBulkRedeployConcurrency),
+	)
+	redeployProjectCmd.PersistentFlags().BoolVar(&projectRedeployBumpChecksum, "bump-checksum", false, "restart the pods of every app, even if its values did not change")
+	redeployProjectCmd.PersistentFlags().BoolVar(&projectRedeployDryRun, "dry-run", false, "list the apps which would be redeployed without redeploying them")
+	redeployProjectCmd.PersistentFlags().BoolVar(&projectRedeployWatch, "watch", false, "wait for every app to finish redeploying, printing their progress")
+	projectCmd.AddCommand(redeployProjectCmd)
+
+	redeployStatusCmd := &cobra.Command{
+		Use:   "redeploy-status [id]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Shows the progress of each app in a project redeploy",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, redeployProjectStatus)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+	redeployStatusCmd.PersistentFlags().BoolVar(&projectRedeployWatch, "watch", false, "wait for every app to finish redeploying, printing their progress")
+	projectCmd.AddCommand(redeployStatusCmd)
+
 	return projectCmd
 }
 
@@ -78,30 +156,46 @@ func createProject(ctx context.Context, _ *types.GetAuthenticatedUserResponse, c
 }
 
 func listProjects(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
-	resp, err := client.ListUserProjects(ctx)
+	resp, err := client.ListUserProjectsV2(ctx, &types.ListUserProjectsV2Request{
+		PaginationRequest: types.PaginationRequest{
+			Page: projectListPage,
+		},
+		PageSize: projectListPageSize,
+		Search:   projectListSearch,
+		SortBy:   types.ProjectListSort(projectListSort),
+	})
 	if err != nil {
 		return err
 	}
 
-	projects := *resp
-
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
 
-	fmt.Fprintf(w, "%s\t%s\n", "ID", "NAME")
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", "ID", "NAME", "ROLE", "APPS", "CLUSTERS", "LAST DEPLOY")
 
 	currProjectID := cliConf.Project
 
-	for _, project := range projects {
+	for _, project := range resp.Projects {
+		lastDeploy := "-"
+		if project.LastActivityAt != nil {
+			lastDeploy = project.LastActivityAt.Local().Format(time.RFC822)
+		}
+
+		row := fmt.Sprintf("%d\t%s\t%s\t%d\t%d\t%s", project.ID, project.Name, project.Role, project.NumApps, project.NumClusters, lastDeploy)
+
 		if currProjectID == project.ID {
-			color.New(color.FgGreen).Fprintf(w, "%d\t%s (current project)\n", project.ID, project.Name)
+			color.New(color.FgGreen).Fprintf(w, "%s (current project)\n", row)
 		} else {
-			fmt.Fprintf(w, "%d\t%s\n", project.ID, project.Name)
+			fmt.Fprintf(w, "%s\n", row)
 		}
 	}
 
 	w.Flush()
 
+	if resp.Pagination.NumPages > 1 {
+		fmt.Printf("Page %d of %d\n", resp.Pagination.CurrentPage, resp.Pagination.NumPages)
+	}
+
 	return nil
 }
 
@@ -149,3 +243,149 @@ func setProjectCluster(ctx context.Context, client api.Client, cliConf config.CL
 
 	return nil
 }
+
+func redeployProject(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
+	if projectRedeployConcurrency < 1 || projectRedeployConcurrency > types.MaxBulkRedeployConcurrency {
+		return fmt.Errorf("--concurrency must be between 1 and %d", types.MaxBulkRedeployConcurrency)
+	}
+
+	resp, err := client.CreateBulkRedeploy(ctx, cliConf.Project, &types.CreateBulkRedeployRequest{
+		BulkRedeployFilter: types.BulkRedeployFilter{
+			ClusterID: projectRedeployCluster,
+			Label:     projectRedeployLabel,
+			EnvGroup:  projectRedeployEnvGroup,
+		},
+		Concurrency:  projectRedeployConcurrency,
+		BumpChecksum: projectRedeployBumpChecksum,
+		DryRun:       projectRedeployDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.DryRun {
+		printBulkRedeployApps(resp)
+		fmt.Printf("%d apps would be redeployed\n", resp.Counts[types.BulkRedeployStatus_Pending])
+		return nil
+	}
+
+	color.New(color.FgGreen).Printf("Started redeploy %s of %d apps\n", resp.ID, resp.Counts[types.BulkRedeployStatus_Pending])
+
+	if !projectRedeployWatch {
+		printBulkRedeployApps(resp)
+		fmt.Printf("Check its progress with: porter project redeploy-status %s --watch\n", resp.ID)
+		return nil
+	}
+
+	return watchBulkRedeploy(ctx, client, cliConf.Project, resp)
+}
+
+func redeployProjectStatus(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	resp, err := client.GetBulkRedeploy(ctx, cliConf.Project, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !projectRedeployWatch {
+		printBulkRedeployApps(resp)
+		printBulkRedeploySummary(resp)
+		return nil
+	}
+
+	return watchBulkRedeploy(ctx, client, cliConf.Project, resp)
+}
+
+// watchBulkRedeploy prints each app of a bulk redeploy as its status changes, until every app has finished. An error is
+// returned if any app failed, so that scripts can check the exit code.
+func watchBulkRedeploy(ctx context.Context, client api.Client, projectID uint, bulkRedeploy *types.BulkRedeploy) error {
+	printed := make(map[uint]types.BulkRedeployStatus)
+
+	for {
+		for _, app := range bulkRedeploy.Apps {
+			if printed[app.PorterAppID] == app.Status {
+				continue
+			}
+			printed[app.PorterAppID] = app.Status
+
+			printBulkRedeployApp(app)
+		}
+
+		if bulkRedeploy.Status == types.BulkRedeployStatus_Completed {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(projectRedeployWatchInterval):
+		}
+
+		resp, err := client.GetBulkRedeploy(ctx, projectID, bulkRedeploy.ID)
+		if err != nil {
+			return err
+		}
+		bulkRedeploy = resp
+	}
+
+	printBulkRedeploySummary(bulkRedeploy)
+
+	if failed := bulkRedeploy.Counts[types.BulkRedeployStatus_Failed]; failed > 0 {
+		return fmt.Errorf("%d apps failed to redeploy", failed)
+	}
+
+	return nil
+}
+
+func printBulkRedeployApp(app types.BulkRedeployApp) {
+	line := fmt.Sprintf("%s (cluster %d): %s", app.AppName, app.ClusterID, app.Status)
+	if app.Revision != 0 {
+		line = fmt.Sprintf("%s, revision %d", line, app.Revision)
+	}
+	if app.Error != "" {
+		line = fmt.Sprintf("%s: %s", line, app.Error)
+	}
+
+	switch app.Status {
+	case types.BulkRedeployStatus_Succeeded:
+		color.New(color.FgGreen).Println(line)
+	case types.BulkRedeployStatus_Failed:
+		color.New(color.FgRed).Println(line)
+	case types.BulkRedeployStatus_Skipped:
+		color.New(color.FgYellow).Println(line)
+	default:
+		fmt.Println(line)
+	}
+}
+
+func printBulkRedeployApps(bulkRedeploy *types.BulkRedeploy) {
+	if len(bulkRedeploy.Apps) == 0 {
+		fmt.Println("No apps match the given filters")
+		return
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "APP", "CLUSTER", "STATUS", "DETAILS")
+	for _, app := range bulkRedeploy.Apps {
+		details := app.Error
+		if app.Revision != 0 {
+			details = fmt.Sprintf("revision %d", app.Revision)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", app.AppName, app.ClusterID, app.Status, details)
+	}
+
+	w.Flush()
+}
+
+func printBulkRedeploySummary(bulkRedeploy *types.BulkRedeploy) {
+	fmt.Printf(
+		"Redeploy %s is %s: %d succeeded, %d failed, %d skipped, %d left\n",
+		bulkRedeploy.ID,
+		bulkRedeploy.Status,
+		bulkRedeploy.Counts[types.BulkRedeployStatus_Succeeded],
+		bulkRedeploy.Counts[types.BulkRedeployStatus_Failed],
+		bulkRedeploy.Counts[types.BulkRedeployStatus_Skipped],
+		bulkRedeploy.Counts[types.BulkRedeployStatus_Pending]+bulkRedeploy.Counts[types.BulkRedeployStatus_Running],
+	)
+}
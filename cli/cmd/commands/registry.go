@@ -46,6 +46,18 @@ func registerCommand_Registry(cliConf config.CLIConfig) *cobra.Command {
 		},
 	}
 
+	registryTestCmd := &cobra.Command{
+		Use:   "test [id]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Tests the connection to the registry with the given id, or the current registry",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, testRegistry)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
 	registryReposCmd := &cobra.Command{
 		Use:     "repo",
 		Aliases: []string{"repos", "repository", "repositories"},
@@ -86,6 +98,7 @@ func registerCommand_Registry(cliConf config.CLIConfig) *cobra.Command {
 	registryCmd.AddCommand(registryReposCmd)
 	registryCmd.AddCommand(registryListCmd)
 	registryCmd.AddCommand(registryDeleteCmd)
+	registryCmd.AddCommand(registryTestCmd)
 
 	registryReposCmd.AddCommand(registryReposListCmd)
 
@@ -159,6 +172,32 @@ func deleteRegistry(ctx context.Context, user *types.GetAuthenticatedUserRespons
 	return nil
 }
 
+func testRegistry(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	id := uint64(cliConf.Registry)
+	if len(args) == 1 {
+		var err error
+		id, err = strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+	if id == 0 {
+		return fmt.Errorf("no registry id given and no current registry is set")
+	}
+
+	resp, err := client.TestRegistryConnection(ctx, cliConf.Project, uint(id))
+	if err != nil {
+		return err
+	}
+
+	if resp.Success {
+		color.New(color.FgGreen).Printf("Connected to registry %d\n", id)
+		return nil
+	}
+
+	return fmt.Errorf("could not connect to registry %d (%s error): %s", id, resp.ErrorKind, resp.Error)
+}
+
 func listRepos(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
 	pID := cliConf.Project
 	rID := cliConf.Registry
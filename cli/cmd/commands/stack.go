@@ -4,17 +4,33 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/porter-dev/porter/cli/cmd/config"
+	"github.com/porter-dev/porter/cli/cmd/utils"
 	v2 "github.com/porter-dev/porter/cli/cmd/v2"
 
 	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
 	"github.com/porter-dev/porter/api/types"
+	appInternal "github.com/porter-dev/porter/internal/porter_app"
 	"github.com/spf13/cobra"
 )
 
-var linkedApps []string
+var (
+	linkedApps      []string
+	stackRunCommand string
+	stackRunDetach  bool
+
+	stackDeleteYes       bool
+	stackDeletePlanHash  string
+	stackDeleteNamespace bool
+	stackDeleteVolumes   bool
+)
+
+// stackRunPollInterval is how often the status of a stack run job is checked while waiting for it to finish
+const stackRunPollInterval = 2 * time.Second
 
 func registerCommand_Stack(cliConf config.CLIConfig) *cobra.Command {
 	stackCmd := &cobra.Command{
@@ -56,7 +72,139 @@ func registerCommand_Stack(cliConf config.CLIConfig) *cobra.Command {
 		},
 	}
 
+	stackRunCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Runs a one-off command as a job against a stack's image and environment",
+		Long: fmt.Sprintf(`
+%s
+
+Launches a job which runs the given command using the image, env and resources of the stack, then
+waits for it to finish and prints its logs. If the job fails, this command exits with exit code 1.
+Finished jobs are cleaned up automatically after an hour.
+
+Example commands:
+
+  %s
+
+To launch the job without waiting for it, use the --detach flag. The status and logs of the job can
+then be read with the run ID:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter stack run\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter stack run --name my-stack --command \"rake backfill\""),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter stack run-status [run-id] --name my-stack"),
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, stackRun)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	stackRunCmd.Flags().StringVar(
+		&stackRunCommand,
+		"command",
+		"",
+		"the command to run in the job",
+	)
+
+	stackRunCmd.Flags().BoolVar(
+		&stackRunDetach,
+		"detach",
+		false,
+		"print the run ID and exit without waiting for the job to finish",
+	)
+
+	stackRunStatusCmd := &cobra.Command{
+		Use:   "run-status [run-id]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Prints the status and logs of a job launched by \"porter stack run\"",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, stackRunStatus)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	stackDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Deletes a stack after showing what is deleted and what is left behind",
+		Long: fmt.Sprintf(`
+%s
+
+Prints the plan of the deletion: the releases, volumes, namespace, domains and events which are
+deleted, and the resources which are left behind, such as volumes which are kept and cloud load
+balancers which are released asynchronously. The stack is deleted once the plan is confirmed.
+
+Example commands:
+
+  %s
+
+To delete without a prompt, pass --yes along with the hash of the plan to delete. The deletion
+is refused if the plan has changed since:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter stack delete\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter stack delete --name my-stack --delete-volumes"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter stack delete --name my-stack --yes --plan-hash [hash]"),
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, stackDelete)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	stackDeleteCmd.Flags().BoolVar(
+		&stackDeleteYes,
+		"yes",
+		false,
+		"delete without a prompt, as described by the plan with the hash given by --plan-hash",
+	)
+
+	stackDeleteCmd.Flags().StringVar(
+		&stackDeletePlanHash,
+		"plan-hash",
+		"",
+		"the hash of the plan to delete, required by --yes",
+	)
+
+	stackDeleteCmd.Flags().BoolVar(
+		&stackDeleteNamespace,
+		"delete-namespace",
+		false,
+		"delete the namespace of the stack along with anything left in it",
+	)
+
+	stackDeleteCmd.Flags().BoolVar(
+		&stackDeleteVolumes,
+		"delete-volumes",
+		false,
+		"delete the persistent volume claims of the stack, which are otherwise kept",
+	)
+
+	stackCleanupStatusCmd := &cobra.Command{
+		Use:   "cleanup-status [cleanup-id]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Prints the load balancers and domains of a deleted stack which have not been released yet",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, stackCleanupStatus)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
 	stackCmd.AddCommand(stackEnvGroupCmd)
+	stackCmd.AddCommand(stackRunCmd)
+	stackCmd.AddCommand(stackRunStatusCmd)
+	stackCmd.AddCommand(stackDeleteCmd)
+	stackCmd.AddCommand(stackCleanupStatusCmd)
 
 	stackCmd.PersistentFlags().StringVar(
 		&name,
@@ -226,3 +374,176 @@ func stackRemoveEnvGroup(ctx context.Context, _ *types.GetAuthenticatedUserRespo
 
 	return nil
 }
+
+func stackRun(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("empty stack name")
+	} else if len(stackRunCommand) == 0 {
+		return fmt.Errorf("empty command")
+	}
+
+	run, err := client.RunStackJob(ctx, cliConf.Project, cliConf.Cluster, name, stackRunCommand)
+	if err != nil {
+		return err
+	}
+
+	if stackRunDetach {
+		color.New(color.FgGreen).Printf("launched job %s with run ID %s\n", run.JobName, run.RunID)
+		return nil
+	}
+
+	color.New(color.FgGreen).Printf("launched job %s, waiting for it to finish...\n", run.JobName)
+
+	for {
+		status, err := client.GetStackRunJob(ctx, cliConf.Project, cliConf.Cluster, name, run.RunID)
+		if err != nil {
+			return err
+		}
+
+		if status.Status != appInternal.JobRunStatus_Running {
+			return printStackRunJob(status)
+		}
+
+		time.Sleep(stackRunPollInterval)
+	}
+}
+
+func stackRunStatus(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("empty stack name")
+	}
+
+	status, err := client.GetStackRunJob(ctx, cliConf.Project, cliConf.Cluster, name, args[0])
+	if err != nil {
+		return err
+	}
+
+	return printStackRunJob(status)
+}
+
+// printStackRunJob prints the logs and status of a stack run job, returning an error if the job failed
+func printStackRunJob(run *appInternal.RunJob) error {
+	for _, line := range run.Logs {
+		fmt.Println(line)
+	}
+
+	switch run.Status {
+	case appInternal.JobRunStatus_Successful:
+		color.New(color.FgGreen).Printf("job %s finished successfully\n", run.Name)
+	case appInternal.JobRunStatus_Failed:
+		return fmt.Errorf("job %s failed", run.Name)
+	default:
+		color.New(color.FgYellow).Printf("job %s is still running\n", run.Name)
+	}
+
+	return nil
+}
+
+func stackDelete(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("empty stack name")
+	}
+	if stackDeleteYes && stackDeletePlanHash == "" {
+		return fmt.Errorf("--yes requires the hash of the plan to delete, passed with --plan-hash")
+	}
+
+	opts := types.DeletePorterAppPlanRequest{
+		DeleteNamespace: stackDeleteNamespace,
+		DeleteVolumes:   stackDeleteVolumes,
+	}
+
+	plan, err := client.GetStackDeletePlan(ctx, cliConf.Project, cliConf.Cluster, name, &opts)
+	if err != nil {
+		return err
+	}
+
+	printStackDeletePlan(plan)
+
+	if stackDeleteYes {
+		if stackDeletePlanHash != plan.PlanHash {
+			return fmt.Errorf("the plan has changed since it was read, its hash is now %s: review it and pass the new hash to delete", plan.PlanHash)
+		}
+	} else {
+		proceed, err := utils.PromptConfirm(fmt.Sprintf("Delete stack %s as planned?", name), false)
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			return nil
+		}
+	}
+
+	res, err := client.DeleteStack(ctx, cliConf.Project, cliConf.Cluster, name, &types.DeletePorterAppRequest{
+		DeletePorterAppPlanRequest: opts,
+		PlanHash:                   plan.PlanHash,
+	})
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("deleted stack %s\n", name)
+
+	if res.CleanupID != 0 {
+		fmt.Printf("load balancers and domains of the stack are released asynchronously, check them with:\n\n  porter stack cleanup-status %d\n", res.CleanupID)
+	}
+
+	return nil
+}
+
+// printStackDeletePlan prints what a deletion removes and what it leaves behind
+func printStackDeletePlan(plan *types.DeletePorterAppPlan) {
+	fmt.Println("The following will be deleted:")
+
+	for _, release := range plan.Releases {
+		fmt.Printf("  release %s\n", release)
+	}
+	for _, claim := range plan.PersistentVolumeClaims {
+		fmt.Printf("  persistent volume claim %s\n", claim)
+	}
+	if plan.Namespace != "" {
+		fmt.Printf("  namespace %s\n", plan.Namespace)
+	}
+	for _, hostname := range plan.DNSRecords {
+		fmt.Printf("  domain %s\n", hostname)
+	}
+	fmt.Printf("  %d events\n", plan.Events)
+
+	if len(plan.Orphaned) != 0 {
+		fmt.Println("\nThe following will be left behind:")
+
+		for _, orphaned := range plan.Orphaned {
+			color.New(color.FgYellow).Printf("  %s %s", orphaned.Kind, orphaned.Name)
+			fmt.Printf(": %s\n", orphaned.Reason)
+		}
+	}
+
+	fmt.Printf("\nPlan hash: %s\n\n", plan.PlanHash)
+}
+
+func stackCleanupStatus(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	cleanupID, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid cleanup id %s: %w", args[0], err)
+	}
+
+	cleanup, err := client.GetStackCleanup(ctx, cliConf.Project, cliConf.Cluster, uint(cleanupID))
+	if err != nil {
+		return err
+	}
+
+	switch cleanup.Status {
+	case types.PorterAppCleanupStatus_Succeeded:
+		color.New(color.FgGreen).Printf("every load balancer and domain of stack %s was released\n", cleanup.AppName)
+		return nil
+	case types.PorterAppCleanupStatus_Leaked:
+		color.New(color.FgRed).Printf("resources of stack %s were not released by %s:\n", cleanup.AppName, cleanup.Deadline.Format(time.RFC3339))
+	default:
+		fmt.Printf("resources of stack %s which have not been released yet, by %s:\n", cleanup.AppName, cleanup.Deadline.Format(time.RFC3339))
+	}
+
+	for _, resource := range cleanup.Pending {
+		fmt.Printf("  %s %s\n", resource.Kind, resource.Name)
+	}
+
+	return nil
+}
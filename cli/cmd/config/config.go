@@ -34,6 +34,10 @@ type CLIConfig struct {
 	Registry   uint   `yaml:"registry"`
 	HelmRepo   uint   `yaml:"helm_repo"`
 	Kubeconfig string `yaml:"kubeconfig"`
+
+	// RecordSession is the path of a file which the requests of a command, and their responses, are recorded to. It
+	// is only set by the --record-session flag, and is never saved to the config file.
+	RecordSession string `yaml:"-" mapstructure:"-"`
 }
 
 // FeatureFlags are any flags that are relevant to the feature set of the CLI. This should not include all feature flags, only those relevant to client-side CLI operations
@@ -338,12 +342,19 @@ func (c *CLIConfig) SetKubeconfig(kubeconfig string) error {
 	return nil
 }
 
-// ValidateCLIEnvironment checks that all required variables are present for running the CLI
-func (c *CLIConfig) ValidateCLIEnvironment() error {
+// ValidateCLIEnvironment checks that all required variables are present for running the CLI. A project or cluster
+// which is not configured is inferred with client, as ResolveProjectAndCluster does.
+func (c *CLIConfig) ValidateCLIEnvironment(ctx context.Context, client ProjectClusterLister) error {
 	if c.Token == "" {
 		return fmt.Errorf("no auth token present, please run 'porter auth login' to authenticate")
 	}
 
+	if (c.Project == 0 || c.Cluster == 0) && client != nil {
+		if err := c.ResolveProjectAndCluster(ctx, client, ResolveOptions{}); err != nil {
+			return err
+		}
+	}
+
 	if c.Project == 0 {
 		return fmt.Errorf("no project selected, please run 'porter config set-project' to select a project")
 	}
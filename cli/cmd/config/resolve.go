@@ -0,0 +1,212 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/utils"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// ProjectClusterLister is the part of the Porter API client which is used to infer the project and cluster of a
+// command
+type ProjectClusterLister interface {
+	ListUserProjects(ctx context.Context) (*types.ListUserProjectsResponse, error)
+	GetProject(ctx context.Context, projectID uint) (*types.ReadProjectResponse, error)
+	ListProjectClusters(ctx context.Context, projectID uint) (*types.ListClusterResponse, error)
+}
+
+// ResolveOptions controls how the project and cluster of a command are inferred
+type ResolveOptions struct {
+	// ProjectOptional leaves the project unset, rather than prompting or failing, when it cannot be inferred. It is set
+	// for commands which do not act on a project, such as listing projects.
+	ProjectOptional bool
+	// ClusterOptional leaves the cluster unset, rather than prompting or failing, when it cannot be inferred. It is
+	// set for commands which do not act on a cluster, such as listing clusters.
+	ClusterOptional bool
+}
+
+var (
+	// isInteractive reports whether the user can be prompted to pick a project or cluster
+	isInteractive = func() bool {
+		return terminal.IsTerminal(int(os.Stdin.Fd())) && terminal.IsTerminal(int(os.Stdout.Fd()))
+	}
+	// promptSelect prompts the user to pick one of the options
+	promptSelect = utils.PromptSelect
+	// noteWriter is where the project and cluster which were inferred are reported. It is stderr, so that the output
+	// of commands can still be piped.
+	noteWriter io.Writer = os.Stderr
+)
+
+// GetAPIClient returns a client for the Porter API of the configured host, authenticated with the configured token
+func (c *CLIConfig) GetAPIClient(ctx context.Context) (api.Client, error) {
+	client, err := api.NewClientWithConfig(ctx, api.NewClientInput{
+		BaseURL:        fmt.Sprintf("%s/api", c.Host),
+		BearerToken:    c.Token,
+		CookieFileName: "cookie.json",
+	})
+	if err != nil {
+		return client, fmt.Errorf("error creating porter API client: %w", err)
+	}
+
+	if err := client.RecordSession(c.RecordSession); err != nil {
+		return client, err
+	}
+
+	return client, nil
+}
+
+// ResolveProjectAndCluster sets the project and cluster when they are not configured. The project is the only one the
+// user belongs to; the cluster is the default cluster of the project, or its only cluster. When there is more than one
+// to choose from, the user is prompted to pick one, or, if the CLI is not run in a terminal, an error lists them.
+// Inferred values are only used for the current command, and are not saved to the config.
+func (c *CLIConfig) ResolveProjectAndCluster(ctx context.Context, client ProjectClusterLister, opts ResolveOptions) error {
+	if c.Project == 0 {
+		projectID, err := resolveProject(ctx, client, opts)
+		if err != nil {
+			return err
+		}
+		c.Project = projectID
+	}
+
+	if c.Project != 0 && c.Cluster == 0 {
+		clusterID, err := resolveCluster(ctx, client, c.Project, opts)
+		if err != nil {
+			return err
+		}
+		c.Cluster = clusterID
+	}
+
+	return nil
+}
+
+func resolveProject(ctx context.Context, client ProjectClusterLister, opts ResolveOptions) (uint, error) {
+	resp, err := client.ListUserProjects(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("no project selected, and the projects you belong to could not be listed: %w", err)
+	}
+	projects := *resp
+
+	options := make([]option, 0, len(projects))
+	for _, project := range projects {
+		options = append(options, option{id: project.ID, name: project.Name})
+	}
+
+	switch len(options) {
+	case 0:
+		if opts.ProjectOptional {
+			return 0, nil
+		}
+		return 0, errors.New("you do not belong to any project, please create one in the Porter dashboard")
+	case 1:
+		note("Using project %s, the only project you belong to\n", options[0])
+		return options[0].id, nil
+	}
+
+	if opts.ProjectOptional {
+		return 0, nil
+	}
+
+	if !isInteractive() {
+		return 0, fmt.Errorf("no project selected, please run 'porter config set-project' or pass --project with one of the projects you belong to:\n%s", listOptions(options))
+	}
+
+	return pick("Select a project:", options)
+}
+
+func resolveCluster(ctx context.Context, client ProjectClusterLister, projectID uint, opts ResolveOptions) (uint, error) {
+	resp, err := client.ListProjectClusters(ctx, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("no cluster selected, and the clusters of project %d could not be listed: %w", projectID, err)
+	}
+	clusters := *resp
+
+	options := make([]option, 0, len(clusters))
+	for _, cluster := range clusters {
+		options = append(options, option{id: cluster.ID, name: cluster.Name})
+	}
+
+	project, err := client.GetProject(ctx, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("no cluster selected, and project %d could not be read: %w", projectID, err)
+	}
+	if project != nil && project.DefaultClusterID != 0 {
+		for _, opt := range options {
+			if opt.id == project.DefaultClusterID {
+				note("Using cluster %s, the default cluster of the project\n", opt)
+				return opt.id, nil
+			}
+		}
+	}
+
+	switch len(options) {
+	case 0:
+		if opts.ClusterOptional {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("project %d has no clusters, please connect one in the Porter dashboard", projectID)
+	case 1:
+		note("Using cluster %s, the only cluster of the project\n", options[0])
+		return options[0].id, nil
+	}
+
+	if opts.ClusterOptional {
+		return 0, nil
+	}
+
+	if !isInteractive() {
+		return 0, fmt.Errorf("no cluster selected, please run 'porter config set-cluster' or pass --cluster with one of the clusters of project %d:\n%s", projectID, listOptions(options))
+	}
+
+	return pick("Select a cluster:", options)
+}
+
+// option is a project or cluster the user can pick
+type option struct {
+	id   uint
+	name string
+}
+
+func (o option) String() string {
+	return fmt.Sprintf("%s (ID %d)", o.name, o.id)
+}
+
+func listOptions(options []option) string {
+	lines := make([]string, 0, len(options))
+	for _, opt := range options {
+		lines = append(lines, fmt.Sprintf("  %d\t%s", opt.id, opt.name))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func pick(prompt string, options []option) (uint, error) {
+	labels := make([]string, 0, len(options))
+	for _, opt := range options {
+		labels = append(labels, opt.String())
+	}
+
+	selected, err := promptSelect(prompt, labels)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, label := range labels {
+		if label == selected {
+			return options[i].id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown selection %s", selected)
+}
+
+func note(format string, opt option) {
+	_, _ = color.New(color.FgYellow).Fprintf(noteWriter, format, opt)
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+type fakeLister struct {
+	projects         []*types.ProjectList
+	clusters         map[uint][]*types.Cluster
+	defaultClusterID uint
+}
+
+func (f fakeLister) ListUserProjects(context.Context) (*types.ListUserProjectsResponse, error) {
+	resp := types.ListUserProjectsResponse(f.projects)
+	return &resp, nil
+}
+
+func (f fakeLister) GetProject(_ context.Context, projectID uint) (*types.ReadProjectResponse, error) {
+	return &types.ReadProjectResponse{ID: projectID, DefaultClusterID: f.defaultClusterID}, nil
+}
+
+func (f fakeLister) ListProjectClusters(_ context.Context, projectID uint) (*types.ListClusterResponse, error) {
+	resp := types.ListClusterResponse(f.clusters[projectID])
+	return &resp, nil
+}
+
+func TestResolveProjectAndCluster(t *testing.T) {
+	storefront := &types.ProjectList{ID: 3, Name: "storefront"}
+	staging := &types.ProjectList{ID: 7, Name: "staging"}
+	production := &types.Cluster{ID: 11, Name: "production"}
+	preview := &types.Cluster{ID: 12, Name: "preview"}
+
+	tests := []struct {
+		name        string
+		config      CLIConfig
+		lister      fakeLister
+		opts        ResolveOptions
+		interactive bool
+		selection   string
+
+		wantProject uint
+		wantCluster uint
+		wantNote    string
+		wantErr     string
+	}{
+		{
+			name:        "configured project and cluster are kept",
+			config:      CLIConfig{Project: 7, Cluster: 12},
+			lister:      fakeLister{projects: []*types.ProjectList{storefront}},
+			wantProject: 7,
+			wantCluster: 12,
+		},
+		{
+			name: "only project and only cluster",
+			lister: fakeLister{
+				projects: []*types.ProjectList{storefront},
+				clusters: map[uint][]*types.Cluster{3: {production}},
+			},
+			wantProject: 3,
+			wantCluster: 11,
+			wantNote:    "Using project storefront (ID 3), the only project you belong to\nUsing cluster production (ID 11), the only cluster of the project\n",
+		},
+		{
+			name:   "default cluster of the project",
+			config: CLIConfig{Project: 3},
+			lister: fakeLister{
+				clusters:         map[uint][]*types.Cluster{3: {production, preview}},
+				defaultClusterID: 12,
+			},
+			wantProject: 3,
+			wantCluster: 12,
+			wantNote:    "Using cluster preview (ID 12), the default cluster of the project\n",
+		},
+		{
+			name:   "default cluster which no longer exists",
+			config: CLIConfig{Project: 3},
+			lister: fakeLister{
+				clusters:         map[uint][]*types.Cluster{3: {production}},
+				defaultClusterID: 12,
+			},
+			wantProject: 3,
+			wantCluster: 11,
+			wantNote:    "Using cluster production (ID 11), the only cluster of the project\n",
+		},
+		{
+			name:    "no projects",
+			lister:  fakeLister{},
+			wantErr: "you do not belong to any project",
+		},
+		{
+			name:    "several projects without a terminal",
+			lister:  fakeLister{projects: []*types.ProjectList{storefront, staging}},
+			wantErr: "no project selected, please run 'porter config set-project' or pass --project with one of the projects you belong to:\n  3\tstorefront\n  7\tstaging",
+		},
+		{
+			name: "several projects in a terminal",
+			lister: fakeLister{
+				projects: []*types.ProjectList{storefront, staging},
+				clusters: map[uint][]*types.Cluster{7: {preview}},
+			},
+			interactive: true,
+			selection:   "staging (ID 7)",
+			wantProject: 7,
+			wantCluster: 12,
+			wantNote:    "Using cluster preview (ID 12), the only cluster of the project\n",
+		},
+		{
+			name:    "project without clusters",
+			config:  CLIConfig{Project: 3},
+			lister:  fakeLister{},
+			wantErr: "project 3 has no clusters",
+		},
+		{
+			name:    "several clusters without a terminal",
+			config:  CLIConfig{Project: 3},
+			lister:  fakeLister{clusters: map[uint][]*types.Cluster{3: {production, preview}}},
+			wantErr: "no cluster selected, please run 'porter config set-cluster' or pass --cluster with one of the clusters of project 3:\n  11\tproduction\n  12\tpreview",
+		},
+		{
+			name:        "several clusters in a terminal",
+			config:      CLIConfig{Project: 3},
+			lister:      fakeLister{clusters: map[uint][]*types.Cluster{3: {production, preview}}},
+			interactive: true,
+			selection:   "production (ID 11)",
+			wantProject: 3,
+			wantCluster: 11,
+		},
+		{
+			name:        "optional cluster is left unset when ambiguous",
+			config:      CLIConfig{Project: 3},
+			lister:      fakeLister{clusters: map[uint][]*types.Cluster{3: {production, preview}}},
+			opts:        ResolveOptions{ClusterOptional: true},
+			interactive: true,
+			wantProject: 3,
+		},
+		{
+			name:        "optional project is left unset when ambiguous",
+			lister:      fakeLister{projects: []*types.ProjectList{storefront, staging}},
+			opts:        ResolveOptions{ProjectOptional: true, ClusterOptional: true},
+			interactive: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var notes bytes.Buffer
+			noteWriter = &notes
+			isInteractive = func() bool { return tt.interactive }
+			promptSelect = func(_ string, options []string) (string, error) {
+				if tt.selection == "" {
+					return "", errors.New("unexpected prompt")
+				}
+				return tt.selection, nil
+			}
+
+			conf := tt.config
+			err := conf.ResolveProjectAndCluster(context.Background(), tt.lister, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if conf.Project != tt.wantProject || conf.Cluster != tt.wantCluster {
+				t.Errorf("expected project %d and cluster %d, got project %d and cluster %d", tt.wantProject, tt.wantCluster, conf.Project, conf.Cluster)
+			}
+			if notes.String() != tt.wantNote {
+				t.Errorf("expected notes %q, got %q", tt.wantNote, notes.String())
+			}
+		})
+	}
+}
+
+func TestValidateCLIEnvironment(t *testing.T) {
+	isInteractive = func() bool { return false }
+	noteWriter = &bytes.Buffer{}
+
+	conf := CLIConfig{}
+	if err := conf.ValidateCLIEnvironment(context.Background(), fakeLister{}); err == nil || !strings.Contains(err.Error(), "no auth token present") {
+		t.Fatalf("expected an error about the auth token, got %v", err)
+	}
+
+	conf = CLIConfig{Token: "token"}
+	lister := fakeLister{
+		projects: []*types.ProjectList{{ID: 3, Name: "storefront"}},
+		clusters: map[uint][]*types.Cluster{3: {{ID: 11, Name: "production"}}},
+	}
+	if err := conf.ValidateCLIEnvironment(context.Background(), lister); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conf.Project != 3 || conf.Cluster != 11 {
+		t.Errorf("expected the project and cluster to be inferred, got project %d and cluster %d", conf.Project, conf.Cluster)
+	}
+
+	conf = CLIConfig{Token: "token", Project: 3}
+	if err := conf.ValidateCLIEnvironment(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "no cluster selected") {
+		t.Fatalf("expected an error about the cluster, got %v", err)
+	}
+}
@@ -17,12 +17,12 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/archive"
-	"github.com/docker/docker/pkg/fileutils"
-	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
 	"github.com/moby/moby/pkg/jsonmessage"
 	"github.com/moby/moby/pkg/stringid"
 	"github.com/moby/term"
 	"mvdan.cc/sh/v3/shell"
+
+	"github.com/porter-dev/porter/internal/ignore"
 )
 
 type BuildOpts struct {
@@ -36,6 +36,10 @@ type BuildOpts struct {
 
 	Env map[string]string
 
+	// Context is the listing of the build context from ListBuildContext. If it is nil, BuildLocal lists the context
+	// itself before creating the tarball.
+	Context *ignore.BuildContext
+
 	LogFile *os.File
 }
 
@@ -56,21 +60,16 @@ func (a *Agent) BuildLocal(ctx context.Context, opts *BuildOpts) (err error) {
 
 	dockerfilePath := opts.DockerfilePath
 
-	// attempt to read dockerignore file and paths
-	dockerIgnoreBytes, _ := os.ReadFile(".dockerignore")
-	var excludes []string
-
-	if len(dockerIgnoreBytes) != 0 {
-		excludes, err = dockerignore.ReadAll(bytes.NewBuffer(dockerIgnoreBytes))
+	buildContext := opts.Context
+	if buildContext == nil {
+		buildContext, err = ListBuildContext(*opts)
 		if err != nil {
-			return fmt.Errorf("error reading .dockerignore: %w", err)
+			return err
 		}
 	}
 
-	excludes = trimBuildFilesFromExcludes(excludes, dockerfilePath)
-
 	tar, err := archive.TarWithOptions(opts.BuildContext, &archive.TarOptions{
-		ExcludePatterns: excludes,
+		ExcludePatterns: buildContext.Excludes,
 	})
 	if err != nil {
 		return fmt.Errorf("error creating tar: %w", err)
@@ -130,14 +129,27 @@ func (a *Agent) BuildLocal(ctx context.Context, opts *BuildOpts) (err error) {
 	return jsonmessage.DisplayJSONMessagesStream(out.Body, writer, termFd, isTerm, nil)
 }
 
-func trimBuildFilesFromExcludes(excludes []string, dockerfile string) []string {
-	if keep, _ := fileutils.Matches(".dockerignore", excludes); keep {
-		excludes = append(excludes, "!.dockerignore")
+// ListBuildContext returns the files in the build context which BuildLocal sends to the docker daemon, filtered by
+// the context's .porterignore, or its .dockerignore if there is no .porterignore. The Dockerfile and .dockerignore
+// are always included, as the daemon reads them from the context.
+func ListBuildContext(opts BuildOpts) (*ignore.BuildContext, error) {
+	matcher, ignoreFile, err := ignore.Load(opts.BuildContext)
+	if err != nil {
+		return nil, fmt.Errorf("error reading ignore file: %w", err)
+	}
+
+	keep := []string{ignore.DockerIgnoreFile}
+	if opts.IsDockerfileInCtx {
+		keep = append(keep, opts.DockerfilePath)
 	}
-	if keep, _ := fileutils.Matches(dockerfile, excludes); keep {
-		excludes = append(excludes, "!"+dockerfile)
+
+	buildContext, err := ignore.ListBuildContext(opts.BuildContext, matcher, keep...)
+	if err != nil {
+		return nil, err
 	}
-	return excludes
+	buildContext.IgnoreFile = ignoreFile
+
+	return buildContext, nil
 }
 
 // AddDockerfileToBuildContext from a ReadCloser, returns a new archive and
@@ -128,6 +128,7 @@ func StartPorter(ctx context.Context, opts *PorterStartOpts) (agent *Agent, id s
 			"DB_NAME=porter",
 			"DB_HOST=porter_postgres_" + opts.ProcessID,
 			"DB_PORT=5432",
+			"DB_SSL_MODE=disable",
 		}...)
 	}
 
@@ -6,7 +6,9 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/cli/cli/git"
 	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
 	"github.com/porter-dev/porter/api/types"
@@ -24,14 +26,20 @@ type StackConf struct {
 	projectID, clusterID uint
 }
 
-// CreateApplicationDeploy creates everything needed to deploy a porter app
-func CreateApplicationDeploy(ctx context.Context, client api.Client, worker *switchboardWorker.Worker, app *Application, applicationName string, cliConf config.CLIConfig) ([]*switchboardTypes.Resource, error) {
-	err := cliConf.ValidateCLIEnvironment()
+// CreateApplicationDeploy creates everything needed to deploy a porter app. The message is the release notes of the deploy, and may be empty.
+// If wait is set, the deploy waits up to waitTimeout for the deployed revision of the app to be ready. If streamLogs is
+// set, the logs of the pods of the app are printed while it is deployed.
+func CreateApplicationDeploy(ctx context.Context, client api.Client, worker *switchboardWorker.Worker, app *Application, applicationName string, message string, wait bool, waitTimeout time.Duration, streamLogs bool, cliConf config.CLIConfig) ([]*switchboardTypes.Resource, error) {
+	err := cliConf.ValidateCLIEnvironment(ctx, &client)
 	if err != nil {
 		errMsg := composePreviewMessage("porter CLI is not configured correctly", Error)
 		return nil, fmt.Errorf("%s: %w", errMsg, err)
 	}
 
+	// apps whose porter.yaml only names an image deploy it as an external image. This is read before the build
+	// resources are created, since they fill in the build settings stored for the app if porter.yaml has none.
+	externalImage := app.Build.ExternalImage()
+
 	// we need to know the builder so that we can inject launcher to the start command later if heroku builder is used
 	var builder string
 	resources, builder, err := createV1BuildResources(ctx, client, app, applicationName, cliConf.Project, cliConf.Cluster)
@@ -44,6 +52,8 @@ func CreateApplicationDeploy(ctx context.Context, client api.Client, worker *swi
 		return nil, fmt.Errorf("malformed application definition: %w", err)
 	}
 
+	gitCommitSHA, gitCommitMessage := headCommit()
+
 	deployAppHook := &DeployAppHook{
 		Client:               client,
 		CLIConfig:            cliConf,
@@ -53,12 +63,36 @@ func CreateApplicationDeploy(ctx context.Context, client api.Client, worker *swi
 		BuildImageDriverName: GetBuildImageDriverName(applicationName),
 		PorterYAML:           applicationBytes,
 		Builder:              builder,
+		ExternalImage:        externalImage,
+		Message:              message,
+		GitCommitSHA:         gitCommitSHA,
+		GitCommitMessage:     gitCommitMessage,
+		Wait:                 wait,
+		WaitTimeout:          waitTimeout,
+		StreamLogs:           streamLogs,
+		ctx:                  ctx,
 	}
 
 	worker.RegisterHook("deploy-app", deployAppHook)
 	return resources, nil
 }
 
+// headCommit returns the SHA and message of the HEAD commit of the git repository in the current directory. Both are
+// empty if the app is not deployed from a git repository.
+func headCommit() (string, string) {
+	commit, err := git.LastCommit()
+	if err != nil || commit == nil {
+		return "", ""
+	}
+
+	message := commit.Title
+	if body, err := git.CommitBody(commit.Sha); err == nil && strings.TrimSpace(body) != "" {
+		message = fmt.Sprintf("%s\n\n%s", message, body)
+	}
+
+	return commit.Sha, message
+}
+
 // Create app event to signfy start of build
 func createAppEvent(ctx context.Context, client api.Client, applicationName string, projectId, clusterId uint) (string, error) {
 	var req *types.CreateOrUpdatePorterAppEventRequest
@@ -74,6 +74,22 @@ func (b *Build) GetImage() string {
 	return *b.Image
 }
 
+// ExternalImage returns the image of a build which only names an image to deploy, with the registry method and no build
+// settings, or an empty string if the image is built
+func (b *Build) ExternalImage() string {
+	if b == nil || b.GetImage() == "" {
+		return ""
+	}
+	if b.Method != nil && *b.Method != "registry" {
+		return ""
+	}
+	if b.Context != nil || b.Builder != nil || len(b.Buildpacks) > 0 || b.Dockerfile != nil {
+		return ""
+	}
+
+	return b.GetImage()
+}
+
 func (b *Build) getV1BuildImage(appName string, env map[string]string, namespace string) (*types.Resource, error) {
 	config := &preview.BuildDriverConfig{}
 
@@ -0,0 +1,48 @@
+package porter_app
+
+import "testing"
+
+func TestBuildExternalImage(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name  string
+		build *Build
+		want  string
+	}{
+		{
+			name:  "no build block",
+			build: nil,
+		},
+		{
+			name:  "image only",
+			build: &Build{Image: str("ghcr.io/acme/tool:v1.2.3")},
+			want:  "ghcr.io/acme/tool:v1.2.3",
+		},
+		{
+			name:  "registry method with an image",
+			build: &Build{Method: str("registry"), Image: str("ghcr.io/acme/tool:v1.2.3")},
+			want:  "ghcr.io/acme/tool:v1.2.3",
+		},
+		{
+			name:  "image which is built",
+			build: &Build{Method: str("docker"), Dockerfile: str("./Dockerfile"), Image: str("registry.example.com/storefront")},
+		},
+		{
+			name:  "image with a build context",
+			build: &Build{Method: str("registry"), Context: str("."), Image: str("registry.example.com/storefront")},
+		},
+		{
+			name:  "pack build",
+			build: &Build{Method: str("pack"), Builder: str("heroku/buildpacks:20")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.build.ExternalImage(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/porter-dev/porter/api/types"
+)
+
+// deployLogsGracePeriod is how long the logs of a deploy are still streamed once the deploy request has returned, for
+// the last lines of its pods and the final status of its revision to arrive
+var deployLogsGracePeriod = 10 * time.Second
+
+// streamDeployLogs prints the logs of the deploy of revision as they are streamed from the server, and returns a function
+// which stops the stream once the deploy request has returned. A stream which fails only stops the logs being printed,
+// since whether the deploy succeeded is read from the deploy request.
+func (t *DeployAppHook) streamDeployLogs(ctx context.Context, revision int) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	finished := make(chan struct{})
+
+	_, _ = color.New(color.FgBlue).Printf("Streaming the logs of revision %d of app %s\n", revision, t.ApplicationName)
+
+	go func() {
+		defer close(finished)
+
+		done, err := t.Client.StreamDeployLogs(ctx, t.ProjectID, t.ClusterID, t.ApplicationName, &types.StreamDeployLogsRequest{Revision: revision}, printDeployLogLine)
+		if err != nil {
+			if ctx.Err() == nil {
+				_, _ = color.New(color.FgYellow).Printf("Stopped streaming deploy logs: %s\n", err.Error())
+			}
+			return
+		}
+		if done != nil {
+			_, _ = color.New(color.FgBlue).Printf("Revision %d of app %s is %s\n", done.Revision, t.ApplicationName, done.Status)
+		}
+	}()
+
+	return func() {
+		select {
+		case <-finished:
+		case <-time.After(deployLogsGracePeriod):
+		}
+		cancel()
+		<-finished
+	}
+}
+
+func printDeployLogLine(line types.DeployLogLine) {
+	_, _ = color.New(color.FgCyan).Printf("[%s/%s] ", line.Pod, line.Container)
+	fmt.Println(line.Line)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
@@ -17,19 +19,48 @@ type DeployAppHook struct {
 	ApplicationName      string
 	ProjectID, ClusterID uint
 	BuildImageDriverName string
-	PorterYAML           []byte
-	Builder              string
-	BuildEventID         string
-	CLIConfig            config.CLIConfig
+	// ServiceBuildImageDriverNames maps services which are built separately to the name of the driver which builds
+	// their image
+	ServiceBuildImageDriverNames map[string]string
+	PorterYAML                   []byte
+	Builder                      string
+	BuildEventID                 string
+	CLIConfig                    config.CLIConfig
+	// ExternalImage is the image deployed by apps which deploy an external image rather than building one
+	ExternalImage string
+	// Message is the release notes of the deploy
+	Message string
+	// GitCommitSHA and GitCommitMessage identify the HEAD commit of the repository the app is deployed from
+	GitCommitSHA     string
+	GitCommitMessage string
+	// Wait waits until the deployed revision of the app is ready before the hook returns, and fails the deploy if it is
+	// not ready within WaitTimeout, or DefaultWaitTimeout if it is not set
+	Wait        bool
+	WaitTimeout time.Duration
+	// StreamLogs prints the logs of the pods of the app, including its pre-deploy job, while it is deployed
+	StreamLogs bool
+
+	// ctx is the context of the command which registered the hook. It is stored on the hook because the switchboard
+	// hook methods do not take a context.
+	ctx context.Context
+}
+
+// context returns the context of the command which registered the hook, or a background context if none was set
+func (t *DeployAppHook) context() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
 }
 
 func (t *DeployAppHook) PreApply() error {
-	err := t.CLIConfig.ValidateCLIEnvironment()
+	ctx := t.context()
+
+	err := t.CLIConfig.ValidateCLIEnvironment(ctx, &t.Client)
 	if err != nil {
 		errMsg := composePreviewMessage("porter CLI is not configured correctly", Error)
 		return fmt.Errorf("%s: %w", errMsg, err)
 	}
-	ctx := context.TODO() // switchboard blocks being able to change this for now
 
 	buildEventId, err := createAppEvent(ctx, t.Client, t.ApplicationName, t.ProjectID, t.ClusterID)
 	if err != nil {
@@ -44,15 +75,22 @@ func (t *DeployAppHook) DataQueries() map[string]interface{} {
 	res := map[string]interface{}{
 		"image": fmt.Sprintf("{$.%s.image}", t.BuildImageDriverName),
 	}
+	for service, driverName := range t.ServiceBuildImageDriverNames {
+		res[serviceImageQuery(service)] = fmt.Sprintf("{$.%s.image}", driverName)
+	}
 	return res
 }
 
-// deploy the app
+// deploy the app. The build has finished by the time the hook runs, so the build event is finished before the deploy,
+// and the activity feed shows the build, pre-deploy and deploy of the app as separate stages.
 func (t *DeployAppHook) PostApply(driverOutput map[string]interface{}) error {
-	ctx := context.TODO() // switchboard blocks being able to change this for now
+	ctx := t.context()
+
+	t.finishBuildEvent(ctx, types.PorterAppEventStatus_Success, map[string]any{})
+
 	namespace := fmt.Sprintf("porter-stack-%s", t.ApplicationName)
 
-	_, err := t.Client.GetRelease(
+	rel, err := t.Client.GetRelease(
 		ctx,
 		t.ProjectID,
 		t.ClusterID,
@@ -62,44 +100,71 @@ func (t *DeployAppHook) PostApply(driverOutput map[string]interface{}) error {
 
 	shouldCreate := err != nil
 
+	// the deploy creates the revision after the current one, or the first revision of a new app
+	revision := 1
+	if err == nil && rel.Release != nil {
+		revision = rel.Version + 1
+	}
+
 	if err != nil {
 		color.New(color.FgYellow).Printf("Could not read release for app %s (%s): attempting creation\n", t.ApplicationName, err.Error())
 	} else {
 		color.New(color.FgGreen).Printf("Found release for app %s: attempting update\n", t.ApplicationName)
 	}
 
-	err = t.createOrUpdateApplication(ctx, shouldCreate, driverOutput)
+	// the server records failed deploys in the activity feed, so the error is only returned
+	return t.createOrUpdateApplication(ctx, shouldCreate, revision, driverOutput)
+}
+
+// finishBuildEvent moves the build event of the deploy out of PROGRESSING. Builds are only finished once, so a build
+// which succeeded is not marked as failed by a later deploy error.
+func (t *DeployAppHook) finishBuildEvent(ctx context.Context, status types.PorterAppEventStatus, metadata map[string]any) {
+	if t.BuildEventID == "" {
+		return
+	}
+
+	_, _ = t.Client.UpdatePorterAppEvent(ctx, t.ProjectID, t.ClusterID, t.ApplicationName, t.BuildEventID, &types.UpdatePorterAppEventRequest{
+		Status:   status,
+		Metadata: metadata,
+	})
+}
+
+func (t *DeployAppHook) createOrUpdateApplication(ctx context.Context, shouldCreate bool, revision int, driverOutput map[string]interface{}) error {
+	imageInfo, err := imageInfoFromDriverOutput(driverOutput, "image")
 	if err != nil {
 		return err
 	}
-	eventRequest := types.CreateOrUpdatePorterAppEventRequest{
-		Status:   "SUCCESS",
-		Type:     types.PorterAppEventType_Build,
-		Metadata: map[string]any{},
-		ID:       t.BuildEventID,
-	}
-	_, _ = t.Client.CreateOrUpdatePorterAppEvent(ctx, t.ProjectID, t.ClusterID, t.ApplicationName, &eventRequest)
 
-	return nil
-}
+	// the image of apps which deploy an external image is not built, so it is read from porter.yaml
+	if !imageInfo.Complete() && t.ExternalImage != "" {
+		imageInfo, err = ImageInfoFromImage(t.ExternalImage)
+		if err != nil {
+			return err
+		}
+	}
 
-func (t *DeployAppHook) createOrUpdateApplication(ctx context.Context, shouldCreate bool, driverOutput map[string]interface{}) error {
-	var imageInfo types.ImageInfo
-	image, ok := driverOutput["image"].(string)
-	// if it contains a $, then it means the query didn't resolve to anything
-	if ok && !strings.Contains(image, "$") {
-		imageSpl := strings.Split(image, ":")
-		if len(imageSpl) == 2 {
-			imageInfo = types.ImageInfo{
-				Repository: imageSpl[0],
-				Tag:        imageSpl[1],
-			}
-		} else {
-			return fmt.Errorf("could not parse image info %s", image)
+	var serviceImageInfo map[string]types.ImageInfo
+	for service := range t.ServiceBuildImageDriverNames {
+		serviceImage, err := imageInfoFromDriverOutput(driverOutput, serviceImageQuery(service))
+		if err != nil {
+			return err
+		}
+		if !serviceImage.Complete() {
+			continue
 		}
+		if serviceImageInfo == nil {
+			serviceImageInfo = make(map[string]types.ImageInfo)
+		}
+		serviceImageInfo[service] = serviceImage
+	}
+
+	// the deploy request returns once the release is installed, so the logs are streamed alongside it
+	var stopDeployLogs func()
+	if t.StreamLogs {
+		stopDeployLogs = t.streamDeployLogs(ctx, revision)
 	}
 
-	_, err := t.Client.CreatePorterApp(
+	app, err := t.Client.CreatePorterApp(
 		ctx,
 		t.ProjectID,
 		t.ClusterID,
@@ -109,10 +174,18 @@ func (t *DeployAppHook) createOrUpdateApplication(ctx context.Context, shouldCre
 			ProjectID:        t.ProjectID,
 			PorterYAMLBase64: base64.StdEncoding.EncodeToString(t.PorterYAML),
 			ImageInfo:        imageInfo,
+			ServiceImageInfo: serviceImageInfo,
 			OverrideRelease:  false, // deploying from the cli will never delete release resources, only append or override
 			Builder:          t.Builder,
+			SourceType:       t.sourceType(),
+			Message:          t.Message,
+			GitCommitSHA:     t.GitCommitSHA,
+			GitCommitMessage: t.GitCommitMessage,
 		},
 	)
+	if stopDeployLogs != nil {
+		stopDeployLogs()
+	}
 	if err != nil {
 		if shouldCreate {
 			return fmt.Errorf("error creating app %s: %w", t.ApplicationName, err)
@@ -120,25 +193,58 @@ func (t *DeployAppHook) createOrUpdateApplication(ctx context.Context, shouldCre
 		return fmt.Errorf("error updating app %s: %w", t.ApplicationName, err)
 	}
 
+	for _, warning := range app.Warnings {
+		color.New(color.FgYellow).Printf("Warning: %s\n", warning) // nolint:errcheck,gosec
+	}
+
+	revision = deployedRevision(app, revision)
+	printDeployedApp(color.Output, t.ApplicationName, revision, app.URLs)
+
+	if t.Wait {
+		return t.waitForDeploy(ctx, revision, app.Namespace, deployedImages(imageInfo, serviceImageInfo))
+	}
+
 	return nil
 }
 
+// deployedRevision returns the revision a deploy created. The revision the server created is waited on, rather than the
+// one read before the deploy, so that a concurrent deploy or a release which is not yet visible is never mistaken for
+// this one. Servers which do not return it leave the revision read before the deploy.
+func deployedRevision(app *types.PorterApp, readRevision int) int {
+	if app.HelmRevisionNumber != 0 {
+		return app.HelmRevisionNumber
+	}
+	return readRevision
+}
+
+// printDeployedApp prints the revision of a deployed app and the URLs it is served on
+func printDeployedApp(w io.Writer, appName string, revision int, urls []string) {
+	color.New(color.FgGreen).Fprintf(w, "Deployed revision %d of app %s\n", revision, appName) // nolint:errcheck,gosec
+	for _, url := range urls {
+		color.New(color.FgGreen).Fprintf(w, "  %s\n", url) // nolint:errcheck,gosec
+	}
+}
+
+// sourceType returns the source type the app is deployed with. Deploys whose porter.yaml does not name an external image
+// leave it to the server, which keeps the source type the app was created with.
+func (t *DeployAppHook) sourceType() types.PorterAppSourceType {
+	if t.ExternalImage != "" {
+		return types.PorterAppSourceType_Image
+	}
+
+	return ""
+}
+
 func (t *DeployAppHook) OnConsolidatedErrors(errors map[string]error) {
-	ctx := context.TODO() // switchboard blocks being able to change this for now
+	ctx := t.context()
 
 	errorStringMap := make(map[string]string)
 	for k, v := range errors {
 		errorStringMap[k] = fmt.Sprintf("%+v", v)
 	}
-	eventRequest := types.CreateOrUpdatePorterAppEventRequest{
-		Status: "FAILED",
-		Type:   types.PorterAppEventType_Build,
-		Metadata: map[string]any{
-			"errors": errorStringMap,
-		},
-		ID: t.BuildEventID,
-	}
-	_, _ = t.Client.CreateOrUpdatePorterAppEvent(ctx, t.ProjectID, t.ClusterID, t.ApplicationName, &eventRequest)
+	t.finishBuildEvent(ctx, types.PorterAppEventStatus_Failed, map[string]any{
+		"errors": errorStringMap,
+	})
 }
 
 func (t *DeployAppHook) OnError(err error) {
@@ -146,3 +252,19 @@ func (t *DeployAppHook) OnError(err error) {
 		"pre-apply": err,
 	})
 }
+
+// serviceImageQuery returns the key of the data query which resolves to the image built for a service
+func serviceImageQuery(service string) string {
+	return fmt.Sprintf("image-%s", service)
+}
+
+// imageInfoFromDriverOutput returns the image a data query resolved to, or empty image info if it did not resolve
+func imageInfoFromDriverOutput(driverOutput map[string]interface{}, query string) (types.ImageInfo, error) {
+	image, ok := driverOutput[query].(string)
+	// if it contains a $, then it means the query didn't resolve to anything
+	if !ok || strings.Contains(image, "$") {
+		return types.ImageInfo{}, nil
+	}
+
+	return ImageInfoFromImage(image)
+}
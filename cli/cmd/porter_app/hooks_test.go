@@ -0,0 +1,50 @@
+package porter_app
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/porter-dev/porter/api/types"
+)
+
+func TestDeployedRevision(t *testing.T) {
+	tests := []struct {
+		name string
+		app  *types.PorterApp
+		want int
+	}{
+		{
+			name: "revision created by the server",
+			app:  &types.PorterApp{HelmRevisionNumber: 5},
+			want: 5,
+		},
+		{
+			name: "server which does not return the revision",
+			app:  &types.PorterApp{},
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deployedRevision(tt.app, 3); got != tt.want {
+				t.Errorf("expected revision %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPrintDeployedApp(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	var out bytes.Buffer
+	printDeployedApp(&out, "web", 4, []string{"https://web.example.com", "https://www.example.com"})
+
+	want := "Deployed revision 4 of app web\n  https://web.example.com\n  https://www.example.com\n"
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+}
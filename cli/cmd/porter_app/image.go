@@ -0,0 +1,42 @@
+package porter_app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// defaultImageTag is the tag of an image which does not set one, as it is for docker
+const defaultImageTag = "latest"
+
+// ImageInfoFromImage splits an image reference such as registry.example.com:5000/app:v1 into its repository and tag.
+// The tag follows the last colon after the last slash, so that the port of a registry is kept in the repository, and an
+// image without a tag is tagged latest. Images pinned by digest are refused, since apps are deployed by tag.
+func ImageInfoFromImage(image string) (types.ImageInfo, error) {
+	if image == "" {
+		return types.ImageInfo{}, fmt.Errorf("image is empty")
+	}
+
+	if strings.Contains(image, "@") {
+		return types.ImageInfo{}, fmt.Errorf("could not parse image info %s: images pinned by digest are not supported, use a tag instead", image)
+	}
+
+	repository := image
+	tag := defaultImageTag
+
+	lastSlash := strings.LastIndex(image, "/")
+	if lastColon := strings.LastIndex(image, ":"); lastColon > lastSlash {
+		repository = image[:lastColon]
+		tag = image[lastColon+1:]
+	}
+
+	if repository == "" || tag == "" {
+		return types.ImageInfo{}, fmt.Errorf("could not parse image info %s", image)
+	}
+
+	return types.ImageInfo{
+		Repository: repository,
+		Tag:        tag,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package porter_app
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+func TestImageInfoFromImage(t *testing.T) {
+	tests := []struct {
+		image   string
+		want    types.ImageInfo
+		wantErr bool
+	}{
+		{
+			image: "nginx:1.25",
+			want:  types.ImageInfo{Repository: "nginx", Tag: "1.25"},
+		},
+		{
+			image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/web:abc1234",
+			want:  types.ImageInfo{Repository: "123456789012.dkr.ecr.us-east-1.amazonaws.com/web", Tag: "abc1234"},
+		},
+		{
+			image: "registry.example.com:5000/app:v1",
+			want:  types.ImageInfo{Repository: "registry.example.com:5000/app", Tag: "v1"},
+		},
+		{
+			image: "registry.example.com:5000/team/app",
+			want:  types.ImageInfo{Repository: "registry.example.com:5000/team/app", Tag: "latest"},
+		},
+		{
+			image: "app",
+			want:  types.ImageInfo{Repository: "app", Tag: "latest"},
+		},
+		{
+			image:   "app@sha256:3b9c0a7e5b7d8f1e2c4a6b8d0f1e3c5a7b9d1f3e5c7a9b1d3f5e7c9a1b3d5f7e",
+			wantErr: true,
+		},
+		{
+			image:   "registry.example.com:5000/app:v1@sha256:3b9c0a7e5b7d8f1e2c4a6b8d0f1e3c5a7b9d1f3e5c7a9b1d3f5e7c9a1b3d5f7e",
+			wantErr: true,
+		},
+		{
+			image:   "app:",
+			wantErr: true,
+		},
+		{
+			image:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, err := ImageInfoFromImage(tt.image)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -30,9 +30,18 @@ type Build struct {
 }
 
 type Service struct {
-	Run    *string                `yaml:"run"`
-	Config map[string]interface{} `yaml:"config"`
-	Type   *string                `yaml:"type" validate:"required, oneof=web worker job"`
+	Run           *string                `yaml:"run"`
+	Config        map[string]interface{} `yaml:"config"`
+	Type          *string                `yaml:"type" validate:"required, oneof=web worker job"`
+	Observability *ServiceObservability  `yaml:"observability,omitempty"`
+	// CustomDomains are the domains a web service is served on along with its porter subdomain
+	CustomDomains []string `yaml:"custom_domains,omitempty"`
+}
+
+// ServiceObservability controls the observability values Porter injects into a service
+type ServiceObservability struct {
+	// Enabled is false to stop the OpenTelemetry env variables of the project being injected
+	Enabled *bool `yaml:"enabled"`
 }
 
 type SyncedEnvSection struct {
@@ -0,0 +1,156 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/stefanmcshane/helm/pkg/release"
+	v1 "k8s.io/api/core/v1"
+)
+
+// DefaultWaitTimeout is how long a deploy is waited for if no timeout is set
+const DefaultWaitTimeout = 10 * time.Minute
+
+// waitPollInterval is how often the release of an app is read while its deploy is waited for
+var waitPollInterval = 10 * time.Second
+
+// waitForDeploy polls the release of the app until revision is deployed and every pod of the release is ready and runs
+// one of images, and returns an error if the release fails, a pod crash loops or the wait timeout passes first
+func (t *DeployAppHook) waitForDeploy(ctx context.Context, revision int, namespace string, images []string) error {
+	timeout := t.WaitTimeout
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	if namespace == "" {
+		namespace = fmt.Sprintf("porter-stack-%s", t.ApplicationName)
+	}
+
+	_, _ = color.New(color.FgBlue).Printf("Waiting up to %s for revision %d of app %s to be ready\n", timeout, revision, t.ApplicationName)
+
+	for {
+		var progress string
+
+		rel, err := t.Client.GetRelease(ctx, t.ProjectID, t.ClusterID, namespace, t.ApplicationName)
+		if err != nil {
+			return fmt.Errorf("error reading release of app %s: %w", t.ApplicationName, err)
+		}
+
+		switch {
+		case rel.Release == nil || rel.Info == nil:
+			progress = "waiting for the release to be readable"
+		case rel.Version < revision:
+			progress = fmt.Sprintf("waiting for revision %d, the release is at revision %d", revision, rel.Version)
+		case rel.Info.Status == release.StatusFailed:
+			return fmt.Errorf("revision %d of app %s failed: %s", rel.Version, t.ApplicationName, rel.Info.Description)
+		case rel.Info.Status != release.StatusDeployed:
+			progress = fmt.Sprintf("revision %d is %s", rel.Version, rel.Info.Status)
+		default:
+			pods, err := t.Client.GetK8sAllPods(ctx, t.ProjectID, t.ClusterID, namespace, t.ApplicationName)
+			if err != nil {
+				return fmt.Errorf("error reading pods of app %s: %w", t.ApplicationName, err)
+			}
+
+			status := releasePodsStatus(*pods, images)
+			if status.crashLooping != "" {
+				return fmt.Errorf("pod %s of app %s is crash looping", status.crashLooping, t.ApplicationName)
+			}
+			if status.ready == status.total {
+				_, _ = color.New(color.FgGreen).Printf("Revision %d of app %s is ready\n", rel.Version, t.ApplicationName)
+				return nil
+			}
+
+			progress = fmt.Sprintf("%d of %d pods are ready", status.ready, status.total)
+			if status.outdated != 0 {
+				progress = fmt.Sprintf("%s, %d still run the previous image", progress, status.outdated)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for revision %d of app %s to be ready: %s", timeout, revision, t.ApplicationName, progress)
+		}
+		_, _ = color.New(color.FgBlue).Printf("%s\n", progress)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// podsStatus counts the pods of a release which are ready to serve the deployed revision
+type podsStatus struct {
+	total int
+	// ready pods run one of the deployed images and pass their readiness checks
+	ready int
+	// outdated pods run none of the deployed images
+	outdated int
+	// crashLooping is the name of a pod which runs one of the deployed images and is crash looping, if there is one
+	crashLooping string
+}
+
+// releasePodsStatus counts the pods of a release which run one of images, and are ready. Pods which are terminating or
+// have exited, such as the pods of jobs, are not counted. Every pod which is running is counted as up to date if images
+// is empty.
+func releasePodsStatus(pods []v1.Pod, images []string) podsStatus {
+	var status podsStatus
+
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		status.total++
+
+		if !runsImage(pod, images) {
+			status.outdated++
+			continue
+		}
+
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason == "CrashLoopBackOff" {
+				status.crashLooping = pod.Name
+			}
+		}
+
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+				status.ready++
+			}
+		}
+	}
+
+	return status
+}
+
+func runsImage(pod v1.Pod, images []string) bool {
+	if len(images) == 0 {
+		return true
+	}
+
+	for _, container := range pod.Spec.Containers {
+		for _, image := range images {
+			if container.Image == image {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// deployedImages returns the images a deploy runs, as they are set on the containers of the app
+func deployedImages(imageInfo types.ImageInfo, serviceImageInfo map[string]types.ImageInfo) []string {
+	var images []string
+	if imageInfo.Complete() {
+		images = append(images, fmt.Sprintf("%s:%s", imageInfo.Repository, imageInfo.Tag))
+	}
+	for _, serviceImage := range serviceImageInfo {
+		images = append(images, fmt.Sprintf("%s:%s", serviceImage.Repository, serviceImage.Tag))
+	}
+
+	return images
+}
@@ -0,0 +1,77 @@
+package porter_app
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func waitTestPod(name, image string, phase v1.PodPhase, ready bool) v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: image}}},
+		Status: v1.PodStatus{
+			Phase:             phase,
+			Conditions:        []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+			ContainerStatuses: []v1.ContainerStatus{{Name: "web"}},
+		},
+	}
+}
+
+func TestReleasePodsStatus(t *testing.T) {
+	images := []string{"registry.example.com/storefront:v2"}
+
+	terminating := waitTestPod("web-old-2", "registry.example.com/storefront:v1", v1.PodRunning, true)
+	terminating.DeletionTimestamp = &metav1.Time{}
+
+	crashLooping := waitTestPod("web-new-2", "registry.example.com/storefront:v2", v1.PodRunning, false)
+	crashLooping.Status.ContainerStatuses[0].State.Waiting = &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+
+	tests := []struct {
+		name string
+		pods []v1.Pod
+		want podsStatus
+	}{
+		{
+			name: "rollout in progress",
+			pods: []v1.Pod{
+				waitTestPod("web-new-1", "registry.example.com/storefront:v2", v1.PodRunning, true),
+				waitTestPod("web-new-2", "registry.example.com/storefront:v2", v1.PodPending, false),
+				waitTestPod("web-old-1", "registry.example.com/storefront:v1", v1.PodRunning, true),
+			},
+			want: podsStatus{total: 3, ready: 1, outdated: 1},
+		},
+		{
+			name: "terminating and exited pods are not counted",
+			pods: []v1.Pod{
+				waitTestPod("web-new-1", "registry.example.com/storefront:v2", v1.PodRunning, true),
+				waitTestPod("migrate-1", "registry.example.com/storefront:v2", v1.PodSucceeded, false),
+				terminating,
+			},
+			want: podsStatus{total: 1, ready: 1},
+		},
+		{
+			name: "crash looping pod",
+			pods: []v1.Pod{crashLooping},
+			want: podsStatus{total: 1, crashLooping: "web-new-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := releasePodsStatus(tt.pods, images); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if got := releasePodsStatus([]v1.Pod{waitTestPod("web-1", "nginx:1.25", v1.PodRunning, true)}, nil); got.ready != 1 || got.outdated != 0 {
+		t.Errorf("expected every running pod to be up to date when the deployed images are not known, got %+v", got)
+	}
+}
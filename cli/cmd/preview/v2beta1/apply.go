@@ -33,7 +33,7 @@ func NewApplier(client api.Client, cliConfig config.CLIConfig, raw []byte, names
 		return nil, fmt.Errorf("%s: %w", errMsg, err)
 	}
 
-	err = cliConfig.ValidateCLIEnvironment()
+	err = cliConfig.ValidateCLIEnvironment(context.TODO(), &client) // can not change because of switchboard
 
 	if err != nil {
 		errMsg := composePreviewMessage("porter CLI is not configured correctly", Error)
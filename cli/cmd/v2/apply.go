@@ -18,6 +18,7 @@ import (
 
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
 
 	"github.com/cli/cli/git"
 
@@ -47,6 +48,8 @@ type ApplyInput struct {
 	WithPredeploy bool
 	// Exact is true when Apply should use the exact app config provided by the user
 	Exact bool
+	// ShowBuildContext is true when Apply should list the files in the build context before uploading them
+	ShowBuildContext bool
 }
 
 // Apply implements the functionality of the `porter apply` command for validate apply v2 projects
@@ -112,6 +115,11 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 			return fmt.Errorf("could not read porter yaml file: %w", err)
 		}
 
+		porterYaml, err = envvalues.ResolveFiles(porterYaml, filepath.Dir(inp.PorterYamlPath))
+		if err != nil {
+			return fmt.Errorf("error resolving env in porter yaml: %w", err)
+		}
+
 		b64YAML = base64.StdEncoding.EncodeToString(porterYaml)
 		color.New(color.FgGreen).Printf("Using Porter YAML at path: %s\n", inp.PorterYamlPath) // nolint:errcheck,gosec
 	}
@@ -207,12 +215,14 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 
 		buildInput, err := buildInputFromBuildSettings(buildInputFromBuildSettingsInput{
 			projectID:            cliConf.Project,
+			clusterID:            cliConf.Cluster,
 			appName:              appName,
 			commitSHA:            commitSHA,
 			image:                buildSettings.Image,
 			build:                buildSettings.Build,
 			buildEnv:             buildEnvVariables,
 			pullImageBeforeBuild: inp.PullImageBeforeBuild,
+			showBuildContext:     inp.ShowBuildContext,
 		})
 		if err != nil {
 			buildError = fmt.Errorf("error creating build input from build settings: %w", err)
@@ -449,12 +459,14 @@ func gitSourceFromEnv() (porter_app.GitSource, error) {
 
 type buildInputFromBuildSettingsInput struct {
 	projectID            uint
+	clusterID            uint
 	appName              string
 	commitSHA            string
 	image                porter_app.Image
 	build                porter_app.BuildSettings
 	buildEnv             map[string]string
 	pullImageBeforeBuild bool
+	showBuildContext     bool
 }
 
 func buildInputFromBuildSettings(inp buildInputFromBuildSettingsInput) (buildInput, error) {
@@ -479,6 +491,7 @@ func buildInputFromBuildSettings(inp buildInputFromBuildSettingsInput) (buildInp
 
 	return buildInput{
 		ProjectID:            inp.projectID,
+		ClusterID:            inp.clusterID,
 		AppName:              inp.appName,
 		BuildContext:         buildContext,
 		Dockerfile:           inp.build.Dockerfile,
@@ -490,6 +503,7 @@ func buildInputFromBuildSettings(inp buildInputFromBuildSettingsInput) (buildInp
 		CurrentImageTag:      inp.image.Tag,
 		Env:                  inp.buildEnv,
 		PullImageBeforeBuild: inp.pullImageBeforeBuild,
+		ShowBuildContext:     inp.showBuildContext,
 	}, nil
 }
 
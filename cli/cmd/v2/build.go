@@ -8,8 +8,11 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/fatih/color"
+	"github.com/porter-dev/porter/api/server/handlers/porter_app"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/cli/cmd/pack"
+	"github.com/porter-dev/porter/internal/ignore"
 
 	"github.com/porter-dev/porter/cli/cmd/docker"
 
@@ -25,6 +28,7 @@ const (
 // buildInput is the input struct for the build method
 type buildInput struct {
 	ProjectID uint
+	ClusterID uint
 	// AppName is the name of the application being built and is used to name the repository
 	AppName      string
 	BuildContext string
@@ -40,6 +44,8 @@ type buildInput struct {
 	RepositoryURL   string
 	// PullImageBeforeBuild is used to pull the docker image before building
 	PullImageBeforeBuild bool
+	// ShowBuildContext prints the files in a docker build context and their total size before they are uploaded
+	ShowBuildContext bool
 
 	Env map[string]string
 }
@@ -117,6 +123,18 @@ func build(ctx context.Context, client api.Client, inp buildInput) buildOutput {
 			UseCache:          inp.PullImageBeforeBuild,
 		}
 
+		buildContext, err := docker.ListBuildContext(*opts)
+		if err != nil {
+			output.Error = fmt.Errorf("error listing build context: %w", err)
+			return output
+		}
+		opts.Context = buildContext
+
+		if inp.ShowBuildContext {
+			printBuildContext(buildContext)
+		}
+		validateBuildContext(ctx, client, inp, buildContext)
+
 		err = dockerAgent.BuildLocal(
 			ctx,
 			opts,
@@ -180,6 +198,63 @@ func build(ctx context.Context, client api.Client, inp buildInput) buildOutput {
 	return output
 }
 
+// printBuildContext lists every file in the build context with its size, followed by the total size
+func printBuildContext(buildContext *ignore.BuildContext) {
+	source := "no ignore file"
+	if buildContext.IgnoreFile != "" {
+		source = buildContext.IgnoreFile
+	}
+
+	color.New(color.FgGreen).Printf("Build context (filtered by %s):\n", source) // nolint:errcheck,gosec
+	for _, f := range buildContext.Files {
+		fmt.Printf("  %10s  %s\n", formatBytes(f.Size), f.Path)
+	}
+	color.New(color.FgGreen).Printf("Total: %d files, %s\n", len(buildContext.Files), formatBytes(buildContext.Size)) // nolint:errcheck,gosec
+}
+
+// validateBuildContext reports the size of the build context to the server, printing any warnings it returns. It
+// never fails the build, so that a server without the validation endpoint can still be deployed to.
+func validateBuildContext(ctx context.Context, client api.Client, inp buildInput, buildContext *ignore.BuildContext) {
+	if inp.ClusterID == 0 {
+		return
+	}
+
+	resp, err := client.ValidateBuildSettings(ctx, inp.ProjectID, inp.ClusterID, inp.AppName, &porter_app.ValidateBuildSettingsRequest{
+		BuildSettings: porter_app.BuildSettings{
+			Method:     inp.BuildMethod,
+			Context:    inp.BuildContext,
+			Dockerfile: inp.Dockerfile,
+			Builder:    inp.Builder,
+			Buildpacks: inp.BuildPacks,
+		},
+		ContextSizeBytes: buildContext.Size,
+		ContextFileCount: len(buildContext.Files),
+	})
+	if err != nil {
+		color.New(color.FgYellow).Printf("Unable to validate build settings: %s\n", err.Error()) // nolint:errcheck,gosec
+		return
+	}
+
+	for _, warning := range resp.Warnings {
+		color.New(color.FgYellow).Printf("Warning: %s\n", warning) // nolint:errcheck,gosec
+	}
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
 func createImageRepositoryIfNotExists(ctx context.Context, client api.Client, projectID uint, imageURL string) error {
 	if projectID == 0 {
 		return errors.New("must specify a project id")
@@ -24,7 +24,17 @@ import (
 
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/config/loader"
+	"github.com/porter-dev/porter/internal/chargeback"
+	"github.com/porter-dev/porter/internal/devmode"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/bulkredeploy"
+	"github.com/porter-dev/porter/internal/porter_app/certexpiry"
+	"github.com/porter-dev/porter/internal/porter_app/deletecleanup"
+	"github.com/porter-dev/porter/internal/porter_app/inactivity"
+	"github.com/porter-dev/porter/internal/porter_app/logalerts"
+	"github.com/porter-dev/porter/internal/porter_app/scaling"
+	"github.com/porter-dev/porter/internal/registry"
+	"github.com/porter-dev/porter/internal/webhooks"
 	"gorm.io/gorm"
 )
 
@@ -36,9 +46,10 @@ func main() {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	var versionFlag, authServiceFlag bool
+	var versionFlag, authServiceFlag, devFlag bool
 	flag.BoolVar(&versionFlag, "version", false, "print version and exit")
 	flag.BoolVar(&authServiceFlag, "auth", false, "run auth service instead of porter api")
+	flag.BoolVar(&devFlag, "dev", false, "run a local development server with an sqlite database and seeded demo data (or set "+devmode.EnvVar+"=1)")
 	flag.Parse()
 
 	// Exit safely when version is used
@@ -47,6 +58,16 @@ func main() {
 		os.Exit(0)
 	}
 
+	// dev mode sets up the environment the config is loaded from, so it must run first
+	isDevMode := devmode.Enabled(devFlag)
+	if isDevMode {
+		devEnv, err := devmode.Setup()
+		if err != nil {
+			log.Fatal("Dev mode setup failed: ", err)
+		}
+		defer devEnv.Close() // nolint:errcheck
+	}
+
 	cl := loader.NewEnvLoader(Version)
 
 	config, err := cl.LoadConfig()
@@ -61,6 +82,15 @@ func main() {
 	}
 	config.Logger.Info().Msg("Initialed data")
 
+	if isDevMode {
+		seed, err := devmode.SeedData(config)
+		if err != nil {
+			log.Fatal("Dev mode seeding failed: ", err)
+		}
+
+		fmt.Println(seed.Banner(config.ServerConf.ServerURL))
+	}
+
 	tracer, err := telemetry.InitTracer(ctx, config.TelemetryConfig)
 	if err != nil {
 		config.Logger.Fatal().Err(err).Msg("Error initializing telemetry")
@@ -87,11 +117,144 @@ func main() {
 		config.Logger.Info().Msg("Created API router")
 
 		p := server.PorterAPIServer{
-			Port:       config.ServerConf.Port,
-			Router:     appRouter,
-			ServerConf: config.ServerConf,
+			Port:           config.ServerConf.Port,
+			Router:         appRouter,
+			ServerConf:     config.ServerConf,
+			StreamRegistry: config.StreamRegistry,
+		}
+
+		if err := config.Supervisor.Register("stream-reaper", func(ctx context.Context) error {
+			config.StreamRegistry.Run(ctx)
+			return nil
+		}); err != nil {
+			config.Logger.Fatal().Err(err).Msg("Error registering background component")
+		}
+
+		if config.ServerConf.LogAlertEvaluationInterval > 0 {
+			logAlertEvaluator := logalerts.NewEvaluatorFromConfig(config, logalerts.Options{
+				Interval:       config.ServerConf.LogAlertEvaluationInterval,
+				ClusterTimeout: config.ServerConf.LogAlertClusterTimeout,
+				MaxLinesPerApp: config.ServerConf.LogAlertMaxLinesPerApp,
+				Logger:         config.Logger,
+			})
+			if err := config.Supervisor.Register("log-alert-evaluator", logAlertEvaluator.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
 		}
 
+		if config.ServerConf.WebhookDeliveryRetention > 0 {
+			webhookDeliveryPruner := webhooks.NewPruner(config.Repo.WebhookDelivery(), webhooks.PrunerOptions{
+				Retention: config.ServerConf.WebhookDeliveryRetention,
+				Logger:    config.Logger,
+			})
+			if err := config.Supervisor.Register("webhook-delivery-retention", webhookDeliveryPruner.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		if config.ServerConf.UsageRollupInterval > 0 {
+			usageRollupJob := chargeback.NewJob(config.Repo.PorterAppEvent(), config.Repo.PorterApp(), config.Repo.UsageRollup(), chargeback.Options{
+				Interval:       config.ServerConf.UsageRollupInterval,
+				SettleDays:     config.ServerConf.UsageRollupSettleDays,
+				BackfillDays:   config.ServerConf.UsageRollupBackfillDays,
+				EventRetention: config.ServerConf.PorterAppEventRetention,
+				Logger:         config.Logger,
+			})
+			if err := config.Supervisor.Register("usage-rollup", usageRollupJob.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		if config.ServerConf.BulkRedeployPollInterval > 0 {
+			bulkRedeployRunner := bulkredeploy.NewRunnerFromConfig(config, bulkredeploy.Options{
+				Interval:         config.ServerConf.BulkRedeployPollInterval,
+				OperationTimeout: config.ServerConf.BulkRedeployOperationTimeout,
+				Logger:           config.Logger,
+			})
+			if err := config.Supervisor.Register("bulk-redeploy", bulkRedeployRunner.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		if config.ServerConf.InactivityCleanupInterval > 0 {
+			inactivityCleaner := inactivity.NewCleanerFromConfig(config, inactivity.Options{
+				Interval:       config.ServerConf.InactivityCleanupInterval,
+				ClusterTimeout: config.ServerConf.InactivityCleanupClusterTimeout,
+				Logger:         config.Logger,
+			})
+			if err := config.Supervisor.Register("inactivity-cleanup", inactivityCleaner.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		if config.ServerConf.ScalingScheduleInterval > 0 {
+			scalingScheduler := scaling.NewSchedulerFromConfig(config, scaling.Options{
+				Interval:       config.ServerConf.ScalingScheduleInterval,
+				ClusterTimeout: config.ServerConf.ScalingScheduleClusterTimeout,
+				Logger:         config.Logger,
+			})
+			if err := config.Supervisor.Register("scaling-scheduler", scalingScheduler.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		if config.ServerConf.CertExpiryCheckInterval > 0 {
+			certChecker := certexpiry.NewCheckerFromConfig(config, certexpiry.Options{
+				Interval:       config.ServerConf.CertExpiryCheckInterval,
+				ClusterTimeout: config.ServerConf.CertExpiryCheckClusterTimeout,
+				Thresholds:     config.ServerConf.CertExpiryAlertDays,
+				Logger:         config.Logger,
+			})
+			if err := config.Supervisor.Register("cert-expiry-checker", certChecker.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		if config.ServerConf.PorterAppCleanupInterval > 0 {
+			cleanupWatcher := deletecleanup.NewWatcherFromConfig(config, deletecleanup.Options{
+				Interval:       config.ServerConf.PorterAppCleanupInterval,
+				ClusterTimeout: config.ServerConf.PorterAppCleanupClusterTimeout,
+				Logger:         config.Logger,
+			})
+			if err := config.Supervisor.Register("porter-app-cleanup-watcher", cleanupWatcher.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		if config.ServerConf.RegistryCredentialCheckInterval > 0 {
+			credentialChecker := registry.NewCredentialChecker(config.Repo, config.DOConf, registry.CredentialCheckerOptions{
+				Interval: config.ServerConf.RegistryCredentialCheckInterval,
+				Logger:   config.Logger,
+			})
+			if err := config.Supervisor.Register("registry-credential-checker", credentialChecker.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		if config.ServerConf.ExpiredRowCleanupInterval > 0 {
+			if err := config.Supervisor.Register("expired-row-cleanup", config.Cleaner.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		if config.ServerConf.AdminOverviewInterval > 0 {
+			if err := config.Supervisor.Register("admin-overview-collector", config.AdminOverview.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		// the buffer writes the queued events when it is stopped, and the supervisor waits for it before the server exits
+		if config.EventBuffer != nil {
+			if err := config.Supervisor.Register("porter-app-event-buffer", config.EventBuffer.Run); err != nil {
+				config.Logger.Fatal().Err(err).Msg("Error registering background component")
+			}
+		}
+
+		g.Go(func() error {
+			config.Supervisor.Run(ctx)
+			return nil
+		})
+
 		g.Go(func() error {
 			config.Logger.Info().Msgf("Starting PorterAPI server on port %d", config.ServerConf.Port)
 			if err := p.ListenAndServe(ctx); err != nil && err != http.ErrServerClosed {
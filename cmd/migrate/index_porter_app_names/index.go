@@ -0,0 +1,103 @@
+package index_porter_app_names
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/features"
+	"github.com/porter-dev/porter/internal/models"
+	lr "github.com/porter-dev/porter/pkg/logger"
+	_gorm "gorm.io/gorm"
+)
+
+// IndexName is the name of the unique index on the cluster and lowercased name of porter apps
+const IndexName = "idx_porter_apps_cluster_id_lower_name"
+
+// NameConflict is a set of apps in a cluster whose names only differ by case
+type NameConflict struct {
+	ClusterID uint
+	// Name is the lowercased name the apps share
+	Name string
+	Apps []*models.PorterApp
+}
+
+// String lists the IDs and names of the conflicting apps
+func (c NameConflict) String() string {
+	apps := make([]string, 0, len(c.Apps))
+	for _, app := range c.Apps {
+		apps = append(apps, fmt.Sprintf("%d (%s)", app.ID, app.Name))
+	}
+
+	return fmt.Sprintf("cluster %d, name %s: apps %s", c.ClusterID, c.Name, strings.Join(apps, ", "))
+}
+
+// FindNameConflicts returns the apps, other than deleted apps, whose names only differ by case from the name of another
+// app in the same cluster
+func FindNameConflicts(db *_gorm.DB) ([]NameConflict, error) {
+	var apps []*models.PorterApp
+
+	if err := db.Select("id", "cluster_id", "name").Order("id ASC").Find(&apps).Error; err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		clusterID uint
+		name      string
+	}
+
+	byName := make(map[key][]*models.PorterApp)
+	for _, app := range apps {
+		k := key{clusterID: app.ClusterID, name: strings.ToLower(app.Name)}
+		byName[k] = append(byName[k], app)
+	}
+
+	var conflicts []NameConflict
+	for k, apps := range byName {
+		if len(apps) > 1 {
+			conflicts = append(conflicts, NameConflict{ClusterID: k.clusterID, Name: k.name, Apps: apps})
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].ClusterID != conflicts[j].ClusterID {
+			return conflicts[i].ClusterID < conflicts[j].ClusterID
+		}
+		return conflicts[i].Name < conflicts[j].Name
+	})
+
+	return conflicts, nil
+}
+
+// IndexPorterAppNames adds a unique index on the cluster and lowercased name of porter apps, so that two apps in a
+// cluster cannot have names which only differ by case, since they would share a namespace. Deleted apps are not
+// indexed.
+//
+// The apps which already conflict are reported first, and the migration fails without adding the index until they
+// have been renamed or deleted, since it cannot choose which of them to keep.
+func IndexPorterAppNames(db *_gorm.DB, _ *features.Client, logger *lr.Logger) error {
+	logger.Info().Msg("starting to index porter app names case-insensitively")
+
+	conflicts, err := FindNameConflicts(db)
+	if err != nil {
+		logger.Error().Msgf("failed to get porter apps: %v", err)
+		return err
+	}
+
+	if len(conflicts) != 0 {
+		for _, conflict := range conflicts {
+			logger.Error().Msgf("porter app names only differ by case in %s", conflict)
+		}
+
+		return fmt.Errorf("%d sets of porter apps have names which only differ by case, rename or delete all but one app of each before migrating", len(conflicts))
+	}
+
+	if err := db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON porter_apps (cluster_id, LOWER(name)) WHERE deleted_at IS NULL", IndexName)).Error; err != nil {
+		logger.Error().Msgf("failed to create index %s: %v", IndexName, err)
+		return err
+	}
+
+	logger.Info().Msg("porter app names migration completed")
+
+	return nil
+}
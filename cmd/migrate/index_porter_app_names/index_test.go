@@ -0,0 +1,113 @@
+package index_porter_app_names
+
+import (
+	"os"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/features"
+	"github.com/porter-dev/porter/internal/models"
+	lr "github.com/porter-dev/porter/pkg/logger"
+	_gorm "gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T, dbFileName string) *_gorm.DB {
+	t.Helper()
+
+	db, err := adapter.New(&env.DBConf{
+		EncryptionKey: "__random_strong_encryption_key__",
+		SQLLite:       true,
+		SQLLitePath:   dbFileName,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	t.Cleanup(func() { os.Remove(dbFileName) })
+
+	if err := db.AutoMigrate(&models.PorterApp{}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	return db
+}
+
+func TestIndexPorterAppNames(t *testing.T) {
+	logger := lr.NewConsole(true)
+	db := setupTestDB(t, "./porter_app_names.db")
+
+	for _, app := range []*models.PorterApp{
+		{ClusterID: 1, Name: "web"},
+		{ClusterID: 2, Name: "Web"},
+		{ClusterID: 1, Name: "worker"},
+		// deleted apps do not conflict with the apps which replaced them
+		{ClusterID: 1, Name: "Worker"},
+	} {
+		if err := db.Create(app).Error; err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+	if err := db.Where("name = ?", "Worker").Delete(&models.PorterApp{}).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := IndexPorterAppNames(db, &features.Client{}, logger); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := db.Create(&models.PorterApp{ClusterID: 1, Name: "WEB"}).Error; err == nil {
+		t.Errorf("expected an app whose name only differs by case to be refused")
+	}
+
+	// once both apps named worker are deleted, the name is free again
+	if err := db.Where("name = ?", "worker").Delete(&models.PorterApp{}).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := db.Create(&models.PorterApp{ClusterID: 1, Name: "WORKER"}).Error; err != nil {
+		t.Errorf("expected the name of deleted apps to be reused, got %v", err)
+	}
+}
+
+func TestIndexPorterAppNamesReportsConflicts(t *testing.T) {
+	logger := lr.NewConsole(true)
+	db := setupTestDB(t, "./porter_app_name_conflicts.db")
+
+	for _, app := range []*models.PorterApp{
+		{ClusterID: 1, Name: "web"},
+		{ClusterID: 1, Name: "Web"},
+		{ClusterID: 1, Name: "worker"},
+		{ClusterID: 2, Name: "api"},
+		{ClusterID: 2, Name: "API"},
+	} {
+		if err := db.Create(app).Error; err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	conflicts, err := FindNameConflicts(db)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	want := []string{
+		"cluster 1, name web: apps 1 (web), 2 (Web)",
+		"cluster 2, name api: apps 4 (api), 5 (API)",
+	}
+	if len(conflicts) != len(want) {
+		t.Fatalf("expected %d conflicts, got %d: %v", len(want), len(conflicts), conflicts)
+	}
+	for i := range want {
+		if got := conflicts[i].String(); got != want[i] {
+			t.Errorf("expected conflict %q, got %q", want[i], got)
+		}
+	}
+
+	if err := IndexPorterAppNames(db, &features.Client{}, logger); err == nil {
+		t.Fatalf("expected the migration to fail while names conflict")
+	}
+
+	// the index is not created, so the conflicting apps can still be renamed one at a time
+	if err := db.Create(&models.PorterApp{ClusterID: 1, Name: "WORKER"}).Error; err != nil {
+		t.Errorf("expected no index after a failed migration, got %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package populate_porter_app_source_types
+
+import (
+	"github.com/porter-dev/porter/internal/features"
+	"github.com/porter-dev/porter/internal/models"
+	lr "github.com/porter-dev/porter/pkg/logger"
+	_gorm "gorm.io/gorm"
+)
+
+// PopulatePorterAppSourceTypes sets the source type of the apps created before apps had one, including deleted apps,
+// inferring it from the build settings and image repository the apps were created with
+func PopulatePorterAppSourceTypes(db *_gorm.DB, _ *features.Client, logger *lr.Logger) error {
+	logger.Info().Msg("starting to populate source types for existing porter apps")
+
+	var apps []*models.PorterApp
+
+	if err := db.Unscoped().Where("source_type IS NULL OR source_type = ''").Find(&apps).Error; err != nil {
+		logger.Error().Msgf("failed to get porter apps: %v", err)
+		return err
+	}
+
+	for _, app := range apps {
+		// the column is updated directly, since saving the app would also overwrite its other fields
+		if err := db.Unscoped().Model(app).UpdateColumn("source_type", app.InferSourceType()).Error; err != nil {
+			logger.Error().Msgf("failed to update porter app ID %d: %v", app.ID, err)
+			return err
+		}
+	}
+
+	logger.Info().Msgf("porter app source types migration completed, %d apps updated", len(apps))
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package populate_porter_app_source_types
+
+import (
+	"os"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/features"
+	"github.com/porter-dev/porter/internal/models"
+	lr "github.com/porter-dev/porter/pkg/logger"
+)
+
+func TestPopulatePorterAppSourceTypes(t *testing.T) {
+	logger := lr.NewConsole(true)
+	dbFileName := "./porter_app_source_types.db"
+
+	db, err := adapter.New(&env.DBConf{
+		EncryptionKey: "__random_strong_encryption_key__",
+		SQLLite:       true,
+		SQLLitePath:   dbFileName,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer os.Remove(dbFileName)
+
+	if err := db.AutoMigrate(&models.PorterApp{}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	apps := []*models.PorterApp{
+		{Name: "storefront", RepoName: "acme/storefront", GitRepoID: 12, Builder: "heroku/buildpacks:20", ImageRepoURI: "registry.example.com/storefront"},
+		{Name: "tool", ImageRepoURI: "ghcr.io/acme/tool"},
+		// an app which is already recorded as deploying an image is left as it is
+		{Name: "proxy", ImageRepoURI: "envoyproxy/envoy", Dockerfile: "./Dockerfile", SourceType: types.PorterAppSourceType_Image},
+		{Name: "worker"},
+	}
+	for _, app := range apps {
+		if err := db.Create(app).Error; err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+	if err := db.Where("name = ?", "tool").Delete(&models.PorterApp{}).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := PopulatePorterAppSourceTypes(db, &features.Client{}, logger); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	var got []*models.PorterApp
+	if err := db.Unscoped().Order("id").Find(&got).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	want := []types.PorterAppSourceType{
+		types.PorterAppSourceType_Build,
+		types.PorterAppSourceType_Image,
+		types.PorterAppSourceType_Image,
+		types.PorterAppSourceType_Build,
+	}
+	for i, app := range got {
+		if app.SourceType != want[i] {
+			t.Errorf("expected app %s to have source type %s, got %s", app.Name, want[i], app.SourceType)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package populate_porter_app_uuids
+
+import (
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/features"
+	"github.com/porter-dev/porter/internal/models"
+	lr "github.com/porter-dev/porter/pkg/logger"
+	_gorm "gorm.io/gorm"
+)
+
+// PopulatePorterAppUUIDs sets the UUID of the apps created before apps had one, including deleted apps, so that their
+// events can be looked up by it
+func PopulatePorterAppUUIDs(db *_gorm.DB, _ *features.Client, logger *lr.Logger) error {
+	logger.Info().Msg("starting to populate uuids for existing porter apps")
+
+	var apps []*models.PorterApp
+
+	if err := db.Unscoped().Where("uuid IS NULL").Find(&apps).Error; err != nil {
+		logger.Error().Msgf("failed to get porter apps: %v", err)
+		return err
+	}
+
+	for _, app := range apps {
+		// the column is updated directly, since saving the app would also overwrite its other fields
+		if err := db.Unscoped().Model(app).UpdateColumn("uuid", uuid.New()).Error; err != nil {
+			logger.Error().Msgf("failed to update porter app ID %d: %v", app.ID, err)
+			return err
+		}
+	}
+
+	logger.Info().Msgf("porter app uuids migration completed, %d apps updated", len(apps))
+
+	return nil
+}
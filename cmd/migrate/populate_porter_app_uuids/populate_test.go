@@ -0,0 +1,63 @@
+package populate_porter_app_uuids
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/features"
+	"github.com/porter-dev/porter/internal/models"
+	lr "github.com/porter-dev/porter/pkg/logger"
+)
+
+func TestPopulatePorterAppUUIDs(t *testing.T) {
+	logger := lr.NewConsole(true)
+	dbFileName := "./porter_app_uuids.db"
+
+	db, err := adapter.New(&env.DBConf{
+		EncryptionKey: "__random_strong_encryption_key__",
+		SQLLite:       true,
+		SQLLitePath:   dbFileName,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer os.Remove(dbFileName)
+
+	if err := db.AutoMigrate(&models.PorterApp{}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	existing := uuid.New()
+	if err := db.Create(&models.PorterApp{Name: "storefront", UUID: existing}).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	// apps created before the column was added have no uuid
+	if err := db.Create(&models.PorterApp{Name: "worker"}).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := db.Model(&models.PorterApp{}).Where("name = ?", "worker").UpdateColumn("uuid", nil).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := db.Where("name = ?", "worker").Delete(&models.PorterApp{}).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := PopulatePorterAppUUIDs(db, &features.Client{}, logger); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	var apps []*models.PorterApp
+	if err := db.Unscoped().Order("id").Find(&apps).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if apps[0].UUID != existing {
+		t.Errorf("expected the existing uuid %s to be kept, got %s", existing, apps[0].UUID)
+	}
+	if apps[1].UUID == uuid.Nil {
+		t.Errorf("expected the deleted app to be given a uuid")
+	}
+}
@@ -2,13 +2,16 @@ package startup_migrations
 
 import (
 	"github.com/porter-dev/porter/cmd/migrate/enable_cluster_preview_envs"
+	"github.com/porter-dev/porter/cmd/migrate/index_porter_app_names"
+	"github.com/porter-dev/porter/cmd/migrate/populate_porter_app_source_types"
+	"github.com/porter-dev/porter/cmd/migrate/populate_porter_app_uuids"
 	"github.com/porter-dev/porter/internal/features"
 	lr "github.com/porter-dev/porter/pkg/logger"
 	"gorm.io/gorm"
 )
 
 // this should be incremented with every new startup migration script
-const LatestMigrationVersion uint = 1
+const LatestMigrationVersion uint = 4
 
 type migrationFunc func(db *gorm.DB, config *features.Client, logger *lr.Logger) error
 
@@ -16,4 +19,7 @@ var StartupMigrations = make(map[uint]migrationFunc)
 
 func init() {
 	StartupMigrations[1] = enable_cluster_preview_envs.EnableClusterPreviewEnvs
+	StartupMigrations[2] = populate_porter_app_uuids.PopulatePorterAppUUIDs
+	StartupMigrations[3] = index_porter_app_names.IndexPorterAppNames
+	StartupMigrations[4] = populate_porter_app_source_types.PopulatePorterAppSourceTypes
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/joeshaw/envdecode"
+	"github.com/porter-dev/porter/internal/kubernetes/tunnel"
+	lr "github.com/porter-dev/porter/pkg/logger"
+	"k8s.io/client-go/rest"
+)
+
+// Version will be linked by an ldflag during build
+var Version string = "dev-ce"
+
+// Conf configures the agent, which runs in the cluster it connects to the Porter server
+type Conf struct {
+	ServerURL string `env:"PORTER_SERVER_URL,required"`
+	ProjectID uint   `env:"PORTER_PROJECT_ID,required"`
+	ClusterID uint   `env:"PORTER_CLUSTER_ID,required"`
+	Token     string `env:"PORTER_TUNNEL_TOKEN,required"`
+
+	// Capabilities are the comma separated classes of requests the agent proxies: deploy, stream and exec. The agent
+	// proxies deploy and stream requests if it is not set.
+	Capabilities string `env:"PORTER_TUNNEL_CAPABILITIES"`
+
+	Debug bool `env:"DEBUG,default=false"`
+}
+
+func main() {
+	var versionFlag bool
+	flag.BoolVar(&versionFlag, "version", false, "print version and exit")
+	flag.Parse()
+
+	if versionFlag {
+		fmt.Println(Version)
+		os.Exit(0)
+	}
+
+	var conf Conf
+	if err := envdecode.StrictDecode(&conf); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to decode tunnel agent conf: %s\n", err)
+		os.Exit(1)
+	}
+
+	logger := lr.NewConsole(conf.Debug)
+
+	// the agent reaches the API server with the service account of its pod
+	restConf, err := rest.InClusterConfig()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("error reading in-cluster config")
+	}
+
+	transport, err := rest.TransportFor(restConf)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("error creating transport for the api server")
+	}
+
+	var capabilities []tunnel.Capability
+	for _, capability := range strings.Split(conf.Capabilities, ",") {
+		if capability = strings.TrimSpace(capability); capability != "" {
+			capabilities = append(capabilities, tunnel.Capability(capability))
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	agent := tunnel.NewAgent(tunnel.AgentOptions{
+		ServerURL:    conf.ServerURL,
+		ProjectID:    conf.ProjectID,
+		ClusterID:    conf.ClusterID,
+		Token:        conf.Token,
+		Version:      Version,
+		APIServerURL: restConf.Host,
+		Transport:    transport,
+		Capabilities: capabilities,
+		Logger:       logger,
+	})
+
+	logger.Info().Str("server_url", conf.ServerURL).Uint("cluster_id", conf.ClusterID).Msg("starting tunnel agent")
+
+	if err := agent.Run(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("tunnel agent stopped")
+	}
+}
@@ -0,0 +1,67 @@
+import React from "react";
+import styled from "styled-components";
+
+import Container from "components/porter/Container";
+import Icon from "components/porter/Icon";
+import Spacer from "components/porter/Spacer";
+import Text from "components/porter/Text";
+
+import { readableDate } from "shared/string_utils";
+import alert from "assets/alert-warning.svg";
+
+import { type PorterAppAlertEvent } from "../types";
+import { Code, StyledEventCard } from "./EventCard";
+
+type Props = {
+  event: PorterAppAlertEvent;
+};
+
+const AlertEventCard: React.FC<Props> = ({ event }) => {
+  return (
+    <StyledEventCard>
+      <Container row spaced>
+        <Container row>
+          <Icon height="16px" src={alert} />
+          <Spacer inline x={1} />
+          <Text>
+            Log alert <Code>{event.metadata.rule_name}</Code> fired
+            {event.metadata.service_name !== "" && (
+              <>
+                {" "}
+                for <Code>{event.metadata.service_name}</Code>
+              </>
+            )}
+          </Text>
+        </Container>
+        <Text color="helper">{readableDate(event.metadata.fired_at)}</Text>
+      </Container>
+      <Spacer y={0.5} />
+      <Text color="helper">
+        {event.metadata.match_count} lines matched{" "}
+        <Code>{event.metadata.pattern}</Code> in the last{" "}
+        {event.metadata.window_minutes} minutes (threshold{" "}
+        {event.metadata.threshold})
+      </Text>
+      {event.metadata.snippet.length !== 0 && (
+        <>
+          <Spacer y={0.5} />
+          <Snippet>{event.metadata.snippet.join("\n")}</Snippet>
+        </>
+      )}
+    </StyledEventCard>
+  );
+};
+
+export default AlertEventCard;
+
+const Snippet = styled.pre`
+  margin: 0;
+  padding: 10px;
+  border-radius: 5px;
+  background: #ffffff11;
+  font-family: monospace;
+  font-size: 12px;
+  white-space: pre-wrap;
+  word-break: break-all;
+  user-select: text;
+`;
@@ -5,8 +5,10 @@ import { match } from "ts-pattern";
 import { useLatestRevision } from "main/home/app-dashboard/app-view/LatestRevisionContext";
 
 import { type PorterAppEvent } from "../types";
+import AlertEventCard from "./AlertEventCard";
 import BuildEventCard from "./BuildEventCard";
 import DeployEventCard from "./DeployEventCard";
+import InactivityEventCard from "./InactivityEventCard";
 import PreDeployEventCard from "./PreDeployEventCard";
 
 type Props = {
@@ -37,6 +39,8 @@ const EventCard: React.FC<Props> = ({
       match(event)
         .with({ type: "APP_EVENT" }, () => "")
         .with({ type: "NOTIFICATION" }, () => "")
+        .with({ type: "ALERT" }, () => "")
+        .with({ type: "INACTIVITY" }, () => "")
         .with({ type: "BUILD" }, (event) =>
           event.metadata.commit_sha
             ? `https://www.github.com/${porterApp.repo_name}/commit/${event.metadata.commit_sha}`
@@ -73,6 +77,8 @@ const EventCard: React.FC<Props> = ({
       match(event)
         .with({ type: "APP_EVENT" }, () => "")
         .with({ type: "NOTIFICATION" }, () => "")
+        .with({ type: "ALERT" }, () => "")
+        .with({ type: "INACTIVITY" }, () => "")
         .with({ type: "BUILD" }, (event) =>
           event.metadata.commit_sha ? event.metadata.commit_sha.slice(0, 7) : ""
         )
@@ -130,6 +136,8 @@ const EventCard: React.FC<Props> = ({
       />
     ))
     .with({ type: "AUTO_ROLLBACK" }, () => null)
+    .with({ type: "ALERT" }, (ev) => <AlertEventCard event={ev} />)
+    .with({ type: "INACTIVITY" }, (ev) => <InactivityEventCard event={ev} />)
     .exhaustive();
 };
 
@@ -0,0 +1,56 @@
+import React from "react";
+
+import Container from "components/porter/Container";
+import Icon from "components/porter/Icon";
+import Spacer from "components/porter/Spacer";
+import Text from "components/porter/Text";
+
+import { readableDate } from "shared/string_utils";
+import alert from "assets/alert-warning.svg";
+import trash from "assets/trash.png";
+
+import { type PorterAppInactivityEvent } from "../types";
+import { StyledEventCard } from "./EventCard";
+
+type Props = {
+  event: PorterAppInactivityEvent;
+};
+
+const InactivityEventCard: React.FC<Props> = ({ event }) => {
+  const paused = event.metadata.action === "PAUSED";
+
+  return (
+    <StyledEventCard>
+      <Container row spaced>
+        <Container row>
+          <Icon height="16px" src={paused ? alert : trash} />
+          <Spacer inline x={1} />
+          <Text>
+            {paused
+              ? "Paused by the inactivity policy"
+              : "Deleted by the inactivity policy"}
+          </Text>
+        </Container>
+        <Text color="helper">{readableDate(event.metadata.at)}</Text>
+      </Container>
+      <Spacer y={0.5} />
+      <Text color="helper">
+        Not deployed since {readableDate(event.metadata.last_deployed_at)}, over
+        the limit of {event.metadata.idle_days} days
+        {event.metadata.daily_requests !== undefined &&
+          `, with ${event.metadata.daily_requests} requests in the last day`}
+      </Text>
+      {paused && event.metadata.delete_at && (
+        <>
+          <Spacer y={0.5} />
+          <Text color="helper">
+            Deploy the app to resume it, or it will be deleted on{" "}
+            {readableDate(event.metadata.delete_at)}
+          </Text>
+        </>
+      )}
+    </StyledEventCard>
+  );
+};
+
+export default InactivityEventCard;
@@ -4,7 +4,9 @@ export type PorterAppEventType =
   | "BUILD"
   | "DEPLOY"
   | "APP_EVENT"
-  | "PRE_DEPLOY";
+  | "PRE_DEPLOY"
+  | "ALERT"
+  | "INACTIVITY";
 
 const porterAppAppEventMetadataValidator = z.object({
   namespace: z.string(),
@@ -46,6 +48,29 @@ const porterAppPreDeployEventMetadataValidator = z.object({
   image_tag: z.string().optional(), // used by the update flow
   commit_sha: z.string().optional(), // used by the apply flow. TODO: remove this field
 });
+const porterAppAlertEventMetadataValidator = z.object({
+  rule_id: z.number(),
+  rule_name: z.string(),
+  app_name: z.string(),
+  service_name: z.string().optional().default(""),
+  match_type: z.string(),
+  pattern: z.string(),
+  threshold: z.number(),
+  window_minutes: z.number(),
+  match_count: z.number(),
+  snippet: z.array(z.string()).optional().default([]),
+  channel: z.string(),
+  fired_at: z.string(),
+});
+const porterAppInactivityEventMetadataValidator = z.object({
+  action: z.enum(["PAUSED", "DELETED"]),
+  app_name: z.string(),
+  last_deployed_at: z.string(),
+  idle_days: z.number(),
+  daily_requests: z.number().optional(),
+  delete_at: z.string().optional(),
+  at: z.string(),
+});
 
 const serviceNoticationValidator = z.object({
   id: z.string(),
@@ -200,6 +225,26 @@ export const porterAppEventValidator = z.discriminatedUnion("type", [
     porter_app_id: z.number(),
     metadata: porterAppDeployEventMetadataValidator,
   }),
+  z.object({
+    id: z.string(),
+    created_at: z.string(),
+    updated_at: z.string(),
+    status: z.string().optional().default(""),
+    type: z.literal("ALERT"),
+    type_external_source: z.string().optional().default(""),
+    porter_app_id: z.number(),
+    metadata: porterAppAlertEventMetadataValidator,
+  }),
+  z.object({
+    id: z.string(),
+    created_at: z.string(),
+    updated_at: z.string(),
+    status: z.string().optional().default(""),
+    type: z.literal("INACTIVITY"),
+    type_external_source: z.string().optional().default(""),
+    porter_app_id: z.number(),
+    metadata: porterAppInactivityEventMetadataValidator,
+  }),
 ]);
 
 export const getPorterAppEventsValidator = z
@@ -218,3 +263,7 @@ export type PorterAppNotificationEvent = PorterAppEvent & {
 export type PorterAppRollbackEvent = PorterAppEvent & {
   type: "AUTO_ROLLBACK";
 };
+export type PorterAppAlertEvent = PorterAppEvent & { type: "ALERT" };
+export type PorterAppInactivityEvent = PorterAppEvent & {
+  type: "INACTIVITY";
+};
@@ -315,9 +315,9 @@ const ExpandedApp: React.FC<Props> = ({ ...props }) => {
     setDeleting(true);
     const { appName } = props.match.params as any;
     try {
-      await api.deletePorterApp(
+      await api.deletePorterStack(
         "<token>",
-        {},
+        { delete_namespace: true },
         {
           cluster_id: currentCluster.id,
           project_id: currentProject.id,
@@ -327,19 +327,6 @@ const ExpandedApp: React.FC<Props> = ({ ...props }) => {
     } catch (err) {
       // TODO: handle error
     }
-    try {
-      await api.deleteNamespace(
-        "<token>",
-        {},
-        {
-          cluster_id: currentCluster.id,
-          id: currentProject.id,
-          namespace: `porter-stack-${appName}`,
-        }
-      );
-    } catch (err) {
-      // TODO: handle error
-    }
 
     let deleteWorkflowFile = false;
 
@@ -131,6 +131,14 @@ const DeployEventCard: React.FC<Props> = ({ event, appData, showServiceStatusDet
           <Icon height="16px" src={deploy} />
           <Spacer inline width="10px" />
           <Text>Application version no. {event.metadata?.revision}</Text>
+          {event.metadata?.rollback_to != null && (
+            <>
+              <Spacer inline width="10px" />
+              <Text color="helper">
+                Rolled back from version {event.metadata.rollback_from} to version {event.metadata.rollback_to}
+              </Text>
+            </>
+          )}
         </Container>
       </Container>
       <Spacer y={0.5} />
@@ -40,5 +40,7 @@ export interface PorterAppDeployEvent extends PorterAppEvent {
         image_tag: string;
         revision: number;
         service_deployment_metadata: Record<string, PorterAppServiceDeploymentMetadata>;
+        rollback_from?: number;
+        rollback_to?: number;
     };
 }
\ No newline at end of file
@@ -245,6 +245,20 @@ const deletePorterApp = baseApi<
   return `/api/projects/${project_id}/clusters/${cluster_id}/applications/${name}`;
 });
 
+const deletePorterStack = baseApi<
+  {
+    delete_namespace: boolean;
+  },
+  {
+    project_id: number;
+    cluster_id: number;
+    name: string;
+  }
+>("DELETE", (pathParams) => {
+  const { project_id, cluster_id, name } = pathParams;
+  return `/api/projects/${project_id}/clusters/${cluster_id}/stacks/${name}`;
+});
+
 const rollbackPorterApp = baseApi<
   {
     revision: number;
@@ -3519,6 +3533,7 @@ export default {
   getPorterAppEvent,
   createPorterApp,
   deletePorterApp,
+  deletePorterStack,
   rollbackPorterApp,
   createSecretAndOpenGitHubPullRequest,
   getLogsWithinTimeRange,
@@ -0,0 +1,290 @@
+package adapter
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	"golang.org/x/sync/singleflight"
+)
+
+// cacheKeyPrefix is prepended to every cache key, so that cache entries can share a redis instance with other data
+const cacheKeyPrefix = "porter:cache:v1"
+
+// CacheResource is the kind of result stored in the cache
+type CacheResource string
+
+const (
+	// CacheResource_RegistryTags is the list of image tags in a registry repository
+	CacheResource_RegistryTags CacheResource = "registry-tags"
+	// CacheResource_HelmRepoIndex is the index.yaml of a helm repo
+	CacheResource_HelmRepoIndex CacheResource = "helm-repo-index"
+)
+
+// CacheKey identifies a cache entry. Every entry is namespaced by the project and the integration, such as the
+// registry or helm repo, which it was read with, so that one project can never be served another project's results.
+type CacheKey struct {
+	ProjectID uint
+	Resource  CacheResource
+	// IntegrationID is the ID of the registry or helm repo the entry was read from
+	IntegrationID uint
+	// Name distinguishes entries for the same integration, such as the repository in a registry
+	Name string
+}
+
+func (k CacheKey) String() string {
+	return fmt.Sprintf("%s:project:%d:%s:%d:%s", cacheKeyPrefix, k.ProjectID, k.Resource, k.IntegrationID, k.Name)
+}
+
+// CacheScope selects the cache entries to invalidate. Every entry in the project for the resource is selected when
+// IntegrationID is zero, every entry for the integration is selected when Name is empty, and otherwise only the
+// entry with the given name is selected.
+type CacheScope struct {
+	ProjectID     uint
+	Resource      CacheResource
+	IntegrationID uint
+	Name          string
+}
+
+func (s CacheScope) prefix() string {
+	prefix := fmt.Sprintf("%s:project:%d:%s:", cacheKeyPrefix, s.ProjectID, s.Resource)
+	if s.IntegrationID == 0 {
+		return prefix
+	}
+
+	return fmt.Sprintf("%s%d:", prefix, s.IntegrationID)
+}
+
+// cacheStore is the storage backing a Cache
+type cacheStore interface {
+	get(ctx context.Context, key string) ([]byte, bool, error)
+	set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// delete removes the entry with the key, returning the number of entries removed
+	delete(ctx context.Context, key string) (int, error)
+	// deletePrefix removes every entry with a key starting with prefix, returning the number of entries removed
+	deletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+// Cache stores the results of slow calls to external services, such as listing registry tags. It is backed by redis
+// so that entries survive restarts and are shared across replicas, or by an in-memory LRU when redis is not enabled.
+type Cache struct {
+	store cacheStore
+	group singleflight.Group
+}
+
+// NewRedisCache returns a Cache backed by redis
+func NewRedisCache(client *redis.Client) *Cache {
+	return &Cache{store: &redisCacheStore{client: client}}
+}
+
+// NewLRUCache returns an in-memory Cache holding at most size entries
+func NewLRUCache(size int) *Cache {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &Cache{store: &lruCacheStore{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}}
+}
+
+// GetOrFill returns the cached value for the key, calling fill and caching its result for ttl if there is no entry.
+// Concurrent calls for the same key in this process share a single call to fill. A nil cache always calls fill, and
+// errors reading from or writing to the cache are not returned, so that a cache outage only makes calls slower.
+func GetOrFill[T any](ctx context.Context, c *Cache, key CacheKey, ttl time.Duration, fill func() (T, error)) (T, error) {
+	if c == nil {
+		return fill()
+	}
+
+	k := key.String()
+
+	var cached T
+	if data, ok, err := c.store.get(ctx, k); err == nil && ok {
+		if err := json.Unmarshal(data, &cached); err == nil {
+			return cached, nil
+		}
+	}
+
+	res, err, _ := c.group.Do(k, func() (interface{}, error) {
+		value, err := fill()
+		if err != nil {
+			return value, err
+		}
+
+		if data, err := json.Marshal(value); err == nil {
+			_ = c.store.set(ctx, k, data, ttl)
+		}
+
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	value, _ := res.(T)
+
+	return value, nil
+}
+
+// Invalidate removes every entry in the scope, returning the number of entries removed
+func (c *Cache) Invalidate(ctx context.Context, scope CacheScope) (int, error) {
+	if c == nil {
+		return 0, nil
+	}
+	if scope.ProjectID == 0 || scope.Resource == "" {
+		return 0, errors.New("project id and resource are required to invalidate the cache")
+	}
+
+	if scope.IntegrationID != 0 && scope.Name != "" {
+		return c.store.delete(ctx, CacheKey(scope).String())
+	}
+
+	return c.store.deletePrefix(ctx, scope.prefix())
+}
+
+type redisCacheStore struct {
+	client *redis.Client
+}
+
+func (s *redisCacheStore) get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := s.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+func (s *redisCacheStore) set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *redisCacheStore) delete(ctx context.Context, key string) (int, error) {
+	n, err := s.client.Del(ctx, key).Result()
+	return int(n), err
+}
+
+func (s *redisCacheStore) deletePrefix(ctx context.Context, prefix string) (int, error) {
+	deleted := 0
+
+	iter := s.client.Scan(ctx, 0, escapeRedisPattern(prefix)+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		n, err := s.client.Del(ctx, iter.Val()).Result()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += int(n)
+	}
+
+	return deleted, iter.Err()
+}
+
+// escapeRedisPattern escapes the glob characters understood by SCAN MATCH, so that the prefix is matched literally
+func escapeRedisPattern(p string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(p)
+}
+
+type lruCacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+type lruCacheStore struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// order holds the most recently used entry at the front
+	order *list.List
+}
+
+func (s *lruCacheStore) get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := elem.Value.(*lruCacheEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		s.order.Remove(elem)
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(elem)
+
+	return entry.value, true, nil
+}
+
+func (s *lruCacheStore) set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := s.entries[key]; ok {
+		elem.Value = &lruCacheEntry{key: key, value: value, expiresAt: expiresAt}
+		s.order.MoveToFront(elem)
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&lruCacheEntry{key: key, value: value, expiresAt: expiresAt})
+
+	for s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*lruCacheEntry).key)
+	}
+
+	return nil
+}
+
+func (s *lruCacheStore) delete(_ context.Context, key string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return 0, nil
+	}
+
+	s.order.Remove(elem)
+	delete(s.entries, key)
+
+	return 1, nil
+}
+
+func (s *lruCacheStore) deletePrefix(_ context.Context, prefix string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for key, elem := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			s.order.Remove(elem)
+			delete(s.entries, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,228 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrFillCachesResult(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(10)
+	key := CacheKey{ProjectID: 1, Resource: CacheResource_RegistryTags, IntegrationID: 2, Name: "web"}
+
+	var calls int32
+	fill := func() ([]string, error) {
+		atomic.AddInt32(&calls, 1)
+		return []string{"v1", "v2"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		tags, err := GetOrFill(ctx, cache, key, time.Minute, fill)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tags) != 2 || tags[1] != "v2" {
+			t.Fatalf("expected cached tags, got %v", tags)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected fill to be called once, got %d", calls)
+	}
+}
+
+func TestGetOrFillSharesConcurrentFills(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(10)
+	key := CacheKey{ProjectID: 1, Resource: CacheResource_HelmRepoIndex, IntegrationID: 3}
+
+	var calls int32
+	fill := func() (string, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(50 * time.Millisecond)
+		return "index", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			res, err := GetOrFill(ctx, cache, key, time.Minute, fill)
+			if err != nil || res != "index" {
+				t.Errorf("expected index, got %q, %v", res, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected 50 concurrent reads to share a single fill, got %d", calls)
+	}
+}
+
+func TestGetOrFillExpiresAndSkipsErrors(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(10)
+	key := CacheKey{ProjectID: 1, Resource: CacheResource_RegistryTags, IntegrationID: 2, Name: "web"}
+
+	if _, err := GetOrFill(ctx, cache, key, time.Minute, func() (string, error) {
+		return "", errors.New("registry unavailable")
+	}); err == nil {
+		t.Fatal("expected fill error to be returned")
+	}
+
+	// the error was not cached, so the next read fills again
+	res, err := GetOrFill(ctx, cache, key, 10*time.Millisecond, func() (string, error) { return "v1", nil })
+	if err != nil || res != "v1" {
+		t.Fatalf("expected v1, got %q, %v", res, err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	res, err = GetOrFill(ctx, cache, key, time.Minute, func() (string, error) { return "v2", nil })
+	if err != nil || res != "v2" {
+		t.Fatalf("expected the expired entry to be refilled, got %q, %v", res, err)
+	}
+}
+
+func TestGetOrFillWithoutCache(t *testing.T) {
+	var calls int
+	for i := 0; i < 2; i++ {
+		_, _ = GetOrFill(context.Background(), nil, CacheKey{}, time.Minute, func() (int, error) {
+			calls++
+			return calls, nil
+		})
+	}
+
+	if calls != 2 {
+		t.Errorf("expected a nil cache to always fill, got %d calls", calls)
+	}
+}
+
+func TestCacheEntriesAreNamespacedByProject(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(100)
+
+	// projects 1 and 12 both have a registry with ID 5 and a repository called web, which must never be confused,
+	// including when one of the project IDs is a prefix of the other
+	for _, projectID := range []uint{1, 12} {
+		projectID := projectID
+		for _, resource := range []CacheResource{CacheResource_RegistryTags, CacheResource_HelmRepoIndex} {
+			key := CacheKey{ProjectID: projectID, Resource: resource, IntegrationID: 5, Name: "web"}
+			if _, err := GetOrFill(ctx, cache, key, time.Minute, func() (string, error) {
+				return fmt.Sprintf("project-%d", projectID), nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	read := func(projectID uint, resource CacheResource) string {
+		res, _ := GetOrFill(ctx, cache, CacheKey{ProjectID: projectID, Resource: resource, IntegrationID: 5, Name: "web"}, time.Minute, func() (string, error) {
+			return "refilled", nil
+		})
+		return res
+	}
+
+	if got := read(1, CacheResource_RegistryTags); got != "project-1" {
+		t.Fatalf("expected project 1 to read its own entry, got %q", got)
+	}
+	if got := read(12, CacheResource_RegistryTags); got != "project-12" {
+		t.Fatalf("expected project 12 to read its own entry, got %q", got)
+	}
+
+	// invalidating project 1 only removes project 1's registry tags
+	deleted, err := cache.Invalidate(ctx, CacheScope{ProjectID: 1, Resource: CacheResource_RegistryTags})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 entry to be removed, got %d", deleted)
+	}
+
+	if got := read(1, CacheResource_RegistryTags); got != "refilled" {
+		t.Errorf("expected project 1's registry tags to be refilled, got %q", got)
+	}
+	if got := read(1, CacheResource_HelmRepoIndex); got != "project-1" {
+		t.Errorf("expected project 1's helm repo index to be kept, got %q", got)
+	}
+	if got := read(12, CacheResource_RegistryTags); got != "project-12" {
+		t.Errorf("expected project 12's registry tags to be kept, got %q", got)
+	}
+
+	if _, err := cache.Invalidate(ctx, CacheScope{Resource: CacheResource_RegistryTags}); err == nil {
+		t.Error("expected invalidation without a project to be rejected")
+	}
+}
+
+func TestCacheInvalidateByIntegrationAndName(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(100)
+
+	keys := []CacheKey{
+		{ProjectID: 1, Resource: CacheResource_RegistryTags, IntegrationID: 2, Name: "web"},
+		{ProjectID: 1, Resource: CacheResource_RegistryTags, IntegrationID: 2, Name: "web-worker"},
+		{ProjectID: 1, Resource: CacheResource_RegistryTags, IntegrationID: 2, Name: "api"},
+		{ProjectID: 1, Resource: CacheResource_RegistryTags, IntegrationID: 20, Name: "web"},
+	}
+	for _, key := range keys {
+		if _, err := GetOrFill(ctx, cache, key, time.Minute, func() (bool, error) { return true, nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a name only removes that repository, not others sharing its prefix
+	deleted, err := cache.Invalidate(ctx, CacheScope{ProjectID: 1, Resource: CacheResource_RegistryTags, IntegrationID: 2, Name: "web"})
+	if err != nil || deleted != 1 {
+		t.Fatalf("expected 1 entry to be removed, got %d, %v", deleted, err)
+	}
+
+	// an integration removes every repository in it, but not in other integrations
+	deleted, err = cache.Invalidate(ctx, CacheScope{ProjectID: 1, Resource: CacheResource_RegistryTags, IntegrationID: 2})
+	if err != nil || deleted != 2 {
+		t.Fatalf("expected 2 entries to be removed, got %d, %v", deleted, err)
+	}
+
+	deleted, err = cache.Invalidate(ctx, CacheScope{ProjectID: 1, Resource: CacheResource_RegistryTags})
+	if err != nil || deleted != 1 {
+		t.Fatalf("expected the last entry to be removed, got %d, %v", deleted, err)
+	}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUCache(2).store
+
+	_ = store.set(ctx, "a", []byte("a"), 0)
+	_ = store.set(ctx, "b", []byte("b"), 0)
+
+	// reading a makes b the least recently used entry
+	if _, ok, _ := store.get(ctx, "a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	_ = store.set(ctx, "c", []byte("c"), 0)
+
+	if _, ok, _ := store.get(ctx, "b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := store.get(ctx, key); !ok {
+			t.Errorf("expected %s to be cached", key)
+		}
+	}
+}
+
+func TestEscapeRedisPattern(t *testing.T) {
+	got := escapeRedisPattern(`porter:cache:v1:project:1:registry-tags:2:web*[a]?\`)
+	want := `porter:cache:v1:project:1:registry-tags:2:web\*\[a\]\?\\`
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
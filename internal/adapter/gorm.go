@@ -1,6 +1,7 @@
 package adapter
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -36,18 +37,9 @@ func New(conf *env.DBConf) (*gorm.DB, error) {
 	}
 
 	// connect to default postgres instance first
-	baseDSN := fmt.Sprintf(
-		"user=%s password=%s port=%d host=%s",
-		conf.Username,
-		conf.Password,
-		conf.Port,
-		conf.Host,
-	)
-
-	if conf.ForceSSL {
-		baseDSN = baseDSN + " sslmode=require"
-	} else {
-		baseDSN = baseDSN + " sslmode=disable"
+	baseDSN, err := postgresBaseDSN(conf)
+	if err != nil {
+		return nil, err
 	}
 
 	postgresDSN := baseDSN + " database=postgres"
@@ -95,3 +87,68 @@ func New(conf *env.DBConf) (*gorm.DB, error) {
 
 	return res, err
 }
+
+const (
+	sslModeDisable    = "disable"
+	sslModeRequire    = "require"
+	sslModeVerifyFull = "verify-full"
+)
+
+// postgresBaseDSN builds the connection string shared by every postgres connection, without a database name.
+// An error is returned if the TLS settings are invalid or reference certificate files which do not exist.
+func postgresBaseDSN(conf *env.DBConf) (string, error) {
+	sslMode := conf.SSLMode
+	if sslMode == "" {
+		sslMode = sslModeRequire
+	}
+
+	if conf.ForceSSL && sslMode == sslModeDisable {
+		sslMode = sslModeRequire
+	}
+
+	switch sslMode {
+	case sslModeDisable, sslModeRequire, sslModeVerifyFull:
+	default:
+		return "", fmt.Errorf("invalid database ssl mode %q: must be one of %s, %s, %s", sslMode, sslModeDisable, sslModeRequire, sslModeVerifyFull)
+	}
+
+	if (conf.SSLCertPath == "") != (conf.SSLKeyPath == "") {
+		return "", errors.New("database ssl client cert and key must be set together")
+	}
+
+	dsn := fmt.Sprintf(
+		"user=%s password=%s port=%d host=%s sslmode=%s",
+		conf.Username,
+		conf.Password,
+		conf.Port,
+		conf.Host,
+		sslMode,
+	)
+
+	certFiles := []struct {
+		param string
+		path  string
+	}{
+		{"sslrootcert", conf.SSLRootCertPath},
+		{"sslcert", conf.SSLCertPath},
+		{"sslkey", conf.SSLKeyPath},
+	}
+
+	for _, certFile := range certFiles {
+		if certFile.path == "" {
+			continue
+		}
+
+		if sslMode == sslModeDisable {
+			return "", fmt.Errorf("database %s is set but ssl mode is %s", certFile.param, sslModeDisable)
+		}
+
+		if _, err := os.Stat(certFile.path); err != nil {
+			return "", fmt.Errorf("error reading database %s: %w", certFile.param, err)
+		}
+
+		dsn += fmt.Sprintf(" %s=%s", certFile.param, certFile.path)
+	}
+
+	return dsn, nil
+}
@@ -0,0 +1,104 @@
+package adapter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+)
+
+func TestPostgresBaseDSN(t *testing.T) {
+	dir := t.TempDir()
+
+	caPath := filepath.Join(dir, "ca.crt")
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+
+	for _, path := range []string{caPath, certPath, keyPath} {
+		if err := os.WriteFile(path, []byte("test"), 0o600); err != nil {
+			t.Fatalf("error writing %s: %v", path, err)
+		}
+	}
+
+	base := "user=porter password=pass port=5432 host=postgres"
+
+	tests := []struct {
+		name    string
+		conf    env.DBConf
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "defaults to require",
+			conf: env.DBConf{},
+			want: base + " sslmode=require",
+		},
+		{
+			name: "disable",
+			conf: env.DBConf{SSLMode: "disable"},
+			want: base + " sslmode=disable",
+		},
+		{
+			name: "force ssl upgrades disable",
+			conf: env.DBConf{SSLMode: "disable", ForceSSL: true},
+			want: base + " sslmode=require",
+		},
+		{
+			name: "verify-full with mtls",
+			conf: env.DBConf{
+				SSLMode:         "verify-full",
+				SSLRootCertPath: caPath,
+				SSLCertPath:     certPath,
+				SSLKeyPath:      keyPath,
+			},
+			want: base + " sslmode=verify-full sslrootcert=" + caPath + " sslcert=" + certPath + " sslkey=" + keyPath,
+		},
+		{
+			name:    "invalid ssl mode",
+			conf:    env.DBConf{SSLMode: "prefer"},
+			wantErr: true,
+		},
+		{
+			name:    "missing root cert",
+			conf:    env.DBConf{SSLMode: "verify-full", SSLRootCertPath: filepath.Join(dir, "missing.crt")},
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			conf:    env.DBConf{SSLCertPath: certPath},
+			wantErr: true,
+		},
+		{
+			name:    "certs with ssl disabled",
+			conf:    env.DBConf{SSLMode: "disable", SSLRootCertPath: caPath},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := tt.conf
+			conf.Username = "porter"
+			conf.Password = "pass"
+			conf.Port = 5432
+			conf.Host = "postgres"
+
+			got, err := postgresBaseDSN(&conf)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got dsn %q", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected dsn %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
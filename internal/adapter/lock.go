@@ -0,0 +1,126 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	"github.com/porter-dev/porter/internal/random"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// lockKeyPrefix is prepended to every lock name stored in redis
+const lockKeyPrefix = "porter:lock:v1:"
+
+const (
+	// lockPollInterval is how often a held lock is retried while waiting for it
+	lockPollInterval = 100 * time.Millisecond
+	// lockReleaseTimeout bounds the time spent releasing a lock, which is done after the request it was taken for
+	lockReleaseTimeout = 5 * time.Second
+)
+
+// ErrLockNotAcquired is returned when a lock is still held by another holder after waiting for it
+var ErrLockNotAcquired = errors.New("lock is held by another holder")
+
+// lockStore is the storage backing a Locker
+type lockStore interface {
+	// acquire takes the lock with the name for the holder with the token, returning false if it is held
+	acquire(ctx context.Context, name, token string, ttl time.Duration) (bool, error)
+	// release releases the lock with the name, if it is held by the holder with the token
+	release(ctx context.Context, name, token string) error
+}
+
+// Locker takes advisory locks which are shared by every replica of the server, such as the lock held while a stack is
+// deployed. It is backed by redis when redis is enabled, or by the database otherwise.
+type Locker struct {
+	store lockStore
+}
+
+// NewRedisLocker returns a Locker backed by redis
+func NewRedisLocker(client *redis.Client) *Locker {
+	return &Locker{store: &redisLockStore{client: client}}
+}
+
+// NewDBLocker returns a Locker backed by the locks table of the database
+func NewDBLocker(repo repository.LockRepository) *Locker {
+	return &Locker{store: &dbLockStore{repo: repo}}
+}
+
+// Acquire takes the lock with the name, waiting up to wait for another holder to release it, and returns the function
+// releasing it. The lock expires after ttl if it is not released, so that a server which goes away does not hold it
+// forever. ErrLockNotAcquired is returned if the lock is still held after waiting. A nil locker does not lock.
+func (l *Locker) Acquire(ctx context.Context, name string, ttl, wait time.Duration) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	token, err := random.StringWithCharset(32, "")
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(wait)
+
+	for {
+		ok, err := l.store.acquire(ctx, name, token, ttl)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return func() {
+				// the lock is released after the request it was taken for, whose context may be done
+				ctx, cancel := context.WithTimeout(context.Background(), lockReleaseTimeout)
+				defer cancel()
+
+				_ = l.store.release(ctx, name, token)
+			}, nil
+		}
+
+		if !time.Now().Add(lockPollInterval).Before(deadline) {
+			return nil, ErrLockNotAcquired
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+type redisLockStore struct {
+	client *redis.Client
+}
+
+// redisReleaseScript deletes a lock only if it is still held by the token, so that a holder whose lock expired cannot
+// release the lock of the holder which took it over
+var redisReleaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+
+func (s *redisLockStore) acquire(ctx context.Context, name, token string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, lockKeyPrefix+name, token, ttl).Result()
+}
+
+func (s *redisLockStore) release(ctx context.Context, name, token string) error {
+	return redisReleaseScript.Run(ctx, s.client, []string{lockKeyPrefix + name}, token).Err()
+}
+
+type dbLockStore struct {
+	repo repository.LockRepository
+}
+
+func (s *dbLockStore) acquire(ctx context.Context, name, token string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+
+	return s.repo.AcquireLock(ctx, name, token, now, now.Add(ttl))
+}
+
+func (s *dbLockStore) release(ctx context.Context, name, token string) error {
+	return s.repo.ReleaseLock(ctx, name, token)
+}
@@ -0,0 +1,78 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+func TestLockerAllowsOneHolder(t *testing.T) {
+	ctx := context.Background()
+	locker := NewDBLocker(test.NewLockRepository(true))
+
+	var acquired, rejected int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := locker.Acquire(ctx, "deploy", time.Minute, 0)
+			switch {
+			case err == nil:
+				atomic.AddInt32(&acquired, 1)
+			case errors.Is(err, ErrLockNotAcquired):
+				atomic.AddInt32(&rejected, 1)
+			default:
+				t.Errorf("unexpected error acquiring lock: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if acquired != 1 || rejected != 9 {
+		t.Errorf("expected one holder to acquire the lock, got %d acquired and %d rejected", acquired, rejected)
+	}
+}
+
+func TestLockerWaitsForRelease(t *testing.T) {
+	ctx := context.Background()
+	locker := NewDBLocker(test.NewLockRepository(true))
+
+	release, err := locker.Acquire(ctx, "deploy", time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		release()
+	}()
+
+	releaseSecond, err := locker.Acquire(ctx, "deploy", time.Minute, 2*time.Second)
+	if err != nil {
+		t.Fatalf("expected the lock to be acquired once released, got %v", err)
+	}
+	releaseSecond()
+
+	if _, err := locker.Acquire(ctx, "deploy", time.Minute, 0); err != nil {
+		t.Errorf("expected a released lock to be free, got %v", err)
+	}
+}
+
+func TestNilLockerDoesNotLock(t *testing.T) {
+	var locker *Locker
+
+	for i := 0; i < 2; i++ {
+		release, err := locker.Acquire(context.Background(), "deploy", time.Minute, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		release()
+	}
+}
@@ -0,0 +1,278 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+)
+
+// loginLimitKeyPrefix is prepended to every login limit counter stored in redis
+const loginLimitKeyPrefix = "porter:login:v1:"
+
+// memoryLoginLimitSweepSize is the number of counters held in memory above which expired counters are removed when a
+// counter is added
+const memoryLoginLimitSweepSize = 10000
+
+// LoginLimitOptions configure the limits of a LoginLimiter. A zero limit or threshold disables it.
+type LoginLimitOptions struct {
+	// Window is the period over which attempts are counted for the per-email and per-IP limits
+	Window time.Duration
+	// MaxAttemptsPerEmail is the number of attempts allowed for an email within the window
+	MaxAttemptsPerEmail int
+	// MaxAttemptsPerIP is the number of attempts allowed from an IP address within the window
+	MaxAttemptsPerIP int
+	// LockoutThreshold is the number of consecutive failed attempts after which an email is locked out
+	LockoutThreshold int
+	// LockoutDuration is how long an email is locked out for, and how long its failed attempts are remembered
+	LockoutDuration time.Duration
+	// Scope separates the counters of limiters which share a store, such as the login limiter and the limiter of
+	// another endpoint backed by the same redis. The counters of the login limiter have no scope.
+	Scope string
+}
+
+// loginLimitStore holds the counters of a LoginLimiter. Counters expire a ttl after they are first incremented.
+type loginLimitStore interface {
+	// incr increments the counter with the key and returns its count and the time until it expires
+	incr(ctx context.Context, key string, ttl time.Duration) (int64, time.Duration, error)
+	// get returns the count of the counter with the key and the time until it expires, or zero if it is not set
+	get(ctx context.Context, key string) (int64, time.Duration, error)
+	// delete removes the counter with the key
+	delete(ctx context.Context, key string) error
+}
+
+// LoginLimiter limits the login attempts made for each email and from each IP address, and locks out emails after
+// too many consecutive failed attempts. It is backed by redis when redis is enabled, so that limits are shared by every
+// replica of the server, or by memory otherwise.
+type LoginLimiter struct {
+	store loginLimitStore
+	opts  LoginLimitOptions
+}
+
+// NewRedisLoginLimiter returns a LoginLimiter backed by redis
+func NewRedisLoginLimiter(client *redis.Client, opts LoginLimitOptions) *LoginLimiter {
+	return &LoginLimiter{store: &redisLoginLimitStore{client: client}, opts: opts}
+}
+
+// NewMemoryLoginLimiter returns a LoginLimiter backed by memory, whose limits are only enforced within a single server
+func NewMemoryLoginLimiter(opts LoginLimitOptions) *LoginLimiter {
+	return &LoginLimiter{store: &memoryLoginLimitStore{counters: make(map[string]memoryLoginLimitCounter)}, opts: opts}
+}
+
+// Allow counts a login attempt for the email from the IP address. It returns the time to wait before trying again if
+// the email is locked out or the attempt is over a limit, or zero if the attempt is allowed. A nil limiter allows
+// every attempt.
+func (l *LoginLimiter) Allow(ctx context.Context, email, ip string) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+
+	email = normalizeLoginEmail(email)
+
+	if l.opts.LockoutThreshold > 0 {
+		locked, ttl, err := l.store.get(ctx, l.key(loginLockoutKey(email)))
+		if err != nil {
+			return 0, fmt.Errorf("error reading login lockout: %w", err)
+		}
+		if locked > 0 {
+			return ttl, nil
+		}
+	}
+
+	var retryAfter time.Duration
+
+	limits := []struct {
+		key string
+		max int
+	}{
+		{key: "email:" + email, max: l.opts.MaxAttemptsPerEmail},
+		{key: "ip:" + ip, max: l.opts.MaxAttemptsPerIP},
+	}
+
+	for _, limit := range limits {
+		if limit.max <= 0 || l.opts.Window <= 0 {
+			continue
+		}
+
+		count, ttl, err := l.store.incr(ctx, l.key("attempts:"+limit.key), l.opts.Window)
+		if err != nil {
+			return 0, fmt.Errorf("error counting login attempts: %w", err)
+		}
+		if count > int64(limit.max) && ttl > retryAfter {
+			retryAfter = ttl
+		}
+	}
+
+	return retryAfter, nil
+}
+
+// Fail records a failed login for the email, and locks the email out once it has failed the threshold number of times
+// in a row
+func (l *LoginLimiter) Fail(ctx context.Context, email string) error {
+	if l == nil || l.opts.LockoutThreshold <= 0 || l.opts.LockoutDuration <= 0 {
+		return nil
+	}
+
+	email = normalizeLoginEmail(email)
+
+	failures, _, err := l.store.incr(ctx, l.key("failures:"+email), l.opts.LockoutDuration)
+	if err != nil {
+		return fmt.Errorf("error counting failed logins: %w", err)
+	}
+	if failures < int64(l.opts.LockoutThreshold) {
+		return nil
+	}
+
+	if _, _, err := l.store.incr(ctx, l.key(loginLockoutKey(email)), l.opts.LockoutDuration); err != nil {
+		return fmt.Errorf("error locking out email: %w", err)
+	}
+
+	// the failures are counted again from zero once the lockout ends
+	return l.store.delete(ctx, l.key("failures:"+email))
+}
+
+// Succeed resets the failed logins of the email after a successful login
+func (l *LoginLimiter) Succeed(ctx context.Context, email string) error {
+	if l == nil {
+		return nil
+	}
+
+	return l.store.delete(ctx, l.key("failures:"+normalizeLoginEmail(email)))
+}
+
+// key returns the key of a counter in the store, prefixed by the scope of the limiter
+func (l *LoginLimiter) key(key string) string {
+	if l.opts.Scope == "" {
+		return key
+	}
+
+	return l.opts.Scope + ":" + key
+}
+
+func loginLockoutKey(email string) string {
+	return "lockout:" + email
+}
+
+// normalizeLoginEmail returns the email which attempts are counted for, so that the limits of an email cannot be
+// avoided by changing its case
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+type redisLoginLimitStore struct {
+	client *redis.Client
+}
+
+// redisLoginLimitIncrScript increments a counter and sets its expiry when it is created, returning its count and the
+// milliseconds until it expires
+var redisLoginLimitIncrScript = redis.NewScript(`
+local count = redis.call("incr", KEYS[1])
+if count == 1 then
+	redis.call("pexpire", KEYS[1], ARGV[1])
+end
+return {count, redis.call("pttl", KEYS[1])}
+`)
+
+func (s *redisLoginLimitStore) incr(ctx context.Context, key string, ttl time.Duration) (int64, time.Duration, error) {
+	reply, err := redisLoginLimitIncrScript.Run(ctx, s.client, []string{loginLimitKeyPrefix + key}, ttl.Milliseconds()).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	res, ok := reply.([]interface{})
+	if !ok || len(res) != 2 {
+		return 0, 0, fmt.Errorf("unexpected reply incrementing login limit counter: %v", reply)
+	}
+
+	count, _ := res[0].(int64)
+	pttl, _ := res[1].(int64)
+
+	return count, time.Duration(pttl) * time.Millisecond, nil
+}
+
+func (s *redisLoginLimitStore) get(ctx context.Context, key string) (int64, time.Duration, error) {
+	pipe := s.client.Pipeline()
+	countCmd := pipe.Get(ctx, loginLimitKeyPrefix+key)
+	ttlCmd := pipe.PTTL(ctx, loginLimitKeyPrefix+key)
+
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return 0, 0, err
+	}
+
+	count, err := countCmd.Int64()
+	if err == redis.Nil {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, err
+	}
+
+	return count, ttlCmd.Val(), nil
+}
+
+func (s *redisLoginLimitStore) delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, loginLimitKeyPrefix+key).Err()
+}
+
+type memoryLoginLimitCounter struct {
+	count   int64
+	expires time.Time
+}
+
+type memoryLoginLimitStore struct {
+	mu       sync.Mutex
+	counters map[string]memoryLoginLimitCounter
+}
+
+func (s *memoryLoginLimitStore) incr(_ context.Context, key string, ttl time.Duration) (int64, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.expires) {
+		if len(s.counters) >= memoryLoginLimitSweepSize {
+			s.sweep(now)
+		}
+		counter = memoryLoginLimitCounter{expires: now.Add(ttl)}
+	}
+
+	counter.count++
+	s.counters[key] = counter
+
+	return counter.count, counter.expires.Sub(now), nil
+}
+
+func (s *memoryLoginLimitStore) get(_ context.Context, key string) (int64, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.expires) {
+		return 0, 0, nil
+	}
+
+	return counter.count, counter.expires.Sub(now), nil
+}
+
+func (s *memoryLoginLimitStore) delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, key)
+	return nil
+}
+
+// sweep removes the expired counters. It is called with the lock held.
+func (s *memoryLoginLimitStore) sweep(now time.Time) {
+	for key, counter := range s.counters {
+		if !now.Before(counter.expires) {
+			delete(s.counters, key)
+		}
+	}
+}
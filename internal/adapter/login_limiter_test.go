@@ -0,0 +1,139 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redis "github.com/go-redis/redis/v8"
+)
+
+// newTestLoginLimiters returns a limiter backed by memory and one backed by an in-process redis, so that both stores
+// are held to the same behavior
+func newTestLoginLimiters(t *testing.T, opts LoginLimitOptions) map[string]*LoginLimiter {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return map[string]*LoginLimiter{
+		"memory": NewMemoryLoginLimiter(opts),
+		"redis":  NewRedisLoginLimiter(client, opts),
+	}
+}
+
+func TestLoginLimiterLocksOutAfterConsecutiveFailures(t *testing.T) {
+	for name, limiter := range newTestLoginLimiters(t, LoginLimitOptions{LockoutThreshold: 3, LockoutDuration: time.Minute}) {
+		limiter := limiter
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			for i := 0; i < 2; i++ {
+				if retryAfter, err := limiter.Allow(ctx, "mrp@porter.run", "10.0.0.1"); err != nil || retryAfter != 0 {
+					t.Fatalf("expected attempt %d to be allowed, got %s, %v", i+1, retryAfter, err)
+				}
+				if err := limiter.Fail(ctx, "mrp@porter.run"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// a successful login resets the failures, so two more failures do not lock the email out
+			if err := limiter.Succeed(ctx, "mrp@porter.run"); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				if err := limiter.Fail(ctx, "mrp@porter.run"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if retryAfter, _ := limiter.Allow(ctx, "mrp@porter.run", "10.0.0.1"); retryAfter != 0 {
+				t.Fatalf("expected the failures to be reset by a successful login, got a retry after %s", retryAfter)
+			}
+
+			if err := limiter.Fail(ctx, "MRP@porter.run"); err != nil {
+				t.Fatal(err)
+			}
+
+			retryAfter, err := limiter.Allow(ctx, "mrp@porter.run", "10.0.0.2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if retryAfter <= 0 || retryAfter > time.Minute {
+				t.Errorf("expected the email to be locked out for up to a minute, got %s", retryAfter)
+			}
+
+			if retryAfter, _ := limiter.Allow(ctx, "support@porter.run", "10.0.0.1"); retryAfter != 0 {
+				t.Errorf("expected other emails not to be locked out, got %s", retryAfter)
+			}
+		})
+	}
+}
+
+func TestLoginLimiterLimitsAttempts(t *testing.T) {
+	for name, limiter := range newTestLoginLimiters(t, LoginLimitOptions{Window: time.Minute, MaxAttemptsPerEmail: 2, MaxAttemptsPerIP: 3}) {
+		limiter := limiter
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			for i, email := range []string{"a@porter.run", "a@porter.run", "b@porter.run"} {
+				if retryAfter, _ := limiter.Allow(ctx, email, "10.0.0.1"); retryAfter != 0 {
+					t.Fatalf("expected attempt %d to be allowed, got %s", i+1, retryAfter)
+				}
+			}
+
+			if retryAfter, _ := limiter.Allow(ctx, "a@porter.run", "10.0.0.2"); retryAfter <= 0 {
+				t.Errorf("expected the email to be over its limit")
+			}
+			if retryAfter, _ := limiter.Allow(ctx, "c@porter.run", "10.0.0.1"); retryAfter <= 0 {
+				t.Errorf("expected the IP address to be over its limit")
+			}
+			if retryAfter, _ := limiter.Allow(ctx, "c@porter.run", "10.0.0.3"); retryAfter != 0 {
+				t.Errorf("expected other emails and addresses to be allowed, got %s", retryAfter)
+			}
+		})
+	}
+}
+
+func TestNilLoginLimiterAllows(t *testing.T) {
+	var limiter *LoginLimiter
+
+	if retryAfter, err := limiter.Allow(context.Background(), "mrp@porter.run", "10.0.0.1"); err != nil || retryAfter != 0 {
+		t.Errorf("expected a nil limiter to allow every attempt, got %s, %v", retryAfter, err)
+	}
+	if err := limiter.Fail(context.Background(), "mrp@porter.run"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRedisLoginLimiterCountersExpire(t *testing.T) {
+	ctx := context.Background()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close() // nolint:errcheck
+
+	limiter := NewRedisLoginLimiter(client, LoginLimitOptions{Window: time.Minute, MaxAttemptsPerEmail: 1})
+
+	if retryAfter, err := limiter.Allow(ctx, "mrp@porter.run", "10.0.0.1"); err != nil || retryAfter != 0 {
+		t.Fatalf("expected the first attempt to be allowed, got %s, %v", retryAfter, err)
+	}
+
+	retryAfter, err := limiter.Allow(ctx, "mrp@porter.run", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retryAfter <= 0 || retryAfter > time.Minute {
+		t.Fatalf("expected a retry within the window, got %s", retryAfter)
+	}
+	if ttl := server.TTL(loginLimitKeyPrefix + "attempts:email:mrp@porter.run"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("expected the counter to expire with the window, got a ttl of %s", ttl)
+	}
+
+	server.FastForward(time.Minute)
+
+	if retryAfter, err := limiter.Allow(ctx, "mrp@porter.run", "10.0.0.1"); err != nil || retryAfter != 0 {
+		t.Errorf("expected attempts to be allowed once the window passed, got %s, %v", retryAfter, err)
+	}
+}
@@ -0,0 +1,322 @@
+// Package adminoverview collects the aggregate view of every project of an instance which its admin reads, such as the
+// number of projects and apps, the deploy volume of each day and the slowest recent deploys
+package adminoverview
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/chargeback"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+const day = 24 * time.Hour
+
+// eventDays is the number of the most recent days which are counted from their deploy events when they have not been
+// rolled up yet, since the usage rollup job only rolls up a day once it has ended
+const eventDays = 2
+
+var deployEventTypes = []string{string(types.PorterAppEventType_Deploy)}
+
+// Options configure how often the overview is collected and how much of it is kept. Zero values use the defaults.
+type Options struct {
+	// Interval is the time between collections of the overview. Defaults to 5m
+	Interval time.Duration
+	// Days is the number of days of deploy volume in the overview, including today. Defaults to 30
+	Days int
+	// SlowestDeploys is the number of the slowest recent deploys in the overview. Defaults to 10
+	SlowestDeploys int
+	// SlowestDeploysWindow is how far back the slowest deploys are looked for. Defaults to 24h
+	SlowestDeploysWindow time.Duration
+	// Logger receives a record of failed collections. Optional
+	Logger *logger.Logger
+}
+
+// Snapshot is the part of the overview which is read from the database, as of its last collection
+type Snapshot struct {
+	CollectedAt    time.Time
+	Totals         types.AdminOverviewTotals
+	Deploys        []types.AdminOverviewDeployDay
+	SlowestDeploys []types.AdminOverviewSlowDeploy
+}
+
+// Collector periodically reads the totals and deploys of the instance, so that reading the overview does not query
+// every project on large instances
+type Collector struct {
+	repo repository.Repository
+	opts Options
+
+	mu       sync.RWMutex
+	snapshot *Snapshot
+}
+
+// NewCollector returns a Collector with the given options
+func NewCollector(repo repository.Repository, opts Options) *Collector {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Minute
+	}
+	if opts.Days <= 0 {
+		opts.Days = 30
+	}
+	if opts.SlowestDeploys <= 0 {
+		opts.SlowestDeploys = 10
+	}
+	if opts.SlowestDeploysWindow <= 0 {
+		opts.SlowestDeploysWindow = day
+	}
+
+	return &Collector{
+		repo: repo,
+		opts: opts,
+	}
+}
+
+// Run collects the overview once on start and then once per interval until ctx is cancelled
+func (c *Collector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Collect(ctx, time.Now().UTC()); err != nil {
+			c.log(zerolog.ErrorLevel).Err(err).Msg("error collecting admin overview")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Snapshot returns the last collected snapshot, or nil if the overview has not been collected yet
+func (c *Collector) Snapshot() *Snapshot {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.snapshot
+}
+
+// Collect reads the overview as of now and replaces the last snapshot. The last snapshot is kept if any part of the
+// overview cannot be read.
+func (c *Collector) Collect(ctx context.Context, now time.Time) error {
+	totals, err := c.totals(ctx)
+	if err != nil {
+		return err
+	}
+
+	deploys, err := c.deploys(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	slowest, err := c.slowestDeploys(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.snapshot = &Snapshot{
+		CollectedAt:    now,
+		Totals:         totals,
+		Deploys:        deploys,
+		SlowestDeploys: slowest,
+	}
+
+	return nil
+}
+
+func (c *Collector) totals(ctx context.Context) (types.AdminOverviewTotals, error) {
+	var totals types.AdminOverviewTotals
+	var err error
+
+	if totals.Projects, err = c.repo.Project().CountProjects(ctx); err != nil {
+		return totals, fmt.Errorf("error counting projects: %w", err)
+	}
+	if totals.Users, err = c.repo.User().CountUsers(ctx); err != nil {
+		return totals, fmt.Errorf("error counting users: %w", err)
+	}
+	if totals.Apps, err = c.repo.PorterApp().CountPorterApps(ctx); err != nil {
+		return totals, fmt.Errorf("error counting apps: %w", err)
+	}
+
+	providers, err := c.repo.Cluster().CountClustersByProvider(ctx)
+	if err != nil {
+		return totals, fmt.Errorf("error counting clusters: %w", err)
+	}
+
+	totals.ClustersByProvider = make([]types.AdminOverviewProviderCount, 0, len(providers))
+	for provider, count := range providers {
+		totals.Clusters += count
+		totals.ClustersByProvider = append(totals.ClustersByProvider, types.AdminOverviewProviderCount{Provider: provider, Clusters: count})
+	}
+	sort.Slice(totals.ClustersByProvider, func(i, j int) bool {
+		return totals.ClustersByProvider[i].Provider < totals.ClustersByProvider[j].Provider
+	})
+
+	return totals, nil
+}
+
+// deploys returns the deploy volume of each day from the oldest to today. Days are counted from the usage rollups, and
+// the most recent days which have not been rolled up yet are counted from their events
+func (c *Collector) deploys(ctx context.Context, now time.Time) ([]types.AdminOverviewDeployDay, error) {
+	today := chargeback.Day(now)
+	start := today.AddDate(0, 0, -(c.opts.Days - 1))
+
+	rolledUpDays, err := c.repo.UsageRollup().ListUsageRollupDays(ctx, start, today)
+	if err != nil {
+		return nil, fmt.Errorf("error listing rolled up days: %w", err)
+	}
+	rolledUp := make(map[time.Time]bool, len(rolledUpDays))
+	for _, d := range rolledUpDays {
+		rolledUp[d.Day.UTC()] = true
+	}
+
+	rollupTotals, err := c.repo.UsageRollup().ListUsageRollupTotalsByDay(ctx, start, today)
+	if err != nil {
+		return nil, fmt.Errorf("error listing usage rollups: %w", err)
+	}
+	totals := make(map[time.Time]*models.UsageRollup, len(rollupTotals))
+	for _, total := range rollupTotals {
+		totals[total.Day.UTC()] = total
+	}
+
+	res := make([]types.AdminOverviewDeployDay, 0, c.opts.Days)
+	for d := start; !d.After(today); d = d.Add(day) {
+		deployDay := types.AdminOverviewDeployDay{Day: d, Source: types.DeployDaySource_Missing}
+
+		switch {
+		case rolledUp[d]:
+			deployDay.Source = types.DeployDaySource_Rollup
+			if total, ok := totals[d]; ok {
+				deployDay.Deploys = total.Deploys
+				deployDay.DeployFailures = total.DeployFailures
+			}
+		case today.Sub(d) < eventDays*day:
+			events, err := c.repo.PorterAppEvent().ListEventsCreatedBetween(ctx, d, d.Add(day), deployEventTypes)
+			if err != nil {
+				return nil, fmt.Errorf("error listing deploy events for %s: %w", d.Format(time.DateOnly), err)
+			}
+
+			deployDay.Source = types.DeployDaySource_Events
+			for _, event := range events {
+				deployDay.Deploys++
+				if event.Status == string(types.PorterAppEventStatus_Failed) {
+					deployDay.DeployFailures++
+				}
+			}
+		}
+
+		if deployDay.Deploys > 0 {
+			deployDay.FailureRate = float64(deployDay.DeployFailures) / float64(deployDay.Deploys)
+		}
+
+		res = append(res, deployDay)
+	}
+
+	return res, nil
+}
+
+// slowestDeploys returns the finished deploys created within the window before now which took the longest, slowest
+// first. Deploys which recorded neither their helm operation nor their rollout are skipped
+func (c *Collector) slowestDeploys(ctx context.Context, now time.Time) ([]types.AdminOverviewSlowDeploy, error) {
+	events, err := c.repo.PorterAppEvent().ListEventsCreatedBetween(ctx, now.Add(-c.opts.SlowestDeploysWindow), now, deployEventTypes)
+	if err != nil {
+		return nil, fmt.Errorf("error listing recent deploy events: %w", err)
+	}
+
+	deploys := make([]types.AdminOverviewSlowDeploy, 0)
+	for _, event := range events {
+		switch types.PorterAppEventStatus(event.Status) {
+		case types.PorterAppEventStatus_Success, types.PorterAppEventStatus_Failed:
+		default:
+			continue
+		}
+
+		// deploys on clusters without agent deploy events finish when their release is written, so they have no rollout
+		rollout := chargeback.EventDuration(event)
+		helmSeconds := floatMetadata(event.Metadata, "helm_seconds")
+		if rollout == 0 && helmSeconds == 0 {
+			continue
+		}
+
+		deploys = append(deploys, types.AdminOverviewSlowDeploy{
+			EventID:        event.ID,
+			PorterAppID:    event.PorterAppID,
+			Revision:       int(floatMetadata(event.Metadata, "revision")),
+			Status:         event.Status,
+			StartedAt:      event.CreatedAt.Add(-time.Duration(helmSeconds * float64(time.Second))).UTC(),
+			HelmSeconds:    helmSeconds,
+			RolloutSeconds: rollout.Seconds(),
+			TotalSeconds:   helmSeconds + rollout.Seconds(),
+		})
+	}
+
+	sort.Slice(deploys, func(i, j int) bool {
+		if deploys[i].TotalSeconds != deploys[j].TotalSeconds {
+			return deploys[i].TotalSeconds > deploys[j].TotalSeconds
+		}
+		return deploys[i].StartedAt.Before(deploys[j].StartedAt)
+	})
+	if len(deploys) > c.opts.SlowestDeploys {
+		deploys = deploys[:c.opts.SlowestDeploys]
+	}
+
+	appIDs := make([]uint, 0, len(deploys))
+	for _, deploy := range deploys {
+		appIDs = append(appIDs, deploy.PorterAppID)
+	}
+
+	apps, err := c.repo.PorterApp().ListPorterAppsByIDs(ctx, appIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error reading apps of slow deploys: %w", err)
+	}
+	byID := make(map[uint]*models.PorterApp, len(apps))
+	for _, app := range apps {
+		byID[app.ID] = app
+	}
+
+	for i := range deploys {
+		if app, ok := byID[deploys[i].PorterAppID]; ok {
+			deploys[i].ProjectID = app.ProjectID
+			deploys[i].ClusterID = app.ClusterID
+			deploys[i].AppName = app.Name
+		}
+	}
+
+	return deploys, nil
+}
+
+// floatMetadata returns a number in the metadata of an event, which is a float64 once the event has been read back
+// from the database
+func floatMetadata(metadata map[string]any, key string) float64 {
+	switch v := metadata[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	default:
+		return 0
+	}
+}
+
+func (c *Collector) log(level zerolog.Level) *zerolog.Event {
+	if c.opts.Logger == nil {
+		return nil
+	}
+
+	return c.opts.Logger.WithLevel(level)
+}
@@ -0,0 +1,155 @@
+package adminoverview_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/adminoverview"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+func createDeployEvent(t *testing.T, repo repository.Repository, appID uint, status types.PorterAppEventStatus, createdAt time.Time, rollout time.Duration, helmSeconds float64) *models.PorterAppEvent {
+	t.Helper()
+
+	event := &models.PorterAppEvent{
+		ID:          uuid.New(),
+		Type:        string(types.PorterAppEventType_Deploy),
+		Status:      string(status),
+		PorterAppID: appID,
+		CreatedAt:   createdAt,
+		Metadata:    map[string]any{"revision": float64(3)},
+	}
+	if rollout != 0 {
+		event.Metadata["end_time"] = createdAt.Add(rollout)
+	}
+	if helmSeconds != 0 {
+		event.Metadata["helm_seconds"] = helmSeconds
+	}
+
+	if err := repo.PorterAppEvent().CreateEvent(context.Background(), event); err != nil {
+		t.Fatalf("error creating event: %v", err)
+	}
+
+	return event
+}
+
+func TestCollect(t *testing.T) {
+	ctx := context.Background()
+	repo := test.NewRepository(true)
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	today := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	if _, err := repo.Project().CreateProject(&models.Project{Name: "storefront"}); err != nil {
+		t.Fatalf("error creating project: %v", err)
+	}
+	if _, err := repo.User().CreateUser(&models.User{Email: "admin@example.com"}); err != nil {
+		t.Fatalf("error creating user: %v", err)
+	}
+	for _, cluster := range []*models.Cluster{{ProjectID: 1, AWSIntegrationID: 1}, {ProjectID: 1, CloudProvider: "gcp"}} {
+		if _, err := repo.Cluster().CreateCluster(cluster, nil); err != nil {
+			t.Fatalf("error creating cluster: %v", err)
+		}
+	}
+	web, err := repo.PorterApp().CreatePorterApp(&models.PorterApp{Name: "web", ProjectID: 1, ClusterID: 2})
+	if err != nil {
+		t.Fatalf("error creating app: %v", err)
+	}
+
+	// two days ago was rolled up, three days ago was not and its events are no longer read
+	twoDaysAgo := today.AddDate(0, 0, -2)
+	err = repo.UsageRollup().ReplaceUsageRollupsForDay(ctx, twoDaysAgo, now, []*models.UsageRollup{
+		{ProjectID: 1, PorterAppID: web.ID, AppName: "web", Day: twoDaysAgo, Deploys: 4, DeployFailures: 1},
+	})
+	if err != nil {
+		t.Fatalf("error replacing rollups: %v", err)
+	}
+	createDeployEvent(t, repo, web.ID, types.PorterAppEventStatus_Success, today.AddDate(0, 0, -3).Add(time.Hour), 0, 0)
+
+	// yesterday and today have not been rolled up yet
+	createDeployEvent(t, repo, web.ID, types.PorterAppEventStatus_Failed, today.Add(-time.Hour), 5*time.Minute, 30)
+	slowest := createDeployEvent(t, repo, web.ID, types.PorterAppEventStatus_Success, now.Add(-2*time.Hour), 10*time.Minute, 60)
+	createDeployEvent(t, repo, web.ID, types.PorterAppEventStatus_Success, now.Add(-time.Hour), 0, 20)
+	createDeployEvent(t, repo, web.ID, types.PorterAppEventStatus_Progressing, now.Add(-time.Minute), 0, 600)
+	createDeployEvent(t, repo, web.ID, types.PorterAppEventStatus_Success, now.Add(-3*time.Hour), 0, 0)
+
+	collector := adminoverview.NewCollector(repo, adminoverview.Options{Days: 4, SlowestDeploys: 3})
+	if collector.Snapshot() != nil {
+		t.Fatalf("expected no snapshot before the first collection")
+	}
+
+	if err := collector.Collect(ctx, now); err != nil {
+		t.Fatalf("error collecting overview: %v", err)
+	}
+
+	snapshot := collector.Snapshot()
+	if !snapshot.CollectedAt.Equal(now) {
+		t.Errorf("expected the snapshot to be collected at %s, got %s", now, snapshot.CollectedAt)
+	}
+
+	wantTotals := types.AdminOverviewTotals{
+		Projects: 1,
+		Users:    1,
+		Apps:     1,
+		Clusters: 2,
+		ClustersByProvider: []types.AdminOverviewProviderCount{
+			{Provider: "AWS", Clusters: 1},
+			{Provider: "GCP", Clusters: 1},
+		},
+	}
+	if !reflect.DeepEqual(snapshot.Totals, wantTotals) {
+		t.Errorf("expected totals %+v, got %+v", wantTotals, snapshot.Totals)
+	}
+
+	wantDeploys := []types.AdminOverviewDeployDay{
+		{Day: today.AddDate(0, 0, -3), Source: types.DeployDaySource_Missing},
+		{Day: twoDaysAgo, Deploys: 4, DeployFailures: 1, FailureRate: 0.25, Source: types.DeployDaySource_Rollup},
+		{Day: today.AddDate(0, 0, -1), Deploys: 1, DeployFailures: 1, FailureRate: 1, Source: types.DeployDaySource_Events},
+		{Day: today, Deploys: 4, Source: types.DeployDaySource_Events},
+	}
+	if !reflect.DeepEqual(snapshot.Deploys, wantDeploys) {
+		t.Errorf("expected deploys %+v, got %+v", wantDeploys, snapshot.Deploys)
+	}
+
+	if len(snapshot.SlowestDeploys) != 3 {
+		t.Fatalf("expected the 3 slowest finished deploys, got %+v", snapshot.SlowestDeploys)
+	}
+	got := snapshot.SlowestDeploys[0]
+	want := types.AdminOverviewSlowDeploy{
+		EventID:        slowest.ID,
+		ProjectID:      1,
+		ClusterID:      2,
+		PorterAppID:    web.ID,
+		AppName:        "web",
+		Revision:       3,
+		Status:         string(types.PorterAppEventStatus_Success),
+		StartedAt:      now.Add(-2 * time.Hour).Add(-time.Minute),
+		HelmSeconds:    60,
+		RolloutSeconds: 600,
+		TotalSeconds:   660,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the slowest deploy to be %+v, got %+v", want, got)
+	}
+	if snapshot.SlowestDeploys[1].TotalSeconds != 330 || snapshot.SlowestDeploys[2].TotalSeconds != 20 {
+		t.Errorf("expected the deploys to be sorted slowest first, got %+v", snapshot.SlowestDeploys)
+	}
+}
+
+func TestCollectKeepsSnapshotOnError(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	collector := adminoverview.NewCollector(test.NewRepository(false), adminoverview.Options{})
+	if err := collector.Collect(ctx, now); err == nil {
+		t.Errorf("expected an error when the database cannot be read")
+	}
+	if collector.Snapshot() != nil {
+		t.Errorf("expected no snapshot after a failed collection")
+	}
+}
@@ -0,0 +1,56 @@
+package adminoverview
+
+import (
+	_ "embed"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/supervisor"
+)
+
+// Schema is the JSON schema of types.AdminOverview, for building dashboards against the overview
+//
+//go:embed schema.json
+var Schema []byte
+
+// Overview assembles the overview returned to the instance admin from the last snapshot of the collector, which is
+// nil before the first collection, the states of the background components of the server, and the errors the server
+// returned since errorsStart
+func Overview(now time.Time, snapshot *Snapshot, components []supervisor.ComponentState, errors []types.AdminOverviewErrorCount, errorsStart time.Time) *types.AdminOverview {
+	res := &types.AdminOverview{
+		SchemaVersion:  types.AdminOverviewSchemaVersion,
+		GeneratedAt:    now,
+		Deploys:        make([]types.AdminOverviewDeployDay, 0),
+		SlowestDeploys: make([]types.AdminOverviewSlowDeploy, 0),
+		Components:     make([]types.AdminOverviewComponent, 0, len(components)),
+		Errors:         errors,
+		ErrorsWindow:   types.AdminOverviewErrorsWindow{Start: errorsStart, End: now},
+	}
+	if res.Errors == nil {
+		res.Errors = make([]types.AdminOverviewErrorCount, 0)
+	}
+
+	if snapshot != nil {
+		collectedAt := snapshot.CollectedAt
+		totals := snapshot.Totals
+
+		res.CollectedAt = &collectedAt
+		res.Totals = &totals
+		res.Deploys = append(res.Deploys, snapshot.Deploys...)
+		res.SlowestDeploys = append(res.SlowestDeploys, snapshot.SlowestDeploys...)
+	}
+
+	for _, state := range components {
+		res.Components = append(res.Components, types.AdminOverviewComponent{
+			Name:        state.Name,
+			Critical:    state.Critical,
+			Status:      string(state.Status),
+			Healthy:     state.Status != supervisor.ComponentStatus_Failed,
+			Restarts:    state.Restarts,
+			LastError:   state.LastError,
+			LastErrorAt: state.LastErrorAt,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,137 @@
+package adminoverview_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/adminoverview"
+	"github.com/porter-dev/porter/internal/supervisor"
+)
+
+type schemaNode struct {
+	Properties map[string]*schemaNode `json:"properties"`
+	Required   []string               `json:"required"`
+	Items      *schemaNode            `json:"items"`
+}
+
+// jsonFields returns the json names of the fields of a struct
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		fields[name] = t.Field(i).Type
+	}
+	return fields
+}
+
+// checkSchema fails if the properties of an object in the schema are not exactly the json fields of t, so that the
+// published schema cannot drift from the response
+func checkSchema(t *testing.T, path string, node *schemaNode, typ reflect.Type) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Struct:
+		if node.Items == nil {
+			t.Errorf("%s: expected the schema to describe the items of the list", path)
+			return
+		}
+		checkSchema(t, path+"[]", node.Items, typ.Elem())
+	case typ.Kind() == reflect.Struct && typ != reflect.TypeOf(time.Time{}):
+		fields := jsonFields(typ)
+
+		var want, got []string
+		for name := range fields {
+			want = append(want, name)
+		}
+		for name := range node.Properties {
+			got = append(got, name)
+		}
+		sort.Strings(want)
+		sort.Strings(got)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("%s: expected the schema to have properties %v, got %v", path, want, got)
+		}
+
+		required := append([]string(nil), node.Required...)
+		sort.Strings(required)
+		if !reflect.DeepEqual(want, required) {
+			t.Errorf("%s: expected every property to be required, got %v", path, required)
+		}
+
+		for name, fieldType := range fields {
+			if property, ok := node.Properties[name]; ok {
+				checkSchema(t, path+"."+name, property, fieldType)
+			}
+		}
+	}
+}
+
+func TestSchemaMatchesOverview(t *testing.T) {
+	root := &schemaNode{}
+	if err := json.Unmarshal(adminoverview.Schema, root); err != nil {
+		t.Fatalf("error parsing schema: %v", err)
+	}
+
+	checkSchema(t, "overview", root, reflect.TypeOf(types.AdminOverview{}))
+
+	var version struct {
+		Properties struct {
+			SchemaVersion struct {
+				Const int `json:"const"`
+			} `json:"schema_version"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(adminoverview.Schema, &version); err != nil {
+		t.Fatalf("error parsing schema: %v", err)
+	}
+	if version.Properties.SchemaVersion.Const != types.AdminOverviewSchemaVersion {
+		t.Errorf("expected the schema to be version %d, got %d", types.AdminOverviewSchemaVersion, version.Properties.SchemaVersion.Const)
+	}
+}
+
+func TestOverview(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	errorsStart := now.Add(-time.Hour)
+
+	res := adminoverview.Overview(now, nil, []supervisor.ComponentState{
+		{Name: "usage-rollup", Status: supervisor.ComponentStatus_Running},
+		{Name: "cert-expiry-checker", Critical: true, Status: supervisor.ComponentStatus_Failed, Restarts: 5, LastError: "cluster unreachable"},
+	}, nil, errorsStart)
+
+	if res.SchemaVersion != types.AdminOverviewSchemaVersion {
+		t.Errorf("expected schema version %d, got %d", types.AdminOverviewSchemaVersion, res.SchemaVersion)
+	}
+	if res.CollectedAt != nil || res.Totals != nil {
+		t.Errorf("expected no collected data before the first collection")
+	}
+	if res.Deploys == nil || res.SlowestDeploys == nil || res.Errors == nil {
+		t.Errorf("expected empty lists rather than nulls, so that dashboards can read them")
+	}
+	if len(res.Components) != 2 || !res.Components[0].Healthy || res.Components[1].Healthy {
+		t.Errorf("expected only the failed component to be unhealthy, got %+v", res.Components)
+	}
+	if !res.ErrorsWindow.Start.Equal(errorsStart) || !res.ErrorsWindow.End.Equal(now) {
+		t.Errorf("expected errors to be counted from %s to %s, got %+v", errorsStart, now, res.ErrorsWindow)
+	}
+
+	collected := now.Add(-time.Minute)
+	res = adminoverview.Overview(now, &adminoverview.Snapshot{
+		CollectedAt: collected,
+		Totals:      types.AdminOverviewTotals{Projects: 3},
+		Deploys:     []types.AdminOverviewDeployDay{{Day: now.Truncate(24 * time.Hour), Deploys: 2}},
+	}, nil, nil, errorsStart)
+
+	if res.CollectedAt == nil || !res.CollectedAt.Equal(collected) {
+		t.Errorf("expected the overview to be collected at %s, got %v", collected, res.CollectedAt)
+	}
+	if res.Totals == nil || res.Totals.Projects != 3 || len(res.Deploys) != 1 {
+		t.Errorf("expected the collected totals and deploys, got %+v and %+v", res.Totals, res.Deploys)
+	}
+}
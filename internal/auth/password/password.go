@@ -0,0 +1,104 @@
+// Package password checks the passwords users sign up or reset their password with against a strength policy
+package password
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsList string
+
+// commonPasswords are lowercase passwords which are too widely used to be accepted, whatever their length or mix
+// of characters
+var commonPasswords = func() map[string]bool {
+	passwords := make(map[string]bool)
+
+	for _, line := range strings.Split(commonPasswordsList, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			passwords[strings.ToLower(line)] = true
+		}
+	}
+
+	return passwords
+}()
+
+// characterClasses are the kinds of characters counted towards Policy.MinCharacterClasses
+var characterClasses = []string{"lowercase letters", "uppercase letters", "digits", "symbols"}
+
+// Policy is the strength a password must have
+type Policy struct {
+	// MinLength is the minimum number of characters of a password
+	MinLength int
+	// MinCharacterClasses is the minimum number of lowercase letters, uppercase letters, digits and symbols which a
+	// password must mix
+	MinCharacterClasses int
+	// RejectCommon rejects passwords which are on the embedded list of commonly used passwords
+	RejectCommon bool
+}
+
+// ValidationError lists every requirement of a policy which a password does not meet
+type ValidationError struct {
+	Failed []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("password must %s", strings.Join(e.Failed, "; "))
+}
+
+// Validate returns a *ValidationError if the password does not meet the policy
+func (p Policy) Validate(password string) error {
+	var failed []string
+
+	if p.MinLength > 0 && utf8.RuneCountInString(password) < p.MinLength {
+		failed = append(failed, fmt.Sprintf("be at least %d characters long", p.MinLength))
+	}
+
+	minClasses := p.MinCharacterClasses
+	if minClasses > len(characterClasses) {
+		minClasses = len(characterClasses)
+	}
+
+	if minClasses > 0 && countCharacterClasses(password) < minClasses {
+		failed = append(failed, fmt.Sprintf("contain at least %d of %s", minClasses, strings.Join(characterClasses, ", ")))
+	}
+
+	if p.RejectCommon && commonPasswords[strings.ToLower(password)] {
+		failed = append(failed, "not be a commonly used password")
+	}
+
+	if len(failed) > 0 {
+		return &ValidationError{Failed: failed}
+	}
+
+	return nil
+}
+
+func countCharacterClasses(password string) int {
+	var lower, upper, digit, symbol bool
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	count := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,61 @@
+package password_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/auth/password"
+)
+
+func TestValidate(t *testing.T) {
+	policy := password.Policy{MinLength: 8, MinCharacterClasses: 2, RejectCommon: true}
+
+	tests := []struct {
+		password string
+		failed   []string
+	}{
+		{password: "correct-horse", failed: nil},
+		{password: "Tr0ub4dor&3", failed: nil},
+		{password: "a", failed: []string{
+			"be at least 8 characters long",
+			"contain at least 2 of lowercase letters, uppercase letters, digits, symbols",
+		}},
+		{password: "abcdefghij", failed: []string{
+			"contain at least 2 of lowercase letters, uppercase letters, digits, symbols",
+		}},
+		{password: "Password1", failed: []string{"not be a commonly used password"}},
+		{password: "qwerty", failed: []string{
+			"be at least 8 characters long",
+			"contain at least 2 of lowercase letters, uppercase letters, digits, symbols",
+			"not be a commonly used password",
+		}},
+	}
+
+	for _, tt := range tests {
+		err := policy.Validate(tt.password)
+
+		if tt.failed == nil {
+			if err != nil {
+				t.Errorf("expected %q to be accepted, got %v", tt.password, err)
+			}
+			continue
+		}
+
+		var validationErr *password.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("expected %q to be rejected with a validation error, got %v", tt.password, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(validationErr.Failed, tt.failed) {
+			t.Errorf("expected %q to fail %v, got %v", tt.password, tt.failed, validationErr.Failed)
+		}
+	}
+}
+
+func TestValidateEmptyPolicy(t *testing.T) {
+	if err := (password.Policy{}).Validate("password"); err != nil {
+		t.Errorf("expected a policy without requirements to accept any password, got %v", err)
+	}
+}
@@ -3,7 +3,9 @@
 package sessionstore
 
 import (
+	"context"
 	"encoding/base32"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -18,6 +20,17 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	// defaultMaxLifetime is the maximum lifetime of sessions if the store is not given one
+	defaultMaxLifetime = 30 * 24 * time.Hour
+	// touchInterval is how long after a session was last recorded as used that it is recorded again, so that every
+	// authenticated request does not write to the sessions table
+	touchInterval = time.Minute
+)
+
+// errSessionExpired is returned when loading a session which is past its maximum lifetime or idle timeout
+var errSessionExpired = errors.New("session expired")
+
 // structs
 
 // PGStore is a wrapper around gorilla/sessions store.
@@ -26,6 +39,11 @@ type PGStore struct {
 	Options *sessions.Options
 	Path    string
 	Repo    repository.SessionRepository
+
+	// MaxLifetime is how long a session lasts after it is created
+	MaxLifetime time.Duration
+	// IdleTimeout is how long a session lasts after an authenticated request last used it. Zero disables it
+	IdleTimeout time.Duration
 }
 
 // Helpers
@@ -57,14 +75,64 @@ func (store *PGStore) MaxAge(age int) {
 }
 
 // load fetches a session by ID from the database and decodes its content
-// into session.Values.
+// into session.Values. A session which has expired is deleted, and an authenticated session is recorded as used.
 func (store *PGStore) load(session *sessions.Session) error {
 	res, err := store.Repo.SelectSession(&models.Session{Key: session.ID})
 	if err != nil {
 		return err
 	}
 
-	return securecookie.DecodeMulti(session.Name(), string(res.Data), &session.Values, store.Codecs...)
+	now := time.Now()
+	if store.expired(res, now) {
+		if _, err := store.Repo.DeleteSession(res); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		return errSessionExpired
+	}
+
+	if err := securecookie.DecodeMulti(session.Name(), string(res.Data), &session.Values, store.Codecs...); err != nil {
+		return err
+	}
+
+	if auth, _ := session.Values["authenticated"].(bool); auth && now.Sub(lastUsedAt(res)) >= touchInterval {
+		// a session which cannot be recorded as used is still valid, and expires earlier at worst
+		_ = store.Repo.TouchSession(context.Background(), res.Key, now, store.expiresAt(res.CreatedAt, now))
+	}
+
+	return nil
+}
+
+// expired returns true if the session is past its expiry or its idle timeout. The idle timeout is checked against
+// the time the session was last used as well, so that shortening it applies to sessions which were already created.
+func (store *PGStore) expired(session *models.Session, now time.Time) bool {
+	if !session.ExpiresAt.IsZero() && now.After(session.ExpiresAt) {
+		return true
+	}
+
+	return store.IdleTimeout > 0 && now.Sub(lastUsedAt(session)) > store.IdleTimeout
+}
+
+// expiresAt returns the expiry of a session created at createdAt and last used at lastUsed, which is the earliest of
+// the end of its maximum lifetime and the end of its idle timeout
+func (store *PGStore) expiresAt(createdAt time.Time, lastUsed time.Time) time.Time {
+	expiresAt := createdAt.Add(store.MaxLifetime)
+
+	if store.IdleTimeout > 0 && lastUsed.Add(store.IdleTimeout).Before(expiresAt) {
+		expiresAt = lastUsed.Add(store.IdleTimeout)
+	}
+
+	return expiresAt
+}
+
+// lastUsedAt returns the time a session was last used. Sessions created before their use was recorded were last used
+// when they were last written.
+func lastUsedAt(session *models.Session) time.Time {
+	if session.LastUsedAt.IsZero() {
+		return session.UpdatedAt
+	}
+
+	return session.LastUsedAt
 }
 
 // save writes encoded session.Values to a database record.
@@ -75,28 +143,20 @@ func (store *PGStore) save(session *sessions.Session) error {
 		return err
 	}
 
-	exOn := session.Values["expires_on"]
-
-	var expiresOn time.Time
-
-	if exOn == nil {
-		expiresOn = time.Now().Add(time.Second * time.Duration(session.Options.MaxAge))
-	} else {
-		expiresOn = exOn.(time.Time)
-		if expiresOn.Sub(time.Now().Add(time.Second*time.Duration(session.Options.MaxAge))) < 0 {
-			expiresOn = time.Now().Add(time.Second * time.Duration(session.Options.MaxAge))
-		}
-	}
-
 	s := &models.Session{
-		Key:       session.ID,
-		Data:      []byte(encoded),
-		ExpiresAt: expiresOn,
+		Key:  session.ID,
+		Data: []byte(encoded),
 	}
 
 	repo := store.Repo
 
+	// the expiry of a session is only moved when it is used, so that saving it does not extend its lifetime
 	if session.IsNew {
+		now := time.Now()
+		s.CreatedAt = now
+		s.LastUsedAt = now
+		s.ExpiresAt = store.expiresAt(now, now)
+
 		_, createErr := repo.CreateSession(s)
 		return createErr
 	}
@@ -112,6 +172,11 @@ type NewStoreOpts struct {
 	CookieSecrets     []string
 
 	Insecure bool
+
+	// MaxLifetime is how long a session lasts after it is created. Defaults to 30 days
+	MaxLifetime time.Duration
+	// IdleTimeout is how long a session lasts after an authenticated request last used it. Zero disables it
+	IdleTimeout time.Duration
 }
 
 // NewStore takes an initialized db and session key pairs to create a session-store in postgres db.
@@ -122,16 +187,23 @@ func NewStore(opts *NewStoreOpts) (*PGStore, error) {
 		keyPairs = append(keyPairs, []byte(key))
 	}
 
+	maxLifetime := opts.MaxLifetime
+	if maxLifetime <= 0 {
+		maxLifetime = defaultMaxLifetime
+	}
+
 	dbStore := &PGStore{
 		Codecs: securecookie.CodecsFromPairs(keyPairs...),
 		Options: &sessions.Options{
 			Path:     "/",
-			MaxAge:   86400 * 30,
+			MaxAge:   int(maxLifetime.Seconds()),
 			Secure:   !opts.Insecure,
 			HttpOnly: true,
 			SameSite: http.SameSiteLaxMode,
 		},
-		Repo: opts.SessionRepository,
+		Repo:        opts.SessionRepository,
+		MaxLifetime: maxLifetime,
+		IdleTimeout: opts.IdleTimeout,
 	}
 
 	return dbStore, nil
@@ -177,6 +249,10 @@ func (store *PGStore) New(r *http.Request, name string) (*sessions.Session, erro
 			if err != nil {
 				if err == gorm.ErrRecordNotFound {
 					err = nil
+				} else if err == errSessionExpired {
+					// the expired session is replaced by a new one with a new ID, which is not authenticated
+					err = nil
+					session.ID = ""
 				} else if strings.Contains(err.Error(), "expired timestamp") {
 					err = nil
 					session.IsNew = false
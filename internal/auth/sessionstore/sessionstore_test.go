@@ -4,9 +4,11 @@ import (
 	"encoding/base64"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
+	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository/test"
 
 	"github.com/porter-dev/porter/internal/auth/sessionstore"
@@ -161,3 +163,95 @@ func TestSessionOptionsAreUniquePerSession(t *testing.T) {
 		t.Fatalf("PGStore.Options.MaxAge: expected %d, got %d", 900, ss.Options.MaxAge)
 	}
 }
+
+func TestSessionExpiry(t *testing.T) {
+	repo := test.NewRepository(true)
+
+	ss, err := sessionstore.NewStore(
+		&sessionstore.NewStoreOpts{
+			SessionRepository: repo.Session(),
+			CookieSecrets:     []string{"secret"},
+			MaxLifetime:       24 * time.Hour,
+			IdleTimeout:       time.Hour,
+		},
+	)
+	if err != nil {
+		t.Fatal("Failed to get store", err)
+	}
+
+	// newAuthenticatedSession saves an authenticated session, returning a request which sends its cookie
+	newAuthenticatedSession := func() (*http.Request, *models.Session) {
+		req, _ := http.NewRequest("GET", "http://www.example.com", nil)
+
+		session, err := ss.New(req, "mysess")
+		if err != nil {
+			t.Fatal("failed to create session", err)
+		}
+		session.Values["authenticated"] = true
+
+		if err := ss.Save(req, headerOnlyResponseWriter(make(http.Header)), session); err != nil {
+			t.Fatal("Failed to save session:", err.Error())
+		}
+
+		encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, ss.Codecs...)
+		if err != nil {
+			t.Fatal("Failed to make cookie value", err)
+		}
+
+		req, _ = http.NewRequest("GET", "http://www.example.com", nil)
+		req.AddCookie(sessions.NewCookie(session.Name(), encoded, session.Options))
+
+		stored, err := repo.Session().SelectSession(&models.Session{Key: session.ID})
+		if err != nil {
+			t.Fatal("failed to read stored session", err)
+		}
+
+		return req, stored
+	}
+
+	// a used session is recorded as used, which moves its expiry to the end of the idle timeout
+	req, stored := newAuthenticatedSession()
+	stored.LastUsedAt = time.Now().Add(-30 * time.Minute)
+
+	session, err := ss.New(req, "mysess")
+	if err != nil {
+		t.Fatal("failed to get session", err)
+	}
+	if auth, _ := session.Values["authenticated"].(bool); !auth {
+		t.Fatal("expected the session to be authenticated")
+	}
+	if time.Since(stored.LastUsedAt) > time.Minute {
+		t.Errorf("expected the session to be recorded as used, last used at %s", stored.LastUsedAt)
+	}
+	if stored.ExpiresAt.Sub(stored.LastUsedAt) != time.Hour {
+		t.Errorf("expected the session to expire at the end of its idle timeout, expires at %s", stored.ExpiresAt)
+	}
+
+	// a session which has not been used for longer than the idle timeout is replaced by an unauthenticated one
+	req, stored = newAuthenticatedSession()
+	stored.LastUsedAt = time.Now().Add(-2 * time.Hour)
+
+	session, err = ss.New(req, "mysess")
+	if err != nil {
+		t.Fatal("failed to get session", err)
+	}
+	if auth, _ := session.Values["authenticated"].(bool); auth || !session.IsNew || session.ID != "" {
+		t.Errorf("expected the idle session to be replaced by a new session")
+	}
+	if _, err := repo.Session().SelectSession(&models.Session{Key: stored.Key}); err == nil {
+		t.Errorf("expected the idle session to be deleted")
+	}
+
+	// a session past its maximum lifetime is replaced, however recently it was used
+	req, stored = newAuthenticatedSession()
+	stored.CreatedAt = time.Now().Add(-25 * time.Hour)
+	stored.ExpiresAt = stored.CreatedAt.Add(24 * time.Hour)
+
+	session, err = ss.New(req, "mysess")
+	if err != nil {
+		t.Fatal("failed to get session", err)
+	}
+	if auth, _ := session.Values["authenticated"].(bool); auth || !session.IsNew {
+		t.Errorf("expected the session past its maximum lifetime to be replaced by a new session")
+	}
+}
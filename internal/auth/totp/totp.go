@@ -0,0 +1,149 @@
+// Package totp implements the time-based one-time passwords of RFC 6238, as generated by authenticator apps, and the
+// backup codes which stand in for them when the authenticator is lost
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" // nolint:gosec
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Digits is the length of a code
+	Digits = 6
+	// Period is how long each code is valid for
+	Period = 30 * time.Second
+	// Skew is the number of periods before and after the current one whose codes are accepted, for authenticators whose
+	// clocks drift
+	Skew = 1
+
+	// secretSize is the size of generated secrets, which RFC 4226 recommends to be 160 bits
+	secretSize = 20
+	// backupCodeLength is the number of characters of a backup code
+	backupCodeLength = 10
+	// backupCodeAlphabet leaves out characters which are easily mistaken for one another
+	backupCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// secretEncoding is the base32 encoding authenticator apps expect secrets in
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random secret
+func GenerateSecret() ([]byte, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("error generating secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// EncodeSecret returns a secret in the form users enter into authenticator apps which cannot scan a QR code
+func EncodeSecret(secret []byte) string {
+	return secretEncoding.EncodeToString(secret)
+}
+
+// ProvisioningURI returns the otpauth URI which enrolls a secret in an authenticator app, which is usually shown to the
+// user as a QR code
+func ProvisioningURI(issuer, account string, secret []byte) string {
+	params := url.Values{}
+	params.Set("secret", EncodeSecret(secret))
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(Digits))
+	params.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", url.PathEscape(issuer+":"+account), params.Encode())
+}
+
+// Counter returns the counter of the period which contains t
+func Counter(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// Code returns the code of a secret for a counter
+func Code(secret []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg) // nolint:errcheck
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, as defined by RFC 4226
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
+
+// Validate returns the counter of the period whose code matches code, within Skew periods of t. Codes of counters at
+// or before lastCounter are rejected, so that a code which was accepted cannot be used again.
+func Validate(secret []byte, code string, t time.Time, lastCounter int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Counter(t)
+	for counter := current - Skew; counter <= current+Skew; counter++ {
+		if counter <= lastCounter {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(Code(secret, counter)), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+
+	return 0, false
+}
+
+// GenerateBackupCodes returns n random backup codes, formatted as two groups of characters separated by a dash
+func GenerateBackupCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	alphabetSize := big.NewInt(int64(len(backupCodeAlphabet)))
+
+	for i := 0; i < n; i++ {
+		var sb strings.Builder
+		for j := 0; j < backupCodeLength; j++ {
+			if j == backupCodeLength/2 {
+				sb.WriteByte('-')
+			}
+
+			idx, err := rand.Int(rand.Reader, alphabetSize)
+			if err != nil {
+				return nil, fmt.Errorf("error generating backup code: %w", err)
+			}
+			sb.WriteByte(backupCodeAlphabet[idx.Int64()])
+		}
+
+		codes = append(codes, sb.String())
+	}
+
+	return codes, nil
+}
+
+// HashBackupCode returns the hash a backup code is stored as. Codes are random, so they are hashed without a salt.
+// Codes are compared without their case, dashes and spaces, which users often get wrong when typing them.
+func HashBackupCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,95 @@
+package totp_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/auth/totp"
+)
+
+// rfcSecret is the SHA1 secret of the test vectors of RFC 6238
+var rfcSecret = []byte("12345678901234567890")
+
+func TestCode(t *testing.T) {
+	// the test vectors of RFC 6238 have 8 digits, of which codes are the last 6
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 1111111111, expected: "050471"},
+		{unix: 1234567890, expected: "005924"},
+		{unix: 2000000000, expected: "279037"},
+	}
+
+	for _, tt := range tests {
+		if code := totp.Code(rfcSecret, totp.Counter(time.Unix(tt.unix, 0))); code != tt.expected {
+			t.Errorf("expected the code at %d to be %s, got %s", tt.unix, tt.expected, code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := totp.Counter(now)
+
+	counter, ok := totp.Validate(rfcSecret, "005924", now, 0)
+	if !ok || counter != current {
+		t.Fatalf("expected the current code to be accepted at counter %d, got %d, %t", current, counter, ok)
+	}
+
+	if _, ok := totp.Validate(rfcSecret, totp.Code(rfcSecret, current-1), now, 0); !ok {
+		t.Errorf("expected the code of the previous period to be accepted")
+	}
+	if _, ok := totp.Validate(rfcSecret, totp.Code(rfcSecret, current-2), now, 0); ok {
+		t.Errorf("expected the code of two periods ago to be rejected")
+	}
+	if _, ok := totp.Validate(rfcSecret, "005924", now, current); ok {
+		t.Errorf("expected a code which was already accepted to be rejected")
+	}
+	if _, ok := totp.Validate(rfcSecret, "00592", now, 0); ok {
+		t.Errorf("expected a code of the wrong length to be rejected")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(totp.ProvisioningURI("Porter", "mrp@porter.run", rfcSecret))
+	if err != nil {
+		t.Fatalf("unexpected error parsing provisioning uri: %v", err)
+	}
+
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Porter:mrp@porter.run" {
+		t.Errorf("unexpected provisioning uri %s", uri)
+	}
+	if secret := uri.Query().Get("secret"); secret != "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" {
+		t.Errorf("expected the secret to be base32 encoded without padding, got %s", secret)
+	}
+	if issuer := uri.Query().Get("issuer"); issuer != "Porter" {
+		t.Errorf("expected the issuer to be Porter, got %s", issuer)
+	}
+}
+
+func TestBackupCodes(t *testing.T) {
+	codes, err := totp.GenerateBackupCodes(10)
+	if err != nil {
+		t.Fatalf("unexpected error generating backup codes: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("expected backup codes to be two groups of 5 characters, got %s", code)
+		}
+		seen[totp.HashBackupCode(code)] = true
+	}
+	if len(seen) != 10 {
+		t.Errorf("expected 10 distinct backup codes, got %d", len(seen))
+	}
+
+	if totp.HashBackupCode(codes[0]) != totp.HashBackupCode(" "+strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))) {
+		t.Errorf("expected backup codes to be compared without their case, dashes and spaces")
+	}
+}
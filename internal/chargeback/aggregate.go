@@ -0,0 +1,103 @@
+package chargeback
+
+import (
+	"sort"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// rolledUpEventTypes are the event types which count towards an app's usage
+var rolledUpEventTypes = []string{
+	string(types.PorterAppEventType_Deploy),
+	string(types.PorterAppEventType_Build),
+}
+
+// Day returns midnight UTC of the day t falls on
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Aggregate rolls up the deploy and build events created on day into one rollup for each app. Events of apps which are
+// not in apps are skipped, since their project is unknown.
+func Aggregate(day time.Time, events []*models.PorterAppEvent, apps map[uint]*models.PorterApp) []*models.UsageRollup {
+	byApp := make(map[uint]*models.UsageRollup)
+
+	for _, event := range events {
+		app, ok := apps[event.PorterAppID]
+		if !ok {
+			continue
+		}
+
+		rollup, ok := byApp[app.ID]
+		if !ok {
+			rollup = &models.UsageRollup{
+				ProjectID:   app.ProjectID,
+				PorterAppID: app.ID,
+				AppName:     app.Name,
+				Day:         day,
+			}
+			byApp[app.ID] = rollup
+		}
+
+		failed := event.Status == string(types.PorterAppEventStatus_Failed)
+		seconds := EventDuration(event).Seconds()
+
+		switch types.PorterAppEventType(event.Type) {
+		case types.PorterAppEventType_Deploy:
+			rollup.Deploys++
+			if failed {
+				rollup.DeployFailures++
+			}
+			rollup.HelmOperationSeconds += seconds
+		case types.PorterAppEventType_Build:
+			rollup.Builds++
+			if failed {
+				rollup.BuildFailures++
+			}
+			rollup.BuildSeconds += seconds
+		}
+	}
+
+	res := make([]*models.UsageRollup, 0, len(byApp))
+	for _, rollup := range byApp {
+		res = append(res, rollup)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].PorterAppID < res[j].PorterAppID
+	})
+
+	return res
+}
+
+// EventDuration returns the time between an event being created and its end_time, or zero if the event has not finished
+// or did not report when it finished
+func EventDuration(event *models.PorterAppEvent) time.Duration {
+	switch types.PorterAppEventStatus(event.Status) {
+	case types.PorterAppEventStatus_Success, types.PorterAppEventStatus_Failed:
+	default:
+		return 0
+	}
+
+	var end time.Time
+	switch v := event.Metadata["end_time"].(type) {
+	case time.Time:
+		end = v
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return 0
+		}
+		end = parsed
+	default:
+		return 0
+	}
+
+	if end.Before(event.CreatedAt) {
+		return 0
+	}
+
+	return end.Sub(event.CreatedAt)
+}
@@ -0,0 +1,124 @@
+package chargeback
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func appEvent(appID uint, eventType types.PorterAppEventType, status types.PorterAppEventStatus, createdAt time.Time, duration time.Duration) *models.PorterAppEvent {
+	event := &models.PorterAppEvent{
+		PorterAppID: appID,
+		Type:        string(eventType),
+		Status:      string(status),
+		CreatedAt:   createdAt,
+		Metadata:    models.JSONB{},
+	}
+	if duration > 0 {
+		// events read from the database have their end time as a string
+		event.Metadata["end_time"] = createdAt.Add(duration).Format(time.RFC3339Nano)
+	}
+
+	return event
+}
+
+func TestAggregate(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := day.Add(10 * time.Hour)
+
+	apps := map[uint]*models.PorterApp{
+		1: {Model: gorm.Model{ID: 1}, ProjectID: 10, Name: "api"},
+		2: {Model: gorm.Model{ID: 2}, ProjectID: 20, Name: "worker"},
+	}
+	events := []*models.PorterAppEvent{
+		appEvent(1, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Success, at, 90*time.Second),
+		appEvent(1, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Failed, at, 30*time.Second),
+		// a deploy which has not finished is counted without a duration
+		appEvent(1, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Progressing, at, 0),
+		appEvent(1, types.PorterAppEventType_Build, types.PorterAppEventStatus_Success, at, 5*time.Minute),
+		// a build which did not report when it finished
+		appEvent(1, types.PorterAppEventType_Build, types.PorterAppEventStatus_Failed, at, 0),
+		appEvent(2, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Success, at, 10*time.Second),
+		// events of unknown apps are skipped
+		appEvent(3, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Success, at, 10*time.Second),
+	}
+
+	rollups := Aggregate(day, events, apps)
+	if len(rollups) != 2 {
+		t.Fatalf("expected 2 rollups, got %d", len(rollups))
+	}
+
+	api := rollups[0]
+	if api.PorterAppID != 1 || api.ProjectID != 10 || api.AppName != "api" || !api.Day.Equal(day) {
+		t.Fatalf("unexpected rollup %+v", api)
+	}
+	if api.Deploys != 3 || api.DeployFailures != 1 || api.HelmOperationSeconds != 120 {
+		t.Fatalf("unexpected deploy usage %+v", api)
+	}
+	if api.Builds != 2 || api.BuildFailures != 1 || api.BuildSeconds != 300 {
+		t.Fatalf("unexpected build usage %+v", api)
+	}
+
+	worker := rollups[1]
+	if worker.ProjectID != 20 || worker.Deploys != 1 || worker.HelmOperationSeconds != 10 || worker.Builds != 0 {
+		t.Fatalf("unexpected rollup %+v", worker)
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+
+	rollups := []*models.UsageRollup{
+		{ProjectID: 10, PorterAppID: 1, AppName: "old-api", Day: start, Deploys: 2, HelmOperationSeconds: 20},
+		{ProjectID: 10, PorterAppID: 1, AppName: "api", Day: start.AddDate(0, 0, 1), Deploys: 1, DeployFailures: 1, HelmOperationSeconds: 5},
+		{ProjectID: 10, PorterAppID: 2, AppName: "worker", Day: start, Builds: 3, BuildSeconds: 600},
+		{ProjectID: 11, PorterAppID: 3, AppName: "other-project", Day: start, Deploys: 100},
+	}
+	rolledUp := []*models.UsageRollupDay{
+		{Day: start},
+		{Day: start.AddDate(0, 0, 1)},
+	}
+
+	report := BuildReport(10, start, end, rollups, rolledUp)
+
+	if report.Start != "2026-03-01" || report.End != "2026-03-03" {
+		t.Fatalf("unexpected period %s to %s", report.Start, report.End)
+	}
+	if len(report.Apps) != 2 {
+		t.Fatalf("expected 2 apps, got %+v", report.Apps)
+	}
+	if report.Apps[0].AppName != "api" || report.Apps[0].Deploys != 3 || report.Apps[0].DeployFailures != 1 || report.Apps[0].HelmOperationSeconds != 25 {
+		t.Fatalf("unexpected app usage %+v", report.Apps[0])
+	}
+	if report.Apps[1].AppName != "worker" || report.Apps[1].Builds != 3 || report.Apps[1].BuildSeconds != 600 {
+		t.Fatalf("unexpected app usage %+v", report.Apps[1])
+	}
+	if report.Total.Deploys != 3 || report.Total.Builds != 3 {
+		t.Fatalf("unexpected total %+v", report.Total)
+	}
+	if len(report.MissingDays) != 1 || report.MissingDays[0] != "2026-03-03" {
+		t.Fatalf("expected the last day to be missing, got %v", report.MissingDays)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header, 2 apps and a total, got %q", buf.String())
+	}
+	if lines[1] != "1,api,3,1,25.000,0,0,0.000" {
+		t.Fatalf("unexpected app row %q", lines[1])
+	}
+	if lines[3] != ",total,3,1,25.000,3,0,600.000" {
+		t.Fatalf("unexpected total row %q", lines[3])
+	}
+}
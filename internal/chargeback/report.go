@@ -0,0 +1,113 @@
+package chargeback
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// BuildReport sums the rollups of a project's apps over the days from start to end, inclusive. Days in the period which
+// are not in rolledUp are reported as missing.
+func BuildReport(projectID uint, start, end time.Time, rollups []*models.UsageRollup, rolledUp []*models.UsageRollupDay) types.UsageReport {
+	start, end = Day(start), Day(end)
+
+	report := types.UsageReport{
+		ProjectID:   projectID,
+		Start:       start.Format(time.DateOnly),
+		End:         end.Format(time.DateOnly),
+		Apps:        make([]types.AppUsage, 0),
+		MissingDays: make([]string, 0),
+	}
+
+	byApp := make(map[uint]*types.AppUsage)
+	for _, rollup := range rollups {
+		if rollup.ProjectID != projectID {
+			continue
+		}
+
+		app, ok := byApp[rollup.PorterAppID]
+		if !ok {
+			app = &types.AppUsage{PorterAppID: rollup.PorterAppID}
+			byApp[rollup.PorterAppID] = app
+		}
+		// rollups are listed by day, so this keeps the app's most recent name
+		app.AppName = rollup.AppName
+
+		usage := rollup.ToUsageType()
+		app.Add(usage)
+		report.Total.Add(usage)
+	}
+
+	for _, app := range byApp {
+		report.Apps = append(report.Apps, *app)
+	}
+	sort.Slice(report.Apps, func(i, j int) bool {
+		if report.Apps[i].AppName != report.Apps[j].AppName {
+			return report.Apps[i].AppName < report.Apps[j].AppName
+		}
+		return report.Apps[i].PorterAppID < report.Apps[j].PorterAppID
+	})
+
+	rolled := make(map[time.Time]bool, len(rolledUp))
+	for _, d := range rolledUp {
+		rolled[Day(d.Day)] = true
+	}
+	for d := start; !d.After(end); d = d.Add(day) {
+		if !rolled[d] {
+			report.MissingDays = append(report.MissingDays, d.Format(time.DateOnly))
+		}
+	}
+
+	return report
+}
+
+var csvHeader = []string{
+	"porter_app_id",
+	"app_name",
+	"deploys",
+	"deploy_failures",
+	"helm_operation_seconds",
+	"builds",
+	"build_failures",
+	"build_seconds",
+}
+
+// WriteCSV writes one row for each app in the report, followed by a row with the project's total
+func WriteCSV(w io.Writer, report types.UsageReport) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, app := range report.Apps {
+		if err := cw.Write(csvRow(strconv.FormatUint(uint64(app.PorterAppID), 10), app.AppName, app.Usage)); err != nil {
+			return err
+		}
+	}
+
+	if err := cw.Write(csvRow("", "total", report.Total)); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvRow(id, name string, usage types.Usage) []string {
+	return []string{
+		id,
+		name,
+		strconv.Itoa(usage.Deploys),
+		strconv.Itoa(usage.DeployFailures),
+		strconv.FormatFloat(usage.HelmOperationSeconds, 'f', 3, 64),
+		strconv.Itoa(usage.Builds),
+		strconv.Itoa(usage.BuildFailures),
+		strconv.FormatFloat(usage.BuildSeconds, 'f', 3, 64),
+	}
+}
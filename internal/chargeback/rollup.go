@@ -0,0 +1,220 @@
+package chargeback
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+const day = 24 * time.Hour
+
+// EventStore reads the events which are rolled up, and deletes them once they are past the retention period
+type EventStore interface {
+	ListEventsCreatedBetween(ctx context.Context, start, end time.Time, eventTypes []string) ([]*models.PorterAppEvent, error)
+	DeleteEventsCreatedBetween(ctx context.Context, start, end time.Time) (int64, error)
+}
+
+// AppReader reads the porter apps that events belong to
+type AppReader interface {
+	ListPorterAppsByIDs(ctx context.Context, ids []uint) ([]*models.PorterApp, error)
+}
+
+// RollupStore stores the daily rollups
+type RollupStore interface {
+	ReplaceUsageRollupsForDay(ctx context.Context, day time.Time, rolledUpAt time.Time, rollups []*models.UsageRollup) error
+	ListUsageRollupDays(ctx context.Context, start, end time.Time) ([]*models.UsageRollupDay, error)
+	MarkUsageRollupDayPruned(ctx context.Context, day time.Time, prunedAt time.Time) error
+}
+
+// Options configure the rollup job. Zero values use the defaults.
+type Options struct {
+	// Interval is the time between runs of the job. Defaults to 1h
+	Interval time.Duration
+	// SettleDays is the number of days after a day ends that its rollup is recomputed on every run, so that deploys and
+	// builds which finish after midnight are counted. Defaults to 2
+	SettleDays int
+	// BackfillDays is how far back the job looks for days which have not been rolled up. Defaults to 90
+	BackfillDays int
+	// EventRetention is how long raw events are kept. Events are only deleted once the day they were created on has a
+	// settled rollup, so that retention never leaves a hole in the rolled up usage. Zero keeps events forever
+	EventRetention time.Duration
+	// Logger receives a record of rolled up and pruned days. Optional
+	Logger *logger.Logger
+}
+
+// Job maintains a rollup of the usage of every app for each day, and applies the event retention policy
+type Job struct {
+	events  EventStore
+	apps    AppReader
+	rollups RollupStore
+	opts    Options
+}
+
+// NewJob returns a Job with the given options
+func NewJob(events EventStore, apps AppReader, rollups RollupStore, opts Options) *Job {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Hour
+	}
+	if opts.SettleDays <= 0 {
+		opts.SettleDays = 2
+	}
+	if opts.BackfillDays <= 0 {
+		opts.BackfillDays = 90
+	}
+
+	return &Job{
+		events:  events,
+		apps:    apps,
+		rollups: rollups,
+		opts:    opts,
+	}
+}
+
+// Run rolls up usage once on start and then once per interval until ctx is cancelled
+func (j *Job) Run(ctx context.Context) error {
+	ticker := time.NewTicker(j.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx, time.Now().UTC()); err != nil {
+			j.log(zerolog.ErrorLevel).Err(err).Msg("error rolling up usage")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce rolls up every completed day in the backfill window which has no rollup or which has not settled, then
+// deletes the events past the retention period. Days whose events were already deleted are never recomputed.
+func (j *Job) RunOnce(ctx context.Context, now time.Time) error {
+	today := Day(now)
+	yesterday := today.Add(-day)
+	windowStart := today.AddDate(0, 0, -j.opts.BackfillDays)
+
+	rolledUp, err := j.rolledUpDays(ctx, windowStart, yesterday)
+	if err != nil {
+		return err
+	}
+
+	for d := windowStart; !d.After(yesterday); d = d.Add(day) {
+		if rolled, ok := rolledUp[d]; ok && (rolled.EventsPrunedAt != nil || j.settled(rolled)) {
+			continue
+		}
+
+		if err := j.rollupDay(ctx, d, now); err != nil {
+			return err
+		}
+	}
+
+	return j.prune(ctx, now)
+}
+
+// rollupDay recomputes the rollups of every app for day d from its events. This is idempotent: the previous rollups of
+// the day are replaced.
+func (j *Job) rollupDay(ctx context.Context, d time.Time, now time.Time) error {
+	events, err := j.events.ListEventsCreatedBetween(ctx, d, d.Add(day), rolledUpEventTypes)
+	if err != nil {
+		return fmt.Errorf("error listing events for %s: %w", d.Format(time.DateOnly), err)
+	}
+
+	appIDs := make([]uint, 0)
+	seen := make(map[uint]bool)
+	for _, event := range events {
+		if !seen[event.PorterAppID] {
+			seen[event.PorterAppID] = true
+			appIDs = append(appIDs, event.PorterAppID)
+		}
+	}
+
+	apps, err := j.apps.ListPorterAppsByIDs(ctx, appIDs)
+	if err != nil {
+		return fmt.Errorf("error reading apps for %s: %w", d.Format(time.DateOnly), err)
+	}
+	appsByID := make(map[uint]*models.PorterApp, len(apps))
+	for _, app := range apps {
+		appsByID[app.ID] = app
+	}
+
+	rollups := Aggregate(d, events, appsByID)
+	if err := j.rollups.ReplaceUsageRollupsForDay(ctx, d, now, rollups); err != nil {
+		return fmt.Errorf("error storing rollups for %s: %w", d.Format(time.DateOnly), err)
+	}
+
+	j.log(zerolog.InfoLevel).Str("day", d.Format(time.DateOnly)).Int("events", len(events)).Int("apps", len(rollups)).Msg("rolled up usage")
+
+	return nil
+}
+
+// prune deletes the events of every settled day which ended before the retention period
+func (j *Job) prune(ctx context.Context, now time.Time) error {
+	if j.opts.EventRetention <= 0 {
+		return nil
+	}
+
+	// the last day whose events are all older than the retention period
+	last := Day(now.Add(-j.opts.EventRetention)).Add(-day)
+
+	days, err := j.rollups.ListUsageRollupDays(ctx, time.Time{}, last)
+	if err != nil {
+		return fmt.Errorf("error listing rolled up days: %w", err)
+	}
+
+	for _, rolled := range days {
+		if rolled.EventsPrunedAt != nil {
+			continue
+		}
+		// an unsettled day may still be recomputed, which needs its events
+		if !j.settled(rolled) {
+			continue
+		}
+
+		d := Day(rolled.Day)
+		deleted, err := j.events.DeleteEventsCreatedBetween(ctx, d, d.Add(day))
+		if err != nil {
+			return fmt.Errorf("error deleting events for %s: %w", d.Format(time.DateOnly), err)
+		}
+
+		if err := j.rollups.MarkUsageRollupDayPruned(ctx, d, now); err != nil {
+			return fmt.Errorf("error marking %s pruned: %w", d.Format(time.DateOnly), err)
+		}
+
+		j.log(zerolog.InfoLevel).Str("day", d.Format(time.DateOnly)).Int64("events", deleted).Msg("deleted events past retention")
+	}
+
+	return nil
+}
+
+// settled returns whether a day was rolled up after its settle period, so its rollup will not change
+func (j *Job) settled(rolled *models.UsageRollupDay) bool {
+	return !rolled.RolledUpAt.Before(Day(rolled.Day).AddDate(0, 0, j.opts.SettleDays+1))
+}
+
+func (j *Job) rolledUpDays(ctx context.Context, start, end time.Time) (map[time.Time]*models.UsageRollupDay, error) {
+	days, err := j.rollups.ListUsageRollupDays(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error listing rolled up days: %w", err)
+	}
+
+	res := make(map[time.Time]*models.UsageRollupDay, len(days))
+	for _, rolled := range days {
+		res[Day(rolled.Day)] = rolled
+	}
+
+	return res, nil
+}
+
+func (j *Job) log(level zerolog.Level) *zerolog.Event {
+	if j.opts.Logger == nil {
+		return nil
+	}
+
+	return j.opts.Logger.WithLevel(level)
+}
@@ -0,0 +1,211 @@
+package chargeback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type fakeEvents struct {
+	events  []*models.PorterAppEvent
+	deleted []time.Time
+}
+
+func (f *fakeEvents) ListEventsCreatedBetween(ctx context.Context, start, end time.Time, eventTypes []string) ([]*models.PorterAppEvent, error) {
+	var res []*models.PorterAppEvent
+	for _, event := range f.events {
+		if !event.CreatedAt.Before(start) && event.CreatedAt.Before(end) {
+			res = append(res, event)
+		}
+	}
+
+	return res, nil
+}
+
+func (f *fakeEvents) DeleteEventsCreatedBetween(ctx context.Context, start, end time.Time) (int64, error) {
+	var kept []*models.PorterAppEvent
+	var deleted int64
+	for _, event := range f.events {
+		if !event.CreatedAt.Before(start) && event.CreatedAt.Before(end) {
+			deleted++
+			continue
+		}
+		kept = append(kept, event)
+	}
+	f.events = kept
+	f.deleted = append(f.deleted, start)
+
+	return deleted, nil
+}
+
+type fakeApps map[uint]*models.PorterApp
+
+func (f fakeApps) ListPorterAppsByIDs(ctx context.Context, ids []uint) ([]*models.PorterApp, error) {
+	var res []*models.PorterApp
+	for _, id := range ids {
+		if app, ok := f[id]; ok {
+			res = append(res, app)
+		}
+	}
+
+	return res, nil
+}
+
+type fakeRollups struct {
+	rollups  map[time.Time][]*models.UsageRollup
+	days     map[time.Time]*models.UsageRollupDay
+	replaces int
+}
+
+func newFakeRollups() *fakeRollups {
+	return &fakeRollups{
+		rollups: make(map[time.Time][]*models.UsageRollup),
+		days:    make(map[time.Time]*models.UsageRollupDay),
+	}
+}
+
+func (f *fakeRollups) ReplaceUsageRollupsForDay(ctx context.Context, day time.Time, rolledUpAt time.Time, rollups []*models.UsageRollup) error {
+	f.replaces++
+	f.rollups[day] = rollups
+	if f.days[day] == nil {
+		f.days[day] = &models.UsageRollupDay{Day: day}
+	}
+	f.days[day].RolledUpAt = rolledUpAt
+
+	return nil
+}
+
+func (f *fakeRollups) ListUsageRollupDays(ctx context.Context, start, end time.Time) ([]*models.UsageRollupDay, error) {
+	var res []*models.UsageRollupDay
+	for d, rolled := range f.days {
+		if !d.Before(start) && !d.After(end) {
+			res = append(res, rolled)
+		}
+	}
+
+	return res, nil
+}
+
+func (f *fakeRollups) MarkUsageRollupDayPruned(ctx context.Context, day time.Time, prunedAt time.Time) error {
+	f.days[day].EventsPrunedAt = &prunedAt
+	return nil
+}
+
+func deploys(rollups []*models.UsageRollup) int {
+	total := 0
+	for _, rollup := range rollups {
+		total += rollup.Deploys
+	}
+
+	return total
+}
+
+func TestRunOnce_BackfillsAndIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 6, 0, 0, 0, time.UTC)
+
+	events := &fakeEvents{events: []*models.PorterAppEvent{
+		appEvent(1, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Success, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), time.Minute),
+		appEvent(1, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Success, time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC), time.Minute),
+		// today is not rolled up until it has ended
+		appEvent(1, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Success, time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC), time.Minute),
+	}}
+	rollups := newFakeRollups()
+	job := NewJob(events, fakeApps{1: {Model: gorm.Model{ID: 1}, ProjectID: 10, Name: "api"}}, rollups, Options{BackfillDays: 10, SettleDays: 2})
+
+	if err := job.RunOnce(ctx, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rollups.days) != 10 {
+		t.Fatalf("expected every day in the backfill window to be rolled up, got %d", len(rollups.days))
+	}
+	if deploys(rollups.rollups[Day(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))]) != 1 {
+		t.Fatalf("expected the backfilled day to have 1 deploy")
+	}
+	if _, ok := rollups.days[Day(now)]; ok {
+		t.Fatalf("expected today not to be rolled up")
+	}
+
+	// running again only recomputes the days which have not settled, and gives the same result
+	replaces := rollups.replaces
+	if err := job.RunOnce(ctx, now.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rollups.replaces-replaces != 2 {
+		t.Fatalf("expected the 2 days within the settle period to be recomputed, got %d", rollups.replaces-replaces)
+	}
+	if deploys(rollups.rollups[Day(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC))]) != 1 {
+		t.Fatalf("expected recomputing a day not to double count its deploys")
+	}
+}
+
+func TestRunOnce_RetentionOnlyPrunesSettledDays(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 6, 0, 0, 0, time.UTC)
+
+	oldDeploy := appEvent(1, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Success, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), time.Minute)
+	// this day is outside the backfill window, so it is never rolled up and must not be pruned
+	outsideWindow := appEvent(1, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Success, time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC), time.Minute)
+	recentDeploy := appEvent(1, types.PorterAppEventType_Deploy, types.PorterAppEventStatus_Success, time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC), time.Minute)
+
+	events := &fakeEvents{events: []*models.PorterAppEvent{oldDeploy, outsideWindow, recentDeploy}}
+	rollups := newFakeRollups()
+	// the retention is shorter than the settle period: unsettled days must be kept regardless
+	job := NewJob(events, fakeApps{1: {Model: gorm.Model{ID: 1}, ProjectID: 10, Name: "api"}}, rollups, Options{
+		BackfillDays:   10,
+		SettleDays:     2,
+		EventRetention: 24 * time.Hour,
+	})
+
+	if err := job.RunOnce(ctx, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	oldDay := Day(oldDeploy.CreatedAt)
+	recentDay := Day(recentDeploy.CreatedAt)
+	if rollups.days[oldDay].EventsPrunedAt == nil {
+		t.Fatalf("expected the old day to be pruned")
+	}
+	if deploys(rollups.rollups[oldDay]) != 1 {
+		t.Fatalf("expected the pruned day to keep its rollup")
+	}
+	if rollups.days[recentDay].EventsPrunedAt != nil {
+		t.Fatalf("expected a day which has not settled to keep its events")
+	}
+
+	remaining := func() map[*models.PorterAppEvent]bool {
+		res := map[*models.PorterAppEvent]bool{}
+		for _, event := range events.events {
+			res[event] = true
+		}
+		return res
+	}
+	if r := remaining(); r[oldDeploy] || !r[outsideWindow] || !r[recentDeploy] {
+		t.Fatalf("unexpected remaining events %v", events.events)
+	}
+
+	// a day later the recent day is recomputed once more, settling it, and only then pruned
+	later := now.AddDate(0, 0, 1)
+	if err := job.RunOnce(ctx, later); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rollups.days[recentDay].RolledUpAt.Equal(later) || rollups.days[recentDay].EventsPrunedAt == nil {
+		t.Fatalf("expected the recent day to be settled and then pruned, got %+v", rollups.days[recentDay])
+	}
+	if deploys(rollups.rollups[recentDay]) != 1 {
+		t.Fatalf("expected the recent day to keep its rollup")
+	}
+
+	// pruned days are final, and are never recomputed from the missing events
+	if !rollups.days[oldDay].RolledUpAt.Equal(now) {
+		t.Fatalf("expected the pruned day not to be recomputed")
+	}
+	if r := remaining(); !r[outsideWindow] {
+		t.Fatalf("expected events of days which were never rolled up to be kept")
+	}
+}
@@ -0,0 +1,266 @@
+// Package cleanup deletes the expired sessions and token caches which would otherwise be kept forever, slowing down
+// lookups on long-running installs.
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+// TableSessions is the table of the sessions deleted by a cleanup
+const TableSessions = "sessions"
+
+// Options configure a Cleaner. Zero values use the defaults.
+type Options struct {
+	// Interval is the time between scheduled cleanups. Defaults to 1h
+	Interval time.Duration
+	// BatchSize is the number of rows deleted by each statement. Defaults to 500
+	BatchSize int
+	// MaxRowsPerRun caps the rows deleted from each table in a single cleanup. Defaults to 10000
+	MaxRowsPerRun int
+	// MaxDuration bounds the time spent deleting rows in a single cleanup, across all tables. Defaults to 1m
+	MaxDuration time.Duration
+	// TokenCacheGracePeriod is how long after it expires a token cache is kept. The entries that clusters, registries
+	// and helm repos read are never deleted, so this only keeps superseded entries around for a refresh which read
+	// them just before they were replaced. Defaults to 24h
+	TokenCacheGracePeriod time.Duration
+	// Logger receives a record of the deleted rows. Optional
+	Logger *logger.Logger
+}
+
+// TableResult is the outcome of a cleanup for a single table
+type TableResult struct {
+	Table string `json:"table"`
+	// Deleted is the number of rows deleted from the table
+	Deleted int64 `json:"deleted"`
+	// Rows is the number of rows left in the table
+	Rows int64 `json:"rows"`
+	// Truncated is true if the cleanup stopped at its row cap or duration limit before every expired row was deleted
+	Truncated bool `json:"truncated"`
+	// Error is the error which stopped the cleanup of the table, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Result is the outcome of a cleanup
+type Result struct {
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Tables    []TableResult `json:"tables"`
+}
+
+// Cleaner deletes expired sessions and token caches in batches, on a schedule or when triggered
+type Cleaner struct {
+	sessions    repository.SessionRepository
+	tokenCaches repository.TokenCacheRepository
+	opts        Options
+
+	// runMu prevents a triggered cleanup from running at the same time as a scheduled one
+	runMu sync.Mutex
+
+	mu      sync.Mutex
+	deleted map[string]int64
+	rows    map[string]int64
+	runs    int64
+}
+
+// NewCleaner returns a Cleaner for the sessions and token caches in the given repositories
+func NewCleaner(sessions repository.SessionRepository, tokenCaches repository.TokenCacheRepository, opts Options) *Cleaner {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Hour
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.MaxRowsPerRun <= 0 {
+		opts.MaxRowsPerRun = 10000
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = time.Minute
+	}
+	if opts.TokenCacheGracePeriod <= 0 {
+		opts.TokenCacheGracePeriod = 24 * time.Hour
+	}
+
+	return &Cleaner{
+		sessions:    sessions,
+		tokenCaches: tokenCaches,
+		opts:        opts,
+		deleted:     make(map[string]int64),
+		rows:        make(map[string]int64),
+	}
+}
+
+// Run cleans up on every interval until ctx is cancelled
+func (c *Cleaner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.Clean(ctx, time.Now())
+		}
+	}
+}
+
+// Clean deletes the rows which expired before now, stopping at the row cap of each table and at the duration limit.
+// A failure to clean up one table does not stop the others from being cleaned up.
+func (c *Cleaner) Clean(ctx context.Context, now time.Time) *Result {
+	c.runMu.Lock()
+	defer c.runMu.Unlock()
+
+	// rows are only deleted within the duration limit, but the tables are still counted once it is reached
+	deleteCtx, cancel := context.WithTimeout(ctx, c.opts.MaxDuration)
+	defer cancel()
+
+	res := &Result{
+		StartedAt: now,
+		Tables:    make([]TableResult, 0, len(repository.TokenCacheTables)+1),
+	}
+
+	res.Tables = append(res.Tables, c.cleanTable(deleteCtx, TableSessions,
+		func(limit int) (int64, error) {
+			return c.sessions.DeleteExpiredSessions(deleteCtx, now, limit)
+		},
+		func() (int64, error) {
+			return c.sessions.CountSessions(ctx)
+		},
+	))
+
+	for _, table := range repository.TokenCacheTables {
+		table := table
+
+		res.Tables = append(res.Tables, c.cleanTable(deleteCtx, string(table),
+			func(limit int) (int64, error) {
+				return c.tokenCaches.DeleteExpiredTokenCaches(deleteCtx, table, now.Add(-c.opts.TokenCacheGracePeriod), limit)
+			},
+			func() (int64, error) {
+				return c.tokenCaches.CountTokenCaches(ctx, table)
+			},
+		))
+	}
+
+	res.Duration = time.Since(now)
+
+	c.mu.Lock()
+	c.runs++
+	for _, table := range res.Tables {
+		c.deleted[table.Table] += table.Deleted
+		if table.Error == "" {
+			c.rows[table.Table] = table.Rows
+		}
+	}
+	c.mu.Unlock()
+
+	return res
+}
+
+func (c *Cleaner) cleanTable(ctx context.Context, table string, deleteBatch func(limit int) (int64, error), count func() (int64, error)) TableResult {
+	res := TableResult{Table: table}
+
+	for {
+		if ctx.Err() != nil {
+			res.Truncated = true
+			break
+		}
+
+		remaining := c.opts.MaxRowsPerRun - int(res.Deleted)
+		if remaining <= 0 {
+			res.Truncated = true
+			break
+		}
+
+		limit := c.opts.BatchSize
+		if remaining < limit {
+			limit = remaining
+		}
+
+		deleted, err := deleteBatch(limit)
+		if err != nil {
+			if ctx.Err() != nil {
+				res.Truncated = true
+				break
+			}
+
+			res.Error = err.Error()
+			c.log(zerolog.ErrorLevel).Err(err).Str("table", table).Msg("error deleting expired rows")
+
+			return res
+		}
+
+		res.Deleted += deleted
+		if deleted < int64(limit) {
+			break
+		}
+	}
+
+	if res.Deleted > 0 {
+		c.log(zerolog.InfoLevel).Str("table", table).Int64("deleted", res.Deleted).Bool("truncated", res.Truncated).Msg("deleted expired rows")
+	}
+
+	rows, err := count()
+	if err != nil {
+		res.Error = err.Error()
+		c.log(zerolog.ErrorLevel).Err(err).Str("table", table).Msg("error counting rows")
+
+		return res
+	}
+	res.Rows = rows
+
+	return res
+}
+
+// WriteMetrics writes the rows deleted by every cleanup so far and the table sizes counted by the last one in the
+// prometheus text exposition format
+func (c *Cleaner) WriteMetrics(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tables := make([]string, 0, len(c.deleted))
+	for table := range c.deleted {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	if _, err := fmt.Fprintf(w, "# HELP porter_cleanup_rows_deleted_total The number of expired rows deleted from a table\n# TYPE porter_cleanup_rows_deleted_total counter\n"); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if _, err := fmt.Fprintf(w, "porter_cleanup_rows_deleted_total{table=\"%s\"} %d\n", table, c.deleted[table]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "# HELP porter_cleanup_table_rows The number of rows in a table after the last cleanup\n# TYPE porter_cleanup_table_rows gauge\n"); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if rows, ok := c.rows[table]; ok {
+			if _, err := fmt.Fprintf(w, "porter_cleanup_table_rows{table=\"%s\"} %d\n", table, rows); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "# HELP porter_cleanup_runs_total The number of cleanups run\n# TYPE porter_cleanup_runs_total counter\nporter_cleanup_runs_total %d\n", c.runs)
+
+	return err
+}
+
+func (c *Cleaner) log(level zerolog.Level) *zerolog.Event {
+	if c.opts.Logger == nil {
+		return nil
+	}
+
+	return c.opts.Logger.WithLevel(level)
+}
@@ -0,0 +1,146 @@
+package cleanup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+func seedSessions(t *testing.T, repo repository.SessionRepository, now time.Time, expired, live int) {
+	t.Helper()
+
+	for i := 0; i < expired; i++ {
+		if _, err := repo.CreateSession(&models.Session{Key: fmt.Sprintf("expired-%d", i), ExpiresAt: now.Add(-time.Duration(i+1) * time.Hour)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for i := 0; i < live; i++ {
+		if _, err := repo.CreateSession(&models.Session{Key: fmt.Sprintf("live-%d", i), ExpiresAt: now.Add(time.Duration(i+1) * time.Hour)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func tableResult(t *testing.T, res *Result, table string) TableResult {
+	t.Helper()
+
+	for _, tableRes := range res.Tables {
+		if tableRes.Table == table {
+			return tableRes
+		}
+	}
+
+	t.Fatalf("expected a result for %s, got %+v", table, res.Tables)
+	return TableResult{}
+}
+
+func TestCleanDeletesOnlyExpiredSessions(t *testing.T) {
+	now := time.Now()
+	repo := test.NewRepository(true)
+	seedSessions(t, repo.Session(), now, 5, 3)
+
+	cleaner := NewCleaner(repo.Session(), repo.TokenCache(), Options{BatchSize: 2})
+
+	sessions := tableResult(t, cleaner.Clean(context.Background(), now), TableSessions)
+	if sessions.Deleted != 5 || sessions.Rows != 3 || sessions.Truncated {
+		t.Errorf("expected the 5 expired sessions to be deleted in batches, got %+v", sessions)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := repo.Session().SelectSession(&models.Session{Key: fmt.Sprintf("live-%d", i)}); err != nil {
+			t.Errorf("expected live-%d to be kept, got %v", i, err)
+		}
+	}
+}
+
+func TestCleanStopsAtTheRowCap(t *testing.T) {
+	now := time.Now()
+	repo := test.NewRepository(true)
+	seedSessions(t, repo.Session(), now, 5, 1)
+
+	cleaner := NewCleaner(repo.Session(), repo.TokenCache(), Options{BatchSize: 2, MaxRowsPerRun: 3})
+
+	sessions := tableResult(t, cleaner.Clean(context.Background(), now), TableSessions)
+	if sessions.Deleted != 3 || sessions.Rows != 3 || !sessions.Truncated {
+		t.Errorf("expected the cleanup to stop after 3 sessions, got %+v", sessions)
+	}
+
+	sessions = tableResult(t, cleaner.Clean(context.Background(), now), TableSessions)
+	if sessions.Deleted != 2 || sessions.Rows != 1 || sessions.Truncated {
+		t.Errorf("expected the next cleanup to delete the rest, got %+v", sessions)
+	}
+
+	var metrics bytes.Buffer
+	if err := cleaner.WriteMetrics(&metrics); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range []string{
+		`porter_cleanup_rows_deleted_total{table="sessions"} 5`,
+		`porter_cleanup_table_rows{table="sessions"} 1`,
+		`porter_cleanup_runs_total 2`,
+	} {
+		if !strings.Contains(metrics.String(), line) {
+			t.Errorf("expected the metrics to contain %q, got\n%s", line, metrics.String())
+		}
+	}
+}
+
+// slowSessions is a session repository whose deletes take longer than the duration limit of a cleanup
+type slowSessions struct {
+	repository.SessionRepository
+	deletes int
+}
+
+func (s *slowSessions) DeleteExpiredSessions(ctx context.Context, before time.Time, limit int) (int64, error) {
+	s.deletes++
+
+	<-ctx.Done()
+	return 0, ctx.Err()
+}
+
+func TestCleanStopsAtTheDurationLimit(t *testing.T) {
+	now := time.Now()
+	repo := test.NewRepository(true)
+	seedSessions(t, repo.Session(), now, 2, 1)
+
+	sessions := &slowSessions{SessionRepository: repo.Session()}
+	cleaner := NewCleaner(sessions, repo.TokenCache(), Options{MaxDuration: 10 * time.Millisecond})
+
+	res := cleaner.Clean(context.Background(), now)
+
+	sessionsRes := tableResult(t, res, TableSessions)
+	if !sessionsRes.Truncated || sessionsRes.Error != "" || sessionsRes.Rows != 3 {
+		t.Errorf("expected the cleanup to stop at the duration limit and still count the sessions, got %+v", sessionsRes)
+	}
+	if sessions.deletes != 1 {
+		t.Errorf("expected a single delete before the duration limit, got %d", sessions.deletes)
+	}
+
+	for _, table := range repository.TokenCacheTables {
+		if tableRes := tableResult(t, res, string(table)); !tableRes.Truncated {
+			t.Errorf("expected %s not to be cleaned up after the duration limit, got %+v", table, tableRes)
+		}
+	}
+}
+
+func TestCleanContinuesAfterAFailedTable(t *testing.T) {
+	now := time.Now()
+	repo := test.NewRepository(true)
+
+	cleaner := NewCleaner(test.NewRepository(false).Session(), repo.TokenCache(), Options{})
+
+	res := cleaner.Clean(context.Background(), now)
+	if tableResult(t, res, TableSessions).Error == "" {
+		t.Error("expected the session cleanup to fail")
+	}
+	if clusters := tableResult(t, res, string(repository.TokenCacheTable_Cluster)); clusters.Error != "" {
+		t.Errorf("expected the token caches to still be cleaned up, got %+v", clusters)
+	}
+}
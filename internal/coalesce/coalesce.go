@@ -0,0 +1,312 @@
+// Package coalesce merges identical concurrent queries against a cluster into a single upstream call,
+// so that many dashboards polling the same stack do not each hit the kubernetes api. Results are also kept for a short
+// time, so that polls made just after a query are served without calling the cluster again.
+package coalesce
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// QueryType is the kind of query being coalesced
+type QueryType string
+
+const (
+	// QueryType_ServiceStatus is a query for the status of an app's services
+	QueryType_ServiceStatus QueryType = "service-status"
+	// QueryType_PodStatus is a query for the pods of an app
+	QueryType_PodStatus QueryType = "pod-status"
+	// QueryType_Release is a query for the helm release of a stack
+	QueryType_Release QueryType = "release"
+	// QueryType_ReleaseHistory is a query for the revisions of the helm release of a stack
+	QueryType_ReleaseHistory QueryType = "release-history"
+	// QueryType_CronStatus is a query for the last runs of the cron jobs of a stack
+	QueryType_CronStatus QueryType = "cron-status"
+)
+
+var queryTypes = []QueryType{QueryType_ServiceStatus, QueryType_PodStatus, QueryType_Release, QueryType_ReleaseHistory, QueryType_CronStatus}
+
+// Source is how the result of a query was served
+type Source string
+
+const (
+	// Source_Miss is a result read from the cluster for the query
+	Source_Miss Source = "miss"
+	// Source_Shared is a result read from the cluster for an identical query which was in flight
+	Source_Shared Source = "shared"
+	// Source_Hit is a result of an earlier query, served from the cache
+	Source_Hit Source = "hit"
+	// Source_Bypass is a result read from the cluster for a query which asked to skip the cache
+	Source_Bypass Source = "bypass"
+)
+
+var sources = []Source{Source_Miss, Source_Shared, Source_Hit, Source_Bypass}
+
+const (
+	// HeaderBypass is the request header which makes the queries of a request skip the cache, for debugging
+	HeaderBypass = "X-Porter-Cache-Bypass"
+	// HeaderCache is the response header which reports how the query of a request was served
+	HeaderCache = "X-Porter-Cache"
+)
+
+// Key identifies a query; only queries with identical keys share an upstream call or a cached result
+type Key struct {
+	ClusterID uint
+	Namespace string
+	StackName string
+	QueryType QueryType
+	// Params contains any other inputs to the query, such as the service name or release revision
+	Params string
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%d/%s/%s/%s/%s", k.ClusterID, k.Namespace, k.StackName, k.QueryType, k.Params)
+}
+
+// stackKey identifies the queries of a stack which are invalidated together
+type stackKey struct {
+	clusterID uint
+	stackName string
+}
+
+func (k Key) stack() stackKey {
+	return stackKey{clusterID: k.ClusterID, stackName: k.StackName}
+}
+
+// Options controls how a single query is served
+type Options struct {
+	// Bypass reads from the cluster without using or sharing the cache or in-flight queries
+	Bypass bool
+}
+
+// OptionsFromRequest returns the options requested by the headers of r
+func OptionsFromRequest(r *http.Request) Options {
+	bypass, _ := strconv.ParseBool(r.Header.Get(HeaderBypass))
+	return Options{Bypass: bypass}
+}
+
+// Result describes how the result of a query was served. It is empty when coalescing is disabled.
+type Result struct {
+	Source Source
+	// Age is how long ago a cached result was read from the cluster
+	Age time.Duration
+}
+
+// SetHeaders reports how the result was served in the headers of the response, with the standard Age header for
+// cached results. It must be called before the response is written.
+func (r Result) SetHeaders(w http.ResponseWriter) {
+	if r.Source == "" {
+		return
+	}
+
+	w.Header().Set(HeaderCache, string(r.Source))
+	if r.Source == Source_Hit {
+		w.Header().Set("Age", strconv.Itoa(int(r.Age.Seconds())))
+	}
+}
+
+type entry struct {
+	value    interface{}
+	storedAt time.Time
+}
+
+// Coalescer shares the result of an in-flight query with every identical query made while it is running, and serves
+// results to identical queries made within its ttl. A nil or disabled Coalescer calls through to the upstream on every
+// query.
+type Coalescer struct {
+	enabled bool
+	ttl     time.Duration
+	group   singleflight.Group
+
+	mu      sync.Mutex
+	entries map[stackKey]map[Key]entry
+	// generations are incremented when the queries of a stack are invalidated, so that queries which were in flight
+	// are neither cached nor shared with the queries made after the invalidation
+	generations map[stackKey]uint64
+	lastSweep   time.Time
+	counts      map[QueryType]map[Source]uint64
+}
+
+// NewCoalescer returns a new Coalescer which caches results for ttl. A ttl of zero only coalesces in-flight queries.
+func NewCoalescer(enabled bool, ttl time.Duration) *Coalescer {
+	return &Coalescer{
+		enabled:     enabled,
+		ttl:         ttl,
+		entries:     make(map[stackKey]map[Key]entry),
+		generations: make(map[stackKey]uint64),
+		counts:      make(map[QueryType]map[Source]uint64),
+	}
+}
+
+// Do returns a cached result for the query if one was read within the ttl, and otherwise calls fn, unless an identical
+// query is already in flight, in which case it waits for and returns that query's result. Errors are not cached.
+// The result is shared between callers, so it must not be modified.
+func Do[T any](c *Coalescer, key Key, opts Options, fn func() (T, error)) (T, Result, error) {
+	if c == nil || !c.enabled {
+		val, err := fn()
+		return val, Result{}, err
+	}
+
+	if opts.Bypass {
+		c.count(key.QueryType, Source_Bypass)
+
+		val, err := fn()
+		return val, Result{Source: Source_Bypass}, err
+	}
+
+	if cached, age, ok := c.lookup(key); ok {
+		if val, ok := cached.(T); ok {
+			c.count(key.QueryType, Source_Hit)
+			return val, Result{Source: Source_Hit, Age: age}, nil
+		}
+	}
+
+	generation := c.generation(key)
+	called := false
+
+	res, err, _ := c.group.Do(fmt.Sprintf("%s#%d", key, generation), func() (interface{}, error) {
+		called = true
+
+		val, err := fn()
+		if err == nil {
+			c.store(key, generation, val)
+		}
+
+		return val, err
+	})
+
+	source := Source_Shared
+	if called {
+		source = Source_Miss
+	}
+	c.count(key.QueryType, source)
+
+	val, _ := res.(T)
+	return val, Result{Source: source}, err
+}
+
+// Invalidate drops the cached results of every query of a stack, and stops queries which are in flight from being
+// cached or shared. It is called after the stack is changed, such as by a deploy, scale or rollback.
+func (c *Coalescer) Invalidate(clusterID uint, stackName string) {
+	if c == nil || !c.enabled {
+		return
+	}
+
+	stack := stackKey{clusterID: clusterID, stackName: stackName}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, stack)
+	c.generations[stack]++
+}
+
+// WriteMetrics writes the number of queries by how they were served, in the prometheus text format
+func (c *Coalescer) WriteMetrics(w io.Writer) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP porter_status_query_results_total The number of status, release and revision queries by how they were served\n# TYPE porter_status_query_results_total counter\n"); err != nil {
+		return err
+	}
+	for _, queryType := range queryTypes {
+		for _, source := range sources {
+			if _, err := fmt.Fprintf(w, "porter_status_query_results_total{query_type=\"%s\",result=\"%s\"} %d\n", queryType, source, c.counts[queryType][source]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Coalescer) lookup(key Key) (interface{}, time.Duration, bool) {
+	if c.ttl <= 0 {
+		return nil, 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key.stack()][key]
+	if !ok {
+		return nil, 0, false
+	}
+
+	age := time.Since(e.storedAt)
+	if age >= c.ttl {
+		delete(c.entries[key.stack()], key)
+		return nil, 0, false
+	}
+
+	return e.value, age, true
+}
+
+func (c *Coalescer) generation(key Key) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generations[key.stack()]
+}
+
+// store caches the result of a query, unless the stack was invalidated since the query started
+func (c *Coalescer) store(key Key, generation uint64, value interface{}) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stack := key.stack()
+	if c.generations[stack] != generation {
+		return
+	}
+
+	now := time.Now()
+	c.sweep(now)
+
+	if c.entries[stack] == nil {
+		c.entries[stack] = make(map[Key]entry)
+	}
+	c.entries[stack][key] = entry{value: value, storedAt: now}
+}
+
+// sweep drops expired results, at most once per ttl, so that stacks which are no longer polled do not hold on to them
+func (c *Coalescer) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+
+	for stack, entries := range c.entries {
+		for key, e := range entries {
+			if now.Sub(e.storedAt) >= c.ttl {
+				delete(entries, key)
+			}
+		}
+		if len(entries) == 0 {
+			delete(c.entries, stack)
+		}
+	}
+}
+
+func (c *Coalescer) count(queryType QueryType, source Source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counts[queryType] == nil {
+		c.counts[queryType] = make(map[Source]uint64)
+	}
+	c.counts[queryType][source]++
+}
@@ -0,0 +1,255 @@
+package coalesce_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/coalesce"
+)
+
+// fireConcurrently makes n concurrent queries with the given key, holding the upstream call open until every query has
+// been made, and returns the results along with the number of upstream calls
+func fireConcurrently(t *testing.T, c *coalesce.Coalescer, key func(i int) coalesce.Key, n int) ([]string, int32) {
+	t.Helper()
+
+	var calls int32
+	release := make(chan struct{})
+
+	var started, done sync.WaitGroup
+	started.Add(n)
+	done.Add(n)
+
+	results := make([]string, n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+
+			res, _, err := coalesce.Do(c, key(i), coalesce.Options{}, func() (string, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return "ready", nil
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			results[i] = res
+		}(i)
+	}
+
+	// give every goroutine time to join the in-flight call before letting the upstream return
+	started.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	return results, atomic.LoadInt32(&calls)
+}
+
+func TestIdenticalConcurrentQueriesShareOneUpstreamCall(t *testing.T) {
+	key := coalesce.Key{ClusterID: 1, StackName: "web", QueryType: coalesce.QueryType_ServiceStatus, Params: "dt/web"}
+
+	results, calls := fireConcurrently(t, coalesce.NewCoalescer(true, 0), func(int) coalesce.Key { return key }, 50)
+
+	if calls != 1 {
+		t.Errorf("expected 1 upstream call, got %d", calls)
+	}
+	for i, res := range results {
+		if res != "ready" {
+			t.Errorf("query %d: expected shared result, got %q", i, res)
+		}
+	}
+}
+
+func TestDifferentQueriesAreNotCoalesced(t *testing.T) {
+	queryTypes := []coalesce.QueryType{coalesce.QueryType_ServiceStatus, coalesce.QueryType_PodStatus, coalesce.QueryType_Release}
+
+	_, calls := fireConcurrently(t, coalesce.NewCoalescer(true, 0), func(i int) coalesce.Key {
+		return coalesce.Key{ClusterID: uint(i % 2), StackName: "web", QueryType: queryTypes[i%3]}
+	}, 60)
+
+	if calls != 6 {
+		t.Errorf("expected 6 upstream calls, got %d", calls)
+	}
+}
+
+func TestDisabledCoalescerCallsUpstreamEveryTime(t *testing.T) {
+	key := coalesce.Key{ClusterID: 1, StackName: "web", QueryType: coalesce.QueryType_Release}
+
+	for _, c := range []*coalesce.Coalescer{coalesce.NewCoalescer(false, 0), nil} {
+		_, calls := fireConcurrently(t, c, func(int) coalesce.Key { return key }, 10)
+		if calls != 10 {
+			t.Errorf("expected 10 upstream calls, got %d", calls)
+		}
+	}
+}
+
+// query calls Do with an upstream which counts its calls and returns the number of calls so far
+func query(t *testing.T, c *coalesce.Coalescer, key coalesce.Key, opts coalesce.Options, calls *int) (int, coalesce.Result) {
+	t.Helper()
+
+	res, result, err := coalesce.Do(c, key, opts, func() (int, error) {
+		*calls++
+		return *calls, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return res, result
+}
+
+func TestRepeatQueriesAreServedFromCacheWithinTTL(t *testing.T) {
+	c := coalesce.NewCoalescer(true, 50*time.Millisecond)
+	key := coalesce.Key{ClusterID: 1, Namespace: "porter-stack-web", StackName: "web", QueryType: coalesce.QueryType_Release}
+
+	var calls int
+	res, result := query(t, c, key, coalesce.Options{}, &calls)
+	if res != 1 || result.Source != coalesce.Source_Miss {
+		t.Fatalf("expected the first query to call upstream, got %d from %s", res, result.Source)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	res, result = query(t, c, key, coalesce.Options{}, &calls)
+	if res != 1 || result.Source != coalesce.Source_Hit {
+		t.Fatalf("expected a repeat query to be served from cache, got %d from %s", res, result.Source)
+	}
+	if result.Age < 10*time.Millisecond {
+		t.Errorf("expected the cached result to report its age, got %s", result.Age)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	res, result = query(t, c, key, coalesce.Options{}, &calls)
+	if res != 2 || result.Source != coalesce.Source_Miss {
+		t.Errorf("expected an expired result to be read again, got %d from %s", res, result.Source)
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	c := coalesce.NewCoalescer(true, time.Minute)
+	key := coalesce.Key{ClusterID: 1, StackName: "web", QueryType: coalesce.QueryType_ReleaseHistory}
+
+	_, _, err := coalesce.Do(c, key, coalesce.Options{}, func() (int, error) {
+		return 0, errors.New("cluster unreachable")
+	})
+	if err == nil {
+		t.Fatal("expected the upstream error to be returned")
+	}
+
+	var calls int
+	if res, result := query(t, c, key, coalesce.Options{}, &calls); res != 1 || result.Source != coalesce.Source_Miss {
+		t.Errorf("expected the query after an error to call upstream, got %d from %s", res, result.Source)
+	}
+}
+
+func TestInvalidateDropsCachedResultsOfStack(t *testing.T) {
+	c := coalesce.NewCoalescer(true, time.Minute)
+	web := coalesce.Key{ClusterID: 1, Namespace: "porter-stack-web", StackName: "web", QueryType: coalesce.QueryType_ServiceStatus, Params: "dt/api"}
+	worker := coalesce.Key{ClusterID: 1, Namespace: "porter-stack-worker", StackName: "worker", QueryType: coalesce.QueryType_ServiceStatus}
+
+	var webCalls, workerCalls int
+	query(t, c, web, coalesce.Options{}, &webCalls)
+	query(t, c, worker, coalesce.Options{}, &workerCalls)
+
+	c.Invalidate(1, "web")
+
+	if res, result := query(t, c, web, coalesce.Options{}, &webCalls); res != 2 || result.Source != coalesce.Source_Miss {
+		t.Errorf("expected the invalidated stack to be read again, got %d from %s", res, result.Source)
+	}
+	if res, result := query(t, c, worker, coalesce.Options{}, &workerCalls); res != 1 || result.Source != coalesce.Source_Hit {
+		t.Errorf("expected other stacks to stay cached, got %d from %s", res, result.Source)
+	}
+}
+
+func TestQueryInFlightDuringInvalidateIsNotCached(t *testing.T) {
+	c := coalesce.NewCoalescer(true, time.Minute)
+	key := coalesce.Key{ClusterID: 1, StackName: "web", QueryType: coalesce.QueryType_Release}
+
+	_, _, err := coalesce.Do(c, key, coalesce.Options{}, func() (int, error) {
+		// the stack is deployed while its release is being read, so the result may be stale
+		c.Invalidate(1, "web")
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := 1
+	if res, result := query(t, c, key, coalesce.Options{}, &calls); res != 2 || result.Source != coalesce.Source_Miss {
+		t.Errorf("expected the result read during the invalidation not to be cached, got %d from %s", res, result.Source)
+	}
+}
+
+func TestBypassSkipsCache(t *testing.T) {
+	c := coalesce.NewCoalescer(true, time.Minute)
+	key := coalesce.Key{ClusterID: 1, StackName: "web", QueryType: coalesce.QueryType_PodStatus}
+
+	var calls int
+	query(t, c, key, coalesce.Options{}, &calls)
+
+	res, result := query(t, c, key, coalesce.Options{Bypass: true}, &calls)
+	if res != 2 || result.Source != coalesce.Source_Bypass {
+		t.Errorf("expected a bypassing query to call upstream, got %d from %s", res, result.Source)
+	}
+
+	if res, _ := query(t, c, key, coalesce.Options{}, &calls); res != 1 {
+		t.Errorf("expected a bypassing query to leave the cache alone, got %d", res)
+	}
+}
+
+func TestResultHeaders(t *testing.T) {
+	rr := httptest.NewRecorder()
+	coalesce.Result{Source: coalesce.Source_Hit, Age: 3500 * time.Millisecond}.SetHeaders(rr)
+
+	if got := rr.Header().Get(coalesce.HeaderCache); got != "hit" {
+		t.Errorf("expected cache header to be hit, got %q", got)
+	}
+	if got := rr.Header().Get("Age"); got != "3" {
+		t.Errorf("expected age header to be 3, got %q", got)
+	}
+
+	rr = httptest.NewRecorder()
+	coalesce.Result{}.SetHeaders(rr)
+	if len(rr.Header()) != 0 {
+		t.Errorf("expected no headers when coalescing is disabled, got %v", rr.Header())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(coalesce.HeaderBypass, "true")
+	if !coalesce.OptionsFromRequest(req).Bypass {
+		t.Error("expected the bypass header to be read")
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	c := coalesce.NewCoalescer(true, time.Minute)
+	key := coalesce.Key{ClusterID: 1, StackName: "web", QueryType: coalesce.QueryType_Release}
+
+	var calls int
+	query(t, c, key, coalesce.Options{}, &calls)
+	query(t, c, key, coalesce.Options{}, &calls)
+	query(t, c, key, coalesce.Options{}, &calls)
+
+	var sb strings.Builder
+	if err := c.WriteMetrics(&sb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, line := range []string{
+		`porter_status_query_results_total{query_type="release",result="miss"} 1`,
+		`porter_status_query_results_total{query_type="release",result="hit"} 2`,
+		`porter_status_query_results_total{query_type="pod-status",result="hit"} 0`,
+	} {
+		if !strings.Contains(sb.String(), line+"\n") {
+			t.Errorf("expected metrics to contain %s, got:\n%s", line, sb.String())
+		}
+	}
+}
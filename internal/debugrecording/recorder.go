@@ -0,0 +1,171 @@
+package debugrecording
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/random"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/porter-dev/porter/pkg/redact"
+	"gorm.io/gorm"
+)
+
+// keyPrefix is the prefix of every object written by the recorder
+const keyPrefix = "debug-recordings"
+
+// RecorderOptions are the options of a Recorder
+type RecorderOptions struct {
+	Repo  repository.DebugRecordingRepository
+	Store Store
+	// MaxExchanges caps the exchanges written for a single recording, so that a busy project cannot fill the store
+	MaxExchanges uint
+	// CacheTTL is how long the active recording of a project is cached for. Requests are checked against the cached
+	// recording's expiry, but a recording which is stopped early may keep recording for up to this long.
+	CacheTTL time.Duration
+}
+
+// Recorder writes the exchanges of projects with an active debug recording to the store
+type Recorder struct {
+	repo         repository.DebugRecordingRepository
+	store        Store
+	maxExchanges uint
+	cacheTTL     time.Duration
+
+	now func() time.Time
+
+	mu    sync.Mutex
+	cache map[uint]cachedRecording
+}
+
+type cachedRecording struct {
+	recording *models.DebugRecording
+	fetchedAt time.Time
+}
+
+// NewRecorder returns a new Recorder
+func NewRecorder(opts RecorderOptions) *Recorder {
+	return &Recorder{
+		repo:         opts.Repo,
+		store:        opts.Store,
+		maxExchanges: opts.MaxExchanges,
+		cacheTTL:     opts.CacheTTL,
+		now:          time.Now,
+		cache:        make(map[uint]cachedRecording),
+	}
+}
+
+// Active returns the active recording of a project, or false if the project's calls are not being recorded. Lookups
+// are cached, since every project-scoped request checks for a recording.
+func (r *Recorder) Active(ctx context.Context, projectID uint) (*models.DebugRecording, bool) {
+	now := r.now()
+
+	r.mu.Lock()
+	cached, ok := r.cache[projectID]
+	r.mu.Unlock()
+
+	if !ok || now.Sub(cached.fetchedAt) >= r.cacheTTL {
+		recording, err := r.repo.ReadActiveDebugRecording(ctx, projectID, now)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			// recording is best effort, so a failed lookup does not fail the request
+			return nil, false
+		}
+
+		cached = cachedRecording{recording: recording, fetchedAt: now}
+
+		r.mu.Lock()
+		r.cache[projectID] = cached
+		r.mu.Unlock()
+	}
+
+	if cached.recording == nil || !cached.recording.IsActive(now) {
+		return nil, false
+	}
+
+	return cached.recording, true
+}
+
+// Invalidate drops the cached recording of a project, so that a recording which was started or stopped on this
+// server takes effect immediately
+func (r *Recorder) Invalidate(projectID uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.cache, projectID)
+}
+
+// Record writes a redacted exchange to the store. Exchanges past the cap of the recording are dropped.
+func (r *Recorder) Record(ctx context.Context, recording *models.DebugRecording, exchange redact.Exchange) error {
+	ctx, span := telemetry.NewSpan(ctx, "debug-recording-record")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: recording.ProjectID},
+		telemetry.AttributeKV{Key: "debug-recording-id", Value: recording.ID},
+	)
+
+	ok, err := r.repo.ReserveDebugRecordingExchange(ctx, recording.ID, r.maxExchanges)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error reserving exchange")
+	}
+	if !ok {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "recording-full", Value: true})
+		return nil
+	}
+
+	data, err := json.Marshal(exchange)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error encoding exchange")
+	}
+
+	if err := r.store.Put(ctx, exchangeKey(recording, exchange), data); err != nil {
+		return telemetry.Error(ctx, span, err, "error writing exchange")
+	}
+
+	return nil
+}
+
+// ListExchanges returns up to limit exchanges of a recording, oldest first
+func (r *Recorder) ListExchanges(ctx context.Context, recording *models.DebugRecording, limit int) ([]types.DebugRecordingExchange, bool, error) {
+	ctx, span := telemetry.NewSpan(ctx, "debug-recording-list-exchanges")
+	defer span.End()
+
+	keys, truncated, err := r.store.List(ctx, recordingPrefix(recording), limit)
+	if err != nil {
+		return nil, false, telemetry.Error(ctx, span, err, "error listing exchanges")
+	}
+
+	exchanges := make([]types.DebugRecordingExchange, 0, len(keys))
+	for _, key := range keys {
+		data, err := r.store.Get(ctx, key)
+		if err != nil {
+			if errors.Is(err, ErrObjectNotFound) {
+				continue
+			}
+
+			return nil, false, telemetry.Error(ctx, span, err, "error reading exchange")
+		}
+
+		exchanges = append(exchanges, types.DebugRecordingExchange{Key: key, Exchange: data})
+	}
+
+	return exchanges, truncated, nil
+}
+
+func recordingPrefix(recording *models.DebugRecording) string {
+	return fmt.Sprintf("%s/%d/%d/", keyPrefix, recording.ProjectID, recording.ID)
+}
+
+// exchangeKey orders the exchanges of a recording by time when keys are listed lexically
+func exchangeKey(recording *models.DebugRecording, exchange redact.Exchange) string {
+	// the suffix keeps exchanges which started in the same nanosecond apart
+	suffix, _ := random.StringWithCharset(6, "abcdefghijklmnopqrstuvwxyz0123456789")
+
+	return fmt.Sprintf("%s%s-%s.json", recordingPrefix(recording), exchange.Time.UTC().Format("20060102T150405.000000000Z"), suffix)
+}
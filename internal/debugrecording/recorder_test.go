@@ -0,0 +1,97 @@
+package debugrecording_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/debugrecording"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+	"github.com/porter-dev/porter/pkg/redact"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := debugrecording.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %v", err)
+	}
+
+	repo := test.NewDebugRecordingRepository(true)
+	recorder := debugrecording.NewRecorder(debugrecording.RecorderOptions{
+		Repo:         repo,
+		Store:        store,
+		MaxExchanges: 2,
+		CacheTTL:     time.Hour,
+	})
+
+	if _, ok := recorder.Active(ctx, 1); ok {
+		t.Fatal("expected no active recording before one is started")
+	}
+
+	recording, err := repo.CreateDebugRecording(ctx, &models.DebugRecording{
+		ProjectID: 1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating recording: %v", err)
+	}
+
+	if _, ok := recorder.Active(ctx, 1); ok {
+		t.Fatal("expected the cached lookup to be used until it is invalidated")
+	}
+	recorder.Invalidate(1)
+
+	active, ok := recorder.Active(ctx, 1)
+	if !ok || active.ID != recording.ID {
+		t.Fatalf("expected recording %d to be active, got %v", recording.ID, active)
+	}
+
+	for i := 0; i < 3; i++ {
+		exchange := redact.NewExchange(redact.ExchangeInput{
+			Start:       time.Now(),
+			End:         time.Now(),
+			Method:      http.MethodPost,
+			URL:         "/api/projects/1/registries",
+			RequestBody: []byte(`{"password":"hunter2"}`),
+			StatusCode:  http.StatusOK,
+		})
+
+		if err := recorder.Record(ctx, active, exchange); err != nil {
+			t.Fatalf("unexpected error recording exchange: %v", err)
+		}
+	}
+
+	exchanges, truncated, err := recorder.ListExchanges(ctx, active, 10)
+	if err != nil {
+		t.Fatalf("unexpected error listing exchanges: %v", err)
+	}
+	if len(exchanges) != 2 || truncated {
+		t.Fatalf("expected the recording to be capped at 2 exchanges, got %d", len(exchanges))
+	}
+	for _, exchange := range exchanges {
+		if strings.Contains(string(exchange.Exchange), "hunter2") {
+			t.Errorf("expected the stored exchange to be redacted, got %s", exchange.Exchange)
+		}
+	}
+
+	exchanges, truncated, err = recorder.ListExchanges(ctx, active, 1)
+	if err != nil || len(exchanges) != 1 || !truncated {
+		t.Fatalf("expected listing to be truncated at the limit, got %d exchanges, %v", len(exchanges), err)
+	}
+
+	stoppedAt := time.Now()
+	recording.StoppedAt = &stoppedAt
+	if _, err := repo.UpdateDebugRecording(ctx, recording); err != nil {
+		t.Fatalf("unexpected error stopping recording: %v", err)
+	}
+	recorder.Invalidate(1)
+
+	if _, ok := recorder.Active(ctx, 1); ok {
+		t.Fatal("expected no active recording once it is stopped")
+	}
+}
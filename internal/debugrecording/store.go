@@ -0,0 +1,226 @@
+// Package debugrecording records the API calls of a project to an object store while an instance admin has a debug
+// recording running for it. Exchanges are redacted with the same rules as CLI session recordings before they are
+// written.
+package debugrecording
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ErrObjectNotFound is returned when an object does not exist in the store
+var ErrObjectNotFound = errors.New("object does not exist")
+
+// Store is the object store recorded exchanges are written to
+type Store interface {
+	// Put writes an object
+	Put(ctx context.Context, key string, data []byte) error
+	// Get reads an object, returning ErrObjectNotFound if it does not exist
+	Get(ctx context.Context, key string) ([]byte, error)
+	// List returns up to limit keys with the given prefix, in lexical order, and whether there were more
+	List(ctx context.Context, prefix string, limit int) ([]string, bool, error)
+}
+
+// S3Options are the options of an S3 store
+type S3Options struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Bucket          string
+}
+
+// S3Store is a store backed by an S3 bucket
+type S3Store struct {
+	client *s3.S3
+	bucket string
+}
+
+// NewS3Store returns a store backed by an S3 bucket. If no access key is set, the default AWS credential chain is used.
+func NewS3Store(opts S3Options) (*S3Store, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("bucket is required")
+	}
+
+	awsConf := &aws.Config{
+		Region: aws.String(opts.Region),
+	}
+	if opts.AccessKeyID != "" {
+		awsConf.Credentials = credentials.NewStaticCredentials(opts.AccessKeyID, opts.SecretAccessKey, "")
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            *awsConf,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create AWS session: %w", err)
+	}
+
+	return &S3Store{
+		client: s3.New(sess),
+		bucket: opts.Bucket,
+	}, nil
+}
+
+// Put writes an object
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Body:        aws.ReadSeekCloser(bytes.NewReader(data)),
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String("application/json"),
+	})
+
+	return err
+}
+
+// Get reads an object
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	output, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrObjectNotFound
+		}
+
+		return nil, err
+	}
+	defer output.Body.Close() // nolint:errcheck
+
+	return io.ReadAll(output.Body)
+}
+
+// List returns up to limit keys with the given prefix
+func (s *S3Store) List(ctx context.Context, prefix string, limit int) ([]string, bool, error) {
+	keys := make([]string, 0)
+	truncated := false
+
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			if len(keys) == limit {
+				truncated = true
+				return false
+			}
+
+			keys = append(keys, aws.StringValue(object.Key))
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return keys, truncated, nil
+}
+
+// FileStore is a store backed by a directory, for servers which run without an object store such as local ones
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a store which writes objects as files under dir
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, errors.New("directory is required")
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating directory: %w", err)
+	}
+
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(key string) (string, error) {
+	p := filepath.Join(s.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(p, filepath.Clean(s.dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+
+	return p, nil
+}
+
+// Put writes an object
+func (s *FileStore) Put(ctx context.Context, key string, data []byte) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(p, data, 0o600)
+}
+
+// Get reads an object
+func (s *FileStore) Get(ctx context.Context, key string) ([]byte, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+
+	return data, err
+}
+
+// List returns up to limit keys with the given prefix
+func (s *FileStore) List(ctx context.Context, prefix string, limit int) ([]string, bool, error) {
+	keys := make([]string, 0)
+
+	err := filepath.WalkDir(s.dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	sort.Strings(keys)
+
+	if len(keys) > limit {
+		return keys[:limit], true, nil
+	}
+
+	return keys, false, nil
+}
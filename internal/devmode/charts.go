@@ -0,0 +1,195 @@
+package devmode
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/repo"
+	"sigs.k8s.io/yaml"
+)
+
+// charts are the minimal web, worker and job charts which porter apps are built from in dev mode
+//
+//go:embed charts
+var charts embed.FS
+
+// ChartRepo is a helm repo serving the embedded charts on a loopback address, so that apps can be deployed in dev mode
+// without reaching the Porter chart repos
+type ChartRepo struct {
+	// URL is the address of the repo, which is used as the app and add-on helm repo URLs
+	URL string
+
+	server   *http.Server
+	index    []byte
+	archives map[string][]byte
+}
+
+// StartChartRepo packages the embedded charts and serves them on a free loopback port until the repo is closed
+func StartChartRepo() (*ChartRepo, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("error listening for chart repo: %w", err)
+	}
+
+	r := &ChartRepo{
+		URL:      "http://" + listener.Addr().String(),
+		archives: make(map[string][]byte),
+	}
+
+	if err := r.packageCharts(); err != nil {
+		listener.Close() // nolint:errcheck
+		return nil, err
+	}
+
+	r.server = &http.Server{
+		Handler:           r,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go r.server.Serve(listener) // nolint:errcheck
+
+	return r, nil
+}
+
+// Close stops serving the repo
+func (r *ChartRepo) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.server.Shutdown(ctx)
+}
+
+// ServeHTTP serves the index of the repo and the chart archives it lists
+func (r *ChartRepo) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	name := strings.TrimPrefix(req.URL.Path, "/")
+
+	if name == "index.yaml" {
+		w.Header().Set("Content-Type", "application/x-yaml")
+		w.Write(r.index) // nolint:errcheck
+		return
+	}
+
+	archive, ok := r.archives[name]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Write(archive) // nolint:errcheck
+}
+
+// packageCharts archives each embedded chart and writes the index listing them
+func (r *ChartRepo) packageCharts() error {
+	dirs, err := charts.ReadDir("charts")
+	if err != nil {
+		return fmt.Errorf("error reading embedded charts: %w", err)
+	}
+
+	index := &repo.IndexFile{
+		APIVersion: repo.APIVersionV1,
+		Entries:    make(map[string]repo.ChartVersions),
+		Generated:  time.Now(),
+	}
+
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+
+		metadata, archive, err := packageChart(dir.Name())
+		if err != nil {
+			return fmt.Errorf("error packaging chart %s: %w", dir.Name(), err)
+		}
+
+		filename := fmt.Sprintf("%s-%s.tgz", metadata.Name, metadata.Version)
+		digest := sha256.Sum256(archive)
+
+		r.archives[filename] = archive
+		index.Entries[metadata.Name] = append(index.Entries[metadata.Name], &repo.ChartVersion{
+			Metadata: metadata,
+			URLs:     []string{r.URL + "/" + filename},
+			Created:  index.Generated,
+			Digest:   hex.EncodeToString(digest[:]),
+		})
+	}
+
+	if len(index.Entries) == 0 {
+		return errors.New("no charts are embedded")
+	}
+
+	r.index, err = yaml.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("error encoding chart repo index: %w", err)
+	}
+
+	return nil
+}
+
+// packageChart returns the metadata of an embedded chart and its gzipped tar archive, laid out as helm package does
+func packageChart(name string) (*chart.Metadata, []byte, error) {
+	root := path.Join("charts", name)
+
+	chartYAML, err := charts.ReadFile(path.Join(root, "Chart.yaml"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metadata := &chart.Metadata{}
+	if err := yaml.Unmarshal(chartYAML, metadata); err != nil {
+		return nil, nil, fmt.Errorf("error decoding Chart.yaml: %w", err)
+	}
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	err = fs.WalkDir(charts, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := charts.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    path.Join(name, strings.TrimPrefix(p, root+"/")),
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}); err != nil {
+			return err
+		}
+
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	return metadata, buf.Bytes(), nil
+}
@@ -0,0 +1,131 @@
+// Package devmode runs the API server locally without any setup. The server uses an sqlite database in the data
+// directory, runs without redis, serves the embedded charts from a loopback helm repo, and is seeded with an admin user
+// and a demo project on start.
+package devmode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// EnvVar enables dev mode when set to true, in the same way as the --dev flag of the server
+const EnvVar = "PORTER_DEV"
+
+// Enabled returns true if dev mode was requested by the flag or by the environment
+func Enabled(flag bool) bool {
+	if flag {
+		return true
+	}
+
+	enabled, _ := strconv.ParseBool(os.Getenv(EnvVar))
+
+	return enabled
+}
+
+// localDBHosts are the database hosts which dev mode runs against, including the postgres container of the docker
+// compose setup
+var localDBHosts = map[string]bool{
+	"":          true,
+	"localhost": true,
+	"127.0.0.1": true,
+	"::1":       true,
+	"postgres":  true,
+}
+
+// Environment is the environment the server runs in while in dev mode
+type Environment struct {
+	// DataDir is the directory holding the sqlite database
+	DataDir string
+	// ChartRepo serves the embedded charts
+	ChartRepo *ChartRepo
+}
+
+// Close stops the services started for dev mode
+func (e *Environment) Close() error {
+	return e.ChartRepo.Close()
+}
+
+// Setup starts the embedded chart repo and sets the environment variables of the server config which have not been
+// set to their dev mode defaults. It must run before the server config is loaded, and refuses to run if the environment
+// points at a database which does not look local.
+func Setup() (*Environment, error) {
+	return setup(os.LookupEnv, os.Setenv)
+}
+
+func setup(lookupEnv func(string) (string, bool), setenv func(string, string) error) (*Environment, error) {
+	if err := checkLocalDatabase(lookupEnv); err != nil {
+		return nil, err
+	}
+
+	dataDir, ok := lookupEnv("PORTER_DEV_DATA_DIR")
+	if !ok || dataDir == "" {
+		dataDir = filepath.Join(os.TempDir(), "porter-dev")
+	}
+
+	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating dev mode data directory: %w", err)
+	}
+
+	chartRepo, err := StartChartRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	port := "8080"
+	if p, ok := lookupEnv("SERVER_PORT"); ok && p != "" {
+		port = p
+	}
+
+	defaults := []struct {
+		key   string
+		value string
+	}{
+		{"SQL_LITE", "true"},
+		{"SQL_LITE_PATH", filepath.Join(dataDir, "porter.db")},
+		{"REDIS_ENABLED", "false"},
+		{"FEATURE_FLAG_CLIENT", "database"},
+		{"COOKIE_INSECURE", "true"},
+		{"DEBUG", "true"},
+		{"VERBOSE_REQUEST_LOGGING", "true"},
+		{"IS_LOCAL", "true"},
+		{"SERVER_URL", "http://localhost:" + port},
+		{"HELM_APP_REPO_URL", chartRepo.URL},
+		{"HELM_ADD_ON_REPO_URL", chartRepo.URL},
+		{"DEBUG_RECORDING_STORE", "file"},
+		{"DEBUG_RECORDING_FILE_PATH", filepath.Join(dataDir, "debug-recordings")},
+	}
+
+	for _, d := range defaults {
+		if _, ok := lookupEnv(d.key); ok {
+			continue
+		}
+
+		if err := setenv(d.key, d.value); err != nil {
+			chartRepo.Close() // nolint:errcheck
+			return nil, fmt.Errorf("error setting %s: %w", d.key, err)
+		}
+	}
+
+	return &Environment{
+		DataDir:   dataDir,
+		ChartRepo: chartRepo,
+	}, nil
+}
+
+// checkLocalDatabase returns an error if the database the server is configured with looks like a shared or production
+// one, so that seeding the dev mode data can never write to it
+func checkLocalDatabase(lookupEnv func(string) (string, bool)) error {
+	if host, ok := lookupEnv("DB_HOST"); ok && !localDBHosts[strings.ToLower(strings.TrimSpace(host))] {
+		return fmt.Errorf("refusing to run in dev mode while DB_HOST is set to %s: unset DB_HOST or set it to localhost", host)
+	}
+
+	if mode, ok := lookupEnv("DB_SSL_MODE"); ok && strings.EqualFold(strings.TrimSpace(mode), "verify-full") {
+		return errors.New("refusing to run in dev mode while DB_SSL_MODE is verify-full, which is only used for hosted databases")
+	}
+
+	return nil
+}
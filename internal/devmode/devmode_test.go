@@ -0,0 +1,173 @@
+package devmode
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/router"
+	"github.com/porter-dev/porter/api/server/shared/config/loader"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+const smokeTestPorterYAML = `version: v1stack
+services:
+  web:
+    type: web
+    run: node index.js
+    config:
+      container:
+        port: 8080
+      ingress:
+        enabled: false
+`
+
+func TestCheckLocalDatabase(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{name: "unset", env: map[string]string{}},
+		{name: "localhost", env: map[string]string{"DB_HOST": "localhost"}},
+		{name: "compose postgres", env: map[string]string{"DB_HOST": "postgres", "SQL_LITE": "false"}},
+		{name: "hosted postgres", env: map[string]string{"DB_HOST": "porter.cluster-abc.us-east-1.rds.amazonaws.com"}, wantErr: true},
+		{name: "verify full ssl", env: map[string]string{"DB_SSL_MODE": "verify-full"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkLocalDatabase(func(key string) (string, bool) {
+				v, ok := tt.env[key]
+				return v, ok
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestDevModeSmoke boots the server in dev mode, logs in with the seeded admin user and deploys a porter app to a
+// cluster whose agents are fakes
+func TestDevModeSmoke(t *testing.T) {
+	t.Setenv("PORTER_DEV_DATA_DIR", t.TempDir())
+	t.Setenv("DB_HOST", "localhost")
+
+	env, err := setup(os.LookupEnv, func(key, value string) error {
+		t.Setenv(key, value)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error setting up dev mode: %v", err)
+	}
+	defer env.Close() // nolint:errcheck
+
+	conf, err := loader.NewEnvLoader("dev-test").LoadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+
+	seed, err := SeedData(conf)
+	if err != nil {
+		t.Fatalf("unexpected error seeding data: %v", err)
+	}
+
+	if _, err := SeedData(conf); err != nil {
+		t.Fatalf("expected seeding to be repeatable, got %v", err)
+	}
+
+	cluster, err := conf.Repo.Cluster().CreateCluster(&models.Cluster{
+		ProjectID:     seed.Project.ID,
+		Name:          "demo",
+		AuthMechanism: models.Local,
+	}, conf.LaunchDarklyClient)
+	if err != nil {
+		t.Fatalf("unexpected error creating cluster: %v", err)
+	}
+
+	k8sAgent := kubernetes.GetAgentTesting()
+	helmAgent := helm.GetAgentTesting(&helm.Form{}, nil, conf.Logger, k8sAgent)
+
+	// the out of cluster agent getter returns the agents in the request context instead of connecting to the cluster
+	appRouter := router.NewAPIRouter(conf)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), authz.KubernetesAgentCtxKey, k8sAgent)
+		ctx = context.WithValue(ctx, authz.HelmAgentCtxKey, helmAgent)
+
+		appRouter.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	defer server.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Jar: jar}
+
+	res := postJSON(t, client, server.URL+"/api/login", types.LoginUserRequest{
+		Email:    AdminEmail,
+		Password: AdminPassword,
+	})
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected the seeded admin user to log in, got status %d", res.StatusCode)
+	}
+
+	res = postJSON(t, client, fmt.Sprintf("%s/api/projects/%d/clusters/%d/applications/web", server.URL, seed.Project.ID, cluster.ID), types.CreatePorterAppRequest{
+		PorterYAMLBase64: base64.StdEncoding.EncodeToString([]byte(smokeTestPorterYAML)),
+		ImageRepoURI:     "nginx",
+		ImageInfo: types.ImageInfo{
+			Repository: "nginx",
+			Tag:        "latest",
+		},
+	})
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected the porter app to be created, got status %d", res.StatusCode)
+	}
+
+	app, err := conf.Repo.PorterApp().ReadScopedPorterAppByName(seed.Project.ID, cluster.ID, "web")
+	if err != nil {
+		t.Fatalf("expected the porter app to be written to the database, got %v", err)
+	}
+	if app.ImageRepoURI != "nginx" {
+		t.Errorf("expected the app to be deployed with the requested image, got %q", app.ImageRepoURI)
+	}
+
+	release, err := helmAgent.GetRelease(context.Background(), "web", 0, false)
+	if err != nil {
+		t.Fatalf("expected the app chart to be installed, got %v", err)
+	}
+	if !strings.Contains(release.Manifest, "kind: Deployment") {
+		t.Errorf("expected the app to be rendered from the embedded charts, got %s", release.Manifest)
+	}
+}
+
+func postJSON(t *testing.T, client *http.Client, url string, body interface{}) *http.Response {
+	t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error calling %s: %v", url, err)
+	}
+	defer res.Body.Close() // nolint:errcheck
+
+	return res
+}
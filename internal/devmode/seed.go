@@ -0,0 +1,182 @@
+package devmode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/gorm"
+	"golang.org/x/crypto/bcrypt"
+	pgorm "gorm.io/gorm"
+)
+
+const (
+	// AdminEmail is the email of the seeded admin user
+	AdminEmail = "admin@porter.local"
+	// AdminPassword is the password of the seeded admin user. Dev mode only runs against local databases, so the
+	// password is fixed to keep the credentials the same across restarts.
+	AdminPassword = "porter-dev"
+
+	demoProjectName          = "demo"
+	demoClusterCandidateName = "demo-cluster"
+	demoClusterServer        = "https://127.0.0.1:6443"
+)
+
+// demoKubeconfig is the kubeconfig of the demo cluster candidate. It points at a local api server with a placeholder
+// token, so that the cluster connection flow can be tried without a cluster.
+const demoKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: demo-cluster
+  cluster:
+    server: https://127.0.0.1:6443
+    insecure-skip-tls-verify: true
+contexts:
+- name: demo
+  context:
+    cluster: demo-cluster
+    user: demo
+current-context: demo
+users:
+- name: demo
+  user:
+    token: porter-dev
+`
+
+// Seed is the data seeded on start in dev mode
+type Seed struct {
+	User             *models.User
+	Project          *models.Project
+	ClusterCandidate *models.ClusterCandidate
+}
+
+// SeedData migrates the database and seeds the admin user, who is made an instance admin, and the demo project with a
+// cluster candidate. Data seeded on an earlier start is reused.
+func SeedData(conf *config.Config) (*Seed, error) {
+	if err := gorm.AutoMigrate(conf.DB, false); err != nil {
+		return nil, fmt.Errorf("error migrating database: %w", err)
+	}
+
+	user, err := seedAdminUser(conf)
+	if err != nil {
+		return nil, fmt.Errorf("error seeding admin user: %w", err)
+	}
+
+	if conf.ServerConf.AdminUserId == "" {
+		conf.ServerConf.AdminUserId = strconv.FormatUint(uint64(user.ID), 10)
+	}
+
+	project, err := seedDemoProject(conf, user)
+	if err != nil {
+		return nil, fmt.Errorf("error seeding demo project: %w", err)
+	}
+
+	candidate, err := seedDemoClusterCandidate(conf, project)
+	if err != nil {
+		return nil, fmt.Errorf("error seeding demo cluster candidate: %w", err)
+	}
+
+	return &Seed{
+		User:             user,
+		Project:          project,
+		ClusterCandidate: candidate,
+	}, nil
+}
+
+// Banner returns the message printed on start in dev mode, with the address of the server and the seeded credentials
+func (s *Seed) Banner(serverURL string) string {
+	lines := []string{
+		"",
+		"Porter is running in dev mode. Do not expose this server: cookies are insecure and the credentials are fixed.",
+		"",
+		fmt.Sprintf("  URL:      %s", serverURL),
+		fmt.Sprintf("  Email:    %s", AdminEmail),
+		fmt.Sprintf("  Password: %s", AdminPassword),
+		fmt.Sprintf("  Project:  %s (id %d)", s.Project.Name, s.Project.ID),
+		"",
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func seedAdminUser(conf *config.Config) (*models.User, error) {
+	user, err := conf.Repo.User().ReadUserByEmail(AdminEmail)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, pgorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	hashedPw, err := bcrypt.GenerateFromPassword([]byte(AdminPassword), 8)
+	if err != nil {
+		return nil, err
+	}
+
+	return conf.Repo.User().CreateUser(&models.User{
+		Email:         AdminEmail,
+		Password:      string(hashedPw),
+		EmailVerified: true,
+		FirstName:     "Porter",
+		LastName:      "Admin",
+	})
+}
+
+func seedDemoProject(conf *config.Config, user *models.User) (*models.Project, error) {
+	projects, err := conf.Repo.Project().ListProjectsByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		if project.Name == demoProjectName {
+			return project, nil
+		}
+	}
+
+	project, err := conf.Repo.Project().CreateProject(&models.Project{
+		Name: demoProjectName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = conf.Repo.Project().CreateProjectRole(project, &models.Role{
+		Role: types.Role{
+			UserID:    user.ID,
+			ProjectID: project.ID,
+			Kind:      types.RoleAdmin,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conf.Repo.Project().ReadProject(project.ID)
+}
+
+func seedDemoClusterCandidate(conf *config.Config, project *models.Project) (*models.ClusterCandidate, error) {
+	candidates, err := conf.Repo.Cluster().ListClusterCandidatesByProjectID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Name == demoClusterCandidateName {
+			return candidate, nil
+		}
+	}
+
+	return conf.Repo.Cluster().CreateClusterCandidate(&models.ClusterCandidate{
+		AuthMechanism: models.Bearer,
+		ProjectID:     project.ID,
+		Name:          demoClusterCandidateName,
+		Server:        demoClusterServer,
+		ContextName:   "demo",
+		Kubeconfig:    []byte(demoKubeconfig),
+	})
+}
@@ -114,6 +114,95 @@ func (a *Agent) ListReleases(
 	return res, nil
 }
 
+// releaseSecretPageSize is the number of release secrets read in each page by ListLatestReleases
+const releaseSecretPageSize = 100
+
+// ListLatestReleases lists the latest revision of each release in the namespace, or in every namespace if it is empty.
+// Unlike ListReleases, statuses filter the latest revision rather than every revision, so that a release whose last
+// upgrade failed is listed as failed rather than by its last deployed revision. Every status is listed if statuses is
+// empty. The release secrets are read in pages, so that clusters with hundreds of releases are not read in one request.
+func (a *Agent) ListLatestReleases(
+	ctx context.Context,
+	namespace string,
+	statuses []string,
+) ([]*release.Release, error) {
+	ctx, span := telemetry.NewSpan(ctx, "helm-list-latest-releases")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "namespace", Value: namespace},
+		telemetry.AttributeKV{Key: "statuses", Value: strings.Join(statuses, ",")},
+	)
+
+	statusSet := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		statusSet[status] = true
+	}
+
+	latestMap := make(map[string]corev1.Secret)
+	latestVersions := make(map[string]int)
+
+	var pages int
+	continueVal := ""
+	for {
+		secretList, err := a.K8sAgent.Clientset.CoreV1().Secrets(namespace).List(
+			ctx,
+			v1.ListOptions{
+				LabelSelector: "owner=helm",
+				Limit:         releaseSecretPageSize,
+				Continue:      continueVal,
+			},
+		)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error getting secret list")
+		}
+		pages++
+
+		for _, secret := range secretList.Items {
+			relName, relNameExists := secret.Labels["name"]
+			if !relNameExists {
+				continue
+			}
+
+			version, err := strconv.Atoi(secret.Labels["version"])
+			if err != nil {
+				continue
+			}
+
+			id := fmt.Sprintf("%s/%s", secret.Namespace, relName)
+			if currVersion, exists := latestVersions[id]; !exists || currVersion < version {
+				latestMap[id] = secret
+				latestVersions[id] = version
+			}
+		}
+
+		if secretList.Continue == "" {
+			break
+		}
+		continueVal = secretList.Continue
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "pages", Value: pages},
+		telemetry.AttributeKV{Key: "releases", Value: len(latestMap)},
+	)
+
+	res := make([]*release.Release, 0)
+
+	for _, secret := range latestMap {
+		if len(statusSet) > 0 && !statusSet[secret.Labels["status"]] {
+			continue
+		}
+
+		rel, isErr, err := kubernetes.ParseSecretToHelmRelease(secret, nil)
+		if !isErr && err == nil {
+			res = append(res, rel)
+		}
+	}
+
+	return res, nil
+}
+
 // GetRelease returns the info of a release.
 func (a *Agent) GetRelease(
 	ctx context.Context,
@@ -290,6 +379,13 @@ type UpgradeReleaseConfig struct {
 	// Optional, if chart is part of a Porter Stack
 	StackName     string
 	StackRevision uint
+
+	// Timeout bounds the install or upgrade, including its hooks and, if WaitForJobs is set, the wait for its
+	// resources. Defaults to DefaultTimeout
+	Timeout time.Duration
+	// WaitForJobs waits until the resources of the release are ready and its jobs have completed before the install
+	// or upgrade succeeds, failing it if they are not within Timeout
+	WaitForJobs bool
 }
 
 // UpgradeRelease upgrades a specific release with new values.yaml
@@ -355,6 +451,9 @@ func (a *Agent) UpgradeReleaseByValues(
 
 	cmd := action.NewUpgrade(a.ActionConfig)
 	cmd.Namespace = rel.Namespace
+	cmd.Timeout = timeoutOrDefault(conf.Timeout)
+	cmd.Wait = conf.WaitForJobs
+	cmd.WaitForJobs = conf.WaitForJobs
 
 	cmd.PostRenderer, err = NewPorterPostrenderer(
 		conf.Cluster,
@@ -514,6 +613,24 @@ type InstallChartConfig struct {
 	Cluster    *models.Cluster
 	Repo       repository.Repository
 	Registries []*models.Registry
+
+	// Timeout bounds the install or upgrade, including its hooks and, if WaitForJobs is set, the wait for its
+	// resources. Defaults to DefaultTimeout
+	Timeout time.Duration
+	// WaitForJobs waits until the resources of the release are ready and its jobs have completed before the install
+	// or upgrade succeeds, failing it if they are not within Timeout
+	WaitForJobs bool
+}
+
+// DefaultTimeout bounds the installs and upgrades of charts which do not set their own timeout
+const DefaultTimeout = 300 * time.Second
+
+func timeoutOrDefault(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return DefaultTimeout
+	}
+
+	return timeout
 }
 
 // InstallChartFromValuesBytes reads the raw values and calls Agent.InstallChart
@@ -575,7 +692,9 @@ func (a *Agent) InstallChart(
 
 	cmd.ReleaseName = conf.Name
 	cmd.Namespace = conf.Namespace
-	cmd.Timeout = 300 * time.Second
+	cmd.Timeout = timeoutOrDefault(conf.Timeout)
+	cmd.Wait = conf.WaitForJobs
+	cmd.WaitForJobs = conf.WaitForJobs
 
 	if err := checkIfInstallable(conf.Chart); err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error checking if installable")
@@ -641,7 +760,9 @@ func (a *Agent) UpgradeInstallChart(
 	}
 
 	cmd.Namespace = conf.Namespace
-	cmd.Timeout = 300 * time.Second
+	cmd.Timeout = timeoutOrDefault(conf.Timeout)
+	cmd.Wait = conf.WaitForJobs
+	cmd.WaitForJobs = conf.WaitForJobs
 
 	if err := checkIfInstallable(conf.Chart); err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error checking if installable")
@@ -682,6 +803,53 @@ func (a *Agent) UpgradeInstallChart(
 	return release, nil
 }
 
+// TemplateChart renders the manifests of a chart with the given values, the equivalent of `helm template`. Nothing is
+// written to the cluster: the chart is rendered client-side, without the post renderer, so image pull secrets are
+// neither created nor injected into the manifests.
+func (a *Agent) TemplateChart(
+	ctx context.Context,
+	conf *InstallChartConfig,
+) (string, error) {
+	ctx, span := telemetry.NewSpan(ctx, "helm-template-chart")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "chart-name", Value: conf.Name},
+		telemetry.AttributeKV{Key: "chart-namespace", Value: conf.Namespace},
+	)
+
+	cmd := action.NewInstall(a.ActionConfig)
+
+	cmd.ReleaseName = conf.Name
+	cmd.Namespace = conf.Namespace
+	cmd.DryRun = true
+	cmd.ClientOnly = true
+	cmd.Replace = true
+	cmd.IncludeCRDs = true
+
+	if err := checkIfInstallable(conf.Chart); err != nil {
+		return "", telemetry.Error(ctx, span, err, "error checking if installable")
+	}
+
+	if req := conf.Chart.Metadata.Dependencies; req != nil {
+		for _, dep := range req {
+			depChart, err := loader.LoadChartPublic(ctx, dep.Repository, dep.Name, dep.Version)
+			if err != nil {
+				return "", telemetry.Error(ctx, span, err, fmt.Sprintf("error retrieving chart dependency %s/%s-%s", dep.Repository, dep.Name, dep.Version))
+			}
+
+			conf.Chart.AddDependency(depChart)
+		}
+	}
+
+	rel, err := cmd.Run(conf.Chart, conf.Values)
+	if err != nil {
+		return "", telemetry.Error(ctx, span, err, "error rendering chart")
+	}
+
+	return rel.Manifest, nil
+}
+
 // UninstallChart uninstalls a chart
 func (a *Agent) UninstallChart(
 	ctx context.Context,
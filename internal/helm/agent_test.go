@@ -2,6 +2,7 @@ package helm_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stefanmcshane/helm/pkg/storage/driver"
@@ -339,3 +340,43 @@ func TestRollbackRelease(t *testing.T) {
 		compareReleaseToStubs(t, []*release.Release{rel}, []releaseStub{tc.expRes})
 	}
 }
+
+func TestTemplateChart(t *testing.T) {
+	agent := newAgentFixture(t, "porter-stack-web")
+
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: "v2",
+			Name:       "web",
+			Version:    "0.1.0",
+		},
+		Templates: []*chart.File{
+			{
+				Name: "templates/configmap.yaml",
+				Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\ndata:\n  image: {{ .Values.image }}\n"),
+			},
+		},
+	}
+
+	manifest, err := agent.TemplateChart(context.Background(), &helm.InstallChartConfig{
+		Chart:     ch,
+		Name:      "web",
+		Namespace: "porter-stack-web",
+		Values:    map[string]interface{}{"image": "nginx:1.25"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(manifest, "name: web") || !strings.Contains(manifest, "image: nginx:1.25") {
+		t.Errorf("expected the manifest to be rendered with the values, got %s", manifest)
+	}
+
+	rels, err := agent.ActionConfig.Releases.ListReleases()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rels) != 0 {
+		t.Errorf("expected no release to be stored, got %d", len(rels))
+	}
+}
@@ -126,7 +126,8 @@ func LoadRepoIndexPublic(repoURL string) (*repo.IndexFile, error) {
 	return LoadRepoIndex(&BasicAuthClient{}, repoURL)
 }
 
-// LoadChart uses an http request to fetch a chart from a remote Helm repo
+// LoadChart uses an http request to fetch a chart from a remote Helm repo. Unless the verification policy is off, the
+// archive must match the digest the chart is pinned to, or the provenance file it was signed with.
 func LoadChart(ctx context.Context, client *BasicAuthClient, repoURL, chartName, chartVersion string) (*chart.Chart, error) {
 	ctx, span := telemetry.NewSpan(ctx, "load-chart")
 	defer span.End()
@@ -150,6 +151,14 @@ func LoadChart(ctx context.Context, client *BasicAuthClient, repoURL, chartName,
 		return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("%s:%s no valid download urls", chartName, chartVersion))
 	}
 
+	policy, verified := currentPolicy()
+	if policy.Mode != VerificationMode_Off {
+		// a verified archive is never downloaded again, so that a tampered download cannot replace it
+		if data, digest, ok := verified.get(chartName, cv.Version); ok {
+			return loadArchive(data, digest, true)
+		}
+	}
+
 	trimmedRepoURL := strings.TrimSuffix(strings.TrimSpace(repoURL), "/")
 	chartURL := cv.URLs[0]
 
@@ -158,9 +167,41 @@ func LoadChart(ctx context.Context, client *BasicAuthClient, repoURL, chartName,
 	}
 
 	// download tgz
-	req, err := http.NewRequest("GET", chartURL, nil)
+	data, err := download(ctx, client, chartURL)
 	if err != nil {
-		return nil, telemetry.Error(ctx, span, err, "error creating request")
+		return nil, telemetry.Error(ctx, span, err, "error downloading chart")
+	}
+
+	digest := archiveDigest(data)
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "chart-digest", Value: digest})
+
+	if policy.Mode == VerificationMode_Off {
+		return loadArchive(data, digest, false)
+	}
+
+	err = policy.verify(chartName, cv.Version, archiveName(chartURL), digest, func() ([]byte, error) {
+		return download(ctx, client, chartURL+".prov")
+	})
+	if err != nil {
+		if policy.Mode == VerificationMode_Enforce {
+			return nil, telemetry.Error(ctx, span, err, "chart failed verification")
+		}
+
+		// in warn mode the chart is still loaded, but is not marked as verified
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "chart-verification-warning", Value: err.Error()})
+		return loadArchive(data, digest, false)
+	}
+
+	verified.add(chartName, cv.Version, digest, data)
+
+	return loadArchive(data, digest, true)
+}
+
+// download returns the body of a GET request to a helm repo
+func download(ctx context.Context, client *BasicAuthClient, fileURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
 	if client.Username != "" {
@@ -169,24 +210,44 @@ func LoadChart(ctx context.Context, client *BasicAuthClient, repoURL, chartName,
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, telemetry.Error(ctx, span, err, "error executing request")
+		return nil, fmt.Errorf("error executing request: %w", err)
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned status %d", fileURL, resp.StatusCode)
+	}
+
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, telemetry.Error(ctx, span, err, "error reading response body")
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	return data, nil
+}
+
+// loadArchive loads a chart from its archive, and annotates it with the digest of the archive
+func loadArchive(data []byte, digest string, isVerified bool) (*chart.Chart, error) {
+	ch, err := chartloader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if ch.Metadata.Annotations == nil {
+		ch.Metadata.Annotations = make(map[string]string)
+	}
+
+	ch.Metadata.Annotations[AnnotationKey_ChartDigest] = digest
+	if isVerified {
+		ch.Metadata.Annotations[AnnotationKey_ChartVerified] = "true"
 	}
 
-	return chartloader.LoadArchive(bytes.NewReader(data))
+	return ch, nil
 }
 
 // LoadChartPublic returns a Helm3 (v2) chart from a remote public repo.
 // If chartVersion is an empty string, the most stable latest version is found.
-//
-// TODO: this is an expensive operation, so after retrieving the digest from the
-// repo index, this should check the digest in the cache
 func LoadChartPublic(ctx context.Context, repoURL, chartName, chartVersion string) (*chart.Chart, error) {
 	return LoadChart(ctx, &BasicAuthClient{}, repoURL, chartName, chartVersion)
 }
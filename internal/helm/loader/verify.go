@@ -0,0 +1,304 @@
+package loader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"github.com/stefanmcshane/helm/pkg/provenance"
+	"golang.org/x/crypto/openpgp"           //nolint
+	"golang.org/x/crypto/openpgp/clearsign" //nolint
+	"sigs.k8s.io/yaml"
+)
+
+// VerificationMode is how charts which fail verification are handled
+type VerificationMode string
+
+const (
+	// VerificationMode_Off does not verify charts
+	VerificationMode_Off VerificationMode = "off"
+	// VerificationMode_Warn verifies charts, but still loads those which fail verification
+	VerificationMode_Warn VerificationMode = "warn"
+	// VerificationMode_Enforce refuses to load charts which fail verification
+	VerificationMode_Enforce VerificationMode = "enforce"
+)
+
+const (
+	// AnnotationKey_ChartDigest is set on loaded charts to the digest of the archive they were loaded from
+	AnnotationKey_ChartDigest = "porter.run/chart-digest"
+	// AnnotationKey_ChartVerified is set on loaded charts whose archive matched a pinned digest or a signed provenance file
+	AnnotationKey_ChartVerified = "porter.run/chart-verified"
+)
+
+// VerificationPolicy is how the charts downloaded from helm repos are verified. A chart with a pinned digest must match
+// it; any other chart must have a provenance file signed by a key of the keyring.
+type VerificationPolicy struct {
+	Mode VerificationMode
+	// Keyring holds the public keys provenance files can be signed with
+	Keyring openpgp.EntityList
+	// Pins maps name@version to the sha256:<hex> digest of the chart's archive
+	Pins map[string]string
+}
+
+// NewVerificationPolicy returns the policy for a mode, the path of an armored or binary PGP public keyring, and digests
+// pinned as name@version=sha256:<hex>
+func NewVerificationPolicy(mode string, keyringPath string, pins []string) (*VerificationPolicy, error) {
+	policy := &VerificationPolicy{
+		Mode: VerificationMode(strings.ToLower(strings.TrimSpace(mode))),
+		Pins: make(map[string]string),
+	}
+
+	switch policy.Mode {
+	case "", VerificationMode_Off:
+		policy.Mode = VerificationMode_Off
+		return policy, nil
+	case VerificationMode_Warn, VerificationMode_Enforce:
+	default:
+		return nil, fmt.Errorf("unknown chart verification mode %s, must be one of off, warn or enforce", mode)
+	}
+
+	if keyringPath != "" {
+		data, err := os.ReadFile(keyringPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading chart verification keyring: %w", err)
+		}
+
+		keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		if err != nil {
+			keyring, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing chart verification keyring: %w", err)
+		}
+
+		policy.Keyring = keyring
+	}
+
+	for _, pin := range pins {
+		pin = strings.TrimSpace(pin)
+		if pin == "" {
+			continue
+		}
+
+		key, digest, ok := strings.Cut(pin, "=")
+		if !ok || !strings.Contains(key, "@") {
+			return nil, fmt.Errorf("invalid chart digest pin %s, must be name@version=sha256:<hex>", pin)
+		}
+
+		sum, ok := strings.CutPrefix(digest, "sha256:")
+		if _, err := hex.DecodeString(sum); !ok || err != nil || len(sum) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid digest for chart %s, must be sha256:<hex>", key)
+		}
+
+		policy.Pins[key] = strings.ToLower(digest)
+	}
+
+	if len(policy.Keyring) == 0 && len(policy.Pins) == 0 {
+		return nil, fmt.Errorf("chart verification mode %s needs a keyring or pinned digests", policy.Mode)
+	}
+
+	return policy, nil
+}
+
+// DigestMismatchError is returned when the archive of a chart does not have the digest it was pinned or signed with
+type DigestMismatchError struct {
+	Chart    string
+	Version  string
+	Expected string
+	Actual   string
+}
+
+func (e *DigestMismatchError) Error() string {
+	return fmt.Sprintf("chart %s:%s digest mismatch: expected %s, got %s", e.Chart, e.Version, e.Expected, e.Actual)
+}
+
+var (
+	policyMu sync.RWMutex
+	policy   = &VerificationPolicy{Mode: VerificationMode_Off}
+
+	verified = newChartCache()
+)
+
+// SetVerificationPolicy sets how the charts loaded by this package are verified. Charts verified under the previous
+// policy are discarded.
+func SetVerificationPolicy(p *VerificationPolicy) {
+	if p == nil {
+		p = &VerificationPolicy{Mode: VerificationMode_Off}
+	}
+
+	policyMu.Lock()
+	defer policyMu.Unlock()
+
+	policy = p
+	verified = newChartCache()
+}
+
+func currentPolicy() (*VerificationPolicy, *chartCache) {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+
+	return policy, verified
+}
+
+// verify checks that an archive has the digest a chart is pinned to, or is signed by its provenance file. fetchProvenance
+// is only called for charts without a pinned digest.
+func (p *VerificationPolicy) verify(name, version, archiveName, digest string, fetchProvenance func() ([]byte, error)) error {
+	if pin, ok := p.Pins[chartKey(name, version)]; ok {
+		if pin != digest {
+			return &DigestMismatchError{Chart: name, Version: version, Expected: pin, Actual: digest}
+		}
+		return nil
+	}
+
+	if len(p.Keyring) == 0 {
+		return fmt.Errorf("chart %s:%s has no pinned digest, and no keyring is configured to verify its provenance file", name, version)
+	}
+
+	prov, err := fetchProvenance()
+	if err != nil {
+		return fmt.Errorf("chart %s:%s has no provenance file: %w", name, version, err)
+	}
+
+	signed, err := signedDigest(p.Keyring, prov, archiveName)
+	if err != nil {
+		return fmt.Errorf("chart %s:%s has an invalid provenance file: %w", name, version, err)
+	}
+
+	if signed != digest {
+		return &DigestMismatchError{Chart: name, Version: version, Expected: signed, Actual: digest}
+	}
+
+	return nil
+}
+
+// signedDigest returns the digest a provenance file signed by a key of the keyring holds for an archive
+func signedDigest(keyring openpgp.EntityList, prov []byte, archiveName string) (string, error) {
+	block, _ := clearsign.Decode(prov)
+	if block == nil {
+		return "", errors.New("no signature found")
+	}
+
+	if _, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body); err != nil {
+		return "", fmt.Errorf("not signed by a trusted key: %w", err)
+	}
+
+	// the chart metadata and the checksums are separated by a yaml document end marker
+	parts := bytes.Split(block.Plaintext, []byte("\n...\n"))
+	if len(parts) < 2 {
+		return "", errors.New("no checksums found")
+	}
+
+	sums := &provenance.SumCollection{}
+	if err := yaml.Unmarshal(parts[1], sums); err != nil {
+		return "", fmt.Errorf("error parsing checksums: %w", err)
+	}
+
+	sum, ok := sums.Files[archiveName]
+	if !ok {
+		return "", fmt.Errorf("no checksum for %s", archiveName)
+	}
+
+	return sum, nil
+}
+
+// chartCache holds the archives of verified charts by their digest, so that a chart version which was verified once is
+// not downloaded again: a later download of the same version, which may have been tampered with, can never replace it
+type chartCache struct {
+	mu       sync.Mutex
+	archives map[string][]byte
+	// digests maps name@version to the digest of its verified archive
+	digests map[string]string
+}
+
+func newChartCache() *chartCache {
+	return &chartCache{
+		archives: make(map[string][]byte),
+		digests:  make(map[string]string),
+	}
+}
+
+func (c *chartCache) get(name, version string) ([]byte, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	digest, ok := c.digests[chartKey(name, version)]
+	if !ok {
+		return nil, "", false
+	}
+
+	return c.archives[digest], digest, true
+}
+
+func (c *chartCache) add(name, version, digest string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := chartKey(name, version)
+	if _, ok := c.digests[key]; ok {
+		return
+	}
+
+	c.digests[key] = digest
+	if _, ok := c.archives[digest]; !ok {
+		c.archives[digest] = data
+	}
+}
+
+// ChartDigest is the digest of the archive a chart was loaded from
+type ChartDigest struct {
+	Digest   string `json:"digest"`
+	Verified bool   `json:"verified"`
+}
+
+// ChartDigests returns the digests of a chart and its dependencies which were loaded by this package, by name@version
+func ChartDigests(ch *chart.Chart) map[string]ChartDigest {
+	digests := make(map[string]ChartDigest)
+	addChartDigests(ch, digests)
+
+	return digests
+}
+
+func addChartDigests(ch *chart.Chart, digests map[string]ChartDigest) {
+	if ch == nil {
+		return
+	}
+
+	if ch.Metadata != nil {
+		if digest := ch.Metadata.Annotations[AnnotationKey_ChartDigest]; digest != "" {
+			digests[chartKey(ch.Metadata.Name, ch.Metadata.Version)] = ChartDigest{
+				Digest:   digest,
+				Verified: ch.Metadata.Annotations[AnnotationKey_ChartVerified] == "true",
+			}
+		}
+	}
+
+	for _, dep := range ch.Dependencies() {
+		addChartDigests(dep, digests)
+	}
+}
+
+func chartKey(name, version string) string {
+	return name + "@" + version
+}
+
+func archiveDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// archiveName returns the file name of the archive at a chart URL, which its provenance file holds the checksum for
+func archiveName(chartURL string) string {
+	if u, err := url.Parse(chartURL); err == nil {
+		return path.Base(u.Path)
+	}
+
+	return path.Base(chartURL)
+}
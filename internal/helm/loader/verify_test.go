@@ -0,0 +1,322 @@
+package loader
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"github.com/stefanmcshane/helm/pkg/chartutil"
+	"golang.org/x/crypto/openpgp"           //nolint
+	"golang.org/x/crypto/openpgp/clearsign" //nolint
+	"sigs.k8s.io/yaml"
+)
+
+// testRepo is a helm repo serving a single chart, web:0.1.0
+type testRepo struct {
+	*httptest.Server
+	archive []byte
+	prov    []byte
+}
+
+func newTestRepo(t *testing.T, archive []byte) *testRepo {
+	repo := &testRepo{archive: archive}
+	repo.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = w.Write([]byte("apiVersion: v1\nentries:\n  web:\n  - name: web\n    version: 0.1.0\n    urls:\n    - web-0.1.0.tgz\n"))
+		case "/web-0.1.0.tgz":
+			_, _ = w.Write(repo.archive)
+		case "/web-0.1.0.tgz.prov":
+			if repo.prov == nil {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(repo.prov)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(repo.Close)
+
+	return repo
+}
+
+func testArchive(t *testing.T, description string) []byte {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: "v2", Name: "web", Version: "0.1.0", Description: description},
+		Templates: []*chart.File{
+			{Name: "templates/configmap.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n")},
+		},
+	}
+
+	dir := t.TempDir()
+	path, err := chartutil.Save(ch, dir)
+	if err != nil {
+		t.Fatalf("error saving chart: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading chart archive: %v", err)
+	}
+
+	return data
+}
+
+// testProvenance returns a provenance file for the archive signed by the signer
+func testProvenance(t *testing.T, signer *openpgp.Entity, archive []byte) []byte {
+	sums, err := yaml.Marshal(map[string]interface{}{
+		"files": map[string]string{"web-0.1.0.tgz": archiveDigest(archive)},
+	})
+	if err != nil {
+		t.Fatalf("error marshalling checksums: %v", err)
+	}
+
+	var prov bytes.Buffer
+	w, err := clearsign.Encode(&prov, signer.PrivateKey, nil)
+	if err != nil {
+		t.Fatalf("error signing provenance file: %v", err)
+	}
+	_, _ = w.Write([]byte("apiVersion: v2\nname: web\nversion: 0.1.0\n\n...\n"))
+	_, _ = w.Write(sums)
+	if err := w.Close(); err != nil {
+		t.Fatalf("error signing provenance file: %v", err)
+	}
+
+	return prov.Bytes()
+}
+
+func testSigner(t *testing.T) *openpgp.Entity {
+	signer, err := openpgp.NewEntity("Porter Charts", "", "charts@example.com", nil)
+	if err != nil {
+		t.Fatalf("error creating signing key: %v", err)
+	}
+
+	return signer
+}
+
+func TestLoadChartVerification(t *testing.T) {
+	archive := testArchive(t, "web service")
+	tampered := testArchive(t, "tampered web service")
+	signer := testSigner(t)
+	untrusted := testSigner(t)
+
+	pinned := map[string]string{"web@0.1.0": archiveDigest(archive)}
+	keyring := openpgp.EntityList{signer}
+
+	tests := []struct {
+		name    string
+		policy  *VerificationPolicy
+		archive []byte
+		prov    []byte
+
+		wantErr      string
+		wantVerified bool
+	}{
+		{
+			name:    "off",
+			policy:  &VerificationPolicy{Mode: VerificationMode_Off},
+			archive: tampered,
+		},
+		{
+			name:         "pinned digest",
+			policy:       &VerificationPolicy{Mode: VerificationMode_Enforce, Pins: pinned},
+			archive:      archive,
+			wantVerified: true,
+		},
+		{
+			name:    "tampered archive with a pinned digest in enforce mode",
+			policy:  &VerificationPolicy{Mode: VerificationMode_Enforce, Pins: pinned},
+			archive: tampered,
+			wantErr: "chart web:0.1.0 digest mismatch: expected " + archiveDigest(archive) + ", got " + archiveDigest(tampered),
+		},
+		{
+			name:    "tampered archive with a pinned digest in warn mode",
+			policy:  &VerificationPolicy{Mode: VerificationMode_Warn, Pins: pinned},
+			archive: tampered,
+		},
+		{
+			name:         "signed provenance file",
+			policy:       &VerificationPolicy{Mode: VerificationMode_Enforce, Keyring: keyring},
+			archive:      archive,
+			prov:         testProvenance(t, signer, archive),
+			wantVerified: true,
+		},
+		{
+			name:    "tampered archive with a signed provenance file in enforce mode",
+			policy:  &VerificationPolicy{Mode: VerificationMode_Enforce, Keyring: keyring},
+			archive: tampered,
+			prov:    testProvenance(t, signer, archive),
+			wantErr: "digest mismatch: expected " + archiveDigest(archive) + ", got " + archiveDigest(tampered),
+		},
+		{
+			name:    "tampered archive with a signed provenance file in warn mode",
+			policy:  &VerificationPolicy{Mode: VerificationMode_Warn, Keyring: keyring},
+			archive: tampered,
+			prov:    testProvenance(t, signer, archive),
+		},
+		{
+			name:    "missing provenance file in enforce mode",
+			policy:  &VerificationPolicy{Mode: VerificationMode_Enforce, Keyring: keyring},
+			archive: archive,
+			wantErr: "chart web:0.1.0 has no provenance file",
+		},
+		{
+			name:    "missing provenance file in warn mode",
+			policy:  &VerificationPolicy{Mode: VerificationMode_Warn, Keyring: keyring},
+			archive: archive,
+		},
+		{
+			name:    "provenance file signed by an untrusted key",
+			policy:  &VerificationPolicy{Mode: VerificationMode_Enforce, Keyring: keyring},
+			archive: archive,
+			prov:    testProvenance(t, untrusted, archive),
+			wantErr: "not signed by a trusted key",
+		},
+		{
+			name:    "no pinned digest and no keyring",
+			policy:  &VerificationPolicy{Mode: VerificationMode_Enforce, Pins: map[string]string{"worker@0.1.0": archiveDigest(archive)}},
+			archive: archive,
+			wantErr: "chart web:0.1.0 has no pinned digest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetVerificationPolicy(tt.policy)
+			t.Cleanup(func() { SetVerificationPolicy(nil) })
+
+			repo := newTestRepo(t, tt.archive)
+			repo.prov = tt.prov
+
+			ch, err := LoadChartPublic(context.Background(), repo.URL, "web", "0.1.0")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := ch.Metadata.Annotations[AnnotationKey_ChartDigest]; got != archiveDigest(tt.archive) {
+				t.Errorf("expected the chart to be annotated with digest %s, got %s", archiveDigest(tt.archive), got)
+			}
+			if got := ch.Metadata.Annotations[AnnotationKey_ChartVerified] == "true"; got != tt.wantVerified {
+				t.Errorf("expected verified to be %t, got %t", tt.wantVerified, got)
+			}
+		})
+	}
+}
+
+func TestLoadChartKeepsVerifiedArchive(t *testing.T) {
+	archive := testArchive(t, "web service")
+	signer := testSigner(t)
+
+	SetVerificationPolicy(&VerificationPolicy{Mode: VerificationMode_Enforce, Keyring: openpgp.EntityList{signer}})
+	t.Cleanup(func() { SetVerificationPolicy(nil) })
+
+	repo := newTestRepo(t, archive)
+	repo.prov = testProvenance(t, signer, archive)
+
+	if _, err := LoadChartPublic(context.Background(), repo.URL, "web", "0.1.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the repo is poisoned after the chart was verified
+	repo.archive = testArchive(t, "tampered web service")
+	repo.prov = testProvenance(t, signer, repo.archive)
+
+	ch, err := LoadChartPublic(context.Background(), repo.URL, "web", "0.1.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ch.Metadata.Annotations[AnnotationKey_ChartDigest]; got != archiveDigest(archive) {
+		t.Errorf("expected the verified archive %s to be kept, got %s", archiveDigest(archive), got)
+	}
+	if ch.Metadata.Description != "web service" {
+		t.Errorf("expected the chart to be loaded from the verified archive, got description %q", ch.Metadata.Description)
+	}
+}
+
+func TestNewVerificationPolicy(t *testing.T) {
+	digest := archiveDigest([]byte("web"))
+
+	policy, err := NewVerificationPolicy("", "", nil)
+	if err != nil || policy.Mode != VerificationMode_Off {
+		t.Fatalf("expected verification to be off by default, got %v, %v", policy, err)
+	}
+
+	policy, err = NewVerificationPolicy("enforce", "", []string{"web@0.1.0=" + digest, " "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.Pins["web@0.1.0"] != digest {
+		t.Errorf("expected web@0.1.0 to be pinned to %s, got %v", digest, policy.Pins)
+	}
+
+	var keyring bytes.Buffer
+	if err := testSigner(t).Serialize(&keyring); err != nil {
+		t.Fatalf("error serializing key: %v", err)
+	}
+	keyringPath := filepath.Join(t.TempDir(), "pubring.gpg")
+	if err := os.WriteFile(keyringPath, keyring.Bytes(), 0o600); err != nil {
+		t.Fatalf("error writing keyring: %v", err)
+	}
+
+	policy, err = NewVerificationPolicy("warn", keyringPath, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policy.Keyring) != 1 {
+		t.Errorf("expected the keyring to hold one key, got %d", len(policy.Keyring))
+	}
+
+	for _, tt := range []struct {
+		mode    string
+		pins    []string
+		wantErr string
+	}{
+		{mode: "strict", wantErr: "unknown chart verification mode"},
+		{mode: "enforce", wantErr: "needs a keyring or pinned digests"},
+		{mode: "enforce", pins: []string{"web=" + digest}, wantErr: "invalid chart digest pin"},
+		{mode: "enforce", pins: []string{"web@0.1.0=md5:abc"}, wantErr: "invalid digest for chart web@0.1.0"},
+	} {
+		if _, err := NewVerificationPolicy(tt.mode, "", tt.pins); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("expected error containing %q for mode %s and pins %v, got %v", tt.wantErr, tt.mode, tt.pins, err)
+		}
+	}
+}
+
+func TestChartDigests(t *testing.T) {
+	web := &chart.Chart{Metadata: &chart.Metadata{
+		Name:        "web",
+		Version:     "0.1.0",
+		Annotations: map[string]string{AnnotationKey_ChartDigest: "sha256:web", AnnotationKey_ChartVerified: "true"},
+	}}
+	worker := &chart.Chart{Metadata: &chart.Metadata{
+		Name:        "worker",
+		Version:     "0.2.0",
+		Annotations: map[string]string{AnnotationKey_ChartDigest: "sha256:worker"},
+	}}
+	umbrella := &chart.Chart{Metadata: &chart.Metadata{Name: "umbrella", Version: "0.96.0"}}
+	umbrella.AddDependency(web, worker)
+
+	digests := ChartDigests(umbrella)
+	if len(digests) != 2 {
+		t.Fatalf("expected the digests of the two dependencies, got %v", digests)
+	}
+	if digests["web@0.1.0"] != (ChartDigest{Digest: "sha256:web", Verified: true}) {
+		t.Errorf("unexpected digest for web@0.1.0: %v", digests["web@0.1.0"])
+	}
+	if digests["worker@0.2.0"] != (ChartDigest{Digest: "sha256:worker"}) {
+		t.Errorf("unexpected digest for worker@0.2.0: %v", digests["worker@0.2.0"])
+	}
+}
@@ -217,14 +217,16 @@ func (d *DockerSecretsPostRenderer) Run(
 		}
 	}
 
-	// create the necessary secrets
+	// create the necessary secrets. The secrets which were generated are used even if
+	// some registries failed, so that one registry with broken credentials does not
+	// leave the images of the other registries without a pull secret
 	secrets, err := d.Agent.CreateImagePullSecrets(
 		d.Repo,
 		d.Namespace,
 		linkedRegs,
 		d.DOAuth,
 	)
-	if err != nil {
+	if err != nil && len(secrets) == 0 {
 		return renderedManifests, nil
 	}
 
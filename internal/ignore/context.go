@@ -0,0 +1,168 @@
+package ignore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// PorterIgnoreFile is read from the root of the build context, using gitignore syntax
+	PorterIgnoreFile = ".porterignore"
+	// DockerIgnoreFile is used when there is no .porterignore, using dockerignore syntax
+	DockerIgnoreFile = ".dockerignore"
+)
+
+// Load returns the matcher for the build context in dir, along with the name of the ignore file it was read from.
+// A .porterignore takes precedence over a .dockerignore. If neither exists, only the .git directory is excluded and
+// the returned file name is empty.
+func Load(dir string) (*Matcher, string, error) {
+	for _, candidate := range []struct {
+		name   string
+		syntax Syntax
+	}{
+		{name: PorterIgnoreFile, syntax: Syntax_Gitignore},
+		{name: DockerIgnoreFile, syntax: Syntax_Dockerignore},
+	} {
+		f, err := os.Open(filepath.Join(dir, candidate.name)) // #nosec G304 -- the build context is chosen by the user
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, "", fmt.Errorf("error opening %s: %w", candidate.name, err)
+		}
+
+		m, err := Parse(f, candidate.syntax)
+		_ = f.Close()
+		if err != nil {
+			return nil, "", fmt.Errorf("error parsing %s: %w", candidate.name, err)
+		}
+
+		return m, candidate.name, nil
+	}
+
+	return New(nil, Syntax_Gitignore), "", nil
+}
+
+// File is a file included in a build context
+type File struct {
+	// Path is slash separated and relative to the root of the build context
+	Path string
+	Size int64
+}
+
+// BuildContext is the set of files which are sent to the builder
+type BuildContext struct {
+	// IgnoreFile is the name of the ignore file the context was filtered with, if any
+	IgnoreFile string
+	Files      []File
+	// Size is the total size of the included files in bytes
+	Size int64
+	// Excludes lists every excluded path whose parent directory is included, as exact patterns which can be passed
+	// to the docker archiver so that the tarball matches Files
+	Excludes []string
+}
+
+// ListBuildContext walks dir, returning every file which is not excluded by m. Paths in keep, relative to dir, are
+// always included, so that the Dockerfile and ignore file the builder needs are never dropped.
+func ListBuildContext(dir string, m *Matcher, keep ...string) (*BuildContext, error) {
+	if m == nil {
+		m = New(nil, Syntax_Gitignore)
+	}
+
+	kept := make(map[string]bool)
+	for _, k := range keep {
+		k = path.Clean(filepath.ToSlash(k))
+		// a kept path must not escape the build context, or be the .git directory
+		if k == "." || strings.HasPrefix(k, "../") || k == ".." || filepath.IsAbs(k) || isUnderGitDir(k) {
+			continue
+		}
+		kept[k] = true
+	}
+
+	buildContext := &BuildContext{}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if m.Excluded(rel, d.IsDir()) && !kept[rel] {
+			if !d.IsDir() {
+				buildContext.Excludes = append(buildContext.Excludes, escapePattern(rel))
+				return nil
+			}
+
+			// a directory has to be walked if something inside it may still be included
+			if isUnderGitDir(rel) || !hasKeptDescendant(kept, rel) && (m.syntax == Syntax_Gitignore || !m.hasExceptions) {
+				buildContext.Excludes = append(buildContext.Excludes, escapePattern(rel))
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		buildContext.Files = append(buildContext.Files, File{Path: rel, Size: info.Size()})
+		buildContext.Size += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking build context: %w", err)
+	}
+
+	return buildContext, nil
+}
+
+func isUnderGitDir(rel string) bool {
+	for _, segment := range strings.Split(rel, "/") {
+		if segment == gitDir {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasKeptDescendant(kept map[string]bool, dir string) bool {
+	for k := range kept {
+		if strings.HasPrefix(k, dir+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// escapePattern escapes glob characters so that the path is matched literally by the docker archiver
+func escapePattern(p string) string {
+	if filepath.Separator != '/' {
+		// backslash is the path separator on windows, so it cannot be used to escape glob characters
+		return filepath.FromSlash(p)
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`)
+	return replacer.Replace(p)
+}
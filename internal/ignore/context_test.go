@@ -0,0 +1,190 @@
+package ignore_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/ignore"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func paths(buildContext *ignore.BuildContext) []string {
+	var out []string
+	for _, f := range buildContext.Files {
+		out = append(out, f.Path)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+func TestListBuildContextWithPorterignore(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".porterignore":                "node_modules/\n*.log\n!keep.log\ndocs/*\n!docs/api\n",
+		".dockerignore":                "src\n",
+		".git/HEAD":                    "ref: refs/heads/main",
+		"Dockerfile":                   "FROM scratch",
+		"src/main.go":                  "package main",
+		"src/debug.log":                "debug",
+		"keep.log":                     "keep",
+		"node_modules/react/index.js":  "module.exports = {}",
+		"web/node_modules/a/index.js":  "module.exports = {}",
+		"docs/guide.md":                "guide",
+		"docs/api/openapi.yaml":        "openapi: 3.0.0",
+		"vendor/github.com/x/.git":     "gitdir: ../../.git/modules/x",
+		"vendor/github.com/x/x.go":     "package x",
+		"web/[brackets]/weird*name.js": "",
+	})
+
+	m, ignoreFile, err := ignore.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ignoreFile != ignore.PorterIgnoreFile {
+		t.Fatalf("expected .porterignore to take precedence, got %q", ignoreFile)
+	}
+
+	buildContext, err := ignore.ListBuildContext(dir, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		".dockerignore",
+		".porterignore",
+		"Dockerfile",
+		"docs/api/openapi.yaml",
+		"keep.log",
+		"src/main.go",
+		"vendor/github.com/x/x.go",
+		"web/[brackets]/weird*name.js",
+	}
+	if got := paths(buildContext); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected files %v, got %v", expected, got)
+	}
+
+	var size int64
+	for _, f := range buildContext.Files {
+		size += f.Size
+	}
+	if buildContext.Size != size || size == 0 {
+		t.Errorf("expected total size %d, got %d", size, buildContext.Size)
+	}
+
+	// excluded directories are pruned rather than listed file by file
+	expectedExcludes := []string{
+		".git",
+		"docs/guide.md",
+		"node_modules",
+		"src/debug.log",
+		"vendor/github.com/x/.git",
+		"web/node_modules",
+	}
+	excludes := append([]string{}, buildContext.Excludes...)
+	sort.Strings(excludes)
+	if !reflect.DeepEqual(excludes, expectedExcludes) {
+		t.Errorf("expected excludes %v, got %v", expectedExcludes, excludes)
+	}
+}
+
+func TestListBuildContextFallsBackToDockerignore(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".dockerignore":   "docs\n!docs/README.md\n*.md\n!README.md\n",
+		".git/HEAD":       "ref: refs/heads/main",
+		"Dockerfile":      "FROM scratch",
+		"README.md":       "readme",
+		"CHANGELOG.md":    "changes",
+		"docs/README.md":  "docs readme",
+		"docs/guide.html": "guide",
+		"app/notes.md":    "nested markdown is not matched by a root pattern",
+	})
+
+	m, ignoreFile, err := ignore.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ignoreFile != ignore.DockerIgnoreFile {
+		t.Fatalf("expected .dockerignore to be used, got %q", ignoreFile)
+	}
+
+	buildContext, err := ignore.ListBuildContext(dir, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the exception re-includes a file inside an excluded directory, which gitignore syntax does not allow
+	expected := []string{".dockerignore", "Dockerfile", "README.md", "app/notes.md", "docs/README.md"}
+	if got := paths(buildContext); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected files %v, got %v", expected, got)
+	}
+}
+
+func TestListBuildContextKeepsBuildFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".porterignore":           "docker/\n",
+		"docker/web.Dockerfile":   "FROM scratch",
+		"docker/other.Dockerfile": "FROM scratch",
+		"main.go":                 "package main",
+	})
+
+	m, _, err := ignore.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buildContext, err := ignore.ListBuildContext(dir, m, "docker/web.Dockerfile", "../outside", ".git/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{".porterignore", "docker/web.Dockerfile", "main.go"}
+	if got := paths(buildContext); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected files %v, got %v", expected, got)
+	}
+	if !reflect.DeepEqual(buildContext.Excludes, []string{"docker/other.Dockerfile"}) {
+		t.Errorf("expected only the other Dockerfile to be excluded, got %v", buildContext.Excludes)
+	}
+}
+
+func TestLoadWithoutIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".git/HEAD": "ref: refs/heads/main",
+		"main.go":   "package main",
+	})
+
+	m, ignoreFile, err := ignore.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ignoreFile != "" {
+		t.Fatalf("expected no ignore file, got %q", ignoreFile)
+	}
+
+	buildContext, err := ignore.ListBuildContext(dir, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := paths(buildContext); !reflect.DeepEqual(got, []string{"main.go"}) {
+		t.Fatalf("expected only main.go, got %v", got)
+	}
+}
@@ -0,0 +1,278 @@
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Syntax is the flavour of pattern syntax used by an ignore file
+type Syntax int
+
+const (
+	// Syntax_Gitignore follows the rules of .gitignore: unanchored patterns match at any depth, a trailing slash
+	// only matches directories, and files inside an excluded directory cannot be re-included
+	Syntax_Gitignore Syntax = iota
+	// Syntax_Dockerignore follows the rules of .dockerignore: every pattern is relative to the root of the build
+	// context, and an exception can re-include a file inside an excluded directory
+	Syntax_Dockerignore
+)
+
+// gitDir is always excluded from a build context, regardless of the patterns in the ignore file
+const gitDir = ".git"
+
+type pattern struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// Matcher decides whether a path in a build context is excluded by a set of ignore patterns
+type Matcher struct {
+	syntax   Syntax
+	patterns []pattern
+	// hasExceptions is true if any pattern is negated
+	hasExceptions bool
+}
+
+// Parse reads ignore patterns from r, one per line
+func Parse(r io.Reader, syntax Syntax) (*Matcher, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading ignore patterns: %w", err)
+	}
+
+	return New(lines, syntax), nil
+}
+
+// New returns a Matcher for the given ignore patterns. Blank lines, comments and malformed patterns are skipped.
+func New(lines []string, syntax Syntax) *Matcher {
+	m := &Matcher{syntax: syntax}
+
+	for _, line := range lines {
+		var p pattern
+		var ok bool
+
+		switch syntax {
+		case Syntax_Dockerignore:
+			p, ok = parseDockerignoreLine(line)
+		default:
+			p, ok = parseGitignoreLine(line)
+		}
+		if !ok {
+			continue
+		}
+
+		m.patterns = append(m.patterns, p)
+		if p.negate {
+			m.hasExceptions = true
+		}
+	}
+
+	return m
+}
+
+func parseGitignoreLine(line string) (pattern, bool) {
+	var p pattern
+
+	line = strings.TrimSuffix(line, "\r")
+	line = trimUnescapedTrailingSpaces(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return p, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// a slash at the start or in the middle of a pattern anchors it to the root, otherwise it matches at any depth
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return p, false
+	}
+
+	p.segments = splitSegments(line)
+	if !anchored && p.segments[0] != "**" {
+		p.segments = append([]string{"**"}, p.segments...)
+	}
+
+	return p, validSegments(p.segments)
+}
+
+func parseDockerignoreLine(line string) (pattern, bool) {
+	var p pattern
+
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return p, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = strings.TrimSpace(line[1:])
+	}
+
+	line = strings.TrimPrefix(path.Clean(line), "/")
+	if line == "" || line == "." {
+		return p, false
+	}
+
+	p.segments = splitSegments(line)
+
+	return p, validSegments(p.segments)
+}
+
+// trimUnescapedTrailingSpaces removes trailing spaces from a gitignore line, unless they are escaped with a backslash
+func trimUnescapedTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") {
+		if strings.HasSuffix(line, `\ `) {
+			return line[:len(line)-2] + " "
+		}
+		line = line[:len(line)-1]
+	}
+
+	return line
+}
+
+func splitSegments(p string) []string {
+	var segments []string
+	for _, segment := range strings.Split(p, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments
+}
+
+func validSegments(segments []string) bool {
+	for _, segment := range segments {
+		if _, err := path.Match(globSegment(segment), ""); err != nil {
+			return false
+		}
+	}
+
+	return len(segments) > 0
+}
+
+// globSegment converts the fnmatch style [!...] character class to the [^...] form understood by path.Match
+func globSegment(segment string) string {
+	return strings.ReplaceAll(segment, "[!", "[^")
+}
+
+func (p pattern) match(segments []string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+
+	return matchSegments(p.segments, segments)
+}
+
+func matchSegments(patterns, segments []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			// a trailing ** matches everything inside a directory, but not the directory itself
+			if len(patterns) == 1 {
+				return len(segments) > 0
+			}
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(patterns[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(globSegment(patterns[0]), segments[0]); !ok {
+			return false
+		}
+
+		patterns, segments = patterns[1:], segments[1:]
+	}
+
+	return len(segments) == 0
+}
+
+// HasExceptions returns true if any pattern re-includes paths with a leading !
+func (m *Matcher) HasExceptions() bool {
+	return m.hasExceptions
+}
+
+// Excluded returns true if the slash separated path, relative to the root of the build context, is excluded. The
+// .git directory is always excluded.
+func (m *Matcher) Excluded(relPath string, isDir bool) bool {
+	segments := splitSegments(path.Clean(strings.TrimPrefix(relPath, "./")))
+	if len(segments) == 0 || segments[0] == "." {
+		return false
+	}
+
+	for _, segment := range segments {
+		if segment == gitDir {
+			return true
+		}
+	}
+
+	if m.syntax == Syntax_Dockerignore {
+		return m.excludedDockerignore(segments)
+	}
+
+	// git stops at an excluded directory, so nothing beneath it can be re-included
+	for i := 1; i < len(segments); i++ {
+		if m.lastMatch(segments[:i], true) {
+			return true
+		}
+	}
+
+	return m.lastMatch(segments, isDir)
+}
+
+// lastMatch returns whether the path is excluded by the last pattern which matches it
+func (m *Matcher) lastMatch(segments []string, isDir bool) bool {
+	excluded := false
+	for _, p := range m.patterns {
+		if p.negate == excluded && p.match(segments, isDir) {
+			excluded = !p.negate
+		}
+	}
+
+	return excluded
+}
+
+// excludedDockerignore matches each pattern against the path and each of its parents, so that a pattern matching a
+// directory excludes everything inside it, while a later exception can still re-include a file
+func (m *Matcher) excludedDockerignore(segments []string) bool {
+	excluded := false
+	for _, p := range m.patterns {
+		if p.negate != excluded {
+			continue
+		}
+
+		for i := len(segments); i > 0; i-- {
+			if p.match(segments[:i], true) {
+				excluded = !p.negate
+				break
+			}
+		}
+	}
+
+	return excluded
+}
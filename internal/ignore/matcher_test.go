@@ -0,0 +1,289 @@
+package ignore_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/ignore"
+)
+
+type corpusCase struct {
+	path     string
+	isDir    bool
+	excluded bool
+}
+
+// gitignoreCorpus is drawn from the examples in the gitignore documentation and the edge cases exercised by git's
+// own ignore tests, and must give the same result as `git check-ignore`
+var gitignoreCorpus = []struct {
+	name     string
+	patterns string
+	cases    []corpusCase
+}{
+	{
+		name:     "unanchored pattern matches at any depth",
+		patterns: "*.log",
+		cases: []corpusCase{
+			{path: "debug.log", excluded: true},
+			{path: "logs/debug.log", excluded: true},
+			{path: "a/b/c/trace.log", excluded: true},
+			{path: "debug.log.txt"},
+			{path: "logs", isDir: true},
+		},
+	},
+	{
+		name:     "leading slash anchors to the root",
+		patterns: "/debug.log",
+		cases: []corpusCase{
+			{path: "debug.log", excluded: true},
+			{path: "logs/debug.log"},
+		},
+	},
+	{
+		name:     "slash in the middle anchors to the root",
+		patterns: "doc/frotz",
+		cases: []corpusCase{
+			{path: "doc/frotz", excluded: true},
+			{path: "doc/frotz/a.txt", excluded: true},
+			{path: "a/doc/frotz"},
+		},
+	},
+	{
+		name:     "trailing slash only matches directories",
+		patterns: "build/",
+		cases: []corpusCase{
+			{path: "build", isDir: true, excluded: true},
+			{path: "build/out.bin", excluded: true},
+			{path: "src/build", isDir: true, excluded: true},
+			{path: "src/build/out.bin", excluded: true},
+			{path: "build"},
+			{path: "src/build"},
+		},
+	},
+	{
+		name:     "anchored directory pattern",
+		patterns: "frotz/\n/vendor/",
+		cases: []corpusCase{
+			{path: "frotz", isDir: true, excluded: true},
+			{path: "a/frotz", isDir: true, excluded: true},
+			{path: "vendor", isDir: true, excluded: true},
+			{path: "pkg/vendor", isDir: true},
+		},
+	},
+	{
+		name:     "leading double star matches in all directories",
+		patterns: "**/foo\n**/logs/debug.log",
+		cases: []corpusCase{
+			{path: "foo", excluded: true},
+			{path: "a/foo", excluded: true},
+			{path: "a/b/foo", excluded: true},
+			{path: "logs/debug.log", excluded: true},
+			{path: "build/logs/debug.log", excluded: true},
+			{path: "logs/build/debug.log"},
+		},
+	},
+	{
+		name:     "trailing double star matches everything inside",
+		patterns: "abc/**",
+		cases: []corpusCase{
+			{path: "abc/x", excluded: true},
+			{path: "abc/x/y", excluded: true},
+			{path: "abc", isDir: true},
+			{path: "x/abc/y"},
+		},
+	},
+	{
+		name:     "middle double star matches zero or more directories",
+		patterns: "a/**/b",
+		cases: []corpusCase{
+			{path: "a/b", excluded: true},
+			{path: "a/x/b", excluded: true},
+			{path: "a/x/y/b", excluded: true},
+			{path: "a/x/y/c"},
+			{path: "x/a/b"},
+		},
+	},
+	{
+		name:     "single star does not match a slash",
+		patterns: "foo/*",
+		cases: []corpusCase{
+			{path: "foo/test.json", excluded: true},
+			{path: "foo/bar", isDir: true, excluded: true},
+			{path: "foo/bar/hello.c", excluded: true},
+			{path: "foo", isDir: true},
+		},
+	},
+	{
+		name:     "question mark and character classes",
+		patterns: "debug?.log\ndebug[0-9].txt\ntrace[!01].out",
+		cases: []corpusCase{
+			{path: "debug1.log", excluded: true},
+			{path: "debug10.log"},
+			{path: "debug5.txt", excluded: true},
+			{path: "debuga.txt"},
+			{path: "trace2.out", excluded: true},
+			{path: "trace1.out"},
+		},
+	},
+	{
+		name:     "negation re-includes a file",
+		patterns: "*.log\n!important.log",
+		cases: []corpusCase{
+			{path: "debug.log", excluded: true},
+			{path: "important.log"},
+			{path: "logs/important.log"},
+		},
+	},
+	{
+		name:     "later patterns override earlier ones",
+		patterns: "!important.log\n*.log",
+		cases: []corpusCase{
+			{path: "important.log", excluded: true},
+		},
+	},
+	{
+		name:     "a file cannot be re-included if its parent directory is excluded",
+		patterns: "logs/\n!logs/important.log",
+		cases: []corpusCase{
+			{path: "logs", isDir: true, excluded: true},
+			{path: "logs/important.log", excluded: true},
+		},
+	},
+	{
+		name:     "excluding everything but one directory",
+		patterns: "/*\n!/foo\n/foo/*\n!/foo/bar",
+		cases: []corpusCase{
+			{path: "README.md", excluded: true},
+			{path: "foo", isDir: true},
+			{path: "foo/baz", excluded: true},
+			{path: "foo/bar", isDir: true},
+			{path: "foo/bar/keep.txt"},
+		},
+	},
+	{
+		name:     "contents excluded with a directory re-included",
+		patterns: "node_modules/*\n!node_modules/local-pkg",
+		cases: []corpusCase{
+			{path: "node_modules", isDir: true},
+			{path: "node_modules/react", isDir: true, excluded: true},
+			{path: "node_modules/react/index.js", excluded: true},
+			{path: "node_modules/local-pkg", isDir: true},
+			{path: "node_modules/local-pkg/index.js"},
+		},
+	},
+	{
+		name:     "comments, blank lines and trailing spaces",
+		patterns: "# a comment\n\n*.tmp   \n\\#notacomment\nspace\\ \n",
+		cases: []corpusCase{
+			{path: "# a comment"},
+			{path: "a.tmp", excluded: true},
+			{path: "#notacomment", excluded: true},
+			{path: "space ", excluded: true},
+			{path: "space"},
+		},
+	},
+	{
+		name:     "escaped leading exclamation mark is literal",
+		patterns: "\\!important!.txt",
+		cases: []corpusCase{
+			{path: "!important!.txt", excluded: true},
+			{path: "important!.txt"},
+		},
+	},
+	{
+		name:     "crlf line endings",
+		patterns: "dist/\r\n*.map\r\n",
+		cases: []corpusCase{
+			{path: "dist", isDir: true, excluded: true},
+			{path: "app.js.map", excluded: true},
+		},
+	},
+	{
+		name:     "git directory is always excluded",
+		patterns: "!.git\n!.git/**",
+		cases: []corpusCase{
+			{path: ".git", isDir: true, excluded: true},
+			{path: ".git/config", excluded: true},
+			{path: "vendor/lib/.git", excluded: true},
+			{path: ".gitignore"},
+			{path: ".github/workflows/deploy.yml"},
+		},
+	},
+}
+
+func TestGitignoreCorpus(t *testing.T) {
+	for _, tt := range gitignoreCorpus {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ignore.Parse(strings.NewReader(tt.patterns), ignore.Syntax_Gitignore)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, c := range tt.cases {
+				if got := m.Excluded(c.path, c.isDir); got != c.excluded {
+					t.Errorf("path %q (dir: %t): expected excluded to be %t, got %t", c.path, c.isDir, c.excluded, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDockerignoreSyntax(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns string
+		cases    []corpusCase
+	}{
+		{
+			name:     "patterns are relative to the root",
+			patterns: "*.log\n/tmp",
+			cases: []corpusCase{
+				{path: "debug.log", excluded: true},
+				{path: "logs/debug.log"},
+				{path: "tmp", isDir: true, excluded: true},
+				{path: "tmp/a", excluded: true},
+			},
+		},
+		{
+			name:     "double star matches any number of directories",
+			patterns: "**/*.log",
+			cases: []corpusCase{
+				{path: "debug.log", excluded: true},
+				{path: "a/b/debug.log", excluded: true},
+			},
+		},
+		{
+			name:     "an exception can re-include a file inside an excluded directory",
+			patterns: "docs\n!docs/README.md",
+			cases: []corpusCase{
+				{path: "docs", isDir: true, excluded: true},
+				{path: "docs/guide.md", excluded: true},
+				{path: "docs/README.md"},
+			},
+		},
+		{
+			name:     "trailing slashes and dot segments are cleaned",
+			patterns: "./build/\n  cache  ",
+			cases: []corpusCase{
+				{path: "build", isDir: true, excluded: true},
+				{path: "build/out", excluded: true},
+				{path: "cache", isDir: true, excluded: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ignore.Parse(strings.NewReader(tt.patterns), ignore.Syntax_Dockerignore)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, c := range tt.cases {
+				if got := m.Excluded(c.path, c.isDir); got != c.excluded {
+					t.Errorf("path %q (dir: %t): expected excluded to be %t, got %t", c.path, c.isDir, c.excluded, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package secretstores
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+)
+
+// awsSecretsManagerStore reads secrets from AWS Secrets Manager with the credentials of an AWS integration
+type awsSecretsManagerStore struct {
+	client secretsmanageriface.SecretsManagerAPI
+	sts    stsiface.STSAPI
+
+	// secrets caches the secrets already read, by name and version, since env variables often use several fields of
+	// one secret
+	secrets map[string]*secretsmanager.GetSecretValueOutput
+}
+
+func newAWSSecretsManagerStore(integration *ints.SecretsProviderIntegration, awsIntegration *ints.AWSIntegration) (*awsSecretsManagerStore, error) {
+	sess, err := awsIntegration.GetSession()
+	if err != nil {
+		return nil, fmt.Errorf("error creating AWS session: %w", err)
+	}
+
+	conf := aws.NewConfig()
+	if integration.AWSRegion != "" {
+		conf = conf.WithRegion(integration.AWSRegion)
+	}
+
+	return &awsSecretsManagerStore{
+		client:  secretsmanager.New(sess, conf),
+		sts:     sts.New(sess, conf),
+		secrets: make(map[string]*secretsmanager.GetSecretValueOutput),
+	}, nil
+}
+
+// Read returns a secret string, or a field of it if the reference sets a key, at its pinned version or its current
+// version
+func (a *awsSecretsManagerStore) Read(ctx context.Context, ref envvalues.SecretReference) (Secret, error) {
+	cacheKey := ref.Path + "@" + ref.PinnedVersion
+
+	out, ok := a.secrets[cacheKey]
+	if !ok {
+		input := &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(ref.Path),
+		}
+		if ref.PinnedVersion != "" {
+			input.VersionId = aws.String(ref.PinnedVersion)
+		}
+
+		var err error
+		out, err = a.client.GetSecretValueWithContext(ctx, input)
+		if err != nil {
+			return Secret{}, awsError(err)
+		}
+		a.secrets[cacheKey] = out
+	}
+
+	if out.SecretString == nil {
+		return Secret{}, errors.New("secret is binary, only secret strings are supported")
+	}
+
+	secret := Secret{
+		Value:   *out.SecretString,
+		Version: aws.StringValue(out.VersionId),
+	}
+	if ref.Key == "" {
+		return secret, nil
+	}
+
+	// the secret string is not valid JSON, so the decoding error, which can quote it, is not returned
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(*out.SecretString), &fields); err != nil {
+		return Secret{}, fmt.Errorf("secret string is not a JSON object, so key %s cannot be read from it", ref.Key)
+	}
+
+	field, ok := fields[ref.Key]
+	if !ok {
+		return Secret{}, fmt.Errorf("secret has no key %s", ref.Key)
+	}
+
+	value, err := fieldString(field)
+	if err != nil {
+		return Secret{}, err
+	}
+	secret.Value = value
+
+	return secret, nil
+}
+
+// Test checks that AWS accepts the credentials of the AWS integration
+func (a *awsSecretsManagerStore) Test(ctx context.Context) error {
+	if _, err := a.sts.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{}); err != nil {
+		return awsError(err)
+	}
+
+	return nil
+}
+
+// awsError returns the code and message of an error returned by AWS, without the request details the SDK adds
+func awsError(err error) error {
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		return fmt.Errorf("%s: %s", aerr.Code(), aerr.Message())
+	}
+
+	return err
+}
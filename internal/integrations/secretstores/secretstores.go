@@ -0,0 +1,186 @@
+// Package secretstores reads the secrets which env variables in porter.yaml reference with valueFrom from the external
+// secret stores of a project, such as Vault or AWS Secrets Manager.
+//
+// Values are only ever returned to the caller, which writes them into the app's secret in the cluster. They are never
+// logged or added to errors: an error names the env variable, the store and the path it was read from, and the error
+// the store returned, which never contains the secret.
+package secretstores
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Secret is a value read from a secret store
+type Secret struct {
+	Value string
+	// Version is the version the value was read at, which changes when the secret is rotated
+	Version string
+}
+
+// Store reads secrets from an external secret store
+type Store interface {
+	// Read returns the field of the secret which ref points to
+	Read(ctx context.Context, ref envvalues.SecretReference) (Secret, error)
+	// Test checks that the store can be reached and accepts its credentials
+	Test(ctx context.Context) error
+}
+
+// NewStore returns a client for the store an integration connects to. The AWS integration is only used, and is
+// required, for the aws_secrets_manager provider.
+func NewStore(integration *ints.SecretsProviderIntegration, awsIntegration *ints.AWSIntegration) (Store, error) {
+	switch integration.Provider {
+	case types.SecretsProvider_Vault:
+		return newVaultStore(integration)
+	case types.SecretsProvider_AWSSecretsManager:
+		if awsIntegration == nil {
+			return nil, errors.New("aws_secrets_manager requires an AWS integration")
+		}
+		return newAWSSecretsManagerStore(integration, awsIntegration)
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %s", integration.Provider)
+	}
+}
+
+// StoreLookup returns the store of the project for a provider
+type StoreLookup func(ctx context.Context, provider types.SecretsProvider) (Store, error)
+
+// RepoStoreLookup returns the stores a project has configured in the database
+func RepoStoreLookup(repo repository.Repository, projectID uint) StoreLookup {
+	return func(ctx context.Context, provider types.SecretsProvider) (Store, error) {
+		integration, err := repo.SecretsProviderIntegration().ReadSecretsProviderIntegration(ctx, projectID, provider)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("the project has no %s secrets provider configured", provider)
+			}
+			return nil, fmt.Errorf("error reading %s secrets provider: %w", provider, err)
+		}
+
+		var awsIntegration *ints.AWSIntegration
+		if provider == types.SecretsProvider_AWSSecretsManager {
+			awsIntegration, err = repo.AWSIntegration().ReadAWSIntegration(projectID, integration.AWSIntegrationID)
+			if err != nil {
+				return nil, fmt.Errorf("error reading AWS integration %d of %s secrets provider: %w", integration.AWSIntegrationID, provider, err)
+			}
+		}
+
+		return NewStore(integration, awsIntegration)
+	}
+}
+
+// Resolver reads the secrets referenced by a deploy, connecting to each store of the project at most once
+type Resolver struct {
+	lookup StoreLookup
+	stores map[types.SecretsProvider]Store
+	errs   map[types.SecretsProvider]error
+}
+
+// NewResolver returns a Resolver which finds stores with lookup
+func NewResolver(lookup StoreLookup) *Resolver {
+	return &Resolver{
+		lookup: lookup,
+		stores: make(map[types.SecretsProvider]Store),
+		errs:   make(map[types.SecretsProvider]error),
+	}
+}
+
+// Resolved are the values of the env variables set with valueFrom, with the versions they were read at
+type Resolved struct {
+	// Values are the values of the env variables, by env variable name
+	Values map[string]string
+
+	refs     map[string]envvalues.SecretReference
+	versions map[string]string
+}
+
+// Fingerprint identifies the secrets and the versions the values were read at, without revealing the values, so that
+// workloads can be restarted when a secret is rotated. It is empty if there are no values.
+func (r Resolved) Fingerprint() string {
+	if len(r.Values) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(r.refs))
+	for key := range r.refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		ref := r.refs[key]
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\n", key, ref.Provider, ref.Path, ref.Key, r.versions[key])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Resolve reads every reference. Every reference which cannot be read is reported, not only the first.
+func (r *Resolver) Resolve(ctx context.Context, refs map[string]envvalues.SecretReference) (Resolved, error) {
+	resolved := Resolved{
+		Values:   make(map[string]string, len(refs)),
+		refs:     refs,
+		versions: make(map[string]string, len(refs)),
+	}
+
+	keys := make([]string, 0, len(refs))
+	for key := range refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []string
+	for _, key := range keys {
+		ref := refs[key]
+
+		store, err := r.store(ctx, ref.Provider)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("env variable %s: %s", key, err))
+			continue
+		}
+
+		secret, err := store.Read(ctx, ref)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("env variable %s: error reading %s: %s", key, ref, err))
+			continue
+		}
+
+		resolved.Values[key] = secret.Value
+		resolved.versions[key] = secret.Version
+	}
+
+	if len(errs) > 0 {
+		return Resolved{}, errors.New(strings.Join(errs, "; "))
+	}
+
+	return resolved, nil
+}
+
+func (r *Resolver) store(ctx context.Context, provider types.SecretsProvider) (Store, error) {
+	if store, ok := r.stores[provider]; ok {
+		return store, nil
+	}
+	if err, ok := r.errs[provider]; ok {
+		return nil, err
+	}
+
+	store, err := r.lookup(ctx, provider)
+	if err != nil {
+		r.errs[provider] = err
+		return nil, err
+	}
+	r.stores[provider] = store
+
+	return store, nil
+}
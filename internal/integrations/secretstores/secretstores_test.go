@@ -0,0 +1,278 @@
+package secretstores
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+)
+
+func newTestVault(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+
+	reads := 0
+	secrets := map[string]map[string]interface{}{
+		"2": {"password": "hunter2", "port": 5432},
+		"3": {"password": "hunter3", "port": 5432},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["role_id"] != "role" || body["secret_id"] != "s3cr3t" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"approle-token"}}`))
+			return
+		case "/v1/auth/token/lookup-self":
+		case "/v1/kv/data/app/db":
+			reads++
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+
+		if token := r.Header.Get("X-Vault-Token"); token != "root-token" && token != "approle-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		if r.URL.Path == "/v1/auth/token/lookup-self" {
+			_, _ = w.Write([]byte(`{"data":{}}`))
+			return
+		}
+
+		version := r.URL.Query().Get("version")
+		if version == "" {
+			version = "3"
+		}
+		data, ok := secrets[version]
+		if !ok {
+			_, _ = w.Write([]byte(`{"data":{"data":null,"metadata":{"version":1}}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     data,
+				"metadata": map[string]interface{}{"version": json.Number(version)},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &reads
+}
+
+func TestVaultStore_Read(t *testing.T) {
+	server, reads := newTestVault(t)
+
+	store, err := NewStore(&ints.SecretsProviderIntegration{
+		Provider:        types.SecretsProvider_Vault,
+		VaultAddress:    server.URL,
+		VaultMount:      "kv",
+		VaultAuthMethod: types.VaultAuthMethod_AppRole,
+		VaultRoleID:     []byte("role"),
+		VaultSecretID:   []byte("s3cr3t"),
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		ref     envvalues.SecretReference
+		want    Secret
+		wantErr string
+	}{
+		{
+			name: "current version",
+			ref:  envvalues.SecretReference{Provider: types.SecretsProvider_Vault, Path: "app/db", Key: "password"},
+			want: Secret{Value: "hunter3", Version: "3"},
+		},
+		{
+			name: "field which is not a string",
+			ref:  envvalues.SecretReference{Provider: types.SecretsProvider_Vault, Path: "app/db", Key: "port"},
+			want: Secret{Value: "5432", Version: "3"},
+		},
+		{
+			name: "pinned version",
+			ref:  envvalues.SecretReference{Provider: types.SecretsProvider_Vault, Path: "app/db", Key: "password", PinnedVersion: "2"},
+			want: Secret{Value: "hunter2", Version: "2"},
+		},
+		{
+			name:    "deleted version",
+			ref:     envvalues.SecretReference{Provider: types.SecretsProvider_Vault, Path: "app/db", Key: "password", PinnedVersion: "1"},
+			wantErr: "secret has no data at this version",
+		},
+		{
+			name:    "missing key",
+			ref:     envvalues.SecretReference{Provider: types.SecretsProvider_Vault, Path: "app/db", Key: "user"},
+			wantErr: "secret has no key user",
+		},
+		{
+			name:    "missing secret",
+			ref:     envvalues.SecretReference{Provider: types.SecretsProvider_Vault, Path: "app/cache", Key: "password"},
+			wantErr: "vault returned 404: secret not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Read(ctx, tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	// the current version and the pinned version were each read once, and the deleted version once
+	if *reads != 3 {
+		t.Errorf("expected 3 reads from vault, got %d", *reads)
+	}
+}
+
+func TestVaultStore_Test(t *testing.T) {
+	server, _ := newTestVault(t)
+
+	tests := []struct {
+		name        string
+		integration *ints.SecretsProviderIntegration
+		wantErr     string
+	}{
+		{
+			name: "valid token",
+			integration: &ints.SecretsProviderIntegration{
+				VaultAuthMethod: types.VaultAuthMethod_Token,
+				VaultToken:      []byte("root-token"),
+			},
+		},
+		{
+			name: "invalid token",
+			integration: &ints.SecretsProviderIntegration{
+				VaultAuthMethod: types.VaultAuthMethod_Token,
+				VaultToken:      []byte("expired-token"),
+			},
+			wantErr: "vault returned 403: permission denied",
+		},
+		{
+			name: "invalid approle",
+			integration: &ints.SecretsProviderIntegration{
+				VaultAuthMethod: types.VaultAuthMethod_AppRole,
+				VaultRoleID:     []byte("role"),
+				VaultSecretID:   []byte("wrong"),
+			},
+			wantErr: "error logging in with approle: vault returned 400: invalid role or secret ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.integration.Provider = types.SecretsProvider_Vault
+			tt.integration.VaultAddress = server.URL
+
+			store, err := NewStore(tt.integration, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = store.Test(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+type fakeStore map[string]Secret
+
+func (f fakeStore) Read(_ context.Context, ref envvalues.SecretReference) (Secret, error) {
+	secret, ok := f[ref.Path]
+	if !ok {
+		return Secret{}, errors.New("secret not found")
+	}
+	return secret, nil
+}
+
+func (f fakeStore) Test(context.Context) error { return nil }
+
+func TestResolver_Resolve(t *testing.T) {
+	lookups := 0
+	store := fakeStore{"app/db": {Value: "hunter2", Version: "1"}}
+	lookup := func(_ context.Context, provider types.SecretsProvider) (Store, error) {
+		lookups++
+		if provider != types.SecretsProvider_Vault {
+			return nil, errors.New("the project has no aws_secrets_manager secrets provider configured")
+		}
+		return store, nil
+	}
+
+	refs := map[string]envvalues.SecretReference{
+		"DB_PASSWORD": {Provider: types.SecretsProvider_Vault, Path: "app/db", Key: "password"},
+		"DB_COPY":     {Provider: types.SecretsProvider_Vault, Path: "app/db", Key: "password"},
+	}
+
+	resolved, err := NewResolver(lookup).Resolve(context.Background(), refs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved.Values["DB_PASSWORD"] != "hunter2" || resolved.Values["DB_COPY"] != "hunter2" {
+		t.Errorf("unexpected values %v", resolved.Values)
+	}
+	if lookups != 1 {
+		t.Errorf("expected the store to be looked up once, got %d", lookups)
+	}
+
+	fingerprint := resolved.Fingerprint()
+	if fingerprint == "" || strings.Contains(fingerprint, "hunter2") {
+		t.Errorf("unexpected fingerprint %q", fingerprint)
+	}
+
+	// rotating the secret changes the fingerprint
+	store["app/db"] = Secret{Value: "hunter3", Version: "2"}
+	rotated, err := NewResolver(lookup).Resolve(context.Background(), refs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated.Fingerprint() == fingerprint {
+		t.Errorf("expected the fingerprint to change when the secret is rotated")
+	}
+
+	refs["API_KEY"] = envvalues.SecretReference{Provider: types.SecretsProvider_AWSSecretsManager, Path: "prod/api"}
+	refs["MISSING"] = envvalues.SecretReference{Provider: types.SecretsProvider_Vault, Path: "app/missing", Key: "token"}
+
+	_, err = NewResolver(lookup).Resolve(context.Background(), refs)
+	want := "env variable API_KEY: the project has no aws_secrets_manager secrets provider configured; " +
+		"env variable MISSING: error reading vault app/missing#token: secret not found"
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,267 @@
+package secretstores
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+)
+
+const (
+	// defaultVaultMount is where Vault mounts the KV version 2 secrets engine by default
+	defaultVaultMount = "secret"
+	// vaultTimeout bounds each request to Vault, so that an unreachable server cannot stall a deploy
+	vaultTimeout = 10 * time.Second
+	// maxVaultResponseBytes bounds the responses read from Vault
+	maxVaultResponseBytes = 1 << 20
+)
+
+// vaultStore reads secrets from a KV version 2 secrets engine
+type vaultStore struct {
+	address    string
+	namespace  string
+	mount      string
+	authMethod types.VaultAuthMethod
+	token      string
+	roleID     string
+	secretID   string
+
+	client *http.Client
+	// data caches the secrets already read, by path and version, since env variables often use several fields of one
+	// secret
+	data map[string]vaultSecretData
+}
+
+type vaultSecretData struct {
+	fields  map[string]interface{}
+	version string
+}
+
+func newVaultStore(integration *ints.SecretsProviderIntegration) (*vaultStore, error) {
+	if integration.VaultAddress == "" {
+		return nil, errors.New("vault secrets provider has no address")
+	}
+
+	mount := strings.Trim(integration.VaultMount, "/")
+	if mount == "" {
+		mount = defaultVaultMount
+	}
+
+	store := &vaultStore{
+		address:    strings.TrimRight(integration.VaultAddress, "/"),
+		namespace:  integration.VaultNamespace,
+		mount:      mount,
+		authMethod: integration.VaultAuthMethod,
+		token:      string(integration.VaultToken),
+		roleID:     string(integration.VaultRoleID),
+		secretID:   string(integration.VaultSecretID),
+		client:     &http.Client{Timeout: vaultTimeout},
+		data:       make(map[string]vaultSecretData),
+	}
+
+	switch store.authMethod {
+	case types.VaultAuthMethod_Token:
+		if store.token == "" {
+			return nil, errors.New("vault secrets provider uses token auth but has no token")
+		}
+	case types.VaultAuthMethod_AppRole:
+		if store.roleID == "" || store.secretID == "" {
+			return nil, errors.New("vault secrets provider uses approle auth but is missing its role id or secret id")
+		}
+	default:
+		return nil, fmt.Errorf("vault secrets provider has unknown auth method %s", store.authMethod)
+	}
+
+	return store, nil
+}
+
+// Read returns a field of a secret, at its pinned version or its current version
+func (v *vaultStore) Read(ctx context.Context, ref envvalues.SecretReference) (Secret, error) {
+	cacheKey := ref.Path + "@" + ref.PinnedVersion
+
+	data, ok := v.data[cacheKey]
+	if !ok {
+		var err error
+		data, err = v.readSecret(ctx, ref.Path, ref.PinnedVersion)
+		if err != nil {
+			return Secret{}, err
+		}
+		v.data[cacheKey] = data
+	}
+
+	field, ok := data.fields[ref.Key]
+	if !ok {
+		return Secret{}, fmt.Errorf("secret has no key %s", ref.Key)
+	}
+
+	value, err := fieldString(field)
+	if err != nil {
+		return Secret{}, err
+	}
+
+	return Secret{Value: value, Version: data.version}, nil
+}
+
+func (v *vaultStore) readSecret(ctx context.Context, path string, version string) (vaultSecretData, error) {
+	endpoint := fmt.Sprintf("/v1/%s/data/%s", v.mount, escapePath(path))
+	if version != "" {
+		endpoint += "?version=" + url.QueryEscape(version)
+	}
+
+	var resp struct {
+		Data struct {
+			Data     map[string]interface{} `json:"data"`
+			Metadata struct {
+				Version int `json:"version"`
+			} `json:"metadata"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+		return vaultSecretData{}, err
+	}
+
+	if resp.Data.Data == nil {
+		return vaultSecretData{}, errors.New("secret has no data at this version, it may have been deleted or destroyed")
+	}
+
+	return vaultSecretData{
+		fields:  resp.Data.Data,
+		version: strconv.Itoa(resp.Data.Metadata.Version),
+	}, nil
+}
+
+// Test logs in and looks up the token Porter reads secrets with
+func (v *vaultStore) Test(ctx context.Context) error {
+	return v.do(ctx, http.MethodGet, "/v1/auth/token/lookup-self", nil, nil)
+}
+
+// login returns the token to read secrets with, logging in with the AppRole on first use
+func (v *vaultStore) login(ctx context.Context) (string, error) {
+	if v.token != "" {
+		return v.token, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"role_id": v.roleID, "secret_id": v.secretID})
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := v.request(ctx, http.MethodPost, "/v1/auth/approle/login", "", body, &resp); err != nil {
+		return "", fmt.Errorf("error logging in with approle: %w", err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return "", errors.New("error logging in with approle: vault returned no token")
+	}
+
+	v.token = resp.Auth.ClientToken
+
+	return v.token, nil
+}
+
+func (v *vaultStore) do(ctx context.Context, method, endpoint string, body []byte, out interface{}) error {
+	token, err := v.login(ctx)
+	if err != nil {
+		return err
+	}
+
+	return v.request(ctx, method, endpoint, token, body, out)
+}
+
+func (v *vaultStore) request(ctx context.Context, method, endpoint, token string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, v.address+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating vault request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error connecting to vault: %w", err)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxVaultResponseBytes))
+	if err != nil {
+		return fmt.Errorf("error reading vault response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return vaultError(resp.StatusCode, respBody)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	// the response may contain secrets, so the decoding error, which can quote it, is not returned
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return errors.New("vault returned a response which is not valid JSON")
+	}
+
+	return nil
+}
+
+// vaultError returns the errors vault reported for a request, which only describe why the request failed
+func vaultError(status int, body []byte) error {
+	var resp struct {
+		Errors []string `json:"errors"`
+	}
+	_ = json.Unmarshal(body, &resp)
+
+	if len(resp.Errors) == 0 {
+		if status == http.StatusNotFound {
+			return errors.New("vault returned 404: secret not found")
+		}
+		return fmt.Errorf("vault returned %d", status)
+	}
+
+	return fmt.Errorf("vault returned %d: %s", status, strings.Join(resp.Errors, ", "))
+}
+
+// escapePath escapes each segment of a secret path, keeping the slashes between them
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// fieldString returns a field of a secret as an env variable value. Fields which are not strings are JSON encoded.
+func fieldString(field interface{}) (string, error) {
+	if s, ok := field.(string); ok {
+		return s, nil
+	}
+
+	by, err := json.Marshal(field)
+	if err != nil {
+		return "", errors.New("secret field cannot be converted to a string")
+	}
+
+	return string(by), nil
+}
@@ -460,9 +460,9 @@ func (a *Agent) GetVersionedConfigMap(name, namespace string, version uint) (*v1
 	return &listResp.Items[0], nil
 }
 
-func (a *Agent) GetLatestVersionedConfigMap(name, namespace string) (*v1.ConfigMap, uint, error) {
+func (a *Agent) GetLatestVersionedConfigMap(ctx context.Context, name, namespace string) (*v1.ConfigMap, uint, error) {
 	listResp, err := a.Clientset.CoreV1().ConfigMaps(namespace).List(
-		context.Background(),
+		ctx,
 		metav1.ListOptions{
 			LabelSelector: fmt.Sprintf("envgroup=%s", name),
 		},
@@ -519,9 +519,9 @@ func (a *Agent) GetLatestVersionedConfigMap(name, namespace string) (*v1.ConfigM
 	return res, latestVersion, nil
 }
 
-func (a *Agent) GetLatestVersionedSecret(name, namespace string) (*v1.Secret, uint, error) {
+func (a *Agent) GetLatestVersionedSecret(ctx context.Context, name, namespace string) (*v1.Secret, uint, error) {
 	listResp, err := a.Clientset.CoreV1().Secrets(namespace).List(
-		context.Background(),
+		ctx,
 		metav1.ListOptions{
 			LabelSelector: fmt.Sprintf("envgroup=%s", name),
 		},
@@ -616,10 +616,10 @@ func (a *Agent) ListNamespaces() (*v1.NamespaceList, error) {
 }
 
 // CreateNamespace creates a namespace with the given name.
-func (a *Agent) CreateNamespace(name string, labels map[string]string) (*v1.Namespace, error) {
+func (a *Agent) CreateNamespace(ctx context.Context, name string, labels map[string]string) (*v1.Namespace, error) {
 	// check if namespace exists
 	checkNS, err := a.Clientset.CoreV1().Namespaces().Get(
-		context.TODO(),
+		ctx,
 		name,
 		metav1.GetOptions{},
 	)
@@ -634,7 +634,7 @@ func (a *Agent) CreateNamespace(name string, labels map[string]string) (*v1.Name
 			stillTerminating := true
 			for {
 				_, err := a.Clientset.CoreV1().Namespaces().Get(
-					context.TODO(),
+					ctx,
 					name,
 					metav1.GetOptions{},
 				)
@@ -671,7 +671,7 @@ func (a *Agent) CreateNamespace(name string, labels map[string]string) (*v1.Name
 	}
 
 	return a.Clientset.CoreV1().Namespaces().Create(
-		context.TODO(),
+		ctx,
 		namespace,
 		metav1.CreateOptions{},
 	)
@@ -881,9 +881,9 @@ func (a *Agent) DeleteJob(name, namespace string) error {
 }
 
 // GetJobPods lists all pods belonging to a job in a namespace
-func (a *Agent) GetJobPods(namespace, jobName string) ([]v1.Pod, error) {
+func (a *Agent) GetJobPods(ctx context.Context, namespace, jobName string) ([]v1.Pod, error) {
 	resp, err := a.Clientset.CoreV1().Pods(namespace).List(
-		context.TODO(),
+		ctx,
 		metav1.ListOptions{
 			LabelSelector: fmt.Sprintf("%s=%s", "job-name", jobName),
 		},
@@ -1105,10 +1105,10 @@ func (a *Agent) GetCronJob(c grapher.Object) (*batchv1.CronJob, error) {
 }
 
 // GetPodsByLabel retrieves pods with matching labels
-func (a *Agent) GetPodsByLabel(selector string, namespace string) (*v1.PodList, error) {
+func (a *Agent) GetPodsByLabel(ctx context.Context, selector string, namespace string) (*v1.PodList, error) {
 	// Search in all namespaces for matching pods
 	return a.Clientset.CoreV1().Pods(namespace).List(
-		context.TODO(),
+		ctx,
 		metav1.ListOptions{
 			LabelSelector: selector,
 		},
@@ -1335,8 +1335,8 @@ func (a *Agent) GetPreviousPodLogs(namespace string, name string, selectedContai
 }
 
 // StopJobWithJobSidecar sends a termination signal to a job running with a sidecar
-func (a *Agent) StopJobWithJobSidecar(namespace, name string) error {
-	jobPods, err := a.GetJobPods(namespace, name)
+func (a *Agent) StopJobWithJobSidecar(ctx context.Context, namespace, name string) error {
+	jobPods, err := a.GetJobPods(ctx, namespace, name)
 	if err != nil {
 		return err
 	}
@@ -1374,7 +1374,7 @@ func (a *Agent) StopJobWithJobSidecar(namespace, name string) error {
 		return err
 	}
 
-	return exec.Stream(remotecommand.StreamOptions{
+	return exec.StreamWithContext(ctx, remotecommand.StreamOptions{
 		Tty:   false,
 		Stdin: strings.NewReader("./signal.sh"),
 	})
@@ -1919,7 +1919,11 @@ func (a *Agent) StreamPorterAgentLokiLog(
 }
 
 // CreateImagePullSecrets will create the required image pull secrets and
-// return a map from the registry name to the name of the secret.
+// return a map from the registry name to the name of the secret. The secrets
+// of the registries are generated in parallel, and a registry whose secret
+// cannot be generated does not stop the others: the secrets which were
+// generated are returned along with the errors of the registries which failed.
+// The outcome for each registry is recorded as its credential status.
 func (a *Agent) CreateImagePullSecrets(
 	repo repository.Repository,
 	namespace string,
@@ -1928,76 +1932,111 @@ func (a *Agent) CreateImagePullSecrets(
 ) (map[string]string, error) {
 	res := make(map[string]string)
 
-	for key, val := range linkedRegs {
-		_reg := registry.Registry(*val)
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
 
-		data, err := _reg.GetDockerConfigJSON(repo, doAuth)
-		if err != nil {
-			return nil, err
-		}
+	for key, val := range linkedRegs {
+		wg.Add(1)
 
-		secretName := fmt.Sprintf("porter-%s-%d", val.ToRegistryType().Service, val.ID)
+		go func(key string, val *models.Registry) {
+			defer wg.Done()
 
-		secret, err := a.Clientset.CoreV1().Secrets(namespace).Get(
-			context.TODO(),
-			secretName,
-			metav1.GetOptions{},
-		)
+			secretName, err := a.createImagePullSecret(repo, namespace, val, doAuth)
 
-		// if not found, create the secret
-		if err != nil && errors.IsNotFound(err) {
-			_, err = a.Clientset.CoreV1().Secrets(namespace).Create(
-				context.TODO(),
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: secretName,
-					},
-					Data: map[string][]byte{
-						string(v1.DockerConfigJsonKey): data,
-					},
-					Type: v1.SecretTypeDockerConfigJson,
-				},
-				metav1.CreateOptions{},
-			)
+			mu.Lock()
+			defer mu.Unlock()
 
 			if err != nil {
-				return nil, err
+				errs = append(errs, fmt.Errorf("error generating pull secret for registry %s: %w", val.Name, err))
+				return
 			}
 
 			// add secret name to the map
 			res[key] = secretName
+		}(key, val)
+	}
 
-			continue
-		} else if err != nil {
-			return nil, err
-		}
+	wg.Wait()
 
-		// otherwise, check that the secret contains the correct data: if
-		// if doesn't, update it
-		if !bytes.Equal(secret.Data[v1.DockerConfigJsonKey], data) {
-			_, err := a.Clientset.CoreV1().Secrets(namespace).Update(
-				context.TODO(),
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: secretName,
-					},
-					Data: map[string][]byte{
-						string(v1.DockerConfigJsonKey): data,
-					},
-					Type: v1.SecretTypeDockerConfigJson,
+	return res, goerrors.Join(errs...)
+}
+
+func (a *Agent) createImagePullSecret(
+	repo repository.Repository,
+	namespace string,
+	val *models.Registry,
+	doAuth *oauth2.Config,
+) (string, error) {
+	_reg := registry.Registry(*val)
+
+	data, err := _reg.GetDockerConfigJSON(repo, doAuth)
+
+	// the status is only used to skip broken registries in later deploys, so failing to record it does not fail
+	// the secret
+	_ = registry.RecordCredentialStatus(repo.Registry(), val, err)
+
+	if err != nil {
+		return "", err
+	}
+
+	secretName := fmt.Sprintf("porter-%s-%d", val.ToRegistryType().Service, val.ID)
+
+	secret, err := a.Clientset.CoreV1().Secrets(namespace).Get(
+		context.TODO(),
+		secretName,
+		metav1.GetOptions{},
+	)
+
+	// if not found, create the secret
+	if err != nil && errors.IsNotFound(err) {
+		_, err = a.Clientset.CoreV1().Secrets(namespace).Create(
+			context.TODO(),
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: secretName,
 				},
-				metav1.UpdateOptions{},
-			)
-			if err != nil {
-				return nil, err
-			}
+				Data: map[string][]byte{
+					string(v1.DockerConfigJsonKey): data,
+				},
+				Type: v1.SecretTypeDockerConfigJson,
+			},
+			metav1.CreateOptions{},
+		)
+
+		if err != nil {
+			return "", err
 		}
 
-		// add secret name to the map
-		res[key] = secretName
+		return secretName, nil
+	} else if err != nil {
+		return "", err
 	}
 
-	return res, nil
+	// otherwise, check that the secret contains the correct data: if
+	// if doesn't, update it
+	if !bytes.Equal(secret.Data[v1.DockerConfigJsonKey], data) {
+		_, err := a.Clientset.CoreV1().Secrets(namespace).Update(
+			context.TODO(),
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: secretName,
+				},
+				Data: map[string][]byte{
+					string(v1.DockerConfigJsonKey): data,
+				},
+				Type: v1.SecretTypeDockerConfigJson,
+			},
+			metav1.UpdateOptions{},
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return secretName, nil
 }
 
 // RunCommandOnPod creates an ephemeral pod from the given pod with the given args as its start command.
@@ -3,7 +3,11 @@ package kubernetes_test
 import (
 	"testing"
 
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository/test"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
@@ -62,3 +66,40 @@ func TestOutOfClusterConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateImagePullSecretsWithBrokenRegistry(t *testing.T) {
+	k8sAgent := newAgentFixture(t)
+	repo := test.NewRepository(true)
+
+	basic, err := repo.BasicIntegration().CreateBasicIntegration(&ints.BasicIntegration{ProjectID: 1, Username: []byte("porter"), Password: []byte("hunter2")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	good, err := repo.Registry().CreateRegistry(&models.Registry{Name: "ecr", URL: "123456789.dkr.ecr.us-east-1.amazonaws.com", ProjectID: 1, BasicIntegrationID: basic.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the basic integration of this registry was deleted, so its pull secret cannot be generated
+	bad, err := repo.Registry().CreateRegistry(&models.Registry{Name: "gar", URL: "us-central1-docker.pkg.dev/storefront/images", ProjectID: 1, BasicIntegrationID: basic.ID + 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secrets, err := k8sAgent.CreateImagePullSecrets(repo, "porter-stack-checkout", map[string]*models.Registry{
+		"123456789.dkr.ecr.us-east-1.amazonaws.com":    good,
+		"us-central1-docker.pkg.dev/storefront/images": bad,
+	}, nil)
+	if err == nil {
+		t.Fatal("expected an error for the broken registry")
+	}
+
+	if len(secrets) != 1 || secrets["123456789.dkr.ecr.us-east-1.amazonaws.com"] == "" {
+		t.Fatalf("expected the pull secret of the working registry to be generated, got %v", secrets)
+	}
+	if good.CredentialStatus != string(types.RegistryCredentialStatus_Valid) {
+		t.Errorf("expected the working registry to be recorded as valid, got %s", good.CredentialStatus)
+	}
+	if bad.CredentialStatus != string(types.RegistryCredentialStatus_Invalid) || bad.CredentialError == "" {
+		t.Errorf("expected the broken registry to be recorded as invalid with its error, got %s: %s", bad.CredentialStatus, bad.CredentialError)
+	}
+}
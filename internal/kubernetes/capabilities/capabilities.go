@@ -0,0 +1,40 @@
+package capabilities
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+const (
+	// DefaultMaxAge is how long detected capabilities are used before they are detected again
+	DefaultMaxAge = 24 * time.Hour
+	// FailedMaxAge is how long capabilities with a failed probe are used before they are detected again
+	FailedMaxAge = 10 * time.Minute
+)
+
+// Known returns true if capabilities were detected and the probe ran without an error, so that the fields it sets can
+// be relied on. Callers should fall back to their previous behavior when a probe is not known.
+func Known(capabilities *types.ClusterCapabilities, probe types.ClusterCapabilityProbe) bool {
+	if capabilities == nil {
+		return false
+	}
+
+	_, failed := capabilities.ProbeErrors[probe]
+	return !failed
+}
+
+// Stale returns true if capabilities were never detected or were detected more than maxAge before now. Capabilities
+// with a failed probe are stale after FailedMaxAge if that is shorter, so that a cluster which was unreachable is not
+// gated on a partial document for long.
+func Stale(capabilities *types.ClusterCapabilities, now time.Time, maxAge time.Duration) bool {
+	if capabilities == nil || capabilities.DetectedAt.IsZero() {
+		return true
+	}
+
+	if len(capabilities.ProbeErrors) > 0 && FailedMaxAge < maxAge {
+		maxAge = FailedMaxAge
+	}
+
+	return now.Sub(capabilities.DetectedAt) > maxAge
+}
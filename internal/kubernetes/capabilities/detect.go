@@ -0,0 +1,284 @@
+// Package capabilities detects the features of a cluster which behave differently across Kubernetes distributions, so
+// that handlers can check what a cluster supports instead of guessing from the cloud provider.
+package capabilities
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// annotationDefaultIngressClass marks the ingress class used by ingresses which do not set one
+	annotationDefaultIngressClass = "ingressclass.kubernetes.io/is-default-class"
+	// annotationDefaultStorageClass marks the storage class used by volume claims which do not set one
+	annotationDefaultStorageClass = "storageclass.kubernetes.io/is-default-class"
+	// annotationBetaDefaultStorageClass is the annotation used by clusters older than Kubernetes 1.13
+	annotationBetaDefaultStorageClass = "storageclass.beta.kubernetes.io/is-default-class"
+	// labelPodSecurityEnforce is the namespace label setting the enforced pod security admission level
+	labelPodSecurityEnforce = "pod-security.kubernetes.io/enforce"
+
+	// maxNodesInspected caps the nodes listed to identify the distribution, since every node of a cluster has the
+	// same distribution labels
+	maxNodesInspected = 5
+)
+
+// distributionNodeLabels identifies a distribution by a label its nodes carry
+var distributionNodeLabels = []struct {
+	label        string
+	distribution types.ClusterDistribution
+}{
+	{label: "eks.amazonaws.com/nodegroup", distribution: types.ClusterDistribution_EKS},
+	{label: "eks.amazonaws.com/compute-type", distribution: types.ClusterDistribution_EKS},
+	{label: "cloud.google.com/gke-nodepool", distribution: types.ClusterDistribution_GKE},
+	{label: "kubernetes.azure.com/cluster", distribution: types.ClusterDistribution_AKS},
+	{label: "kubernetes.azure.com/agentpool", distribution: types.ClusterDistribution_AKS},
+	{label: "doks.digitalocean.com/node-id", distribution: types.ClusterDistribution_DOKS},
+	{label: "node.openshift.io/os_id", distribution: types.ClusterDistribution_OpenShift},
+	{label: "k3s.io/hostname", distribution: types.ClusterDistribution_K3s},
+}
+
+// networkPolicyGroups identifies a network plugin which enforces NetworkPolicy by an API group it serves
+var networkPolicyGroups = []struct {
+	group    string
+	enforcer string
+}{
+	{group: "crd.projectcalico.org", enforcer: "calico"},
+	{group: "cilium.io", enforcer: "cilium"},
+	{group: "crd.antrea.io", enforcer: "antrea"},
+	// the network policy agent of the AWS VPC CNI stores the endpoints of each policy in this group
+	{group: "networking.k8s.aws", enforcer: "aws-network-policy-agent"},
+}
+
+// networkPolicyDaemonSets identifies a network plugin which enforces NetworkPolicy by the daemon set it runs in
+// kube-system, for plugins which serve no API group of their own
+var networkPolicyDaemonSets = []struct {
+	prefix   string
+	enforcer string
+}{
+	{prefix: "calico-node", enforcer: "calico"},
+	{prefix: "canal", enforcer: "calico"},
+	{prefix: "cilium", enforcer: "cilium"},
+	// GKE Dataplane V2 runs cilium as anetd
+	{prefix: "anetd", enforcer: "cilium"},
+	{prefix: "azure-npm", enforcer: "azure-npm"},
+	{prefix: "kube-router", enforcer: "kube-router"},
+	{prefix: "weave-net", enforcer: "weave"},
+}
+
+// Detect probes a cluster for its capabilities. An error is returned only if the API server cannot be reached, in which
+// case any previously detected capabilities should be kept. Otherwise probes are independent: one which fails is
+// recorded in ProbeErrors and the rest still run.
+func Detect(ctx context.Context, clientset kubernetes.Interface, now time.Time) (types.ClusterCapabilities, error) {
+	capabilities := types.ClusterCapabilities{
+		Distribution:   types.ClusterDistribution_Unknown,
+		IngressClasses: []string{},
+		DetectedAt:     now.UTC(),
+	}
+
+	version, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return capabilities, fmt.Errorf("error reaching the cluster's api server: %w", err)
+	}
+	capabilities.KubernetesVersion = version.GitVersion
+
+	fail := func(probe types.ClusterCapabilityProbe, err error) {
+		if capabilities.ProbeErrors == nil {
+			capabilities.ProbeErrors = make(map[types.ClusterCapabilityProbe]string)
+		}
+		capabilities.ProbeErrors[probe] = err.Error()
+	}
+
+	groups, err := apiGroups(clientset)
+	if err != nil {
+		fail(types.ClusterCapabilityProbe_APIGroups, err)
+	} else {
+		capabilities.MetricsServer = groups["metrics.k8s.io"]
+		capabilities.CertManager = groups["cert-manager.io"]
+		capabilities.PodSecurityPolicies = groups["policy/v1beta1/podsecuritypolicies"]
+	}
+
+	if err := detectDistribution(ctx, clientset, groups, &capabilities); err != nil {
+		fail(types.ClusterCapabilityProbe_Distribution, err)
+	}
+	if err := detectIngressClasses(ctx, clientset, &capabilities); err != nil {
+		fail(types.ClusterCapabilityProbe_IngressClasses, err)
+	}
+	if err := detectDefaultStorageClass(ctx, clientset, &capabilities); err != nil {
+		fail(types.ClusterCapabilityProbe_StorageClasses, err)
+	}
+	if err := detectPodSecurity(ctx, clientset, &capabilities); err != nil {
+		fail(types.ClusterCapabilityProbe_PodSecurity, err)
+	}
+	if err := detectNetworkPolicyEnforcer(ctx, clientset, groups, &capabilities); err != nil {
+		fail(types.ClusterCapabilityProbe_NetworkPolicy, err)
+	}
+
+	return capabilities, nil
+}
+
+// apiGroups returns the API groups served by the cluster. PodSecurityPolicy shares the policy group with resources which
+// are still served, so it is returned as the resource path policy/v1beta1/podsecuritypolicies.
+func apiGroups(clientset kubernetes.Interface) (map[string]bool, error) {
+	groupList, err := clientset.Discovery().ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]bool, len(groupList.Groups))
+	for _, group := range groupList.Groups {
+		groups[group.Name] = true
+
+		if group.Name != "policy" {
+			continue
+		}
+		for _, version := range group.Versions {
+			if version.Version != "v1beta1" {
+				continue
+			}
+			resources, err := clientset.Discovery().ServerResourcesForGroupVersion(version.GroupVersion)
+			if err != nil {
+				continue
+			}
+			for _, resource := range resources.APIResources {
+				if resource.Name == "podsecuritypolicies" {
+					groups["policy/v1beta1/podsecuritypolicies"] = true
+				}
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+// detectDistribution identifies the distribution from OpenShift's API groups, the version string of the API server, and
+// the labels of the nodes, in that order. groups may be nil if the API groups could not be listed.
+func detectDistribution(ctx context.Context, clientset kubernetes.Interface, groups map[string]bool, capabilities *types.ClusterCapabilities) error {
+	// OpenShift can run on any cloud, so it is checked before the version string and node labels
+	if groups["config.openshift.io"] || groups["route.openshift.io"] {
+		capabilities.Distribution = types.ClusterDistribution_OpenShift
+		return nil
+	}
+
+	if distribution := distributionFromVersion(capabilities.KubernetesVersion); distribution != types.ClusterDistribution_Unknown {
+		capabilities.Distribution = distribution
+		return nil
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: maxNodesInspected})
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes.Items {
+		for _, candidate := range distributionNodeLabels {
+			if _, ok := node.Labels[candidate.label]; ok {
+				capabilities.Distribution = candidate.distribution
+				return nil
+			}
+		}
+		if node.Labels["node.kubernetes.io/instance-type"] == "k3s" {
+			capabilities.Distribution = types.ClusterDistribution_K3s
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// distributionFromVersion identifies the distributions which include a marker in the version of their API server,
+// for example v1.27.4-eks-2d98532, v1.27.3-gke.100 or v1.27.4+k3s1
+func distributionFromVersion(gitVersion string) types.ClusterDistribution {
+	switch {
+	case strings.Contains(gitVersion, "-eks-"):
+		return types.ClusterDistribution_EKS
+	case strings.Contains(gitVersion, "-gke."):
+		return types.ClusterDistribution_GKE
+	case strings.Contains(gitVersion, "+k3s"):
+		return types.ClusterDistribution_K3s
+	default:
+		return types.ClusterDistribution_Unknown
+	}
+}
+
+func detectIngressClasses(ctx context.Context, clientset kubernetes.Interface, capabilities *types.ClusterCapabilities) error {
+	ingressClasses, err := clientset.NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, ingressClass := range ingressClasses.Items {
+		capabilities.IngressClasses = append(capabilities.IngressClasses, ingressClass.Name)
+		if ingressClass.Annotations[annotationDefaultIngressClass] == "true" {
+			capabilities.DefaultIngressClass = ingressClass.Name
+		}
+	}
+	sort.Strings(capabilities.IngressClasses)
+
+	// a single ingress class is used even when it is not marked as the default, since there is nothing else to pick
+	if capabilities.DefaultIngressClass == "" && len(capabilities.IngressClasses) == 1 {
+		capabilities.DefaultIngressClass = capabilities.IngressClasses[0]
+	}
+
+	return nil
+}
+
+func detectDefaultStorageClass(ctx context.Context, clientset kubernetes.Interface, capabilities *types.ClusterCapabilities) error {
+	storageClasses, err := clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Annotations[annotationDefaultStorageClass] == "true" || storageClass.Annotations[annotationBetaDefaultStorageClass] == "true" {
+			capabilities.DefaultStorageClass = storageClass.Name
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func detectPodSecurity(ctx context.Context, clientset kubernetes.Interface, capabilities *types.ClusterCapabilities) error {
+	namespace, err := clientset.CoreV1().Namespaces().Get(ctx, "default", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	capabilities.PodSecurityEnforce = namespace.Labels[labelPodSecurityEnforce]
+
+	return nil
+}
+
+// detectNetworkPolicyEnforcer looks for a network plugin which enforces NetworkPolicy, first by the API groups it serves
+// and then by the daemon sets of kube-system. groups may be nil if the API groups could not be listed.
+func detectNetworkPolicyEnforcer(ctx context.Context, clientset kubernetes.Interface, groups map[string]bool, capabilities *types.ClusterCapabilities) error {
+	for _, candidate := range networkPolicyGroups {
+		if groups[candidate.group] {
+			capabilities.NetworkPolicyEnforcer = candidate.enforcer
+			return nil
+		}
+	}
+
+	daemonSets, err := clientset.AppsV1().DaemonSets("kube-system").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, daemonSet := range daemonSets.Items {
+		for _, candidate := range networkPolicyDaemonSets {
+			if strings.HasPrefix(daemonSet.Name, candidate.prefix) {
+				capabilities.NetworkPolicyEnforcer = candidate.enforcer
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package capabilities
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func fakeClientset(gitVersion string, groupVersions []string, objects ...runtime.Object) *fake.Clientset {
+	clientset := fake.NewSimpleClientset(objects...)
+
+	discovery := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.FakedServerVersion = &version.Info{GitVersion: gitVersion}
+	for _, groupVersion := range groupVersions {
+		resources := &metav1.APIResourceList{GroupVersion: groupVersion}
+		if groupVersion == "policy/v1beta1" {
+			resources.APIResources = []metav1.APIResource{{Name: "podsecuritypolicies"}}
+		}
+		discovery.Resources = append(discovery.Resources, resources)
+	}
+
+	return clientset
+}
+
+func TestDetect(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	clientset := fakeClientset(
+		"v1.27.4-eks-2d98532",
+		[]string{"v1", "metrics.k8s.io/v1beta1", "cert-manager.io/v1"},
+		&networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "nginx"}},
+		&networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{
+			Name:        "alb",
+			Annotations: map[string]string{annotationDefaultIngressClass: "true"},
+		}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "gp3"}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{
+			Name:        "gp2",
+			Annotations: map[string]string{annotationDefaultStorageClass: "true"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "default",
+			Labels: map[string]string{labelPodSecurityEnforce: "baseline"},
+		}},
+	)
+
+	got, err := Detect(context.Background(), clientset, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Distribution != types.ClusterDistribution_EKS || got.KubernetesVersion != "v1.27.4-eks-2d98532" {
+		t.Errorf("unexpected distribution %s %s", got.Distribution, got.KubernetesVersion)
+	}
+	if len(got.IngressClasses) != 2 || got.IngressClasses[0] != "alb" || got.IngressClasses[1] != "nginx" || got.DefaultIngressClass != "alb" {
+		t.Errorf("unexpected ingress classes %v, default %q", got.IngressClasses, got.DefaultIngressClass)
+	}
+	if got.DefaultStorageClass != "gp2" {
+		t.Errorf("unexpected default storage class %q", got.DefaultStorageClass)
+	}
+	if !got.MetricsServer || !got.CertManager || got.PodSecurityPolicies {
+		t.Errorf("unexpected api groups: metrics server %t, cert manager %t, psp %t", got.MetricsServer, got.CertManager, got.PodSecurityPolicies)
+	}
+	if got.PodSecurityEnforce != "baseline" {
+		t.Errorf("unexpected pod security level %q", got.PodSecurityEnforce)
+	}
+	if len(got.ProbeErrors) != 0 || !got.DetectedAt.Equal(now) {
+		t.Errorf("unexpected probe errors %v or detection time %v", got.ProbeErrors, got.DetectedAt)
+	}
+}
+
+func TestDetect_Distribution(t *testing.T) {
+	node := func(labels map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: labels}}
+	}
+
+	tests := []struct {
+		name          string
+		gitVersion    string
+		groupVersions []string
+		node          *corev1.Node
+		want          types.ClusterDistribution
+	}{
+		{name: "gke version", gitVersion: "v1.27.3-gke.100", want: types.ClusterDistribution_GKE},
+		{name: "k3s version", gitVersion: "v1.27.4+k3s1", want: types.ClusterDistribution_K3s},
+		{name: "aks node labels", gitVersion: "v1.27.3", node: node(map[string]string{"kubernetes.azure.com/cluster": "mc_rg"}), want: types.ClusterDistribution_AKS},
+		{name: "doks node labels", gitVersion: "v1.27.4", node: node(map[string]string{"doks.digitalocean.com/node-id": "1"}), want: types.ClusterDistribution_DOKS},
+		{name: "k3s instance type", gitVersion: "v1.27.4", node: node(map[string]string{"node.kubernetes.io/instance-type": "k3s"}), want: types.ClusterDistribution_K3s},
+		{name: "openshift on aws", gitVersion: "v1.27.6+f67aeb3", groupVersions: []string{"config.openshift.io/v1"}, node: node(map[string]string{"eks.amazonaws.com/nodegroup": "ng"}), want: types.ClusterDistribution_OpenShift},
+		{name: "unknown", gitVersion: "v1.28.0", node: node(map[string]string{"kubernetes.io/os": "linux"}), want: types.ClusterDistribution_Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if tt.node != nil {
+				objects = append(objects, tt.node)
+			}
+
+			got, err := Detect(context.Background(), fakeClientset(tt.gitVersion, tt.groupVersions, objects...), time.Now())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Distribution != tt.want {
+				t.Fatalf("got distribution %s, want %s", got.Distribution, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect_SingleIngressClassAndPodSecurityPolicies(t *testing.T) {
+	clientset := fakeClientset(
+		"v1.24.2",
+		[]string{"policy/v1beta1"},
+		&networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "traefik"}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{
+			Name:        "standard",
+			Annotations: map[string]string{annotationBetaDefaultStorageClass: "true"},
+		}},
+	)
+
+	got, err := Detect(context.Background(), clientset, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.DefaultIngressClass != "traefik" {
+		t.Errorf("expected the only ingress class to be the default, got %q", got.DefaultIngressClass)
+	}
+	if got.DefaultStorageClass != "standard" {
+		t.Errorf("expected the beta annotation to mark the default storage class, got %q", got.DefaultStorageClass)
+	}
+	if !got.PodSecurityPolicies || got.MetricsServer {
+		t.Errorf("unexpected api groups: psp %t, metrics server %t", got.PodSecurityPolicies, got.MetricsServer)
+	}
+	// the default namespace does not exist in the fake cluster
+	if _, ok := got.ProbeErrors[types.ClusterCapabilityProbe_PodSecurity]; !ok {
+		t.Errorf("expected the pod security probe to fail, got %v", got.ProbeErrors)
+	}
+	if !Known(&got, types.ClusterCapabilityProbe_StorageClasses) || Known(&got, types.ClusterCapabilityProbe_PodSecurity) {
+		t.Errorf("expected only the failed probe to be unknown")
+	}
+}
+
+func TestDetect_FailedProbe(t *testing.T) {
+	clientset := fakeClientset("v1.27.4-eks-2d98532", nil)
+	clientset.PrependReactor("list", "storageclasses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("storageclasses is forbidden")
+	})
+
+	got, err := Detect(context.Background(), clientset, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.ProbeErrors[types.ClusterCapabilityProbe_StorageClasses] != "storageclasses is forbidden" {
+		t.Fatalf("expected the storage class probe to fail, got %v", got.ProbeErrors)
+	}
+	if Known(&got, types.ClusterCapabilityProbe_StorageClasses) {
+		t.Fatalf("expected storage classes not to be known")
+	}
+	if got.Distribution != types.ClusterDistribution_EKS {
+		t.Fatalf("expected the other probes to run, got distribution %s", got.Distribution)
+	}
+}
+
+func TestDetect_NetworkPolicyEnforcer(t *testing.T) {
+	daemonSet := func(name string) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system"}}
+	}
+
+	tests := []struct {
+		name          string
+		groupVersions []string
+		daemonSet     *appsv1.DaemonSet
+		want          string
+	}{
+		{name: "calico api group", groupVersions: []string{"crd.projectcalico.org/v1"}, want: "calico"},
+		{name: "aws network policy agent", groupVersions: []string{"networking.k8s.aws/v1alpha1"}, want: "aws-network-policy-agent"},
+		{name: "gke dataplane v2", daemonSet: daemonSet("anetd"), want: "cilium"},
+		{name: "azure npm", daemonSet: daemonSet("azure-npm"), want: "azure-npm"},
+		{name: "flannel", daemonSet: daemonSet("kube-flannel-ds"), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if tt.daemonSet != nil {
+				objects = append(objects, tt.daemonSet)
+			}
+
+			got, err := Detect(context.Background(), fakeClientset("v1.28.0", tt.groupVersions, objects...), time.Now())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !Known(&got, types.ClusterCapabilityProbe_NetworkPolicy) {
+				t.Fatalf("expected the network policy probe to run, got %v", got.ProbeErrors)
+			}
+			if got.NetworkPolicyEnforcer != tt.want {
+				t.Fatalf("got network policy enforcer %q, want %q", got.NetworkPolicyEnforcer, tt.want)
+			}
+		})
+	}
+}
+
+func TestStale(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		capabilities *types.ClusterCapabilities
+		want         bool
+	}{
+		{name: "never detected", capabilities: nil, want: true},
+		{name: "fresh", capabilities: &types.ClusterCapabilities{DetectedAt: now.Add(-time.Hour)}, want: false},
+		{name: "old", capabilities: &types.ClusterCapabilities{DetectedAt: now.Add(-25 * time.Hour)}, want: true},
+		{
+			name: "failed probe",
+			capabilities: &types.ClusterCapabilities{
+				DetectedAt:  now.Add(-time.Hour),
+				ProbeErrors: map[types.ClusterCapabilityProbe]string{types.ClusterCapabilityProbe_PodSecurity: "forbidden"},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Stale(tt.capabilities, now, DefaultMaxAge); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
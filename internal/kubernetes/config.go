@@ -73,7 +73,15 @@ func GetAgentOutOfClusterConfig(ctx context.Context, conf *OutOfClusterConfig) (
 
 	var restConf *rest.Config
 
-	if conf.Cluster.ProvisionedBy == "CAPI" {
+	if conf.Cluster.AuthMechanism == models.AgentTunnel {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "provisioner", Value: "tunnel"})
+
+		rc, err := conf.restConfigForTunnelCluster()
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error getting rest config for tunnel cluster")
+		}
+		restConf = rc
+	} else if conf.Cluster.ProvisionedBy == "CAPI" {
 		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "provisioner", Value: conf.Cluster.ProvisionedBy})
 
 		rc, err := restConfigForCAPICluster(ctx, conf.CAPIManagementClusterClient, *conf.Cluster)
@@ -237,6 +245,32 @@ type OutOfClusterConfig struct {
 	DigitalOceanOAuth *oauth2.Config
 
 	CAPIManagementClusterClient porterv1connect.ClusterControlPlaneServiceClient
+
+	// Only required for clusters connected through a tunnel agent
+	Tunnel ClusterTunnel
+}
+
+// ClusterTunnel routes the requests for clusters which are connected through a tunnel agent running inside them
+type ClusterTunnel interface {
+	// RESTConfig returns a config which sends requests through the agent of the cluster, or an error if it is not connected
+	RESTConfig(clusterID uint) (*rest.Config, error)
+}
+
+// restConfigForTunnelCluster gets the kubernetes rest API client for a cluster connected through a tunnel agent
+func (conf *OutOfClusterConfig) restConfigForTunnelCluster() (*rest.Config, error) {
+	if conf.Tunnel == nil {
+		return nil, fmt.Errorf("cluster %d is connected through a tunnel agent, which this server does not accept", conf.Cluster.ID)
+	}
+
+	restConf, err := conf.Tunnel.RESTConfig(conf.Cluster.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	restConf.Timeout = conf.Timeout
+	rest.SetKubernetesDefaults(restConf)
+
+	return restConf, nil
 }
 
 // ToRESTConfig creates a kubernetes REST client factory -- it calls ClientConfig on
@@ -254,6 +288,10 @@ func (conf *OutOfClusterConfig) ToRESTConfig() (*rest.Config, error) {
 	// 	telemetry.AttributeKV{Key: "project-id", Value: conf.Cluster.ProjectID},
 	// )
 
+	if conf.Cluster.AuthMechanism == models.AgentTunnel {
+		return conf.restConfigForTunnelCluster()
+	}
+
 	if conf.Cluster.ProvisionedBy == "CAPI" {
 		// telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "capi-provisioned", Value: true})
 
@@ -586,6 +624,9 @@ func (conf *OutOfClusterConfig) CreateRawConfigFromCluster(ctx context.Context)
 		)
 
 		authInfoMap[authInfoName].Token = string(azInt.AKSPassword)
+	case models.AgentTunnel:
+		// requests are sent through the agent of the cluster, which authenticates them itself, so the raw config only
+		// carries the namespace
 	default:
 		return nil, telemetry.Error(ctx, span, nil, "auth mechanism not supported")
 	}
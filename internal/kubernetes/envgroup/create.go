@@ -14,7 +14,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 )
 
-func ConvertV1ToV2EnvGroup(agent *kubernetes.Agent, name, namespace string) (*v1.ConfigMap, error) {
+func ConvertV1ToV2EnvGroup(ctx context.Context, agent *kubernetes.Agent, name, namespace string) (*v1.ConfigMap, error) {
 	cm, err := agent.GetConfigMap(name, namespace)
 	if err != nil {
 		return nil, err
@@ -40,7 +40,7 @@ func ConvertV1ToV2EnvGroup(agent *kubernetes.Agent, name, namespace string) (*v1
 		secretVariables[key] = string(val)
 	}
 
-	envGroup, err := CreateEnvGroup(agent, types.ConfigMapInput{
+	envGroup, err := CreateEnvGroup(ctx, agent, types.ConfigMapInput{
 		Name:            name,
 		Namespace:       namespace,
 		Variables:       variables,
@@ -59,9 +59,9 @@ func ConvertV1ToV2EnvGroup(agent *kubernetes.Agent, name, namespace string) (*v1
 	return envGroup, nil
 }
 
-func CreateEnvGroup(agent *kubernetes.Agent, input types.ConfigMapInput) (*v1.ConfigMap, error) {
+func CreateEnvGroup(ctx context.Context, agent *kubernetes.Agent, input types.ConfigMapInput) (*v1.ConfigMap, error) {
 	// look for a latest configmap
-	oldCM, latestVersion, err := agent.GetLatestVersionedConfigMap(input.Name, input.Namespace)
+	oldCM, latestVersion, err := agent.GetLatestVersionedConfigMap(ctx, input.Name, input.Namespace)
 
 	if err != nil && !errors.Is(err, kubernetes.IsNotFoundError) {
 		return nil, err
@@ -81,7 +81,7 @@ func CreateEnvGroup(agent *kubernetes.Agent, input types.ConfigMapInput) (*v1.Co
 		}
 	}
 
-	oldSecret, _, err := agent.GetLatestVersionedSecret(input.Name, input.Namespace)
+	oldSecret, _, err := agent.GetLatestVersionedSecret(ctx, input.Name, input.Namespace)
 
 	if input.SecretVariables == nil {
 		input.SecretVariables = make(map[string]string)
@@ -124,6 +124,41 @@ func CreateEnvGroup(agent *kubernetes.Agent, input types.ConfigMapInput) (*v1.Co
 	return cm, err
 }
 
+// CopyEnvGroup creates a new version of the env group in toNamespace from the latest version of the env group in
+// fromNamespace, including its secret variables
+func CopyEnvGroup(ctx context.Context, agent *kubernetes.Agent, name, fromNamespace, toNamespace string) (*v1.ConfigMap, error) {
+	cm, _, err := agent.GetLatestVersionedConfigMap(ctx, name, fromNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, _, err := agent.GetLatestVersionedSecret(ctx, name, fromNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	secretVars := make(map[string]string)
+
+	// secret variables are referenced from the config map, and the references are recreated for the new version
+	for key, val := range cm.Data {
+		if !strings.Contains(val, "PORTERSECRET") {
+			vars[key] = val
+		}
+	}
+
+	for key, val := range secret.Data {
+		secretVars[key] = string(val)
+	}
+
+	return CreateEnvGroup(ctx, agent, types.ConfigMapInput{
+		Name:            name,
+		Namespace:       toNamespace,
+		Variables:       vars,
+		SecretVariables: secretVars,
+	})
+}
+
 func ToEnvGroup(configMap *v1.ConfigMap) (*types.EnvGroup, error) {
 	res := &types.EnvGroup{
 		CreatedAt: configMap.ObjectMeta.CreationTimestamp.Time,
@@ -1,6 +1,7 @@
 package envgroup
 
 import (
+	"context"
 	"errors"
 
 	"github.com/porter-dev/porter/api/types"
@@ -8,12 +9,12 @@ import (
 	v1 "k8s.io/api/core/v1"
 )
 
-func GetEnvGroup(agent *kubernetes.Agent, name, namespace string, version uint) (*types.EnvGroup, error) {
+func GetEnvGroup(ctx context.Context, agent *kubernetes.Agent, name, namespace string, version uint) (*types.EnvGroup, error) {
 	var configMap *v1.ConfigMap
 	var err error
 
 	if version == 0 {
-		configMap, _, err = agent.GetLatestVersionedConfigMap(name, namespace)
+		configMap, _, err = agent.GetLatestVersionedConfigMap(ctx, name, namespace)
 	} else {
 		configMap, err = agent.GetVersionedConfigMap(name, namespace, version)
 	}
@@ -2,6 +2,7 @@ package prometheus
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -53,3 +54,48 @@ func Test_getNginxStatusQuery(t *testing.T) {
 		})
 	}
 }
+
+func Test_getIngressRequestsQuery(t *testing.T) {
+	query := getIngressRequestsQuery("porter-stack-preview-42", 24*time.Hour)
+
+	assert.Equal(t,
+		`sum(increase(nginx_ingress_controller_requests{exported_namespace="porter-stack-preview-42"}[86400s])) or sum(increase(nginx_ingress_controller_requests{namespace="porter-stack-preview-42"}[86400s])) or on() vector(0)`,
+		query,
+	)
+}
+
+func Test_parseScalarQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected float64
+		wantErr  bool
+	}{
+		{
+			name:     "single series",
+			raw:      `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000.123,"41.5"]}]}}`,
+			expected: 41.5,
+		},
+		{
+			name:     "no series",
+			raw:      `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			expected: 0,
+		},
+		{
+			name:    "value is not a number",
+			raw:     `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"NaN?"]}]}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := parseScalarQuery([]byte(tt.raw))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err, "expected nil, got %v", err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/internal/telemetry"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// QueryIngressRequests returns the number of requests the NGINX ingress controller routed to a namespace within the
+// window ending now. A namespace without any ingress traffic returns 0.
+func QueryIngressRequests(
+	ctx context.Context,
+	clientset kubernetes.Interface,
+	service *v1.Service,
+	namespace string,
+	window time.Duration,
+) (float64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "query-ingress-requests")
+	defer span.End()
+
+	if len(service.Spec.Ports) == 0 {
+		return 0, telemetry.Error(ctx, span, nil, "prometheus service has no exposed ports to query")
+	}
+
+	query := getIngressRequestsQuery(namespace, window)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "namespace", Value: namespace},
+		telemetry.AttributeKV{Key: "query", Value: query},
+	)
+
+	resp := clientset.CoreV1().Services(service.Namespace).ProxyGet(
+		"http",
+		service.Name,
+		fmt.Sprintf("%d", service.Spec.Ports[0].Port),
+		"/api/v1/query",
+		map[string]string{"query": query},
+	)
+
+	rawQuery, err := resp.DoRaw(ctx)
+	if err != nil {
+		return 0, telemetry.Error(ctx, span, err, "failed to get raw query")
+	}
+
+	requests, err := parseScalarQuery(rawQuery)
+	if err != nil {
+		return 0, telemetry.Error(ctx, span, err, "failed to parse query")
+	}
+
+	return requests, nil
+}
+
+func getIngressRequestsQuery(namespace string, window time.Duration) string {
+	var queries []string
+
+	// we recently changed the way labels are read into prometheus, which has removed the 'exported_' prepended to certain labels
+	namespaceLabels := []string{"exported_namespace", "namespace"}
+	for _, namespaceLabel := range namespaceLabels {
+		queries = append(queries, fmt.Sprintf(`sum(increase(nginx_ingress_controller_requests{%s="%s"}[%ds]))`, namespaceLabel, namespace, int64(window.Seconds())))
+	}
+	queries = append(queries, "on() vector(0)")
+
+	return strings.Join(queries, " or ")
+}
+
+type promRawInstantQuery struct {
+	Data struct {
+		Result []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// parseScalarQuery returns the value of the first series of an instant query
+func parseScalarQuery(rawQuery []byte) (float64, error) {
+	rawQueryObj := &promRawInstantQuery{}
+
+	if err := json.Unmarshal(rawQuery, rawQueryObj); err != nil {
+		return 0, err
+	}
+
+	if len(rawQueryObj.Data.Result) == 0 {
+		return 0, nil
+	}
+
+	value := rawQueryObj.Data.Result[0].Value
+	if len(value) != 2 {
+		return 0, fmt.Errorf("expected a timestamp and a value, found %v", value)
+	}
+
+	str, ok := value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("expected the value to be a string, found %T", value[1])
+	}
+
+	return strconv.ParseFloat(str, 64)
+}
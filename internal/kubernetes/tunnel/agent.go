@@ -0,0 +1,305 @@
+package tunnel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/porter-dev/porter/pkg/logger"
+)
+
+const (
+	defaultReconnectBackoff    = time.Second
+	defaultMaxReconnectBackoff = time.Minute
+	// stableConnection is how long a connection has to last for the reconnect backoff to be reset
+	stableConnection = time.Minute
+)
+
+// AgentOptions configure an Agent
+type AgentOptions struct {
+	// ServerURL is the URL of the Porter server, such as https://dashboard.porter.run
+	ServerURL string
+	// ProjectID and ClusterID identify the cluster the agent runs in
+	ProjectID uint
+	ClusterID uint
+	// Token is the tunnel token of the cluster
+	Token string
+	// Version is reported to the server when the agent connects
+	Version string
+
+	// APIServerURL is the URL of the Kubernetes API server requests are proxied to
+	APIServerURL string
+	// Transport authenticates requests to the API server
+	Transport http.RoundTripper
+
+	// Capabilities are the classes of requests the agent proxies, defaulting to deploy and stream requests. Requests
+	// which upgrade the connection cannot be proxied yet.
+	Capabilities []Capability
+
+	// ReconnectBackoff is how long the agent waits before reconnecting, doubled up to MaxReconnectBackoff while
+	// connecting keeps failing
+	ReconnectBackoff    time.Duration
+	MaxReconnectBackoff time.Duration
+
+	Dialer *websocket.Dialer
+	Logger *logger.Logger
+}
+
+// Agent keeps a connection open to the Porter server and proxies the requests the server sends over it to the
+// Kubernetes API server of its cluster
+type Agent struct {
+	opts   AgentOptions
+	client *http.Client
+}
+
+// NewAgent returns an Agent for the cluster it runs in
+func NewAgent(opts AgentOptions) *Agent {
+	if len(opts.Capabilities) == 0 {
+		opts.Capabilities = []Capability{CapabilityDeploy, CapabilityStream}
+	}
+	if opts.ReconnectBackoff == 0 {
+		opts.ReconnectBackoff = defaultReconnectBackoff
+	}
+	if opts.MaxReconnectBackoff == 0 {
+		opts.MaxReconnectBackoff = defaultMaxReconnectBackoff
+	}
+	if opts.Dialer == nil {
+		opts.Dialer = websocket.DefaultDialer
+	}
+	if opts.Logger == nil {
+		opts.Logger = logger.NewConsole(false)
+	}
+
+	return &Agent{
+		opts:   opts,
+		client: &http.Client{Transport: opts.Transport},
+	}
+}
+
+// Run connects to the server and serves its requests until the context is done, reconnecting whenever the
+// connection drops
+func (a *Agent) Run(ctx context.Context) error {
+	backoff := a.opts.ReconnectBackoff
+
+	for {
+		connectedAt := time.Now()
+		err := a.serve(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if time.Since(connectedAt) > stableConnection {
+			backoff = a.opts.ReconnectBackoff
+		}
+
+		a.opts.Logger.Error().Err(err).Dur("backoff", backoff).Msg("tunnel connection to the porter server dropped, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > a.opts.MaxReconnectBackoff {
+			backoff = a.opts.MaxReconnectBackoff
+		}
+	}
+}
+
+// connectURL returns the websocket URL of the server endpoint agents connect to
+func (a *Agent) connectURL() (string, error) {
+	u, err := url.Parse(a.opts.ServerURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid server url: %w", err)
+	}
+
+	switch u.Scheme {
+	case "https", "wss":
+		u.Scheme = "wss"
+	case "http", "ws":
+		u.Scheme = "ws"
+	default:
+		return "", fmt.Errorf("unsupported server url scheme %q", u.Scheme)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + ConnectPath
+
+	return u.String(), nil
+}
+
+// serve serves a single connection to the server until it drops
+func (a *Agent) serve(ctx context.Context) error {
+	connectURL, err := a.connectURL()
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+a.opts.Token)
+	header.Set(HeaderProjectID, fmt.Sprint(a.opts.ProjectID))
+	header.Set(HeaderClusterID, fmt.Sprint(a.opts.ClusterID))
+
+	conn, resp, err := a.opts.Dialer.DialContext(ctx, connectURL, header)
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("error connecting to the porter server, got status %d: %w", resp.StatusCode, err)
+		}
+		return fmt.Errorf("error connecting to the porter server: %w", err)
+	}
+	fc := &frameConn{conn: conn}
+	defer conn.Close()
+
+	// the connection is only closed once every request it carries has been cancelled
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stopClosing := fc.closeWhenDone(connCtx)
+	defer stopClosing()
+
+	// the server pings the agent, so not hearing from it means the connection is gone
+	if err := conn.SetReadDeadline(time.Now().Add(pongWait)); err != nil {
+		return err
+	}
+	conn.SetPingHandler(func(data string) error {
+		if err := conn.SetReadDeadline(time.Now().Add(pongWait)); err != nil {
+			return err
+		}
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(writeTimeout))
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		return err
+	})
+
+	err = fc.write(&Frame{
+		Type: FrameTypeRegister,
+		Registration: &Registration{
+			Version:      a.opts.Version,
+			Capabilities: a.opts.Capabilities,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error registering with the porter server: %w", err)
+	}
+
+	a.opts.Logger.Info().Uint("cluster_id", a.opts.ClusterID).Msg("connected to the porter server")
+
+	var mu sync.Mutex
+	cancels := make(map[uint64]context.CancelFunc)
+
+	for {
+		frame, err := fc.read()
+		if err != nil {
+			return err
+		}
+
+		switch frame.Type {
+		case FrameTypeRequest:
+			reqCtx, cancelReq := context.WithCancel(connCtx)
+
+			mu.Lock()
+			cancels[frame.StreamID] = cancelReq
+			mu.Unlock()
+
+			go func(frame *Frame) {
+				defer func() {
+					mu.Lock()
+					delete(cancels, frame.StreamID)
+					mu.Unlock()
+					cancelReq()
+				}()
+
+				a.proxy(reqCtx, fc, frame)
+			}(frame)
+		case FrameTypeCancel:
+			mu.Lock()
+			cancelReq, ok := cancels[frame.StreamID]
+			mu.Unlock()
+
+			if ok {
+				cancelReq()
+			}
+		}
+	}
+}
+
+// proxy sends a request to the API server, and streams its response back to the server
+func (a *Agent) proxy(ctx context.Context, fc *frameConn, frame *Frame) {
+	end := func(err error) {
+		reply := &Frame{Type: FrameTypeEnd, StreamID: frame.StreamID}
+		if err != nil {
+			reply.Error = err.Error()
+		}
+		_ = fc.write(reply)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, frame.Method, strings.TrimSuffix(a.opts.APIServerURL, "/")+frame.URL, bytes.NewReader(frame.Body))
+	if err != nil {
+		end(fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	req.Header = forwardedHeader(frame.Header)
+
+	capability := RequiredCapability(req)
+	if !a.supports(capability) {
+		end(fmt.Errorf("%s requests are not supported by this agent", capability))
+		return
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		end(err)
+		return
+	}
+	defer resp.Body.Close()
+
+	err = fc.write(&Frame{
+		Type:       FrameTypeResponse,
+		StreamID:   frame.StreamID,
+		StatusCode: resp.StatusCode,
+		Header:     forwardedHeader(resp.Header),
+	})
+	if err != nil {
+		return
+	}
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			chunk := make([]byte, n)
+			copy(chunk, buf[:n])
+
+			if err := fc.write(&Frame{Type: FrameTypeData, StreamID: frame.StreamID, Body: chunk}); err != nil {
+				return
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			end(nil)
+			return
+		}
+		if err != nil {
+			end(err)
+			return
+		}
+	}
+}
+
+func (a *Agent) supports(capability Capability) bool {
+	for _, c := range a.opts.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,468 @@
+package tunnel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	// ErrAgentOffline is returned for requests to a cluster whose agent is not connected
+	ErrAgentOffline = errors.New("the tunnel agent of the cluster is not connected")
+	// ErrCapabilityNotSupported is returned for requests which the agent of the cluster cannot proxy
+	ErrCapabilityNotSupported = errors.New("the tunnel agent of the cluster does not support this operation")
+)
+
+const (
+	// defaultResponseTimeout is how long a request waits for the agent to start its response
+	defaultResponseTimeout = 60 * time.Second
+	// maxBufferedBody bounds the part of a response body received from an agent but not yet read by its client
+	maxBufferedBody = 8 * 1024 * 1024
+)
+
+// Status is the connection state of the agent of a cluster
+type Status struct {
+	Connected    bool
+	ConnectedAt  time.Time
+	Version      string
+	Capabilities []Capability
+}
+
+// HubOptions configure a Hub
+type HubOptions struct {
+	// ResponseTimeout is how long a request waits for the agent to start its response, defaulting to a minute
+	ResponseTimeout time.Duration
+}
+
+// Hub holds the connections of the agents connected to this server, and routes the requests for their clusters
+// through them. Agents are only connected to a single replica of the server, so requests for a cluster served by
+// another replica fail with ErrAgentOffline.
+type Hub struct {
+	opts HubOptions
+
+	mu       sync.RWMutex
+	sessions map[uint]*session
+}
+
+// NewHub returns a Hub without any connected agents
+func NewHub(opts HubOptions) *Hub {
+	if opts.ResponseTimeout == 0 {
+		opts.ResponseTimeout = defaultResponseTimeout
+	}
+
+	return &Hub{
+		opts:     opts,
+		sessions: make(map[uint]*session),
+	}
+}
+
+// Serve routes requests for a cluster through the connection of its agent until the connection is closed or the
+// context is done. A newer connection for the same cluster replaces the older one.
+func (h *Hub) Serve(ctx context.Context, clusterID uint, conn *websocket.Conn, registration Registration) error {
+	s := &session{
+		conn:         &frameConn{conn: conn},
+		registration: registration,
+		connectedAt:  time.Now().UTC(),
+		streams:      make(map[uint64]*stream),
+		done:         make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	previous := h.sessions[clusterID]
+	h.sessions[clusterID] = s
+	h.mu.Unlock()
+
+	if previous != nil {
+		previous.conn.conn.Close()
+	}
+
+	defer func() {
+		h.mu.Lock()
+		if h.sessions[clusterID] == s {
+			delete(h.sessions, clusterID)
+		}
+		h.mu.Unlock()
+
+		s.close()
+	}()
+
+	stopClosing := s.conn.closeWhenDone(ctx)
+	defer stopClosing()
+
+	// the pong handler runs on the reading goroutine, so it is set before reading starts
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	if err := conn.SetReadDeadline(time.Now().Add(pongWait)); err != nil {
+		return err
+	}
+
+	go s.ping()
+
+	return s.readLoop()
+}
+
+// Disconnect closes the connection of the agent of a cluster, if it is connected
+func (h *Hub) Disconnect(clusterID uint) {
+	h.mu.RLock()
+	s := h.sessions[clusterID]
+	h.mu.RUnlock()
+
+	if s != nil {
+		s.conn.conn.Close()
+	}
+}
+
+// Status returns the connection state of the agent of a cluster
+func (h *Hub) Status(clusterID uint) Status {
+	h.mu.RLock()
+	s := h.sessions[clusterID]
+	h.mu.RUnlock()
+
+	if s == nil {
+		return Status{}
+	}
+
+	return Status{
+		Connected:    true,
+		ConnectedAt:  s.connectedAt,
+		Version:      s.registration.Version,
+		Capabilities: s.registration.Capabilities,
+	}
+}
+
+// RESTConfig returns a config for the Kubernetes API of a cluster which sends every request through its agent. An
+// error wrapping ErrAgentOffline is returned if the agent is not connected.
+func (h *Hub) RESTConfig(clusterID uint) (*rest.Config, error) {
+	if _, err := h.session(clusterID); err != nil {
+		return nil, err
+	}
+
+	return &rest.Config{
+		// requests never leave the server over this host, it only names the cluster in the errors of the client
+		Host:      fmt.Sprintf("http://cluster-%d.tunnel", clusterID),
+		Transport: &transport{hub: h, clusterID: clusterID},
+	}, nil
+}
+
+func (h *Hub) session(clusterID uint) (*session, error) {
+	h.mu.RLock()
+	s := h.sessions[clusterID]
+	h.mu.RUnlock()
+
+	if s == nil {
+		return nil, fmt.Errorf("cluster %d: %w", clusterID, ErrAgentOffline)
+	}
+
+	return s, nil
+}
+
+// transport sends the requests of a cluster through its agent
+type transport struct {
+	hub       *Hub
+	clusterID uint
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s, err := t.hub.session(t.clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	capability := RequiredCapability(req)
+	if !s.registration.supports(capability) {
+		return nil, fmt.Errorf("cluster %d does not support %s requests: %w", t.clusterID, capability, ErrCapabilityNotSupported)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	id, st := s.open(req.Context())
+
+	err = s.conn.write(&Frame{
+		Type:     FrameTypeRequest,
+		StreamID: id,
+		Method:   req.Method,
+		URL:      req.URL.RequestURI(),
+		Header:   forwardedHeader(req.Header),
+		Body:     body,
+	})
+	if err != nil {
+		s.remove(id)
+		return nil, fmt.Errorf("error sending request to the tunnel agent of cluster %d: %w", t.clusterID, err)
+	}
+
+	timer := time.NewTimer(t.hub.opts.ResponseTimeout)
+	defer timer.Stop()
+
+	select {
+	case head := <-st.head:
+		if head.Error != "" {
+			s.remove(id)
+			return nil, fmt.Errorf("tunnel agent of cluster %d: %s", t.clusterID, head.Error)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", head.StatusCode, http.StatusText(head.StatusCode)),
+			StatusCode:    head.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        head.Header,
+			Body:          &streamBody{session: s, id: id, stream: st},
+			ContentLength: -1,
+			Request:       req,
+		}, nil
+	case <-s.done:
+		return nil, fmt.Errorf("cluster %d: %w", t.clusterID, ErrAgentOffline)
+	case <-req.Context().Done():
+		s.cancel(id)
+		return nil, req.Context().Err()
+	case <-timer.C:
+		s.cancel(id)
+		return nil, fmt.Errorf("timed out waiting for the tunnel agent of cluster %d to respond", t.clusterID)
+	}
+}
+
+// session is the connection of a single agent
+type session struct {
+	conn         *frameConn
+	registration Registration
+	connectedAt  time.Time
+
+	mu      sync.Mutex
+	nextID  uint64
+	streams map[uint64]*stream
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *session) open(ctx context.Context) (uint64, *stream) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	st := &stream{
+		ctx:    ctx,
+		head:   make(chan *Frame, 1),
+		notify: make(chan struct{}, 1),
+	}
+	s.streams[s.nextID] = st
+
+	return s.nextID, st
+}
+
+func (s *session) get(id uint64) *stream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.streams[id]
+}
+
+func (s *session) remove(id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.streams, id)
+}
+
+// cancel stops the agent proxying a request whose client has gone away
+func (s *session) cancel(id uint64) {
+	s.remove(id)
+	_ = s.conn.write(&Frame{Type: FrameTypeCancel, StreamID: id})
+}
+
+func (s *session) readLoop() error {
+	for {
+		frame, err := s.conn.read()
+		if err != nil {
+			return err
+		}
+
+		st := s.get(frame.StreamID)
+		if st == nil {
+			// the client of the stream has gone away
+			continue
+		}
+
+		switch frame.Type {
+		case FrameTypeResponse:
+			st.respond(frame)
+		case FrameTypeData:
+			if err := st.push(frame.Body); err != nil {
+				s.cancel(frame.StreamID)
+				st.finish(err)
+			}
+		case FrameTypeEnd:
+			s.remove(frame.StreamID)
+
+			if frame.Error == "" {
+				st.respond(&Frame{Error: "the agent ended the stream without responding"})
+				st.finish(io.EOF)
+				continue
+			}
+
+			st.respond(frame)
+			st.finish(errors.New(frame.Error))
+		}
+	}
+}
+
+func (s *session) ping() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.conn.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				s.conn.conn.Close()
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// close fails every open stream of the session
+func (s *session) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		streams := s.streams
+		s.streams = make(map[uint64]*stream)
+		s.mu.Unlock()
+
+		for _, st := range streams {
+			st.finish(ErrAgentOffline)
+		}
+
+		s.conn.conn.Close()
+	})
+}
+
+// stream is a single request proxied by an agent
+type stream struct {
+	ctx  context.Context
+	head chan *Frame
+
+	mu        sync.Mutex
+	responded bool
+	buf       bytes.Buffer
+	err       error
+	notify    chan struct{}
+}
+
+// respond delivers the head of the response, or an error if the request failed before the agent responded. Only
+// the first call has an effect.
+func (st *stream) respond(frame *Frame) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.responded {
+		return
+	}
+	st.responded = true
+	st.head <- frame
+}
+
+func (st *stream) push(data []byte) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.buf.Len()+len(data) > maxBufferedBody {
+		return fmt.Errorf("the client of the stream fell more than %d bytes behind the tunnel agent", maxBufferedBody)
+	}
+	st.buf.Write(data)
+	st.signal()
+
+	return nil
+}
+
+// finish ends the body of the stream with an error, which is io.EOF if the body is complete. Only the first call
+// has an effect.
+func (st *stream) finish(err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.err == nil {
+		st.err = err
+	}
+	st.signal()
+}
+
+func (st *stream) signal() {
+	select {
+	case st.notify <- struct{}{}:
+	default:
+	}
+}
+
+// streamBody is the body of a response streamed by an agent
+type streamBody struct {
+	session *session
+	id      uint64
+	stream  *stream
+
+	closeOnce sync.Once
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	st := b.stream
+
+	for {
+		st.mu.Lock()
+		if st.buf.Len() > 0 {
+			n, _ := st.buf.Read(p)
+			st.mu.Unlock()
+			return n, nil
+		}
+		if st.err != nil {
+			err := st.err
+			st.mu.Unlock()
+			return 0, err
+		}
+		st.mu.Unlock()
+
+		select {
+		case <-st.notify:
+		case <-st.ctx.Done():
+			return 0, st.ctx.Err()
+		}
+	}
+}
+
+func (b *streamBody) Close() error {
+	b.closeOnce.Do(func() {
+		b.stream.mu.Lock()
+		complete := b.stream.err != nil
+		b.stream.mu.Unlock()
+
+		if complete {
+			b.session.remove(b.id)
+			return
+		}
+
+		b.session.cancel(b.id)
+		b.stream.finish(errors.New("response body closed"))
+	})
+
+	return nil
+}
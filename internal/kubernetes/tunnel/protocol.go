@@ -0,0 +1,203 @@
+// Package tunnel connects the Porter server to clusters whose Kubernetes API it cannot reach directly. An agent
+// running inside such a cluster dials out to the server and keeps a websocket open, over which the server sends the
+// Kubernetes API requests of the cluster. The agent proxies each request to the API server it runs beside, and
+// streams the response back.
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// ConnectPath is the path of the server endpoint agents connect to
+	ConnectPath = "/api/cluster-tunnel/connect"
+
+	// HeaderProjectID is the header in which an agent sends the project of its cluster when it connects
+	HeaderProjectID = "X-Porter-Project-Id"
+	// HeaderClusterID is the header in which an agent sends the id of its cluster when it connects
+	HeaderClusterID = "X-Porter-Cluster-Id"
+)
+
+// Capability is a class of Kubernetes API requests an agent can proxy
+type Capability string
+
+const (
+	// CapabilityDeploy covers the requests which return a single response, which is all the deploy path needs:
+	// installing, upgrading and reading helm releases, and reading and writing namespaces and secrets
+	CapabilityDeploy Capability = "deploy"
+	// CapabilityStream covers requests whose responses are streamed, such as following pod logs and watches
+	CapabilityStream Capability = "stream"
+	// CapabilityExec covers requests which upgrade the connection: exec, attach and port forwarding
+	CapabilityExec Capability = "exec"
+)
+
+// RequiredCapability returns the capability an agent needs to proxy a request
+func RequiredCapability(req *http.Request) Capability {
+	if req.Header.Get("Upgrade") != "" {
+		return CapabilityExec
+	}
+
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	for _, suffix := range []string{"/exec", "/attach", "/portforward"} {
+		if strings.HasSuffix(path, suffix) {
+			return CapabilityExec
+		}
+	}
+
+	query := req.URL.Query()
+	if query.Get("watch") == "true" || query.Get("watch") == "1" || (strings.HasSuffix(path, "/log") && query.Get("follow") == "true") {
+		return CapabilityStream
+	}
+
+	return CapabilityDeploy
+}
+
+// FrameType is the kind of a message sent over the tunnel
+type FrameType string
+
+const (
+	// FrameTypeRegister is the first frame an agent sends, describing itself
+	FrameTypeRegister FrameType = "register"
+	// FrameTypeRequest is sent by the server to open a stream with a request for the agent to proxy
+	FrameTypeRequest FrameType = "request"
+	// FrameTypeResponse is sent by the agent with the status and headers of the response to a request
+	FrameTypeResponse FrameType = "response"
+	// FrameTypeData is sent by the agent with a chunk of the body of a response
+	FrameTypeData FrameType = "data"
+	// FrameTypeEnd is sent by the agent once a response is complete, or with an error if the request failed
+	FrameTypeEnd FrameType = "end"
+	// FrameTypeCancel is sent by the server when the client of a stream has gone away
+	FrameTypeCancel FrameType = "cancel"
+)
+
+// Frame is a message sent over the tunnel. Every frame but registration belongs to the stream of a single request.
+type Frame struct {
+	Type     FrameType `json:"type"`
+	StreamID uint64    `json:"stream_id,omitempty"`
+
+	// Method and URL are set on requests, where URL is the path and query of the request to the API server
+	Method string      `json:"method,omitempty"`
+	URL    string      `json:"url,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+
+	StatusCode int    `json:"status_code,omitempty"`
+	Body       []byte `json:"body,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	Registration *Registration `json:"registration,omitempty"`
+}
+
+// Registration describes an agent to the server
+type Registration struct {
+	Version      string       `json:"version"`
+	Capabilities []Capability `json:"capabilities"`
+}
+
+// ReadRegistration reads the registration an agent sends first after connecting
+func ReadRegistration(conn *websocket.Conn) (Registration, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return Registration{}, err
+	}
+
+	frame, err := (&frameConn{conn: conn}).read()
+	if err != nil {
+		return Registration{}, fmt.Errorf("error reading registration: %w", err)
+	}
+	if frame.Type != FrameTypeRegister || frame.Registration == nil {
+		return Registration{}, fmt.Errorf("expected a %s frame, got %s", FrameTypeRegister, frame.Type)
+	}
+
+	return *frame.Registration, nil
+}
+
+// supports returns true if the agent registered with the capability
+func (r Registration) supports(capability Capability) bool {
+	for _, c := range r.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	// writeTimeout bounds how long a frame may take to be written
+	writeTimeout = 10 * time.Second
+	// pingInterval is how often the server pings an agent to keep the connection alive and detect that it is gone
+	pingInterval = 30 * time.Second
+	// pongWait is how long either side waits to hear from the other before it considers the connection dead
+	pongWait = 3 * pingInterval
+	// chunkSize is the size of the body chunks an agent sends
+	chunkSize = 32 * 1024
+)
+
+// frameConn serializes the frames written to a websocket, which only supports one concurrent writer
+type frameConn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+func (c *frameConn) write(frame *Frame) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+
+	return c.conn.WriteJSON(frame)
+}
+
+func (c *frameConn) read() (*Frame, error) {
+	_, by, err := c.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	frame := &Frame{}
+	if err := json.Unmarshal(by, frame); err != nil {
+		return nil, err
+	}
+
+	return frame, nil
+}
+
+// closeWhenDone closes the connection once the context is done, so that a blocked read returns
+func (c *frameConn) closeWhenDone(ctx context.Context) func() {
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.conn.Close()
+		case <-done:
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// hopByHopHeaders only apply to a single connection, so they are not forwarded through the tunnel
+var hopByHopHeaders = []string{"Connection", "Keep-Alive", "Proxy-Connection", "Transfer-Encoding", "Upgrade", "Te", "Trailer"}
+
+func forwardedHeader(header http.Header) http.Header {
+	forwarded := header.Clone()
+	if forwarded == nil {
+		return http.Header{}
+	}
+
+	for _, h := range hopByHopHeaders {
+		forwarded.Del(h)
+	}
+
+	return forwarded
+}
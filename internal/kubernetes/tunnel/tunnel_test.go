@@ -0,0 +1,212 @@
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const testClusterID = 3
+
+// connectAgent serves a hub behind a test server and connects an agent to it, which proxies requests to the api
+func connectAgent(t *testing.T, api http.Handler, capabilities ...Capability) *Hub {
+	t.Helper()
+
+	hub := NewHub(HubOptions{ResponseTimeout: 5 * time.Second})
+	upgrader := &websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ConnectPath || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		registration, err := ReadRegistration(conn)
+		if err != nil {
+			return
+		}
+
+		_ = hub.Serve(r.Context(), testClusterID, conn, registration)
+	}))
+	t.Cleanup(server.Close)
+
+	apiServer := httptest.NewServer(api)
+	t.Cleanup(apiServer.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	agent := NewAgent(AgentOptions{
+		ServerURL:    server.URL,
+		ProjectID:    1,
+		ClusterID:    testClusterID,
+		Token:        "token",
+		Version:      "test",
+		APIServerURL: apiServer.URL,
+		Transport:    http.DefaultTransport,
+		Capabilities: capabilities,
+	})
+	go agent.Run(ctx) // nolint:errcheck
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !hub.Status(testClusterID).Connected {
+		if time.Now().After(deadline) {
+			t.Fatal("agent did not connect")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return hub
+}
+
+func tunnelClient(t *testing.T, hub *Hub) *http.Client {
+	t.Helper()
+
+	conf, err := hub.RESTConfig(testClusterID)
+	if err != nil {
+		t.Fatalf("unexpected error getting rest config: %v", err)
+	}
+
+	return &http.Client{Transport: conf.Transport}
+}
+
+func TestRESTConfigAgentOffline(t *testing.T) {
+	hub := NewHub(HubOptions{})
+
+	if _, err := hub.RESTConfig(testClusterID); !errors.Is(err, ErrAgentOffline) {
+		t.Fatalf("expected agent offline error, got %v", err)
+	}
+	if hub.Status(testClusterID).Connected {
+		t.Error("expected the agent to be disconnected")
+	}
+}
+
+func TestTunnelRoundTrip(t *testing.T) {
+	hub := connectAgent(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"method":%q,"path":%q,"body":%q}`, r.Method, r.URL.RequestURI(), body)
+	}))
+
+	status := hub.Status(testClusterID)
+	if status.Version != "test" || len(status.Capabilities) != 2 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	client := tunnelClient(t, hub)
+
+	resp, err := client.Post("http://cluster.tunnel/api/v1/namespaces/default/secrets?fieldManager=porter", "application/json", strings.NewReader("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected status 201, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected the response headers to be proxied, got %v", resp.Header)
+	}
+
+	expected := `{"method":"POST","path":"/api/v1/namespaces/default/secrets?fieldManager=porter","body":"secret"}`
+	if string(body) != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+}
+
+func TestTunnelStreamsResponses(t *testing.T) {
+	release := make(chan struct{})
+
+	hub := connectAgent(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+
+		fmt.Fprintln(w, "first line")
+		flusher.Flush()
+
+		<-release
+		fmt.Fprintln(w, "second line")
+	}))
+
+	client := tunnelClient(t, hub)
+
+	resp, err := client.Get("http://cluster.tunnel/api/v1/namespaces/default/pods/web/log?follow=true")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+
+	// the first line has to arrive before the api server finishes the response
+	line, err := reader.ReadString('\n')
+	if err != nil || line != "first line\n" {
+		t.Fatalf("expected the first line, got %q: %v", line, err)
+	}
+
+	close(release)
+
+	rest, err := io.ReadAll(reader)
+	if err != nil || string(rest) != "second line\n" {
+		t.Fatalf("expected the second line, got %q: %v", rest, err)
+	}
+}
+
+func TestTunnelRejectsUnsupportedCapabilities(t *testing.T) {
+	hub := connectAgent(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request to the api server")
+	}), CapabilityDeploy)
+
+	client := tunnelClient(t, hub)
+
+	tests := []string{
+		"http://cluster.tunnel/api/v1/namespaces/default/pods/web/exec?command=sh",
+		"http://cluster.tunnel/api/v1/namespaces/default/pods?watch=true",
+	}
+
+	for _, url := range tests {
+		_, err := client.Get(url)
+		if !errors.Is(err, ErrCapabilityNotSupported) {
+			t.Errorf("expected %s to be rejected, got %v", url, err)
+		}
+	}
+}
+
+func TestTunnelDisconnect(t *testing.T) {
+	hub := connectAgent(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	client := tunnelClient(t, hub)
+	hub.Disconnect(testClusterID)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := client.Get("http://cluster.tunnel/api/v1/namespaces")
+		if errors.Is(err, ErrAgentOffline) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected requests to fail once the agent is disconnected, got %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -64,10 +65,18 @@ func (j JSONB) Value() (driver.Value, error) {
 	return string(valueString), err
 }
 
-// Scan implements the sql.Scanner interface
+// Scan implements the sql.Scanner interface. Postgres returns jsonb columns as bytes, while sqlite returns them as
+// strings.
 func (j *JSONB) Scan(value interface{}) error {
-	if err := json.Unmarshal(value.([]byte), &j); err != nil {
-		return err
+	switch v := value.(type) {
+	case nil:
+		*j = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), j)
+	case []byte:
+		return json.Unmarshal(v, j)
+	default:
+		return fmt.Errorf("unsupported type %T for jsonb", value)
 	}
-	return nil
 }
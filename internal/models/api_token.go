@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/porter-dev/porter/api/types"
@@ -20,6 +21,10 @@ type APIToken struct {
 	PolicyName      string
 	Name            string
 
+	// Scopes is a comma-separated list of the scopes the token is limited to, or empty if the token is only limited
+	// by its policy
+	Scopes string
+
 	// SecretKey is hashed like a password before storage
 	SecretKey []byte
 }
@@ -29,6 +34,42 @@ func (p *APIToken) IsExpired() bool {
 	return timeLeft < 0
 }
 
+// ScopeList returns the scopes the token is limited to
+func (p *APIToken) ScopeList() []types.APITokenScope {
+	if p.Scopes == "" {
+		return nil
+	}
+
+	res := make([]types.APITokenScope, 0)
+	for _, scope := range strings.Split(p.Scopes, ",") {
+		res = append(res, types.APITokenScope(scope))
+	}
+
+	return res
+}
+
+// CanCallEndpoint returns true if the scopes of the token permit calling an endpoint. Tokens without scopes may call
+// any endpoint, subject to their policy.
+func (p *APIToken) CanCallEndpoint(endpointMeta types.APIRequestMetadata) bool {
+	scopes := p.ScopeList()
+	if len(scopes) == 0 {
+		return true
+	}
+
+	required := endpointMeta.RequiredTokenScope()
+	if required == "" {
+		return false
+	}
+
+	for _, scope := range scopes {
+		if scope == required {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (p *APIToken) ToAPITokenMetaType() *types.APITokenMeta {
 	return &types.APITokenMeta{
 		ID:         p.UniqueID,
@@ -37,6 +78,7 @@ func (p *APIToken) ToAPITokenMetaType() *types.APITokenMeta {
 		PolicyName: p.PolicyName,
 		PolicyUID:  p.PolicyUID,
 		Name:       p.Name,
+		Scopes:     p.ScopeList(),
 	}
 }
 
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// AuditLogEntry is an action taken on a project which its members can review. Entries are never updated.
+type AuditLogEntry struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"index"`
+
+	Action      string
+	ActorUserID uint
+	Description string
+
+	Metadata JSONB `json:"metadata" sql:"type:jsonb" gorm:"type:jsonb"`
+}
+
+// ToAuditLogEntryType converts the model to its API type
+func (e *AuditLogEntry) ToAuditLogEntryType() types.AuditLogEntry {
+	return types.AuditLogEntry{
+		ID:          e.ID,
+		ProjectID:   e.ProjectID,
+		Action:      types.AuditLogAction(e.Action),
+		ActorUserID: e.ActorUserID,
+		Description: e.Description,
+		Metadata:    e.Metadata,
+		CreatedAt:   e.CreatedAt,
+	}
+}
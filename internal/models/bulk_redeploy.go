@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// BulkRedeploy is a group of app redeploys created by a single request, which the bulk redeploy runner works through
+// a few apps at a time on each cluster
+type BulkRedeploy struct {
+	gorm.Model
+
+	// ID is the operation group ID returned to the client
+	ID uuid.UUID `gorm:"type:uuid;primaryKey"`
+
+	ProjectID uint `gorm:"index"`
+
+	// the filter which selected the apps, kept to show what was requested
+	ClusterID uint
+	Label     string
+	EnvGroup  string
+
+	// Concurrency is the number of apps of this group redeployed at once on each cluster
+	Concurrency  int
+	BumpChecksum bool
+
+	// CompletedAt is set once every operation has finished, so that the runner stops looking at the group
+	CompletedAt *time.Time `gorm:"index"`
+
+	Operations []BulkRedeployOperation `gorm:"foreignKey:BulkRedeployID"`
+}
+
+// BulkRedeployOperation is the redeploy of a single app in a bulk redeploy
+type BulkRedeployOperation struct {
+	gorm.Model
+
+	BulkRedeployID uuid.UUID `gorm:"type:uuid;index"`
+
+	ProjectID   uint
+	ClusterID   uint
+	PorterAppID uint
+	AppName     string
+
+	Status string
+	Error  string
+	// Revision is the helm revision created by the redeploy
+	Revision int
+
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// Finished returns true if the operation succeeded, failed or was skipped
+func (o *BulkRedeployOperation) Finished() bool {
+	switch types.BulkRedeployStatus(o.Status) {
+	case types.BulkRedeployStatus_Succeeded, types.BulkRedeployStatus_Failed, types.BulkRedeployStatus_Skipped:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToBulkRedeployType converts the model and its operations to the API type
+func (b *BulkRedeploy) ToBulkRedeployType() types.BulkRedeploy {
+	res := types.BulkRedeploy{
+		ID:        b.ID.String(),
+		ProjectID: b.ProjectID,
+		Filter: types.BulkRedeployFilter{
+			ClusterID: b.ClusterID,
+			Label:     b.Label,
+			EnvGroup:  b.EnvGroup,
+		},
+		Concurrency:  b.Concurrency,
+		BumpChecksum: b.BumpChecksum,
+		Status:       types.BulkRedeployStatus_Running,
+		Counts:       make(map[types.BulkRedeployStatus]int),
+		Apps:         make([]types.BulkRedeployApp, 0, len(b.Operations)),
+		CreatedAt:    b.CreatedAt,
+		CompletedAt:  b.CompletedAt,
+	}
+	if b.CompletedAt != nil {
+		res.Status = types.BulkRedeployStatus_Completed
+	}
+
+	for _, op := range b.Operations {
+		app := types.BulkRedeployApp{
+			PorterAppID: op.PorterAppID,
+			AppName:     op.AppName,
+			ClusterID:   op.ClusterID,
+			Status:      types.BulkRedeployStatus(op.Status),
+			Error:       op.Error,
+			Revision:    op.Revision,
+			StartedAt:   op.StartedAt,
+			FinishedAt:  op.FinishedAt,
+		}
+
+		res.Counts[app.Status]++
+		res.Apps = append(res.Apps, app)
+	}
+
+	return res
+}
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models/integrations"
@@ -23,6 +26,8 @@ const (
 	Azure     ClusterAuth = "azure-sp"
 	Local     ClusterAuth = "local"
 	InCluster ClusterAuth = "in-cluster"
+	// AgentTunnel clusters are reached through a tunnel agent running inside them, which dials out to the server
+	AgentTunnel ClusterAuth = "agent-tunnel"
 )
 
 // Cluster is an integration that can connect to a Kubernetes cluster via
@@ -79,6 +84,19 @@ type Cluster struct {
 	// For AWS EKS clusters, this will be an ARN for the final target role in the assume role chain.
 	CloudProviderCredentialIdentifier string `json:"cloud_provider_credential_identifier"`
 
+	// SchedulingDefaults are the node selector and tolerations applied to every Porter-managed workload on the cluster
+	SchedulingDefaults ClusterSchedulingDefaults `json:"scheduling_defaults" gorm:"type:jsonb"`
+
+	// NetworkPoliciesEnabled deploys the apps of the cluster with a NetworkPolicy for each service, generated from the
+	// ports and links of its services
+	NetworkPoliciesEnabled bool `gorm:"default:false"`
+
+	// Capabilities are the features detected on the cluster at connect and health-check time
+	Capabilities ClusterCapabilities `json:"capabilities" gorm:"type:jsonb"`
+
+	// TunnelTokenHash is the bcrypt hash of the token the tunnel agent of an AgentTunnel cluster authenticates with
+	TunnelTokenHash []byte `json:"-"`
+
 	// ------------------------------------------------------------------
 	// All fields below this line are encrypted before storage
 	// ------------------------------------------------------------------
@@ -132,9 +150,99 @@ func (c *Cluster) ToClusterType() *types.Cluster {
 		ProvisionedBy:                     c.ProvisionedBy,
 		CloudProvider:                     c.CloudProvider,
 		CloudProviderCredentialIdentifier: c.CloudProviderCredentialIdentifier,
+		SchedulingDefaults:                c.SchedulingDefaults.ToClusterSchedulingDefaultsType(),
+		NetworkPoliciesEnabled:            c.NetworkPoliciesEnabled,
+		Capabilities:                      c.Capabilities.ToClusterCapabilitiesType(),
+	}
+}
+
+// Provider returns the cloud provider which hosts the cluster: its CloudProvider if it was provisioned by porter,
+// otherwise the provider of the integration it is reached through, or OTHER for clusters connected by kubeconfig
+func (c *Cluster) Provider() string {
+	switch {
+	case c.CloudProvider != "":
+		return strings.ToUpper(c.CloudProvider)
+	case c.AWSIntegrationID != 0:
+		return "AWS"
+	case c.GCPIntegrationID != 0:
+		return "GCP"
+	case c.DOIntegrationID != 0:
+		return "DO"
+	case c.AzureIntegrationID != 0:
+		return "AZURE"
+	default:
+		return "OTHER"
 	}
 }
 
+// ClusterSchedulingDefaults is stored as json on the cluster
+type ClusterSchedulingDefaults types.ClusterSchedulingDefaults
+
+// Value implements the driver.Valuer interface
+func (d ClusterSchedulingDefaults) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(d)
+	return string(valueString), err
+}
+
+// Scan implements the sql.Scanner interface
+func (d *ClusterSchedulingDefaults) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = ClusterSchedulingDefaults{}
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	case []byte:
+		return json.Unmarshal(v, d)
+	default:
+		return fmt.Errorf("unsupported type %T for cluster scheduling defaults", value)
+	}
+}
+
+// ToClusterSchedulingDefaultsType generates an external types.ClusterSchedulingDefaults, or nil if no defaults are set
+func (d ClusterSchedulingDefaults) ToClusterSchedulingDefaultsType() *types.ClusterSchedulingDefaults {
+	if len(d.NodeSelector) == 0 && len(d.Tolerations) == 0 {
+		return nil
+	}
+
+	defaults := types.ClusterSchedulingDefaults(d)
+	return &defaults
+}
+
+// ClusterCapabilities is stored as json on the cluster
+type ClusterCapabilities types.ClusterCapabilities
+
+// Value implements the driver.Valuer interface
+func (c ClusterCapabilities) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(c)
+	return string(valueString), err
+}
+
+// Scan implements the sql.Scanner interface
+func (c *ClusterCapabilities) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = ClusterCapabilities{}
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case []byte:
+		return json.Unmarshal(v, c)
+	default:
+		return fmt.Errorf("unsupported type %T for cluster capabilities", value)
+	}
+}
+
+// ToClusterCapabilitiesType generates an external types.ClusterCapabilities, or nil if capabilities were never detected
+func (c ClusterCapabilities) ToClusterCapabilitiesType() *types.ClusterCapabilities {
+	if c.DetectedAt.IsZero() {
+		return nil
+	}
+
+	capabilities := types.ClusterCapabilities(c)
+	return &capabilities
+}
+
 // ClusterCandidate is a cluster integration that requires additional action
 // from the user to set up.
 type ClusterCandidate struct {
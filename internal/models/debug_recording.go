@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// DebugRecording is a window in which the API calls of a project are recorded to the object store. A recording is
+// active until it expires or is stopped, and a project has at most one active recording.
+type DebugRecording struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"index"`
+
+	StartedByUserID uint
+	Reason          string
+
+	ExpiresAt time.Time
+	StoppedAt *time.Time
+
+	// ExchangeCount is the number of exchanges written to the object store, which is capped by the server
+	ExchangeCount uint
+}
+
+// IsActive returns true if calls to the project should be recorded at the given time
+func (r *DebugRecording) IsActive(now time.Time) bool {
+	return r.StoppedAt == nil && now.Before(r.ExpiresAt)
+}
+
+// ToDebugRecordingType converts the model to its API type
+func (r *DebugRecording) ToDebugRecordingType(now time.Time) types.DebugRecording {
+	return types.DebugRecording{
+		ID:              r.ID,
+		ProjectID:       r.ProjectID,
+		StartedByUserID: r.StartedByUserID,
+		Reason:          r.Reason,
+		Active:          r.IsActive(now),
+		ExchangeCount:   r.ExchangeCount,
+		StartedAt:       r.CreatedAt,
+		ExpiresAt:       r.ExpiresAt,
+		StoppedAt:       r.StoppedAt,
+	}
+}
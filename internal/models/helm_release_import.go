@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// HelmReleaseImport records that a helm release deployed outside of Porter was adopted as a porter app
+type HelmReleaseImport struct {
+	gorm.Model
+
+	ProjectID   uint `gorm:"index"`
+	ClusterID   uint `gorm:"index"`
+	PorterAppID uint `gorm:"uniqueIndex"`
+
+	ReleaseName  string
+	Namespace    string
+	ChartName    string
+	ChartVersion string
+
+	// Mode is either an application chart import, which Porter manages like the apps it created, or an external chart
+	// import, which Porter only upgrades with new values
+	Mode string
+
+	// ImportedRevision is the helm revision of the release when it was imported
+	ImportedRevision int
+	// AdoptedRevision is the first helm revision deployed by Porter, or 0 if Porter has not upgraded the release yet
+	AdoptedRevision int
+	AdoptedAt       *time.Time
+}
+
+// ToHelmReleaseImportType converts the model to its API type
+func (i *HelmReleaseImport) ToHelmReleaseImportType() types.HelmReleaseImport {
+	return types.HelmReleaseImport{
+		ID:               i.ID,
+		ProjectID:        i.ProjectID,
+		ClusterID:        i.ClusterID,
+		PorterAppID:      i.PorterAppID,
+		ReleaseName:      i.ReleaseName,
+		Namespace:        i.Namespace,
+		ChartName:        i.ChartName,
+		ChartVersion:     i.ChartVersion,
+		Mode:             types.HelmReleaseImportMode(i.Mode),
+		ImportedRevision: i.ImportedRevision,
+		AdoptedRevision:  i.AdoptedRevision,
+		AdoptedAt:        i.AdoptedAt,
+		CreatedAt:        i.CreatedAt,
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// InactivityPolicy pauses and then deletes the apps of a project which have gone unused. A project has at most one.
+type InactivityPolicy struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"uniqueIndex"`
+
+	Enabled bool
+
+	// NamePattern is a glob matching the names of the apps the policy applies to, in addition to ephemeral apps
+	NamePattern string
+
+	IdleDays         uint
+	GraceDays        uint
+	MaxDailyRequests uint
+}
+
+// ToInactivityPolicyType converts the model to its API type
+func (p *InactivityPolicy) ToInactivityPolicyType() *types.InactivityPolicy {
+	return &types.InactivityPolicy{
+		ProjectID:        p.ProjectID,
+		Enabled:          p.Enabled,
+		NamePattern:      p.NamePattern,
+		IdleDays:         p.IdleDays,
+		GraceDays:        p.GraceDays,
+		MaxDailyRequests: p.MaxDailyRequests,
+		CreatedAt:        p.CreatedAt,
+		UpdatedAt:        p.UpdatedAt,
+	}
+}
@@ -0,0 +1,66 @@
+package integrations
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// SecretsProviderIntegration is the config Porter uses to read the secrets which env variables in porter.yaml
+// reference with valueFrom. A project has at most one per provider.
+type SecretsProviderIntegration struct {
+	gorm.Model
+
+	ProjectID uint                  `json:"project_id" gorm:"uniqueIndex:idx_secrets_provider_project_provider"`
+	Provider  types.SecretsProvider `json:"provider" gorm:"uniqueIndex:idx_secrets_provider_project_provider"`
+
+	// Vault settings, for the vault provider
+	VaultAddress    string                `json:"vault_address"`
+	VaultNamespace  string                `json:"vault_namespace"`
+	VaultMount      string                `json:"vault_mount"`
+	VaultAuthMethod types.VaultAuthMethod `json:"vault_auth_method"`
+
+	// AWS settings, for the aws_secrets_manager provider
+	AWSIntegrationID uint   `json:"aws_integration_id"`
+	AWSRegion        string `json:"aws_region"`
+
+	// ------------------------------------------------------------------
+	// All fields encrypted before storage.
+	// ------------------------------------------------------------------
+
+	// The Vault token, for the token auth method
+	VaultToken []byte `json:"vault_token"`
+
+	// The AppRole role ID, for the approle auth method
+	VaultRoleID []byte `json:"vault_role_id"`
+
+	// The AppRole secret ID, for the approle auth method
+	VaultSecretID []byte `json:"vault_secret_id"`
+}
+
+// ToSecretsProviderIntegrationType converts the integration to its API type, without its credentials
+func (s *SecretsProviderIntegration) ToSecretsProviderIntegrationType() *types.SecretsProviderIntegration {
+	res := &types.SecretsProviderIntegration{
+		ID:        s.ID,
+		ProjectID: s.ProjectID,
+		Provider:  s.Provider,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+
+	switch s.Provider {
+	case types.SecretsProvider_Vault:
+		res.Vault = &types.VaultSecretsProviderConfig{
+			Address:    s.VaultAddress,
+			Namespace:  s.VaultNamespace,
+			Mount:      s.VaultMount,
+			AuthMethod: s.VaultAuthMethod,
+		}
+	case types.SecretsProvider_AWSSecretsManager:
+		res.AWS = &types.AWSSecretsProviderConfig{
+			AWSIntegrationID: s.AWSIntegrationID,
+			Region:           s.AWSRegion,
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Lock is an advisory lock on a shared resource, such as a stack which is being deployed. A lock is held by the
+// holder whose token it was taken with until it is released or expires, so that a server which goes away while holding
+// a lock does not hold it forever.
+type Lock struct {
+	// Name identifies the locked resource
+	Name string `gorm:"primaryKey"`
+	// Token identifies the holder of the lock, so that only the holder can release it
+	Token string
+
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
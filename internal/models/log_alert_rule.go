@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// LogAlertRule is an alert on a porter app which fires when lines matching a pattern appear in the app's logs more
+// often than a threshold within a window
+type LogAlertRule struct {
+	gorm.Model
+
+	ProjectID   uint `gorm:"index"`
+	ClusterID   uint
+	PorterAppID uint `gorm:"index"`
+
+	// DeploymentTargetID and Namespace identify the app's logs which are evaluated by the rule
+	DeploymentTargetID uuid.UUID `gorm:"type:uuid;default:00000000-0000-0000-0000-000000000000"`
+	Namespace          string
+
+	Name string
+	// ServiceName limits the rule to a single service of the app. If empty, logs from every service are evaluated
+	ServiceName string
+
+	MatchType     string
+	Pattern       string
+	Threshold     uint
+	WindowMinutes uint
+
+	Channel            string
+	SlackIntegrationID uint
+	WebhookURL         string
+
+	Enabled bool
+
+	// LastFiredAt is used to stop a rule firing again until its window has passed
+	LastFiredAt *time.Time
+}
+
+// ToLogAlertRuleType converts the model to its API type
+func (r *LogAlertRule) ToLogAlertRuleType() types.LogAlertRule {
+	return types.LogAlertRule{
+		ID:                 r.ID,
+		ProjectID:          r.ProjectID,
+		ClusterID:          r.ClusterID,
+		PorterAppID:        r.PorterAppID,
+		DeploymentTargetID: r.DeploymentTargetID.String(),
+		Name:               r.Name,
+		ServiceName:        r.ServiceName,
+		MatchType:          types.LogAlertMatchType(r.MatchType),
+		Pattern:            r.Pattern,
+		Threshold:          r.Threshold,
+		WindowMinutes:      r.WindowMinutes,
+		Channel:            types.LogAlertChannel(r.Channel),
+		SlackIntegrationID: r.SlackIntegrationID,
+		WebhookURL:         r.WebhookURL,
+		Enabled:            r.Enabled,
+		LastFiredAt:        r.LastFiredAt,
+		CreatedAt:          r.CreatedAt,
+		UpdatedAt:          r.UpdatedAt,
+	}
+}
@@ -1,6 +1,12 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"github.com/porter-dev/porter/api/types"
@@ -13,14 +19,28 @@ type PorterApp struct {
 	ProjectID uint
 	ClusterID uint
 
+	// UUID identifies the app across renames
+	UUID uuid.UUID `gorm:"type:uuid;index"`
+
 	Name string
 
+	// PreviousName is the name the app was last renamed from. Requests for the previous name are served for the app
+	// until PreviousNameExpiresAt, unless another app has taken the name.
+	PreviousName          string `gorm:"index"`
+	PreviousNameExpiresAt *time.Time
+
+	// SourceType is whether the app is built by Porter or deploys an external image. It is empty for apps created
+	// before it was recorded, until they are backfilled; see SourceTypeOrInferred.
+	SourceType types.PorterAppSourceType
+
 	ImageRepoURI string
 
-	// Git repo information (optional)
-	GitRepoID uint
-	RepoName  string
-	GitBranch string
+	// Git repo information (optional). GitRepoID is the github app installation of the repo, and GitlabIntegrationID is
+	// the gitlab integration of the project for repos hosted on gitlab.
+	GitRepoID           uint
+	GitlabIntegrationID uint
+	RepoName            string
+	GitBranch           string
 
 	BuildContext   string
 	Builder        string
@@ -28,17 +48,71 @@ type PorterApp struct {
 	Dockerfile     string
 	PullRequestURL string
 
+	// DeploySummaryCommentsDisabled stops deploy summaries being commented on the app's pull request
+	DeploySummaryCommentsDisabled bool `gorm:"default:false"`
+
+	// Ephemeral flags a preview or experiment app, which the inactivity policy of the project applies to
+	Ephemeral bool `gorm:"default:false"`
+	// InactivityCleanupDisabled opts the app out of the inactivity policy of the project
+	InactivityCleanupDisabled bool `gorm:"default:false"`
+
+	// ScalingSchedules are the scaling schedules of the services of the app, from the porter.yaml it was last deployed
+	// with. It is NULL for apps without any.
+	ScalingSchedules PorterAppScalingSchedules `gorm:"type:jsonb"`
+	// ScalingSchedulesPaused suspends the scaling schedules of the app until they are resumed
+	ScalingSchedulesPaused bool `gorm:"default:false"`
+
+	// PausedAt is when the app was paused, or nil if it is running. A paused app has its deployments scaled to zero and
+	// its cron jobs suspended until it is resumed.
+	PausedAt *time.Time
+	// PausedWorkloads are the deployments and cron jobs the app was paused from, which are restored when it is resumed.
+	// It is NULL for apps which are running.
+	PausedWorkloads PorterAppPausedWorkloads `gorm:"type:jsonb"`
+
+	// EnvGroups are the env groups the app was last deployed with, which are synced into the namespace of the app and
+	// redeployed to it when they are updated. It is NULL for apps without any.
+	EnvGroups PorterAppEnvGroups `gorm:"type:jsonb"`
+
+	// ManualScales are the services of the app which were scaled by hand since it was last deployed. It is NULL for apps
+	// without any.
+	ManualScales PorterAppManualScales `gorm:"type:jsonb"`
+
+	// Tags group the app with other apps, such as by team or environment, and label the namespace of the app. It is NULL
+	// for apps without any.
+	Tags PorterAppTags `gorm:"type:jsonb"`
+
+	// DeployWebhookTokenHash is the sha256 hash of the token of the deploy webhook of the app, or empty if the app has no
+	// deploy webhook. The token itself is only shown when it is generated.
+	DeployWebhookTokenHash string `gorm:"index"`
+
+	// CertAlertChannel is where alerts about the certificates of the app's domains are sent. Empty sends them to the
+	// slack integrations of the project, like the slack channel.
+	CertAlertChannel string
+	// CertAlertWebhookURL is where alerts are posted for the webhook channel
+	CertAlertWebhookURL string
+
 	// Porter YAML
 	PorterYamlPath string
 }
 
+// BeforeSave sets the UUID of apps created before it was added, and of new apps
+func (a *PorterApp) BeforeSave(tx *gorm.DB) error {
+	if a.UUID == uuid.Nil {
+		a.UUID = uuid.New()
+	}
+
+	return nil
+}
+
 // ToPorterAppType generates an external types.PorterApp to be shared over REST
 func (a *PorterApp) ToPorterAppType() *types.PorterApp {
 	return &types.PorterApp{
 		ID:             a.ID,
+		UUID:           a.UUID.String(),
 		ProjectID:      a.ProjectID,
 		ClusterID:      a.ClusterID,
 		Name:           a.Name,
+		SourceType:     a.SourceTypeOrInferred(),
 		ImageRepoURI:   a.ImageRepoURI,
 		GitRepoID:      a.GitRepoID,
 		RepoName:       a.RepoName,
@@ -49,6 +123,16 @@ func (a *PorterApp) ToPorterAppType() *types.PorterApp {
 		Dockerfile:     a.Dockerfile,
 		PullRequestURL: a.PullRequestURL,
 		PorterYamlPath: a.PorterYamlPath,
+
+		GitlabIntegrationID:           a.GitlabIntegrationID,
+		DeploySummaryCommentsDisabled: a.DeploySummaryCommentsDisabled,
+		Ephemeral:                     a.Ephemeral,
+		InactivityCleanupDisabled:     a.InactivityCleanupDisabled,
+		Tags:                          a.Tags,
+		DeployWebhookEnabled:          a.DeployWebhookTokenHash != "",
+
+		Status:   a.status(),
+		PausedAt: a.PausedAt,
 	}
 }
 
@@ -58,9 +142,11 @@ func (a *PorterApp) ToPorterAppType() *types.PorterApp {
 func (a *PorterApp) ToPorterAppTypeWithRevision(revision int) *types.PorterApp {
 	return &types.PorterApp{
 		ID:                 a.ID,
+		UUID:               a.UUID.String(),
 		ProjectID:          a.ProjectID,
 		ClusterID:          a.ClusterID,
 		Name:               a.Name,
+		SourceType:         a.SourceTypeOrInferred(),
 		ImageRepoURI:       a.ImageRepoURI,
 		GitRepoID:          a.GitRepoID,
 		RepoName:           a.RepoName,
@@ -72,5 +158,257 @@ func (a *PorterApp) ToPorterAppTypeWithRevision(revision int) *types.PorterApp {
 		PullRequestURL:     a.PullRequestURL,
 		PorterYamlPath:     a.PorterYamlPath,
 		HelmRevisionNumber: revision,
+
+		GitlabIntegrationID:           a.GitlabIntegrationID,
+		DeploySummaryCommentsDisabled: a.DeploySummaryCommentsDisabled,
+		Ephemeral:                     a.Ephemeral,
+		InactivityCleanupDisabled:     a.InactivityCleanupDisabled,
+		Tags:                          a.Tags,
+		DeployWebhookEnabled:          a.DeployWebhookTokenHash != "",
+
+		Status:   a.status(),
+		PausedAt: a.PausedAt,
+	}
+}
+
+// SourceTypeOrInferred returns the source type of the app, or the source type inferred from its settings if it has none
+// recorded
+func (a *PorterApp) SourceTypeOrInferred() types.PorterAppSourceType {
+	if a.SourceType != "" {
+		return a.SourceType
+	}
+
+	return a.InferSourceType()
+}
+
+// InferSourceType infers the source type of the app from its settings. Apps with an image repository and no repository
+// or build settings deploy an external image, and every other app is built by Porter.
+func (a *PorterApp) InferSourceType() types.PorterAppSourceType {
+	hasBuildSettings := a.GitRepoID != 0 || a.GitlabIntegrationID != 0 || a.RepoName != "" || a.BuildContext != "" || a.Builder != "" || a.Buildpacks != "" || a.Dockerfile != ""
+	if a.ImageRepoURI != "" && !hasBuildSettings {
+		return types.PorterAppSourceType_Image
+	}
+
+	return types.PorterAppSourceType_Build
+}
+
+// status returns whether the app is running or paused
+func (a *PorterApp) status() types.PorterAppStatus {
+	if a.PausedAt != nil {
+		return types.PorterAppStatus_Paused
+	}
+	return types.PorterAppStatus_Running
+}
+
+// PorterAppManualScales are the services of an app which were scaled by hand by their name in porter.yaml, stored as
+// json on the app
+type PorterAppManualScales map[string]*ManualScale
+
+// ManualScale is a change of the replicas of a service outside of a deploy of its app
+type ManualScale struct {
+	// HelmName is the name of the chart of the service within the umbrella chart of the app, such as web-web
+	HelmName string `json:"helm_name"`
+	Replicas int    `json:"replicas"`
+	// Autoscaled is true if the minimum replicas of the autoscaler of the service were set, rather than its replicas
+	Autoscaled bool      `json:"autoscaled,omitempty"`
+	UserID     uint      `json:"user_id,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// Value implements the driver.Valuer interface. Apps without manual scales are stored as NULL.
+func (s PorterAppManualScales) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	valueString, err := json.Marshal(s)
+	return string(valueString), err
+}
+
+// Scan implements the sql.Scanner interface
+func (s *PorterAppManualScales) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	case []byte:
+		return json.Unmarshal(v, s)
+	default:
+		return fmt.Errorf("unsupported type %T for porter app manual scales", value)
+	}
+}
+
+// PorterAppEnvGroups are the names of the env groups linked to an app, stored as json on the app
+type PorterAppEnvGroups []string
+
+// Contains reports whether the env group is linked to the app
+func (g PorterAppEnvGroups) Contains(name string) bool {
+	for _, envGroup := range g {
+		if envGroup == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements the driver.Valuer interface. Apps without env groups are stored as NULL.
+func (g PorterAppEnvGroups) Value() (driver.Value, error) {
+	if len(g) == 0 {
+		return nil, nil
+	}
+
+	valueString, err := json.Marshal(g)
+	return string(valueString), err
+}
+
+// Scan implements the sql.Scanner interface
+func (g *PorterAppEnvGroups) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*g = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), g)
+	case []byte:
+		return json.Unmarshal(v, g)
+	default:
+		return fmt.Errorf("unsupported type %T for porter app env groups", value)
+	}
+}
+
+// PorterAppTags are the tags of an app, stored as json on the app
+type PorterAppTags []string
+
+// HasAll reports whether the app has every one of the tags
+func (t PorterAppTags) HasAll(tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, appTag := range t {
+			if appTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Value implements the driver.Valuer interface. Apps without tags are stored as NULL.
+func (t PorterAppTags) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+
+	valueString, err := json.Marshal(t)
+	return string(valueString), err
+}
+
+// Scan implements the sql.Scanner interface
+func (t *PorterAppTags) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	case []byte:
+		return json.Unmarshal(v, t)
+	default:
+		return fmt.Errorf("unsupported type %T for porter app tags", value)
+	}
+}
+
+// PorterAppPausedWorkloads are the workloads of a paused app, stored as json on the app
+type PorterAppPausedWorkloads struct {
+	// Deployments are the replicas each deployment of the app ran before it was scaled to zero, by deployment name
+	Deployments map[string]int32 `json:"deployments,omitempty"`
+	// CronJobs are the cron jobs of the app which were suspended. Cron jobs which were already suspended are left out,
+	// so that they stay suspended when the app is resumed.
+	CronJobs []string `json:"cron_jobs,omitempty"`
+}
+
+// Empty reports whether no workloads are recorded
+func (w PorterAppPausedWorkloads) Empty() bool {
+	return len(w.Deployments) == 0 && len(w.CronJobs) == 0
+}
+
+// Value implements the driver.Valuer interface. Apps without paused workloads are stored as NULL.
+func (w PorterAppPausedWorkloads) Value() (driver.Value, error) {
+	if w.Empty() {
+		return nil, nil
+	}
+
+	valueString, err := json.Marshal(w)
+	return string(valueString), err
+}
+
+// Scan implements the sql.Scanner interface
+func (w *PorterAppPausedWorkloads) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*w = PorterAppPausedWorkloads{}
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	case []byte:
+		return json.Unmarshal(v, w)
+	default:
+		return fmt.Errorf("unsupported type %T for porter app paused workloads", value)
+	}
+}
+
+// PorterAppScalingSchedules are the scheduled services of an app by their name in porter.yaml, stored as json on the app
+type PorterAppScalingSchedules map[string]*ScheduledService
+
+// ScheduledService is a service of an app with a scaling schedule, and the replicas the schedule last scaled it to
+type ScheduledService struct {
+	// HelmName is the name of the chart of the service within the umbrella chart of the app, such as web-web
+	HelmName string `json:"helm_name"`
+	// BaseReplicas are the replicas the service was deployed with, which it runs outside of the windows of its schedule
+	BaseReplicas int                          `json:"base_replicas"`
+	Schedule     types.ServiceScalingSchedule `json:"schedule"`
+	// Applied is the last scaling the schedule did, or nil if it has not scaled the service since the app was deployed
+	// or the schedule was resumed
+	Applied *AppliedScaling `json:"applied,omitempty"`
+	// LastError is why the schedule last failed to scale the service, if it has not succeeded since
+	LastError string `json:"last_error,omitempty"`
+}
+
+// AppliedScaling is a change of the replicas of a service by its scaling schedule
+type AppliedScaling struct {
+	Replicas int `json:"replicas"`
+	// Window is the window which was open, or empty for the base replicas
+	Window string    `json:"window,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Value implements the driver.Valuer interface. Apps without scheduled services are stored as NULL, so that the apps
+// with schedules can be listed without reading the json.
+func (s PorterAppScalingSchedules) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	valueString, err := json.Marshal(s)
+	return string(valueString), err
+}
+
+// Scan implements the sql.Scanner interface
+func (s *PorterAppScalingSchedules) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	case []byte:
+		return json.Unmarshal(v, s)
+	default:
+		return fmt.Errorf("unsupported type %T for porter app scaling schedules", value)
 	}
 }
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// PorterAppCleanup tracks the resources of a deleted app which are released asynchronously after the deletion, such
+// as the cloud load balancers of its LoadBalancer services and the DNS records of its porter managed domains. The
+// cleanup watcher checks its pending resources until they are released, or flags them as leaked at its deadline.
+type PorterAppCleanup struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	ProjectID uint `gorm:"index"`
+	ClusterID uint
+	AppName   string
+	Namespace string
+
+	Status string `gorm:"index"`
+	// Pending are the resources which have not been released yet
+	Pending PorterAppCleanupResources `gorm:"type:jsonb"`
+	// Deadline is when resources which are still pending are flagged as leaked
+	Deadline   time.Time
+	FinishedAt *time.Time
+}
+
+// PorterAppCleanupResources are the pending resources of a cleanup
+type PorterAppCleanupResources []types.PorterAppCleanupResource
+
+// Value implements the driver.Valuer interface
+func (r PorterAppCleanupResources) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return nil, nil
+	}
+
+	valueString, err := json.Marshal(r)
+	return string(valueString), err
+}
+
+// Scan implements the sql.Scanner interface
+func (r *PorterAppCleanupResources) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	case []byte:
+		return json.Unmarshal(v, r)
+	default:
+		return fmt.Errorf("unsupported type %T for porter app cleanup resources", value)
+	}
+}
+
+// ToPorterAppCleanupType converts the model to its API type
+func (c *PorterAppCleanup) ToPorterAppCleanupType() types.PorterAppCleanup {
+	pending := make([]types.PorterAppCleanupResource, 0, len(c.Pending))
+	pending = append(pending, c.Pending...)
+
+	return types.PorterAppCleanup{
+		ID:         c.ID,
+		ClusterID:  c.ClusterID,
+		AppName:    c.AppName,
+		Namespace:  c.Namespace,
+		Status:     types.PorterAppCleanupStatus(c.Status),
+		Pending:    pending,
+		Deadline:   c.Deadline,
+		CreatedAt:  c.CreatedAt,
+		FinishedAt: c.FinishedAt,
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// PorterAppDomain is a host served by the ingresses of an app, with the certificate which served it the last time it
+// was checked. Domains are discovered from the ingresses of the app by the certificate expiry checker, and removed once
+// the app stops serving them.
+type PorterAppDomain struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	ProjectID   uint `gorm:"index"`
+	ClusterID   uint
+	PorterAppID uint `gorm:"index"`
+	Hostname    string
+
+	CertStatus string
+	CertSource string
+	CertIssuer string
+	// CertExpiresAt is kept from the last successful check while the certificate cannot be read
+	CertExpiresAt *time.Time
+	CheckedAt     *time.Time
+	// CheckError is why the certificate could not be read in the last check
+	CheckError string
+
+	// AlertedThresholdDays is the lowest alert threshold an alert was sent for about the current certificate, or 0 once
+	// the alert for its expiry was sent. It is nil until the certificate crosses its first threshold, and reset when the
+	// certificate is replaced.
+	AlertedThresholdDays *uint
+}
+
+// ToDomainCertStatusType converts the model to its API type
+func (d *PorterAppDomain) ToDomainCertStatusType() types.DomainCertStatus {
+	return types.DomainCertStatus{
+		Hostname:  d.Hostname,
+		Status:    types.CertStatus(d.CertStatus),
+		Source:    types.CertSource(d.CertSource),
+		Issuer:    d.CertIssuer,
+		ExpiresAt: d.CertExpiresAt,
+		CheckedAt: d.CheckedAt,
+		Error:     d.CheckError,
+	}
+}
@@ -30,7 +30,9 @@ type PorterAppEvent struct {
 	AppInstanceID uuid.UUID `json:"app_instance_id" gorm:"type:uuid;index:idx_app_instance_deployment_target;default:00000000-0000-0000-0000-000000000000"`
 	// DeploymentTargetID is the ID of the deployment target that the event relates to
 	DeploymentTargetID uuid.UUID `json:"deployment_target_id" gorm:"type:uuid;index:idx_app_deployment_target;index:idx_app_instance_deployment_target;default:00000000-0000-0000-0000-000000000000"`
-	Metadata           JSONB     `json:"metadata" sql:"type:jsonb" gorm:"type:jsonb"`
+	// Message is the release notes of a deploy event, kept out of the metadata so that it can be searched
+	Message  string `json:"message,omitempty" gorm:"index:idx_porter_app_event_message"`
+	Metadata JSONB  `json:"metadata" sql:"type:jsonb" gorm:"type:jsonb"`
 }
 
 // TableName overrides the table name
@@ -51,6 +53,7 @@ func (p *PorterAppEvent) ToPorterAppEvent() types.PorterAppEvent {
 		UpdatedAt:          p.UpdatedAt,
 		PorterAppID:        p.PorterAppID,
 		DeploymentTargetID: p.DeploymentTargetID.String(),
+		Message:            p.Message,
 	}
 	if p.Metadata != nil {
 		ty.Metadata = p.Metadata
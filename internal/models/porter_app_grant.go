@@ -0,0 +1,72 @@
+package models
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// PorterAppGrant gives a user, or every member of a project with a role, access to the apps of the project whose
+// names match a pattern. Grants are only enforced in projects with ScopedAppAccess turned on.
+type PorterAppGrant struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"index"`
+
+	// AppPattern is the name of an app, or a pattern where * matches any part of a name. It is made of lowercase
+	// letters, digits, dashes and *, so it can be matched with LIKE once * is replaced with %.
+	AppPattern string
+
+	// Exactly one of UserID and Role is set
+	UserID uint
+	Role   string
+
+	Permission string
+
+	CreatedByUserID uint
+}
+
+// MatchesApp returns true if the pattern of the grant matches the name of an app
+func (g *PorterAppGrant) MatchesApp(appName string) bool {
+	parts := strings.Split(g.AppPattern, "*")
+	if len(parts) == 1 {
+		return g.AppPattern == appName
+	}
+
+	if !strings.HasPrefix(appName, parts[0]) {
+		return false
+	}
+	rest := appName[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+
+	return len(rest) >= len(last) && strings.HasSuffix(rest, last)
+}
+
+// Allows returns true if the grant permits the permission. Deploy grants also permit reading and deleting.
+func (g *PorterAppGrant) Allows(permission types.PorterAppGrantPermission) bool {
+	return g.Permission == string(types.PorterAppGrantPermission_Deploy) || g.Permission == string(permission)
+}
+
+// ToPorterAppGrantType converts the model to its API type
+func (g *PorterAppGrant) ToPorterAppGrantType() types.PorterAppGrant {
+	return types.PorterAppGrant{
+		ID:              g.ID,
+		ProjectID:       g.ProjectID,
+		AppPattern:      g.AppPattern,
+		UserID:          g.UserID,
+		Role:            g.Role,
+		Permission:      types.PorterAppGrantPermission(g.Permission),
+		CreatedByUserID: g.CreatedByUserID,
+		CreatedAt:       g.CreatedAt,
+	}
+}
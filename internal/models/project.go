@@ -1,7 +1,10 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -201,6 +204,18 @@ type Project struct {
 	EnableSandbox        bool `gorm:"default:false"`
 	EnableReprovision    bool `gorm:"default:false"`
 	AdvancedInfraEnabled bool `gorm:"default:false"`
+
+	// DeploySummaryCommentsDisabled stops deploy summaries being commented on the pull requests of the project's apps
+	DeploySummaryCommentsDisabled bool `gorm:"default:false"`
+	// DeploySummaryCommentsSkipClosedPRs stops deploy summary comments being updated once their pull request is merged or closed
+	DeploySummaryCommentsSkipClosedPRs bool `gorm:"default:false"`
+	// ObservabilityConfig is where the project's apps send their telemetry. It is empty if it has not been set.
+	ObservabilityConfig ProjectObservabilityConfig `gorm:"type:jsonb"`
+	// DefaultClusterID is the cluster the CLI uses when no cluster is configured. It is zero if it has not been set.
+	DefaultClusterID uint
+	// ScopedAppAccess requires developers to hold a PorterAppGrant for an app to deploy, roll back or delete it. Without
+	// it, developers can act on every app of the project.
+	ScopedAppAccess bool `gorm:"default:false"`
 }
 
 // GetFeatureFlag calls launchdarkly for the specified flag
@@ -299,9 +314,53 @@ func (p *Project) ToProjectType(launchDarklyClient *features.Client) types.Proje
 		ManagedDeploymentTargetsEnabled: p.GetFeatureFlag(ManagedDeploymentTargetsEnabled, launchDarklyClient),
 		AdvancedInfraEnabled:            p.GetFeatureFlag(AdvancedInfraEnabled, launchDarklyClient),
 		SandboxEnabled:                  p.EnableSandbox,
+		DefaultClusterID:                p.DefaultClusterID,
+		ScopedAppAccess:                 p.ScopedAppAccess,
+	}
+}
+
+// ToDeploySummaryCommentSettingsType generates an external types.DeploySummaryCommentSettings to be shared over REST
+func (p *Project) ToDeploySummaryCommentSettingsType() *types.DeploySummaryCommentSettings {
+	return &types.DeploySummaryCommentSettings{
+		Enabled:                !p.DeploySummaryCommentsDisabled,
+		SkipClosedPullRequests: p.DeploySummaryCommentsSkipClosedPRs,
+	}
+}
+
+// ProjectObservabilityConfig is stored as json on the project
+type ProjectObservabilityConfig types.ProjectObservabilityConfig
+
+// Value implements the driver.Valuer interface
+func (c ProjectObservabilityConfig) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(c)
+	return string(valueString), err
+}
+
+// Scan implements the sql.Scanner interface
+func (c *ProjectObservabilityConfig) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = ProjectObservabilityConfig{}
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case []byte:
+		return json.Unmarshal(v, c)
+	default:
+		return fmt.Errorf("unsupported type %T for project observability config", value)
 	}
 }
 
+// ToProjectObservabilityConfigType generates an external types.ProjectObservabilityConfig, or nil if no config is set
+func (c ProjectObservabilityConfig) ToProjectObservabilityConfigType() *types.ProjectObservabilityConfig {
+	if c.OTLPEndpoint == "" {
+		return nil
+	}
+
+	config := types.ProjectObservabilityConfig(c)
+	return &config
+}
+
 // ToProjectListType returns a "minified" version of a Project
 // suitable for api responses to GET /projects
 // TODO: update this in the future to use default values for all
@@ -337,6 +396,32 @@ func (p *Project) ToProjectListType() *types.ProjectList {
 	}
 }
 
+// ProjectSummary is a project along with a user's role in the project and aggregate
+// information about the project's apps, clusters and deploys
+type ProjectSummary struct {
+	Project *Project
+
+	// RoleKind is the kind of role the user has in the project
+	RoleKind types.RoleKind
+	// NumApps is the number of porter apps in the project
+	NumApps int64
+	// NumClusters is the number of clusters in the project
+	NumClusters int64
+	// LastActivityAt is the creation time of the latest deploy event in the project, nil if there are none
+	LastActivityAt *time.Time
+}
+
+// ToProjectSummaryType generates an external types.ProjectSummary to be shared over REST
+func (p *ProjectSummary) ToProjectSummaryType() *types.ProjectSummary {
+	return &types.ProjectSummary{
+		ProjectList:    p.Project.ToProjectListType(),
+		Role:           p.RoleKind,
+		NumApps:        p.NumApps,
+		NumClusters:    p.NumClusters,
+		LastActivityAt: p.LastActivityAt,
+	}
+}
+
 func getProjectContext(projectID uint, projectName string) ldcontext.Context {
 	projectIdentifier := fmt.Sprintf("project-%d", projectID)
 	launchDarklyName := fmt.Sprintf("%s: %s", projectIdentifier, projectName)
@@ -6,6 +6,16 @@ import (
 	"gorm.io/gorm"
 )
 
+// PWResetTokenPurpose is what a PWResetToken was created for
+type PWResetTokenPurpose string
+
+const (
+	// PWResetTokenPurpose_PasswordReset is the purpose of the token of a password reset email
+	PWResetTokenPurpose_PasswordReset PWResetTokenPurpose = "password_reset"
+	// PWResetTokenPurpose_EmailVerification is the purpose of the token of a verification email
+	PWResetTokenPurpose_EmailVerification PWResetTokenPurpose = "email_verification"
+)
+
 // PWResetToken type that extends gorm.Model
 type PWResetToken struct {
 	gorm.Model
@@ -14,6 +24,9 @@ type PWResetToken struct {
 	IsValid bool
 	Expiry  *time.Time
 
+	// Purpose is what the token was created for. Tokens created before it was recorded have none.
+	Purpose PWResetTokenPurpose
+
 	// Token is hashed like a password before storage
 	Token string
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"strings"
+	"time"
 
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models/integrations"
@@ -32,6 +33,16 @@ type Registry struct {
 	// For AWS EKS clusters, this will be an ARN for the final target role in the assume role chain.
 	CloudProviderCredentialIdentifier string `json:"cloud_provider_credential_identifier" gorm:"default:''"`
 
+	// CredentialStatus is the last known state of the credentials of the registry, recorded whenever they are used to
+	// generate a pull secret or checked by the credential checker. Empty until they are first used.
+	CredentialStatus string `json:"credential_status" gorm:"default:''"`
+
+	// CredentialError is the error the credentials last failed with
+	CredentialError string `json:"credential_error" gorm:"default:''"`
+
+	// CredentialCheckedAt is when the credentials were last used or checked
+	CredentialCheckedAt *time.Time `json:"credential_checked_at"`
+
 	// ------------------------------------------------------------------
 	// All fields below this line are encrypted before storage
 	// ------------------------------------------------------------------
@@ -86,3 +97,23 @@ func (r *Registry) ToRegistryType() *types.Registry {
 		BasicIntegrationID: r.BasicIntegrationID,
 	}
 }
+
+// ToRegistryCredentialStateType returns the credential status of the registry
+func (r *Registry) ToRegistryCredentialStateType() types.RegistryCredentialState {
+	reg := r.ToRegistryType()
+
+	status := types.RegistryCredentialStatus(r.CredentialStatus)
+	if status == "" {
+		status = types.RegistryCredentialStatus_Unknown
+	}
+
+	return types.RegistryCredentialState{
+		RegistryID: r.ID,
+		Name:       r.Name,
+		URL:        reg.URL,
+		Service:    reg.Service,
+		Status:     status,
+		LastError:  r.CredentialError,
+		CheckedAt:  r.CredentialCheckedAt,
+	}
+}
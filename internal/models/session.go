@@ -13,6 +13,9 @@ type Session struct {
 	Key string `gorm:"unique"`
 	// encrypted cookie
 	Data []byte
-	// Time the session will expire
+	// Time the session will expire, which is the earliest of the end of its maximum lifetime and the end of its idle
+	// timeout after it was last used
 	ExpiresAt time.Time
+	// Time an authenticated request last used the session
+	LastUsedAt time.Time
 }
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// UsageRollup is the usage of a porter app on a single day, aggregated from the app's events by the usage rollup job
+type UsageRollup struct {
+	gorm.Model
+
+	ProjectID   uint `gorm:"index;uniqueIndex:idx_usage_rollup_app_day"`
+	PorterAppID uint `gorm:"uniqueIndex:idx_usage_rollup_app_day"`
+	// AppName is copied from the porter app, so that the usage of deleted apps can still be reported
+	AppName string
+	// Day is midnight UTC of the day the usage was rolled up for
+	Day time.Time `gorm:"index;uniqueIndex:idx_usage_rollup_app_day"`
+
+	Deploys              int
+	DeployFailures       int
+	HelmOperationSeconds float64
+	Builds               int
+	BuildFailures        int
+	BuildSeconds         float64
+}
+
+// UsageRollupDay records that the usage of every app on a day was rolled up, including days without usage
+type UsageRollupDay struct {
+	gorm.Model
+
+	// Day is midnight UTC of the day that was rolled up
+	Day        time.Time `gorm:"uniqueIndex"`
+	RolledUpAt time.Time
+	// EventsPrunedAt is set once the raw events of the day were deleted by the event retention policy. The rollups of a
+	// pruned day are final, since they can no longer be recomputed
+	EventsPrunedAt *time.Time
+}
+
+// ToUsageType returns the usage counters of the rollup
+func (u *UsageRollup) ToUsageType() types.Usage {
+	return types.Usage{
+		Deploys:              u.Deploys,
+		DeployFailures:       u.DeployFailures,
+		HelmOperationSeconds: u.HelmOperationSeconds,
+		Builds:               u.Builds,
+		BuildFailures:        u.BuildFailures,
+		BuildSeconds:         u.BuildSeconds,
+	}
+}
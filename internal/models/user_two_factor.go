@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserTwoFactor is the TOTP second factor of the password logins of a user. It is created when the user enrolls an
+// authenticator app, and only applies to their logins once it is enabled with a code from that app.
+type UserTwoFactor struct {
+	gorm.Model
+
+	UserID uint `gorm:"uniqueIndex"`
+	// Secret is the TOTP secret shared with the user's authenticator app. It is encrypted in the database
+	Secret  []byte
+	Enabled bool
+	// BackupCodes are the hashes of the backup codes which have not been used yet
+	BackupCodes UserBackupCodes `gorm:"type:jsonb"`
+
+	// LastCounter is the TOTP counter of the last code which was accepted, so that a code cannot be used twice
+	LastCounter int64
+	// FailedAttempts is the number of codes rejected since the last one which was accepted, or since the user was last
+	// locked out
+	FailedAttempts uint
+	// LockedUntil is when codes are accepted again after too many were rejected
+	LockedUntil *time.Time
+}
+
+// UserBackupCodes are the hashes of the backup codes of a user, stored as json
+type UserBackupCodes []string
+
+// Use removes the backup code with the given hash, and reports whether the user had it
+func (c *UserBackupCodes) Use(hash string) bool {
+	for i, code := range *c {
+		if code == hash {
+			*c = append((*c)[:i:i], (*c)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements the driver.Valuer interface. Users without backup codes are stored as NULL.
+func (c UserBackupCodes) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return nil, nil
+	}
+
+	valueString, err := json.Marshal(c)
+	return string(valueString), err
+}
+
+// Scan implements the sql.Scanner interface
+func (c *UserBackupCodes) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case []byte:
+		return json.Unmarshal(v, c)
+	default:
+		return fmt.Errorf("unsupported type %T for user backup codes", value)
+	}
+}
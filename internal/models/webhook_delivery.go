@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// WebhookDelivery is a single attempt at delivering an event to an outbound webhook, such as the webhook channel of a
+// log alert rule. The attempts of a delivery share a chain ID, and every delivery of an event shares its event ID.
+type WebhookDelivery struct {
+	ID        uint      `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"index"`
+
+	ProjectID uint `gorm:"index"`
+	// LogAlertRuleID is the rule whose webhook the event was delivered to
+	LogAlertRuleID uint `gorm:"index"`
+
+	EventID string `gorm:"index"`
+	ChainID string
+	// Attempt is the 1-indexed number of the attempt within its chain
+	Attempt uint
+	// Replay is true for the attempts of a redelivery requested by a user
+	Replay bool
+
+	TargetURL      string
+	RequestHeaders JSONB `sql:"type:jsonb" gorm:"type:jsonb"`
+	// BodySHA256 is the hex encoded hash of Payload
+	BodySHA256 string
+	// Payload is the body which was sent, kept so that the event can be redelivered with the same body
+	Payload []byte
+
+	// ResponseCode is 0 if no response was received, in which case Error says why
+	ResponseCode int
+	Error        string
+	LatencyMS    int64
+	Succeeded    bool
+}
+
+// ToWebhookDeliveryType converts the model to its API type
+func (d *WebhookDelivery) ToWebhookDeliveryType() types.WebhookDelivery {
+	headers := make(map[string]string, len(d.RequestHeaders))
+	for key, value := range d.RequestHeaders {
+		if s, ok := value.(string); ok {
+			headers[key] = s
+		}
+	}
+
+	return types.WebhookDelivery{
+		ID:             d.ID,
+		LogAlertRuleID: d.LogAlertRuleID,
+		EventID:        d.EventID,
+		ChainID:        d.ChainID,
+		Attempt:        d.Attempt,
+		Replay:         d.Replay,
+		TargetURL:      d.TargetURL,
+		RequestHeaders: headers,
+		BodySHA256:     d.BodySHA256,
+		ResponseCode:   d.ResponseCode,
+		Error:          d.Error,
+		LatencyMS:      d.LatencyMS,
+		Succeeded:      d.Succeeded,
+		CreatedAt:      d.CreatedAt,
+	}
+}
@@ -42,4 +42,7 @@ type NotifyOpts struct {
 	Timestamp *time.Time
 
 	Version int
+
+	// Message is the release notes of the deployment, if any
+	Message string
 }
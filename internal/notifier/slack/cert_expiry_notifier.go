@@ -0,0 +1,94 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+// CertExpiryNotifier sends alerts about expiring certificates of app domains to Slack
+type CertExpiryNotifier struct {
+	slackInts []*integrations.SlackIntegration
+}
+
+// NewCertExpiryNotifier returns a CertExpiryNotifier which posts to each of the given slack integrations
+func NewCertExpiryNotifier(slackInts ...*integrations.SlackIntegration) *CertExpiryNotifier {
+	return &CertExpiryNotifier{
+		slackInts: slackInts,
+	}
+}
+
+// Notify posts that the certificate of a domain is expiring or expired, with a link to the app's activity feed at url
+func (s *CertExpiryNotifier) Notify(ctx context.Context, event types.PorterAppCertExpiryEventMetadata, url string) error {
+	var topSectionMarkdwn string
+	switch event.Status {
+	case types.CertStatus_Expired:
+		topSectionMarkdwn = fmt.Sprintf(
+			":rotating_light: The certificate for %s of application %s has expired. <%s|View the activity feed.>",
+			"`"+event.Hostname+"`",
+			"`"+event.AppName+"`",
+			url,
+		)
+	case types.CertStatus_Expiring:
+		topSectionMarkdwn = fmt.Sprintf(
+			":warning: The certificate for %s of application %s expires in less than %d days. <%s|View the activity feed.>",
+			"`"+event.Hostname+"`",
+			"`"+event.AppName+"`",
+			event.ThresholdDays,
+			url,
+		)
+	default:
+		return fmt.Errorf("unexpected certificate status %s", event.Status)
+	}
+
+	res := []*SlackBlock{
+		getMarkdownBlock(topSectionMarkdwn),
+		getDividerBlock(),
+		getMarkdownBlock(fmt.Sprintf(
+			"*Expires:* <!date^%d^ {date_num} {time_secs}| %s>",
+			event.ExpiresAt.Unix(),
+			event.ExpiresAt.Format("2006-01-02 15:04:05 UTC"),
+		)),
+	}
+
+	if event.Issuer != "" {
+		res = append(res, getMarkdownBlock(fmt.Sprintf("*Issuer:* %s", event.Issuer)))
+	}
+
+	payload, err := json.Marshal(&SlackPayload{
+		Blocks: res,
+	})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: time.Second * 5,
+	}
+
+	for _, slackInt := range s.slackInts {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, string(slackInt.Webhook), bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("slack webhook for integration %d returned status %d", slackInt.ID, resp.StatusCode)
+		}
+	}
+
+	return nil
+}
@@ -36,6 +36,10 @@ func getSlackBlocks(opts *notifier.NotifyOpts) ([]*SlackBlock, []*SlackBlock) {
 		res = append(res, getMarkdownBlock(fmt.Sprintf("*Version:* %d", opts.Version)))
 	}
 
+	if opts.Message != "" {
+		res = append(res, getMarkdownBlock(fmt.Sprintf("*Release notes:* %s", "`"+escapeMrkdwn(opts.Message)+"`")))
+	}
+
 	basicRes := res
 
 	infoBlock := getInfoBlock(opts)
@@ -140,3 +144,17 @@ func getFailedInfoMessage(opts *notifier.NotifyOpts) string {
 
 	return fmt.Sprintf("```\n%s\n```", info)
 }
+
+// mrkdwnEscaper escapes text written by users so that it cannot mention users or channels, link to other pages or end
+// the inline code it is shown in
+var mrkdwnEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"`", "'",
+	"\n", " ",
+)
+
+func escapeMrkdwn(text string) string {
+	return mrkdwnEscaper.Replace(text)
+}
@@ -0,0 +1,99 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+// InactivityNotifier sends the steps the inactivity policy takes on idle apps to Slack
+type InactivityNotifier struct {
+	slackInts []*integrations.SlackIntegration
+}
+
+// NewInactivityNotifier returns an InactivityNotifier which posts to each of the given slack integrations
+func NewInactivityNotifier(slackInts ...*integrations.SlackIntegration) *InactivityNotifier {
+	return &InactivityNotifier{
+		slackInts: slackInts,
+	}
+}
+
+// Notify posts that an app was paused or deleted, with a link to the app's activity feed at url
+func (s *InactivityNotifier) Notify(ctx context.Context, event types.PorterAppInactivityEventMetadata, url string) error {
+	var topSectionMarkdwn string
+	switch event.Action {
+	case types.InactivityAction_Paused:
+		topSectionMarkdwn = fmt.Sprintf(
+			":double_vertical_bar: Application %s was paused because it has not been deployed for %d days. <%s|View the activity feed.>",
+			"`"+event.AppName+"`",
+			event.IdleDays,
+			url,
+		)
+	case types.InactivityAction_Deleted:
+		topSectionMarkdwn = fmt.Sprintf(
+			":wastebasket: Application %s was deleted because it stayed paused without being deployed.",
+			"`"+event.AppName+"`",
+		)
+	default:
+		return fmt.Errorf("unknown inactivity action %s", event.Action)
+	}
+
+	res := []*SlackBlock{
+		getMarkdownBlock(topSectionMarkdwn),
+		getDividerBlock(),
+		getMarkdownBlock(fmt.Sprintf(
+			"*Last deployed:* <!date^%d^ {date_num} {time_secs}| %s>",
+			event.LastDeployedAt.Unix(),
+			event.LastDeployedAt.Format("2006-01-02 15:04:05 UTC"),
+		)),
+	}
+
+	if event.DailyRequests != nil {
+		res = append(res, getMarkdownBlock(fmt.Sprintf("*Requests in the last day:* %.0f", *event.DailyRequests)))
+	}
+
+	if event.DeleteAt != nil {
+		res = append(res, getMarkdownBlock(fmt.Sprintf(
+			"*Deleted on:* <!date^%d^ {date_num} {time_secs}| %s> unless it is deployed again, or opted out of the inactivity policy",
+			event.DeleteAt.Unix(),
+			event.DeleteAt.Format("2006-01-02 15:04:05 UTC"),
+		)))
+	}
+
+	payload, err := json.Marshal(&SlackPayload{
+		Blocks: res,
+	})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: time.Second * 5,
+	}
+
+	for _, slackInt := range s.slackInts {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, string(slackInt.Webhook), bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("slack webhook for integration %d returned status %d", slackInt.ID, resp.StatusCode)
+		}
+	}
+
+	return nil
+}
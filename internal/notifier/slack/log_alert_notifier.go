@@ -0,0 +1,97 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+// LogAlertNotifier sends fired log alerts to Slack
+type LogAlertNotifier struct {
+	slackInts []*integrations.SlackIntegration
+}
+
+// NewLogAlertNotifier returns a LogAlertNotifier which posts to each of the given slack integrations
+func NewLogAlertNotifier(slackInts ...*integrations.SlackIntegration) *LogAlertNotifier {
+	return &LogAlertNotifier{
+		slackInts: slackInts,
+	}
+}
+
+// Notify posts the alert, with a snippet of the matching lines and a link to the app's activity feed at url
+func (s *LogAlertNotifier) Notify(ctx context.Context, alert types.PorterAppAlertEventMetadata, url string) error {
+	service := ""
+	if alert.ServiceName != "" {
+		service = fmt.Sprintf(" service %s of", "`"+alert.ServiceName+"`")
+	}
+
+	topSectionMarkdwn := fmt.Sprintf(
+		":rotating_light: Log alert *%s* fired for%s application %s. <%s|View the activity feed.>",
+		alert.RuleName,
+		service,
+		"`"+alert.AppName+"`",
+		url,
+	)
+
+	res := []*SlackBlock{
+		getMarkdownBlock(topSectionMarkdwn),
+		getDividerBlock(),
+		getMarkdownBlock(fmt.Sprintf(
+			"*Matched:* %d lines matching %s %s in the last %d minutes, over the threshold of %d",
+			alert.MatchCount,
+			alert.MatchType,
+			"`"+alert.Pattern+"`",
+			alert.WindowMinutes,
+			alert.Threshold,
+		)),
+		getMarkdownBlock(fmt.Sprintf(
+			"*Fired at:* <!date^%d^ {date_num} {time_secs}| %s>",
+			alert.FiredAt.Unix(),
+			alert.FiredAt.Format("2006-01-02 15:04:05 UTC"),
+		)),
+	}
+
+	if len(alert.Snippet) != 0 {
+		// a code fence in a log line would end the snippet's code block early
+		snippet := strings.ReplaceAll(strings.Join(alert.Snippet, "\n"), "```", "'''")
+		res = append(res, getMarkdownBlock(fmt.Sprintf("```\n%s\n```", snippet)))
+	}
+
+	payload, err := json.Marshal(&SlackPayload{
+		Blocks: res,
+	})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: time.Second * 5,
+	}
+
+	for _, slackInt := range s.slackInts {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, string(slackInt.Webhook), bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("slack webhook for integration %d returned status %d", slackInt.ID, resp.StatusCode)
+		}
+	}
+
+	return nil
+}
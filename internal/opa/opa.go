@@ -284,7 +284,7 @@ func (runner *KubernetesOPARunner) runPodQueries(name string, collection Kuberne
 
 	lsel := strings.Join(lselArr, ",")
 
-	pods, err := runner.k8sAgent.GetPodsByLabel(lsel, collection.Match.Namespace)
+	pods, err := runner.k8sAgent.GetPodsByLabel(context.Background(), lsel, collection.Match.Namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,124 @@
+// Package bulkredeploy redeploys every app of a project which matches a filter, a few apps at a time on each cluster,
+// such as after a shared env group changes
+package bulkredeploy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Matcher checks the helm values of apps against the label and env group of a filter. The cluster of the filter is
+// checked by the caller, which lists the apps of each cluster.
+type Matcher struct {
+	selector labels.Selector
+	envGroup string
+}
+
+// NewMatcher returns a Matcher for the filter, or an error if its label selector is invalid
+func NewMatcher(filter types.BulkRedeployFilter) (*Matcher, error) {
+	m := &Matcher{envGroup: filter.EnvGroup}
+
+	if filter.Label != "" {
+		selector, err := labels.Parse(filter.Label)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", filter.Label, err)
+		}
+		m.selector = selector
+	}
+
+	return m, nil
+}
+
+// NeedsValues returns true if matching requires the values of an app's release
+func (m *Matcher) NeedsValues() bool {
+	return m.selector != nil || m.envGroup != ""
+}
+
+// Matches returns true if the values of an app's release match the filter. Services are the top level values with a
+// container, so a label selector and an env group may match different services of the same app.
+func (m *Matcher) Matches(values map[string]interface{}) bool {
+	labelMatched := m.selector == nil
+	envGroupMatched := m.envGroup == ""
+
+	for _, service := range services(values) {
+		serviceLabels := stringMap(service["labels"])
+
+		if !labelMatched && m.selector.Matches(labels.Set(serviceLabels)) {
+			labelMatched = true
+		}
+		if !envGroupMatched && usesEnvGroup(service, serviceLabels, m.envGroup) {
+			envGroupMatched = true
+		}
+	}
+
+	return labelMatched && envGroupMatched
+}
+
+// services returns the values of each service of an app
+func services(values map[string]interface{}) []map[string]interface{} {
+	var res []map[string]interface{}
+
+	for key, value := range values {
+		if key == "global" {
+			continue
+		}
+
+		service, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := service["container"]; !ok {
+			continue
+		}
+
+		res = append(res, service)
+	}
+
+	return res
+}
+
+// usesEnvGroup returns true if a service is linked to the env group, or syncs its variables
+func usesEnvGroup(service map[string]interface{}, serviceLabels map[string]string, envGroup string) bool {
+	for _, linked := range strings.Split(serviceLabels[environment_groups.LabelKey_LinkedEnvironmentGroup], ".") {
+		if linked == envGroup {
+			return true
+		}
+	}
+
+	container, _ := service["container"].(map[string]interface{})
+	env, _ := container["env"].(map[string]interface{})
+	synced, _ := env["synced"].([]interface{})
+
+	for _, group := range synced {
+		groupMap, _ := group.(map[string]interface{})
+		if name, _ := groupMap["name"].(string); name == envGroup {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stringMap returns the string values of a labels map, which may be decoded as either map type
+func stringMap(value interface{}) map[string]string {
+	res := make(map[string]string)
+
+	switch typed := value.(type) {
+	case map[string]string:
+		for k, v := range typed {
+			res[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range typed {
+			if s, ok := v.(string); ok {
+				res[k] = s
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,79 @@
+package bulkredeploy
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// releaseValues returns the values of an app with a web service and a worker service, as decoded from a helm release
+func releaseValues() map[string]interface{} {
+	return map[string]interface{}{
+		"global": map[string]interface{}{
+			"image": map[string]interface{}{"repository": "nginx", "tag": "latest"},
+		},
+		"web-web": map[string]interface{}{
+			"labels": map[string]interface{}{
+				"porter.run/linked-environment-group": "shared-db.stripe",
+				"tier":                                "frontend",
+			},
+			"container": map[string]interface{}{
+				"env": map[string]interface{}{"normal": map[string]interface{}{}, "synced": []interface{}{}},
+			},
+		},
+		"worker-wkr": map[string]interface{}{
+			"labels": map[string]string{"tier": "backend"},
+			"container": map[string]interface{}{
+				"env": map[string]interface{}{
+					"synced": []interface{}{
+						map[string]interface{}{"name": "sendgrid", "version": 3},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   types.BulkRedeployFilter
+		values   map[string]interface{}
+		want     bool
+		needsVal bool
+	}{
+		{name: "no filter", values: releaseValues(), want: true},
+		{name: "linked env group", filter: types.BulkRedeployFilter{EnvGroup: "shared-db"}, values: releaseValues(), want: true, needsVal: true},
+		{name: "second linked env group", filter: types.BulkRedeployFilter{EnvGroup: "stripe"}, values: releaseValues(), want: true, needsVal: true},
+		{name: "synced env group", filter: types.BulkRedeployFilter{EnvGroup: "sendgrid"}, values: releaseValues(), want: true, needsVal: true},
+		{name: "env group prefix is not a match", filter: types.BulkRedeployFilter{EnvGroup: "shared"}, values: releaseValues(), want: false, needsVal: true},
+		{name: "label", filter: types.BulkRedeployFilter{Label: "tier=backend"}, values: releaseValues(), want: true, needsVal: true},
+		{name: "label set selector", filter: types.BulkRedeployFilter{Label: "tier in (frontend,edge)"}, values: releaseValues(), want: true, needsVal: true},
+		{name: "label not matched", filter: types.BulkRedeployFilter{Label: "tier=batch"}, values: releaseValues(), want: false, needsVal: true},
+		{name: "label and env group on different services", filter: types.BulkRedeployFilter{Label: "tier=backend", EnvGroup: "shared-db"}, values: releaseValues(), want: true, needsVal: true},
+		{name: "label matched but env group not", filter: types.BulkRedeployFilter{Label: "tier=backend", EnvGroup: "redis"}, values: releaseValues(), want: false, needsVal: true},
+		{name: "global values are not a service", filter: types.BulkRedeployFilter{Label: "!tier"}, values: map[string]interface{}{"global": map[string]interface{}{"container": map[string]interface{}{}}}, want: false, needsVal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMatcher(tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := m.Matches(tt.values); got != tt.want {
+				t.Errorf("got match %t, want %t", got, tt.want)
+			}
+			if got := m.NeedsValues(); got != tt.needsVal {
+				t.Errorf("got needs values %t, want %t", got, tt.needsVal)
+			}
+		})
+	}
+}
+
+func TestNewMatcher_InvalidLabel(t *testing.T) {
+	if _, err := NewMatcher(types.BulkRedeployFilter{Label: "tier in (a"}); err == nil {
+		t.Fatalf("expected an error for an invalid label selector")
+	}
+}
@@ -0,0 +1,99 @@
+package bulkredeploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// maxClustersResolved is the number of clusters whose releases are read at once when resolving a filter
+const maxClustersResolved = 5
+
+// Resolve returns the apps which match the filter of the matcher, with a pending status if they can be redeployed. Apps
+// without a helm release are skipped, and the apps on a cluster which cannot be reached are failed, since whether they
+// match is unknown. Apps without a release can only match a filter on the cluster, so they are left out if the filter
+// has a label or env group.
+func Resolve(ctx context.Context, source ReleaseSource, projectID uint, apps []*models.PorterApp, matcher *Matcher) []types.BulkRedeployApp {
+	clusters := make(map[uint][]*models.PorterApp)
+	for _, app := range apps {
+		clusters[app.ClusterID] = append(clusters[app.ClusterID], app)
+	}
+
+	var mu sync.Mutex
+	var res []types.BulkRedeployApp
+	sem := make(chan struct{}, maxClustersResolved)
+	var wg sync.WaitGroup
+
+	for clusterID, clusterApps := range clusters {
+		wg.Add(1)
+		go func(clusterID uint, clusterApps []*models.PorterApp) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			resolved := resolveCluster(ctx, source, projectID, clusterID, clusterApps, matcher)
+
+			mu.Lock()
+			res = append(res, resolved...)
+			mu.Unlock()
+		}(clusterID, clusterApps)
+	}
+
+	wg.Wait()
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].ClusterID != res[j].ClusterID {
+			return res[i].ClusterID < res[j].ClusterID
+		}
+		return res[i].AppName < res[j].AppName
+	})
+
+	return res
+}
+
+func resolveCluster(ctx context.Context, source ReleaseSource, projectID, clusterID uint, apps []*models.PorterApp, matcher *Matcher) []types.BulkRedeployApp {
+	resolved := func(app *models.PorterApp, status types.BulkRedeployStatus, err error) types.BulkRedeployApp {
+		res := types.BulkRedeployApp{
+			PorterAppID: app.ID,
+			AppName:     app.Name,
+			ClusterID:   clusterID,
+			Status:      status,
+		}
+		if err != nil {
+			res.Error = err.Error()
+		}
+		return res
+	}
+
+	var res []types.BulkRedeployApp
+
+	releases, err := source.Connect(ctx, projectID, clusterID)
+	if err != nil {
+		for _, app := range apps {
+			res = append(res, resolved(app, types.BulkRedeployStatus_Failed, fmt.Errorf("error connecting to cluster: %w", err)))
+		}
+		return res
+	}
+
+	for _, app := range apps {
+		values, err := releases.Values(ctx, app.Name)
+		switch {
+		case errors.Is(err, ErrNoRelease):
+			if !matcher.NeedsValues() {
+				res = append(res, resolved(app, types.BulkRedeployStatus_Skipped, err))
+			}
+		case err != nil:
+			res = append(res, resolved(app, types.BulkRedeployStatus_Failed, fmt.Errorf("error reading helm release: %w", err)))
+		case matcher.Matches(values):
+			res = append(res, resolved(app, types.BulkRedeployStatus_Pending, nil))
+		}
+	}
+
+	return res
+}
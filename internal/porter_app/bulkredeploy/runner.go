@@ -0,0 +1,256 @@
+package bulkredeploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+// ErrNoRelease is returned for an app without a helm release, which a bulk redeploy skips
+var ErrNoRelease = errors.New("app has no helm release to redeploy, apps deployed from app revisions are redeployed with porter apply")
+
+// Store lists the bulk redeploys to work through, and records the progress of their operations
+type Store interface {
+	ListIncompleteBulkRedeploys(ctx context.Context) ([]*models.BulkRedeploy, error)
+	ClaimBulkRedeployOperation(ctx context.Context, operation *models.BulkRedeployOperation, concurrency int, startedAt time.Time) (bool, error)
+	UpdateBulkRedeployOperation(ctx context.Context, operation *models.BulkRedeployOperation) (*models.BulkRedeployOperation, error)
+	CompleteBulkRedeploy(ctx context.Context, id uuid.UUID, completedAt time.Time) error
+}
+
+// ReleaseSource connects to the clusters that apps run on to read and upgrade their helm releases
+type ReleaseSource interface {
+	// Connect returns the releases of the apps on a cluster, or an error if the cluster is unreachable
+	Connect(ctx context.Context, projectID, clusterID uint) (ClusterReleases, error)
+}
+
+// ClusterReleases reads and upgrades the helm releases of apps on a single cluster
+type ClusterReleases interface {
+	// Values returns the values of the latest release of an app, or ErrNoRelease if the app has no release
+	Values(ctx context.Context, appName string) (map[string]interface{}, error)
+	// Redeploy upgrades the latest release of an app with its current values, returning the new revision. If
+	// bumpChecksum is set, the pods of the app are restarted even if nothing in the release changed.
+	Redeploy(ctx context.Context, appName string, bumpChecksum bool) (int, error)
+}
+
+// Options configure how often bulk redeploys are checked for operations to start, and how long an operation may take.
+// Zero values use the defaults.
+type Options struct {
+	// Interval is the time between checks for pending operations. Defaults to 5s
+	Interval time.Duration
+	// OperationTimeout bounds the time spent redeploying a single app. An operation which has been running for twice
+	// as long was interrupted, such as by a restart of the server running it, and is marked as failed. Defaults to 10m
+	OperationTimeout time.Duration
+	// Logger receives a record of started and failed operations. Optional
+	Logger *logger.Logger
+}
+
+// Runner starts the pending operations of bulk redeploys as slots free up on their clusters, and completes the bulk
+// redeploys whose operations have all finished
+type Runner struct {
+	store   Store
+	source  ReleaseSource
+	opts    Options
+	log     worker.Logger
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	running map[uint]bool
+}
+
+// NewRunner returns a Runner with the given options
+func NewRunner(store Store, source ReleaseSource, opts Options) *Runner {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.OperationTimeout <= 0 {
+		opts.OperationTimeout = 10 * time.Minute
+	}
+
+	return &Runner{
+		store:   store,
+		source:  source,
+		opts:    opts,
+		log:     worker.NewLogger(opts.Logger),
+		running: make(map[uint]bool),
+	}
+}
+
+// Run checks for operations to start once per interval until ctx is cancelled, then waits for the operations it started
+// to return
+func (r *Runner) Run(ctx context.Context) error {
+	defer r.wg.Wait()
+
+	return worker.Run(ctx, r.opts.Interval, r.log, "error processing bulk redeploys", r.process)
+}
+
+// process starts the pending operations which fit in the concurrency of their bulk redeploy, fails the operations which
+// were interrupted, and completes the bulk redeploys which have finished
+func (r *Runner) process(ctx context.Context, now time.Time) error {
+	bulkRedeploys, err := r.store.ListIncompleteBulkRedeploys(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing incomplete bulk redeploys: %w", err)
+	}
+
+	for _, bulkRedeploy := range bulkRedeploys {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		r.processBulkRedeploy(ctx, bulkRedeploy, now)
+	}
+
+	return nil
+}
+
+func (r *Runner) processBulkRedeploy(ctx context.Context, bulkRedeploy *models.BulkRedeploy, now time.Time) {
+	concurrency := bulkRedeploy.Concurrency
+	if concurrency <= 0 {
+		concurrency = types.DefaultBulkRedeployConcurrency
+	}
+
+	running := make(map[uint]int)
+	finished := true
+
+	for i := range bulkRedeploy.Operations {
+		op := &bulkRedeploy.Operations[i]
+
+		if types.BulkRedeployStatus(op.Status) == types.BulkRedeployStatus_Running && r.interrupted(op, now) {
+			r.finish(op, 0, errors.New("redeploy was interrupted before it finished"))
+		}
+
+		switch types.BulkRedeployStatus(op.Status) {
+		case types.BulkRedeployStatus_Running:
+			running[op.ClusterID]++
+			finished = false
+		case types.BulkRedeployStatus_Pending:
+			finished = false
+		}
+	}
+
+	for i := range bulkRedeploy.Operations {
+		op := &bulkRedeploy.Operations[i]
+
+		if types.BulkRedeployStatus(op.Status) != types.BulkRedeployStatus_Pending || running[op.ClusterID] >= concurrency {
+			continue
+		}
+
+		claimed, err := r.store.ClaimBulkRedeployOperation(ctx, op, concurrency, now)
+		if err != nil {
+			r.logOperation(zerolog.ErrorLevel, op).Err(err).Msg("error claiming bulk redeploy operation")
+			continue
+		}
+		if !claimed {
+			// another replica started the operation, or filled the cluster's slots first
+			continue
+		}
+
+		running[op.ClusterID]++
+		r.start(ctx, op, bulkRedeploy.BumpChecksum)
+	}
+
+	if finished {
+		if err := r.store.CompleteBulkRedeploy(ctx, bulkRedeploy.ID, now); err != nil {
+			r.log.WithLevel(zerolog.ErrorLevel).Err(err).Str("bulk_redeploy_id", bulkRedeploy.ID.String()).Msg("error completing bulk redeploy")
+		}
+	}
+}
+
+// interrupted returns true if a running operation is not running in this runner and has been running for longer than
+// any runner would let it
+func (r *Runner) interrupted(op *models.BulkRedeployOperation, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running[op.ID] {
+		return false
+	}
+
+	return op.StartedAt == nil || now.Sub(*op.StartedAt) > 2*r.opts.OperationTimeout
+}
+
+// start redeploys the app of a claimed operation in the background
+func (r *Runner) start(ctx context.Context, op *models.BulkRedeployOperation, bumpChecksum bool) {
+	r.mu.Lock()
+	r.running[op.ID] = true
+	r.mu.Unlock()
+
+	r.logOperation(zerolog.InfoLevel, op).Msg("redeploying app")
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() {
+			r.mu.Lock()
+			delete(r.running, op.ID)
+			r.mu.Unlock()
+		}()
+
+		revision, err := r.redeploy(ctx, op, bumpChecksum)
+		r.finish(op, revision, err)
+	}()
+}
+
+func (r *Runner) redeploy(ctx context.Context, op *models.BulkRedeployOperation, bumpChecksum bool) (revision int, err error) {
+	// this runs outside of the supervised goroutine, so a panic must be recovered here to not crash the server
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.logOperation(zerolog.ErrorLevel, op).Str("stack", string(debug.Stack())).Msgf("panic redeploying app: %v", rec)
+			err = fmt.Errorf("unexpected error redeploying app: %v", rec)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, r.opts.OperationTimeout)
+	defer cancel()
+
+	releases, err := r.source.Connect(ctx, op.ProjectID, op.ClusterID)
+	if err != nil {
+		return 0, fmt.Errorf("error connecting to cluster: %w", err)
+	}
+
+	return releases.Redeploy(ctx, op.AppName, bumpChecksum)
+}
+
+// finish records the result of an operation. The update is not bound to the runner's context, so that an operation
+// interrupted by a shutdown is still recorded as failed.
+func (r *Runner) finish(op *models.BulkRedeployOperation, revision int, err error) {
+	now := time.Now().UTC()
+	op.FinishedAt = &now
+	// a redeploy which upgraded the release but failed to restart the pods still records the new revision
+	op.Revision = revision
+
+	switch {
+	case errors.Is(err, ErrNoRelease):
+		op.Status = string(types.BulkRedeployStatus_Skipped)
+		op.Error = err.Error()
+	case err != nil:
+		op.Status = string(types.BulkRedeployStatus_Failed)
+		op.Error = err.Error()
+		r.logOperation(zerolog.WarnLevel, op).Err(err).Msg("error redeploying app")
+	default:
+		op.Status = string(types.BulkRedeployStatus_Succeeded)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := r.store.UpdateBulkRedeployOperation(ctx, op); err != nil {
+		r.logOperation(zerolog.ErrorLevel, op).Err(err).Msg("error recording result of bulk redeploy operation")
+	}
+}
+
+func (r *Runner) logOperation(level zerolog.Level, op *models.BulkRedeployOperation) *zerolog.Event {
+	return r.log.WithLevel(level).
+		Str("bulk_redeploy_id", op.BulkRedeployID.String()).
+		Uint("project_id", op.ProjectID).
+		Uint("cluster_id", op.ClusterID).
+		Str("app_name", op.AppName)
+}
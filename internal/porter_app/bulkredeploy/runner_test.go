@@ -0,0 +1,409 @@
+package bulkredeploy
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker/workertest"
+	"gorm.io/gorm"
+)
+
+type fakeStore struct {
+	mu            sync.Mutex
+	bulkRedeploys []*models.BulkRedeploy
+	completed     map[uuid.UUID]bool
+}
+
+func (s *fakeStore) ListIncompleteBulkRedeploys(ctx context.Context) ([]*models.BulkRedeploy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*models.BulkRedeploy
+	for _, bulkRedeploy := range s.bulkRedeploys {
+		if s.completed[bulkRedeploy.ID] {
+			continue
+		}
+
+		// copies the operations, like reading them from the database would
+		clone := *bulkRedeploy
+		clone.Operations = append([]models.BulkRedeployOperation(nil), bulkRedeploy.Operations...)
+		res = append(res, &clone)
+	}
+
+	return res, nil
+}
+
+func (s *fakeStore) ClaimBulkRedeployOperation(ctx context.Context, operation *models.BulkRedeployOperation, concurrency int, startedAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.operation(operation.ID)
+
+	running := 0
+	for _, bulkRedeploy := range s.bulkRedeploys {
+		for _, op := range bulkRedeploy.Operations {
+			if op.BulkRedeployID == stored.BulkRedeployID && op.ClusterID == stored.ClusterID && op.Status == string(types.BulkRedeployStatus_Running) {
+				running++
+			}
+		}
+	}
+	if running >= concurrency || stored.Status != string(types.BulkRedeployStatus_Pending) {
+		return false, nil
+	}
+
+	stored.Status = string(types.BulkRedeployStatus_Running)
+	stored.StartedAt = &startedAt
+	operation.Status = stored.Status
+	operation.StartedAt = stored.StartedAt
+
+	return true, nil
+}
+
+func (s *fakeStore) UpdateBulkRedeployOperation(ctx context.Context, operation *models.BulkRedeployOperation) (*models.BulkRedeployOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.operation(operation.ID)
+	*stored = *operation
+
+	return operation, nil
+}
+
+func (s *fakeStore) CompleteBulkRedeploy(ctx context.Context, id uuid.UUID, completedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.completed[id] = true
+	return nil
+}
+
+// operation returns the stored operation with the id, which must be called with the lock held
+func (s *fakeStore) operation(id uint) *models.BulkRedeployOperation {
+	for _, bulkRedeploy := range s.bulkRedeploys {
+		for i := range bulkRedeploy.Operations {
+			if bulkRedeploy.Operations[i].ID == id {
+				return &bulkRedeploy.Operations[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *fakeStore) statuses() map[string]types.BulkRedeployStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make(map[string]types.BulkRedeployStatus)
+	for _, bulkRedeploy := range s.bulkRedeploys {
+		for _, op := range bulkRedeploy.Operations {
+			res[op.AppName] = types.BulkRedeployStatus(op.Status)
+		}
+	}
+
+	return res
+}
+
+// fakeCluster serves the releases of a single cluster, blocking redeploys until release is closed if it is set
+type fakeCluster struct {
+	workertest.Cluster
+	values  map[string]map[string]interface{}
+	failing map[string]bool
+	release chan struct{}
+}
+
+type fakeSource struct {
+	mu          sync.Mutex
+	clusters    map[uint]*fakeCluster
+	active      map[uint]int
+	maxActive   map[uint]int
+	redeployed  []string
+	bumpedCount int
+}
+
+func newFakeSource(clusters map[uint]*fakeCluster) *fakeSource {
+	return &fakeSource{clusters: clusters, active: make(map[uint]int), maxActive: make(map[uint]int)}
+}
+
+func (s *fakeSource) Connect(ctx context.Context, projectID, clusterID uint) (ClusterReleases, error) {
+	cluster, err := workertest.Connect(s.clusters, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeReleases{source: s, clusterID: clusterID, cluster: cluster}, nil
+}
+
+type fakeReleases struct {
+	source    *fakeSource
+	clusterID uint
+	cluster   *fakeCluster
+}
+
+func (r *fakeReleases) Values(ctx context.Context, appName string) (map[string]interface{}, error) {
+	values, ok := r.cluster.values[appName]
+	if !ok {
+		return nil, ErrNoRelease
+	}
+
+	return values, nil
+}
+
+func (r *fakeReleases) Redeploy(ctx context.Context, appName string, bumpChecksum bool) (int, error) {
+	r.source.mu.Lock()
+	r.source.active[r.clusterID]++
+	if r.source.active[r.clusterID] > r.source.maxActive[r.clusterID] {
+		r.source.maxActive[r.clusterID] = r.source.active[r.clusterID]
+	}
+	r.source.mu.Unlock()
+
+	defer func() {
+		r.source.mu.Lock()
+		r.source.active[r.clusterID]--
+		r.source.redeployed = append(r.source.redeployed, appName)
+		if bumpChecksum {
+			r.source.bumpedCount++
+		}
+		r.source.mu.Unlock()
+	}()
+
+	if r.cluster.release != nil {
+		select {
+		case <-r.cluster.release:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	if r.cluster.failing[appName] {
+		return 0, errors.New("upgrade failed")
+	}
+	if _, ok := r.cluster.values[appName]; !ok {
+		return 0, ErrNoRelease
+	}
+
+	return 7, nil
+}
+
+func newBulkRedeploy(concurrency int, apps map[string]uint) *models.BulkRedeploy {
+	bulkRedeploy := &models.BulkRedeploy{
+		ID:           uuid.New(),
+		ProjectID:    1,
+		Concurrency:  concurrency,
+		BumpChecksum: true,
+	}
+
+	id := uint(1)
+	for _, name := range sortedNames(apps) {
+		bulkRedeploy.Operations = append(bulkRedeploy.Operations, models.BulkRedeployOperation{
+			Model:          gorm.Model{ID: id},
+			BulkRedeployID: bulkRedeploy.ID,
+			ProjectID:      1,
+			ClusterID:      apps[name],
+			AppName:        name,
+			Status:         string(types.BulkRedeployStatus_Pending),
+		})
+		id++
+	}
+
+	return bulkRedeploy
+}
+
+func sortedNames(apps map[string]uint) []string {
+	names := make([]string, 0, len(apps))
+	for name := range apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func TestRunner_ConcurrencyPerCluster(t *testing.T) {
+	release := make(chan struct{})
+	values := map[string]map[string]interface{}{"a": {}, "b": {}, "c": {}, "d": {}, "e": {}, "f": {}}
+	source := newFakeSource(map[uint]*fakeCluster{
+		1: {values: values, release: release},
+		2: {values: values, release: release},
+	})
+	bulkRedeploy := newBulkRedeploy(2, map[string]uint{"a": 1, "b": 1, "c": 1, "d": 2, "e": 2, "f": 1})
+	store := &fakeStore{bulkRedeploys: []*models.BulkRedeploy{bulkRedeploy}, completed: make(map[uuid.UUID]bool)}
+
+	r := NewRunner(store, source, Options{})
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	if err := r.process(ctx, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	statuses := store.statuses()
+	running := map[uint]int{}
+	for _, op := range bulkRedeploy.Operations {
+		if statuses[op.AppName] == types.BulkRedeployStatus_Running {
+			running[op.ClusterID]++
+		}
+	}
+	if running[1] != 2 || running[2] != 2 {
+		t.Fatalf("expected 2 running operations on each cluster, got %v", running)
+	}
+
+	// a second pass while the first operations are still running starts nothing more
+	if err := r.process(ctx, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	close(release)
+	r.wg.Wait()
+
+	for i := 0; i < 3; i++ {
+		if err := r.process(ctx, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		r.wg.Wait()
+	}
+
+	for name, status := range store.statuses() {
+		if status != types.BulkRedeployStatus_Succeeded {
+			t.Errorf("expected %s to succeed, got %s", name, status)
+		}
+	}
+	if source.maxActive[1] > 2 || source.maxActive[2] > 2 {
+		t.Errorf("expected at most 2 concurrent redeploys per cluster, got %v", source.maxActive)
+	}
+	if len(source.redeployed) != 6 || source.bumpedCount != 6 {
+		t.Errorf("expected every app to be redeployed once with a checksum bump, got %v and %d bumps", source.redeployed, source.bumpedCount)
+	}
+	if !store.completed[bulkRedeploy.ID] {
+		t.Errorf("expected the bulk redeploy to be completed")
+	}
+}
+
+func TestRunner_FailuresDoNotStopOtherApps(t *testing.T) {
+	source := newFakeSource(map[uint]*fakeCluster{
+		1: {values: map[string]map[string]interface{}{"ok": {}, "broken": {}}, failing: map[string]bool{"broken": true}},
+		2: {Cluster: workertest.Cluster{Unreachable: true}},
+		3: {values: map[string]map[string]interface{}{}},
+	})
+	bulkRedeploy := newBulkRedeploy(1, map[string]uint{"ok": 1, "broken": 1, "offline": 2, "v2-app": 3})
+	store := &fakeStore{bulkRedeploys: []*models.BulkRedeploy{bulkRedeploy}, completed: make(map[uuid.UUID]bool)}
+
+	r := NewRunner(store, source, Options{})
+	for i := 0; i < 3; i++ {
+		if err := r.process(context.Background(), time.Now().UTC()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		r.wg.Wait()
+	}
+
+	want := map[string]types.BulkRedeployStatus{
+		"ok":      types.BulkRedeployStatus_Succeeded,
+		"broken":  types.BulkRedeployStatus_Failed,
+		"offline": types.BulkRedeployStatus_Failed,
+		"v2-app":  types.BulkRedeployStatus_Skipped,
+	}
+	got := store.statuses()
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("expected %s to be %s, got %s", name, status, got[name])
+		}
+	}
+
+	for _, op := range store.bulkRedeploys[0].Operations {
+		if op.AppName == "ok" && op.Revision != 7 {
+			t.Errorf("expected the new revision to be recorded, got %d", op.Revision)
+		}
+		if op.Status != string(types.BulkRedeployStatus_Succeeded) && op.Error == "" {
+			t.Errorf("expected %s to record why it did not succeed", op.AppName)
+		}
+		if op.FinishedAt == nil {
+			t.Errorf("expected %s to record when it finished", op.AppName)
+		}
+	}
+	if !store.completed[bulkRedeploy.ID] {
+		t.Errorf("expected the bulk redeploy to be completed")
+	}
+}
+
+func TestRunner_InterruptedOperation(t *testing.T) {
+	source := newFakeSource(map[uint]*fakeCluster{1: {values: map[string]map[string]interface{}{"a": {}, "b": {}}}})
+	bulkRedeploy := newBulkRedeploy(1, map[string]uint{"a": 1, "b": 1})
+
+	// a was claimed by a server which restarted before it finished
+	startedAt := time.Now().UTC().Add(-time.Hour)
+	bulkRedeploy.Operations[0].Status = string(types.BulkRedeployStatus_Running)
+	bulkRedeploy.Operations[0].StartedAt = &startedAt
+
+	store := &fakeStore{bulkRedeploys: []*models.BulkRedeploy{bulkRedeploy}, completed: make(map[uuid.UUID]bool)}
+
+	r := NewRunner(store, source, Options{OperationTimeout: 10 * time.Minute})
+	if err := r.process(context.Background(), time.Now().UTC()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r.wg.Wait()
+
+	got := store.statuses()
+	if got["a"] != types.BulkRedeployStatus_Failed {
+		t.Errorf("expected the interrupted operation to fail, got %s", got["a"])
+	}
+	if got["b"] != types.BulkRedeployStatus_Succeeded {
+		t.Errorf("expected the slot of the interrupted operation to be reused, got %s", got["b"])
+	}
+}
+
+func TestResolve(t *testing.T) {
+	source := newFakeSource(map[uint]*fakeCluster{
+		1: {values: map[string]map[string]interface{}{"api": releaseValues(), "docs": {}}},
+		2: {Cluster: workertest.Cluster{Unreachable: true}},
+	})
+	apps := []*models.PorterApp{
+		{Model: gorm.Model{ID: 1}, ClusterID: 1, Name: "docs"},
+		{Model: gorm.Model{ID: 2}, ClusterID: 1, Name: "api"},
+		{Model: gorm.Model{ID: 3}, ClusterID: 1, Name: "v2-app"},
+		{Model: gorm.Model{ID: 4}, ClusterID: 2, Name: "offline"},
+	}
+
+	t.Run("env group", func(t *testing.T) {
+		m, _ := NewMatcher(types.BulkRedeployFilter{EnvGroup: "shared-db"})
+		got := Resolve(context.Background(), source, 1, apps, m)
+
+		if len(got) != 2 {
+			t.Fatalf("expected the matching app and the unreachable app, got %+v", got)
+		}
+		if got[0].AppName != "api" || got[0].Status != types.BulkRedeployStatus_Pending {
+			t.Errorf("expected api to be pending, got %+v", got[0])
+		}
+		if got[1].AppName != "offline" || got[1].Status != types.BulkRedeployStatus_Failed || got[1].Error == "" {
+			t.Errorf("expected the app on the unreachable cluster to fail, got %+v", got[1])
+		}
+	})
+
+	t.Run("cluster only", func(t *testing.T) {
+		m, _ := NewMatcher(types.BulkRedeployFilter{})
+		got := Resolve(context.Background(), source, 1, apps[:3], m)
+
+		want := []struct {
+			name   string
+			status types.BulkRedeployStatus
+		}{
+			{"api", types.BulkRedeployStatus_Pending},
+			{"docs", types.BulkRedeployStatus_Pending},
+			{"v2-app", types.BulkRedeployStatus_Skipped},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("expected %d apps, got %+v", len(want), got)
+		}
+		for i, w := range want {
+			if got[i].AppName != w.name || got[i].Status != w.status {
+				t.Errorf("expected %s to be %s, got %+v", w.name, w.status, got[i])
+			}
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package bulkredeploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// annotationRestartedAt is the pod template annotation set by kubectl rollout restart, which is reused so that a bumped
+// app shows the same history as one restarted by hand. Helm's three-way merge keeps it across later upgrades.
+const annotationRestartedAt = "kubectl.kubernetes.io/restartedAt"
+
+// NewRunnerFromConfig returns a Runner which reads bulk redeploys from the server's database and upgrades the helm
+// releases of their apps on each cluster
+func NewRunnerFromConfig(conf *config.Config, opts Options) *Runner {
+	return NewRunner(conf.Repo.BulkRedeploy(), NewHelmReleaseSource(conf), opts)
+}
+
+// NewHelmReleaseSource returns a ReleaseSource which connects to clusters with the server's credentials
+func NewHelmReleaseSource(conf *config.Config) ReleaseSource {
+	return worker.NewAgentSource(conf, func(cluster *models.Cluster, agent *kubernetes.Agent) (ClusterReleases, error) {
+		return &helmClusterReleases{conf: conf, cluster: cluster, agent: agent}, nil
+	})
+}
+
+type helmClusterReleases struct {
+	conf    *config.Config
+	cluster *models.Cluster
+	agent   *kubernetes.Agent
+}
+
+// Values returns the values of the latest release of an app
+func (c *helmClusterReleases) Values(ctx context.Context, appName string) (map[string]interface{}, error) {
+	_, rel, err := c.latestRelease(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	return rel.Config, nil
+}
+
+// Redeploy upgrades the latest release of an app with the same chart and values
+func (c *helmClusterReleases) Redeploy(ctx context.Context, appName string, bumpChecksum bool) (int, error) {
+	helmAgent, rel, err := c.latestRelease(ctx, appName)
+	if err != nil {
+		return 0, err
+	}
+
+	registries, err := c.conf.Repo.Registry().ListRegistriesByProjectID(c.cluster.ProjectID)
+	if err != nil {
+		return 0, fmt.Errorf("error listing registries: %w", err)
+	}
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	upgraded, err := helmAgent.UpgradeInstallChart(ctx, &helm.InstallChartConfig{
+		Chart:      rel.Chart,
+		Name:       appName,
+		Namespace:  namespace,
+		Values:     rel.Config,
+		Cluster:    c.cluster,
+		Repo:       c.conf.Repo,
+		Registries: registries,
+	}, c.conf.DOConf, c.conf.ServerConf.DisablePullSecretsInjection)
+	if err != nil {
+		return 0, fmt.Errorf("error upgrading helm release: %w", err)
+	}
+
+	if bumpChecksum {
+		if err := c.restart(ctx, namespace); err != nil {
+			return upgraded.Version, fmt.Errorf("upgraded to revision %d but failed to restart pods: %w", upgraded.Version, err)
+		}
+	}
+
+	return upgraded.Version, nil
+}
+
+func (c *helmClusterReleases) latestRelease(ctx context.Context, appName string) (*helm.Agent, *release.Release, error) {
+	helmAgent, err := helm.GetAgentFromK8sAgent("secret", utils.NamespaceFromPorterAppName(appName), c.conf.Logger, c.agent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting helm agent: %w", err)
+	}
+
+	rel, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil, nil, ErrNoRelease
+		}
+		return nil, nil, fmt.Errorf("error getting latest helm release: %w", err)
+	}
+
+	return helmAgent, rel, nil
+}
+
+// restart rolls out new pods for the deployments and stateful sets of an app, the same way as kubectl rollout restart
+func (c *helmClusterReleases) restart(ctx context.Context, namespace string) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, annotationRestartedAt, time.Now().UTC().Format(time.RFC3339)))
+
+	deployments, err := c.agent.Clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, deployment := range deployments.Items {
+		if _, err := c.agent.Clientset.AppsV1().Deployments(namespace).Patch(ctx, deployment.Name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("error restarting deployment %s: %w", deployment.Name, err)
+		}
+	}
+
+	statefulSets, err := c.agent.Clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, statefulSet := range statefulSets.Items {
+		if _, err := c.agent.Clientset.AppsV1().StatefulSets(namespace).Patch(ctx, statefulSet.Name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("error restarting stateful set %s: %w", statefulSet.Name, err)
+		}
+	}
+
+	return nil
+}
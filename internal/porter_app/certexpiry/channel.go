@@ -0,0 +1,39 @@
+package certexpiry
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// ValidateAlertChannel checks the channel alerts about the certificates of an app's domains are sent to. The channels
+// are the same as the ones of log alert rules.
+func ValidateAlertChannel(channel types.LogAlertChannel, webhookURL string) error {
+	switch channel {
+	case types.LogAlertChannel_Slack, types.LogAlertChannel_Inbox:
+		if webhookURL != "" {
+			return errors.New("webhook url can only be set for the webhook channel")
+		}
+	case types.LogAlertChannel_Webhook:
+		parsed, err := url.Parse(webhookURL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return errors.New("webhook url must be a valid https url")
+		}
+	default:
+		return fmt.Errorf("channel must be one of %s, %s or %s", types.LogAlertChannel_Slack, types.LogAlertChannel_Webhook, types.LogAlertChannel_Inbox)
+	}
+
+	return nil
+}
+
+// AlertChannel returns the channel alerts about the certificates of an app's domains are sent to. Apps which never
+// chose one send them to the slack integrations of their project.
+func AlertChannel(channel string) types.LogAlertChannel {
+	if channel == "" {
+		return types.LogAlertChannel_Slack
+	}
+
+	return types.LogAlertChannel(channel)
+}
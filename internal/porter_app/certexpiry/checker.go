@@ -0,0 +1,415 @@
+package certexpiry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+// AppStore lists the apps whose domains are checked
+type AppStore interface {
+	ListPorterApps(ctx context.Context) ([]*models.PorterApp, error)
+}
+
+// DomainStore records the domains of apps and the certificates which serve them
+type DomainStore interface {
+	ListPorterAppDomains(ctx context.Context, porterAppID uint) ([]*models.PorterAppDomain, error)
+	CreatePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) (*models.PorterAppDomain, error)
+	UpdatePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) error
+	DeletePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) error
+}
+
+// EventStore records alerts in an app's activity feed
+type EventStore interface {
+	CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error
+}
+
+// ClusterSource connects to the clusters that apps run on
+type ClusterSource interface {
+	// Connect returns the domains of the apps on a cluster, or an error if the cluster is unreachable
+	Connect(ctx context.Context, projectID, clusterID uint) (ClusterDomains, error)
+}
+
+// ClusterDomains reads the domains of the apps of a single cluster, and the certificates which serve them
+type ClusterDomains interface {
+	// Domains returns the hosts served by the ingresses of an app
+	Domains(ctx context.Context, appName string) ([]Domain, error)
+	// Certificate returns the certificate serving a domain of an app, or an error if it cannot be read
+	Certificate(ctx context.Context, appName string, domain Domain) (*Certificate, error)
+}
+
+// Domain is a host served by an ingress of an app
+type Domain struct {
+	Host string
+	// SecretName is the TLS secret of the host in its ingress, if the ingress terminates TLS for it
+	SecretName string
+	// Address is the external IP or hostname of the ingress, if it has one
+	Address string
+}
+
+// Certificate is the certificate serving a domain
+type Certificate struct {
+	Source   types.CertSource
+	Issuer   string
+	NotAfter time.Time
+}
+
+// Notifier sends an alert to the alert channel of its app
+type Notifier interface {
+	Notify(ctx context.Context, app *models.PorterApp, alert types.PorterAppCertExpiryEventMetadata) error
+}
+
+// Options configure how often certificates are checked and when alerts are sent. Zero values use the defaults.
+type Options struct {
+	// Interval is the time between checks of every domain. Defaults to 1h
+	Interval time.Duration
+	// ClusterTimeout bounds the time spent checking the domains of a single cluster in each check, so that an
+	// unreachable cluster cannot stall the others. Defaults to 2m
+	ClusterTimeout time.Duration
+	// Thresholds are the numbers of days before a certificate expires at which an alert is sent about it. An alert is
+	// also sent once it expires. Defaults to 30, 14 and 3
+	Thresholds []uint
+	// Logger receives a record of skipped clusters, unreadable certificates and sent alerts. Optional
+	Logger *logger.Logger
+}
+
+// Checker periodically reads the certificates serving the domains of apps, records when they expire, and alerts the
+// apps whose certificates are about to expire
+type Checker struct {
+	apps     AppStore
+	domains  DomainStore
+	events   EventStore
+	clusters ClusterSource
+	notifier Notifier
+	opts     Options
+	log      worker.Logger
+}
+
+// NewChecker returns a Checker with the given options
+func NewChecker(apps AppStore, domains DomainStore, events EventStore, clusters ClusterSource, notifier Notifier, opts Options) *Checker {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Hour
+	}
+	if opts.ClusterTimeout <= 0 {
+		opts.ClusterTimeout = 2 * time.Minute
+	}
+
+	// thresholds are kept from the earliest to the latest, without duplicates. A zero threshold is the expiry itself,
+	// which is always alerted
+	seen := make(map[uint]bool)
+	var thresholds []uint
+	for _, days := range opts.Thresholds {
+		if days != 0 && !seen[days] {
+			seen[days] = true
+			thresholds = append(thresholds, days)
+		}
+	}
+	if len(thresholds) == 0 {
+		thresholds = []uint{30, 14, 3}
+	}
+	sort.Slice(thresholds, func(i, j int) bool { return thresholds[i] > thresholds[j] })
+	opts.Thresholds = thresholds
+
+	return &Checker{
+		apps:     apps,
+		domains:  domains,
+		events:   events,
+		clusters: clusters,
+		notifier: notifier,
+		opts:     opts,
+		log:      worker.NewLogger(opts.Logger),
+	}
+}
+
+// Run checks every domain once per interval until ctx is cancelled
+func (c *Checker) Run(ctx context.Context) error {
+	return worker.Run(ctx, c.opts.Interval, c.log, "error checking domain certificates", c.check)
+}
+
+// check checks the domains of every app, returning once every cluster has been checked or has timed out
+func (c *Checker) check(ctx context.Context, now time.Time) error {
+	apps, err := c.apps.ListPorterApps(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing apps: %w", err)
+	}
+
+	clusters := make(map[worker.ClusterKey][]*models.PorterApp)
+	for _, app := range apps {
+		if app.ClusterID == 0 {
+			continue
+		}
+
+		ck := worker.ClusterKey{ProjectID: app.ProjectID, ClusterID: app.ClusterID}
+		clusters[ck] = append(clusters[ck], app)
+	}
+
+	worker.EachCluster(ctx, clusters, worker.ClusterOptions{
+		Timeout: c.opts.ClusterTimeout,
+		Action:  "checking domain certificates",
+		Logger:  c.log,
+	}, func(ctx context.Context, ck worker.ClusterKey, apps []*models.PorterApp) {
+		c.checkCluster(ctx, ck, apps, now)
+	})
+
+	return nil
+}
+
+func (c *Checker) checkCluster(ctx context.Context, ck worker.ClusterKey, apps []*models.PorterApp, now time.Time) {
+	cluster, err := c.clusters.Connect(ctx, ck.ProjectID, ck.ClusterID)
+	if err != nil {
+		c.log.Cluster(zerolog.WarnLevel, ck).Err(err).Msg("cluster is unreachable, marking the certificates of its domains as unknown")
+
+		// the domains of an unreachable cluster may still be served, so they are not removed, but their last check no
+		// longer says whether they are healthy
+		for _, app := range apps {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if _, err := c.markUnknown(ctx, app, fmt.Sprintf("cluster is unreachable: %s", err), now); err != nil {
+				c.log.App(zerolog.ErrorLevel, app).Err(err).Msg("error recording domains of unreachable cluster")
+			}
+		}
+
+		return
+	}
+
+	for _, app := range apps {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if _, err := c.CheckApp(ctx, cluster, app, now); err != nil {
+			c.log.App(zerolog.ErrorLevel, app).Err(err).Msg("error checking domain certificates of app")
+		}
+	}
+}
+
+// CheckApp reads the certificate of every domain an app serves on cluster, records it, and alerts the app about the
+// certificates which crossed an alert threshold since they were last checked. Domains the app no longer serves are
+// removed. It returns the domains of the app, ordered by hostname. Certificates which cannot be read are recorded as
+// unknown, so an error is only returned if the domains cannot be recorded.
+func (c *Checker) CheckApp(ctx context.Context, cluster ClusterDomains, app *models.PorterApp, now time.Time) ([]*models.PorterAppDomain, error) {
+	served, err := cluster.Domains(ctx, app.Name)
+	if err != nil {
+		c.log.App(zerolog.WarnLevel, app).Err(err).Msg("error reading domains of app, marking their certificates as unknown")
+		return c.markUnknown(ctx, app, fmt.Sprintf("error reading ingresses: %s", err), now)
+	}
+
+	existing, err := c.domains.ListPorterAppDomains(ctx, app.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing domains: %w", err)
+	}
+
+	byHost := make(map[string]*models.PorterAppDomain, len(existing))
+	for _, record := range existing {
+		byHost[record.Hostname] = record
+	}
+
+	sort.Slice(served, func(i, j int) bool { return served[i].Host < served[j].Host })
+
+	checked := make(map[string]bool, len(served))
+	records := make([]*models.PorterAppDomain, 0, len(served))
+
+	for _, domain := range served {
+		if domain.Host == "" || checked[domain.Host] {
+			continue
+		}
+		checked[domain.Host] = true
+
+		cert, certErr := cluster.Certificate(ctx, app.Name, domain)
+		if certErr != nil {
+			c.log.App(zerolog.InfoLevel, app).Str("hostname", domain.Host).Str("error", certErr.Error()).Msg("could not read certificate of domain")
+		}
+
+		record, ok := byHost[domain.Host]
+		if !ok {
+			record = &models.PorterAppDomain{
+				ProjectID:   app.ProjectID,
+				ClusterID:   app.ClusterID,
+				PorterAppID: app.ID,
+				Hostname:    domain.Host,
+			}
+		}
+
+		threshold, due := c.apply(record, cert, certErr, now)
+		if due && c.alert(ctx, app, record, threshold, now) {
+			record.AlertedThresholdDays = &threshold
+		}
+
+		if record.ID == 0 {
+			if _, err := c.domains.CreatePorterAppDomain(ctx, record); err != nil {
+				return nil, fmt.Errorf("error recording domain %s: %w", domain.Host, err)
+			}
+		} else if err := c.domains.UpdatePorterAppDomain(ctx, record); err != nil {
+			return nil, fmt.Errorf("error recording domain %s: %w", domain.Host, err)
+		}
+
+		records = append(records, record)
+	}
+
+	for _, record := range existing {
+		if checked[record.Hostname] {
+			continue
+		}
+
+		if err := c.domains.DeletePorterAppDomain(ctx, record); err != nil {
+			return nil, fmt.Errorf("error removing domain %s: %w", record.Hostname, err)
+		}
+	}
+
+	return records, nil
+}
+
+// markUnknown records that the certificates of every known domain of an app could not be read
+func (c *Checker) markUnknown(ctx context.Context, app *models.PorterApp, reason string, now time.Time) ([]*models.PorterAppDomain, error) {
+	records, err := c.domains.ListPorterAppDomains(ctx, app.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing domains: %w", err)
+	}
+
+	for _, record := range records {
+		record.CertStatus = string(types.CertStatus_Unknown)
+		record.CheckError = reason
+		record.CheckedAt = &now
+
+		if err := c.domains.UpdatePorterAppDomain(ctx, record); err != nil {
+			return nil, fmt.Errorf("error recording domain %s: %w", record.Hostname, err)
+		}
+	}
+
+	return records, nil
+}
+
+// apply sets the certificate of a domain as of now, and returns the alert threshold the certificate crossed if an
+// alert about it is due. A certificate which replaced the one last checked has its alerts reset.
+func (c *Checker) apply(record *models.PorterAppDomain, cert *Certificate, certErr error, now time.Time) (uint, bool) {
+	record.CheckedAt = &now
+
+	if certErr != nil || cert == nil {
+		record.CertStatus = string(types.CertStatus_Unknown)
+		record.CheckError = "certificate could not be read"
+		if certErr != nil {
+			record.CheckError = certErr.Error()
+		}
+		return 0, false
+	}
+
+	notAfter := cert.NotAfter.UTC()
+	if record.CertExpiresAt == nil || !record.CertExpiresAt.Equal(notAfter) {
+		record.AlertedThresholdDays = nil
+	}
+
+	record.CertSource = string(cert.Source)
+	record.CertIssuer = cert.Issuer
+	record.CertExpiresAt = &notAfter
+	record.CheckError = ""
+
+	threshold, crossed := c.crossedThreshold(notAfter, now)
+	switch {
+	case !crossed:
+		record.CertStatus = string(types.CertStatus_OK)
+		record.AlertedThresholdDays = nil
+		return 0, false
+	case threshold == 0:
+		record.CertStatus = string(types.CertStatus_Expired)
+	default:
+		record.CertStatus = string(types.CertStatus_Expiring)
+	}
+
+	if record.AlertedThresholdDays != nil && *record.AlertedThresholdDays <= threshold {
+		return 0, false
+	}
+
+	return threshold, true
+}
+
+// crossedThreshold returns the latest alert threshold a certificate expiring at notAfter has crossed at now, or 0 if
+// it has expired, and whether it crossed any
+func (c *Checker) crossedThreshold(notAfter time.Time, now time.Time) (uint, bool) {
+	remaining := notAfter.Sub(now)
+	if remaining <= 0 {
+		return 0, true
+	}
+
+	var threshold uint
+	crossed := false
+	for _, days := range c.opts.Thresholds {
+		if remaining <= time.Duration(days)*24*time.Hour {
+			threshold = days
+			crossed = true
+		}
+	}
+
+	return threshold, crossed
+}
+
+// alert records an alert about the certificate of a domain in the app's activity feed and sends it to the app's alert
+// channel. The event is what marks the alert as sent, so it reports whether the event was recorded, and an alert whose
+// event cannot be recorded is sent again in the next check.
+func (c *Checker) alert(ctx context.Context, app *models.PorterApp, record *models.PorterAppDomain, threshold uint, now time.Time) bool {
+	eventID := uuid.New()
+
+	alert := types.PorterAppCertExpiryEventMetadata{
+		EventID:       eventID.String(),
+		AppName:       app.Name,
+		Hostname:      record.Hostname,
+		Status:        types.CertStatus(record.CertStatus),
+		Source:        types.CertSource(record.CertSource),
+		Issuer:        record.CertIssuer,
+		ThresholdDays: threshold,
+		ExpiresAt:     *record.CertExpiresAt,
+		At:            now,
+	}
+
+	metadata, err := certExpiryEventMetadata(alert)
+	if err != nil {
+		c.log.App(zerolog.ErrorLevel, app).Err(err).Msg("error encoding certificate expiry alert")
+		return false
+	}
+
+	if err := c.events.CreateEvent(ctx, &models.PorterAppEvent{
+		ID:          eventID,
+		Type:        string(types.PorterAppEventType_CertExpiry),
+		PorterAppID: app.ID,
+		Metadata:    metadata,
+	}); err != nil {
+		c.log.App(zerolog.ErrorLevel, app).Err(err).Str("hostname", record.Hostname).Msg("error recording certificate expiry alert")
+		return false
+	}
+
+	c.log.App(zerolog.InfoLevel, app).Str("hostname", record.Hostname).Uint("threshold_days", threshold).Msg("certificate of domain crossed an alert threshold")
+
+	if AlertChannel(app.CertAlertChannel) == types.LogAlertChannel_Inbox || c.notifier == nil {
+		return true
+	}
+
+	if err := c.notifier.Notify(ctx, app, alert); err != nil {
+		c.log.App(zerolog.ErrorLevel, app).Err(err).Str("hostname", record.Hostname).Msg("error sending certificate expiry alert")
+	}
+
+	return true
+}
+
+func certExpiryEventMetadata(alert types.PorterAppCertExpiryEventMetadata) (models.JSONB, error) {
+	by, err := json.Marshal(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := models.JSONB{}
+	if err := json.Unmarshal(by, &metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
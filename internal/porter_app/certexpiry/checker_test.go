@@ -0,0 +1,331 @@
+package certexpiry
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker/workertest"
+	"gorm.io/gorm"
+)
+
+const day = 24 * time.Hour
+
+type fakeAppStore []*models.PorterApp
+
+func (s fakeAppStore) ListPorterApps(ctx context.Context) ([]*models.PorterApp, error) {
+	return s, nil
+}
+
+// fakeDomainStore stores copies of domains, so that the checker only sees what it recorded
+type fakeDomainStore struct {
+	mu      sync.Mutex
+	nextID  uint
+	domains map[uint]models.PorterAppDomain
+}
+
+func (s *fakeDomainStore) ListPorterAppDomains(ctx context.Context, porterAppID uint) ([]*models.PorterAppDomain, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*models.PorterAppDomain
+	for _, domain := range s.domains {
+		if domain.PorterAppID == porterAppID {
+			domain := domain
+			res = append(res, &domain)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Hostname < res[j].Hostname })
+
+	return res, nil
+}
+
+func (s *fakeDomainStore) CreatePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) (*models.PorterAppDomain, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.domains == nil {
+		s.domains = make(map[uint]models.PorterAppDomain)
+	}
+	s.nextID++
+	domain.ID = s.nextID
+	s.domains[domain.ID] = *domain
+
+	return domain, nil
+}
+
+func (s *fakeDomainStore) UpdatePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.domains[domain.ID] = *domain
+	return nil
+}
+
+func (s *fakeDomainStore) DeletePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.domains, domain.ID)
+	return nil
+}
+
+// byHost returns the recorded domain of a host
+func (s *fakeDomainStore) byHost(t *testing.T, host string) models.PorterAppDomain {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, domain := range s.domains {
+		if domain.Hostname == host {
+			return domain
+		}
+	}
+
+	t.Fatalf("expected domain %s to be recorded", host)
+	return models.PorterAppDomain{}
+}
+
+type fakeEventStore struct {
+	mu     sync.Mutex
+	events []*models.PorterAppEvent
+}
+
+func (s *fakeEventStore) CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, appEvent)
+	return nil
+}
+
+// fakeCluster serves the domains of the apps of a single cluster
+type fakeCluster struct {
+	workertest.Cluster
+	domains map[string][]Domain
+	// certs is the certificate served for each host. Hosts which are not set cannot be read
+	certs map[string]*Certificate
+}
+
+func (c *fakeCluster) Domains(ctx context.Context, appName string) ([]Domain, error) {
+	return c.domains[appName], nil
+}
+
+func (c *fakeCluster) Certificate(ctx context.Context, appName string, domain Domain) (*Certificate, error) {
+	cert, ok := c.certs[domain.Host]
+	if !ok {
+		return nil, errors.New("ingress has no external address")
+	}
+
+	return cert, nil
+}
+
+type fakeClusterSource = workertest.Source[ClusterDomains, *fakeCluster]
+
+type fakeNotifier struct {
+	mu       sync.Mutex
+	notified []types.PorterAppCertExpiryEventMetadata
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, app *models.PorterApp, alert types.PorterAppCertExpiryEventMetadata) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.notified = append(n.notified, alert)
+	return nil
+}
+
+type checkerFixture struct {
+	domains  *fakeDomainStore
+	events   *fakeEventStore
+	notifier *fakeNotifier
+	checker  *Checker
+}
+
+func newCheckerFixture(apps []*models.PorterApp, clusters fakeClusterSource) *checkerFixture {
+	f := &checkerFixture{
+		domains:  &fakeDomainStore{},
+		events:   &fakeEventStore{},
+		notifier: &fakeNotifier{},
+	}
+	f.checker = NewChecker(fakeAppStore(apps), f.domains, f.events, clusters, f.notifier, Options{})
+
+	return f
+}
+
+func (f *checkerFixture) check(t *testing.T, now time.Time) {
+	t.Helper()
+
+	if err := f.checker.check(context.Background(), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func testApp(id uint, name string) *models.PorterApp {
+	return &models.PorterApp{Model: gorm.Model{ID: id}, ProjectID: 1, ClusterID: 1, Name: name}
+}
+
+func TestCheck_AlertsOncePerThreshold(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(40 * day)
+
+	cluster := &fakeCluster{
+		domains: map[string][]Domain{"web": {{Host: "app.example.com", Address: "1.2.3.4"}}},
+		certs:   map[string]*Certificate{"app.example.com": {Source: types.CertSource_TLSProbe, Issuer: "R3", NotAfter: expiresAt}},
+	}
+	f := newCheckerFixture([]*models.PorterApp{testApp(10, "web")}, fakeClusterSource{1: cluster})
+
+	f.check(t, now)
+	if domain := f.domains.byHost(t, "app.example.com"); domain.CertStatus != string(types.CertStatus_OK) || domain.CertIssuer != "R3" {
+		t.Fatalf("expected a certificate outside of the thresholds to be ok, got %+v", domain)
+	}
+	if len(f.notifier.notified) != 0 {
+		t.Fatalf("expected no alert, got %+v", f.notifier.notified)
+	}
+
+	f.check(t, expiresAt.Add(-29*day))
+	f.check(t, expiresAt.Add(-28*day))
+	if len(f.notifier.notified) != 1 || f.notifier.notified[0].ThresholdDays != 30 || f.notifier.notified[0].Status != types.CertStatus_Expiring {
+		t.Fatalf("expected a single alert for the 30 day threshold, got %+v", f.notifier.notified)
+	}
+	if len(f.events.events) != 1 || f.events.events[0].Type != string(types.PorterAppEventType_CertExpiry) {
+		t.Fatalf("expected the alert to be recorded in the activity feed, got %+v", f.events.events)
+	}
+
+	f.check(t, expiresAt.Add(-13*day))
+	if len(f.notifier.notified) != 2 || f.notifier.notified[1].ThresholdDays != 14 {
+		t.Fatalf("expected an alert for the 14 day threshold, got %+v", f.notifier.notified)
+	}
+
+	f.check(t, expiresAt.Add(time.Hour))
+	f.check(t, expiresAt.Add(2*time.Hour))
+	if len(f.notifier.notified) != 3 || f.notifier.notified[2].Status != types.CertStatus_Expired {
+		t.Fatalf("expected a single alert once the certificate expired, got %+v", f.notifier.notified)
+	}
+	if domain := f.domains.byHost(t, "app.example.com"); domain.CertStatus != string(types.CertStatus_Expired) {
+		t.Fatalf("expected the certificate to be expired, got %s", domain.CertStatus)
+	}
+}
+
+func TestCheck_RenewedCertificateResetsAlerts(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cluster := &fakeCluster{
+		domains: map[string][]Domain{"web": {{Host: "app.example.com", Address: "1.2.3.4"}}},
+		certs:   map[string]*Certificate{"app.example.com": {Source: types.CertSource_TLSProbe, NotAfter: now.Add(10 * day)}},
+	}
+	f := newCheckerFixture([]*models.PorterApp{testApp(10, "web")}, fakeClusterSource{1: cluster})
+
+	f.check(t, now)
+	if len(f.notifier.notified) != 1 || f.notifier.notified[0].ThresholdDays != 14 {
+		t.Fatalf("expected an alert for the latest threshold crossed, got %+v", f.notifier.notified)
+	}
+
+	renewedAt := now.Add(day)
+	cluster.certs["app.example.com"] = &Certificate{Source: types.CertSource_TLSProbe, NotAfter: renewedAt.Add(90 * day)}
+	f.check(t, renewedAt)
+	if domain := f.domains.byHost(t, "app.example.com"); domain.CertStatus != string(types.CertStatus_OK) || domain.AlertedThresholdDays != nil {
+		t.Fatalf("expected the renewed certificate to be ok with its alerts reset, got %+v", domain)
+	}
+
+	f.check(t, renewedAt.Add(61*day))
+	if len(f.notifier.notified) != 2 || f.notifier.notified[1].ThresholdDays != 30 {
+		t.Fatalf("expected the renewed certificate to be alerted about again, got %+v", f.notifier.notified)
+	}
+}
+
+func TestCheck_UnreadableCertificateIsUnknown(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(60 * day)
+
+	cluster := &fakeCluster{
+		domains: map[string][]Domain{"web": {{Host: "app.example.com", Address: "1.2.3.4"}}},
+		certs:   map[string]*Certificate{"app.example.com": {Source: types.CertSource_TLSProbe, NotAfter: expiresAt}},
+	}
+	f := newCheckerFixture([]*models.PorterApp{testApp(10, "web")}, fakeClusterSource{1: cluster})
+
+	f.check(t, now)
+
+	delete(cluster.certs, "app.example.com")
+	f.check(t, expiresAt.Add(-day))
+
+	domain := f.domains.byHost(t, "app.example.com")
+	if domain.CertStatus != string(types.CertStatus_Unknown) || domain.CheckError == "" {
+		t.Fatalf("expected an unreadable certificate to be unknown with the reason, got %+v", domain)
+	}
+	if domain.CertExpiresAt == nil || !domain.CertExpiresAt.Equal(expiresAt) {
+		t.Fatalf("expected the expiry of the last check to be kept, got %v", domain.CertExpiresAt)
+	}
+	if len(f.notifier.notified) != 0 {
+		t.Fatalf("expected no alert about a certificate which could not be read, got %+v", f.notifier.notified)
+	}
+}
+
+func TestCheck_RemovesDomainsWhichAreNoLongerServed(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cluster := &fakeCluster{
+		domains: map[string][]Domain{"web": {{Host: "a.example.com"}, {Host: "b.example.com"}}},
+	}
+	f := newCheckerFixture([]*models.PorterApp{testApp(10, "web")}, fakeClusterSource{1: cluster})
+
+	f.check(t, now)
+	if len(f.domains.domains) != 2 {
+		t.Fatalf("expected both domains to be recorded, got %+v", f.domains.domains)
+	}
+
+	cluster.domains["web"] = []Domain{{Host: "b.example.com"}}
+	f.check(t, now.Add(time.Hour))
+
+	domains, _ := f.domains.ListPorterAppDomains(context.Background(), 10)
+	if len(domains) != 1 || domains[0].Hostname != "b.example.com" {
+		t.Fatalf("expected the removed domain to be deleted, got %+v", domains)
+	}
+}
+
+func TestCheck_UnreachableClusterMarksDomainsUnknown(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	clusters := fakeClusterSource{1: {
+		domains: map[string][]Domain{"web": {{Host: "app.example.com"}}},
+		certs:   map[string]*Certificate{"app.example.com": {Source: types.CertSource_CertManager, NotAfter: now.Add(60 * day)}},
+	}}
+	f := newCheckerFixture([]*models.PorterApp{testApp(10, "web")}, clusters)
+
+	f.check(t, now)
+
+	clusters[1].Unreachable = true
+	f.check(t, now.Add(time.Hour))
+
+	if domain := f.domains.byHost(t, "app.example.com"); domain.CertStatus != string(types.CertStatus_Unknown) {
+		t.Fatalf("expected the domain of an unreachable cluster to be unknown, got %+v", domain)
+	}
+}
+
+func TestCheck_InboxChannelOnlyRecordsEvent(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	app := testApp(10, "web")
+	app.CertAlertChannel = string(types.LogAlertChannel_Inbox)
+
+	cluster := &fakeCluster{
+		domains: map[string][]Domain{"web": {{Host: "app.example.com"}}},
+		certs:   map[string]*Certificate{"app.example.com": {Source: types.CertSource_TLSProbe, NotAfter: now.Add(2 * day)}},
+	}
+	f := newCheckerFixture([]*models.PorterApp{app}, fakeClusterSource{1: cluster})
+
+	f.check(t, now)
+
+	if len(f.notifier.notified) != 0 {
+		t.Fatalf("expected no notification for the inbox channel, got %+v", f.notifier.notified)
+	}
+	if len(f.events.events) != 1 || f.events.events[0].Metadata["threshold_days"] != float64(3) {
+		t.Fatalf("expected the alert to be recorded in the activity feed, got %+v", f.events.events)
+	}
+}
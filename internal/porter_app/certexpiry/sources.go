@@ -0,0 +1,267 @@
+package certexpiry
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/notifier/slack"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	"github.com/porter-dev/porter/internal/webhooks"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// probeTimeout bounds the connection to the ingress of a domain to read its certificate
+const probeTimeout = 10 * time.Second
+
+var certificateResource = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+// NewCheckerFromConfig returns a Checker which reads apps and domains from the server's database, reads certificates
+// on each cluster with the server's credentials, and sends alerts through the channel of each app
+func NewCheckerFromConfig(conf *config.Config, opts Options) *Checker {
+	return NewChecker(
+		conf.Repo.PorterApp(),
+		conf.Repo.PorterAppDomain(),
+		conf.Repo.PorterAppEvent(),
+		worker.NewAgentSource(conf, func(cluster *models.Cluster, agent *kubernetes.Agent) (ClusterDomains, error) {
+			return NewClusterDomains(agent), nil
+		}),
+		NewNotifierFromConfig(conf),
+		opts,
+	)
+}
+
+// NewNotifierFromConfig returns a Notifier which sends alerts to the slack integrations of the app's project, or to
+// the app's webhook
+func NewNotifierFromConfig(conf *config.Config) Notifier {
+	return &channelNotifier{conf: conf, dispatcher: webhooks.NewDispatcherFromConfig(conf)}
+}
+
+// NewClusterDomains returns the domains of the apps on the cluster of agent. Certificates are read from the
+// cert-manager Certificate of the TLS secret of a domain, or by connecting to the external address of its ingress.
+func NewClusterDomains(agent *kubernetes.Agent) ClusterDomains {
+	return &agentClusterDomains{agent: agent}
+}
+
+type agentClusterDomains struct {
+	agent *kubernetes.Agent
+	// dynamicClient reads cert-manager Certificates, and is created on the first read
+	dynamicClient dynamic.Interface
+}
+
+// Domains returns the hosts of the rules of the ingresses in the namespace of an app
+func (c *agentClusterDomains) Domains(ctx context.Context, appName string) ([]Domain, error) {
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	ingresses, err := c.agent.Clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing ingresses: %w", err)
+	}
+
+	var domains []Domain
+	for _, ingress := range ingresses.Items {
+		var address string
+		for _, lb := range ingress.Status.LoadBalancer.Ingress {
+			if lb.IP != "" {
+				address = lb.IP
+				break
+			}
+			if lb.Hostname != "" {
+				address = lb.Hostname
+				break
+			}
+		}
+
+		secrets := make(map[string]string)
+		for _, tlsConf := range ingress.Spec.TLS {
+			for _, host := range tlsConf.Hosts {
+				secrets[host] = tlsConf.SecretName
+			}
+		}
+
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host == "" {
+				continue
+			}
+
+			domains = append(domains, Domain{Host: rule.Host, SecretName: secrets[rule.Host], Address: address})
+		}
+	}
+
+	return domains, nil
+}
+
+// Certificate returns the certificate of a domain from the cert-manager Certificate of its TLS secret if there is one,
+// or by connecting to the external address of its ingress otherwise
+func (c *agentClusterDomains) Certificate(ctx context.Context, appName string, domain Domain) (*Certificate, error) {
+	var certManagerErr error
+	if domain.SecretName != "" {
+		cert, err := c.certManagerCertificate(ctx, utils.NamespaceFromPorterAppName(appName), domain.SecretName)
+		if err == nil && cert != nil {
+			return cert, nil
+		}
+		certManagerErr = err
+	}
+
+	if domain.Address == "" {
+		if certManagerErr != nil {
+			return nil, certManagerErr
+		}
+		return nil, errors.New("ingress has no external address to connect to, which is the case for ingresses of private load balancers")
+	}
+
+	return probeCertificate(ctx, domain)
+}
+
+// certManagerCertificate returns the certificate of the cert-manager Certificate which issues secretName, or nil if
+// cert-manager does not issue it
+func (c *agentClusterDomains) certManagerCertificate(ctx context.Context, namespace, secretName string) (*Certificate, error) {
+	if c.dynamicClient == nil {
+		if c.agent.RESTClientGetter == nil {
+			return nil, nil
+		}
+
+		restConf, err := c.agent.RESTClientGetter.ToRESTConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error getting rest config: %w", err)
+		}
+
+		c.dynamicClient, err = dynamic.NewForConfig(restConf)
+		if err != nil {
+			return nil, fmt.Errorf("error creating dynamic client: %w", err)
+		}
+	}
+
+	certs, err := c.dynamicClient.Resource(certificateResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		// clusters without cert-manager do not serve the Certificate resource
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing cert-manager certificates: %w", err)
+	}
+
+	for _, cert := range certs.Items {
+		if name, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName"); name != secretName {
+			continue
+		}
+
+		notAfter, _, _ := unstructured.NestedString(cert.Object, "status", "notAfter")
+		if notAfter == "" {
+			return nil, fmt.Errorf("cert-manager certificate %s has not been issued", cert.GetName())
+		}
+
+		expiresAt, err := time.Parse(time.RFC3339, notAfter)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing expiry of cert-manager certificate %s: %w", cert.GetName(), err)
+		}
+
+		issuerKind, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind")
+		issuerName, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "name")
+		if issuerKind == "" {
+			issuerKind = "Issuer"
+		}
+
+		return &Certificate{
+			Source:   types.CertSource_CertManager,
+			Issuer:   fmt.Sprintf("%s/%s", issuerKind, issuerName),
+			NotAfter: expiresAt,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// probeCertificate connects to the ingress of a domain and returns the certificate it serves for the domain
+func probeCertificate(ctx context.Context, domain Domain) (*Certificate, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: probeTimeout},
+		// the certificate is read rather than trusted, so that expired and self-signed certificates are still reported
+		Config: &tls.Config{ServerName: domain.Host, InsecureSkipVerify: true}, // nolint:gosec
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(domain.Address, "443"))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to ingress at %s: %w", domain.Address, err)
+	}
+	defer conn.Close() // nolint:errcheck
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil, errors.New("ingress did not negotiate tls")
+	}
+
+	peers := tlsConn.ConnectionState().PeerCertificates
+	if len(peers) == 0 {
+		return nil, errors.New("ingress did not serve a certificate")
+	}
+
+	leaf := peers[0]
+	if err := leaf.VerifyHostname(domain.Host); err != nil {
+		// ingress controllers serve their default certificate for hosts they have no certificate for
+		return nil, fmt.Errorf("ingress served a certificate which is not valid for %s", domain.Host)
+	}
+
+	issuer := leaf.Issuer.CommonName
+	if issuer == "" && len(leaf.Issuer.Organization) != 0 {
+		issuer = leaf.Issuer.Organization[0]
+	}
+
+	return &Certificate{
+		Source:   types.CertSource_TLSProbe,
+		Issuer:   issuer,
+		NotAfter: leaf.NotAfter,
+	}, nil
+}
+
+// channelNotifier sends alerts to the project's slack integrations, or to the app's webhook
+type channelNotifier struct {
+	conf       *config.Config
+	dispatcher *webhooks.Dispatcher
+}
+
+// Notify sends the alert to the app's alert channel. Projects without a slack integration are only notified through
+// the app's activity feed.
+func (n *channelNotifier) Notify(ctx context.Context, app *models.PorterApp, alert types.PorterAppCertExpiryEventMetadata) error {
+	switch AlertChannel(app.CertAlertChannel) {
+	case types.LogAlertChannel_Slack:
+		slackInts, err := n.conf.Repo.SlackIntegration().ListSlackIntegrationsByProjectID(app.ProjectID)
+		if err != nil {
+			return fmt.Errorf("error listing slack integrations: %w", err)
+		}
+		if len(slackInts) == 0 {
+			return nil
+		}
+
+		url := fmt.Sprintf("%s/apps/%s/activity", n.conf.ServerConf.ServerURL, alert.AppName)
+		return slack.NewCertExpiryNotifier(slackInts...).Notify(ctx, alert, url)
+	case types.LogAlertChannel_Webhook:
+		payload, err := json.Marshal(alert)
+		if err != nil {
+			return err
+		}
+
+		_, err = n.dispatcher.Deliver(ctx, webhooks.Event{
+			ProjectID: app.ProjectID,
+			EventID:   alert.EventID,
+			URL:       app.CertAlertWebhookURL,
+			Payload:   payload,
+		})
+		return err
+	default:
+		return nil
+	}
+}
@@ -2,7 +2,6 @@ package porter_app
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 
@@ -100,8 +99,9 @@ func CreateOrGetAppRecord(ctx context.Context, input CreateOrGetAppRecordInput)
 		telemetry.AttributeKV{Key: "name", Value: input.Name},
 	)
 
+	// an empty app is returned if no app has the name
 	existingApp, err := input.PorterAppRepository.ReadPorterAppByName(input.ClusterID, input.Name)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+	if err != nil {
 		return app, telemetry.Error(ctx, span, err, "error reading porter app by name")
 	}
 
@@ -0,0 +1,41 @@
+package deletecleanup
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewWatcherFromConfig returns a Watcher which reads cleanups from the server's database, checks load balancers on each
+// cluster with the server's credentials and resolves domains with the default resolver
+func NewWatcherFromConfig(conf *config.Config, opts Options) *Watcher {
+	clusters := worker.NewAgentSource(conf, func(cluster *models.Cluster, agent *kubernetes.Agent) (ClusterServices, error) {
+		return &agentClusterServices{agent: agent}, nil
+	})
+
+	return NewWatcher(conf.Repo.PorterAppCleanup(), clusters, net.DefaultResolver, opts)
+}
+
+type agentClusterServices struct {
+	agent *kubernetes.Agent
+}
+
+// ServiceExists reports whether a service still exists
+func (c *agentClusterServices) ServiceExists(ctx context.Context, namespace, name string) (bool, error) {
+	_, err := c.agent.Clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading service %s: %w", name, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,212 @@
+package deletecleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+// CleanupStore reads the cleanups of deleted apps which have resources left to release, and records what was released
+type CleanupStore interface {
+	ListPendingPorterAppCleanups(ctx context.Context) ([]*models.PorterAppCleanup, error)
+	UpdatePorterAppCleanup(ctx context.Context, cleanup *models.PorterAppCleanup) error
+}
+
+// ClusterSource connects to the clusters that deleted apps ran on
+type ClusterSource interface {
+	// Connect returns the services of a cluster, or an error if the cluster is unreachable
+	Connect(ctx context.Context, projectID, clusterID uint) (ClusterServices, error)
+}
+
+// ClusterServices reads the services of a single cluster
+type ClusterServices interface {
+	// ServiceExists reports whether a service still exists. A LoadBalancer service is kept by its finalizer until its
+	// cloud load balancer is released.
+	ServiceExists(ctx context.Context, namespace, name string) (bool, error)
+}
+
+// Resolver resolves the domains of deleted apps. It is satisfied by *net.Resolver.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Options configure how often cleanups are checked. Zero values use the defaults.
+type Options struct {
+	// Interval is the time between checks of every pending cleanup. Defaults to 1m
+	Interval time.Duration
+	// ClusterTimeout bounds the time spent checking the cleanups of a single cluster in each check, so that an
+	// unreachable cluster cannot stall the others. Defaults to 30s
+	ClusterTimeout time.Duration
+	// Logger receives a record of skipped clusters and finished cleanups. Optional
+	Logger *logger.Logger
+}
+
+// Watcher periodically checks whether the load balancers and domains of deleted apps were released. A cleanup succeeds
+// once all of its resources are released, and is flagged as leaked if some of them are still there at its deadline.
+type Watcher struct {
+	cleanups CleanupStore
+	clusters ClusterSource
+	resolver Resolver
+	opts     Options
+	log      worker.Logger
+}
+
+// NewWatcher returns a Watcher with the given options
+func NewWatcher(cleanups CleanupStore, clusters ClusterSource, resolver Resolver, opts Options) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.ClusterTimeout <= 0 {
+		opts.ClusterTimeout = 30 * time.Second
+	}
+
+	return &Watcher{
+		cleanups: cleanups,
+		clusters: clusters,
+		resolver: resolver,
+		opts:     opts,
+		log:      worker.NewLogger(opts.Logger),
+	}
+}
+
+// Run checks every pending cleanup once per interval until ctx is cancelled
+func (w *Watcher) Run(ctx context.Context) error {
+	return worker.Run(ctx, w.opts.Interval, w.log, "error checking porter app cleanups", w.check)
+}
+
+// check checks every pending cleanup, returning once every cluster has been checked or has timed out
+func (w *Watcher) check(ctx context.Context, now time.Time) error {
+	cleanups, err := w.cleanups.ListPendingPorterAppCleanups(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing pending porter app cleanups: %w", err)
+	}
+
+	clusters := make(map[worker.ClusterKey][]*models.PorterAppCleanup)
+	for _, cleanup := range cleanups {
+		ck := worker.ClusterKey{ProjectID: cleanup.ProjectID, ClusterID: cleanup.ClusterID}
+		clusters[ck] = append(clusters[ck], cleanup)
+	}
+
+	worker.EachCluster(ctx, clusters, worker.ClusterOptions{
+		Timeout: w.opts.ClusterTimeout,
+		Action:  "checking porter app cleanups",
+		Logger:  w.log,
+	}, func(ctx context.Context, ck worker.ClusterKey, cleanups []*models.PorterAppCleanup) {
+		w.checkCluster(ctx, ck, cleanups, now)
+	})
+
+	return nil
+}
+
+// checkCluster checks the cleanups of a cluster. The load balancers of a cluster which is unreachable are kept
+// pending, so that they are flagged as leaked if the cluster stays unreachable past the deadline of their cleanup.
+func (w *Watcher) checkCluster(ctx context.Context, ck worker.ClusterKey, cleanups []*models.PorterAppCleanup, now time.Time) {
+	cluster, err := w.clusters.Connect(ctx, ck.ProjectID, ck.ClusterID)
+	if err != nil {
+		w.log.Cluster(zerolog.WarnLevel, ck).Err(err).Msg("cluster is unreachable, keeping the load balancers of its deleted apps pending")
+		cluster = nil
+	}
+
+	for _, cleanup := range cleanups {
+		if ctx.Err() != nil {
+			return
+		}
+
+		w.checkCleanup(ctx, cluster, cleanup, now)
+	}
+}
+
+// checkCleanup removes the released resources of a cleanup, then finishes it if there are none left or its deadline
+// has passed
+func (w *Watcher) checkCleanup(ctx context.Context, cluster ClusterServices, cleanup *models.PorterAppCleanup, now time.Time) {
+	var pending models.PorterAppCleanupResources
+	for _, resource := range cleanup.Pending {
+		released, err := w.released(ctx, cluster, cleanup.Namespace, resource)
+		if err != nil {
+			w.logCleanup(zerolog.DebugLevel, cleanup).Err(err).Str("kind", resource.Kind).Str("name", resource.Name).Msg("error checking resource of deleted app, keeping it pending")
+		}
+		if !released {
+			pending = append(pending, resource)
+		}
+	}
+
+	changed := len(pending) != len(cleanup.Pending)
+	cleanup.Pending = pending
+
+	switch {
+	case len(pending) == 0:
+		cleanup.Status = string(types.PorterAppCleanupStatus_Succeeded)
+		cleanup.FinishedAt = &now
+		changed = true
+	case !now.Before(cleanup.Deadline):
+		cleanup.Status = string(types.PorterAppCleanupStatus_Leaked)
+		cleanup.FinishedAt = &now
+		changed = true
+	}
+
+	if !changed {
+		return
+	}
+
+	if err := w.cleanups.UpdatePorterAppCleanup(ctx, cleanup); err != nil {
+		w.logCleanup(zerolog.ErrorLevel, cleanup).Err(err).Msg("error recording released resources of deleted app, they are checked again in the next check")
+		return
+	}
+
+	switch types.PorterAppCleanupStatus(cleanup.Status) {
+	case types.PorterAppCleanupStatus_Succeeded:
+		w.logCleanup(zerolog.InfoLevel, cleanup).Msg("every resource of deleted app was released")
+	case types.PorterAppCleanupStatus_Leaked:
+		w.logCleanup(zerolog.WarnLevel, cleanup).Int("leaked", len(pending)).Msg("resources of deleted app were not released by the deadline, flagging them on the project")
+	}
+}
+
+// released reports whether a resource of a deleted app was released: a load balancer once the finalizer of its service
+// has completed and the service is gone, and a domain once it no longer resolves. Resources which set their own
+// namespace, such as those of services which ran in another namespace, are looked up in it.
+func (w *Watcher) released(ctx context.Context, cluster ClusterServices, namespace string, resource types.PorterAppCleanupResource) (bool, error) {
+	if resource.Namespace != "" {
+		namespace = resource.Namespace
+	}
+
+	switch resource.Kind {
+	case types.OrphanedResourceKind_LoadBalancer:
+		if cluster == nil {
+			return false, errors.New("cluster is unreachable")
+		}
+
+		exists, err := cluster.ServiceExists(ctx, namespace, resource.Name)
+		if err != nil {
+			return false, err
+		}
+
+		return !exists, nil
+	case types.OrphanedResourceKind_DNSRecord:
+		_, err := w.resolver.LookupHost(ctx, resource.Name)
+		if err == nil {
+			return false, nil
+		}
+
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return true, nil
+		}
+
+		return false, err
+	default:
+		// resources of kinds which cannot be checked are not tracked, so that they do not leak every cleanup
+		return true, nil
+	}
+}
+
+func (w *Watcher) logCleanup(level zerolog.Level, cleanup *models.PorterAppCleanup) *zerolog.Event {
+	return w.log.WithLevel(level).Uint("project_id", cleanup.ProjectID).Uint("cluster_id", cleanup.ClusterID).Uint("porter_app_cleanup_id", cleanup.ID).Str("app_name", cleanup.AppName)
+}
@@ -0,0 +1,163 @@
+package deletecleanup
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker/workertest"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+// fakeClusterServices holds the names of the services which still exist
+type fakeClusterServices struct {
+	workertest.Cluster
+	services map[string]bool
+}
+
+func (c *fakeClusterServices) ServiceExists(ctx context.Context, namespace, name string) (bool, error) {
+	return c.services[name], nil
+}
+
+type fakeClusterSource = workertest.Source[ClusterServices, *fakeClusterServices]
+
+// fakeResolver resolves the hosts it holds, and reports every other host as not found
+type fakeResolver map[string]bool
+
+func (r fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if r[host] {
+		return []string{"203.0.113.10"}, nil
+	}
+
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+var now = time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+// newCleanup stores a pending cleanup of an app on cluster 1 with a load balancer and a domain
+func newCleanup(t *testing.T, repo repository.PorterAppCleanupRepository, deadline time.Time) *models.PorterAppCleanup {
+	t.Helper()
+
+	cleanup, err := repo.CreatePorterAppCleanup(context.Background(), &models.PorterAppCleanup{
+		ProjectID: 1,
+		ClusterID: 1,
+		AppName:   "payments",
+		Namespace: "porter-stack-payments",
+		Status:    string(types.PorterAppCleanupStatus_Pending),
+		Pending: models.PorterAppCleanupResources{
+			{Kind: types.OrphanedResourceKind_LoadBalancer, Name: "payments-web"},
+			{Kind: types.OrphanedResourceKind_DNSRecord, Name: "payments-web-abc.withporter.run"},
+		},
+		Deadline: deadline,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return cleanup
+}
+
+func readCleanup(t *testing.T, repo repository.PorterAppCleanupRepository, id uint) *models.PorterAppCleanup {
+	t.Helper()
+
+	cleanup, err := repo.ReadPorterAppCleanup(context.Background(), 1, id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return cleanup
+}
+
+func TestCheck_SucceedsOnceEveryResourceIsReleased(t *testing.T) {
+	repo := test.NewPorterAppCleanupRepository(true)
+	cleanup := newCleanup(t, repo, now.Add(time.Hour))
+
+	cluster := &fakeClusterServices{services: map[string]bool{"payments-web": true}}
+	resolver := fakeResolver{}
+	w := NewWatcher(repo, fakeClusterSource{1: cluster}, resolver, Options{})
+
+	if err := w.check(context.Background(), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := readCleanup(t, repo, cleanup.ID)
+	if got.Status != string(types.PorterAppCleanupStatus_Pending) || len(got.Pending) != 1 || got.Pending[0].Name != "payments-web" {
+		t.Fatalf("expected only the load balancer to be pending after the domain stopped resolving, got %s %v", got.Status, got.Pending)
+	}
+
+	// the finalizer of the service completes
+	delete(cluster.services, "payments-web")
+
+	if err := w.check(context.Background(), now.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got = readCleanup(t, repo, cleanup.ID)
+	if got.Status != string(types.PorterAppCleanupStatus_Succeeded) || len(got.Pending) != 0 || got.FinishedAt == nil {
+		t.Errorf("expected the cleanup to succeed, got %s %v", got.Status, got.Pending)
+	}
+}
+
+func TestCheck_FlagsResourcesPendingPastTheDeadlineAsLeaked(t *testing.T) {
+	repo := test.NewPorterAppCleanupRepository(true)
+	cleanup := newCleanup(t, repo, now.Add(time.Minute))
+
+	cluster := &fakeClusterServices{services: map[string]bool{"payments-web": true}}
+	resolver := fakeResolver{"payments-web-abc.withporter.run": true}
+	w := NewWatcher(repo, fakeClusterSource{1: cluster}, resolver, Options{})
+
+	if err := w.check(context.Background(), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := readCleanup(t, repo, cleanup.ID); got.Status != string(types.PorterAppCleanupStatus_Pending) || len(got.Pending) != 2 {
+		t.Fatalf("expected the cleanup to stay pending before its deadline, got %s %v", got.Status, got.Pending)
+	}
+
+	resolver["payments-web-abc.withporter.run"] = false
+
+	if err := w.check(context.Background(), now.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := readCleanup(t, repo, cleanup.ID)
+	if got.Status != string(types.PorterAppCleanupStatus_Leaked) || len(got.Pending) != 1 || got.Pending[0].Kind != types.OrphanedResourceKind_LoadBalancer {
+		t.Errorf("expected the load balancer to be leaked, got %s %v", got.Status, got.Pending)
+	}
+
+	pending, err := repo.ListPendingPorterAppCleanups(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected a leaked cleanup to not be checked again, got %d pending", len(pending))
+	}
+}
+
+func TestCheck_KeepsLoadBalancersOfUnreachableClustersPending(t *testing.T) {
+	repo := test.NewPorterAppCleanupRepository(true)
+	cleanup := newCleanup(t, repo, now.Add(time.Hour))
+
+	cluster := &fakeClusterServices{Cluster: workertest.Cluster{Unreachable: true}}
+	w := NewWatcher(repo, fakeClusterSource{1: cluster}, fakeResolver{}, Options{})
+
+	if err := w.check(context.Background(), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := readCleanup(t, repo, cleanup.ID)
+	if got.Status != string(types.PorterAppCleanupStatus_Pending) || len(got.Pending) != 1 || got.Pending[0].Kind != types.OrphanedResourceKind_LoadBalancer {
+		t.Errorf("expected the load balancer to stay pending while its cluster is unreachable, got %s %v", got.Status, got.Pending)
+	}
+
+	if err := w.check(context.Background(), now.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := readCleanup(t, repo, cleanup.ID); got.Status != string(types.PorterAppCleanupStatus_Leaked) {
+		t.Errorf("expected the load balancer to be leaked once the deadline passes, got %s", got.Status)
+	}
+}
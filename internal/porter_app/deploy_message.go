@@ -0,0 +1,100 @@
+package porter_app
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxDeployMessageLength is the maximum number of characters kept from the release notes message of a deploy
+const MaxDeployMessageLength = 280
+
+// NormalizeDeployMessage collapses the whitespace of a deploy message onto a single line, drops characters which are not
+// printable, and truncates it to MaxDeployMessageLength characters
+func NormalizeDeployMessage(message string) string {
+	var b strings.Builder
+	pendingSpace := false
+	length := 0
+
+	for _, r := range message {
+		if length >= MaxDeployMessageLength {
+			break
+		}
+		if r == utf8.RuneError {
+			continue
+		}
+		if unicode.IsSpace(r) {
+			pendingSpace = b.Len() > 0
+			continue
+		}
+		if !unicode.IsPrint(r) {
+			continue
+		}
+
+		if pendingSpace {
+			b.WriteRune(' ')
+			length++
+			pendingSpace = false
+			if length >= MaxDeployMessageLength {
+				break
+			}
+		}
+		b.WriteRune(r)
+		length++
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// MaxCommitMessageLength is the maximum number of characters kept from the message of the commit which is deployed
+const MaxCommitMessageLength = 1000
+
+// TruncateCommitMessage drops the characters of a commit message which are not printable, other than newlines and tabs,
+// and truncates it to MaxCommitMessageLength characters. Unlike a deploy message, the lines of the commit message are kept.
+func TruncateCommitMessage(message string) string {
+	var b strings.Builder
+	length := 0
+
+	for _, r := range message {
+		if length >= MaxCommitMessageLength {
+			break
+		}
+		if r == utf8.RuneError {
+			continue
+		}
+		if r != '\n' && r != '\t' && !unicode.IsPrint(r) {
+			continue
+		}
+
+		b.WriteRune(r)
+		length++
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// githubMarkdownEscaper escapes the characters which github markdown would otherwise interpret, including the pipes which
+// would end a table cell
+var githubMarkdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"[", `\[`,
+	"]", `\]`,
+	"(", `\(`,
+	")", `\)`,
+	"<", `\<`,
+	">", `\>`,
+	"#", `\#`,
+	"!", `\!`,
+	"|", `\|`,
+	// a zero-width space keeps mentions of users and teams from notifying them
+	"@", "@\u200b",
+)
+
+// GithubDeployMessage renders a deploy message as plain text in github markdown, safe to use in a table cell
+func GithubDeployMessage(message string) string {
+	return githubMarkdownEscaper.Replace(NormalizeDeployMessage(message))
+}
@@ -0,0 +1,73 @@
+package porter_app
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNormalizeDeployMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "empty", message: "", want: ""},
+		{name: "collapses whitespace", message: "  fix checkout\n\n\ttotals  ", want: "fix checkout totals"},
+		{name: "drops control characters", message: "fix\x1b[31m checkout\x00", want: "fix[31m checkout"},
+		{name: "keeps unicode", message: "corrige le panier 🛒", want: "corrige le panier 🛒"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeDeployMessage(tt.message); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeDeployMessageTruncates(t *testing.T) {
+	got := NormalizeDeployMessage(strings.Repeat("é ", MaxDeployMessageLength))
+	if n := utf8.RuneCountInString(got); n > MaxDeployMessageLength {
+		t.Errorf("expected at most %d characters, got %d", MaxDeployMessageLength, n)
+	}
+	if strings.HasSuffix(got, " ") {
+		t.Errorf("expected no trailing space, got %q", got)
+	}
+}
+
+func TestTruncateCommitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "empty", message: "", want: ""},
+		{name: "keeps lines", message: "fix checkout\n\n\tround totals\n", want: "fix checkout\n\n\tround totals"},
+		{name: "drops control characters", message: "fix\x1b[31m checkout\x00", want: "fix[31m checkout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateCommitMessage(tt.message); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTruncateCommitMessageTruncates(t *testing.T) {
+	got := TruncateCommitMessage(strings.Repeat("é", MaxCommitMessageLength+1))
+	if n := utf8.RuneCountInString(got); n != MaxCommitMessageLength {
+		t.Errorf("expected %d characters, got %d", MaxCommitMessageLength, n)
+	}
+}
+
+func TestGithubDeployMessage(t *testing.T) {
+	got := GithubDeployMessage("cc @porter-dev/team | [click](https://evil.example) **now**")
+	want := "cc @\u200bporter-dev/team \\| \\[click\\]\\(https://evil.example\\) \\*\\*now\\*\\*"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
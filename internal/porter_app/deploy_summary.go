@@ -0,0 +1,284 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+const (
+	// deploySummaryMarkerFormat is the hidden marker used to find the deploy summary comment for an app on a pull request
+	deploySummaryMarkerFormat = "<!-- porter-deploy-summary:%s -->"
+
+	// maxGithubRateLimitRetries is the number of times a rate limited github request is retried
+	maxGithubRateLimitRetries = 3
+	// maxGithubRateLimitWait is the longest we will wait on a rate limited github request before giving up
+	maxGithubRateLimitWait = 30 * time.Second
+	// defaultGithubRateLimitWait is used when github rate limits a request without telling us how long to wait
+	defaultGithubRateLimitWait = time.Minute
+)
+
+// DeploySummary contains the details of a deploy shown in the pull request comment
+type DeploySummary struct {
+	AppName        string
+	RevisionNumber uint64
+	Status         models.AppRevisionStatus
+	ImageTag       string
+	CommitSHA      string
+	// Message is the release notes of the deploy, escaped before it is shown
+	Message string
+	// AppURLs are the public urls of the app, shown when the deploy succeeds
+	AppURLs      []string
+	DashboardURL string
+}
+
+// IsDeploySummaryStatus returns true if a revision with the given status should be summarized on its pull request
+func IsDeploySummaryStatus(status models.AppRevisionStatus) bool {
+	switch status {
+	case models.AppRevisionStatus_InstallSuccessful,
+		models.AppRevisionStatus_InstallFailed,
+		models.AppRevisionStatus_BuildFailed,
+		models.AppRevisionStatus_PredeployFailed:
+		return true
+	}
+
+	return false
+}
+
+// deploySummaryDiagnosticsHint points the reader at where to look for a failed deploy
+func deploySummaryDiagnosticsHint(status models.AppRevisionStatus) string {
+	switch status {
+	case models.AppRevisionStatus_BuildFailed:
+		return "The image failed to build. Check the build step in the GitHub Actions run for this commit."
+	case models.AppRevisionStatus_PredeployFailed:
+		return "The pre-deploy job failed. Check the pre-deploy job logs in the Porter dashboard."
+	case models.AppRevisionStatus_InstallFailed:
+		return "The services failed to become healthy. Check the service events and logs in the Porter dashboard."
+	}
+
+	return ""
+}
+
+// DeploySummaryCommentBody returns the markdown body of the deploy summary comment, including the hidden marker for the app
+func DeploySummaryCommentBody(summary DeploySummary, repoName string) string {
+	badge := "![deploy failed](https://img.shields.io/badge/deploy-failed-red)"
+	if summary.Status == models.AppRevisionStatus_InstallSuccessful {
+		badge = "![deploy succeeded](https://img.shields.io/badge/deploy-succeeded-brightgreen)"
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, deploySummaryMarkerFormat+"\n", summary.AppName)
+	fmt.Fprintf(&body, "## Porter deploy summary for `%s`\n\n%s\n\n", summary.AppName, badge)
+
+	body.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&body, "| Revision | %d |\n", summary.RevisionNumber)
+	if summary.ImageTag != "" {
+		fmt.Fprintf(&body, "| Image tag | `%s` |\n", summary.ImageTag)
+	}
+	if summary.CommitSHA != "" {
+		fmt.Fprintf(&body, "| Commit | [`%s`](https://github.com/%s/commit/%s) |\n", summary.CommitSHA, repoName, summary.CommitSHA)
+	}
+	if message := GithubDeployMessage(summary.Message); message != "" {
+		fmt.Fprintf(&body, "| Release notes | %s |\n", message)
+	}
+	if summary.Status == models.AppRevisionStatus_InstallSuccessful {
+		for _, appURL := range summary.AppURLs {
+			fmt.Fprintf(&body, "| URL | https://%s |\n", appURL)
+		}
+	}
+
+	if hint := deploySummaryDiagnosticsHint(summary.Status); hint != "" {
+		fmt.Fprintf(&body, "\n**Diagnostics**: %s\n", hint)
+	}
+
+	if summary.DashboardURL != "" {
+		fmt.Fprintf(&body, "\nApp details are available in the [Porter Dashboard](%s)\n", summary.DashboardURL)
+	}
+
+	return body.String()
+}
+
+// PullRequest identifies a github pull request
+type PullRequest struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// ParsePullRequestURL parses a url of the form https://github.com/<owner>/<repo>/pull/<number>
+func ParsePullRequestURL(pullRequestURL string) (PullRequest, error) {
+	u, err := url.Parse(pullRequestURL)
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("invalid pull request url: %w", err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[2] != "pull" {
+		return PullRequest{}, fmt.Errorf("pull request url %s is not in the format https://github.com/<owner>/<repo>/pull/<number>", pullRequestURL)
+	}
+
+	number, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("invalid pull request number in url %s: %w", pullRequestURL, err)
+	}
+
+	return PullRequest{Owner: parts[0], Repo: parts[1], Number: number}, nil
+}
+
+// UpsertDeploySummaryCommentInput is the input to the UpsertDeploySummaryComment function
+type UpsertDeploySummaryCommentInput struct {
+	Client      *github.Client
+	PullRequest PullRequest
+	Summary     DeploySummary
+	// SkipClosedPullRequests leaves the comment untouched once the pull request has been merged or closed
+	SkipClosedPullRequests bool
+}
+
+// UpsertDeploySummaryComment creates the deploy summary comment for an app on a pull request, or updates it in place if it already exists
+func UpsertDeploySummaryComment(ctx context.Context, inp UpsertDeploySummaryCommentInput) error {
+	ctx, span := telemetry.NewSpan(ctx, "upsert-deploy-summary-comment")
+	defer span.End()
+
+	if inp.Client == nil {
+		return telemetry.Error(ctx, span, nil, "github client is nil")
+	}
+	if inp.Summary.AppName == "" {
+		return telemetry.Error(ctx, span, nil, "app name is empty")
+	}
+
+	pr := inp.PullRequest
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "repo-owner", Value: pr.Owner},
+		telemetry.AttributeKV{Key: "repo-name", Value: pr.Repo},
+		telemetry.AttributeKV{Key: "pr-number", Value: pr.Number},
+		telemetry.AttributeKV{Key: "revision-status", Value: string(inp.Summary.Status)},
+	)
+
+	if inp.SkipClosedPullRequests {
+		var pullRequest *github.PullRequest
+		err := withGithubRateLimitRetry(ctx, func() (*github.Response, error) {
+			var resp *github.Response
+			var err error
+			pullRequest, resp, err = inp.Client.PullRequests.Get(ctx, pr.Owner, pr.Repo, pr.Number)
+			return resp, err
+		})
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error getting pull request")
+		}
+
+		if pullRequest.GetState() == "closed" {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "skipped-closed-pr", Value: true})
+			return nil
+		}
+	}
+
+	marker := fmt.Sprintf(deploySummaryMarkerFormat, inp.Summary.AppName)
+	body := DeploySummaryCommentBody(inp.Summary, fmt.Sprintf("%s/%s", pr.Owner, pr.Repo))
+
+	var existing *github.IssueComment
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for existing == nil {
+		var comments []*github.IssueComment
+		var resp *github.Response
+		err := withGithubRateLimitRetry(ctx, func() (*github.Response, error) {
+			var err error
+			comments, resp, err = inp.Client.Issues.ListComments(ctx, pr.Owner, pr.Repo, pr.Number, opts)
+			return resp, err
+		})
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error listing pull request comments")
+		}
+
+		for _, comment := range comments {
+			if strings.HasPrefix(comment.GetBody(), marker) {
+				existing = comment
+				break
+			}
+		}
+
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if existing != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "comment-id", Value: existing.GetID()})
+
+		err := withGithubRateLimitRetry(ctx, func() (*github.Response, error) {
+			_, resp, err := inp.Client.Issues.EditComment(ctx, pr.Owner, pr.Repo, existing.GetID(), &github.IssueComment{Body: &body})
+			return resp, err
+		})
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error updating deploy summary comment")
+		}
+
+		return nil
+	}
+
+	err := withGithubRateLimitRetry(ctx, func() (*github.Response, error) {
+		_, resp, err := inp.Client.Issues.CreateComment(ctx, pr.Owner, pr.Repo, pr.Number, &github.IssueComment{Body: &body})
+		return resp, err
+	})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error creating deploy summary comment")
+	}
+
+	return nil
+}
+
+// withGithubRateLimitRetry calls a github api, retrying after the wait requested by github if the call was rate limited
+func withGithubRateLimitRetry(ctx context.Context, call func() (*github.Response, error)) error {
+	for attempt := 0; ; attempt++ {
+		resp, err := call()
+		if err == nil {
+			return nil
+		}
+
+		wait, ok := githubRateLimitWait(resp, err)
+		if !ok || attempt >= maxGithubRateLimitRetries || wait > maxGithubRateLimitWait {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// githubRateLimitWait returns how long to wait before retrying a github call, and false if the call was not rate limited
+func githubRateLimitWait(resp *github.Response, err error) (time.Duration, bool) {
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter, true
+		}
+		return defaultGithubRateLimitWait, true
+	}
+
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return time.Until(rateLimitErr.Rate.Reset.Time), true
+	}
+
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if err != nil {
+			return defaultGithubRateLimitWait, true
+		}
+		return time.Duration(retryAfter) * time.Second, true
+	}
+
+	return 0, false
+}
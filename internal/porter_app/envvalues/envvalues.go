@@ -0,0 +1,115 @@
+// Package envvalues validates the env variable values in porter.yaml, and resolves the values the porter CLI reads from
+// local files. Values are kept byte-for-byte: multi-line values such as PEM certificates are never trimmed or folded,
+// and scalars which YAML types as numbers or booleans are rejected instead of being silently converted.
+package envvalues
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// MaxValueBytes is the largest value a single env variable can have
+	MaxValueBytes = 64 * 1024
+	// MaxServiceBytes is the largest total size of the env variables of a single service, keys included. The env of
+	// every service is stored in its helm release, which kubernetes caps at 1MiB
+	MaxServiceBytes = 256 * 1024
+
+	// FromFileKey is the porter.yaml key which sets an env variable to the contents of a local file
+	FromFileKey = "fromFile"
+)
+
+// FromYAML returns the string value of an env variable decoded by gopkg.in/yaml.v2. Values which YAML did not decode as
+// a string are rejected, since converting them back to a string would not give the value as written: 0100 would become
+// 64, 1e3 would become 1000 and on would become true.
+func FromYAML(key string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return "", fmt.Errorf("env variable %s was parsed as the boolean %t rather than a string: quote the value in porter.yaml to keep it as written", key, v)
+	case int, int64, uint64, float64:
+		return "", fmt.Errorf("env variable %s was parsed as the number %v rather than a string: quote the value in porter.yaml to keep it as written", key, v)
+	case map[interface{}]interface{}:
+		if _, ok := v[FromFileKey]; ok {
+			return "", FromFileNotResolvedError(key)
+		}
+		if _, ok := v[ValueFromKey]; ok {
+			return "", ValueFromNotResolvedError(key)
+		}
+		return "", mappingError(key)
+	case map[string]interface{}:
+		if _, ok := v[FromFileKey]; ok {
+			return "", FromFileNotResolvedError(key)
+		}
+		if _, ok := v[ValueFromKey]; ok {
+			return "", ValueFromNotResolvedError(key)
+		}
+		return "", mappingError(key)
+	case []interface{}:
+		return "", fmt.Errorf("env variable %s was parsed as a list rather than a string: quote the value in porter.yaml to keep it as written", key)
+	default:
+		return "", fmt.Errorf("env variable %s has unsupported type %T: quote the value in porter.yaml to keep it as written", key, value)
+	}
+}
+
+// MapFromYAML returns the string values of env variables decoded by gopkg.in/yaml.v2, rejecting any value which is not
+// a string
+func MapFromYAML(raw map[string]interface{}) (map[string]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	env := make(map[string]string, len(raw))
+	for _, key := range sortedKeys(raw) {
+		value, err := FromYAML(key, raw[key])
+		if err != nil {
+			return nil, err
+		}
+		env[key] = value
+	}
+
+	return env, nil
+}
+
+// CheckSize returns an error if any env variable of a service is larger than MaxValueBytes, or if all of them together
+// are larger than MaxServiceBytes
+func CheckSize(service string, env map[string]string) error {
+	total := 0
+
+	for _, key := range sortedKeys(env) {
+		value := env[key]
+		if len(value) > MaxValueBytes {
+			return fmt.Errorf("env variable %s of %s is %d bytes, which is larger than the limit of %d bytes", key, service, len(value), MaxValueBytes)
+		}
+		total += len(key) + len(value)
+	}
+
+	if total > MaxServiceBytes {
+		return fmt.Errorf("env variables of %s are %d bytes in total, which is larger than the limit of %d bytes", service, total, MaxServiceBytes)
+	}
+
+	return nil
+}
+
+func mappingError(key string) error {
+	return fmt.Errorf("env variable %s was parsed as a mapping rather than a string: check the indentation of the value, and use a literal block (|) for multi-line values", key)
+}
+
+// FromFileNotResolvedError is returned for an env variable set with fromFile which reached the server, since only the
+// porter CLI can read the file
+func FromFileNotResolvedError(key string) error {
+	return fmt.Errorf("env variable %s uses %s, which is only supported when applying porter.yaml with the porter CLI", key, FromFileKey)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,232 @@
+package envvalues
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+const testPEM = "-----BEGIN CERTIFICATE-----\nMIIBszCCAVmgAwIBAgIUQ7v1\n  indented line with trailing space \n\twith a tab\n-----END CERTIFICATE-----\n\n"
+
+func TestFromYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    string
+		wantErr string
+	}{
+		{name: "quoted number", yaml: `V: "8080"`, want: "8080"},
+		{name: "null", yaml: `V:`, want: ""},
+		{name: "literal block", yaml: "V: |\n  line one\n  line two\n", want: "line one\nline two\n"},
+		{name: "number", yaml: `V: 8080`, wantErr: "parsed as the number 8080"},
+		{name: "octal", yaml: `V: 0100`, wantErr: "parsed as the number 64"},
+		{name: "yaml 1.1 boolean", yaml: `V: on`, wantErr: "parsed as the boolean true"},
+		{name: "mapping from a stray indent", yaml: "V:\n  nested: value\n", wantErr: "parsed as a mapping"},
+		{name: "unresolved fromFile", yaml: "V:\n  fromFile: cert.pem\n", wantErr: "only supported when applying porter.yaml with the porter CLI"},
+		{name: "unresolved valueFrom", yaml: "V:\n  valueFrom:\n    provider: vault\n", wantErr: "only supported when porter.yaml is applied by Porter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw map[string]interface{}
+			if err := yamlv2.Unmarshal([]byte(tt.yaml), &raw); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := MapFromYAML(raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got["V"] != tt.want {
+				t.Fatalf("got %q, want %q", got["V"], tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSize(t *testing.T) {
+	if err := CheckSize("service web", map[string]string{"CERT": strings.Repeat("a", MaxValueBytes)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := CheckSize("service web", map[string]string{"CERT": strings.Repeat("a", MaxValueBytes+1)})
+	if err == nil || !strings.Contains(err.Error(), "env variable CERT of service web is 65537 bytes") {
+		t.Fatalf("expected value size error, got %v", err)
+	}
+
+	env := map[string]string{}
+	for i := 0; i < MaxServiceBytes/MaxValueBytes+1; i++ {
+		env[strings.Repeat("K", i+1)] = strings.Repeat("a", MaxValueBytes)
+	}
+	err = CheckSize("service web", env)
+	if err == nil || !strings.Contains(err.Error(), "env variables of service web are") {
+		t.Fatalf("expected service size error, got %v", err)
+	}
+}
+
+func writeFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestResolveFiles(t *testing.T) {
+	dir := t.TempDir()
+	crlf := "{\r\n  \"name\": \"caf\u00e9 \U0001F680\",\r\n  \"list\": [1, 2]\r\n}"
+	writeFile(t, dir, "cert.pem", testPEM)
+	writeFile(t, dir, "config.json", crlf)
+	writeFile(t, dir, "unicode.txt", "\u65e5\u672c\u8a9e \u00fcml\u00e4ut")
+
+	porterYaml := `version: v2
+name: app
+env:
+  PORT: "8080"
+  # certificates are read from the repository
+  TLS_CERT:
+    fromFile: cert.pem
+  CONFIG:
+    fromFile: ./config.json
+services:
+  - name: web
+    type: web
+previews:
+  env:
+    - key: UNICODE
+      fromFile: unicode.txt
+    - key: PLAIN
+      value: plain
+`
+
+	resolved, err := ResolveFiles([]byte(porterYaml), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(resolved), FromFileKey) || strings.Contains(string(resolved), "cert.pem") {
+		t.Fatalf("expected file paths not to be sent, got %s", resolved)
+	}
+
+	// the server parses porter.yaml with yaml.v2
+	var parsed struct {
+		Env      map[string]string `yaml:"env"`
+		Previews struct {
+			Env []struct {
+				Key   string `yaml:"key"`
+				Value string `yaml:"value"`
+			} `yaml:"env"`
+		} `yaml:"previews"`
+	}
+	if err := yamlv2.Unmarshal(resolved, &parsed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if parsed.Env["TLS_CERT"] != testPEM {
+		t.Errorf("pem was changed: got %q, want %q", parsed.Env["TLS_CERT"], testPEM)
+	}
+	if parsed.Env["CONFIG"] != crlf {
+		t.Errorf("crlf content was changed: got %q, want %q", parsed.Env["CONFIG"], crlf)
+	}
+	if parsed.Env["PORT"] != "8080" {
+		t.Errorf("unexpected PORT %q", parsed.Env["PORT"])
+	}
+	if len(parsed.Previews.Env) != 2 || parsed.Previews.Env[0].Value != "\u65e5\u672c\u8a9e \u00fcml\u00e4ut" || parsed.Previews.Env[1].Value != "plain" {
+		t.Errorf("unexpected preview env %+v", parsed.Previews.Env)
+	}
+	if !strings.Contains(string(resolved), "# certificates are read from the repository") {
+		t.Errorf("expected comments to be kept")
+	}
+}
+
+func TestResolveFiles_V1ContainerEnv(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "cert.pem", testPEM)
+
+	porterYaml := `version: v1stack
+env:
+  NODE_ENV: production
+apps:
+  web:
+    type: web
+    config:
+      container:
+        env:
+          normal:
+            TLS_CERT:
+              fromFile: cert.pem
+`
+
+	resolved, err := ResolveFiles([]byte(porterYaml), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed struct {
+		Apps map[string]struct {
+			Config struct {
+				Container struct {
+					Env struct {
+						Normal map[string]string `yaml:"normal"`
+					} `yaml:"env"`
+				} `yaml:"container"`
+			} `yaml:"config"`
+		} `yaml:"apps"`
+	}
+	if err := yamlv2.Unmarshal(resolved, &parsed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := parsed.Apps["web"].Config.Container.Env.Normal["TLS_CERT"]; got != testPEM {
+		t.Fatalf("pem was changed: got %q, want %q", got, testPEM)
+	}
+}
+
+func TestResolveFiles_Unchanged(t *testing.T) {
+	porterYaml := "version: v2\nname: app\nenv:\n  PORT: '8080'\n  CERT: |\n    line\n"
+
+	resolved, err := ResolveFiles([]byte(porterYaml), t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resolved) != porterYaml {
+		t.Fatalf("expected porter.yaml without fromFile to be returned unchanged, got %q", resolved)
+	}
+}
+
+func TestResolveFiles_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "binary.bin", "\xff\xfe\x00")
+	writeFile(t, dir, "large.txt", strings.Repeat("a", MaxValueBytes+1))
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{name: "unquoted number", yaml: "env:\n  PORT: 8080\n", wantErr: "env variable PORT was parsed as the number 8080"},
+		{name: "yaml 1.1 boolean", yaml: "env:\n  ENABLED: yes\n", wantErr: "env variable ENABLED was parsed as the boolean true"},
+		{name: "unquoted number in a list", yaml: "env:\n  - key: PORT\n    value: 8080\n", wantErr: "env variable PORT was parsed as the number"},
+		{name: "missing file", yaml: "env:\n  CERT:\n    fromFile: missing.pem\n", wantErr: "error reading missing.pem for env variable CERT"},
+		{name: "binary file", yaml: "env:\n  CERT:\n    fromFile: binary.bin\n", wantErr: "is not UTF-8 text"},
+		{name: "large file", yaml: "env:\n  CERT:\n    fromFile: large.txt\n", wantErr: "larger than the limit"},
+		{name: "value and fromFile", yaml: "env:\n  - key: CERT\n    value: a\n    fromFile: cert.pem\n", wantErr: "sets both value and fromFile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResolveFiles([]byte(tt.yaml), dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
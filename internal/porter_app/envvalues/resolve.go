@@ -0,0 +1,239 @@
+package envvalues
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	yamlv2 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ResolveFiles inlines the contents of every env variable in porterYaml which is set with fromFile, reading the files
+// relative to dir. Both the map form of env (KEY: {fromFile: path}) and the list form of env (- key: KEY, fromFile: path)
+// are supported, as is the container env of a service in a v1 porter.yaml. Only the file's contents are sent to Porter,
+// never its path.
+//
+// Plain env values which the server would parse as a number or a boolean are rejected, so that the user is asked to
+// quote them before anything is deployed. If nothing was inlined, porterYaml is returned unchanged.
+func ResolveFiles(porterYaml []byte, dir string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(porterYaml, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing porter.yaml: %w", err)
+	}
+
+	r := &resolver{dir: dir}
+	if err := r.walk(&doc); err != nil {
+		return nil, err
+	}
+
+	if !r.changed {
+		return porterYaml, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("error writing porter.yaml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("error writing porter.yaml: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+type resolver struct {
+	dir     string
+	changed bool
+}
+
+func (r *resolver) walk(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := r.walk(child); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "env" {
+				if err := r.env(value); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := r.walk(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// env resolves an env section, which is either a map of env variables, a list of env variable definitions, or the
+// container env of a v1 service, whose variables are under normal
+func (r *resolver) env(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind != yaml.MappingNode {
+				continue
+			}
+			if err := r.envDefinition(item); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		if normal := mappingValue(node, "normal"); normal != nil && normal.Kind == yaml.MappingNode {
+			return r.envMap(normal)
+		}
+		return r.envMap(node)
+	}
+
+	return nil
+}
+
+func (r *resolver) envMap(node *yaml.Node) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+
+		switch value.Kind {
+		case yaml.ScalarNode:
+			if err := CheckScalar(key, value); err != nil {
+				return err
+			}
+		case yaml.MappingNode:
+			path := mappingValue(value, FromFileKey)
+			if path == nil || len(value.Content) != 2 {
+				// the server reports values which are mappings
+				continue
+			}
+
+			contents, err := r.read(key, path)
+			if err != nil {
+				return err
+			}
+			node.Content[i+1] = stringNode(contents)
+			r.changed = true
+		}
+	}
+
+	return nil
+}
+
+func (r *resolver) envDefinition(node *yaml.Node) error {
+	key := ""
+	if keyNode := mappingValue(node, "key"); keyNode != nil {
+		key = keyNode.Value
+	}
+
+	value := mappingValue(node, "value")
+	if value != nil && value.Kind == yaml.ScalarNode {
+		if err := CheckScalar(key, value); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != FromFileKey {
+			continue
+		}
+		if value != nil {
+			return fmt.Errorf("env variable %s sets both value and %s, only one can be set", key, FromFileKey)
+		}
+
+		contents, err := r.read(key, node.Content[i+1])
+		if err != nil {
+			return err
+		}
+		node.Content[i] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "value"}
+		node.Content[i+1] = stringNode(contents)
+		r.changed = true
+	}
+
+	return nil
+}
+
+// read returns the contents of the file an env variable is set from, which must be text no larger than MaxValueBytes
+func (r *resolver) read(key string, pathNode *yaml.Node) (string, error) {
+	if pathNode.Kind != yaml.ScalarNode || pathNode.Value == "" {
+		return "", fmt.Errorf("%s of env variable %s must be the path of a file", FromFileKey, key)
+	}
+
+	path := pathNode.Value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.dir, path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s for env variable %s: %w", pathNode.Value, key, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s for env variable %s is a directory, not a file", pathNode.Value, key)
+	}
+	if info.Size() > MaxValueBytes {
+		return "", fmt.Errorf("%s for env variable %s is %d bytes, which is larger than the limit of %d bytes", pathNode.Value, key, info.Size(), MaxValueBytes)
+	}
+
+	contents, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("error reading %s for env variable %s: %w", pathNode.Value, key, err)
+	}
+	if !utf8.Valid(contents) {
+		return "", fmt.Errorf("%s for env variable %s is not UTF-8 text: base64 encode binary files before setting them as env variables", pathNode.Value, key)
+	}
+
+	return string(contents), nil
+}
+
+// CheckScalar rejects a plain scalar which the server, which parses porter.yaml with gopkg.in/yaml.v2, would not
+// parse as a string. Quoted scalars, block scalars and scalars tagged !!str are always strings.
+func CheckScalar(key string, node *yaml.Node) error {
+	if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return nil
+	}
+	if node.Style&yaml.TaggedStyle != 0 {
+		if node.Tag == "!!str" {
+			return nil
+		}
+		return fmt.Errorf("env variable %s is tagged %s: env variable values must be strings", key, node.Tag)
+	}
+
+	var parsed interface{}
+	if err := yamlv2.Unmarshal([]byte(node.Value), &parsed); err != nil {
+		// plain scalars which are not a YAML document on their own are left for the server to report
+		return nil
+	}
+
+	_, err := FromYAML(key, parsed)
+	return err
+}
+
+// stringNode returns a double quoted scalar, which keeps carriage returns, tabs, trailing whitespace and trailing
+// newlines exactly as they are in the file
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!str",
+		Style: yaml.DoubleQuotedStyle,
+		Value: value,
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+package envvalues
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/porter-dev/porter/api/types"
+	"gopkg.in/yaml.v3"
+)
+
+// ValueFromKey is the porter.yaml key which sets an env variable to a secret read from an external secret store when
+// the app is deployed
+const ValueFromKey = "valueFrom"
+
+// SecretReference is where the value of an env variable set with valueFrom is read from. Only the reference is part of
+// porter.yaml; the value is read by the server each time the app is deployed and is only written to the app's secret
+// in the cluster.
+type SecretReference struct {
+	// Provider is the secret store, which must be configured for the project
+	Provider types.SecretsProvider `yaml:"provider" json:"provider"`
+	// Path is the path of the secret in a Vault KV mount, or the name or ARN of a secret in AWS Secrets Manager
+	Path string `yaml:"path" json:"path"`
+	// Key is the field of the secret to read. It is required for Vault. For AWS Secrets Manager, the secret string is
+	// read as a JSON object if it is set, or used as the value if it is not.
+	Key string `yaml:"key,omitempty" json:"key,omitempty"`
+	// PinnedVersion is the version of the secret to read. Without it, every deploy reads the current version, so that a
+	// rotated secret is picked up by the next deploy.
+	PinnedVersion string `yaml:"pinned_version,omitempty" json:"pinned_version,omitempty"`
+}
+
+// String returns the reference without its version, for error messages
+func (r SecretReference) String() string {
+	if r.Key == "" {
+		return fmt.Sprintf("%s %s", r.Provider, r.Path)
+	}
+
+	return fmt.Sprintf("%s %s#%s", r.Provider, r.Path, r.Key)
+}
+
+var secretReferenceFields = []string{"provider", "path", "key", "pinned_version"}
+
+// ParseSecretReference returns the reference which the valueFrom mapping of an env variable points to
+func ParseSecretReference(key string, node *yaml.Node) (SecretReference, error) {
+	var ref SecretReference
+
+	if node.Kind != yaml.MappingNode {
+		return ref, fmt.Errorf("%s of env variable %s must be a mapping with provider and path", ValueFromKey, key)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		field, value := node.Content[i].Value, node.Content[i+1]
+
+		known := false
+		for _, f := range secretReferenceFields {
+			known = known || f == field
+		}
+		if !known {
+			return ref, fmt.Errorf("%s of env variable %s has unknown field %s, expected one of provider, path, key and pinned_version", ValueFromKey, key, field)
+		}
+		if value.Kind != yaml.ScalarNode {
+			return ref, fmt.Errorf("%s.%s of env variable %s must be a string", ValueFromKey, field, key)
+		}
+
+		switch field {
+		case "provider":
+			ref.Provider = types.SecretsProvider(value.Value)
+		case "path":
+			ref.Path = value.Value
+		case "key":
+			ref.Key = value.Value
+		case "pinned_version":
+			ref.PinnedVersion = value.Value
+		}
+	}
+
+	switch ref.Provider {
+	case types.SecretsProvider_Vault:
+		if ref.Key == "" {
+			return ref, fmt.Errorf("%s of env variable %s must set the key of the vault secret to read", ValueFromKey, key)
+		}
+		if ref.PinnedVersion != "" {
+			if version, err := strconv.Atoi(ref.PinnedVersion); err != nil || version < 1 {
+				return ref, fmt.Errorf("pinned_version of env variable %s must be a vault secret version, such as 3", key)
+			}
+		}
+	case types.SecretsProvider_AWSSecretsManager:
+	case "":
+		return ref, fmt.Errorf("%s of env variable %s must set provider, either %s or %s", ValueFromKey, key, types.SecretsProvider_Vault, types.SecretsProvider_AWSSecretsManager)
+	default:
+		return ref, fmt.Errorf("%s of env variable %s has unknown provider %s, expected %s or %s", ValueFromKey, key, ref.Provider, types.SecretsProvider_Vault, types.SecretsProvider_AWSSecretsManager)
+	}
+
+	if ref.Path == "" {
+		return ref, fmt.Errorf("%s of env variable %s must set the path of the secret", ValueFromKey, key)
+	}
+
+	return ref, nil
+}
+
+// ValueFromNotResolvedError is returned for an env variable set with valueFrom which reached a parser that cannot read
+// secret stores
+func ValueFromNotResolvedError(key string) error {
+	return fmt.Errorf("env variable %s uses %s, which is only supported when porter.yaml is applied by Porter", key, ValueFromKey)
+}
+
+// SecretReferences are the env variables of a porter.yaml which are set with valueFrom
+type SecretReferences struct {
+	// App is the app's env, which every service is deployed with
+	App map[string]SecretReference
+	// Services is the container env of each service of a v1stack porter.yaml, by service name. The pre-deploy job is
+	// named release.
+	Services map[string]map[string]SecretReference
+	// Previews is the env of the preview overrides of a v2 porter.yaml
+	Previews map[string]SecretReference
+}
+
+// Empty returns true if no env variable is set with valueFrom
+func (r SecretReferences) Empty() bool {
+	if len(r.App) > 0 || len(r.Previews) > 0 {
+		return false
+	}
+	for _, refs := range r.Services {
+		if len(refs) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ForService returns the references a service of a v1stack porter.yaml is deployed with, which are the app's merged
+// with the service's own
+func (r SecretReferences) ForService(name string) map[string]SecretReference {
+	refs := make(map[string]SecretReference, len(r.App)+len(r.Services[name]))
+	for key, ref := range r.App {
+		refs[key] = ref
+	}
+	for key, ref := range r.Services[name] {
+		refs[key] = ref
+	}
+
+	return refs
+}
+
+// ExtractSecretReferences removes every env variable set with valueFrom from porterYaml and returns them, so that the
+// rest of porter.yaml can be parsed as usual. The app's env, the container env of each service and pre-deploy job of a
+// v1stack porter.yaml, and the env of the preview overrides of a v2 porter.yaml are supported, in either the map or
+// the list form. If no env variable is set with valueFrom, porterYaml is returned unchanged.
+func ExtractSecretReferences(porterYaml []byte) ([]byte, SecretReferences, error) {
+	var refs SecretReferences
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(porterYaml, &doc); err != nil {
+		return nil, refs, fmt.Errorf("error parsing porter.yaml: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return porterYaml, refs, nil
+	}
+	root := doc.Content[0]
+
+	var err error
+	if refs.App, err = extractEnv(mappingValue(root, "env")); err != nil {
+		return nil, refs, err
+	}
+
+	if previews := mappingValue(root, "previews"); previews != nil && previews.Kind == yaml.MappingNode {
+		if refs.Previews, err = extractEnv(mappingValue(previews, "env")); err != nil {
+			return nil, refs, err
+		}
+	}
+
+	services := map[string]*yaml.Node{}
+	for _, section := range []string{"services", "apps"} {
+		if node := mappingValue(root, section); node != nil && node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				services[node.Content[i].Value] = node.Content[i+1]
+			}
+		}
+	}
+	if release := mappingValue(root, "release"); release != nil {
+		services["release"] = release
+	}
+
+	for name, service := range services {
+		if service.Kind != yaml.MappingNode {
+			continue
+		}
+
+		var normal *yaml.Node
+		if config := mappingValue(service, "config"); config != nil && config.Kind == yaml.MappingNode {
+			if container := mappingValue(config, "container"); container != nil && container.Kind == yaml.MappingNode {
+				if env := mappingValue(container, "env"); env != nil && env.Kind == yaml.MappingNode {
+					normal = mappingValue(env, "normal")
+				}
+			}
+		}
+
+		serviceRefs, err := extractEnv(normal)
+		if err != nil {
+			return nil, refs, fmt.Errorf("service %s: %w", name, err)
+		}
+		if len(serviceRefs) > 0 {
+			if refs.Services == nil {
+				refs.Services = map[string]map[string]SecretReference{}
+			}
+			refs.Services[name] = serviceRefs
+		}
+	}
+
+	if refs.Empty() {
+		return porterYaml, refs, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, refs, fmt.Errorf("error writing porter.yaml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, refs, fmt.Errorf("error writing porter.yaml: %w", err)
+	}
+
+	return buf.Bytes(), refs, nil
+}
+
+// extractEnv removes the env variables set with valueFrom from an env section, which is either a map of env variables
+// or a list of env variable definitions
+func extractEnv(node *yaml.Node) (map[string]SecretReference, error) {
+	if node == nil {
+		return nil, nil
+	}
+
+	refs := map[string]SecretReference{}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		var kept []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+
+			valueFrom := mappingValue(value, ValueFromKey)
+			if value.Kind != yaml.MappingNode || valueFrom == nil {
+				kept = append(kept, node.Content[i], value)
+				continue
+			}
+			if len(value.Content) != 2 {
+				return nil, fmt.Errorf("env variable %s sets %s alongside other fields, only %s can be set", key, ValueFromKey, ValueFromKey)
+			}
+
+			ref, err := ParseSecretReference(key, valueFrom)
+			if err != nil {
+				return nil, err
+			}
+			refs[key] = ref
+		}
+		node.Content = kept
+	case yaml.SequenceNode:
+		var kept []*yaml.Node
+		for _, item := range node.Content {
+			valueFrom := mappingValue(item, ValueFromKey)
+			if item.Kind != yaml.MappingNode || valueFrom == nil {
+				kept = append(kept, item)
+				continue
+			}
+
+			key := ""
+			if keyNode := mappingValue(item, "key"); keyNode != nil {
+				key = keyNode.Value
+			}
+			if key == "" {
+				return nil, fmt.Errorf("env variable set with %s must have a key", ValueFromKey)
+			}
+			for _, field := range []string{"value", FromFileKey, "from"} {
+				if mappingValue(item, field) != nil {
+					return nil, fmt.Errorf("env variable %s sets both %s and %s, only one can be set", key, field, ValueFromKey)
+				}
+			}
+
+			ref, err := ParseSecretReference(key, valueFrom)
+			if err != nil {
+				return nil, err
+			}
+			refs[key] = ref
+		}
+		node.Content = kept
+	}
+
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	return refs, nil
+}
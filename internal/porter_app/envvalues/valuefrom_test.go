@@ -0,0 +1,161 @@
+package envvalues
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+func TestExtractSecretReferences_V1Stack(t *testing.T) {
+	porterYaml := `version: v1stack
+env:
+  PORT: "8080"
+  DB_PASSWORD:
+    valueFrom:
+      provider: vault
+      path: myapp/db
+      key: password
+      pinned_version: "3"
+services:
+  web:
+    type: web
+    config:
+      container:
+        env:
+          normal:
+            DEBUG: "false"
+            API_KEY:
+              valueFrom:
+                provider: aws_secrets_manager
+                path: myapp/api-key
+release:
+  run: npm run migrate
+  config:
+    container:
+      env:
+        normal:
+          MIGRATION_TOKEN:
+            valueFrom:
+              provider: vault
+              path: myapp/migrations
+              key: token
+`
+
+	got, refs, err := ExtractSecretReferences([]byte(porterYaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dbPassword := SecretReference{Provider: types.SecretsProvider_Vault, Path: "myapp/db", Key: "password", PinnedVersion: "3"}
+	apiKey := SecretReference{Provider: types.SecretsProvider_AWSSecretsManager, Path: "myapp/api-key"}
+	migrationToken := SecretReference{Provider: types.SecretsProvider_Vault, Path: "myapp/migrations", Key: "token"}
+
+	if !reflect.DeepEqual(refs.App, map[string]SecretReference{"DB_PASSWORD": dbPassword}) {
+		t.Errorf("unexpected app references %v", refs.App)
+	}
+	if !reflect.DeepEqual(refs.ForService("web"), map[string]SecretReference{"DB_PASSWORD": dbPassword, "API_KEY": apiKey}) {
+		t.Errorf("unexpected web references %v", refs.ForService("web"))
+	}
+	if !reflect.DeepEqual(refs.ForService("release"), map[string]SecretReference{"DB_PASSWORD": dbPassword, "MIGRATION_TOKEN": migrationToken}) {
+		t.Errorf("unexpected release references %v", refs.ForService("release"))
+	}
+
+	// the remaining porter.yaml must parse as a plain porter.yaml
+	var parsed struct {
+		Env      map[string]string `yaml:"env"`
+		Services map[string]struct {
+			Config struct {
+				Container struct {
+					Env struct {
+						Normal map[string]string `yaml:"normal"`
+					} `yaml:"env"`
+				} `yaml:"container"`
+			} `yaml:"config"`
+		} `yaml:"services"`
+	}
+	if err := yamlv2.Unmarshal(got, &parsed); err != nil {
+		t.Fatalf("error parsing extracted porter.yaml: %v\n%s", err, got)
+	}
+	if !reflect.DeepEqual(parsed.Env, map[string]string{"PORT": "8080"}) {
+		t.Errorf("unexpected env %v", parsed.Env)
+	}
+	if !reflect.DeepEqual(parsed.Services["web"].Config.Container.Env.Normal, map[string]string{"DEBUG": "false"}) {
+		t.Errorf("unexpected web env %v", parsed.Services["web"].Config.Container.Env.Normal)
+	}
+}
+
+func TestExtractSecretReferences_V2List(t *testing.T) {
+	porterYaml := `version: v2
+name: test-app
+env:
+  - key: PORT
+    value: "8080"
+  - key: DB_PASSWORD
+    valueFrom:
+      provider: vault
+      path: myapp/db
+      key: password
+previews:
+  env:
+    DB_PASSWORD:
+      valueFrom:
+        provider: vault
+        path: myapp/preview-db
+        key: password
+`
+
+	got, refs, err := ExtractSecretReferences([]byte(porterYaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(refs.App, map[string]SecretReference{"DB_PASSWORD": {Provider: types.SecretsProvider_Vault, Path: "myapp/db", Key: "password"}}) {
+		t.Errorf("unexpected app references %v", refs.App)
+	}
+	if !reflect.DeepEqual(refs.Previews, map[string]SecretReference{"DB_PASSWORD": {Provider: types.SecretsProvider_Vault, Path: "myapp/preview-db", Key: "password"}}) {
+		t.Errorf("unexpected preview references %v", refs.Previews)
+	}
+	if strings.Contains(string(got), ValueFromKey) || !strings.Contains(string(got), "PORT") {
+		t.Errorf("expected only the valueFrom entries to be removed, got\n%s", got)
+	}
+}
+
+func TestExtractSecretReferences_Unchanged(t *testing.T) {
+	porterYaml := []byte("env:\n  PORT: 8080 # a comment the server rejects later\n")
+
+	got, refs, err := ExtractSecretReferences(porterYaml)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !refs.Empty() || string(got) != string(porterYaml) {
+		t.Fatalf("expected porter.yaml to be unchanged, got %q", got)
+	}
+}
+
+func TestExtractSecretReferences_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{name: "no provider", yaml: "env:\n  V:\n    valueFrom:\n      path: a\n", wantErr: "must set provider"},
+		{name: "unknown provider", yaml: "env:\n  V:\n    valueFrom:\n      provider: gcp\n      path: a\n", wantErr: "unknown provider gcp"},
+		{name: "vault without key", yaml: "env:\n  V:\n    valueFrom:\n      provider: vault\n      path: a\n", wantErr: "must set the key"},
+		{name: "vault version is not a number", yaml: "env:\n  V:\n    valueFrom:\n      provider: vault\n      path: a\n      key: b\n      pinned_version: latest\n", wantErr: "must be a vault secret version"},
+		{name: "unknown field", yaml: "env:\n  V:\n    valueFrom:\n      provider: vault\n      path: a\n      key: b\n      version: 2\n", wantErr: "unknown field version"},
+		{name: "valueFrom alongside value", yaml: "env:\n  - key: V\n    value: x\n    valueFrom:\n      provider: vault\n      path: a\n      key: b\n", wantErr: "sets both value and valueFrom"},
+		{name: "valueFrom alongside other fields", yaml: "env:\n  V:\n    fromFile: a\n    valueFrom:\n      provider: vault\n      path: a\n      key: b\n", wantErr: "alongside other fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ExtractSecretReferences([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package helmimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"gorm.io/gorm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// LabelKey_ImportedRelease marks the helm storage secret of the revision a release was imported at
+const LabelKey_ImportedRelease = "porter.run/imported-release"
+
+// LabelImportedRelease labels the helm storage secret of the release's current revision with the porter app it was
+// imported as. Nothing is redeployed: helm does not copy the labels to the secrets of later revisions, so the label
+// only ever marks the revision the release was imported at.
+func LabelImportedRelease(ctx context.Context, clientset kubernetes.Interface, rel *release.Release, appName string) error {
+	secrets, err := clientset.CoreV1().Secrets(rel.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("owner=helm,name=%s,version=%d", rel.Name, rel.Version),
+	})
+	if err != nil {
+		return fmt.Errorf("error listing helm storage secrets: %w", err)
+	}
+	if len(secrets.Items) == 0 {
+		return fmt.Errorf("helm storage secret for revision %d of release %s not found", rel.Version, rel.Name)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{
+				porter_app.LabelKey_AppName: appName,
+				LabelKey_ImportedRelease:    "true",
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range secrets.Items {
+		_, err := clientset.CoreV1().Secrets(rel.Namespace).Patch(ctx, secret.Name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("error labelling helm storage secret %s: %w", secret.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// RecordUpgrade marks rel as the point an imported release was adopted by Porter, if it is the first revision Porter
+// deployed since the release was imported. Releases which were not imported are ignored.
+func RecordUpgrade(ctx context.Context, repo repository.HelmReleaseImportRepository, clusterID uint, rel *release.Release) error {
+	if rel == nil {
+		return nil
+	}
+
+	helmImport, err := repo.ReadHelmReleaseImport(ctx, clusterID, rel.Namespace, rel.Name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("error reading helm release import: %w", err)
+	}
+
+	if helmImport.AdoptedRevision != 0 || rel.Version <= helmImport.ImportedRevision {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	helmImport.AdoptedRevision = rel.Version
+	helmImport.AdoptedAt = &now
+
+	if _, err := repo.UpdateHelmReleaseImport(ctx, helmImport); err != nil {
+		return fmt.Errorf("error recording adoption of imported release: %w", err)
+	}
+
+	return nil
+}
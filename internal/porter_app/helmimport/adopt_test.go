@@ -0,0 +1,138 @@
+package helmimport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"gorm.io/gorm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeImportRepo struct {
+	imports map[string]*models.HelmReleaseImport
+	updates int
+}
+
+func (r *fakeImportRepo) CreateHelmReleaseImport(ctx context.Context, helmImport *models.HelmReleaseImport) (*models.HelmReleaseImport, error) {
+	r.imports[releaseKey(helmImport.Namespace, helmImport.ReleaseName)] = helmImport
+	return helmImport, nil
+}
+
+func (r *fakeImportRepo) ReadHelmReleaseImport(ctx context.Context, clusterID uint, namespace, releaseName string) (*models.HelmReleaseImport, error) {
+	helmImport, ok := r.imports[releaseKey(namespace, releaseName)]
+	if !ok || helmImport.ClusterID != clusterID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return helmImport, nil
+}
+
+func (r *fakeImportRepo) ListHelmReleaseImportsByClusterID(ctx context.Context, projectID, clusterID uint) ([]*models.HelmReleaseImport, error) {
+	var res []*models.HelmReleaseImport
+	for _, helmImport := range r.imports {
+		if helmImport.ProjectID == projectID && helmImport.ClusterID == clusterID {
+			res = append(res, helmImport)
+		}
+	}
+
+	return res, nil
+}
+
+func (r *fakeImportRepo) UpdateHelmReleaseImport(ctx context.Context, helmImport *models.HelmReleaseImport) (*models.HelmReleaseImport, error) {
+	r.updates++
+	r.imports[releaseKey(helmImport.Namespace, helmImport.ReleaseName)] = helmImport
+	return helmImport, nil
+}
+
+func TestRecordUpgrade(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeImportRepo{imports: map[string]*models.HelmReleaseImport{
+		"default/api": {ClusterID: 1, Namespace: "default", ReleaseName: "api", ImportedRevision: 3},
+	}}
+
+	// a release which was not imported is ignored
+	if err := RecordUpgrade(ctx, repo, 1, &release.Release{Name: "other", Namespace: "default", Version: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the imported revision is not an upgrade by Porter
+	if err := RecordUpgrade(ctx, repo, 1, &release.Release{Name: "api", Namespace: "default", Version: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updates != 0 {
+		t.Fatalf("expected no updates, got %d", repo.updates)
+	}
+
+	before := time.Now()
+	if err := RecordUpgrade(ctx, repo, 1, &release.Release{Name: "api", Namespace: "default", Version: 4}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	helmImport := repo.imports["default/api"]
+	if helmImport.AdoptedRevision != 4 || helmImport.AdoptedAt == nil || helmImport.AdoptedAt.Before(before.Add(-time.Second)) {
+		t.Fatalf("expected revision 4 to be the adoption point, got %d at %v", helmImport.AdoptedRevision, helmImport.AdoptedAt)
+	}
+
+	// later upgrades do not move the adoption point
+	if err := RecordUpgrade(ctx, repo, 1, &release.Release{Name: "api", Namespace: "default", Version: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if helmImport.AdoptedRevision != 4 || repo.updates != 1 {
+		t.Fatalf("expected adoption point to stay at revision 4, got %d after %d updates", helmImport.AdoptedRevision, repo.updates)
+	}
+}
+
+func TestLabelImportedRelease(t *testing.T) {
+	ctx := context.Background()
+
+	storageSecret := func(name string, version string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					"owner":   "helm",
+					"name":    "api",
+					"version": version,
+				},
+			},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		storageSecret("sh.helm.release.v1.api.v2", "2"),
+		storageSecret("sh.helm.release.v1.api.v3", "3"),
+	)
+
+	rel := &release.Release{Name: "api", Namespace: "default", Version: 3}
+	if err := LabelImportedRelease(ctx, clientset, rel, "imported-api"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	current, err := clientset.CoreV1().Secrets("default").Get(ctx, "sh.helm.release.v1.api.v3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Labels[porter_app.LabelKey_AppName] != "imported-api" || current.Labels[LabelKey_ImportedRelease] != "true" {
+		t.Fatalf("expected imported revision to be labelled, got %v", current.Labels)
+	}
+	if current.Labels["owner"] != "helm" {
+		t.Fatalf("expected helm labels to be kept, got %v", current.Labels)
+	}
+
+	previous, err := clientset.CoreV1().Secrets("default").Get(ctx, "sh.helm.release.v1.api.v2", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := previous.Labels[LabelKey_ImportedRelease]; ok {
+		t.Fatalf("expected earlier revisions not to be labelled")
+	}
+
+	if err := LabelImportedRelease(ctx, clientset, &release.Release{Name: "missing", Namespace: "default", Version: 1}, "missing"); err == nil {
+		t.Fatalf("expected an error when the storage secret does not exist")
+	}
+}
@@ -0,0 +1,230 @@
+package helmimport
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+const (
+	// umbrellaChartName is the chart Porter deploys for apps with several services, with one dependency on an
+	// application chart per service
+	umbrellaChartName = "umbrella"
+	// stackNamespacePrefix is the prefix of the namespaces Porter creates for its own apps
+	stackNamespacePrefix = "porter-stack-"
+	// secretEnvPrefix marks an environment variable whose value is stored in a secret
+	secretEnvPrefix = "PORTERSECRET"
+)
+
+// applicationCharts are Porter's application charts, keyed by the suffix of their alias in the umbrella chart
+var applicationCharts = map[string]string{
+	"-web": "web",
+	"-wkr": "worker",
+	"-job": "job",
+}
+
+func isApplicationChart(name string) bool {
+	for _, chartName := range applicationCharts {
+		if chartName == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Classify describes a release and whether it can be imported as a porter app. Releases of Porter's application
+// charts have their services inferred from their values, releases of other charts are imported as external charts, and
+// releases whose chart structure is unknown are reported with the reasons they cannot be imported.
+func Classify(rel *release.Release) types.ImportableRelease {
+	res := types.ImportableRelease{
+		Name:      rel.Name,
+		Namespace: rel.Namespace,
+		Revision:  rel.Version,
+		Status:    string(release.StatusUnknown),
+	}
+	if rel.Info != nil {
+		res.Status = rel.Info.Status.String()
+	}
+
+	if rel.Chart == nil || rel.Chart.Metadata == nil {
+		res.Reasons = append(res.Reasons, "release has no chart metadata, so its chart structure is unknown")
+		return res
+	}
+
+	metadata := rel.Chart.Metadata
+	res.Chart = metadata.Name
+	res.ChartVersion = metadata.Version
+	res.AppVersion = metadata.AppVersion
+
+	if res.Status != string(release.StatusDeployed) {
+		res.Reasons = append(res.Reasons, fmt.Sprintf("release status is %s, only deployed releases can be imported", res.Status))
+	}
+
+	if metadata.Type == "library" {
+		res.Reasons = append(res.Reasons, fmt.Sprintf("chart %s is a library chart, which does not deploy any resources", metadata.Name))
+		return res
+	}
+
+	var mode types.HelmReleaseImportMode
+
+	switch {
+	case isApplicationChart(metadata.Name):
+		mode = types.HelmReleaseImportMode_Application
+		res.Services = []types.ImportedService{
+			serviceFromValues(rel.Name, metadata.Name, rel.Config, nil),
+		}
+	case metadata.Name == umbrellaChartName:
+		mode = types.HelmReleaseImportMode_Application
+
+		services, reasons := umbrellaServices(rel)
+		res.Services = services
+		res.Reasons = append(res.Reasons, reasons...)
+	default:
+		mode = types.HelmReleaseImportMode_ExternalChart
+
+		// helm does not store subcharts with a release, so a chart with dependencies may have no templates of its own
+		if len(rel.Chart.Templates) == 0 && len(metadata.Dependencies) == 0 {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("chart %s has no templates or dependencies, so its chart structure is unknown", metadata.Name))
+		}
+	}
+
+	if len(res.Reasons) == 0 {
+		res.Importable = true
+		res.Mode = mode
+	}
+
+	return res
+}
+
+// umbrellaServices infers a service from each dependency of an umbrella chart release, or returns the reasons the
+// dependencies are not Porter's application charts
+func umbrellaServices(rel *release.Release) ([]types.ImportedService, []string) {
+	var services []types.ImportedService
+	var reasons []string
+
+	if len(rel.Chart.Metadata.Dependencies) == 0 {
+		return nil, []string{"umbrella chart has no dependencies, so its services are unknown"}
+	}
+
+	globalImage := nestedMap(rel.Config, "global", "image")
+
+	for _, dep := range rel.Chart.Metadata.Dependencies {
+		alias := dep.Alias
+		if alias == "" {
+			alias = dep.Name
+		}
+
+		var serviceName, serviceType string
+		for suffix, chartName := range applicationCharts {
+			if strings.HasSuffix(alias, suffix) {
+				serviceName = strings.TrimSuffix(alias, suffix)
+				serviceType = chartName
+			}
+		}
+
+		// the dependency name can be the alias because of https://github.com/helm/helm/issues/9214
+		if serviceName == "" || (dep.Name != serviceType && dep.Name != alias) {
+			reasons = append(reasons, fmt.Sprintf("dependency %s is not one of Porter's application charts", alias))
+			continue
+		}
+
+		values, _ := rel.Config[alias].(map[string]interface{})
+		services = append(services, serviceFromValues(serviceName, serviceType, values, globalImage))
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+
+	return services, reasons
+}
+
+// serviceFromValues reads the image and environment of a service from the values of an application chart
+func serviceFromValues(name, serviceType string, values map[string]interface{}, globalImage map[string]interface{}) types.ImportedService {
+	service := types.ImportedService{
+		Name: name,
+		Type: serviceType,
+	}
+
+	image := nestedMap(values, "image")
+	if image == nil {
+		image = globalImage
+	}
+	service.ImageRepository, _ = image["repository"].(string)
+	service.ImageTag = fmt.Sprint(valueOrEmpty(image["tag"]))
+
+	for key, val := range nestedMap(values, "container", "env", "normal") {
+		str := fmt.Sprint(valueOrEmpty(val))
+		if strings.HasPrefix(str, secretEnvPrefix) {
+			service.SecretEnvKeys = append(service.SecretEnvKeys, key)
+			continue
+		}
+
+		if service.Env == nil {
+			service.Env = make(map[string]string)
+		}
+		service.Env[key] = str
+	}
+	sort.Strings(service.SecretEnvKeys)
+
+	return service
+}
+
+func valueOrEmpty(val interface{}) interface{} {
+	if val == nil {
+		return ""
+	}
+
+	return val
+}
+
+// nestedMap returns the map at the path of fields in obj, or nil if there is none
+func nestedMap(obj map[string]interface{}, fields ...string) map[string]interface{} {
+	curr := obj
+	for _, field := range fields {
+		next, ok := curr[field].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		curr = next
+	}
+
+	return curr
+}
+
+// ManagedReleases are the releases in a cluster which Porter already manages, and so are not listed for import
+type ManagedReleases struct {
+	imported map[string]bool
+}
+
+// NewManagedReleases returns the releases managed by Porter given the imports in a cluster
+func NewManagedReleases(imports []*models.HelmReleaseImport) ManagedReleases {
+	m := ManagedReleases{
+		imported: make(map[string]bool, len(imports)),
+	}
+
+	for _, helmImport := range imports {
+		m.imported[releaseKey(helmImport.Namespace, helmImport.ReleaseName)] = true
+	}
+
+	return m
+}
+
+// Manages returns true if the release was already imported, or was deployed by Porter into one of its app namespaces
+func (m ManagedReleases) Manages(rel *release.Release) bool {
+	if strings.HasPrefix(rel.Namespace, stackNamespacePrefix) {
+		return true
+	}
+
+	return m.imported[releaseKey(rel.Namespace, rel.Name)]
+}
+
+func releaseKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
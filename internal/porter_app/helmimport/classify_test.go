@@ -0,0 +1,222 @@
+package helmimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+func deployedRelease(name, namespace string, metadata *chart.Metadata, values map[string]interface{}) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: namespace,
+		Version:   3,
+		Info:      &release.Info{Status: release.StatusDeployed},
+		Chart: &chart.Chart{
+			Metadata:  metadata,
+			Templates: []*chart.File{{Name: "templates/deployment.yaml"}},
+		},
+		Config: values,
+	}
+}
+
+func hasReason(res types.ImportableRelease, substr string) bool {
+	for _, reason := range res.Reasons {
+		if strings.Contains(reason, substr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestClassify_ApplicationChart(t *testing.T) {
+	rel := deployedRelease("api", "default", &chart.Metadata{Name: "web", Version: "0.50.0"}, map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "registry.example.com/api",
+			"tag":        "abc123",
+		},
+		"container": map[string]interface{}{
+			"env": map[string]interface{}{
+				"normal": map[string]interface{}{
+					"PORT":         "8080",
+					"DATABASE_URL": "PORTERSECRET_api.v1",
+				},
+			},
+		},
+	})
+
+	res := Classify(rel)
+
+	if !res.Importable || res.Mode != types.HelmReleaseImportMode_Application {
+		t.Fatalf("expected importable application release, got %+v", res)
+	}
+	if len(res.Services) != 1 {
+		t.Fatalf("expected 1 service, got %d", len(res.Services))
+	}
+
+	service := res.Services[0]
+	if service.Name != "api" || service.Type != "web" {
+		t.Fatalf("unexpected service %+v", service)
+	}
+	if service.ImageRepository != "registry.example.com/api" || service.ImageTag != "abc123" {
+		t.Fatalf("unexpected image %s:%s", service.ImageRepository, service.ImageTag)
+	}
+	if service.Env["PORT"] != "8080" || len(service.Env) != 1 {
+		t.Fatalf("expected only plain env to be returned, got %v", service.Env)
+	}
+	if len(service.SecretEnvKeys) != 1 || service.SecretEnvKeys[0] != "DATABASE_URL" {
+		t.Fatalf("expected secret env key, got %v", service.SecretEnvKeys)
+	}
+}
+
+func TestClassify_UmbrellaChart(t *testing.T) {
+	rel := deployedRelease("shop", "default", &chart.Metadata{
+		Name:    "umbrella",
+		Version: "0.96.0",
+		Dependencies: []*chart.Dependency{
+			{Name: "web", Alias: "frontend-web"},
+			// the dependency name can be the alias because of https://github.com/helm/helm/issues/9214
+			{Name: "queue-wkr", Alias: "queue-wkr"},
+		},
+	}, map[string]interface{}{
+		"global": map[string]interface{}{
+			"image": map[string]interface{}{"repository": "registry.example.com/shop", "tag": "v2"},
+		},
+		"frontend-web": map[string]interface{}{
+			"container": map[string]interface{}{
+				"env": map[string]interface{}{"normal": map[string]interface{}{"MODE": "frontend"}},
+			},
+		},
+	})
+	rel.Chart.Templates = nil
+
+	res := Classify(rel)
+
+	if !res.Importable || res.Mode != types.HelmReleaseImportMode_Application {
+		t.Fatalf("expected importable application release, got %+v", res)
+	}
+	if len(res.Services) != 2 {
+		t.Fatalf("expected 2 services, got %+v", res.Services)
+	}
+	if res.Services[0].Name != "frontend" || res.Services[0].Type != "web" || res.Services[0].Env["MODE"] != "frontend" {
+		t.Fatalf("unexpected service %+v", res.Services[0])
+	}
+	if res.Services[1].Name != "queue" || res.Services[1].Type != "worker" {
+		t.Fatalf("unexpected service %+v", res.Services[1])
+	}
+	if res.Services[1].ImageRepository != "registry.example.com/shop" || res.Services[1].ImageTag != "v2" {
+		t.Fatalf("expected the global image to be used, got %+v", res.Services[1])
+	}
+}
+
+func TestClassify_UmbrellaChartWithUnknownDependency(t *testing.T) {
+	rel := deployedRelease("shop", "default", &chart.Metadata{
+		Name: "umbrella",
+		Dependencies: []*chart.Dependency{
+			{Name: "web", Alias: "frontend-web"},
+			{Name: "redis", Alias: "cache"},
+		},
+	}, nil)
+
+	res := Classify(rel)
+
+	if res.Importable || res.Mode != "" {
+		t.Fatalf("expected release not to be importable, got %+v", res)
+	}
+	if !hasReason(res, "dependency cache is not one of Porter's application charts") {
+		t.Fatalf("unexpected reasons %v", res.Reasons)
+	}
+}
+
+func TestClassify_ExternalChart(t *testing.T) {
+	res := Classify(deployedRelease("cache", "data", &chart.Metadata{Name: "redis", Version: "17.0.0", AppVersion: "7.0"}, nil))
+
+	if !res.Importable || res.Mode != types.HelmReleaseImportMode_ExternalChart {
+		t.Fatalf("expected importable external chart release, got %+v", res)
+	}
+	if res.Chart != "redis" || res.ChartVersion != "17.0.0" || res.AppVersion != "7.0" || res.Revision != 3 {
+		t.Fatalf("unexpected release description %+v", res)
+	}
+	if len(res.Services) != 0 {
+		t.Fatalf("expected no services to be inferred for an external chart, got %+v", res.Services)
+	}
+}
+
+func TestClassify_NotImportable(t *testing.T) {
+	tests := []struct {
+		name   string
+		rel    *release.Release
+		reason string
+	}{
+		{
+			name:   "no chart metadata",
+			rel:    &release.Release{Name: "a", Namespace: "default", Info: &release.Info{Status: release.StatusDeployed}},
+			reason: "no chart metadata",
+		},
+		{
+			name: "failed release",
+			rel: func() *release.Release {
+				rel := deployedRelease("a", "default", &chart.Metadata{Name: "redis"}, nil)
+				rel.Info.Status = release.StatusFailed
+				return rel
+			}(),
+			reason: "release status is failed",
+		},
+		{
+			name:   "library chart",
+			rel:    deployedRelease("a", "default", &chart.Metadata{Name: "common", Type: "library"}, nil),
+			reason: "library chart",
+		},
+		{
+			name: "no templates",
+			rel: func() *release.Release {
+				rel := deployedRelease("a", "default", &chart.Metadata{Name: "empty"}, nil)
+				rel.Chart.Templates = nil
+				return rel
+			}(),
+			reason: "no templates or dependencies",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Classify(tt.rel)
+
+			if res.Importable {
+				t.Fatalf("expected release not to be importable")
+			}
+			if !hasReason(res, tt.reason) {
+				t.Fatalf("expected a reason containing %q, got %v", tt.reason, res.Reasons)
+			}
+		})
+	}
+}
+
+func TestManagedReleases(t *testing.T) {
+	managed := NewManagedReleases([]*models.HelmReleaseImport{
+		{Namespace: "default", ReleaseName: "imported"},
+	})
+
+	tests := []struct {
+		namespace string
+		name      string
+		want      bool
+	}{
+		{namespace: "default", name: "imported", want: true},
+		{namespace: "other", name: "imported", want: false},
+		{namespace: "porter-stack-api", name: "api", want: true},
+		{namespace: "default", name: "unmanaged", want: false},
+	}
+
+	for _, tt := range tests {
+		got := managed.Manages(&release.Release{Name: tt.name, Namespace: tt.namespace})
+		if got != tt.want {
+			t.Errorf("Manages(%s/%s) = %t, want %t", tt.namespace, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package helmimport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+// ClusterReleases finds the porter app which owns each helm release managed by Porter in a cluster
+type ClusterReleases struct {
+	// importedBy maps imported releases to the ID of the app they were imported as
+	importedBy map[string]uint
+	appsByID   map[uint]string
+	appsByName map[string]bool
+}
+
+// NewClusterReleases returns the releases managed by Porter given the imports and the porter apps in a cluster
+func NewClusterReleases(imports []*models.HelmReleaseImport, apps []*models.PorterApp) ClusterReleases {
+	c := ClusterReleases{
+		importedBy: make(map[string]uint, len(imports)),
+		appsByID:   make(map[uint]string, len(apps)),
+		appsByName: make(map[string]bool, len(apps)),
+	}
+
+	for _, helmImport := range imports {
+		c.importedBy[releaseKey(helmImport.Namespace, helmImport.ReleaseName)] = helmImport.PorterAppID
+	}
+
+	for _, app := range apps {
+		c.appsByID[app.ID] = app.Name
+		c.appsByName[app.Name] = true
+	}
+
+	return c
+}
+
+// Classify returns the release as it is listed for the cluster, and false if Porter does not manage it. Porter manages
+// the releases it imported and every release in the namespace of one of its apps. A release in the namespace of an
+// app which no longer exists is orphaned.
+func (c ClusterReleases) Classify(rel *release.Release) (types.ClusterRelease, bool) {
+	res := types.ClusterRelease{
+		Name:      rel.Name,
+		Namespace: rel.Namespace,
+		Revision:  rel.Version,
+	}
+
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		res.Chart = rel.Chart.Metadata.Name
+		res.ChartVersion = rel.Chart.Metadata.Version
+	}
+
+	if rel.Info != nil {
+		res.Status = rel.Info.Status.String()
+
+		if !rel.Info.LastDeployed.IsZero() {
+			lastDeployed := rel.Info.LastDeployed.Time.UTC()
+			res.LastDeployed = &lastDeployed
+		}
+	}
+
+	if appID, ok := c.importedBy[releaseKey(rel.Namespace, rel.Name)]; ok {
+		res.Kind = types.ClusterReleaseKind_App
+		appName, ok := c.appsByID[appID]
+		res.App, res.Orphaned = appName, !ok
+		return res, true
+	}
+
+	if !strings.HasPrefix(rel.Namespace, stackNamespacePrefix) {
+		return res, false
+	}
+
+	appName := strings.TrimPrefix(rel.Namespace, stackNamespacePrefix)
+	switch rel.Name {
+	case appName:
+		res.Kind = types.ClusterReleaseKind_App
+	case fmt.Sprintf("%s-r", appName):
+		res.Kind = types.ClusterReleaseKind_PreDeploy
+	default:
+		res.Kind = types.ClusterReleaseKind_Addon
+	}
+
+	if c.appsByName[appName] {
+		res.App = appName
+	} else {
+		res.Orphaned = true
+	}
+
+	return res, true
+}
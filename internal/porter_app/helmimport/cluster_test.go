@@ -0,0 +1,59 @@
+package helmimport
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"gorm.io/gorm"
+)
+
+func TestClusterReleases_Classify(t *testing.T) {
+	apps := []*models.PorterApp{
+		{Model: gorm.Model{ID: 1}, Name: "shop"},
+		{Model: gorm.Model{ID: 2}, Name: "api"},
+	}
+	imports := []*models.HelmReleaseImport{
+		{Namespace: "default", ReleaseName: "api", PorterAppID: 2},
+		{Namespace: "default", ReleaseName: "legacy", PorterAppID: 3},
+	}
+	c := NewClusterReleases(imports, apps)
+
+	tests := []struct {
+		name         string
+		namespace    string
+		wantManaged  bool
+		wantKind     types.ClusterReleaseKind
+		wantApp      string
+		wantOrphaned bool
+	}{
+		{name: "shop", namespace: "porter-stack-shop", wantManaged: true, wantKind: types.ClusterReleaseKind_App, wantApp: "shop"},
+		{name: "shop-r", namespace: "porter-stack-shop", wantManaged: true, wantKind: types.ClusterReleaseKind_PreDeploy, wantApp: "shop"},
+		{name: "redis", namespace: "porter-stack-shop", wantManaged: true, wantKind: types.ClusterReleaseKind_Addon, wantApp: "shop"},
+		{name: "blog", namespace: "porter-stack-blog", wantManaged: true, wantKind: types.ClusterReleaseKind_App, wantOrphaned: true},
+		{name: "api", namespace: "default", wantManaged: true, wantKind: types.ClusterReleaseKind_App, wantApp: "api"},
+		{name: "legacy", namespace: "default", wantManaged: true, wantKind: types.ClusterReleaseKind_App, wantOrphaned: true},
+		{name: "ingress-nginx", namespace: "ingress-nginx"},
+	}
+
+	for _, tt := range tests {
+		rel := deployedRelease(tt.name, tt.namespace, &chart.Metadata{Name: "web", Version: "0.50.0"}, nil)
+
+		res, managed := c.Classify(rel)
+		if managed != tt.wantManaged {
+			t.Errorf("%s/%s: expected managed to be %t", tt.namespace, tt.name, tt.wantManaged)
+			continue
+		}
+		if !managed {
+			continue
+		}
+
+		if res.Kind != tt.wantKind || res.App != tt.wantApp || res.Orphaned != tt.wantOrphaned {
+			t.Errorf("%s/%s: expected kind %s, app %q and orphaned %t, got %s, %q and %t", tt.namespace, tt.name, tt.wantKind, tt.wantApp, tt.wantOrphaned, res.Kind, res.App, res.Orphaned)
+		}
+		if res.Chart != "web" || res.ChartVersion != "0.50.0" || res.Revision != 3 || res.Status != "deployed" {
+			t.Errorf("%s/%s: unexpected release %+v", tt.namespace, tt.name, res)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package inactivity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+// PolicyStore lists the policies to evaluate
+type PolicyStore interface {
+	ListEnabledInactivityPolicies(ctx context.Context) ([]*models.InactivityPolicy, error)
+}
+
+// AppLister lists the apps of a project
+type AppLister interface {
+	ListPorterApps(ctx context.Context, projectID uint) ([]*models.PorterApp, error)
+}
+
+// EventStore reads the events which schedules are computed from, and records the steps taken on apps
+type EventStore interface {
+	EventReader
+	CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error
+}
+
+// ClusterSource connects to the clusters that apps run on
+type ClusterSource interface {
+	// Connect returns the apps on a cluster, or an error if the cluster is unreachable
+	Connect(ctx context.Context, projectID, clusterID uint) (ClusterApps, error)
+}
+
+// ClusterApps reads the traffic of and acts on the apps of a single cluster
+type ClusterApps interface {
+	// DailyRequests returns the number of requests an app received within the last day, or false if the cluster has no
+	// metrics to read them from
+	DailyRequests(ctx context.Context, appName string) (float64, bool, error)
+	// Pause scales the workloads of an app down until it is next deployed
+	Pause(ctx context.Context, appName string) error
+	// Delete deletes an app
+	Delete(ctx context.Context, appName string) error
+}
+
+// Notifier sends the steps taken on apps to their project
+type Notifier interface {
+	Notify(ctx context.Context, projectID uint, event types.PorterAppInactivityEventMetadata) error
+}
+
+// Options configure how often policies are evaluated. Zero values use the defaults.
+type Options struct {
+	// Interval is the time between evaluations of every policy. Defaults to 1h
+	Interval time.Duration
+	// ClusterTimeout bounds the time spent acting on the apps of a single cluster in each evaluation, so that an
+	// unreachable cluster cannot stall the others. Defaults to 2m
+	ClusterTimeout time.Duration
+	// Logger receives a record of skipped clusters, paused and deleted apps and failed notifications. Optional
+	Logger *logger.Logger
+}
+
+// Cleaner periodically pauses the apps which have been idle for longer than the inactivity policy of their project
+// allows, and deletes the apps which stayed paused for the policy's grace period
+type Cleaner struct {
+	policies PolicyStore
+	apps     AppLister
+	events   EventStore
+	clusters ClusterSource
+	notifier Notifier
+	opts     Options
+	log      worker.Logger
+}
+
+// NewCleaner returns a Cleaner with the given options
+func NewCleaner(policies PolicyStore, apps AppLister, events EventStore, clusters ClusterSource, notifier Notifier, opts Options) *Cleaner {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Hour
+	}
+	if opts.ClusterTimeout <= 0 {
+		opts.ClusterTimeout = 2 * time.Minute
+	}
+
+	return &Cleaner{
+		policies: policies,
+		apps:     apps,
+		events:   events,
+		clusters: clusters,
+		notifier: notifier,
+		opts:     opts,
+		log:      worker.NewLogger(opts.Logger),
+	}
+}
+
+// Run evaluates every enabled policy once per interval until ctx is cancelled
+func (c *Cleaner) Run(ctx context.Context) error {
+	return worker.Run(ctx, c.opts.Interval, c.log, "error evaluating inactivity policies", c.evaluate)
+}
+
+// dueAction is an action which is due on an app
+type dueAction struct {
+	policy   *models.InactivityPolicy
+	app      *models.PorterApp
+	schedule Schedule
+	action   types.InactivityAction
+}
+
+// evaluate takes every action which is due at now, returning once every cluster has been acted on or has timed out
+func (c *Cleaner) evaluate(ctx context.Context, now time.Time) error {
+	policies, err := c.policies.ListEnabledInactivityPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing enabled inactivity policies: %w", err)
+	}
+
+	clusters := make(map[worker.ClusterKey][]dueAction)
+	for _, policy := range policies {
+		apps, err := c.apps.ListPorterApps(ctx, policy.ProjectID)
+		if err != nil {
+			c.log.WithLevel(zerolog.WarnLevel).Err(err).Uint("project_id", policy.ProjectID).Msg("error listing apps for inactivity policy")
+			continue
+		}
+
+		for _, app := range apps {
+			if app.InactivityCleanupDisabled || !Matches(policy, app) {
+				continue
+			}
+
+			s, err := ScheduleFor(ctx, c.events, policy, app)
+			if err != nil {
+				c.log.App(zerolog.WarnLevel, app).Err(err).Msg("error computing inactivity schedule")
+				continue
+			}
+
+			action := s.Due(now)
+			if action == "" {
+				continue
+			}
+
+			ck := worker.ClusterKey{ProjectID: app.ProjectID, ClusterID: app.ClusterID}
+			clusters[ck] = append(clusters[ck], dueAction{policy: policy, app: app, schedule: s, action: action})
+		}
+	}
+
+	worker.EachCluster(ctx, clusters, worker.ClusterOptions{
+		Timeout: c.opts.ClusterTimeout,
+		Action:  "acting on idle apps",
+		Logger:  c.log,
+	}, func(ctx context.Context, ck worker.ClusterKey, actions []dueAction) {
+		c.actOnCluster(ctx, ck, actions, now)
+	})
+
+	return nil
+}
+
+func (c *Cleaner) actOnCluster(ctx context.Context, ck worker.ClusterKey, actions []dueAction, now time.Time) {
+	cluster, err := c.clusters.Connect(ctx, ck.ProjectID, ck.ClusterID)
+	if err != nil {
+		c.log.Cluster(zerolog.WarnLevel, ck).Err(err).Msg("cluster is unreachable, skipping its idle apps until the next evaluation")
+		return
+	}
+
+	for _, due := range actions {
+		if ctx.Err() != nil {
+			return
+		}
+
+		switch due.action {
+		case types.InactivityAction_Paused:
+			c.pause(ctx, cluster, due, now)
+		case types.InactivityAction_Deleted:
+			c.delete(ctx, cluster, due, now)
+		}
+	}
+}
+
+// pause scales down an app which has not been deployed for the policy's idle days, unless its traffic shows it is in use
+func (c *Cleaner) pause(ctx context.Context, cluster ClusterApps, due dueAction, now time.Time) {
+	requests, known, err := cluster.DailyRequests(ctx, due.app.Name)
+	if err != nil {
+		// an app is only paused once it is known to be idle, so it is checked again in the next evaluation
+		c.log.App(zerolog.WarnLevel, due.app).Err(err).Msg("error reading traffic of idle app, not pausing it")
+		return
+	}
+	if known && requests > float64(due.policy.MaxDailyRequests) {
+		c.log.App(zerolog.InfoLevel, due.app).Float64("daily_requests", requests).Msg("app has not been deployed within the idle days but is receiving traffic, not pausing it")
+		return
+	}
+
+	if err := cluster.Pause(ctx, due.app.Name); err != nil {
+		c.log.App(zerolog.ErrorLevel, due.app).Err(err).Msg("error pausing idle app")
+		return
+	}
+
+	deleteAt := now.Add(time.Duration(due.policy.GraceDays) * day)
+	event := types.PorterAppInactivityEventMetadata{
+		Action:         types.InactivityAction_Paused,
+		AppName:        due.app.Name,
+		LastDeployedAt: due.schedule.LastDeployedAt,
+		IdleDays:       due.policy.IdleDays,
+		DeleteAt:       &deleteAt,
+		At:             now,
+	}
+	if known {
+		event.DailyRequests = &requests
+	}
+
+	c.record(ctx, due, event)
+}
+
+// delete deletes an app which stayed paused for the policy's grace period
+func (c *Cleaner) delete(ctx context.Context, cluster ClusterApps, due dueAction, now time.Time) {
+	if err := cluster.Delete(ctx, due.app.Name); err != nil {
+		c.log.App(zerolog.ErrorLevel, due.app).Err(err).Msg("error deleting idle app")
+		return
+	}
+
+	c.record(ctx, due, types.PorterAppInactivityEventMetadata{
+		Action:         types.InactivityAction_Deleted,
+		AppName:        due.app.Name,
+		LastDeployedAt: due.schedule.LastDeployedAt,
+		IdleDays:       due.policy.IdleDays,
+		At:             now,
+	})
+}
+
+// record adds a step taken on an app to its activity feed and notifies its project. The event is what marks the step
+// as taken, so an app whose event cannot be recorded has the step retried in the next evaluation.
+func (c *Cleaner) record(ctx context.Context, due dueAction, event types.PorterAppInactivityEventMetadata) {
+	app := due.app
+
+	metadata, err := inactivityEventMetadata(event)
+	if err != nil {
+		c.log.App(zerolog.ErrorLevel, app).Err(err).Msg("error encoding inactivity event")
+		return
+	}
+
+	if err := c.events.CreateEvent(ctx, &models.PorterAppEvent{
+		ID:                 uuid.New(),
+		Type:               string(types.PorterAppEventType_Inactivity),
+		Status:             string(types.PorterAppEventStatus_Success),
+		PorterAppID:        app.ID,
+		DeploymentTargetID: due.schedule.DeploymentTargetID,
+		Metadata:           metadata,
+	}); err != nil {
+		c.log.App(zerolog.ErrorLevel, app).Err(err).Str("action", string(event.Action)).Msg("error recording inactivity event")
+		return
+	}
+
+	c.log.App(zerolog.InfoLevel, app).Str("action", string(event.Action)).Msg("inactivity policy acted on idle app")
+
+	if c.notifier == nil {
+		return
+	}
+
+	if err := c.notifier.Notify(ctx, app.ProjectID, event); err != nil {
+		c.log.App(zerolog.ErrorLevel, app).Err(err).Msg("error sending inactivity notification")
+	}
+}
+
+func inactivityEventMetadata(event types.PorterAppInactivityEventMetadata) (models.JSONB, error) {
+	by, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := models.JSONB{}
+	if err := json.Unmarshal(by, &metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,303 @@
+package inactivity
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker/workertest"
+	"gorm.io/gorm"
+)
+
+type fakePolicyStore []*models.InactivityPolicy
+
+func (s fakePolicyStore) ListEnabledInactivityPolicies(ctx context.Context) ([]*models.InactivityPolicy, error) {
+	return s, nil
+}
+
+type fakeAppLister map[uint][]*models.PorterApp
+
+func (l fakeAppLister) ListPorterApps(ctx context.Context, projectID uint) ([]*models.PorterApp, error) {
+	return l[projectID], nil
+}
+
+// fakeEventStore records events as created at its current time
+type fakeEventStore struct {
+	mu     sync.Mutex
+	now    time.Time
+	events []*models.PorterAppEvent
+}
+
+func (s *fakeEventStore) ReadLatestEventByType(ctx context.Context, porterAppID uint, eventType string) (*models.PorterAppEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest *models.PorterAppEvent
+	for _, event := range s.events {
+		if event.PorterAppID == porterAppID && event.Type == eventType && (latest == nil || event.CreatedAt.After(latest.CreatedAt)) {
+			latest = event
+		}
+	}
+	if latest == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return latest, nil
+}
+
+func (s *fakeEventStore) CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	appEvent.CreatedAt = s.now
+	s.events = append(s.events, appEvent)
+	return nil
+}
+
+func (s *fakeEventStore) ofType(eventType types.PorterAppEventType) []*models.PorterAppEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*models.PorterAppEvent
+	for _, event := range s.events {
+		if event.Type == string(eventType) {
+			res = append(res, event)
+		}
+	}
+
+	return res
+}
+
+// fakeClusterApps serves the traffic of the apps of a single cluster and records the actions taken on them
+type fakeClusterApps struct {
+	workertest.Cluster
+
+	// requests is the daily requests of each app. Apps which are not set have unknown traffic
+	requests   map[string]float64
+	requestErr error
+	paused     []string
+	deleted    []string
+}
+
+func (c *fakeClusterApps) DailyRequests(ctx context.Context, appName string) (float64, bool, error) {
+	if c.requestErr != nil {
+		return 0, false, c.requestErr
+	}
+
+	requests, ok := c.requests[appName]
+	return requests, ok, nil
+}
+
+func (c *fakeClusterApps) Pause(ctx context.Context, appName string) error {
+	c.paused = append(c.paused, appName)
+	return nil
+}
+
+func (c *fakeClusterApps) Delete(ctx context.Context, appName string) error {
+	c.deleted = append(c.deleted, appName)
+	return nil
+}
+
+type fakeClusterSource = workertest.Source[ClusterApps, *fakeClusterApps]
+
+type fakeNotifier struct {
+	mu       sync.Mutex
+	notified []types.PorterAppInactivityEventMetadata
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, projectID uint, event types.PorterAppInactivityEventMetadata) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.notified = append(n.notified, event)
+	return nil
+}
+
+type cleanerFixture struct {
+	events   *fakeEventStore
+	clusters fakeClusterSource
+	notifier *fakeNotifier
+	cleaner  *Cleaner
+}
+
+func newCleanerFixture(apps []*models.PorterApp, clusters fakeClusterSource) *cleanerFixture {
+	f := &cleanerFixture{
+		events:   &fakeEventStore{},
+		clusters: clusters,
+		notifier: &fakeNotifier{},
+	}
+	f.cleaner = NewCleaner(fakePolicyStore{testPolicy()}, fakeAppLister{1: apps}, f.events, clusters, f.notifier, Options{})
+
+	return f
+}
+
+// evaluate runs an evaluation at now, recording any events at the same time
+func (f *cleanerFixture) evaluate(t *testing.T, now time.Time) {
+	t.Helper()
+
+	f.events.now = now
+	if err := f.cleaner.evaluate(context.Background(), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEvaluate_PausesThenDeletesIdleApp(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cluster := &fakeClusterApps{requests: map[string]float64{"preview-42": 3}}
+	f := newCleanerFixture([]*models.PorterApp{testApp(10, "preview-42", created)}, fakeClusterSource{1: cluster})
+
+	f.evaluate(t, created.Add(6*day))
+	if len(cluster.paused) != 0 {
+		t.Fatalf("expected an app within its idle days not to be paused, got %v", cluster.paused)
+	}
+
+	pausedAt := created.Add(7 * day)
+	f.evaluate(t, pausedAt)
+	if len(cluster.paused) != 1 || cluster.paused[0] != "preview-42" {
+		t.Fatalf("expected the idle app to be paused, got %v", cluster.paused)
+	}
+
+	paused := f.events.ofType(types.PorterAppEventType_Inactivity)
+	if len(paused) != 1 || paused[0].Metadata["action"] != string(types.InactivityAction_Paused) || paused[0].Metadata["daily_requests"] != float64(3) {
+		t.Fatalf("unexpected events %+v", paused)
+	}
+	if len(f.notifier.notified) != 1 || f.notifier.notified[0].DeleteAt == nil || !f.notifier.notified[0].DeleteAt.Equal(pausedAt.Add(3*day)) {
+		t.Fatalf("expected a notification with the delete date, got %+v", f.notifier.notified)
+	}
+
+	f.evaluate(t, pausedAt.Add(day))
+	if len(cluster.paused) != 1 || len(cluster.deleted) != 0 {
+		t.Fatalf("expected nothing to be done within the grace period, got paused %v and deleted %v", cluster.paused, cluster.deleted)
+	}
+
+	f.evaluate(t, pausedAt.Add(3*day))
+	if len(cluster.deleted) != 1 || cluster.deleted[0] != "preview-42" {
+		t.Fatalf("expected the paused app to be deleted, got %v", cluster.deleted)
+	}
+	if events := f.events.ofType(types.PorterAppEventType_Inactivity); len(events) != 2 || events[1].Metadata["action"] != string(types.InactivityAction_Deleted) {
+		t.Fatalf("expected a delete event, got %+v", events)
+	}
+
+	f.evaluate(t, pausedAt.Add(10*day))
+	if len(cluster.deleted) != 1 {
+		t.Fatalf("expected a deleted app not to be deleted again, got %v", cluster.deleted)
+	}
+}
+
+func TestEvaluate_SkipsAppsWhichAreNotIdle(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(30 * day)
+
+	optedOut := testApp(11, "preview-opted-out", created)
+	optedOut.InactivityCleanupDisabled = true
+
+	cluster := &fakeClusterApps{requests: map[string]float64{"preview-busy": 500}}
+	f := newCleanerFixture([]*models.PorterApp{
+		testApp(10, "preview-busy", created),
+		optedOut,
+		testApp(12, "api", created),
+		testApp(13, "preview-deployed", created),
+	}, fakeClusterSource{1: cluster})
+	f.events.events = append(f.events.events, &models.PorterAppEvent{PorterAppID: 13, Type: string(types.PorterAppEventType_Deploy), CreatedAt: now.Add(-day)})
+
+	f.evaluate(t, now)
+
+	if len(cluster.paused) != 0 || len(f.notifier.notified) != 0 {
+		t.Fatalf("expected no app to be paused, got %v", cluster.paused)
+	}
+}
+
+func TestEvaluate_Traffic(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(30 * day)
+
+	t.Run("unknown traffic pauses on deploys alone", func(t *testing.T) {
+		cluster := &fakeClusterApps{}
+		f := newCleanerFixture([]*models.PorterApp{testApp(10, "preview-42", created)}, fakeClusterSource{1: cluster})
+
+		f.evaluate(t, now)
+
+		if len(cluster.paused) != 1 {
+			t.Fatalf("expected the app to be paused, got %v", cluster.paused)
+		}
+		if _, ok := f.events.ofType(types.PorterAppEventType_Inactivity)[0].Metadata["daily_requests"]; ok {
+			t.Fatalf("expected no daily requests to be recorded when traffic is unknown")
+		}
+	})
+
+	t.Run("traffic which cannot be read does not pause", func(t *testing.T) {
+		cluster := &fakeClusterApps{requestErr: errors.New("prometheus is unavailable")}
+		f := newCleanerFixture([]*models.PorterApp{testApp(10, "preview-42", created)}, fakeClusterSource{1: cluster})
+
+		f.evaluate(t, now)
+
+		if len(cluster.paused) != 0 || len(f.events.ofType(types.PorterAppEventType_Inactivity)) != 0 {
+			t.Fatalf("expected the app not to be paused, got %v", cluster.paused)
+		}
+	})
+}
+
+func TestEvaluate_UnreachableClusterIsSkipped(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	unreachable := testApp(10, "preview-1", created)
+	reachable := testApp(11, "preview-2", created)
+	reachable.ClusterID = 2
+
+	clusters := fakeClusterSource{1: {Cluster: workertest.Cluster{Unreachable: true}}, 2: {}}
+	f := newCleanerFixture([]*models.PorterApp{unreachable, reachable}, clusters)
+
+	f.evaluate(t, created.Add(30*day))
+
+	if len(clusters[2].paused) != 1 || clusters[2].paused[0] != "preview-2" {
+		t.Fatalf("expected the app on the reachable cluster to be paused, got %v", clusters[2].paused)
+	}
+	if events := f.events.ofType(types.PorterAppEventType_Inactivity); len(events) != 1 || events[0].PorterAppID != 11 {
+		t.Fatalf("expected a single event for the reachable app, got %+v", events)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pausedAt := created.Add(8 * day)
+
+	optedOut := testApp(11, "preview-opted-out", created)
+	optedOut.InactivityCleanupDisabled = true
+
+	events := &fakeEventStore{events: []*models.PorterAppEvent{
+		{PorterAppID: 12, Type: string(types.PorterAppEventType_Inactivity), CreatedAt: pausedAt, Metadata: models.JSONB{"action": string(types.InactivityAction_Paused)}},
+		{PorterAppID: 13, Type: string(types.PorterAppEventType_Inactivity), CreatedAt: pausedAt, Metadata: models.JSONB{"action": string(types.InactivityAction_Deleted)}},
+	}}
+
+	candidates, err := Candidates(context.Background(), events, testPolicy(), []*models.PorterApp{
+		testApp(10, "preview-scheduled", created),
+		optedOut,
+		testApp(12, "preview-paused", created),
+		testApp(13, "preview-deleted", created),
+		testApp(14, "api", created),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]types.InactivityCandidateStatus{
+		"preview-scheduled": types.InactivityCandidateStatus_Scheduled,
+		"preview-opted-out": types.InactivityCandidateStatus_OptedOut,
+		"preview-paused":    types.InactivityCandidateStatus_Paused,
+	}
+	if len(candidates) != len(expected) {
+		t.Fatalf("expected %d candidates, got %+v", len(expected), candidates)
+	}
+	for _, candidate := range candidates {
+		if candidate.Status != expected[candidate.AppName] {
+			t.Errorf("expected %s to be %s, got %s", candidate.AppName, expected[candidate.AppName], candidate.Status)
+		}
+		if candidate.AppName == "preview-paused" && !candidate.DeleteAt.Equal(pausedAt.Add(3*day)) {
+			t.Errorf("expected the paused app to be deleted the grace days after it was paused, got %s", candidate.DeleteAt)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package inactivity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+const day = 24 * time.Hour
+
+// EventReader reads the events which the schedule of an app is computed from
+type EventReader interface {
+	// ReadLatestEventByType returns the most recent event of a type on an app, or gorm.ErrRecordNotFound if it has none
+	ReadLatestEventByType(ctx context.Context, porterAppID uint, eventType string) (*models.PorterAppEvent, error)
+}
+
+// ValidateNamePattern returns an error if pattern is not a valid glob
+func ValidateNamePattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("name pattern %q is not a valid glob: %w", pattern, err)
+	}
+
+	return nil
+}
+
+// Matches returns true if the policy applies to the app, because it is flagged as ephemeral or its name matches the
+// policy's pattern. Apps which opted out still match, so that they are listed in the report.
+func Matches(policy *models.InactivityPolicy, app *models.PorterApp) bool {
+	if app.Ephemeral {
+		return true
+	}
+	if policy.NamePattern == "" {
+		return false
+	}
+
+	matched, _ := path.Match(policy.NamePattern, app.Name)
+	return matched
+}
+
+// Schedule is when an app which the policy applies to is paused and deleted
+type Schedule struct {
+	LastDeployedAt time.Time
+	// DeploymentTargetID is the deployment target of the last deploy, which events for the app are recorded in
+	DeploymentTargetID uuid.UUID
+	// PauseAt is the policy's idle days after the last deploy
+	PauseAt time.Time
+	// PausedAt is set if the app was paused since it was last deployed
+	PausedAt *time.Time
+	// DeleteAt is the policy's grace days after the app was paused, or after PauseAt if it is not paused yet
+	DeleteAt time.Time
+	// Deleted is true if the app was deleted since it was last deployed
+	Deleted bool
+}
+
+// NewSchedule returns the schedule of an app from its latest deploy and inactivity events, either of which may be nil.
+// An app which was never deployed is idle from when it was created. A deploy resets the schedule, so an inactivity
+// event from before the latest deploy is ignored.
+func NewSchedule(policy *models.InactivityPolicy, app *models.PorterApp, lastDeploy, lastInactivity *models.PorterAppEvent) Schedule {
+	s := Schedule{LastDeployedAt: app.CreatedAt.UTC()}
+	if lastDeploy != nil {
+		s.LastDeployedAt = lastDeploy.CreatedAt.UTC()
+		s.DeploymentTargetID = lastDeploy.DeploymentTargetID
+	}
+
+	s.PauseAt = s.LastDeployedAt.Add(time.Duration(policy.IdleDays) * day)
+	s.DeleteAt = s.PauseAt.Add(time.Duration(policy.GraceDays) * day)
+
+	if lastInactivity == nil || !lastInactivity.CreatedAt.After(s.LastDeployedAt) {
+		return s
+	}
+
+	action, _ := lastInactivity.Metadata["action"].(string)
+	switch types.InactivityAction(action) {
+	case types.InactivityAction_Paused:
+		pausedAt := lastInactivity.CreatedAt.UTC()
+		s.PausedAt = &pausedAt
+		s.DeleteAt = pausedAt.Add(time.Duration(policy.GraceDays) * day)
+	case types.InactivityAction_Deleted:
+		s.Deleted = true
+	}
+
+	return s
+}
+
+// Due returns the action which is due at now, or an empty action if there is none
+func (s Schedule) Due(now time.Time) types.InactivityAction {
+	switch {
+	case s.Deleted:
+		return ""
+	case s.PausedAt == nil && !now.Before(s.PauseAt):
+		return types.InactivityAction_Paused
+	case s.PausedAt != nil && !now.Before(s.DeleteAt):
+		return types.InactivityAction_Deleted
+	}
+
+	return ""
+}
+
+// ScheduleFor returns the schedule of an app, reading its latest events from events
+func ScheduleFor(ctx context.Context, events EventReader, policy *models.InactivityPolicy, app *models.PorterApp) (Schedule, error) {
+	lastDeploy, err := latestEvent(ctx, events, app.ID, types.PorterAppEventType_Deploy)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("error reading latest deploy event: %w", err)
+	}
+
+	lastInactivity, err := latestEvent(ctx, events, app.ID, types.PorterAppEventType_Inactivity)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("error reading latest inactivity event: %w", err)
+	}
+
+	return NewSchedule(policy, app, lastDeploy, lastInactivity), nil
+}
+
+// Candidates returns the apps which the policy applies to, with the dates they will be paused and deleted. Apps which
+// were deleted by the policy are left out.
+func Candidates(ctx context.Context, events EventReader, policy *models.InactivityPolicy, apps []*models.PorterApp) ([]types.InactivityCandidate, error) {
+	candidates := []types.InactivityCandidate{}
+
+	for _, app := range apps {
+		if !Matches(policy, app) {
+			continue
+		}
+
+		s, err := ScheduleFor(ctx, events, policy, app)
+		if err != nil {
+			return nil, fmt.Errorf("error computing schedule of app %s: %w", app.Name, err)
+		}
+		if s.Deleted {
+			continue
+		}
+
+		candidate := types.InactivityCandidate{
+			PorterAppID:    app.ID,
+			AppName:        app.Name,
+			ClusterID:      app.ClusterID,
+			Ephemeral:      app.Ephemeral,
+			Status:         types.InactivityCandidateStatus_Scheduled,
+			LastDeployedAt: s.LastDeployedAt,
+			PauseAt:        s.PauseAt,
+			PausedAt:       s.PausedAt,
+			DeleteAt:       s.DeleteAt,
+		}
+		switch {
+		case app.InactivityCleanupDisabled:
+			candidate.Status = types.InactivityCandidateStatus_OptedOut
+		case s.PausedAt != nil:
+			candidate.Status = types.InactivityCandidateStatus_Paused
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+func latestEvent(ctx context.Context, events EventReader, porterAppID uint, eventType types.PorterAppEventType) (*models.PorterAppEvent, error) {
+	event, err := events.ReadLatestEventByType(ctx, porterAppID, string(eventType))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return event, nil
+}
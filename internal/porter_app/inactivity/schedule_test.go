@@ -0,0 +1,164 @@
+package inactivity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func testPolicy() *models.InactivityPolicy {
+	return &models.InactivityPolicy{
+		ProjectID:        1,
+		Enabled:          true,
+		NamePattern:      "preview-*",
+		IdleDays:         7,
+		GraceDays:        3,
+		MaxDailyRequests: 10,
+	}
+}
+
+func testApp(id uint, name string, createdAt time.Time) *models.PorterApp {
+	return &models.PorterApp{
+		Model:     gorm.Model{ID: id, CreatedAt: createdAt},
+		ProjectID: 1,
+		ClusterID: 1,
+		Name:      name,
+	}
+}
+
+func event(eventType types.PorterAppEventType, at time.Time, action types.InactivityAction) *models.PorterAppEvent {
+	e := &models.PorterAppEvent{Type: string(eventType), CreatedAt: at}
+	if action != "" {
+		e.Metadata = models.JSONB{"action": string(action)}
+	}
+
+	return e
+}
+
+func TestMatches(t *testing.T) {
+	policy := testPolicy()
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ephemeral := testApp(2, "experiment", created)
+	ephemeral.Ephemeral = true
+
+	tests := []struct {
+		name     string
+		app      *models.PorterApp
+		expected bool
+	}{
+		{name: "name matches the pattern", app: testApp(1, "preview-42", created), expected: true},
+		{name: "ephemeral app", app: ephemeral, expected: true},
+		{name: "other app", app: testApp(3, "api", created), expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Matches(policy, tt.app); got != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	policy.NamePattern = ""
+	if Matches(policy, testApp(1, "preview-42", created)) {
+		t.Fatalf("expected a policy without a pattern to only match ephemeral apps")
+	}
+}
+
+func TestValidateNamePattern(t *testing.T) {
+	if err := ValidateNamePattern("preview-*"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ValidateNamePattern("preview-["); err == nil {
+		t.Fatalf("expected an error for an unterminated character class")
+	}
+}
+
+func TestNewSchedule(t *testing.T) {
+	policy := testPolicy()
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployed := created.Add(2 * day)
+	paused := deployed.Add(8 * day)
+
+	tests := []struct {
+		name           string
+		lastDeploy     *models.PorterAppEvent
+		lastInactivity *models.PorterAppEvent
+		now            time.Time
+		lastDeployedAt time.Time
+		pauseAt        time.Time
+		deleteAt       time.Time
+		paused         bool
+		due            types.InactivityAction
+	}{
+		{
+			name:           "never deployed is idle from creation",
+			now:            created.Add(6 * day),
+			lastDeployedAt: created,
+			pauseAt:        created.Add(7 * day),
+			deleteAt:       created.Add(10 * day),
+		},
+		{
+			name:           "due to be paused",
+			lastDeploy:     event(types.PorterAppEventType_Deploy, deployed, ""),
+			now:            deployed.Add(7 * day),
+			lastDeployedAt: deployed,
+			pauseAt:        deployed.Add(7 * day),
+			deleteAt:       deployed.Add(10 * day),
+			due:            types.InactivityAction_Paused,
+		},
+		{
+			name:           "paused within the grace period",
+			lastDeploy:     event(types.PorterAppEventType_Deploy, deployed, ""),
+			lastInactivity: event(types.PorterAppEventType_Inactivity, paused, types.InactivityAction_Paused),
+			now:            paused.Add(2 * day),
+			lastDeployedAt: deployed,
+			pauseAt:        deployed.Add(7 * day),
+			deleteAt:       paused.Add(3 * day),
+			paused:         true,
+		},
+		{
+			name:           "due to be deleted",
+			lastDeploy:     event(types.PorterAppEventType_Deploy, deployed, ""),
+			lastInactivity: event(types.PorterAppEventType_Inactivity, paused, types.InactivityAction_Paused),
+			now:            paused.Add(3 * day),
+			lastDeployedAt: deployed,
+			pauseAt:        deployed.Add(7 * day),
+			deleteAt:       paused.Add(3 * day),
+			paused:         true,
+			due:            types.InactivityAction_Deleted,
+		},
+		{
+			name:           "deploy after the pause resets the schedule",
+			lastDeploy:     event(types.PorterAppEventType_Deploy, paused.Add(day), ""),
+			lastInactivity: event(types.PorterAppEventType_Inactivity, paused, types.InactivityAction_Paused),
+			now:            paused.Add(4 * day),
+			lastDeployedAt: paused.Add(day),
+			pauseAt:        paused.Add(8 * day),
+			deleteAt:       paused.Add(11 * day),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSchedule(policy, testApp(1, "preview-42", created), tt.lastDeploy, tt.lastInactivity)
+
+			if !s.LastDeployedAt.Equal(tt.lastDeployedAt) || !s.PauseAt.Equal(tt.pauseAt) || !s.DeleteAt.Equal(tt.deleteAt) {
+				t.Fatalf("expected last deploy %s, pause at %s and delete at %s, got %+v", tt.lastDeployedAt, tt.pauseAt, tt.deleteAt, s)
+			}
+			if (s.PausedAt != nil) != tt.paused {
+				t.Fatalf("expected paused to be %v, got paused at %v", tt.paused, s.PausedAt)
+			}
+			if got := s.Due(tt.now); got != tt.due {
+				t.Fatalf("expected %q to be due, got %q", tt.due, got)
+			}
+		})
+	}
+
+	deleted := NewSchedule(policy, testApp(1, "preview-42", created), nil, event(types.PorterAppEventType_Inactivity, paused, types.InactivityAction_Deleted))
+	if !deleted.Deleted || deleted.Due(paused.Add(30*day)) != "" {
+		t.Fatalf("expected nothing to be due for a deleted app, got %+v", deleted)
+	}
+}
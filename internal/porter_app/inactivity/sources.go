@@ -0,0 +1,175 @@
+package inactivity
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/notifier/slack"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// labelKey_AppName is the label which the workloads of apps deployed through the cluster control plane are selected by
+const labelKey_AppName = "porter.run/app-name"
+
+// NewCleanerFromConfig returns a Cleaner which reads policies, apps and events from the server's database, pauses apps
+// by scaling them down on each cluster, deletes them through the cluster control plane and notifies the project's
+// slack integrations
+func NewCleanerFromConfig(conf *config.Config, opts Options) *Cleaner {
+	return NewCleaner(
+		conf.Repo.InactivityPolicy(),
+		&repoAppLister{conf: conf},
+		conf.Repo.PorterAppEvent(),
+		worker.NewAgentSource(conf, func(cluster *models.Cluster, agent *kubernetes.Agent) (ClusterApps, error) {
+			return &agentClusterApps{conf: conf, cluster: cluster, agent: agent}, nil
+		}),
+		&slackNotifier{conf: conf},
+		opts,
+	)
+}
+
+// repoAppLister lists the apps of every cluster of a project
+type repoAppLister struct {
+	conf *config.Config
+}
+
+// ListPorterApps returns the apps of a project
+func (l *repoAppLister) ListPorterApps(ctx context.Context, projectID uint) ([]*models.PorterApp, error) {
+	clusters, err := l.conf.Repo.Cluster().ListClustersByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing clusters: %w", err)
+	}
+
+	var apps []*models.PorterApp
+	for _, cluster := range clusters {
+		clusterApps, err := l.conf.Repo.PorterApp().ListScopedPorterAppsByClusterID(projectID, cluster.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error listing apps of cluster %d: %w", cluster.ID, err)
+		}
+		apps = append(apps, clusterApps...)
+	}
+
+	return apps, nil
+}
+
+type agentClusterApps struct {
+	conf    *config.Config
+	cluster *models.Cluster
+	agent   *kubernetes.Agent
+}
+
+// DailyRequests returns the number of requests the ingress controller routed to the namespace of an app within the
+// last day, from the cluster's prometheus. Apps deployed through the cluster control plane share the namespace of
+// their deployment target, so their traffic cannot be told apart from other apps and is reported as unknown.
+func (c *agentClusterApps) DailyRequests(ctx context.Context, appName string) (float64, bool, error) {
+	svc, found, err := prometheus.GetPrometheusService(c.agent.Clientset)
+	if err != nil {
+		return 0, false, fmt.Errorf("error getting prometheus service: %w", err)
+	}
+	if !found {
+		return 0, false, nil
+	}
+
+	namespace := utils.NamespaceFromPorterAppName(appName)
+	if _, err := c.agent.Clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("error reading namespace of app: %w", err)
+	}
+
+	requests, err := prometheus.QueryIngressRequests(ctx, c.agent.Clientset, svc, namespace, day)
+	if err != nil {
+		return 0, false, fmt.Errorf("error querying ingress requests: %w", err)
+	}
+
+	return requests, true, nil
+}
+
+// Pause scales the deployments and stateful sets of an app to zero replicas. The next deploy of the app scales them
+// back up, since the replicas in its manifests differ from the live objects. Cron jobs are left scheduled, since
+// suspending them would outlast the next deploy.
+func (c *agentClusterApps) Pause(ctx context.Context, appName string) error {
+	patch := []byte(`{"spec":{"replicas":0}}`)
+
+	// apps deployed as stacks have their own namespace, while apps deployed through the cluster control plane are
+	// labelled with their name in the namespace of their deployment target
+	selections := []struct {
+		namespace string
+		options   metav1.ListOptions
+	}{
+		{namespace: utils.NamespaceFromPorterAppName(appName)},
+		{options: metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", labelKey_AppName, appName)}},
+	}
+
+	for _, selection := range selections {
+		deployments, err := c.agent.Clientset.AppsV1().Deployments(selection.namespace).List(ctx, selection.options)
+		if err != nil {
+			return fmt.Errorf("error listing deployments: %w", err)
+		}
+		for _, deployment := range deployments.Items {
+			if _, err := c.agent.Clientset.AppsV1().Deployments(deployment.Namespace).Patch(ctx, deployment.Name, k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+				return fmt.Errorf("error scaling down deployment %s: %w", deployment.Name, err)
+			}
+		}
+
+		statefulSets, err := c.agent.Clientset.AppsV1().StatefulSets(selection.namespace).List(ctx, selection.options)
+		if err != nil {
+			return fmt.Errorf("error listing stateful sets: %w", err)
+		}
+		for _, statefulSet := range statefulSets.Items {
+			if _, err := c.agent.Clientset.AppsV1().StatefulSets(statefulSet.Namespace).Patch(ctx, statefulSet.Name, k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+				return fmt.Errorf("error scaling down stateful set %s: %w", statefulSet.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes an app through the cluster control plane, the same way as deleting it from the dashboard or CLI
+func (c *agentClusterApps) Delete(ctx context.Context, appName string) error {
+	resp, err := c.conf.ClusterControlPlaneClient.DeletePorterApp(ctx, connect.NewRequest(&porterv1.DeletePorterAppRequest{
+		ProjectId: int64(c.cluster.ProjectID),
+		ClusterId: int64(c.cluster.ID),
+		AppName:   appName,
+	}))
+	if err != nil {
+		return fmt.Errorf("error deleting porter app: %w", err)
+	}
+	if resp == nil || resp.Msg == nil {
+		return fmt.Errorf("cluster control plane returned an empty response")
+	}
+
+	return nil
+}
+
+// slackNotifier sends the steps taken on apps to the slack integrations of their project
+type slackNotifier struct {
+	conf *config.Config
+}
+
+// Notify posts the step to every slack integration of the project. Projects without one are only notified through the
+// app's activity feed.
+func (n *slackNotifier) Notify(ctx context.Context, projectID uint, event types.PorterAppInactivityEventMetadata) error {
+	slackInts, err := n.conf.Repo.SlackIntegration().ListSlackIntegrationsByProjectID(projectID)
+	if err != nil {
+		return fmt.Errorf("error listing slack integrations: %w", err)
+	}
+	if len(slackInts) == 0 {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/apps/%s/activity", n.conf.ServerConf.ServerURL, event.AppName)
+	return slack.NewInactivityNotifier(slackInts...).Notify(ctx, event, url)
+}
@@ -0,0 +1,128 @@
+package lint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField is a field of a cron expression and the values it can take
+type cronField struct {
+	name     string
+	min, max int
+	// names are the names which can be used in place of numbers, such as JAN for 1
+	names map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}},
+	// both 0 and 7 are sunday
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}},
+}
+
+// cronMacros are the predefined schedules which can be used in place of the five fields
+var cronMacros = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+
+// ValidateCron returns an error if expr is not a schedule that a kubernetes cron job accepts: five fields for the
+// minute, hour, day of month, month and day of week, or one of the predefined schedules such as @daily
+func ValidateCron(expr string) error {
+	expr = strings.TrimSpace(expr)
+
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		return fmt.Errorf("time zones cannot be set in the schedule, schedules run in the time zone of the cluster")
+	}
+
+	if strings.HasPrefix(expr, "@") {
+		if every, ok := strings.CutPrefix(expr, "@every "); ok {
+			if d, err := time.ParseDuration(strings.TrimSpace(every)); err != nil || d <= 0 {
+				return fmt.Errorf("%q is not a valid interval for @every, such as 1h30m", strings.TrimSpace(every))
+			}
+			return nil
+		}
+		if !contains(cronMacros, expr) {
+			return fmt.Errorf("%s is not a predefined schedule, expected one of %s or @every followed by an interval", expr, strings.Join(cronMacros, ", "))
+		}
+		return nil
+	}
+
+	values := strings.Fields(expr)
+	if len(values) != len(cronFields) {
+		return fmt.Errorf("expected 5 fields (minute, hour, day of month, month, day of week), found %d in %q", len(values), expr)
+	}
+
+	for i, value := range values {
+		if err := cronFields[i].validate(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate checks a comma separated list of values, ranges and steps in a single field
+func (f cronField) validate(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item == "" {
+			return fmt.Errorf("%s %q has an empty item in its list", f.name, value)
+		}
+
+		rangePart, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			n, err := strconv.Atoi(step)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("%s %q has step %q, which must be a positive number", f.name, item, step)
+			}
+		}
+
+		if rangePart == "*" || rangePart == "?" {
+			if rangePart == "?" && f.name != "day of month" && f.name != "day of week" {
+				return fmt.Errorf("? can only be used for the day of month or day of week, not the %s", f.name)
+			}
+			continue
+		}
+
+		low, high, isRange := strings.Cut(rangePart, "-")
+		lowValue, err := f.parse(low)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+
+		highValue, err := f.parse(high)
+		if err != nil {
+			return err
+		}
+		if lowValue > highValue {
+			return fmt.Errorf("%s range %q starts after it ends", f.name, rangePart)
+		}
+	}
+
+	return nil
+}
+
+// parse returns a single value of the field, given as a number or a name
+func (f cronField) parse(value string) (int, error) {
+	if n, ok := f.names[strings.ToUpper(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s %q is not a number", f.name, value)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s %d is out of range, expected %d to %d", f.name, n, f.min, f.max)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,47 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: "*/10 * * * *"},
+		{expr: "0 9-17 * * MON-FRI"},
+		{expr: "0,30 0 1 jan,jul ?"},
+		{expr: "5 4 * * 7"},
+		{expr: "@daily"},
+		{expr: "@every 1h30m"},
+		{expr: "* * * *", wantErr: "expected 5 fields"},
+		{expr: "60 * * * *", wantErr: "minute 60 is out of range"},
+		{expr: "0 0 0 * *", wantErr: "day of month 0 is out of range"},
+		{expr: "0 0 * 13 *", wantErr: "month 13 is out of range"},
+		{expr: "*/0 * * * *", wantErr: "must be a positive number"},
+		{expr: "0 17-9 * * *", wantErr: "starts after it ends"},
+		{expr: "0 0 * * FUN", wantErr: `day of week "FUN" is not a number`},
+		{expr: "? * * * *", wantErr: "? can only be used for the day of month or day of week"},
+		{expr: "0 0 1,,2 * *", wantErr: "empty item"},
+		{expr: "@fortnightly", wantErr: "is not a predefined schedule"},
+		{expr: "@every soon", wantErr: "not a valid interval"},
+		{expr: "CRON_TZ=UTC 0 0 * * *", wantErr: "time zones cannot be set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			err := ValidateCron(tt.expr)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateDomain returns an error if domain is not a fully qualified domain name which an ingress can route and
+// cert-manager can issue a certificate for
+func ValidateDomain(domain string) error {
+	if domain == "" {
+		return fmt.Errorf("domain is empty")
+	}
+	if strings.HasPrefix(domain, "*.") {
+		return fmt.Errorf("wildcard domains cannot be issued certificates by an HTTP-01 challenge")
+	}
+	if strings.ToLower(domain) != domain {
+		return fmt.Errorf("domain must be lowercase")
+	}
+	if msgs := validation.IsDNS1123Subdomain(domain); len(msgs) > 0 {
+		return fmt.Errorf("%s", strings.Join(msgs, ", "))
+	}
+	if !strings.Contains(domain, ".") {
+		return fmt.Errorf("domain must be fully qualified, such as app.example.com")
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if len(label) > validation.DNS1123LabelMaxLength {
+			return fmt.Errorf("label %s is longer than %d characters", label, validation.DNS1123LabelMaxLength)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+// Package lint validates a v1stack porter.yaml without a connection to Porter. The same checks are run by the porter
+// CLI, so that porter.yaml can be linted in CI without credentials, and by the server before a porter.yaml is deployed,
+// so that the two can never disagree about whether a porter.yaml is valid.
+//
+// Findings are reported with the line and column of the YAML node they refer to. Checks which need the project or the
+// cluster, such as the registries images can be pulled from or whether the cluster has room for the app, are not part
+// of this package and are only run when the app is applied.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is how serious a finding is. The values match the annotation levels of GitHub checks.
+type Severity string
+
+const (
+	// SeverityError is a finding which stops the porter.yaml from being deployed
+	SeverityError Severity = "error"
+	// SeverityWarning is a finding for a porter.yaml which deploys, but likely not as intended
+	SeverityWarning Severity = "warning"
+	// SeverityNotice is a finding which is only informational
+	SeverityNotice Severity = "notice"
+)
+
+// ServerOnlyChecks are the checks which are only run when porter.yaml is applied, since they need the project or the
+// target cluster
+var ServerOnlyChecks = []string{
+	"images are pulled from a registry connected to the project",
+	"the cluster has capacity for the requested resources",
+	"env groups referenced by the app exist",
+}
+
+// Finding is a problem found in a porter.yaml
+type Finding struct {
+	// Line is the 1-indexed line of the node the finding refers to, or 0 if the finding is about the whole file
+	Line int `json:"line"`
+	// Column is the 1-indexed column of the node the finding refers to
+	Column int `json:"column"`
+	// Path is the dotted path of the node the finding refers to, such as services.web.config.container.port
+	Path     string   `json:"path,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// String returns the finding as line:column: severity: message
+func (f Finding) String() string {
+	if f.Line == 0 {
+		return fmt.Sprintf("%s: %s", f.Severity, f.Message)
+	}
+
+	return fmt.Sprintf("%d:%d: %s: %s", f.Line, f.Column, f.Severity, f.Message)
+}
+
+// Options change which checks are run
+type Options struct {
+	// AppName is the name the app is deployed as. If set, the names of the kubernetes resources created for each service
+	// are checked against the kubernetes limits, otherwise only the service names are.
+	AppName string
+	// ResolvesFromFile is set by the porter CLI, which inlines env variables set with fromFile before porter.yaml is
+	// sent to Porter. The server rejects fromFile, since it cannot read the files.
+	ResolvesFromFile bool
+}
+
+// Lint returns the findings for porterYaml, ordered by their position in the file
+func Lint(porterYaml []byte, opts Options) []Finding {
+	l := &linter{opts: opts}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(porterYaml, &doc); err != nil {
+		l.findings = append(l.findings, syntaxError(err))
+		return l.findings
+	}
+
+	if len(doc.Content) == 0 {
+		l.errorf(nil, "", "porter.yaml is empty")
+		return l.findings
+	}
+
+	l.stack(doc.Content[0])
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		if l.findings[i].Line != l.findings[j].Line {
+			return l.findings[i].Line < l.findings[j].Line
+		}
+		return l.findings[i].Column < l.findings[j].Column
+	})
+
+	return l.findings
+}
+
+// ErrInvalid is wrapped by the errors returned by Errors, so that callers can tell a porter.yaml which is invalid from
+// a failure to deploy it
+var ErrInvalid = errors.New("porter.yaml is invalid")
+
+// Errors returns an error listing the findings which are errors, or nil if there are none
+func Errors(findings []Finding) error {
+	var errs []string
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			errs = append(errs, finding.String())
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalid, strings.Join(errs, "; "))
+}
+
+// Locate returns the line and column of the key at a dotted path of porterYaml, such as services.web.config, or zeros
+// if porterYaml does not set it. Paths under services also find services written under apps.
+func Locate(porterYaml []byte, path string) (int, int) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(porterYaml, &doc); err != nil || len(doc.Content) == 0 || path == "" {
+		return 0, 0
+	}
+
+	keys := strings.Split(path, ".")
+	f := lookupPath(doc.Content[0], keys...)
+	if f == nil && keys[0] == "services" {
+		f = lookupPath(doc.Content[0], append([]string{"apps"}, keys[1:]...)...)
+	}
+	if f == nil {
+		return 0, 0
+	}
+
+	return f.key.Line, f.key.Column
+}
+
+// syntaxLine matches the line which gopkg.in/yaml.v3 reports a syntax error at
+var syntaxLine = regexp.MustCompile(`line (\d+):`)
+
+func syntaxError(err error) Finding {
+	finding := Finding{Severity: SeverityError, Message: fmt.Sprintf("error parsing porter.yaml: %s", strings.TrimPrefix(err.Error(), "yaml: "))}
+
+	if match := syntaxLine.FindStringSubmatch(err.Error()); match != nil {
+		finding.Line, _ = strconv.Atoi(match[1])
+		finding.Column = 1
+	}
+
+	return finding
+}
+
+type linter struct {
+	opts     Options
+	findings []Finding
+}
+
+func (l *linter) add(severity Severity, node *yaml.Node, path string, format string, args ...interface{}) {
+	finding := Finding{Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		finding.Line = node.Line
+		finding.Column = node.Column
+	}
+
+	l.findings = append(l.findings, finding)
+}
+
+func (l *linter) errorf(node *yaml.Node, path string, format string, args ...interface{}) {
+	l.add(SeverityError, node, path, format, args...)
+}
+
+func (l *linter) warnf(node *yaml.Node, path string, format string, args ...interface{}) {
+	l.add(SeverityWarning, node, path, format, args...)
+}
+
+func (l *linter) noticef(node *yaml.Node, path string, format string, args ...interface{}) {
+	l.add(SeverityNotice, node, path, format, args...)
+}
+
+// field is a key of a mapping node and its value
+type field struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// fields returns the keys and values of a mapping node, in the order they are written
+func fields(node *yaml.Node) []field {
+	var fs []field
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fs = append(fs, field{key: node.Content[i], value: node.Content[i+1]})
+	}
+
+	return fs
+}
+
+// lookup returns the key and value of a field of a mapping node, or nil if the node is not a mapping or the field is
+// not set
+func lookup(node *yaml.Node, key string) *field {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for _, f := range fields(node) {
+		if f.key.Value == key {
+			return &f
+		}
+	}
+
+	return nil
+}
+
+// isNull reports whether a node is an explicit or implicit null, such as a key with no value
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// kindName describes the kind of a node for error messages
+func kindName(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.AliasNode:
+		return "an alias"
+	}
+
+	switch node.Tag {
+	case "!!int", "!!float":
+		return "a number"
+	case "!!bool":
+		return "a boolean"
+	case "!!null":
+		return "empty"
+	}
+
+	return "a string"
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
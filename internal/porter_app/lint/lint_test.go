@@ -0,0 +1,538 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+const validPorterYaml = `version: v1stack
+build:
+  method: pack
+  builder: heroku/buildpacks:20
+env:
+  PORT: "8080"
+  GREETING: |
+    hello
+services:
+  web:
+    type: web
+    run: node index.js
+    config:
+      container:
+        port: 8080
+        env:
+          normal:
+            DEBUG: "true"
+      service:
+        port: "8080"
+      ingress:
+        hosts:
+          - example.com
+    custom_domains:
+      - www.example.com
+    scaling:
+      timezone: America/New_York
+      schedules:
+        - name: business hours
+          days: MON-FRI
+          start: "08:00"
+          end: "20:00"
+          replicas: 10
+  cleanup-job:
+    type: job
+    run: node cleanup.js
+    observability:
+      enabled: false
+    config:
+      schedule:
+        enabled: true
+        value: "*/10 * * * *"
+release:
+  run: npm run migrate
+`
+
+func TestLint_Valid(t *testing.T) {
+	findings := Lint([]byte(validPorterYaml), Options{AppName: "test-app"})
+	if len(findings) != 0 {
+		t.Fatalf("expected no findings, got %v", findings)
+	}
+	if err := Errors(findings); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		opts     Options
+		line     int
+		column   int
+		path     string
+		severity Severity
+		message  string
+	}{
+		{
+			name:     "syntax error",
+			yaml:     "services:\n  web:\n\ttype: web\n",
+			line:     3,
+			severity: SeverityError,
+			message:  "error parsing porter.yaml",
+		},
+		{
+			name:     "apps and services",
+			yaml:     "apps:\n  web: {}\nservices:\n  web: {}\n",
+			line:     3,
+			column:   1,
+			path:     "services",
+			severity: SeverityError,
+			message:  "'apps' and 'services' are synonymous",
+		},
+		{
+			name:     "invalid service name",
+			yaml:     "services:\n  Web_App:\n    type: web\n",
+			line:     2,
+			column:   3,
+			path:     "services.Web_App",
+			severity: SeverityError,
+			message:  "service name Web_App is invalid",
+		},
+		{
+			name:     "service name too long for the app",
+			yaml:     "services:\n  " + strings.Repeat("a", 40) + ":\n    type: web\n",
+			opts:     Options{AppName: strings.Repeat("b", 20)},
+			line:     2,
+			column:   3,
+			severity: SeverityError,
+			message:  "longer than the limit of 63 characters",
+		},
+		{
+			name:     "cron job name too long",
+			yaml:     "services:\n  " + strings.Repeat("a", 50) + ":\n    type: job\n    config:\n      schedule:\n        enabled: true\n        value: \"@daily\"\n",
+			line:     2,
+			column:   3,
+			severity: SeverityError,
+			message:  "the name of its cron job would be",
+		},
+		{
+			name:     "unknown service type",
+			yaml:     "services:\n  api:\n    type: wrker\n",
+			line:     3,
+			column:   11,
+			path:     "services.api.type",
+			severity: SeverityError,
+			message:  `type of service api must be one of web, worker, job, cron, found "wrker"`,
+		},
+		{
+			name:     "web port out of range",
+			yaml:     "services:\n  web:\n    config:\n      container:\n        port: \"80\"\n",
+			line:     5,
+			column:   15,
+			path:     "services.web.config.container.port",
+			severity: SeverityError,
+			message:  "port of web service web must be between 1024 and 65535, found 80",
+		},
+		{
+			name:     "port is not a number",
+			yaml:     "services:\n  worker:\n    config:\n      container:\n        port: http\n",
+			line:     5,
+			column:   15,
+			severity: SeverityError,
+			message:  `port of service worker must be a number, found "http"`,
+		},
+		{
+			name:     "service port does not match container port",
+			yaml:     "services:\n  web:\n    config:\n      container:\n        port: \"8080\"\n      service:\n        port: \"3000\"\n",
+			line:     7,
+			column:   15,
+			path:     "services.web.config.service.port",
+			severity: SeverityError,
+			message:  "service web exposes port 3000, but its container listens on port 8080",
+		},
+		{
+			name:     "host routed to two services",
+			yaml:     "services:\n  web:\n    config:\n      ingress:\n        hosts: [example.com]\n  api-web:\n    config:\n      ingress:\n        hosts: [example.com]\n",
+			line:     9,
+			column:   17,
+			severity: SeverityError,
+			message:  "host example.com of service api-web is also routed to service web",
+		},
+		{
+			name:     "invalid custom domain",
+			yaml:     "services:\n  web:\n    custom_domains:\n      - app_example.com\n",
+			line:     4,
+			column:   9,
+			path:     "services.web.custom_domains",
+			severity: SeverityError,
+			message:  "custom domain app_example.com of service web is invalid",
+		},
+		{
+			name:     "custom domain which is not fully qualified",
+			yaml:     "services:\n  web:\n    custom_domains: [localhost]\n",
+			line:     3,
+			column:   22,
+			path:     "services.web.custom_domains",
+			severity: SeverityError,
+			message:  "domain must be fully qualified",
+		},
+		{
+			name:     "custom domain routed to two services",
+			yaml:     "services:\n  web:\n    config:\n      ingress:\n        hosts: [example.com]\n  api-web:\n    custom_domains: [example.com]\n",
+			line:     7,
+			column:   22,
+			path:     "services.api-web.custom_domains",
+			severity: SeverityError,
+			message:  "custom domain example.com of service api-web is also routed to service web",
+		},
+		{
+			name:     "custom domain of a worker",
+			yaml:     "services:\n  worker:\n    custom_domains: [jobs.example.com]\n",
+			line:     3,
+			column:   5,
+			path:     "services.worker.custom_domains",
+			severity: SeverityError,
+			message:  "service worker is a worker service, only web services can have custom domains",
+		},
+		{
+			name:     "network peer with both cidr and namespace",
+			yaml:     "services:\n  web:\n    network:\n      allow_egress:\n        - cidr: 10.0.0.0/16\n          namespace: monitoring\n",
+			line:     5,
+			column:   11,
+			path:     "services.web.network.allow_egress[0]",
+			severity: SeverityError,
+			message:  "network peer of service web must set only one of cidr and namespace",
+		},
+		{
+			name:     "invalid network cidr",
+			yaml:     "services:\n  web:\n    network:\n      allow_ingress:\n        - cidr: 10.0.0.0\n",
+			line:     5,
+			column:   17,
+			path:     "services.web.network.allow_ingress[0].cidr",
+			severity: SeverityError,
+			message:  `cidr of service web must be a CIDR such as 10.0.0.0/16, found "10.0.0.0"`,
+		},
+		{
+			name:     "invalid cron schedule",
+			yaml:     "services:\n  cleanup:\n    type: job\n    config:\n      schedule:\n        enabled: true\n        value: \"0 25 * * *\"\n",
+			line:     7,
+			column:   16,
+			path:     "services.cleanup.config.schedule.value",
+			severity: SeverityError,
+			message:  "hour 25 is out of range",
+		},
+		{
+			name:     "schedule on a web service",
+			yaml:     "services:\n  web:\n    config:\n      schedule:\n        enabled: true\n        value: \"@daily\"\n",
+			line:     4,
+			column:   7,
+			severity: SeverityError,
+			message:  "only job services can be scheduled",
+		},
+		{
+			name:     "invalid cron service schedule",
+			yaml:     "services:\n  cleanup:\n    type: cron\n    schedule: \"*/5 * * *\"\n",
+			line:     4,
+			column:   15,
+			path:     "services.cleanup.schedule",
+			severity: SeverityError,
+			message:  "expected 5 fields",
+		},
+		{
+			name:     "cron service without a schedule",
+			yaml:     "services:\n  cleanup:\n    type: cron\n    run: ./cleanup\n",
+			line:     3,
+			column:   5,
+			path:     "services.cleanup",
+			severity: SeverityError,
+			message:  "cron service cleanup must set a cron expression in schedule",
+		},
+		{
+			name:     "invalid cron service time zone",
+			yaml:     "services:\n  cleanup:\n    type: cron\n    schedule: \"@daily\"\n    timezone: EST5\n",
+			line:     5,
+			column:   15,
+			path:     "services.cleanup.timezone",
+			severity: SeverityError,
+			message:  "must be an IANA time zone",
+		},
+		{
+			name:     "cron field on a worker",
+			yaml:     "services:\n  worker:\n    schedule: \"@daily\"\n",
+			line:     3,
+			column:   5,
+			path:     "services.worker.schedule",
+			severity: SeverityError,
+			message:  "service worker is a worker service, only cron services can set schedule",
+		},
+		{
+			name:     "worker health check path without a port",
+			yaml:     "services:\n  worker:\n    healthCheck:\n      path: /healthz\n",
+			line:     3,
+			column:   5,
+			path:     "services.worker.healthCheck",
+			severity: SeverityError,
+			message:  "healthCheck of worker service worker sets an HTTP path without a port",
+		},
+		{
+			name:     "health check on a job",
+			yaml:     "services:\n  migrate:\n    type: job\n    healthCheck:\n      path: /healthz\n",
+			line:     4,
+			column:   5,
+			path:     "services.migrate.healthCheck",
+			severity: SeverityError,
+			message:  "so it cannot set healthCheck",
+		},
+		{
+			name:     "invalid startup probe failure threshold",
+			yaml:     "services:\n  web:\n    healthCheck:\n      path: /healthz\n      startupProbe:\n        failureThreshold: 0\n",
+			line:     6,
+			column:   27,
+			path:     "services.web.healthCheck.startupProbe.failureThreshold",
+			severity: SeverityError,
+			message:  "failureThreshold of service web must be a whole number of at least 1",
+		},
+		{
+			name:     "enabled schedule without a value",
+			yaml:     "services:\n  cleanup:\n    type: job\n    config:\n      schedule:\n        enabled: true\n",
+			line:     6,
+			column:   9,
+			severity: SeverityError,
+			message:  "does not set a cron expression in value",
+		},
+		{
+			name:     "app env parsed as a number",
+			yaml:     "env:\n  PORT: 8080\nservices:\n  web: {}\n",
+			line:     2,
+			column:   9,
+			path:     "env.PORT",
+			severity: SeverityError,
+			message:  "env variable PORT was parsed as the number 8080",
+		},
+		{
+			name:     "container env parsed as a yaml 1.1 boolean",
+			yaml:     "services:\n  web:\n    config:\n      container:\n        env:\n          normal:\n            DEBUG: on\n",
+			line:     7,
+			column:   20,
+			path:     "services.web.config.container.env.normal.DEBUG",
+			severity: SeverityError,
+			message:  "env variable DEBUG was parsed as the boolean true",
+		},
+		{
+			name:     "env parsed as a mapping",
+			yaml:     "env:\n  CERT:\n    -----BEGIN: x\nservices:\n  web: {}\n",
+			line:     3,
+			column:   5,
+			severity: SeverityError,
+			message:  "env variable CERT was parsed as a mapping",
+		},
+		{
+			name:     "unresolved fromFile",
+			yaml:     "env:\n  CERT:\n    fromFile: cert.pem\nservices:\n  web: {}\n",
+			line:     3,
+			column:   5,
+			severity: SeverityError,
+			message:  "only supported when applying porter.yaml with the porter CLI",
+		},
+		{
+			name:     "valueFrom without a path",
+			yaml:     "env:\n  DB_PASSWORD:\n    valueFrom:\n      provider: vault\n      key: password\nservices:\n  web: {}\n",
+			line:     4,
+			column:   7,
+			path:     "env.DB_PASSWORD.valueFrom",
+			severity: SeverityError,
+			message:  "valueFrom of env variable DB_PASSWORD must set the path of the secret",
+		},
+		{
+			name:     "env too large for a service",
+			yaml:     "env:\n  A: " + strings.Repeat("a", 64*1024) + "\n  B: " + strings.Repeat("b", 64*1024) + "\n  C: " + strings.Repeat("c", 64*1024) + "\nservices:\n  web:\n    config:\n      container:\n        env:\n          normal:\n            D: " + strings.Repeat("d", 64*1024) + "\n",
+			line:     6,
+			column:   3,
+			path:     "services.web",
+			severity: SeverityError,
+			message:  "env variables of service web are",
+		},
+		{
+			name:     "build method without its settings",
+			yaml:     "build:\n  method: docker\nservices:\n  web: {}\n",
+			line:     2,
+			column:   11,
+			severity: SeverityError,
+			message:  "build method docker requires dockerfile to be set",
+		},
+		{
+			name:     "observability opt out is not a boolean",
+			yaml:     "services:\n  web:\n    observability:\n      enabled: nope\n",
+			line:     4,
+			column:   16,
+			path:     "services.web.observability.enabled",
+			severity: SeverityError,
+			message:  `observability.enabled of service web must be true or false, found "nope"`,
+		},
+		{
+			name:     "service namespace of another app",
+			yaml:     "services:\n  worker:\n    namespace: porter-stack-other\n",
+			opts:     Options{AppName: "test-app"},
+			line:     3,
+			column:   16,
+			path:     "services.worker.namespace",
+			severity: SeverityError,
+			message:  "service worker cannot be deployed to namespace porter-stack-other, which is the namespace of another app",
+		},
+		{
+			name:     "service namespace reserved for kubernetes",
+			yaml:     "services:\n  worker:\n    namespace: kube-system\n",
+			line:     3,
+			column:   16,
+			path:     "services.worker.namespace",
+			severity: SeverityError,
+			message:  "which is reserved for kubernetes",
+		},
+		{
+			name:     "release namespace",
+			yaml:     "services:\n  web: {}\nrelease:\n  run: migrate\n  namespace: workers\n",
+			line:     5,
+			column:   3,
+			path:     "release.namespace",
+			severity: SeverityError,
+			message:  "release always runs in the namespace of the app",
+		},
+		{
+			name:     "scaling schedule of a job",
+			yaml:     "services:\n  cleanup-job:\n    scaling:\n      schedules: []\n",
+			line:     3,
+			column:   5,
+			path:     "services.cleanup-job.scaling",
+			severity: SeverityError,
+			message:  "service cleanup-job is a job service, only web and worker services can have a scaling schedule",
+		},
+		{
+			name:     "scaling schedule with autoscaling",
+			yaml:     "services:\n  web:\n    config:\n      autoscaling:\n        enabled: true\n    scaling:\n      schedules:\n        - {days: \"*\", start: \"08:00\", end: \"20:00\", replicas: 4}\n",
+			line:     6,
+			column:   5,
+			path:     "services.web.scaling",
+			severity: SeverityError,
+			message:  "service web has a scaling schedule and enables autoscaling",
+		},
+		{
+			name:     "overlapping scaling windows",
+			yaml:     "services:\n  web:\n    scaling:\n      schedules:\n        - {name: day, days: \"*\", start: \"08:00\", end: \"20:00\", replicas: 4}\n        - {name: evening, days: FRI, start: \"19:00\", end: \"23:00\", replicas: 6}\n",
+			line:     6,
+			column:   45,
+			path:     "services.web.scaling.schedules[1].start",
+			severity: SeverityError,
+			message:  "window evening overlaps window day on Friday at 19:00",
+		},
+		{
+			name:     "unknown scaling timezone",
+			yaml:     "services:\n  web:\n    scaling:\n      timezone: Europe/Atlantis\n      schedules:\n        - {days: \"*\", start: \"08:00\", end: \"20:00\", replicas: 4}\n",
+			line:     4,
+			column:   17,
+			path:     "services.web.scaling.timezone",
+			severity: SeverityError,
+			message:  `timezone "Europe/Atlantis" is not a known time zone`,
+		},
+		{
+			name:     "unknown scaling window field",
+			yaml:     "services:\n  web:\n    scaling:\n      schedules:\n        - {days: \"*\", start: \"08:00\", end: \"20:00\", replicas: 4, timezone: UTC}\n",
+			line:     5,
+			column:   66,
+			path:     "services.web.scaling.schedules[0].timezone",
+			severity: SeverityWarning,
+			message:  "unknown field timezone is ignored",
+		},
+		{
+			name:     "unknown field",
+			yaml:     "services:\n  web:\n    type: web\n    replicas: 2\n",
+			line:     4,
+			column:   5,
+			path:     "services.web.replicas",
+			severity: SeverityWarning,
+			message:  "unknown field replicas is ignored",
+		},
+		{
+			name:     "no services",
+			yaml:     "version: v1stack\n",
+			line:     1,
+			column:   1,
+			severity: SeverityWarning,
+			message:  "does not define any services",
+		},
+		{
+			name:     "other versions",
+			yaml:     "version: v2\nname: test-app\n",
+			line:     1,
+			column:   10,
+			path:     "version",
+			severity: SeverityNotice,
+			message:  "porter.yaml files with version v2 are validated when they are applied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Lint([]byte(tt.yaml), tt.opts)
+
+			for _, finding := range findings {
+				if !strings.Contains(finding.Message, tt.message) {
+					continue
+				}
+				if finding.Severity != tt.severity {
+					t.Errorf("expected severity %s, got %s", tt.severity, finding.Severity)
+				}
+				if finding.Line != tt.line || (tt.column != 0 && finding.Column != tt.column) {
+					t.Errorf("expected the finding at %d:%d, got %d:%d", tt.line, tt.column, finding.Line, finding.Column)
+				}
+				if tt.path != "" && finding.Path != tt.path {
+					t.Errorf("expected path %q, got %q", tt.path, finding.Path)
+				}
+				if (Errors(findings) != nil) != (tt.severity == SeverityError) {
+					t.Errorf("expected Errors to return an error only for error findings, got %v", Errors(findings))
+				}
+				return
+			}
+
+			t.Fatalf("expected a finding containing %q, got %v", tt.message, findings)
+		})
+	}
+}
+
+func TestLint_ResolvesFromFile(t *testing.T) {
+	porterYaml := "env:\n  CERT:\n    fromFile: cert.pem\nservices:\n  web: {}\n"
+
+	if findings := Lint([]byte(porterYaml), Options{ResolvesFromFile: true}); len(findings) != 0 {
+		t.Fatalf("expected fromFile to be accepted when it is resolved, got %v", findings)
+	}
+}
+
+func TestLint_ValueFrom(t *testing.T) {
+	porterYaml := "env:\n  DB_PASSWORD:\n    valueFrom:\n      provider: vault\n      path: myapp/db\n      key: password\nservices:\n  web:\n    config:\n      container:\n        env:\n          normal:\n            API_KEY:\n              valueFrom:\n                provider: aws_secrets_manager\n                path: myapp/api-key\n"
+
+	if findings := Lint([]byte(porterYaml), Options{}); len(findings) != 0 {
+		t.Fatalf("expected valueFrom to be accepted, got %v", findings)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		path       string
+		porterYaml string
+		line       int
+		column     int
+	}{
+		{path: "services.web.config.container.port", porterYaml: validPorterYaml, line: 15, column: 9},
+		{path: "release", porterYaml: validPorterYaml, line: 43, column: 1},
+		{path: "services.web.config.resources", porterYaml: validPorterYaml},
+		{path: "services.web.run", porterYaml: "apps:\n  web:\n    run: node index.js\n", line: 3, column: 5},
+		{path: "services.web", porterYaml: "services: ["},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			line, column := Locate([]byte(tt.porterYaml), tt.path)
+			if line != tt.line || column != tt.column {
+				t.Errorf("expected %s at %d:%d, got %d:%d", tt.path, tt.line, tt.column, line, column)
+			}
+		})
+	}
+}
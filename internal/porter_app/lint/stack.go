@@ -0,0 +1,976 @@
+package lint
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+	"github.com/porter-dev/porter/internal/porter_app/scaling"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// maxResourceNameLength is the longest name of the kubernetes resources created for a service, which are named
+	// after the app and the service
+	maxResourceNameLength = validation.DNS1123LabelMaxLength
+	// maxCronJobNameLength is the longest name of a cron job, which kubernetes shortens from 63 characters so that the
+	// names of the jobs it creates stay valid labels
+	maxCronJobNameLength = 52
+
+	// minWebPort is the lowest port a web service can listen on, since the container does not run as root
+	minWebPort = 1024
+)
+
+var (
+	stackFields       = []string{"version", "build", "env", "synced_env", "apps", "services", "release", "applications"}
+	applicationFields = []string{"services", "build", "env", "release"}
+	serviceFields     = []string{"run", "config", "type", "observability", "scaling", "namespace", "custom_domains", "network", "schedule", "timezone", "concurrency_policy", "history", "healthCheck"}
+	cronServiceFields = []string{"schedule", "timezone", "concurrency_policy", "history"}
+	cronHistoryFields = []string{"successful", "failed"}
+	networkFields     = []string{"allow_ingress", "allow_egress"}
+	networkPeerFields = []string{"cidr", "namespace", "ports"}
+	healthFields      = []string{"path", "port", "initialDelaySeconds", "periodSeconds", "failureThreshold", "startupProbe"}
+	startupFields     = []string{"periodSeconds", "failureThreshold"}
+	scalingFields     = []string{"timezone", "apply", "schedules"}
+	windowFields      = []string{"name", "days", "start", "end", "replicas"}
+	serviceTypes      = []string{"web", "worker", "job", "cron"}
+	cronConcurrency   = []string{"allow", "forbid", "replace"}
+	buildMethods      = []string{"pack", "docker", "registry"}
+
+	// systemNamespaces are the namespaces of the cluster components which porter installs, which services cannot be
+	// deployed to
+	systemNamespaces = []string{"default", "porter-agent-system", "porter-env-group", "cert-manager", "ingress-nginx", "monitoring"}
+)
+
+// stack lints the root of a v1stack porter.yaml
+func (l *linter) stack(root *yaml.Node) {
+	root = deref(root)
+	if root.Kind != yaml.MappingNode {
+		l.errorf(root, "", "porter.yaml must be a mapping, found %s", kindName(root))
+		return
+	}
+
+	if version := lookup(root, "version"); version != nil {
+		switch version.value.Value {
+		case "", "v1stack":
+		default:
+			l.noticef(version.value, "version", "only v1stack porter.yaml files can be linted, porter.yaml files with version %s are validated when they are applied", version.value.Value)
+			return
+		}
+	}
+
+	l.unknownFields(root, "", stackFields)
+
+	if build := lookup(root, "build"); build != nil {
+		l.build(build.value, "build")
+	}
+
+	if applications := lookup(root, "applications"); applications != nil {
+		if lookup(root, "apps") != nil || lookup(root, "services") != nil {
+			l.errorf(applications.key, "applications", "'applications' cannot be defined alongside 'apps' or 'services'")
+		}
+
+		apps := deref(applications.value)
+		if apps.Kind != yaml.MappingNode {
+			l.errorf(apps, "applications", "applications must be a mapping of application names to applications, found %s", kindName(apps))
+			return
+		}
+		for _, app := range fields(apps) {
+			l.application(app, join("applications", app.key.Value))
+		}
+		return
+	}
+
+	l.services(root, "", l.opts.AppName)
+}
+
+// application lints an application of the legacy applications section, which is deployed as its own app
+func (l *linter) application(app field, path string) {
+	node := deref(app.value)
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, path, "application %s must be a mapping, found %s", app.key.Value, kindName(node))
+		return
+	}
+
+	l.unknownFields(node, path, applicationFields)
+	if lookup(node, "services") == nil {
+		l.errorf(app.key, path, "application %s must define services", app.key.Value)
+	}
+	if build := lookup(node, "build"); build != nil {
+		l.build(build.value, join(path, "build"))
+	}
+
+	l.services(node, path, app.key.Value)
+}
+
+// services lints the env, services and release of an app
+func (l *linter) services(node *yaml.Node, path string, appName string) {
+	appEnv := map[string]string{}
+	if env := lookup(node, "env"); env != nil {
+		appEnv = l.env(env.value, join(path, "env"))
+	}
+
+	apps, services := lookup(node, "apps"), lookup(node, "services")
+	if apps != nil && services != nil {
+		l.errorf(services.key, join(path, "services"), "'apps' and 'services' are synonymous but both were defined")
+	}
+
+	section := services
+	if section == nil {
+		section = apps
+	}
+
+	release := lookup(node, "release")
+
+	if section == nil || isNull(section.value) {
+		if release == nil {
+			l.warnf(node, path, "porter.yaml does not define any services, only the services which are already deployed will be kept")
+		}
+	} else {
+		sectionPath := join(path, section.key.Value)
+		sectionNode := deref(section.value)
+		if sectionNode.Kind != yaml.MappingNode {
+			l.errorf(sectionNode, sectionPath, "%s must be a mapping of service names to services, found %s", section.key.Value, kindName(sectionNode))
+		} else {
+			hosts := map[string]string{}
+			for _, service := range fields(sectionNode) {
+				l.service(service, join(sectionPath, service.key.Value), appName, appEnv, hosts)
+			}
+		}
+	}
+
+	if release != nil && !isNull(release.value) {
+		l.release(release, join(path, "release"), appEnv)
+	}
+}
+
+// service lints a service, and records the custom hosts of web services in hosts so that hosts used by more than one
+// service are reported
+func (l *linter) service(service field, path string, appName string, appEnv map[string]string, hosts map[string]string) {
+	name := service.key.Value
+
+	for _, msg := range validation.IsDNS1123Label(name) {
+		l.errorf(service.key, path, "service name %s is invalid: %s", name, msg)
+	}
+
+	node := deref(service.value)
+	if isNull(node) {
+		node = &yaml.Node{Kind: yaml.MappingNode}
+	}
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, path, "service %s must be a mapping, found %s", name, kindName(node))
+		return
+	}
+
+	l.unknownFields(node, path, serviceFields)
+
+	serviceType := serviceTypeFromName(name)
+	if typeField := lookup(node, "type"); typeField != nil {
+		if !l.oneOf(typeField.value, join(path, "type"), "type of service "+name, serviceTypes) {
+			return
+		}
+		serviceType = deref(typeField.value).Value
+	}
+
+	if run := lookup(node, "run"); run != nil {
+		l.scalar(run.value, join(path, "run"), "run command of service "+name)
+	}
+
+	l.observability(node, path, name)
+	l.namespace(node, path, name, appName)
+
+	config := l.config(node, path, name)
+
+	schedule := l.schedule(config, join(path, "config"), name, serviceType)
+	cron := l.cron(node, path, name, serviceType)
+	l.nameLength(service.key, path, appName, name, serviceType, schedule || cron)
+	l.ports(config, join(path, "config"), name, serviceType)
+	l.hosts(config, join(path, "config"), name, serviceType, hosts)
+	l.customDomains(node, path, name, serviceType, hosts)
+	l.network(node, path, name)
+	l.healthCheck(node, config, path, name, serviceType)
+	l.scaling(node, config, path, name, serviceType)
+
+	l.serviceEnv(service.key, config, path, name, appEnv)
+}
+
+// release lints the pre-deploy job of an app, which always runs as a job
+func (l *linter) release(release *field, path string, appEnv map[string]string) {
+	node := deref(release.value)
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, path, "release must be a mapping, found %s", kindName(node))
+		return
+	}
+
+	l.unknownFields(node, path, serviceFields)
+
+	if typeField := lookup(node, "type"); typeField != nil && deref(typeField.value).Value != "job" {
+		l.errorf(typeField.value, join(path, "type"), "release always runs as a job, but has type %s", describe(deref(typeField.value)))
+	}
+	if run := lookup(node, "run"); run == nil || isNull(run.value) {
+		l.warnf(release.key, path, "release does not set run, so no pre-deploy job will be run")
+	} else {
+		l.scalar(run.value, join(path, "run"), "run command of release")
+	}
+	if namespace := lookup(node, "namespace"); namespace != nil {
+		l.errorf(namespace.key, join(path, "namespace"), "release always runs in the namespace of the app, so it cannot set a namespace")
+	}
+	if customDomains := lookup(node, "custom_domains"); customDomains != nil {
+		l.errorf(customDomains.key, join(path, "custom_domains"), "release runs as a job, so it cannot set custom domains")
+	}
+	if network := lookup(node, "network"); network != nil {
+		l.errorf(network.key, join(path, "network"), "release runs outside of the network policies of the app, so it cannot set network")
+	}
+	if healthCheck := lookup(node, "healthCheck"); healthCheck != nil {
+		l.errorf(healthCheck.key, join(path, "healthCheck"), "release runs as a job, so it cannot set healthCheck")
+	}
+
+	l.observability(node, path, "release")
+
+	config := l.config(node, path, "release")
+	l.scaling(node, config, path, "release", "job")
+	l.serviceEnv(release.key, config, path, "release", appEnv)
+}
+
+// observability checks the setting a service uses to opt out of the OpenTelemetry env variables
+func (l *linter) observability(service *yaml.Node, path string, name string) {
+	observability := lookup(service, "observability")
+	if observability == nil || isNull(observability.value) {
+		return
+	}
+
+	path = join(path, "observability")
+	node := deref(observability.value)
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, path, "observability of service %s must be a mapping, found %s", name, kindName(node))
+		return
+	}
+
+	l.unknownFields(node, path, []string{"enabled"})
+
+	if enabled := lookup(node, "enabled"); enabled != nil && deref(enabled.value).Tag != "!!bool" {
+		l.errorf(deref(enabled.value), join(path, "enabled"), "observability.enabled of service %s must be true or false, found %s", name, describe(deref(enabled.value)))
+	}
+}
+
+// namespace checks the namespace a service overrides the namespace of the app with. Services cannot be deployed to the
+// namespaces of the cluster components or of other apps.
+func (l *linter) namespace(service *yaml.Node, path string, name string, appName string) {
+	namespaceField := lookup(service, "namespace")
+	if namespaceField == nil || isNull(namespaceField.value) {
+		return
+	}
+
+	path = join(path, "namespace")
+	node := deref(namespaceField.value)
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!str" {
+		l.errorf(node, path, "namespace of service %s must be a string, found %s", name, describe(node))
+		return
+	}
+
+	namespace := node.Value
+	if msgs := validation.IsDNS1123Label(namespace); len(msgs) > 0 {
+		for _, msg := range msgs {
+			l.errorf(node, path, "namespace %s of service %s is invalid: %s", namespace, name, msg)
+		}
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(namespace, "kube-"):
+		l.errorf(node, path, "service %s cannot be deployed to namespace %s, which is reserved for kubernetes", name, namespace)
+	case strings.HasPrefix(namespace, "porter-stack-") && (appName == "" || namespace != "porter-stack-"+appName):
+		l.errorf(node, path, "service %s cannot be deployed to namespace %s, which is the namespace of another app", name, namespace)
+	case contains(systemNamespaces, namespace):
+		l.errorf(node, path, "service %s cannot be deployed to namespace %s, which holds components of the cluster", name, namespace)
+	}
+}
+
+// network checks the traffic a service allows beyond the network policy generated for it. Each peer is either a CIDR
+// or a namespace, and may limit the traffic to some ports.
+func (l *linter) network(service *yaml.Node, path string, name string) {
+	networkField := lookup(service, "network")
+	if networkField == nil || isNull(networkField.value) {
+		return
+	}
+
+	path = join(path, "network")
+	node := deref(networkField.value)
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, path, "network of service %s must be a mapping, found %s", name, kindName(node))
+		return
+	}
+
+	l.unknownFields(node, path, networkFields)
+
+	for _, direction := range networkFields {
+		peersField := lookup(node, direction)
+		if peersField == nil || isNull(peersField.value) {
+			continue
+		}
+
+		peersPath := join(path, direction)
+		peers := deref(peersField.value)
+		if peers.Kind != yaml.SequenceNode {
+			l.errorf(peers, peersPath, "%s of service %s must be a list, found %s", direction, name, kindName(peers))
+			continue
+		}
+
+		for i, peer := range peers.Content {
+			l.networkPeer(deref(peer), fmt.Sprintf("%s[%d]", peersPath, i), name)
+		}
+	}
+}
+
+func (l *linter) networkPeer(peer *yaml.Node, path string, name string) {
+	if peer.Kind != yaml.MappingNode {
+		l.errorf(peer, path, "network peer of service %s must be a mapping, found %s", name, kindName(peer))
+		return
+	}
+
+	l.unknownFields(peer, path, networkPeerFields)
+
+	cidr, namespace := lookup(peer, "cidr"), lookup(peer, "namespace")
+	switch {
+	case cidr == nil && namespace == nil:
+		l.errorf(peer, path, "network peer of service %s must set cidr or namespace", name)
+	case cidr != nil && namespace != nil:
+		l.errorf(peer, path, "network peer of service %s must set only one of cidr and namespace", name)
+	case cidr != nil:
+		node := deref(cidr.value)
+		if _, _, err := net.ParseCIDR(node.Value); node.Kind != yaml.ScalarNode || err != nil {
+			l.errorf(node, join(path, "cidr"), "cidr of service %s must be a CIDR such as 10.0.0.0/16, found %s", name, describe(node))
+		}
+	default:
+		node := deref(namespace.value)
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!str" || len(validation.IsDNS1123Label(node.Value)) > 0 {
+			l.errorf(node, join(path, "namespace"), "namespace of service %s must be a namespace name, found %s", name, describe(node))
+		}
+	}
+
+	portsField := lookup(peer, "ports")
+	if portsField == nil || isNull(portsField.value) {
+		return
+	}
+
+	ports := deref(portsField.value)
+	if ports.Kind != yaml.SequenceNode {
+		l.errorf(ports, join(path, "ports"), "ports of service %s must be a list, found %s", name, kindName(ports))
+		return
+	}
+	for _, port := range ports.Content {
+		port = deref(port)
+		if value, err := strconv.Atoi(port.Value); port.Kind != yaml.ScalarNode || err != nil || value < 1 || value > 65535 {
+			l.errorf(port, join(path, "ports"), "port of service %s must be a number between 1 and 65535, found %s", name, describe(port))
+		}
+	}
+}
+
+// scaling checks the scaling schedule of a service, which cannot be combined with autoscaling since both would set the
+// replicas of the service
+func (l *linter) scaling(service *yaml.Node, config *yaml.Node, path string, name string, serviceType string) {
+	scalingField := lookup(service, "scaling")
+	if scalingField == nil || isNull(scalingField.value) {
+		return
+	}
+
+	path = join(path, "scaling")
+	node := deref(scalingField.value)
+
+	if serviceType == "job" || serviceType == "cron" {
+		l.errorf(scalingField.key, path, "service %s is a %s service, only web and worker services can have a scaling schedule", name, serviceType)
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, path, "scaling of service %s must be a mapping, found %s", name, kindName(node))
+		return
+	}
+
+	l.unknownFields(node, path, scalingFields)
+
+	if enabled := lookupPath(config, "autoscaling", "enabled"); enabled != nil && deref(enabled.value).Value == "true" {
+		l.errorf(scalingField.key, path, "service %s has a scaling schedule and enables autoscaling, which would both set its replicas: remove the schedule or disable config.autoscaling", name)
+	}
+
+	var windows []*yaml.Node
+	if schedules := lookup(node, "schedules"); schedules != nil && !isNull(schedules.value) {
+		list := deref(schedules.value)
+		if list.Kind != yaml.SequenceNode {
+			l.errorf(list, join(path, "schedules"), "schedules of service %s must be a list, found %s", name, kindName(list))
+			return
+		}
+
+		for i, window := range list.Content {
+			window = deref(window)
+			if window.Kind != yaml.MappingNode {
+				l.errorf(window, windowPath(path, i), "scaling window of service %s must be a mapping, found %s", name, kindName(window))
+				return
+			}
+			l.unknownFields(window, windowPath(path, i), windowFields)
+			windows = append(windows, window)
+		}
+	}
+
+	var schedule types.ServiceScalingSchedule
+	if err := node.Decode(&schedule); err != nil {
+		l.errorf(node, path, "scaling of service %s is invalid: %s", name, err)
+		return
+	}
+
+	_, errs := scaling.Compile(schedule)
+	for _, err := range errs {
+		target, targetPath := node, path
+		if err.Window >= 0 && err.Window < len(windows) {
+			target, targetPath = windows[err.Window], windowPath(path, err.Window)
+		}
+		if f := lookup(target, err.Field); f != nil {
+			target, targetPath = deref(f.value), join(targetPath, err.Field)
+		}
+
+		l.errorf(target, targetPath, "scaling schedule of service %s is invalid: %s", name, err.Message)
+	}
+}
+
+func windowPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", join(path, "schedules"), i)
+}
+
+// config returns the config of a service, or nil if it is not set or is not a mapping
+func (l *linter) config(service *yaml.Node, path string, name string) *yaml.Node {
+	config := lookup(service, "config")
+	if config == nil || isNull(config.value) {
+		return nil
+	}
+
+	node := deref(config.value)
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, join(path, "config"), "config of service %s must be a mapping, found %s", name, kindName(node))
+		return nil
+	}
+
+	return node
+}
+
+// nameLength checks that the kubernetes resources created for a service are named within the kubernetes limits. The
+// resources are named after the helm chart of the service within the app, such as my-app-web-web for a web service
+// named web.
+func (l *linter) nameLength(key *yaml.Node, path string, appName string, name string, serviceType string, scheduled bool) {
+	resourceName := helmName(name, serviceType)
+	if appName != "" {
+		resourceName = appName + "-" + resourceName
+	}
+
+	limit, kind := maxResourceNameLength, "resources"
+	if scheduled {
+		limit, kind = maxCronJobNameLength, "cron job"
+	}
+
+	if len(resourceName) <= limit {
+		return
+	}
+
+	if appName == "" {
+		l.errorf(key, path, "service name %s is too long: the name of its %s would be %s, which is longer than the limit of %d characters", name, kind, resourceName, limit)
+		return
+	}
+
+	l.errorf(key, path, "service name %s is too long for app %s: the name of its %s would be %s, which is longer than the limit of %d characters", name, appName, kind, resourceName, limit)
+}
+
+// schedule checks the cron schedule of a service, and reports whether the service runs on a schedule
+func (l *linter) schedule(config *yaml.Node, path string, name string, serviceType string) bool {
+	schedule := lookup(config, "schedule")
+	if schedule == nil || isNull(schedule.value) {
+		return false
+	}
+
+	path = join(path, "schedule")
+	node := deref(schedule.value)
+
+	if serviceType == "cron" {
+		l.errorf(schedule.key, path, "service %s is a cron service, which sets its cron expression in schedule rather than config.schedule", name)
+		return false
+	}
+	if serviceType != "job" {
+		l.errorf(schedule.key, path, "service %s is a %s service, only job services can be scheduled", name, serviceType)
+		return false
+	}
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, path, "schedule of service %s must be a mapping, found %s", name, kindName(node))
+		return false
+	}
+
+	enabled := lookup(node, "enabled")
+	if enabled == nil || deref(enabled.value).Value != "true" {
+		return false
+	}
+
+	value := lookup(node, "value")
+	if value == nil || deref(value.value).Value == "" {
+		l.errorf(enabled.key, path, "schedule of service %s is enabled, but does not set a cron expression in value", name)
+		return true
+	}
+
+	expr := deref(value.value)
+	if err := ValidateCron(expr.Value); err != nil {
+		l.errorf(expr, join(path, "value"), "schedule of service %s is invalid: %s", name, err)
+	}
+
+	return true
+}
+
+// cron checks the schedule of a cron service, and that only cron services set the fields of one. It reports whether
+// the service is a cron service with a schedule.
+func (l *linter) cron(service *yaml.Node, path string, name string, serviceType string) bool {
+	if serviceType != "cron" {
+		for _, key := range cronServiceFields {
+			if f := lookup(service, key); f != nil {
+				l.errorf(f.key, join(path, key), "service %s is a %s service, only cron services can set %s", name, serviceType, key)
+			}
+		}
+		return false
+	}
+
+	schedule := lookup(service, "schedule")
+	if schedule == nil || isNull(schedule.value) {
+		l.errorf(service, path, "cron service %s must set a cron expression in schedule, such as \"0 * * * *\"", name)
+		return false
+	}
+
+	expr := deref(schedule.value)
+	switch {
+	case expr.Kind != yaml.ScalarNode || expr.Tag != "!!str":
+		l.errorf(expr, join(path, "schedule"), "schedule of service %s must be a cron expression, found %s", name, describe(expr))
+	case strings.HasPrefix(strings.TrimSpace(expr.Value), "TZ=") || strings.HasPrefix(strings.TrimSpace(expr.Value), "CRON_TZ="):
+		l.errorf(expr, join(path, "schedule"), "schedule of service %s sets a time zone, which is set in timezone instead", name)
+	default:
+		if err := ValidateCron(expr.Value); err != nil {
+			l.errorf(expr, join(path, "schedule"), "schedule of service %s is invalid: %s", name, err)
+		}
+	}
+
+	if timezone := lookup(service, "timezone"); timezone != nil && !isNull(timezone.value) {
+		node := deref(timezone.value)
+		// the cluster reads time zones from its own database, so UTC offsets and abbreviations such as EST are rejected
+		if _, err := time.LoadLocation(node.Value); node.Kind != yaml.ScalarNode || node.Value == "" || node.Value == "Local" || err != nil {
+			l.errorf(node, join(path, "timezone"), "timezone of service %s must be an IANA time zone such as America/New_York, found %s", name, describe(node))
+		}
+	}
+
+	if policy := lookup(service, "concurrency_policy"); policy != nil && !isNull(policy.value) {
+		l.oneOf(policy.value, join(path, "concurrency_policy"), "concurrency policy of service "+name, cronConcurrency)
+	}
+
+	if history := lookup(service, "history"); history != nil && !isNull(history.value) {
+		historyPath := join(path, "history")
+		node := deref(history.value)
+		if node.Kind != yaml.MappingNode {
+			l.errorf(node, historyPath, "history of service %s must be a mapping, found %s", name, kindName(node))
+			return true
+		}
+
+		l.unknownFields(node, historyPath, cronHistoryFields)
+		for _, key := range cronHistoryFields {
+			f := lookup(node, key)
+			if f == nil || isNull(f.value) {
+				continue
+			}
+
+			limit := deref(f.value)
+			if value, err := strconv.Atoi(limit.Value); limit.Kind != yaml.ScalarNode || err != nil || value < 0 {
+				l.errorf(limit, join(historyPath, key), "history of service %s must keep zero or more %s runs, found %s", name, key, describe(limit))
+			}
+		}
+	}
+
+	return true
+}
+
+// healthCheck checks the probes of a web or worker service. Probes are sent over HTTP to the path, on the port of the
+// container of web services unless another port is set, so worker services which have no port must set one.
+func (l *linter) healthCheck(service *yaml.Node, config *yaml.Node, path string, name string, serviceType string) {
+	healthCheck := lookup(service, "healthCheck")
+	if healthCheck == nil || isNull(healthCheck.value) {
+		return
+	}
+
+	configPath := join(path, "config")
+	path = join(path, "healthCheck")
+	if serviceType != "web" && serviceType != "worker" {
+		l.errorf(healthCheck.key, path, "service %s is a %s service, which runs to completion, so it cannot set healthCheck", name, serviceType)
+		return
+	}
+
+	node := deref(healthCheck.value)
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, path, "healthCheck of service %s must be a mapping, found %s", name, kindName(node))
+		return
+	}
+
+	l.unknownFields(node, path, healthFields)
+
+	if health := lookup(config, "health"); health != nil {
+		l.warnf(health.key, join(configPath, "health"), "config.health of service %s is replaced by its healthCheck", name)
+	}
+
+	probePath := lookup(node, "path")
+	if probePath == nil || isNull(probePath.value) {
+		l.errorf(healthCheck.key, path, "healthCheck of service %s must set the HTTP path it is probed on, such as /healthz", name)
+	} else if value := deref(probePath.value); value.Kind != yaml.ScalarNode || !strings.HasPrefix(value.Value, "/") {
+		l.errorf(value, join(path, "path"), "path of the healthCheck of service %s must start with /, found %s", name, describe(value))
+	}
+
+	port := lookup(node, "port")
+	l.port(port, join(path, "port"), name, serviceType)
+	if serviceType == "worker" && probePath != nil && (port == nil || isNull(port.value)) {
+		l.errorf(healthCheck.key, path, "healthCheck of worker service %s sets an HTTP path without a port, which workers do not expose by default", name)
+	}
+
+	l.probeSetting(node, path, name, "initialDelaySeconds", 0)
+	l.probeSetting(node, path, name, "periodSeconds", 1)
+	l.probeSetting(node, path, name, "failureThreshold", 1)
+
+	startupProbe := lookup(node, "startupProbe")
+	if startupProbe == nil || isNull(startupProbe.value) {
+		return
+	}
+
+	startupPath := join(path, "startupProbe")
+	startup := deref(startupProbe.value)
+	if startup.Kind != yaml.MappingNode {
+		l.errorf(startup, startupPath, "startupProbe of service %s must be a mapping, found %s", name, kindName(startup))
+		return
+	}
+
+	l.unknownFields(startup, startupPath, startupFields)
+	l.probeSetting(startup, startupPath, name, "periodSeconds", 1)
+	l.probeSetting(startup, startupPath, name, "failureThreshold", 1)
+}
+
+// probeSetting checks that a number of seconds or of failures of a probe is a whole number of at least lowest
+func (l *linter) probeSetting(probe *yaml.Node, path string, name string, key string, lowest int) {
+	f := lookup(probe, key)
+	if f == nil || isNull(f.value) {
+		return
+	}
+
+	node := deref(f.value)
+	if value, err := strconv.Atoi(node.Value); node.Kind != yaml.ScalarNode || err != nil || value < lowest {
+		l.errorf(node, join(path, key), "%s of service %s must be a whole number of at least %d, found %s", key, name, lowest, describe(node))
+	}
+}
+
+// ports checks that the port of a service is in range, and that the port its kubernetes service exposes is the port
+// its container listens on
+func (l *linter) ports(config *yaml.Node, path string, name string, serviceType string) {
+	containerPort := l.port(lookupPath(config, "container", "port"), join(path, "container.port"), name, serviceType)
+	servicePort := l.port(lookupPath(config, "service", "port"), join(path, "service.port"), name, serviceType)
+
+	if containerPort != nil && servicePort != nil && containerPort.Value != servicePort.Value {
+		l.errorf(servicePort, join(path, "service.port"), "service %s exposes port %s, but its container listens on port %s", name, servicePort.Value, containerPort.Value)
+	}
+}
+
+func (l *linter) port(port *field, path string, name string, serviceType string) *yaml.Node {
+	if port == nil || isNull(port.value) {
+		return nil
+	}
+
+	node := deref(port.value)
+	value, err := strconv.Atoi(node.Value)
+	if node.Kind != yaml.ScalarNode || err != nil {
+		l.errorf(node, path, "port of service %s must be a number, found %s", name, describe(node))
+		return nil
+	}
+
+	lowest := 1
+	if serviceType == "web" {
+		lowest = minWebPort
+	}
+	if value < lowest || value > 65535 {
+		l.errorf(node, path, "port of %s service %s must be between %d and 65535, found %d", serviceType, name, lowest, value)
+		return nil
+	}
+
+	return node
+}
+
+// hosts reports custom domains which are routed to more than one web service
+func (l *linter) hosts(config *yaml.Node, path string, name string, serviceType string, hosts map[string]string) {
+	if serviceType != "web" {
+		return
+	}
+
+	// hosts of a service without an ingress are not routed to it
+	if enabled := lookupPath(config, "ingress", "enabled"); enabled != nil && deref(enabled.value).Value == "false" {
+		return
+	}
+
+	path = join(path, "ingress.hosts")
+	hostsField := lookupPath(config, "ingress", "hosts")
+	if hostsField == nil || isNull(hostsField.value) {
+		return
+	}
+
+	node := deref(hostsField.value)
+	if node.Kind != yaml.SequenceNode {
+		l.errorf(node, path, "hosts of service %s must be a list, found %s", name, kindName(node))
+		return
+	}
+
+	for _, host := range node.Content {
+		host = deref(host)
+		if host.Kind != yaml.ScalarNode || host.Value == "" {
+			continue
+		}
+
+		if other, ok := hosts[host.Value]; ok && other != name {
+			l.errorf(host, path, "host %s of service %s is also routed to service %s", host.Value, name, other)
+			continue
+		}
+		hosts[host.Value] = name
+	}
+}
+
+// customDomains checks the custom domains a web service is served on, which must be valid domain names and are
+// recorded in hosts along with the hosts set in the config of services
+func (l *linter) customDomains(service *yaml.Node, path string, name string, serviceType string, hosts map[string]string) {
+	customDomainsField := lookup(service, "custom_domains")
+	if customDomainsField == nil || isNull(customDomainsField.value) {
+		return
+	}
+
+	path = join(path, "custom_domains")
+	if serviceType != "web" {
+		l.errorf(customDomainsField.key, path, "service %s is a %s service, only web services can have custom domains", name, serviceType)
+		return
+	}
+
+	node := deref(customDomainsField.value)
+	if node.Kind != yaml.SequenceNode {
+		l.errorf(node, path, "custom domains of service %s must be a list, found %s", name, kindName(node))
+		return
+	}
+
+	for _, domain := range node.Content {
+		domain = deref(domain)
+		if domain.Kind != yaml.ScalarNode || domain.Tag != "!!str" {
+			l.errorf(domain, path, "custom domain of service %s must be a string, found %s", name, describe(domain))
+			continue
+		}
+
+		if err := ValidateDomain(domain.Value); err != nil {
+			l.errorf(domain, path, "custom domain %s of service %s is invalid: %s", domain.Value, name, err)
+			continue
+		}
+
+		if other, ok := hosts[domain.Value]; ok {
+			if other == name {
+				l.errorf(domain, path, "custom domain %s is already a host of service %s", domain.Value, name)
+			} else {
+				l.errorf(domain, path, "custom domain %s of service %s is also routed to service %s", domain.Value, name, other)
+			}
+			continue
+		}
+		hosts[domain.Value] = name
+	}
+}
+
+// serviceEnv checks the container env of a service, and the size of the env it is deployed with, which is the app's
+// env merged with its own
+func (l *linter) serviceEnv(key *yaml.Node, config *yaml.Node, path string, name string, appEnv map[string]string) {
+	env := make(map[string]string, len(appEnv))
+	for k, v := range appEnv {
+		env[k] = v
+	}
+
+	if normal := lookupPath(config, "container", "env", "normal"); normal != nil {
+		for k, v := range l.env(normal.value, join(path, "config.container.env.normal")) {
+			env[k] = v
+		}
+	}
+
+	if err := envvalues.CheckSize(fmt.Sprintf("service %s", name), env); err != nil {
+		l.errorf(key, path, "%s", err)
+	}
+}
+
+// env checks that every value of an env section is a string, and returns the values which are known before porter.yaml
+// is applied
+func (l *linter) env(node *yaml.Node, path string) map[string]string {
+	values := map[string]string{}
+
+	node = deref(node)
+	if isNull(node) {
+		return values
+	}
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, path, "env must be a mapping of env variable names to values, found %s", kindName(node))
+		return values
+	}
+
+	for _, variable := range fields(node) {
+		key, value := variable.key.Value, deref(variable.value)
+		variablePath := join(path, key)
+
+		switch value.Kind {
+		case yaml.ScalarNode:
+			if err := envvalues.CheckScalar(key, value); err != nil {
+				l.errorf(value, variablePath, "%s", err)
+				continue
+			}
+			if !isNull(value) {
+				values[key] = value.Value
+			}
+		case yaml.MappingNode:
+			// values read from a secret store are only known once the server resolves them
+			if valueFrom := lookup(value, envvalues.ValueFromKey); valueFrom != nil && len(value.Content) == 2 {
+				if _, err := envvalues.ParseSecretReference(key, deref(valueFrom.value)); err != nil {
+					l.errorf(valueFrom.value, join(variablePath, envvalues.ValueFromKey), "%s", err)
+				}
+				continue
+			}
+			if lookup(value, envvalues.FromFileKey) == nil || len(value.Content) != 2 {
+				_, err := envvalues.FromYAML(key, map[string]interface{}{})
+				l.errorf(value, variablePath, "%s", err)
+				continue
+			}
+			if !l.opts.ResolvesFromFile {
+				l.errorf(value, variablePath, "%s", envvalues.FromFileNotResolvedError(key))
+			}
+		case yaml.SequenceNode:
+			_, err := envvalues.FromYAML(key, []interface{}{})
+			l.errorf(value, variablePath, "%s", err)
+		}
+	}
+
+	return values
+}
+
+// build checks the build settings, which must name the image to deploy or how to build it
+func (l *linter) build(node *yaml.Node, path string) {
+	node = deref(node)
+	if isNull(node) {
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, path, "build must be a mapping, found %s", kindName(node))
+		return
+	}
+
+	method := lookup(node, "method")
+	if method == nil {
+		l.errorf(node, path, "build must set method to one of %s", strings.Join(buildMethods, ", "))
+		return
+	}
+	if !l.oneOf(method.value, join(path, "method"), "build method", buildMethods) {
+		return
+	}
+
+	methodValue := deref(method.value).Value
+	required := map[string]string{"pack": "builder", "docker": "dockerfile", "registry": "image"}[methodValue]
+	if value := lookup(node, required); value == nil || deref(value.value).Value == "" {
+		l.errorf(method.value, join(path, "method"), "build method %s requires %s to be set", methodValue, required)
+	}
+}
+
+// unknownFields warns about fields which Porter ignores, which are most often misspelled or misplaced
+func (l *linter) unknownFields(node *yaml.Node, path string, known []string) {
+	for _, f := range fields(node) {
+		if !contains(known, f.key.Value) {
+			l.warnf(f.key, join(path, f.key.Value), "unknown field %s is ignored, expected one of %s", f.key.Value, strings.Join(known, ", "))
+		}
+	}
+}
+
+// oneOf checks that a node is one of the allowed values, and reports whether it is
+func (l *linter) oneOf(node *yaml.Node, path string, description string, allowed []string) bool {
+	node = deref(node)
+	if node.Kind == yaml.ScalarNode && contains(allowed, node.Value) {
+		return true
+	}
+
+	l.errorf(node, path, "%s must be one of %s, found %s", description, strings.Join(allowed, ", "), describe(node))
+	return false
+}
+
+// scalar checks that a node is a single value rather than a mapping or a list
+func (l *linter) scalar(node *yaml.Node, path string, description string) {
+	node = deref(node)
+	if node.Kind != yaml.ScalarNode {
+		l.errorf(node, path, "%s must be a string, found %s", description, kindName(node))
+	}
+}
+
+// lookupPath returns the field at a path of nested mappings, or nil if any of them is not set
+func lookupPath(node *yaml.Node, keys ...string) *field {
+	var f *field
+	for _, key := range keys {
+		f = lookup(node, key)
+		if f == nil {
+			return nil
+		}
+		node = deref(f.value)
+	}
+
+	return f
+}
+
+// deref returns the node an alias refers to
+func deref(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	return node
+}
+
+// describe returns the value of a scalar node, or the kind of any other node
+func describe(node *yaml.Node) string {
+	if node.Kind == yaml.ScalarNode && !isNull(node) {
+		return strconv.Quote(node.Value)
+	}
+
+	return kindName(node)
+}
+
+// serviceTypeFromName infers the type of a service which does not set one from its name, as Porter does when deploying
+func serviceTypeFromName(name string) string {
+	if strings.Contains(name, "web") {
+		return "web"
+	}
+	if strings.Contains(name, "job") {
+		return "job"
+	}
+
+	return "worker"
+}
+
+// helmName is the name of the chart of a service within the app's umbrella chart
+func helmName(name string, serviceType string) string {
+	switch serviceType {
+	case "web":
+		return name + "-web"
+	case "worker":
+		return name + "-wkr"
+	case "job", "cron":
+		return name + "-job"
+	}
+
+	return name
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,315 @@
+package logalerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+// RuleStore lists the rules to evaluate, and records when they fire
+type RuleStore interface {
+	ListEnabledLogAlertRules(ctx context.Context) ([]*models.LogAlertRule, error)
+	UpdateLogAlertRule(ctx context.Context, rule *models.LogAlertRule) (*models.LogAlertRule, error)
+}
+
+// AppReader reads the porter app a rule belongs to
+type AppReader interface {
+	ReadPorterAppByID(ctx context.Context, id uint) (*models.PorterApp, error)
+}
+
+// EventStore records alerts in an app's activity feed
+type EventStore interface {
+	CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error
+}
+
+// LogSource connects to the clusters that apps run on to read their logs
+type LogSource interface {
+	// Connect returns a reader for the logs on a cluster, or an error if the cluster is unreachable
+	Connect(ctx context.Context, projectID, clusterID uint) (AppLogReader, error)
+}
+
+// AppLogReader reads the logs of apps on a single cluster
+type AppLogReader interface {
+	// AppLogs returns up to query.Limit of the most recent log lines of an app between query.Start and query.End
+	AppLogs(ctx context.Context, query LogQuery) ([]porter_app.StructuredLog, error)
+}
+
+// LogQuery selects the logs of an app in a deployment target
+type LogQuery struct {
+	AppName            string
+	DeploymentTargetID uuid.UUID
+	Namespace          string
+	Start              time.Time
+	End                time.Time
+	Limit              uint
+}
+
+// Notifier sends a fired alert to its rule's notification channel
+type Notifier interface {
+	Notify(ctx context.Context, rule *models.LogAlertRule, alert types.PorterAppAlertEventMetadata) error
+}
+
+// Options configure how often rules are evaluated and how much work each evaluation does. Zero values use the defaults.
+type Options struct {
+	// Interval is the time between evaluations of every rule. Defaults to 1m
+	Interval time.Duration
+	// ClusterTimeout bounds the time spent reading logs from a single cluster in each evaluation, so that an unreachable
+	// cluster cannot stall the others. Defaults to 30s
+	ClusterTimeout time.Duration
+	// MaxLinesPerApp bounds the number of log lines read for each app in each evaluation. Defaults to 5000
+	MaxLinesPerApp uint
+	// MatchBudget bounds the time spent matching a single rule against an app's logs in each evaluation. Defaults to 250ms
+	MatchBudget time.Duration
+	// Concurrency is the number of clusters evaluated at once. Defaults to 5
+	Concurrency int
+	// Logger receives a record of skipped clusters, fired alerts and failed notifications. Optional
+	Logger *logger.Logger
+}
+
+// Evaluator periodically matches log alert rules against the logs of their apps, and fires the rules which exceed their threshold
+type Evaluator struct {
+	rules    RuleStore
+	apps     AppReader
+	events   EventStore
+	logs     LogSource
+	notifier Notifier
+	opts     Options
+	log      worker.Logger
+}
+
+// NewEvaluator returns an Evaluator with the given options
+func NewEvaluator(rules RuleStore, apps AppReader, events EventStore, logs LogSource, notifier Notifier, opts Options) *Evaluator {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.ClusterTimeout <= 0 {
+		opts.ClusterTimeout = 30 * time.Second
+	}
+	if opts.MaxLinesPerApp == 0 {
+		opts.MaxLinesPerApp = 5000
+	}
+	if opts.MatchBudget <= 0 {
+		opts.MatchBudget = 250 * time.Millisecond
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 5
+	}
+
+	return &Evaluator{
+		rules:    rules,
+		apps:     apps,
+		events:   events,
+		logs:     logs,
+		notifier: notifier,
+		opts:     opts,
+		log:      worker.NewLogger(opts.Logger),
+	}
+}
+
+// Run evaluates every enabled rule once per interval until ctx is cancelled
+func (e *Evaluator) Run(ctx context.Context) error {
+	return worker.Run(ctx, e.opts.Interval, e.log, "error evaluating log alert rules", e.evaluate)
+}
+
+type appKey struct {
+	porterAppID        uint
+	deploymentTargetID uuid.UUID
+}
+
+// evaluate matches every enabled rule against the logs ending at now, returning once every cluster has been evaluated
+// or has timed out
+func (e *Evaluator) evaluate(ctx context.Context, now time.Time) error {
+	rules, err := e.rules.ListEnabledLogAlertRules(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing enabled log alert rules: %w", err)
+	}
+
+	clusters := make(map[worker.ClusterKey]map[appKey][]*models.LogAlertRule)
+	for _, rule := range rules {
+		ck := worker.ClusterKey{ProjectID: rule.ProjectID, ClusterID: rule.ClusterID}
+		if clusters[ck] == nil {
+			clusters[ck] = make(map[appKey][]*models.LogAlertRule)
+		}
+
+		ak := appKey{porterAppID: rule.PorterAppID, deploymentTargetID: rule.DeploymentTargetID}
+		clusters[ck][ak] = append(clusters[ck][ak], rule)
+	}
+
+	worker.EachCluster(ctx, clusters, worker.ClusterOptions{
+		Timeout:     e.opts.ClusterTimeout,
+		Concurrency: e.opts.Concurrency,
+		Action:      "evaluating log alert rules",
+		Logger:      e.log,
+	}, func(ctx context.Context, ck worker.ClusterKey, apps map[appKey][]*models.LogAlertRule) {
+		e.evaluateCluster(ctx, ck, apps, now)
+	})
+
+	return nil
+}
+
+func (e *Evaluator) evaluateCluster(ctx context.Context, ck worker.ClusterKey, apps map[appKey][]*models.LogAlertRule, now time.Time) {
+	reader, err := e.logs.Connect(ctx, ck.ProjectID, ck.ClusterID)
+	if err != nil {
+		e.log.Cluster(zerolog.WarnLevel, ck).Err(err).Msg("cluster is unreachable, skipping its log alert rules until the next evaluation")
+		return
+	}
+
+	keys := make([]appKey, 0, len(apps))
+	for ak := range apps {
+		keys = append(keys, ak)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].porterAppID < keys[j].porterAppID
+	})
+
+	for _, ak := range keys {
+		if ctx.Err() != nil {
+			return
+		}
+
+		e.evaluateApp(ctx, ck, reader, apps[ak], now)
+	}
+}
+
+func (e *Evaluator) evaluateApp(ctx context.Context, ck worker.ClusterKey, reader AppLogReader, rules []*models.LogAlertRule, now time.Time) {
+	first := rules[0]
+
+	app, err := e.apps.ReadPorterAppByID(ctx, first.PorterAppID)
+	if err != nil {
+		e.log.Cluster(zerolog.WarnLevel, ck).Err(err).Uint("porter_app_id", first.PorterAppID).Msg("error reading porter app for log alert rules")
+		return
+	}
+
+	// rules which fired within their window are skipped, so that a burst of errors sends a single alert
+	var active []*models.LogAlertRule
+	var window uint
+	for _, rule := range rules {
+		if rule.LastFiredAt != nil && now.Sub(*rule.LastFiredAt) < time.Duration(rule.WindowMinutes)*time.Minute {
+			continue
+		}
+
+		active = append(active, rule)
+		if rule.WindowMinutes > window {
+			window = rule.WindowMinutes
+		}
+	}
+	if len(active) == 0 {
+		return
+	}
+
+	logs, err := reader.AppLogs(ctx, LogQuery{
+		AppName:            app.Name,
+		DeploymentTargetID: first.DeploymentTargetID,
+		Namespace:          first.Namespace,
+		Start:              now.Add(-time.Duration(window) * time.Minute),
+		End:                now,
+		Limit:              e.opts.MaxLinesPerApp,
+	})
+	if err != nil {
+		e.log.Cluster(zerolog.WarnLevel, ck).Err(err).Str("app_name", app.Name).Msg("error reading logs for log alert rules")
+		return
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Timestamp.After(logs[j].Timestamp)
+	})
+
+	for _, rule := range active {
+		m, err := newMatcher(types.LogAlertMatchType(rule.MatchType), rule.Pattern)
+		if err != nil {
+			e.logRule(zerolog.WarnLevel, rule).Err(err).Msg("skipping invalid log alert rule")
+			continue
+		}
+
+		res := evaluateRule(rule, m, logs, now, e.opts.MatchBudget)
+		if res.BudgetExceeded {
+			e.logRule(zerolog.WarnLevel, rule).Int("match_count", res.Count).Msg("log alert rule exceeded its match budget, only part of the logs were checked")
+		}
+
+		if res.Count > int(rule.Threshold) && ctx.Err() == nil {
+			e.fire(ctx, app, rule, res, now)
+		}
+	}
+}
+
+// fire records an alert in the app's activity feed and sends it to the rule's notification channel
+func (e *Evaluator) fire(ctx context.Context, app *models.PorterApp, rule *models.LogAlertRule, res evaluation, now time.Time) {
+	eventID := uuid.New()
+
+	alert := types.PorterAppAlertEventMetadata{
+		EventID:       eventID.String(),
+		RuleID:        rule.ID,
+		RuleName:      rule.Name,
+		AppName:       app.Name,
+		ServiceName:   rule.ServiceName,
+		MatchType:     types.LogAlertMatchType(rule.MatchType),
+		Pattern:       rule.Pattern,
+		Threshold:     rule.Threshold,
+		WindowMinutes: rule.WindowMinutes,
+		MatchCount:    res.Count,
+		Snippet:       res.Snippet,
+		Channel:       types.LogAlertChannel(rule.Channel),
+		FiredAt:       now,
+	}
+
+	metadata, err := alertEventMetadata(alert)
+	if err != nil {
+		e.logRule(zerolog.ErrorLevel, rule).Err(err).Msg("error encoding log alert")
+		return
+	}
+
+	event := &models.PorterAppEvent{
+		ID:                 eventID,
+		Type:               string(types.PorterAppEventType_Alert),
+		PorterAppID:        rule.PorterAppID,
+		DeploymentTargetID: rule.DeploymentTargetID,
+		Metadata:           metadata,
+	}
+	if err := e.events.CreateEvent(ctx, event); err != nil {
+		e.logRule(zerolog.ErrorLevel, rule).Err(err).Msg("error recording log alert event")
+		return
+	}
+
+	rule.LastFiredAt = &now
+	if _, err := e.rules.UpdateLogAlertRule(ctx, rule); err != nil {
+		e.logRule(zerolog.ErrorLevel, rule).Err(err).Msg("error recording when log alert rule fired")
+	}
+
+	e.logRule(zerolog.InfoLevel, rule).Int("match_count", res.Count).Msg("log alert rule fired")
+
+	if types.LogAlertChannel(rule.Channel) == types.LogAlertChannel_Inbox || e.notifier == nil {
+		return
+	}
+
+	if err := e.notifier.Notify(ctx, rule, alert); err != nil {
+		e.logRule(zerolog.ErrorLevel, rule).Err(err).Msg("error sending log alert notification")
+	}
+}
+
+func alertEventMetadata(alert types.PorterAppAlertEventMetadata) (models.JSONB, error) {
+	by, err := json.Marshal(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := models.JSONB{}
+	if err := json.Unmarshal(by, &metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+func (e *Evaluator) logRule(level zerolog.Level, rule *models.LogAlertRule) *zerolog.Event {
+	return e.log.WithLevel(level).Uint("project_id", rule.ProjectID).Uint("porter_app_id", rule.PorterAppID).Uint("log_alert_rule_id", rule.ID)
+}
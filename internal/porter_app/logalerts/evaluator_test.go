@@ -0,0 +1,313 @@
+package logalerts
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/porter_app/worker/workertest"
+	"gorm.io/gorm"
+)
+
+type fakeRuleStore struct {
+	mu      sync.Mutex
+	rules   []*models.LogAlertRule
+	updated []*models.LogAlertRule
+}
+
+func (s *fakeRuleStore) ListEnabledLogAlertRules(ctx context.Context) ([]*models.LogAlertRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rules, nil
+}
+
+func (s *fakeRuleStore) UpdateLogAlertRule(ctx context.Context, rule *models.LogAlertRule) (*models.LogAlertRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updated = append(s.updated, rule)
+	return rule, nil
+}
+
+type fakeAppReader map[uint]*models.PorterApp
+
+func (r fakeAppReader) ReadPorterAppByID(ctx context.Context, id uint) (*models.PorterApp, error) {
+	app, ok := r[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return app, nil
+}
+
+type fakeEventStore struct {
+	mu     sync.Mutex
+	events []*models.PorterAppEvent
+}
+
+func (s *fakeEventStore) CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, appEvent)
+	return nil
+}
+
+func (s *fakeEventStore) list() []*models.PorterAppEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*models.PorterAppEvent(nil), s.events...)
+}
+
+// fakeCluster serves logs for a single cluster, or fails or hangs when connected to
+type fakeCluster struct {
+	workertest.Cluster
+	logs map[string][]porter_app.StructuredLog
+}
+
+type fakeLogSource struct {
+	mu       sync.Mutex
+	clusters map[uint]*fakeCluster
+	queries  []LogQuery
+}
+
+func (s *fakeLogSource) Connect(ctx context.Context, projectID, clusterID uint) (AppLogReader, error) {
+	cluster, err := workertest.Connect(s.clusters, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeLogReader{source: s, cluster: cluster}, nil
+}
+
+type fakeLogReader struct {
+	source  *fakeLogSource
+	cluster *fakeCluster
+}
+
+func (r *fakeLogReader) AppLogs(ctx context.Context, query LogQuery) ([]porter_app.StructuredLog, error) {
+	r.source.mu.Lock()
+	r.source.queries = append(r.source.queries, query)
+	r.source.mu.Unlock()
+
+	return r.cluster.logs[query.AppName], nil
+}
+
+type fakeNotifier struct {
+	mu     sync.Mutex
+	alerts []types.PorterAppAlertEventMetadata
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, rule *models.LogAlertRule, alert types.PorterAppAlertEventMetadata) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func (n *fakeNotifier) list() []types.PorterAppAlertEventMetadata {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]types.PorterAppAlertEventMetadata(nil), n.alerts...)
+}
+
+type evaluatorFixture struct {
+	rules    *fakeRuleStore
+	events   *fakeEventStore
+	logs     *fakeLogSource
+	notifier *fakeNotifier
+	eval     *Evaluator
+}
+
+func newEvaluatorFixture(rules []*models.LogAlertRule, apps fakeAppReader, clusters map[uint]*fakeCluster, opts Options) *evaluatorFixture {
+	f := &evaluatorFixture{
+		rules:    &fakeRuleStore{rules: rules},
+		events:   &fakeEventStore{},
+		logs:     &fakeLogSource{clusters: clusters},
+		notifier: &fakeNotifier{},
+	}
+	f.eval = NewEvaluator(f.rules, apps, f.events, f.logs, f.notifier, opts)
+
+	return f
+}
+
+func slackRule(id, clusterID, appID uint) *models.LogAlertRule {
+	rule := validRule()
+	rule.ID = id
+	rule.ProjectID = 1
+	rule.ClusterID = clusterID
+	rule.PorterAppID = appID
+	rule.DeploymentTargetID = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	rule.Namespace = "default"
+	rule.Channel = string(types.LogAlertChannel_Slack)
+
+	return rule
+}
+
+func TestEvaluate_FiresOverThreshold(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rule := slackRule(1, 1, 10)
+
+	f := newEvaluatorFixture(
+		[]*models.LogAlertRule{rule},
+		fakeAppReader{10: {Name: "api"}},
+		map[uint]*fakeCluster{1: {logs: map[string][]porter_app.StructuredLog{
+			"api": logsEndingAt(now, "web", "ERROR one", "ok", "ERROR two", "ERROR three"),
+		}}},
+		Options{MaxLinesPerApp: 100},
+	)
+
+	if err := f.eval.evaluate(context.Background(), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := f.events.list()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Type != string(types.PorterAppEventType_Alert) || events[0].PorterAppID != 10 {
+		t.Fatalf("unexpected event %+v", events[0])
+	}
+	if events[0].Metadata["rule_name"] != "errors" || events[0].Metadata["match_count"] != float64(3) {
+		t.Fatalf("unexpected event metadata %v", events[0].Metadata)
+	}
+
+	alerts := f.notifier.list()
+	if len(alerts) != 1 || alerts[0].MatchCount != 3 || alerts[0].AppName != "api" || len(alerts[0].Snippet) != 3 {
+		t.Fatalf("unexpected alerts %+v", alerts)
+	}
+
+	if rule.LastFiredAt == nil || !rule.LastFiredAt.Equal(now) {
+		t.Fatalf("expected rule to record when it fired, got %v", rule.LastFiredAt)
+	}
+
+	query := f.logs.queries[0]
+	if query.Limit != 100 || query.Namespace != "default" || !query.Start.Equal(now.Add(-5*time.Minute)) {
+		t.Fatalf("unexpected log query %+v", query)
+	}
+}
+
+func TestEvaluate_AtThresholdDoesNotFire(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	f := newEvaluatorFixture(
+		[]*models.LogAlertRule{slackRule(1, 1, 10)},
+		fakeAppReader{10: {Name: "api"}},
+		map[uint]*fakeCluster{1: {logs: map[string][]porter_app.StructuredLog{
+			"api": logsEndingAt(now, "web", "ERROR one", "ERROR two"),
+		}}},
+		Options{},
+	)
+
+	if err := f.eval.evaluate(context.Background(), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.events.list()) != 0 || len(f.notifier.list()) != 0 {
+		t.Fatalf("expected rule at its threshold not to fire")
+	}
+}
+
+func TestEvaluate_Cooldown(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rule := slackRule(1, 1, 10)
+	lastFired := now.Add(-time.Minute)
+	rule.LastFiredAt = &lastFired
+
+	f := newEvaluatorFixture(
+		[]*models.LogAlertRule{rule},
+		fakeAppReader{10: {Name: "api"}},
+		map[uint]*fakeCluster{1: {logs: map[string][]porter_app.StructuredLog{
+			"api": logsEndingAt(now, "web", "ERROR", "ERROR", "ERROR"),
+		}}},
+		Options{},
+	)
+
+	if err := f.eval.evaluate(context.Background(), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.events.list()) != 0 {
+		t.Fatalf("expected rule which fired within its window not to fire again")
+	}
+	if len(f.logs.queries) != 0 {
+		t.Fatalf("expected no logs to be read when every rule is cooling down")
+	}
+}
+
+func TestEvaluate_InboxDoesNotNotify(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rule := slackRule(1, 1, 10)
+	rule.Channel = string(types.LogAlertChannel_Inbox)
+
+	f := newEvaluatorFixture(
+		[]*models.LogAlertRule{rule},
+		fakeAppReader{10: {Name: "api"}},
+		map[uint]*fakeCluster{1: {logs: map[string][]porter_app.StructuredLog{
+			"api": logsEndingAt(now, "web", "ERROR", "ERROR", "ERROR"),
+		}}},
+		Options{},
+	)
+
+	if err := f.eval.evaluate(context.Background(), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.events.list()) != 1 {
+		t.Fatalf("expected inbox alert to be recorded in the activity feed")
+	}
+	if len(f.notifier.list()) != 0 {
+		t.Fatalf("expected inbox alert not to be sent to a notifier")
+	}
+}
+
+func TestEvaluate_BadClustersDoNotBlockOthers(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	hang := make(chan struct{})
+	defer close(hang)
+
+	f := newEvaluatorFixture(
+		[]*models.LogAlertRule{
+			slackRule(1, 1, 10),
+			slackRule(2, 2, 20),
+			slackRule(3, 3, 30),
+		},
+		fakeAppReader{10: {Name: "unreachable"}, 20: {Name: "hanging"}, 30: {Name: "healthy"}},
+		map[uint]*fakeCluster{
+			1: {Cluster: workertest.Cluster{Unreachable: true}},
+			2: {Cluster: workertest.Cluster{Hang: hang}},
+			3: {logs: map[string][]porter_app.StructuredLog{
+				"healthy": logsEndingAt(now, "web", "ERROR", "ERROR", "ERROR"),
+			}},
+		},
+		Options{ClusterTimeout: 50 * time.Millisecond},
+	)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f.eval.evaluate(context.Background(), now)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("evaluation was stalled by a hanging cluster")
+	}
+
+	alerts := f.notifier.list()
+	if len(alerts) != 1 || alerts[0].AppName != "healthy" {
+		t.Fatalf("expected only the healthy cluster's rule to fire, got %+v", alerts)
+	}
+}
@@ -0,0 +1,169 @@
+package logalerts
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+)
+
+const (
+	// MaxPatternLength is the longest substring or regex a rule can match on
+	MaxPatternLength = 256
+	// MaxWindowMinutes is the longest window a rule can count matching lines over
+	MaxWindowMinutes = 60
+	// maxNameLength is the longest name a rule can have
+	maxNameLength = 100
+
+	// maxLineLength is the number of bytes of each log line which are matched against a rule, so that a single huge
+	// line cannot use up a rule's match budget
+	maxLineLength = 4096
+	// snippetSize is the number of matching lines included in an alert
+	snippetSize = 5
+	// maxSnippetLineLength is the number of bytes of each matching line included in an alert
+	maxSnippetLineLength = 500
+	// budgetCheckInterval is the number of lines matched between checks of a rule's match budget
+	budgetCheckInterval = 64
+)
+
+// ValidateRule returns an error describing the first invalid field of a rule, such as a regex which does not compile
+func ValidateRule(rule *models.LogAlertRule) error {
+	if rule == nil {
+		return errors.New("rule cannot be nil")
+	}
+
+	if strings.TrimSpace(rule.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(rule.Name) > maxNameLength {
+		return fmt.Errorf("name must be at most %d characters", maxNameLength)
+	}
+
+	if rule.Pattern == "" {
+		return errors.New("pattern is required")
+	}
+	if len(rule.Pattern) > MaxPatternLength {
+		return fmt.Errorf("pattern must be at most %d characters", MaxPatternLength)
+	}
+	if _, err := newMatcher(types.LogAlertMatchType(rule.MatchType), rule.Pattern); err != nil {
+		return err
+	}
+
+	if rule.WindowMinutes == 0 || rule.WindowMinutes > MaxWindowMinutes {
+		return fmt.Errorf("window must be between 1 and %d minutes", MaxWindowMinutes)
+	}
+
+	switch types.LogAlertChannel(rule.Channel) {
+	case types.LogAlertChannel_Slack, types.LogAlertChannel_Inbox:
+		if rule.WebhookURL != "" {
+			return errors.New("webhook url can only be set for the webhook channel")
+		}
+	case types.LogAlertChannel_Webhook:
+		parsed, err := url.Parse(rule.WebhookURL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return errors.New("webhook url must be a valid https url")
+		}
+	default:
+		return fmt.Errorf("channel must be one of %s, %s or %s", types.LogAlertChannel_Slack, types.LogAlertChannel_Webhook, types.LogAlertChannel_Inbox)
+	}
+
+	if rule.SlackIntegrationID != 0 && types.LogAlertChannel(rule.Channel) != types.LogAlertChannel_Slack {
+		return errors.New("slack integration can only be set for the slack channel")
+	}
+
+	return nil
+}
+
+// matcher matches log lines against a rule's pattern
+type matcher struct {
+	substring string
+	re        *regexp.Regexp
+}
+
+func newMatcher(matchType types.LogAlertMatchType, pattern string) (*matcher, error) {
+	switch matchType {
+	case types.LogAlertMatchType_Substring:
+		return &matcher{substring: pattern}, nil
+	case types.LogAlertMatchType_Regex:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		return &matcher{re: re}, nil
+	default:
+		return nil, fmt.Errorf("match type must be one of %s or %s", types.LogAlertMatchType_Substring, types.LogAlertMatchType_Regex)
+	}
+}
+
+func (m *matcher) match(line string) bool {
+	if len(line) > maxLineLength {
+		line = line[:maxLineLength]
+	}
+
+	if m.re != nil {
+		return m.re.MatchString(line)
+	}
+
+	return strings.Contains(line, m.substring)
+}
+
+// evaluation is the result of matching a rule against an app's logs
+type evaluation struct {
+	// Count is the number of lines in the rule's window which matched
+	Count int
+	// Snippet contains the most recent matching lines, oldest first
+	Snippet []string
+	// BudgetExceeded is true if matching was stopped before every line was checked, in which case Count is a lower bound
+	BudgetExceeded bool
+}
+
+// evaluateRule counts the lines of logs within the rule's window ending at now which match the rule. Logs must be
+// sorted newest first. Matching stops once budget has elapsed.
+func evaluateRule(rule *models.LogAlertRule, m *matcher, logs []porter_app.StructuredLog, now time.Time, budget time.Duration) evaluation {
+	var res evaluation
+
+	windowStart := now.Add(-time.Duration(rule.WindowMinutes) * time.Minute)
+	started := time.Now()
+
+	for i, log := range logs {
+		if i%budgetCheckInterval == 0 && i != 0 && time.Since(started) > budget {
+			res.BudgetExceeded = true
+			break
+		}
+
+		if log.Timestamp.Before(windowStart) {
+			break
+		}
+		if log.Timestamp.After(now) {
+			continue
+		}
+		if rule.ServiceName != "" && log.ServiceName != rule.ServiceName {
+			continue
+		}
+		if !m.match(log.Line) {
+			continue
+		}
+
+		res.Count++
+		if len(res.Snippet) < snippetSize {
+			line := log.Line
+			if len(line) > maxSnippetLineLength {
+				line = line[:maxSnippetLineLength] + "..."
+			}
+			res.Snippet = append(res.Snippet, line)
+		}
+	}
+
+	// lines were collected newest first, but read more naturally in the order they were logged
+	for i, j := 0, len(res.Snippet)-1; i < j; i, j = i+1, j-1 {
+		res.Snippet[i], res.Snippet[j] = res.Snippet[j], res.Snippet[i]
+	}
+
+	return res
+}
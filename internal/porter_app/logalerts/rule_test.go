@@ -0,0 +1,233 @@
+package logalerts
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+)
+
+func validRule() *models.LogAlertRule {
+	return &models.LogAlertRule{
+		Name:          "errors",
+		MatchType:     string(types.LogAlertMatchType_Substring),
+		Pattern:       "ERROR",
+		Threshold:     2,
+		WindowMinutes: 5,
+		Channel:       string(types.LogAlertChannel_Inbox),
+		Enabled:       true,
+	}
+}
+
+func TestValidateRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(rule *models.LogAlertRule)
+		wantErr string
+	}{
+		{name: "valid", modify: func(rule *models.LogAlertRule) {}},
+		{name: "missing name", modify: func(rule *models.LogAlertRule) { rule.Name = " " }, wantErr: "name is required"},
+		{name: "long name", modify: func(rule *models.LogAlertRule) { rule.Name = strings.Repeat("a", maxNameLength+1) }, wantErr: "name must be"},
+		{name: "missing pattern", modify: func(rule *models.LogAlertRule) { rule.Pattern = "" }, wantErr: "pattern is required"},
+		{name: "long pattern", modify: func(rule *models.LogAlertRule) { rule.Pattern = strings.Repeat("a", MaxPatternLength+1) }, wantErr: "pattern must be"},
+		{
+			name: "invalid regex",
+			modify: func(rule *models.LogAlertRule) {
+				rule.MatchType = string(types.LogAlertMatchType_Regex)
+				rule.Pattern = "(unclosed"
+			},
+			wantErr: "invalid regex",
+		},
+		{name: "unknown match type", modify: func(rule *models.LogAlertRule) { rule.MatchType = "glob" }, wantErr: "match type"},
+		{name: "zero window", modify: func(rule *models.LogAlertRule) { rule.WindowMinutes = 0 }, wantErr: "window must be"},
+		{name: "long window", modify: func(rule *models.LogAlertRule) { rule.WindowMinutes = MaxWindowMinutes + 1 }, wantErr: "window must be"},
+		{name: "unknown channel", modify: func(rule *models.LogAlertRule) { rule.Channel = "email" }, wantErr: "channel must be"},
+		{
+			name: "webhook without url",
+			modify: func(rule *models.LogAlertRule) {
+				rule.Channel = string(types.LogAlertChannel_Webhook)
+			},
+			wantErr: "webhook url must be",
+		},
+		{
+			name: "webhook over http",
+			modify: func(rule *models.LogAlertRule) {
+				rule.Channel = string(types.LogAlertChannel_Webhook)
+				rule.WebhookURL = "http://example.com/hook"
+			},
+			wantErr: "webhook url must be",
+		},
+		{
+			name: "webhook",
+			modify: func(rule *models.LogAlertRule) {
+				rule.Channel = string(types.LogAlertChannel_Webhook)
+				rule.WebhookURL = "https://example.com/hook"
+			},
+		},
+		{
+			name:    "webhook url on inbox channel",
+			modify:  func(rule *models.LogAlertRule) { rule.WebhookURL = "https://example.com/hook" },
+			wantErr: "webhook url can only be set",
+		},
+		{
+			name:    "slack integration on inbox channel",
+			modify:  func(rule *models.LogAlertRule) { rule.SlackIntegrationID = 1 },
+			wantErr: "slack integration can only be set",
+		},
+		{
+			name: "slack integration",
+			modify: func(rule *models.LogAlertRule) {
+				rule.Channel = string(types.LogAlertChannel_Slack)
+				rule.SlackIntegrationID = 1
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := validRule()
+			tt.modify(rule)
+
+			err := ValidateRule(rule)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// logsEndingAt returns one log line per second for each of lines, with the last line logged at end, newest first
+func logsEndingAt(end time.Time, service string, lines ...string) []porter_app.StructuredLog {
+	logs := make([]porter_app.StructuredLog, 0, len(lines))
+	for i := len(lines) - 1; i >= 0; i-- {
+		logs = append(logs, porter_app.StructuredLog{
+			Timestamp:   end.Add(-time.Duration(len(lines)-1-i) * time.Second),
+			Line:        lines[i],
+			ServiceName: service,
+		})
+	}
+
+	return logs
+}
+
+func mustMatcher(t *testing.T, rule *models.LogAlertRule) *matcher {
+	t.Helper()
+
+	m, err := newMatcher(types.LogAlertMatchType(rule.MatchType), rule.Pattern)
+	if err != nil {
+		t.Fatalf("unexpected error creating matcher: %v", err)
+	}
+
+	return m
+}
+
+func TestEvaluateRule_Substring(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rule := validRule()
+	logs := logsEndingAt(now, "web", "ERROR one", "ok", "ERROR two", "error lowercase", "ERROR three")
+
+	res := evaluateRule(rule, mustMatcher(t, rule), logs, now, time.Second)
+
+	if res.Count != 3 {
+		t.Fatalf("expected 3 matches, got %d", res.Count)
+	}
+	want := []string{"ERROR one", "ERROR two", "ERROR three"}
+	if fmt.Sprint(res.Snippet) != fmt.Sprint(want) {
+		t.Fatalf("expected snippet %v oldest first, got %v", want, res.Snippet)
+	}
+	if res.BudgetExceeded {
+		t.Fatalf("expected budget not to be exceeded")
+	}
+}
+
+func TestEvaluateRule_Regex(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rule := validRule()
+	rule.MatchType = string(types.LogAlertMatchType_Regex)
+	rule.Pattern = `status=5\d\d`
+	logs := logsEndingAt(now, "web", "status=500", "status=200", "status=503", "status=5xx")
+
+	res := evaluateRule(rule, mustMatcher(t, rule), logs, now, time.Second)
+
+	if res.Count != 2 {
+		t.Fatalf("expected 2 matches, got %d", res.Count)
+	}
+}
+
+func TestEvaluateRule_WindowAndService(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rule := validRule()
+	rule.WindowMinutes = 1
+	rule.ServiceName = "web"
+
+	logs := []porter_app.StructuredLog{
+		{Timestamp: now.Add(time.Second), Line: "ERROR from the future", ServiceName: "web"},
+		{Timestamp: now.Add(-10 * time.Second), Line: "ERROR in window", ServiceName: "web"},
+		{Timestamp: now.Add(-20 * time.Second), Line: "ERROR from worker", ServiceName: "worker"},
+		{Timestamp: now.Add(-2 * time.Minute), Line: "ERROR before window", ServiceName: "web"},
+	}
+
+	res := evaluateRule(rule, mustMatcher(t, rule), logs, now, time.Second)
+
+	if res.Count != 1 {
+		t.Fatalf("expected 1 match, got %d", res.Count)
+	}
+	if len(res.Snippet) != 1 || res.Snippet[0] != "ERROR in window" {
+		t.Fatalf("unexpected snippet %v", res.Snippet)
+	}
+}
+
+func TestEvaluateRule_SnippetBounds(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rule := validRule()
+
+	lines := make([]string, 0, snippetSize+3)
+	for i := 0; i < snippetSize+2; i++ {
+		lines = append(lines, fmt.Sprintf("ERROR %d", i))
+	}
+	lines = append(lines, "ERROR "+strings.Repeat("x", maxSnippetLineLength))
+
+	res := evaluateRule(rule, mustMatcher(t, rule), logsEndingAt(now, "web", lines...), now, time.Second)
+
+	if res.Count != len(lines) {
+		t.Fatalf("expected %d matches, got %d", len(lines), res.Count)
+	}
+	if len(res.Snippet) != snippetSize {
+		t.Fatalf("expected snippet of %d lines, got %d", snippetSize, len(res.Snippet))
+	}
+
+	last := res.Snippet[len(res.Snippet)-1]
+	if len(last) != maxSnippetLineLength+len("...") || !strings.HasSuffix(last, "...") {
+		t.Fatalf("expected the newest line to be truncated, got %d bytes", len(last))
+	}
+}
+
+func TestEvaluateRule_Budget(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rule := validRule()
+
+	lines := make([]string, budgetCheckInterval*3)
+	for i := range lines {
+		lines[i] = "ERROR"
+	}
+
+	res := evaluateRule(rule, mustMatcher(t, rule), logsEndingAt(now, "web", lines...), now, 0)
+
+	if !res.BudgetExceeded {
+		t.Fatalf("expected budget to be exceeded")
+	}
+	if res.Count != budgetCheckInterval {
+		t.Fatalf("expected matching to stop after %d lines, got %d", budgetCheckInterval, res.Count)
+	}
+}
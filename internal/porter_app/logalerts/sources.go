@@ -0,0 +1,123 @@
+package logalerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	porter_agent "github.com/porter-dev/porter/internal/kubernetes/porter_agent/v2"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier/slack"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	"github.com/porter-dev/porter/internal/webhooks"
+	v1 "k8s.io/api/core/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const (
+	lokiLabel_PorterAppName      = "porter_run_app_name"
+	lokiLabel_DeploymentTargetId = "porter_run_deployment_target_id"
+	lokiLabel_Namespace          = "namespace"
+)
+
+// NewEvaluatorFromConfig returns an Evaluator which reads rules and apps from the server's database, reads logs from
+// the porter agent on each cluster, and sends alerts through the project's slack integrations or the rule's webhook
+func NewEvaluatorFromConfig(conf *config.Config, opts Options) *Evaluator {
+	return NewEvaluator(
+		conf.Repo.LogAlertRule(),
+		conf.Repo.PorterApp(),
+		conf.Repo.PorterAppEvent(),
+		worker.NewAgentSource(conf, openAgentLogReader),
+		&channelNotifier{conf: conf, dispatcher: webhooks.NewDispatcherFromConfig(conf)},
+		opts,
+	)
+}
+
+// openAgentLogReader returns a reader for the porter agent running on a cluster
+func openAgentLogReader(cluster *models.Cluster, agent *kubernetes.Agent) (AppLogReader, error) {
+	agentSvc, err := porter_agent.GetAgentService(agent.Clientset)
+	if err != nil {
+		return nil, fmt.Errorf("error getting porter agent service: %w", err)
+	}
+
+	return &agentLogReader{clientset: agent.Clientset, agentSvc: agentSvc}, nil
+}
+
+type agentLogReader struct {
+	clientset k8s.Interface
+	agentSvc  *v1.Service
+}
+
+// AppLogs returns the most recent log lines of an app from the porter agent
+func (r *agentLogReader) AppLogs(ctx context.Context, query LogQuery) ([]porter_app.StructuredLog, error) {
+	logs, err := porter_agent.Logs(ctx, r.clientset, r.agentSvc, &types.LogRequest{
+		Limit:      query.Limit,
+		StartRange: &query.Start,
+		EndRange:   &query.End,
+		MatchLabels: map[string]string{
+			lokiLabel_Namespace:          query.Namespace,
+			lokiLabel_PorterAppName:      query.AppName,
+			lokiLabel_DeploymentTargetId: query.DeploymentTargetID.String(),
+		},
+		Direction: "backward",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if logs == nil {
+		return nil, nil
+	}
+
+	return porter_app.AgentLogToStructuredLog(logs.Logs), nil
+}
+
+// channelNotifier sends alerts to the project's slack integrations, or to the rule's webhook
+type channelNotifier struct {
+	conf       *config.Config
+	dispatcher *webhooks.Dispatcher
+}
+
+// Notify sends the alert to the rule's channel
+func (n *channelNotifier) Notify(ctx context.Context, rule *models.LogAlertRule, alert types.PorterAppAlertEventMetadata) error {
+	switch types.LogAlertChannel(rule.Channel) {
+	case types.LogAlertChannel_Slack:
+		slackInts, err := n.conf.Repo.SlackIntegration().ListSlackIntegrationsByProjectID(rule.ProjectID)
+		if err != nil {
+			return fmt.Errorf("error listing slack integrations: %w", err)
+		}
+
+		var targets []*integrations.SlackIntegration
+		for _, slackInt := range slackInts {
+			if rule.SlackIntegrationID == 0 || slackInt.ID == rule.SlackIntegrationID {
+				targets = append(targets, slackInt)
+			}
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("project %d has no slack integration to send the alert to", rule.ProjectID)
+		}
+
+		url := fmt.Sprintf("%s/apps/%s/activity", n.conf.ServerConf.ServerURL, alert.AppName)
+		return slack.NewLogAlertNotifier(targets...).Notify(ctx, alert, url)
+	case types.LogAlertChannel_Webhook:
+		payload, err := json.Marshal(alert)
+		if err != nil {
+			return err
+		}
+
+		_, err = n.dispatcher.Deliver(ctx, webhooks.Event{
+			ProjectID:      rule.ProjectID,
+			LogAlertRuleID: rule.ID,
+			EventID:        alert.EventID,
+			URL:            rule.WebhookURL,
+			Payload:        payload,
+		})
+		return err
+	default:
+		return nil
+	}
+}
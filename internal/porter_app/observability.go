@@ -0,0 +1,175 @@
+package porter_app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// LabelKey_Name is the standard kubernetes label for the name of the service
+	LabelKey_Name = "app.kubernetes.io/name"
+	// LabelKey_Version is the standard kubernetes label for the version of the service, which is its image tag
+	LabelKey_Version = "app.kubernetes.io/version"
+	// LabelKey_PartOf is the standard kubernetes label for the app the service is part of
+	LabelKey_PartOf = "app.kubernetes.io/part-of"
+)
+
+const (
+	envOTELServiceName        = "OTEL_SERVICE_NAME"
+	envOTELResourceAttributes = "OTEL_RESOURCE_ATTRIBUTES"
+	envOTELEndpoint           = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envOTELProtocol           = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	envOTELSampler            = "OTEL_TRACES_SAMPLER"
+	envOTELSamplerArg         = "OTEL_TRACES_SAMPLER_ARG"
+)
+
+// ObservabilityWorkload is the service of an app which observability values are injected into
+type ObservabilityWorkload struct {
+	AppName     string
+	ServiceName string
+	Namespace   string
+	// Version is the image tag the service is deployed with
+	Version string
+	// InjectEnv is false for services which opt out of the OpenTelemetry env variables. The standard labels are
+	// injected regardless.
+	InjectEnv bool
+}
+
+// ObservabilityLabels returns the standard kubernetes labels for a workload. Labels whose value would not be a valid
+// label value are left out.
+func ObservabilityLabels(workload ObservabilityWorkload) map[string]string {
+	labels := make(map[string]string)
+
+	for key, value := range map[string]string{
+		LabelKey_Name:    workload.ServiceName,
+		LabelKey_PartOf:  workload.AppName,
+		LabelKey_Version: labelValue(workload.Version),
+	} {
+		if value != "" && len(validation.IsValidLabelValue(value)) == 0 {
+			labels[key] = value
+		}
+	}
+
+	return labels
+}
+
+// ObservabilityEnv returns the OpenTelemetry env variables which point the SDKs of a workload at the collector of the
+// project, or nil if the project has no observability config
+func ObservabilityEnv(workload ObservabilityWorkload, config *types.ProjectObservabilityConfig) map[string]string {
+	if config == nil || config.OTLPEndpoint == "" {
+		return nil
+	}
+
+	attributes := []string{
+		"service.namespace=" + workload.AppName,
+		"k8s.namespace.name=" + workload.Namespace,
+	}
+	if workload.Version != "" {
+		attributes = append(attributes, "service.version="+workload.Version)
+	}
+
+	env := map[string]string{
+		envOTELServiceName:        fmt.Sprintf("%s-%s", workload.AppName, workload.ServiceName),
+		envOTELResourceAttributes: strings.Join(attributes, ","),
+		envOTELEndpoint:           config.OTLPEndpoint,
+		envOTELSampler:            "parentbased_traceidratio",
+		envOTELSamplerArg:         strconv.FormatFloat(config.SampleRate, 'f', -1, 64),
+	}
+	if config.OTLPProtocol != "" {
+		env[envOTELProtocol] = config.OTLPProtocol
+	}
+
+	return env
+}
+
+// InjectObservabilityValues adds the standard labels and, if the project has an observability config, the
+// OpenTelemetry env variables to the helm values of a single service. Values built from porter.yaml must be passed in
+// before the existing values of the release are merged, so that every key which is already set was set by the user.
+// Those keep the value the user set, and a warning is returned for each one which differs from the injected value.
+func InjectObservabilityValues(values map[string]interface{}, workload ObservabilityWorkload, config *types.ProjectObservabilityConfig) []string {
+	var warnings []string
+
+	labels := ObservabilityLabels(workload)
+	for _, key := range []string{"labels", "podLabels"} {
+		warnings = append(warnings, inject(stringMap(values, key), labels, fmt.Sprintf("label %%s of service %s", workload.ServiceName))...)
+	}
+
+	if workload.InjectEnv {
+		if container := stringMap(values, "container"); container != nil {
+			if env := stringMap(container, "env"); env != nil {
+				warnings = append(warnings, inject(stringMap(env, "normal"), ObservabilityEnv(workload, config), fmt.Sprintf("env variable %%s of service %s", workload.ServiceName))...)
+			}
+		}
+	}
+
+	sort.Strings(warnings)
+	return warnings
+}
+
+// inject sets each of the injected values in target which is not already set, and returns a warning for each which is
+// set to a different value. The subject is formatted with the key for the warning.
+func inject(target map[string]interface{}, injected map[string]string, subject string) []string {
+	if target == nil {
+		return nil
+	}
+
+	var warnings []string
+	for key, value := range injected {
+		existing, ok := target[key]
+		if !ok {
+			target[key] = value
+			continue
+		}
+
+		if fmt.Sprint(existing) != value {
+			warnings = append(warnings, fmt.Sprintf("%s is set to %q, so Porter did not set it to %q", fmt.Sprintf(subject, key), fmt.Sprint(existing), value))
+		}
+	}
+
+	return warnings
+}
+
+// stringMap returns the map stored under key, creating it if it is not set. Maps of strings, which are created by
+// some of the helpers building helm values, are converted so that any value can be stored. If the key holds something
+// other than a map, nil is returned.
+func stringMap(values map[string]interface{}, key string) map[string]interface{} {
+	switch value := values[key].(type) {
+	case map[string]interface{}:
+		return value
+	case map[string]string:
+		converted := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			converted[k] = v
+		}
+		values[key] = converted
+		return converted
+	case nil:
+		created := make(map[string]interface{})
+		values[key] = created
+		return created
+	}
+
+	return nil
+}
+
+// labelValue converts a string into a valid kubernetes label value by replacing disallowed characters and
+// truncating it to the maximum length
+func labelValue(s string) string {
+	value := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, s)
+
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+
+	return strings.Trim(value, "-_.")
+}
@@ -0,0 +1,274 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	kubernetes_porter_app "github.com/porter-dev/porter/internal/kubernetes/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+const (
+	// LabelKey_RunJobID is the label set on run jobs, and their pods, containing the run ID
+	LabelKey_RunJobID = "porter.run/run-job-id"
+	// annotationKey_RunJobCommand is the annotation set on run jobs containing the command the job was launched with
+	annotationKey_RunJobCommand = "porter.run/run-job-command"
+
+	// runJobTTLSecondsAfterFinished is how long a finished run job, along with its pods and logs, is kept before it is cleaned up
+	runJobTTLSecondsAfterFinished int32 = 60 * 60
+	// runJobLogTailLines is the number of log lines returned for a run job
+	runJobLogTailLines int64 = 1000
+	// maxJobNameLength keeps the job name short enough to be used as the job-name label on its pods
+	maxJobNameLength = 63
+)
+
+// ErrRunJobNotFound is returned when a run job does not exist, or has already been cleaned up
+var ErrRunJobNotFound = errors.New("run job not found")
+
+// RunJob is a one-off command run as a job against a stack's image and environment
+type RunJob struct {
+	// ID is the run ID returned when the job was launched
+	ID string `json:"id"`
+	// Name is the name of the job object
+	Name string `json:"name"`
+	// Command is the command the job was launched with
+	Command string `json:"command"`
+	// Status is the status of the job run
+	Status JobRunStatus `json:"status"`
+	// CreatedAt is the time the job was created
+	CreatedAt time.Time `json:"created_at"`
+	// FinishedAt is the time the job finished, if applicable
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	// Logs are the last lines logged by the job's pod
+	Logs []string `json:"logs"`
+}
+
+// LaunchRunJobInput is the input to LaunchRunJob
+type LaunchRunJobInput struct {
+	// AppName is the name of the stack
+	AppName string
+	// Namespace is the namespace of the stack
+	Namespace string
+	// Builder is the builder of the stack's image, used to run commands through the buildpacks launcher
+	Builder string
+	// Command is the command to run
+	Command  string
+	K8sAgent *kubernetes.Agent
+}
+
+// LaunchRunJob launches a job running the given command, using the image, env and resources of a running pod of the stack.
+// The job is cleaned up automatically once it has been finished for runJobTTLSecondsAfterFinished.
+func LaunchRunJob(ctx context.Context, inp LaunchRunJobInput) (RunJob, error) {
+	ctx, span := telemetry.NewSpan(ctx, "launch-run-job")
+	defer span.End()
+
+	if inp.K8sAgent == nil {
+		return RunJob{}, telemetry.Error(ctx, span, nil, "k8s agent is nil")
+	}
+	if inp.AppName == "" {
+		return RunJob{}, telemetry.Error(ctx, span, nil, "app name is empty")
+	}
+
+	args := strings.Fields(inp.Command)
+	if len(args) == 0 {
+		return RunJob{}, telemetry.Error(ctx, span, nil, "command is empty")
+	}
+
+	if (strings.Contains(inp.Builder, "heroku") || strings.Contains(inp.Builder, "paketo")) &&
+		args[0] != "/cnb/lifecycle/launcher" &&
+		args[0] != "launcher" {
+		// this is a buildpacks image, so we prepend commands with launcher command
+		args = append([]string{"/cnb/lifecycle/launcher"}, args...)
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: inp.AppName},
+		telemetry.AttributeKV{Key: "namespace", Value: inp.Namespace},
+	)
+
+	podList, err := inp.K8sAgent.GetPodsByLabel(ctx, kubernetes_porter_app.LabelKey_PorterApplication, inp.Namespace)
+	if err != nil {
+		return RunJob{}, telemetry.Error(ctx, span, err, "error getting pods by label")
+	}
+
+	var source *v1.Pod
+	for i := range podList.Items {
+		if podList.Items[i].Status.Phase == v1.PodRunning {
+			source = &podList.Items[i]
+			break
+		}
+	}
+	if source == nil {
+		return RunJob{}, telemetry.Error(ctx, span, nil, "no running pods found to copy the job from")
+	}
+
+	suffix, err := kubernetes.RandomString(8)
+	if err != nil {
+		return RunJob{}, telemetry.Error(ctx, span, err, "error generating run id")
+	}
+	runID := strings.ToLower(suffix)
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "run-id", Value: runID})
+
+	jobName := inp.AppName
+	if len(jobName) > maxJobNameLength-len(runID)-len("-run-") {
+		jobName = strings.TrimSuffix(jobName[:maxJobNameLength-len(runID)-len("-run-")], "-")
+	}
+	jobName = fmt.Sprintf("%s-run-%s", jobName, runID)
+
+	labels := map[string]string{LabelKey_RunJobID: runID}
+
+	// only the app container is copied, so that sidecars do not keep the job running after the command exits
+	container := *source.Spec.Containers[0].DeepCopy()
+	container.Command = args[:1]
+	container.Args = args[1:]
+	container.LivenessProbe = nil
+	container.ReadinessProbe = nil
+	container.StartupProbe = nil
+	container.Ports = nil
+
+	podSpec := source.Spec.DeepCopy()
+	podSpec.Containers = []v1.Container{container}
+	podSpec.RestartPolicy = v1.RestartPolicyNever
+	podSpec.NodeName = ""
+
+	backoffLimit := int32(0)
+	ttl := runJobTTLSecondsAfterFinished
+
+	job, err := inp.K8sAgent.Clientset.BatchV1().Jobs(inp.Namespace).Create(ctx, &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        jobName,
+			Namespace:   inp.Namespace,
+			Labels:      labels,
+			Annotations: map[string]string{annotationKey_RunJobCommand: inp.Command},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       *podSpec,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return RunJob{}, telemetry.Error(ctx, span, err, "error creating run job")
+	}
+
+	return RunJob{
+		ID:        runID,
+		Name:      job.Name,
+		Command:   inp.Command,
+		Status:    JobRunStatus_Running,
+		CreatedAt: job.CreationTimestamp.Time,
+		Logs:      []string{},
+	}, nil
+}
+
+// GetRunJobInput is the input to GetRunJob
+type GetRunJobInput struct {
+	// Namespace is the namespace of the stack
+	Namespace string
+	// RunID is the run ID returned by LaunchRunJob
+	RunID    string
+	K8sAgent *kubernetes.Agent
+}
+
+// GetRunJob returns the status and logs of a run job, or ErrRunJobNotFound if it does not exist or has been cleaned up
+func GetRunJob(ctx context.Context, inp GetRunJobInput) (RunJob, error) {
+	ctx, span := telemetry.NewSpan(ctx, "get-run-job")
+	defer span.End()
+
+	if inp.K8sAgent == nil {
+		return RunJob{}, telemetry.Error(ctx, span, nil, "k8s agent is nil")
+	}
+	if inp.RunID == "" {
+		return RunJob{}, telemetry.Error(ctx, span, nil, "run id is empty")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "namespace", Value: inp.Namespace},
+		telemetry.AttributeKV{Key: "run-id", Value: inp.RunID},
+	)
+
+	jobs, err := inp.K8sAgent.ListJobsByLabel(inp.Namespace, kubernetes.Label{Key: LabelKey_RunJobID, Val: inp.RunID})
+	if err != nil {
+		return RunJob{}, telemetry.Error(ctx, span, err, "error listing run jobs")
+	}
+	if len(jobs) == 0 {
+		return RunJob{}, ErrRunJobNotFound
+	}
+	job := jobs[0]
+
+	run := RunJob{
+		ID:        inp.RunID,
+		Name:      job.Name,
+		Command:   job.Annotations[annotationKey_RunJobCommand],
+		Status:    runJobStatus(job),
+		CreatedAt: job.CreationTimestamp.Time,
+		Logs:      []string{},
+	}
+
+	if job.Status.CompletionTime != nil {
+		run.FinishedAt = job.Status.CompletionTime.Time
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+			run.FinishedAt = condition.LastTransitionTime.Time
+		}
+	}
+
+	pods, err := inp.K8sAgent.GetJobPods(ctx, inp.Namespace, job.Name)
+	if err != nil {
+		return RunJob{}, telemetry.Error(ctx, span, err, "error getting run job pods")
+	}
+	if len(pods) == 0 {
+		// the pod has not been scheduled yet
+		return run, nil
+	}
+
+	// the backoff limit is zero, so the job only ever has a single pod
+	pod := pods[0]
+	if pod.Status.Phase == v1.PodPending {
+		return run, nil
+	}
+
+	tailLines := runJobLogTailLines
+	raw, err := inp.K8sAgent.Clientset.CoreV1().Pods(inp.Namespace).GetLogs(pod.Name, &v1.PodLogOptions{
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		return RunJob{}, telemetry.Error(ctx, span, err, "error getting run job logs")
+	}
+
+	logs := strings.TrimSuffix(string(raw), "\n")
+	if logs != "" {
+		run.Logs = strings.Split(logs, "\n")
+	}
+
+	return run, nil
+}
+
+func runJobStatus(job batchv1.Job) JobRunStatus {
+	switch {
+	case job.Status.Succeeded > 0:
+		return JobRunStatus_Successful
+	case job.Status.Failed > 0:
+		return JobRunStatus_Failed
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+			return JobRunStatus_Failed
+		}
+	}
+
+	return JobRunStatus_Running
+}
@@ -0,0 +1,346 @@
+// Package scaling runs the services of apps at set numbers of replicas during the windows of their scaling schedules,
+// such as 10 replicas from 8am to 8pm on weekdays, and at the replicas they were deployed with otherwise.
+package scaling
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// the windows are in the time zone of the app's users, which the server's image may not have the database for
+	_ "time/tzdata"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+const (
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+
+	// MaxReplicas is the most replicas a window can run a service with
+	MaxReplicas = 100
+	// MaxWindows is the most windows a service can have
+	MaxWindows = 20
+	// MinWindowMinutes is the shortest a window can be open for. Windows are applied once a minute, so shorter windows
+	// would be missed when an evaluation runs late.
+	MinWindowMinutes = 15
+)
+
+var (
+	dayNames      = map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}
+	dayLabels     = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+	applyModes    = []types.ScalingScheduleApply{types.ScalingScheduleApply_Patch, types.ScalingScheduleApply_Upgrade}
+	timeOfDayExpr = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+)
+
+// ValidationError is a problem with a scaling schedule
+type ValidationError struct {
+	// Window is the index of the window the problem is with, or -1 if it is with the whole schedule
+	Window int
+	// Field is the field of the window or schedule the problem is with, such as start
+	Field   string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Window < 0 {
+		return e.Message
+	}
+
+	return fmt.Sprintf("schedules[%d]: %s", e.Window, e.Message)
+}
+
+// Schedule is a validated scaling schedule
+type Schedule struct {
+	location *time.Location
+	windows  []window
+}
+
+type window struct {
+	// index is the position of the window in the list it was written in
+	index    int
+	label    string
+	days     [7]bool
+	start    int
+	length   int
+	replicas int
+}
+
+// Compile validates a scaling schedule, returning every problem with it
+func Compile(s types.ServiceScalingSchedule) (*Schedule, []ValidationError) {
+	var errs []ValidationError
+	fail := func(w int, field string, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Window: w, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	timezone := s.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		fail(-1, "timezone", "timezone %q is not a known time zone, such as America/New_York", s.Timezone)
+	}
+
+	if s.Apply != "" && !validApply(s.Apply) {
+		fail(-1, "apply", "apply must be one of %s, found %q", joinApplyModes(), s.Apply)
+	}
+
+	if len(s.Schedules) == 0 {
+		fail(-1, "schedules", "schedules must list at least one window")
+	}
+	if len(s.Schedules) > MaxWindows {
+		fail(-1, "schedules", "a service can have at most %d windows, found %d", MaxWindows, len(s.Schedules))
+	}
+
+	sched := &Schedule{location: location}
+	names := map[string]int{}
+
+	for i, w := range s.Schedules {
+		compiled := window{index: i, label: w.Name, replicas: w.Replicas}
+		if compiled.label == "" {
+			compiled.label = fmt.Sprintf("schedules[%d]", i)
+		}
+		valid := true
+
+		if w.Name != "" {
+			if other, ok := names[w.Name]; ok {
+				fail(i, "name", "window name %s is also used by schedules[%d]", w.Name, other)
+			}
+			names[w.Name] = i
+		}
+
+		days, err := parseDays(w.Days)
+		if err != nil {
+			fail(i, "days", "%s", err)
+			valid = false
+		}
+		compiled.days = days
+
+		start, err := parseTimeOfDay(w.Start)
+		if err != nil {
+			fail(i, "start", "start %s", err)
+			valid = false
+		}
+		end, err := parseTimeOfDay(w.End)
+		if err != nil {
+			fail(i, "end", "end %s", err)
+			valid = false
+		}
+		compiled.start = start
+
+		if valid {
+			compiled.length = (end - start + minutesPerDay) % minutesPerDay
+			if compiled.length == 0 {
+				fail(i, "end", "window opens and closes at %s, a window must close at a different time than it opens", w.Start)
+				valid = false
+			} else if compiled.length < MinWindowMinutes {
+				fail(i, "end", "window is open for %d minutes, windows must be open for at least %d minutes", compiled.length, MinWindowMinutes)
+				valid = false
+			}
+		}
+
+		if w.Replicas < 0 || w.Replicas > MaxReplicas {
+			fail(i, "replicas", "replicas must be between 0 and %d, found %d", MaxReplicas, w.Replicas)
+		}
+
+		if valid {
+			sched.windows = append(sched.windows, compiled)
+		}
+	}
+
+	errs = append(errs, sched.overlaps()...)
+
+	if len(errs) != 0 {
+		return nil, errs
+	}
+
+	return sched, nil
+}
+
+// overlaps returns an error for each window which is open at the same time as an earlier window
+func (s *Schedule) overlaps() []ValidationError {
+	var errs []ValidationError
+
+	var occupied [minutesPerWeek]*window
+	for i := range s.windows {
+		w := &s.windows[i]
+
+	days:
+		for day, open := range w.days {
+			if !open {
+				continue
+			}
+
+			for m := 0; m < w.length; m++ {
+				minute := (day*minutesPerDay + w.start + m) % minutesPerWeek
+				if other := occupied[minute]; other != nil && other != w {
+					errs = append(errs, ValidationError{
+						Window:  w.index,
+						Field:   "start",
+						Message: fmt.Sprintf("window %s overlaps window %s on %s at %s", w.label, other.label, dayLabels[minute/minutesPerDay], formatTimeOfDay(minute%minutesPerDay)),
+					})
+					break days
+				}
+				occupied[minute] = w
+			}
+		}
+	}
+
+	return errs
+}
+
+// At returns the window which is open at t, or an empty label if none is, and the replicas the service runs with at t
+func (s *Schedule) At(t time.Time, baseReplicas int) (string, int) {
+	local := t.In(s.location)
+	minute := int(local.Weekday())*minutesPerDay + local.Hour()*60 + local.Minute()
+
+	for _, w := range s.windows {
+		for day, open := range w.days {
+			if open && (minute-day*minutesPerDay-w.start+minutesPerWeek)%minutesPerWeek < w.length {
+				return w.label, w.replicas
+			}
+		}
+	}
+
+	return "", baseReplicas
+}
+
+// Next returns the first time after t that the replicas of the service change, and what they change to. It returns
+// false if they never change, which is the case when every window runs the base replicas.
+func (s *Schedule) Next(t time.Time, baseReplicas int) (time.Time, int, bool) {
+	_, current := s.At(t, baseReplicas)
+
+	// the boundaries of the windows are wall clock times, so they are found on each of the next days in the time zone of
+	// the schedule, which makes them move with daylight saving time
+	local := t.In(s.location)
+	var boundaries []time.Time
+	for day := 0; day <= 8; day++ {
+		for _, w := range s.windows {
+			for _, minute := range []int{w.start, (w.start + w.length) % minutesPerDay} {
+				boundary := time.Date(local.Year(), local.Month(), local.Day()+day, minute/60, minute%60, 0, 0, s.location)
+				if boundary.After(t) {
+					boundaries = append(boundaries, boundary)
+				}
+			}
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+
+	for _, boundary := range boundaries {
+		if _, replicas := s.At(boundary, baseReplicas); replicas != current {
+			return boundary, replicas, true
+		}
+	}
+
+	return time.Time{}, 0, false
+}
+
+// Location is the time zone the windows are in
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// parseDays parses the day of week field of a cron expression into the days it matches, where both 0 and 7 are Sunday
+func parseDays(expr string) ([7]bool, error) {
+	var days [7]bool
+
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return days, fmt.Errorf("days must be set, such as MON-FRI or *")
+	}
+
+	for _, item := range strings.Split(expr, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return days, fmt.Errorf("days %q has step %q, which must be a positive number", item, stepPart)
+			}
+			step = n
+		}
+
+		low, high := 0, 6
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			if low, err = parseDay(lowPart); err != nil {
+				return days, err
+			}
+			high = low
+			if isRange {
+				if high, err = parseDay(highPart); err != nil {
+					return days, err
+				}
+			} else if hasStep {
+				high = 7
+			}
+			if low > high {
+				return days, fmt.Errorf("days range %q starts after it ends, use 5-7 rather than FRI-SUN to wrap around the week", rangePart)
+			}
+		}
+
+		for day := low; day <= high; day += step {
+			days[day%7] = true
+		}
+	}
+
+	return days, nil
+}
+
+func parseDay(value string) (int, error) {
+	if day, ok := dayNames[strings.ToUpper(value)]; ok {
+		return day, nil
+	}
+
+	day, err := strconv.Atoi(value)
+	if err != nil || day < 0 || day > 7 {
+		return 0, fmt.Errorf("day %q is not a day of the week, expected 0 to 7 or SUN to SAT", value)
+	}
+
+	return day, nil
+}
+
+// parseTimeOfDay parses HH:MM into minutes since midnight
+func parseTimeOfDay(value string) (int, error) {
+	match := timeOfDayExpr.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("must be a time of day as HH:MM, found %q", value)
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	if hour > 23 || minute > 59 {
+		return 0, fmt.Errorf("%q is not a time of day, expected 00:00 to 23:59", value)
+	}
+
+	return hour*60 + minute, nil
+}
+
+func formatTimeOfDay(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+func validApply(apply types.ScalingScheduleApply) bool {
+	for _, mode := range applyModes {
+		if mode == apply {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinApplyModes() string {
+	modes := make([]string, len(applyModes))
+	for i, mode := range applyModes {
+		modes[i] = string(mode)
+	}
+
+	return strings.Join(modes, ", ")
+}
@@ -0,0 +1,258 @@
+package scaling
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// businessHours runs 10 replicas from 8am to 8pm on weekdays in New York
+func businessHours() types.ServiceScalingSchedule {
+	return types.ServiceScalingSchedule{
+		Timezone: "America/New_York",
+		Schedules: []types.ScalingWindow{
+			{Name: "business hours", Days: "MON-FRI", Start: "08:00", End: "20:00", Replicas: 10},
+		},
+	}
+}
+
+func compile(t *testing.T, s types.ServiceScalingSchedule) *Schedule {
+	t.Helper()
+
+	sched, errs := Compile(s)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors compiling schedule: %v", errs)
+	}
+
+	return sched
+}
+
+func TestCompile(t *testing.T) {
+	window := func(days, start, end string, replicas int) types.ScalingWindow {
+		return types.ScalingWindow{Days: days, Start: start, End: end, Replicas: replicas}
+	}
+
+	tests := []struct {
+		name     string
+		schedule types.ServiceScalingSchedule
+		window   int
+		field    string
+		message  string
+	}{
+		{
+			name:     "unknown timezone",
+			schedule: types.ServiceScalingSchedule{Timezone: "Mars/Olympus_Mons", Schedules: []types.ScalingWindow{window("*", "08:00", "20:00", 2)}},
+			window:   -1,
+			field:    "timezone",
+			message:  "not a known time zone",
+		},
+		{
+			name:     "unknown apply mode",
+			schedule: types.ServiceScalingSchedule{Apply: "restart", Schedules: []types.ScalingWindow{window("*", "08:00", "20:00", 2)}},
+			window:   -1,
+			field:    "apply",
+			message:  "apply must be one of patch, upgrade",
+		},
+		{
+			name:     "no windows",
+			schedule: types.ServiceScalingSchedule{},
+			window:   -1,
+			field:    "schedules",
+			message:  "at least one window",
+		},
+		{
+			name:     "unknown day",
+			schedule: types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{window("MON-FUN", "08:00", "20:00", 2)}},
+			window:   0,
+			field:    "days",
+			message:  `day "FUN" is not a day of the week`,
+		},
+		{
+			name:     "range which wraps the week",
+			schedule: types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{window("FRI-SUN", "08:00", "20:00", 2)}},
+			window:   0,
+			field:    "days",
+			message:  "starts after it ends",
+		},
+		{
+			name:     "time which is not HH:MM",
+			schedule: types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{window("*", "8am", "20:00", 2)}},
+			window:   0,
+			field:    "start",
+			message:  "must be a time of day as HH:MM",
+		},
+		{
+			name:     "time past the end of the day",
+			schedule: types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{window("*", "08:00", "24:00", 2)}},
+			window:   0,
+			field:    "end",
+			message:  "expected 00:00 to 23:59",
+		},
+		{
+			name:     "window which closes when it opens",
+			schedule: types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{window("*", "08:00", "08:00", 2)}},
+			window:   0,
+			field:    "end",
+			message:  "must close at a different time than it opens",
+		},
+		{
+			name:     "window which is too short",
+			schedule: types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{window("*", "08:00", "08:10", 2)}},
+			window:   0,
+			field:    "end",
+			message:  "open for 10 minutes",
+		},
+		{
+			name:     "too many replicas",
+			schedule: types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{window("*", "08:00", "20:00", 101)}},
+			window:   0,
+			field:    "replicas",
+			message:  "between 0 and 100",
+		},
+		{
+			name: "overlapping windows",
+			schedule: types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{
+				{Name: "business hours", Days: "MON-FRI", Start: "08:00", End: "20:00", Replicas: 10},
+				{Name: "evening batch", Days: "*", Start: "19:00", End: "21:00", Replicas: 4},
+			}},
+			window:  1,
+			field:   "start",
+			message: "window evening batch overlaps window business hours on Monday at 19:00",
+		},
+		{
+			name: "overnight window which overlaps across the end of the week",
+			schedule: types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{
+				window("SAT", "22:00", "02:00", 4),
+				window("SUN", "01:00", "03:00", 6),
+			}},
+			window:  1,
+			field:   "start",
+			message: "window schedules[1] overlaps window schedules[0] on Sunday at 01:00",
+		},
+		{
+			name: "duplicate window names",
+			schedule: types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{
+				{Name: "peak", Days: "MON", Start: "08:00", End: "20:00", Replicas: 10},
+				{Name: "peak", Days: "TUE", Start: "08:00", End: "20:00", Replicas: 10},
+			}},
+			window:  1,
+			field:   "name",
+			message: "also used by schedules[0]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, errs := Compile(tt.schedule)
+			if sched != nil {
+				t.Error("expected no schedule to be returned for an invalid schedule")
+			}
+
+			for _, err := range errs {
+				if err.Window == tt.window && err.Field == tt.field && strings.Contains(err.Message, tt.message) {
+					return
+				}
+			}
+			t.Fatalf("expected an error on %s of window %d containing %q, got %v", tt.field, tt.window, tt.message, errs)
+		})
+	}
+
+	t.Run("valid schedule", func(t *testing.T) {
+		sched := compile(t, types.ServiceScalingSchedule{
+			Apply: types.ScalingScheduleApply_Upgrade,
+			Schedules: []types.ScalingWindow{
+				{Days: "1-5", Start: "08:00", End: "20:00", Replicas: 10},
+				{Days: "5-7", Start: "20:00", End: "08:00", Replicas: 0},
+			},
+		})
+		if sched.Location() != time.UTC {
+			t.Errorf("expected a schedule without a timezone to be in UTC, got %s", sched.Location())
+		}
+	})
+}
+
+func TestScheduleAt(t *testing.T) {
+	sched := compile(t, businessHours())
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		window   string
+		replicas int
+	}{
+		// 8am in New York is 13:00 UTC in winter
+		{name: "before the window opens", at: time.Date(2024, 3, 4, 12, 59, 0, 0, time.UTC), replicas: 2},
+		{name: "when the window opens", at: time.Date(2024, 3, 4, 13, 0, 0, 0, time.UTC), window: "business hours", replicas: 10},
+		{name: "just before the window closes", at: time.Date(2024, 3, 5, 0, 59, 0, 0, time.UTC), window: "business hours", replicas: 10},
+		{name: "when the window closes", at: time.Date(2024, 3, 5, 1, 0, 0, 0, time.UTC), replicas: 2},
+		{name: "weekend", at: time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC), replicas: 2},
+		// 8am in New York is 12:00 UTC once daylight saving time starts on 10 March
+		{name: "when the window opens in daylight saving time", at: time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC), window: "business hours", replicas: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, replicas := sched.At(tt.at, 2)
+			if window != tt.window || replicas != tt.replicas {
+				t.Fatalf("expected window %q with %d replicas, got %q with %d", tt.window, tt.replicas, window, replicas)
+			}
+		})
+	}
+
+	t.Run("overnight window is open on the next day", func(t *testing.T) {
+		sched := compile(t, types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{{Days: "FRI", Start: "22:00", End: "06:00", Replicas: 0}}})
+
+		if _, replicas := sched.At(time.Date(2024, 3, 9, 3, 0, 0, 0, time.UTC), 2); replicas != 0 {
+			t.Errorf("expected the window opened on friday to be open early on saturday, got %d replicas", replicas)
+		}
+		if _, replicas := sched.At(time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC), 2); replicas != 2 {
+			t.Errorf("expected the window not to be open early on sunday, got %d replicas", replicas)
+		}
+	})
+}
+
+func TestScheduleNext(t *testing.T) {
+	sched := compile(t, businessHours())
+
+	t.Run("next window opens after the weekend and daylight saving time change", func(t *testing.T) {
+		// 9pm on friday 8 March in New York
+		next, replicas, ok := sched.Next(time.Date(2024, 3, 9, 2, 0, 0, 0, time.UTC), 2)
+		if !ok {
+			t.Fatal("expected a next transition")
+		}
+
+		expected := time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)
+		if !next.Equal(expected) || replicas != 10 {
+			t.Errorf("expected 10 replicas at %s, got %d at %s", expected, replicas, next.UTC())
+		}
+	})
+
+	t.Run("open window closes on the same day", func(t *testing.T) {
+		next, replicas, ok := sched.Next(time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC), 2)
+
+		expected := time.Date(2024, 3, 5, 1, 0, 0, 0, time.UTC)
+		if !ok || !next.Equal(expected) || replicas != 2 {
+			t.Errorf("expected 2 replicas at %s, got %d at %s", expected, replicas, next.UTC())
+		}
+	})
+
+	t.Run("adjacent windows with the same replicas are a single transition", func(t *testing.T) {
+		sched := compile(t, types.ServiceScalingSchedule{Schedules: []types.ScalingWindow{
+			{Name: "morning", Days: "*", Start: "08:00", End: "12:00", Replicas: 10},
+			{Name: "afternoon", Days: "*", Start: "12:00", End: "18:00", Replicas: 10},
+		}})
+
+		next, replicas, ok := sched.Next(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC), 2)
+
+		expected := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+		if !ok || !next.Equal(expected) || replicas != 2 {
+			t.Errorf("expected 2 replicas at %s, got %d at %s", expected, replicas, next.UTC())
+		}
+	})
+
+	t.Run("windows which run the base replicas never change them", func(t *testing.T) {
+		if _, _, ok := sched.Next(time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC), 10); ok {
+			t.Error("expected no transition")
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+// AppStore reads the apps with scaling schedules and records how their services were scaled
+type AppStore interface {
+	ListPorterAppsWithScalingSchedules(ctx context.Context) ([]*models.PorterApp, error)
+	UpdatePorterAppScalingSchedules(ctx context.Context, app *models.PorterApp) (bool, error)
+}
+
+// ClusterSource connects to the clusters that apps run on
+type ClusterSource interface {
+	// Connect returns the apps on a cluster, or an error if the cluster is unreachable
+	Connect(ctx context.Context, projectID, clusterID uint) (ClusterApps, error)
+}
+
+// ClusterApps scales the services of the apps of a single cluster. Both methods leave services which already run the
+// given replicas untouched.
+type ClusterApps interface {
+	// Patch sets the replicas of the deployment of a service, without a new helm revision
+	Patch(ctx context.Context, appName string, helmName string, replicas int) error
+	// Upgrade upgrades the helm release of an app with the replicas of the given services, by their helm names, set in
+	// its values
+	Upgrade(ctx context.Context, appName string, replicas map[string]int) error
+}
+
+// Options configure how often schedules are evaluated. Zero values use the defaults.
+type Options struct {
+	// Interval is the time between evaluations of every schedule. Defaults to 1m
+	Interval time.Duration
+	// ClusterTimeout bounds the time spent scaling the apps of a single cluster in each evaluation, so that an
+	// unreachable cluster cannot stall the others. Defaults to 2m
+	ClusterTimeout time.Duration
+	// Logger receives a record of skipped clusters and scaled services. Optional
+	Logger *logger.Logger
+}
+
+// Scheduler periodically scales the services of apps to the replicas of the window of their scaling schedule which is
+// open, and back to the replicas they were deployed with once it closes
+type Scheduler struct {
+	apps     AppStore
+	clusters ClusterSource
+	opts     Options
+	log      worker.Logger
+}
+
+// NewScheduler returns a Scheduler with the given options
+func NewScheduler(apps AppStore, clusters ClusterSource, opts Options) *Scheduler {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.ClusterTimeout <= 0 {
+		opts.ClusterTimeout = 2 * time.Minute
+	}
+
+	return &Scheduler{
+		apps:     apps,
+		clusters: clusters,
+		opts:     opts,
+		log:      worker.NewLogger(opts.Logger),
+	}
+}
+
+// Run evaluates every schedule once per interval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) error {
+	return worker.Run(ctx, s.opts.Interval, s.log, "error evaluating scaling schedules", s.evaluate)
+}
+
+// dueScaling is a service of an app which does not run the replicas of its schedule
+type dueScaling struct {
+	name     string
+	service  *models.ScheduledService
+	window   string
+	replicas int
+}
+
+// dueApp is an app with at least one service to scale
+type dueApp struct {
+	app      *models.PorterApp
+	services []dueScaling
+}
+
+// evaluate scales every service which is due at now, returning once every cluster has been scaled or has timed out
+func (s *Scheduler) evaluate(ctx context.Context, now time.Time) error {
+	apps, err := s.apps.ListPorterAppsWithScalingSchedules(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing apps with scaling schedules: %w", err)
+	}
+
+	clusters := make(map[worker.ClusterKey][]dueApp)
+	for _, app := range apps {
+		// the services of a paused app are scaled to zero until it is resumed, which applies its schedules again
+		if app.ScalingSchedulesPaused || app.PausedAt != nil {
+			continue
+		}
+
+		services := s.dueServices(app, now)
+		if len(services) == 0 {
+			continue
+		}
+
+		ck := worker.ClusterKey{ProjectID: app.ProjectID, ClusterID: app.ClusterID}
+		clusters[ck] = append(clusters[ck], dueApp{app: app, services: services})
+	}
+
+	worker.EachCluster(ctx, clusters, worker.ClusterOptions{
+		Timeout: s.opts.ClusterTimeout,
+		Action:  "scaling scheduled apps",
+		Logger:  s.log,
+	}, func(ctx context.Context, ck worker.ClusterKey, apps []dueApp) {
+		s.scaleCluster(ctx, ck, apps, now)
+	})
+
+	return nil
+}
+
+// dueServices returns the services of an app which are not known to run the replicas of their schedule at now. A
+// service which has not been scaled since the app was deployed is always due, since the deploy reset its replicas to
+// the ones it was deployed with; scaling it is a no-op outside of a window.
+func (s *Scheduler) dueServices(app *models.PorterApp, now time.Time) []dueScaling {
+	names := make([]string, 0, len(app.ScalingSchedules))
+	for name := range app.ScalingSchedules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var due []dueScaling
+	for _, name := range names {
+		service := app.ScalingSchedules[name]
+		if service == nil {
+			continue
+		}
+
+		sched, errs := Compile(service.Schedule)
+		if len(errs) != 0 {
+			// schedules are validated when the app is deployed, so this only happens if the rules have since changed
+			s.log.App(zerolog.WarnLevel, app).Str("service", name).Str("error", errs[0].Error()).Msg("invalid scaling schedule, not scaling service")
+			continue
+		}
+
+		window, replicas := sched.At(now, service.BaseReplicas)
+		if service.Applied != nil && service.LastError == "" && service.Applied.Window == window && service.Applied.Replicas == replicas {
+			continue
+		}
+
+		due = append(due, dueScaling{name: name, service: service, window: window, replicas: replicas})
+	}
+
+	return due
+}
+
+func (s *Scheduler) scaleCluster(ctx context.Context, ck worker.ClusterKey, apps []dueApp, now time.Time) {
+	cluster, err := s.clusters.Connect(ctx, ck.ProjectID, ck.ClusterID)
+	if err != nil {
+		s.log.Cluster(zerolog.WarnLevel, ck).Err(err).Msg("cluster is unreachable, skipping its scheduled apps until the next evaluation")
+		return
+	}
+
+	for _, due := range apps {
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.scaleApp(ctx, cluster, due, now)
+	}
+}
+
+// scaleApp scales the due services of an app, then records how each one was scaled. Services which are upgraded are
+// upgraded together, so that the app gets a single revision for every window which opens or closes at the same time.
+func (s *Scheduler) scaleApp(ctx context.Context, cluster ClusterApps, due dueApp, now time.Time) {
+	app := due.app
+
+	var upgrades []dueScaling
+	for _, service := range due.services {
+		if service.service.Schedule.Apply == types.ScalingScheduleApply_Upgrade {
+			upgrades = append(upgrades, service)
+			continue
+		}
+
+		err := cluster.Patch(ctx, app.Name, service.service.HelmName, service.replicas)
+		s.record(app, service, err, now)
+	}
+
+	if len(upgrades) != 0 {
+		replicas := make(map[string]int, len(upgrades))
+		for _, service := range upgrades {
+			replicas[service.service.HelmName] = service.replicas
+		}
+
+		err := cluster.Upgrade(ctx, app.Name, replicas)
+		for _, service := range upgrades {
+			s.record(app, service, err, now)
+		}
+	}
+
+	updated, err := s.apps.UpdatePorterAppScalingSchedules(ctx, app)
+	if err != nil {
+		s.log.App(zerolog.ErrorLevel, app).Err(err).Msg("error recording scaled services, they are checked again in the next evaluation")
+		return
+	}
+	if !updated {
+		// the app was deployed while it was being scaled, which reset the scaling of its services
+		s.log.App(zerolog.InfoLevel, app).Msg("app was updated while its services were being scaled, they are checked again in the next evaluation")
+	}
+}
+
+// record sets how a service was scaled. A service which failed to scale keeps its last applied scaling and is retried
+// in the next evaluation.
+func (s *Scheduler) record(app *models.PorterApp, due dueScaling, err error, now time.Time) {
+	if err != nil {
+		due.service.LastError = err.Error()
+		s.log.App(zerolog.ErrorLevel, app).Err(err).Str("service", due.name).Int("replicas", due.replicas).Msg("error scaling service to its scheduled replicas")
+		return
+	}
+
+	due.service.LastError = ""
+	due.service.Applied = &models.AppliedScaling{Replicas: due.replicas, Window: due.window, At: now}
+	s.log.App(zerolog.InfoLevel, app).Str("service", due.name).Str("window", due.window).Int("replicas", due.replicas).Msg("scaled service to its scheduled replicas")
+}
@@ -0,0 +1,289 @@
+package scaling
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker/workertest"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+// fakeClusterApps records the replicas services are scaled to, by their helm names
+type fakeClusterApps struct {
+	workertest.Cluster
+
+	// err is returned by every call which scales a service
+	err error
+	// onScale is called before every call which scales a service
+	onScale  func()
+	patches  map[string][]int
+	upgrades []map[string]int
+}
+
+func (c *fakeClusterApps) Patch(ctx context.Context, appName string, helmName string, replicas int) error {
+	if c.onScale != nil {
+		c.onScale()
+	}
+	if c.err != nil {
+		return c.err
+	}
+
+	if c.patches == nil {
+		c.patches = map[string][]int{}
+	}
+	c.patches[helmName] = append(c.patches[helmName], replicas)
+	return nil
+}
+
+func (c *fakeClusterApps) Upgrade(ctx context.Context, appName string, replicas map[string]int) error {
+	if c.onScale != nil {
+		c.onScale()
+	}
+	if c.err != nil {
+		return c.err
+	}
+
+	c.upgrades = append(c.upgrades, replicas)
+	return nil
+}
+
+type fakeClusterSource = workertest.Source[ClusterApps, *fakeClusterApps]
+
+// newScheduledApp stores an app whose services run with the given schedules
+func newScheduledApp(t *testing.T, repo repository.PorterAppRepository, name string, schedules models.PorterAppScalingSchedules) *models.PorterApp {
+	t.Helper()
+
+	app, err := repo.CreatePorterApp(&models.PorterApp{ProjectID: 1, ClusterID: 1, Name: name, ScalingSchedules: schedules})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return app
+}
+
+func scheduledService(helmName string, apply types.ScalingScheduleApply) *models.ScheduledService {
+	schedule := businessHours()
+	schedule.Apply = apply
+
+	return &models.ScheduledService{HelmName: helmName, BaseReplicas: 2, Schedule: schedule}
+}
+
+// newYork returns a time on monday 4 March 2024 in New York
+func newYork(t *testing.T, hour, minute int) time.Time {
+	t.Helper()
+
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return time.Date(2024, 3, 4, hour, minute, 0, 0, location)
+}
+
+func evaluate(t *testing.T, s *Scheduler, now time.Time) {
+	t.Helper()
+
+	if err := s.evaluate(context.Background(), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func readApp(t *testing.T, repo repository.PorterAppRepository, id uint) *models.PorterApp {
+	t.Helper()
+
+	app, err := repo.ReadPorterAppByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return app
+}
+
+func TestEvaluate_ScalesServicesWhenWindowsOpenAndClose(t *testing.T) {
+	repo := test.NewRepository(true).PorterApp()
+	app := newScheduledApp(t, repo, "payments", models.PorterAppScalingSchedules{
+		"web": scheduledService("web-web", types.ScalingScheduleApply_Patch),
+	})
+	cluster := &fakeClusterApps{}
+	s := NewScheduler(repo, fakeClusterSource{1: cluster}, Options{})
+
+	// a service which was just deployed is checked against its schedule once, even outside of a window
+	evaluate(t, s, newYork(t, 7, 59))
+	evaluate(t, s, newYork(t, 8, 0))
+	evaluate(t, s, newYork(t, 12, 0))
+	evaluate(t, s, newYork(t, 20, 0))
+	evaluate(t, s, newYork(t, 21, 0))
+
+	patches := cluster.patches["web-web"]
+	if len(patches) != 3 || patches[0] != 2 || patches[1] != 10 || patches[2] != 2 {
+		t.Fatalf("expected web to be scaled to 2, 10 and back to 2 replicas, got %v", patches)
+	}
+
+	applied := readApp(t, repo, app.ID).ScalingSchedules["web"].Applied
+	if applied == nil || applied.Replicas != 2 || applied.Window != "" || !applied.At.Equal(newYork(t, 20, 0)) {
+		t.Errorf("expected the closing of the window to be recorded, got %+v", applied)
+	}
+}
+
+func TestEvaluate_UpgradesServicesOfAnAppTogether(t *testing.T) {
+	repo := test.NewRepository(true).PorterApp()
+	newScheduledApp(t, repo, "payments", models.PorterAppScalingSchedules{
+		"web":    scheduledService("web-web", types.ScalingScheduleApply_Upgrade),
+		"worker": scheduledService("worker-wkr", types.ScalingScheduleApply_Upgrade),
+		"cron":   scheduledService("cron-wkr", types.ScalingScheduleApply_Patch),
+	})
+	cluster := &fakeClusterApps{}
+	s := NewScheduler(repo, fakeClusterSource{1: cluster}, Options{})
+
+	evaluate(t, s, newYork(t, 8, 0))
+
+	if len(cluster.upgrades) != 1 || cluster.upgrades[0]["web-web"] != 10 || cluster.upgrades[0]["worker-wkr"] != 10 || len(cluster.upgrades[0]) != 2 {
+		t.Fatalf("expected a single upgrade of web and worker to 10 replicas, got %v", cluster.upgrades)
+	}
+	if patches := cluster.patches["cron-wkr"]; len(patches) != 1 || patches[0] != 10 {
+		t.Fatalf("expected cron to be patched to 10 replicas, got %v", patches)
+	}
+}
+
+func TestEvaluate_RetriesServicesWhichFailToScale(t *testing.T) {
+	repo := test.NewRepository(true).PorterApp()
+	app := newScheduledApp(t, repo, "payments", models.PorterAppScalingSchedules{
+		"web": scheduledService("web-web", types.ScalingScheduleApply_Patch),
+	})
+	cluster := &fakeClusterApps{err: errors.New("deployment payments-web-web not found")}
+	s := NewScheduler(repo, fakeClusterSource{1: cluster}, Options{})
+
+	evaluate(t, s, newYork(t, 8, 0))
+
+	service := readApp(t, repo, app.ID).ScalingSchedules["web"]
+	if service.Applied != nil || service.LastError != "deployment payments-web-web not found" {
+		t.Fatalf("expected the error to be recorded without an applied scaling, got %+v", service)
+	}
+
+	cluster.err = nil
+	evaluate(t, s, newYork(t, 8, 1))
+
+	service = readApp(t, repo, app.ID).ScalingSchedules["web"]
+	if service.Applied == nil || service.Applied.Replicas != 10 || service.LastError != "" {
+		t.Fatalf("expected the service to be scaled on the next evaluation, got %+v", service)
+	}
+}
+
+func TestEvaluate_SkipsPausedAppsAndUnreachableClusters(t *testing.T) {
+	repo := test.NewRepository(true).PorterApp()
+	paused := newScheduledApp(t, repo, "payments", models.PorterAppScalingSchedules{
+		"web": scheduledService("web-web", types.ScalingScheduleApply_Patch),
+	})
+	paused.ScalingSchedulesPaused = true
+	if _, err := repo.UpdatePorterApp(paused); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	unreachable, err := repo.CreatePorterApp(&models.PorterApp{ProjectID: 1, ClusterID: 2, Name: "billing", ScalingSchedules: models.PorterAppScalingSchedules{
+		"web": scheduledService("web-web", types.ScalingScheduleApply_Patch),
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cluster := &fakeClusterApps{}
+	s := NewScheduler(repo, fakeClusterSource{1: cluster, 2: {Cluster: workertest.Cluster{Unreachable: true}}}, Options{})
+
+	evaluate(t, s, newYork(t, 8, 0))
+
+	if len(cluster.patches) != 0 {
+		t.Errorf("expected a paused app not to be scaled, got %v", cluster.patches)
+	}
+	if service := readApp(t, repo, unreachable.ID).ScalingSchedules["web"]; service.Applied != nil {
+		t.Errorf("expected an app on an unreachable cluster not to be recorded as scaled, got %+v", service.Applied)
+	}
+}
+
+func TestEvaluate_SkipsScaledToZeroApps(t *testing.T) {
+	repo := test.NewRepository(true).PorterApp()
+	app := newScheduledApp(t, repo, "payments", models.PorterAppScalingSchedules{
+		"web": scheduledService("web-web", types.ScalingScheduleApply_Patch),
+	})
+	pausedAt := time.Now()
+	app.PausedAt = &pausedAt
+	if _, err := repo.UpdatePorterApp(app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cluster := &fakeClusterApps{}
+	s := NewScheduler(repo, fakeClusterSource{1: cluster}, Options{})
+
+	evaluate(t, s, newYork(t, 8, 0))
+
+	if len(cluster.patches) != 0 {
+		t.Errorf("expected a paused app to stay scaled to zero, got %v", cluster.patches)
+	}
+}
+
+func TestEvaluate_KeepsDeploysMadeWhileScaling(t *testing.T) {
+	repo := test.NewRepository(true).PorterApp()
+	app := newScheduledApp(t, repo, "payments", models.PorterAppScalingSchedules{
+		"web": scheduledService("web-web", types.ScalingScheduleApply_Patch),
+	})
+
+	// the app is deployed with a new schedule while its services are being scaled
+	cluster := &fakeClusterApps{onScale: func() {
+		deployed := readApp(t, repo, app.ID)
+		deployed.ScalingSchedules = models.PorterAppScalingSchedules{
+			"web": scheduledService("web-web", types.ScalingScheduleApply_Upgrade),
+		}
+		if _, err := repo.UpdatePorterApp(deployed); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}}
+	s := NewScheduler(repo, fakeClusterSource{1: cluster}, Options{})
+
+	evaluate(t, s, newYork(t, 8, 0))
+
+	service := readApp(t, repo, app.ID).ScalingSchedules["web"]
+	if service.Schedule.Apply != types.ScalingScheduleApply_Upgrade || service.Applied != nil {
+		t.Fatalf("expected the deployed schedule to be kept, got %+v", service)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	app := &models.PorterApp{ScalingSchedules: models.PorterAppScalingSchedules{
+		"worker": scheduledService("worker-wkr", types.ScalingScheduleApply_Patch),
+		"web":    scheduledService("web-web", types.ScalingScheduleApply_Patch),
+	}}
+	app.ScalingSchedules["web"].Applied = &models.AppliedScaling{Replicas: 10, Window: "business hours", At: newYork(t, 8, 0)}
+
+	status := Status(app, newYork(t, 9, 0))
+	if status == nil || status.Paused || len(status.Services) != 2 {
+		t.Fatalf("expected the status of both services, got %+v", status)
+	}
+
+	web := status.Services[0]
+	if web.Service != "web" || web.Timezone != "America/New_York" || web.ActiveWindow != "business hours" || web.Replicas != 10 {
+		t.Errorf("expected web to be in its business hours window, got %+v", web)
+	}
+	if web.AppliedReplicas == nil || *web.AppliedReplicas != 10 {
+		t.Errorf("expected web to have been scaled to 10 replicas, got %v", web.AppliedReplicas)
+	}
+	if web.NextTransitionAt == nil || !web.NextTransitionAt.Equal(newYork(t, 20, 0)) || web.NextReplicas != 2 {
+		t.Errorf("expected web to be scaled to 2 replicas at 8pm, got %d at %v", web.NextReplicas, web.NextTransitionAt)
+	}
+	if status.Services[1].Service != "worker" || status.Services[1].AppliedReplicas != nil {
+		t.Errorf("expected worker not to have been scaled, got %+v", status.Services[1])
+	}
+
+	app.ScalingSchedulesPaused = true
+	status = Status(app, newYork(t, 9, 0))
+	if !status.Paused || status.Services[0].NextTransitionAt != nil {
+		t.Errorf("expected a paused app to have no next transition, got %+v", status)
+	}
+
+	if Status(&models.PorterApp{}, newYork(t, 9, 0)) != nil {
+		t.Error("expected no status for an app without scaling schedules")
+	}
+}
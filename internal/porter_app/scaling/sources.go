@@ -0,0 +1,119 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	utils "github.com/porter-dev/porter/api/utils/porter_app"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app/worker"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// NewSchedulerFromConfig returns a Scheduler which reads apps from the server's database and scales their services on
+// each cluster with the server's credentials
+func NewSchedulerFromConfig(conf *config.Config, opts Options) *Scheduler {
+	return NewScheduler(conf.Repo.PorterApp(), worker.NewAgentSource(conf, func(cluster *models.Cluster, agent *kubernetes.Agent) (ClusterApps, error) {
+		return &agentClusterApps{conf: conf, cluster: cluster, agent: agent}, nil
+	}), opts)
+}
+
+type agentClusterApps struct {
+	conf    *config.Config
+	cluster *models.Cluster
+	agent   *kubernetes.Agent
+}
+
+// Patch sets the replicas of the deployment of a service. The deployment of a service is named after the app and the
+// helm name of the service, in the namespace of the app.
+func (c *agentClusterApps) Patch(ctx context.Context, appName string, helmName string, replicas int) error {
+	namespace := utils.NamespaceFromPorterAppName(appName)
+	name := fmt.Sprintf("%s-%s", appName, helmName)
+
+	deployment, err := c.agent.Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error reading deployment %s: %w", name, err)
+	}
+	if deployment.Spec.Replicas != nil && int(*deployment.Spec.Replicas) == replicas {
+		return nil
+	}
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	if _, err := c.agent.Clientset.AppsV1().Deployments(namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("error scaling deployment %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Upgrade upgrades the latest release of an app with the same chart and values, except for the replicas of the given
+// services. The release is left at its revision if every service already has its replicas.
+func (c *agentClusterApps) Upgrade(ctx context.Context, appName string, replicas map[string]int) error {
+	namespace := utils.NamespaceFromPorterAppName(appName)
+
+	helmAgent, err := helm.GetAgentFromK8sAgent("secret", namespace, c.conf.Logger, c.agent)
+	if err != nil {
+		return fmt.Errorf("error getting helm agent: %w", err)
+	}
+
+	rel, err := helmAgent.GetRelease(ctx, appName, 0, false)
+	if err != nil {
+		return fmt.Errorf("error getting latest helm release: %w", err)
+	}
+
+	values := rel.Config
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	if !setReplicaCounts(values, replicas) {
+		return nil
+	}
+
+	registries, err := c.conf.Repo.Registry().ListRegistriesByProjectID(c.cluster.ProjectID)
+	if err != nil {
+		return fmt.Errorf("error listing registries: %w", err)
+	}
+
+	_, err = helmAgent.UpgradeInstallChart(ctx, &helm.InstallChartConfig{
+		Chart:      rel.Chart,
+		Name:       appName,
+		Namespace:  namespace,
+		Values:     values,
+		Cluster:    c.cluster,
+		Repo:       c.conf.Repo,
+		Registries: registries,
+	}, c.conf.DOConf, c.conf.ServerConf.DisablePullSecretsInjection)
+	if err != nil {
+		return fmt.Errorf("error upgrading helm release: %w", err)
+	}
+
+	return nil
+}
+
+// setReplicaCounts sets the replicaCount of each service in the values of an app, by the helm name of the service, and
+// reports whether any of them changed
+func setReplicaCounts(values map[string]interface{}, replicas map[string]int) bool {
+	changed := false
+
+	for helmName, count := range replicas {
+		serviceValues, ok := values[helmName].(map[string]interface{})
+		if !ok {
+			serviceValues = map[string]interface{}{}
+			values[helmName] = serviceValues
+		}
+
+		// replica counts are written as strings or numbers in porter.yaml, depending on how they were quoted
+		if current, ok := serviceValues["replicaCount"]; ok && fmt.Sprint(current) == fmt.Sprint(count) {
+			continue
+		}
+
+		serviceValues["replicaCount"] = count
+		changed = true
+	}
+
+	return changed
+}
@@ -0,0 +1,65 @@
+package scaling
+
+import (
+	"sort"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// Status returns where the services of an app are in their scaling schedules at now, or nil if the app has none. The
+// services of a paused app have no next transition, since they are not scaled until the schedules are resumed.
+func Status(app *models.PorterApp, now time.Time) *types.ScalingScheduleStatus {
+	if app == nil || len(app.ScalingSchedules) == 0 {
+		return nil
+	}
+
+	status := &types.ScalingScheduleStatus{
+		Paused:   app.ScalingSchedulesPaused || app.PausedAt != nil,
+		Services: []types.ServiceScalingStatus{},
+	}
+
+	for name, service := range app.ScalingSchedules {
+		if service == nil {
+			continue
+		}
+
+		serviceStatus := types.ServiceScalingStatus{
+			Service:   name,
+			Timezone:  service.Schedule.Timezone,
+			Replicas:  service.BaseReplicas,
+			LastError: service.LastError,
+		}
+		if service.Applied != nil {
+			replicas, at := service.Applied.Replicas, service.Applied.At
+			serviceStatus.AppliedReplicas = &replicas
+			serviceStatus.AppliedAt = &at
+		}
+
+		sched, errs := Compile(service.Schedule)
+		if len(errs) != 0 {
+			if serviceStatus.LastError == "" {
+				serviceStatus.LastError = errs[0].Error()
+			}
+			status.Services = append(status.Services, serviceStatus)
+			continue
+		}
+
+		serviceStatus.Timezone = sched.Location().String()
+		serviceStatus.ActiveWindow, serviceStatus.Replicas = sched.At(now, service.BaseReplicas)
+
+		if !status.Paused {
+			if next, replicas, ok := sched.Next(now, service.BaseReplicas); ok {
+				serviceStatus.NextTransitionAt = &next
+				serviceStatus.NextReplicas = replicas
+			}
+		}
+
+		status.Services = append(status.Services, serviceStatus)
+	}
+
+	sort.Slice(status.Services, func(i, j int) bool { return status.Services[i].Service < status.Services[j].Service })
+
+	return status
+}
@@ -0,0 +1,137 @@
+package porter_app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SchedulingDefaultsHashKey is the key in the global helm values of an app which records the scheduling defaults the app was deployed with
+const SchedulingDefaultsHashKey = "schedulingDefaultsHash"
+
+const (
+	tolerationOperatorExists = "Exists"
+	tolerationOperatorEqual  = "Equal"
+	tolerationEffectNoExec   = "NoExecute"
+)
+
+var validTolerationEffects = map[string]bool{
+	"":                 true,
+	"NoSchedule":       true,
+	"PreferNoSchedule": true,
+	"NoExecute":        true,
+}
+
+// ValidateSchedulingDefaults checks that a node selector and list of tolerations would be accepted by Kubernetes
+// when applied to a pod spec. These are the same rules Kubernetes enforces on scheduling settings set on a service.
+func ValidateSchedulingDefaults(defaults types.ClusterSchedulingDefaults) error {
+	var errs []string
+
+	for key, value := range defaults.NodeSelector {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Sprintf("invalid node selector key %q: %s", key, msg))
+		}
+
+		for _, msg := range validation.IsValidLabelValue(value) {
+			errs = append(errs, fmt.Sprintf("invalid node selector value %q for key %q: %s", value, key, msg))
+		}
+	}
+
+	for i, toleration := range defaults.Tolerations {
+		if toleration.Key != "" {
+			for _, msg := range validation.IsQualifiedName(toleration.Key) {
+				errs = append(errs, fmt.Sprintf("invalid key for toleration %d: %s", i, msg))
+			}
+		}
+
+		switch toleration.Operator {
+		case tolerationOperatorEqual, "":
+			if toleration.Key == "" {
+				errs = append(errs, fmt.Sprintf("toleration %d must set a key when the operator is %s", i, tolerationOperatorEqual))
+			}
+
+			for _, msg := range validation.IsValidLabelValue(toleration.Value) {
+				errs = append(errs, fmt.Sprintf("invalid value for toleration %d: %s", i, msg))
+			}
+		case tolerationOperatorExists:
+			if toleration.Value != "" {
+				errs = append(errs, fmt.Sprintf("toleration %d must not set a value when the operator is %s", i, tolerationOperatorExists))
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("invalid operator %q for toleration %d: must be one of %s, %s", toleration.Operator, i, tolerationOperatorEqual, tolerationOperatorExists))
+		}
+
+		if !validTolerationEffects[toleration.Effect] {
+			errs = append(errs, fmt.Sprintf("invalid effect %q for toleration %d: must be one of NoSchedule, PreferNoSchedule, NoExecute", toleration.Effect, i))
+		}
+
+		if toleration.TolerationSeconds != nil && toleration.Effect != tolerationEffectNoExec {
+			errs = append(errs, fmt.Sprintf("toleration %d can only set toleration seconds when the effect is %s", i, tolerationEffectNoExec))
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// SchedulingDefaultsHash returns an identifier for a set of scheduling defaults, which is recorded in the helm values of every app
+// deployed with them. An empty string is returned if no defaults are set.
+func SchedulingDefaultsHash(defaults types.ClusterSchedulingDefaults) string {
+	if len(defaults.NodeSelector) == 0 && len(defaults.Tolerations) == 0 {
+		return ""
+	}
+
+	// encoding/json sorts map keys, so equal defaults always hash to the same value
+	by, err := json.Marshal(defaults)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(by)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// SchedulingDefaultsHelmValues converts scheduling defaults into the nodeSelector and tolerations helm values
+// used by the Porter application charts
+func SchedulingDefaultsHelmValues(defaults types.ClusterSchedulingDefaults) (map[string]interface{}, []interface{}) {
+	nodeSelector := make(map[string]interface{}, len(defaults.NodeSelector))
+	for key, value := range defaults.NodeSelector {
+		nodeSelector[key] = value
+	}
+
+	tolerations := make([]interface{}, 0, len(defaults.Tolerations))
+	for _, toleration := range defaults.Tolerations {
+		t := map[string]interface{}{}
+
+		if toleration.Key != "" {
+			t["key"] = toleration.Key
+		}
+		if toleration.Operator != "" {
+			t["operator"] = toleration.Operator
+		}
+		if toleration.Value != "" {
+			t["value"] = toleration.Value
+		}
+		if toleration.Effect != "" {
+			t["effect"] = toleration.Effect
+		}
+		if toleration.TolerationSeconds != nil {
+			t["tolerationSeconds"] = *toleration.TolerationSeconds
+		}
+
+		tolerations = append(tolerations, t)
+	}
+
+	return nodeSelector, tolerations
+}
@@ -0,0 +1,472 @@
+package porter_app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"github.com/stefanmcshane/helm/pkg/release"
+	"golang.org/x/oauth2"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/envgroup"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// envGroupNamespace is the namespace which holds the source of truth for all env groups in a cluster
+const envGroupNamespace = "porter-env-group"
+
+// SnapshotStackInput is the input to SnapshotStack
+type SnapshotStackInput struct {
+	App       *models.PorterApp
+	Namespace string
+	// Release is the latest release of the stack
+	Release *release.Release
+	// PreDeployRelease is the latest release of the stack's pre-deploy job, if one exists
+	PreDeployRelease *release.Release
+	K8sAgent         *kubernetes.Agent
+}
+
+// SnapshotStack captures the config of a stack into a portable snapshot. Env groups and domains are read from the
+// release values, and volumes from the persistent volume claims in the stack namespace.
+func SnapshotStack(ctx context.Context, input SnapshotStackInput) (*types.StackSnapshot, error) {
+	ctx, span := telemetry.NewSpan(ctx, "snapshot-stack")
+	defer span.End()
+
+	if input.App == nil {
+		return nil, telemetry.Error(ctx, span, nil, "app is nil")
+	}
+	if input.Release == nil {
+		return nil, telemetry.Error(ctx, span, nil, "release is nil")
+	}
+	if input.K8sAgent == nil {
+		return nil, telemetry.Error(ctx, span, nil, "k8s agent is nil")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: input.App.Name},
+		telemetry.AttributeKV{Key: "namespace", Value: input.Namespace},
+	)
+
+	snapshot := &types.StackSnapshot{
+		Version:   types.StackSnapshotVersion,
+		CreatedAt: time.Now().UTC(),
+		App:       input.App.ToPorterAppType(),
+		Namespace: input.Namespace,
+		Values:    input.Release.Config,
+		EnvGroups: snapshotEnvGroups(input.Release.Config),
+		Domains:   snapshotDomains(input.Release.Config),
+		Volumes:   []types.StackSnapshotVolume{},
+	}
+
+	if input.PreDeployRelease != nil {
+		snapshot.PreDeployValues = input.PreDeployRelease.Config
+	}
+
+	pvcs, err := input.K8sAgent.Clientset.CoreV1().PersistentVolumeClaims(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing persistent volume claims")
+	}
+
+	for _, pvc := range pvcs.Items {
+		volume := types.StackSnapshotVolume{
+			Name:        pvc.Name,
+			VolumeName:  pvc.Spec.VolumeName,
+			AccessModes: []string{},
+		}
+
+		if pvc.Spec.StorageClassName != nil {
+			volume.StorageClass = *pvc.Spec.StorageClassName
+		}
+
+		if size, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+			volume.Size = size.String()
+		}
+
+		for _, mode := range pvc.Spec.AccessModes {
+			volume.AccessModes = append(volume.AccessModes, string(mode))
+		}
+
+		snapshot.Volumes = append(snapshot.Volumes, volume)
+	}
+
+	sort.Slice(snapshot.Volumes, func(i, j int) bool {
+		return snapshot.Volumes[i].Name < snapshot.Volumes[j].Name
+	})
+
+	return snapshot, nil
+}
+
+// StackSnapshotRestoreValues returns a copy of the helm values in the snapshot which can be used to deploy a stack with the given name.
+// If the stack is being restored under a new name, Porter-managed subdomains are removed so that new ones are created for the stack.
+func StackSnapshotRestoreValues(snapshot *types.StackSnapshot, name string) (map[string]interface{}, error) {
+	if snapshot == nil {
+		return nil, errors.New("snapshot is nil")
+	}
+
+	by, err := json.Marshal(snapshot.Values)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling snapshot values: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(by, &values); err != nil {
+		return nil, fmt.Errorf("error unmarshaling snapshot values: %w", err)
+	}
+
+	if snapshot.App != nil && snapshot.App.Name == name {
+		return values, nil
+	}
+
+	for _, service := range serviceValues(values) {
+		if ingress, ok := service["ingress"].(map[string]interface{}); ok {
+			delete(ingress, "porter_hosts")
+		}
+	}
+
+	return values, nil
+}
+
+// RestoreStackInput is the input to RestoreStack
+type RestoreStackInput struct {
+	Snapshot  *types.StackSnapshot
+	Name      string
+	Namespace string
+	// Chart and Values are the stack chart and values to install, built from the snapshot values
+	Chart  *chart.Chart
+	Values map[string]interface{}
+
+	Cluster                     *models.Cluster
+	Repo                        repository.Repository
+	Registries                  []*models.Registry
+	HelmAgent                   *helm.Agent
+	K8sAgent                    *kubernetes.Agent
+	DOConf                      *oauth2.Config
+	DisablePullSecretsInjection bool
+}
+
+// RestoreStackOutput is the output of RestoreStack
+type RestoreStackOutput struct {
+	Release           *release.Release
+	ReattachedVolumes []string
+	NewVolumes        []string
+	MissingEnvGroups  []string
+}
+
+// RestoreStack recreates a stack from a snapshot. Env groups referenced by the snapshot are cloned into the stack namespace,
+// volumes which still exist are reattached, and the stack chart is installed. The pre-deploy job is not run.
+func RestoreStack(ctx context.Context, input RestoreStackInput) (RestoreStackOutput, error) {
+	ctx, span := telemetry.NewSpan(ctx, "restore-stack")
+	defer span.End()
+
+	output := RestoreStackOutput{
+		ReattachedVolumes: []string{},
+		NewVolumes:        []string{},
+		MissingEnvGroups:  []string{},
+	}
+
+	if input.Snapshot == nil {
+		return output, telemetry.Error(ctx, span, nil, "snapshot is nil")
+	}
+	if input.Snapshot.Version != types.StackSnapshotVersion {
+		return output, telemetry.Error(ctx, span, nil, fmt.Sprintf("unsupported snapshot version %q", input.Snapshot.Version))
+	}
+	if input.HelmAgent == nil || input.K8sAgent == nil {
+		return output, telemetry.Error(ctx, span, nil, "helm agent and k8s agent are required")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: input.Name},
+		telemetry.AttributeKV{Key: "namespace", Value: input.Namespace},
+	)
+
+	if _, err := input.K8sAgent.CreateNamespace(ctx, input.Namespace, nil); err != nil {
+		return output, telemetry.Error(ctx, span, err, "error creating namespace")
+	}
+
+	envGroupVersions := make(map[string]uint)
+	for _, envGroupRef := range input.Snapshot.EnvGroups {
+		version, err := cloneEnvGroup(ctx, input.K8sAgent, envGroupRef.Name, input.Namespace)
+		if err != nil {
+			if errors.Is(err, kubernetes.IsNotFoundError) {
+				output.MissingEnvGroups = append(output.MissingEnvGroups, envGroupRef.Name)
+				continue
+			}
+
+			return output, telemetry.Error(ctx, span, err, fmt.Sprintf("error cloning env group %s", envGroupRef.Name))
+		}
+
+		envGroupVersions[envGroupRef.Name] = version
+	}
+
+	// the synced env group versions in the values refer to the versions in the old namespace
+	for _, service := range serviceValues(input.Values) {
+		for _, group := range syncedEnvGroups(service) {
+			name, _ := group["name"].(string)
+			if version, ok := envGroupVersions[name]; ok {
+				group["version"] = version
+			}
+		}
+	}
+
+	for _, volume := range input.Snapshot.Volumes {
+		reattached, err := reattachVolume(ctx, input.K8sAgent, volume, input.Namespace)
+		if err != nil {
+			return output, telemetry.Error(ctx, span, err, fmt.Sprintf("error reattaching volume %s", volume.Name))
+		}
+
+		if reattached {
+			output.ReattachedVolumes = append(output.ReattachedVolumes, volume.Name)
+		} else {
+			output.NewVolumes = append(output.NewVolumes, volume.Name)
+		}
+	}
+
+	rel, err := input.HelmAgent.InstallChart(ctx, &helm.InstallChartConfig{
+		Chart:      input.Chart,
+		Name:       input.Name,
+		Namespace:  input.Namespace,
+		Values:     input.Values,
+		Cluster:    input.Cluster,
+		Repo:       input.Repo,
+		Registries: input.Registries,
+	}, input.DOConf, input.DisablePullSecretsInjection)
+	if err != nil {
+		return output, telemetry.Error(ctx, span, err, "error installing stack chart")
+	}
+
+	output.Release = rel
+
+	return output, nil
+}
+
+// cloneEnvGroup copies the latest version of an env group into the given namespace, returning the version of the copy
+func cloneEnvGroup(ctx context.Context, agent *kubernetes.Agent, name, namespace string) (uint, error) {
+	cm, _, err := agent.GetLatestVersionedConfigMap(ctx, name, envGroupNamespace)
+	if err != nil {
+		return 0, err
+	}
+
+	vars := make(map[string]string)
+	for key, val := range cm.Data {
+		if !strings.Contains(val, "PORTERSECRET") {
+			vars[key] = val
+		}
+	}
+
+	secretVars := make(map[string]string)
+
+	// env groups without secret variables may not have a secret
+	secret, _, err := agent.GetLatestVersionedSecret(ctx, name, envGroupNamespace)
+	if err != nil && !errors.Is(err, kubernetes.IsNotFoundError) {
+		return 0, err
+	}
+	if secret != nil {
+		for key, val := range secret.Data {
+			secretVars[key] = string(val)
+		}
+	}
+
+	configMap, err := envgroup.CreateEnvGroup(ctx, agent, types.ConfigMapInput{
+		Name:            name,
+		Namespace:       namespace,
+		Variables:       vars,
+		SecretVariables: secretVars,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	clonedEnvGroup, err := envgroup.ToEnvGroup(configMap)
+	if err != nil {
+		return 0, err
+	}
+
+	return clonedEnvGroup.Version, nil
+}
+
+// reattachVolume makes a volume from a snapshot available to the stack in the given namespace. It returns true if the claim already exists
+// in the namespace, or if the persistent volume it was bound to was retained and has been bound to a new claim.
+func reattachVolume(ctx context.Context, agent *kubernetes.Agent, volume types.StackSnapshotVolume, namespace string) (bool, error) {
+	_, err := agent.Clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, volume.Name, metav1.GetOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return false, err
+	}
+
+	if volume.VolumeName == "" {
+		return false, nil
+	}
+
+	pv, err := agent.Clientset.CoreV1().PersistentVolumes().Get(ctx, volume.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	// only volumes which are no longer claimed can be reattached, otherwise the data is still in use by another claim
+	if pv.Status.Phase != v1.VolumeReleased && pv.Status.Phase != v1.VolumeAvailable {
+		return false, nil
+	}
+
+	if pv.Spec.ClaimRef != nil {
+		pv.Spec.ClaimRef = nil
+
+		if _, err := agent.Clientset.CoreV1().PersistentVolumes().Update(ctx, pv, metav1.UpdateOptions{}); err != nil {
+			return false, fmt.Errorf("error releasing persistent volume claim ref: %w", err)
+		}
+	}
+
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      volume.Name,
+			Namespace: namespace,
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			VolumeName:  volume.VolumeName,
+			Resources:   v1.ResourceRequirements{Requests: pv.Spec.Capacity},
+			AccessModes: pv.Spec.AccessModes,
+		},
+	}
+
+	// an empty storage class is set explicitly so that the default storage class does not provision a new volume
+	storageClass := pv.Spec.StorageClassName
+	pvc.Spec.StorageClassName = &storageClass
+
+	if _, err := agent.Clientset.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{}); err != nil {
+		return false, fmt.Errorf("error creating persistent volume claim: %w", err)
+	}
+
+	return true, nil
+}
+
+// serviceValues returns the values of each service in the stack values, keyed by the helm name of the service
+func serviceValues(values map[string]interface{}) map[string]map[string]interface{} {
+	res := make(map[string]map[string]interface{})
+
+	for key, val := range values {
+		if key == "global" {
+			continue
+		}
+
+		if service, ok := val.(map[string]interface{}); ok {
+			res[key] = service
+		}
+	}
+
+	return res
+}
+
+// syncedEnvGroups returns the env groups synced to a service, read from container.env.synced
+func syncedEnvGroups(service map[string]interface{}) []map[string]interface{} {
+	container, ok := service["container"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	env, ok := container["env"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	synced, ok := env["synced"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	groups := make([]map[string]interface{}, 0, len(synced))
+	for _, s := range synced {
+		if group, ok := s.(map[string]interface{}); ok {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+func snapshotEnvGroups(values map[string]interface{}) []types.StackSnapshotEnvGroup {
+	seen := make(map[string]bool)
+	res := []types.StackSnapshotEnvGroup{}
+
+	for _, service := range serviceValues(values) {
+		for _, group := range syncedEnvGroups(service) {
+			name, _ := group["name"].(string)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			envGroup := types.StackSnapshotEnvGroup{Name: name}
+
+			switch version := group["version"].(type) {
+			case float64:
+				envGroup.Version = uint(version)
+			case int:
+				envGroup.Version = uint(version)
+			case int64:
+				envGroup.Version = uint(version)
+			case uint:
+				envGroup.Version = version
+			}
+
+			res = append(res, envGroup)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	return res
+}
+
+func snapshotDomains(values map[string]interface{}) []types.StackSnapshotDomain {
+	res := []types.StackSnapshotDomain{}
+
+	for name, service := range serviceValues(values) {
+		ingress, ok := service["ingress"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, key := range []string{"porter_hosts", "hosts"} {
+			hosts, _ := ingress[key].([]interface{})
+
+			for _, host := range hosts {
+				if hostStr, ok := host.(string); ok && hostStr != "" {
+					res = append(res, types.StackSnapshotDomain{
+						Service:       name,
+						Host:          hostStr,
+						PorterManaged: key == "porter_hosts",
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Service != res[j].Service {
+			return res[i].Service < res[j].Service
+		}
+		return res[i].Host < res[j].Host
+	})
+
+	return res
+}
@@ -107,7 +107,7 @@ func GetServiceStatus(ctx context.Context, inp GetServiceStatusInput) (ServiceSt
 		LabelKey_ServiceName, inp.ServiceName,
 	)
 
-	podList, err := inp.Agent.GetPodsByLabel(selectorString, inp.DeploymentTarget.Namespace)
+	podList, err := inp.Agent.GetPodsByLabel(ctx, selectorString, inp.DeploymentTarget.Namespace)
 	if err != nil {
 		return serviceStatus, telemetry.Error(ctx, span, err, "error getting pods by label")
 	}
@@ -0,0 +1,198 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/matryer/is"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+)
+
+// fakeGithub is an in-memory github api which serves the pull request and issue comment endpoints used by the deploy summary commenter
+type fakeGithub struct {
+	mu sync.Mutex
+
+	prState  string
+	comments []*github.IssueComment
+
+	creates int
+	edits   int
+	// rateLimitedCreates is the number of comment creations which are rejected by the secondary rate limit before one succeeds
+	rateLimitedCreates int
+}
+
+func (f *fakeGithub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/porter-dev/app/pulls/1":
+		_ = json.NewEncoder(w).Encode(&github.PullRequest{State: github.String(f.prState)})
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/porter-dev/app/issues/1/comments":
+		_ = json.NewEncoder(w).Encode(f.comments)
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/porter-dev/app/issues/1/comments":
+		if f.rateLimitedCreates > 0 {
+			f.rateLimitedCreates--
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"secondary rate limit","documentation_url":"https://docs.github.com/rest#abuse-rate-limits"}`))
+			return
+		}
+
+		comment := &github.IssueComment{}
+		_ = json.NewDecoder(r.Body).Decode(comment)
+		comment.ID = github.Int64(int64(len(f.comments) + 1))
+		f.comments = append(f.comments, comment)
+		f.creates++
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(comment)
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/repos/porter-dev/app/issues/comments/"):
+		edited := &github.IssueComment{}
+		_ = json.NewDecoder(r.Body).Decode(edited)
+
+		for _, comment := range f.comments {
+			if fmt.Sprintf("/repos/porter-dev/app/issues/comments/%d", comment.GetID()) == r.URL.Path {
+				comment.Body = edited.Body
+				f.edits++
+				_ = json.NewEncoder(w).Encode(comment)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeGithubClient(t *testing.T, fake *fakeGithub) *github.Client {
+	t.Helper()
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = baseURL
+
+	return client
+}
+
+func TestUpsertDeploySummaryCommentUpdatesInPlace(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	fake := &fakeGithub{
+		prState:  "open",
+		comments: []*github.IssueComment{{ID: github.Int64(100), Body: github.String("looks good to me")}},
+	}
+	client := newFakeGithubClient(t, fake)
+
+	pr, err := porter_app.ParsePullRequestURL("https://github.com/porter-dev/app/pull/1")
+	is.NoErr(err)
+	is.Equal(pr, porter_app.PullRequest{Owner: "porter-dev", Repo: "app", Number: 1})
+
+	err = porter_app.UpsertDeploySummaryComment(ctx, porter_app.UpsertDeploySummaryCommentInput{
+		Client:      client,
+		PullRequest: pr,
+		Summary: porter_app.DeploySummary{
+			AppName:        "web",
+			RevisionNumber: 1,
+			Status:         models.AppRevisionStatus_InstallFailed,
+			ImageTag:       "abc123",
+		},
+	})
+	is.NoErr(err)
+
+	err = porter_app.UpsertDeploySummaryComment(ctx, porter_app.UpsertDeploySummaryCommentInput{
+		Client:      client,
+		PullRequest: pr,
+		Summary: porter_app.DeploySummary{
+			AppName:        "web",
+			RevisionNumber: 2,
+			Status:         models.AppRevisionStatus_InstallSuccessful,
+			ImageTag:       "def456",
+			AppURLs:        []string{"web.example.com"},
+		},
+	})
+	is.NoErr(err)
+
+	is.Equal(fake.creates, 1)
+	is.Equal(fake.edits, 1)
+	is.Equal(len(fake.comments), 2)
+	is.Equal(fake.comments[0].GetBody(), "looks good to me")
+
+	body := fake.comments[1].GetBody()
+	is.True(strings.HasPrefix(body, "<!-- porter-deploy-summary:web -->"))
+	is.True(strings.Contains(body, "| Revision | 2 |"))
+	is.True(strings.Contains(body, "`def456`"))
+	is.True(strings.Contains(body, "https://web.example.com"))
+	is.True(!strings.Contains(body, "Diagnostics"))
+}
+
+func TestUpsertDeploySummaryCommentSkipsClosedPullRequest(t *testing.T) {
+	is := is.New(t)
+
+	fake := &fakeGithub{prState: "closed"}
+	client := newFakeGithubClient(t, fake)
+
+	input := porter_app.UpsertDeploySummaryCommentInput{
+		Client:                 client,
+		PullRequest:            porter_app.PullRequest{Owner: "porter-dev", Repo: "app", Number: 1},
+		Summary:                porter_app.DeploySummary{AppName: "web", Status: models.AppRevisionStatus_InstallSuccessful},
+		SkipClosedPullRequests: true,
+	}
+
+	is.NoErr(porter_app.UpsertDeploySummaryComment(context.Background(), input))
+	is.Equal(fake.creates, 0)
+
+	// closed pull requests are still updated unless the project opts out
+	input.SkipClosedPullRequests = false
+	is.NoErr(porter_app.UpsertDeploySummaryComment(context.Background(), input))
+	is.Equal(fake.creates, 1)
+}
+
+func TestUpsertDeploySummaryCommentRetriesRateLimit(t *testing.T) {
+	is := is.New(t)
+
+	fake := &fakeGithub{prState: "open", rateLimitedCreates: 2}
+	client := newFakeGithubClient(t, fake)
+
+	err := porter_app.UpsertDeploySummaryComment(context.Background(), porter_app.UpsertDeploySummaryCommentInput{
+		Client:      client,
+		PullRequest: porter_app.PullRequest{Owner: "porter-dev", Repo: "app", Number: 1},
+		Summary:     porter_app.DeploySummary{AppName: "web", Status: models.AppRevisionStatus_BuildFailed},
+	})
+	is.NoErr(err)
+
+	is.Equal(fake.rateLimitedCreates, 0)
+	is.Equal(fake.creates, 1)
+	is.True(strings.Contains(fake.comments[0].GetBody(), "**Diagnostics**: The image failed to build."))
+}
+
+func TestParsePullRequestURLInvalid(t *testing.T) {
+	is := is.New(t)
+
+	for _, pullRequestURL := range []string{
+		"https://github.com/porter-dev/app",
+		"https://github.com/porter-dev/app/issues/1",
+		"https://github.com/porter-dev/app/pull/abc",
+	} {
+		_, err := porter_app.ParsePullRequestURL(pullRequestURL)
+		is.True(err != nil)
+	}
+}
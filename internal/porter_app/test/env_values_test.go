@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	pemValue     = "-----BEGIN CERTIFICATE-----\nMIIBszCCAVmgAwIBAgIUQ7v1bXlrZXk=\n\tindented with a tab \n-----END CERTIFICATE-----\n"
+	crlfValue    = "{\r\n  \"name\": \"test\",\r\n  \"nested\": {\"id\": 1}\r\n}\r\n"
+	unicodeValue = "café 日本語 \U0001F680"
+)
+
+const envValuesPorterYaml = `version: v2
+name: test-app
+services:
+  - name: web
+    type: web
+    run: node index.js
+    port: 8080
+env:
+  PORT: "8080"
+  TLS_CERT:
+    fromFile: cert.pem
+  CONFIG:
+    fromFile: config.json
+  GREETING: |
+    hello
+      world
+previews:
+  env:
+    - key: TLS_CERT
+      fromFile: cert.pem
+    - key: GREETING
+      value: ` + "\"café 日本語 \U0001F680\"" + `
+`
+
+func TestEnvValuesRoundTrip(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	is.NoErr(os.WriteFile(filepath.Join(dir, "cert.pem"), []byte(pemValue), 0o600))
+	is.NoErr(os.WriteFile(filepath.Join(dir, "config.json"), []byte(crlfValue), 0o600))
+
+	// apply: the CLI inlines files before the server parses porter.yaml
+	resolved, err := envvalues.ResolveFiles([]byte(envValuesPorterYaml), dir)
+	is.NoErr(err) // files should be inlined without issues
+
+	deployed, err := porter_app.ParseYAML(ctx, resolved, "test-app")
+	is.NoErr(err) // resolved porter yaml should parse without issues
+
+	wantEnv := map[string]string{
+		"PORT":     "8080",
+		"TLS_CERT": pemValue,
+		"CONFIG":   crlfValue,
+		"GREETING": "hello\n  world\n",
+	}
+	is.Equal(deployed.EnvVariables, wantEnv)
+
+	// preview: overrides are parsed the same way as the app env
+	is.True(deployed.PreviewApp != nil)
+	is.Equal(deployed.PreviewApp.EnvVariables, map[string]string{
+		"TLS_CERT": pemValue,
+		"GREETING": unicodeValue,
+	})
+
+	// export: the env is written back to porter.yaml in the same way as the yaml from revision endpoint
+	exported, err := v2.AppFromProto(deployed.AppProto)
+	is.NoErr(err)
+
+	keys := make([]string, 0, len(deployed.EnvVariables))
+	for key := range deployed.EnvVariables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		exported.Env = append(exported.Env, v2.EnvVariableDefinition{
+			Key:    key,
+			Source: v2.EnvVariableSource_Value,
+			Value: v2.EnvValueOptional{
+				Value: deployed.EnvVariables[key],
+				IsSet: true,
+			},
+		})
+	}
+
+	exportedYaml, err := yaml.Marshal(exported)
+	is.NoErr(err)
+
+	redeployed, err := porter_app.ParseYAML(ctx, exportedYaml, "test-app")
+	is.NoErr(err) // exported porter yaml should parse without issues
+	is.Equal(redeployed.EnvVariables, wantEnv)
+}
+
+func TestEnvValuesRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		wantErr string
+	}{
+		{name: "number", env: "env:\n  PORT: 8080\n", wantErr: "env variable PORT was parsed as the number 8080"},
+		{name: "boolean", env: "env:\n  DEBUG: true\n", wantErr: "env variable DEBUG was parsed as the boolean true"},
+		{name: "number in a list", env: "env:\n  - key: REPLICAS\n    value: 3\n", wantErr: "env variable REPLICAS was parsed as the number 3"},
+		{name: "stray indent", env: "env:\n  CERT:\n    -----BEGIN: x\n", wantErr: "env variable CERT was parsed as a mapping"},
+		{name: "unresolved fromFile", env: "env:\n  CERT:\n    fromFile: cert.pem\n", wantErr: "only supported when applying porter.yaml with the porter CLI"},
+		{name: "value too large", env: "env:\n  BLOB: " + strings.Repeat("a", envvalues.MaxValueBytes+1) + "\n", wantErr: "env variable BLOB of app test-app is 65537 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			porterYaml := "version: v2\nname: test-app\nservices:\n  - name: web\n    type: web\n    run: node index.js\n    port: 8080\n" + tt.env
+
+			_, err := porter_app.ParseYAML(context.Background(), []byte(porterYaml), "test-app")
+			is.True(err != nil) // invalid env values should be rejected
+			is.True(strings.Contains(err.Error(), tt.wantErr))
+		})
+	}
+}
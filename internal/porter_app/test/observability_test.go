@@ -0,0 +1,81 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/porter_app"
+)
+
+func TestObservabilityLabels(t *testing.T) {
+	is := is.New(t)
+
+	labels := porter_app.ObservabilityLabels(porter_app.ObservabilityWorkload{
+		AppName:     "storefront",
+		ServiceName: "web",
+		Version:     "feature/" + strings.Repeat("a", 70) + "-",
+	})
+
+	is.Equal(labels[porter_app.LabelKey_Name], "web")
+	is.Equal(labels[porter_app.LabelKey_PartOf], "storefront")
+	// the image tag is converted into a valid label value
+	is.Equal(labels[porter_app.LabelKey_Version], "feature-"+strings.Repeat("a", 55))
+
+	labels = porter_app.ObservabilityLabels(porter_app.ObservabilityWorkload{AppName: "storefront", ServiceName: "web"})
+	_, ok := labels[porter_app.LabelKey_Version]
+	is.True(!ok) // no version label without an image tag
+}
+
+func TestObservabilityEnv(t *testing.T) {
+	is := is.New(t)
+
+	workload := porter_app.ObservabilityWorkload{AppName: "storefront", ServiceName: "web", Namespace: "porter-stack-storefront", Version: "v1"}
+
+	is.Equal(porter_app.ObservabilityEnv(workload, nil), nil)
+
+	env := porter_app.ObservabilityEnv(workload, &types.ProjectObservabilityConfig{
+		OTLPEndpoint: "https://otlp.example.com",
+		OTLPProtocol: "http/protobuf",
+		SampleRate:   1,
+	})
+	is.Equal(env["OTEL_SERVICE_NAME"], "storefront-web")
+	is.Equal(env["OTEL_EXPORTER_OTLP_PROTOCOL"], "http/protobuf")
+	is.Equal(env["OTEL_TRACES_SAMPLER_ARG"], "1")
+}
+
+func TestInjectObservabilityValues(t *testing.T) {
+	is := is.New(t)
+
+	values := map[string]interface{}{
+		"labels": map[string]string{porter_app.LabelKey_PartOf: "shop"},
+		"container": map[string]interface{}{
+			"env": map[string]interface{}{
+				"normal": map[string]interface{}{"OTEL_TRACES_SAMPLER": "always_on"},
+			},
+		},
+	}
+
+	warnings := porter_app.InjectObservabilityValues(values, porter_app.ObservabilityWorkload{
+		AppName:     "storefront",
+		ServiceName: "web",
+		InjectEnv:   true,
+	}, &types.ProjectObservabilityConfig{OTLPEndpoint: "https://otlp.example.com", SampleRate: 0.5})
+
+	labels := values["labels"].(map[string]interface{})
+	is.Equal(labels[porter_app.LabelKey_PartOf], "shop") // set in porter.yaml
+	is.Equal(labels[porter_app.LabelKey_Name], "web")
+
+	podLabels := values["podLabels"].(map[string]interface{})
+	is.Equal(podLabels[porter_app.LabelKey_PartOf], "storefront")
+
+	env := values["container"].(map[string]interface{})["env"].(map[string]interface{})["normal"].(map[string]interface{})
+	is.Equal(env["OTEL_TRACES_SAMPLER"], "always_on") // set in porter.yaml
+	is.Equal(env["OTEL_EXPORTER_OTLP_ENDPOINT"], "https://otlp.example.com")
+
+	is.Equal(len(warnings), 2)
+	is.True(strings.HasPrefix(warnings[0], "env variable OTEL_TRACES_SAMPLER of service web"))
+	is.True(strings.HasPrefix(warnings[1], "label app.kubernetes.io/part-of of service web"))
+}
@@ -0,0 +1,194 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/porter_app"
+)
+
+func runJobSourcePod() *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-web-7d9f8-abcde",
+			Namespace: "porter-stack-app",
+			Labels:    map[string]string{"porter.run/porter-application": "true"},
+		},
+		Spec: v1.PodSpec{
+			NodeName:           "node-1",
+			ServiceAccountName: "app-web",
+			Containers: []v1.Container{
+				{
+					Name:    "web",
+					Image:   "registry.io/app:abc123",
+					Command: []string{"bundle", "exec", "puma"},
+					Env:     []v1.EnvVar{{Name: "DATABASE_URL", Value: "postgres://db"}},
+					EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "app-env"}}}},
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("512Mi")},
+					},
+					Ports:         []v1.ContainerPort{{ContainerPort: 80}},
+					LivenessProbe: &v1.Probe{},
+				},
+				{Name: "cloudsql-proxy", Image: "gcr.io/cloudsql-proxy"},
+			},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func TestLaunchRunJob(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	k8sAgent := kubernetes.GetAgentTesting(runJobSourcePod())
+
+	run, err := porter_app.LaunchRunJob(ctx, porter_app.LaunchRunJobInput{
+		AppName:   "app",
+		Namespace: "porter-stack-app",
+		Command:   "rake backfill",
+		K8sAgent:  k8sAgent,
+	})
+	is.NoErr(err)
+	is.True(run.ID != "")
+	is.Equal(run.Status, porter_app.JobRunStatus_Running)
+	is.Equal(run.Name, "app-run-"+run.ID)
+
+	job, err := k8sAgent.Clientset.BatchV1().Jobs("porter-stack-app").Get(ctx, run.Name, metav1.GetOptions{})
+	is.NoErr(err)
+
+	// finished jobs are cleaned up, and failed jobs are not retried
+	is.True(job.Spec.TTLSecondsAfterFinished != nil && *job.Spec.TTLSecondsAfterFinished > 0)
+	is.Equal(*job.Spec.BackoffLimit, int32(0))
+	is.Equal(job.Labels[porter_app.LabelKey_RunJobID], run.ID)
+	is.Equal(job.Spec.Template.Labels[porter_app.LabelKey_RunJobID], run.ID)
+
+	// only the app container is run, with the stack's image, env and resources
+	podSpec := job.Spec.Template.Spec
+	is.Equal(podSpec.RestartPolicy, v1.RestartPolicyNever)
+	is.Equal(podSpec.NodeName, "")
+	is.Equal(podSpec.ServiceAccountName, "app-web")
+	is.Equal(len(podSpec.Containers), 1)
+
+	container := podSpec.Containers[0]
+	is.Equal(container.Image, "registry.io/app:abc123")
+	is.Equal(container.Command, []string{"rake"})
+	is.Equal(container.Args, []string{"backfill"})
+	is.Equal(container.Env, []v1.EnvVar{{Name: "DATABASE_URL", Value: "postgres://db"}})
+	is.Equal(container.EnvFrom[0].SecretRef.Name, "app-env")
+	is.Equal(container.Resources.Requests.Cpu().String(), "250m")
+	is.Equal(container.LivenessProbe, nil)
+	is.Equal(len(container.Ports), 0)
+}
+
+func TestLaunchRunJobBuildpacksLauncher(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	k8sAgent := kubernetes.GetAgentTesting(runJobSourcePod())
+
+	run, err := porter_app.LaunchRunJob(ctx, porter_app.LaunchRunJobInput{
+		AppName:   "app",
+		Namespace: "porter-stack-app",
+		Builder:   "heroku/buildpacks:20",
+		Command:   "rake backfill",
+		K8sAgent:  k8sAgent,
+	})
+	is.NoErr(err)
+
+	job, err := k8sAgent.Clientset.BatchV1().Jobs("porter-stack-app").Get(ctx, run.Name, metav1.GetOptions{})
+	is.NoErr(err)
+	is.Equal(job.Spec.Template.Spec.Containers[0].Command, []string{"/cnb/lifecycle/launcher"})
+	is.Equal(job.Spec.Template.Spec.Containers[0].Args, []string{"rake", "backfill"})
+}
+
+func TestLaunchRunJobWithoutRunningPod(t *testing.T) {
+	is := is.New(t)
+
+	pod := runJobSourcePod()
+	pod.Status.Phase = v1.PodPending
+
+	_, err := porter_app.LaunchRunJob(context.Background(), porter_app.LaunchRunJobInput{
+		AppName:   "app",
+		Namespace: "porter-stack-app",
+		Command:   "rake backfill",
+		K8sAgent:  kubernetes.GetAgentTesting(pod),
+	})
+	is.True(err != nil)
+}
+
+func TestGetRunJobResult(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	k8sAgent := kubernetes.GetAgentTesting(runJobSourcePod())
+
+	run, err := porter_app.LaunchRunJob(ctx, porter_app.LaunchRunJobInput{
+		AppName:   "app",
+		Namespace: "porter-stack-app",
+		Command:   "rake backfill",
+		K8sAgent:  k8sAgent,
+	})
+	is.NoErr(err)
+
+	// before the job's pod is scheduled the run is reported as running, without logs
+	got, err := porter_app.GetRunJob(ctx, porter_app.GetRunJobInput{
+		Namespace: "porter-stack-app",
+		RunID:     run.ID,
+		K8sAgent:  k8sAgent,
+	})
+	is.NoErr(err)
+	is.Equal(got.Status, porter_app.JobRunStatus_Running)
+	is.Equal(got.Command, "rake backfill")
+	is.Equal(len(got.Logs), 0)
+
+	// simulate the job controller running the job to completion
+	job, err := k8sAgent.Clientset.BatchV1().Jobs("porter-stack-app").Get(ctx, run.Name, metav1.GetOptions{})
+	is.NoErr(err)
+
+	completedAt := metav1.Now()
+	job.Status = batchv1.JobStatus{Succeeded: 1, CompletionTime: &completedAt}
+	_, err = k8sAgent.Clientset.BatchV1().Jobs("porter-stack-app").UpdateStatus(ctx, job, metav1.UpdateOptions{})
+	is.NoErr(err)
+
+	_, err = k8sAgent.Clientset.CoreV1().Pods("porter-stack-app").Create(ctx, &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      run.Name + "-xyz12",
+			Namespace: "porter-stack-app",
+			Labels:    map[string]string{"job-name": run.Name, porter_app.LabelKey_RunJobID: run.ID},
+		},
+		Status: v1.PodStatus{Phase: v1.PodSucceeded},
+	}, metav1.CreateOptions{})
+	is.NoErr(err)
+
+	got, err = porter_app.GetRunJob(ctx, porter_app.GetRunJobInput{
+		Namespace: "porter-stack-app",
+		RunID:     run.ID,
+		K8sAgent:  k8sAgent,
+	})
+	is.NoErr(err)
+	is.Equal(got.Status, porter_app.JobRunStatus_Successful)
+	is.Equal(got.Name, run.Name)
+	is.True(!got.FinishedAt.IsZero())
+	// the fake clientset returns a fixed log body for every pod
+	is.Equal(got.Logs, []string{"fake logs"})
+}
+
+func TestGetRunJobNotFound(t *testing.T) {
+	is := is.New(t)
+
+	_, err := porter_app.GetRunJob(context.Background(), porter_app.GetRunJobInput{
+		Namespace: "porter-stack-app",
+		RunID:     "cleanedup",
+		K8sAgent:  kubernetes.GetAgentTesting(),
+	})
+	is.True(errors.Is(err, porter_app.ErrRunJobNotFound))
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"k8s.io/utils/pointer"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/porter_app"
+)
+
+func TestValidateSchedulingDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults types.ClusterSchedulingDefaults
+		wantErr  bool
+	}{
+		{
+			name: "valid",
+			defaults: types.ClusterSchedulingDefaults{
+				NodeSelector: map[string]string{"porter.run/pool": "apps"},
+				Tolerations: []types.Toleration{
+					{Key: "dedicated", Operator: "Equal", Value: "apps", Effect: "NoSchedule"},
+					{Key: "spot", Operator: "Exists", Effect: "NoExecute", TolerationSeconds: pointer.Int64(30)},
+				},
+			},
+		},
+		{
+			name: "invalid node selector key",
+			defaults: types.ClusterSchedulingDefaults{
+				NodeSelector: map[string]string{"not a key": "apps"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "exists with value",
+			defaults: types.ClusterSchedulingDefaults{
+				Tolerations: []types.Toleration{{Key: "spot", Operator: "Exists", Value: "true"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown effect",
+			defaults: types.ClusterSchedulingDefaults{
+				Tolerations: []types.Toleration{{Key: "spot", Operator: "Exists", Effect: "NoRun"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "toleration seconds without NoExecute",
+			defaults: types.ClusterSchedulingDefaults{
+				Tolerations: []types.Toleration{{Key: "spot", Operator: "Exists", Effect: "NoSchedule", TolerationSeconds: pointer.Int64(30)}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			err := porter_app.ValidateSchedulingDefaults(tt.defaults)
+			is.Equal(err != nil, tt.wantErr)
+		})
+	}
+}
+
+func TestSchedulingDefaultsHash(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(porter_app.SchedulingDefaultsHash(types.ClusterSchedulingDefaults{}), "")
+
+	a := types.ClusterSchedulingDefaults{NodeSelector: map[string]string{"a": "1", "b": "2"}}
+	b := types.ClusterSchedulingDefaults{NodeSelector: map[string]string{"b": "2", "a": "1"}}
+	c := types.ClusterSchedulingDefaults{NodeSelector: map[string]string{"a": "1"}}
+
+	is.Equal(porter_app.SchedulingDefaultsHash(a), porter_app.SchedulingDefaultsHash(b))
+	is.True(porter_app.SchedulingDefaultsHash(a) != porter_app.SchedulingDefaultsHash(c))
+}
+
+func TestSchedulingDefaultsHelmValues(t *testing.T) {
+	is := is.New(t)
+
+	nodeSelector, tolerations := porter_app.SchedulingDefaultsHelmValues(types.ClusterSchedulingDefaults{
+		NodeSelector: map[string]string{"porter.run/pool": "apps"},
+		Tolerations: []types.Toleration{
+			{Key: "spot", Operator: "Exists", Effect: "NoExecute", TolerationSeconds: pointer.Int64(30)},
+		},
+	})
+
+	is.Equal(nodeSelector, map[string]interface{}{"porter.run/pool": "apps"})
+	is.Equal(tolerations, []interface{}{
+		map[string]interface{}{
+			"key":               "spot",
+			"operator":          "Exists",
+			"effect":            "NoExecute",
+			"tolerationSeconds": int64(30),
+		},
+	})
+}
@@ -0,0 +1,209 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	"github.com/stefanmcshane/helm/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/pkg/logger"
+)
+
+func TestSnapshotAndRestoreStack(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	storageClass := "standard"
+	k8sAgent := kubernetes.GetAgentTesting(
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-app-web", Namespace: "porter-stack-app"},
+			Spec: v1.PersistentVolumeClaimSpec{
+				VolumeName:       "pv-1",
+				StorageClassName: &storageClass,
+				AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			},
+		},
+		&v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+			Spec: v1.PersistentVolumeSpec{
+				Capacity:                      v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+				AccessModes:                   []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+				StorageClassName:              storageClass,
+				PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+				ClaimRef:                      &v1.ObjectReference{Name: "data-app-web", Namespace: "porter-stack-app"},
+			},
+			Status: v1.PersistentVolumeStatus{Phase: v1.VolumeBound},
+		},
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shared.v2",
+				Namespace: "porter-env-group",
+				Labels:    map[string]string{"envgroup": "shared", "version": "2"},
+			},
+			Data: map[string]string{"PLAIN": "value", "TOKEN": "PORTERSECRET_shared.v2"},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shared.v2",
+				Namespace: "porter-env-group",
+				Labels:    map[string]string{"envgroup": "shared", "version": "2"},
+			},
+			Data: map[string][]byte{"TOKEN": []byte("s3cr3t-value")},
+		},
+	)
+
+	stackRelease := &release.Release{
+		Name:      "app",
+		Namespace: "porter-stack-app",
+		Config: map[string]interface{}{
+			"global": map[string]interface{}{
+				"image": map[string]interface{}{"repository": "nginx", "tag": "1.25"},
+			},
+			"web-web": map[string]interface{}{
+				"container": map[string]interface{}{
+					"env": map[string]interface{}{
+						"synced": []interface{}{
+							map[string]interface{}{"name": "shared", "version": float64(2)},
+						},
+					},
+				},
+				"ingress": map[string]interface{}{
+					"enabled":      true,
+					"porter_hosts": []interface{}{"app-abc.withporter.run"},
+					"hosts":        []interface{}{"app.example.com"},
+				},
+			},
+		},
+	}
+
+	snapshot, err := porter_app.SnapshotStack(ctx, porter_app.SnapshotStackInput{
+		App:       &models.PorterApp{Name: "app", Builder: "heroku/buildpacks:20"},
+		Namespace: "porter-stack-app",
+		Release:   stackRelease,
+		K8sAgent:  k8sAgent,
+	})
+	is.NoErr(err)
+
+	is.Equal(snapshot.Version, types.StackSnapshotVersion)
+	is.Equal(snapshot.EnvGroups, []types.StackSnapshotEnvGroup{{Name: "shared", Version: 2}})
+	is.Equal(snapshot.Domains, []types.StackSnapshotDomain{
+		{Service: "web-web", Host: "app-abc.withporter.run", PorterManaged: true},
+		{Service: "web-web", Host: "app.example.com"},
+	})
+	is.Equal(snapshot.Volumes, []types.StackSnapshotVolume{
+		{Name: "data-app-web", VolumeName: "pv-1", StorageClass: "standard", Size: "10Gi", AccessModes: []string{"ReadWriteOnce"}},
+	})
+
+	// the snapshot must survive being written out as a bundle
+	by, err := json.Marshal(snapshot)
+	is.NoErr(err)
+	is.True(!strings.Contains(string(by), "s3cr3t-value"))
+
+	bundle := &types.StackSnapshot{}
+	is.NoErr(json.Unmarshal(by, bundle))
+
+	// simulate losing the original namespace, leaving the retained volume behind
+	err = k8sAgent.Clientset.CoreV1().PersistentVolumeClaims("porter-stack-app").Delete(ctx, "data-app-web", metav1.DeleteOptions{})
+	is.NoErr(err)
+
+	pv, err := k8sAgent.Clientset.CoreV1().PersistentVolumes().Get(ctx, "pv-1", metav1.GetOptions{})
+	is.NoErr(err)
+	pv.Status.Phase = v1.VolumeReleased
+	_, err = k8sAgent.Clientset.CoreV1().PersistentVolumes().UpdateStatus(ctx, pv, metav1.UpdateOptions{})
+	is.NoErr(err)
+
+	values, err := porter_app.StackSnapshotRestoreValues(bundle, "app-restored")
+	is.NoErr(err)
+
+	helmAgent := helm.GetAgentTesting(&helm.Form{Namespace: "porter-stack-app-restored"}, nil, logger.NewConsole(true), k8sAgent)
+
+	output, err := porter_app.RestoreStack(ctx, porter_app.RestoreStackInput{
+		Snapshot:  bundle,
+		Name:      "app-restored",
+		Namespace: "porter-stack-app-restored",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: "v2", Name: "umbrella", Version: "0.1.0", Type: "application"},
+		},
+		Values:    values,
+		Cluster:   &models.Cluster{},
+		HelmAgent: helmAgent,
+		K8sAgent:  k8sAgent,
+	})
+	is.NoErr(err)
+
+	is.Equal(output.ReattachedVolumes, []string{"data-app-web"})
+	is.Equal(output.NewVolumes, []string{})
+	is.Equal(output.MissingEnvGroups, []string{})
+	is.Equal(output.Release.Namespace, "porter-stack-app-restored")
+
+	// the restored stack gets a new subdomain, but keeps its custom domains
+	ingress := output.Release.Config["web-web"].(map[string]interface{})["ingress"].(map[string]interface{})
+	_, hasPorterHosts := ingress["porter_hosts"]
+	is.True(!hasPorterHosts)
+	is.Equal(ingress["hosts"], []interface{}{"app.example.com"})
+
+	// env groups are cloned into the new namespace, and the values point at the cloned version
+	clonedEnvGroup, version, err := k8sAgent.GetLatestVersionedConfigMap(ctx, "shared", "porter-stack-app-restored")
+	is.NoErr(err)
+	is.Equal(clonedEnvGroup.Data["PLAIN"], "value")
+
+	synced := output.Release.Config["web-web"].(map[string]interface{})["container"].(map[string]interface{})["env"].(map[string]interface{})["synced"].([]interface{})
+	is.Equal(synced[0].(map[string]interface{})["version"], version)
+
+	pvc, err := k8sAgent.Clientset.CoreV1().PersistentVolumeClaims("porter-stack-app-restored").Get(ctx, "data-app-web", metav1.GetOptions{})
+	is.NoErr(err)
+	is.Equal(pvc.Spec.VolumeName, "pv-1")
+
+	pv, err = k8sAgent.Clientset.CoreV1().PersistentVolumes().Get(ctx, "pv-1", metav1.GetOptions{})
+	is.NoErr(err)
+	is.True(pv.Spec.ClaimRef == nil)
+}
+
+func TestRestoreStackMissingEnvGroupAndVolume(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	k8sAgent := kubernetes.GetAgentTesting()
+	helmAgent := helm.GetAgentTesting(&helm.Form{Namespace: "porter-stack-app"}, nil, logger.NewConsole(true), k8sAgent)
+
+	snapshot := &types.StackSnapshot{
+		Version:   types.StackSnapshotVersion,
+		App:       &types.PorterApp{Name: "app"},
+		Values:    map[string]interface{}{},
+		EnvGroups: []types.StackSnapshotEnvGroup{{Name: "deleted", Version: 1}},
+		Volumes:   []types.StackSnapshotVolume{{Name: "data", VolumeName: "pv-gone", Size: "1Gi"}},
+	}
+
+	output, err := porter_app.RestoreStack(ctx, porter_app.RestoreStackInput{
+		Snapshot:  snapshot,
+		Name:      "app",
+		Namespace: "porter-stack-app",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: "v2", Name: "umbrella", Version: "0.1.0", Type: "application"},
+		},
+		Values:    map[string]interface{}{},
+		Cluster:   &models.Cluster{},
+		HelmAgent: helmAgent,
+		K8sAgent:  k8sAgent,
+	})
+	is.NoErr(err)
+
+	is.Equal(output.MissingEnvGroups, []string{"deleted"})
+	is.Equal(output.NewVolumes, []string{"data"})
+	is.Equal(output.ReattachedVolumes, []string{})
+}
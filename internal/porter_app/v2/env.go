@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
 )
 
 // Env is a list of env variable definitions
@@ -64,14 +65,28 @@ type EnvVariableFromAppOptional struct {
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface for Env in order to support both a list of env variables and a map of env variables
 func (e *Env) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	var asList []EnvVariableDefinition
-	if err := unmarshal(&asList); err == nil {
+	var raw interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	if _, ok := raw.([]interface{}); ok {
+		var asList []EnvVariableDefinition
+		if err := unmarshal(&asList); err != nil {
+			return err
+		}
 		*e = asList
 		return nil
 	}
 
-	var asMap map[string]string
-	if err := unmarshal(&asMap); err != nil {
+	// values are decoded without a type so that numbers and booleans are rejected instead of converted to strings
+	var rawMap map[string]interface{}
+	if err := unmarshal(&rawMap); err != nil {
+		return err
+	}
+
+	asMap, err := envvalues.MapFromYAML(rawMap)
+	if err != nil {
 		return err
 	}
 
@@ -124,6 +139,16 @@ type rawEnvVarDef struct {
 	From  rawEnvVariableReference `yaml:"from,omitempty"`
 }
 
+// rawEnvVarInput is the yaml representation of an env variable as it is unmarshaled. The value is decoded without a
+// type, so that numbers and booleans are rejected instead of converted to strings
+type rawEnvVarInput struct {
+	Key       string                  `yaml:"key"`
+	Value     interface{}             `yaml:"value,omitempty"`
+	FromFile  string                  `yaml:"fromFile,omitempty"`
+	ValueFrom interface{}             `yaml:"valueFrom,omitempty"`
+	From      rawEnvVariableReference `yaml:"from,omitempty"`
+}
+
 // rawEnvVariableReference is a struct used to unmarshal the yaml representation of an env variable reference
 type rawEnvVariableReference struct {
 	// source for the value. right now only "app" is supported
@@ -138,16 +163,28 @@ type rawEnvVariableReference struct {
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface for EnvVariableDefinition in order to support both a string value and a reference to another app
 func (def *EnvVariableDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	var raw rawEnvVarDef
+	var raw rawEnvVarInput
 	if err := unmarshal(&raw); err != nil {
 		return err
 	}
 
+	if raw.FromFile != "" {
+		return envvalues.FromFileNotResolvedError(raw.Key)
+	}
+	if raw.ValueFrom != nil {
+		return envvalues.ValueFromNotResolvedError(raw.Key)
+	}
+
+	value, err := envvalues.FromYAML(raw.Key, raw.Value)
+	if err != nil {
+		return err
+	}
+
 	def.Key = raw.Key
-	if raw.Value != "" {
+	if value != "" {
 		def.Source = EnvVariableSource_Value
 		def.Value = EnvValueOptional{
-			Value: raw.Value,
+			Value: value,
 			IsSet: true,
 		}
 	}
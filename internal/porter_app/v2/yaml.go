@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/internal/porter_app/envvalues"
 	"github.com/porter-dev/porter/internal/telemetry"
 	"gopkg.in/yaml.v2"
 )
@@ -343,6 +344,11 @@ func ProtoFromApp(ctx context.Context, porterApp PorterApp) (*porterv1.PorterApp
 		}
 	}
 
+	// every service of the app is deployed with the app's env, so the app's env is checked against the limit of a single service
+	if err := envvalues.CheckSize(fmt.Sprintf("app %s", porterApp.Name), envMap); err != nil {
+		return appProto, nil, telemetry.Error(ctx, span, err, "env variables are too large")
+	}
+
 	appProto.Env = envVariables
 
 	return appProto, envMap, nil
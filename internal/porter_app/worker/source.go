@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// AgentSource connects to clusters with the server's credentials, through the tunnel agent of a cluster if it is
+// connected through one. T is what a worker reads from and acts on in a cluster.
+type AgentSource[T any] struct {
+	conf *config.Config
+	open func(cluster *models.Cluster, agent *kubernetes.Agent) (T, error)
+}
+
+// NewAgentSource returns an AgentSource which opens each cluster it connects to with open
+func NewAgentSource[T any](conf *config.Config, open func(cluster *models.Cluster, agent *kubernetes.Agent) (T, error)) *AgentSource[T] {
+	return &AgentSource[T]{conf: conf, open: open}
+}
+
+// Connect reads a cluster and connects to it, or returns an error if the cluster is unreachable
+func (s *AgentSource[T]) Connect(ctx context.Context, projectID, clusterID uint) (T, error) {
+	var empty T
+
+	cluster, err := s.conf.Repo.Cluster().ReadCluster(projectID, clusterID)
+	if err != nil {
+		return empty, fmt.Errorf("error reading cluster: %w", err)
+	}
+
+	agent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, authz.OutOfClusterConfig(s.conf, cluster))
+	if err != nil {
+		return empty, fmt.Errorf("error connecting to cluster: %w", err)
+	}
+
+	return s.open(cluster, agent)
+}
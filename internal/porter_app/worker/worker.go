@@ -0,0 +1,155 @@
+// Package worker runs the background workers of porter apps, which periodically act on the apps of every cluster. It
+// holds what the workers share: the loop which runs them, the timeout which keeps an unreachable cluster from stalling
+// the others, the connection to clusters with the server's credentials, and their logging.
+package worker
+
+import (
+	"context"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+// Run calls fn with the current time once per interval until ctx is cancelled. An error returned by fn is logged with
+// errMsg, and fn is called again at the next interval.
+func Run(ctx context.Context, interval time.Duration, log Logger, errMsg string, fn func(ctx context.Context, now time.Time) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if err := fn(ctx, now.UTC()); err != nil {
+				log.WithLevel(zerolog.ErrorLevel).Err(err).Msg(errMsg)
+			}
+		}
+	}
+}
+
+// ClusterKey identifies the cluster which some of the work of a worker is done on
+type ClusterKey struct {
+	ProjectID uint
+	ClusterID uint
+}
+
+// ClusterOptions configure how EachCluster does the work of each cluster
+type ClusterOptions struct {
+	// Timeout bounds the time spent on a single cluster, so that an unreachable cluster cannot stall the others
+	Timeout time.Duration
+	// Concurrency is the number of clusters worked on at once. Defaults to 1
+	Concurrency int
+	// Action describes the work done on a cluster in logs, such as "checking domain certificates"
+	Action string
+	// Logger receives a record of clusters which timed out or panicked
+	Logger Logger
+}
+
+// EachCluster calls fn with the work of each cluster, in order of project and cluster id, returning once every cluster
+// has been worked on or has timed out. fn is given a context which is done once the cluster timeout has passed, and is
+// given up on at that point even if it does not respect its context. No more clusters are started once ctx is done.
+func EachCluster[W any](ctx context.Context, clusters map[ClusterKey]W, opts ClusterOptions, fn func(ctx context.Context, ck ClusterKey, work W)) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	keys := make([]ClusterKey, 0, len(clusters))
+	for ck := range clusters {
+		keys = append(keys, ck)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ProjectID != keys[j].ProjectID {
+			return keys[i].ProjectID < keys[j].ProjectID
+		}
+		return keys[i].ClusterID < keys[j].ClusterID
+	})
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for _, ck := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		if ctx.Err() != nil {
+			<-sem
+			return
+		}
+
+		wg.Add(1)
+		go func(ck ClusterKey, work W) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			withClusterTimeout(ctx, ck, opts, func(ctx context.Context) {
+				fn(ctx, ck, work)
+			})
+		}(ck, clusters[ck])
+	}
+}
+
+// withClusterTimeout calls fn, giving up once the cluster timeout has passed even if fn does not respect its context
+func withClusterTimeout(ctx context.Context, ck ClusterKey, opts ClusterOptions, fn func(ctx context.Context)) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		// this runs outside of the supervised goroutine, so a panic must be recovered here to not crash the server
+		defer func() {
+			if r := recover(); r != nil {
+				opts.Logger.Cluster(zerolog.ErrorLevel, ck).Str("stack", string(debug.Stack())).Msgf("panic %s: %v", opts.Action, r)
+			}
+		}()
+
+		fn(ctx)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		opts.Logger.Cluster(zerolog.WarnLevel, ck).Msgf("timed out %s of cluster, skipping it until the next run", opts.Action)
+	}
+}
+
+// Logger records the work of a worker. The zero Logger records nothing.
+type Logger struct {
+	logger *logger.Logger
+}
+
+// NewLogger returns a Logger which records to l, or nothing if l is nil
+func NewLogger(l *logger.Logger) Logger {
+	return Logger{logger: l}
+}
+
+// WithLevel starts an event at the given level, or returns nil if there is no logger, which zerolog treats as a no-op
+func (l Logger) WithLevel(level zerolog.Level) *zerolog.Event {
+	if l.logger == nil {
+		return nil
+	}
+
+	return l.logger.WithLevel(level)
+}
+
+// Cluster starts an event about a cluster
+func (l Logger) Cluster(level zerolog.Level, ck ClusterKey) *zerolog.Event {
+	return l.WithLevel(level).Uint("project_id", ck.ProjectID).Uint("cluster_id", ck.ClusterID)
+}
+
+// App starts an event about an app
+func (l Logger) App(level zerolog.Level, app *models.PorterApp) *zerolog.Event {
+	return l.WithLevel(level).Uint("project_id", app.ProjectID).Uint("cluster_id", app.ClusterID).Uint("porter_app_id", app.ID).Str("app_name", app.Name)
+}
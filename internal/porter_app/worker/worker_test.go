@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEachCluster_InOrderOfProjectAndCluster(t *testing.T) {
+	clusters := map[ClusterKey]string{
+		{ProjectID: 2, ClusterID: 1}: "c",
+		{ProjectID: 1, ClusterID: 2}: "b",
+		{ProjectID: 1, ClusterID: 1}: "a",
+	}
+
+	var got []string
+	EachCluster(context.Background(), clusters, ClusterOptions{Timeout: time.Second}, func(ctx context.Context, ck ClusterKey, work string) {
+		got = append(got, work)
+	})
+
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Fatalf("expected clusters in order of project and cluster id, got %v", got)
+	}
+}
+
+func TestEachCluster_BadClustersDoNotBlockOthers(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+
+	clusters := map[ClusterKey]string{
+		{ProjectID: 1, ClusterID: 1}: "panicking",
+		{ProjectID: 1, ClusterID: 2}: "hanging",
+		{ProjectID: 1, ClusterID: 3}: "healthy",
+	}
+
+	var mu sync.Mutex
+	var done []string
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+
+		EachCluster(context.Background(), clusters, ClusterOptions{Timeout: 50 * time.Millisecond}, func(ctx context.Context, ck ClusterKey, work string) {
+			switch work {
+			case "panicking":
+				panic("unexpected")
+			case "hanging":
+				// ignores ctx, like a client without a timeout would
+				<-hang
+			}
+
+			mu.Lock()
+			done = append(done, work)
+			mu.Unlock()
+		})
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("clusters were stalled by a hanging cluster")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(done) != 1 || done[0] != "healthy" {
+		t.Fatalf("expected only the healthy cluster to finish, got %v", done)
+	}
+}
+
+func TestEachCluster_Concurrency(t *testing.T) {
+	clusters := make(map[ClusterKey]int)
+	for i := uint(1); i <= 6; i++ {
+		clusters[ClusterKey{ProjectID: 1, ClusterID: i}] = int(i)
+	}
+
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+
+	EachCluster(context.Background(), clusters, ClusterOptions{Timeout: time.Second, Concurrency: 2}, func(ctx context.Context, ck ClusterKey, work int) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+	})
+
+	if maxActive != 2 {
+		t.Fatalf("expected 2 clusters at once, got %d", maxActive)
+	}
+}
+
+func TestEachCluster_StopsOnceCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	clusters := map[ClusterKey]int{
+		{ProjectID: 1, ClusterID: 1}: 1,
+		{ProjectID: 1, ClusterID: 2}: 2,
+	}
+
+	var got []int
+	EachCluster(ctx, clusters, ClusterOptions{Timeout: time.Second}, func(ctx context.Context, ck ClusterKey, work int) {
+		got = append(got, work)
+		cancel()
+	})
+
+	if len(got) != 1 {
+		t.Fatalf("expected no clusters to start once cancelled, got %v", got)
+	}
+}
@@ -0,0 +1,56 @@
+// Package workertest fakes the clusters which the workers of porter apps connect to in their tests
+package workertest
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnreachable is returned when connecting to a fake cluster which is unreachable
+var ErrUnreachable = errors.New("connection refused")
+
+// Cluster is embedded in the fake clusters of a worker's tests, to make them unreachable or hang when connected to
+type Cluster struct {
+	Unreachable bool
+	// Hang blocks connections until it is closed, ignoring their context like a client without a timeout would
+	Hang chan struct{}
+}
+
+func (c *Cluster) fakeCluster() *Cluster {
+	return c
+}
+
+// FakeCluster is a fake cluster which embeds Cluster
+type FakeCluster interface {
+	fakeCluster() *Cluster
+}
+
+// Connect returns the fake cluster with the given id, or ErrUnreachable if there is none or it is unreachable
+func Connect[C FakeCluster](clusters map[uint]C, clusterID uint) (C, error) {
+	cluster, ok := clusters[clusterID]
+	if !ok || cluster.fakeCluster().Unreachable {
+		var empty C
+		return empty, ErrUnreachable
+	}
+
+	if hang := cluster.fakeCluster().Hang; hang != nil {
+		<-hang
+	}
+
+	return cluster, nil
+}
+
+// Source connects to the fake clusters it holds by cluster id. T is what the worker reads from and acts on in a
+// cluster, which the fake clusters must implement.
+type Source[T any, C FakeCluster] map[uint]C
+
+// Connect returns the fake cluster with the given id, or ErrUnreachable if there is none or it is unreachable
+func (s Source[T, C]) Connect(ctx context.Context, projectID, clusterID uint) (T, error) {
+	cluster, err := Connect(s, clusterID)
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+
+	return any(cluster).(T), nil
+}
@@ -0,0 +1,293 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/docker/cli/cli/config/configfile"
+	ptypes "github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"golang.org/x/oauth2"
+)
+
+// ConnectionError is returned when a registry cannot be reached with its credentials. Kind is whether the credentials
+// were rejected, the registry could not be reached, or the credentials do not grant access to the registry.
+type ConnectionError struct {
+	Kind ptypes.RegistryConnectionErrorKind
+	// StatusCode is the status the registry responded with, if it responded
+	StatusCode int
+	Err        error
+}
+
+func (e *ConnectionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// TestConnection generates the credentials a pull secret would be created from, and calls the registry's API with them
+// the way a pull would: the registry is pinged, and the tags of the repository the registry is connected to are listed
+// if its URL names one. A *ConnectionError describes why the registry could not be reached.
+func (r *Registry) TestConnection(ctx context.Context, repo repository.Repository, doAuth *oauth2.Config, client *http.Client) error {
+	ctx, span := telemetry.NewSpan(ctx, "test-registry-connection")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "registry-name", Value: r.Name},
+		telemetry.AttributeKV{Key: "registry-id", Value: r.ID},
+		telemetry.AttributeKV{Key: "project-id", Value: r.ProjectID},
+	)
+
+	conf, err := r.dockerConfigFile(repo, doAuth)
+	if err != nil {
+		return &ConnectionError{Kind: credentialErrorKind(err), Err: fmt.Errorf("error getting credentials for registry %s: %w", r.Name, err)}
+	}
+
+	var username, password string
+	for _, auth := range conf.AuthConfigs {
+		username, password = auth.Username, auth.Password
+	}
+	if username == "" && password == "" {
+		return &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Auth, Err: fmt.Errorf("registry %s has no credentials", r.Name)}
+	}
+
+	endpoint, repoPath, err := registryEndpoint(r.URL)
+	if err != nil {
+		return &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Network, Err: err}
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "registry-endpoint", Value: endpoint},
+		telemetry.AttributeKV{Key: "repository", Value: repoPath},
+	)
+
+	return checkRegistryAPI(ctx, client, endpoint, repoPath, username, password)
+}
+
+// dockerConfigFile returns the docker config a pull secret is generated from, with the credentials of the integration
+// the registry is connected with
+func (r *Registry) dockerConfigFile(repo repository.Repository, doAuth *oauth2.Config) (*configfile.ConfigFile, error) {
+	switch {
+	case r.AWSIntegrationID != 0:
+		return r.getECRDockerConfigFile(repo)
+	case r.GCPIntegrationID != 0:
+		return r.getGCRDockerConfigFile(repo)
+	case r.DOIntegrationID != 0:
+		return r.getDOCRDockerConfigFile(repo, doAuth)
+	case r.BasicIntegrationID != 0:
+		return r.getPrivateRegistryDockerConfigFile(repo)
+	case r.AzureIntegrationID != 0:
+		return r.getACRDockerConfigFile(repo)
+	default:
+		return nil, errors.New("registry is not connected with an integration")
+	}
+}
+
+// registryEndpoint returns the base URL of the registry API for a registry URL, and the repository the URL names, if
+// any. Docker Hub registries are served by registry-1.docker.io.
+func registryEndpoint(registryURL string) (string, string, error) {
+	if !strings.Contains(registryURL, "://") {
+		registryURL = "https://" + registryURL
+	}
+
+	parsed, err := url.Parse(registryURL)
+	if err != nil || parsed.Host == "" {
+		return "", "", fmt.Errorf("invalid registry url %s", registryURL)
+	}
+
+	host := parsed.Host
+	if host == "index.docker.io" || host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+
+	return fmt.Sprintf("%s://%s", parsed.Scheme, host), strings.Trim(parsed.Path, "/"), nil
+}
+
+// checkRegistryAPI pings the registry API at endpoint with basic credentials, exchanging them for a bearer token if the
+// registry asks for one, and lists the tags of repoPath if it is set
+func checkRegistryAPI(ctx context.Context, client *http.Client, endpoint, repoPath, username, password string) error {
+	authorization := "Basic " + generateAuthToken(username, password)
+
+	resp, err := registryGet(ctx, client, endpoint+"/v2/", authorization)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		realm, params, ok := bearerChallenge(resp.Header.Get("WWW-Authenticate"))
+		if ok {
+			if repoPath != "" {
+				params.Set("scope", fmt.Sprintf("repository:%s:pull", repoPath))
+			}
+
+			token, err := fetchBearerToken(ctx, client, realm, params, authorization)
+			if err != nil {
+				return err
+			}
+			authorization = "Bearer " + token
+
+			resp, err = registryGet(ctx, client, endpoint+"/v2/", authorization)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := statusError(resp, "registry rejected the credentials"); err != nil {
+		return err
+	}
+
+	if repoPath == "" {
+		return nil
+	}
+
+	resp, err = registryGet(ctx, client, fmt.Sprintf("%s/v2/%s/tags/list?n=1", endpoint, repoPath), authorization)
+	if err != nil {
+		return err
+	}
+
+	// the path of the registry may be a namespace of repositories rather than a repository, or a repository which has
+	// no images yet, so only a refused request is an error
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return &ConnectionError{
+			Kind:       ptypes.RegistryConnectionErrorKind_Permission,
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("credentials are not allowed to pull from %s", repoPath),
+		}
+	}
+
+	return statusError(resp, "registry rejected the credentials")
+}
+
+// registryGet sends a GET request to the registry and closes the body of the response, which is only used for its
+// status and headers
+func registryGet(ctx context.Context, client *http.Client, reqURL, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Network, Err: err}
+	}
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Network, Err: fmt.Errorf("error reaching registry: %w", err)}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return resp, nil
+}
+
+// fetchBearerToken exchanges basic credentials for a bearer token at the realm of the registry's challenge
+func fetchBearerToken(ctx context.Context, client *http.Client, realm string, params url.Values, authorization string) (string, error) {
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Network, Err: fmt.Errorf("invalid token realm %s", realm)}
+	}
+	tokenURL.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Network, Err: err}
+	}
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Network, Err: fmt.Errorf("error reaching registry token service: %w", err)}
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	if err := statusError(resp, "registry token service rejected the credentials"); err != nil {
+		return "", err
+	}
+
+	tokenResp := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Unknown, Err: fmt.Errorf("error reading registry token: %w", err)}
+	}
+
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	if tokenResp.AccessToken != "" {
+		return tokenResp.AccessToken, nil
+	}
+
+	return "", &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Auth, Err: errors.New("registry token service returned no token")}
+}
+
+// bearerChallenge parses a Bearer WWW-Authenticate challenge into its realm and the other parameters, which are sent
+// to the realm when requesting a token
+func bearerChallenge(header string) (string, url.Values, bool) {
+	scheme, rest, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", nil, false
+	}
+
+	var realm string
+	params := url.Values{}
+	for _, param := range strings.Split(rest, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+
+		if strings.EqualFold(key, "realm") {
+			realm = value
+			continue
+		}
+		params.Set(key, value)
+	}
+
+	return realm, params, realm != ""
+}
+
+// statusError returns a *ConnectionError for a response which is not successful, or nil if it is
+func statusError(resp *http.Response, rejected string) error {
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		return &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Auth, StatusCode: resp.StatusCode, Err: errors.New(rejected)}
+	case resp.StatusCode == http.StatusForbidden:
+		return &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Permission, StatusCode: resp.StatusCode, Err: errors.New("credentials are not allowed to access the registry")}
+	default:
+		return &ConnectionError{Kind: ptypes.RegistryConnectionErrorKind_Unknown, StatusCode: resp.StatusCode, Err: fmt.Errorf("registry responded with status %d", resp.StatusCode)}
+	}
+}
+
+// credentialErrorKind classifies an error generating the credentials of a registry. Credentials are generated by the
+// cloud provider for ECR, so a denied request for an ECR token means the role lacks permission rather than that its
+// credentials are wrong.
+func credentialErrorKind(err error) ptypes.RegistryConnectionErrorKind {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ptypes.RegistryConnectionErrorKind_Network
+	}
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == "AccessDeniedException" {
+		return ptypes.RegistryConnectionErrorKind_Permission
+	}
+
+	return ptypes.RegistryConnectionErrorKind_Auth
+}
@@ -0,0 +1,176 @@
+package registry_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/registry"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+// testConnection connects a registry with basic credentials to the registry API served by handler, at path, and tests
+// its connection
+func testConnection(t *testing.T, path string, handler http.HandlerFunc) error {
+	t.Helper()
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	repo := test.NewRepository(true)
+
+	basic, err := repo.BasicIntegration().CreateBasicIntegration(&ints.BasicIntegration{ProjectID: 1, Username: []byte("porter"), Password: []byte("hunter2")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reg, err := repo.Registry().CreateRegistry(&models.Registry{
+		Name:               "private",
+		URL:                server.URL + path,
+		ProjectID:          1,
+		BasicIntegrationID: basic.ID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_reg := registry.Registry(*reg)
+	return _reg.TestConnection(context.Background(), repo, nil, server.Client())
+}
+
+func TestTestConnection(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		handler  http.HandlerFunc
+		wantKind types.RegistryConnectionErrorKind
+		wantCode int
+	}{
+		{
+			name: "basic credentials are accepted",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if user, pass, ok := r.BasicAuth(); !ok || user != "porter" || pass != "hunter2" {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+			},
+		},
+		{
+			name: "basic credentials are exchanged for a bearer token",
+			path: "/storefront/checkout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/token":
+					if r.URL.Query().Get("scope") != "repository:storefront/checkout:pull" {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					_, _ = w.Write([]byte(`{"token": "pull-token"}`))
+				default:
+					if r.Header.Get("Authorization") != "Bearer pull-token" {
+						w.Header().Set("WWW-Authenticate", `Bearer realm="https://`+r.Host+`/token",service="registry"`)
+						w.WriteHeader(http.StatusUnauthorized)
+					}
+				}
+			},
+		},
+		{
+			name: "repository without images is not an error",
+			path: "/storefront",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v2/" {
+					w.WriteHeader(http.StatusNotFound)
+				}
+			},
+		},
+		{
+			name: "rejected credentials are an auth error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			wantKind: types.RegistryConnectionErrorKind_Auth,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "forbidden registry is a permission error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			wantKind: types.RegistryConnectionErrorKind_Permission,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name: "forbidden repository is a permission error",
+			path: "/storefront/checkout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v2/" {
+					w.WriteHeader(http.StatusForbidden)
+				}
+			},
+			wantKind: types.RegistryConnectionErrorKind_Permission,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name: "unexpected status is an unknown error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			wantKind: types.RegistryConnectionErrorKind_Unknown,
+			wantCode: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testConnection(t, tt.path, tt.handler)
+
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("expected the connection to succeed, got %v", err)
+				}
+				return
+			}
+
+			var connErr *registry.ConnectionError
+			if !errors.As(err, &connErr) {
+				t.Fatalf("expected a connection error, got %v", err)
+			}
+			if connErr.Kind != tt.wantKind {
+				t.Errorf("expected a %s error, got %s: %v", tt.wantKind, connErr.Kind, connErr)
+			}
+			if connErr.StatusCode != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, connErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestTestConnectionUnreachable(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	repo := test.NewRepository(true)
+
+	basic, err := repo.BasicIntegration().CreateBasicIntegration(&ints.BasicIntegration{ProjectID: 1, Username: []byte("porter"), Password: []byte("hunter2")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_reg := registry.Registry(models.Registry{
+		Name:               "private",
+		URL:                url,
+		ProjectID:          1,
+		BasicIntegrationID: basic.ID,
+	})
+	err = _reg.TestConnection(context.Background(), repo, nil, http.DefaultClient)
+
+	var connErr *registry.ConnectionError
+	if !errors.As(err, &connErr) || connErr.Kind != types.RegistryConnectionErrorKind_Network {
+		t.Fatalf("expected a network error, got %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	ptypes "github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+	"golang.org/x/oauth2"
+)
+
+// RecordCredentialStatus records whether the credentials of a registry worked the last time they were used, which
+// deploys use to skip registries whose credentials are known to be broken
+func RecordCredentialStatus(repo repository.RegistryRepository, reg *models.Registry, credErr error) error {
+	checkedAt := time.Now().UTC()
+
+	reg.CredentialCheckedAt = &checkedAt
+	reg.CredentialStatus = string(ptypes.RegistryCredentialStatus_Valid)
+	reg.CredentialError = ""
+
+	if credErr != nil {
+		reg.CredentialStatus = string(ptypes.RegistryCredentialStatus_Invalid)
+		reg.CredentialError = credErr.Error()
+	}
+
+	return repo.UpdateRegistryCredentialStatus(reg)
+}
+
+// CheckCredentials generates the docker config a pull secret would be created from with the credentials of a
+// registry, and records the outcome as the registry's credential status
+func CheckCredentials(repo repository.Repository, reg *models.Registry, doAuth *oauth2.Config) error {
+	_reg := Registry(*reg)
+	_, credErr := _reg.GetDockerConfigJSON(repo, doAuth)
+
+	if err := RecordCredentialStatus(repo.Registry(), reg, credErr); err != nil {
+		return fmt.Errorf("error recording credential status of registry %s: %w", reg.Name, err)
+	}
+
+	return credErr
+}
+
+// BrokenRegistryError is returned when the image being deployed is pulled from a registry whose credentials are broken
+type BrokenRegistryError struct {
+	Registry string
+	Image    string
+	Err      error
+}
+
+func (e *BrokenRegistryError) Error() string {
+	return fmt.Sprintf("image %s is pulled from registry %s, whose credentials are broken: %s. Update the credentials of the registry and deploy again", e.Image, e.Registry, e.Err)
+}
+
+func (e *BrokenRegistryError) Unwrap() error {
+	return e.Err
+}
+
+// DeployRegistries returns the registries which pull secrets are generated for when deploying an image. Registries
+// whose credentials are known to be broken are left out with a warning, so that they cannot break deploys which do not
+// pull from them. The registry of the image itself is checked again if it is known to be broken, as its credentials
+// may have been fixed since; a BrokenRegistryError is returned if they still fail.
+func DeployRegistries(
+	repo repository.Repository,
+	regs []*models.Registry,
+	image string,
+	doAuth *oauth2.Config,
+) ([]*models.Registry, []string, error) {
+	return DeployRegistriesForImages(repo, regs, []string{image}, doAuth)
+}
+
+// DeployRegistriesForImages is DeployRegistries for deploys which pull several images, such as stacks whose services
+// are built separately. The registry of each image is checked again if it is known to be broken.
+func DeployRegistriesForImages(
+	repo repository.Repository,
+	regs []*models.Registry,
+	images []string,
+	doAuth *oauth2.Config,
+) ([]*models.Registry, []string, error) {
+	deployable := make([]*models.Registry, 0, len(regs))
+	warnings := make([]string, 0)
+
+	for _, reg := range regs {
+		if reg.CredentialStatus != string(ptypes.RegistryCredentialStatus_Invalid) {
+			deployable = append(deployable, reg)
+			continue
+		}
+
+		image := pulledImage(reg, images)
+		if image != "" {
+			if err := CheckCredentials(repo, reg, doAuth); err != nil {
+				return nil, warnings, &BrokenRegistryError{Registry: reg.Name, Image: image, Err: err}
+			}
+
+			deployable = append(deployable, reg)
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf("no pull secret was generated for registry %s, as its credentials are broken: %s", reg.Name, reg.CredentialError))
+	}
+
+	return deployable, warnings, nil
+}
+
+// pulledImage returns the first of the images which is pulled from a registry, or an empty string if none are
+func pulledImage(reg *models.Registry, images []string) string {
+	for _, image := range images {
+		if image != "" && PullsFrom(reg, image) {
+			return image
+		}
+	}
+
+	return ""
+}
+
+// PullsFrom reports whether an image, with or without a tag, is pulled from a registry
+func PullsFrom(reg *models.Registry, image string) bool {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return false
+	}
+
+	regURL := reg.URL
+	if _, after, ok := strings.Cut(regURL, "://"); ok {
+		regURL = after
+	}
+	regURL = strings.Trim(regURL, "/")
+
+	// images without a domain are normalized to docker.io, while docker hub registries are connected as index.docker.io
+	if rest, ok := strings.CutPrefix(regURL, "index.docker.io"); ok {
+		regURL = "docker.io" + rest
+	}
+
+	repoName := named.Name()
+
+	return regURL != "" && (repoName == regURL || strings.HasPrefix(repoName, regURL+"/"))
+}
+
+// CredentialCheckerOptions configure a CredentialChecker. Zero values use the defaults.
+type CredentialCheckerOptions struct {
+	// Interval is the time between checks of the registries whose credentials are broken. Defaults to 15m
+	Interval time.Duration
+	// Logger receives a record of the registries whose credentials were fixed. Optional
+	Logger *logger.Logger
+}
+
+// CredentialChecker periodically checks the credentials of the registries which are known to be broken, so that a
+// registry fixed outside of Porter, such as by restoring the IAM role it assumes, is used by deploys again
+type CredentialChecker struct {
+	repo   repository.Repository
+	doAuth *oauth2.Config
+	opts   CredentialCheckerOptions
+}
+
+// NewCredentialChecker returns a CredentialChecker for the registries of repo
+func NewCredentialChecker(repo repository.Repository, doAuth *oauth2.Config, opts CredentialCheckerOptions) *CredentialChecker {
+	if opts.Interval <= 0 {
+		opts.Interval = 15 * time.Minute
+	}
+
+	return &CredentialChecker{
+		repo:   repo,
+		doAuth: doAuth,
+		opts:   opts,
+	}
+}
+
+// Run checks the broken registries on every interval until ctx is cancelled
+func (c *CredentialChecker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := c.check(ctx); err != nil {
+				c.log(zerolog.ErrorLevel).Err(err).Msg("error checking registry credentials")
+			}
+		}
+	}
+}
+
+func (c *CredentialChecker) check(ctx context.Context) error {
+	regs, err := c.repo.Registry().ListRegistriesByCredentialStatus(string(ptypes.RegistryCredentialStatus_Invalid))
+	if err != nil {
+		return fmt.Errorf("error listing registries with broken credentials: %w", err)
+	}
+
+	for _, reg := range regs {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err := CheckCredentials(c.repo, reg, c.doAuth); err == nil {
+			c.log(zerolog.InfoLevel).Uint("project_id", reg.ProjectID).Uint("registry_id", reg.ID).Msg("registry credentials were fixed")
+		}
+	}
+
+	return nil
+}
+
+func (c *CredentialChecker) log(level zerolog.Level) *zerolog.Event {
+	if c.opts.Logger == nil {
+		return nil
+	}
+
+	return c.opts.Logger.WithLevel(level)
+}
@@ -0,0 +1,157 @@
+package registry_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/registry"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+// createRegistries connects a registry whose credentials work and one whose credentials were broken by a deploy, by
+// pointing the latter at a basic integration which does not exist
+func createRegistries(t *testing.T, repo repository.Repository) (*models.Registry, *models.Registry) {
+	t.Helper()
+
+	basic, err := repo.BasicIntegration().CreateBasicIntegration(&ints.BasicIntegration{ProjectID: 1, Username: []byte("porter"), Password: []byte("hunter2")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	good, err := repo.Registry().CreateRegistry(&models.Registry{
+		Name:               "ecr",
+		URL:                "123456789.dkr.ecr.us-east-1.amazonaws.com",
+		ProjectID:          1,
+		BasicIntegrationID: basic.ID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bad, err := repo.Registry().CreateRegistry(&models.Registry{
+		Name:               "gar",
+		URL:                "https://us-central1-docker.pkg.dev/storefront/images",
+		ProjectID:          1,
+		BasicIntegrationID: basic.ID + 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.CheckCredentials(repo, bad, nil); err == nil {
+		t.Fatal("expected the credentials of the broken registry to fail")
+	}
+
+	return good, bad
+}
+
+func TestDeployRegistries(t *testing.T) {
+	t.Run("broken registry which the image is not pulled from is skipped", func(t *testing.T) {
+		repo := test.NewRepository(true)
+		good, bad := createRegistries(t, repo)
+
+		deployable, warnings, err := registry.DeployRegistries(repo, []*models.Registry{good, bad}, "123456789.dkr.ecr.us-east-1.amazonaws.com/checkout", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(deployable) != 1 || deployable[0].ID != good.ID {
+			t.Errorf("expected only the registry with working credentials, got %v", deployable)
+		}
+		if len(warnings) != 1 {
+			t.Errorf("expected a warning for the skipped registry, got %v", warnings)
+		}
+	})
+
+	t.Run("broken registry which the image is pulled from fails the deploy", func(t *testing.T) {
+		repo := test.NewRepository(true)
+		good, bad := createRegistries(t, repo)
+
+		_, _, err := registry.DeployRegistries(repo, []*models.Registry{good, bad}, "us-central1-docker.pkg.dev/storefront/images/checkout:v2", nil)
+
+		var brokenErr *registry.BrokenRegistryError
+		if !errors.As(err, &brokenErr) {
+			t.Fatalf("expected a broken registry error, got %v", err)
+		}
+		if brokenErr.Registry != "gar" {
+			t.Errorf("expected the error to name registry gar, got %s", brokenErr.Registry)
+		}
+	})
+
+	t.Run("broken registry which was fixed is used again", func(t *testing.T) {
+		repo := test.NewRepository(true)
+		good, bad := createRegistries(t, repo)
+
+		fixed, err := repo.BasicIntegration().CreateBasicIntegration(&ints.BasicIntegration{ProjectID: 1, Username: []byte("porter"), Password: []byte("rotated")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		bad.BasicIntegrationID = fixed.ID
+
+		deployable, warnings, err := registry.DeployRegistries(repo, []*models.Registry{good, bad}, "us-central1-docker.pkg.dev/storefront/images/checkout:v2", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(deployable) != 2 || len(warnings) != 0 {
+			t.Errorf("expected both registries without warnings, got %v and %v", deployable, warnings)
+		}
+		if bad.CredentialStatus != string(types.RegistryCredentialStatus_Valid) {
+			t.Errorf("expected the fixed registry to be recorded as valid, got %s", bad.CredentialStatus)
+		}
+	})
+
+	t.Run("broken registry which one of the images is pulled from fails the deploy", func(t *testing.T) {
+		repo := test.NewRepository(true)
+		good, bad := createRegistries(t, repo)
+
+		images := []string{"123456789.dkr.ecr.us-east-1.amazonaws.com/checkout:v2", "us-central1-docker.pkg.dev/storefront/images/checkout-worker:v2"}
+		_, _, err := registry.DeployRegistriesForImages(repo, []*models.Registry{good, bad}, images, nil)
+
+		var brokenErr *registry.BrokenRegistryError
+		if !errors.As(err, &brokenErr) {
+			t.Fatalf("expected a broken registry error, got %v", err)
+		}
+		if brokenErr.Image != images[1] {
+			t.Errorf("expected the error to name image %s, got %s", images[1], brokenErr.Image)
+		}
+	})
+
+	t.Run("registries which were never checked are used", func(t *testing.T) {
+		repo := test.NewRepository(true)
+		unchecked := &models.Registry{Name: "hub", URL: "index.docker.io/porter", ProjectID: 1}
+
+		deployable, warnings, err := registry.DeployRegistries(repo, []*models.Registry{unchecked}, "porter/checkout", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(deployable) != 1 || len(warnings) != 0 {
+			t.Errorf("expected the registry without warnings, got %v and %v", deployable, warnings)
+		}
+	})
+}
+
+func TestPullsFrom(t *testing.T) {
+	tests := []struct {
+		name   string
+		regURL string
+		image  string
+		want   bool
+	}{
+		{name: "ecr", regURL: "123456789.dkr.ecr.us-east-1.amazonaws.com", image: "123456789.dkr.ecr.us-east-1.amazonaws.com/checkout:v2", want: true},
+		{name: "registry with a scheme and path", regURL: "https://us-central1-docker.pkg.dev/storefront/images/", image: "us-central1-docker.pkg.dev/storefront/images/checkout", want: true},
+		{name: "registry with a port", regURL: "registry.internal:5000", image: "registry.internal:5000/checkout@sha256:" + "a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4", want: true},
+		{name: "docker hub", regURL: "index.docker.io/porter", image: "porter/checkout:latest", want: true},
+		{name: "other repository of the same host", regURL: "us-central1-docker.pkg.dev/storefront/images", image: "us-central1-docker.pkg.dev/storefront/imagesv2/checkout", want: false},
+		{name: "other host", regURL: "123456789.dkr.ecr.us-east-1.amazonaws.com", image: "ghcr.io/porter-dev/checkout", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registry.PullsFrom(&models.Registry{URL: tt.regURL}, tt.image); got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
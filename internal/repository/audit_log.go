@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// AuditLogRepository represents the set of queries on the AuditLogEntry model
+type AuditLogRepository interface {
+	// CreateAuditLogEntry adds an entry to the audit log of a project
+	CreateAuditLogEntry(ctx context.Context, entry *models.AuditLogEntry) (*models.AuditLogEntry, error)
+	// ListAuditLogEntriesByProjectID returns up to limit entries of the audit log of a project, newest first
+	ListAuditLogEntriesByProjectID(ctx context.Context, projectID uint, limit int) ([]*models.AuditLogEntry, error)
+}
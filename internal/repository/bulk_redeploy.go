@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// BulkRedeployRepository represents the set of queries on the BulkRedeploy and BulkRedeployOperation models
+type BulkRedeployRepository interface {
+	// CreateBulkRedeploy creates a bulk redeploy along with its operations
+	CreateBulkRedeploy(ctx context.Context, bulkRedeploy *models.BulkRedeploy) (*models.BulkRedeploy, error)
+	// ReadBulkRedeploy returns a bulk redeploy of a project with its operations
+	ReadBulkRedeploy(ctx context.Context, projectID uint, id uuid.UUID) (*models.BulkRedeploy, error)
+	// ListIncompleteBulkRedeploys returns every bulk redeploy with operations left to finish, with its operations
+	ListIncompleteBulkRedeploys(ctx context.Context) ([]*models.BulkRedeploy, error)
+	// ClaimBulkRedeployOperation moves a pending operation to running if fewer than concurrency operations of its bulk
+	// redeploy are running on its cluster, returning false if the operation was not claimed. Claims lock the bulk
+	// redeploy, so the concurrency holds across server replicas.
+	ClaimBulkRedeployOperation(ctx context.Context, operation *models.BulkRedeployOperation, concurrency int, startedAt time.Time) (bool, error)
+	// UpdateBulkRedeployOperation updates an operation
+	UpdateBulkRedeployOperation(ctx context.Context, operation *models.BulkRedeployOperation) (*models.BulkRedeployOperation, error)
+	// CompleteBulkRedeploy records that every operation of a bulk redeploy has finished
+	CompleteBulkRedeploy(ctx context.Context, id uuid.UUID, completedAt time.Time) error
+}
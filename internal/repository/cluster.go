@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/porter-dev/porter/internal/features"
 	"github.com/porter-dev/porter/internal/models"
 	ints "github.com/porter-dev/porter/internal/models/integrations"
@@ -20,5 +22,9 @@ type ClusterRepository interface {
 	ListClustersByProjectID(projectID uint) ([]*models.Cluster, error)
 	UpdateCluster(cluster *models.Cluster, launchDarklyClient *features.Client) (*models.Cluster, error)
 	UpdateClusterTokenCache(tokenCache *ints.ClusterTokenCache) (*models.Cluster, error)
+	UpdateClusterCapabilities(clusterID uint, capabilities models.ClusterCapabilities) error
 	DeleteCluster(cluster *models.Cluster) error
+	// CountClustersByProvider returns the number of clusters of the instance hosted by each cloud provider, keyed by
+	// models.Cluster.Provider
+	CountClustersByProvider(ctx context.Context) (map[string]int64, error)
 }
@@ -0,0 +1,71 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "audit log/create and list",
+			Covers: []string{
+				"AuditLogRepository.CreateAuditLogEntry",
+				"AuditLogRepository.ListAuditLogEntriesByProjectID",
+			},
+			Run: testAuditLogCreateAndList,
+		},
+	)
+}
+
+func testAuditLogCreateAndList(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	if _, err := repo.AuditLog().CreateAuditLogEntry(ctx, &models.AuditLogEntry{Action: "debug_recording.started"}); err == nil {
+		t.Error("expected an error creating an entry without a project")
+	}
+
+	ids := make([]uint, 0)
+	for _, action := range []string{"debug_recording.started", "debug_recording.stopped", "debug_recording.started"} {
+		entry, err := repo.AuditLog().CreateAuditLogEntry(ctx, &models.AuditLogEntry{
+			ProjectID:   1,
+			Action:      action,
+			ActorUserID: 1,
+			Metadata:    models.JSONB{"debug_recording_id": float64(1)},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error creating entry: %v", err)
+		}
+		ids = append(ids, entry.ID)
+	}
+
+	if _, err := repo.AuditLog().CreateAuditLogEntry(ctx, &models.AuditLogEntry{ProjectID: 2, Action: "debug_recording.started"}); err != nil {
+		t.Fatalf("unexpected error creating entry of another project: %v", err)
+	}
+
+	entries, err := repo.AuditLog().ListAuditLogEntriesByProjectID(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error listing entries: %v", err)
+	}
+
+	got := make([]uint, 0, len(entries))
+	for _, entry := range entries {
+		got = append(got, entry.ID)
+	}
+	expectIDs(t, "entries of project 1, newest first", got, ids[2], ids[1], ids[0])
+
+	if entries[0].Metadata["debug_recording_id"] != float64(1) {
+		t.Errorf("expected the metadata of the entry to be kept, got %v", entries[0].Metadata)
+	}
+
+	entries, err = repo.AuditLog().ListAuditLogEntriesByProjectID(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error listing entries with a limit: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != ids[2] {
+		t.Errorf("expected the 2 newest entries, got %d entries", len(entries))
+	}
+}
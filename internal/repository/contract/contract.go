@@ -0,0 +1,90 @@
+// Package contract is a suite of tests which every implementation of repository.Repository must pass, so that the
+// in-memory test repository used by handler tests behaves like the gorm repository used in production.
+//
+// Each case declares the repository methods it covers. The coverage check of this package fails when a method of a
+// sub-repository is neither covered by a case nor listed in testdata/uncovered.txt, so new repository methods must
+// ship with contract cases.
+package contract
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// Factory returns an empty repository for a single contract case
+type Factory func(t *testing.T) repository.Repository
+
+// Case is a contract case, run against a fresh repository
+type Case struct {
+	// Name is the name of the subtest the case is run as
+	Name string
+	// Covers are the methods the case exercises, as <sub-repository interface>.<method>, such as
+	// PorterAppRepository.CreatePorterApp
+	Covers []string
+	// Run exercises the methods against repo
+	Run func(t *testing.T, repo repository.Repository)
+}
+
+var cases []Case
+
+func register(c ...Case) {
+	cases = append(cases, c...)
+}
+
+// Run runs every contract case against a repository returned by factory
+func Run(t *testing.T, factory Factory) {
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.Name, func(t *testing.T) {
+			c.Run(t, factory(t))
+		})
+	}
+}
+
+// Covered returns the methods covered by the contract cases
+func Covered() map[string]bool {
+	covered := make(map[string]bool)
+
+	for _, c := range cases {
+		for _, method := range c.Covers {
+			covered[method] = true
+		}
+	}
+
+	return covered
+}
+
+// Methods returns every method of every sub-repository of repository.Repository, as <sub-repository
+// interface>.<method>
+func Methods() []string {
+	methods := make([]string, 0)
+	seen := make(map[reflect.Type]bool)
+
+	repoType := reflect.TypeOf((*repository.Repository)(nil)).Elem()
+
+	for i := 0; i < repoType.NumMethod(); i++ {
+		accessor := repoType.Method(i).Type
+		if accessor.NumOut() != 1 || accessor.Out(0).Kind() != reflect.Interface {
+			continue
+		}
+
+		subRepo := accessor.Out(0)
+		if seen[subRepo] {
+			continue
+		}
+		seen[subRepo] = true
+
+		for j := 0; j < subRepo.NumMethod(); j++ {
+			methods = append(methods, fmt.Sprintf("%s.%s", subRepo.Name(), subRepo.Method(j).Name))
+		}
+	}
+
+	sort.Strings(methods)
+
+	return methods
+}
@@ -0,0 +1,60 @@
+package contract_test
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/repository/contract"
+)
+
+// TestCoverage fails when a repository method is neither covered by a contract case nor listed in
+// testdata/uncovered.txt, which lists the methods which predate the contract suite. Methods are removed from the list
+// as cases are written for them, and new methods must not be added to it.
+func TestCoverage(t *testing.T) {
+	f, err := os.Open("testdata/uncovered.txt")
+	if err != nil {
+		t.Fatalf("unexpected error opening uncovered methods: %v", err)
+	}
+	defer f.Close()
+
+	uncovered := make(map[string]bool)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			uncovered[line] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("unexpected error reading uncovered methods: %v", err)
+	}
+
+	covered := contract.Covered()
+	methods := make(map[string]bool)
+
+	for _, method := range contract.Methods() {
+		methods[method] = true
+
+		switch {
+		case covered[method] && uncovered[method]:
+			t.Errorf("%s is covered by a contract case, remove it from testdata/uncovered.txt", method)
+		case !covered[method] && !uncovered[method]:
+			t.Errorf("%s is not covered by a contract case, add one to the contract package", method)
+		}
+	}
+
+	for method := range covered {
+		if !methods[method] {
+			t.Errorf("contract cases cover %s, which is not a repository method", method)
+		}
+	}
+
+	for method := range uncovered {
+		if !methods[method] {
+			t.Errorf("%s is not a repository method, remove it from testdata/uncovered.txt", method)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "debug recording/create, read and list",
+			Covers: []string{
+				"DebugRecordingRepository.CreateDebugRecording",
+				"DebugRecordingRepository.ReadDebugRecording",
+				"DebugRecordingRepository.ReadActiveDebugRecording",
+				"DebugRecordingRepository.ListDebugRecordingsByProjectID",
+				"DebugRecordingRepository.UpdateDebugRecording",
+			},
+			Run: testDebugRecordingCreateReadAndList,
+		},
+		Case{
+			Name: "debug recording/reserve exchanges",
+			Covers: []string{
+				"DebugRecordingRepository.ReserveDebugRecordingExchange",
+			},
+			Run: testDebugRecordingReserveExchange,
+		},
+	)
+}
+
+func createDebugRecording(t *testing.T, repo repository.Repository, recording *models.DebugRecording) *models.DebugRecording {
+	t.Helper()
+
+	recording, err := repo.DebugRecording().CreateDebugRecording(context.Background(), recording)
+	if err != nil {
+		t.Fatalf("unexpected error creating debug recording: %v", err)
+	}
+
+	return recording
+}
+
+func debugRecordingIDs(recordings []*models.DebugRecording) []uint {
+	ids := make([]uint, 0, len(recordings))
+	for _, recording := range recordings {
+		ids = append(ids, recording.ID)
+	}
+
+	return ids
+}
+
+func testDebugRecordingCreateReadAndList(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	if _, err := repo.DebugRecording().CreateDebugRecording(ctx, &models.DebugRecording{ExpiresAt: now.Add(time.Hour)}); err == nil {
+		t.Error("expected an error creating a recording without a project")
+	}
+
+	expired := createDebugRecording(t, repo, &models.DebugRecording{ProjectID: 1, StartedByUserID: 1, Reason: "ticket 1", ExpiresAt: now.Add(-time.Minute)})
+	active := createDebugRecording(t, repo, &models.DebugRecording{ProjectID: 1, StartedByUserID: 1, Reason: "ticket 2", ExpiresAt: now.Add(time.Hour)})
+	other := createDebugRecording(t, repo, &models.DebugRecording{ProjectID: 2, StartedByUserID: 1, Reason: "ticket 3", ExpiresAt: now.Add(time.Hour)})
+
+	got, err := repo.DebugRecording().ReadDebugRecording(ctx, 1, active.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading recording: %v", err)
+	}
+	if got.ID != active.ID || got.Reason != "ticket 2" || got.StartedByUserID != 1 {
+		t.Errorf("expected the created recording, got %+v", got)
+	}
+
+	_, err = repo.DebugRecording().ReadDebugRecording(ctx, 1, other.ID)
+	expectNotFound(t, "reading another project's recording", err)
+
+	got, err = repo.DebugRecording().ReadActiveDebugRecording(ctx, 1, now)
+	if err != nil {
+		t.Fatalf("unexpected error reading active recording: %v", err)
+	}
+	if got.ID != active.ID {
+		t.Errorf("expected recording %d to be active, got %d", active.ID, got.ID)
+	}
+
+	// an active recording expires without being updated
+	_, err = repo.DebugRecording().ReadActiveDebugRecording(ctx, 1, now.Add(2*time.Hour))
+	expectNotFound(t, "reading a recording after it expired", err)
+
+	recordings, err := repo.DebugRecording().ListDebugRecordingsByProjectID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing recordings: %v", err)
+	}
+	expectIDs(t, "recordings of project 1", debugRecordingIDs(recordings), active.ID, expired.ID)
+
+	stoppedAt := now
+	active.StoppedAt = &stoppedAt
+	if _, err := repo.DebugRecording().UpdateDebugRecording(ctx, active); err != nil {
+		t.Fatalf("unexpected error stopping recording: %v", err)
+	}
+
+	_, err = repo.DebugRecording().ReadActiveDebugRecording(ctx, 1, now)
+	expectNotFound(t, "reading a stopped recording", err)
+
+	got, err = repo.DebugRecording().ReadActiveDebugRecording(ctx, 2, now)
+	if err != nil {
+		t.Fatalf("unexpected error reading the active recording of another project: %v", err)
+	}
+	if got.ID != other.ID {
+		t.Errorf("expected recording %d to be active, got %d", other.ID, got.ID)
+	}
+}
+
+func testDebugRecordingReserveExchange(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	recording := createDebugRecording(t, repo, &models.DebugRecording{ProjectID: 1, Reason: "ticket", ExpiresAt: time.Now().Add(time.Hour)})
+
+	for i := 0; i < 2; i++ {
+		ok, err := repo.DebugRecording().ReserveDebugRecordingExchange(ctx, recording.ID, 2)
+		if err != nil {
+			t.Fatalf("unexpected error reserving exchange: %v", err)
+		}
+		if !ok {
+			t.Errorf("expected exchange %d to be reserved", i+1)
+		}
+	}
+
+	ok, err := repo.DebugRecording().ReserveDebugRecordingExchange(ctx, recording.ID, 2)
+	if err != nil {
+		t.Fatalf("unexpected error reserving exchange: %v", err)
+	}
+	if ok {
+		t.Error("expected a full recording to refuse another exchange")
+	}
+
+	got, err := repo.DebugRecording().ReadDebugRecording(ctx, 1, recording.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading recording: %v", err)
+	}
+	if got.ExchangeCount != 2 {
+		t.Errorf("expected 2 exchanges to be counted, got %d", got.ExchangeCount)
+	}
+}
@@ -0,0 +1,111 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "dns record/delete by hostname",
+			Covers: []string{
+				"DNSRecordRepository.CreateDNSRecord",
+				"DNSRecordRepository.DeleteDNSRecordsByHostname",
+			},
+			Run: testDNSRecordDeleteByHostname,
+		},
+		Case{
+			Name: "dns record/update endpoints by hostname",
+			Covers: []string{
+				"DNSRecordRepository.CreateDNSRecord",
+				"DNSRecordRepository.UpdateDNSRecordEndpointsByHostname",
+			},
+			Run: testDNSRecordUpdateEndpointsByHostname,
+		},
+	)
+}
+
+func testDNSRecordDeleteByHostname(t *testing.T, repo repository.Repository) {
+	for i, hostname := range []string{"web.internal", "api.internal", "web.internal", "worker.internal"} {
+		_, err := repo.DNSRecord().CreateDNSRecord(&models.DNSRecord{
+			SubdomainPrefix: string(rune('a' + i)),
+			RootDomain:      "porter.run",
+			Hostname:        hostname,
+			ClusterID:       1,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error creating record: %v", err)
+		}
+	}
+
+	deleted, err := repo.DNSRecord().DeleteDNSRecordsByHostname(nil)
+	if err != nil || deleted != 0 {
+		t.Errorf("expected deleting no hostnames to delete nothing, got %d: %v", deleted, err)
+	}
+
+	deleted, err = repo.DNSRecord().DeleteDNSRecordsByHostname([]string{"web.internal", "missing.internal"})
+	if err != nil {
+		t.Fatalf("unexpected error deleting records: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected both records of the hostname to be deleted, got %d", deleted)
+	}
+
+	deleted, err = repo.DNSRecord().DeleteDNSRecordsByHostname([]string{"web.internal", "api.internal"})
+	if err != nil {
+		t.Fatalf("unexpected error deleting records: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected only the record which was left to be deleted, got %d", deleted)
+	}
+}
+
+func testDNSRecordUpdateEndpointsByHostname(t *testing.T, repo repository.Repository) {
+	for i, hostname := range []string{"web.internal", "api.internal", "worker.internal"} {
+		_, err := repo.DNSRecord().CreateDNSRecord(&models.DNSRecord{
+			SubdomainPrefix: string(rune('a' + i)),
+			RootDomain:      "porter.run",
+			Endpoint:        "10.0.0.1",
+			Hostname:        hostname,
+			ClusterID:       1,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error creating record: %v", err)
+		}
+	}
+
+	updated, err := repo.DNSRecord().UpdateDNSRecordEndpointsByHostname(nil, "10.0.0.2")
+	if err != nil || len(updated) != 0 {
+		t.Errorf("expected updating no hostnames to update nothing, got %d: %v", len(updated), err)
+	}
+
+	updated, err = repo.DNSRecord().UpdateDNSRecordEndpointsByHostname([]string{"web.internal", "worker.internal", "missing.internal"}, "10.0.0.2")
+	if err != nil {
+		t.Fatalf("unexpected error updating records: %v", err)
+	}
+	if len(updated) != 2 {
+		t.Fatalf("expected the records of both hostnames to be updated, got %d", len(updated))
+	}
+	for _, record := range updated {
+		if record.Endpoint != "10.0.0.2" {
+			t.Errorf("expected record %s to point at 10.0.0.2, got %s", record.Hostname, record.Endpoint)
+		}
+	}
+
+	// the records which were not updated keep their endpoint, and are left when the others are deleted
+	deleted, err := repo.DNSRecord().DeleteDNSRecordsByHostname([]string{"web.internal", "worker.internal"})
+	if err != nil || deleted != 2 {
+		t.Errorf("expected the updated records to be deleted, got %d: %v", deleted, err)
+	}
+
+	updated, err = repo.DNSRecord().UpdateDNSRecordEndpointsByHostname([]string{"api.internal"}, "10.0.0.1")
+	if err != nil {
+		t.Fatalf("unexpected error updating records: %v", err)
+	}
+	if len(updated) != 1 || updated[0].Hostname != "api.internal" {
+		t.Errorf("expected only the record of api.internal to be left, got %v", updated)
+	}
+}
@@ -0,0 +1,96 @@
+package contract
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "instance/counts",
+			Covers: []string{
+				"ProjectRepository.CountProjects",
+				"UserRepository.CountUsers",
+				"ClusterRepository.CountClustersByProvider",
+				"PorterAppRepository.CountPorterApps",
+			},
+			Run: testInstanceCounts,
+		},
+	)
+}
+
+func testInstanceCounts(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	for _, name := range []string{"storefront", "billing"} {
+		if _, err := repo.Project().CreateProject(&models.Project{Name: name}); err != nil {
+			t.Fatalf("unexpected error creating project: %v", err)
+		}
+	}
+
+	for _, email := range []string{"ada@example.com", "grace@example.com", "linus@example.com"} {
+		if _, err := repo.User().CreateUser(&models.User{Email: email}); err != nil {
+			t.Fatalf("unexpected error creating user: %v", err)
+		}
+	}
+
+	for _, cluster := range []*models.Cluster{
+		{ProjectID: 1, Name: "capi", CloudProvider: "aws"},
+		{ProjectID: 1, Name: "eks", AWSIntegrationID: 1},
+		{ProjectID: 2, Name: "gke", GCPIntegrationID: 1},
+		{ProjectID: 2, Name: "kubeconfig"},
+	} {
+		if _, err := repo.Cluster().CreateCluster(cluster, nil); err != nil {
+			t.Fatalf("unexpected error creating cluster: %v", err)
+		}
+	}
+
+	var deleted *models.PorterApp
+	for _, name := range []string{"web", "worker", "cron"} {
+		app, err := repo.PorterApp().CreatePorterApp(&models.PorterApp{ProjectID: 1, ClusterID: 1, Name: name})
+		if err != nil {
+			t.Fatalf("unexpected error creating app: %v", err)
+		}
+		deleted = app
+	}
+	if _, err := repo.PorterApp().DeletePorterApp(deleted); err != nil {
+		t.Fatalf("unexpected error deleting app: %v", err)
+	}
+
+	projects, err := repo.Project().CountProjects(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error counting projects: %v", err)
+	}
+	if projects != 2 {
+		t.Errorf("expected 2 projects, got %d", projects)
+	}
+
+	users, err := repo.User().CountUsers(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error counting users: %v", err)
+	}
+	if users != 3 {
+		t.Errorf("expected 3 users, got %d", users)
+	}
+
+	clusters, err := repo.Cluster().CountClustersByProvider(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error counting clusters: %v", err)
+	}
+	if want := map[string]int64{"AWS": 2, "GCP": 1, "OTHER": 1}; !reflect.DeepEqual(clusters, want) {
+		t.Errorf("expected clusters by provider %v, got %v", want, clusters)
+	}
+
+	apps, err := repo.PorterApp().CountPorterApps(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error counting apps: %v", err)
+	}
+	if apps != 2 {
+		t.Errorf("expected the deleted app not to be counted, got %d apps", apps)
+	}
+}
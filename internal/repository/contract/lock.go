@@ -0,0 +1,74 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "lock/acquire and release",
+			Covers: []string{
+				"LockRepository.AcquireLock",
+				"LockRepository.ReleaseLock",
+			},
+			Run: testLockAcquireAndRelease,
+		},
+	)
+}
+
+func acquireLock(t *testing.T, repo repository.Repository, name, token string, now time.Time) bool {
+	t.Helper()
+
+	ok, err := repo.Lock().AcquireLock(context.Background(), name, token, now, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error acquiring lock: %v", err)
+	}
+
+	return ok
+}
+
+func testLockAcquireAndRelease(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	if _, err := repo.Lock().AcquireLock(ctx, "deploy", "", now, now.Add(time.Minute)); err == nil {
+		t.Error("expected an error acquiring a lock without a token")
+	}
+
+	if !acquireLock(t, repo, "deploy", "a", now) {
+		t.Fatal("expected a free lock to be acquired")
+	}
+	if acquireLock(t, repo, "deploy", "b", now) {
+		t.Error("expected a held lock not to be acquired by another holder")
+	}
+	if !acquireLock(t, repo, "other", "b", now) {
+		t.Error("expected locks with other names to be acquired")
+	}
+
+	if err := repo.Lock().ReleaseLock(ctx, "deploy", "b"); err != nil {
+		t.Fatalf("unexpected error releasing lock: %v", err)
+	}
+	if acquireLock(t, repo, "deploy", "b", now) {
+		t.Error("expected a release by another holder to leave the lock held")
+	}
+
+	if err := repo.Lock().ReleaseLock(ctx, "deploy", "a"); err != nil {
+		t.Fatalf("unexpected error releasing lock: %v", err)
+	}
+	if !acquireLock(t, repo, "deploy", "b", now) {
+		t.Fatal("expected a released lock to be acquired")
+	}
+
+	// the lock taken by b expires after a minute
+	if !acquireLock(t, repo, "deploy", "c", now.Add(2*time.Minute)) {
+		t.Error("expected an expired lock to be taken over")
+	}
+	if acquireLock(t, repo, "deploy", "b", now.Add(2*time.Minute)) {
+		t.Error("expected the lock to be held by the holder which took it over")
+	}
+}
@@ -0,0 +1,139 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "log alert rule/create, read and list",
+			Covers: []string{
+				"LogAlertRuleRepository.CreateLogAlertRule",
+				"LogAlertRuleRepository.ReadLogAlertRule",
+				"LogAlertRuleRepository.ListLogAlertRulesByPorterAppID",
+				"LogAlertRuleRepository.ListEnabledLogAlertRules",
+			},
+			Run: testLogAlertRuleCreateReadAndList,
+		},
+		Case{
+			Name: "log alert rule/update and delete",
+			Covers: []string{
+				"LogAlertRuleRepository.UpdateLogAlertRule",
+				"LogAlertRuleRepository.DeleteLogAlertRule",
+			},
+			Run: testLogAlertRuleUpdateAndDelete,
+		},
+	)
+}
+
+func createLogAlertRule(t *testing.T, repo repository.Repository, rule *models.LogAlertRule) *models.LogAlertRule {
+	t.Helper()
+
+	rule, err := repo.LogAlertRule().CreateLogAlertRule(context.Background(), rule)
+	if err != nil {
+		t.Fatalf("unexpected error creating log alert rule: %v", err)
+	}
+
+	return rule
+}
+
+func logAlertRuleIDs(rules []*models.LogAlertRule) []uint {
+	ids := make([]uint, 0, len(rules))
+	for _, rule := range rules {
+		ids = append(ids, rule.ID)
+	}
+
+	return ids
+}
+
+func testLogAlertRuleCreateReadAndList(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	if _, err := repo.LogAlertRule().CreateLogAlertRule(ctx, &models.LogAlertRule{PorterAppID: 1, Name: "errors"}); err == nil {
+		t.Error("expected an error creating a rule without a project")
+	}
+	if _, err := repo.LogAlertRule().CreateLogAlertRule(ctx, &models.LogAlertRule{ProjectID: 1, Name: "errors"}); err == nil {
+		t.Error("expected an error creating a rule without a porter app")
+	}
+
+	// rules are listed for evaluation by cluster, then app, then id
+	timeouts := createLogAlertRule(t, repo, &models.LogAlertRule{ProjectID: 1, ClusterID: 2, PorterAppID: 1, Name: "timeouts", Pattern: "timeout", Threshold: 5, WindowMinutes: 10, Enabled: true})
+	errs := createLogAlertRule(t, repo, &models.LogAlertRule{ProjectID: 1, ClusterID: 1, PorterAppID: 1, Name: "errors", Pattern: "ERROR", Threshold: 1, WindowMinutes: 5, Enabled: true})
+	disabled := createLogAlertRule(t, repo, &models.LogAlertRule{ProjectID: 1, ClusterID: 1, PorterAppID: 1, Name: "panics", Pattern: "panic"})
+	other := createLogAlertRule(t, repo, &models.LogAlertRule{ProjectID: 2, ClusterID: 1, PorterAppID: 2, Name: "errors", Pattern: "ERROR", Enabled: true})
+
+	got, err := repo.LogAlertRule().ReadLogAlertRule(ctx, 1, 1, errs.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading rule: %v", err)
+	}
+	if got.ID != errs.ID || got.Pattern != "ERROR" || got.Threshold != 1 || got.WindowMinutes != 5 || !got.Enabled {
+		t.Errorf("expected the created rule, got %+v", got)
+	}
+
+	// the rule of another project or app must look like it does not exist
+	_, err = repo.LogAlertRule().ReadLogAlertRule(ctx, 1, 2, other.ID)
+	expectNotFound(t, "reading another project's rule", err)
+
+	_, err = repo.LogAlertRule().ReadLogAlertRule(ctx, 2, 1, other.ID)
+	expectNotFound(t, "reading the rule of another app", err)
+
+	rules, err := repo.LogAlertRule().ListLogAlertRulesByPorterAppID(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing rules: %v", err)
+	}
+	expectIDs(t, "rules of app 1", logAlertRuleIDs(rules), timeouts.ID, errs.ID, disabled.ID)
+
+	rules, err = repo.LogAlertRule().ListLogAlertRulesByPorterAppID(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error listing rules of another project's app: %v", err)
+	}
+	expectIDs(t, "rules of another project's app", logAlertRuleIDs(rules))
+
+	rules, err = repo.LogAlertRule().ListEnabledLogAlertRules(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing enabled rules: %v", err)
+	}
+	expectIDs(t, "enabled rules", logAlertRuleIDs(rules), errs.ID, other.ID, timeouts.ID)
+}
+
+func testLogAlertRuleUpdateAndDelete(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	rule := createLogAlertRule(t, repo, &models.LogAlertRule{ProjectID: 1, ClusterID: 1, PorterAppID: 1, Name: "errors", Pattern: "ERROR", Enabled: true})
+	createLogAlertRule(t, repo, &models.LogAlertRule{ProjectID: 1, ClusterID: 1, PorterAppID: 1, Name: "timeouts", Pattern: "timeout", Enabled: true})
+
+	// updates write every field, so a rule can be disabled
+	rule.Enabled = false
+	rule.Threshold = 3
+	if _, err := repo.LogAlertRule().UpdateLogAlertRule(ctx, rule); err != nil {
+		t.Fatalf("unexpected error updating rule: %v", err)
+	}
+
+	got, err := repo.LogAlertRule().ReadLogAlertRule(ctx, 1, 1, rule.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading updated rule: %v", err)
+	}
+	if got.Enabled || got.Threshold != 3 {
+		t.Errorf("expected the rule to be disabled with a threshold of 3, got %+v", got)
+	}
+
+	if err := repo.LogAlertRule().DeleteLogAlertRule(ctx, rule); err != nil {
+		t.Fatalf("unexpected error deleting rule: %v", err)
+	}
+
+	_, err = repo.LogAlertRule().ReadLogAlertRule(ctx, 1, 1, rule.ID)
+	expectNotFound(t, "reading a deleted rule", err)
+
+	rules, err := repo.LogAlertRule().ListLogAlertRulesByPorterAppID(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing rules: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Name != "timeouts" {
+		t.Errorf("expected only the remaining rule to be listed, got %v", logAlertRuleIDs(rules))
+	}
+}
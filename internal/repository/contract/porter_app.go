@@ -0,0 +1,586 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "porter app/create and read",
+			Covers: []string{
+				"PorterAppRepository.CreatePorterApp",
+				"PorterAppRepository.ReadPorterAppByID",
+				"PorterAppRepository.ReadPorterAppByName",
+			},
+			Run: testPorterAppCreateAndRead,
+		},
+		Case{
+			Name: "porter app/scoped reads",
+			Covers: []string{
+				"PorterAppRepository.ReadScopedPorterAppByID",
+				"PorterAppRepository.ReadScopedPorterAppByName",
+				"PorterAppRepository.ReadScopedPorterAppByUUID",
+				"PorterAppRepository.ListScopedPorterAppsByClusterID",
+				"PorterAppRepository.ListPorterAppByClusterID",
+				"PorterAppRepository.ReadPorterAppsByProjectIDAndName",
+			},
+			Run: testPorterAppScopedReads,
+		},
+		Case{
+			Name: "porter app/update and delete",
+			Covers: []string{
+				"PorterAppRepository.UpdatePorterApp",
+				"PorterAppRepository.DeletePorterApp",
+				"PorterAppRepository.ListPorterAppsByIDs",
+			},
+			Run: testPorterAppUpdateAndDelete,
+		},
+		Case{
+			Name: "porter app/previous names",
+			Covers: []string{
+				"PorterAppRepository.ReadScopedPorterAppByPreviousName",
+			},
+			Run: testPorterAppPreviousNames,
+		},
+		Case{
+			Name: "porter app/rename",
+			Covers: []string{
+				"PorterAppRepository.RenamePorterApp",
+			},
+			Run: testPorterAppRename,
+		},
+		Case{
+			Name: "porter app/case-insensitive names",
+			Covers: []string{
+				"PorterAppRepository.ReadPorterAppByName",
+				"PorterAppRepository.ReadScopedPorterAppByName",
+				"PorterAppRepository.ReadPorterAppsByProjectIDAndName",
+				"PorterAppRepository.ReadScopedPorterAppByPreviousName",
+				"PorterAppRepository.RenamePorterApp",
+			},
+			Run: testPorterAppCaseInsensitiveNames,
+		},
+		Case{
+			Name: "porter app/scaling schedules",
+			Covers: []string{
+				"PorterAppRepository.ListPorterAppsWithScalingSchedules",
+				"PorterAppRepository.UpdatePorterAppScalingSchedules",
+			},
+			Run: testPorterAppScalingSchedules,
+		},
+		Case{
+			Name: "porter app/deploy webhook token",
+			Covers: []string{
+				"PorterAppRepository.ReadPorterAppByDeployWebhookTokenHash",
+			},
+			Run: testPorterAppDeployWebhookToken,
+		},
+		Case{
+			Name: "porter app/list every app",
+			Covers: []string{
+				"PorterAppRepository.ListPorterApps",
+			},
+			Run: testPorterAppListAll,
+		},
+	)
+}
+
+func createPorterApp(t *testing.T, repo repository.Repository, app *models.PorterApp) *models.PorterApp {
+	t.Helper()
+
+	app, err := repo.PorterApp().CreatePorterApp(app)
+	if err != nil {
+		t.Fatalf("unexpected error creating porter app: %v", err)
+	}
+
+	return app
+}
+
+func porterAppIDs(apps []*models.PorterApp) []uint {
+	ids := make([]uint, 0, len(apps))
+	for _, app := range apps {
+		ids = append(ids, app.ID)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+func expectIDs(t *testing.T, what string, got []uint, want ...uint) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("expected %s %v, got %v", what, want, got)
+		return
+	}
+
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("expected %s %v, got %v", what, want, got)
+			return
+		}
+	}
+}
+
+func expectNotFound(t *testing.T, what string, err error) {
+	t.Helper()
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected gorm.ErrRecordNotFound %s, got %v", what, err)
+	}
+}
+
+func testPorterAppCreateAndRead(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	app := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "web", ImageRepoURI: "porter/web"})
+	if app.ID == 0 {
+		t.Fatal("expected the app to be given an id")
+	}
+	if app.UUID == uuid.Nil {
+		t.Error("expected the app to be given a uuid")
+	}
+
+	got, err := repo.PorterApp().ReadPorterAppByID(ctx, app.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading by id: %v", err)
+	}
+	if got.ID != app.ID || got.Name != "web" || got.ImageRepoURI != "porter/web" || got.UUID != app.UUID {
+		t.Errorf("expected app %d named web, got %d named %s", app.ID, got.ID, got.Name)
+	}
+
+	got, err = repo.PorterApp().ReadPorterAppByName(1, "web")
+	if err != nil {
+		t.Fatalf("unexpected error reading by name: %v", err)
+	}
+	if got.ID != app.ID {
+		t.Errorf("expected app %d reading by name, got %d", app.ID, got.ID)
+	}
+
+	// the unscoped reads return an empty app rather than an error for an app which does not exist, which callers
+	// check for with app.ID == 0
+	got, err = repo.PorterApp().ReadPorterAppByID(ctx, app.ID+1)
+	if err != nil {
+		t.Fatalf("unexpected error reading a missing app by id: %v", err)
+	}
+	if got == nil || got.ID != 0 {
+		t.Errorf("expected an empty app reading a missing app by id, got %v", got)
+	}
+
+	got, err = repo.PorterApp().ReadPorterAppByName(2, "web")
+	if err != nil {
+		t.Fatalf("unexpected error reading a missing app by name: %v", err)
+	}
+	if got == nil || got.ID != 0 {
+		t.Errorf("expected an empty app reading a missing app by name, got %v", got)
+	}
+}
+
+func testPorterAppScopedReads(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	// identically named apps in two clusters of project 1, and in a cluster of project 2
+	first := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "web"})
+	second := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 2, Name: "web"})
+	other := createPorterApp(t, repo, &models.PorterApp{ProjectID: 2, ClusterID: 3, Name: "web"})
+	worker := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "worker"})
+
+	got, err := repo.PorterApp().ReadScopedPorterAppByID(ctx, 1, first.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading by id: %v", err)
+	}
+	if got.ID != first.ID {
+		t.Errorf("expected app %d reading by id, got %d", first.ID, got.ID)
+	}
+
+	got, err = repo.PorterApp().ReadScopedPorterAppByName(1, 2, "web")
+	if err != nil {
+		t.Fatalf("unexpected error reading by name: %v", err)
+	}
+	if got.ID != second.ID {
+		t.Errorf("expected app %d reading by name, got %d", second.ID, got.ID)
+	}
+
+	got, err = repo.PorterApp().ReadScopedPorterAppByUUID(ctx, 2, 3, other.UUID)
+	if err != nil {
+		t.Fatalf("unexpected error reading by uuid: %v", err)
+	}
+	if got.ID != other.ID {
+		t.Errorf("expected app %d reading by uuid, got %d", other.ID, got.ID)
+	}
+
+	// the app of another project must look like it does not exist
+	_, err = repo.PorterApp().ReadScopedPorterAppByID(ctx, 1, other.ID)
+	expectNotFound(t, "reading another project's app by id", err)
+
+	_, err = repo.PorterApp().ReadScopedPorterAppByName(1, 3, "web")
+	expectNotFound(t, "reading another project's app by name", err)
+
+	_, err = repo.PorterApp().ReadScopedPorterAppByUUID(ctx, 1, 3, other.UUID)
+	expectNotFound(t, "reading another project's app by uuid", err)
+
+	_, err = repo.PorterApp().ReadScopedPorterAppByUUID(ctx, 1, 1, uuid.New())
+	expectNotFound(t, "reading a missing uuid", err)
+
+	apps, err := repo.PorterApp().ListScopedPorterAppsByClusterID(1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing scoped apps: %v", err)
+	}
+	expectIDs(t, "scoped apps of cluster 1", porterAppIDs(apps), first.ID, worker.ID)
+
+	apps, err = repo.PorterApp().ListScopedPorterAppsByClusterID(1, 3)
+	if err != nil {
+		t.Fatalf("unexpected error listing scoped apps of another project's cluster: %v", err)
+	}
+	expectIDs(t, "scoped apps of another project's cluster", porterAppIDs(apps))
+
+	apps, err = repo.PorterApp().ListPorterAppByClusterID(3)
+	if err != nil {
+		t.Fatalf("unexpected error listing apps: %v", err)
+	}
+	expectIDs(t, "apps of cluster 3", porterAppIDs(apps), other.ID)
+
+	apps, err = repo.PorterApp().ReadPorterAppsByProjectIDAndName(1, "web")
+	if err != nil {
+		t.Fatalf("unexpected error listing apps by name: %v", err)
+	}
+	expectIDs(t, "apps named web in project 1", porterAppIDs(apps), first.ID, second.ID)
+}
+
+func testPorterAppUpdateAndDelete(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	web := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "web"})
+	worker := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "worker"})
+
+	web.GitBranch = "main"
+	if _, err := repo.PorterApp().UpdatePorterApp(web); err != nil {
+		t.Fatalf("unexpected error updating app: %v", err)
+	}
+
+	got, err := repo.PorterApp().ReadScopedPorterAppByID(ctx, 1, web.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading updated app: %v", err)
+	}
+	if got.GitBranch != "main" {
+		t.Errorf("expected the updated branch main, got %q", got.GitBranch)
+	}
+
+	if _, err := repo.PorterApp().DeletePorterApp(worker); err != nil {
+		t.Fatalf("unexpected error deleting app: %v", err)
+	}
+
+	_, err = repo.PorterApp().ReadScopedPorterAppByID(ctx, 1, worker.ID)
+	expectNotFound(t, "reading a deleted app", err)
+
+	got, err = repo.PorterApp().ReadPorterAppByID(ctx, worker.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading a deleted app by id: %v", err)
+	}
+	if got.ID != 0 {
+		t.Errorf("expected an empty app reading a deleted app by id, got %d", got.ID)
+	}
+
+	apps, err := repo.PorterApp().ListPorterAppByClusterID(1)
+	if err != nil {
+		t.Fatalf("unexpected error listing apps: %v", err)
+	}
+	expectIDs(t, "apps after delete", porterAppIDs(apps), web.ID)
+
+	// listing by ids includes deleted apps, so that usage and history of deleted apps can be attributed
+	apps, err = repo.PorterApp().ListPorterAppsByIDs(ctx, []uint{web.ID, worker.ID, worker.ID + 1})
+	if err != nil {
+		t.Fatalf("unexpected error listing apps by ids: %v", err)
+	}
+	expectIDs(t, "apps listed by ids", porterAppIDs(apps), web.ID, worker.ID)
+
+	apps, err = repo.PorterApp().ListPorterAppsByIDs(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error listing apps by no ids: %v", err)
+	}
+	expectIDs(t, "apps listed by no ids", porterAppIDs(apps))
+}
+
+func testPorterAppPreviousNames(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	expired := now.Add(-time.Hour)
+	soon := now.Add(time.Hour)
+	later := now.Add(2 * time.Hour)
+
+	createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "jobs-v2", PreviousName: "jobs", PreviousNameExpiresAt: &expired})
+	first := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "api", PreviousName: "web", PreviousNameExpiresAt: &soon})
+	latest := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "gateway", PreviousName: "web", PreviousNameExpiresAt: &later})
+
+	got, err := repo.PorterApp().ReadScopedPorterAppByPreviousName(ctx, 1, 1, "web")
+	if err != nil {
+		t.Fatalf("unexpected error reading by previous name: %v", err)
+	}
+	if got.ID != latest.ID {
+		t.Errorf("expected the app most recently renamed from web (%d) rather than %d, got %d", latest.ID, first.ID, got.ID)
+	}
+
+	_, err = repo.PorterApp().ReadScopedPorterAppByPreviousName(ctx, 1, 1, "jobs")
+	expectNotFound(t, "reading an expired previous name", err)
+
+	_, err = repo.PorterApp().ReadScopedPorterAppByPreviousName(ctx, 2, 1, "web")
+	expectNotFound(t, "reading another project's previous name", err)
+}
+
+func testPorterAppRename(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+
+	app := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "payments"})
+	createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "checkout"})
+	createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 2, Name: "billing"})
+
+	renamed, err := repo.PorterApp().RenamePorterApp(ctx, app, "checkout", expiresAt)
+	if err != nil {
+		t.Fatalf("unexpected error renaming onto a taken name: %v", err)
+	}
+	if renamed {
+		t.Fatalf("expected renaming onto the name of another app in the cluster to be refused")
+	}
+	if app.Name != "payments" || app.PreviousName != "" {
+		t.Errorf("expected a refused rename to leave the app as it was, got %s renamed from %q", app.Name, app.PreviousName)
+	}
+
+	// an app in another cluster does not take the name
+	renamed, err = repo.PorterApp().RenamePorterApp(ctx, app, "billing", expiresAt)
+	if err != nil {
+		t.Fatalf("unexpected error renaming the app: %v", err)
+	}
+	if !renamed {
+		t.Fatalf("expected the app to be renamed")
+	}
+	if app.Name != "billing" || app.PreviousName != "payments" {
+		t.Errorf("expected the app to be billing renamed from payments, got %s renamed from %q", app.Name, app.PreviousName)
+	}
+
+	got, err := repo.PorterApp().ReadScopedPorterAppByName(1, 1, "billing")
+	if err != nil {
+		t.Fatalf("unexpected error reading the app by its new name: %v", err)
+	}
+	if got.ID != app.ID || got.PreviousName != "payments" {
+		t.Errorf("expected app %d renamed from payments, got app %d renamed from %q", app.ID, got.ID, got.PreviousName)
+	}
+	if got.PreviousNameExpiresAt == nil || !got.PreviousNameExpiresAt.Equal(expiresAt) {
+		t.Errorf("expected the previous name to expire at %v, got %v", expiresAt, got.PreviousNameExpiresAt)
+	}
+
+	_, err = repo.PorterApp().ReadScopedPorterAppByName(1, 1, "payments")
+	expectNotFound(t, "reading the app by its previous name", err)
+
+	_, err = repo.PorterApp().RenamePorterApp(ctx, &models.PorterApp{}, "ledger", expiresAt)
+	if err == nil {
+		t.Errorf("expected an error renaming an app without an id")
+	}
+}
+
+func testPorterAppCaseInsensitiveNames(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	expiresAt := time.Now().UTC().Add(time.Hour)
+
+	// apps created before names were lowercased at the api may have mixed-case names
+	legacy := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "MyApp"})
+	renamed := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "gateway", PreviousName: "Web", PreviousNameExpiresAt: &expiresAt})
+	createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 2, Name: "worker"})
+
+	got, err := repo.PorterApp().ReadPorterAppByName(1, "myapp")
+	if err != nil {
+		t.Fatalf("unexpected error reading by name: %v", err)
+	}
+	if got.ID != legacy.ID {
+		t.Errorf("expected app %d reading myapp, got %d", legacy.ID, got.ID)
+	}
+
+	got, err = repo.PorterApp().ReadScopedPorterAppByName(1, 1, "MYAPP")
+	if err != nil {
+		t.Fatalf("unexpected error reading by scoped name: %v", err)
+	}
+	if got.ID != legacy.ID {
+		t.Errorf("expected app %d reading MYAPP, got %d", legacy.ID, got.ID)
+	}
+
+	apps, err := repo.PorterApp().ReadPorterAppsByProjectIDAndName(1, "myApp")
+	if err != nil {
+		t.Fatalf("unexpected error listing apps by name: %v", err)
+	}
+	expectIDs(t, "apps named myApp in project 1", porterAppIDs(apps), legacy.ID)
+
+	got, err = repo.PorterApp().ReadScopedPorterAppByPreviousName(ctx, 1, 1, "web")
+	if err != nil {
+		t.Fatalf("unexpected error reading by previous name: %v", err)
+	}
+	if got.ID != renamed.ID {
+		t.Errorf("expected app %d reading the previous name web, got %d", renamed.ID, got.ID)
+	}
+
+	ok, err := repo.PorterApp().RenamePorterApp(ctx, renamed, "myapp", expiresAt)
+	if err != nil {
+		t.Fatalf("unexpected error renaming onto a name which differs by case: %v", err)
+	}
+	if ok {
+		t.Errorf("expected renaming onto a name which only differs by case from another app to be refused")
+	}
+
+	ok, err = repo.PorterApp().RenamePorterApp(ctx, renamed, "worker", expiresAt)
+	if err != nil {
+		t.Fatalf("unexpected error renaming the app: %v", err)
+	}
+	if !ok {
+		t.Errorf("expected the app to be renamed onto the name of an app in another cluster")
+	}
+}
+
+func testPorterAppScalingSchedules(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	web := createPorterApp(t, repo, &models.PorterApp{
+		ProjectID: 1,
+		ClusterID: 1,
+		Name:      "web",
+		ScalingSchedules: models.PorterAppScalingSchedules{
+			"web": {
+				HelmName:     "web-web",
+				BaseReplicas: 2,
+				Schedule: types.ServiceScalingSchedule{
+					Timezone:  "America/New_York",
+					Schedules: []types.ScalingWindow{{Name: "business hours", Days: "MON-FRI", Start: "08:00", End: "20:00", Replicas: 10}},
+				},
+			},
+		},
+	})
+	createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "api"})
+
+	apps, err := repo.PorterApp().ListPorterAppsWithScalingSchedules(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing apps with scaling schedules: %v", err)
+	}
+	expectIDs(t, "apps with scaling schedules", porterAppIDs(apps), web.ID)
+
+	listed := apps[0]
+	service, ok := listed.ScalingSchedules["web"]
+	if !ok || service.HelmName != "web-web" || service.BaseReplicas != 2 || service.Schedule.Timezone != "America/New_York" ||
+		len(service.Schedule.Schedules) != 1 || service.Schedule.Schedules[0].Replicas != 10 {
+		t.Fatalf("expected the scaling schedule of web to be stored, got %+v", listed.ScalingSchedules)
+	}
+
+	// successive updates of the same app succeed, since each one moves the app to the time it was written at
+	for _, replicas := range []int{10, 2} {
+		service.Applied = &models.AppliedScaling{Replicas: replicas, Window: "business hours", At: time.Now().UTC()}
+
+		updated, err := repo.PorterApp().UpdatePorterAppScalingSchedules(ctx, listed)
+		if err != nil {
+			t.Fatalf("unexpected error updating scaling schedules: %v", err)
+		}
+		if !updated {
+			t.Fatalf("expected the scaling schedules to be updated to %d replicas", replicas)
+		}
+	}
+
+	got, err := repo.PorterApp().ReadPorterAppByID(ctx, web.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading app: %v", err)
+	}
+	if applied := got.ScalingSchedules["web"].Applied; applied == nil || applied.Replicas != 2 {
+		t.Errorf("expected the applied scaling of web to be stored, got %+v", applied)
+	}
+
+	// a deploy of the app in between the app being listed and updated replaces its schedules, which are kept
+	if _, err := repo.PorterApp().UpdatePorterApp(got); err != nil {
+		t.Fatalf("unexpected error updating app: %v", err)
+	}
+	service.LastError = "stale"
+
+	updated, err := repo.PorterApp().UpdatePorterAppScalingSchedules(ctx, listed)
+	if err != nil {
+		t.Fatalf("unexpected error updating stale scaling schedules: %v", err)
+	}
+	if updated {
+		t.Error("expected the scaling schedules of an app which was updated since it was listed not to be written")
+	}
+
+	got, err = repo.PorterApp().ReadPorterAppByID(ctx, web.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading app: %v", err)
+	}
+	if got.ScalingSchedules["web"].LastError != "" {
+		t.Errorf("expected the stale scaling schedules not to be written, got %+v", got.ScalingSchedules["web"])
+	}
+
+	got.ScalingSchedules = nil
+	if _, err := repo.PorterApp().UpdatePorterApp(got); err != nil {
+		t.Fatalf("unexpected error updating app: %v", err)
+	}
+
+	apps, err = repo.PorterApp().ListPorterAppsWithScalingSchedules(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing apps with scaling schedules: %v", err)
+	}
+	expectIDs(t, "apps with scaling schedules after they are removed", porterAppIDs(apps))
+}
+
+func testPorterAppDeployWebhookToken(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	web := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "web", DeployWebhookTokenHash: "hash"})
+	createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "api"})
+
+	got, err := repo.PorterApp().ReadPorterAppByDeployWebhookTokenHash(ctx, "hash")
+	if err != nil {
+		t.Fatalf("unexpected error reading app by deploy webhook token hash: %v", err)
+	}
+	if got.ID != web.ID {
+		t.Errorf("expected app %d reading by deploy webhook token hash, got %d", web.ID, got.ID)
+	}
+
+	_, err = repo.PorterApp().ReadPorterAppByDeployWebhookTokenHash(ctx, "other")
+	expectNotFound(t, "reading an unknown deploy webhook token hash", err)
+
+	// apps without a deploy webhook have an empty hash, which must never match
+	_, err = repo.PorterApp().ReadPorterAppByDeployWebhookTokenHash(ctx, "")
+	expectNotFound(t, "reading an empty deploy webhook token hash", err)
+
+	got.DeployWebhookTokenHash = ""
+	if _, err := repo.PorterApp().UpdatePorterApp(got); err != nil {
+		t.Fatalf("unexpected error revoking deploy webhook token: %v", err)
+	}
+
+	_, err = repo.PorterApp().ReadPorterAppByDeployWebhookTokenHash(ctx, "hash")
+	expectNotFound(t, "reading a revoked deploy webhook token hash", err)
+}
+
+func testPorterAppListAll(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	web := createPorterApp(t, repo, &models.PorterApp{ProjectID: 1, ClusterID: 1, Name: "web"})
+	api := createPorterApp(t, repo, &models.PorterApp{ProjectID: 2, ClusterID: 2, Name: "api"})
+	worker := createPorterApp(t, repo, &models.PorterApp{ProjectID: 2, ClusterID: 2, Name: "worker"})
+
+	if _, err := repo.PorterApp().DeletePorterApp(worker); err != nil {
+		t.Fatalf("unexpected error deleting app: %v", err)
+	}
+
+	apps, err := repo.PorterApp().ListPorterApps(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing apps: %v", err)
+	}
+	expectIDs(t, "apps of every project", porterAppIDs(apps), web.ID, api.ID)
+}
@@ -0,0 +1,138 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "porter app cleanup/create, read and update",
+			Covers: []string{
+				"PorterAppCleanupRepository.CreatePorterAppCleanup",
+				"PorterAppCleanupRepository.ReadPorterAppCleanup",
+				"PorterAppCleanupRepository.UpdatePorterAppCleanup",
+			},
+			Run: testPorterAppCleanupCreateReadAndUpdate,
+		},
+		Case{
+			Name: "porter app cleanup/list pending and by status",
+			Covers: []string{
+				"PorterAppCleanupRepository.ListPendingPorterAppCleanups",
+				"PorterAppCleanupRepository.ListPorterAppCleanupsByStatus",
+			},
+			Run: testPorterAppCleanupList,
+		},
+	)
+}
+
+func createPorterAppCleanup(t *testing.T, repo repository.Repository, projectID uint, status types.PorterAppCleanupStatus) *models.PorterAppCleanup {
+	t.Helper()
+
+	cleanup, err := repo.PorterAppCleanup().CreatePorterAppCleanup(context.Background(), &models.PorterAppCleanup{
+		ProjectID: projectID,
+		ClusterID: 1,
+		AppName:   "payments",
+		Namespace: "porter-stack-payments",
+		Status:    string(status),
+		Pending: models.PorterAppCleanupResources{
+			{Kind: types.OrphanedResourceKind_LoadBalancer, Name: "payments-web"},
+			{Kind: types.OrphanedResourceKind_DNSRecord, Name: "payments-web-abc.withporter.run"},
+		},
+		Deadline: time.Now().UTC().Add(time.Hour).Truncate(time.Second),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating porter app cleanup: %v", err)
+	}
+
+	return cleanup
+}
+
+func testPorterAppCleanupCreateReadAndUpdate(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	created := createPorterAppCleanup(t, repo, 1, types.PorterAppCleanupStatus_Pending)
+	if created.ID == 0 {
+		t.Fatalf("expected the cleanup to be given an id")
+	}
+
+	got, err := repo.PorterAppCleanup().ReadPorterAppCleanup(ctx, 1, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading porter app cleanup: %v", err)
+	}
+	if got.AppName != "payments" || len(got.Pending) != 2 || got.Pending[0].Name != "payments-web" {
+		t.Errorf("expected the cleanup of payments with 2 pending resources, got %+v", got)
+	}
+	if !got.Deadline.Equal(created.Deadline) {
+		t.Errorf("expected deadline %v, got %v", created.Deadline, got.Deadline)
+	}
+
+	_, err = repo.PorterAppCleanup().ReadPorterAppCleanup(ctx, 2, created.ID)
+	expectNotFound(t, "reading another project's cleanup", err)
+
+	// releasing every resource writes an empty list rather than leaving the previous one
+	finishedAt := time.Now().UTC().Truncate(time.Second)
+	got.Status = string(types.PorterAppCleanupStatus_Succeeded)
+	got.Pending = nil
+	got.FinishedAt = &finishedAt
+	if err := repo.PorterAppCleanup().UpdatePorterAppCleanup(ctx, got); err != nil {
+		t.Fatalf("unexpected error updating porter app cleanup: %v", err)
+	}
+
+	got, err = repo.PorterAppCleanup().ReadPorterAppCleanup(ctx, 1, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading updated porter app cleanup: %v", err)
+	}
+	if got.Status != string(types.PorterAppCleanupStatus_Succeeded) || len(got.Pending) != 0 {
+		t.Errorf("expected a succeeded cleanup without pending resources, got %s with %v", got.Status, got.Pending)
+	}
+	if got.FinishedAt == nil || !got.FinishedAt.Equal(finishedAt) {
+		t.Errorf("expected the cleanup to finish at %v, got %v", finishedAt, got.FinishedAt)
+	}
+
+	if err := repo.PorterAppCleanup().UpdatePorterAppCleanup(ctx, &models.PorterAppCleanup{}); err == nil {
+		t.Errorf("expected an error updating a cleanup without an id")
+	}
+}
+
+func testPorterAppCleanupList(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	pending := createPorterAppCleanup(t, repo, 1, types.PorterAppCleanupStatus_Pending)
+	leaked := createPorterAppCleanup(t, repo, 1, types.PorterAppCleanupStatus_Leaked)
+	createPorterAppCleanup(t, repo, 1, types.PorterAppCleanupStatus_Succeeded)
+	otherPending := createPorterAppCleanup(t, repo, 2, types.PorterAppCleanupStatus_Pending)
+	createPorterAppCleanup(t, repo, 2, types.PorterAppCleanupStatus_Leaked)
+
+	cleanups, err := repo.PorterAppCleanup().ListPendingPorterAppCleanups(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing pending cleanups: %v", err)
+	}
+	ids := make([]uint, 0, len(cleanups))
+	for _, cleanup := range cleanups {
+		ids = append(ids, cleanup.ID)
+	}
+	expectIDs(t, "pending cleanups of every project", ids, pending.ID, otherPending.ID)
+
+	cleanups, err = repo.PorterAppCleanup().ListPorterAppCleanupsByStatus(ctx, 1, string(types.PorterAppCleanupStatus_Leaked))
+	if err != nil {
+		t.Fatalf("unexpected error listing leaked cleanups: %v", err)
+	}
+	if len(cleanups) != 1 || cleanups[0].ID != leaked.ID {
+		t.Errorf("expected only the leaked cleanup of project 1, got %v", cleanups)
+	}
+
+	cleanups, err = repo.PorterAppCleanup().ListPorterAppCleanupsByStatus(ctx, 3, string(types.PorterAppCleanupStatus_Leaked))
+	if err != nil {
+		t.Fatalf("unexpected error listing the cleanups of a project without any: %v", err)
+	}
+	if len(cleanups) != 0 {
+		t.Errorf("expected no cleanups of a project without any, got %v", cleanups)
+	}
+}
@@ -0,0 +1,160 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "porter app domain/create, list, update and delete",
+			Covers: []string{
+				"PorterAppDomainRepository.CreatePorterAppDomain",
+				"PorterAppDomainRepository.ListPorterAppDomains",
+				"PorterAppDomainRepository.UpdatePorterAppDomain",
+				"PorterAppDomainRepository.DeletePorterAppDomain",
+			},
+			Run: testPorterAppDomainLifecycle,
+		},
+		Case{
+			Name:   "porter app domain/list by hostnames",
+			Covers: []string{"PorterAppDomainRepository.ListPorterAppDomainsByHostnames"},
+			Run:    testListPorterAppDomainsByHostnames,
+		},
+	)
+}
+
+func createPorterAppDomain(t *testing.T, repo repository.Repository, porterAppID uint, hostname string) *models.PorterAppDomain {
+	t.Helper()
+
+	domain, err := repo.PorterAppDomain().CreatePorterAppDomain(context.Background(), &models.PorterAppDomain{
+		ProjectID:   1,
+		ClusterID:   1,
+		PorterAppID: porterAppID,
+		Hostname:    hostname,
+		CertStatus:  string(types.CertStatus_Unknown),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating porter app domain: %v", err)
+	}
+
+	return domain
+}
+
+func testPorterAppDomainLifecycle(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	api := createPorterAppDomain(t, repo, 1, "api.example.com")
+	app := createPorterAppDomain(t, repo, 1, "app.example.com")
+	createPorterAppDomain(t, repo, 2, "other.example.com")
+	if api.ID == 0 || app.ID == 0 {
+		t.Fatalf("expected the domains to be given ids")
+	}
+
+	domains, err := repo.PorterAppDomain().ListPorterAppDomains(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing porter app domains: %v", err)
+	}
+	if len(domains) != 2 || domains[0].Hostname != "api.example.com" || domains[1].Hostname != "app.example.com" {
+		t.Fatalf("expected the domains of app 1 ordered by hostname, got %+v", domains)
+	}
+
+	expiresAt := time.Now().UTC().Add(10 * 24 * time.Hour).Truncate(time.Second)
+	checkedAt := time.Now().UTC().Truncate(time.Second)
+	alerted := uint(14)
+
+	got := domains[0]
+	got.CertStatus = string(types.CertStatus_Expiring)
+	got.CertSource = string(types.CertSource_CertManager)
+	got.CertIssuer = "letsencrypt-prod"
+	got.CertExpiresAt = &expiresAt
+	got.CheckedAt = &checkedAt
+	got.AlertedThresholdDays = &alerted
+	if err := repo.PorterAppDomain().UpdatePorterAppDomain(ctx, got); err != nil {
+		t.Fatalf("unexpected error updating porter app domain: %v", err)
+	}
+
+	domains, err = repo.PorterAppDomain().ListPorterAppDomains(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing updated porter app domains: %v", err)
+	}
+	got = domains[0]
+	if got.CertStatus != string(types.CertStatus_Expiring) || got.CertIssuer != "letsencrypt-prod" ||
+		got.CertExpiresAt == nil || !got.CertExpiresAt.Equal(expiresAt) ||
+		got.AlertedThresholdDays == nil || *got.AlertedThresholdDays != 14 {
+		t.Errorf("expected the certificate of api.example.com to be written, got %+v", got)
+	}
+
+	// a renewed certificate clears the alerts sent about the previous one, which must be written as NULL
+	got.CertStatus = string(types.CertStatus_OK)
+	got.AlertedThresholdDays = nil
+	if err := repo.PorterAppDomain().UpdatePorterAppDomain(ctx, got); err != nil {
+		t.Fatalf("unexpected error updating porter app domain: %v", err)
+	}
+
+	domains, err = repo.PorterAppDomain().ListPorterAppDomains(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing updated porter app domains: %v", err)
+	}
+	if domains[0].CertStatus != string(types.CertStatus_OK) || domains[0].AlertedThresholdDays != nil {
+		t.Errorf("expected the alerts of api.example.com to be cleared, got %+v", domains[0])
+	}
+
+	if err := repo.PorterAppDomain().DeletePorterAppDomain(ctx, app); err != nil {
+		t.Fatalf("unexpected error deleting porter app domain: %v", err)
+	}
+
+	domains, err = repo.PorterAppDomain().ListPorterAppDomains(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing porter app domains: %v", err)
+	}
+	if len(domains) != 1 || domains[0].Hostname != "api.example.com" {
+		t.Errorf("expected only api.example.com to be left, got %+v", domains)
+	}
+
+	if err := repo.PorterAppDomain().UpdatePorterAppDomain(ctx, &models.PorterAppDomain{}); err == nil {
+		t.Errorf("expected an error updating a domain without an id")
+	}
+}
+
+func testListPorterAppDomainsByHostnames(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	createPorterAppDomain(t, repo, 1, "api.example.com")
+	createPorterAppDomain(t, repo, 2, "app.example.com")
+	createPorterAppDomain(t, repo, 2, "docs.example.com")
+
+	other, err := repo.PorterAppDomain().CreatePorterAppDomain(ctx, &models.PorterAppDomain{
+		ProjectID:   2,
+		ClusterID:   2,
+		PorterAppID: 3,
+		Hostname:    "app.example.com",
+		CertStatus:  string(types.CertStatus_Unknown),
+	})
+	if err != nil || other.ID == 0 {
+		t.Fatalf("unexpected error creating porter app domain of another project: %v", err)
+	}
+
+	domains, err := repo.PorterAppDomain().ListPorterAppDomainsByHostnames(ctx, 1, []string{"app.example.com", "api.example.com", "www.example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error listing porter app domains by hostnames: %v", err)
+	}
+	if len(domains) != 2 || domains[0].Hostname != "api.example.com" || domains[0].PorterAppID != 1 ||
+		domains[1].Hostname != "app.example.com" || domains[1].PorterAppID != 2 {
+		t.Fatalf("expected the domains of project 1 with the given hostnames ordered by hostname, got %+v", domains)
+	}
+
+	domains, err = repo.PorterAppDomain().ListPorterAppDomainsByHostnames(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("unexpected error listing porter app domains without hostnames: %v", err)
+	}
+	if len(domains) != 0 {
+		t.Errorf("expected no domains without hostnames, got %+v", domains)
+	}
+}
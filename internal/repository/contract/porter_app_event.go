@@ -0,0 +1,645 @@
+package contract
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "porter app event/create, read and update",
+			Covers: []string{
+				"PorterAppEventRepository.CreateEvent",
+				"PorterAppEventRepository.ReadEvent",
+				"PorterAppEventRepository.UpdateEvent",
+			},
+			Run: testPorterAppEventCreateReadAndUpdate,
+		},
+		Case{
+			Name: "porter app event/create in batches",
+			Covers: []string{
+				"PorterAppEventRepository.CreateEvents",
+			},
+			Run: testPorterAppEventCreateInBatches,
+		},
+		Case{
+			Name: "porter app event/list and paginate",
+			Covers: []string{
+				"PorterAppEventRepository.ListEventsByPorterAppID",
+				"PorterAppEventRepository.ListEventsByPorterAppIDAndDeploymentTargetID",
+				"PorterAppEventRepository.ListBuildDeployEventsByPorterAppIDAndDeploymentTargetID",
+			},
+			Run: testPorterAppEventListAndPaginate,
+		},
+		Case{
+			Name: "porter app event/list filtered",
+			Covers: []string{
+				"PorterAppEventRepository.ListFilteredEventsByPorterAppID",
+			},
+			Run: testPorterAppEventListFiltered,
+		},
+		Case{
+			Name: "porter app event/search messages",
+			Covers: []string{
+				"PorterAppEventRepository.ListFilteredEventsByPorterAppID",
+			},
+			Run: testPorterAppEventSearchMessages,
+		},
+		Case{
+			Name: "porter app event/list filtered by deployment target",
+			Covers: []string{
+				"PorterAppEventRepository.ListFilteredEventsByPorterAppID",
+			},
+			Run: testPorterAppEventListFilteredByDeploymentTarget,
+		},
+		Case{
+			Name: "porter app event/deploy events and notifications",
+			Covers: []string{
+				"PorterAppEventRepository.ReadDeployEventByRevision",
+				"PorterAppEventRepository.ReadDeployEventByAppRevisionID",
+				"PorterAppEventRepository.ReadNotificationsByAppRevisionID",
+				"PorterAppEventRepository.NotificationByID",
+				"PorterAppEventRepository.ReadLatestEventByType",
+			},
+			Run: testPorterAppEventDeploysAndNotifications,
+		},
+		Case{
+			Name: "porter app event/created between",
+			Covers: []string{
+				"PorterAppEventRepository.ListEventsCreatedBetween",
+				"PorterAppEventRepository.DeleteEventsCreatedBetween",
+			},
+			Run: testPorterAppEventCreatedBetween,
+		},
+		Case{
+			Name: "porter app event/delete by porter app",
+			Covers: []string{
+				"PorterAppEventRepository.DeleteEventsByPorterAppID",
+			},
+			Run: testPorterAppEventDeleteByPorterApp,
+		},
+	)
+}
+
+// eventTime returns the time of the nth event of a case. Times are whole seconds in UTC, so that they survive a round
+// trip through every database.
+func eventTime(n int) time.Time {
+	return time.Date(2026, time.March, 1, 12, 0, n, 0, time.UTC)
+}
+
+func createEvent(t *testing.T, repo repository.Repository, event *models.PorterAppEvent) *models.PorterAppEvent {
+	t.Helper()
+
+	if err := repo.PorterAppEvent().CreateEvent(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error creating event: %v", err)
+	}
+
+	return event
+}
+
+func eventIDs(events []*models.PorterAppEvent) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+
+	return ids
+}
+
+func sortedEventIDs(events []*models.PorterAppEvent) []uuid.UUID {
+	ids := eventIDs(events)
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	return ids
+}
+
+func expectEventIDs(t *testing.T, what string, got []uuid.UUID, want ...uuid.UUID) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("expected %s %v, got %v", what, want, got)
+		return
+	}
+
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("expected %s %v, got %v", what, want, got)
+			return
+		}
+	}
+}
+
+func expectPage(t *testing.T, what string, got, want helpers.PaginatedResult) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("expected %s to be paginated as %+v, got %+v", what, want, got)
+	}
+}
+
+func testPorterAppEventCreateReadAndUpdate(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	if err := repo.PorterAppEvent().CreateEvent(ctx, &models.PorterAppEvent{Type: "BUILD"}); err == nil {
+		t.Error("expected an error creating an event without a porter app")
+	}
+
+	event := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "BUILD", Status: "PROGRESSING", Metadata: models.JSONB{"commit_sha": "a1b2c3"}})
+	if event.ID == uuid.Nil {
+		t.Fatal("expected the event to be given an id")
+	}
+	if event.CreatedAt.IsZero() || event.UpdatedAt.IsZero() {
+		t.Error("expected the event to be given creation and update times")
+	}
+
+	// ids are unique
+	if err := repo.PorterAppEvent().CreateEvent(ctx, &models.PorterAppEvent{ID: event.ID, PorterAppID: 1, Type: "DEPLOY"}); err == nil {
+		t.Error("expected an error creating an event with the id of another event")
+	}
+
+	got, err := repo.PorterAppEvent().ReadEvent(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading event: %v", err)
+	}
+	if got.ID != event.ID || got.Type != "BUILD" || got.Status != "PROGRESSING" || got.Metadata["commit_sha"] != "a1b2c3" {
+		t.Errorf("expected the created event, got %+v", got)
+	}
+
+	_, err = repo.PorterAppEvent().ReadEvent(ctx, uuid.New())
+	expectNotFound(t, "reading a missing event", err)
+
+	if _, err := repo.PorterAppEvent().ReadEvent(ctx, uuid.Nil); err == nil {
+		t.Error("expected an error reading an event without an id")
+	}
+
+	// updates write the fields which are set, leaving the others unchanged
+	if err := repo.PorterAppEvent().UpdateEvent(ctx, &models.PorterAppEvent{ID: event.ID, PorterAppID: 1, Status: "SUCCESS"}); err != nil {
+		t.Fatalf("unexpected error updating event: %v", err)
+	}
+
+	got, err = repo.PorterAppEvent().ReadEvent(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading updated event: %v", err)
+	}
+	if got.Status != "SUCCESS" || got.Type != "BUILD" || got.Metadata["commit_sha"] != "a1b2c3" {
+		t.Errorf("expected only the status to be updated, got %+v", got)
+	}
+
+	if err := repo.PorterAppEvent().UpdateEvent(ctx, &models.PorterAppEvent{PorterAppID: 1, Status: "FAILED"}); err == nil {
+		t.Error("expected an error updating an event without an id")
+	}
+}
+
+func testPorterAppEventListAndPaginate(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	target := uuid.New()
+	otherTarget := uuid.New()
+
+	build := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, DeploymentTargetID: target, Type: "BUILD", CreatedAt: eventTime(1)})
+	notification := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, DeploymentTargetID: target, Type: "NOTIFICATION", CreatedAt: eventTime(2)})
+	deploy := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, DeploymentTargetID: target, Type: "DEPLOY", CreatedAt: eventTime(3)})
+	appEvent := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, DeploymentTargetID: target, Type: "APP_EVENT", CreatedAt: eventTime(4)})
+	preDeploy := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, DeploymentTargetID: otherTarget, Type: "PRE_DEPLOY", CreatedAt: eventTime(5)})
+	createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 2, DeploymentTargetID: target, Type: "BUILD", CreatedAt: eventTime(6)})
+
+	// events are listed newest first
+	events, page, err := repo.PorterAppEvent().ListEventsByPorterAppID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing events: %v", err)
+	}
+	expectEventIDs(t, "events of app 1", eventIDs(events), preDeploy.ID, appEvent.ID, deploy.ID, notification.ID, build.ID)
+	expectPage(t, "events of app 1", page, helpers.PaginatedResult{NumPages: 1, CurrentPage: 0, NextPage: 1})
+
+	events, page, err = repo.PorterAppEvent().ListEventsByPorterAppID(ctx, 1, helpers.WithPage(1), helpers.WithPageSize(2))
+	if err != nil {
+		t.Fatalf("unexpected error listing the first page: %v", err)
+	}
+	expectEventIDs(t, "first page", eventIDs(events), preDeploy.ID, appEvent.ID)
+	expectPage(t, "first page", page, helpers.PaginatedResult{NumPages: 3, CurrentPage: 1, NextPage: 2})
+
+	events, page, err = repo.PorterAppEvent().ListEventsByPorterAppID(ctx, 1, helpers.WithPage(3), helpers.WithPageSize(2))
+	if err != nil {
+		t.Fatalf("unexpected error listing the last page: %v", err)
+	}
+	expectEventIDs(t, "last page", eventIDs(events), build.ID)
+	expectPage(t, "last page", page, helpers.PaginatedResult{NumPages: 3, CurrentPage: 3, NextPage: 3})
+
+	events, page, err = repo.PorterAppEvent().ListEventsByPorterAppID(ctx, 1, helpers.WithPage(4), helpers.WithPageSize(2))
+	if err != nil {
+		t.Fatalf("unexpected error listing past the last page: %v", err)
+	}
+	expectEventIDs(t, "past the last page", eventIDs(events))
+	expectPage(t, "past the last page", page, helpers.PaginatedResult{NumPages: 3, CurrentPage: 4, NextPage: 3})
+
+	events, page, err = repo.PorterAppEvent().ListEventsByPorterAppIDAndDeploymentTargetID(ctx, 1, target, helpers.WithPage(1), helpers.WithPageSize(3))
+	if err != nil {
+		t.Fatalf("unexpected error listing events of a deployment target: %v", err)
+	}
+	expectEventIDs(t, "events of a deployment target", eventIDs(events), appEvent.ID, deploy.ID, notification.ID)
+	expectPage(t, "events of a deployment target", page, helpers.PaginatedResult{NumPages: 2, CurrentPage: 1, NextPage: 2})
+
+	// build and deploy events leave out notifications and app events
+	events, page, err = repo.PorterAppEvent().ListBuildDeployEventsByPorterAppIDAndDeploymentTargetID(ctx, 1, target, helpers.WithPage(1))
+	if err != nil {
+		t.Fatalf("unexpected error listing build and deploy events: %v", err)
+	}
+	expectEventIDs(t, "build and deploy events", eventIDs(events), deploy.ID, build.ID)
+	expectPage(t, "build and deploy events", page, helpers.PaginatedResult{NumPages: 1, CurrentPage: 1, NextPage: 1})
+
+	events, _, err = repo.PorterAppEvent().ListEventsByPorterAppID(ctx, 3)
+	if err != nil {
+		t.Fatalf("unexpected error listing events of an app without events: %v", err)
+	}
+	expectEventIDs(t, "events of an app without events", eventIDs(events))
+}
+
+func testPorterAppEventListFiltered(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	failedDeploy := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", Status: "FAILED", CreatedAt: eventTime(1)})
+	build := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "BUILD", Status: "SUCCESS", CreatedAt: eventTime(2)})
+	deploy := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", Status: "SUCCESS", CreatedAt: eventTime(3)})
+	lastDeploy := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", Status: "SUCCESS", CreatedAt: eventTime(4)})
+	createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 2, Type: "DEPLOY", Status: "SUCCESS", CreatedAt: eventTime(5)})
+
+	events, total, err := repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error listing events: %v", err)
+	}
+	expectEventIDs(t, "events of app 1", eventIDs(events), lastDeploy.ID, deploy.ID, build.ID, failedDeploy.ID)
+	if total != 4 {
+		t.Errorf("expected 4 events of app 1, got %d", total)
+	}
+
+	events, total, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Type: "DEPLOY", Status: "SUCCESS"})
+	if err != nil {
+		t.Fatalf("unexpected error listing successful deploys: %v", err)
+	}
+	expectEventIDs(t, "successful deploys", eventIDs(events), lastDeploy.ID, deploy.ID)
+	if total != 2 {
+		t.Errorf("expected 2 successful deploys, got %d", total)
+	}
+
+	// the total counts every matching event, not only those on the page
+	events, total, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Type: "DEPLOY", Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error listing the first page of deploys: %v", err)
+	}
+	expectEventIDs(t, "first page of deploys", eventIDs(events), lastDeploy.ID, deploy.ID)
+	if total != 3 {
+		t.Errorf("expected a total of 3 deploys, got %d", total)
+	}
+
+	events, total, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Type: "DEPLOY", Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("unexpected error listing the last page of deploys: %v", err)
+	}
+	expectEventIDs(t, "last page of deploys", eventIDs(events), failedDeploy.ID)
+	if total != 3 {
+		t.Errorf("expected a total of 3 deploys, got %d", total)
+	}
+
+	events, total, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Limit: 2, Offset: 10})
+	if err != nil {
+		t.Fatalf("unexpected error listing past the last page: %v", err)
+	}
+	expectEventIDs(t, "past the last page", eventIDs(events))
+	if total != 4 {
+		t.Errorf("expected a total of 4 events, got %d", total)
+	}
+
+	events, total, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Status: "CANCELED"})
+	if err != nil {
+		t.Fatalf("unexpected error listing canceled events: %v", err)
+	}
+	expectEventIDs(t, "canceled events", eventIDs(events))
+	if total != 0 {
+		t.Errorf("expected no canceled events, got %d", total)
+	}
+
+	events, total, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 3, repository.PorterAppEventFilter{Limit: 20})
+	if err != nil {
+		t.Fatalf("unexpected error listing events of an app without events: %v", err)
+	}
+	expectEventIDs(t, "events of an app without events", eventIDs(events))
+	if total != 0 {
+		t.Errorf("expected no events of an app without events, got %d", total)
+	}
+}
+
+func testPorterAppEventSearchMessages(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	checkout := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", Status: "SUCCESS", Message: "Fix checkout totals for 100% discounts", CreatedAt: eventTime(1)})
+	cart := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", Status: "FAILED", Message: "Speed up cart_totals query", CreatedAt: eventTime(2)})
+	createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "BUILD", Status: "SUCCESS", CreatedAt: eventTime(3)})
+	createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 2, Type: "DEPLOY", Status: "SUCCESS", Message: "Fix checkout on app 2", CreatedAt: eventTime(4)})
+
+	events, total, err := repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Search: "CHECKOUT"})
+	if err != nil {
+		t.Fatalf("unexpected error searching events: %v", err)
+	}
+	expectEventIDs(t, "events mentioning checkout", eventIDs(events), checkout.ID)
+	if total != 1 {
+		t.Errorf("expected 1 event mentioning checkout, got %d", total)
+	}
+
+	// every word has to match, in any order
+	events, _, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Search: "query cart"})
+	if err != nil {
+		t.Fatalf("unexpected error searching events: %v", err)
+	}
+	expectEventIDs(t, "events mentioning query and cart", eventIDs(events), cart.ID)
+
+	events, _, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Search: "checkout query"})
+	if err != nil {
+		t.Fatalf("unexpected error searching events: %v", err)
+	}
+	expectEventIDs(t, "events mentioning checkout and query", eventIDs(events))
+
+	// wildcards are matched literally
+	events, _, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Search: "100%"})
+	if err != nil {
+		t.Fatalf("unexpected error searching events: %v", err)
+	}
+	expectEventIDs(t, "events mentioning 100%", eventIDs(events), checkout.ID)
+
+	events, _, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Search: "t_t"})
+	if err != nil {
+		t.Fatalf("unexpected error searching events: %v", err)
+	}
+	expectEventIDs(t, "events mentioning t_t", eventIDs(events), cart.ID)
+
+	events, _, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Search: "%"})
+	if err != nil {
+		t.Fatalf("unexpected error searching events: %v", err)
+	}
+	expectEventIDs(t, "events mentioning %", eventIDs(events), checkout.ID)
+
+	events, _, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{Search: "fix", Status: "FAILED"})
+	if err != nil {
+		t.Fatalf("unexpected error searching events: %v", err)
+	}
+	expectEventIDs(t, "failed events mentioning fix", eventIDs(events))
+}
+
+func testPorterAppEventListFilteredByDeploymentTarget(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	legacy := uuid.Nil
+	target := uuid.New()
+
+	legacyBuild := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "BUILD", Status: "SUCCESS", CreatedAt: eventTime(1)})
+	legacyDeploy := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", Status: "FAILED", CreatedAt: eventTime(2)})
+	targetDeploy := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, DeploymentTargetID: target, Type: "DEPLOY", Status: "FAILED", CreatedAt: eventTime(3)})
+
+	events, total, err := repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{DeploymentTargetID: &legacy})
+	if err != nil {
+		t.Fatalf("unexpected error listing legacy events: %v", err)
+	}
+	expectEventIDs(t, "legacy events", eventIDs(events), legacyDeploy.ID, legacyBuild.ID)
+	if total != 2 {
+		t.Errorf("expected 2 legacy events, got %d", total)
+	}
+
+	events, total, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{DeploymentTargetID: &target, Status: "FAILED"})
+	if err != nil {
+		t.Fatalf("unexpected error listing failed events of the deployment target: %v", err)
+	}
+	expectEventIDs(t, "failed events of the deployment target", eventIDs(events), targetDeploy.ID)
+	if total != 1 {
+		t.Errorf("expected 1 failed event of the deployment target, got %d", total)
+	}
+
+	// a negative limit or offset is ignored rather than failing the query
+	events, total, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{DeploymentTargetID: &legacy, Limit: -1, Offset: -20})
+	if err != nil {
+		t.Fatalf("unexpected error listing with a negative limit and offset: %v", err)
+	}
+	expectEventIDs(t, "legacy events with a negative limit and offset", eventIDs(events), legacyDeploy.ID, legacyBuild.ID)
+	if total != 2 {
+		t.Errorf("expected 2 legacy events with a negative limit and offset, got %d", total)
+	}
+
+	unused := uuid.New()
+	events, total, err = repo.PorterAppEvent().ListFilteredEventsByPorterAppID(ctx, 1, repository.PorterAppEventFilter{DeploymentTargetID: &unused, Limit: 20})
+	if err != nil {
+		t.Fatalf("unexpected error listing events of a deployment target without events: %v", err)
+	}
+	expectEventIDs(t, "events of a deployment target without events", eventIDs(events))
+	if total != 0 {
+		t.Errorf("expected no events of a deployment target without events, got %d", total)
+	}
+}
+
+func testPorterAppEventDeploysAndNotifications(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	instance := uuid.New()
+
+	createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", CreatedAt: eventTime(1), Metadata: models.JSONB{"revision": 2, "app_revision_id": "rev-2"}})
+	deploy := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", CreatedAt: eventTime(2), Metadata: models.JSONB{"revision": 3, "app_revision_id": "rev-3"}})
+	build := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "BUILD", CreatedAt: eventTime(3), Metadata: models.JSONB{"revision": 3}})
+	notification := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, AppInstanceID: instance, Type: "NOTIFICATION", CreatedAt: eventTime(4), Metadata: models.JSONB{"id": "n-1", "app_revision_id": "rev-3"}})
+	createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, AppInstanceID: instance, Type: "NOTIFICATION", CreatedAt: eventTime(5), Metadata: models.JSONB{"id": "n-2", "app_revision_id": "rev-2"}})
+
+	got, err := repo.PorterAppEvent().ReadDeployEventByRevision(ctx, 1, 3)
+	if err != nil {
+		t.Fatalf("unexpected error reading deploy event by revision: %v", err)
+	}
+	if got.ID != deploy.ID {
+		t.Errorf("expected deploy event %s by revision, got %s", deploy.ID, got.ID)
+	}
+
+	_, err = repo.PorterAppEvent().ReadDeployEventByRevision(ctx, 1, 4)
+	expectNotFound(t, "reading a missing revision", err)
+
+	got, err = repo.PorterAppEvent().ReadDeployEventByAppRevisionID(ctx, 1, "rev-3")
+	if err != nil {
+		t.Fatalf("unexpected error reading deploy event by app revision id: %v", err)
+	}
+	if got.ID != deploy.ID {
+		t.Errorf("expected deploy event %s by app revision id, got %s", deploy.ID, got.ID)
+	}
+
+	_, err = repo.PorterAppEvent().ReadDeployEventByAppRevisionID(ctx, 2, "rev-3")
+	expectNotFound(t, "reading the app revision of another app", err)
+
+	if _, err := repo.PorterAppEvent().ReadDeployEventByAppRevisionID(ctx, 1, ""); err == nil {
+		t.Error("expected an error reading a deploy event without an app revision id")
+	}
+
+	notifications, err := repo.PorterAppEvent().ReadNotificationsByAppRevisionID(ctx, instance, "rev-3")
+	if err != nil {
+		t.Fatalf("unexpected error reading notifications: %v", err)
+	}
+	expectEventIDs(t, "notifications of rev-3", eventIDs(notifications), notification.ID)
+
+	gotNotification, err := repo.PorterAppEvent().NotificationByID(ctx, "n-1")
+	if err != nil {
+		t.Fatalf("unexpected error reading notification: %v", err)
+	}
+	if gotNotification.ID != notification.ID {
+		t.Errorf("expected notification %s, got %s", notification.ID, gotNotification.ID)
+	}
+
+	// reading a missing notification returns an empty event rather than an error
+	gotNotification, err = repo.PorterAppEvent().NotificationByID(ctx, "n-3")
+	if err != nil {
+		t.Fatalf("unexpected error reading a missing notification: %v", err)
+	}
+	if gotNotification == nil || gotNotification.ID != uuid.Nil {
+		t.Errorf("expected an empty event reading a missing notification, got %v", gotNotification)
+	}
+
+	latest, err := repo.PorterAppEvent().ReadLatestEventByType(ctx, 1, "DEPLOY")
+	if err != nil {
+		t.Fatalf("unexpected error reading latest event: %v", err)
+	}
+	if latest.ID != deploy.ID {
+		t.Errorf("expected latest deploy event %s, got %s", deploy.ID, latest.ID)
+	}
+
+	latest, err = repo.PorterAppEvent().ReadLatestEventByType(ctx, 1, "BUILD")
+	if err != nil {
+		t.Fatalf("unexpected error reading latest build event: %v", err)
+	}
+	if latest.ID != build.ID {
+		t.Errorf("expected latest build event %s, got %s", build.ID, latest.ID)
+	}
+
+	_, err = repo.PorterAppEvent().ReadLatestEventByType(ctx, 1, "PRE_DEPLOY")
+	expectNotFound(t, "reading the latest event of a type without events", err)
+}
+
+func testPorterAppEventCreatedBetween(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	before := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", CreatedAt: eventTime(1)})
+	start := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", CreatedAt: eventTime(2)})
+	build := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 2, Type: "BUILD", CreatedAt: eventTime(3)})
+	end := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 2, Type: "DEPLOY", CreatedAt: eventTime(4)})
+
+	// the range includes its start and excludes its end
+	events, err := repo.PorterAppEvent().ListEventsCreatedBetween(ctx, eventTime(2), eventTime(4), []string{"DEPLOY", "BUILD"})
+	if err != nil {
+		t.Fatalf("unexpected error listing events: %v", err)
+	}
+	expectEventIDs(t, "deploy and build events in range", sortedEventIDs(events), sortedEventIDs([]*models.PorterAppEvent{start, build})...)
+
+	events, err = repo.PorterAppEvent().ListEventsCreatedBetween(ctx, eventTime(2), eventTime(4), []string{"DEPLOY"})
+	if err != nil {
+		t.Fatalf("unexpected error listing deploy events: %v", err)
+	}
+	expectEventIDs(t, "deploy events in range", eventIDs(events), start.ID)
+
+	deleted, err := repo.PorterAppEvent().DeleteEventsCreatedBetween(ctx, eventTime(2), eventTime(4))
+	if err != nil {
+		t.Fatalf("unexpected error deleting events: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 events to be deleted, got %d", deleted)
+	}
+
+	_, err = repo.PorterAppEvent().ReadEvent(ctx, build.ID)
+	expectNotFound(t, "reading a deleted event", err)
+
+	events, err = repo.PorterAppEvent().ListEventsCreatedBetween(ctx, eventTime(0), eventTime(5), []string{"DEPLOY", "BUILD"})
+	if err != nil {
+		t.Fatalf("unexpected error listing remaining events: %v", err)
+	}
+	expectEventIDs(t, "remaining events", sortedEventIDs(events), sortedEventIDs([]*models.PorterAppEvent{before, end})...)
+}
+
+func testPorterAppEventDeleteByPorterApp(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "BUILD", CreatedAt: eventTime(1)})
+	createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY", CreatedAt: eventTime(2)})
+	other := createEvent(t, repo, &models.PorterAppEvent{PorterAppID: 2, Type: "DEPLOY", CreatedAt: eventTime(3)})
+
+	deleted, err := repo.PorterAppEvent().DeleteEventsByPorterAppID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error deleting events: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 events to be deleted, got %d", deleted)
+	}
+
+	events, _, err := repo.PorterAppEvent().ListEventsByPorterAppID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing events of the deleted app: %v", err)
+	}
+	expectEventIDs(t, "events of the deleted app", eventIDs(events))
+
+	if _, err := repo.PorterAppEvent().ReadEvent(ctx, other.ID); err != nil {
+		t.Errorf("expected the events of other apps to be kept, got %v", err)
+	}
+
+	deleted, err = repo.PorterAppEvent().DeleteEventsByPorterAppID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error deleting events again: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("expected no events to be deleted again, got %d", deleted)
+	}
+
+	if _, err := repo.PorterAppEvent().DeleteEventsByPorterAppID(ctx, 0); err == nil {
+		t.Error("expected an error deleting the events of an app without an id")
+	}
+}
+
+func testPorterAppEventCreateInBatches(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	if err := repo.PorterAppEvent().CreateEvents(ctx, nil); err != nil {
+		t.Fatalf("unexpected error creating no events: %v", err)
+	}
+
+	events := []*models.PorterAppEvent{
+		{PorterAppID: 1, Type: "BUILD", CreatedAt: eventTime(1)},
+		{PorterAppID: 1, Type: "DEPLOY", CreatedAt: eventTime(2)},
+		{PorterAppID: 2, Type: "DEPLOY", CreatedAt: eventTime(3)},
+	}
+	if err := repo.PorterAppEvent().CreateEvents(ctx, events); err != nil {
+		t.Fatalf("unexpected error creating events: %v", err)
+	}
+	for _, event := range events {
+		if event.ID == uuid.Nil || event.UpdatedAt.IsZero() {
+			t.Errorf("expected every event to be given an id and an update time, got %+v", event)
+		}
+	}
+
+	listed, _, err := repo.PorterAppEvent().ListEventsByPorterAppID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing events: %v", err)
+	}
+	expectEventIDs(t, "events created in a batch", eventIDs(listed), events[1].ID, events[0].ID)
+
+	// a batch with an event which cannot be created creates none of its events
+	batch := []*models.PorterAppEvent{
+		{PorterAppID: 3, Type: "BUILD"},
+		{ID: events[2].ID, PorterAppID: 3, Type: "DEPLOY"},
+	}
+	if err := repo.PorterAppEvent().CreateEvents(ctx, batch); err == nil {
+		t.Error("expected an error creating a batch with the id of another event")
+	}
+
+	listed, _, err = repo.PorterAppEvent().ListEventsByPorterAppID(ctx, 3)
+	if err != nil {
+		t.Fatalf("unexpected error listing events of the failed batch: %v", err)
+	}
+	expectEventIDs(t, "events of the failed batch", eventIDs(listed))
+
+	if err := repo.PorterAppEvent().CreateEvents(ctx, []*models.PorterAppEvent{{Type: "BUILD"}}); err == nil {
+		t.Error("expected an error creating a batch with an event without a porter app")
+	}
+}
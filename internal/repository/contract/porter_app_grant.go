@@ -0,0 +1,138 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "porter app grant/create, read and list",
+			Covers: []string{
+				"PorterAppGrantRepository.CreatePorterAppGrant",
+				"PorterAppGrantRepository.ReadPorterAppGrant",
+				"PorterAppGrantRepository.ListPorterAppGrantsByProjectID",
+				"PorterAppGrantRepository.ListMatchingPorterAppGrants",
+			},
+			Run: testPorterAppGrantCreateReadAndList,
+		},
+		Case{
+			Name: "porter app grant/delete",
+			Covers: []string{
+				"PorterAppGrantRepository.DeletePorterAppGrant",
+			},
+			Run: testPorterAppGrantDelete,
+		},
+	)
+}
+
+func createPorterAppGrant(t *testing.T, repo repository.Repository, grant *models.PorterAppGrant) *models.PorterAppGrant {
+	t.Helper()
+
+	grant, err := repo.PorterAppGrant().CreatePorterAppGrant(context.Background(), grant)
+	if err != nil {
+		t.Fatalf("unexpected error creating app grant: %v", err)
+	}
+
+	return grant
+}
+
+func porterAppGrantIDs(grants []*models.PorterAppGrant) []uint {
+	ids := make([]uint, 0, len(grants))
+	for _, grant := range grants {
+		ids = append(ids, grant.ID)
+	}
+
+	return ids
+}
+
+func testPorterAppGrantCreateReadAndList(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	if _, err := repo.PorterAppGrant().CreatePorterAppGrant(ctx, &models.PorterAppGrant{AppPattern: "web", UserID: 1}); err == nil {
+		t.Error("expected an error creating a grant without a project")
+	}
+	if _, err := repo.PorterAppGrant().CreatePorterAppGrant(ctx, &models.PorterAppGrant{ProjectID: 1, AppPattern: "web", UserID: 1, Role: "developer"}); err == nil {
+		t.Error("expected an error creating a grant for both a user and a role")
+	}
+
+	exact := createPorterAppGrant(t, repo, &models.PorterAppGrant{ProjectID: 1, AppPattern: "web", UserID: 1, Permission: "deploy"})
+	prefix := createPorterAppGrant(t, repo, &models.PorterAppGrant{ProjectID: 1, AppPattern: "team-a-*", UserID: 1, Permission: "read"})
+	role := createPorterAppGrant(t, repo, &models.PorterAppGrant{ProjectID: 1, AppPattern: "*-api", Role: "developer", Permission: "deploy"})
+	other := createPorterAppGrant(t, repo, &models.PorterAppGrant{ProjectID: 1, AppPattern: "*", UserID: 2, Permission: "deploy"})
+	createPorterAppGrant(t, repo, &models.PorterAppGrant{ProjectID: 2, AppPattern: "*", UserID: 1, Permission: "deploy"})
+
+	read, err := repo.PorterAppGrant().ReadPorterAppGrant(ctx, 1, prefix.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading grant: %v", err)
+	}
+	if read.AppPattern != "team-a-*" || read.UserID != 1 || read.Permission != "read" {
+		t.Errorf("unexpected grant: %+v", read)
+	}
+
+	if _, err := repo.PorterAppGrant().ReadPorterAppGrant(ctx, 2, prefix.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected a grant of another project not to be found, got %v", err)
+	}
+
+	grants, err := repo.PorterAppGrant().ListPorterAppGrantsByProjectID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing grants: %v", err)
+	}
+	expectIDs(t, "grants of project 1", porterAppGrantIDs(grants), exact.ID, prefix.ID, role.ID, other.ID)
+
+	tests := []struct {
+		appName string
+		userID  uint
+		role    string
+		want    []uint
+	}{
+		{appName: "web", userID: 1, role: "developer", want: []uint{exact.ID}},
+		{appName: "team-a-api", userID: 1, role: "developer", want: []uint{prefix.ID, role.ID}},
+		{appName: "team-a-api", userID: 1, role: "viewer", want: []uint{prefix.ID}},
+		{appName: "team-b-api", userID: 3, role: "developer", want: []uint{role.ID}},
+		{appName: "team-b-api", userID: 3, role: "viewer", want: []uint{}},
+		{appName: "team-b-api", want: []uint{role.ID, other.ID}},
+		{appName: "webapp", userID: 1, want: []uint{}},
+	}
+
+	for _, tt := range tests {
+		grants, err := repo.PorterAppGrant().ListMatchingPorterAppGrants(ctx, 1, tt.appName, tt.userID, tt.role)
+		if err != nil {
+			t.Fatalf("unexpected error listing matching grants: %v", err)
+		}
+		expectIDs(t, "grants matching "+tt.appName+" for "+tt.role, porterAppGrantIDs(grants), tt.want...)
+	}
+}
+
+func testPorterAppGrantDelete(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	grant := createPorterAppGrant(t, repo, &models.PorterAppGrant{ProjectID: 1, AppPattern: "web", UserID: 1, Permission: "deploy"})
+	kept := createPorterAppGrant(t, repo, &models.PorterAppGrant{ProjectID: 1, AppPattern: "api", UserID: 1, Permission: "deploy"})
+
+	if err := repo.PorterAppGrant().DeletePorterAppGrant(ctx, grant); err != nil {
+		t.Fatalf("unexpected error deleting grant: %v", err)
+	}
+
+	if _, err := repo.PorterAppGrant().ReadPorterAppGrant(ctx, 1, grant.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected the deleted grant not to be found, got %v", err)
+	}
+
+	grants, err := repo.PorterAppGrant().ListMatchingPorterAppGrants(ctx, 1, "web", 1, "")
+	if err != nil {
+		t.Fatalf("unexpected error listing matching grants: %v", err)
+	}
+	expectIDs(t, "grants matching web", porterAppGrantIDs(grants))
+
+	grants, err = repo.PorterAppGrant().ListPorterAppGrantsByProjectID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing grants: %v", err)
+	}
+	expectIDs(t, "grants of project 1", porterAppGrantIDs(grants), kept.ID)
+}
@@ -0,0 +1,56 @@
+package contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "pw reset token/invalidate by email and purpose",
+			Covers: []string{
+				"PWResetTokenRepository.InvalidatePWResetTokensByEmail",
+			},
+			Run: testPWResetTokenInvalidateByEmail,
+		},
+	)
+}
+
+func testPWResetTokenInvalidateByEmail(t *testing.T, repo repository.Repository) {
+	expiry := time.Now().Add(30 * time.Minute)
+
+	var tokens []*models.PWResetToken
+	for _, token := range []*models.PWResetToken{
+		{Email: "user@example.com", Purpose: models.PWResetTokenPurpose_EmailVerification},
+		{Email: "user@example.com", Purpose: models.PWResetTokenPurpose_EmailVerification},
+		{Email: "user@example.com", Purpose: models.PWResetTokenPurpose_PasswordReset},
+		{Email: "other@example.com", Purpose: models.PWResetTokenPurpose_EmailVerification},
+	} {
+		token.IsValid = true
+		token.Expiry = &expiry
+
+		created, err := repo.PWResetToken().CreatePWResetToken(token)
+		if err != nil {
+			t.Fatalf("unexpected error creating pw reset token: %v", err)
+		}
+		tokens = append(tokens, created)
+	}
+
+	if err := repo.PWResetToken().InvalidatePWResetTokensByEmail("user@example.com", models.PWResetTokenPurpose_EmailVerification); err != nil {
+		t.Fatalf("unexpected error invalidating pw reset tokens: %v", err)
+	}
+
+	for i, want := range []bool{false, false, true, true} {
+		token, err := repo.PWResetToken().ReadPWResetToken(tokens[i].ID)
+		if err != nil {
+			t.Fatalf("unexpected error reading pw reset token: %v", err)
+		}
+		if token.IsValid != want {
+			t.Errorf("expected the validity of the %s token of %s to be %t, got %t", token.Purpose, token.Email, want, token.IsValid)
+		}
+	}
+}
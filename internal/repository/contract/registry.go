@@ -0,0 +1,168 @@
+package contract
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "registry/create, read and delete",
+			Covers: []string{
+				"RegistryRepository.CreateRegistry",
+				"RegistryRepository.ReadRegistry",
+				"RegistryRepository.ReadRegistryByInfraID",
+				"RegistryRepository.ListRegistriesByProjectID",
+				"RegistryRepository.DeleteRegistry",
+			},
+			Run: testRegistryCreateReadAndDelete,
+		},
+		Case{
+			Name: "registry/credential status",
+			Covers: []string{
+				"RegistryRepository.UpdateRegistryCredentialStatus",
+				"RegistryRepository.ListRegistriesByCredentialStatus",
+			},
+			Run: testRegistryCredentialStatus,
+		},
+	)
+}
+
+func createProject(t *testing.T, repo repository.Repository, name string) *models.Project {
+	t.Helper()
+
+	project, err := repo.Project().CreateProject(&models.Project{Name: name})
+	if err != nil {
+		t.Fatalf("unexpected error creating project: %v", err)
+	}
+
+	return project
+}
+
+func createRegistry(t *testing.T, repo repository.Repository, reg *models.Registry) *models.Registry {
+	t.Helper()
+
+	reg, err := repo.Registry().CreateRegistry(reg)
+	if err != nil {
+		t.Fatalf("unexpected error creating registry: %v", err)
+	}
+
+	return reg
+}
+
+func registryIDs(regs []*models.Registry) []uint {
+	ids := make([]uint, 0, len(regs))
+	for _, reg := range regs {
+		ids = append(ids, reg.ID)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+func testRegistryCreateReadAndDelete(t *testing.T, repo repository.Repository) {
+	project := createProject(t, repo, "storefront")
+	otherProject := createProject(t, repo, "billing")
+
+	ecr := createRegistry(t, repo, &models.Registry{ProjectID: project.ID, Name: "ecr", URL: "123456789.dkr.ecr.us-east-1.amazonaws.com", InfraID: 4})
+	gar := createRegistry(t, repo, &models.Registry{ProjectID: project.ID, Name: "gar", URL: "us-central1-docker.pkg.dev/storefront/images"})
+	other := createRegistry(t, repo, &models.Registry{ProjectID: otherProject.ID, Name: "ecr", URL: "987654321.dkr.ecr.us-east-1.amazonaws.com", InfraID: 5})
+
+	got, err := repo.Registry().ReadRegistry(project.ID, ecr.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading registry: %v", err)
+	}
+	if got.ID != ecr.ID || got.Name != "ecr" || got.URL != "123456789.dkr.ecr.us-east-1.amazonaws.com" {
+		t.Errorf("expected the created registry, got %+v", got)
+	}
+
+	got, err = repo.Registry().ReadRegistryByInfraID(project.ID, 4)
+	if err != nil {
+		t.Fatalf("unexpected error reading registry by infra id: %v", err)
+	}
+	if got.ID != ecr.ID {
+		t.Errorf("expected registry %d by infra id, got %d", ecr.ID, got.ID)
+	}
+
+	// the registry of another project must look like it does not exist
+	_, err = repo.Registry().ReadRegistry(project.ID, other.ID)
+	expectNotFound(t, "reading another project's registry", err)
+
+	_, err = repo.Registry().ReadRegistryByInfraID(project.ID, 5)
+	expectNotFound(t, "reading another project's registry by infra id", err)
+
+	regs, err := repo.Registry().ListRegistriesByProjectID(project.ID)
+	if err != nil {
+		t.Fatalf("unexpected error listing registries: %v", err)
+	}
+	expectIDs(t, "registries of the project", registryIDs(regs), ecr.ID, gar.ID)
+
+	if err := repo.Registry().DeleteRegistry(gar); err != nil {
+		t.Fatalf("unexpected error deleting registry: %v", err)
+	}
+
+	_, err = repo.Registry().ReadRegistry(project.ID, gar.ID)
+	expectNotFound(t, "reading a deleted registry", err)
+
+	regs, err = repo.Registry().ListRegistriesByProjectID(project.ID)
+	if err != nil {
+		t.Fatalf("unexpected error listing registries after delete: %v", err)
+	}
+	expectIDs(t, "registries after delete", registryIDs(regs), ecr.ID)
+}
+
+func testRegistryCredentialStatus(t *testing.T, repo repository.Repository) {
+	project := createProject(t, repo, "storefront")
+
+	ecr := createRegistry(t, repo, &models.Registry{ProjectID: project.ID, Name: "ecr", URL: "123456789.dkr.ecr.us-east-1.amazonaws.com"})
+	gar := createRegistry(t, repo, &models.Registry{ProjectID: project.ID, Name: "gar", URL: "us-central1-docker.pkg.dev/storefront/images"})
+
+	checkedAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	// recording the status leaves the other fields of the registry unchanged
+	if err := repo.Registry().UpdateRegistryCredentialStatus(&models.Registry{
+		Model:               ecr.Model,
+		CredentialStatus:    "invalid",
+		CredentialError:     "token expired",
+		CredentialCheckedAt: &checkedAt,
+	}); err != nil {
+		t.Fatalf("unexpected error updating credential status: %v", err)
+	}
+
+	regs, err := repo.Registry().ListRegistriesByCredentialStatus("invalid")
+	if err != nil {
+		t.Fatalf("unexpected error listing registries by credential status: %v", err)
+	}
+	expectIDs(t, "registries with invalid credentials", registryIDs(regs), ecr.ID)
+
+	got, err := repo.Registry().ReadRegistry(project.ID, ecr.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading registry: %v", err)
+	}
+	if got.Name != "ecr" || got.CredentialStatus != "invalid" || got.CredentialError != "token expired" {
+		t.Errorf("expected only the credential status to be updated, got %+v", got)
+	}
+	if got.CredentialCheckedAt == nil || !got.CredentialCheckedAt.Equal(checkedAt) {
+		t.Errorf("expected the credentials to be checked at %s, got %v", checkedAt, got.CredentialCheckedAt)
+	}
+
+	regs, err = repo.Registry().ListRegistriesByCredentialStatus("valid")
+	if err != nil {
+		t.Fatalf("unexpected error listing registries with valid credentials: %v", err)
+	}
+	expectIDs(t, "registries with valid credentials", registryIDs(regs))
+
+	got, err = repo.Registry().ReadRegistry(project.ID, gar.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading unchecked registry: %v", err)
+	}
+	if got.CredentialStatus != "" || got.CredentialCheckedAt != nil {
+		t.Errorf("expected the registry which was not checked to have no credential status, got %q", got.CredentialStatus)
+	}
+}
@@ -0,0 +1,129 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "session/delete expired",
+			Covers: []string{
+				"SessionRepository.DeleteExpiredSessions",
+				"SessionRepository.CountSessions",
+			},
+			Run: testSessionDeleteExpired,
+		},
+		Case{
+			Name: "session/touch",
+			Covers: []string{
+				"SessionRepository.TouchSession",
+			},
+			Run: testSessionTouch,
+		},
+	)
+}
+
+func testSessionDeleteExpired(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, session := range []*models.Session{
+		{Key: "expired-1", ExpiresAt: now.Add(-48 * time.Hour)},
+		{Key: "live", ExpiresAt: now.Add(time.Hour)},
+		{Key: "expired-2", ExpiresAt: now.Add(-24 * time.Hour)},
+		{Key: "expired-3", ExpiresAt: now.Add(-time.Hour)},
+	} {
+		if _, err := repo.Session().CreateSession(session); err != nil {
+			t.Fatalf("unexpected error creating session: %v", err)
+		}
+	}
+
+	deleted, err := repo.Session().DeleteExpiredSessions(ctx, now, 2)
+	if err != nil {
+		t.Fatalf("unexpected error deleting expired sessions: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected the limit of 2 sessions to be deleted, got %d", deleted)
+	}
+
+	deleted, err = repo.Session().DeleteExpiredSessions(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("unexpected error deleting expired sessions: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected the remaining expired session to be deleted, got %d", deleted)
+	}
+
+	count, err := repo.Session().CountSessions(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error counting sessions: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 session to be left, got %d", count)
+	}
+
+	if _, err := repo.Session().SelectSession(&models.Session{Key: "live"}); err != nil {
+		t.Errorf("expected the live session to be kept, got %v", err)
+	}
+	if _, err := repo.Session().SelectSession(&models.Session{Key: "expired-2"}); err == nil {
+		t.Error("expected the expired session to be deleted")
+	}
+}
+
+func testSessionTouch(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	idleExpiry := now.Add(-time.Minute)
+
+	for _, session := range []*models.Session{
+		{Key: "idle", ExpiresAt: idleExpiry, LastUsedAt: now.Add(-time.Hour)},
+		{Key: "untouched", ExpiresAt: idleExpiry, LastUsedAt: now.Add(-time.Hour)},
+	} {
+		if _, err := repo.Session().CreateSession(session); err != nil {
+			t.Fatalf("unexpected error creating session: %v", err)
+		}
+	}
+
+	// a request within the idle timeout moves the expiry of its session to the end of the next idle timeout
+	idleTimeoutExpiry := now.Add(30 * time.Minute)
+	if err := repo.Session().TouchSession(ctx, "idle", now, idleTimeoutExpiry); err != nil {
+		t.Fatalf("unexpected error touching session: %v", err)
+	}
+
+	touched, err := repo.Session().SelectSession(&models.Session{Key: "idle"})
+	if err != nil {
+		t.Fatalf("unexpected error selecting session: %v", err)
+	}
+	if !touched.LastUsedAt.Equal(now) {
+		t.Errorf("expected the session to be last used at %v, got %v", now, touched.LastUsedAt)
+	}
+	if !touched.ExpiresAt.Equal(idleTimeoutExpiry) {
+		t.Errorf("expected the session to expire at %v, got %v", idleTimeoutExpiry, touched.ExpiresAt)
+	}
+
+	untouched, err := repo.Session().SelectSession(&models.Session{Key: "untouched"})
+	if err != nil {
+		t.Fatalf("unexpected error selecting session: %v", err)
+	}
+	if !untouched.ExpiresAt.Equal(idleExpiry) {
+		t.Errorf("expected the expiry of another session to be kept, got %v", untouched.ExpiresAt)
+	}
+
+	// only the session which was not used within its idle timeout has expired
+	deleted, err := repo.Session().DeleteExpiredSessions(ctx, now, 0)
+	if err != nil {
+		t.Fatalf("unexpected error deleting expired sessions: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected the idle session to be deleted, got %d deleted", deleted)
+	}
+	if _, err := repo.Session().SelectSession(&models.Session{Key: "idle"}); err != nil {
+		t.Errorf("expected the touched session to be kept, got %v", err)
+	}
+}
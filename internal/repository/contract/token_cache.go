@@ -0,0 +1,68 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "token cache/entries in use are never deleted",
+			Covers: []string{
+				"TokenCacheRepository.DeleteExpiredTokenCaches",
+				"TokenCacheRepository.CountTokenCaches",
+			},
+			Run: testTokenCacheInUseEntriesKept,
+		},
+	)
+}
+
+func testTokenCacheInUseEntriesKept(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	project := createProject(t, repo, "token-caches")
+	expired := ints.TokenCache{Token: []byte("token"), Expiry: time.Now().Add(-30 * 24 * time.Hour)}
+
+	if _, err := repo.Cluster().CreateCluster(&models.Cluster{ProjectID: project.ID, Name: "cluster", TokenCache: ints.ClusterTokenCache{TokenCache: expired}}, nil); err != nil {
+		t.Fatalf("unexpected error creating cluster: %v", err)
+	}
+	createRegistry(t, repo, &models.Registry{ProjectID: project.ID, Name: "registry", TokenCache: ints.RegTokenCache{TokenCache: expired}})
+	if _, err := repo.HelmRepo().CreateHelmRepo(&models.HelmRepo{ProjectID: project.ID, Name: "helm-repo", TokenCache: ints.HelmRepoTokenCache{TokenCache: expired}}); err != nil {
+		t.Fatalf("unexpected error creating helm repo: %v", err)
+	}
+
+	want := map[repository.TokenCacheTable]int64{
+		repository.TokenCacheTable_Base:     0,
+		repository.TokenCacheTable_Cluster:  1,
+		repository.TokenCacheTable_Registry: 1,
+		repository.TokenCacheTable_HelmRepo: 1,
+	}
+
+	for _, table := range repository.TokenCacheTables {
+		deleted, err := repo.TokenCache().DeleteExpiredTokenCaches(ctx, table, time.Now(), 100)
+		if err != nil {
+			t.Fatalf("unexpected error deleting expired %s: %v", table, err)
+		}
+		if deleted != 0 {
+			t.Errorf("expected the %s in use to be kept, got %d deleted", table, deleted)
+		}
+
+		count, err := repo.TokenCache().CountTokenCaches(ctx, table)
+		if err != nil {
+			t.Fatalf("unexpected error counting %s: %v", table, err)
+		}
+		if count != want[table] {
+			t.Errorf("expected %d %s, got %d", want[table], table, count)
+		}
+	}
+
+	if _, err := repo.TokenCache().CountTokenCaches(ctx, repository.TokenCacheTable("sessions")); err == nil {
+		t.Error("expected an error counting a table which does not store token caches")
+	}
+}
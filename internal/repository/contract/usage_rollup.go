@@ -0,0 +1,100 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "usage rollup/replace and list",
+			Covers: []string{
+				"UsageRollupRepository.ReplaceUsageRollupsForDay",
+				"UsageRollupRepository.ListUsageRollupDays",
+				"UsageRollupRepository.MarkUsageRollupDayPruned",
+				"UsageRollupRepository.ListUsageRollupsByProjectID",
+				"UsageRollupRepository.ListUsageRollupTotalsByDay",
+			},
+			Run: testUsageRollupReplaceAndList,
+		},
+	)
+}
+
+func testUsageRollupReplaceAndList(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	first := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 1)
+	rolledUpAt := second.Add(time.Hour)
+
+	err := repo.UsageRollup().ReplaceUsageRollupsForDay(ctx, first, rolledUpAt, []*models.UsageRollup{
+		{ProjectID: 1, PorterAppID: 1, AppName: "web", Day: first, Deploys: 9, DeployFailures: 9},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error replacing rollups: %v", err)
+	}
+
+	// the rollups of a day are replaced as a whole when the day is rolled up again
+	err = repo.UsageRollup().ReplaceUsageRollupsForDay(ctx, first, rolledUpAt, []*models.UsageRollup{
+		{ProjectID: 1, PorterAppID: 1, AppName: "web", Day: first, Deploys: 3, DeployFailures: 1, HelmOperationSeconds: 30},
+		{ProjectID: 2, PorterAppID: 2, AppName: "api", Day: first, Deploys: 2, Builds: 1, BuildSeconds: 60},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error replacing rollups: %v", err)
+	}
+
+	err = repo.UsageRollup().ReplaceUsageRollupsForDay(ctx, second, rolledUpAt, []*models.UsageRollup{
+		{ProjectID: 1, PorterAppID: 1, AppName: "web", Day: second, Deploys: 1, HelmOperationSeconds: 10},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error replacing rollups: %v", err)
+	}
+
+	if err := repo.UsageRollup().ReplaceUsageRollupsForDay(ctx, second, rolledUpAt, []*models.UsageRollup{{Day: first}}); err == nil {
+		t.Error("expected a rollup for another day to be rejected")
+	}
+
+	rollups, err := repo.UsageRollup().ListUsageRollupsByProjectID(ctx, 1, first, second)
+	if err != nil {
+		t.Fatalf("unexpected error listing rollups: %v", err)
+	}
+	if len(rollups) != 2 || rollups[0].Deploys != 3 || rollups[1].Deploys != 1 {
+		t.Errorf("expected the replaced rollups of the project on both days, got %v", rollups)
+	}
+
+	totals, err := repo.UsageRollup().ListUsageRollupTotalsByDay(ctx, first, second)
+	if err != nil {
+		t.Fatalf("unexpected error listing rollup totals: %v", err)
+	}
+	if len(totals) != 2 {
+		t.Fatalf("expected a total for each day, got %d", len(totals))
+	}
+	if !totals[0].Day.Equal(first) || totals[0].Deploys != 5 || totals[0].DeployFailures != 1 || totals[0].HelmOperationSeconds != 30 || totals[0].Builds != 1 || totals[0].BuildSeconds != 60 {
+		t.Errorf("expected the usage of every app to be summed on the first day, got %+v", totals[0])
+	}
+	if !totals[1].Day.Equal(second) || totals[1].Deploys != 1 {
+		t.Errorf("expected the usage of the second day, got %+v", totals[1])
+	}
+
+	if err := repo.UsageRollup().MarkUsageRollupDayPruned(ctx, first, rolledUpAt); err != nil {
+		t.Fatalf("unexpected error marking day pruned: %v", err)
+	}
+	if err := repo.UsageRollup().MarkUsageRollupDayPruned(ctx, second.AddDate(0, 0, 1), rolledUpAt); err == nil {
+		t.Error("expected a day which was not rolled up not to be marked pruned")
+	}
+
+	days, err := repo.UsageRollup().ListUsageRollupDays(ctx, first, second)
+	if err != nil {
+		t.Fatalf("unexpected error listing rolled up days: %v", err)
+	}
+	if len(days) != 2 || !days[0].Day.Equal(first) || !days[1].Day.Equal(second) {
+		t.Fatalf("expected both days to be rolled up, got %v", days)
+	}
+	if days[0].EventsPrunedAt == nil || days[1].EventsPrunedAt != nil {
+		t.Errorf("expected only the first day to be pruned, got %v and %v", days[0].EventsPrunedAt, days[1].EventsPrunedAt)
+	}
+}
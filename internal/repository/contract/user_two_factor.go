@@ -0,0 +1,93 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "user two factor/create, replace, read and delete",
+			Covers: []string{
+				"UserTwoFactorRepository.CreateOrUpdateUserTwoFactor",
+				"UserTwoFactorRepository.ReadUserTwoFactor",
+				"UserTwoFactorRepository.DeleteUserTwoFactor",
+			},
+			Run: testUserTwoFactorLifecycle,
+		},
+	)
+}
+
+func testUserTwoFactorLifecycle(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	_, err := repo.UserTwoFactor().ReadUserTwoFactor(ctx, 1)
+	expectNotFound(t, "reading the second factor of a user who has not enrolled", err)
+
+	secret := []byte("12345678901234567890")
+	created, err := repo.UserTwoFactor().CreateOrUpdateUserTwoFactor(ctx, &models.UserTwoFactor{UserID: 1, Secret: secret})
+	if err != nil {
+		t.Fatalf("unexpected error creating user two factor: %v", err)
+	}
+	if created.ID == 0 {
+		t.Fatal("expected the created user two factor to have an id")
+	}
+	if !bytes.Equal(created.Secret, secret) {
+		t.Errorf("expected the caller to keep the plaintext secret, got %q", created.Secret)
+	}
+
+	lockedUntil := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	replaced, err := repo.UserTwoFactor().CreateOrUpdateUserTwoFactor(ctx, &models.UserTwoFactor{
+		UserID:         1,
+		Secret:         secret,
+		Enabled:        true,
+		BackupCodes:    models.UserBackupCodes{"hash-1", "hash-2"},
+		LastCounter:    41152263,
+		FailedAttempts: 2,
+		LockedUntil:    &lockedUntil,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error replacing user two factor: %v", err)
+	}
+	if replaced.ID != created.ID {
+		t.Errorf("expected the second factor of a user to be replaced in place, got id %d for %d", replaced.ID, created.ID)
+	}
+
+	read, err := repo.UserTwoFactor().ReadUserTwoFactor(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error reading user two factor: %v", err)
+	}
+	if !bytes.Equal(read.Secret, secret) {
+		t.Errorf("expected the secret to be read decrypted, got %q", read.Secret)
+	}
+	if !read.Enabled || read.LastCounter != 41152263 || read.FailedAttempts != 2 {
+		t.Errorf("unexpected user two factor %+v", read)
+	}
+	if len(read.BackupCodes) != 2 || read.BackupCodes[0] != "hash-1" || read.BackupCodes[1] != "hash-2" {
+		t.Errorf("expected the backup codes to be read, got %v", read.BackupCodes)
+	}
+	if read.LockedUntil == nil || !read.LockedUntil.Equal(lockedUntil) {
+		t.Errorf("expected the lockout to be read, got %v", read.LockedUntil)
+	}
+
+	_, err = repo.UserTwoFactor().ReadUserTwoFactor(ctx, 2)
+	expectNotFound(t, "reading the second factor of another user", err)
+
+	if err := repo.UserTwoFactor().DeleteUserTwoFactor(ctx, read); err != nil {
+		t.Fatalf("unexpected error deleting user two factor: %v", err)
+	}
+
+	_, err = repo.UserTwoFactor().ReadUserTwoFactor(ctx, 1)
+	expectNotFound(t, "reading a deleted second factor", err)
+
+	// a user who disabled their second factor can enroll again
+	if _, err := repo.UserTwoFactor().CreateOrUpdateUserTwoFactor(ctx, &models.UserTwoFactor{UserID: 1, Secret: secret}); err != nil {
+		t.Fatalf("unexpected error enrolling again: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func init() {
+	register(
+		Case{
+			Name: "webhook delivery/create, read and list",
+			Covers: []string{
+				"WebhookDeliveryRepository.CreateWebhookDelivery",
+				"WebhookDeliveryRepository.ReadWebhookDelivery",
+				"WebhookDeliveryRepository.ListWebhookDeliveries",
+			},
+			Run: testWebhookDeliveryCreateReadAndList,
+		},
+		Case{
+			Name: "webhook delivery/count and delete",
+			Covers: []string{
+				"WebhookDeliveryRepository.CountWebhookDeliveries",
+				"WebhookDeliveryRepository.CountWebhookRedeliveries",
+				"WebhookDeliveryRepository.DeleteWebhookDeliveriesBefore",
+			},
+			Run: testWebhookDeliveryCountAndDelete,
+		},
+	)
+}
+
+func createWebhookDelivery(t *testing.T, repo repository.Repository, delivery *models.WebhookDelivery) *models.WebhookDelivery {
+	t.Helper()
+
+	delivery, err := repo.WebhookDelivery().CreateWebhookDelivery(context.Background(), delivery)
+	if err != nil {
+		t.Fatalf("unexpected error creating webhook delivery: %v", err)
+	}
+
+	return delivery
+}
+
+func webhookDeliveryIDs(deliveries []*models.WebhookDelivery) []uint {
+	ids := make([]uint, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.ID)
+	}
+
+	return ids
+}
+
+func testWebhookDeliveryCreateReadAndList(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	if _, err := repo.WebhookDelivery().CreateWebhookDelivery(ctx, &models.WebhookDelivery{LogAlertRuleID: 1, EventID: "event"}); err == nil {
+		t.Error("expected an error creating a delivery without a project")
+	}
+	if _, err := repo.WebhookDelivery().CreateWebhookDelivery(ctx, &models.WebhookDelivery{ProjectID: 1, LogAlertRuleID: 1}); err == nil {
+		t.Error("expected an error creating a delivery without an event")
+	}
+
+	failed := createWebhookDelivery(t, repo, &models.WebhookDelivery{
+		ProjectID:      1,
+		LogAlertRuleID: 1,
+		EventID:        "event-1",
+		ChainID:        "chain-1",
+		Attempt:        1,
+		TargetURL:      "https://example.com/hook",
+		RequestHeaders: models.JSONB{"Idempotency-Key": "event-1"},
+		BodySHA256:     "abc",
+		Payload:        []byte(`{"rule_id":1}`),
+		ResponseCode:   502,
+		LatencyMS:      20,
+	})
+	succeeded := createWebhookDelivery(t, repo, &models.WebhookDelivery{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-1", ChainID: "chain-1", Attempt: 2, ResponseCode: 200, Succeeded: true})
+	otherRule := createWebhookDelivery(t, repo, &models.WebhookDelivery{ProjectID: 1, LogAlertRuleID: 2, EventID: "event-2", ChainID: "chain-2", Attempt: 1})
+	otherProject := createWebhookDelivery(t, repo, &models.WebhookDelivery{ProjectID: 2, LogAlertRuleID: 1, EventID: "event-3", ChainID: "chain-3", Attempt: 1})
+
+	got, err := repo.WebhookDelivery().ReadWebhookDelivery(ctx, 1, 1, failed.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading delivery: %v", err)
+	}
+	if got.EventID != "event-1" || got.Attempt != 1 || got.ResponseCode != 502 || string(got.Payload) != `{"rule_id":1}` || got.RequestHeaders["Idempotency-Key"] != "event-1" {
+		t.Errorf("expected the created delivery, got %+v", got)
+	}
+
+	// the delivery of another rule or project must look like it does not exist
+	_, err = repo.WebhookDelivery().ReadWebhookDelivery(ctx, 1, 1, otherRule.ID)
+	expectNotFound(t, "reading another rule's delivery", err)
+	_, err = repo.WebhookDelivery().ReadWebhookDelivery(ctx, 1, 1, otherProject.ID)
+	expectNotFound(t, "reading another project's delivery", err)
+
+	deliveries, err := repo.WebhookDelivery().ListWebhookDeliveries(ctx, 1, 1, repository.WebhookDeliveryFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error listing deliveries: %v", err)
+	}
+	expectIDs(t, "listing deliveries newest first", webhookDeliveryIDs(deliveries), succeeded.ID, failed.ID)
+
+	isSucceeded := false
+	deliveries, err = repo.WebhookDelivery().ListWebhookDeliveries(ctx, 1, 1, repository.WebhookDeliveryFilter{Succeeded: &isSucceeded})
+	if err != nil {
+		t.Fatalf("unexpected error listing failed deliveries: %v", err)
+	}
+	expectIDs(t, "listing failed deliveries", webhookDeliveryIDs(deliveries), failed.ID)
+
+	deliveries, err = repo.WebhookDelivery().ListWebhookDeliveries(ctx, 1, 1, repository.WebhookDeliveryFilter{Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error listing deliveries with a limit: %v", err)
+	}
+	expectIDs(t, "listing deliveries with a limit", webhookDeliveryIDs(deliveries), succeeded.ID)
+}
+
+func testWebhookDeliveryCountAndDelete(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	old := createWebhookDelivery(t, repo, &models.WebhookDelivery{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-1", ChainID: "chain-1", Attempt: 1, Succeeded: true, CreatedAt: now.Add(-48 * time.Hour)})
+	createWebhookDelivery(t, repo, &models.WebhookDelivery{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-2", ChainID: "chain-2", Attempt: 1, CreatedAt: now.Add(-time.Hour)})
+	createWebhookDelivery(t, repo, &models.WebhookDelivery{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-2", ChainID: "chain-2", Attempt: 2, Succeeded: true, CreatedAt: now.Add(-time.Hour)})
+	// a redelivery which took two attempts counts once
+	createWebhookDelivery(t, repo, &models.WebhookDelivery{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-2", ChainID: "chain-3", Attempt: 1, Replay: true, CreatedAt: now.Add(-time.Minute)})
+	createWebhookDelivery(t, repo, &models.WebhookDelivery{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-2", ChainID: "chain-3", Attempt: 2, Replay: true, Succeeded: true, CreatedAt: now.Add(-time.Minute)})
+	createWebhookDelivery(t, repo, &models.WebhookDelivery{ProjectID: 1, LogAlertRuleID: 2, EventID: "event-3", ChainID: "chain-4", Attempt: 1, Replay: true, CreatedAt: now.Add(-time.Minute)})
+
+	attempts, succeeded, err := repo.WebhookDelivery().CountWebhookDeliveries(ctx, 1, 1, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error counting deliveries: %v", err)
+	}
+	if attempts != 4 || succeeded != 2 {
+		t.Errorf("expected 4 attempts in the last day of which 2 succeeded, got %d and %d", attempts, succeeded)
+	}
+
+	attempts, succeeded, err = repo.WebhookDelivery().CountWebhookDeliveries(ctx, 1, 3, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error counting deliveries of a rule without any: %v", err)
+	}
+	if attempts != 0 || succeeded != 0 {
+		t.Errorf("expected no attempts for a rule without deliveries, got %d and %d", attempts, succeeded)
+	}
+
+	redeliveries, err := repo.WebhookDelivery().CountWebhookRedeliveries(ctx, 1, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error counting redeliveries: %v", err)
+	}
+	if redeliveries != 1 {
+		t.Errorf("expected 1 redelivery in the last hour, got %d", redeliveries)
+	}
+
+	deleted, err := repo.WebhookDelivery().DeleteWebhookDeliveriesBefore(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error deleting old deliveries: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected 1 delivery to be deleted, got %d", deleted)
+	}
+
+	_, err = repo.WebhookDelivery().ReadWebhookDelivery(ctx, 1, 1, old.ID)
+	expectNotFound(t, "reading a deleted delivery", err)
+
+	deliveries, err := repo.WebhookDelivery().ListWebhookDeliveries(ctx, 1, 1, repository.WebhookDeliveryFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error listing deliveries: %v", err)
+	}
+	if len(deliveries) != 4 {
+		t.Errorf("expected the deliveries of the last day to be kept, got %d", len(deliveries))
+	}
+}
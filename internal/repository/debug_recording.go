@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// DebugRecordingRepository represents the set of queries on the DebugRecording model
+type DebugRecordingRepository interface {
+	// CreateDebugRecording creates a new debug recording
+	CreateDebugRecording(ctx context.Context, recording *models.DebugRecording) (*models.DebugRecording, error)
+	// ReadDebugRecording returns a debug recording by its id, scoped to a project
+	ReadDebugRecording(ctx context.Context, projectID, id uint) (*models.DebugRecording, error)
+	// ReadActiveDebugRecording returns the recording of a project which is active at the given time
+	ReadActiveDebugRecording(ctx context.Context, projectID uint, now time.Time) (*models.DebugRecording, error)
+	// ListDebugRecordingsByProjectID returns every debug recording of a project, newest first
+	ListDebugRecordingsByProjectID(ctx context.Context, projectID uint) ([]*models.DebugRecording, error)
+	// UpdateDebugRecording updates a debug recording
+	UpdateDebugRecording(ctx context.Context, recording *models.DebugRecording) (*models.DebugRecording, error)
+	// ReserveDebugRecordingExchange increments the exchange count of a recording, unless it has already reached max.
+	// It returns false if the recording is full.
+	ReserveDebugRecordingExchange(ctx context.Context, id uint, max uint) (bool, error)
+}
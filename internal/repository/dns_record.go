@@ -8,4 +8,9 @@ import (
 // DNSRecord model
 type DNSRecordRepository interface {
 	CreateDNSRecord(record *models.DNSRecord) (*models.DNSRecord, error)
+	// DeleteDNSRecordsByHostname deletes the records of the given hostnames, returning how many were deleted
+	DeleteDNSRecordsByHostname(hostnames []string) (int64, error)
+	// UpdateDNSRecordEndpointsByHostname points the records of the given hostnames at the endpoint, returning the
+	// updated records
+	UpdateDNSRecordEndpointsByHostname(hostnames []string, endpoint string) ([]*models.DNSRecord, error)
 }
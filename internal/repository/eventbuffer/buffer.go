@@ -0,0 +1,301 @@
+// Package eventbuffer batches the writes of porter app events. Deploys from large CI fan-outs create hundreds of events
+// a minute, and writing each of them with its own INSERT in the request path adds latency to every request and load on
+// the database.
+//
+// Events are given their id and creation time when they are queued, so callers can use them immediately. Every read,
+// update and delete of events writes the queued events first, so that an event is never missing from a read made on
+// the same server after it was created.
+package eventbuffer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+// Options configure a Buffer. Zero values use the defaults.
+type Options struct {
+	// FlushInterval is the longest an event is queued before it is written. Defaults to 500ms
+	FlushInterval time.Duration
+	// BatchSize is the number of queued events which triggers a write before the interval. Defaults to 100
+	BatchSize int
+	// MaxQueueSize bounds the queued events. Events created while the queue is full are written synchronously, after
+	// the queued events. Defaults to 1000
+	MaxQueueSize int
+	// ShutdownTimeout bounds the time spent writing the queued events once the buffer is stopped. Defaults to 10s
+	ShutdownTimeout time.Duration
+	// Logger receives the events which could not be written. Optional
+	Logger *logger.Logger
+}
+
+// the reasons an event is written synchronously, used as the label of the metric which counts them
+const (
+	syncReasonRequested = "requested"
+	syncReasonOverflow  = "overflow"
+	syncReasonStopped   = "stopped"
+)
+
+type synchronousKey struct{}
+
+// Synchronous returns a context whose events are written before CreateEvent returns, for events which must be readable
+// as soon as they are created, such as events returned to clients which may update them through another server
+func Synchronous(ctx context.Context) context.Context {
+	return context.WithValue(ctx, synchronousKey{}, true)
+}
+
+func isSynchronous(ctx context.Context) bool {
+	synchronous, _ := ctx.Value(synchronousKey{}).(bool)
+	return synchronous
+}
+
+// Buffer is a PorterAppEventRepository which queues created events and writes them in batches. Events are written in
+// the order they were created, so the events of an app are never written out of order.
+type Buffer struct {
+	repository.PorterAppEventRepository
+
+	opts Options
+
+	// flushMu is held while events are written, so that batches and synchronous writes are never written out of order
+	flushMu sync.Mutex
+	// flushNow is signalled when the queue reaches the batch size
+	flushNow chan struct{}
+
+	mu      sync.Mutex
+	queue   []*models.PorterAppEvent
+	stopped bool
+
+	// metrics, guarded by mu
+	flushes           int64
+	flushSeconds      float64
+	flushedEvents     int64
+	failedEvents      int64
+	synchronousWrites map[string]int64
+}
+
+// New returns a Buffer which writes events to events
+func New(events repository.PorterAppEventRepository, opts Options) *Buffer {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 500 * time.Millisecond
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.MaxQueueSize <= 0 {
+		opts.MaxQueueSize = 1000
+	}
+	if opts.MaxQueueSize < opts.BatchSize {
+		opts.MaxQueueSize = opts.BatchSize
+	}
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = 10 * time.Second
+	}
+
+	return &Buffer{
+		PorterAppEventRepository: events,
+		opts:                     opts,
+		flushNow:                 make(chan struct{}, 1),
+		synchronousWrites:        make(map[string]int64),
+	}
+}
+
+// Run writes the queued events on every interval, and whenever the queue reaches the batch size, until ctx is
+// cancelled. The queued events are then written, and events created afterwards are written synchronously.
+func (b *Buffer) Run(ctx context.Context) error {
+	b.mu.Lock()
+	b.stopped = false
+	b.mu.Unlock()
+
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			stopCtx, cancel := context.WithTimeout(context.Background(), b.opts.ShutdownTimeout)
+			defer cancel()
+
+			return b.Stop(stopCtx)
+		case <-ticker.C:
+			b.Flush(ctx)
+		case <-b.flushNow:
+			b.Flush(ctx)
+		}
+	}
+}
+
+// Stop writes the queued events. Events created after Stop are written synchronously.
+func (b *Buffer) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	b.stopped = true
+	b.mu.Unlock()
+
+	b.Flush(ctx)
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("queued porter app events were not written before the shutdown deadline: %w", ctx.Err())
+	}
+
+	return nil
+}
+
+// QueueDepth returns the number of events waiting to be written
+func (b *Buffer) QueueDepth() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.queue)
+}
+
+// CreateEvent queues an event to be written with the next batch. The event is given its id and creation time
+// immediately. Events are written synchronously if ctx is Synchronous, if the queue is full or if the buffer is stopped.
+func (b *Buffer) CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
+	if appEvent.PorterAppID == 0 {
+		return fmt.Errorf("invalid porter app id supplied to create event")
+	}
+	if appEvent.ID == uuid.Nil {
+		appEvent.ID = uuid.New()
+	}
+	if appEvent.CreatedAt.IsZero() {
+		appEvent.CreatedAt = time.Now().UTC()
+	}
+	if appEvent.UpdatedAt.IsZero() {
+		appEvent.UpdatedAt = appEvent.CreatedAt
+	}
+
+	if isSynchronous(ctx) {
+		return b.createSynchronously(ctx, appEvent, syncReasonRequested)
+	}
+
+	b.mu.Lock()
+	switch {
+	case b.stopped:
+		b.mu.Unlock()
+		return b.createSynchronously(ctx, appEvent, syncReasonStopped)
+	case len(b.queue) >= b.opts.MaxQueueSize:
+		b.mu.Unlock()
+		return b.createSynchronously(ctx, appEvent, syncReasonOverflow)
+	}
+
+	// the caller may keep changing the event it passed in, so the queue keeps its own copy
+	queued := *appEvent
+	b.queue = append(b.queue, &queued)
+	full := len(b.queue) >= b.opts.BatchSize
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.flushNow <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// createSynchronously writes the queued events and then the event, so that it is not written before events created
+// earlier
+func (b *Buffer) createSynchronously(ctx context.Context, appEvent *models.PorterAppEvent, reason string) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.flushLocked(ctx)
+
+	b.mu.Lock()
+	b.synchronousWrites[reason]++
+	b.mu.Unlock()
+
+	return b.PorterAppEventRepository.CreateEvent(ctx, appEvent)
+}
+
+// Flush writes the queued events in a single batch. If the batch fails, its events are written one at a time, so that
+// an event which cannot be written does not stop the others from being written.
+func (b *Buffer) Flush(ctx context.Context) {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.flushLocked(ctx)
+}
+
+func (b *Buffer) flushLocked(ctx context.Context) {
+	b.mu.Lock()
+	events := b.queue
+	b.queue = nil
+	b.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	start := time.Now()
+
+	var failed int64
+	if err := b.PorterAppEventRepository.CreateEvents(ctx, events); err != nil {
+		for _, event := range events {
+			if err := b.PorterAppEventRepository.CreateEvent(ctx, event); err != nil {
+				failed++
+				b.log(zerolog.ErrorLevel).Err(err).Str("event-id", event.ID.String()).Uint("porter-app-id", event.PorterAppID).Str("type", event.Type).Msg("error writing queued porter app event")
+			}
+		}
+	}
+
+	b.mu.Lock()
+	b.flushes++
+	b.flushSeconds += time.Since(start).Seconds()
+	b.flushedEvents += int64(len(events)) - failed
+	b.failedEvents += failed
+	b.mu.Unlock()
+}
+
+// WriteMetrics writes the depth of the queue and the writes of the buffer so far in the prometheus text exposition
+// format
+func (b *Buffer) WriteMetrics(w io.Writer) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, err := fmt.Fprintf(w, "# HELP porter_app_event_queue_depth The number of porter app events waiting to be written\n# TYPE porter_app_event_queue_depth gauge\nporter_app_event_queue_depth %d\n", len(b.queue))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "# HELP porter_app_event_flush_duration_seconds The time spent writing batches of queued porter app events\n# TYPE porter_app_event_flush_duration_seconds summary\nporter_app_event_flush_duration_seconds_sum %g\nporter_app_event_flush_duration_seconds_count %d\n", b.flushSeconds, b.flushes)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "# HELP porter_app_event_flushed_total The number of queued porter app events written\n# TYPE porter_app_event_flushed_total counter\nporter_app_event_flushed_total %d\n", b.flushedEvents)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "# HELP porter_app_event_write_failures_total The number of queued porter app events which could not be written\n# TYPE porter_app_event_write_failures_total counter\nporter_app_event_write_failures_total %d\n", b.failedEvents)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "# HELP porter_app_event_synchronous_writes_total The number of porter app events written without being queued\n# TYPE porter_app_event_synchronous_writes_total counter\n"); err != nil {
+		return err
+	}
+	for _, reason := range []string{syncReasonRequested, syncReasonOverflow, syncReasonStopped} {
+		if _, err := fmt.Fprintf(w, "porter_app_event_synchronous_writes_total{reason=\"%s\"} %d\n", reason, b.synchronousWrites[reason]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Buffer) log(level zerolog.Level) *zerolog.Event {
+	if b.opts.Logger == nil {
+		return nil
+	}
+
+	return b.opts.Logger.WithLevel(level)
+}
@@ -0,0 +1,270 @@
+package eventbuffer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+// recordingEvents records the writes made to the events repository it wraps
+type recordingEvents struct {
+	repository.PorterAppEventRepository
+
+	mu sync.Mutex
+	// batches are the sizes of the batches written, and singles the number of events written one at a time
+	batches     []int
+	singles     int
+	failBatches bool
+}
+
+func (r *recordingEvents) CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
+	r.mu.Lock()
+	r.singles++
+	r.mu.Unlock()
+
+	return r.PorterAppEventRepository.CreateEvent(ctx, appEvent)
+}
+
+func (r *recordingEvents) CreateEvents(ctx context.Context, appEvents []*models.PorterAppEvent) error {
+	r.mu.Lock()
+	r.batches = append(r.batches, len(appEvents))
+	fail := r.failBatches
+	r.mu.Unlock()
+
+	if fail {
+		return errors.New("batch insert failed")
+	}
+
+	return r.PorterAppEventRepository.CreateEvents(ctx, appEvents)
+}
+
+func newRecordingEvents() *recordingEvents {
+	return &recordingEvents{PorterAppEventRepository: test.NewRepository(true).PorterAppEvent()}
+}
+
+func storedEvents(t *testing.T, events repository.PorterAppEventRepository, porterAppID uint) []*models.PorterAppEvent {
+	t.Helper()
+
+	stored, _, err := events.ListEventsByPorterAppID(context.Background(), porterAppID)
+	if err != nil {
+		t.Fatalf("unexpected error listing events: %v", err)
+	}
+
+	return stored
+}
+
+func TestCreateEventQueuesUntilFlush(t *testing.T) {
+	ctx := context.Background()
+	events := newRecordingEvents()
+	buffer := New(events, Options{BatchSize: 10})
+
+	event := &models.PorterAppEvent{PorterAppID: 1, Type: "BUILD"}
+	if err := buffer.CreateEvent(ctx, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.ID == uuid.Nil || event.CreatedAt.IsZero() {
+		t.Errorf("expected the event to be given an id and creation time when it is queued, got %+v", event)
+	}
+	if buffer.QueueDepth() != 1 || len(storedEvents(t, events.PorterAppEventRepository, 1)) != 0 {
+		t.Fatalf("expected the event to be queued without being written")
+	}
+
+	// reads through the buffer see the queued event
+	got, err := buffer.ReadEvent(ctx, event.ID)
+	if err != nil || got.ID != event.ID {
+		t.Fatalf("expected the queued event to be written before it is read, got %+v and %v", got, err)
+	}
+	if buffer.QueueDepth() != 0 || len(events.batches) != 1 || events.singles != 0 {
+		t.Errorf("expected the queue to be written in a single batch, got batches %v and %d single writes", events.batches, events.singles)
+	}
+
+	if err := buffer.CreateEvent(ctx, &models.PorterAppEvent{Type: "BUILD"}); err == nil {
+		t.Error("expected an error queueing an event without a porter app")
+	}
+}
+
+func TestEventsAreWrittenInOrderPerApp(t *testing.T) {
+	ctx := context.Background()
+	events := newRecordingEvents()
+	buffer := New(events, Options{BatchSize: 3, MaxQueueSize: 4})
+
+	start := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	var want []uuid.UUID
+	for i := 0; i < 6; i++ {
+		event := &models.PorterAppEvent{PorterAppID: uint(i%2 + 1), Type: "DEPLOY", CreatedAt: start.Add(time.Duration(i) * time.Second)}
+
+		createCtx := ctx
+		if i == 2 {
+			createCtx = Synchronous(ctx)
+		}
+		if err := buffer.CreateEvent(createCtx, event); err != nil {
+			t.Fatalf("unexpected error creating event %d: %v", i, err)
+		}
+		if event.PorterAppID == 1 {
+			want = append([]uuid.UUID{event.ID}, want...)
+		}
+	}
+	buffer.Flush(ctx)
+
+	// events are listed newest first, and the synchronous write did not jump ahead of the events queued before it
+	stored := storedEvents(t, events.PorterAppEventRepository, 1)
+	if len(stored) != len(want) {
+		t.Fatalf("expected %d events, got %d", len(want), len(stored))
+	}
+	for i := range stored {
+		if stored[i].ID != want[i] {
+			t.Fatalf("expected the events of the app in the order they were created, got %v", stored)
+		}
+	}
+	if len(events.batches) != 2 || events.batches[0] != 2 || events.batches[1] != 3 || events.singles != 1 {
+		t.Errorf("expected the queued events to be written before the synchronous write, got batches %v and %d single writes", events.batches, events.singles)
+	}
+}
+
+func TestOverflowIsWrittenSynchronously(t *testing.T) {
+	ctx := context.Background()
+	events := newRecordingEvents()
+	buffer := New(events, Options{BatchSize: 2, MaxQueueSize: 2})
+
+	for i := 0; i < 3; i++ {
+		if err := buffer.CreateEvent(ctx, &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if buffer.QueueDepth() != 0 {
+		t.Errorf("expected the full queue to be written with the overflowing event, got %d queued", buffer.QueueDepth())
+	}
+	if len(storedEvents(t, events.PorterAppEventRepository, 1)) != 3 || events.singles != 1 {
+		t.Errorf("expected the overflowing event to be written synchronously, got %d single writes", events.singles)
+	}
+
+	var metrics bytes.Buffer
+	if err := buffer.WriteMetrics(&metrics); err != nil {
+		t.Fatalf("unexpected error writing metrics: %v", err)
+	}
+	for _, want := range []string{
+		"porter_app_event_queue_depth 0",
+		"porter_app_event_flushed_total 2",
+		"porter_app_event_flush_duration_seconds_count 1",
+		`porter_app_event_synchronous_writes_total{reason="overflow"} 1`,
+	} {
+		if !strings.Contains(metrics.String(), want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, metrics.String())
+		}
+	}
+}
+
+func TestRunFlushesOnShutdown(t *testing.T) {
+	events := newRecordingEvents()
+	buffer := New(events, Options{FlushInterval: time.Hour, BatchSize: 100})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- buffer.Run(ctx)
+	}()
+
+	for i := 0; i < 5; i++ {
+		if err := buffer.CreateEvent(context.Background(), &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error stopping the buffer: %v", err)
+	}
+
+	if got := len(storedEvents(t, events.PorterAppEventRepository, 1)); got != 5 {
+		t.Errorf("expected the queued events to be written on shutdown, got %d", got)
+	}
+
+	// events created after shutdown are not left in the queue
+	if err := buffer.CreateEvent(context.Background(), &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buffer.QueueDepth() != 0 || len(storedEvents(t, events.PorterAppEventRepository, 1)) != 6 {
+		t.Errorf("expected events created after shutdown to be written synchronously")
+	}
+}
+
+func TestRunFlushesFullBatches(t *testing.T) {
+	events := newRecordingEvents()
+	buffer := New(events, Options{FlushInterval: time.Hour, BatchSize: 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go buffer.Run(ctx) // nolint:errcheck
+
+	for i := 0; i < 3; i++ {
+		if err := buffer.CreateEvent(context.Background(), &models.PorterAppEvent{PorterAppID: 1, Type: "DEPLOY"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for buffer.QueueDepth() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a full batch to be written before the flush interval")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFailedBatchIsWrittenOneAtATime(t *testing.T) {
+	ctx := context.Background()
+	events := newRecordingEvents()
+	events.failBatches = true
+	buffer := New(events, Options{})
+
+	existing := &models.PorterAppEvent{PorterAppID: 1, Type: "BUILD"}
+	if err := events.PorterAppEventRepository.CreateEvent(ctx, existing); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, event := range []*models.PorterAppEvent{
+		{PorterAppID: 1, Type: "DEPLOY"},
+		{ID: existing.ID, PorterAppID: 1, Type: "DEPLOY"},
+		{PorterAppID: 1, Type: "PRE_DEPLOY"},
+	} {
+		if err := buffer.CreateEvent(ctx, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	buffer.Flush(ctx)
+
+	if got := len(storedEvents(t, events.PorterAppEventRepository, 1)); got != 3 {
+		t.Errorf("expected the events which can be written to be kept, got %d events", got)
+	}
+
+	var metrics bytes.Buffer
+	if err := buffer.WriteMetrics(&metrics); err != nil {
+		t.Fatalf("unexpected error writing metrics: %v", err)
+	}
+	if !strings.Contains(metrics.String(), "porter_app_event_write_failures_total 1") {
+		t.Errorf("expected the event which could not be written to be counted, got:\n%s", metrics.String())
+	}
+}
+
+func TestWrapRepository(t *testing.T) {
+	repo := test.NewRepository(true)
+	buffer := New(repo.PorterAppEvent(), Options{})
+
+	wrapped := WrapRepository(repo, buffer)
+	if wrapped.PorterAppEvent() != buffer {
+		t.Error("expected porter app events to be written through the buffer")
+	}
+	if wrapped.PorterApp() != repo.PorterApp() {
+		t.Error("expected the other repositories to be unchanged")
+	}
+}
@@ -0,0 +1,109 @@
+package eventbuffer
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
+)
+
+// bufferedRepository is a repository whose porter app events are written through a Buffer
+type bufferedRepository struct {
+	repository.Repository
+
+	buffer *Buffer
+}
+
+// WrapRepository returns repo with its porter app events written through buffer
+func WrapRepository(repo repository.Repository, buffer *Buffer) repository.Repository {
+	return &bufferedRepository{Repository: repo, buffer: buffer}
+}
+
+func (r *bufferedRepository) PorterAppEvent() repository.PorterAppEventRepository {
+	return r.buffer
+}
+
+// The methods below write the queued events before they read, update or delete events, so that they see every event
+// created before them.
+
+func (b *Buffer) ListEventsByPorterAppID(ctx context.Context, porterAppID uint, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.ListEventsByPorterAppID(ctx, porterAppID, opts...)
+}
+
+func (b *Buffer) ListFilteredEventsByPorterAppID(ctx context.Context, porterAppID uint, filter repository.PorterAppEventFilter) ([]*models.PorterAppEvent, int64, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.ListFilteredEventsByPorterAppID(ctx, porterAppID, filter)
+}
+
+func (b *Buffer) ListEventsByPorterAppIDAndDeploymentTargetID(ctx context.Context, porterAppID uint, deploymentTargetID uuid.UUID, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.ListEventsByPorterAppIDAndDeploymentTargetID(ctx, porterAppID, deploymentTargetID, opts...)
+}
+
+func (b *Buffer) ListBuildDeployEventsByPorterAppIDAndDeploymentTargetID(ctx context.Context, porterAppID uint, deploymentTargetID uuid.UUID, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.ListBuildDeployEventsByPorterAppIDAndDeploymentTargetID(ctx, porterAppID, deploymentTargetID, opts...)
+}
+
+// CreateEvents writes the queued events and then the events, in a single batch
+func (b *Buffer) CreateEvents(ctx context.Context, appEvents []*models.PorterAppEvent) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.flushLocked(ctx)
+	return b.PorterAppEventRepository.CreateEvents(ctx, appEvents)
+}
+
+func (b *Buffer) UpdateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.UpdateEvent(ctx, appEvent)
+}
+
+func (b *Buffer) ReadEvent(ctx context.Context, id uuid.UUID) (models.PorterAppEvent, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.ReadEvent(ctx, id)
+}
+
+func (b *Buffer) ReadDeployEventByRevision(ctx context.Context, porterAppID uint, revision float64) (models.PorterAppEvent, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.ReadDeployEventByRevision(ctx, porterAppID, revision)
+}
+
+func (b *Buffer) ReadDeployEventByAppRevisionID(ctx context.Context, porterAppID uint, appRevisionID string) (models.PorterAppEvent, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.ReadDeployEventByAppRevisionID(ctx, porterAppID, appRevisionID)
+}
+
+func (b *Buffer) ReadNotificationsByAppRevisionID(ctx context.Context, porterAppInstanceID uuid.UUID, appRevisionID string) ([]*models.PorterAppEvent, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.ReadNotificationsByAppRevisionID(ctx, porterAppInstanceID, appRevisionID)
+}
+
+func (b *Buffer) NotificationByID(ctx context.Context, notificationID string) (*models.PorterAppEvent, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.NotificationByID(ctx, notificationID)
+}
+
+func (b *Buffer) ListEventsCreatedBetween(ctx context.Context, start, end time.Time, eventTypes []string) ([]*models.PorterAppEvent, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.ListEventsCreatedBetween(ctx, start, end, eventTypes)
+}
+
+func (b *Buffer) ReadLatestEventByType(ctx context.Context, porterAppID uint, eventType string) (*models.PorterAppEvent, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.ReadLatestEventByType(ctx, porterAppID, eventType)
+}
+
+func (b *Buffer) DeleteEventsCreatedBetween(ctx context.Context, start, end time.Time) (int64, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.DeleteEventsCreatedBetween(ctx, start, end)
+}
+
+func (b *Buffer) DeleteEventsByPorterAppID(ctx context.Context, porterAppID uint) (int64, error) {
+	b.Flush(ctx)
+	return b.PorterAppEventRepository.DeleteEventsByPorterAppID(ctx, porterAppID)
+}
@@ -27,7 +27,7 @@ func (cr APIContractRepository) Insert(ctx context.Context, conf models.APIContr
 	if conf.ID == uuid.Nil {
 		conf.ID = uuid.New()
 	}
-	tx := cr.db.Create(&conf)
+	tx := cr.db.WithContext(ctx).Create(&conf)
 	if tx.Error != nil {
 		return conf, tx.Error
 	}
@@ -61,7 +61,7 @@ func (cr APIContractRepository) List(ctx context.Context, projectID uint, filter
 	}
 
 	var confs []*models.APIContractRevision
-	query := cr.db.Model(&models.APIContractRevision{}).Where("project_id = ?", projectID)
+	query := cr.db.WithContext(ctx).Model(&models.APIContractRevision{}).Where("project_id = ?", projectID)
 
 	if opts.ClusterID != 0 {
 		query = query.Where("cluster_id = ?", opts.ClusterID)
@@ -85,7 +85,7 @@ func (cr APIContractRepository) Delete(ctx context.Context, projectID uint, clus
 		conf.ClusterID = int(clusterID)
 	}
 
-	tx := cr.db.Delete(&conf)
+	tx := cr.db.WithContext(ctx).Delete(&conf)
 	if tx.Error != nil {
 		return tx.Error
 	}
@@ -99,7 +99,7 @@ func (cr APIContractRepository) Get(ctx context.Context, revisionID uuid.UUID) (
 	}
 
 	var acr models.APIContractRevision
-	tx := cr.db.Find(&acr, "id = ?", revisionID.String())
+	tx := cr.db.WithContext(ctx).Find(&acr, "id = ?", revisionID.String())
 	if tx.Error != nil {
 		return models.APIContractRevision{}, fmt.Errorf("no contract revision found for id %s: %w", revisionID, tx.Error)
 	}
@@ -137,7 +137,7 @@ func (cr APIContractRepository) Latest(ctx context.Context, projectID uint, filt
 		args = append(args, opts.ClusterID)
 	}
 
-	tx := cr.db.Raw(queryString, args...).Scan(&confs)
+	tx := cr.db.WithContext(ctx).Raw(queryString, args...).Scan(&confs)
 	if tx.Error != nil {
 		return nil, telemetry.Error(ctx, span, tx.Error, "error getting latest api contract revisions")
 	}
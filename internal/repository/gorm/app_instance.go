@@ -32,7 +32,7 @@ func (repo *AppInstanceRepository) Get(ctx context.Context, id string) (*models.
 		return nil, telemetry.Error(ctx, span, nil, "id is empty")
 	}
 
-	if err := repo.db.Where("id = ?", id).First(&appInstance).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Where("id = ?", id).First(&appInstance).Error; err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error getting app instance")
 	}
 
@@ -0,0 +1,61 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository uses gorm.DB for querying the database
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository returns an AuditLogRepository which uses
+// gorm.DB for querying the database
+func NewAuditLogRepository(db *gorm.DB) repository.AuditLogRepository {
+	return &AuditLogRepository{db}
+}
+
+// CreateAuditLogEntry adds an entry to the audit log of a project
+func (repo *AuditLogRepository) CreateAuditLogEntry(ctx context.Context, entry *models.AuditLogEntry) (*models.AuditLogEntry, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-audit-log-entry")
+	defer span.End()
+
+	if entry == nil {
+		return nil, telemetry.Error(ctx, span, nil, "audit log entry is nil")
+	}
+	if entry.ProjectID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "project id is empty")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: entry.ProjectID},
+		telemetry.AttributeKV{Key: "action", Value: entry.Action},
+	)
+
+	if err := repo.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating audit log entry")
+	}
+
+	return entry, nil
+}
+
+// ListAuditLogEntriesByProjectID returns up to limit entries of the audit log of a project, newest first
+func (repo *AuditLogRepository) ListAuditLogEntriesByProjectID(ctx context.Context, projectID uint, limit int) ([]*models.AuditLogEntry, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-audit-log-entries")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: projectID})
+
+	entries := []*models.AuditLogEntry{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id DESC").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing audit log entries")
+	}
+
+	return entries, nil
+}
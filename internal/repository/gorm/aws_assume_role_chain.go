@@ -40,7 +40,7 @@ func (cr AWSAssumeRoleChain) List(ctx context.Context, projectID uint) ([]*model
 		query += fmt.Sprintf(" and target_arn not like '%%arn:aws:iam::%s%%'", account)
 	}
 
-	tx := cr.db.Where(query, projectID).Find(&confs)
+	tx := cr.db.WithContext(ctx).Where(query, projectID).Find(&confs)
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
@@ -59,7 +59,7 @@ func (cr AWSAssumeRoleChain) ListByAwsAccountId(ctx context.Context, awsAccountI
 	}
 
 	targetArn := fmt.Sprintf("arn:aws:iam::%s:role/porter-manager", awsAccountID)
-	tx := cr.db.Where("target_arn = ?", targetArn).Find(&confs)
+	tx := cr.db.WithContext(ctx).Where("target_arn = ?", targetArn).Find(&confs)
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
@@ -74,13 +74,13 @@ func (cr AWSAssumeRoleChain) Delete(ctx context.Context, projectID uint) error {
 	}
 
 	var confs []*models.AWSAssumeRoleChain
-	tx := cr.db.Where("project_id = ?", projectID).Find(&confs)
+	tx := cr.db.WithContext(ctx).Where("project_id = ?", projectID).Find(&confs)
 	if tx.Error != nil {
 		return tx.Error
 	}
 
 	for _, conf := range confs {
-		tx := cr.db.Delete(conf)
+		tx := cr.db.WithContext(ctx).Delete(conf)
 		if tx.Error != nil {
 			return tx.Error
 		}
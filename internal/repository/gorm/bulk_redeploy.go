@@ -0,0 +1,175 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BulkRedeployRepository uses gorm.DB for querying the database
+type BulkRedeployRepository struct {
+	db *gorm.DB
+}
+
+// NewBulkRedeployRepository returns a BulkRedeployRepository which uses
+// gorm.DB for querying the database
+func NewBulkRedeployRepository(db *gorm.DB) repository.BulkRedeployRepository {
+	return &BulkRedeployRepository{db}
+}
+
+// CreateBulkRedeploy creates a bulk redeploy along with its operations
+func (repo *BulkRedeployRepository) CreateBulkRedeploy(ctx context.Context, bulkRedeploy *models.BulkRedeploy) (*models.BulkRedeploy, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-bulk-redeploy")
+	defer span.End()
+
+	if bulkRedeploy == nil {
+		return nil, telemetry.Error(ctx, span, nil, "bulk redeploy is nil")
+	}
+	if bulkRedeploy.ProjectID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "project id is empty")
+	}
+	if bulkRedeploy.ID == uuid.Nil {
+		bulkRedeploy.ID = uuid.New()
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: bulkRedeploy.ProjectID},
+		telemetry.AttributeKV{Key: "bulk-redeploy-id", Value: bulkRedeploy.ID.String()},
+		telemetry.AttributeKV{Key: "operations", Value: len(bulkRedeploy.Operations)},
+	)
+
+	// the operations are created in the same transaction by gorm's association handling
+	if err := repo.db.WithContext(ctx).Create(bulkRedeploy).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating bulk redeploy")
+	}
+
+	return bulkRedeploy, nil
+}
+
+// ReadBulkRedeploy returns a bulk redeploy of a project with its operations
+func (repo *BulkRedeployRepository) ReadBulkRedeploy(ctx context.Context, projectID uint, id uuid.UUID) (*models.BulkRedeploy, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-bulk-redeploy")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "bulk-redeploy-id", Value: id.String()},
+	)
+
+	bulkRedeploy := &models.BulkRedeploy{}
+
+	if err := repo.db.WithContext(ctx).Preload("Operations", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("project_id = ? AND id = ?", projectID, id).First(bulkRedeploy).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading bulk redeploy")
+	}
+
+	return bulkRedeploy, nil
+}
+
+// ListIncompleteBulkRedeploys returns every bulk redeploy with operations left to finish, with its operations
+func (repo *BulkRedeployRepository) ListIncompleteBulkRedeploys(ctx context.Context) ([]*models.BulkRedeploy, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-incomplete-bulk-redeploys")
+	defer span.End()
+
+	bulkRedeploys := []*models.BulkRedeploy{}
+
+	if err := repo.db.WithContext(ctx).Preload("Operations", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("completed_at IS NULL").Order("created_at ASC").Find(&bulkRedeploys).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing incomplete bulk redeploys")
+	}
+
+	return bulkRedeploys, nil
+}
+
+// ClaimBulkRedeployOperation moves a pending operation to running if fewer than concurrency operations of its bulk
+// redeploy are running on its cluster, returning false if the operation was not claimed
+func (repo *BulkRedeployRepository) ClaimBulkRedeployOperation(ctx context.Context, operation *models.BulkRedeployOperation, concurrency int, startedAt time.Time) (bool, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-claim-bulk-redeploy-operation")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "bulk-redeploy-id", Value: operation.BulkRedeployID.String()},
+		telemetry.AttributeKV{Key: "operation-id", Value: operation.ID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: operation.ClusterID},
+	)
+
+	claimed := false
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// locking the bulk redeploy serializes the claims of its operations, so that replicas counting the running
+		// operations at the same time cannot both claim the last free slot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			Where("id = ?", operation.BulkRedeployID).First(&models.BulkRedeploy{}).Error; err != nil {
+			return err
+		}
+
+		var running int64
+		if err := tx.Model(&models.BulkRedeployOperation{}).
+			Where("bulk_redeploy_id = ? AND cluster_id = ? AND status = ?", operation.BulkRedeployID, operation.ClusterID, string(types.BulkRedeployStatus_Running)).
+			Count(&running).Error; err != nil {
+			return err
+		}
+		if running >= int64(concurrency) {
+			return nil
+		}
+
+		res := tx.Model(&models.BulkRedeployOperation{}).
+			Where("id = ? AND status = ?", operation.ID, string(types.BulkRedeployStatus_Pending)).
+			Updates(map[string]interface{}{
+				"status":     string(types.BulkRedeployStatus_Running),
+				"started_at": startedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		claimed = res.RowsAffected == 1
+		return nil
+	})
+	if err != nil {
+		return false, telemetry.Error(ctx, span, err, "error claiming bulk redeploy operation")
+	}
+
+	if claimed {
+		operation.Status = string(types.BulkRedeployStatus_Running)
+		operation.StartedAt = &startedAt
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "claimed", Value: claimed})
+
+	return claimed, nil
+}
+
+// UpdateBulkRedeployOperation updates an operation
+func (repo *BulkRedeployRepository) UpdateBulkRedeployOperation(ctx context.Context, operation *models.BulkRedeployOperation) (*models.BulkRedeployOperation, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-update-bulk-redeploy-operation")
+	defer span.End()
+
+	if err := repo.db.WithContext(ctx).Save(operation).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error updating bulk redeploy operation")
+	}
+
+	return operation, nil
+}
+
+// CompleteBulkRedeploy records that every operation of a bulk redeploy has finished
+func (repo *BulkRedeployRepository) CompleteBulkRedeploy(ctx context.Context, id uuid.UUID, completedAt time.Time) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-complete-bulk-redeploy")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "bulk-redeploy-id", Value: id.String()})
+
+	if err := repo.db.WithContext(ctx).Model(&models.BulkRedeploy{}).Where("id = ?", id).Update("completed_at", completedAt).Error; err != nil {
+		return telemetry.Error(ctx, span, err, "error completing bulk redeploy")
+	}
+
+	return nil
+}
@@ -1,12 +1,14 @@
 package gorm
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/porter-dev/porter/internal/encryption"
 	"github.com/porter-dev/porter/internal/features"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
 	"gorm.io/gorm"
 
 	ints "github.com/porter-dev/porter/internal/models/integrations"
@@ -281,6 +283,15 @@ func (repo *ClusterRepository) UpdateCluster(
 	return cluster, nil
 }
 
+// UpdateClusterCapabilities replaces only the detected capabilities of a cluster, so that detection never overwrites
+// settings which were changed while it ran
+func (repo *ClusterRepository) UpdateClusterCapabilities(
+	clusterID uint,
+	capabilities models.ClusterCapabilities,
+) error {
+	return repo.db.Model(&models.Cluster{}).Where("id = ?", clusterID).Update("capabilities", capabilities).Error
+}
+
 // UpdateClusterTokenCache updates the token cache for a cluster
 func (repo *ClusterRepository) UpdateClusterTokenCache(
 	tokenCache *ints.ClusterTokenCache,
@@ -453,3 +464,25 @@ func (repo *ClusterRepository) DecryptClusterCandidateData(
 
 	return nil
 }
+
+// CountClustersByProvider returns the number of clusters of the instance hosted by each cloud provider, keyed by
+// models.Cluster.Provider
+func (repo *ClusterRepository) CountClustersByProvider(ctx context.Context) (map[string]int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-count-clusters-by-provider")
+	defer span.End()
+
+	// only the columns which decide the provider are read, so that the encrypted fields are not decrypted
+	clusters := []*models.Cluster{}
+	if err := repo.db.WithContext(ctx).
+		Select("cloud_provider", "aws_integration_id", "gcp_integration_id", "do_integration_id", "azure_integration_id").
+		Find(&clusters).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing cluster providers")
+	}
+
+	counts := make(map[string]int64)
+	for _, cluster := range clusters {
+		counts[cluster.Provider()]++
+	}
+
+	return counts, nil
+}
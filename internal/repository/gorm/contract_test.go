@@ -0,0 +1,43 @@
+package gorm_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/contract"
+	"github.com/porter-dev/porter/internal/repository/gorm"
+)
+
+func TestContract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) repository.Repository {
+		db, err := adapter.New(&env.DBConf{
+			EncryptionKey: "__random_strong_encryption_key__",
+			SQLLite:       true,
+			SQLLitePath:   filepath.Join(t.TempDir(), "porter.db"),
+		})
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		if err := gorm.AutoMigrate(db, false); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		t.Cleanup(func() {
+			if sqlDB, err := db.DB(); err == nil {
+				sqlDB.Close()
+			}
+		})
+
+		var key [32]byte
+
+		for i, b := range []byte("__random_strong_encryption_key__") {
+			key[i] = b
+		}
+
+		return gorm.NewRepository(db, &key, nil)
+	})
+}
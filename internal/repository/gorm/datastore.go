@@ -63,7 +63,7 @@ func (repo *DatastoreRepository) Insert(ctx context.Context, datastore *models.D
 		datastore.UpdatedAt = time.Now().UTC()
 	}
 
-	if err := repo.db.Save(datastore).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Save(datastore).Error; err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error saving datastore")
 	}
 
@@ -83,7 +83,7 @@ func (repo *DatastoreRepository) GetByProjectIDAndName(ctx context.Context, proj
 	}
 
 	datastore := &models.Datastore{}
-	if err := repo.db.Where("project_id = ? AND name = ?", projectId, name).Limit(1).Find(&datastore).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND name = ?", projectId, name).Limit(1).Find(&datastore).Error; err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error finding datastore")
 	}
 
@@ -100,7 +100,7 @@ func (repo *DatastoreRepository) ListByProjectID(ctx context.Context, projectId
 	}
 
 	datastores := []*models.Datastore{}
-	if err := repo.db.Where("project_id = ?", projectId).Find(&datastores).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectId).Find(&datastores).Error; err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error finding datastores")
 	}
 
@@ -120,7 +120,7 @@ func (repo *DatastoreRepository) Delete(ctx context.Context, datastore *models.D
 		return nil, telemetry.Error(ctx, span, nil, "datastore id is nil")
 	}
 
-	if err := repo.db.Delete(&datastore).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Delete(&datastore).Error; err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error deleting datastore")
 	}
 
@@ -147,7 +147,7 @@ func (repo *DatastoreRepository) UpdateStatus(ctx context.Context, datastore *mo
 	datastore.Status = status
 	datastore.UpdatedAt = time.Now().UTC()
 
-	if err := repo.db.Save(datastore).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Save(datastore).Error; err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error updating datastore status")
 	}
 
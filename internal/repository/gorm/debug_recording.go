@@ -0,0 +1,127 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DebugRecordingRepository uses gorm.DB for querying the database
+type DebugRecordingRepository struct {
+	db *gorm.DB
+}
+
+// NewDebugRecordingRepository returns a DebugRecordingRepository which uses
+// gorm.DB for querying the database
+func NewDebugRecordingRepository(db *gorm.DB) repository.DebugRecordingRepository {
+	return &DebugRecordingRepository{db}
+}
+
+// CreateDebugRecording creates a new debug recording
+func (repo *DebugRecordingRepository) CreateDebugRecording(ctx context.Context, recording *models.DebugRecording) (*models.DebugRecording, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-debug-recording")
+	defer span.End()
+
+	if recording == nil {
+		return nil, telemetry.Error(ctx, span, nil, "debug recording is nil")
+	}
+	if recording.ProjectID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "project id is empty")
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: recording.ProjectID})
+
+	if err := repo.db.WithContext(ctx).Create(recording).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating debug recording")
+	}
+
+	return recording, nil
+}
+
+// ReadDebugRecording returns a debug recording by its id, scoped to a project
+func (repo *DebugRecordingRepository) ReadDebugRecording(ctx context.Context, projectID, id uint) (*models.DebugRecording, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-debug-recording")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "debug-recording-id", Value: id},
+	)
+
+	recording := &models.DebugRecording{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND id = ?", projectID, id).First(recording).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading debug recording")
+	}
+
+	return recording, nil
+}
+
+// ReadActiveDebugRecording returns the recording of a project which is active at the given time
+func (repo *DebugRecordingRepository) ReadActiveDebugRecording(ctx context.Context, projectID uint, now time.Time) (*models.DebugRecording, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-active-debug-recording")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: projectID})
+
+	recording := &models.DebugRecording{}
+
+	if err := repo.db.WithContext(ctx).
+		Where("project_id = ? AND stopped_at IS NULL AND expires_at > ?", projectID, now).
+		Order("id DESC").
+		First(recording).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading active debug recording")
+	}
+
+	return recording, nil
+}
+
+// ListDebugRecordingsByProjectID returns every debug recording of a project, newest first
+func (repo *DebugRecordingRepository) ListDebugRecordingsByProjectID(ctx context.Context, projectID uint) ([]*models.DebugRecording, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-debug-recordings")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: projectID})
+
+	recordings := []*models.DebugRecording{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id DESC").Find(&recordings).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing debug recordings")
+	}
+
+	return recordings, nil
+}
+
+// UpdateDebugRecording updates a debug recording
+func (repo *DebugRecordingRepository) UpdateDebugRecording(ctx context.Context, recording *models.DebugRecording) (*models.DebugRecording, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-update-debug-recording")
+	defer span.End()
+
+	if err := repo.db.WithContext(ctx).Save(recording).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error updating debug recording")
+	}
+
+	return recording, nil
+}
+
+// ReserveDebugRecordingExchange increments the exchange count of a recording, unless it has already reached max. The
+// check and the increment are a single statement, so that concurrent requests cannot push a recording past its cap.
+func (repo *DebugRecordingRepository) ReserveDebugRecordingExchange(ctx context.Context, id uint, max uint) (bool, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-reserve-debug-recording-exchange")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "debug-recording-id", Value: id})
+
+	res := repo.db.WithContext(ctx).Model(&models.DebugRecording{}).
+		Where("id = ? AND exchange_count < ?", id, max).
+		UpdateColumn("exchange_count", gorm.Expr("exchange_count + 1"))
+	if res.Error != nil {
+		return false, telemetry.Error(ctx, span, res.Error, "error reserving debug recording exchange")
+	}
+
+	return res.RowsAffected == 1, nil
+}
@@ -25,3 +25,38 @@ func (repo *DNSRecordRepository) CreateDNSRecord(record *models.DNSRecord) (*mod
 
 	return record, nil
 }
+
+// DeleteDNSRecordsByHostname deletes the records of the given hostnames
+func (repo *DNSRecordRepository) DeleteDNSRecordsByHostname(hostnames []string) (int64, error) {
+	if len(hostnames) == 0 {
+		return 0, nil
+	}
+
+	res := repo.db.Where("hostname IN (?)", hostnames).Delete(&models.DNSRecord{})
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	return res.RowsAffected, nil
+}
+
+// UpdateDNSRecordEndpointsByHostname points the records of the given hostnames at the endpoint
+func (repo *DNSRecordRepository) UpdateDNSRecordEndpointsByHostname(hostnames []string, endpoint string) ([]*models.DNSRecord, error) {
+	records := []*models.DNSRecord{}
+	if len(hostnames) == 0 {
+		return records, nil
+	}
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.DNSRecord{}).Where("hostname IN (?)", hostnames).Update("endpoint", endpoint).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("hostname IN (?)", hostnames).Order("id").Find(&records).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -50,7 +50,7 @@ func (repo *GithubWebhookRepository) Insert(ctx context.Context, webhook *models
 		webhook.UpdatedAt = time.Now().UTC()
 	}
 
-	if err := repo.db.Save(webhook).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Save(webhook).Error; err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error saving webhook")
 	}
 
@@ -64,7 +64,7 @@ func (repo *GithubWebhookRepository) GetByClusterAndAppID(ctx context.Context, c
 
 	webhook := &models.GithubWebhook{}
 
-	if err := repo.db.Where("cluster_id = ? AND porter_app_id = ?", clusterID, appID).Limit(1).Find(&webhook).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Where("cluster_id = ? AND porter_app_id = ?", clusterID, appID).Limit(1).Find(&webhook).Error; err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error finding webhook")
 	}
 
@@ -78,7 +78,7 @@ func (repo *GithubWebhookRepository) Get(ctx context.Context, id uuid.UUID) (*mo
 
 	webhook := &models.GithubWebhook{}
 
-	if err := repo.db.Where("id = ?", id).Limit(1).Find(&webhook).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&webhook).Error; err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error finding webhook")
 	}
 
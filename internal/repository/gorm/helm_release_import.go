@@ -0,0 +1,94 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// HelmReleaseImportRepository uses gorm.DB for querying the database
+type HelmReleaseImportRepository struct {
+	db *gorm.DB
+}
+
+// NewHelmReleaseImportRepository returns a HelmReleaseImportRepository which uses
+// gorm.DB for querying the database
+func NewHelmReleaseImportRepository(db *gorm.DB) repository.HelmReleaseImportRepository {
+	return &HelmReleaseImportRepository{db}
+}
+
+// CreateHelmReleaseImport records that a helm release was imported as a porter app
+func (repo *HelmReleaseImportRepository) CreateHelmReleaseImport(ctx context.Context, helmImport *models.HelmReleaseImport) (*models.HelmReleaseImport, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-helm-release-import")
+	defer span.End()
+
+	if helmImport == nil {
+		return nil, telemetry.Error(ctx, span, nil, "helm release import is nil")
+	}
+	if helmImport.ClusterID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "cluster id is empty")
+	}
+	if helmImport.PorterAppID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "porter app id is empty")
+	}
+
+	if err := repo.db.WithContext(ctx).Create(helmImport).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating helm release import")
+	}
+
+	return helmImport, nil
+}
+
+// ReadHelmReleaseImport returns the import of a helm release in a cluster
+func (repo *HelmReleaseImportRepository) ReadHelmReleaseImport(ctx context.Context, clusterID uint, namespace, releaseName string) (*models.HelmReleaseImport, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-helm-release-import")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "cluster-id", Value: clusterID},
+		telemetry.AttributeKV{Key: "namespace", Value: namespace},
+		telemetry.AttributeKV{Key: "release-name", Value: releaseName},
+	)
+
+	helmImport := &models.HelmReleaseImport{}
+
+	if err := repo.db.WithContext(ctx).Where("cluster_id = ? AND namespace = ? AND release_name = ?", clusterID, namespace, releaseName).First(helmImport).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading helm release import")
+	}
+
+	return helmImport, nil
+}
+
+// ListHelmReleaseImportsByClusterID returns every helm release imported in a cluster
+func (repo *HelmReleaseImportRepository) ListHelmReleaseImportsByClusterID(ctx context.Context, projectID, clusterID uint) ([]*models.HelmReleaseImport, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-helm-release-imports-by-cluster-id")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: clusterID},
+	)
+
+	imports := []*models.HelmReleaseImport{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND cluster_id = ?", projectID, clusterID).Order("id ASC").Find(&imports).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing helm release imports")
+	}
+
+	return imports, nil
+}
+
+// UpdateHelmReleaseImport updates a helm release import
+func (repo *HelmReleaseImportRepository) UpdateHelmReleaseImport(ctx context.Context, helmImport *models.HelmReleaseImport) (*models.HelmReleaseImport, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-update-helm-release-import")
+	defer span.End()
+
+	if err := repo.db.WithContext(ctx).Save(helmImport).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error updating helm release import")
+	}
+
+	return helmImport, nil
+}
@@ -0,0 +1,96 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// InactivityPolicyRepository uses gorm.DB for querying the database
+type InactivityPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewInactivityPolicyRepository returns an InactivityPolicyRepository which uses
+// gorm.DB for querying the database
+func NewInactivityPolicyRepository(db *gorm.DB) repository.InactivityPolicyRepository {
+	return &InactivityPolicyRepository{db}
+}
+
+// ReadInactivityPolicy returns the inactivity policy of a project
+func (repo *InactivityPolicyRepository) ReadInactivityPolicy(ctx context.Context, projectID uint) (*models.InactivityPolicy, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-inactivity-policy")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: projectID})
+
+	policy := &models.InactivityPolicy{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectID).First(policy).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading inactivity policy")
+	}
+
+	return policy, nil
+}
+
+// CreateOrUpdateInactivityPolicy creates the inactivity policy of a project, or replaces the existing one
+func (repo *InactivityPolicyRepository) CreateOrUpdateInactivityPolicy(ctx context.Context, policy *models.InactivityPolicy) (*models.InactivityPolicy, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-or-update-inactivity-policy")
+	defer span.End()
+
+	if policy == nil {
+		return nil, telemetry.Error(ctx, span, nil, "inactivity policy is nil")
+	}
+	if policy.ProjectID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "project id is empty")
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: policy.ProjectID})
+
+	existing := &models.InactivityPolicy{}
+	err := repo.db.WithContext(ctx).Where("project_id = ?", policy.ProjectID).First(existing).Error
+	switch {
+	case err == nil:
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, telemetry.Error(ctx, span, err, "error reading existing inactivity policy")
+	}
+
+	if err := repo.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error saving inactivity policy")
+	}
+
+	return policy, nil
+}
+
+// DeleteInactivityPolicy deletes the inactivity policy of a project. The row is removed rather than soft deleted, so
+// that a new policy can be created for the project.
+func (repo *InactivityPolicyRepository) DeleteInactivityPolicy(ctx context.Context, policy *models.InactivityPolicy) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-inactivity-policy")
+	defer span.End()
+
+	if err := repo.db.WithContext(ctx).Unscoped().Delete(policy).Error; err != nil {
+		return telemetry.Error(ctx, span, err, "error deleting inactivity policy")
+	}
+
+	return nil
+}
+
+// ListEnabledInactivityPolicies returns every enabled inactivity policy, for evaluation
+func (repo *InactivityPolicyRepository) ListEnabledInactivityPolicies(ctx context.Context) ([]*models.InactivityPolicy, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-enabled-inactivity-policies")
+	defer span.End()
+
+	policies := []*models.InactivityPolicy{}
+
+	if err := repo.db.WithContext(ctx).Where("enabled = ?", true).Order("project_id ASC").Find(&policies).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing enabled inactivity policies")
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,65 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LockRepository uses gorm.DB for querying the database
+type LockRepository struct {
+	db *gorm.DB
+}
+
+// NewLockRepository returns a LockRepository which uses
+// gorm.DB for querying the database
+func NewLockRepository(db *gorm.DB) repository.LockRepository {
+	return &LockRepository{db}
+}
+
+// AcquireLock takes the lock with the name for the holder with the token. The lock is taken by inserting its row,
+// which fails on the primary key while another holder has the row, so that concurrent holders cannot both take it.
+func (repo *LockRepository) AcquireLock(ctx context.Context, name, token string, now, expiresAt time.Time) (bool, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-acquire-lock")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "lock-name", Value: name})
+
+	if name == "" || token == "" {
+		return false, telemetry.Error(ctx, span, nil, "lock name and token are required")
+	}
+
+	if err := repo.db.WithContext(ctx).Where("name = ? AND expires_at <= ?", name, now).Delete(&models.Lock{}).Error; err != nil {
+		return false, telemetry.Error(ctx, span, err, "error deleting expired lock")
+	}
+
+	res := repo.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Lock{
+		Name:      name,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+	if res.Error != nil {
+		return false, telemetry.Error(ctx, span, res.Error, "error acquiring lock")
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// ReleaseLock releases the lock with the name, if it is held by the holder with the token
+func (repo *LockRepository) ReleaseLock(ctx context.Context, name, token string) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-release-lock")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "lock-name", Value: name})
+
+	if err := repo.db.WithContext(ctx).Where("name = ? AND token = ?", name, token).Delete(&models.Lock{}).Error; err != nil {
+		return telemetry.Error(ctx, span, err, "error releasing lock")
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// LogAlertRuleRepository uses gorm.DB for querying the database
+type LogAlertRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewLogAlertRuleRepository returns a LogAlertRuleRepository which uses
+// gorm.DB for querying the database
+func NewLogAlertRuleRepository(db *gorm.DB) repository.LogAlertRuleRepository {
+	return &LogAlertRuleRepository{db}
+}
+
+// CreateLogAlertRule creates a new log alert rule
+func (repo *LogAlertRuleRepository) CreateLogAlertRule(ctx context.Context, rule *models.LogAlertRule) (*models.LogAlertRule, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-log-alert-rule")
+	defer span.End()
+
+	if rule == nil {
+		return nil, telemetry.Error(ctx, span, nil, "log alert rule is nil")
+	}
+	if rule.ProjectID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "project id is empty")
+	}
+	if rule.PorterAppID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "porter app id is empty")
+	}
+
+	if err := repo.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating log alert rule")
+	}
+
+	return rule, nil
+}
+
+// ReadLogAlertRule returns a log alert rule by its id, scoped to a project and porter app
+func (repo *LogAlertRuleRepository) ReadLogAlertRule(ctx context.Context, projectID, porterAppID, id uint) (*models.LogAlertRule, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-log-alert-rule")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "porter-app-id", Value: porterAppID},
+		telemetry.AttributeKV{Key: "log-alert-rule-id", Value: id},
+	)
+
+	rule := &models.LogAlertRule{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND porter_app_id = ? AND id = ?", projectID, porterAppID, id).First(rule).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading log alert rule")
+	}
+
+	return rule, nil
+}
+
+// ListLogAlertRulesByPorterAppID returns every log alert rule on a porter app
+func (repo *LogAlertRuleRepository) ListLogAlertRulesByPorterAppID(ctx context.Context, projectID, porterAppID uint) ([]*models.LogAlertRule, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-log-alert-rules-by-porter-app-id")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "porter-app-id", Value: porterAppID},
+	)
+
+	rules := []*models.LogAlertRule{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND porter_app_id = ?", projectID, porterAppID).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing log alert rules")
+	}
+
+	return rules, nil
+}
+
+// ListEnabledLogAlertRules returns every enabled log alert rule, for evaluation
+func (repo *LogAlertRuleRepository) ListEnabledLogAlertRules(ctx context.Context) ([]*models.LogAlertRule, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-enabled-log-alert-rules")
+	defer span.End()
+
+	rules := []*models.LogAlertRule{}
+
+	if err := repo.db.WithContext(ctx).Where("enabled = ?", true).Order("cluster_id ASC, porter_app_id ASC, id ASC").Find(&rules).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing enabled log alert rules")
+	}
+
+	return rules, nil
+}
+
+// UpdateLogAlertRule updates a log alert rule
+func (repo *LogAlertRuleRepository) UpdateLogAlertRule(ctx context.Context, rule *models.LogAlertRule) (*models.LogAlertRule, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-update-log-alert-rule")
+	defer span.End()
+
+	if err := repo.db.WithContext(ctx).Save(rule).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error updating log alert rule")
+	}
+
+	return rule, nil
+}
+
+// DeleteLogAlertRule deletes a log alert rule
+func (repo *LogAlertRuleRepository) DeleteLogAlertRule(ctx context.Context, rule *models.LogAlertRule) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-log-alert-rule")
+	defer span.End()
+
+	if err := repo.db.WithContext(ctx).Delete(rule).Error; err != nil {
+		return telemetry.Error(ctx, span, err, "error deleting log alert rule")
+	}
+
+	return nil
+}
@@ -22,6 +22,7 @@ func AutoMigrate(db *gorm.DB, debug bool) error {
 		&models.Environment{},
 		&models.Deployment{},
 		&models.Session{},
+		&models.UserTwoFactor{},
 		&models.GitRepo{},
 		&models.Registry{},
 		&models.HelmRepo{},
@@ -68,6 +69,20 @@ func AutoMigrate(db *gorm.DB, debug bool) error {
 		&models.AppTemplate{},
 		&models.GithubWebhook{},
 		&models.Datastore{},
+		&models.LogAlertRule{},
+		&models.HelmReleaseImport{},
+		&models.BulkRedeploy{},
+		&models.BulkRedeployOperation{},
+		&models.UsageRollup{},
+		&models.UsageRollupDay{},
+		&models.InactivityPolicy{},
+		&models.DebugRecording{},
+		&models.AuditLogEntry{},
+		&models.Lock{},
+		&models.WebhookDelivery{},
+		&models.PorterAppGrant{},
+		&models.PorterAppCleanup{},
+		&models.PorterAppDomain{},
 		&ints.KubeIntegration{},
 		&ints.BasicIntegration{},
 		&ints.OIDCIntegration{},
@@ -84,6 +99,7 @@ func AutoMigrate(db *gorm.DB, debug bool) error {
 		&ints.GithubAppInstallation{},
 		&ints.GithubAppOAuthIntegration{},
 		&ints.SlackIntegration{},
+		&ints.SecretsProviderIntegration{},
 		&models.Ipam{},
 	)
 }
@@ -2,10 +2,15 @@ package gorm
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PorterAppRepository uses gorm.DB for querying the database
@@ -40,17 +45,18 @@ func (repo *PorterAppRepository) ListPorterAppByClusterID(clusterID uint) ([]*mo
 func (repo *PorterAppRepository) ReadPorterAppByID(ctx context.Context, id uint) (*models.PorterApp, error) {
 	app := &models.PorterApp{}
 
-	if err := repo.db.Where("id = ?", id).Limit(1).Find(&app).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&app).Error; err != nil {
 		return nil, err
 	}
 
 	return app, nil
 }
 
+// ReadPorterAppByName returns a PorterApp by its cluster ID and name. Names are compared case-insensitively.
 func (repo *PorterAppRepository) ReadPorterAppByName(clusterID uint, name string) (*models.PorterApp, error) {
 	app := &models.PorterApp{}
 
-	if err := repo.db.Where("cluster_id = ? AND name = ?", clusterID, name).Limit(1).Find(&app).Error; err != nil {
+	if err := repo.db.Where("cluster_id = ? AND LOWER(name) = LOWER(?)", clusterID, name).Limit(1).Find(&app).Error; err != nil {
 		return nil, err
 	}
 
@@ -62,7 +68,7 @@ func (repo *PorterAppRepository) ReadPorterAppByName(clusterID uint, name string
 func (repo *PorterAppRepository) ReadPorterAppsByProjectIDAndName(projectID uint, name string) ([]*models.PorterApp, error) {
 	apps := []*models.PorterApp{}
 
-	if err := repo.db.Where("project_id = ? AND name = ?", projectID, name).Find(&apps).Error; err != nil {
+	if err := repo.db.Where("project_id = ? AND LOWER(name) = LOWER(?)", projectID, name).Find(&apps).Error; err != nil {
 		return nil, err
 	}
 
@@ -84,3 +90,205 @@ func (repo *PorterAppRepository) DeletePorterApp(app *models.PorterApp) (*models
 
 	return app, nil
 }
+
+// ReadScopedPorterAppByID returns a PorterApp by its ID, if it belongs to the given project
+func (repo *PorterAppRepository) ReadScopedPorterAppByID(ctx context.Context, projectID, id uint) (*models.PorterApp, error) {
+	app := &models.PorterApp{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND id = ?", projectID, id).First(&app).Error; err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// ReadScopedPorterAppByName returns a PorterApp by its cluster ID and name, if it belongs to the given project
+func (repo *PorterAppRepository) ReadScopedPorterAppByName(projectID, clusterID uint, name string) (*models.PorterApp, error) {
+	app := &models.PorterApp{}
+
+	if err := repo.db.Where("project_id = ? AND cluster_id = ? AND LOWER(name) = LOWER(?)", projectID, clusterID, name).First(&app).Error; err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// ListScopedPorterAppsByClusterID returns all PorterApps in a cluster, if the cluster belongs to the given project
+func (repo *PorterAppRepository) ListScopedPorterAppsByClusterID(projectID, clusterID uint) ([]*models.PorterApp, error) {
+	apps := []*models.PorterApp{}
+
+	if err := repo.db.Where("project_id = ? AND cluster_id = ?", projectID, clusterID).Find(&apps).Error; err != nil {
+		return nil, err
+	}
+
+	return apps, nil
+}
+
+// ReadScopedPorterAppByUUID returns a PorterApp by its cluster ID and UUID, if it belongs to the given project
+func (repo *PorterAppRepository) ReadScopedPorterAppByUUID(ctx context.Context, projectID, clusterID uint, id uuid.UUID) (*models.PorterApp, error) {
+	app := &models.PorterApp{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND cluster_id = ? AND uuid = ?", projectID, clusterID, id).First(&app).Error; err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// ReadScopedPorterAppByPreviousName returns the PorterApp in a cluster which was renamed from name, if it belongs to the
+// given project and the previous name has not expired
+func (repo *PorterAppRepository) ReadScopedPorterAppByPreviousName(ctx context.Context, projectID, clusterID uint, name string) (*models.PorterApp, error) {
+	app := &models.PorterApp{}
+
+	if err := repo.db.WithContext(ctx).
+		Where("project_id = ? AND cluster_id = ? AND LOWER(previous_name) = LOWER(?) AND previous_name_expires_at > ?", projectID, clusterID, name, time.Now().UTC()).
+		Order("previous_name_expires_at DESC").
+		First(&app).Error; err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// ReadPorterAppByDeployWebhookTokenHash returns the PorterApp whose deploy webhook token has the given hash
+func (repo *PorterAppRepository) ReadPorterAppByDeployWebhookTokenHash(ctx context.Context, tokenHash string) (*models.PorterApp, error) {
+	app := &models.PorterApp{}
+
+	if tokenHash == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if err := repo.db.WithContext(ctx).Where("deploy_webhook_token_hash = ?", tokenHash).First(&app).Error; err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// ListPorterAppsByIDs returns the apps with the given IDs, including deleted apps
+func (repo *PorterAppRepository) ListPorterAppsByIDs(ctx context.Context, ids []uint) ([]*models.PorterApp, error) {
+	apps := []*models.PorterApp{}
+
+	if len(ids) == 0 {
+		return apps, nil
+	}
+
+	if err := repo.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Find(&apps).Error; err != nil {
+		return nil, err
+	}
+
+	return apps, nil
+}
+
+// ListPorterApps returns the apps of every project
+func (repo *PorterAppRepository) ListPorterApps(ctx context.Context) ([]*models.PorterApp, error) {
+	apps := []*models.PorterApp{}
+
+	if err := repo.db.WithContext(ctx).Order("id ASC").Find(&apps).Error; err != nil {
+		return nil, err
+	}
+
+	return apps, nil
+}
+
+// CountPorterApps returns the number of apps of every project
+func (repo *PorterAppRepository) CountPorterApps(ctx context.Context) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-count-porter-apps")
+	defer span.End()
+
+	var count int64
+	if err := repo.db.WithContext(ctx).Model(&models.PorterApp{}).Count(&count).Error; err != nil {
+		return 0, telemetry.Error(ctx, span, err, "error counting porter apps")
+	}
+
+	return count, nil
+}
+
+// ListPorterAppsWithScalingSchedules returns the apps which have at least one service with a scaling schedule
+func (repo *PorterAppRepository) ListPorterAppsWithScalingSchedules(ctx context.Context) ([]*models.PorterApp, error) {
+	apps := []*models.PorterApp{}
+
+	if err := repo.db.WithContext(ctx).Where("scaling_schedules IS NOT NULL").Order("id ASC").Find(&apps).Error; err != nil {
+		return nil, err
+	}
+
+	return apps, nil
+}
+
+// UpdatePorterAppScalingSchedules writes the scaling schedules of an app, unless the app was updated since it was read
+func (repo *PorterAppRepository) UpdatePorterAppScalingSchedules(ctx context.Context, app *models.PorterApp) (bool, error) {
+	if app == nil || app.ID == 0 {
+		return false, errors.New("porter app id is empty")
+	}
+
+	// the time is written at the precision postgres stores, so that it matches the app the next time it is updated
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	res := repo.db.WithContext(ctx).Model(&models.PorterApp{}).
+		Where("id = ? AND updated_at = ?", app.ID, app.UpdatedAt).
+		UpdateColumns(map[string]interface{}{
+			"scaling_schedules": app.ScalingSchedules,
+			"updated_at":        now,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	app.UpdatedAt = now
+
+	return true, nil
+}
+
+// RenamePorterApp renames an app and records its previous name, unless another app in its cluster has the new name
+func (repo *PorterAppRepository) RenamePorterApp(ctx context.Context, app *models.PorterApp, newName string, previousNameExpiresAt time.Time) (bool, error) {
+	if app == nil || app.ID == 0 {
+		return false, errors.New("porter app id is empty")
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	renamed := false
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// locking the app serializes renames of the same app, so that two of them cannot both record its previous name
+		current := &models.PorterApp{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", app.ID).First(current).Error; err != nil {
+			return err
+		}
+
+		var taken int64
+		if err := tx.Model(&models.PorterApp{}).
+			Where("project_id = ? AND cluster_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", current.ProjectID, current.ClusterID, newName, current.ID).
+			Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken != 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.PorterApp{}).Where("id = ?", current.ID).UpdateColumns(map[string]interface{}{
+			"name":                     newName,
+			"previous_name":            current.Name,
+			"previous_name_expires_at": previousNameExpiresAt,
+			"updated_at":               now,
+		}).Error; err != nil {
+			return err
+		}
+
+		app.PreviousName = current.Name
+		renamed = true
+
+		return nil
+	})
+	if err != nil || !renamed {
+		return false, err
+	}
+
+	app.Name = newName
+	app.PreviousNameExpiresAt = &previousNameExpiresAt
+	app.UpdatedAt = now
+
+	return true, nil
+}
@@ -0,0 +1,111 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// PorterAppCleanupRepository uses gorm.DB for querying the database
+type PorterAppCleanupRepository struct {
+	db *gorm.DB
+}
+
+// NewPorterAppCleanupRepository returns a PorterAppCleanupRepository which uses
+// gorm.DB for querying the database
+func NewPorterAppCleanupRepository(db *gorm.DB) repository.PorterAppCleanupRepository {
+	return &PorterAppCleanupRepository{db}
+}
+
+// CreatePorterAppCleanup records the cleanup of a deleted app
+func (repo *PorterAppCleanupRepository) CreatePorterAppCleanup(ctx context.Context, cleanup *models.PorterAppCleanup) (*models.PorterAppCleanup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-porter-app-cleanup")
+	defer span.End()
+
+	if cleanup == nil {
+		return nil, telemetry.Error(ctx, span, nil, "porter app cleanup is nil")
+	}
+	if cleanup.ProjectID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "project id is empty")
+	}
+
+	if err := repo.db.WithContext(ctx).Create(cleanup).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating porter app cleanup")
+	}
+
+	return cleanup, nil
+}
+
+// ReadPorterAppCleanup returns a cleanup by its id, scoped to a project
+func (repo *PorterAppCleanupRepository) ReadPorterAppCleanup(ctx context.Context, projectID, id uint) (*models.PorterAppCleanup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-porter-app-cleanup")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "porter-app-cleanup-id", Value: id},
+	)
+
+	cleanup := &models.PorterAppCleanup{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND id = ?", projectID, id).First(cleanup).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading porter app cleanup")
+	}
+
+	return cleanup, nil
+}
+
+// ListPorterAppCleanupsByStatus returns the cleanups of a project with a status, oldest first
+func (repo *PorterAppCleanupRepository) ListPorterAppCleanupsByStatus(ctx context.Context, projectID uint, status string) ([]*models.PorterAppCleanup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-porter-app-cleanups-by-status")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "status", Value: status},
+	)
+
+	cleanups := []*models.PorterAppCleanup{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND status = ?", projectID, status).Order("id").Find(&cleanups).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing porter app cleanups")
+	}
+
+	return cleanups, nil
+}
+
+// ListPendingPorterAppCleanups returns the cleanups of every project which have resources left to release, oldest first
+func (repo *PorterAppCleanupRepository) ListPendingPorterAppCleanups(ctx context.Context) ([]*models.PorterAppCleanup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-pending-porter-app-cleanups")
+	defer span.End()
+
+	cleanups := []*models.PorterAppCleanup{}
+
+	if err := repo.db.WithContext(ctx).Where("status = ?", string(types.PorterAppCleanupStatus_Pending)).Order("id").Find(&cleanups).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing pending porter app cleanups")
+	}
+
+	return cleanups, nil
+}
+
+// UpdatePorterAppCleanup writes the status and pending resources of a cleanup
+func (repo *PorterAppCleanupRepository) UpdatePorterAppCleanup(ctx context.Context, cleanup *models.PorterAppCleanup) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-update-porter-app-cleanup")
+	defer span.End()
+
+	if cleanup == nil || cleanup.ID == 0 {
+		return telemetry.Error(ctx, span, nil, "porter app cleanup id is empty")
+	}
+
+	// Select writes the pending resources even once they are empty, which Updates would skip as a zero value
+	err := repo.db.WithContext(ctx).Model(cleanup).Select("status", "pending", "finished_at", "updated_at").Updates(cleanup).Error
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error updating porter app cleanup")
+	}
+
+	return nil
+}
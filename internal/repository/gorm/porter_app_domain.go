@@ -0,0 +1,114 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// PorterAppDomainRepository uses gorm.DB for querying the database
+type PorterAppDomainRepository struct {
+	db *gorm.DB
+}
+
+// NewPorterAppDomainRepository returns a PorterAppDomainRepository which uses
+// gorm.DB for querying the database
+func NewPorterAppDomainRepository(db *gorm.DB) repository.PorterAppDomainRepository {
+	return &PorterAppDomainRepository{db}
+}
+
+// ListPorterAppDomains returns the domains of an app, ordered by hostname
+func (repo *PorterAppDomainRepository) ListPorterAppDomains(ctx context.Context, porterAppID uint) ([]*models.PorterAppDomain, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-porter-app-domains")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-id", Value: porterAppID})
+
+	domains := []*models.PorterAppDomain{}
+
+	if err := repo.db.WithContext(ctx).Where("porter_app_id = ?", porterAppID).Order("hostname").Find(&domains).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing porter app domains")
+	}
+
+	return domains, nil
+}
+
+// ListPorterAppDomainsByHostnames returns the domains of the apps of a project which have one of the given hostnames
+func (repo *PorterAppDomainRepository) ListPorterAppDomainsByHostnames(ctx context.Context, projectID uint, hostnames []string) ([]*models.PorterAppDomain, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-porter-app-domains-by-hostnames")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "hostname-count", Value: len(hostnames)},
+	)
+
+	domains := []*models.PorterAppDomain{}
+	if len(hostnames) == 0 {
+		return domains, nil
+	}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND hostname IN ?", projectID, hostnames).Order("hostname").Find(&domains).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing porter app domains by hostnames")
+	}
+
+	return domains, nil
+}
+
+// CreatePorterAppDomain records a domain served by an app
+func (repo *PorterAppDomainRepository) CreatePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) (*models.PorterAppDomain, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-porter-app-domain")
+	defer span.End()
+
+	if domain == nil {
+		return nil, telemetry.Error(ctx, span, nil, "porter app domain is nil")
+	}
+	if domain.PorterAppID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "porter app id is empty")
+	}
+
+	if err := repo.db.WithContext(ctx).Create(domain).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating porter app domain")
+	}
+
+	return domain, nil
+}
+
+// UpdatePorterAppDomain writes the certificate of a domain, and the alerts sent about it
+func (repo *PorterAppDomainRepository) UpdatePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-update-porter-app-domain")
+	defer span.End()
+
+	if domain == nil || domain.ID == 0 {
+		return telemetry.Error(ctx, span, nil, "porter app domain id is empty")
+	}
+
+	// Select writes the fields which are cleared, such as the check error, which Updates would skip as zero values
+	err := repo.db.WithContext(ctx).Model(domain).
+		Select("cert_status", "cert_source", "cert_issuer", "cert_expires_at", "checked_at", "check_error", "alerted_threshold_days", "updated_at").
+		Updates(domain).Error
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error updating porter app domain")
+	}
+
+	return nil
+}
+
+// DeletePorterAppDomain deletes a domain which is no longer served by its app
+func (repo *PorterAppDomainRepository) DeletePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-porter-app-domain")
+	defer span.End()
+
+	if domain == nil || domain.ID == 0 {
+		return telemetry.Error(ctx, span, nil, "porter app domain id is empty")
+	}
+
+	if err := repo.db.WithContext(ctx).Delete(domain).Error; err != nil {
+		return telemetry.Error(ctx, span, err, "error deleting porter app domain")
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/porter-dev/porter/internal/telemetry"
@@ -36,7 +37,7 @@ func (repo *PorterAppEventRepository) ListEventsByPorterAppID(ctx context.Contex
 		return nil, paginatedResult, errors.New("invalid porter app id supplied")
 	}
 
-	db := repo.db.Model(&models.PorterAppEvent{})
+	db := repo.db.WithContext(ctx).Model(&models.PorterAppEvent{})
 	resultDB := db.Where("porter_app_id = ?", id).Order("created_at DESC")
 	resultDB = resultDB.Scopes(helpers.Paginate(db, &paginatedResult, opts...))
 
@@ -49,6 +50,59 @@ func (repo *PorterAppEventRepository) ListEventsByPorterAppID(ctx context.Contex
 	return apps, paginatedResult, nil
 }
 
+// likeEscaper escapes the wildcards of a LIKE pattern, so that searches match them literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// ListFilteredEventsByPorterAppID returns the events of a porter app which match the filter, newest first, along with
+// the number of events which match before the limit and offset are applied
+func (repo *PorterAppEventRepository) ListFilteredEventsByPorterAppID(ctx context.Context, porterAppID uint, filter repository.PorterAppEventFilter) ([]*models.PorterAppEvent, int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-filtered-events-by-porter-app-id")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-id", Value: porterAppID},
+		telemetry.AttributeKV{Key: "type", Value: filter.Type},
+		telemetry.AttributeKV{Key: "status", Value: filter.Status},
+		telemetry.AttributeKV{Key: "search", Value: filter.Search},
+		telemetry.AttributeKV{Key: "limit", Value: filter.Limit},
+		telemetry.AttributeKV{Key: "offset", Value: filter.Offset},
+	)
+
+	query := repo.db.WithContext(ctx).Model(&models.PorterAppEvent{}).Where("porter_app_id = ?", porterAppID)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.DeploymentTargetID != nil {
+		query = query.Where("deployment_target_id = ?", *filter.DeploymentTargetID)
+	}
+	for _, term := range strings.Fields(strings.ToLower(filter.Search)) {
+		query = query.Where(`LOWER(message) LIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(term)+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, telemetry.Error(ctx, span, err, "error counting events by porter app id")
+	}
+
+	query = query.Order("created_at DESC")
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	events := []*models.PorterAppEvent{}
+	if err := query.Find(&events).Error; err != nil {
+		return nil, 0, telemetry.Error(ctx, span, err, "error listing events by porter app id")
+	}
+
+	return events, total, nil
+}
+
 // ListEventsByPorterAppIDAndDeploymentTargetID returns a list of events for a given porter app id and deployment target id
 func (repo *PorterAppEventRepository) ListEventsByPorterAppIDAndDeploymentTargetID(ctx context.Context, porterAppID uint, deploymentTargetID uuid.UUID, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error) {
 	ctx, span := telemetry.NewSpan(ctx, "list-events-by-porter-app-id-and-deployment-target-id")
@@ -67,7 +121,7 @@ func (repo *PorterAppEventRepository) ListEventsByPorterAppIDAndDeploymentTarget
 		return nil, paginatedResult, telemetry.Error(ctx, span, nil, "invalid porter app id supplied")
 	}
 
-	db := repo.db.Model(&models.PorterAppEvent{})
+	db := repo.db.WithContext(ctx).Model(&models.PorterAppEvent{})
 	resultDB := db.Where("porter_app_id = ? AND deployment_target_id = ?", id, deploymentTargetID).Order("created_at DESC")
 	resultDB = resultDB.Scopes(helpers.Paginate(db, &paginatedResult, opts...))
 
@@ -100,7 +154,7 @@ func (repo *PorterAppEventRepository) ListBuildDeployEventsByPorterAppIDAndDeplo
 		return nil, paginatedResult, telemetry.Error(ctx, span, nil, "invalid porter app id supplied")
 	}
 
-	db := repo.db.Model(&models.PorterAppEvent{})
+	db := repo.db.WithContext(ctx).Model(&models.PorterAppEvent{})
 	resultDB := db.Where("porter_app_id = ? AND deployment_target_id = ? AND type != 'APP_EVENT' AND type != 'NOTIFICATION'", id, deploymentTargetID).Order("created_at DESC")
 	resultDB = resultDB.Scopes(helpers.Paginate(db, &paginatedResult, opts...))
 
@@ -127,7 +181,36 @@ func (repo *PorterAppEventRepository) CreateEvent(ctx context.Context, appEvent
 		return errors.New("invalid porter app id supplied to create event")
 	}
 
-	if err := repo.db.Create(appEvent).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Create(appEvent).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateEvents creates events in a single multi-row insert, in the order they are given. Either every event is
+// created or none are.
+func (repo *PorterAppEventRepository) CreateEvents(ctx context.Context, appEvents []*models.PorterAppEvent) error {
+	if len(appEvents) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for _, appEvent := range appEvents {
+		if appEvent.ID == uuid.Nil {
+			appEvent.ID = uuid.New()
+		}
+		if appEvent.CreatedAt.IsZero() {
+			appEvent.CreatedAt = now
+		}
+		if appEvent.UpdatedAt.IsZero() {
+			appEvent.UpdatedAt = now
+		}
+		if appEvent.PorterAppID == 0 {
+			return errors.New("invalid porter app id supplied to create events")
+		}
+	}
+
+	if err := repo.db.WithContext(ctx).Create(&appEvents).Error; err != nil {
 		return err
 	}
 	return nil
@@ -147,7 +230,7 @@ func (repo *PorterAppEventRepository) UpdateEvent(ctx context.Context, appEvent
 		appEvent.UpdatedAt = time.Now().UTC()
 	}
 
-	if err := repo.db.Model(appEvent).Updates(appEvent).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Model(appEvent).Updates(appEvent).Error; err != nil {
 		return err
 	}
 	return nil
@@ -162,7 +245,7 @@ func (repo *PorterAppEventRepository) ReadEvent(ctx context.Context, id uuid.UUI
 
 	strID := id.String()
 
-	if err := repo.db.Where("id = ?", strID).First(&appEvent).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Where("id = ?", strID).First(&appEvent).Error; err != nil {
 		return appEvent, err
 	}
 
@@ -182,7 +265,7 @@ func (repo *PorterAppEventRepository) ReadNotificationsByAppRevisionID(ctx conte
 	}
 
 	// TODO: make app_revision_id a column in porter_app_event table: https://linear.app/porter/issue/POR-2096/add-app-revision-id-column-to-porter-app-events-table
-	if err := repo.db.Where("app_instance_id = ? AND type = 'NOTIFICATION' AND metadata->>'app_revision_id' = ?", porterAppInstanceId, appRevisionId).Find(&notifications).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Where("app_instance_id = ? AND type = 'NOTIFICATION' AND metadata->>'app_revision_id' = ?", porterAppInstanceId, appRevisionId).Find(&notifications).Error; err != nil {
 		return notifications, err
 	}
 
@@ -194,7 +277,7 @@ func (repo *PorterAppEventRepository) NotificationByID(ctx context.Context, noti
 	notification := &models.PorterAppEvent{}
 
 	// TODO: make app_revision_id a column in porter_app_event table: https://linear.app/porter/issue/POR-2096/add-app-revision-id-column-to-porter-app-events-table
-	if err := repo.db.Where("type = 'NOTIFICATION' AND metadata->>'id' = ?", notificationID).Find(&notification).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Where("type = 'NOTIFICATION' AND metadata->>'id' = ?", notificationID).Find(&notification).Error; err != nil {
 		return notification, err
 	}
 
@@ -218,7 +301,8 @@ func (repo *PorterAppEventRepository) ReadDeployEventByRevision(ctx context.Cont
 	}
 	strRevision := string(revJSON)
 
-	if err := repo.db.Where("porter_app_id = ? AND type = 'DEPLOY' AND metadata->>'revision' = ?", strAppID, strRevision).First(&appEvent).Error; err != nil {
+	// the cast is a no-op in postgres, where ->> returns text, but is needed in sqlite, where ->> returns numbers as numbers
+	if err := repo.db.WithContext(ctx).Where("porter_app_id = ? AND type = 'DEPLOY' AND CAST(metadata->>'revision' AS TEXT) = ?", strAppID, strRevision).First(&appEvent).Error; err != nil {
 		return appEvent, err
 	}
 
@@ -240,9 +324,74 @@ func (repo *PorterAppEventRepository) ReadDeployEventByAppRevisionID(ctx context
 	// Convert porterAppID to string
 	strAppID := strconv.Itoa(int(porterAppID))
 
-	if err := repo.db.Where("porter_app_id = ? AND type = 'DEPLOY' AND metadata->>'app_revision_id' = ?", strAppID, appRevisionID).First(&appEvent).Error; err != nil {
+	if err := repo.db.WithContext(ctx).Where("porter_app_id = ? AND type = 'DEPLOY' AND metadata->>'app_revision_id' = ?", strAppID, appRevisionID).First(&appEvent).Error; err != nil {
 		return appEvent, err
 	}
 
 	return appEvent, nil
 }
+
+// ListEventsCreatedBetween returns the events of the given types created in [start, end), across all apps
+func (repo *PorterAppEventRepository) ListEventsCreatedBetween(ctx context.Context, start, end time.Time, eventTypes []string) ([]*models.PorterAppEvent, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-events-created-between")
+	defer span.End()
+
+	events := []*models.PorterAppEvent{}
+
+	if err := repo.db.WithContext(ctx).Where("created_at >= ? AND created_at < ? AND type IN ?", start, end, eventTypes).Find(&events).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing events")
+	}
+
+	return events, nil
+}
+
+// ReadLatestEventByType returns the most recent event of a type on a porter app, or gorm.ErrRecordNotFound if it has none
+func (repo *PorterAppEventRepository) ReadLatestEventByType(ctx context.Context, porterAppID uint, eventType string) (*models.PorterAppEvent, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-latest-event-by-type")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "porter-app-id", Value: porterAppID},
+		telemetry.AttributeKV{Key: "event-type", Value: eventType},
+	)
+
+	event := &models.PorterAppEvent{}
+
+	if err := repo.db.WithContext(ctx).Where("porter_app_id = ? AND type = ?", porterAppID, eventType).Order("created_at DESC").First(event).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading latest event")
+	}
+
+	return event, nil
+}
+
+// DeleteEventsCreatedBetween permanently deletes every event created in [start, end), returning the number deleted
+func (repo *PorterAppEventRepository) DeleteEventsCreatedBetween(ctx context.Context, start, end time.Time) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-events-created-between")
+	defer span.End()
+
+	res := repo.db.WithContext(ctx).Unscoped().Where("created_at >= ? AND created_at < ?", start, end).Delete(&models.PorterAppEvent{})
+	if res.Error != nil {
+		return 0, telemetry.Error(ctx, span, res.Error, "error deleting events")
+	}
+
+	return res.RowsAffected, nil
+}
+
+// DeleteEventsByPorterAppID permanently deletes every event of a porter app, returning the number deleted
+func (repo *PorterAppEventRepository) DeleteEventsByPorterAppID(ctx context.Context, porterAppID uint) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-events-by-porter-app-id")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "porter-app-id", Value: porterAppID})
+
+	if porterAppID == 0 {
+		return 0, telemetry.Error(ctx, span, nil, "invalid porter app id supplied")
+	}
+
+	res := repo.db.WithContext(ctx).Unscoped().Where("porter_app_id = ?", porterAppID).Delete(&models.PorterAppEvent{})
+	if res.Error != nil {
+		return 0, telemetry.Error(ctx, span, res.Error, "error deleting events")
+	}
+
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,120 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// PorterAppGrantRepository uses gorm.DB for querying the database
+type PorterAppGrantRepository struct {
+	db *gorm.DB
+}
+
+// NewPorterAppGrantRepository returns a PorterAppGrantRepository which uses
+// gorm.DB for querying the database
+func NewPorterAppGrantRepository(db *gorm.DB) repository.PorterAppGrantRepository {
+	return &PorterAppGrantRepository{db}
+}
+
+// CreatePorterAppGrant creates a new app grant
+func (repo *PorterAppGrantRepository) CreatePorterAppGrant(ctx context.Context, grant *models.PorterAppGrant) (*models.PorterAppGrant, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-porter-app-grant")
+	defer span.End()
+
+	if grant == nil {
+		return nil, telemetry.Error(ctx, span, nil, "app grant is nil")
+	}
+	if grant.ProjectID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "project id is empty")
+	}
+	if (grant.UserID == 0) == (grant.Role == "") {
+		return nil, telemetry.Error(ctx, span, nil, "exactly one of user id and role must be set")
+	}
+
+	if err := repo.db.WithContext(ctx).Create(grant).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating app grant")
+	}
+
+	return grant, nil
+}
+
+// ReadPorterAppGrant returns an app grant by its id, scoped to a project
+func (repo *PorterAppGrantRepository) ReadPorterAppGrant(ctx context.Context, projectID, id uint) (*models.PorterAppGrant, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-porter-app-grant")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "porter-app-grant-id", Value: id},
+	)
+
+	grant := &models.PorterAppGrant{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND id = ?", projectID, id).First(grant).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading app grant")
+	}
+
+	return grant, nil
+}
+
+// ListPorterAppGrantsByProjectID returns every app grant of a project, oldest first
+func (repo *PorterAppGrantRepository) ListPorterAppGrantsByProjectID(ctx context.Context, projectID uint) ([]*models.PorterAppGrant, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-porter-app-grants-by-project-id")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: projectID})
+
+	grants := []*models.PorterAppGrant{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id ASC").Find(&grants).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing app grants")
+	}
+
+	return grants, nil
+}
+
+// ListMatchingPorterAppGrants returns the app grants of a project whose pattern matches an app, and which are for
+// the user or the role. The patterns are matched in the query, since their * become the % of a LIKE.
+func (repo *PorterAppGrantRepository) ListMatchingPorterAppGrants(ctx context.Context, projectID uint, appName string, userID uint, role string) ([]*models.PorterAppGrant, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-matching-porter-app-grants")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "user-id", Value: userID},
+		telemetry.AttributeKV{Key: "role", Value: role},
+	)
+
+	query := repo.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Where("? LIKE REPLACE(app_pattern, '*', '%')", appName)
+
+	if userID != 0 || role != "" {
+		query = query.Where("((user_id <> 0 AND user_id = ?) OR (role <> '' AND role = ?))", userID, role)
+	}
+
+	grants := []*models.PorterAppGrant{}
+
+	if err := query.Order("id ASC").Find(&grants).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing matching app grants")
+	}
+
+	return grants, nil
+}
+
+// DeletePorterAppGrant deletes an app grant
+func (repo *PorterAppGrantRepository) DeletePorterAppGrant(ctx context.Context, grant *models.PorterAppGrant) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-porter-app-grant")
+	defer span.End()
+
+	if err := repo.db.WithContext(ctx).Delete(grant).Error; err != nil {
+		return telemetry.Error(ctx, span, err, "error deleting app grant")
+	}
+
+	return nil
+}
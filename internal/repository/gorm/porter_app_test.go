@@ -0,0 +1,77 @@
+package gorm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestScopedPorterAppReads(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_scoped_porter_app_reads.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	// two projects with an identically named app in each of their clusters
+	apps := make([]*models.PorterApp, 0, 2)
+	for _, id := range []uint{1, 2} {
+		app, err := tester.repo.PorterApp().CreatePorterApp(&models.PorterApp{
+			ProjectID: id,
+			ClusterID: id,
+			Name:      "web",
+		})
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		apps = append(apps, app)
+	}
+
+	app, err := tester.repo.PorterApp().ReadScopedPorterAppByName(1, 1, "web")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if app.ID != apps[0].ID {
+		t.Errorf("expected app %d, got %d", apps[0].ID, app.ID)
+	}
+
+	app, err = tester.repo.PorterApp().ReadScopedPorterAppByID(context.Background(), 2, apps[1].ID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if app.ID != apps[1].ID {
+		t.Errorf("expected app %d, got %d", apps[1].ID, app.ID)
+	}
+
+	// reading another project's app, by name or by ID, must look like the app does not exist
+	_, err = tester.repo.PorterApp().ReadScopedPorterAppByName(1, 2, "web")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected record not found reading by name, got %v", err)
+	}
+
+	_, err = tester.repo.PorterApp().ReadScopedPorterAppByID(context.Background(), 1, apps[1].ID)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected record not found reading by id, got %v", err)
+	}
+
+	list, err := tester.repo.PorterApp().ListScopedPorterAppsByClusterID(1, 2)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("expected no apps listing another project's cluster, got %d", len(list))
+	}
+
+	list, err = tester.repo.PorterApp().ListScopedPorterAppsByClusterID(2, 2)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if len(list) != 1 || list[0].ID != apps[1].ID {
+		t.Errorf("expected only app %d listing own cluster, got %v", apps[1].ID, list)
+	}
+}
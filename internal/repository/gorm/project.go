@@ -1,10 +1,17 @@
 package gorm
 
 import (
+	"context"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
+	"github.com/porter-dev/porter/internal/telemetry"
 	"gorm.io/gorm"
 )
 
@@ -104,6 +111,181 @@ func (repo *ProjectRepository) ListProjectsByUserID(userID uint) ([]*models.Proj
 	return projects, nil
 }
 
+// projectSummaryRow is a single row of the aggregate query used by ListProjectSummariesByUserID
+type projectSummaryRow struct {
+	ProjectID      uint
+	RoleKind       string
+	NumApps        int64
+	NumClusters    int64
+	LastActivityAt aggregateTime
+}
+
+// aggregateTime scans the result of an aggregate over a timestamp column. Postgres returns a time.Time,
+// but sqlite loses the column type on aggregates and returns the timestamp as a string.
+type aggregateTime struct {
+	Time *time.Time
+}
+
+// aggregateTimeLayouts are the layouts sqlite uses when writing timestamps
+var aggregateTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// Scan implements sql.Scanner
+func (a *aggregateTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		a.Time = nil
+	case time.Time:
+		a.Time = &v
+	case []byte:
+		return a.Scan(string(v))
+	case string:
+		for _, layout := range aggregateTimeLayouts {
+			if t, err := time.ParseInLocation(layout, v, time.UTC); err == nil {
+				a.Time = &t
+				return nil
+			}
+		}
+
+		return fmt.Errorf("unable to parse timestamp %s", v)
+	default:
+		return fmt.Errorf("unsupported timestamp type %T", value)
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer
+func (a aggregateTime) Value() (driver.Value, error) {
+	if a.Time == nil {
+		return nil, nil
+	}
+
+	return *a.Time, nil
+}
+
+// ListProjectSummariesByUserID returns a page of the projects where a user has an associated role, along with the user's role
+// and the app count, cluster count and latest deploy time of each project. All aggregates are computed in a single query
+// rather than per project.
+func (repo *ProjectRepository) ListProjectSummariesByUserID(
+	ctx context.Context,
+	userID uint,
+	filter repository.ProjectSummaryFilter,
+	opts ...helpers.QueryOption,
+) ([]*models.ProjectSummary, helpers.PaginatedResult, error) {
+	ctx, span := telemetry.NewSpan(ctx, "list-project-summaries-by-user-id")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "user-id", Value: userID},
+		telemetry.AttributeKV{Key: "search", Value: filter.Search},
+		telemetry.AttributeKV{Key: "sort-by", Value: string(filter.SortBy)},
+	)
+
+	paginatedResult := helpers.PaginatedResult{}
+
+	if userID == 0 {
+		return nil, paginatedResult, telemetry.Error(ctx, span, nil, "user id cannot be 0")
+	}
+
+	var order string
+	switch filter.SortBy {
+	case types.ProjectListSortName, "":
+		order = "LOWER(projects.name) ASC, projects.id ASC"
+	case types.ProjectListSortCreatedAt:
+		order = "projects.created_at DESC, projects.id DESC"
+	case types.ProjectListSortRecentlyActive:
+		order = "CASE WHEN activity.last_activity_at IS NULL THEN 1 ELSE 0 END ASC, activity.last_activity_at DESC, projects.id DESC"
+	default:
+		return nil, paginatedResult, telemetry.Error(ctx, span, nil, fmt.Sprintf("invalid sort option %s", filter.SortBy))
+	}
+
+	// userProjects returns a fresh query over the projects the user has a role in, so that the count and the
+	// page query do not share statement state
+	userProjects := func() *gorm.DB {
+		query := repo.db.WithContext(ctx).Table("projects").
+			Joins("JOIN roles ON roles.project_id = projects.id AND roles.user_id = ? AND roles.deleted_at IS NULL", userID).
+			Where("projects.deleted_at IS NULL")
+
+		if filter.Search != "" {
+			query = query.Where("LOWER(projects.name) LIKE LOWER(?)", "%"+filter.Search+"%")
+		}
+
+		return query
+	}
+
+	appCounts := repo.db.WithContext(ctx).Table("porter_apps").
+		Select("project_id, COUNT(*) AS num_apps").
+		Where("deleted_at IS NULL").
+		Group("project_id")
+
+	clusterCounts := repo.db.WithContext(ctx).Table("clusters").
+		Select("project_id, COUNT(*) AS num_clusters").
+		Where("deleted_at IS NULL").
+		Group("project_id")
+
+	latestDeploys := repo.db.WithContext(ctx).Table("porter_app_events").
+		Select("porter_apps.project_id AS project_id, MAX(porter_app_events.created_at) AS last_activity_at").
+		Joins("JOIN porter_apps ON porter_apps.id = porter_app_events.porter_app_id").
+		Where("porter_app_events.type = ? AND porter_app_events.deleted_at IS NULL", types.PorterAppEventType_Deploy).
+		Group("porter_apps.project_id")
+
+	rows := []projectSummaryRow{}
+
+	err := userProjects().
+		Select("projects.id AS project_id, roles.kind AS role_kind, COALESCE(apps.num_apps, 0) AS num_apps, COALESCE(cluster_counts.num_clusters, 0) AS num_clusters, activity.last_activity_at AS last_activity_at").
+		Joins("LEFT JOIN (?) AS apps ON apps.project_id = projects.id", appCounts).
+		Joins("LEFT JOIN (?) AS cluster_counts ON cluster_counts.project_id = projects.id", clusterCounts).
+		Joins("LEFT JOIN (?) AS activity ON activity.project_id = projects.id", latestDeploys).
+		Order(order).
+		Scopes(helpers.Paginate(userProjects(), &paginatedResult, opts...)).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, paginatedResult, telemetry.Error(ctx, span, err, "error listing project summaries")
+	}
+
+	if len(rows) == 0 {
+		return []*models.ProjectSummary{}, paginatedResult, nil
+	}
+
+	projectIDs := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		projectIDs = append(projectIDs, row.ProjectID)
+	}
+
+	projects := make([]*models.Project, 0, len(rows))
+	if err := repo.db.WithContext(ctx).Preload("Roles").Where("id IN (?)", projectIDs).Find(&projects).Error; err != nil {
+		return nil, paginatedResult, telemetry.Error(ctx, span, err, "error reading projects for summaries")
+	}
+
+	projectsByID := make(map[uint]*models.Project, len(projects))
+	for _, project := range projects {
+		projectsByID[project.ID] = project
+	}
+
+	summaries := make([]*models.ProjectSummary, 0, len(rows))
+	for _, row := range rows {
+		project, ok := projectsByID[row.ProjectID]
+		if !ok {
+			continue
+		}
+
+		summaries = append(summaries, &models.ProjectSummary{
+			Project:        project,
+			RoleKind:       types.RoleKind(row.RoleKind),
+			NumApps:        row.NumApps,
+			NumClusters:    row.NumClusters,
+			LastActivityAt: row.LastActivityAt.Time,
+		})
+	}
+
+	return summaries, paginatedResult, nil
+}
+
 // ReadProject gets a projects specified by a unique id
 func (repo *ProjectRepository) ListProjectRoles(projID uint) ([]models.Role, error) {
 	project := &models.Project{}
@@ -115,6 +297,19 @@ func (repo *ProjectRepository) ListProjectRoles(projID uint) ([]models.Role, err
 	return project.Roles, nil
 }
 
+// CountProjects returns the number of projects of the instance
+func (repo *ProjectRepository) CountProjects(ctx context.Context) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-count-projects")
+	defer span.End()
+
+	var count int64
+	if err := repo.db.WithContext(ctx).Model(&models.Project{}).Count(&count).Error; err != nil {
+		return 0, telemetry.Error(ctx, span, err, "error counting projects")
+	}
+
+	return count, nil
+}
+
 // DeleteProject deletes a project (marking deleted in the db)
 func (repo *ProjectRepository) DeleteProject(project *models.Project) (*models.Project, error) {
 	if err := repo.db.Delete(&project).Error; err != nil {
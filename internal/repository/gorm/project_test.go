@@ -1,11 +1,15 @@
 package gorm_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
 
 	"gorm.io/gorm"
 	orm "gorm.io/gorm"
@@ -241,6 +245,125 @@ func TestListProjectsByUserID(t *testing.T) {
 	}
 }
 
+func TestListProjectSummariesByUserID(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./list_project_summaries_user_id.db",
+	}
+
+	setupTestEnv(tester, t)
+	initMultiUser(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+
+	// project 1 is never deployed to, project 2 has an app with two deploys, project 3 belongs to another user
+	for i, name := range []string{"quiet-project", "busy-project", "other-project"} {
+		proj, err := tester.repo.Project().CreateProject(&models.Project{Name: name})
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		userID := tester.initUsers[0].ID
+		kind := types.RoleAdmin
+		if i == 1 {
+			kind = types.RoleDeveloper
+		}
+		if i == 2 {
+			userID = tester.initUsers[1].ID
+		}
+
+		_, err = tester.repo.Project().CreateProjectRole(proj, &models.Role{
+			Role: types.Role{
+				Kind:      kind,
+				UserID:    userID,
+				ProjectID: proj.ID,
+			},
+		})
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		tester.initProjects = append(tester.initProjects, proj)
+	}
+
+	cluster := &models.Cluster{ProjectID: tester.initProjects[1].ID, Name: "cluster-test"}
+	if err := tester.db.Create(cluster).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	app, err := tester.repo.PorterApp().CreatePorterApp(&models.PorterApp{
+		ProjectID: tester.initProjects[1].ID,
+		ClusterID: cluster.ID,
+		Name:      "web",
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	latestDeploy := time.Date(2023, 6, 2, 0, 0, 0, 0, time.UTC)
+	for _, createdAt := range []time.Time{latestDeploy.Add(-time.Hour), latestDeploy} {
+		err := tester.repo.PorterAppEvent().CreateEvent(ctx, &models.PorterAppEvent{
+			PorterAppID: app.ID,
+			Type:        string(types.PorterAppEventType_Deploy),
+			Status:      string(types.PorterAppEventStatus_Success),
+			CreatedAt:   createdAt,
+		})
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	summaries, paginatedResult, err := tester.repo.Project().ListProjectSummariesByUserID(
+		ctx,
+		tester.initUsers[0].ID,
+		repository.ProjectSummaryFilter{SortBy: types.ProjectListSortRecentlyActive},
+		helpers.WithPage(1),
+	)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if paginatedResult.NumPages != 1 {
+		t.Errorf("incorrect number of pages: expected %d, got %d", 1, paginatedResult.NumPages)
+	}
+
+	if len(summaries) != 2 {
+		t.Fatalf("incorrect number of summaries: expected %d, got %d", 2, len(summaries))
+	}
+
+	busy, quiet := summaries[0], summaries[1]
+
+	if busy.Project.Name != "busy-project" || quiet.Project.Name != "quiet-project" {
+		t.Fatalf("incorrect order: got %s, %s", busy.Project.Name, quiet.Project.Name)
+	}
+
+	if busy.RoleKind != types.RoleDeveloper || busy.NumApps != 1 || busy.NumClusters != 1 {
+		t.Errorf("incorrect summary for busy project: %+v", busy)
+	}
+
+	if busy.LastActivityAt == nil || !busy.LastActivityAt.Equal(latestDeploy) {
+		t.Errorf("incorrect last activity for busy project: expected %s, got %v", latestDeploy, busy.LastActivityAt)
+	}
+
+	if quiet.RoleKind != types.RoleAdmin || quiet.NumApps != 0 || quiet.NumClusters != 0 || quiet.LastActivityAt != nil {
+		t.Errorf("incorrect summary for quiet project: %+v", quiet)
+	}
+
+	summaries, _, err = tester.repo.Project().ListProjectSummariesByUserID(
+		ctx,
+		tester.initUsers[0].ID,
+		repository.ProjectSummaryFilter{Search: "QUIET"},
+		helpers.WithPage(1),
+	)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(summaries) != 1 || summaries[0].Project.Name != "quiet-project" {
+		t.Errorf("search did not filter projects: got %d summaries", len(summaries))
+	}
+}
+
 func TestReadProjectRole(t *testing.T) {
 	tester := &tester{
 		dbFileName: "./get_project_role.db",
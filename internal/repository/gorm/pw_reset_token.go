@@ -46,3 +46,10 @@ func (repo *PWResetTokenRepository) UpdatePWResetToken(
 
 	return pwToken, nil
 }
+
+// InvalidatePWResetTokensByEmail invalidates every valid PWResetToken for an email which was created for a purpose
+func (repo *PWResetTokenRepository) InvalidatePWResetTokensByEmail(email string, purpose models.PWResetTokenPurpose) error {
+	return repo.db.Model(&models.PWResetToken{}).
+		Where("email = ? AND purpose = ? AND is_valid = ?", email, purpose, true).
+		Update("is_valid", false).Error
+}
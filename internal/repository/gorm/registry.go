@@ -146,6 +146,35 @@ func (repo *RegistryRepository) UpdateRegistryTokenCache(
 	return registry, nil
 }
 
+// UpdateRegistryCredentialStatus writes the credential status of a registry, leaving its other columns as they are
+func (repo *RegistryRepository) UpdateRegistryCredentialStatus(
+	reg *models.Registry,
+) error {
+	return repo.db.Model(&models.Registry{}).Where("id = ?", reg.ID).UpdateColumns(map[string]interface{}{
+		"credential_status":     reg.CredentialStatus,
+		"credential_error":      reg.CredentialError,
+		"credential_checked_at": reg.CredentialCheckedAt,
+	}).Error
+}
+
+// ListRegistriesByCredentialStatus finds all registries whose credentials
+// were last recorded with the given status
+func (repo *RegistryRepository) ListRegistriesByCredentialStatus(
+	status string,
+) ([]*models.Registry, error) {
+	regs := []*models.Registry{}
+
+	if err := repo.db.Preload("TokenCache").Where("credential_status = ?", status).Find(&regs).Error; err != nil {
+		return nil, err
+	}
+
+	for _, reg := range regs {
+		repo.DecryptRegistryData(reg, repo.key)
+	}
+
+	return regs, nil
+}
+
 // DeleteRegistry removes a registry from the db
 func (repo *RegistryRepository) DeleteRegistry(
 	reg *models.Registry,
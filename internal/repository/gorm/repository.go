@@ -59,7 +59,22 @@ type GormRepository struct {
 	githubWebhook             repository.GithubWebhookRepository
 	datastore                 repository.DatastoreRepository
 	appInstance               repository.AppInstanceRepository
+	logAlertRule              repository.LogAlertRuleRepository
+	helmReleaseImport         repository.HelmReleaseImportRepository
+	bulkRedeploy              repository.BulkRedeployRepository
+	usageRollup               repository.UsageRollupRepository
+	inactivityPolicy          repository.InactivityPolicyRepository
+	secretsProvider           repository.SecretsProviderIntegrationRepository
 	ipam                      repository.IpamRepository
+	debugRecording            repository.DebugRecordingRepository
+	auditLog                  repository.AuditLogRepository
+	lock                      repository.LockRepository
+	webhookDelivery           repository.WebhookDeliveryRepository
+	porterAppGrant            repository.PorterAppGrantRepository
+	tokenCache                repository.TokenCacheRepository
+	porterAppCleanup          repository.PorterAppCleanupRepository
+	porterAppDomain           repository.PorterAppDomainRepository
+	userTwoFactor             repository.UserTwoFactorRepository
 }
 
 func (t *GormRepository) User() repository.UserRepository {
@@ -276,11 +291,86 @@ func (t *GormRepository) AppInstance() repository.AppInstanceRepository {
 	return t.appInstance
 }
 
+// LogAlertRule returns the LogAlertRuleRepository interface implemented by gorm
+func (t *GormRepository) LogAlertRule() repository.LogAlertRuleRepository {
+	return t.logAlertRule
+}
+
+// HelmReleaseImport returns the HelmReleaseImportRepository interface implemented by gorm
+func (t *GormRepository) HelmReleaseImport() repository.HelmReleaseImportRepository {
+	return t.helmReleaseImport
+}
+
+// BulkRedeploy returns the BulkRedeployRepository interface implemented by gorm
+func (t *GormRepository) BulkRedeploy() repository.BulkRedeployRepository {
+	return t.bulkRedeploy
+}
+
+// UsageRollup returns the UsageRollupRepository interface implemented by gorm
+func (t *GormRepository) UsageRollup() repository.UsageRollupRepository {
+	return t.usageRollup
+}
+
+// InactivityPolicy returns the InactivityPolicyRepository interface implemented by gorm
+func (t *GormRepository) InactivityPolicy() repository.InactivityPolicyRepository {
+	return t.inactivityPolicy
+}
+
+// SecretsProviderIntegration returns the SecretsProviderIntegrationRepository interface implemented by gorm
+func (t *GormRepository) SecretsProviderIntegration() repository.SecretsProviderIntegrationRepository {
+	return t.secretsProvider
+}
+
 // Ipam returns the IpamRepository interface implemented by gorm
 func (t *GormRepository) Ipam() repository.IpamRepository {
 	return t.ipam
 }
 
+// DebugRecording returns the DebugRecordingRepository interface implemented by gorm
+func (t *GormRepository) DebugRecording() repository.DebugRecordingRepository {
+	return t.debugRecording
+}
+
+// AuditLog returns the AuditLogRepository interface implemented by gorm
+func (t *GormRepository) AuditLog() repository.AuditLogRepository {
+	return t.auditLog
+}
+
+// Lock returns the LockRepository interface implemented by gorm
+func (t *GormRepository) Lock() repository.LockRepository {
+	return t.lock
+}
+
+// WebhookDelivery returns the WebhookDeliveryRepository interface implemented by gorm
+func (t *GormRepository) WebhookDelivery() repository.WebhookDeliveryRepository {
+	return t.webhookDelivery
+}
+
+// PorterAppGrant returns the PorterAppGrantRepository interface implemented by gorm
+func (t *GormRepository) PorterAppGrant() repository.PorterAppGrantRepository {
+	return t.porterAppGrant
+}
+
+// TokenCache returns the TokenCacheRepository interface implemented by gorm
+func (t *GormRepository) TokenCache() repository.TokenCacheRepository {
+	return t.tokenCache
+}
+
+// PorterAppCleanup returns the PorterAppCleanupRepository interface implemented by gorm
+func (t *GormRepository) PorterAppCleanup() repository.PorterAppCleanupRepository {
+	return t.porterAppCleanup
+}
+
+// PorterAppDomain returns the PorterAppDomainRepository interface implemented by gorm
+func (t *GormRepository) PorterAppDomain() repository.PorterAppDomainRepository {
+	return t.porterAppDomain
+}
+
+// UserTwoFactor returns the UserTwoFactorRepository interface implemented by gorm
+func (t *GormRepository) UserTwoFactor() repository.UserTwoFactorRepository {
+	return t.userTwoFactor
+}
+
 // NewRepository returns a Repository which persists users in memory
 // and accepts a parameter that can trigger read/write errors
 func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.CredentialStorage) repository.Repository {
@@ -337,6 +427,21 @@ func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.Creden
 		githubWebhook:             NewGithubWebhookRepository(db),
 		datastore:                 NewDatastoreRepository(db),
 		appInstance:               NewAppInstanceRepository(db),
+		logAlertRule:              NewLogAlertRuleRepository(db),
+		helmReleaseImport:         NewHelmReleaseImportRepository(db),
+		bulkRedeploy:              NewBulkRedeployRepository(db),
+		usageRollup:               NewUsageRollupRepository(db),
+		inactivityPolicy:          NewInactivityPolicyRepository(db),
+		secretsProvider:           NewSecretsProviderIntegrationRepository(db, key),
 		ipam:                      NewIpamRepository(db),
+		debugRecording:            NewDebugRecordingRepository(db),
+		auditLog:                  NewAuditLogRepository(db),
+		lock:                      NewLockRepository(db),
+		webhookDelivery:           NewWebhookDeliveryRepository(db),
+		porterAppGrant:            NewPorterAppGrantRepository(db),
+		tokenCache:                NewTokenCacheRepository(db),
+		porterAppCleanup:          NewPorterAppCleanupRepository(db),
+		porterAppDomain:           NewPorterAppDomainRepository(db),
+		userTwoFactor:             NewUserTwoFactorRepository(db, key),
 	}
 }
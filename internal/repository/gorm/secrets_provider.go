@@ -0,0 +1,160 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/encryption"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// SecretsProviderIntegrationRepository uses gorm.DB for querying the database
+type SecretsProviderIntegrationRepository struct {
+	db  *gorm.DB
+	key *[32]byte
+}
+
+// NewSecretsProviderIntegrationRepository returns a SecretsProviderIntegrationRepository which uses
+// gorm.DB for querying the database. It accepts an encryption key to encrypt
+// the credentials of each integration
+func NewSecretsProviderIntegrationRepository(db *gorm.DB, key *[32]byte) repository.SecretsProviderIntegrationRepository {
+	return &SecretsProviderIntegrationRepository{db, key}
+}
+
+// ReadSecretsProviderIntegration returns the integration of a project for a provider, with its credentials decrypted
+func (repo *SecretsProviderIntegrationRepository) ReadSecretsProviderIntegration(ctx context.Context, projectID uint, provider types.SecretsProvider) (*ints.SecretsProviderIntegration, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-secrets-provider-integration")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "provider", Value: string(provider)},
+	)
+
+	integration := &ints.SecretsProviderIntegration{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND provider = ?", projectID, provider).First(integration).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading secrets provider integration")
+	}
+
+	if err := repo.decrypt(integration); err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error decrypting secrets provider integration")
+	}
+
+	return integration, nil
+}
+
+// ListSecretsProviderIntegrations returns the integrations of a project, with their credentials decrypted
+func (repo *SecretsProviderIntegrationRepository) ListSecretsProviderIntegrations(ctx context.Context, projectID uint) ([]*ints.SecretsProviderIntegration, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-secrets-provider-integrations")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: projectID})
+
+	integrations := []*ints.SecretsProviderIntegration{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectID).Order("provider ASC").Find(&integrations).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing secrets provider integrations")
+	}
+
+	for _, integration := range integrations {
+		if err := repo.decrypt(integration); err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error decrypting secrets provider integration")
+		}
+	}
+
+	return integrations, nil
+}
+
+// CreateOrUpdateSecretsProviderIntegration creates the integration of a project for a provider, or replaces the existing one
+func (repo *SecretsProviderIntegrationRepository) CreateOrUpdateSecretsProviderIntegration(ctx context.Context, integration *ints.SecretsProviderIntegration) (*ints.SecretsProviderIntegration, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-or-update-secrets-provider-integration")
+	defer span.End()
+
+	if integration == nil {
+		return nil, telemetry.Error(ctx, span, nil, "secrets provider integration is nil")
+	}
+	if integration.ProjectID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "project id is empty")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: integration.ProjectID},
+		telemetry.AttributeKV{Key: "provider", Value: string(integration.Provider)},
+	)
+
+	existing := &ints.SecretsProviderIntegration{}
+	err := repo.db.WithContext(ctx).Where("project_id = ? AND provider = ?", integration.ProjectID, integration.Provider).First(existing).Error
+	switch {
+	case err == nil:
+		integration.ID = existing.ID
+		integration.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, telemetry.Error(ctx, span, err, "error reading existing secrets provider integration")
+	}
+
+	// the caller keeps the plaintext credentials, so a copy is encrypted and saved
+	saved := *integration
+	if err := repo.encrypt(&saved); err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error encrypting secrets provider integration")
+	}
+
+	if err := repo.db.WithContext(ctx).Save(&saved).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error saving secrets provider integration")
+	}
+
+	integration.Model = saved.Model
+
+	return integration, nil
+}
+
+// DeleteSecretsProviderIntegration deletes the integration of a project for a provider. The row is removed rather than
+// soft deleted, so that a new integration can be created for the provider.
+func (repo *SecretsProviderIntegrationRepository) DeleteSecretsProviderIntegration(ctx context.Context, integration *ints.SecretsProviderIntegration) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-secrets-provider-integration")
+	defer span.End()
+
+	if err := repo.db.WithContext(ctx).Unscoped().Delete(integration).Error; err != nil {
+		return telemetry.Error(ctx, span, err, "error deleting secrets provider integration")
+	}
+
+	return nil
+}
+
+func (repo *SecretsProviderIntegrationRepository) encrypt(integration *ints.SecretsProviderIntegration) error {
+	for _, field := range []*[]byte{&integration.VaultToken, &integration.VaultRoleID, &integration.VaultSecretID} {
+		if len(*field) == 0 {
+			continue
+		}
+
+		cipherData, err := encryption.Encrypt(*field, repo.key)
+		if err != nil {
+			return err
+		}
+
+		*field = cipherData
+	}
+
+	return nil
+}
+
+func (repo *SecretsProviderIntegrationRepository) decrypt(integration *ints.SecretsProviderIntegration) error {
+	for _, field := range []*[]byte{&integration.VaultToken, &integration.VaultRoleID, &integration.VaultSecretID} {
+		if len(*field) == 0 {
+			continue
+		}
+
+		plaintext, err := encryption.Decrypt(*field, repo.key)
+		if err != nil {
+			return err
+		}
+
+		*field = plaintext
+	}
+
+	return nil
+}
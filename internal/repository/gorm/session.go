@@ -1,8 +1,12 @@
 package gorm
 
 import (
+	"context"
+	"time"
+
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
 	"gorm.io/gorm"
 )
 
@@ -49,3 +53,55 @@ func (s *SessionRepository) SelectSession(session *models.Session) (*models.Sess
 
 	return session, nil
 }
+
+// TouchSession records that the session with a key was used, moving its expiry to expiresAt
+func (s *SessionRepository) TouchSession(ctx context.Context, key string, lastUsedAt time.Time, expiresAt time.Time) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-touch-session")
+	defer span.End()
+
+	err := s.db.WithContext(ctx).Model(&models.Session{}).Where("Key = ?", key).Updates(map[string]interface{}{
+		"last_used_at": lastUsedAt,
+		"expires_at":   expiresAt,
+	}).Error
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error touching session")
+	}
+
+	return nil
+}
+
+// DeleteExpiredSessions deletes up to limit sessions which expired before a time, returning how many were deleted
+func (s *SessionRepository) DeleteExpiredSessions(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-expired-sessions")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "before", Value: before},
+		telemetry.AttributeKV{Key: "limit", Value: limit},
+	)
+
+	expired := s.db.WithContext(ctx).Unscoped().Model(&models.Session{}).Select("id").Where("expires_at < ?", before).Order("id")
+	if limit > 0 {
+		expired = expired.Limit(limit)
+	}
+
+	res := s.db.WithContext(ctx).Unscoped().Where("id IN (?)", expired).Delete(&models.Session{})
+	if res.Error != nil {
+		return 0, telemetry.Error(ctx, span, res.Error, "error deleting expired sessions")
+	}
+
+	return res.RowsAffected, nil
+}
+
+// CountSessions returns the number of stored sessions
+func (s *SessionRepository) CountSessions(ctx context.Context) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-count-sessions")
+	defer span.End()
+
+	var count int64
+	if err := s.db.WithContext(ctx).Unscoped().Model(&models.Session{}).Count(&count).Error; err != nil {
+		return 0, telemetry.Error(ctx, span, err, "error counting sessions")
+	}
+
+	return count, nil
+}
@@ -0,0 +1,115 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// TokenCacheRepository uses gorm.DB for querying the database
+type TokenCacheRepository struct {
+	db *gorm.DB
+}
+
+// NewTokenCacheRepository returns a TokenCacheRepository which uses gorm.DB for querying the database
+func NewTokenCacheRepository(db *gorm.DB) repository.TokenCacheRepository {
+	return &TokenCacheRepository{db}
+}
+
+// tokenCacheTable is how the entries of a token cache table are queried
+type tokenCacheTable struct {
+	model interface{}
+	// inUse matches the entries which are still read by their owner, and so may be refreshed at any time
+	inUse string
+}
+
+var tokenCacheTables = map[repository.TokenCacheTable]tokenCacheTable{
+	repository.TokenCacheTable_Base: {
+		model: &ints.TokenCache{},
+	},
+	// a cluster reads the entry its token_cache_id references, which is refreshed in place
+	repository.TokenCacheTable_Cluster: {
+		model: &ints.ClusterTokenCache{},
+		inUse: "EXISTS (SELECT 1 FROM clusters WHERE clusters.token_cache_id = cluster_token_caches.id)",
+	},
+	// registries and helm repos preload their newest entry, and every refresh saves a new one
+	repository.TokenCacheTable_Registry: {
+		model: &ints.RegTokenCache{},
+		inUse: "(EXISTS (SELECT 1 FROM registries WHERE registries.id = reg_token_caches.registry_id AND registries.deleted_at IS NULL) AND " +
+			"id IN (SELECT MAX(id) FROM reg_token_caches WHERE deleted_at IS NULL GROUP BY registry_id))",
+	},
+	repository.TokenCacheTable_HelmRepo: {
+		model: &ints.HelmRepoTokenCache{},
+		inUse: "(EXISTS (SELECT 1 FROM helm_repos WHERE helm_repos.id = helm_repo_token_caches.helm_repo_id AND helm_repos.deleted_at IS NULL) AND " +
+			"id IN (SELECT MAX(id) FROM helm_repo_token_caches WHERE deleted_at IS NULL GROUP BY helm_repo_id))",
+	},
+}
+
+// DeleteExpiredTokenCaches deletes up to limit entries of a table which expired before a time, returning how many
+// were deleted
+func (repo *TokenCacheRepository) DeleteExpiredTokenCaches(ctx context.Context, table repository.TokenCacheTable, before time.Time, limit int) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-expired-token-caches")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "table", Value: string(table)},
+		telemetry.AttributeKV{Key: "before", Value: before},
+		telemetry.AttributeKV{Key: "limit", Value: limit},
+	)
+
+	t, ok := tokenCacheTables[table]
+	if !ok {
+		return 0, telemetry.Error(ctx, span, fmt.Errorf("unknown token cache table %s", table), "error deleting expired token caches")
+	}
+
+	expired := repo.db.WithContext(ctx).Unscoped().Model(t.model).Where("expiry < ?", before).Order("id")
+	if limit > 0 {
+		expired = expired.Limit(limit)
+	}
+	if t.inUse != "" {
+		expired = expired.Where("NOT " + t.inUse)
+	}
+
+	// the ids are read before they are deleted, since MySQL neither limits an IN subquery nor deletes from a table which
+	// a subquery of the delete reads
+	var ids []uint
+	if err := expired.Pluck("id", &ids).Error; err != nil {
+		return 0, telemetry.Error(ctx, span, err, "error listing expired token caches")
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// an entry which was refreshed since it was read is kept
+	res := repo.db.WithContext(ctx).Unscoped().Where("id IN ? AND expiry < ?", ids, before).Delete(t.model)
+	if res.Error != nil {
+		return 0, telemetry.Error(ctx, span, res.Error, "error deleting expired token caches")
+	}
+
+	return res.RowsAffected, nil
+}
+
+// CountTokenCaches returns the number of entries in a table
+func (repo *TokenCacheRepository) CountTokenCaches(ctx context.Context, table repository.TokenCacheTable) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-count-token-caches")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "table", Value: string(table)})
+
+	t, ok := tokenCacheTables[table]
+	if !ok {
+		return 0, telemetry.Error(ctx, span, fmt.Errorf("unknown token cache table %s", table), "error counting token caches")
+	}
+
+	var count int64
+	if err := repo.db.WithContext(ctx).Unscoped().Model(t.model).Count(&count).Error; err != nil {
+		return 0, telemetry.Error(ctx, span, err, "error counting token caches")
+	}
+
+	return count, nil
+}
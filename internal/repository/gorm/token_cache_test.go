@@ -0,0 +1,113 @@
+package gorm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func TestDeleteExpiredTokenCaches(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_delete_expired_token_caches.db",
+	}
+
+	setupTestEnv(tester, t)
+	initProject(tester, t)
+	initCluster(tester, t)
+	initRegistry(tester, t)
+	initRegistry(tester, t)
+	initHelmRepo(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+	expired := time.Now().Add(-24 * time.Hour)
+	live := time.Now().Add(time.Hour)
+
+	cluster := tester.initClusters[0]
+	reg, deletedReg := tester.initRegs[0], tester.initRegs[1]
+	hr := tester.initHRs[0]
+
+	// a cluster entry which the cluster no longer references
+	staleClusterCache := &ints.ClusterTokenCache{ClusterID: cluster.ID, TokenCache: ints.TokenCache{Expiry: expired}}
+	// the first entries of the registries, the first of which is superseded by a refresh
+	originalRegCache := &ints.RegTokenCache{RegistryID: reg.ID, TokenCache: ints.TokenCache{Expiry: expired, Token: []byte("original")}}
+	deletedRegCache := &ints.RegTokenCache{RegistryID: deletedReg.ID, TokenCache: ints.TokenCache{Expiry: expired}}
+	newestRegCache := &ints.RegTokenCache{RegistryID: reg.ID, TokenCache: ints.TokenCache{Expiry: expired, Token: []byte("newest")}}
+	// an orphaned entry which has not expired yet
+	liveRegCache := &ints.RegTokenCache{TokenCache: ints.TokenCache{Expiry: live}}
+	// a helm repo entry which a refresh superseded, and the newest entry of the helm repo
+	originalHRCache := &ints.HelmRepoTokenCache{HelmRepoID: hr.ID, TokenCache: ints.TokenCache{Expiry: expired}}
+	newestHRCache := &ints.HelmRepoTokenCache{HelmRepoID: hr.ID, TokenCache: ints.TokenCache{Expiry: expired}}
+
+	for _, cache := range []interface{}{staleClusterCache, originalRegCache, deletedRegCache, newestRegCache, liveRegCache, originalHRCache, newestHRCache} {
+		if err := tester.db.Create(cache).Error; err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	if err := tester.repo.Registry().DeleteRegistry(deletedReg); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	deleted, err := tester.repo.TokenCache().DeleteExpiredTokenCaches(ctx, repository.TokenCacheTable_Cluster, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected the unreferenced cluster token cache to be deleted, got %d deleted", deleted)
+	}
+
+	// the registries' original entries are superseded or orphaned, and are deleted one batch at a time
+	deleted, err = tester.repo.TokenCache().DeleteExpiredTokenCaches(ctx, repository.TokenCacheTable_Registry, time.Now(), 1)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected the limit of 1 registry token cache to be deleted, got %d deleted", deleted)
+	}
+
+	deleted, err = tester.repo.TokenCache().DeleteExpiredTokenCaches(ctx, repository.TokenCacheTable_Registry, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected the remaining expired registry token cache to be deleted, got %d deleted", deleted)
+	}
+
+	deleted, err = tester.repo.TokenCache().DeleteExpiredTokenCaches(ctx, repository.TokenCacheTable_HelmRepo, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected the superseded helm repo token cache to be deleted, got %d deleted", deleted)
+	}
+
+	for table, want := range map[repository.TokenCacheTable]int64{
+		repository.TokenCacheTable_Cluster:  1,
+		repository.TokenCacheTable_Registry: 2,
+		repository.TokenCacheTable_HelmRepo: 1,
+	} {
+		count, err := tester.repo.TokenCache().CountTokenCaches(ctx, table)
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		if count != want {
+			t.Errorf("expected %d %s to be left, got %d", want, table, count)
+		}
+	}
+
+	if _, err := tester.repo.Cluster().ReadCluster(cluster.ProjectID, cluster.ID); err != nil {
+		t.Errorf("expected the token cache of the cluster to be kept, got %v", err)
+	}
+
+	readReg, err := tester.repo.Registry().ReadRegistry(reg.ProjectID, reg.ID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if readReg.TokenCache.ID != newestRegCache.ID {
+		t.Errorf("expected the newest token cache of the registry to be kept, got %d", readReg.TokenCache.ID)
+	}
+}
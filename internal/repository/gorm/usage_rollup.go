@@ -0,0 +1,134 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UsageRollupRepository uses gorm.DB for querying the database
+type UsageRollupRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRollupRepository returns a UsageRollupRepository which uses
+// gorm.DB for querying the database
+func NewUsageRollupRepository(db *gorm.DB) repository.UsageRollupRepository {
+	return &UsageRollupRepository{db}
+}
+
+// ReplaceUsageRollupsForDay replaces the rollups of every app on a day and records when the day was rolled up
+func (repo *UsageRollupRepository) ReplaceUsageRollupsForDay(ctx context.Context, day time.Time, rolledUpAt time.Time, rollups []*models.UsageRollup) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-replace-usage-rollups-for-day")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "day", Value: day.Format(time.DateOnly)},
+		telemetry.AttributeKV{Key: "rollups", Value: len(rollups)},
+	)
+
+	for _, rollup := range rollups {
+		if !rollup.Day.Equal(day) {
+			return telemetry.Error(ctx, span, nil, "rollup is not for the day being replaced")
+		}
+	}
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// rollups are hard deleted so that the unique index on app and day can be reused by the replacements
+		if err := tx.Unscoped().Where("day = ?", day).Delete(&models.UsageRollup{}).Error; err != nil {
+			return err
+		}
+
+		if len(rollups) > 0 {
+			if err := tx.Create(rollups).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "day"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rolled_up_at", "updated_at"}),
+		}).Create(&models.UsageRollupDay{
+			Day:        day,
+			RolledUpAt: rolledUpAt,
+		}).Error
+	})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error replacing usage rollups")
+	}
+
+	return nil
+}
+
+// ListUsageRollupDays returns the rolled up days between start and end, inclusive
+func (repo *UsageRollupRepository) ListUsageRollupDays(ctx context.Context, start, end time.Time) ([]*models.UsageRollupDay, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-usage-rollup-days")
+	defer span.End()
+
+	days := []*models.UsageRollupDay{}
+
+	if err := repo.db.WithContext(ctx).Where("day >= ? AND day <= ?", start, end).Order("day ASC").Find(&days).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing usage rollup days")
+	}
+
+	return days, nil
+}
+
+// MarkUsageRollupDayPruned records that the raw events of a rolled up day were deleted
+func (repo *UsageRollupRepository) MarkUsageRollupDayPruned(ctx context.Context, day time.Time, prunedAt time.Time) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-mark-usage-rollup-day-pruned")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "day", Value: day.Format(time.DateOnly)})
+
+	res := repo.db.WithContext(ctx).Model(&models.UsageRollupDay{}).Where("day = ?", day).Update("events_pruned_at", prunedAt)
+	if res.Error != nil {
+		return telemetry.Error(ctx, span, res.Error, "error marking usage rollup day pruned")
+	}
+	if res.RowsAffected == 0 {
+		return telemetry.Error(ctx, span, gorm.ErrRecordNotFound, "day has not been rolled up")
+	}
+
+	return nil
+}
+
+// ListUsageRollupsByProjectID returns the rollups of a project's apps between start and end, inclusive
+func (repo *UsageRollupRepository) ListUsageRollupsByProjectID(ctx context.Context, projectID uint, start, end time.Time) ([]*models.UsageRollup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-usage-rollups-by-project-id")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: projectID})
+
+	rollups := []*models.UsageRollup{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND day >= ? AND day <= ?", projectID, start, end).Order("day ASC").Find(&rollups).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing usage rollups")
+	}
+
+	return rollups, nil
+}
+
+// ListUsageRollupTotalsByDay returns the usage of every app summed for each day between start and end, inclusive, as
+// rollups without a project or app. Days without usage are omitted
+func (repo *UsageRollupRepository) ListUsageRollupTotalsByDay(ctx context.Context, start, end time.Time) ([]*models.UsageRollup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-usage-rollup-totals-by-day")
+	defer span.End()
+
+	totals := []*models.UsageRollup{}
+
+	if err := repo.db.WithContext(ctx).Model(&models.UsageRollup{}).
+		Select("day, SUM(deploys) AS deploys, SUM(deploy_failures) AS deploy_failures, SUM(helm_operation_seconds) AS helm_operation_seconds, SUM(builds) AS builds, SUM(build_failures) AS build_failures, SUM(build_seconds) AS build_seconds").
+		Where("day >= ? AND day <= ?", start, end).
+		Group("day").
+		Order("day ASC").
+		Scan(&totals).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error summing usage rollups")
+	}
+
+	return totals, nil
+}
@@ -1,8 +1,11 @@
 package gorm
 
 import (
+	"context"
+
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -104,3 +107,16 @@ func (repo *UserRepository) CheckPassword(id int, pwd string) (bool, error) {
 
 	return true, nil
 }
+
+// CountUsers returns the number of users of the instance
+func (repo *UserRepository) CountUsers(ctx context.Context) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-count-users")
+	defer span.End()
+
+	var count int64
+	if err := repo.db.WithContext(ctx).Model(&models.User{}).Count(&count).Error; err != nil {
+		return 0, telemetry.Error(ctx, span, err, "error counting users")
+	}
+
+	return count, nil
+}
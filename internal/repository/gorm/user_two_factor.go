@@ -0,0 +1,107 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UserTwoFactorRepository uses gorm.DB for querying the database
+type UserTwoFactorRepository struct {
+	db  *gorm.DB
+	key *[32]byte
+}
+
+// NewUserTwoFactorRepository returns a UserTwoFactorRepository which uses gorm.DB for querying the database. It
+// accepts an encryption key to encrypt the TOTP secret of each user
+func NewUserTwoFactorRepository(db *gorm.DB, key *[32]byte) repository.UserTwoFactorRepository {
+	return &UserTwoFactorRepository{db, key}
+}
+
+// ReadUserTwoFactor returns the second factor of a user, with its secret decrypted
+func (repo *UserTwoFactorRepository) ReadUserTwoFactor(ctx context.Context, userID uint) (*models.UserTwoFactor, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-user-two-factor")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "user-id", Value: userID})
+
+	twoFactor := &models.UserTwoFactor{}
+
+	if err := repo.db.WithContext(ctx).Where("user_id = ?", userID).First(twoFactor).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading user two factor")
+	}
+
+	if len(twoFactor.Secret) != 0 {
+		secret, err := encryption.Decrypt(twoFactor.Secret, repo.key)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error decrypting user two factor")
+		}
+		twoFactor.Secret = secret
+	}
+
+	return twoFactor, nil
+}
+
+// CreateOrUpdateUserTwoFactor creates the second factor of a user, or replaces the existing one
+func (repo *UserTwoFactorRepository) CreateOrUpdateUserTwoFactor(ctx context.Context, twoFactor *models.UserTwoFactor) (*models.UserTwoFactor, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-or-update-user-two-factor")
+	defer span.End()
+
+	if twoFactor == nil {
+		return nil, telemetry.Error(ctx, span, nil, "user two factor is nil")
+	}
+	if twoFactor.UserID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "user id is empty")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "user-id", Value: twoFactor.UserID},
+		telemetry.AttributeKV{Key: "enabled", Value: twoFactor.Enabled},
+	)
+
+	existing := &models.UserTwoFactor{}
+	err := repo.db.WithContext(ctx).Where("user_id = ?", twoFactor.UserID).First(existing).Error
+	switch {
+	case err == nil:
+		twoFactor.ID = existing.ID
+		twoFactor.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, telemetry.Error(ctx, span, err, "error reading existing user two factor")
+	}
+
+	// the caller keeps the plaintext secret, so a copy is encrypted and saved
+	saved := *twoFactor
+	if len(saved.Secret) != 0 {
+		cipherData, err := encryption.Encrypt(saved.Secret, repo.key)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error encrypting user two factor")
+		}
+		saved.Secret = cipherData
+	}
+
+	if err := repo.db.WithContext(ctx).Save(&saved).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error saving user two factor")
+	}
+
+	twoFactor.Model = saved.Model
+
+	return twoFactor, nil
+}
+
+// DeleteUserTwoFactor deletes the second factor of a user. The row is removed rather than soft deleted, so that the
+// user can enroll again.
+func (repo *UserTwoFactorRepository) DeleteUserTwoFactor(ctx context.Context, twoFactor *models.UserTwoFactor) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-user-two-factor")
+	defer span.End()
+
+	if err := repo.db.WithContext(ctx).Unscoped().Delete(twoFactor).Error; err != nil {
+		return telemetry.Error(ctx, span, err, "error deleting user two factor")
+	}
+
+	return nil
+}
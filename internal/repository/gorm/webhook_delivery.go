@@ -0,0 +1,158 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// WebhookDeliveryRepository uses gorm.DB for querying the database
+type WebhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookDeliveryRepository returns a WebhookDeliveryRepository which uses
+// gorm.DB for querying the database
+func NewWebhookDeliveryRepository(db *gorm.DB) repository.WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db}
+}
+
+// CreateWebhookDelivery records a delivery attempt
+func (repo *WebhookDeliveryRepository) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-webhook-delivery")
+	defer span.End()
+
+	if delivery == nil {
+		return nil, telemetry.Error(ctx, span, nil, "webhook delivery is nil")
+	}
+	if delivery.ProjectID == 0 {
+		return nil, telemetry.Error(ctx, span, nil, "project id is empty")
+	}
+	if delivery.EventID == "" {
+		return nil, telemetry.Error(ctx, span, nil, "event id is empty")
+	}
+
+	if err := repo.db.WithContext(ctx).Create(delivery).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating webhook delivery")
+	}
+
+	return delivery, nil
+}
+
+// ReadWebhookDelivery returns a delivery attempt by its id, scoped to a project and log alert rule
+func (repo *WebhookDeliveryRepository) ReadWebhookDelivery(ctx context.Context, projectID, logAlertRuleID, id uint) (*models.WebhookDelivery, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-read-webhook-delivery")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "log-alert-rule-id", Value: logAlertRuleID},
+		telemetry.AttributeKV{Key: "webhook-delivery-id", Value: id},
+	)
+
+	delivery := &models.WebhookDelivery{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND log_alert_rule_id = ? AND id = ?", projectID, logAlertRuleID, id).First(delivery).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading webhook delivery")
+	}
+
+	return delivery, nil
+}
+
+// ListWebhookDeliveries returns the delivery attempts to the webhook of a log alert rule, newest first
+func (repo *WebhookDeliveryRepository) ListWebhookDeliveries(ctx context.Context, projectID, logAlertRuleID uint, filter repository.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-webhook-deliveries")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "log-alert-rule-id", Value: logAlertRuleID},
+		telemetry.AttributeKV{Key: "limit", Value: filter.Limit},
+	)
+
+	query := repo.db.WithContext(ctx).Where("project_id = ? AND log_alert_rule_id = ?", projectID, logAlertRuleID)
+	if filter.Succeeded != nil {
+		query = query.Where("succeeded = ?", *filter.Succeeded)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	deliveries := []*models.WebhookDelivery{}
+
+	if err := query.Order("id DESC").Find(&deliveries).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// CountWebhookDeliveries returns the number of delivery attempts to the webhook of a log alert rule since a time, and
+// how many of them succeeded
+func (repo *WebhookDeliveryRepository) CountWebhookDeliveries(ctx context.Context, projectID, logAlertRuleID uint, since time.Time) (int64, int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-count-webhook-deliveries")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "log-alert-rule-id", Value: logAlertRuleID},
+		telemetry.AttributeKV{Key: "since", Value: since},
+	)
+
+	var counts struct {
+		Attempts  int64
+		Succeeded int64
+	}
+
+	err := repo.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Select("COUNT(*) AS attempts, COALESCE(SUM(CASE WHEN succeeded THEN 1 ELSE 0 END), 0) AS succeeded").
+		Where("project_id = ? AND log_alert_rule_id = ? AND created_at >= ?", projectID, logAlertRuleID, since).
+		Scan(&counts).Error
+	if err != nil {
+		return 0, 0, telemetry.Error(ctx, span, err, "error counting webhook deliveries")
+	}
+
+	return counts.Attempts, counts.Succeeded, nil
+}
+
+// CountWebhookRedeliveries returns the number of redeliveries to the webhook of a log alert rule started since a time
+func (repo *WebhookDeliveryRepository) CountWebhookRedeliveries(ctx context.Context, logAlertRuleID uint, since time.Time) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-count-webhook-redeliveries")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "log-alert-rule-id", Value: logAlertRuleID},
+		telemetry.AttributeKV{Key: "since", Value: since},
+	)
+
+	var count int64
+
+	// each redelivery starts a chain, whose first attempt stands for the whole redelivery
+	err := repo.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("log_alert_rule_id = ? AND replay = ? AND attempt = 1 AND created_at >= ?", logAlertRuleID, true, since).
+		Count(&count).Error
+	if err != nil {
+		return 0, telemetry.Error(ctx, span, err, "error counting webhook redeliveries")
+	}
+
+	return count, nil
+}
+
+// DeleteWebhookDeliveriesBefore deletes the delivery attempts made before a time, returning how many were deleted
+func (repo *WebhookDeliveryRepository) DeleteWebhookDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-webhook-deliveries-before")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "before", Value: before})
+
+	res := repo.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.WebhookDelivery{})
+	if res.Error != nil {
+		return 0, telemetry.Error(ctx, span, res.Error, "error deleting webhook deliveries")
+	}
+
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// HelmReleaseImportRepository represents the set of queries on the HelmReleaseImport model
+type HelmReleaseImportRepository interface {
+	// CreateHelmReleaseImport records that a helm release was imported as a porter app
+	CreateHelmReleaseImport(ctx context.Context, helmImport *models.HelmReleaseImport) (*models.HelmReleaseImport, error)
+	// ReadHelmReleaseImport returns the import of a helm release in a cluster
+	ReadHelmReleaseImport(ctx context.Context, clusterID uint, namespace, releaseName string) (*models.HelmReleaseImport, error)
+	// ListHelmReleaseImportsByClusterID returns every helm release imported in a cluster
+	ListHelmReleaseImportsByClusterID(ctx context.Context, projectID, clusterID uint) ([]*models.HelmReleaseImport, error)
+	// UpdateHelmReleaseImport updates a helm release import
+	UpdateHelmReleaseImport(ctx context.Context, helmImport *models.HelmReleaseImport) (*models.HelmReleaseImport, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// InactivityPolicyRepository represents the set of queries on the InactivityPolicy model
+type InactivityPolicyRepository interface {
+	// ReadInactivityPolicy returns the inactivity policy of a project
+	ReadInactivityPolicy(ctx context.Context, projectID uint) (*models.InactivityPolicy, error)
+	// CreateOrUpdateInactivityPolicy creates the inactivity policy of a project, or replaces the existing one
+	CreateOrUpdateInactivityPolicy(ctx context.Context, policy *models.InactivityPolicy) (*models.InactivityPolicy, error)
+	// DeleteInactivityPolicy deletes the inactivity policy of a project
+	DeleteInactivityPolicy(ctx context.Context, policy *models.InactivityPolicy) error
+	// ListEnabledInactivityPolicies returns every enabled inactivity policy, for evaluation
+	ListEnabledInactivityPolicies(ctx context.Context) ([]*models.InactivityPolicy, error)
+}
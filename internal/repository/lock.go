@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// LockRepository represents the set of queries on the Lock model
+type LockRepository interface {
+	// AcquireLock takes the lock with the name for the holder with the token, until expiresAt. A lock which expired
+	// before now is taken over. It returns false if the lock is held by another holder.
+	AcquireLock(ctx context.Context, name, token string, now, expiresAt time.Time) (bool, error)
+	// ReleaseLock releases the lock with the name, if it is held by the holder with the token
+	ReleaseLock(ctx context.Context, name, token string) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// LogAlertRuleRepository represents the set of queries on the LogAlertRule model
+type LogAlertRuleRepository interface {
+	// CreateLogAlertRule creates a new log alert rule
+	CreateLogAlertRule(ctx context.Context, rule *models.LogAlertRule) (*models.LogAlertRule, error)
+	// ReadLogAlertRule returns a log alert rule by its id, scoped to a project and porter app
+	ReadLogAlertRule(ctx context.Context, projectID, porterAppID, id uint) (*models.LogAlertRule, error)
+	// ListLogAlertRulesByPorterAppID returns every log alert rule on a porter app
+	ListLogAlertRulesByPorterAppID(ctx context.Context, projectID, porterAppID uint) ([]*models.LogAlertRule, error)
+	// ListEnabledLogAlertRules returns every enabled log alert rule, for evaluation
+	ListEnabledLogAlertRules(ctx context.Context) ([]*models.LogAlertRule, error)
+	// UpdateLogAlertRule updates a log alert rule
+	UpdateLogAlertRule(ctx context.Context, rule *models.LogAlertRule) (*models.LogAlertRule, error)
+	// DeleteLogAlertRule deletes a log alert rule
+	DeleteLogAlertRule(ctx context.Context, rule *models.LogAlertRule) error
+}
@@ -2,17 +2,51 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
 )
 
 // PorterAppRepository represents the set of queries on the PorterApp model
+//
+// The Scoped methods take the project ID from the request scope and enforce it in the query, so a cluster or app ID
+// from another project never matches. Handlers should use them instead of the unscoped methods, and treat
+// gorm.ErrRecordNotFound as a 404.
+//
+// App names, and the previous names of apps, are compared case-insensitively, since the namespace of an app is derived
+// from its name and two apps whose names only differ by case would collide in their cluster.
 type PorterAppRepository interface {
+	// ReadPorterAppByID returns an empty app, rather than an error, if the app does not exist
 	ReadPorterAppByID(ctx context.Context, id uint) (*models.PorterApp, error)
+	// ReadPorterAppByName returns an empty app, rather than an error, if the app does not exist
 	ReadPorterAppByName(clusterID uint, name string) (*models.PorterApp, error)
 	ReadPorterAppsByProjectIDAndName(projectID uint, name string) ([]*models.PorterApp, error)
 	CreatePorterApp(app *models.PorterApp) (*models.PorterApp, error)
 	ListPorterAppByClusterID(clusterID uint) ([]*models.PorterApp, error)
 	UpdatePorterApp(app *models.PorterApp) (*models.PorterApp, error)
 	DeletePorterApp(app *models.PorterApp) (*models.PorterApp, error)
+	// ListPorterAppsByIDs returns the apps with the given IDs, including deleted apps
+	ListPorterAppsByIDs(ctx context.Context, ids []uint) ([]*models.PorterApp, error)
+	// ListPorterApps returns the apps of every project, for background checks which apply to every app
+	ListPorterApps(ctx context.Context) ([]*models.PorterApp, error)
+	// CountPorterApps returns the number of apps of every project
+	CountPorterApps(ctx context.Context) (int64, error)
+	// ListPorterAppsWithScalingSchedules returns the apps which have at least one service with a scaling schedule
+	ListPorterAppsWithScalingSchedules(ctx context.Context) ([]*models.PorterApp, error)
+	// UpdatePorterAppScalingSchedules writes the scaling schedules of an app, unless the app was updated since it was
+	// read, and reports whether they were written. Only the scaling schedules are written.
+	UpdatePorterAppScalingSchedules(ctx context.Context, app *models.PorterApp) (bool, error)
+	// RenamePorterApp renames an app and records its previous name in a single transaction, unless another app in its
+	// cluster has the new name, and reports whether it was renamed
+	RenamePorterApp(ctx context.Context, app *models.PorterApp, newName string, previousNameExpiresAt time.Time) (bool, error)
+
+	ReadScopedPorterAppByID(ctx context.Context, projectID, id uint) (*models.PorterApp, error)
+	ReadScopedPorterAppByName(projectID, clusterID uint, name string) (*models.PorterApp, error)
+	ListScopedPorterAppsByClusterID(projectID, clusterID uint) ([]*models.PorterApp, error)
+	ReadScopedPorterAppByUUID(ctx context.Context, projectID, clusterID uint, id uuid.UUID) (*models.PorterApp, error)
+	// ReadScopedPorterAppByPreviousName returns the app which was renamed from name, if the previous name has not expired
+	ReadScopedPorterAppByPreviousName(ctx context.Context, projectID, clusterID uint, name string) (*models.PorterApp, error)
+	// ReadPorterAppByDeployWebhookTokenHash returns the app whose deploy webhook token has the given hash
+	ReadPorterAppByDeployWebhookTokenHash(ctx context.Context, tokenHash string) (*models.PorterApp, error)
 }
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// PorterAppCleanupRepository represents the set of queries on the PorterAppCleanup model
+type PorterAppCleanupRepository interface {
+	// CreatePorterAppCleanup records the cleanup of a deleted app
+	CreatePorterAppCleanup(ctx context.Context, cleanup *models.PorterAppCleanup) (*models.PorterAppCleanup, error)
+	// ReadPorterAppCleanup returns a cleanup by its id, scoped to a project
+	ReadPorterAppCleanup(ctx context.Context, projectID, id uint) (*models.PorterAppCleanup, error)
+	// ListPorterAppCleanupsByStatus returns the cleanups of a project with a status, oldest first
+	ListPorterAppCleanupsByStatus(ctx context.Context, projectID uint, status string) ([]*models.PorterAppCleanup, error)
+	// ListPendingPorterAppCleanups returns the cleanups of every project which have resources left to release, oldest
+	// first
+	ListPendingPorterAppCleanups(ctx context.Context) ([]*models.PorterAppCleanup, error)
+	// UpdatePorterAppCleanup writes the status and pending resources of a cleanup
+	UpdatePorterAppCleanup(ctx context.Context, cleanup *models.PorterAppCleanup) error
+}
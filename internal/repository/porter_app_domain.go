@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// PorterAppDomainRepository represents the set of queries on the PorterAppDomain model
+type PorterAppDomainRepository interface {
+	// ListPorterAppDomains returns the domains of an app, ordered by hostname
+	ListPorterAppDomains(ctx context.Context, porterAppID uint) ([]*models.PorterAppDomain, error)
+	// ListPorterAppDomainsByHostnames returns the domains of the apps of a project which have one of the given hostnames
+	ListPorterAppDomainsByHostnames(ctx context.Context, projectID uint, hostnames []string) ([]*models.PorterAppDomain, error)
+	// CreatePorterAppDomain records a domain served by an app
+	CreatePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) (*models.PorterAppDomain, error)
+	// UpdatePorterAppDomain writes the certificate of a domain, and the alerts sent about it
+	UpdatePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) error
+	// DeletePorterAppDomain deletes a domain which is no longer served by its app
+	DeletePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) error
+}
@@ -2,19 +2,42 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
 )
 
+// PorterAppEventFilter selects the events returned by ListFilteredEventsByPorterAppID
+type PorterAppEventFilter struct {
+	// Type only returns events of this type, such as DEPLOY, if set
+	Type string
+	// Status only returns events with this status, such as FAILED, if set
+	Status string
+	// DeploymentTargetID only returns events of this deployment target, if set. Legacy events have the nil id.
+	DeploymentTargetID *uuid.UUID
+	// Search only returns events whose message contains every word of it, ignoring case, if set
+	Search string
+	// Limit caps the number of events returned, if set
+	Limit int
+	// Offset skips this many of the newest events which match
+	Offset int
+}
+
 // PorterAppEventRepository represents the set of queries on the PorterAppEvent model
 type PorterAppEventRepository interface {
 	ListEventsByPorterAppID(ctx context.Context, porterAppID uint, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error)
+	// ListFilteredEventsByPorterAppID returns the events of a porter app which match the filter, newest first, along
+	// with the number of events which match before the limit and offset are applied
+	ListFilteredEventsByPorterAppID(ctx context.Context, porterAppID uint, filter PorterAppEventFilter) ([]*models.PorterAppEvent, int64, error)
 	// ListEventsByPorterAppIDAndDeploymentTargetID returns a list of events for a given porter app id and deployment target id
 	ListEventsByPorterAppIDAndDeploymentTargetID(ctx context.Context, porterAppID uint, deploymentTargetID uuid.UUID, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error)
 	ListBuildDeployEventsByPorterAppIDAndDeploymentTargetID(ctx context.Context, porterAppID uint, deploymentTargetID uuid.UUID, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error)
 	CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error
+	// CreateEvents creates events in a single multi-row insert, in the order they are given. Either every event is
+	// created or none are.
+	CreateEvents(ctx context.Context, appEvents []*models.PorterAppEvent) error
 	UpdateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error
 	ReadEvent(ctx context.Context, id uuid.UUID) (models.PorterAppEvent, error)
 	ReadDeployEventByRevision(ctx context.Context, porterAppID uint, revision float64) (models.PorterAppEvent, error)
@@ -22,4 +45,12 @@ type PorterAppEventRepository interface {
 	ReadDeployEventByAppRevisionID(ctx context.Context, porterAppID uint, appRevisionID string) (models.PorterAppEvent, error)
 	ReadNotificationsByAppRevisionID(ctx context.Context, porterAppInstanceID uuid.UUID, appRevisionID string) ([]*models.PorterAppEvent, error)
 	NotificationByID(ctx context.Context, notificationID string) (*models.PorterAppEvent, error)
+	// ListEventsCreatedBetween returns the events of the given types created in [start, end), across all apps
+	ListEventsCreatedBetween(ctx context.Context, start, end time.Time, eventTypes []string) ([]*models.PorterAppEvent, error)
+	// ReadLatestEventByType returns the most recent event of a type on a porter app, or gorm.ErrRecordNotFound if it has none
+	ReadLatestEventByType(ctx context.Context, porterAppID uint, eventType string) (*models.PorterAppEvent, error)
+	// DeleteEventsCreatedBetween permanently deletes every event created in [start, end), returning the number deleted
+	DeleteEventsCreatedBetween(ctx context.Context, start, end time.Time) (int64, error)
+	// DeleteEventsByPorterAppID permanently deletes every event of a porter app, returning the number deleted
+	DeleteEventsByPorterAppID(ctx context.Context, porterAppID uint) (int64, error)
 }
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// PorterAppGrantRepository represents the set of queries on the PorterAppGrant model
+type PorterAppGrantRepository interface {
+	// CreatePorterAppGrant creates a new app grant
+	CreatePorterAppGrant(ctx context.Context, grant *models.PorterAppGrant) (*models.PorterAppGrant, error)
+	// ReadPorterAppGrant returns an app grant by its id, scoped to a project
+	ReadPorterAppGrant(ctx context.Context, projectID, id uint) (*models.PorterAppGrant, error)
+	// ListPorterAppGrantsByProjectID returns every app grant of a project, oldest first
+	ListPorterAppGrantsByProjectID(ctx context.Context, projectID uint) ([]*models.PorterAppGrant, error)
+	// ListMatchingPorterAppGrants returns the app grants of a project whose pattern matches an app, and which are for
+	// the user or the role. Grants for any holder are returned if userID is zero and role is empty.
+	ListMatchingPorterAppGrants(ctx context.Context, projectID uint, appName string, userID uint, role string) ([]*models.PorterAppGrant, error)
+	// DeletePorterAppGrant deletes an app grant
+	DeletePorterAppGrant(ctx context.Context, grant *models.PorterAppGrant) error
+}
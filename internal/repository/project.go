@@ -1,12 +1,24 @@
 package repository
 
 import (
+	"context"
+
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
 )
 
 // WriteProject is the function type for all Project write operations
 type WriteProject func(project *models.Project) (*models.Project, error)
 
+// ProjectSummaryFilter is used to filter and sort the project summaries listed for a user
+type ProjectSummaryFilter struct {
+	// Search restricts the results to projects whose name contains the search string (case-insensitive)
+	Search string
+	// SortBy is the ordering of the results, defaulting to types.ProjectListSortName
+	SortBy types.ProjectListSort
+}
+
 // ProjectRepository represents the set of queries on the Project model
 type ProjectRepository interface {
 	CreateProject(project *models.Project) (*models.Project, error)
@@ -17,7 +29,12 @@ type ProjectRepository interface {
 	ReadProjectRole(projID, userID uint) (*models.Role, error)
 	ListProjectRoles(projID uint) ([]models.Role, error)
 	ListProjectsByUserID(userID uint) ([]*models.Project, error)
+	// ListProjectSummariesByUserID returns a page of the projects where a user has an associated role, along with the user's role
+	// and the app count, cluster count and latest deploy time of each project
+	ListProjectSummariesByUserID(ctx context.Context, userID uint, filter ProjectSummaryFilter, opts ...helpers.QueryOption) ([]*models.ProjectSummary, helpers.PaginatedResult, error)
 	DeleteProject(project *models.Project) (*models.Project, error)
 	DeleteProjectRole(projID, userID uint) (*models.Role, error)
 	DeleteRolesForProject(projID uint) error
+	// CountProjects returns the number of projects of the instance
+	CountProjects(ctx context.Context) (int64, error)
 }
@@ -9,4 +9,5 @@ type PWResetTokenRepository interface {
 	CreatePWResetToken(pwToken *models.PWResetToken) (*models.PWResetToken, error)
 	ReadPWResetToken(id uint) (*models.PWResetToken, error)
 	UpdatePWResetToken(pwToken *models.PWResetToken) (*models.PWResetToken, error)
+	InvalidatePWResetTokensByEmail(email string, purpose models.PWResetTokenPurpose) error
 }
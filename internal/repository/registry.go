@@ -13,5 +13,7 @@ type RegistryRepository interface {
 	ListRegistriesByProjectID(projectID uint) ([]*models.Registry, error)
 	UpdateRegistry(reg *models.Registry) (*models.Registry, error)
 	UpdateRegistryTokenCache(tokenCache *ints.RegTokenCache) (*models.Registry, error)
+	UpdateRegistryCredentialStatus(reg *models.Registry) error
+	ListRegistriesByCredentialStatus(status string) ([]*models.Registry, error)
 	DeleteRegistry(reg *models.Registry) error
 }
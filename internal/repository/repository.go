@@ -53,4 +53,19 @@ type Repository interface {
 	GithubWebhook() GithubWebhookRepository
 	Datastore() DatastoreRepository
 	AppInstance() AppInstanceRepository
+	LogAlertRule() LogAlertRuleRepository
+	HelmReleaseImport() HelmReleaseImportRepository
+	BulkRedeploy() BulkRedeployRepository
+	UsageRollup() UsageRollupRepository
+	InactivityPolicy() InactivityPolicyRepository
+	SecretsProviderIntegration() SecretsProviderIntegrationRepository
+	DebugRecording() DebugRecordingRepository
+	AuditLog() AuditLogRepository
+	Lock() LockRepository
+	WebhookDelivery() WebhookDeliveryRepository
+	PorterAppGrant() PorterAppGrantRepository
+	TokenCache() TokenCacheRepository
+	PorterAppCleanup() PorterAppCleanupRepository
+	PorterAppDomain() PorterAppDomainRepository
+	UserTwoFactor() UserTwoFactorRepository
 }
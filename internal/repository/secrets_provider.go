@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/api/types"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+)
+
+// SecretsProviderIntegrationRepository represents the set of queries on the SecretsProviderIntegration model
+type SecretsProviderIntegrationRepository interface {
+	// ReadSecretsProviderIntegration returns the integration of a project for a provider, with its credentials decrypted
+	ReadSecretsProviderIntegration(ctx context.Context, projectID uint, provider types.SecretsProvider) (*ints.SecretsProviderIntegration, error)
+	// ListSecretsProviderIntegrations returns the integrations of a project, with their credentials decrypted
+	ListSecretsProviderIntegrations(ctx context.Context, projectID uint) ([]*ints.SecretsProviderIntegration, error)
+	// CreateOrUpdateSecretsProviderIntegration creates the integration of a project for a provider, or replaces the existing one
+	CreateOrUpdateSecretsProviderIntegration(ctx context.Context, integration *ints.SecretsProviderIntegration) (*ints.SecretsProviderIntegration, error)
+	// DeleteSecretsProviderIntegration deletes the integration of a project for a provider
+	DeleteSecretsProviderIntegration(ctx context.Context, integration *ints.SecretsProviderIntegration) error
+}
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/porter-dev/porter/internal/models"
 )
 
@@ -10,4 +13,11 @@ type SessionRepository interface {
 	UpdateSession(session *models.Session) (*models.Session, error)
 	DeleteSession(session *models.Session) (*models.Session, error)
 	SelectSession(session *models.Session) (*models.Session, error)
+	// TouchSession records that the session with a key was used, moving its expiry to expiresAt
+	TouchSession(ctx context.Context, key string, lastUsedAt time.Time, expiresAt time.Time) error
+	// DeleteExpiredSessions deletes up to limit sessions which expired before a time, or all of them if limit is not
+	// positive, returning how many were deleted
+	DeleteExpiredSessions(ctx context.Context, before time.Time, limit int) (int64, error)
+	// CountSessions returns the number of stored sessions
+	CountSessions(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// AuditLogRepository is a test repository that implements repository.AuditLogRepository
+// and stores audit log entries in-memory, indexed by their array index + 1
+type AuditLogRepository struct {
+	canQuery bool
+	entries  []*models.AuditLogEntry
+}
+
+// NewAuditLogRepository returns the test AuditLogRepository
+func NewAuditLogRepository(canQuery bool) repository.AuditLogRepository {
+	return &AuditLogRepository{canQuery, []*models.AuditLogEntry{}}
+}
+
+// CreateAuditLogEntry adds an entry to the audit log of a project
+func (repo *AuditLogRepository) CreateAuditLogEntry(ctx context.Context, entry *models.AuditLogEntry) (*models.AuditLogEntry, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if entry == nil {
+		return nil, errors.New("audit log entry is nil")
+	}
+	if entry.ProjectID == 0 {
+		return nil, errors.New("project id is empty")
+	}
+
+	repo.entries = append(repo.entries, entry)
+	entry.ID = uint(len(repo.entries))
+	entry.CreatedAt = time.Now()
+
+	return entry, nil
+}
+
+// ListAuditLogEntriesByProjectID returns up to limit entries of the audit log of a project, newest first
+func (repo *AuditLogRepository) ListAuditLogEntriesByProjectID(ctx context.Context, projectID uint, limit int) ([]*models.AuditLogEntry, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.AuditLogEntry{}
+	for i := len(repo.entries) - 1; i >= 0 && len(res) < limit; i-- {
+		if repo.entries[i].ProjectID == projectID {
+			res = append(res, repo.entries[i])
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,51 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// BulkRedeployRepository is a test repository that implements repository.BulkRedeployRepository
+type BulkRedeployRepository struct {
+	canQuery bool
+}
+
+// NewBulkRedeployRepository returns the test BulkRedeployRepository
+func NewBulkRedeployRepository() repository.BulkRedeployRepository {
+	return &BulkRedeployRepository{canQuery: false}
+}
+
+// CreateBulkRedeploy creates a bulk redeploy along with its operations
+func (repo *BulkRedeployRepository) CreateBulkRedeploy(ctx context.Context, bulkRedeploy *models.BulkRedeploy) (*models.BulkRedeploy, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ReadBulkRedeploy returns a bulk redeploy of a project with its operations
+func (repo *BulkRedeployRepository) ReadBulkRedeploy(ctx context.Context, projectID uint, id uuid.UUID) (*models.BulkRedeploy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListIncompleteBulkRedeploys returns every bulk redeploy with operations left to finish, with its operations
+func (repo *BulkRedeployRepository) ListIncompleteBulkRedeploys(ctx context.Context) ([]*models.BulkRedeploy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ClaimBulkRedeployOperation moves a pending operation to running if fewer than concurrency operations are running
+func (repo *BulkRedeployRepository) ClaimBulkRedeployOperation(ctx context.Context, operation *models.BulkRedeployOperation, concurrency int, startedAt time.Time) (bool, error) {
+	return false, errors.New("cannot write database")
+}
+
+// UpdateBulkRedeployOperation updates an operation
+func (repo *BulkRedeployRepository) UpdateBulkRedeployOperation(ctx context.Context, operation *models.BulkRedeployOperation) (*models.BulkRedeployOperation, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// CompleteBulkRedeploy records that every operation of a bulk redeploy has finished
+func (repo *BulkRedeployRepository) CompleteBulkRedeploy(ctx context.Context, id uuid.UUID, completedAt time.Time) error {
+	return errors.New("cannot write database")
+}
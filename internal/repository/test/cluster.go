@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/porter-dev/porter/internal/features"
@@ -171,6 +172,24 @@ func (repo *ClusterRepository) UpdateCluster(
 	return cluster, nil
 }
 
+// UpdateClusterCapabilities replaces the detected capabilities of a cluster
+func (repo *ClusterRepository) UpdateClusterCapabilities(
+	clusterID uint,
+	capabilities models.ClusterCapabilities,
+) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if int(clusterID-1) >= len(repo.clusters) || repo.clusters[clusterID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.clusters[clusterID-1].Capabilities = capabilities
+
+	return nil
+}
+
 // UpdateClusterTokenCache updates the token cache for a cluster
 func (repo *ClusterRepository) UpdateClusterTokenCache(
 	tokenCache *ints.ClusterTokenCache,
@@ -203,3 +222,20 @@ func (repo *ClusterRepository) DeleteCluster(
 
 	return nil
 }
+
+// CountClustersByProvider returns the number of clusters of the instance hosted by each cloud provider, keyed by
+// models.Cluster.Provider
+func (repo *ClusterRepository) CountClustersByProvider(ctx context.Context) (map[string]int64, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	counts := make(map[string]int64)
+	for _, cluster := range repo.clusters {
+		if cluster != nil {
+			counts[cluster.Provider()]++
+		}
+	}
+
+	return counts, nil
+}
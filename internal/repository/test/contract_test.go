@@ -0,0 +1,15 @@
+package test_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/contract"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+func TestContract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) repository.Repository {
+		return test.NewRepository(true)
+	})
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// DebugRecordingRepository is a test repository that implements repository.DebugRecordingRepository
+// and stores debug recordings in-memory, indexed by their array index + 1
+type DebugRecordingRepository struct {
+	canQuery   bool
+	recordings []*models.DebugRecording
+}
+
+// NewDebugRecordingRepository returns the test DebugRecordingRepository
+func NewDebugRecordingRepository(canQuery bool) repository.DebugRecordingRepository {
+	return &DebugRecordingRepository{canQuery, []*models.DebugRecording{}}
+}
+
+// CreateDebugRecording creates a new debug recording
+func (repo *DebugRecordingRepository) CreateDebugRecording(ctx context.Context, recording *models.DebugRecording) (*models.DebugRecording, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if recording == nil {
+		return nil, errors.New("debug recording is nil")
+	}
+	if recording.ProjectID == 0 {
+		return nil, errors.New("project id is empty")
+	}
+
+	repo.recordings = append(repo.recordings, recording)
+	recording.ID = uint(len(repo.recordings))
+	recording.CreatedAt = time.Now()
+
+	return recording, nil
+}
+
+// ReadDebugRecording returns a debug recording by its id, scoped to a project
+func (repo *DebugRecordingRepository) ReadDebugRecording(ctx context.Context, projectID, id uint) (*models.DebugRecording, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	if id == 0 || int(id-1) >= len(repo.recordings) || repo.recordings[id-1].ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.recordings[id-1], nil
+}
+
+// ReadActiveDebugRecording returns the recording of a project which is active at the given time
+func (repo *DebugRecordingRepository) ReadActiveDebugRecording(ctx context.Context, projectID uint, now time.Time) (*models.DebugRecording, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for i := len(repo.recordings) - 1; i >= 0; i-- {
+		recording := repo.recordings[i]
+		if recording.ProjectID == projectID && recording.IsActive(now) {
+			return recording, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListDebugRecordingsByProjectID returns every debug recording of a project, newest first
+func (repo *DebugRecordingRepository) ListDebugRecordingsByProjectID(ctx context.Context, projectID uint) ([]*models.DebugRecording, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.DebugRecording{}
+	for i := len(repo.recordings) - 1; i >= 0; i-- {
+		if repo.recordings[i].ProjectID == projectID {
+			res = append(res, repo.recordings[i])
+		}
+	}
+
+	return res, nil
+}
+
+// UpdateDebugRecording updates a debug recording
+func (repo *DebugRecordingRepository) UpdateDebugRecording(ctx context.Context, recording *models.DebugRecording) (*models.DebugRecording, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if recording.ID == 0 || int(recording.ID-1) >= len(repo.recordings) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.recordings[recording.ID-1] = recording
+
+	return recording, nil
+}
+
+// ReserveDebugRecordingExchange increments the exchange count of a recording, unless it has already reached max
+func (repo *DebugRecordingRepository) ReserveDebugRecordingExchange(ctx context.Context, id uint, max uint) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("cannot write database")
+	}
+
+	if id == 0 || int(id-1) >= len(repo.recordings) {
+		return false, nil
+	}
+
+	recording := repo.recordings[id-1]
+	if recording.ExchangeCount >= max {
+		return false, nil
+	}
+
+	recording.ExchangeCount++
+
+	return true, nil
+}
@@ -34,3 +34,50 @@ func (repo *DNSRecordRepository) CreateDNSRecord(
 
 	return record, nil
 }
+
+// DeleteDNSRecordsByHostname deletes the records of the given hostnames
+func (repo *DNSRecordRepository) DeleteDNSRecordsByHostname(hostnames []string) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("Cannot write database")
+	}
+
+	toDelete := make(map[string]bool, len(hostnames))
+	for _, hostname := range hostnames {
+		toDelete[hostname] = true
+	}
+
+	var deleted int64
+	kept := make([]*models.DNSRecord, 0, len(repo.dnsRecords))
+	for _, record := range repo.dnsRecords {
+		if toDelete[record.Hostname] {
+			deleted++
+			continue
+		}
+		kept = append(kept, record)
+	}
+	repo.dnsRecords = kept
+
+	return deleted, nil
+}
+
+// UpdateDNSRecordEndpointsByHostname points the records of the given hostnames at the endpoint
+func (repo *DNSRecordRepository) UpdateDNSRecordEndpointsByHostname(hostnames []string, endpoint string) ([]*models.DNSRecord, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	toUpdate := make(map[string]bool, len(hostnames))
+	for _, hostname := range hostnames {
+		toUpdate[hostname] = true
+	}
+
+	updated := []*models.DNSRecord{}
+	for _, record := range repo.dnsRecords {
+		if toUpdate[record.Hostname] {
+			record.Endpoint = endpoint
+			updated = append(updated, record)
+		}
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,39 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// HelmReleaseImportRepository is a test repository that implements repository.HelmReleaseImportRepository
+type HelmReleaseImportRepository struct {
+	canQuery bool
+}
+
+// NewHelmReleaseImportRepository returns the test HelmReleaseImportRepository
+func NewHelmReleaseImportRepository() repository.HelmReleaseImportRepository {
+	return &HelmReleaseImportRepository{canQuery: false}
+}
+
+// CreateHelmReleaseImport records that a helm release was imported as a porter app
+func (repo *HelmReleaseImportRepository) CreateHelmReleaseImport(ctx context.Context, helmImport *models.HelmReleaseImport) (*models.HelmReleaseImport, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// ReadHelmReleaseImport returns the import of a helm release in a cluster
+func (repo *HelmReleaseImportRepository) ReadHelmReleaseImport(ctx context.Context, clusterID uint, namespace, releaseName string) (*models.HelmReleaseImport, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListHelmReleaseImportsByClusterID returns every helm release imported in a cluster
+func (repo *HelmReleaseImportRepository) ListHelmReleaseImportsByClusterID(ctx context.Context, projectID, clusterID uint) ([]*models.HelmReleaseImport, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// UpdateHelmReleaseImport updates a helm release import
+func (repo *HelmReleaseImportRepository) UpdateHelmReleaseImport(ctx context.Context, helmImport *models.HelmReleaseImport) (*models.HelmReleaseImport, error) {
+	return nil, errors.New("cannot write database")
+}
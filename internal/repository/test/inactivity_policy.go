@@ -0,0 +1,39 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// InactivityPolicyRepository is a test repository that implements repository.InactivityPolicyRepository
+type InactivityPolicyRepository struct {
+	canQuery bool
+}
+
+// NewInactivityPolicyRepository returns the test InactivityPolicyRepository
+func NewInactivityPolicyRepository() repository.InactivityPolicyRepository {
+	return &InactivityPolicyRepository{canQuery: false}
+}
+
+// ReadInactivityPolicy returns the inactivity policy of a project
+func (repo *InactivityPolicyRepository) ReadInactivityPolicy(ctx context.Context, projectID uint) (*models.InactivityPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// CreateOrUpdateInactivityPolicy creates the inactivity policy of a project, or replaces the existing one
+func (repo *InactivityPolicyRepository) CreateOrUpdateInactivityPolicy(ctx context.Context, policy *models.InactivityPolicy) (*models.InactivityPolicy, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// DeleteInactivityPolicy deletes the inactivity policy of a project
+func (repo *InactivityPolicyRepository) DeleteInactivityPolicy(ctx context.Context, policy *models.InactivityPolicy) error {
+	return errors.New("cannot write database")
+}
+
+// ListEnabledInactivityPolicies returns every enabled inactivity policy, for evaluation
+func (repo *InactivityPolicyRepository) ListEnabledInactivityPolicies(ctx context.Context) ([]*models.InactivityPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// LockRepository is a test repository that implements repository.LockRepository and stores locks in-memory, keyed
+// by their name. Unlike most test repositories it is safe for concurrent use, since locks are taken by concurrent
+// requests.
+type LockRepository struct {
+	canQuery bool
+
+	mu    sync.Mutex
+	locks map[string]*models.Lock
+}
+
+// NewLockRepository returns the test LockRepository
+func NewLockRepository(canQuery bool) repository.LockRepository {
+	return &LockRepository{canQuery: canQuery, locks: make(map[string]*models.Lock)}
+}
+
+// AcquireLock takes the lock with the name for the holder with the token
+func (repo *LockRepository) AcquireLock(ctx context.Context, name, token string, now, expiresAt time.Time) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("cannot write database")
+	}
+
+	if name == "" || token == "" {
+		return false, errors.New("lock name and token are required")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if lock, ok := repo.locks[name]; ok && lock.ExpiresAt.After(now) {
+		return false, nil
+	}
+
+	repo.locks[name] = &models.Lock{
+		Name:      name,
+		Token:     token,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+
+	return true, nil
+}
+
+// ReleaseLock releases the lock with the name, if it is held by the holder with the token
+func (repo *LockRepository) ReleaseLock(ctx context.Context, name, token string) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if lock, ok := repo.locks[name]; ok && lock.Token == token {
+		delete(repo.locks, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// LogAlertRuleRepository is a test repository that implements repository.LogAlertRuleRepository
+// and stores log alert rules in-memory, indexed by their array index + 1
+type LogAlertRuleRepository struct {
+	canQuery bool
+	rules    []*models.LogAlertRule
+}
+
+// NewLogAlertRuleRepository returns the test LogAlertRuleRepository
+func NewLogAlertRuleRepository(canQuery bool) repository.LogAlertRuleRepository {
+	return &LogAlertRuleRepository{canQuery, []*models.LogAlertRule{}}
+}
+
+// CreateLogAlertRule creates a new log alert rule
+func (repo *LogAlertRuleRepository) CreateLogAlertRule(ctx context.Context, rule *models.LogAlertRule) (*models.LogAlertRule, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if rule == nil {
+		return nil, errors.New("log alert rule is nil")
+	}
+	if rule.ProjectID == 0 {
+		return nil, errors.New("project id is empty")
+	}
+	if rule.PorterAppID == 0 {
+		return nil, errors.New("porter app id is empty")
+	}
+
+	repo.rules = append(repo.rules, rule)
+	rule.ID = uint(len(repo.rules))
+
+	return rule, nil
+}
+
+// ReadLogAlertRule returns a log alert rule by its id, scoped to a project and porter app
+func (repo *LogAlertRuleRepository) ReadLogAlertRule(ctx context.Context, projectID, porterAppID, id uint) (*models.LogAlertRule, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	if id == 0 || int(id-1) >= len(repo.rules) || repo.rules[id-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	rule := repo.rules[id-1]
+	if rule.ProjectID != projectID || rule.PorterAppID != porterAppID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return rule, nil
+}
+
+// ListLogAlertRulesByPorterAppID returns every log alert rule on a porter app
+func (repo *LogAlertRuleRepository) ListLogAlertRulesByPorterAppID(ctx context.Context, projectID, porterAppID uint) ([]*models.LogAlertRule, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.LogAlertRule{}
+	for _, rule := range repo.rules {
+		if rule != nil && rule.ProjectID == projectID && rule.PorterAppID == porterAppID {
+			res = append(res, rule)
+		}
+	}
+
+	return res, nil
+}
+
+// ListEnabledLogAlertRules returns every enabled log alert rule, for evaluation
+func (repo *LogAlertRuleRepository) ListEnabledLogAlertRules(ctx context.Context) ([]*models.LogAlertRule, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.LogAlertRule{}
+	for _, rule := range repo.rules {
+		if rule != nil && rule.Enabled {
+			res = append(res, rule)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].ClusterID != res[j].ClusterID {
+			return res[i].ClusterID < res[j].ClusterID
+		}
+
+		return res[i].PorterAppID < res[j].PorterAppID
+	})
+
+	return res, nil
+}
+
+// UpdateLogAlertRule updates a log alert rule
+func (repo *LogAlertRuleRepository) UpdateLogAlertRule(ctx context.Context, rule *models.LogAlertRule) (*models.LogAlertRule, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if rule.ID == 0 || int(rule.ID-1) >= len(repo.rules) || repo.rules[rule.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.rules[rule.ID-1] = rule
+
+	return rule, nil
+}
+
+// DeleteLogAlertRule deletes a log alert rule
+func (repo *LogAlertRuleRepository) DeleteLogAlertRule(ctx context.Context, rule *models.LogAlertRule) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	if rule.ID == 0 || int(rule.ID-1) >= len(repo.rules) || repo.rules[rule.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.rules[rule.ID-1] = nil
+
+	return nil
+}
@@ -4,47 +4,400 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
 )
 
+const (
+	CreatePorterAppMethod      string = "create_porter_app_0"
+	ReadPorterAppMethod        string = "read_porter_app_0"
+	ListPorterAppsMethod       string = "list_porter_apps_0"
+	ReadScopedPorterAppMethod  string = "read_scoped_porter_app_0"
+	ListScopedPorterAppsMethod string = "list_scoped_porter_apps_0"
+)
+
+// PorterAppRepository will return errors on queries if canQuery is false
+// and stores porter apps in-memory, indexed by their array index + 1
 type PorterAppRepository struct {
 	canQuery       bool
 	failingMethods string
+	apps           []*models.PorterApp
+	// deleted holds deleted apps by their ID, which are still listed by ListPorterAppsByIDs
+	deleted map[uint]*models.PorterApp
 }
 
 func NewPorterAppRepository(canQuery bool, failingMethods ...string) repository.PorterAppRepository {
-	return &PorterAppRepository{canQuery, strings.Join(failingMethods, ",")}
+	return &PorterAppRepository{canQuery, strings.Join(failingMethods, ","), []*models.PorterApp{}, map[uint]*models.PorterApp{}}
 }
 
+// ReadPorterAppByName returns the app with the given name in a cluster, or an empty app if it does not exist
 func (repo *PorterAppRepository) ReadPorterAppByName(clusterID uint, name string) (*models.PorterApp, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadPorterAppMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, app := range repo.apps {
+		if app != nil && app.ClusterID == clusterID && strings.EqualFold(app.Name, name) {
+			return app, nil
+		}
+	}
+
+	return &models.PorterApp{}, nil
 }
 
-// ReadPorterAppsByProjectIDAndName is a test method that is not implemented
+// ReadPorterAppsByProjectIDAndName returns all apps with the given name in a project
 func (repo *PorterAppRepository) ReadPorterAppsByProjectIDAndName(projectID uint, name string) ([]*models.PorterApp, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppsMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.PorterApp{}
+	for _, app := range repo.apps {
+		if app != nil && app.ProjectID == projectID && strings.EqualFold(app.Name, name) {
+			res = append(res, app)
+		}
+	}
+
+	return res, nil
 }
 
+// CreatePorterApp appends a new app to the in-memory apps array
 func (repo *PorterAppRepository) CreatePorterApp(app *models.PorterApp) (*models.PorterApp, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, CreatePorterAppMethod) {
+		return nil, errors.New("cannot write database")
+	}
+
+	repo.apps = append(repo.apps, app)
+	app.ID = uint(len(repo.apps))
+	if app.UUID == uuid.Nil {
+		app.UUID = uuid.New()
+	}
+
+	return app, nil
 }
 
+// UpdatePorterApp replaces an existing app in the in-memory apps array
 func (repo *PorterAppRepository) UpdatePorterApp(app *models.PorterApp) (*models.PorterApp, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	// like gorm's Save, an app without an ID is inserted, subject to the unique index on the cluster and name of apps
+	if app.ID == 0 {
+		for _, existing := range repo.apps {
+			if existing != nil && existing.ClusterID == app.ClusterID && strings.EqualFold(existing.Name, app.Name) {
+				return nil, errors.New("duplicate key value violates unique constraint")
+			}
+		}
+
+		return repo.CreatePorterApp(app)
+	}
+
+	if int(app.ID-1) >= len(repo.apps) || repo.apps[app.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if app.UUID == uuid.Nil {
+		app.UUID = uuid.New()
+	}
+	app.UpdatedAt = time.Now()
+	repo.apps[app.ID-1] = app
+
+	return app, nil
 }
 
-// ListPorterAppByClusterID is a test method that is not implemented
+// ListPorterAppByClusterID returns all apps in a cluster
 func (repo *PorterAppRepository) ListPorterAppByClusterID(clusterID uint) ([]*models.PorterApp, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppsMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.PorterApp{}
+	for _, app := range repo.apps {
+		if app != nil && app.ClusterID == clusterID {
+			res = append(res, app)
+		}
+	}
+
+	return res, nil
 }
 
+// DeletePorterApp removes an app from the in-memory apps array
 func (repo *PorterAppRepository) DeletePorterApp(app *models.PorterApp) (*models.PorterApp, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if int(app.ID-1) >= len(repo.apps) || repo.apps[app.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	app.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	repo.deleted[app.ID] = app
+	repo.apps[app.ID-1] = nil
+
+	return app, nil
 }
 
-// ReadPorterAppByID is a test method that is not implemented
+// ReadPorterAppByID returns the app with the given ID, or an empty app if it does not exist
 func (repo *PorterAppRepository) ReadPorterAppByID(ctx context.Context, id uint) (*models.PorterApp, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadPorterAppMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	if id == 0 || int(id-1) >= len(repo.apps) || repo.apps[id-1] == nil {
+		return &models.PorterApp{}, nil
+	}
+
+	return repo.apps[id-1], nil
+}
+
+// ReadScopedPorterAppByID returns the app with the given ID if it belongs to the project
+func (repo *PorterAppRepository) ReadScopedPorterAppByID(ctx context.Context, projectID, id uint) (*models.PorterApp, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadScopedPorterAppMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	if id == 0 || int(id-1) >= len(repo.apps) || repo.apps[id-1] == nil || repo.apps[id-1].ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.apps[id-1], nil
+}
+
+// ReadScopedPorterAppByName returns the app with the given name in a cluster if it belongs to the project
+func (repo *PorterAppRepository) ReadScopedPorterAppByName(projectID, clusterID uint, name string) (*models.PorterApp, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadScopedPorterAppMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, app := range repo.apps {
+		if app != nil && app.ProjectID == projectID && app.ClusterID == clusterID && strings.EqualFold(app.Name, name) {
+			return app, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListScopedPorterAppsByClusterID returns all apps in a cluster which belong to the project
+func (repo *PorterAppRepository) ListScopedPorterAppsByClusterID(projectID, clusterID uint) ([]*models.PorterApp, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListScopedPorterAppsMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.PorterApp{}
+	for _, app := range repo.apps {
+		if app != nil && app.ProjectID == projectID && app.ClusterID == clusterID {
+			res = append(res, app)
+		}
+	}
+
+	return res, nil
+}
+
+// ListPorterAppsByIDs returns the apps with the given IDs, including deleted apps
+func (repo *PorterAppRepository) ListPorterAppsByIDs(ctx context.Context, ids []uint) ([]*models.PorterApp, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppsMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.PorterApp{}
+	listed := make(map[uint]bool)
+	for _, id := range ids {
+		if listed[id] {
+			continue
+		}
+		listed[id] = true
+
+		if id != 0 && int(id-1) < len(repo.apps) && repo.apps[id-1] != nil {
+			res = append(res, repo.apps[id-1])
+		} else if app, ok := repo.deleted[id]; ok {
+			res = append(res, app)
+		}
+	}
+
+	return res, nil
+}
+
+// ReadScopedPorterAppByUUID returns the app with the given UUID in a cluster if it belongs to the project
+func (repo *PorterAppRepository) ReadScopedPorterAppByUUID(ctx context.Context, projectID, clusterID uint, id uuid.UUID) (*models.PorterApp, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadScopedPorterAppMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, app := range repo.apps {
+		if app != nil && app.ProjectID == projectID && app.ClusterID == clusterID && app.UUID == id {
+			return app, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ReadScopedPorterAppByPreviousName returns the app in a cluster which was most recently renamed from name if it
+// belongs to the project and the previous name has not expired
+func (repo *PorterAppRepository) ReadScopedPorterAppByPreviousName(ctx context.Context, projectID, clusterID uint, name string) (*models.PorterApp, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadScopedPorterAppMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	var res *models.PorterApp
+	for _, app := range repo.apps {
+		if app != nil && app.ProjectID == projectID && app.ClusterID == clusterID && strings.EqualFold(app.PreviousName, name) &&
+			app.PreviousNameExpiresAt != nil && app.PreviousNameExpiresAt.After(time.Now()) &&
+			(res == nil || app.PreviousNameExpiresAt.After(*res.PreviousNameExpiresAt)) {
+			res = app
+		}
+	}
+
+	if res == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return res, nil
+}
+
+// ReadPorterAppByDeployWebhookTokenHash returns the app whose deploy webhook token has the given hash
+func (repo *PorterAppRepository) ReadPorterAppByDeployWebhookTokenHash(ctx context.Context, tokenHash string) (*models.PorterApp, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadPorterAppMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, app := range repo.apps {
+		if app != nil && tokenHash != "" && app.DeployWebhookTokenHash == tokenHash {
+			return app, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListPorterApps returns the apps of every project
+func (repo *PorterAppRepository) ListPorterApps(ctx context.Context) ([]*models.PorterApp, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppsMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.PorterApp{}
+	for _, app := range repo.apps {
+		if app != nil {
+			res = append(res, app)
+		}
+	}
+
+	return res, nil
+}
+
+// CountPorterApps returns the number of apps of every project
+func (repo *PorterAppRepository) CountPorterApps(ctx context.Context) (int64, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppsMethod) {
+		return 0, errors.New("cannot read database")
+	}
+
+	var count int64
+	for _, app := range repo.apps {
+		if app != nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// ListPorterAppsWithScalingSchedules returns copies of the apps which have at least one service with a scaling
+// schedule, so that changes to them are only stored by UpdatePorterAppScalingSchedules
+func (repo *PorterAppRepository) ListPorterAppsWithScalingSchedules(ctx context.Context) ([]*models.PorterApp, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppsMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.PorterApp{}
+	for _, app := range repo.apps {
+		if app != nil && len(app.ScalingSchedules) != 0 {
+			copied := *app
+			copied.ScalingSchedules = copyScalingSchedules(app.ScalingSchedules)
+			res = append(res, &copied)
+		}
+	}
+
+	return res, nil
+}
+
+// UpdatePorterAppScalingSchedules writes the scaling schedules of an app, unless the app was updated since it was read
+func (repo *PorterAppRepository) UpdatePorterAppScalingSchedules(ctx context.Context, app *models.PorterApp) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("cannot write database")
+	}
+
+	if app == nil || app.ID == 0 {
+		return false, errors.New("porter app id is empty")
+	}
+
+	if int(app.ID-1) >= len(repo.apps) || repo.apps[app.ID-1] == nil {
+		return false, nil
+	}
+
+	stored := repo.apps[app.ID-1]
+	if !stored.UpdatedAt.Equal(app.UpdatedAt) {
+		return false, nil
+	}
+
+	stored.ScalingSchedules = copyScalingSchedules(app.ScalingSchedules)
+	stored.UpdatedAt = time.Now()
+	app.UpdatedAt = stored.UpdatedAt
+
+	return true, nil
+}
+
+// RenamePorterApp renames an app and records its previous name, unless another app in its cluster has the new name
+func (repo *PorterAppRepository) RenamePorterApp(ctx context.Context, app *models.PorterApp, newName string, previousNameExpiresAt time.Time) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("cannot write database")
+	}
+
+	if app == nil || app.ID == 0 {
+		return false, errors.New("porter app id is empty")
+	}
+
+	if int(app.ID-1) >= len(repo.apps) || repo.apps[app.ID-1] == nil {
+		return false, gorm.ErrRecordNotFound
+	}
+
+	stored := repo.apps[app.ID-1]
+	for _, other := range repo.apps {
+		if other != nil && other.ID != stored.ID && other.ProjectID == stored.ProjectID && other.ClusterID == stored.ClusterID && strings.EqualFold(other.Name, newName) {
+			return false, nil
+		}
+	}
+
+	stored.PreviousName = stored.Name
+	stored.Name = newName
+	stored.PreviousNameExpiresAt = &previousNameExpiresAt
+	stored.UpdatedAt = time.Now()
+
+	app.PreviousName = stored.PreviousName
+	app.Name = newName
+	app.PreviousNameExpiresAt = &previousNameExpiresAt
+	app.UpdatedAt = stored.UpdatedAt
+
+	return true, nil
+}
+
+func copyScalingSchedules(schedules models.PorterAppScalingSchedules) models.PorterAppScalingSchedules {
+	if schedules == nil {
+		return nil
+	}
+
+	copied := make(models.PorterAppScalingSchedules, len(schedules))
+	for name, service := range schedules {
+		copiedService := *service
+		copied[name] = &copiedService
+	}
+
+	return copied
 }
@@ -0,0 +1,145 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// PorterAppCleanupRepository is a test repository that implements repository.PorterAppCleanupRepository
+// and stores cleanups in-memory, indexed by their array index + 1
+type PorterAppCleanupRepository struct {
+	canQuery bool
+
+	mu       sync.Mutex
+	cleanups []*models.PorterAppCleanup
+}
+
+// NewPorterAppCleanupRepository returns the test PorterAppCleanupRepository
+func NewPorterAppCleanupRepository(canQuery bool) repository.PorterAppCleanupRepository {
+	return &PorterAppCleanupRepository{canQuery: canQuery, cleanups: []*models.PorterAppCleanup{}}
+}
+
+// CreatePorterAppCleanup records the cleanup of a deleted app
+func (repo *PorterAppCleanupRepository) CreatePorterAppCleanup(ctx context.Context, cleanup *models.PorterAppCleanup) (*models.PorterAppCleanup, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if cleanup == nil {
+		return nil, errors.New("porter app cleanup is nil")
+	}
+	if cleanup.ProjectID == 0 {
+		return nil, errors.New("project id is empty")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	now := time.Now()
+	if cleanup.CreatedAt.IsZero() {
+		cleanup.CreatedAt = now
+	}
+	cleanup.UpdatedAt = now
+
+	stored := copyPorterAppCleanup(cleanup)
+	repo.cleanups = append(repo.cleanups, stored)
+	stored.ID = uint(len(repo.cleanups))
+	cleanup.ID = stored.ID
+
+	return cleanup, nil
+}
+
+// ReadPorterAppCleanup returns a cleanup by its id, scoped to a project
+func (repo *PorterAppCleanupRepository) ReadPorterAppCleanup(ctx context.Context, projectID, id uint) (*models.PorterAppCleanup, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if id == 0 || int(id-1) >= len(repo.cleanups) || repo.cleanups[id-1].ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return copyPorterAppCleanup(repo.cleanups[id-1]), nil
+}
+
+// ListPorterAppCleanupsByStatus returns the cleanups of a project with a status, oldest first
+func (repo *PorterAppCleanupRepository) ListPorterAppCleanupsByStatus(ctx context.Context, projectID uint, status string) ([]*models.PorterAppCleanup, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := []*models.PorterAppCleanup{}
+	for _, cleanup := range repo.cleanups {
+		if cleanup.ProjectID == projectID && cleanup.Status == status {
+			res = append(res, copyPorterAppCleanup(cleanup))
+		}
+	}
+
+	return res, nil
+}
+
+// ListPendingPorterAppCleanups returns the cleanups of every project which have resources left to release, oldest first
+func (repo *PorterAppCleanupRepository) ListPendingPorterAppCleanups(ctx context.Context) ([]*models.PorterAppCleanup, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := []*models.PorterAppCleanup{}
+	for _, cleanup := range repo.cleanups {
+		if cleanup.Status == string(types.PorterAppCleanupStatus_Pending) {
+			res = append(res, copyPorterAppCleanup(cleanup))
+		}
+	}
+
+	return res, nil
+}
+
+// UpdatePorterAppCleanup writes the status and pending resources of a cleanup
+func (repo *PorterAppCleanupRepository) UpdatePorterAppCleanup(ctx context.Context, cleanup *models.PorterAppCleanup) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	if cleanup == nil || cleanup.ID == 0 {
+		return errors.New("porter app cleanup id is empty")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if int(cleanup.ID-1) >= len(repo.cleanups) {
+		return gorm.ErrRecordNotFound
+	}
+
+	stored := repo.cleanups[cleanup.ID-1]
+	stored.Status = cleanup.Status
+	stored.Pending = append(models.PorterAppCleanupResources(nil), cleanup.Pending...)
+	stored.FinishedAt = cleanup.FinishedAt
+	stored.UpdatedAt = time.Now()
+	cleanup.UpdatedAt = stored.UpdatedAt
+
+	return nil
+}
+
+func copyPorterAppCleanup(cleanup *models.PorterAppCleanup) *models.PorterAppCleanup {
+	copied := *cleanup
+	copied.Pending = append(models.PorterAppCleanupResources(nil), cleanup.Pending...)
+
+	return &copied
+}
@@ -0,0 +1,170 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// PorterAppDomainRepository is a test repository that implements repository.PorterAppDomainRepository
+// and stores domains in-memory by their id
+type PorterAppDomainRepository struct {
+	canQuery bool
+
+	mu      sync.Mutex
+	nextID  uint
+	domains map[uint]*models.PorterAppDomain
+}
+
+// NewPorterAppDomainRepository returns the test PorterAppDomainRepository
+func NewPorterAppDomainRepository(canQuery bool) repository.PorterAppDomainRepository {
+	return &PorterAppDomainRepository{canQuery: canQuery, domains: map[uint]*models.PorterAppDomain{}}
+}
+
+// ListPorterAppDomains returns the domains of an app, ordered by hostname
+func (repo *PorterAppDomainRepository) ListPorterAppDomains(ctx context.Context, porterAppID uint) ([]*models.PorterAppDomain, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := []*models.PorterAppDomain{}
+	for _, domain := range repo.domains {
+		if domain.PorterAppID == porterAppID {
+			res = append(res, copyPorterAppDomain(domain))
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Hostname < res[j].Hostname })
+
+	return res, nil
+}
+
+// ListPorterAppDomainsByHostnames returns the domains of the apps of a project which have one of the given hostnames
+func (repo *PorterAppDomainRepository) ListPorterAppDomainsByHostnames(ctx context.Context, projectID uint, hostnames []string) ([]*models.PorterAppDomain, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	wanted := make(map[string]bool, len(hostnames))
+	for _, hostname := range hostnames {
+		wanted[hostname] = true
+	}
+
+	res := []*models.PorterAppDomain{}
+	for _, domain := range repo.domains {
+		if domain.ProjectID == projectID && wanted[domain.Hostname] {
+			res = append(res, copyPorterAppDomain(domain))
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Hostname < res[j].Hostname })
+
+	return res, nil
+}
+
+// CreatePorterAppDomain records a domain served by an app
+func (repo *PorterAppDomainRepository) CreatePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) (*models.PorterAppDomain, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if domain == nil {
+		return nil, errors.New("porter app domain is nil")
+	}
+	if domain.PorterAppID == 0 {
+		return nil, errors.New("porter app id is empty")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	now := time.Now()
+	domain.CreatedAt = now
+	domain.UpdatedAt = now
+
+	repo.nextID++
+	domain.ID = repo.nextID
+	repo.domains[domain.ID] = copyPorterAppDomain(domain)
+
+	return domain, nil
+}
+
+// UpdatePorterAppDomain writes the certificate of a domain, and the alerts sent about it
+func (repo *PorterAppDomainRepository) UpdatePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	if domain == nil || domain.ID == 0 {
+		return errors.New("porter app domain id is empty")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, ok := repo.domains[domain.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	updated := copyPorterAppDomain(domain)
+	updated.ProjectID = stored.ProjectID
+	updated.ClusterID = stored.ClusterID
+	updated.PorterAppID = stored.PorterAppID
+	updated.Hostname = stored.Hostname
+	updated.CreatedAt = stored.CreatedAt
+	updated.UpdatedAt = time.Now()
+	domain.UpdatedAt = updated.UpdatedAt
+
+	repo.domains[domain.ID] = updated
+
+	return nil
+}
+
+// DeletePorterAppDomain deletes a domain which is no longer served by its app
+func (repo *PorterAppDomainRepository) DeletePorterAppDomain(ctx context.Context, domain *models.PorterAppDomain) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	if domain == nil || domain.ID == 0 {
+		return errors.New("porter app domain id is empty")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.domains, domain.ID)
+
+	return nil
+}
+
+func copyPorterAppDomain(domain *models.PorterAppDomain) *models.PorterAppDomain {
+	copied := *domain
+	if domain.CertExpiresAt != nil {
+		expiresAt := *domain.CertExpiresAt
+		copied.CertExpiresAt = &expiresAt
+	}
+	if domain.CheckedAt != nil {
+		checkedAt := *domain.CheckedAt
+		copied.CheckedAt = &checkedAt
+	}
+	if domain.AlertedThresholdDays != nil {
+		alerted := *domain.AlertedThresholdDays
+		copied.AlertedThresholdDays = &alerted
+	}
+
+	return &copied
+}
@@ -2,63 +2,505 @@ package test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
+	"gorm.io/gorm"
 )
 
+const (
+	CreatePorterAppEventMethod string = "create_porter_app_event_0"
+	ReadPorterAppEventMethod   string = "read_porter_app_event_0"
+	ListPorterAppEventsMethod  string = "list_porter_app_events_0"
+)
+
+// PorterAppEventRepository will return errors on queries if canQuery is false
+// and stores porter app events in-memory, in the order they were created
 type PorterAppEventRepository struct {
-	canQuery bool
+	canQuery       bool
+	failingMethods string
+	events         []*models.PorterAppEvent
 }
 
 func NewPorterAppEventRepository(canQuery bool, failingMethods ...string) repository.PorterAppEventRepository {
-	return &PorterAppEventRepository{canQuery: false}
+	return &PorterAppEventRepository{canQuery, strings.Join(failingMethods, ","), []*models.PorterAppEvent{}}
 }
 
+// ListEventsByPorterAppID returns a page of the events of a porter app, newest first
 func (repo *PorterAppEventRepository) ListEventsByPorterAppID(ctx context.Context, porterAppID uint, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error) {
-	return nil, helpers.PaginatedResult{}, errors.New("cannot write database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppEventsMethod) {
+		return nil, helpers.PaginatedResult{}, errors.New("cannot read database")
+	}
+
+	events, result := paginate(repo.filter(func(event *models.PorterAppEvent) bool {
+		return event.PorterAppID == porterAppID
+	}), opts...)
+
+	return events, result, nil
+}
+
+// ListFilteredEventsByPorterAppID returns the events of a porter app which match the filter, newest first, along with
+// the number of events which match before the limit and offset are applied
+func (repo *PorterAppEventRepository) ListFilteredEventsByPorterAppID(ctx context.Context, porterAppID uint, filter repository.PorterAppEventFilter) ([]*models.PorterAppEvent, int64, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppEventsMethod) {
+		return nil, 0, errors.New("cannot read database")
+	}
+
+	events := newestFirst(repo.filter(func(event *models.PorterAppEvent) bool {
+		return event.PorterAppID == porterAppID &&
+			(filter.Type == "" || event.Type == filter.Type) &&
+			(filter.Status == "" || event.Status == filter.Status) &&
+			(filter.DeploymentTargetID == nil || event.DeploymentTargetID == *filter.DeploymentTargetID) &&
+			messageMatches(event.Message, filter.Search)
+	}))
+	total := int64(len(events))
+
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(events) {
+		offset = len(events)
+	}
+	events = events[offset:]
+
+	if filter.Limit > 0 && filter.Limit < len(events) {
+		events = events[:filter.Limit]
+	}
+
+	return events, total, nil
 }
 
-// ListEventsByPorterAppIDAndDeploymentTargetID is a test method
+// messageMatches returns true if the message contains every word of the search, ignoring case
+func messageMatches(message string, search string) bool {
+	message = strings.ToLower(message)
+	for _, term := range strings.Fields(strings.ToLower(search)) {
+		if !strings.Contains(message, term) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ListEventsByPorterAppIDAndDeploymentTargetID returns a page of the events of a porter app in a deployment target, newest first
 func (repo *PorterAppEventRepository) ListEventsByPorterAppIDAndDeploymentTargetID(ctx context.Context, porterAppID uint, deploymentTargetID uuid.UUID, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error) {
-	return nil, helpers.PaginatedResult{}, errors.New("cannot write database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppEventsMethod) {
+		return nil, helpers.PaginatedResult{}, errors.New("cannot read database")
+	}
+
+	events, result := paginate(repo.filter(func(event *models.PorterAppEvent) bool {
+		return event.PorterAppID == porterAppID && event.DeploymentTargetID == deploymentTargetID
+	}), opts...)
+
+	return events, result, nil
 }
 
-// ListBuildDeployEventsByPorterAppIDAndDeploymentTargetID is a test method
+// ListBuildDeployEventsByPorterAppIDAndDeploymentTargetID returns a page of the events of a porter app in a deployment
+// target, newest first, withholding notification and app_event type events
 func (repo *PorterAppEventRepository) ListBuildDeployEventsByPorterAppIDAndDeploymentTargetID(ctx context.Context, porterAppID uint, deploymentTargetID uuid.UUID, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult, error) {
-	return nil, helpers.PaginatedResult{}, errors.New("cannot write database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppEventsMethod) {
+		return nil, helpers.PaginatedResult{}, errors.New("cannot read database")
+	}
+
+	events, result := paginate(repo.filter(func(event *models.PorterAppEvent) bool {
+		return event.PorterAppID == porterAppID && event.DeploymentTargetID == deploymentTargetID &&
+			event.Type != "APP_EVENT" && event.Type != "NOTIFICATION"
+	}), opts...)
+
+	return events, result, nil
 }
 
+// CreateEvent appends a new event to the in-memory events array
 func (repo *PorterAppEventRepository) CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
-	return errors.New("cannot write database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, CreatePorterAppEventMethod) {
+		return errors.New("cannot write database")
+	}
+
+	if appEvent.ID == uuid.Nil {
+		appEvent.ID = uuid.New()
+	}
+	if appEvent.CreatedAt.IsZero() {
+		appEvent.CreatedAt = time.Now().UTC()
+	}
+	if appEvent.UpdatedAt.IsZero() {
+		appEvent.UpdatedAt = time.Now().UTC()
+	}
+	if appEvent.PorterAppID == 0 {
+		return errors.New("invalid porter app id supplied to create event")
+	}
+
+	if repo.find(appEvent.ID) != nil {
+		return errors.New("duplicate key value violates unique constraint")
+	}
+
+	event := *appEvent
+	repo.events = append(repo.events, &event)
+
+	return nil
+}
+
+// CreateEvents appends new events to the in-memory events array, in the order they are given. No events are
+// appended if any of them cannot be created.
+func (repo *PorterAppEventRepository) CreateEvents(ctx context.Context, appEvents []*models.PorterAppEvent) error {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, CreatePorterAppEventMethod) {
+		return errors.New("cannot write database")
+	}
+
+	ids := make(map[uuid.UUID]bool, len(appEvents))
+	for _, appEvent := range appEvents {
+		if appEvent.ID == uuid.Nil {
+			appEvent.ID = uuid.New()
+		}
+		if appEvent.CreatedAt.IsZero() {
+			appEvent.CreatedAt = time.Now().UTC()
+		}
+		if appEvent.UpdatedAt.IsZero() {
+			appEvent.UpdatedAt = time.Now().UTC()
+		}
+		if appEvent.PorterAppID == 0 {
+			return errors.New("invalid porter app id supplied to create events")
+		}
+		if ids[appEvent.ID] || repo.find(appEvent.ID) != nil {
+			return errors.New("duplicate key value violates unique constraint")
+		}
+		ids[appEvent.ID] = true
+	}
+
+	for _, appEvent := range appEvents {
+		event := *appEvent
+		repo.events = append(repo.events, &event)
+	}
+
+	return nil
 }
 
+// UpdateEvent writes the fields of appEvent which are set to the stored event, as gorm's Updates does
 func (repo *PorterAppEventRepository) UpdateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
-	return errors.New("cannot update database")
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	if appEvent.PorterAppID == 0 {
+		return errors.New("invalid porter app id supplied to update event")
+	}
+
+	if appEvent.ID == uuid.Nil {
+		return errors.New("invalid porter app event id supplied to update event")
+	}
+
+	if appEvent.UpdatedAt.IsZero() {
+		appEvent.UpdatedAt = time.Now().UTC()
+	}
+
+	event := repo.find(appEvent.ID)
+	if event == nil {
+		return nil
+	}
+
+	if appEvent.Status != "" {
+		event.Status = appEvent.Status
+	}
+	if appEvent.Type != "" {
+		event.Type = appEvent.Type
+	}
+	if appEvent.TypeExternalSource != "" {
+		event.TypeExternalSource = appEvent.TypeExternalSource
+	}
+	if !appEvent.CreatedAt.IsZero() {
+		event.CreatedAt = appEvent.CreatedAt
+	}
+	if appEvent.AppInstanceID != uuid.Nil {
+		event.AppInstanceID = appEvent.AppInstanceID
+	}
+	if appEvent.DeploymentTargetID != uuid.Nil {
+		event.DeploymentTargetID = appEvent.DeploymentTargetID
+	}
+	if appEvent.Metadata != nil {
+		event.Metadata = appEvent.Metadata
+	}
+	event.PorterAppID = appEvent.PorterAppID
+	event.UpdatedAt = appEvent.UpdatedAt
+
+	return nil
 }
 
+// ReadEvent returns the event with the given id, or gorm.ErrRecordNotFound if it does not exist
 func (repo *PorterAppEventRepository) ReadEvent(ctx context.Context, id uuid.UUID) (models.PorterAppEvent, error) {
-	return models.PorterAppEvent{}, errors.New("cannot read database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadPorterAppEventMethod) {
+		return models.PorterAppEvent{}, errors.New("cannot read database")
+	}
+
+	if id == uuid.Nil {
+		return models.PorterAppEvent{}, errors.New("invalid porter app event id supplied")
+	}
+
+	event := repo.find(id)
+	if event == nil {
+		return models.PorterAppEvent{}, gorm.ErrRecordNotFound
+	}
+
+	return *event, nil
 }
 
+// ReadDeployEventByRevision returns the deploy event of a porter app for a helm revision
 func (repo *PorterAppEventRepository) ReadDeployEventByRevision(ctx context.Context, porterAppID uint, revision float64) (models.PorterAppEvent, error) {
-	return models.PorterAppEvent{}, errors.New("cannot read database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadPorterAppEventMethod) {
+		return models.PorterAppEvent{}, errors.New("cannot read database")
+	}
+
+	if porterAppID == 0 {
+		return models.PorterAppEvent{}, errors.New("invalid porter app ID supplied")
+	}
+
+	revJSON, err := json.Marshal(revision)
+	if err != nil {
+		return models.PorterAppEvent{}, errors.New("unable to marshal revision")
+	}
+
+	for _, event := range repo.events {
+		if event.PorterAppID == porterAppID && event.Type == "DEPLOY" && metadataText(event.Metadata, "revision") == string(revJSON) {
+			return *event, nil
+		}
+	}
+
+	return models.PorterAppEvent{}, gorm.ErrRecordNotFound
 }
 
-// ReadDeployEventByAppRevisionID is a test method
+// ReadDeployEventByAppRevisionID returns a deploy event for a given porter app id and app revision ID
 func (repo *PorterAppEventRepository) ReadDeployEventByAppRevisionID(ctx context.Context, porterAppID uint, appRevisionID string) (models.PorterAppEvent, error) {
-	return models.PorterAppEvent{}, errors.New("cannot read database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadPorterAppEventMethod) {
+		return models.PorterAppEvent{}, errors.New("cannot read database")
+	}
+
+	if porterAppID == 0 {
+		return models.PorterAppEvent{}, errors.New("invalid porter app ID supplied")
+	}
+
+	if appRevisionID == "" {
+		return models.PorterAppEvent{}, errors.New("no app revision ID supplied")
+	}
+
+	for _, event := range repo.events {
+		if event.PorterAppID == porterAppID && event.Type == "DEPLOY" && metadataText(event.Metadata, "app_revision_id") == appRevisionID {
+			return *event, nil
+		}
+	}
+
+	return models.PorterAppEvent{}, gorm.ErrRecordNotFound
 }
 
-// ReadNotificationsByAppRevisionID is a test method
+// ReadNotificationsByAppRevisionID returns a list of notifications for a given porter app instance id and app revision ID
 func (repo *PorterAppEventRepository) ReadNotificationsByAppRevisionID(ctx context.Context, porterAppInstanceID uuid.UUID, appRevisionID string) ([]*models.PorterAppEvent, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadPorterAppEventMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	if appRevisionID == "" {
+		return []*models.PorterAppEvent{}, errors.New("invalid app revision ID supplied")
+	}
+
+	if porterAppInstanceID == uuid.Nil {
+		return []*models.PorterAppEvent{}, errors.New("invalid porter app instance ID supplied")
+	}
+
+	return repo.filter(func(event *models.PorterAppEvent) bool {
+		return event.AppInstanceID == porterAppInstanceID && event.Type == "NOTIFICATION" && metadataText(event.Metadata, "app_revision_id") == appRevisionID
+	}), nil
 }
 
-// NotificationByID returns a notification by the notification id
+// NotificationByID returns a notification by the notification id, or an empty event if it does not exist
 func (repo *PorterAppEventRepository) NotificationByID(ctx context.Context, notificationID string) (*models.PorterAppEvent, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadPorterAppEventMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, event := range repo.events {
+		if event.Type == "NOTIFICATION" && metadataText(event.Metadata, "id") == notificationID {
+			notification := *event
+			return &notification, nil
+		}
+	}
+
+	return &models.PorterAppEvent{}, nil
+}
+
+// ListEventsCreatedBetween returns the events of the given types created in [start, end), across all apps
+func (repo *PorterAppEventRepository) ListEventsCreatedBetween(ctx context.Context, start, end time.Time, eventTypes []string) ([]*models.PorterAppEvent, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListPorterAppEventsMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	return repo.filter(func(event *models.PorterAppEvent) bool {
+		if event.CreatedAt.Before(start) || !event.CreatedAt.Before(end) {
+			return false
+		}
+
+		for _, eventType := range eventTypes {
+			if event.Type == eventType {
+				return true
+			}
+		}
+
+		return false
+	}), nil
+}
+
+// ReadLatestEventByType returns the most recent event of a type on a porter app, or gorm.ErrRecordNotFound if it has none
+func (repo *PorterAppEventRepository) ReadLatestEventByType(ctx context.Context, porterAppID uint, eventType string) (*models.PorterAppEvent, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ReadPorterAppEventMethod) {
+		return nil, errors.New("cannot read database")
+	}
+
+	events := newestFirst(repo.filter(func(event *models.PorterAppEvent) bool {
+		return event.PorterAppID == porterAppID && event.Type == eventType
+	}))
+
+	if len(events) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return events[0], nil
+}
+
+// DeleteEventsCreatedBetween removes every event created in [start, end) from the in-memory events array, returning
+// the number deleted
+func (repo *PorterAppEventRepository) DeleteEventsCreatedBetween(ctx context.Context, start, end time.Time) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("cannot write database")
+	}
+
+	remaining := make([]*models.PorterAppEvent, 0, len(repo.events))
+	for _, event := range repo.events {
+		if event.CreatedAt.Before(start) || !event.CreatedAt.Before(end) {
+			remaining = append(remaining, event)
+		}
+	}
+
+	deleted := int64(len(repo.events) - len(remaining))
+	repo.events = remaining
+
+	return deleted, nil
+}
+
+// DeleteEventsByPorterAppID removes every event of a porter app from the in-memory events array, returning the number
+// deleted
+func (repo *PorterAppEventRepository) DeleteEventsByPorterAppID(ctx context.Context, porterAppID uint) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("cannot write database")
+	}
+
+	if porterAppID == 0 {
+		return 0, errors.New("invalid porter app id supplied")
+	}
+
+	remaining := make([]*models.PorterAppEvent, 0, len(repo.events))
+	for _, event := range repo.events {
+		if event.PorterAppID != porterAppID {
+			remaining = append(remaining, event)
+		}
+	}
+
+	deleted := int64(len(repo.events) - len(remaining))
+	repo.events = remaining
+
+	return deleted, nil
+}
+
+func (repo *PorterAppEventRepository) find(id uuid.UUID) *models.PorterAppEvent {
+	for _, event := range repo.events {
+		if event.ID == id {
+			return event
+		}
+	}
+
+	return nil
+}
+
+// filter returns copies of the events which match, so that callers cannot modify the stored events
+func (repo *PorterAppEventRepository) filter(match func(event *models.PorterAppEvent) bool) []*models.PorterAppEvent {
+	res := []*models.PorterAppEvent{}
+
+	for _, event := range repo.events {
+		if match(event) {
+			copied := *event
+			res = append(res, &copied)
+		}
+	}
+
+	return res
+}
+
+func newestFirst(events []*models.PorterAppEvent) []*models.PorterAppEvent {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.After(events[j].CreatedAt)
+	})
+
+	return events
+}
+
+// paginate sorts events newest first and returns the page requested by opts, with the same defaults as
+// helpers.Paginate
+func paginate(events []*models.PorterAppEvent, opts ...helpers.QueryOption) ([]*models.PorterAppEvent, helpers.PaginatedResult) {
+	q := helpers.Query{
+		PageSize: 50,
+		Page:     0,
+	}
+
+	for _, opt := range opts {
+		opt(&q)
+	}
+
+	result := helpers.PaginatedResult{
+		NumPages:    int64(math.Ceil(float64(len(events)) / float64(q.PageSize))),
+		CurrentPage: int64(q.Page),
+		NextPage:    int64(q.Page + 1),
+	}
+	if result.CurrentPage >= result.NumPages {
+		result.NextPage = result.NumPages
+	}
+
+	// a query without a page is not offset
+	offset := (q.Page - 1) * q.PageSize
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(events) {
+		offset = len(events)
+	}
+
+	end := offset + q.PageSize
+	if end > len(events) {
+		end = len(events)
+	}
+
+	return newestFirst(events)[offset:end], result
+}
+
+// metadataText returns a metadata value as the ->> operator of postgres does: strings unquoted, and other values as
+// JSON
+func metadataText(metadata models.JSONB, key string) string {
+	value, ok := metadata[key]
+	if !ok || value == nil {
+		return ""
+	}
+
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(valueJSON)
 }
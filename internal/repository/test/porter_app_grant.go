@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// PorterAppGrantRepository is a test repository that implements repository.PorterAppGrantRepository
+// and stores app grants in-memory, indexed by their array index + 1
+type PorterAppGrantRepository struct {
+	canQuery bool
+	grants   []*models.PorterAppGrant
+}
+
+// NewPorterAppGrantRepository returns the test PorterAppGrantRepository
+func NewPorterAppGrantRepository(canQuery bool) repository.PorterAppGrantRepository {
+	return &PorterAppGrantRepository{canQuery, []*models.PorterAppGrant{}}
+}
+
+// CreatePorterAppGrant creates a new app grant
+func (repo *PorterAppGrantRepository) CreatePorterAppGrant(ctx context.Context, grant *models.PorterAppGrant) (*models.PorterAppGrant, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if grant == nil {
+		return nil, errors.New("app grant is nil")
+	}
+	if grant.ProjectID == 0 {
+		return nil, errors.New("project id is empty")
+	}
+	if (grant.UserID == 0) == (grant.Role == "") {
+		return nil, errors.New("exactly one of user id and role must be set")
+	}
+
+	repo.grants = append(repo.grants, grant)
+	grant.ID = uint(len(repo.grants))
+
+	return grant, nil
+}
+
+// ReadPorterAppGrant returns an app grant by its id, scoped to a project
+func (repo *PorterAppGrantRepository) ReadPorterAppGrant(ctx context.Context, projectID, id uint) (*models.PorterAppGrant, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	if id == 0 || int(id-1) >= len(repo.grants) || repo.grants[id-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	grant := repo.grants[id-1]
+	if grant.ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return grant, nil
+}
+
+// ListPorterAppGrantsByProjectID returns every app grant of a project, oldest first
+func (repo *PorterAppGrantRepository) ListPorterAppGrantsByProjectID(ctx context.Context, projectID uint) ([]*models.PorterAppGrant, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.PorterAppGrant{}
+	for _, grant := range repo.grants {
+		if grant != nil && grant.ProjectID == projectID {
+			res = append(res, grant)
+		}
+	}
+
+	return res, nil
+}
+
+// ListMatchingPorterAppGrants returns the app grants of a project whose pattern matches an app, and which are for
+// the user or the role
+func (repo *PorterAppGrantRepository) ListMatchingPorterAppGrants(ctx context.Context, projectID uint, appName string, userID uint, role string) ([]*models.PorterAppGrant, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	anyHolder := userID == 0 && role == ""
+
+	res := []*models.PorterAppGrant{}
+	for _, grant := range repo.grants {
+		if grant == nil || grant.ProjectID != projectID || !grant.MatchesApp(appName) {
+			continue
+		}
+
+		if anyHolder || (userID != 0 && grant.UserID == userID) || (role != "" && grant.Role == role) {
+			res = append(res, grant)
+		}
+	}
+
+	return res, nil
+}
+
+// DeletePorterAppGrant deletes an app grant
+func (repo *PorterAppGrantRepository) DeletePorterAppGrant(ctx context.Context, grant *models.PorterAppGrant) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	if grant.ID == 0 || int(grant.ID-1) >= len(repo.grants) || repo.grants[grant.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.grants[grant.ID-1] = nil
+
+	return nil
+}
@@ -1,11 +1,16 @@
 package test
 
 import (
+	"context"
 	"errors"
+	"math"
+	"sort"
 	"strings"
 
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
 	"gorm.io/gorm"
 )
 
@@ -14,6 +19,7 @@ const (
 	CreateProjectRoleMethod    string = "create_project_role_0"
 	ReadProjectMethod          string = "read_project_0"
 	ListProjectsByUserIDMethod string = "list_projects_by_user_id_0"
+	ListProjectSummariesMethod string = "list_project_summaries_0"
 )
 
 // ProjectRepository will return errors on queries if canQuery is false
@@ -100,7 +106,7 @@ func (repo *ProjectRepository) UpdateProjectRole(projID uint, role *models.Role)
 }
 
 // ReadProject gets a projects specified by a unique id
-func (repo *ProjectRepository) ReadProjectRole(userID, projID uint) (*models.Role, error) {
+func (repo *ProjectRepository) ReadProjectRole(projID, userID uint) (*models.Role, error) {
 	if !repo.canQuery {
 		return nil, errors.New("Cannot read from database")
 	}
@@ -155,6 +161,84 @@ func (repo *ProjectRepository) ListProjectsByUserID(userID uint) ([]*models.Proj
 	return resp, nil
 }
 
+// ListProjectSummariesByUserID lists a page of projects where a user has an associated role. The
+// in-memory repository has no access to apps, clusters or events, so only the role is populated.
+func (repo *ProjectRepository) ListProjectSummariesByUserID(
+	ctx context.Context,
+	userID uint,
+	filter repository.ProjectSummaryFilter,
+	opts ...helpers.QueryOption,
+) ([]*models.ProjectSummary, helpers.PaginatedResult, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, ListProjectSummariesMethod) {
+		return nil, helpers.PaginatedResult{}, errors.New("Cannot read from database")
+	}
+
+	summaries := make([]*models.ProjectSummary, 0)
+
+	for _, project := range repo.projects {
+		if project == nil {
+			continue
+		}
+
+		if filter.Search != "" && !strings.Contains(strings.ToLower(project.Name), strings.ToLower(filter.Search)) {
+			continue
+		}
+
+		for _, role := range project.Roles {
+			if role.UserID == userID {
+				summaries = append(summaries, &models.ProjectSummary{
+					Project:  project,
+					RoleKind: role.Kind,
+				})
+				break
+			}
+		}
+	}
+
+	switch filter.SortBy {
+	case types.ProjectListSortName, "":
+		sort.SliceStable(summaries, func(i, j int) bool {
+			return strings.ToLower(summaries[i].Project.Name) < strings.ToLower(summaries[j].Project.Name)
+		})
+	default:
+		// projects are created in id order, and no project has activity in the in-memory repository
+		sort.SliceStable(summaries, func(i, j int) bool {
+			return summaries[i].Project.ID > summaries[j].Project.ID
+		})
+	}
+
+	q := helpers.Query{
+		PageSize: 50,
+		Page:     1,
+	}
+
+	for _, opt := range opts {
+		opt(&q)
+	}
+
+	paginatedResult := helpers.PaginatedResult{
+		NumPages:    int64(math.Ceil(float64(len(summaries)) / float64(q.PageSize))),
+		CurrentPage: int64(q.Page),
+		NextPage:    int64(q.Page + 1),
+	}
+
+	if paginatedResult.CurrentPage >= paginatedResult.NumPages {
+		paginatedResult.NextPage = paginatedResult.NumPages
+	}
+
+	start := (q.Page - 1) * q.PageSize
+	if start >= len(summaries) {
+		return []*models.ProjectSummary{}, paginatedResult, nil
+	}
+
+	end := start + q.PageSize
+	if end > len(summaries) {
+		end = len(summaries)
+	}
+
+	return summaries[start:end], paginatedResult, nil
+}
+
 // ListProjectRoles returns a list of roles for the project
 func (repo *ProjectRepository) ListProjectRoles(projID uint) ([]models.Role, error) {
 	if !repo.canQuery {
@@ -227,3 +311,19 @@ func (repo *ProjectRepository) DeleteProjectRole(projID, userID uint) (*models.R
 func (repo *ProjectRepository) DeleteRolesForProject(projID uint) error {
 	return errors.New("unimplemented")
 }
+
+// CountProjects returns the number of projects of the instance
+func (repo *ProjectRepository) CountProjects(ctx context.Context) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("Cannot read from database")
+	}
+
+	var count int64
+	for _, project := range repo.projects {
+		if project != nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
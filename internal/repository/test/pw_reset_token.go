@@ -63,3 +63,18 @@ func (repo *PWResetTokenRepository) UpdatePWResetToken(
 
 	return pwToken, nil
 }
+
+// InvalidatePWResetTokensByEmail invalidates every valid PWResetToken for an email which was created for a purpose
+func (repo *PWResetTokenRepository) InvalidatePWResetTokensByEmail(email string, purpose models.PWResetTokenPurpose) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	for _, pwToken := range repo.pwResetTokens {
+		if pwToken != nil && pwToken.Email == email && pwToken.Purpose == purpose {
+			pwToken.IsValid = false
+		}
+	}
+
+	return nil
+}
@@ -45,7 +45,7 @@ func (repo *RegistryRepository) ReadRegistry(
 		return nil, errors.New("Cannot read from database")
 	}
 
-	if int(regID-1) >= len(repo.registries) || repo.registries[regID-1] == nil {
+	if int(regID-1) >= len(repo.registries) || repo.registries[regID-1] == nil || repo.registries[regID-1].ProjectID != projectID {
 		return nil, gorm.ErrRecordNotFound
 	}
 
@@ -53,10 +53,21 @@ func (repo *RegistryRepository) ReadRegistry(
 	return repo.registries[index], nil
 }
 
+// ReadRegistryByInfraID finds the registry of a project which was provisioned by an infra
 func (repo *RegistryRepository) ReadRegistryByInfraID(
 	projectID, infraID uint,
 ) (*models.Registry, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, reg := range repo.registries {
+		if reg != nil && reg.ProjectID == projectID && reg.InfraID == infraID {
+			return reg, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
 }
 
 // ListRegistriesByProjectID finds all registries
@@ -112,6 +123,46 @@ func (repo *RegistryRepository) UpdateRegistryTokenCache(
 	return repo.registries[index], nil
 }
 
+// UpdateRegistryCredentialStatus writes the credential status of a registry
+func (repo *RegistryRepository) UpdateRegistryCredentialStatus(
+	reg *models.Registry,
+) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if int(reg.ID-1) >= len(repo.registries) || repo.registries[reg.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	index := int(reg.ID - 1)
+	repo.registries[index].CredentialStatus = reg.CredentialStatus
+	repo.registries[index].CredentialError = reg.CredentialError
+	repo.registries[index].CredentialCheckedAt = reg.CredentialCheckedAt
+
+	return nil
+}
+
+// ListRegistriesByCredentialStatus finds all registries whose credentials
+// were last recorded with the given status
+func (repo *RegistryRepository) ListRegistriesByCredentialStatus(
+	status string,
+) ([]*models.Registry, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.Registry, 0)
+
+	for _, reg := range repo.registries {
+		if reg != nil && reg.CredentialStatus == status {
+			res = append(res, reg)
+		}
+	}
+
+	return res, nil
+}
+
 // DeleteRegistry removes a registry from the array by setting it to nil
 func (repo *RegistryRepository) DeleteRegistry(
 	reg *models.Registry,
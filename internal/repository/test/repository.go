@@ -57,6 +57,21 @@ type TestRepository struct {
 	githubWebhook             repository.GithubWebhookRepository
 	datastore                 repository.DatastoreRepository
 	appInstance               repository.AppInstanceRepository
+	logAlertRule              repository.LogAlertRuleRepository
+	helmReleaseImport         repository.HelmReleaseImportRepository
+	bulkRedeploy              repository.BulkRedeployRepository
+	usageRollup               repository.UsageRollupRepository
+	inactivityPolicy          repository.InactivityPolicyRepository
+	secretsProvider           repository.SecretsProviderIntegrationRepository
+	debugRecording            repository.DebugRecordingRepository
+	auditLog                  repository.AuditLogRepository
+	lock                      repository.LockRepository
+	webhookDelivery           repository.WebhookDeliveryRepository
+	porterAppGrant            repository.PorterAppGrantRepository
+	tokenCache                repository.TokenCacheRepository
+	porterAppCleanup          repository.PorterAppCleanupRepository
+	porterAppDomain           repository.PorterAppDomainRepository
+	userTwoFactor             repository.UserTwoFactorRepository
 }
 
 func (t *TestRepository) User() repository.UserRepository {
@@ -273,16 +288,95 @@ func (t *TestRepository) AppInstance() repository.AppInstanceRepository {
 	return t.appInstance
 }
 
+// LogAlertRule returns a test LogAlertRuleRepository
+func (t *TestRepository) LogAlertRule() repository.LogAlertRuleRepository {
+	return t.logAlertRule
+}
+
+// HelmReleaseImport returns a test HelmReleaseImportRepository
+func (t *TestRepository) HelmReleaseImport() repository.HelmReleaseImportRepository {
+	return t.helmReleaseImport
+}
+
+// BulkRedeploy returns a test BulkRedeployRepository
+func (t *TestRepository) BulkRedeploy() repository.BulkRedeployRepository {
+	return t.bulkRedeploy
+}
+
+// UsageRollup returns a test UsageRollupRepository
+func (t *TestRepository) UsageRollup() repository.UsageRollupRepository {
+	return t.usageRollup
+}
+
+// InactivityPolicy returns a test InactivityPolicyRepository
+func (t *TestRepository) InactivityPolicy() repository.InactivityPolicyRepository {
+	return t.inactivityPolicy
+}
+
+// SecretsProviderIntegration returns a test SecretsProviderIntegrationRepository
+func (t *TestRepository) SecretsProviderIntegration() repository.SecretsProviderIntegrationRepository {
+	return t.secretsProvider
+}
+
+// DebugRecording returns a test DebugRecordingRepository
+func (t *TestRepository) DebugRecording() repository.DebugRecordingRepository {
+	return t.debugRecording
+}
+
+// AuditLog returns a test AuditLogRepository
+func (t *TestRepository) AuditLog() repository.AuditLogRepository {
+	return t.auditLog
+}
+
+// Lock returns a test LockRepository
+func (t *TestRepository) Lock() repository.LockRepository {
+	return t.lock
+}
+
+// WebhookDelivery returns a test WebhookDeliveryRepository
+func (t *TestRepository) WebhookDelivery() repository.WebhookDeliveryRepository {
+	return t.webhookDelivery
+}
+
+// PorterAppGrant returns a test PorterAppGrantRepository
+func (t *TestRepository) PorterAppGrant() repository.PorterAppGrantRepository {
+	return t.porterAppGrant
+}
+
+// TokenCache returns a test TokenCacheRepository
+func (t *TestRepository) TokenCache() repository.TokenCacheRepository {
+	return t.tokenCache
+}
+
+// PorterAppCleanup returns a test PorterAppCleanupRepository
+func (t *TestRepository) PorterAppCleanup() repository.PorterAppCleanupRepository {
+	return t.porterAppCleanup
+}
+
+// PorterAppDomain returns a test PorterAppDomainRepository
+func (t *TestRepository) PorterAppDomain() repository.PorterAppDomainRepository {
+	return t.porterAppDomain
+}
+
+// UserTwoFactor returns a test UserTwoFactorRepository
+func (t *TestRepository) UserTwoFactor() repository.UserTwoFactorRepository {
+	return t.userTwoFactor
+}
+
 // NewRepository returns a Repository which persists users in memory
 // and accepts a parameter that can trigger read/write errors
 func NewRepository(canQuery bool, failingMethods ...string) repository.Repository {
+	cluster := NewClusterRepository(canQuery)
+	helmRepo := NewHelmRepoRepository(canQuery)
+	registry := NewRegistryRepository(canQuery)
+
 	return &TestRepository{
 		user:                      NewUserRepository(canQuery, failingMethods...),
 		session:                   NewSessionRepository(canQuery, failingMethods...),
 		project:                   NewProjectRepository(canQuery, failingMethods...),
-		cluster:                   NewClusterRepository(canQuery),
-		helmRepo:                  NewHelmRepoRepository(canQuery),
-		registry:                  NewRegistryRepository(canQuery),
+		cluster:                   cluster,
+		helmRepo:                  helmRepo,
+		registry:                  registry,
 		gitRepo:                   NewGitRepoRepository(canQuery),
 		gitActionConfig:           NewGitActionConfigRepository(canQuery),
 		invite:                    NewInviteRepository(canQuery),
@@ -322,12 +416,27 @@ func NewRepository(canQuery bool, failingMethods ...string) repository.Repositor
 		apiContractRevision:       NewAPIContractRevisioner(),
 		awsAssumeRoleChainer:      NewAWSAssumeRoleChainer(),
 		porterApp:                 NewPorterAppRepository(canQuery, failingMethods...),
-		porterAppEvent:            NewPorterAppEventRepository(canQuery),
+		porterAppEvent:            NewPorterAppEventRepository(canQuery, failingMethods...),
 		deploymentTarget:          NewDeploymentTargetRepository(),
 		appRevision:               NewAppRevisionRepository(),
 		appTemplate:               NewAppTemplateRepository(),
 		githubWebhook:             NewGithubWebhookRepository(),
 		datastore:                 NewDatastoreRepository(),
 		appInstance:               NewAppInstanceRepository(),
+		logAlertRule:              NewLogAlertRuleRepository(canQuery),
+		helmReleaseImport:         NewHelmReleaseImportRepository(),
+		bulkRedeploy:              NewBulkRedeployRepository(),
+		usageRollup:               NewUsageRollupRepository(canQuery),
+		inactivityPolicy:          NewInactivityPolicyRepository(),
+		secretsProvider:           NewSecretsProviderIntegrationRepository(),
+		debugRecording:            NewDebugRecordingRepository(canQuery),
+		auditLog:                  NewAuditLogRepository(canQuery),
+		lock:                      NewLockRepository(canQuery),
+		webhookDelivery:           NewWebhookDeliveryRepository(canQuery),
+		porterAppGrant:            NewPorterAppGrantRepository(canQuery),
+		tokenCache:                NewTokenCacheRepository(canQuery, cluster, registry, helmRepo),
+		porterAppCleanup:          NewPorterAppCleanupRepository(canQuery),
+		porterAppDomain:           NewPorterAppDomainRepository(canQuery),
+		userTwoFactor:             NewUserTwoFactorRepository(canQuery),
 	}
 }
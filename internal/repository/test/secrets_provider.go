@@ -0,0 +1,40 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/porter-dev/porter/api/types"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// SecretsProviderIntegrationRepository is a test repository that implements repository.SecretsProviderIntegrationRepository
+type SecretsProviderIntegrationRepository struct {
+	canQuery bool
+}
+
+// NewSecretsProviderIntegrationRepository returns the test SecretsProviderIntegrationRepository
+func NewSecretsProviderIntegrationRepository() repository.SecretsProviderIntegrationRepository {
+	return &SecretsProviderIntegrationRepository{canQuery: false}
+}
+
+// ReadSecretsProviderIntegration returns the integration of a project for a provider
+func (repo *SecretsProviderIntegrationRepository) ReadSecretsProviderIntegration(ctx context.Context, projectID uint, provider types.SecretsProvider) (*ints.SecretsProviderIntegration, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListSecretsProviderIntegrations returns the integrations of a project
+func (repo *SecretsProviderIntegrationRepository) ListSecretsProviderIntegrations(ctx context.Context, projectID uint) ([]*ints.SecretsProviderIntegration, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// CreateOrUpdateSecretsProviderIntegration creates the integration of a project for a provider, or replaces the existing one
+func (repo *SecretsProviderIntegrationRepository) CreateOrUpdateSecretsProviderIntegration(ctx context.Context, integration *ints.SecretsProviderIntegration) (*ints.SecretsProviderIntegration, error) {
+	return nil, errors.New("cannot write database")
+}
+
+// DeleteSecretsProviderIntegration deletes the integration of a project for a provider
+func (repo *SecretsProviderIntegrationRepository) DeleteSecretsProviderIntegration(ctx context.Context, integration *ints.SecretsProviderIntegration) error {
+	return errors.New("cannot write database")
+}
@@ -1,8 +1,10 @@
 package test
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
@@ -34,11 +36,15 @@ func (repo *SessionRepository) CreateSession(session *models.Session) (*models.S
 
 	// make sure key doesn't exist
 	for _, s := range repo.sessions {
-		if s.Key == session.Key {
+		if s != nil && s.Key == session.Key {
 			return nil, errors.New("Cannot write database")
 		}
 	}
 
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+
 	sessions := repo.sessions
 	sessions = append(sessions, session)
 	repo.sessions = sessions
@@ -56,7 +62,7 @@ func (repo *SessionRepository) UpdateSession(session *models.Session) (*models.S
 	var oldSession *models.Session
 
 	for _, s := range repo.sessions {
-		if s.Key == session.Key {
+		if s != nil && s.Key == session.Key {
 			oldSession = s
 		}
 	}
@@ -93,10 +99,65 @@ func (repo *SessionRepository) SelectSession(session *models.Session) (*models.S
 	}
 
 	for _, s := range repo.sessions {
-		if s.Key == session.Key {
+		if s != nil && s.Key == session.Key {
 			return s, nil
 		}
 	}
 
 	return nil, gorm.ErrRecordNotFound
 }
+
+// TouchSession records that the session with a key was used, moving its expiry to expiresAt
+func (repo *SessionRepository) TouchSession(ctx context.Context, key string, lastUsedAt time.Time, expiresAt time.Time) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	for _, s := range repo.sessions {
+		if s != nil && s.Key == key {
+			s.LastUsedAt = lastUsedAt
+			s.ExpiresAt = expiresAt
+
+			return nil
+		}
+	}
+
+	return gorm.ErrRecordNotFound
+}
+
+// DeleteExpiredSessions deletes up to limit sessions which expired before a time, returning how many were deleted
+func (repo *SessionRepository) DeleteExpiredSessions(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("Cannot write database")
+	}
+
+	var deleted int64
+	for i, s := range repo.sessions {
+		if limit > 0 && deleted >= int64(limit) {
+			break
+		}
+
+		if s != nil && s.ExpiresAt.Before(before) {
+			repo.sessions[i] = nil
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// CountSessions returns the number of stored sessions
+func (repo *SessionRepository) CountSessions(ctx context.Context) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("cannot read database")
+	}
+
+	var count int64
+	for _, s := range repo.sessions {
+		if s != nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
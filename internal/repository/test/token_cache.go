@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// TokenCacheRepository is a test TokenCacheRepository over the token caches of the test cluster, registry and helm
+// repo repositories. Those store a single token cache in each of their models and refresh it in place, so every entry
+// is in use by its owner and there are never superseded entries to delete.
+type TokenCacheRepository struct {
+	canQuery  bool
+	clusters  *ClusterRepository
+	registry  *RegistryRepository
+	helmRepos *HelmRepoRepository
+}
+
+// NewTokenCacheRepository returns a test TokenCacheRepository over the token caches of the given repositories
+func NewTokenCacheRepository(canQuery bool, clusters repository.ClusterRepository, registry repository.RegistryRepository, helmRepos repository.HelmRepoRepository) repository.TokenCacheRepository {
+	repo := &TokenCacheRepository{canQuery: canQuery}
+	repo.clusters, _ = clusters.(*ClusterRepository)
+	repo.registry, _ = registry.(*RegistryRepository)
+	repo.helmRepos, _ = helmRepos.(*HelmRepoRepository)
+
+	return repo
+}
+
+// DeleteExpiredTokenCaches deletes up to limit entries of a table which expired before a time, returning how many
+// were deleted
+func (repo *TokenCacheRepository) DeleteExpiredTokenCaches(ctx context.Context, table repository.TokenCacheTable, before time.Time, limit int) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("cannot write database")
+	}
+
+	if _, err := repo.CountTokenCaches(ctx, table); err != nil {
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+// CountTokenCaches returns the number of entries in a table
+func (repo *TokenCacheRepository) CountTokenCaches(ctx context.Context, table repository.TokenCacheTable) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("cannot read database")
+	}
+
+	var count int64
+
+	switch table {
+	case repository.TokenCacheTable_Base:
+	case repository.TokenCacheTable_Cluster:
+		if repo.clusters != nil {
+			for _, cluster := range repo.clusters.clusters {
+				if cluster != nil {
+					count++
+				}
+			}
+		}
+	case repository.TokenCacheTable_Registry:
+		if repo.registry != nil {
+			for _, reg := range repo.registry.registries {
+				if reg != nil {
+					count++
+				}
+			}
+		}
+	case repository.TokenCacheTable_HelmRepo:
+		if repo.helmRepos != nil {
+			for _, hr := range repo.helmRepos.helmRepos {
+				if hr != nil {
+					count++
+				}
+			}
+		}
+	default:
+		return 0, fmt.Errorf("unknown token cache table %s", table)
+	}
+
+	return count, nil
+}
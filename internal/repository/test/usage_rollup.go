@@ -0,0 +1,171 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// UsageRollupRepository is a test repository that implements repository.UsageRollupRepository
+// and stores rollups in-memory by their day
+type UsageRollupRepository struct {
+	canQuery bool
+
+	mu      sync.Mutex
+	rollups map[time.Time][]*models.UsageRollup
+	days    map[time.Time]*models.UsageRollupDay
+}
+
+// NewUsageRollupRepository returns the test UsageRollupRepository
+func NewUsageRollupRepository(canQuery bool) repository.UsageRollupRepository {
+	return &UsageRollupRepository{
+		canQuery: canQuery,
+		rollups:  map[time.Time][]*models.UsageRollup{},
+		days:     map[time.Time]*models.UsageRollupDay{},
+	}
+}
+
+// ReplaceUsageRollupsForDay replaces the rollups of every app on a day and records when the day was rolled up
+func (repo *UsageRollupRepository) ReplaceUsageRollupsForDay(ctx context.Context, day time.Time, rolledUpAt time.Time, rollups []*models.UsageRollup) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	for _, rollup := range rollups {
+		if !rollup.Day.Equal(day) {
+			return errors.New("rollup is not for the day being replaced")
+		}
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key := day.UTC()
+
+	stored := make([]*models.UsageRollup, 0, len(rollups))
+	for _, rollup := range rollups {
+		copied := *rollup
+		stored = append(stored, &copied)
+	}
+	repo.rollups[key] = stored
+
+	if existing, ok := repo.days[key]; ok {
+		existing.RolledUpAt = rolledUpAt
+	} else {
+		repo.days[key] = &models.UsageRollupDay{Day: day, RolledUpAt: rolledUpAt}
+	}
+
+	return nil
+}
+
+// ListUsageRollupDays returns the rolled up days between start and end, inclusive
+func (repo *UsageRollupRepository) ListUsageRollupDays(ctx context.Context, start, end time.Time) ([]*models.UsageRollupDay, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := []*models.UsageRollupDay{}
+	for _, day := range repo.days {
+		if !day.Day.Before(start) && !day.Day.After(end) {
+			copied := *day
+			res = append(res, &copied)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Day.Before(res[j].Day) })
+
+	return res, nil
+}
+
+// MarkUsageRollupDayPruned records that the raw events of a rolled up day were deleted
+func (repo *UsageRollupRepository) MarkUsageRollupDayPruned(ctx context.Context, day time.Time, prunedAt time.Time) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	existing, ok := repo.days[day.UTC()]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	existing.EventsPrunedAt = &prunedAt
+
+	return nil
+}
+
+// ListUsageRollupsByProjectID returns the rollups of a project's apps between start and end, inclusive
+func (repo *UsageRollupRepository) ListUsageRollupsByProjectID(ctx context.Context, projectID uint, start, end time.Time) ([]*models.UsageRollup, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := []*models.UsageRollup{}
+	for day, rollups := range repo.rollups {
+		if day.Before(start) || day.After(end) {
+			continue
+		}
+
+		for _, rollup := range rollups {
+			if rollup.ProjectID == projectID {
+				copied := *rollup
+				res = append(res, &copied)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if !res[i].Day.Equal(res[j].Day) {
+			return res[i].Day.Before(res[j].Day)
+		}
+		return res[i].PorterAppID < res[j].PorterAppID
+	})
+
+	return res, nil
+}
+
+// ListUsageRollupTotalsByDay returns the usage of every app summed for each day between start and end, inclusive, as
+// rollups without a project or app. Days without usage are omitted
+func (repo *UsageRollupRepository) ListUsageRollupTotalsByDay(ctx context.Context, start, end time.Time) ([]*models.UsageRollup, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := []*models.UsageRollup{}
+	for day, rollups := range repo.rollups {
+		if day.Before(start) || day.After(end) || len(rollups) == 0 {
+			continue
+		}
+
+		total := &models.UsageRollup{Day: rollups[0].Day}
+		for _, rollup := range rollups {
+			total.Deploys += rollup.Deploys
+			total.DeployFailures += rollup.DeployFailures
+			total.HelmOperationSeconds += rollup.HelmOperationSeconds
+			total.Builds += rollup.Builds
+			total.BuildFailures += rollup.BuildFailures
+			total.BuildSeconds += rollup.BuildSeconds
+		}
+		res = append(res, total)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Day.Before(res[j].Day) })
+
+	return res, nil
+}
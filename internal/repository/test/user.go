@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -183,3 +184,19 @@ func (repo *UserRepository) CheckPassword(id int, pwd string) (bool, error) {
 
 	return true, nil
 }
+
+// CountUsers returns the number of users of the instance
+func (repo *UserRepository) CountUsers(ctx context.Context) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("Cannot read from database")
+	}
+
+	var count int64
+	for _, user := range repo.users {
+		if user != nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// UserTwoFactorRepository is a test repository that implements repository.UserTwoFactorRepository
+// and stores second factors in-memory by their user id
+type UserTwoFactorRepository struct {
+	canQuery bool
+
+	mu         sync.Mutex
+	nextID     uint
+	twoFactors map[uint]*models.UserTwoFactor
+}
+
+// NewUserTwoFactorRepository returns the test UserTwoFactorRepository
+func NewUserTwoFactorRepository(canQuery bool) repository.UserTwoFactorRepository {
+	return &UserTwoFactorRepository{canQuery: canQuery, twoFactors: map[uint]*models.UserTwoFactor{}}
+}
+
+// ReadUserTwoFactor returns the second factor of a user
+func (repo *UserTwoFactorRepository) ReadUserTwoFactor(ctx context.Context, userID uint) (*models.UserTwoFactor, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	twoFactor, ok := repo.twoFactors[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return copyUserTwoFactor(twoFactor), nil
+}
+
+// CreateOrUpdateUserTwoFactor creates the second factor of a user, or replaces the existing one
+func (repo *UserTwoFactorRepository) CreateOrUpdateUserTwoFactor(ctx context.Context, twoFactor *models.UserTwoFactor) (*models.UserTwoFactor, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if twoFactor == nil {
+		return nil, errors.New("user two factor is nil")
+	}
+	if twoFactor.UserID == 0 {
+		return nil, errors.New("user id is empty")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := repo.twoFactors[twoFactor.UserID]; ok {
+		twoFactor.ID = existing.ID
+		twoFactor.CreatedAt = existing.CreatedAt
+	} else {
+		repo.nextID++
+		twoFactor.ID = repo.nextID
+		twoFactor.CreatedAt = now
+	}
+	twoFactor.UpdatedAt = now
+
+	repo.twoFactors[twoFactor.UserID] = copyUserTwoFactor(twoFactor)
+
+	return twoFactor, nil
+}
+
+// DeleteUserTwoFactor deletes the second factor of a user
+func (repo *UserTwoFactorRepository) DeleteUserTwoFactor(ctx context.Context, twoFactor *models.UserTwoFactor) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	if twoFactor == nil || twoFactor.UserID == 0 {
+		return errors.New("user id is empty")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.twoFactors, twoFactor.UserID)
+
+	return nil
+}
+
+func copyUserTwoFactor(twoFactor *models.UserTwoFactor) *models.UserTwoFactor {
+	copied := *twoFactor
+	copied.Secret = append([]byte(nil), twoFactor.Secret...)
+	if twoFactor.BackupCodes != nil {
+		copied.BackupCodes = append(models.UserBackupCodes{}, twoFactor.BackupCodes...)
+	}
+	if twoFactor.LockedUntil != nil {
+		lockedUntil := *twoFactor.LockedUntil
+		copied.LockedUntil = &lockedUntil
+	}
+
+	return &copied
+}
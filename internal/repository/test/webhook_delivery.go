@@ -0,0 +1,168 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// WebhookDeliveryRepository is a test repository that implements repository.WebhookDeliveryRepository
+// and stores webhook deliveries in-memory, indexed by their array index + 1
+type WebhookDeliveryRepository struct {
+	canQuery bool
+
+	mu         sync.Mutex
+	deliveries []*models.WebhookDelivery
+}
+
+// NewWebhookDeliveryRepository returns the test WebhookDeliveryRepository
+func NewWebhookDeliveryRepository(canQuery bool) repository.WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{canQuery: canQuery, deliveries: []*models.WebhookDelivery{}}
+}
+
+// CreateWebhookDelivery records a delivery attempt
+func (repo *WebhookDeliveryRepository) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if delivery == nil {
+		return nil, errors.New("webhook delivery is nil")
+	}
+	if delivery.ProjectID == 0 {
+		return nil, errors.New("project id is empty")
+	}
+	if delivery.EventID == "" {
+		return nil, errors.New("event id is empty")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	repo.deliveries = append(repo.deliveries, delivery)
+	delivery.ID = uint(len(repo.deliveries))
+
+	return delivery, nil
+}
+
+// ReadWebhookDelivery returns a delivery attempt by its id, scoped to a project and log alert rule
+func (repo *WebhookDeliveryRepository) ReadWebhookDelivery(ctx context.Context, projectID, logAlertRuleID, id uint) (*models.WebhookDelivery, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if id == 0 || int(id-1) >= len(repo.deliveries) || repo.deliveries[id-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	delivery := repo.deliveries[id-1]
+	if delivery.ProjectID != projectID || delivery.LogAlertRuleID != logAlertRuleID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return delivery, nil
+}
+
+// ListWebhookDeliveries returns the delivery attempts to the webhook of a log alert rule, newest first
+func (repo *WebhookDeliveryRepository) ListWebhookDeliveries(ctx context.Context, projectID, logAlertRuleID uint, filter repository.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := []*models.WebhookDelivery{}
+	for i := len(repo.deliveries) - 1; i >= 0; i-- {
+		delivery := repo.deliveries[i]
+		if delivery == nil || delivery.ProjectID != projectID || delivery.LogAlertRuleID != logAlertRuleID {
+			continue
+		}
+		if filter.Succeeded != nil && delivery.Succeeded != *filter.Succeeded {
+			continue
+		}
+
+		res = append(res, delivery)
+		if filter.Limit > 0 && len(res) == filter.Limit {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// CountWebhookDeliveries returns the number of delivery attempts to the webhook of a log alert rule since a time, and
+// how many of them succeeded
+func (repo *WebhookDeliveryRepository) CountWebhookDeliveries(ctx context.Context, projectID, logAlertRuleID uint, since time.Time) (int64, int64, error) {
+	if !repo.canQuery {
+		return 0, 0, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var attempts, succeeded int64
+	for _, delivery := range repo.deliveries {
+		if delivery == nil || delivery.ProjectID != projectID || delivery.LogAlertRuleID != logAlertRuleID || delivery.CreatedAt.Before(since) {
+			continue
+		}
+
+		attempts++
+		if delivery.Succeeded {
+			succeeded++
+		}
+	}
+
+	return attempts, succeeded, nil
+}
+
+// CountWebhookRedeliveries returns the number of redeliveries to the webhook of a log alert rule started since a time
+func (repo *WebhookDeliveryRepository) CountWebhookRedeliveries(ctx context.Context, logAlertRuleID uint, since time.Time) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var count int64
+	for _, delivery := range repo.deliveries {
+		if delivery != nil && delivery.LogAlertRuleID == logAlertRuleID && delivery.Replay && delivery.Attempt == 1 && !delivery.CreatedAt.Before(since) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// DeleteWebhookDeliveriesBefore deletes the delivery attempts made before a time, returning how many were deleted
+func (repo *WebhookDeliveryRepository) DeleteWebhookDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	if !repo.canQuery {
+		return 0, errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var deleted int64
+	for i, delivery := range repo.deliveries {
+		if delivery != nil && delivery.CreatedAt.Before(before) {
+			repo.deliveries[i] = nil
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
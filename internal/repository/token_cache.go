@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// TokenCacheTable is a table which token caches are stored in
+type TokenCacheTable string
+
+const (
+	// TokenCacheTable_Base stores the token caches which are not owned by a cluster, registry or helm repo
+	TokenCacheTable_Base TokenCacheTable = "token_caches"
+	// TokenCacheTable_Cluster stores the token caches referenced by clusters.token_cache_id
+	TokenCacheTable_Cluster TokenCacheTable = "cluster_token_caches"
+	// TokenCacheTable_Registry stores the token caches of registries
+	TokenCacheTable_Registry TokenCacheTable = "reg_token_caches"
+	// TokenCacheTable_HelmRepo stores the token caches of helm repos
+	TokenCacheTable_HelmRepo TokenCacheTable = "helm_repo_token_caches"
+)
+
+// TokenCacheTables are all of the tables which token caches are stored in
+var TokenCacheTables = []TokenCacheTable{
+	TokenCacheTable_Base,
+	TokenCacheTable_Cluster,
+	TokenCacheTable_Registry,
+	TokenCacheTable_HelmRepo,
+}
+
+// TokenCacheRepository represents the set of queries which maintain the token cache tables
+type TokenCacheRepository interface {
+	// DeleteExpiredTokenCaches deletes up to limit entries of a table which expired before a time, or all of them if
+	// limit is not positive, returning how many were deleted. The entry a cluster, registry or helm repo currently reads
+	// and refreshes is never deleted, however long ago it expired; only entries which were superseded or whose owner is
+	// gone are.
+	DeleteExpiredTokenCaches(ctx context.Context, table TokenCacheTable, before time.Time, limit int) (int64, error)
+	// CountTokenCaches returns the number of entries in a table
+	CountTokenCaches(ctx context.Context, table TokenCacheTable) (int64, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// UsageRollupRepository represents the set of queries on the UsageRollup and UsageRollupDay models
+type UsageRollupRepository interface {
+	// ReplaceUsageRollupsForDay replaces the rollups of every app on a day and records when the day was rolled up
+	ReplaceUsageRollupsForDay(ctx context.Context, day time.Time, rolledUpAt time.Time, rollups []*models.UsageRollup) error
+	// ListUsageRollupDays returns the rolled up days between start and end, inclusive
+	ListUsageRollupDays(ctx context.Context, start, end time.Time) ([]*models.UsageRollupDay, error)
+	// MarkUsageRollupDayPruned records that the raw events of a rolled up day were deleted
+	MarkUsageRollupDayPruned(ctx context.Context, day time.Time, prunedAt time.Time) error
+	// ListUsageRollupsByProjectID returns the rollups of a project's apps between start and end, inclusive
+	ListUsageRollupsByProjectID(ctx context.Context, projectID uint, start, end time.Time) ([]*models.UsageRollup, error)
+	// ListUsageRollupTotalsByDay returns the usage of every app summed for each day between start and end, inclusive, as
+	// rollups without a project or app. Days without usage are omitted
+	ListUsageRollupTotalsByDay(ctx context.Context, start, end time.Time) ([]*models.UsageRollup, error)
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/porter-dev/porter/internal/models"
 )
 
@@ -18,4 +20,6 @@ type UserRepository interface {
 	ListUsersByIDs(ids []uint) ([]*models.User, error)
 	UpdateUser(user *models.User) (*models.User, error)
 	DeleteUser(user *models.User) (*models.User, error)
+	// CountUsers returns the number of users of the instance
+	CountUsers(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// UserTwoFactorRepository represents the set of queries on the UserTwoFactor model
+type UserTwoFactorRepository interface {
+	// ReadUserTwoFactor returns the second factor of a user, with its secret decrypted
+	ReadUserTwoFactor(ctx context.Context, userID uint) (*models.UserTwoFactor, error)
+	// CreateOrUpdateUserTwoFactor creates the second factor of a user, or replaces the existing one
+	CreateOrUpdateUserTwoFactor(ctx context.Context, twoFactor *models.UserTwoFactor) (*models.UserTwoFactor, error)
+	// DeleteUserTwoFactor deletes the second factor of a user
+	DeleteUserTwoFactor(ctx context.Context, twoFactor *models.UserTwoFactor) error
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// WebhookDeliveryFilter selects the deliveries returned by ListWebhookDeliveries
+type WebhookDeliveryFilter struct {
+	// Succeeded only returns attempts with this outcome, if set
+	Succeeded *bool
+	// Limit caps the number of attempts returned, if set
+	Limit int
+}
+
+// WebhookDeliveryRepository represents the set of queries on the WebhookDelivery model
+type WebhookDeliveryRepository interface {
+	// CreateWebhookDelivery records a delivery attempt
+	CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error)
+	// ReadWebhookDelivery returns a delivery attempt by its id, scoped to a project and log alert rule
+	ReadWebhookDelivery(ctx context.Context, projectID, logAlertRuleID, id uint) (*models.WebhookDelivery, error)
+	// ListWebhookDeliveries returns the delivery attempts to the webhook of a log alert rule, newest first
+	ListWebhookDeliveries(ctx context.Context, projectID, logAlertRuleID uint, filter WebhookDeliveryFilter) ([]*models.WebhookDelivery, error)
+	// CountWebhookDeliveries returns the number of delivery attempts to the webhook of a log alert rule since a time, and
+	// how many of them succeeded
+	CountWebhookDeliveries(ctx context.Context, projectID, logAlertRuleID uint, since time.Time) (attempts int64, succeeded int64, err error)
+	// CountWebhookRedeliveries returns the number of redeliveries to the webhook of a log alert rule started since a time
+	CountWebhookRedeliveries(ctx context.Context, logAlertRuleID uint, since time.Time) (int64, error)
+	// DeleteWebhookDeliveriesBefore deletes the delivery attempts made before a time, returning how many were deleted
+	DeleteWebhookDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
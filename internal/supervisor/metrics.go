@@ -0,0 +1,76 @@
+package supervisor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMetrics writes the state of every component in the prometheus text exposition format
+func (s *Supervisor) WriteMetrics(w io.Writer) error {
+	states := s.States()
+
+	metrics := []struct {
+		name  string
+		help  string
+		kind  string
+		value func(state ComponentState) int
+	}{
+		{
+			name: "porter_component_up",
+			help: "Whether a background component is running",
+			kind: "gauge",
+			value: func(state ComponentState) int {
+				return boolToInt(state.Status == ComponentStatus_Running)
+			},
+		},
+		{
+			name: "porter_component_failed",
+			help: "Whether a background component has exceeded its restart budget",
+			kind: "gauge",
+			value: func(state ComponentState) int {
+				return boolToInt(state.Status == ComponentStatus_Failed)
+			},
+		},
+		{
+			name: "porter_component_restarts_total",
+			help: "The number of times a background component has been restarted after a panic or error",
+			kind: "counter",
+			value: func(state ComponentState) int {
+				return state.Restarts
+			},
+		},
+	}
+
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind); err != nil {
+			return err
+		}
+
+		for _, state := range states {
+			_, err := fmt.Fprintf(w, "%s{component=\"%s\",critical=\"%t\"} %d\n",
+				metric.name, escapeLabelValue(state.Name), state.Critical, metric.value(state),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	ready, _ := s.Ready()
+	_, err := fmt.Fprintf(w, "# HELP porter_components_ready Whether every critical background component is within its restart budget\n# TYPE porter_components_ready gauge\nporter_components_ready %d\n", boolToInt(ready))
+
+	return err
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
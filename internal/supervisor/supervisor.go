@@ -0,0 +1,324 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/pkg/logger"
+)
+
+// ComponentStatus is the lifecycle state of a supervised component
+type ComponentStatus string
+
+const (
+	// ComponentStatus_Pending is a component which has been registered but not started
+	ComponentStatus_Pending ComponentStatus = "pending"
+	// ComponentStatus_Running is a component whose run function is executing
+	ComponentStatus_Running ComponentStatus = "running"
+	// ComponentStatus_Restarting is a component waiting out its backoff after a panic or error
+	ComponentStatus_Restarting ComponentStatus = "restarting"
+	// ComponentStatus_Exited is a component whose run function returned without an error
+	ComponentStatus_Exited ComponentStatus = "exited"
+	// ComponentStatus_Failed is a component which exceeded its restart budget, and is no longer restarted
+	ComponentStatus_Failed ComponentStatus = "failed"
+	// ComponentStatus_Stopped is a component which returned after the supervisor was stopped
+	ComponentStatus_Stopped ComponentStatus = "stopped"
+)
+
+// RunFunc runs a background component until ctx is cancelled. Returning an error or panicking restarts the
+// component, while returning nil marks it as exited.
+type RunFunc func(ctx context.Context) error
+
+// Options configure how a Supervisor restarts failed components. Zero values use the defaults.
+type Options struct {
+	// InitialBackoff is the wait before the first restart, which doubles on each consecutive failure. Defaults to 1s
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between restarts. Defaults to 1m
+	MaxBackoff time.Duration
+	// MaxRestarts is the number of consecutive restarts allowed before a component is marked as failed. Defaults to 10
+	MaxRestarts int
+	// StableAfter is how long a component must run before its consecutive restarts and backoff are reset. Defaults to 5m
+	StableAfter time.Duration
+	// Logger receives a record of every panic, error and restart. Optional
+	Logger *logger.Logger
+}
+
+// ComponentState is the state of a supervised component, reported by the component status endpoint
+type ComponentState struct {
+	Name     string          `json:"name"`
+	Critical bool            `json:"critical"`
+	Status   ComponentStatus `json:"status"`
+	// Restarts is the total number of times the component has been restarted
+	Restarts int `json:"restarts"`
+	// ConsecutiveRestarts is the number of restarts since the component last ran for longer than StableAfter
+	ConsecutiveRestarts int        `json:"consecutive_restarts"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastErrorAt         *time.Time `json:"last_error_at,omitempty"`
+	StartedAt           *time.Time `json:"started_at,omitempty"`
+}
+
+type component struct {
+	run   RunFunc
+	state ComponentState
+}
+
+// Supervisor runs long-lived background components, recovering their panics and restarting them with backoff
+type Supervisor struct {
+	opts Options
+
+	mu         sync.Mutex
+	components map[string]*component
+	started    bool
+}
+
+// New returns a Supervisor with the given options
+func New(opts Options) *Supervisor {
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Minute
+	}
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = opts.InitialBackoff
+	}
+	if opts.MaxRestarts <= 0 {
+		opts.MaxRestarts = 10
+	}
+	if opts.StableAfter <= 0 {
+		opts.StableAfter = 5 * time.Minute
+	}
+
+	return &Supervisor{
+		opts:       opts,
+		components: make(map[string]*component),
+	}
+}
+
+// Register adds a background component, which is started by Run
+func (s *Supervisor) Register(name string, run RunFunc) error {
+	return s.register(name, run, false)
+}
+
+// RegisterCritical adds a background component which the server cannot serve without. Once a critical component
+// exceeds its restart budget, Ready returns false.
+func (s *Supervisor) RegisterCritical(name string, run RunFunc) error {
+	return s.register(name, run, true)
+}
+
+func (s *Supervisor) register(name string, run RunFunc, critical bool) error {
+	if name == "" {
+		return errors.New("component name cannot be empty")
+	}
+	if run == nil {
+		return fmt.Errorf("component %s must have a run function", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("cannot register component %s after the supervisor has started", name)
+	}
+	if _, ok := s.components[name]; ok {
+		return fmt.Errorf("component %s is already registered", name)
+	}
+
+	s.components[name] = &component{
+		run: run,
+		state: ComponentState{
+			Name:     name,
+			Critical: critical,
+			Status:   ComponentStatus_Pending,
+		},
+	}
+
+	return nil
+}
+
+// Run starts every registered component, and blocks until ctx is cancelled and every component has returned
+func (s *Supervisor) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.started = true
+	components := make([]*component, 0, len(s.components))
+	for _, c := range s.components {
+		components = append(components, c)
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, c := range components {
+		wg.Add(1)
+		go func(c *component) {
+			defer wg.Done()
+			s.supervise(ctx, c)
+		}(c)
+	}
+
+	wg.Wait()
+}
+
+func (s *Supervisor) supervise(ctx context.Context, c *component) {
+	backoff := s.opts.InitialBackoff
+
+	for {
+		startedAt := time.Now()
+		s.update(c, func(state *ComponentState) {
+			state.Status = ComponentStatus_Running
+			state.StartedAt = &startedAt
+		})
+
+		err := s.runOnce(ctx, c)
+
+		if ctx.Err() != nil {
+			s.update(c, func(state *ComponentState) {
+				state.Status = ComponentStatus_Stopped
+			})
+			return
+		}
+
+		if err == nil {
+			s.update(c, func(state *ComponentState) {
+				state.Status = ComponentStatus_Exited
+			})
+			s.logInfo(c, "background component exited")
+			return
+		}
+
+		// a component which ran for long enough before failing gets a fresh restart budget
+		if time.Since(startedAt) >= s.opts.StableAfter {
+			backoff = s.opts.InitialBackoff
+			s.update(c, func(state *ComponentState) {
+				state.ConsecutiveRestarts = 0
+			})
+		}
+
+		failedAt := time.Now()
+		var failed bool
+		s.update(c, func(state *ComponentState) {
+			state.LastError = err.Error()
+			state.LastErrorAt = &failedAt
+
+			if state.ConsecutiveRestarts >= s.opts.MaxRestarts {
+				state.Status = ComponentStatus_Failed
+				failed = true
+				return
+			}
+
+			state.Status = ComponentStatus_Restarting
+			state.Restarts++
+			state.ConsecutiveRestarts++
+		})
+
+		if failed {
+			s.logError(c, err, "background component exceeded its restart budget and will not be restarted")
+			return
+		}
+
+		s.logError(c, err, fmt.Sprintf("background component failed, restarting in %s", backoff))
+
+		select {
+		case <-ctx.Done():
+			s.update(c, func(state *ComponentState) {
+				state.Status = ComponentStatus_Stopped
+			})
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.opts.MaxBackoff {
+			backoff = s.opts.MaxBackoff
+		}
+	}
+}
+
+// runOnce calls the component's run function, converting a panic into an error carrying the stack trace
+func (s *Supervisor) runOnce(ctx context.Context, c *component) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: string(debug.Stack())}
+		}
+	}()
+
+	return c.run(ctx)
+}
+
+// PanicError is the error recorded when a component panics
+type PanicError struct {
+	Value interface{}
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+func (s *Supervisor) update(c *component, fn func(state *ComponentState)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(&c.state)
+}
+
+func (s *Supervisor) logInfo(c *component, msg string) {
+	if s.opts.Logger == nil {
+		return
+	}
+
+	s.opts.Logger.Info().Str("component", c.state.Name).Msg(msg)
+}
+
+func (s *Supervisor) logError(c *component, err error, msg string) {
+	if s.opts.Logger == nil {
+		return
+	}
+
+	event := s.opts.Logger.Error().Err(err).Str("component", c.state.Name)
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		event = event.Str("stack", panicErr.Stack)
+	}
+
+	event.Msg(msg)
+}
+
+// States returns the state of every registered component, sorted by name
+func (s *Supervisor) States() []ComponentState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]ComponentState, 0, len(s.components))
+	for _, c := range s.components {
+		states = append(states, c.state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+
+	return states
+}
+
+// Ready returns false if any critical component has exceeded its restart budget, along with the names of the
+// failed critical components
+func (s *Supervisor) Ready() (bool, []string) {
+	if s == nil {
+		return true, nil
+	}
+
+	failed := make([]string, 0)
+	for _, state := range s.States() {
+		if state.Critical && state.Status == ComponentStatus_Failed {
+			failed = append(failed, state.Name)
+		}
+	}
+
+	return len(failed) == 0, failed
+}
@@ -0,0 +1,305 @@
+package supervisor_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/supervisor"
+)
+
+func fastOptions() supervisor.Options {
+	return supervisor.Options{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		MaxRestarts:    3,
+		StableAfter:    time.Hour,
+	}
+}
+
+func stateOf(t *testing.T, s *supervisor.Supervisor, name string) supervisor.ComponentState {
+	t.Helper()
+
+	for _, state := range s.States() {
+		if state.Name == name {
+			return state
+		}
+	}
+
+	t.Fatalf("component %s is not registered", name)
+	return supervisor.ComponentState{}
+}
+
+func waitFor(t *testing.T, msg string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", msg)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// startSupervisor runs the supervisor until the test ends, failing the test if it does not stop
+func startSupervisor(t *testing.T, s *supervisor.Supervisor) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Error("supervisor did not stop after its context was cancelled")
+		}
+	})
+}
+
+func TestSupervisorRestartsPanickingComponent(t *testing.T) {
+	s := supervisor.New(fastOptions())
+
+	var runs int32
+	err := s.Register("webhook-dispatcher", func(ctx context.Context) error {
+		if atomic.AddInt32(&runs, 1) <= 2 {
+			panic("nil map write")
+		}
+
+		<-ctx.Done()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	startSupervisor(t, s)
+
+	waitFor(t, "the component to recover", func() bool {
+		state := stateOf(t, s, "webhook-dispatcher")
+		return state.Status == supervisor.ComponentStatus_Running && state.Restarts == 2
+	})
+
+	state := stateOf(t, s, "webhook-dispatcher")
+	if state.LastError != "panic: nil map write" || state.LastErrorAt == nil {
+		t.Errorf("expected the panic to be recorded as the last error, got %q", state.LastError)
+	}
+	if state.ConsecutiveRestarts != 2 {
+		t.Errorf("expected 2 consecutive restarts, got %d", state.ConsecutiveRestarts)
+	}
+	if ready, _ := s.Ready(); !ready {
+		t.Error("expected the supervisor to be ready while components are within their restart budget")
+	}
+}
+
+func TestSupervisorFlipsReadinessWhenCriticalComponentExceedsBudget(t *testing.T) {
+	s := supervisor.New(fastOptions())
+
+	var criticalRuns, optionalRuns int32
+	if err := s.RegisterCritical("stream-listener", func(ctx context.Context) error {
+		atomic.AddInt32(&criticalRuns, 1)
+		panic("connection reset")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register("usage-scheduler", func(ctx context.Context) error {
+		atomic.AddInt32(&optionalRuns, 1)
+		return errors.New("upstream unavailable")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RegisterCritical("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	startSupervisor(t, s)
+
+	waitFor(t, "both failing components to exceed their budget", func() bool {
+		return stateOf(t, s, "stream-listener").Status == supervisor.ComponentStatus_Failed &&
+			stateOf(t, s, "usage-scheduler").Status == supervisor.ComponentStatus_Failed
+	})
+
+	// the initial run plus one run for each of the 3 restarts in the budget
+	if got := atomic.LoadInt32(&criticalRuns); got != 4 {
+		t.Errorf("expected the critical component to run 4 times, got %d", got)
+	}
+	if got := stateOf(t, s, "stream-listener").Restarts; got != 3 {
+		t.Errorf("expected 3 restarts, got %d", got)
+	}
+	if got := stateOf(t, s, "usage-scheduler").LastError; got != "upstream unavailable" {
+		t.Errorf("expected the returned error to be recorded, got %q", got)
+	}
+
+	// no more restarts are attempted once the budget is exceeded
+	time.Sleep(20 * time.Millisecond)
+	if got := atomic.LoadInt32(&criticalRuns); got != 4 {
+		t.Errorf("expected no restarts after the budget was exceeded, got %d runs", got)
+	}
+
+	// only the critical component affects readiness
+	ready, failed := s.Ready()
+	if ready {
+		t.Fatal("expected the supervisor not to be ready")
+	}
+	if len(failed) != 1 || failed[0] != "stream-listener" {
+		t.Errorf("expected only stream-listener to be reported, got %v", failed)
+	}
+	if status := stateOf(t, s, "worker").Status; status != supervisor.ComponentStatus_Running {
+		t.Errorf("expected the healthy component to keep running, got %s", status)
+	}
+}
+
+func TestSupervisorResetsBudgetAfterStableRun(t *testing.T) {
+	opts := fastOptions()
+	opts.MaxRestarts = 1
+	opts.StableAfter = 10 * time.Millisecond
+	s := supervisor.New(opts)
+
+	var runs int32
+	if err := s.RegisterCritical("scheduler", func(ctx context.Context) error {
+		if atomic.AddInt32(&runs, 1) > 4 {
+			<-ctx.Done()
+			return nil
+		}
+
+		// each run is stable before failing, so the single restart in the budget is never used up
+		time.Sleep(15 * time.Millisecond)
+		return errors.New("lost leader lock")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	startSupervisor(t, s)
+
+	waitFor(t, "the component to keep restarting", func() bool {
+		state := stateOf(t, s, "scheduler")
+		return state.Status == supervisor.ComponentStatus_Running && state.Restarts == 4
+	})
+
+	if got := stateOf(t, s, "scheduler").ConsecutiveRestarts; got != 1 {
+		t.Errorf("expected the consecutive restarts to be reset by each stable run, got %d", got)
+	}
+	if ready, _ := s.Ready(); !ready {
+		t.Error("expected the supervisor to be ready")
+	}
+}
+
+func TestSupervisorExitAndStop(t *testing.T) {
+	s := supervisor.New(fastOptions())
+
+	var oneShotRuns int32
+	if err := s.Register("one-shot", func(ctx context.Context) error {
+		atomic.AddInt32(&oneShotRuns, 1)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register("loop", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	waitFor(t, "the one-shot component to exit", func() bool {
+		return stateOf(t, s, "one-shot").Status == supervisor.ComponentStatus_Exited &&
+			stateOf(t, s, "loop").Status == supervisor.ComponentStatus_Running
+	})
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to return once its context was cancelled")
+	}
+
+	if status := stateOf(t, s, "loop").Status; status != supervisor.ComponentStatus_Stopped {
+		t.Errorf("expected the loop to be stopped, got %s", status)
+	}
+	if got := stateOf(t, s, "loop").Restarts; got != 0 {
+		t.Errorf("expected a component stopped by the supervisor not to be restarted, got %d restarts", got)
+	}
+	if got := atomic.LoadInt32(&oneShotRuns); got != 1 {
+		t.Errorf("expected a component which exited cleanly not to be restarted, got %d runs", got)
+	}
+}
+
+func TestSupervisorRegisterValidation(t *testing.T) {
+	s := supervisor.New(supervisor.Options{})
+	run := func(ctx context.Context) error { return nil }
+
+	if err := s.Register("", run); err == nil {
+		t.Error("expected an empty name to be rejected")
+	}
+	if err := s.Register("worker", nil); err == nil {
+		t.Error("expected a nil run function to be rejected")
+	}
+	if err := s.Register("worker", run); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RegisterCritical("worker", run); err == nil {
+		t.Error("expected a duplicate name to be rejected")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.Run(ctx)
+
+	if err := s.Register("late", run); err == nil {
+		t.Error("expected registering after the supervisor has started to be rejected")
+	}
+}
+
+func TestSupervisorMetrics(t *testing.T) {
+	s := supervisor.New(fastOptions())
+
+	if err := s.RegisterCritical("stream-listener", func(ctx context.Context) error {
+		panic("boom")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	startSupervisor(t, s)
+
+	waitFor(t, "the component to fail", func() bool {
+		return stateOf(t, s, "stream-listener").Status == supervisor.ComponentStatus_Failed
+	})
+
+	var buf bytes.Buffer
+	if err := s.WriteMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"# TYPE porter_component_restarts_total counter",
+		`porter_component_up{component="stream-listener",critical="true"} 0`,
+		`porter_component_failed{component="stream-listener",critical="true"} 1`,
+		`porter_component_restarts_total{component="stream-listener",critical="true"} 3`,
+		"porter_components_ready 0",
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("expected metrics to contain %q, got:\n%s", line, buf.String())
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type budgetKey struct{}
+
+// Budget tracks the spans which are in flight while a request runs under a deadline, so that the downstream call
+// which was running when the deadline passed can be recorded
+type Budget struct {
+	mu       sync.Mutex
+	inFlight []*budgetSpan
+	// exceededBy is the first span which ended after the deadline
+	exceededBy string
+}
+
+// WithBudget returns a context in which the spans created by NewSpan are tracked by the returned budget
+func WithBudget(ctx context.Context) (context.Context, *Budget) {
+	budget := &Budget{}
+	return context.WithValue(ctx, budgetKey{}, budget), budget
+}
+
+// Exceeded marks the spans which are still in flight as having run past the deadline, and returns the name of the
+// call which consumed the budget: the first span which ended after the deadline passed, or else the most recently
+// started span which is still in flight. It returns an empty string if no span ran past the deadline.
+func (b *Budget) Exceeded() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, span := range b.inFlight {
+		WithAttributes(span, AttributeKV{Key: "deadline-exceeded", Value: true})
+	}
+
+	if b.exceededBy == "" && len(b.inFlight) > 0 {
+		b.exceededBy = b.inFlight[len(b.inFlight)-1].name
+	}
+
+	return b.exceededBy
+}
+
+func (b *Budget) start(span *budgetSpan) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inFlight = append(b.inFlight, span)
+}
+
+func (b *Budget) end(span *budgetSpan) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// spans end from the innermost out, so the first to end after the deadline is the call which was running when
+	// it passed
+	if errors.Is(span.ctx.Err(), context.DeadlineExceeded) {
+		WithAttributes(span, AttributeKV{Key: "deadline-exceeded", Value: true})
+		if b.exceededBy == "" {
+			b.exceededBy = span.name
+		}
+	}
+
+	for i, s := range b.inFlight {
+		if s == span {
+			b.inFlight = append(b.inFlight[:i], b.inFlight[i+1:]...)
+			return
+		}
+	}
+}
+
+// budgetSpan is a span which is removed from the budget's in flight spans when it ends
+type budgetSpan struct {
+	trace.Span
+	ctx    context.Context
+	name   string
+	budget *Budget
+}
+
+func (s *budgetSpan) End(options ...trace.SpanEndOption) {
+	s.budget.end(s)
+	s.Span.End(options...)
+}
+
+// trackSpan adds a span to the budget of the context, if the context has one
+func trackSpan(ctx context.Context, name string, span trace.Span) trace.Span {
+	budget, ok := ctx.Value(budgetKey{}).(*Budget)
+	if !ok {
+		return span
+	}
+
+	tracked := &budgetSpan{Span: span, ctx: ctx, name: name, budget: budget}
+	budget.start(tracked)
+
+	return tracked
+}
@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// SamplerAlways records every trace
+	SamplerAlways = "always"
+	// SamplerNever records no traces
+	SamplerNever = "never"
+	// SamplerRatio records a ratio of traces, chosen by their trace ID
+	SamplerRatio = "ratio"
+	// SamplerParentBased records the traces whose parent span was recorded by the caller, and a ratio of the traces
+	// started by Porter
+	SamplerParentBased = "parent_based"
+)
+
+// sampleRatioBits holds the bits of the ratio of traces recorded by the ratio sampler, so that it can be changed
+// while spans are being sampled
+var sampleRatioBits atomic.Uint64
+
+func init() {
+	sampleRatioBits.Store(math.Float64bits(1))
+}
+
+// SampleRatio returns the ratio of traces recorded by the ratio and parent-based samplers
+func SampleRatio() float64 {
+	return math.Float64frombits(sampleRatioBits.Load())
+}
+
+// SetSampleRatio changes the ratio of traces recorded by the ratio and parent-based samplers. It applies to the traces
+// started after it is called, without reconfiguring the tracer.
+func SetSampleRatio(ratio float64) error {
+	if math.IsNaN(ratio) || ratio < 0 || ratio > 1 {
+		return fmt.Errorf("sample ratio must be between 0 and 1, got %v", ratio)
+	}
+
+	sampleRatioBits.Store(math.Float64bits(ratio))
+	return nil
+}
+
+// NewSampler returns the sampler with the given name, one of SamplerAlways, SamplerNever, SamplerRatio or
+// SamplerParentBased. The ratio and parent-based samplers read the ratio set by SetSampleRatio for every trace.
+func NewSampler(name string) (sdktrace.Sampler, error) {
+	switch name {
+	case SamplerAlways:
+		return sdktrace.AlwaysSample(), nil
+	case SamplerNever:
+		return sdktrace.NeverSample(), nil
+	case SamplerRatio:
+		return ratioSampler{}, nil
+	case SamplerParentBased, "":
+		return sdktrace.ParentBased(ratioSampler{}), nil
+	default:
+		return nil, fmt.Errorf("unknown telemetry sampler %s", name)
+	}
+}
+
+// ratioSampler records the ratio of traces set by SetSampleRatio. Like sdktrace.TraceIDRatioBased, it decides by
+// the trace ID, so that every service which samples a trace at the same ratio makes the same decision.
+type ratioSampler struct{}
+
+func (ratioSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	traceState := trace.SpanContextFromContext(p.ParentContext).TraceState()
+
+	bound := uint64(SampleRatio() * (1 << 63))
+	if binary.BigEndian.Uint64(p.TraceID[8:16])>>1 < bound {
+		return sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample, Tracestate: traceState}
+	}
+
+	return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: traceState}
+}
+
+func (ratioSampler) Description() string {
+	return fmt.Sprintf("PorterRatioBased{%g}", SampleRatio())
+}
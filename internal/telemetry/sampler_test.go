@@ -0,0 +1,71 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceIDAt returns a trace ID which the ratio sampler records at any ratio above fraction
+func traceIDAt(fraction float64) trace.TraceID {
+	var id trace.TraceID
+	binary.BigEndian.PutUint64(id[8:16], uint64(fraction*(1<<63))<<1)
+	return id
+}
+
+func TestRatioSamplerReadsRatioAtRuntime(t *testing.T) {
+	defer func() { _ = SetSampleRatio(1) }()
+
+	sampler, err := NewSampler(SamplerRatio)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sampled := func(id trace.TraceID) bool {
+		res := sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: id, Name: "span"})
+		return res.Decision == sdktrace.RecordAndSample
+	}
+
+	low, high := traceIDAt(0.1), traceIDAt(0.9)
+
+	if !sampled(low) || !sampled(high) {
+		t.Fatalf("expected every trace to be sampled at a ratio of 1")
+	}
+
+	if err := SetSampleRatio(0.5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sampled(low) || sampled(high) {
+		t.Errorf("expected only the trace below the ratio to be sampled at a ratio of 0.5")
+	}
+
+	if err := SetSampleRatio(0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sampled(low) || sampled(high) {
+		t.Errorf("expected no trace to be sampled at a ratio of 0")
+	}
+}
+
+func TestSetSampleRatioValidates(t *testing.T) {
+	defer func() { _ = SetSampleRatio(1) }()
+
+	for _, ratio := range []float64{-0.1, 1.5} {
+		if err := SetSampleRatio(ratio); err == nil {
+			t.Errorf("expected an error for a ratio of %v", ratio)
+		}
+	}
+
+	if SampleRatio() != 1 {
+		t.Errorf("expected an invalid ratio to leave the ratio unchanged, got %v", SampleRatio())
+	}
+}
+
+func TestNewSamplerUnknown(t *testing.T) {
+	if _, err := NewSampler("sometimes"); err == nil {
+		t.Errorf("expected an error for an unknown sampler")
+	}
+}
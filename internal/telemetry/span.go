@@ -18,20 +18,32 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// noopSpan is the span returned by NewSpan when tracing is disabled
+var noopSpan = trace.SpanFromContext(context.Background())
+
 // AttributeKey helps enforce consistent naming conventions for attribute key
 type AttributeKey string
 
 // NewSpan is a convenience function for creating a new span, with a Porter-namespaced name to avoid conflicts.
 // Any commonly used variables in the context, will be added to the span such as clusterID, projectID.
 // When using this function, make sure to call `defer span.End()` immediately after, to avoid lost spans.
+// If tracing is disabled, the context is returned as it is with a span which records nothing, without allocating.
 func NewSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	if !Enabled() {
+		return ctx, trackSpan(ctx, name, noopSpan)
+	}
+
 	ctx, span := otel.Tracer("").Start(ctx, prefixSpanKey(name))
 	AddKnownContextVariablesToSpan(ctx, span)
-	return ctx, span
+	return ctx, trackSpan(ctx, name, span)
 }
 
 // AddKnownContextVariablesToSpan adds known commonly read context variables to a span
 func AddKnownContextVariablesToSpan(ctx context.Context, span trace.Span) {
+	if !span.IsRecording() {
+		return
+	}
+
 	user, ok := ctx.Value(types.UserScope).(*models.User)
 	if ok {
 		WithAttributes(span, AttributeKV{Key: "user-id", Value: user.ID})
@@ -56,6 +68,11 @@ type AttributeKV struct {
 // WithAttributes is a convenience function for adding attributes to a given span
 // This will also add the namespaced prefix to the keys
 func WithAttributes(span trace.Span, attrs ...AttributeKV) {
+	// the keys are only prefixed and the values converted for spans which are recorded
+	if !span.IsRecording() {
+		return
+	}
+
 	for _, attr := range attrs {
 		if attr.Key != "" {
 			switch val := attr.Value.(type) {
@@ -98,6 +115,13 @@ func WithAttributes(span trace.Span, attrs ...AttributeKV) {
 // It is advised to let the raw error be set at err, and message to be a human
 // readable string
 func Error(_ context.Context, span trace.Span, err error, message string) error {
+	if !span.IsRecording() {
+		if err == nil {
+			err = errors.New(message)
+		}
+		return err
+	}
+
 	if message != "" {
 		WithAttributes(span, AttributeKV{Key: "message", Value: message})
 	}
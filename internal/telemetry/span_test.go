@@ -0,0 +1,63 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewSpanDisabledDoesNotAllocate(t *testing.T) {
+	disabled.Store(true)
+	defer disabled.Store(false)
+
+	ctx := context.Background()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, span := NewSpan(ctx, "disabled-span")
+		WithAttributes(span, AttributeKV{Key: "enabled", Value: false})
+		span.End()
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations when tracing is disabled, got %v", allocs)
+	}
+}
+
+func TestNewSpanDisabledKeepsBudget(t *testing.T) {
+	disabled.Store(true)
+	defer disabled.Store(false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctx, budget := WithBudget(ctx)
+
+	_, span := NewSpan(ctx, "slow-call")
+	if got := budget.Exceeded(); got != "slow-call" {
+		t.Errorf("expected the span in flight to be tracked when tracing is disabled, got %q", got)
+	}
+	span.End()
+}
+
+func BenchmarkNewSpanDisabled(b *testing.B) {
+	disabled.Store(true)
+	defer disabled.Store(false)
+
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, span := NewSpan(ctx, "benchmark")
+		WithAttributes(span, AttributeKV{Key: "iteration", Value: "benchmark"})
+		span.End()
+	}
+}
+
+func BenchmarkNewSpanEnabled(b *testing.B) {
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, span := NewSpan(ctx, "benchmark")
+		WithAttributes(span, AttributeKV{Key: "iteration", Value: "benchmark"})
+		span.End()
+	}
+}
@@ -2,16 +2,42 @@ package telemetry
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
 
 	"github.com/honeycombio/otel-config-go/otelconfig"
 )
 
+const (
+	// ProtocolGRPC exports spans to the collector over gRPC
+	ProtocolGRPC = "grpc"
+	// ProtocolHTTP exports spans to the collector as protobuf over HTTP
+	ProtocolHTTP = "http/protobuf"
+)
+
 // TracerConfig contains all config for setting up an otel tracer
 type TracerConfig struct {
+	// Disabled turns tracing off, so that NewSpan returns a span which records nothing without allocating
+	Disabled bool
+
 	// ServiceName will show as service.name in spans
 	ServiceName string
-	// CollectorURL is the OLTP endpoint for receiving traces
+	// ServiceVersion will show as service.version in spans
+	ServiceVersion string
+	// Environment will show as deployment.environment in spans
+	Environment string
+
+	// CollectorURL is the OLTP endpoint for receiving traces. Tracing is disabled if it is empty
 	CollectorURL string
+	// Protocol is the protocol spans are exported with, one of ProtocolGRPC or ProtocolHTTP. Defaults to ProtocolGRPC
+	Protocol string
+
+	// Sampler is the sampler which decides which traces are recorded, one of SamplerAlways, SamplerNever, SamplerRatio
+	// or SamplerParentBased. Defaults to SamplerParentBased
+	Sampler string
+	// SampleRatio is the ratio of traces recorded by the ratio and parent-based samplers. It can be changed at runtime
+	// with SetSampleRatio
+	SampleRatio float64
 
 	Debug bool
 }
@@ -24,23 +50,77 @@ type Tracer struct {
 	Shutdown func()
 }
 
+// disabled is true if spans are not recorded, so that NewSpan can skip creating them
+var disabled atomic.Bool
+
+// configured is true once a tracer exports spans to a collector
+var configured atomic.Bool
+
+// Enabled returns true if spans are recorded. Callers which build expensive attributes should check it first.
+func Enabled() bool {
+	return !disabled.Load()
+}
+
+// Configured returns true if a tracer exports spans to a collector, so that tracing can be enabled at runtime
+func Configured() bool {
+	return configured.Load()
+}
+
+// SetEnabled turns recording spans on or off at runtime. Tracing cannot be enabled if no tracer was configured.
+func SetEnabled(enabled bool) error {
+	if enabled && !Configured() {
+		return fmt.Errorf("tracing cannot be enabled since no collector is configured")
+	}
+
+	disabled.Store(!enabled)
+	return nil
+}
+
 // InitTracer is using the Honeycomb and Lightstep partnership launcher for setting up opentelemetry
 // Make sure to run `defer tp.Shutdown(ctx)` after calling this function
 // to ensure that no traces are lost on exit
 func InitTracer(ctx context.Context, conf TracerConfig) (Tracer, error) {
-	if conf.CollectorURL == "" {
-		return Tracer{}, nil
+	tracer := Tracer{
+		config:   conf,
+		Shutdown: func() {},
 	}
 
-	tracer := Tracer{
-		config: conf,
+	if conf.Disabled || conf.CollectorURL == "" {
+		disabled.Store(true)
+		return tracer, nil
+	}
+
+	protocol := conf.Protocol
+	if protocol == "" {
+		protocol = ProtocolGRPC
+	}
+	if protocol != ProtocolGRPC && protocol != ProtocolHTTP {
+		return tracer, fmt.Errorf("unknown telemetry protocol %s", protocol)
+	}
+
+	if err := SetSampleRatio(conf.SampleRatio); err != nil {
+		return tracer, err
+	}
+
+	sampler, err := NewSampler(conf.Sampler)
+	if err != nil {
+		return tracer, err
+	}
+
+	resourceAttributes := map[string]string{}
+	if conf.Environment != "" {
+		resourceAttributes["deployment.environment"] = conf.Environment
 	}
 
 	bsp := NewBaggageSpanProcessor()
 
 	lnchr, err := otelconfig.ConfigureOpenTelemetry(
 		otelconfig.WithServiceName(conf.ServiceName),
+		otelconfig.WithServiceVersion(conf.ServiceVersion),
+		otelconfig.WithResourceAttributes(resourceAttributes),
 		otelconfig.WithExporterEndpoint(conf.CollectorURL),
+		otelconfig.WithExporterProtocol(otelconfig.Protocol(protocol)),
+		otelconfig.WithSampler(sampler),
 		otelconfig.WithSpanProcessor(bsp),
 		otelconfig.WithLogLevel("DEBUG"),
 		otelconfig.WithMetricsEnabled(false),  // can turn this on later
@@ -51,6 +131,9 @@ func InitTracer(ctx context.Context, conf TracerConfig) (Tracer, error) {
 		return tracer, err
 	}
 
+	configured.Store(true)
+	disabled.Store(false)
+
 	tracer.Shutdown = lnchr
 	return tracer, nil
 }
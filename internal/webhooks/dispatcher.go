@@ -0,0 +1,257 @@
+// Package webhooks delivers events to user-configured webhooks, such as the webhook channel of log alert rules, and
+// records every attempt so that deliveries can be inspected and replayed
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+const (
+	// HeaderEventID identifies the delivered event. It is the same for every attempt and redelivery of the event
+	HeaderEventID = "X-Porter-Event-Id"
+	// HeaderDeliveryID identifies the chain of attempts of a delivery. A redelivery starts a new chain
+	HeaderDeliveryID = "X-Porter-Delivery-Id"
+	// HeaderAttempt is the number of the attempt within its chain, starting at 1
+	HeaderAttempt = "X-Porter-Delivery-Attempt"
+	// HeaderIdempotencyKey is set to the event id, so that receivers can discard an event they have already handled
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderReplay is set to "true" on the attempts of a redelivery
+	HeaderReplay = "X-Porter-Replay"
+	// HeaderSignature is the hex encoded HMAC-SHA256 of "<event id>.<payload>", prefixed with "sha256=". It is only set
+	// if the server has a signing key
+	HeaderSignature = "X-Porter-Signature"
+)
+
+// ErrRedeliveryRateLimited is returned by Redeliver when the webhook has been redelivered to too often in the last hour
+var ErrRedeliveryRateLimited = errors.New("too many redeliveries to this webhook in the last hour")
+
+// Options configure how events are delivered. Zero values use the defaults.
+type Options struct {
+	// MaxAttempts is the number of attempts made to deliver an event before giving up. Defaults to 3
+	MaxAttempts uint
+	// RetryBackoff is the wait before the second attempt, doubled for each attempt after it. Defaults to 1s
+	RetryBackoff time.Duration
+	// SigningKey signs the payloads sent to webhooks. Payloads are not signed if it is empty
+	SigningKey string
+	// RedeliveryLimit caps the redeliveries to a single webhook in an hour. Defaults to 10
+	RedeliveryLimit int64
+	// Client sends the requests to webhooks. Defaults to a client with a 5s timeout
+	Client *http.Client
+}
+
+// Event is a payload to deliver to a webhook
+type Event struct {
+	ProjectID      uint
+	LogAlertRuleID uint
+	// EventID identifies the event across attempts and redeliveries
+	EventID string
+	URL     string
+	Payload []byte
+}
+
+// Dispatcher delivers events to webhooks, retrying failed attempts and recording each of them
+type Dispatcher struct {
+	repo repository.WebhookDeliveryRepository
+	opts Options
+	// sleep waits between attempts, and is replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewDispatcher returns a Dispatcher which records its attempts in repo
+func NewDispatcher(repo repository.WebhookDeliveryRepository, opts Options) *Dispatcher {
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = time.Second
+	}
+	if opts.RedeliveryLimit <= 0 {
+		opts.RedeliveryLimit = 10
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	return &Dispatcher{
+		repo:  repo,
+		opts:  opts,
+		sleep: sleep,
+	}
+}
+
+// NewDispatcherFromConfig returns a Dispatcher which records its attempts in the server's database
+func NewDispatcherFromConfig(conf *config.Config) *Dispatcher {
+	return NewDispatcher(conf.Repo.WebhookDelivery(), Options{
+		MaxAttempts:     conf.ServerConf.WebhookDeliveryMaxAttempts,
+		SigningKey:      conf.ServerConf.WebhookSigningKey,
+		RedeliveryLimit: conf.ServerConf.WebhookRedeliveryLimit,
+	})
+}
+
+// Deliver sends an event to its webhook, retrying until an attempt succeeds or the attempts run out. It returns the
+// last attempt, and an error if no attempt succeeded
+func (d *Dispatcher) Deliver(ctx context.Context, event Event) (*models.WebhookDelivery, error) {
+	return d.deliver(ctx, event, false)
+}
+
+// Redeliver sends the payload of a past delivery to the webhook at targetURL again, as a new chain of attempts marked
+// as a replay. The payload and event id are unchanged, so the signature matches the original delivery's
+func (d *Dispatcher) Redeliver(ctx context.Context, delivery *models.WebhookDelivery, targetURL string) (*models.WebhookDelivery, error) {
+	if delivery == nil {
+		return nil, errors.New("webhook delivery is nil")
+	}
+
+	count, err := d.repo.CountWebhookRedeliveries(ctx, delivery.LogAlertRuleID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("error counting redeliveries: %w", err)
+	}
+	if count >= d.opts.RedeliveryLimit {
+		return nil, ErrRedeliveryRateLimited
+	}
+
+	return d.deliver(ctx, Event{
+		ProjectID:      delivery.ProjectID,
+		LogAlertRuleID: delivery.LogAlertRuleID,
+		EventID:        delivery.EventID,
+		URL:            targetURL,
+		Payload:        delivery.Payload,
+	}, true)
+}
+
+// deliver makes the attempts of a single chain. Deliveries and redeliveries only differ by the replay header
+func (d *Dispatcher) deliver(ctx context.Context, event Event, replay bool) (*models.WebhookDelivery, error) {
+	chainID := uuid.New().String()
+	sum := sha256.Sum256(event.Payload)
+	bodySHA := hex.EncodeToString(sum[:])
+
+	var last *models.WebhookDelivery
+	var lastErr error
+	backoff := d.opts.RetryBackoff
+
+	for attempt := uint(1); attempt <= d.opts.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := d.sleep(ctx, backoff); err != nil {
+				return last, err
+			}
+			backoff *= 2
+		}
+
+		headers := d.headers(event, chainID, attempt, replay)
+		code, latency, err := d.send(ctx, event, headers)
+
+		delivery := &models.WebhookDelivery{
+			ProjectID:      event.ProjectID,
+			LogAlertRuleID: event.LogAlertRuleID,
+			EventID:        event.EventID,
+			ChainID:        chainID,
+			Attempt:        attempt,
+			Replay:         replay,
+			TargetURL:      event.URL,
+			RequestHeaders: headers,
+			BodySHA256:     bodySHA,
+			Payload:        event.Payload,
+			ResponseCode:   code,
+			LatencyMS:      latency.Milliseconds(),
+			Succeeded:      err == nil,
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+
+		recorded, recordErr := d.repo.CreateWebhookDelivery(ctx, delivery)
+		if recordErr != nil {
+			return delivery, fmt.Errorf("error recording webhook delivery: %w", recordErr)
+		}
+		last = recorded
+
+		if err == nil {
+			return last, nil
+		}
+		lastErr = err
+	}
+
+	return last, fmt.Errorf("webhook delivery failed after %d attempts: %w", d.opts.MaxAttempts, lastErr)
+}
+
+// headers returns the headers sent with an attempt, which are recorded with it
+func (d *Dispatcher) headers(event Event, chainID string, attempt uint, replay bool) models.JSONB {
+	headers := models.JSONB{
+		"Content-Type":       "application/json",
+		HeaderEventID:        event.EventID,
+		HeaderDeliveryID:     chainID,
+		HeaderAttempt:        strconv.FormatUint(uint64(attempt), 10),
+		HeaderIdempotencyKey: event.EventID,
+	}
+	if replay {
+		headers[HeaderReplay] = "true"
+	}
+	if d.opts.SigningKey != "" {
+		headers[HeaderSignature] = Sign(d.opts.SigningKey, event.EventID, event.Payload)
+	}
+
+	return headers
+}
+
+// send makes a single attempt, returning the webhook's status code and how long it took to respond
+func (d *Dispatcher) send(ctx context.Context, event Event, headers models.JSONB) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, event.URL, bytes.NewReader(event.Payload))
+	if err != nil {
+		return 0, 0, err
+	}
+	for key, value := range headers {
+		if s, ok := value.(string); ok {
+			req.Header.Set(key, s)
+		}
+	}
+
+	start := time.Now()
+	resp, err := d.opts.Client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return 0, latency, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp.StatusCode, latency, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, latency, nil
+}
+
+// Sign returns the signature header of a payload, which receivers can recompute with the signing key to verify that
+// an event was sent by Porter
+func Sign(key, eventID string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(eventID))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
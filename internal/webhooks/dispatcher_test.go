@@ -0,0 +1,202 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+// webhookServer answers with the given status codes in order, repeating the last one, and records every request
+type webhookServer struct {
+	mu       sync.Mutex
+	codes    []int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	s.requests = append(s.requests, r)
+	s.bodies = append(s.bodies, string(body))
+
+	code := s.codes[len(s.codes)-1]
+	if len(s.requests) <= len(s.codes) {
+		code = s.codes[len(s.requests)-1]
+	}
+	w.WriteHeader(code)
+}
+
+func newTestDispatcher(repo repository.WebhookDeliveryRepository, opts Options) *Dispatcher {
+	d := NewDispatcher(repo, opts)
+	d.sleep = func(ctx context.Context, _ time.Duration) error { return ctx.Err() }
+	return d
+}
+
+func TestDeliverRetriesAndRecordsEveryAttempt(t *testing.T) {
+	srv := &webhookServer{codes: []int{http.StatusBadGateway, http.StatusOK}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	repo := test.NewWebhookDeliveryRepository(true)
+	d := newTestDispatcher(repo, Options{SigningKey: "secret"})
+
+	last, err := d.Deliver(context.Background(), Event{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-1", URL: ts.URL, Payload: []byte(`{"rule_id":1}`)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last.Attempt != 2 || !last.Succeeded || last.ResponseCode != http.StatusOK {
+		t.Errorf("expected the second attempt to succeed, got %+v", last)
+	}
+
+	deliveries, err := repo.ListWebhookDeliveries(context.Background(), 1, 1, repository.WebhookDeliveryFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("expected 2 recorded attempts, got %d", len(deliveries))
+	}
+
+	first := deliveries[1]
+	if first.Attempt != 1 || first.Succeeded || first.ResponseCode != http.StatusBadGateway || first.Error == "" {
+		t.Errorf("expected the first attempt to be recorded as failed, got %+v", first)
+	}
+	if first.ChainID != last.ChainID {
+		t.Errorf("expected the attempts to share a chain, got %q and %q", first.ChainID, last.ChainID)
+	}
+	if first.BodySHA256 == "" || first.BodySHA256 != last.BodySHA256 {
+		t.Errorf("expected the attempts to record the same body hash, got %q and %q", first.BodySHA256, last.BodySHA256)
+	}
+	if first.Replay {
+		t.Error("expected a delivery not to be marked as a replay")
+	}
+
+	req := srv.requests[0]
+	if req.Header.Get(HeaderIdempotencyKey) != "event-1" || req.Header.Get(HeaderEventID) != "event-1" {
+		t.Errorf("expected the event id in the idempotency headers, got %v", req.Header)
+	}
+	if req.Header.Get(HeaderAttempt) != "1" || srv.requests[1].Header.Get(HeaderAttempt) != "2" {
+		t.Errorf("expected the attempt headers to count the attempts, got %q and %q", req.Header.Get(HeaderAttempt), srv.requests[1].Header.Get(HeaderAttempt))
+	}
+	if req.Header.Get(HeaderReplay) != "" {
+		t.Errorf("expected no replay header on a delivery, got %q", req.Header.Get(HeaderReplay))
+	}
+	if req.Header.Get(HeaderSignature) != Sign("secret", "event-1", []byte(`{"rule_id":1}`)) {
+		t.Errorf("expected a signed payload, got %q", req.Header.Get(HeaderSignature))
+	}
+	if first.RequestHeaders[HeaderSignature] != req.Header.Get(HeaderSignature) {
+		t.Errorf("expected the sent headers to be recorded, got %v", first.RequestHeaders)
+	}
+}
+
+func TestDeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	srv := &webhookServer{codes: []int{http.StatusInternalServerError}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	repo := test.NewWebhookDeliveryRepository(true)
+	d := newTestDispatcher(repo, Options{MaxAttempts: 2})
+
+	last, err := d.Deliver(context.Background(), Event{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-1", URL: ts.URL, Payload: []byte(`{}`)})
+	if err == nil {
+		t.Fatal("expected an error when every attempt fails")
+	}
+	if last == nil || last.Attempt != 2 || last.Succeeded {
+		t.Errorf("expected the last failed attempt, got %+v", last)
+	}
+	if len(srv.requests) != 2 {
+		t.Errorf("expected 2 attempts, got %d", len(srv.requests))
+	}
+}
+
+func TestRedeliverReplaysTheSignedPayload(t *testing.T) {
+	srv := &webhookServer{codes: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusNoContent}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	repo := test.NewWebhookDeliveryRepository(true)
+	d := newTestDispatcher(repo, Options{SigningKey: "secret"})
+
+	failed, err := d.Deliver(context.Background(), Event{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-1", URL: ts.URL, Payload: []byte(`{"rule_id":1}`)})
+	if err == nil {
+		t.Fatal("expected the delivery to fail")
+	}
+
+	replayed, err := d.Redeliver(context.Background(), failed, ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !replayed.Replay || replayed.Attempt != 1 || !replayed.Succeeded || replayed.ChainID == failed.ChainID {
+		t.Errorf("expected a new successful chain marked as a replay, got %+v", replayed)
+	}
+
+	original, replay := srv.requests[0], srv.requests[3]
+	if replay.Header.Get(HeaderReplay) != "true" {
+		t.Errorf("expected the replay header on a redelivery, got %q", replay.Header.Get(HeaderReplay))
+	}
+	if replay.Header.Get(HeaderSignature) != original.Header.Get(HeaderSignature) || srv.bodies[3] != srv.bodies[0] {
+		t.Error("expected a redelivery to send the same signed payload")
+	}
+	if replay.Header.Get(HeaderIdempotencyKey) != "event-1" {
+		t.Errorf("expected the redelivery to keep the event's idempotency key, got %q", replay.Header.Get(HeaderIdempotencyKey))
+	}
+}
+
+func TestRedeliverIsRateLimited(t *testing.T) {
+	srv := &webhookServer{codes: []int{http.StatusOK}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	repo := test.NewWebhookDeliveryRepository(true)
+	d := newTestDispatcher(repo, Options{RedeliveryLimit: 2})
+
+	delivery, err := d.Deliver(context.Background(), Event{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-1", URL: ts.URL, Payload: []byte(`{}`)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := d.Redeliver(context.Background(), delivery, ts.URL); err != nil {
+			t.Fatalf("unexpected error on redelivery %d: %v", i+1, err)
+		}
+	}
+
+	if _, err := d.Redeliver(context.Background(), delivery, ts.URL); !errors.Is(err, ErrRedeliveryRateLimited) {
+		t.Errorf("expected the third redelivery to be rate limited, got %v", err)
+	}
+	if len(srv.requests) != 3 {
+		t.Errorf("expected a rate limited redelivery not to be sent, got %d requests", len(srv.requests))
+	}
+}
+
+func TestPrunerDeletesExpiredDeliveries(t *testing.T) {
+	repo := test.NewWebhookDeliveryRepository(true)
+	now := time.Now()
+
+	for _, createdAt := range []time.Time{now.Add(-8 * 24 * time.Hour), now.Add(-time.Hour)} {
+		if _, err := repo.CreateWebhookDelivery(context.Background(), &models.WebhookDelivery{ProjectID: 1, LogAlertRuleID: 1, EventID: "event-1", Attempt: 1, CreatedAt: createdAt}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	NewPruner(repo, PrunerOptions{}).prune(context.Background(), now)
+
+	deliveries, err := repo.ListWebhookDeliveries(context.Background(), 1, 1, repository.WebhookDeliveryFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != 2 {
+		t.Errorf("expected only the delivery within the retention period to be kept, got %d deliveries", len(deliveries))
+	}
+}
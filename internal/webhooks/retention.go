@@ -0,0 +1,76 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/rs/zerolog"
+)
+
+// PrunerOptions configure a Pruner. Zero values use the defaults.
+type PrunerOptions struct {
+	// Interval is the time between deletions of expired deliveries. Defaults to 1h
+	Interval time.Duration
+	// Retention is how long delivery attempts are kept. Defaults to 7 days
+	Retention time.Duration
+	// Logger receives a record of the deleted deliveries. Optional
+	Logger *logger.Logger
+}
+
+// Pruner periodically deletes the delivery attempts which are older than the retention period
+type Pruner struct {
+	repo repository.WebhookDeliveryRepository
+	opts PrunerOptions
+}
+
+// NewPruner returns a Pruner for the deliveries recorded in repo
+func NewPruner(repo repository.WebhookDeliveryRepository, opts PrunerOptions) *Pruner {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Hour
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 7 * 24 * time.Hour
+	}
+
+	return &Pruner{
+		repo: repo,
+		opts: opts,
+	}
+}
+
+// Run deletes the expired deliveries on every interval until ctx is cancelled
+func (p *Pruner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.prune(ctx, time.Now())
+		}
+	}
+}
+
+func (p *Pruner) prune(ctx context.Context, now time.Time) {
+	deleted, err := p.repo.DeleteWebhookDeliveriesBefore(ctx, now.Add(-p.opts.Retention))
+	if err != nil {
+		p.log(zerolog.ErrorLevel).Err(err).Msg("error deleting expired webhook deliveries")
+		return
+	}
+
+	if deleted > 0 {
+		p.log(zerolog.InfoLevel).Int64("deleted", deleted).Msg("deleted expired webhook deliveries")
+	}
+}
+
+func (p *Pruner) log(level zerolog.Level) *zerolog.Event {
+	if p.opts.Logger == nil {
+		return nil
+	}
+
+	return p.opts.Logger.WithLevel(level)
+}
@@ -0,0 +1,76 @@
+package redact
+
+import (
+	"net/http"
+	"time"
+)
+
+// MaxBodyBytes is the largest body kept in a recorded exchange. Longer bodies are truncated before they are redacted.
+const MaxBodyBytes = 256 * 1024
+
+// Exchange is a redacted request to the Porter API and its response
+type Exchange struct {
+	Time       time.Time `json:"time"`
+	DurationMS int64     `json:"duration_ms"`
+
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestHeaders  http.Header `json:"request_headers,omitempty"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	StatusCode      int         `json:"status_code,omitempty"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+
+	// Truncated is true if either body was longer than MaxBodyBytes
+	Truncated bool `json:"truncated,omitempty"`
+	// Error is the error which stopped the request from completing, if any
+	Error string `json:"error,omitempty"`
+}
+
+// ExchangeInput is an unredacted request and its response
+type ExchangeInput struct {
+	Start           time.Time
+	End             time.Time
+	Method          string
+	URL             string
+	RequestHeaders  http.Header
+	RequestBody     []byte
+	StatusCode      int
+	ResponseHeaders http.Header
+	ResponseBody    []byte
+	Error           error
+}
+
+// NewExchange redacts a request and its response
+func NewExchange(input ExchangeInput) Exchange {
+	requestBody, requestTruncated := truncate(input.RequestBody)
+	responseBody, responseTruncated := truncate(input.ResponseBody)
+
+	exchange := Exchange{
+		Time:            input.Start.UTC(),
+		DurationMS:      input.End.Sub(input.Start).Milliseconds(),
+		Method:          input.Method,
+		URL:             URL(input.URL),
+		RequestHeaders:  Header(input.RequestHeaders),
+		RequestBody:     string(Body(requestBody)),
+		StatusCode:      input.StatusCode,
+		ResponseHeaders: Header(input.ResponseHeaders),
+		ResponseBody:    string(Body(responseBody)),
+		Truncated:       requestTruncated || responseTruncated,
+	}
+
+	if input.Error != nil {
+		exchange.Error = String(input.Error.Error())
+	}
+
+	return exchange
+}
+
+// truncate cuts a body to MaxBodyBytes. A truncated JSON body no longer parses, so it is redacted as plain text.
+func truncate(body []byte) ([]byte, bool) {
+	if len(body) <= MaxBodyBytes {
+		return body, false
+	}
+
+	return body[:MaxBodyBytes], true
+}
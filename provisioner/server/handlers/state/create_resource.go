@@ -353,7 +353,7 @@ func createRDSEnvGroup(ctx context.Context, config *config.Config, infra *models
 		host = strArr[0]
 		port = strArr[1]
 	}
-	_, err = envgroup.CreateEnvGroup(agent, types.ConfigMapInput{
+	_, err = envgroup.CreateEnvGroup(ctx, agent, types.ConfigMapInput{
 		Name:      fmt.Sprintf("rds-credentials-%s", lastApplied["db_name"].(string)),
 		Namespace: "default",
 		Variables: map[string]string{},
@@ -406,7 +406,7 @@ func createS3EnvGroup(ctx context.Context, config *config.Config, infra *models.
 		return fmt.Errorf("failed to get agent: %s", err.Error())
 	}
 	// split the instance endpoint on the port
-	_, err = envgroup.CreateEnvGroup(agent, types.ConfigMapInput{
+	_, err = envgroup.CreateEnvGroup(ctx, agent, types.ConfigMapInput{
 		Name:      fmt.Sprintf("s3-credentials-%s", lastApplied["bucket_name"].(string)),
 		Namespace: "default",
 		Variables: map[string]string{},
@@ -22,6 +22,7 @@ REDIS_ENABLED=false
 # DB_USER=porter
 # DB_PASS=porter
 # DB_NAME=porter
+# DB_SSL_MODE=disable
 
 EOM
 }